@@ -0,0 +1,58 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"sync"
+
+	"github.com/kaiachain/kaia/common"
+)
+
+// RevertDecoder resolves revert data that is not a plain `Error(string)`,
+// e.g. Solidity custom errors, using the ABI known for the reverting contract.
+type RevertDecoder interface {
+	DecodeRevert(contract common.Address, data []byte) (string, error)
+}
+
+var (
+	revertDecoderMu sync.RWMutex
+	revertDecoder   RevertDecoder
+)
+
+// SetRevertDecoder registers a node-wide RevertDecoder. Passing nil removes it.
+func SetRevertDecoder(d RevertDecoder) {
+	revertDecoderMu.Lock()
+	defer revertDecoderMu.Unlock()
+	revertDecoder = d
+}
+
+// UnpackRevertOf resolves the revert reason of the given contract. It first tries
+// the standard `Error(string)` encoding, then falls back to the registered RevertDecoder.
+func UnpackRevertOf(contract *common.Address, data []byte) (string, error) {
+	reason, err := UnpackRevert(data)
+	if err == nil || contract == nil {
+		return reason, err
+	}
+
+	revertDecoderMu.RLock()
+	d := revertDecoder
+	revertDecoderMu.RUnlock()
+	if d == nil {
+		return "", err
+	}
+	return d.DecodeRevert(*contract, data)
+}
@@ -321,6 +321,11 @@ func (api *EthereumAPI) FeeHistory(ctx context.Context, blockCount DecimalOrHex,
 	return api.publicKaiaAPI.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
 }
 
+// ProtocolVersion returns the current Kaia protocol version this node supports.
+func (api *EthereumAPI) ProtocolVersion() hexutil.Uint {
+	return api.publicKaiaAPI.ProtocolVersion()
+}
+
 // Syncing returns false in case the node is currently not syncing with the network. It can be up to date or has not
 // yet received the latest block headers from its pears. In case it is synchronizing:
 // - startingBlock: block number this node started to synchronise from
@@ -426,15 +431,21 @@ func doGetProof(ctx context.Context, b Backend, address common.Address, storageK
 	}
 	codeHash := state.GetCodeHash(address)
 
-	contractStorageRootExt, err := state.GetContractStorageRoot(address)
-	if err != nil {
-		return nil, err
+	// Accounts without storage (non-existing accounts and EOAs) are proven
+	// against the empty storage trie, as in Ethereum.
+	contractStorageRootExt := types.EmptyRootHashOriginal.ExtendZero()
+	if state.IsContractAccount(address) {
+		contractStorageRootExt, err = state.GetContractStorageRoot(address)
+		if err != nil {
+			return nil, err
+		}
 	}
 	contractStorageRoot := contractStorageRootExt.Unextend()
 
-	// if we have a storageTrie, (which means the account exists), we can update the storagehash
 	if len(keys) > 0 {
-		storageTrie, err := statedb.NewTrie(contractStorageRoot, state.Database().TrieDB(), nil)
+		// The storage root is kept extended so that the storage trie of a historical
+		// block can be opened even if its nodes are stored with extended hashes.
+		storageTrie, err := statedb.NewStorageTrie(contractStorageRootExt, state.Database().TrieDB(), nil)
 		if err != nil {
 			return nil, err
 		}
@@ -737,6 +748,8 @@ type EthRPCTransaction struct {
 	Type             hexutil.Uint64    `json:"type"`
 	Accesses         *types.AccessList `json:"accessList,omitempty"`
 	ChainID          *hexutil.Big      `json:"chainId,omitempty"`
+	BlobFeeCap       *hexutil.Big      `json:"maxFeePerBlobGas,omitempty"`
+	BlobHashes       []common.Hash     `json:"blobVersionedHashes,omitempty"`
 	V                *hexutil.Big      `json:"v"`
 	R                *hexutil.Big      `json:"r"`
 	S                *hexutil.Big      `json:"s"`
@@ -769,6 +782,10 @@ type ethTxJSON struct {
 	ChainID    *hexutil.Big      `json:"chainId,omitempty"`
 	AccessList *types.AccessList `json:"accessList,omitempty"`
 
+	// Blob transaction fields:
+	MaxFeePerBlobGas    *hexutil.Big  `json:"maxFeePerBlobGas,omitempty"`
+	BlobVersionedHashes []common.Hash `json:"blobVersionedHashes,omitempty"`
+
 	// Only used for encoding:
 	Hash common.Hash `json:"hash"`
 }
@@ -850,12 +867,16 @@ func newEthRPCTransaction(block *types.Block, tx *types.Transaction, blockHash c
 		al := tx.AccessList()
 		result.Accesses = &al
 		result.ChainID = (*hexutil.Big)(tx.ChainId())
-	case types.TxTypeEthereumDynamicFee:
+	case types.TxTypeEthereumDynamicFee, types.TxTypeEthereumBlob:
 		al := tx.AccessList()
 		result.Accesses = &al
 		result.ChainID = (*hexutil.Big)(tx.ChainId())
 		result.GasFeeCap = (*hexutil.Big)(tx.GasFeeCap())
 		result.GasTipCap = (*hexutil.Big)(tx.GasTipCap())
+		if typeInt == types.TxTypeEthereumBlob {
+			result.BlobFeeCap = (*hexutil.Big)(tx.BlobGasFeeCap())
+			result.BlobHashes = tx.BlobHashes()
+		}
 		if block != nil {
 			result.GasPrice = (*hexutil.Big)(tx.EffectiveGasPrice(block.Header(), config))
 		} else {
@@ -917,6 +938,14 @@ func formatTxToEthTxJSON(tx *types.Transaction) *ethTxJSON {
 		enc.ChainID = (*hexutil.Big)(tx.ChainId())
 		enc.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
 		enc.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	case types.TxTypeEthereumBlob:
+		al := tx.AccessList()
+		enc.AccessList = &al
+		enc.ChainID = (*hexutil.Big)(tx.ChainId())
+		enc.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		enc.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+		enc.MaxFeePerBlobGas = (*hexutil.Big)(tx.BlobGasFeeCap())
+		enc.BlobVersionedHashes = tx.BlobHashes()
 	default:
 		enc.GasPrice = (*hexutil.Big)(tx.GasPrice())
 	}
@@ -1014,9 +1043,15 @@ func (api *EthereumAPI) GetTransactionReceipt(ctx context.Context, hash common.H
 	tx, blockHash, blockNumber, index, receipt := txpoolAPI.GetTxLookupInfoAndReceipt(ctx, hash)
 
 	if tx == nil {
-		return nil, nil
+		return nil, checkTxReceiptPruned(txpoolAPI, hash)
 	}
 	receipts := txpoolAPI.GetBlockReceipts(ctx, blockHash)
+	if uint64(len(receipts)) <= index {
+		if err := blockchain.CheckReceiptsPruned(txpoolAPI.ChainDB(), blockNumber); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("the receipts of the block (%s) are not available", blockHash.String())
+	}
 	cumulativeGasUsed := uint64(0)
 	for i := uint64(0); i <= index; i++ {
 		cumulativeGasUsed += receipts[i].GasUsed
@@ -1051,6 +1086,9 @@ func (api *EthereumAPI) GetBlockReceipts(ctx context.Context, blockNrOrHash rpc.
 		outputList        = make([]map[string]interface{}, 0, len(receipts))
 	)
 	if receipts.Len() != txs.Len() {
+		if err := blockchain.CheckReceiptsPruned(b.ChainDB(), blockNumber); err != nil {
+			return nil, err
+		}
 		return nil, fmt.Errorf("the size of transactions and receipts is different in the block (%s)", blockHash.String())
 	}
 	for index, receipt := range receipts {
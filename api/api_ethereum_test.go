@@ -31,6 +31,7 @@ import (
 	"github.com/kaiachain/kaia/params"
 	"github.com/kaiachain/kaia/rlp"
 	"github.com/kaiachain/kaia/storage/database"
+	"github.com/kaiachain/kaia/storage/statedb"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -986,6 +987,32 @@ func TestEthereumAPI_GetTransactionReceipt(t *testing.T) {
 	mockCtrl.Finish()
 }
 
+// TestEthereumAPI_GetReceiptsPruned tests the receipt methods on a block whose receipts are pruned.
+func TestEthereumAPI_GetReceiptsPruned(t *testing.T) {
+	mockCtrl, mockBackend, api := testInitForEthApi(t)
+	defer mockCtrl.Finish()
+	block, txs, _, _, _ := createTestData(t, nil)
+
+	db := database.NewMemoryDBManager()
+	db.WriteTxLookupEntries(block)
+	db.WriteLastPrunedReceiptBlockNumber(block.NumberU64())
+	mockBackend.EXPECT().ChainDB().Return(db).AnyTimes()
+	mockBackend.EXPECT().BlockByNumberOrHash(gomock.Any(), gomock.Any()).Return(block, nil).AnyTimes()
+	mockBackend.EXPECT().GetBlockReceipts(gomock.Any(), block.Hash()).Return(nil).AnyTimes()
+	mockBackend.EXPECT().GetTxLookupInfoAndReceipt(gomock.Any(), txs[0].Hash()).Return(nil, common.Hash{}, uint64(0), uint64(0), nil).AnyTimes()
+
+	_, err := api.GetBlockReceipts(context.Background(), rpc.NewBlockNumberOrHashWithHash(block.Hash(), false))
+	assert.ErrorIs(t, err, blockchain.ErrReceiptsPruned)
+	_, err = api.GetTransactionReceipt(context.Background(), txs[0].Hash())
+	assert.ErrorIs(t, err, blockchain.ErrReceiptsPruned)
+
+	// The receipts of the later blocks are not pruned.
+	db.WriteLastPrunedReceiptBlockNumber(block.NumberU64() - 1)
+	receipt, err := api.GetTransactionReceipt(context.Background(), txs[0].Hash())
+	assert.NoError(t, err)
+	assert.Nil(t, receipt)
+}
+
 func testInitForEthApi(t *testing.T) (*gomock.Controller, *mock_api.MockBackend, EthereumAPI) {
 	mockCtrl := gomock.NewController(t)
 	mockBackend := mock_api.NewMockBackend(mockCtrl)
@@ -2603,3 +2630,66 @@ func TestEthereumAPI_EstimateGas(t *testing.T) {
 		return api.EstimateGas(context.Background(), args, nil, nil)
 	})
 }
+
+func TestEthereumAPI_GetProofHistorical(t *testing.T) {
+	mockCtrl, mockBackend, api := testInitForEthApi(t)
+	defer mockCtrl.Finish()
+
+	var (
+		contract = common.HexToAddress("0xcccc")
+		eoa      = common.HexToAddress("0xaaaa")
+		missing  = common.HexToAddress("0xdddd")
+		slot     = common.HexToHash("0x01")
+		gspec    = &blockchain.Genesis{Alloc: blockchain.GenesisAlloc{
+			eoa:      {Balance: big.NewInt(params.KAIA)},
+			contract: {Balance: common.Big0, Code: hexutil.MustDecode(codeRevertHello), Storage: map[common.Hash]common.Hash{slot: common.HexToHash("0x11")}},
+		}, Config: dummyChainConfigForEthereumAPITest}
+		dbm    = database.NewMemoryDBManager()
+		db     = state.NewDatabase(dbm)
+		header = gspec.MustCommit(dbm).Header()
+	)
+
+	// Advance the state so that the genesis state is no longer the latest one.
+	latest, err := state.New(header.Root, db, nil, nil)
+	require.NoError(t, err)
+	latest.SetState(contract, slot, common.HexToHash("0x22"))
+	latestRoot, err := latest.Commit(true)
+	require.NoError(t, err)
+	require.NotEqual(t, header.Root, latestRoot)
+
+	mockBackend.EXPECT().StateAndHeaderByNumberOrHash(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(...interface{}) (*state.StateDB, *types.Header, error) {
+			state, err := state.New(header.Root, db, nil, nil)
+			return state, header, err
+		}).AnyTimes()
+
+	verify := func(root common.Hash, key []byte, proof []string) []byte {
+		proofDB := database.NewMemoryDBManager()
+		for _, enc := range proof {
+			node := hexutil.MustDecode(enc)
+			proofDB.WriteMerkleProof(database.TrieNodeKey(crypto.Keccak256Hash(node).ExtendZero()), node)
+		}
+		value, err, _ := statedb.VerifyProof(root, key, proofDB)
+		require.NoError(t, err)
+		return value
+	}
+
+	blockNrOrHash := rpc.NewBlockNumberOrHashWithNumber(0)
+	result, err := api.GetProof(context.Background(), contract, []string{slot.Hex()}, blockNrOrHash)
+	require.NoError(t, err)
+	assert.NotNil(t, verify(header.Root, crypto.Keccak256(contract.Bytes()), result.AccountProof))
+	require.Len(t, result.StorageProof, 1)
+	assert.Equal(t, big.NewInt(0x11), result.StorageProof[0].Value.ToInt())
+	var value []byte
+	require.NoError(t, rlp.DecodeBytes(verify(result.StorageHash, crypto.Keccak256(slot.Bytes()), result.StorageProof[0].Proof), &value))
+	assert.Equal(t, []byte{0x11}, value)
+
+	// Accounts without storage are proven against the empty storage trie.
+	for _, addr := range []common.Address{eoa, missing} {
+		result, err := api.GetProof(context.Background(), addr, []string{slot.Hex()}, blockNrOrHash)
+		require.NoError(t, err)
+		assert.Equal(t, types.EmptyRootHashOriginal, result.StorageHash)
+		assert.Empty(t, result.StorageProof[0].Proof)
+		assert.Zero(t, result.StorageProof[0].Value.ToInt().Sign())
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/kaiachain/kaia/blockchain"
+	"github.com/kaiachain/kaia/blockchain/state"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/blockchain/vm"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/networks/rpc"
+	"github.com/kaiachain/kaia/params"
+)
+
+const (
+	// maxSimulationSessions is the maximum number of simulation sessions alive at once.
+	maxSimulationSessions = 16
+	// simulationSessionTimeout is the idle time after which a simulation session is dropped.
+	simulationSessionTimeout = 10 * time.Minute
+)
+
+var (
+	errSimulationNotFound    = errors.New("simulation session not found")
+	errTooManySimulations    = errors.New("too many simulation sessions")
+	errSimulationNoSnapshot  = errors.New("simulation snapshot not found")
+	errSimulationPendingRoot = errors.New("simulation session can't be started on the pending block")
+)
+
+// simulationSession is a state forked from a historical block. Its state is
+// never committed, so every write stays in memory on top of the forked root.
+type simulationSession struct {
+	lock      sync.Mutex
+	state     *state.StateDB
+	header    *types.Header
+	snapshots []*state.StateDB
+	txs       int
+	lastUsed  time.Time
+}
+
+// SimulationTxResult is the result of a transaction executed in a simulation session.
+type SimulationTxResult struct {
+	GasUsed    hexutil.Uint64 `json:"gasUsed"`
+	ReturnData hexutil.Bytes  `json:"returnData"`
+	Error      string         `json:"error,omitempty"`
+	Logs       []*types.Log   `json:"logs"`
+}
+
+// PrivateSimulationAPI provides simulation sessions, which keep a forked state
+// alive across RPC calls so that transactions can be executed on top of each
+// other without touching the chain.
+type PrivateSimulationAPI struct {
+	b        Backend
+	lock     sync.Mutex
+	sessions map[string]*simulationSession
+}
+
+// NewPrivateSimulationAPI creates a new API for simulation sessions.
+func NewPrivateSimulationAPI(b Backend) *PrivateSimulationAPI {
+	return &PrivateSimulationAPI{b: b, sessions: make(map[string]*simulationSession)}
+}
+
+// SimulationStart forks the state of the given block and returns the id of the new session.
+func (api *PrivateSimulationAPI) SimulationStart(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (string, error) {
+	if blockNr, ok := blockNrOrHash.Number(); ok && blockNr == rpc.PendingBlockNumber {
+		return "", errSimulationPendingRoot
+	}
+	statedb, header, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if statedb == nil || err != nil {
+		return "", err
+	}
+
+	api.lock.Lock()
+	defer api.lock.Unlock()
+
+	api.expireSessions()
+	if len(api.sessions) >= maxSimulationSessions {
+		return "", errTooManySimulations
+	}
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", err
+	}
+	id := hexutil.Encode(buf[:])
+	api.sessions[id] = &simulationSession{state: statedb, header: header, lastUsed: time.Now()}
+	logger.Debug("Started a simulation session", "id", id, "number", header.Number, "root", header.Root)
+	return id, nil
+}
+
+// SimulationStop drops the given simulation session.
+func (api *PrivateSimulationAPI) SimulationStop(id string) error {
+	api.lock.Lock()
+	defer api.lock.Unlock()
+
+	if _, ok := api.sessions[id]; !ok {
+		return errSimulationNotFound
+	}
+	delete(api.sessions, id)
+	return nil
+}
+
+// SimulationSetBalance sets the balance of the account in the simulation session.
+func (api *PrivateSimulationAPI) SimulationSetBalance(id string, address common.Address, balance hexutil.Big) error {
+	return api.update(id, func(s *simulationSession) error {
+		s.state.SetBalance(address, (*big.Int)(&balance))
+		return nil
+	})
+}
+
+// SimulationSetNonce sets the nonce of the account in the simulation session.
+func (api *PrivateSimulationAPI) SimulationSetNonce(id string, address common.Address, nonce hexutil.Uint64) error {
+	return api.update(id, func(s *simulationSession) error {
+		s.state.SetNonce(address, uint64(nonce))
+		return nil
+	})
+}
+
+// SimulationSetCode sets the code of the account in the simulation session.
+func (api *PrivateSimulationAPI) SimulationSetCode(id string, address common.Address, code hexutil.Bytes) error {
+	return api.update(id, func(s *simulationSession) error {
+		return s.state.SetCode(address, code)
+	})
+}
+
+// SimulationSetStorageAt sets a storage slot of the account in the simulation session.
+func (api *PrivateSimulationAPI) SimulationSetStorageAt(id string, address common.Address, key, value common.Hash) error {
+	return api.update(id, func(s *simulationSession) error {
+		s.state.SetState(address, key, value)
+		return nil
+	})
+}
+
+// SimulationGetBalance returns the balance of the account in the simulation session.
+func (api *PrivateSimulationAPI) SimulationGetBalance(id string, address common.Address) (*hexutil.Big, error) {
+	var balance *big.Int
+	err := api.update(id, func(s *simulationSession) error {
+		balance = s.state.GetBalance(address)
+		return nil
+	})
+	return (*hexutil.Big)(balance), err
+}
+
+// SimulationGetTransactionCount returns the nonce of the account in the simulation session.
+func (api *PrivateSimulationAPI) SimulationGetTransactionCount(id string, address common.Address) (hexutil.Uint64, error) {
+	var nonce uint64
+	err := api.update(id, func(s *simulationSession) error {
+		nonce = s.state.GetNonce(address)
+		return nil
+	})
+	return hexutil.Uint64(nonce), err
+}
+
+// SimulationGetCode returns the code of the account in the simulation session.
+func (api *PrivateSimulationAPI) SimulationGetCode(id string, address common.Address) (hexutil.Bytes, error) {
+	var code []byte
+	err := api.update(id, func(s *simulationSession) error {
+		code = s.state.GetCode(address)
+		return nil
+	})
+	return code, err
+}
+
+// SimulationGetStorageAt returns a storage slot of the account in the simulation session.
+func (api *PrivateSimulationAPI) SimulationGetStorageAt(id string, address common.Address, key common.Hash) (common.Hash, error) {
+	var value common.Hash
+	err := api.update(id, func(s *simulationSession) error {
+		value = s.state.GetState(address, key)
+		return nil
+	})
+	return value, err
+}
+
+// SimulationCall executes a call on the simulation session without keeping its changes.
+func (api *PrivateSimulationAPI) SimulationCall(ctx context.Context, id string, args CallArgs) (hexutil.Bytes, error) {
+	var result *blockchain.ExecutionResult
+	err := api.update(id, func(s *simulationSession) error {
+		var err error
+		result, _, err = doCallAtState(ctx, api.b, args, s.state.Copy(), s.header, api.vmConfig(), api.b.RPCEVMTimeout(), api.gasCap(), true)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Revert()) > 0 {
+		return nil, blockchain.NewRevertError(result)
+	}
+	return result.Return(), result.Unwrap()
+}
+
+// SimulationSendTransaction executes a transaction on the simulation session and
+// keeps its changes. The sender pays for the gas, and the transaction isn't signed,
+// so any account can be impersonated.
+func (api *PrivateSimulationAPI) SimulationSendTransaction(ctx context.Context, id string, args CallArgs) (*SimulationTxResult, error) {
+	var res *SimulationTxResult
+	err := api.update(id, func(s *simulationSession) error {
+		// Execute on a copy, so that a failed execution leaves the session intact.
+		statedb := s.state.Copy()
+		txHash := common.BigToHash(big.NewInt(int64(s.txs + 1)))
+		statedb.SetTxContext(txHash, s.header.Hash(), s.txs)
+		result, _, err := doCallAtState(ctx, api.b, args, statedb, s.header, api.vmConfig(), api.b.RPCEVMTimeout(), api.gasCap(), false)
+		if err != nil {
+			return err
+		}
+		statedb.Finalise(true, true)
+		s.state, s.txs = statedb, s.txs+1
+
+		res = &SimulationTxResult{
+			GasUsed:    hexutil.Uint64(result.UsedGas),
+			ReturnData: result.Return(),
+			Logs:       statedb.GetLogs(txHash),
+		}
+		if err := result.Unwrap(); err != nil {
+			res.Error = err.Error()
+		}
+		return nil
+	})
+	return res, err
+}
+
+// SimulationSnapshot saves the current state of the simulation session and
+// returns the id to revert to it with SimulationRevert.
+func (api *PrivateSimulationAPI) SimulationSnapshot(id string) (hexutil.Uint, error) {
+	var snapshot int
+	err := api.update(id, func(s *simulationSession) error {
+		s.snapshots = append(s.snapshots, s.state.Copy())
+		snapshot = len(s.snapshots) - 1
+		return nil
+	})
+	return hexutil.Uint(snapshot), err
+}
+
+// SimulationRevert reverts the simulation session to the given snapshot.
+// The snapshot and all the later ones are dropped.
+func (api *PrivateSimulationAPI) SimulationRevert(id string, snapshot hexutil.Uint) error {
+	return api.update(id, func(s *simulationSession) error {
+		if int(snapshot) >= len(s.snapshots) {
+			return errSimulationNoSnapshot
+		}
+		s.state = s.snapshots[snapshot]
+		s.snapshots = s.snapshots[:snapshot]
+		return nil
+	})
+}
+
+// update runs fn on the given simulation session exclusively.
+func (api *PrivateSimulationAPI) update(id string, fn func(s *simulationSession) error) error {
+	api.lock.Lock()
+	api.expireSessions()
+	s, ok := api.sessions[id]
+	api.lock.Unlock()
+	if !ok {
+		return errSimulationNotFound
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.lastUsed = time.Now()
+	return fn(s)
+}
+
+// expireSessions drops the sessions idle for longer than simulationSessionTimeout.
+// The caller must hold api.lock.
+func (api *PrivateSimulationAPI) expireSessions() {
+	for id, s := range api.sessions {
+		if s.lock.TryLock() {
+			expired := time.Since(s.lastUsed) > simulationSessionTimeout
+			s.lock.Unlock()
+			if expired {
+				logger.Debug("Dropped an idle simulation session", "id", id)
+				delete(api.sessions, id)
+			}
+		}
+	}
+}
+
+func (api *PrivateSimulationAPI) vmConfig() vm.Config {
+	return vm.Config{ComputationCostLimit: params.OpcodeComputationCostLimitInfinite}
+}
+
+func (api *PrivateSimulationAPI) gasCap() *big.Int {
+	if gasCap := api.b.RPCGasCap(); gasCap != nil {
+		return gasCap
+	}
+	return big.NewInt(0)
+}
@@ -0,0 +1,122 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mock_api "github.com/kaiachain/kaia/api/mocks"
+	"github.com/kaiachain/kaia/blockchain"
+	"github.com/kaiachain/kaia/blockchain/state"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/blockchain/vm"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/networks/rpc"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivateSimulationAPI(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockBackend := mock_api.NewMockBackend(mockCtrl)
+
+	var (
+		account1 = common.HexToAddress("0xaaaa")
+		account2 = common.HexToAddress("0xbbbb")
+		slot     = common.HexToHash("0x01")
+		gspec    = &blockchain.Genesis{Alloc: blockchain.GenesisAlloc{
+			account1: {Balance: big.NewInt(params.KAIA)},
+		}, Config: dummyChainConfigForEthereumAPITest}
+		dbm    = database.NewMemoryDBManager()
+		db     = state.NewDatabase(dbm)
+		block  = gspec.MustCommit(dbm)
+		header = block.Header()
+		chain  = &testChainContext{header: header}
+	)
+	any := gomock.Any()
+	mockBackend.EXPECT().ChainConfig().Return(dummyChainConfigForEthereumAPITest).AnyTimes()
+	mockBackend.EXPECT().RPCGasCap().Return(nil).AnyTimes()
+	mockBackend.EXPECT().RPCEVMTimeout().Return(5 * time.Second).AnyTimes()
+	mockBackend.EXPECT().StateAndHeaderByNumberOrHash(any, any).DoAndReturn(func(...interface{}) (*state.StateDB, *types.Header, error) {
+		state, err := state.New(block.Root(), db, nil, nil)
+		return state, header, err
+	}).AnyTimes()
+	mockBackend.EXPECT().GetEVM(any, any, any, any, any).DoAndReturn(func(_ context.Context, msg blockchain.Message, state *state.StateDB, header *types.Header, vmConfig vm.Config) (*vm.EVM, func() error, error) {
+		txContext := blockchain.NewEVMTxContext(msg, header, dummyChainConfigForEthereumAPITest)
+		blockContext := blockchain.NewEVMBlockContext(header, chain, nil)
+		return vm.NewEVM(blockContext, txContext, state, dummyChainConfigForEthereumAPITest, &vmConfig), func() error { return nil }, nil
+	}).AnyTimes()
+
+	api := NewPrivateSimulationAPI(mockBackend)
+	ctx := context.Background()
+	id, err := api.SimulationStart(ctx, rpc.NewBlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+	require.NoError(t, err)
+
+	// Transfers are kept in the session.
+	value := hexutil.Big(*big.NewInt(100))
+	for i := 0; i < 2; i++ {
+		res, err := api.SimulationSendTransaction(ctx, id, CallArgs{From: account1, To: &account2, Gas: 21000, Value: value})
+		require.NoError(t, err)
+		assert.Equal(t, hexutil.Uint64(21000), res.GasUsed)
+		assert.Empty(t, res.Error)
+	}
+	balance, err := api.SimulationGetBalance(id, account2)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(200), balance.ToInt())
+	nonce, err := api.SimulationGetTransactionCount(id, account1)
+	require.NoError(t, err)
+	assert.Equal(t, hexutil.Uint64(2), nonce)
+
+	// Overrides are applied to the session only.
+	snapshot, err := api.SimulationSnapshot(id)
+	require.NoError(t, err)
+	require.NoError(t, api.SimulationSetStorageAt(id, account2, slot, common.HexToHash("0x11")))
+	require.NoError(t, api.SimulationSetBalance(id, account2, hexutil.Big(*big.NewInt(1))))
+	stored, err := api.SimulationGetStorageAt(id, account2, slot)
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToHash("0x11"), stored)
+
+	require.NoError(t, api.SimulationRevert(id, snapshot))
+	assert.ErrorIs(t, api.SimulationRevert(id, snapshot), errSimulationNoSnapshot)
+	balance, err = api.SimulationGetBalance(id, account2)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(200), balance.ToInt())
+
+	// The chain state is left untouched.
+	chainState, err := state.New(block.Root(), db, nil, nil)
+	require.NoError(t, err)
+	assert.Zero(t, chainState.GetBalance(account2).Sign())
+
+	// A failed transaction leaves the session intact.
+	_, err = api.SimulationSendTransaction(ctx, id, CallArgs{From: account2, To: &account1, Gas: 21000, Value: hexutil.Big(*big.NewInt(1000))})
+	assert.Error(t, err)
+	balance, err = api.SimulationGetBalance(id, account2)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(200), balance.ToInt())
+
+	require.NoError(t, api.SimulationStop(id))
+	_, err = api.SimulationGetBalance(id, account2)
+	assert.ErrorIs(t, err, errSimulationNotFound)
+}
@@ -31,6 +31,7 @@ import (
 	"time"
 
 	"github.com/kaiachain/kaia/blockchain"
+	"github.com/kaiachain/kaia/blockchain/state"
 	"github.com/kaiachain/kaia/blockchain/types"
 	"github.com/kaiachain/kaia/blockchain/types/account"
 	"github.com/kaiachain/kaia/blockchain/types/accountkey"
@@ -108,6 +109,9 @@ func (s *PublicBlockChainAPI) GetBlockReceipts(ctx context.Context, blockNrOrHas
 	receipts := s.b.GetBlockReceipts(ctx, blockHash)
 	txs := block.Transactions()
 	if receipts.Len() != txs.Len() {
+		if err := blockchain.CheckReceiptsPruned(s.b.ChainDB(), block.NumberU64()); err != nil {
+			return nil, err
+		}
 		return nil, fmt.Errorf("the size of transactions and receipts is different in the block (%s)", blockHash.String())
 	}
 	fieldsList := make([]map[string]interface{}, 0, len(receipts))
@@ -118,6 +122,16 @@ func (s *PublicBlockChainAPI) GetBlockReceipts(ctx context.Context, blockNrOrHas
 	return fieldsList, nil
 }
 
+// checkTxReceiptPruned returns blockchain.ErrReceiptsPruned if the receipt of the
+// given transaction is missing because the receipts of its block are pruned.
+func checkTxReceiptPruned(b Backend, txHash common.Hash) error {
+	blockHash, blockNumber, _ := b.ChainDB().ReadTxLookupEntry(txHash)
+	if common.EmptyHash(blockHash) {
+		return nil
+	}
+	return blockchain.CheckReceiptsPruned(b.ChainDB(), blockNumber)
+}
+
 // GetBalance returns the amount of kei for the given address in the state of the
 // given block number or hash. The rpc.LatestBlockNumber and rpc.PendingBlockNumber meta
 // block numbers and hash are also allowed.
@@ -315,6 +329,12 @@ func DoCall(ctx context.Context, b Backend, args CallArgs, blockNrOrHash rpc.Blo
 	if state == nil || err != nil {
 		return nil, 0, err
 	}
+	return doCallAtState(ctx, b, args, state, header, vmCfg, timeout, globalGasCap, true)
+}
+
+// doCallAtState executes the call on top of the given state, which is modified by the call.
+// If prefund is set, the sender is funded with the gas fee before the execution.
+func doCallAtState(ctx context.Context, b Backend, args CallArgs, state *state.StateDB, header *types.Header, vmCfg vm.Config, timeout time.Duration, globalGasCap *big.Int, prefund bool) (*blockchain.ExecutionResult, uint64, error) {
 	// Setup context so it may be cancelled the call has completed
 	// or, in case of unmetered gas, setup a context with a timeout.
 	var cancel context.CancelFunc
@@ -345,7 +365,9 @@ func DoCall(ctx context.Context, b Backend, args CallArgs, blockNrOrHash rpc.Blo
 	}
 
 	// Add gas fee to sender for estimating gasLimit/computing cost or calling a function by insufficient balance sender.
-	state.AddBalance(msg.ValidatedSender(), new(big.Int).Mul(new(big.Int).SetUint64(msg.Gas()), msg.EffectiveGasPrice(header, b.ChainConfig())))
+	if prefund {
+		state.AddBalance(msg.ValidatedSender(), new(big.Int).Mul(new(big.Int).SetUint64(msg.Gas()), msg.EffectiveGasPrice(header, b.ChainConfig())))
+	}
 
 	// The intrinsicGas is checked again later in the blockchain.ApplyMessage function,
 	// but we check in advance here in order to keep StateTransition.TransactionDb method as unchanged as possible
@@ -643,7 +665,7 @@ func newRPCTransaction(header *types.Header, tx *types.Transaction, blockHash co
 	output["from"] = getFrom(tx)
 	output["hash"] = tx.Hash()
 	output["transactionIndex"] = hexutil.Uint(index)
-	if tx.Type() == types.TxTypeEthereumDynamicFee {
+	if tx.Type() == types.TxTypeEthereumDynamicFee || tx.Type() == types.TxTypeEthereumBlob {
 		if header != nil {
 			output["gasPrice"] = (*hexutil.Big)(tx.EffectiveGasPrice(header, config))
 		} else {
@@ -29,7 +29,9 @@ import (
 	"github.com/kaiachain/kaia/networks/p2p"
 )
 
-// PublicNetAPI offers network related RPC methods
+// PublicNetAPI offers network related RPC methods. It covers the whole net namespace of
+// the Ethereum JSON-RPC, i.e. net_version, net_listening and net_peerCount, and adds
+// net_peerCountByType and net_networkID.
 type PublicNetAPI struct {
 	net            p2p.Server
 	networkVersion uint64
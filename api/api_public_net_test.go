@@ -0,0 +1,47 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"testing"
+
+	"github.com/kaiachain/kaia/networks/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPublicNetAPIMethods checks that the net namespace serves every net_* method of the Ethereum JSON-RPC.
+func TestPublicNetAPIMethods(t *testing.T) {
+	server := rpc.NewServer()
+	defer server.Stop()
+	assert.NoError(t, server.RegisterName("net", NewPublicNetAPI(nil, 1001)))
+
+	methods := server.Methods()["net"]
+	for _, method := range []string{"version", "listening", "peerCount"} {
+		assert.Contains(t, methods, method)
+	}
+
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	var version string
+	assert.NoError(t, client.Call(&version, "net_version"))
+	assert.Equal(t, "1001", version)
+
+	var listening bool
+	assert.NoError(t, client.Call(&listening, "net_listening"))
+	assert.True(t, listening)
+}
@@ -258,6 +258,11 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceiptBySenderTxHash(ctx conte
 // GetTransactionReceipt returns the transaction receipt for the given transaction hash.
 func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
 	tx, blockHash, blockNumber, index, receipt := s.b.GetTxLookupInfoAndReceipt(ctx, hash)
+	if tx == nil {
+		if err := checkTxReceiptPruned(s.b, hash); err != nil {
+			return nil, err
+		}
+	}
 	return s.getTransactionReceipt(ctx, tx, blockHash, blockNumber, index, receipt)
 }
 
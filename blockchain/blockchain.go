@@ -135,6 +135,25 @@ type CacheConfig struct {
 	TrieNodeCacheConfig  *statedb.TrieNodeCacheConfig // Configures trie node cache
 	SnapshotCacheSize    int                          // Memory allowance (MB) to use for caching snapshot entries in memory
 	SnapshotAsyncGen     bool                         // Enables snapshot data generation asynchronously
+
+	OnlinePruning           bool          // Deletes the trie nodes unreachable from the recent state periodically while the node runs
+	OnlinePruningRetention  uint64        // Number of the recent blocks whose state is kept by the online pruning
+	OnlinePruningInterval   time.Duration // Interval between the online pruning sessions
+	OnlinePruningRate       int           // Maximum number of the trie nodes read or deleted per second by the online pruning. If zero, unlimited.
+	OnlinePruningBloomSize  uint64        // Size (MiB) of the bloom filter marking the reachable trie nodes
+	OnlinePruningCheckpoint string        // File keeping the bloom filter of an interrupted online pruning session. If empty, the session is not resumed.
+
+	ReceiptRetention uint64 // Number of the recent blocks whose receipts are kept. If zero, receipts are not pruned.
+
+	StateDiff        bool // Records the accounts and storage slots changed by each imported block
+	StateDiffPersist bool // Writes the recorded state diffs to the database (implies StateDiff)
+
+	TrieJournal string // File keeping the in-memory state tries of the recent blocks across restarts. If empty, they're dropped on shutdown.
+
+	TrieScheme       string // Commitment scheme of the state tries. If empty, the MPT scheme.
+	TrieMirrorScheme string // Commitment scheme the writes to the state tries are mirrored to. If empty, they're not mirrored.
+
+	TrieIntegrityCheckRate uint64 // One out of TrieIntegrityCheckRate trie nodes written to the disk is verified in the background. If zero, nothing is verified.
 }
 
 // gcBlock is used for priority queue for GC.
@@ -167,12 +186,23 @@ type BlockChain struct {
 	chBlock chan gcBlock       // chPushBlockGCPrque is a channel for delivering the gc item to gc loop.
 	chPrune chan uint64        // chPrune is a channel for delivering the current block number for pruning loop.
 
+	chPruneReceipts chan uint64 // chPruneReceipts delivers the current block number to the receipt pruning loop.
+
+	statePruningRunning atomic.Bool         // true while an online state pruning session runs
+	statePruningMu      sync.Mutex          // protects the fields below
+	statePruner         *statePruner        // the running online state pruning session
+	statePruningStatus  *StatePruningStatus // the status of the latest online state pruning session
+
+	statePinsMu sync.Mutex                // protects statePins
+	statePins   map[common.Hash]*statePin // the state roots pinned against the garbage collection
+
 	hc            *HeaderChain
 	rmLogsFeed    event.Feed
 	chainFeed     event.Feed
 	chainSideFeed event.Feed
 	chainHeadFeed event.Feed
 	logsFeed      event.Feed
+	corruptFeed   event.Feed
 	scope         event.SubscriptionScope
 	genesisBlock  *types.Block
 
@@ -187,6 +217,8 @@ type BlockChain struct {
 	// future blocks are blocks added for later processing
 	futureBlocks *lru.Cache
 
+	stateDiffCache *lru.Cache // Cache for the state diffs of the recent blocks
+
 	quit    chan struct{} // blockchain quit channel
 	running int32         // running must be called atomically
 	// procInterrupt must be atomically called
@@ -220,6 +252,7 @@ type BlockChain struct {
 	// kaiax modules
 	executionModules  []kaiax.ExecutionModule
 	rewindableModules []kaiax.RewindableModule
+	txProcessModules  []kaiax.TxProcessModule
 }
 
 // prefetchTx is used to prefetch transactions, when fetcher works.
@@ -252,7 +285,12 @@ func NewBlockChain(db database.DBManager, cacheConfig *CacheConfig, chainConfig
 
 	state.EnabledExpensive = db.GetDBConfig().EnableDBPerfMetrics
 
+	stateCache, err := state.NewDatabaseWithTrieSchemes(db, cacheConfig.TrieNodeCacheConfig, cacheConfig.TrieScheme, cacheConfig.TrieMirrorScheme)
+	if err != nil {
+		return nil, err
+	}
 	futureBlocks, _ := lru.New(maxFutureBlocks)
+	stateDiffCache, _ := lru.New(stateDiffCacheLimit)
 
 	bc := &BlockChain{
 		chainConfig:        chainConfig,
@@ -261,9 +299,12 @@ func NewBlockChain(db database.DBManager, cacheConfig *CacheConfig, chainConfig
 		triegc:             prque.New(),
 		chBlock:            make(chan gcBlock, 2048), // downloader.maxResultsProcess
 		chPrune:            make(chan uint64, 2048),  // downloader.maxResultsProcess
-		stateCache:         state.NewDatabaseWithNewCache(db, cacheConfig.TrieNodeCacheConfig),
+		chPruneReceipts:    make(chan uint64, 1),
+		statePins:          make(map[common.Hash]*statePin),
+		stateCache:         stateCache,
 		quit:               make(chan struct{}),
 		futureBlocks:       futureBlocks,
+		stateDiffCache:     stateDiffCache,
 		engine:             engine,
 		vmConfig:           vmConfig,
 		parallelDBWrite:    db.IsParallelDBWrite(),
@@ -280,7 +321,6 @@ func NewBlockChain(db database.DBManager, cacheConfig *CacheConfig, chainConfig
 	bc.prefetcher = newStatePrefetcher(chainConfig, bc, engine)
 	bc.processor = NewStateProcessor(chainConfig, bc, engine)
 
-	var err error
 	bc.hc, err = NewHeaderChain(db, chainConfig, engine, bc.getProcInterrupt)
 	if err != nil {
 		return nil, err
@@ -341,6 +381,13 @@ func NewBlockChain(db database.DBManager, cacheConfig *CacheConfig, chainConfig
 		}
 	}
 
+	// Restore the state tries of the recent blocks journalled on the last shutdown
+	if bc.cacheConfig.TrieJournal != "" && !bc.isArchiveMode() {
+		if err := bc.loadTrieJournal(); err != nil {
+			logger.Warn("Failed to load state trie journal", "err", err)
+		}
+	}
+
 	// Load any existing snapshot, regenerating it if loading failed
 	if bc.cacheConfig.SnapshotCacheSize > 0 {
 		// If the chain was rewound past the snapshot persistent layer (causing
@@ -367,7 +414,22 @@ func NewBlockChain(db database.DBManager, cacheConfig *CacheConfig, chainConfig
 	go bc.update()
 	bc.gcCachedNodeLoop()
 	bc.pruneTrieNodeLoop()
+	if cacheConfig.OnlinePruning {
+		bc.statePruningLoop()
+	}
+	if cacheConfig.ReceiptRetention != 0 {
+		bc.receiptPruningLoop()
+	}
 	bc.restartStateMigration()
+	bc.resumeStatePruning()
+	if cacheConfig.TrieIntegrityCheckRate != 0 {
+		bc.stateCache.TrieDB().StartIntegrityCheck(statedb.IntegrityCheckConfig{
+			SampleRate: cacheConfig.TrieIntegrityCheckRate,
+			OnCorrupt: func(hash common.ExtHash) {
+				bc.corruptFeed.Send(TrieCorruptionEvent{Hash: hash})
+			},
+		})
+	}
 
 	if cacheConfig.TrieNodeCacheConfig.DumpPeriodically() {
 		logger.Info("LocalCache is used for trie node cache, start saving cache to file periodically",
@@ -628,12 +690,14 @@ func (bc *BlockChain) setHeadBeyondRoot(head uint64, root common.Hash, repair bo
 
 	// Rewind the header chain, deleting all block bodies until then
 	delFn := func(hash common.Hash, num uint64) {
-		// Remove relative body, receipts, header-governance database,
+		// Remove relative body, receipts, state diff, header-governance database,
 		// istanbul snapshot database, and staking info database from the active store.
 		// The header, total difficulty and canonical hash will be
 		// removed in the hc.SetHead function.
 		bc.db.DeleteBody(hash, num)
 		bc.db.DeleteReceipts(hash, num)
+		bc.db.DeleteStateDiff(hash, num)
+		bc.stateDiffCache.Remove(hash)
 		bc.db.DeleteGovernance(num)
 		if params.IsCheckpointInterval(num) {
 			bc.db.DeleteIstanbulSnapshot(hash)
@@ -759,14 +823,23 @@ func (bc *BlockChain) StateAt(root common.Hash) (*state.StateDB, error) {
 
 // PrunableStateAt returns a new mutable state based on a particular point in time.
 // If live pruning is enabled on the databse, and num is nonzero, then trie will mark obsolete nodes for pruning.
+// If recording state diffs is enabled, the state records the changes made on it.
 func (bc *BlockChain) PrunableStateAt(root common.Hash, num uint64) (*state.StateDB, error) {
+	var (
+		stateDB *state.StateDB
+		err     error
+	)
 	if bc.IsLivePruningRequired() {
-		return state.New(root, bc.stateCache, bc.snaps, &statedb.TrieOpts{
+		stateDB, err = state.New(root, bc.stateCache, bc.snaps, &statedb.TrieOpts{
 			PruningBlockNumber: num,
 		})
 	} else {
-		return bc.StateAt(root)
+		stateDB, err = bc.StateAt(root)
+	}
+	if err == nil && bc.IsStateDiffEnabled() {
+		stateDB.EnableStateDiff()
 	}
+	return stateDB, err
 }
 
 // StateAtWithPersistent returns a new mutable state based on a particular point in time with persistent trie nodes.
@@ -1099,6 +1172,8 @@ func (bc *BlockChain) Stop() {
 	atomic.StoreInt32(&bc.procInterrupt, 1)
 
 	bc.wg.Wait()
+	bc.releaseStatePins()
+	bc.stateCache.TrieDB().StopIntegrityCheck()
 
 	// Ensure that the entirety of the state snapshot is journalled to disk.
 	var snapBase common.Hash
@@ -1128,8 +1203,18 @@ func (bc *BlockChain) Stop() {
 				logger.Error("Failed to commit recent state trie", "err", err)
 			}
 		}
+		var gcBlocks []gcBlock
 		for !bc.triegc.Empty() {
-			triedb.Dereference(bc.triegc.PopItem().(common.Hash))
+			root, number := bc.triegc.Pop()
+			gcBlocks = append(gcBlocks, gcBlock{root.(common.Hash), uint64(-number)})
+		}
+		if bc.cacheConfig.TrieJournal != "" {
+			if err := bc.writeTrieJournal(gcBlocks); err != nil {
+				logger.Error("Failed to journal state tries", "err", err)
+			}
+		}
+		for _, block := range gcBlocks {
+			triedb.Dereference(block.root)
 		}
 		if size, _, _ := triedb.Size(); size != 0 {
 			logger.Error("Dangling trie nodes after full cleanup")
@@ -1583,6 +1668,8 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	if err != nil {
 		return status, err
 	}
+	bc.writeStateDiff(block, stateDB)
+	bc.notifyReceiptPruning(block.NumberU64())
 
 	// Publish the committed block to the redis cache of stateDB.
 	// The cache uses the block to distinguish the latest state.
@@ -2132,6 +2219,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks) (int, []interface{}, []*ty
 			blockFinalizeTimer.Update(time.Duration(processFinalizeTime))
 			blockValidateTimer.Update(time.Duration(validateTime))
 			blockInsertTimer.Update(time.Duration(totalTime))
+			bc.db.ReportBlockImportTime(time.Duration(totalTime))
 
 			coalescedLogs = append(coalescedLogs, logs...)
 			events = append(events, ChainEvent{
@@ -2730,6 +2818,11 @@ func (bc *BlockChain) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscript
 	return bc.scope.Track(bc.logsFeed.Subscribe(ch))
 }
 
+// SubscribeTrieCorruptionEvent registers a subscription of TrieCorruptionEvent.
+func (bc *BlockChain) SubscribeTrieCorruptionEvent(ch chan<- TrieCorruptionEvent) event.Subscription {
+	return bc.scope.Track(bc.corruptFeed.Subscribe(ch))
+}
+
 // isArchiveMode returns whether current blockchain is in archiving mode or not.
 // cacheConfig.ArchiveMode means trie caching is disabled.
 func (bc *BlockChain) isArchiveMode() bool {
@@ -2782,16 +2875,36 @@ func (bc *BlockChain) ApplyTransaction(chainConfig *params.ChainConfig, author *
 		return nil, nil, err
 	}
 	// Create a new context to be used in the EVM environment
-	blockContext := NewEVMBlockContext(header, bc, author)
+	var (
+		chain            ChainContext
+		txProcessModules []kaiax.TxProcessModule
+	)
+	if bc != nil { // bc is nil when GenerateChain builds blocks without a chain
+		chain = bc
+		txProcessModules = bc.txProcessModules
+	}
+	blockContext := NewEVMBlockContext(header, chain, author)
 	txContext := NewEVMTxContext(msg, header, chainConfig)
 	// Create a new environment which holds all relevant information
 	// about the transaction and calling mechanisms.
 	vmenv := vm.NewEVM(blockContext, txContext, statedb, chainConfig, vmConfig)
+	// Let the modules veto the transaction. The message is already derived from tx,
+	// so a transformed tx returned by a module is not applied here.
+	for _, module := range txProcessModules {
+		if _, err := module.PreRunTx(vmenv, tx); err != nil {
+			return nil, nil, err
+		}
+	}
 	// Apply the transaction to the current state (included in the env)
 	result, err := ApplyMessage(vmenv, msg)
 	if err != nil {
 		return nil, nil, err
 	}
+	for _, module := range txProcessModules {
+		if err := module.PostRunTx(vmenv, tx); err != nil {
+			return nil, nil, err
+		}
+	}
 
 	var internalTrace *vm.InternalTxTrace
 	if vmConfig.EnableInternalTxTracing {
@@ -2822,6 +2935,10 @@ func (bc *BlockChain) RegisterRewindableModule(modules ...kaiax.RewindableModule
 	bc.rewindableModules = append(bc.rewindableModules, modules...)
 }
 
+func (bc *BlockChain) RegisterTxProcessModule(modules ...kaiax.TxProcessModule) {
+	bc.txProcessModules = append(bc.txProcessModules, modules...)
+}
+
 func GetInternalTxTrace(tracer vm.Tracer) (*vm.InternalTxTrace, error) {
 	var (
 		internalTxTrace *vm.InternalTxTrace
@@ -2847,11 +2964,22 @@ func GetInternalTxTrace(tracer vm.Tracer) (*vm.InternalTxTrace, error) {
 }
 
 // CheckBlockChainVersion checks the version of the current database and upgrade if possible.
+// It also refuses the database last opened by a newer version of Kaia, and then marks the
+// database with the running version.
 func CheckBlockChainVersion(chainDB database.DBManager) error {
 	bcVersion := chainDB.ReadDatabaseVersion()
 	if bcVersion != nil && *bcVersion > BlockChainVersion {
-		return fmt.Errorf("database version is v%d, Kaia %s only supports v%d", *bcVersion, params.Version, BlockChainVersion)
-	} else if bcVersion == nil || *bcVersion < BlockChainVersion {
+		return fmt.Errorf("database version is v%d, Kaia %s only supports v%d. "+
+			"Run `kcn db downgrade --to v%d` with a newer Kaia if a reverse migration is available", *bcVersion, params.Version, BlockChainVersion, BlockChainVersion)
+	}
+	if err := checkDatabaseAppVersion(chainDB.ReadDatabaseAppVersion()); err != nil {
+		return err
+	}
+	if chainDB.IsReadOnly() {
+		// The versions are upgraded by the node owning the databases.
+		return nil
+	}
+	if bcVersion == nil || *bcVersion < BlockChainVersion {
 		bcVersionStr := "N/A"
 		if bcVersion != nil {
 			bcVersionStr = strconv.Itoa(int(*bcVersion))
@@ -2859,5 +2987,6 @@ func CheckBlockChainVersion(chainDB database.DBManager) error {
 		logger.Warn("Upgrade database version", "from", bcVersionStr, "to", BlockChainVersion)
 		chainDB.WriteDatabaseVersion(BlockChainVersion)
 	}
+	chainDB.WriteDatabaseAppVersion(params.Version)
 	return nil
 }
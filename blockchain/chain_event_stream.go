@@ -0,0 +1,141 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"fmt"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/event"
+)
+
+// chainEventStreamHeadChanSize is the size of the channel listening to ChainHeadEvent
+// for a chain event stream. Head events are only used as wake-up signals.
+const chainEventStreamHeadChanSize = 10
+
+// SubscribeChainEventFrom registers a subscription of ChainEvent for the canonical blocks
+// from the given block number on. Unlike SubscribeChainEvent, events are delivered in
+// strictly increasing block number order without gaps, starting with the replay of the
+// blocks already in the chain. A slow subscriber never blocks the block insertion;
+// it just lags behind the head and catches up later.
+//
+// If the delivered blocks are reorganized away, the stream rewinds to the common ancestor
+// of the delivered blocks and the new canonical chain, and continues with the new canonical
+// block following it. A subscriber thus sees a block number not greater than the previous
+// one only after a reorg, and should discard what it derived from the replaced blocks.
+//
+// The replayed events do not carry InternalTxTraces. The subscription fails if a block
+// to be delivered is not available in the database.
+func (bc *BlockChain) SubscribeChainEventFrom(from uint64, ch chan<- ChainEvent) event.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		headCh := make(chan ChainHeadEvent, chainEventStreamHeadChanSize)
+		headSub := bc.SubscribeChainHeadEvent(headCh)
+		defer headSub.Unsubscribe()
+
+		// Coalesce head events so that the feed is never blocked by this subscriber.
+		notify := make(chan struct{}, 1)
+		go func() {
+			for {
+				select {
+				case <-headCh:
+					select {
+					case notify <- struct{}{}:
+					default:
+					}
+				case <-headSub.Err():
+					return
+				}
+			}
+		}()
+
+		var (
+			next = from
+			last common.Hash // the hash of the last delivered block, which is at next-1
+		)
+		for {
+			for head := bc.CurrentBlock().NumberU64(); ; {
+				// Rewind if the last delivered block is no longer canonical
+				if last != (common.Hash{}) && bc.db.ReadCanonicalHash(next-1) != last {
+					var err error
+					if next, last, err = bc.rewindChainEventStream(from, next-1, last); err != nil {
+						return err
+					}
+					head = bc.CurrentBlock().NumberU64()
+				}
+				if next > head {
+					break
+				}
+				block := bc.GetBlockByNumber(next)
+				if block == nil {
+					return fmt.Errorf("block %d is not available", next)
+				}
+				if last != (common.Hash{}) && block.ParentHash() != last {
+					continue // reorganized in the meantime
+				}
+				select {
+				case ch <- newChainEvent(block, bc.GetReceiptsByBlockHash(block.Hash())):
+				case <-quit:
+					return nil
+				}
+				next, last = next+1, block.Hash()
+			}
+
+			select {
+			case <-notify:
+			case err := <-headSub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	})
+}
+
+// rewindChainEventStream finds the common ancestor of the canonical chain and the delivered
+// block of the given number and hash, walking back no further than from. It returns the number
+// of the block to deliver next and the hash of the ancestor, which is empty if none of the
+// delivered blocks is canonical anymore.
+func (bc *BlockChain) rewindChainEventStream(from, num uint64, hash common.Hash) (uint64, common.Hash, error) {
+	for {
+		if bc.db.ReadCanonicalHash(num) == hash {
+			return num + 1, hash, nil
+		}
+		if num == from {
+			return from, common.Hash{}, nil
+		}
+		header := bc.GetHeader(hash, num)
+		if header == nil {
+			return 0, common.Hash{}, fmt.Errorf("block %d (%x) is not available", num, hash)
+		}
+		hash, num = header.ParentHash, num-1
+	}
+}
+
+// newChainEvent assembles a ChainEvent of the given canonical block and its receipts.
+func newChainEvent(block *types.Block, receipts types.Receipts) ChainEvent {
+	var logs []*types.Log
+	for _, receipt := range receipts {
+		logs = append(logs, receipt.Logs...)
+	}
+	return ChainEvent{
+		Block:    block,
+		Hash:     block.Hash(),
+		Receipts: receipts,
+		Logs:     logs,
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kaiachain/kaia/consensus/gxhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribeChainEventFrom(t *testing.T) {
+	engine := gxhash.NewFaker()
+	db, bc, err := newCanonical(engine, 5, true)
+	require.NoError(t, err)
+	defer bc.Stop()
+
+	expect := func(ch <-chan ChainEvent, from, to uint64) {
+		for num := from; num <= to; num++ {
+			select {
+			case ev := <-ch:
+				assert.Equal(t, num, ev.Block.NumberU64())
+				assert.Equal(t, bc.GetBlockByNumber(num).Hash(), ev.Hash)
+			case <-time.After(time.Second):
+				t.Fatalf("timeout waiting for block %d", num)
+			}
+		}
+	}
+	expectNone := func(ch <-chan ChainEvent) {
+		select {
+		case ev := <-ch:
+			t.Fatalf("unexpected block %d", ev.Block.NumberU64())
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	// Replay from the past, and a late start in the future
+	pastCh, futureCh := make(chan ChainEvent), make(chan ChainEvent)
+	pastSub := bc.SubscribeChainEventFrom(2, pastCh)
+	defer pastSub.Unsubscribe()
+	futureSub := bc.SubscribeChainEventFrom(7, futureCh)
+	defer futureSub.Unsubscribe()
+
+	expect(pastCh, 2, 5)
+	expectNone(pastCh)
+	expectNone(futureCh)
+
+	// New blocks are delivered in order even if the subscribers do not keep up
+	blocks := MakeBlockChain(bc.CurrentBlock(), 4, engine, db, canonicalSeed)
+	_, err = bc.InsertChain(blocks)
+	require.NoError(t, err)
+
+	expect(pastCh, 6, 9)
+	expect(futureCh, 7, 9)
+	expectNone(pastCh)
+	expectNone(futureCh)
+}
+
+func TestSubscribeChainEventFromReorg(t *testing.T) {
+	engine := gxhash.NewFaker()
+	db, bc, err := newCanonical(engine, 5, true)
+	require.NoError(t, err)
+	defer bc.Stop()
+
+	expect := func(ch <-chan ChainEvent, from, to uint64) {
+		for num := from; num <= to; num++ {
+			select {
+			case ev := <-ch:
+				assert.Equal(t, num, ev.Block.NumberU64())
+				assert.Equal(t, bc.GetBlockByNumber(num).Hash(), ev.Hash)
+			case <-time.After(time.Second):
+				t.Fatalf("timeout waiting for block %d", num)
+			}
+		}
+	}
+
+	ch := make(chan ChainEvent)
+	sub := bc.SubscribeChainEventFrom(2, ch)
+	defer sub.Unsubscribe()
+	expect(ch, 2, 5)
+	replaced := bc.GetBlockByNumber(4).Hash()
+
+	// A longer fork from block 3 replaces the delivered blocks 4 and 5,
+	// so the stream rewinds to block 3 and delivers the new blocks 4 to 7.
+	fork := MakeBlockChain(bc.GetBlockByNumber(3), 4, engine, db, forkSeed)
+	_, err = bc.InsertChain(fork)
+	require.NoError(t, err)
+	require.Equal(t, fork[len(fork)-1].Hash(), bc.CurrentBlock().Hash())
+	require.NotEqual(t, replaced, bc.GetBlockByNumber(4).Hash())
+
+	expect(ch, 4, 7)
+}
@@ -77,6 +77,7 @@ type ChainIndexer struct {
 	children []*ChainIndexer     // Child indexers to cascade chain updates to
 
 	active uint32          // Flag whether the event loop was started
+	paused uint32          // Flag whether the section processing is paused
 	update chan struct{}   // Notification channel that headers should be processed
 	quit   chan chan error // Quit channel to tear down running goroutines
 
@@ -138,6 +139,22 @@ func (c *ChainIndexer) Start(chain ChainIndexerChain) {
 	go c.eventLoop(chain.CurrentHeader(), events, sub)
 }
 
+// Pause suspends the processing of new sections, e.g. to relieve an overloaded node.
+// Chain head events are still tracked so that no section is missed on Resume.
+func (c *ChainIndexer) Pause() {
+	atomic.StoreUint32(&c.paused, 1)
+}
+
+// Resume restarts the processing of the sections completed while paused.
+func (c *ChainIndexer) Resume() {
+	if atomic.CompareAndSwapUint32(&c.paused, 1, 0) {
+		select {
+		case c.update <- struct{}{}:
+		default:
+		}
+	}
+}
+
 // Close tears down all goroutines belonging to the indexer and returns any error
 // that might have occurred internally.
 func (c *ChainIndexer) Close() error {
@@ -281,6 +298,10 @@ func (c *ChainIndexer) updateLoop() {
 			return
 
 		case <-c.update:
+			if atomic.LoadUint32(&c.paused) == 1 {
+				// Resume will notify again
+				continue
+			}
 			// Section headers completed (or rolled back), update the index
 			c.lock.Lock()
 			if c.knownSections > c.storedSections {
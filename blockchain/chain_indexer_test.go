@@ -141,6 +141,39 @@ func testChainIndexer(t *testing.T, count int) {
 	}
 }
 
+// Tests that a paused indexer processes no section until it is resumed.
+func TestChainIndexerPause(t *testing.T) {
+	db := database.NewMemoryDBManager()
+	defer db.Close()
+
+	backend := &testChainIndexBackend{t: t, processCh: make(chan uint64)}
+	backend.indexer = NewChainIndexer(db, db, backend, 10, 0, 0, "indexer")
+	defer backend.indexer.Close()
+
+	for i := uint64(0); i < 10; i++ {
+		header := &types.Header{Number: big.NewInt(int64(i))}
+		if i > 0 {
+			header.ParentHash = db.ReadCanonicalHash(i - 1)
+		}
+		db.WriteHeader(header)
+		db.WriteCanonicalHash(header.Hash(), i)
+	}
+
+	backend.indexer.Pause()
+	backend.indexer.newHead(9, false)
+	select {
+	case processed := <-backend.processCh:
+		t.Fatalf("Unexpected processed block #%d while paused", processed)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	backend.indexer.Resume()
+	if _, cascade := backend.assertBlocks(9, 9); !cascade {
+		t.Fatal("Section not processed after resume")
+	}
+	backend.assertSections()
+}
+
 // testChainIndexBackend implements ChainIndexerBackend
 type testChainIndexBackend struct {
 	t                          *testing.T
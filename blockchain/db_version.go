@@ -0,0 +1,94 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/storage/database"
+)
+
+// reverseMigrations maps a database version to the migration back to the previous version.
+// A version without an entry cannot be downgraded.
+var reverseMigrations = map[uint64]func(database.DBManager) error{
+	4: downgradeCodeScheme,
+}
+
+// downgradeCodeScheme reverts the database v4 to v3 by copying the contract codes to the legacy
+// scheme. The prefixed codes are left in place since the versions before v4 do not read them.
+func downgradeCodeScheme(chainDB database.DBManager) error {
+	copied, err := chainDB.CopyCodesToLegacyScheme()
+	if err != nil {
+		return err
+	}
+	logger.Info("Copied contract codes to the legacy scheme", "codes", copied)
+	return nil
+}
+
+// checkDatabaseAppVersion refuses the database last opened by a newer minor or major version
+// of Kaia, which may have written the data the running version cannot read.
+func checkDatabaseAppVersion(stored string) error {
+	major, minor, ok := parseAppVersion(stored)
+	if !ok {
+		return nil
+	}
+	if major > params.VersionMajor || (major == params.VersionMajor && minor > params.VersionMinor) {
+		return fmt.Errorf("database was last opened by Kaia %s, which is newer than %s. "+
+			"Run `kcn db downgrade --to v%d` with Kaia %s to open it with an older version", stored, params.Version, BlockChainVersion, stored)
+	}
+	return nil
+}
+
+func parseAppVersion(version string) (major, minor int, ok bool) {
+	var patch int
+	if _, err := fmt.Sscanf(version, "v%d.%d.%d", &major, &minor, &patch); err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+// DowngradeDatabase reverts the database to the given version by running the reverse migrations
+// in order, and removes the app version marker so that an older Kaia supporting the version can open it.
+// Nothing is changed if any of the required reverse migrations is missing.
+func DowngradeDatabase(chainDB database.DBManager, to uint64) error {
+	cur := chainDB.ReadDatabaseVersion()
+	if cur == nil {
+		return errors.New("database version is not found")
+	}
+	if *cur > BlockChainVersion {
+		return fmt.Errorf("database version is v%d, Kaia %s only supports v%d", *cur, params.Version, BlockChainVersion)
+	}
+	if to > *cur {
+		return fmt.Errorf("cannot downgrade database v%d to a newer version v%d", *cur, to)
+	}
+	for v := *cur; v > to; v-- {
+		if _, ok := reverseMigrations[v]; !ok {
+			return fmt.Errorf("no reverse migration from database v%d to v%d", v, v-1)
+		}
+	}
+
+	for v := *cur; v > to; v-- {
+		logger.Info("Downgrading database version", "from", v, "to", v-1)
+		if err := reverseMigrations[v](chainDB); err != nil {
+			return fmt.Errorf("failed to downgrade database v%d to v%d: %w", v, v-1, err)
+		}
+		chainDB.WriteDatabaseVersion(v - 1)
+	}
+	chainDB.DeleteDatabaseAppVersion()
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package blockchain
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDatabaseAppVersion(t *testing.T) {
+	testcases := []struct {
+		appVersion string
+		ok         bool
+	}{
+		{"", true},
+		{params.Version, true},
+		{fmt.Sprintf("v%d.%d.%d", params.VersionMajor, params.VersionMinor, params.VersionPatch+1), true},
+		{fmt.Sprintf("v%d.%d.0", params.VersionMajor, params.VersionMinor+1), false},
+		{fmt.Sprintf("v%d.0.0", params.VersionMajor+1), false},
+		{"unknown", true},
+	}
+	for _, tc := range testcases {
+		dbm := database.NewMemoryDBManager()
+		dbm.WriteDatabaseVersion(BlockChainVersion)
+		if tc.appVersion != "" {
+			dbm.WriteDatabaseAppVersion(tc.appVersion)
+		}
+
+		err := CheckBlockChainVersion(dbm)
+		if !tc.ok {
+			assert.Error(t, err, tc.appVersion)
+			assert.Equal(t, tc.appVersion, dbm.ReadDatabaseAppVersion())
+			continue
+		}
+		require.NoError(t, err, tc.appVersion)
+		assert.Equal(t, params.Version, dbm.ReadDatabaseAppVersion())
+	}
+}
+
+func TestDowngradeDatabase(t *testing.T) {
+	code := []byte{0x60, 0x00}
+	hash := crypto.Keccak256Hash(code)
+
+	dbm := database.NewMemoryDBManager()
+	dbm.WriteCode(hash, code)
+	dbm.WriteDatabaseVersion(BlockChainVersion)
+	dbm.WriteDatabaseAppVersion("v99.0.0")
+
+	// Refuse to downgrade to a newer version or without a reverse migration.
+	assert.Error(t, DowngradeDatabase(dbm, BlockChainVersion+1))
+	assert.Error(t, DowngradeDatabase(dbm, 2))
+	assert.Equal(t, uint64(BlockChainVersion), *dbm.ReadDatabaseVersion())
+	assert.Equal(t, "v99.0.0", dbm.ReadDatabaseAppVersion())
+	legacy, _ := dbm.GetMemDB().Get(hash[:])
+	assert.Empty(t, legacy)
+
+	// Downgrading to the same version only clears the app version.
+	require.NoError(t, DowngradeDatabase(dbm, BlockChainVersion))
+	assert.Equal(t, uint64(BlockChainVersion), *dbm.ReadDatabaseVersion())
+	assert.Empty(t, dbm.ReadDatabaseAppVersion())
+
+	// v4 to v3 copies the codes to the legacy scheme.
+	require.NoError(t, DowngradeDatabase(dbm, 3))
+	assert.Equal(t, uint64(3), *dbm.ReadDatabaseVersion())
+	legacy, _ = dbm.GetMemDB().Get(hash[:])
+	assert.Equal(t, code, legacy)
+	assert.Equal(t, code, dbm.ReadCode(hash))
+	assert.Equal(t, code, dbm.ReadCodeWithPrefix(hash))
+}
@@ -43,6 +43,12 @@ var (
 	// next one expected based on the local chain.
 	ErrNonceTooHigh = errors.New("nonce too high")
 
+	// ErrStateDiffNotFound is returned if the state diff of a block is not recorded or already dropped.
+	ErrStateDiffNotFound = errors.New("state diff of the block is not available")
+
+	// ErrReceiptsPruned is returned if the receipts of a block are discarded by the receipt pruning.
+	ErrReceiptsPruned = errors.New("receipts of the block are pruned")
+
 	// tx_pool
 
 	// ErrInvalidSender is returned if the transaction contains an invalid signature.
@@ -99,6 +105,18 @@ var (
 	// making the transaction invalid, rather a DOS protection.
 	ErrOversizedData = errors.New("oversized data")
 
+	// ErrBlobPoolFull is returned if the blobs of a transaction do not fit in the
+	// blob limit of the transaction pool.
+	ErrBlobPoolFull = errors.New("txpool is full of blobs")
+
+	// ErrBlobTxDisabled is returned if a blob transaction is executed without
+	// the blob base fee, i.e., before the blob tx hardfork.
+	ErrBlobTxDisabled = errors.New("blob tx is not enabled")
+
+	// ErrBlobFeeCapTooLow is returned if the max fee per blob gas of a transaction
+	// is below the blob base fee of the block.
+	ErrBlobFeeCapTooLow = errors.New("max fee per blob gas is below the blob base fee")
+
 	// ErrInvlidUnitPrice is returned if gas price of transaction is not equal to UnitPrice
 	ErrInvalidUnitPrice = errors.New("invalid unit price")
 
@@ -68,3 +68,7 @@ type ChainSideEvent struct {
 }
 
 type ChainHeadEvent struct{ Block *types.Block }
+
+// TrieCorruptionEvent is posted when a trie node read back from the disk doesn't
+// match its hash and is quarantined.
+type TrieCorruptionEvent struct{ Hash common.ExtHash }
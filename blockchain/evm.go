@@ -47,14 +47,28 @@ type ChainContext interface {
 	GetHeader(common.Hash, uint64) *types.Header
 }
 
+// BlobBaseFeeReader is implemented by the consensus engines which know the blob base fee set by governance.
+type BlobBaseFeeReader interface {
+	// BlobBaseFee returns the blob base fee at the given block, or nil if blob txs are not enabled.
+	BlobBaseFee(num uint64) *big.Int
+}
+
+// PausedContractsReader is implemented by the consensus engines which know the contracts paused by governance.
+type PausedContractsReader interface {
+	// PausedContracts returns the contracts paused at the given block, or nil if none.
+	PausedContracts(num uint64) map[common.Address]bool
+}
+
 // NewEVMBlockContext creates a new context for use in the EVM.
 func NewEVMBlockContext(header *types.Header, chain ChainContext, author *common.Address) vm.BlockContext {
 	// If we don't have an explicit author (i.e. not mining), extract from the header
 	var (
-		beneficiary common.Address
-		rewardBase  common.Address
-		baseFee     *big.Int
-		random      common.Hash
+		beneficiary     common.Address
+		rewardBase      common.Address
+		baseFee         *big.Int
+		random          common.Hash
+		blobBaseFee     *big.Int
+		pausedContracts map[common.Address]bool
 	)
 
 	if author == nil {
@@ -77,25 +91,37 @@ func NewEVMBlockContext(header *types.Header, chain ChainContext, author *common
 		random = header.ParentHash
 	}
 
+	if chain != nil {
+		if reader, ok := chain.Engine().(BlobBaseFeeReader); ok {
+			blobBaseFee = reader.BlobBaseFee(header.Number.Uint64())
+		}
+		if reader, ok := chain.Engine().(PausedContractsReader); ok {
+			pausedContracts = reader.PausedContracts(header.Number.Uint64())
+		}
+	}
+
 	return vm.BlockContext{
-		CanTransfer: CanTransfer,
-		Transfer:    Transfer,
-		GetHash:     GetHashFn(header, chain),
-		Coinbase:    beneficiary,
-		Rewardbase:  rewardBase,
-		BlockNumber: new(big.Int).Set(header.Number),
-		Time:        new(big.Int).Set(header.Time),
-		BlockScore:  new(big.Int).Set(header.BlockScore),
-		BaseFee:     baseFee,
-		Random:      random,
+		CanTransfer:     CanTransfer,
+		Transfer:        Transfer,
+		GetHash:         GetHashFn(header, chain),
+		Coinbase:        beneficiary,
+		Rewardbase:      rewardBase,
+		BlockNumber:     new(big.Int).Set(header.Number),
+		Time:            new(big.Int).Set(header.Time),
+		BlockScore:      new(big.Int).Set(header.BlockScore),
+		BaseFee:         baseFee,
+		Random:          random,
+		BlobBaseFee:     blobBaseFee,
+		PausedContracts: pausedContracts,
 	}
 }
 
 // NewEVMTxContext creates a new transaction context for a single transaction.
 func NewEVMTxContext(msg Message, header *types.Header, config *params.ChainConfig) vm.TxContext {
 	return vm.TxContext{
-		Origin:   msg.ValidatedSender(),
-		GasPrice: new(big.Int).Set(msg.EffectiveGasPrice(header, config)),
+		Origin:     msg.ValidatedSender(),
+		GasPrice:   new(big.Int).Set(msg.EffectiveGasPrice(header, config)),
+		BlobHashes: msg.BlobHashes(),
 	}
 }
 
@@ -45,8 +45,9 @@ import (
 //go:generate gencodec -type GenesisAccount -field-override genesisAccountMarshaling -out gen_genesis_account.go
 
 var (
-	errGenesisNoConfig = errors.New("genesis has no chain configuration")
-	errNoGenesis       = errors.New("genesis block is not provided")
+	errGenesisNoConfig   = errors.New("genesis has no chain configuration")
+	errNoGenesis         = errors.New("genesis block is not provided")
+	errNoGenesisReadOnly = errors.New("genesis block is not found in the read-only database")
 )
 
 // Genesis specifies the header fields, state of a genesis block. It also defines hard
@@ -188,6 +189,9 @@ func SetupGenesisBlock(db database.DBManager, genesis *Genesis, networkId uint64
 	// Just commit the new block if there is no stored genesis block.
 	stored := db.ReadCanonicalHash(0)
 	if (stored == common.Hash{}) {
+		if db.IsReadOnly() {
+			return params.AllGxhashProtocolChanges, common.Hash{}, errNoGenesisReadOnly
+		}
 		if genesis == nil {
 			switch {
 			case isPrivate:
@@ -220,7 +224,7 @@ func SetupGenesisBlock(db database.DBManager, genesis *Genesis, networkId uint64
 	if genesis != nil {
 		// If overwriteGenesis is true, overwrite existing genesis block with the new one.
 		// This is to run a test with pre-existing data.
-		if overwriteGenesis {
+		if overwriteGenesis && !db.IsReadOnly() {
 			headBlock := findBlockWithState(db)
 			logger.Warn("Trying to overwrite original genesis block with the new one",
 				"headBlockHash", headBlock.Hash().String(), "headBlockNum", headBlock.NumberU64())
@@ -239,7 +243,10 @@ func SetupGenesisBlock(db database.DBManager, genesis *Genesis, networkId uint64
 	// The genesis block is present in the database but the corresponding state might not.
 	// Because the trie can be partially corrupted, we always commit the trie.
 	// It can happen in a state migrated database or live pruned database.
-	commitGenesisState(genesis, db, networkId)
+	// A read-only database is left as is; its owner restores the trie.
+	if !db.IsReadOnly() {
+		commitGenesisState(genesis, db, networkId)
+	}
 
 	// Get the existing chain configuration.
 	newcfg := genesis.configOrDefault(stored)
@@ -249,7 +256,9 @@ func SetupGenesisBlock(db database.DBManager, genesis *Genesis, networkId uint64
 	storedcfg := db.ReadChainConfig(stored)
 	if storedcfg == nil {
 		logger.Info("Found genesis block without chain config")
-		db.WriteChainConfig(stored, newcfg)
+		if !db.IsReadOnly() {
+			db.WriteChainConfig(stored, newcfg)
+		}
 		return newcfg, stored, nil
 	} else {
 		if storedcfg.Governance == nil {
@@ -282,7 +291,9 @@ func SetupGenesisBlock(db database.DBManager, genesis *Genesis, networkId uint64
 	if compatErr != nil && *height != 0 && compatErr.RewindTo != 0 {
 		return newcfg, stored, compatErr
 	}
-	db.WriteChainConfig(stored, newcfg)
+	if !db.IsReadOnly() {
+		db.WriteChainConfig(stored, newcfg)
+	}
 	return newcfg, stored, nil
 }
 
@@ -477,6 +488,14 @@ func SetGenesisGovernance(genesis *Genesis) []byte {
 	g["istanbul.epoch"] = genesis.Config.Istanbul.Epoch
 	g["istanbul.policy"] = genesis.Config.Istanbul.ProposerPolicy
 	g["istanbul.committeesize"] = genesis.Config.Istanbul.SubGroupSize
+	if governance.Multisig != nil {
+		signers := make([]string, len(governance.Multisig.Signers))
+		for i, signer := range governance.Multisig.Signers {
+			signers[i] = signer.Hex()
+		}
+		g["governance.multisigsigners"] = strings.Join(signers, ",")
+		g["governance.multisigthreshold"] = governance.Multisig.Threshold
+	}
 
 	data, err := json.Marshal(g)
 	if err != nil {
@@ -23,6 +23,7 @@
 package blockchain
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"reflect"
@@ -34,6 +35,7 @@ import (
 	"github.com/kaiachain/kaia/common"
 	"github.com/kaiachain/kaia/consensus/gxhash"
 	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/rlp"
 	"github.com/kaiachain/kaia/storage/database"
 	"github.com/stretchr/testify/assert"
 )
@@ -371,6 +373,29 @@ func TestGenesisRestoreState(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func TestSetGenesisGovernanceMultisig(t *testing.T) {
+	decode := func(genesis *Genesis) map[string]any {
+		var data []byte
+		assert.NoError(t, rlp.DecodeBytes(SetGenesisGovernance(genesis), &data))
+		m := make(map[string]any)
+		assert.NoError(t, json.Unmarshal(data, &m))
+		return m
+	}
+
+	genesis := genMainnetGenesisBlock()
+	assert.NotContains(t, decode(genesis), "governance.multisigsigners")
+
+	genesis.Config.Governance.GovernanceMode = "multisig"
+	genesis.Config.Governance.Multisig = &params.MultisigConfig{
+		Signers:   []common.Address{{1}, {2}},
+		Threshold: 2,
+	}
+	m := decode(genesis)
+	assert.Equal(t, "multisig", m["governance.governancemode"])
+	assert.Equal(t, common.Address{1}.Hex()+","+common.Address{2}.Hex(), m["governance.multisigsigners"])
+	assert.Equal(t, float64(2), m["governance.multisigthreshold"])
+}
+
 func genMainnetGenesisBlock() *Genesis {
 	genesis := DefaultGenesisBlock()
 	genesis.Config = params.MainnetChainConfig.Copy()
@@ -371,7 +371,9 @@ func (hc *HeaderChain) CurrentBlock() *types.Block {
 
 // SetCurrentHeader sets the current head header of the canonical chain.
 func (hc *HeaderChain) SetCurrentHeader(head *types.Header) {
-	hc.chainDB.WriteHeadHeaderHash(head.Hash())
+	if !hc.chainDB.IsReadOnly() {
+		hc.chainDB.WriteHeadHeaderHash(head.Hash())
+	}
 
 	hc.currentHeader.Store(head)
 	hc.currentHeaderHash = head.Hash()
@@ -0,0 +1,100 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kaiachain/kaia/storage/database"
+)
+
+// receiptPruningBatch is the maximum number of blocks whose receipts are pruned
+// in a round, so that catching up with a long history doesn't hold the loop.
+const receiptPruningBatch = 10000
+
+// notifyReceiptPruning delivers the number of the written block to the receipt
+// pruning loop. It never blocks; a skipped number is covered by the next one.
+func (bc *BlockChain) notifyReceiptPruning(number uint64) {
+	if bc.cacheConfig.ReceiptRetention == 0 || bc.isArchiveMode() {
+		return
+	}
+	select {
+	case bc.chPruneReceipts <- number:
+	default:
+	}
+}
+
+// receiptPruningLoop discards the receipts of the blocks older than the last
+// ReceiptRetention blocks while the node runs. Headers and bodies are kept.
+func (bc *BlockChain) receiptPruningLoop() {
+	if bc.isArchiveMode() {
+		logger.Warn("Receipt pruning is not supported in archive mode")
+		return
+	}
+	logger.Info("Receipt pruning is enabled", "retention", bc.cacheConfig.ReceiptRetention)
+
+	// Resume right after the last pruned block; the genesis block has no receipts.
+	startNum := uint64(1)
+	if lastPruned, err := bc.db.ReadLastPrunedReceiptBlockNumber(); err == nil {
+		startNum = lastPruned + 1
+	}
+
+	bc.wg.Add(1)
+	go func() {
+		defer bc.wg.Done()
+		for {
+			select {
+			case num := <-bc.chPruneReceipts:
+				for num > bc.cacheConfig.ReceiptRetention {
+					limit := num - bc.cacheConfig.ReceiptRetention // Prune [1, latest - retention]
+					if limit < startNum {
+						break
+					}
+					if limit-startNum >= receiptPruningBatch {
+						limit = startNum + receiptPruningBatch - 1
+					}
+
+					startTime := time.Now()
+					count := bc.db.PruneReceipts(startNum, limit)
+					bc.db.WriteLastPrunedReceiptBlockNumber(limit)
+
+					logger.Debug("Pruned receipts", "number", num, "start", startNum, "limit", limit,
+						"count", count, "elapsed", time.Since(startTime))
+
+					startNum = limit + 1
+					select {
+					case <-bc.quit:
+						return
+					default:
+					}
+				}
+			case <-bc.quit:
+				return
+			}
+		}
+	}()
+}
+
+// CheckReceiptsPruned returns ErrReceiptsPruned if the receipts of the given block
+// are discarded by the receipt pruning.
+func CheckReceiptsPruned(db database.DBManager, number uint64) error {
+	if lastPruned, err := db.ReadLastPrunedReceiptBlockNumber(); err == nil && number <= lastPruned {
+		return fmt.Errorf("%w: block %d (receipts are kept from block %d)", ErrReceiptsPruned, number, lastPruned+1)
+	}
+	return nil
+}
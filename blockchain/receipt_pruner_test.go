@@ -0,0 +1,89 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/blockchain/vm"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/consensus/gxhash"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockChain_ReceiptPruning(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr    = crypto.PubkeyToAddress(key.PublicKey)
+		db      = database.NewMemoryDBManager()
+		gspec   = &Genesis{Config: params.TestChainConfig, Alloc: GenesisAlloc{addr: {Balance: big.NewInt(10000000000000)}}}
+		genesis = gspec.MustCommit(db)
+		signer  = types.LatestSignerForChainID(gspec.Config.ChainID)
+	)
+	cacheConfig := &CacheConfig{
+		CacheSize:        512,
+		BlockInterval:    DefaultBlockInterval,
+		TriesInMemory:    DefaultTriesInMemory,
+		ReceiptRetention: 4,
+	}
+	chain, err := NewBlockChain(db, cacheConfig, gspec.Config, gxhash.NewFaker(), vm.Config{})
+	require.NoError(t, err)
+	defer chain.Stop()
+
+	blocks, _ := GenerateChain(gspec.Config, genesis, gxhash.NewFaker(), db, 10, func(i int, gen *BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(gen.TxNonce(addr), common.HexToAddress("0x1111"), big.NewInt(1), params.TxGas, nil, nil), signer, key)
+		require.NoError(t, err)
+		gen.AddTx(tx)
+	})
+	_, err = chain.InsertChain(blocks)
+	require.NoError(t, err)
+
+	// The receipts of the blocks [1, 6] are pruned, the last 4 blocks are kept.
+	require.Eventually(t, func() bool {
+		lastPruned, err := db.ReadLastPrunedReceiptBlockNumber()
+		return err == nil && lastPruned == 6
+	}, 10*time.Second, 10*time.Millisecond)
+
+	for _, block := range blocks {
+		var (
+			num    = block.NumberU64()
+			hash   = block.Hash()
+			txHash = block.Transactions()[0].Hash()
+		)
+		assert.NotNil(t, chain.GetHeaderByHash(hash))
+		assert.NotNil(t, chain.GetBlockByHash(hash))
+		if num <= 6 {
+			assert.Nil(t, chain.GetReceiptsByBlockHash(hash), num)
+			assert.Nil(t, chain.GetReceiptByTxHash(txHash), num)
+			assert.ErrorIs(t, CheckReceiptsPruned(db, num), ErrReceiptsPruned)
+		} else {
+			assert.Len(t, chain.GetReceiptsByBlockHash(hash), 1, num)
+			assert.NotNil(t, chain.GetReceiptByTxHash(txHash), num)
+			assert.NoError(t, CheckReceiptsPruned(db, num))
+		}
+		// The transaction itself is still available.
+		tx, _, _, _ := chain.GetTxAndLookupInfo(txHash)
+		assert.NotNil(t, tx, num)
+	}
+}
@@ -57,6 +57,10 @@ type Database interface {
 	// ContractCode retrieves a particular contract's code.
 	ContractCode(codeHash common.Hash) ([]byte, error)
 
+	// ContractCodes retrieves the codes of the given code hashes, reading the ones
+	// not cached at once.
+	ContractCodes(codeHashes []common.Hash) ([][]byte, error)
+
 	// DeleteCode deletes a particular contract's code.
 	DeleteCode(codeHash common.Hash)
 
@@ -73,7 +77,7 @@ type Database interface {
 	RUnlockGCCachedNode()
 }
 
-// Trie is a Kaia Merkle Patricia trie.
+// Trie is a trie of a commitment scheme of the state, by default the Kaia Merkle Patricia trie.
 type Trie interface {
 	// GetKey returns the sha3 preimage of a hashed key that was previously used
 	// to store a value.
@@ -143,17 +147,49 @@ func getCodeSizeCache() common.Cache {
 func NewDatabaseWithNewCache(db database.DBManager, cacheConfig *statedb.TrieNodeCacheConfig) Database {
 	return &cachingDB{
 		db:            statedb.NewDatabaseWithNewCache(db, cacheConfig),
+		scheme:        mptTrieScheme{},
 		codeSizeCache: getCodeSizeCache(),
 		codeCache:     lru.NewSizeConstrainedCache[common.Hash, []byte](codeCacheSize),
 	}
 }
 
+// NewDatabaseWithTrieSchemes creates a backing store for state like NewDatabaseWithNewCache, whose
+// tries are of the named commitment scheme, or of the MPT scheme if the name is empty. If a mirror
+// scheme is named, the database runs in the transition mode, where the writes to the tries are
+// mirrored to the tries of the mirror scheme.
+func NewDatabaseWithTrieSchemes(db database.DBManager, cacheConfig *statedb.TrieNodeCacheConfig, scheme, mirror string) (Database, error) {
+	if scheme == "" {
+		scheme = MPTTrieScheme
+	}
+	s, err := GetTrieScheme(scheme)
+	if err != nil {
+		return nil, err
+	}
+	var m TrieScheme
+	if mirror != "" {
+		if mirror == scheme {
+			return nil, fmt.Errorf("trie scheme %q can't mirror itself", scheme)
+		}
+		if m, err = GetTrieScheme(mirror); err != nil {
+			return nil, err
+		}
+	}
+	return &cachingDB{
+		db:            statedb.NewDatabaseWithNewCache(db, cacheConfig),
+		scheme:        s,
+		mirror:        m,
+		codeSizeCache: getCodeSizeCache(),
+		codeCache:     lru.NewSizeConstrainedCache[common.Hash, []byte](codeCacheSize),
+	}, nil
+}
+
 // NewDatabaseWithExistingCache creates a backing store for state with given cache. The returned database
 // is safe for concurrent use and retains a lot of collapsed RLP trie nodes in a
 // large memory cache.
 func NewDatabaseWithExistingCache(db database.DBManager, cache statedb.TrieNodeCache) Database {
 	return &cachingDB{
 		db:            statedb.NewDatabaseWithExistingCache(db, cache),
+		scheme:        mptTrieScheme{},
 		codeSizeCache: getCodeSizeCache(),
 		codeCache:     lru.NewSizeConstrainedCache[common.Hash, []byte](codeCacheSize),
 	}
@@ -161,28 +197,40 @@ func NewDatabaseWithExistingCache(db database.DBManager, cache statedb.TrieNodeC
 
 type cachingDB struct {
 	db            *statedb.Database
+	scheme        TrieScheme // commitment scheme of the tries
+	mirror        TrieScheme // if set, the writes to the tries are mirrored to the tries of this scheme
 	codeSizeCache common.Cache
 	codeCache     *lru.SizeConstrainedCache[common.Hash, []byte]
 }
 
 // OpenTrie opens the main account trie at a specific root hash.
 func (db *cachingDB) OpenTrie(root common.Hash, opts *statedb.TrieOpts) (Trie, error) {
-	return statedb.NewSecureTrie(root, db.db, opts)
+	tr, err := db.scheme.OpenTrie(db.db, root, opts)
+	if err != nil || db.mirror == nil {
+		return tr, err
+	}
+	return newTransitionTrie(tr, root.ExtendZero(), db.db, func(mirrorRoot common.ExtHash) (Trie, error) {
+		return db.mirror.OpenTrie(db.db, mirrorRoot.Unextend(), opts)
+	})
 }
 
 // OpenStorageTrie opens the storage trie of an account.
 func (db *cachingDB) OpenStorageTrie(root common.ExtHash, opts *statedb.TrieOpts) (Trie, error) {
-	return statedb.NewSecureStorageTrie(root, db.db, opts)
+	tr, err := db.scheme.OpenStorageTrie(db.db, root, opts)
+	if err != nil || db.mirror == nil {
+		return tr, err
+	}
+	return newTransitionTrie(tr, root, db.db, func(mirrorRoot common.ExtHash) (Trie, error) {
+		return db.mirror.OpenStorageTrie(db.db, mirrorRoot, opts)
+	})
 }
 
 // CopyTrie returns an independent copy of the given trie.
 func (db *cachingDB) CopyTrie(t Trie) Trie {
-	switch t := t.(type) {
-	case *statedb.SecureTrie:
-		return t.Copy()
-	default:
-		panic(fmt.Errorf("unknown trie type %T", t))
+	if t, ok := t.(*transitionTrie); ok {
+		return &transitionTrie{Trie: db.scheme.CopyTrie(t.Trie), mirror: db.mirror.CopyTrie(t.mirror), db: t.db}
 	}
+	return db.scheme.CopyTrie(t)
 }
 
 // ContractCode retrieves a particular contract's code.
@@ -199,6 +247,36 @@ func (db *cachingDB) ContractCode(codeHash common.Hash) ([]byte, error) {
 	return nil, errors.New("not found")
 }
 
+// ContractCodes retrieves the codes of the given code hashes. The codes missing in
+// the cache are read from the database at once.
+func (db *cachingDB) ContractCodes(codeHashes []common.Hash) ([][]byte, error) {
+	var (
+		codes   = make([][]byte, len(codeHashes))
+		missing []int
+		hashes  []common.Hash
+	)
+	for i, codeHash := range codeHashes {
+		if code, _ := db.codeCache.Get(codeHash); len(code) > 0 {
+			codes[i] = code
+			continue
+		}
+		missing = append(missing, i)
+		hashes = append(hashes, codeHash)
+	}
+	if len(missing) == 0 {
+		return codes, nil
+	}
+	for j, code := range db.db.DiskDB().ReadCodes(hashes) {
+		if len(code) == 0 {
+			return nil, fmt.Errorf("code %x not found", hashes[j])
+		}
+		db.codeCache.Add(hashes[j], code)
+		db.codeSizeCache.Add(hashes[j], len(code))
+		codes[missing[j]] = code
+	}
+	return codes, nil
+}
+
 // DeleteCode deletes a particular contract's code.
 func (db *cachingDB) DeleteCode(codeHash common.Hash) {
 	db.codeCache.DeleteCode(codeHash)
@@ -28,6 +28,7 @@ import (
 
 	"github.com/kaiachain/kaia/blockchain/types/account"
 	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
 	"github.com/kaiachain/kaia/rlp"
 	"github.com/kaiachain/kaia/storage/statedb"
 )
@@ -42,6 +43,9 @@ type DumpAccount struct {
 	CodeHash string            `json:"codeHash"`
 	Code     string            `json:"code"`
 	Storage  map[string]string `json:"storage"`
+
+	Address   *common.Address `json:"address,omitempty"` // Set in IteratorDump if the address preimage is known
+	SecureKey hexutil.Bytes   `json:"key,omitempty"`     // Hashed address, set in IteratorDump
 }
 
 type Dump struct {
@@ -91,6 +95,107 @@ func (self *StateDB) RawDump() Dump {
 	return dump
 }
 
+// DumpConfig controls which parts of the state are collected by IteratorDump.
+type DumpConfig struct {
+	SkipCode          bool
+	SkipStorage       bool
+	OnlyWithAddresses bool   // Skip the accounts whose address preimage is unknown
+	Start             []byte // Hashed address to start the iteration from
+	Max               uint64 // Maximum number of accounts to collect, zero means no limit
+}
+
+// IteratorDump is a page of the accounts in the state, ordered by the hashed address.
+type IteratorDump struct {
+	Root     string                 `json:"root"`
+	Accounts map[string]DumpAccount `json:"accounts"`
+	Next     hexutil.Bytes          `json:"next,omitempty"` // Hashed address to continue from, nil if no accounts are left
+}
+
+// IteratorDump collects the accounts of the committed state starting from conf.Start.
+// Unlike RawDump, the accounts are read from the tries only, so the ones without
+// a known address preimage are also collected under the key "pre(<hashed address>)".
+func (self *StateDB) IteratorDump(conf *DumpConfig) (IteratorDump, error) {
+	dump := IteratorDump{
+		Root:     fmt.Sprintf("%x", self.trie.Hash()),
+		Accounts: make(map[string]DumpAccount),
+	}
+
+	it := statedb.NewIterator(self.trie.NodeIterator(conf.Start))
+	for it.Next() {
+		if conf.Max > 0 && uint64(len(dump.Accounts)) >= conf.Max {
+			dump.Next = common.CopyBytes(it.Key)
+			break
+		}
+		addr := self.trie.GetKey(it.Key)
+		if addr == nil && conf.OnlyWithAddresses {
+			continue
+		}
+		acc, err := self.dumpAccount(it.Value, conf)
+		if err != nil {
+			return IteratorDump{}, err
+		}
+		acc.SecureKey = common.CopyBytes(it.Key)
+
+		key := fmt.Sprintf("pre(%s)", common.BytesToHash(it.Key).Hex())
+		if addr != nil {
+			address := common.BytesToAddress(addr)
+			acc.Address = &address
+			key = common.Bytes2Hex(addr)
+		}
+		dump.Accounts[key] = acc
+	}
+	if it.Err != nil {
+		return IteratorDump{}, it.Err
+	}
+	return dump, nil
+}
+
+// dumpAccount decodes the given account trie leaf and loads its code and storage
+// from the database as requested by conf.
+func (self *StateDB) dumpAccount(enc []byte, conf *DumpConfig) (DumpAccount, error) {
+	serializer := account.NewAccountSerializer()
+	if err := rlp.DecodeBytes(enc, serializer); err != nil {
+		return DumpAccount{}, err
+	}
+	data := serializer.GetAccount()
+
+	acc := DumpAccount{
+		Balance:  data.GetBalance().String(),
+		Nonce:    data.GetNonce(),
+		Root:     common.Bytes2Hex(emptyRoot.Bytes()),
+		CodeHash: common.Bytes2Hex(emptyCodeHash),
+	}
+	pa := account.GetProgramAccount(data)
+	if pa == nil {
+		return acc, nil
+	}
+	acc.Root = common.Bytes2Hex(pa.GetStorageRoot().Unextend().Bytes())
+	acc.CodeHash = common.Bytes2Hex(pa.GetCodeHash())
+
+	if !conf.SkipCode {
+		code, err := self.db.ContractCode(common.BytesToHash(pa.GetCodeHash()))
+		if err != nil {
+			return DumpAccount{}, err
+		}
+		acc.Code = common.Bytes2Hex(code)
+	}
+	if !conf.SkipStorage {
+		storageTrie, err := self.db.OpenStorageTrie(pa.GetStorageRoot(), nil)
+		if err != nil {
+			return DumpAccount{}, err
+		}
+		acc.Storage = make(map[string]string)
+		storageIt := statedb.NewIterator(storageTrie.NodeIterator(nil))
+		for storageIt.Next() {
+			acc.Storage[common.Bytes2Hex(storageTrie.GetKey(storageIt.Key))] = common.Bytes2Hex(storageIt.Value)
+		}
+		if storageIt.Err != nil {
+			return DumpAccount{}, storageIt.Err
+		}
+	}
+	return acc, nil
+}
+
 func (self *StateDB) Dump() []byte {
 	json, err := json.MarshalIndent(self.RawDump(), "", "    ")
 	if err != nil {
@@ -0,0 +1,162 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"sort"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+)
+
+// StateDiff is the set of accounts and storage slots changed by a block.
+// Only the values after the block are kept.
+type StateDiff struct {
+	Accounts []*AccountDiff `json:"accounts"`
+}
+
+// AccountDiff is the change of an account made by a block.
+// If the account is deleted, its storage is wiped and the other fields are left empty.
+type AccountDiff struct {
+	Address  common.Address
+	Created  bool // true if the account was (re)created in the block
+	Deleted  bool // true if the account was self-destructed or removed as an empty account
+	Nonce    uint64
+	Balance  *big.Int
+	CodeHash common.Hash
+	Storage  []*StorageDiff // the storage slots changed by the block, sorted by key
+}
+
+// StorageDiff is the value of a storage slot after a block. A zero value means the slot is deleted.
+type StorageDiff struct {
+	Key   common.Hash `json:"key"`
+	Value common.Hash `json:"value"`
+}
+
+func (d *AccountDiff) MarshalJSON() ([]byte, error) {
+	type accountDiff struct {
+		Address  common.Address `json:"address"`
+		Created  bool           `json:"created,omitempty"`
+		Deleted  bool           `json:"deleted,omitempty"`
+		Nonce    hexutil.Uint64 `json:"nonce"`
+		Balance  *hexutil.Big   `json:"balance"`
+		CodeHash common.Hash    `json:"codeHash"`
+		Storage  []*StorageDiff `json:"storage,omitempty"`
+	}
+	return json.Marshal(&accountDiff{
+		Address:  d.Address,
+		Created:  d.Created,
+		Deleted:  d.Deleted,
+		Nonce:    hexutil.Uint64(d.Nonce),
+		Balance:  (*hexutil.Big)(d.Balance),
+		CodeHash: d.CodeHash,
+		Storage:  d.Storage,
+	})
+}
+
+// stateDiffRecorder collects the accounts and storage slots written to the tries
+// while a block is applied to a StateDB.
+type stateDiffRecorder struct {
+	accounts map[common.Address]*AccountDiff
+	storage  map[common.Address]map[common.Hash]common.Hash
+}
+
+func newStateDiffRecorder() *stateDiffRecorder {
+	return &stateDiffRecorder{
+		accounts: make(map[common.Address]*AccountDiff),
+		storage:  make(map[common.Address]map[common.Hash]common.Hash),
+	}
+}
+
+func (r *stateDiffRecorder) account(addr common.Address) *AccountDiff {
+	d, ok := r.accounts[addr]
+	if !ok {
+		d = &AccountDiff{Address: addr, Balance: new(big.Int)}
+		r.accounts[addr] = d
+	}
+	return d
+}
+
+// updateAccount records the account written to the account trie.
+func (r *stateDiffRecorder) updateAccount(obj *stateObject) {
+	d := r.account(obj.address)
+	d.Created = d.Created || obj.created
+	d.Deleted = false
+	d.Nonce = obj.Nonce()
+	d.Balance = new(big.Int).Set(obj.Balance())
+	d.CodeHash = common.BytesToHash(obj.CodeHash())
+}
+
+// deleteAccount records the account deleted from the account trie.
+// The storage recorded so far is dropped since the whole storage is wiped.
+func (r *stateDiffRecorder) deleteAccount(addr common.Address) {
+	r.accounts[addr] = &AccountDiff{Address: addr, Deleted: true, Balance: new(big.Int)}
+	delete(r.storage, addr)
+}
+
+// updateStorage records the storage slot written to the storage trie.
+func (r *stateDiffRecorder) updateStorage(addr common.Address, key, value common.Hash) {
+	storage, ok := r.storage[addr]
+	if !ok {
+		storage = make(map[common.Hash]common.Hash)
+		r.storage[addr] = storage
+	}
+	storage[key] = value
+}
+
+func (r *stateDiffRecorder) copy() *stateDiffRecorder {
+	cpy := newStateDiffRecorder()
+	for addr, d := range r.accounts {
+		dcpy := *d
+		dcpy.Balance = new(big.Int).Set(d.Balance)
+		cpy.accounts[addr] = &dcpy
+	}
+	for addr, storage := range r.storage {
+		cpy.storage[addr] = make(map[common.Hash]common.Hash, len(storage))
+		for key, value := range storage {
+			cpy.storage[addr][key] = value
+		}
+	}
+	return cpy
+}
+
+// stateDiff returns the recorded changes sorted by address and storage key.
+func (r *stateDiffRecorder) stateDiff() *StateDiff {
+	for addr := range r.storage {
+		r.account(addr)
+	}
+	diff := &StateDiff{Accounts: make([]*AccountDiff, 0, len(r.accounts))}
+	for addr, d := range r.accounts {
+		dcpy := *d
+		dcpy.Balance = new(big.Int).Set(d.Balance)
+		dcpy.Storage = nil
+		for key, value := range r.storage[addr] {
+			dcpy.Storage = append(dcpy.Storage, &StorageDiff{Key: key, Value: value})
+		}
+		sort.Slice(dcpy.Storage, func(i, j int) bool {
+			return bytes.Compare(dcpy.Storage[i].Key[:], dcpy.Storage[j].Key[:]) < 0
+		})
+		diff.Accounts = append(diff.Accounts, &dcpy)
+	}
+	sort.Slice(diff.Accounts, func(i, j int) bool {
+		return bytes.Compare(diff.Accounts[i].Address[:], diff.Accounts[j].Address[:]) < 0
+	})
+	return diff
+}
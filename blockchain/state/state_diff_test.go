@@ -0,0 +1,102 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/rlp"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateDiff(t *testing.T) {
+	var (
+		eoa      = common.HexToAddress("0x1111")
+		removed  = common.HexToAddress("0x2222")
+		created  = common.HexToAddress("0x3333")
+		contract = common.HexToAddress("0x4444")
+		code     = []byte{0x60, 0x80, 0x60, 0x40}
+
+		slot1 = common.HexToHash("0x01")
+		slot2 = common.HexToHash("0x02")
+		slot3 = common.HexToHash("0x03")
+	)
+	db := NewDatabase(database.NewMemoryDBManager())
+	stateDB, _ := New(common.Hash{}, db, nil, nil)
+	stateDB.AddBalance(eoa, big.NewInt(10))
+	stateDB.AddBalance(removed, big.NewInt(5))
+	stateDB.CreateSmartContractAccount(contract, params.CodeFormatEVM, params.Rules{IsIstanbul: true})
+	stateDB.SetCode(contract, code)
+	stateDB.SetState(contract, slot1, common.HexToHash("0x01"))
+	stateDB.SetState(contract, slot2, common.HexToHash("0x01"))
+	root, err := stateDB.Commit(false)
+	require.NoError(t, err)
+	assert.Nil(t, stateDB.StateDiff())
+
+	stateDB, _ = New(root, db, nil, nil)
+	stateDB.EnableStateDiff()
+
+	// The first transaction
+	stateDB.AddBalance(eoa, big.NewInt(1))
+	stateDB.SetState(contract, slot1, common.HexToHash("0x07"))
+	stateDB.SetState(contract, slot2, common.Hash{})
+	stateDB.SetState(contract, slot3, common.Hash{}) // noop
+	stateDB.Finalise(true, true)
+
+	// The second transaction, partially reverted
+	stateDB.AddBalance(created, big.NewInt(3))
+	stateDB.SelfDestruct(removed)
+	snapshot := stateDB.Snapshot()
+	stateDB.SetState(contract, slot3, common.HexToHash("0x09"))
+	stateDB.RevertToSnapshot(snapshot)
+	stateDB.Finalise(true, true)
+
+	_, err = stateDB.Commit(true)
+	require.NoError(t, err)
+
+	expected := &StateDiff{Accounts: []*AccountDiff{
+		{Address: eoa, Balance: big.NewInt(11), CodeHash: common.BytesToHash(emptyCodeHash)},
+		{Address: removed, Deleted: true, Balance: new(big.Int)},
+		{Address: created, Created: true, Balance: big.NewInt(3), CodeHash: common.BytesToHash(emptyCodeHash)},
+		{Address: contract, Balance: new(big.Int), CodeHash: crypto.Keccak256Hash(code), Storage: []*StorageDiff{
+			{Key: slot1, Value: common.HexToHash("0x07")},
+			{Key: slot2, Value: common.Hash{}},
+		}},
+	}}
+	diff := stateDB.StateDiff()
+	assert.Equal(t, expected, diff)
+
+	// The state diff can be stored and restored
+	data, err := rlp.EncodeToBytes(diff)
+	require.NoError(t, err)
+	decoded := new(StateDiff)
+	require.NoError(t, rlp.DecodeBytes(data, decoded))
+	reencoded, err := rlp.EncodeToBytes(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, data, reencoded)
+
+	encoded, err := json.Marshal(diff.Accounts[2])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"address":"0x0000000000000000000000000000000000003333","created":true,"nonce":"0x0","balance":"0x3","codeHash":"`+common.BytesToHash(emptyCodeHash).Hex()+`"}`, string(encoded))
+}
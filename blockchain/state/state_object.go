@@ -267,6 +267,55 @@ func (s *stateObject) GetCommittedState(db Database, key common.Hash) common.Has
 	return value
 }
 
+// loadCommittedStates caches the committed values of the given storage keys. The
+// keys not cached yet are read from the snapshot at once if available, or from
+// the storage trie one by one otherwise.
+func (s *stateObject) loadCommittedStates(db Database, keys []common.Hash) {
+	var missing []common.Hash
+	for _, key := range keys {
+		if _, cached := s.originStorage[key]; !cached {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+	if s.db.snap != nil {
+		// The storage of an object destructed in *this* block is cleared out.
+		if _, destructed := s.db.snapDestructs[s.addrHash]; destructed {
+			for _, key := range missing {
+				s.originStorage[key] = common.Hash{}
+			}
+			return
+		}
+		if EnabledExpensive {
+			defer func(start time.Time) { s.db.SnapshotStorageReads += time.Since(start) }(time.Now())
+		}
+		hashes := make([]common.Hash, len(missing))
+		for i, key := range missing {
+			hashes[i] = crypto.Keccak256Hash(key.Bytes())
+		}
+		if encs, err := s.db.snap.StorageBatch(s.addrHash, hashes); err == nil {
+			for i, enc := range encs {
+				var value common.Hash
+				if len(enc) > 0 {
+					_, content, _, err := rlp.Split(enc)
+					if err != nil {
+						s.setError(err)
+					}
+					value.SetBytes(content)
+				}
+				s.originStorage[missing[i]] = value
+			}
+			return
+		}
+	}
+	// If the snapshot is unavailable or reading from it fails, load from the database.
+	for _, key := range missing {
+		s.GetCommittedState(db, key)
+	}
+}
+
 // SetState updates a value in account trie.
 func (s *stateObject) SetState(db Database, key, value common.Hash) {
 	// If the new value is the same as old, don't set
@@ -357,6 +406,9 @@ func (s *stateObject) updateStorageTrie(db Database) Trie {
 			continue
 		}
 		s.originStorage[key] = value
+		if s.db.stateDiff != nil {
+			s.db.stateDiff.updateStorage(s.address, key, value)
+		}
 
 		var v []byte
 		if (value == common.Hash{}) {
@@ -382,12 +434,9 @@ func (s *stateObject) updateStorageTrie(db Database) Trie {
 }
 
 // updateStorageRoot sets the storage trie root to the newly updated one.
+// The caller tracks the hashing time since it may be called concurrently for different objects.
 func (s *stateObject) updateStorageRoot(db Database) {
 	if acc := account.GetProgramAccount(s.account); acc != nil {
-		// Track the amount of time wasted on hashing the storage trie
-		if EnabledExpensive {
-			defer func(start time.Time) { s.db.StorageHashes += time.Since(start) }(time.Now())
-		}
 		acc.SetStorageRoot(s.storageTrie.HashExt())
 	}
 }
@@ -420,6 +469,13 @@ func (s *stateObject) CommitStorageTrie(db Database) error {
 	if EnabledExpensive {
 		defer func(start time.Time) { s.db.StorageCommits += time.Since(start) }(time.Now())
 	}
+	return s.commitStorageRoot()
+}
+
+// commitStorageRoot writes the storage trie of the object to db and updates the storage trie root.
+// The pending storage updates must have been applied by updateStorageTrie.
+// It is safe to call concurrently for different objects.
+func (s *stateObject) commitStorageRoot() error {
 	if acc := account.GetProgramAccount(s.account); acc != nil {
 		root, err := s.storageTrie.CommitExt(nil)
 		if err != nil {
@@ -29,6 +29,7 @@ import (
 
 	"github.com/kaiachain/kaia/blockchain/types/account"
 	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
 	"github.com/kaiachain/kaia/crypto"
 	"github.com/kaiachain/kaia/params"
 	"github.com/kaiachain/kaia/storage/database"
@@ -94,6 +95,46 @@ func (s *StateSuite) TestDump(c *checker.C) {
 	}
 }
 
+func (s *StateSuite) TestIteratorDump(c *checker.C) {
+	obj1 := s.state.GetOrNewStateObject(toAddr([]byte{0x01}))
+	obj1.AddBalance(big.NewInt(22))
+	obj2 := s.state.GetOrNewSmartContract(toAddr([]byte{0x01, 0x02}))
+	obj2.SetCode(crypto.Keccak256Hash([]byte{3, 3, 3, 3, 3, 3, 3}), []byte{3, 3, 3, 3, 3, 3, 3})
+	obj3 := s.state.GetOrNewStateObject(toAddr([]byte{0x02}))
+	obj3.SetBalance(big.NewInt(44))
+	s.state.Commit(false)
+
+	// walk the accounts two at a time
+	accounts := make(map[string]DumpAccount)
+	var start []byte
+	for pages := 0; ; pages++ {
+		c.Assert(pages < 2, checker.Equals, true)
+		dump, err := s.state.IteratorDump(&DumpConfig{Start: start, Max: 2})
+		c.Assert(err, checker.IsNil)
+		for key, acc := range dump.Accounts {
+			accounts[key] = acc
+		}
+		if dump.Next == nil {
+			break
+		}
+		start = dump.Next
+	}
+	c.Assert(len(accounts), checker.Equals, 3)
+
+	acc := accounts["0000000000000000000000000000000000000102"]
+	c.Assert(*acc.Address, checker.Equals, toAddr([]byte{0x01, 0x02}))
+	c.Assert(acc.SecureKey, checker.DeepEquals, hexutil.Bytes(crypto.Keccak256(acc.Address.Bytes())))
+	c.Assert(acc.Code, checker.Equals, "03030303030303")
+	c.Assert(accounts["0000000000000000000000000000000000000002"].Balance, checker.Equals, "44")
+
+	// code and storage are left out on request
+	dump, err := s.state.IteratorDump(&DumpConfig{SkipCode: true, SkipStorage: true})
+	c.Assert(err, checker.IsNil)
+	acc = dump.Accounts["0000000000000000000000000000000000000102"]
+	c.Assert(acc.Code, checker.Equals, "")
+	c.Assert(acc.Storage, checker.IsNil)
+}
+
 func (s *StateSuite) SetUpTest(c *checker.C) {
 	s.db = database.NewMemoryDBManager()
 	s.state, _ = New(common.Hash{}, NewDatabase(s.db), nil, nil)
@@ -23,8 +23,10 @@
 package state
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
+	"runtime"
 	"sort"
 	"sync/atomic"
 	"time"
@@ -39,8 +41,12 @@ import (
 	"github.com/kaiachain/kaia/rlp"
 	"github.com/kaiachain/kaia/snapshot"
 	"github.com/kaiachain/kaia/storage/statedb"
+	"golang.org/x/sync/errgroup"
 )
 
+// parallelStorageTrieThreshold is the number of storage tries from which they are hashed or committed in parallel.
+const parallelStorageTrieThreshold = 8
+
 type revision struct {
 	id           int
 	journalIndex int
@@ -72,6 +78,9 @@ type StateDB struct {
 	snapAccounts  map[common.Hash][]byte
 	snapStorage   map[common.Hash]map[common.Hash][]byte
 
+	// Records the accounts and storage slots changed by a block if it is non-nil.
+	stateDiff *stateDiffRecorder
+
 	// This map holds 'live' objects, which will get modified while processing a state transition.
 	stateObjects             map[common.Address]*stateObject
 	stateObjectsDirty        map[common.Address]struct{}
@@ -289,6 +298,36 @@ func (s *StateDB) GetCode(addr common.Address) []byte {
 	return nil
 }
 
+// GetCodeBatch retrieves the codes of the given accounts. The codes not loaded yet
+// are read from the database at once.
+func (s *StateDB) GetCodeBatch(addrs []common.Address) [][]byte {
+	var (
+		objects []*stateObject
+		hashes  []common.Hash
+	)
+	for _, addr := range addrs {
+		stateObject := s.getStateObject(addr)
+		if stateObject == nil || stateObject.code != nil || bytes.Equal(stateObject.CodeHash(), emptyCodeHash) {
+			continue
+		}
+		objects = append(objects, stateObject)
+		hashes = append(hashes, common.BytesToHash(stateObject.CodeHash()))
+	}
+	if len(hashes) > 0 {
+		if codes, err := s.db.ContractCodes(hashes); err == nil {
+			for i, code := range codes {
+				objects[i].code = code
+			}
+		}
+		// On failure, each code is loaded below reporting its own error.
+	}
+	codes := make([][]byte, len(addrs))
+	for i, addr := range addrs {
+		codes[i] = s.GetCode(addr)
+	}
+	return codes
+}
+
 func (s *StateDB) GetAccount(addr common.Address) account.Account {
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
@@ -338,6 +377,21 @@ func (s *StateDB) GetState(addr common.Address, hash common.Hash) common.Hash {
 	return common.Hash{}
 }
 
+// GetStorageBatch retrieves the values of the given storage keys of an account. The
+// committed values not cached yet are read from the database at once if possible.
+func (s *StateDB) GetStorageBatch(addr common.Address, keys []common.Hash) []common.Hash {
+	values := make([]common.Hash, len(keys))
+	stateObject := s.getStateObject(addr)
+	if stateObject == nil {
+		return values
+	}
+	stateObject.loadCommittedStates(s.db, keys)
+	for i, key := range keys {
+		values[i] = stateObject.GetState(s.db, key)
+	}
+	return values
+}
+
 // GetCommittedState retrieves a value from the given account's committed storage trie.
 func (s *StateDB) GetCommittedState(addr common.Address, hash common.Hash) common.Hash {
 	stateObject := s.getStateObject(addr)
@@ -422,6 +476,15 @@ func (s *StateDB) HasSelfDestructed(addr common.Address) bool {
 	return false
 }
 
+// IsDirty returns true if the account has been modified since the state was last committed.
+func (s *StateDB) IsDirty(addr common.Address) bool {
+	if _, ok := s.stateObjectsDirty[addr]; ok {
+		return true
+	}
+	_, ok := s.journal.dirties[addr]
+	return ok
+}
+
 /*
  * SETTERS
  */
@@ -518,6 +581,19 @@ func (s *StateDB) SelfDestruct(addr common.Address) {
 	stateObject.account.SetBalance(new(big.Int))
 }
 
+// RestoreAccount replaces the account with the given account data, e.g. the data of an account
+// removed from the state, proven at an earlier block. The storage trie and the code the data refers
+// to must still be available in the database. The balance of the replaced account, if any, is kept.
+func (s *StateDB) RestoreAccount(addr common.Address, acc account.Account) {
+	newobj, prev := s.createObject(addr)
+	balance := new(big.Int).Set(acc.GetBalance())
+	if prev != nil {
+		balance.Add(balance, prev.Balance())
+	}
+	newobj.account = acc
+	newobj.SetBalance(balance)
+}
+
 func (s *StateDB) SelfDestruct6780(addr common.Address) {
 	stateObject := s.getStateObject(addr)
 	if stateObject == nil {
@@ -595,6 +671,9 @@ func (s *StateDB) updateStateObject(stateObject *stateObject) {
 	if s.snap != nil {
 		s.snapAccounts[stateObject.addrHash] = snapshotData
 	}
+	if s.stateDiff != nil {
+		s.stateDiff.updateAccount(stateObject)
+	}
 }
 
 // deleteStateObject removes the given object from the state trie.
@@ -606,6 +685,10 @@ func (s *StateDB) deleteStateObject(stateObject *stateObject) {
 	stateObject.deleted = true
 	addr := stateObject.Address()
 	s.setError(s.trie.TryDelete(addr[:]))
+
+	if s.stateDiff != nil {
+		s.stateDiff.deleteAccount(addr)
+	}
 }
 
 // getStateObject retrieves a state object given by the address, returning nil if
@@ -902,9 +985,28 @@ func (s *StateDB) Copy() *StateDB {
 			state.snapStorage[k] = temp
 		}
 	}
+	if s.stateDiff != nil {
+		state.stateDiff = s.stateDiff.copy()
+	}
 	return state
 }
 
+// EnableStateDiff starts recording the accounts and storage slots changed in the state.
+// It should be called before any transaction is applied to the state.
+func (s *StateDB) EnableStateDiff() {
+	s.stateDiff = newStateDiffRecorder()
+}
+
+// StateDiff returns the accounts and storage slots changed since EnableStateDiff is called.
+// The changes are written to the tries at Finalise or Commit, so it should be called after them.
+// It returns nil if the recording is not enabled.
+func (s *StateDB) StateDiff() *StateDiff {
+	if s.stateDiff == nil {
+		return nil
+	}
+	return s.stateDiff.stateDiff()
+}
+
 // deepCopyLogs deep-copies StateDB.logs from the left to the right.
 func deepCopyLogs(from, to *StateDB) {
 	for hash, logs := range from.logs {
@@ -985,17 +1087,48 @@ func (stateDB *StateDB) Finalise(deleteEmptyObjects bool, setStorageRoot bool) {
 	stateDB.clearJournalAndRefund()
 
 	if setStorageRoot && len(stateDB.stateObjectsDirtyStorage) > 0 {
+		objs := make([]*stateObject, 0, len(stateDB.stateObjectsDirtyStorage))
 		for addr := range stateDB.stateObjectsDirtyStorage {
-			so, exist := stateDB.stateObjects[addr]
-			if exist {
-				so.updateStorageRoot(stateDB.db)
-				stateDB.updateStateObject(so)
+			if so, exist := stateDB.stateObjects[addr]; exist {
+				objs = append(objs, so)
 			}
 		}
+		// Track the amount of time wasted on hashing the storage tries
+		start := time.Now()
+		forEachStateObject(objs, func(so *stateObject) error {
+			so.updateStorageRoot(stateDB.db)
+			return nil
+		})
+		if EnabledExpensive {
+			stateDB.StorageHashes += time.Since(start)
+		}
+		for _, so := range objs {
+			stateDB.updateStateObject(so)
+		}
 		stateDB.stateObjectsDirtyStorage = make(map[common.Address]struct{})
 	}
 }
 
+// forEachStateObject runs fn for the objects, concurrently if there are many of them.
+// Each object owns its storage trie and the trie nodes are inserted into the trie database
+// under its lock, so the storage tries of different objects can be hashed or committed in parallel.
+func forEachStateObject(objs []*stateObject, fn func(so *stateObject) error) error {
+	if len(objs) < parallelStorageTrieThreshold {
+		for _, so := range objs {
+			if err := fn(so); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	var eg errgroup.Group
+	eg.SetLimit(runtime.GOMAXPROCS(0))
+	for _, so := range objs {
+		eg.Go(func() error { return fn(so) })
+	}
+	return eg.Wait()
+}
+
 // IntermediateRoot computes the current root hash of the state statedb.
 // It is called in between transactions to get the root hash that
 // goes into transaction receipts.
@@ -1034,8 +1167,7 @@ func (s *StateDB) Commit(deleteEmptyObjects bool) (root common.Hash, err error)
 		s.stateObjectsDirty[addr] = struct{}{}
 	}
 
-	objectEncoder := getStateObjectEncoder(len(s.stateObjects))
-	var stateObjectsToUpdate []*stateObject
+	var stateObjectsToUpdate, storageTriesToCommit []*stateObject
 	// Commit objects to the trie.
 	for addr, stateObject := range s.stateObjects {
 		_, isDirty := s.stateObjectsDirty[addr]
@@ -1052,17 +1184,33 @@ func (s *StateDB) Commit(deleteEmptyObjects bool) (root common.Hash, err error)
 					stateObject.dirtyCode = false
 				}
 				// Write any storage changes in the state object to its storage trie.
-				if err := stateObject.CommitStorageTrie(s.db); err != nil {
-					return common.Hash{}, err
+				// The storage tries are committed together below.
+				stateObject.updateStorageTrie(s.db)
+				if stateObject.dbErr != nil {
+					return common.Hash{}, stateObject.dbErr
 				}
+				storageTriesToCommit = append(storageTriesToCommit, stateObject)
 			}
 			// Update the object in the main account trie.
 			stateObjectsToUpdate = append(stateObjectsToUpdate, stateObject)
-			objectEncoder.encode(stateObject)
 		}
 		delete(s.stateObjectsDirty, addr)
 	}
 
+	// Commit the storage tries, measuring the amount of wasted time
+	start := time.Now()
+	if err := forEachStateObject(storageTriesToCommit, (*stateObject).commitStorageRoot); err != nil {
+		return common.Hash{}, err
+	}
+	if EnabledExpensive {
+		s.StorageCommits += time.Since(start)
+	}
+
+	// The objects are encoded after their storage roots are updated.
+	objectEncoder := getStateObjectEncoder(len(stateObjectsToUpdate))
+	for _, so := range stateObjectsToUpdate {
+		objectEncoder.encode(so)
+	}
 	for _, so := range stateObjectsToUpdate {
 		s.updateStateObject(so)
 	}
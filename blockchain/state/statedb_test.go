@@ -37,6 +37,7 @@ import (
 	"github.com/kaiachain/kaia/blockchain/types"
 	"github.com/kaiachain/kaia/common"
 	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/snapshot"
 	"github.com/kaiachain/kaia/storage/database"
 	"github.com/kaiachain/kaia/storage/statedb"
 	"github.com/stretchr/testify/assert"
@@ -239,6 +240,54 @@ func TestPruningOptions(t *testing.T) {
 	assert.True(t, opens(true, true))
 }
 
+// Test that the storage tries hashed and committed in parallel result in the same state
+// as the ones processed one by one.
+func TestParallelStorageTrieCommit(t *testing.T) {
+	var (
+		n     = 4 * parallelStorageTrieThreshold
+		addrs = make([]common.Address, n)
+	)
+	for i := range addrs {
+		addrs[i] = common.BytesToAddress([]byte{0xaa, byte(i)})
+	}
+	setStorage := func(stateDB *StateDB, addr common.Address) {
+		stateDB.CreateSmartContractAccount(addr, params.CodeFormatEVM, params.Rules{})
+		for j := 0; j < 10; j++ {
+			stateDB.SetState(addr, common.Hash{byte(j)}, common.BytesToHash(append(addr.Bytes(), byte(j))))
+		}
+	}
+
+	// All storage tries at once, which are processed in parallel.
+	db := NewDatabase(database.NewMemoryDBManager())
+	stateDB, _ := New(common.Hash{}, db, nil, nil)
+	for _, addr := range addrs {
+		setStorage(stateDB, addr)
+	}
+	intermediate := stateDB.IntermediateRoot(false)
+	root, err := stateDB.Commit(false)
+	assert.NoError(t, err)
+	assert.Equal(t, intermediate, root)
+
+	// A few storage tries at a time, which are processed one by one.
+	seqDB := NewDatabase(database.NewMemoryDBManager())
+	seqRoot := common.Hash{}
+	for i := 0; i < n; i += parallelStorageTrieThreshold - 1 {
+		stateDB, _ := New(seqRoot, seqDB, nil, nil)
+		for _, addr := range addrs[i:min(i+parallelStorageTrieThreshold-1, n)] {
+			setStorage(stateDB, addr)
+		}
+		seqRoot, err = stateDB.Commit(false)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, seqRoot, root)
+
+	stateDB, err = New(root, db, nil, nil)
+	assert.NoError(t, err)
+	for _, addr := range addrs {
+		assert.Equal(t, common.BytesToHash(append(addr.Bytes(), 9)), stateDB.GetState(addr, common.Hash{9}))
+	}
+}
+
 // Test that the storage root (ExtHash) has correct extensions
 // under different pruning options.
 func TestPruningRoot(t *testing.T) {
@@ -834,3 +883,90 @@ func TestStateDBTransientStorage(t *testing.T) {
 		t.Fatalf("transient storage mismatch: have %x, want %x", got, value)
 	}
 }
+
+func TestStateDBBatchReads(t *testing.T) {
+	memDb := database.NewMemoryDBManager()
+	db := NewDatabase(memDb)
+	state, _ := New(common.Hash{}, db, nil, nil)
+
+	var (
+		contract = common.Address{0x01}
+		eoa      = common.Address{0x02}
+		missing  = common.Address{0x03}
+		code     = []byte{0x60, 0x80, 0x60, 0x40}
+		keys     = []common.Hash{{0x01}, {0x02}, {0x03}}
+		rules    = params.Rules{IsIstanbul: true}
+	)
+	state.CreateSmartContractAccount(contract, params.CodeFormatEVM, rules)
+	state.SetCode(contract, code)
+	state.SetState(contract, keys[0], common.Hash{0x11})
+	state.SetState(contract, keys[1], common.Hash{0x12})
+	state.AddBalance(eoa, common.Big1)
+	root, err := state.Commit(true)
+	assert.NoError(t, err)
+	assert.NoError(t, db.TrieDB().Commit(root, false, 0))
+
+	for _, withSnap := range []bool{false, true} {
+		var snaps *snapshot.Tree
+		if withSnap {
+			snaps, err = snapshot.New(memDb, db.TrieDB(), 16, root, false, true, false)
+			assert.NoError(t, err)
+		}
+		state, err := New(root, NewDatabase(memDb), snaps, nil)
+		assert.NoError(t, err)
+
+		// A dirty value precedes the committed one.
+		state.SetState(contract, keys[1], common.Hash{0x22})
+		assert.Equal(t, []common.Hash{{0x11}, {0x22}, {}}, state.GetStorageBatch(contract, keys), withSnap)
+		assert.Equal(t, common.Hash{0x12}, state.GetCommittedState(contract, keys[1]), withSnap)
+		assert.Equal(t, make([]common.Hash, len(keys)), state.GetStorageBatch(missing, keys), withSnap)
+
+		codes := state.GetCodeBatch([]common.Address{contract, eoa, missing})
+		assert.Equal(t, [][]byte{code, nil, nil}, codes, withSnap)
+		assert.NoError(t, state.Error(), withSnap)
+	}
+}
+
+func TestStateDBRestoreAccount(t *testing.T) {
+	db := NewDatabase(database.NewMemoryDBManager())
+	state, _ := New(common.Hash{}, db, nil, nil)
+
+	var (
+		contract = common.Address{0x01}
+		key      = common.Hash{0x01}
+		code     = []byte{0x60, 0x80, 0x60, 0x40}
+	)
+	state.CreateSmartContractAccount(contract, params.CodeFormatEVM, params.Rules{IsIstanbul: true})
+	state.SetCode(contract, code)
+	state.SetState(contract, key, common.Hash{0x11})
+	state.AddBalance(contract, big.NewInt(10))
+	root, err := state.Commit(true)
+	assert.NoError(t, err)
+	assert.False(t, state.IsDirty(contract))
+	acc := state.GetAccount(contract).DeepCopy()
+
+	// Remove the account
+	state.SelfDestruct(contract)
+	assert.True(t, state.IsDirty(contract))
+	_, err = state.Commit(true)
+	assert.NoError(t, err)
+	assert.False(t, state.Exist(contract))
+
+	// Restoring the account data brings back its storage and code
+	state.RestoreAccount(contract, acc.DeepCopy())
+	assert.Equal(t, common.Hash{0x11}, state.GetState(contract, key))
+	assert.Equal(t, code, state.GetCode(contract))
+	assert.Equal(t, big.NewInt(10), state.GetBalance(contract))
+	restored, err := state.Commit(true)
+	assert.NoError(t, err)
+	assert.Equal(t, root, restored)
+
+	// The balance of a recreated account is kept
+	state.SelfDestruct(contract)
+	_, err = state.Commit(true)
+	assert.NoError(t, err)
+	state.AddBalance(contract, big.NewInt(5))
+	state.RestoreAccount(contract, acc.DeepCopy())
+	assert.Equal(t, big.NewInt(15), state.GetBalance(contract))
+	assert.Equal(t, common.Hash{0x11}, state.GetState(contract, key))
+}
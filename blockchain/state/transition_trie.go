@@ -0,0 +1,94 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/storage/statedb"
+)
+
+// transitionTrie is a trie of the current commitment scheme whose writes are mirrored to a trie
+// of the next scheme, so that the next one is built up alongside while the current one stays
+// authoritative. The reads, the hashes and the proofs are served by the current trie.
+//
+// The mirror is committed along the current trie and referenced by its root, so it is kept
+// in memory, flushed and garbage collected together with it. The root of the mirror is recorded
+// by the root of the current trie to open the mirror again. If tries of the same root have
+// different mirrors, the mirror committed last is recorded.
+type transitionTrie struct {
+	Trie
+	mirror Trie
+	db     *statedb.Database
+}
+
+func newTransitionTrie(tr Trie, root common.ExtHash, db *statedb.Database, openMirror func(root common.ExtHash) (Trie, error)) (Trie, error) {
+	// The mirror holds a subset of the entries of the trie, so an empty trie has an empty mirror.
+	mirrorRoot := common.ExtHash{}
+	if !common.EmptyExtHash(root) && root.Unextend() != emptyRoot {
+		if r, ok := db.DiskDB().ReadTrieMirrorRoot(root); ok {
+			mirrorRoot = r
+		}
+	}
+	mirror, err := openMirror(mirrorRoot)
+	if err != nil {
+		return nil, err
+	}
+	return &transitionTrie{Trie: tr, mirror: mirror, db: db}, nil
+}
+
+func (t *transitionTrie) TryUpdate(key, value []byte) error {
+	if err := t.Trie.TryUpdate(key, value); err != nil {
+		return err
+	}
+	return t.mirror.TryUpdate(key, value)
+}
+
+func (t *transitionTrie) TryUpdateWithKeys(key, hashKey, hexKey, value []byte) error {
+	if err := t.Trie.TryUpdateWithKeys(key, hashKey, hexKey, value); err != nil {
+		return err
+	}
+	// The hashed keys are of the current scheme, so the mirror derives its own.
+	return t.mirror.TryUpdate(key, value)
+}
+
+func (t *transitionTrie) TryDelete(key []byte) error {
+	if err := t.Trie.TryDelete(key); err != nil {
+		return err
+	}
+	return t.mirror.TryDelete(key)
+}
+
+func (t *transitionTrie) Commit(onleaf statedb.LeafCallback) (common.Hash, error) {
+	root, err := t.CommitExt(onleaf)
+	return root.Unextend(), err
+}
+
+func (t *transitionTrie) CommitExt(onleaf statedb.LeafCallback) (common.ExtHash, error) {
+	mirrorRoot, err := t.mirror.CommitExt(nil)
+	if err != nil {
+		return common.ExtHash{}, err
+	}
+	root, err := t.Trie.CommitExt(onleaf)
+	if err != nil {
+		return common.ExtHash{}, err
+	}
+	if !common.EmptyExtHash(root) && root.Unextend() != emptyRoot {
+		t.db.Reference(mirrorRoot, root)
+		t.db.DiskDB().WriteTrieMirrorRoot(root, mirrorRoot)
+	}
+	return root, nil
+}
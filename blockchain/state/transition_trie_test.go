@@ -0,0 +1,134 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/kaiachain/kaia/storage/statedb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMirrorTrieScheme = "mpt-mirror-test"
+
+func init() {
+	RegisterTrieScheme(testMirrorTrieScheme, mptTrieScheme{})
+}
+
+func TestNewDatabaseWithTrieSchemes(t *testing.T) {
+	db := database.NewMemoryDBManager()
+	_, err := NewDatabaseWithTrieSchemes(db, nil, "unknown", "")
+	assert.Error(t, err)
+	_, err = NewDatabaseWithTrieSchemes(db, nil, "", "unknown")
+	assert.Error(t, err)
+	_, err = NewDatabaseWithTrieSchemes(db, nil, MPTTrieScheme, MPTTrieScheme)
+	assert.Error(t, err)
+	assert.Panics(t, func() { RegisterTrieScheme(MPTTrieScheme, mptTrieScheme{}) })
+
+	sdb, err := NewDatabaseWithTrieSchemes(db, nil, "", "")
+	require.NoError(t, err)
+	tr, err := sdb.OpenTrie(common.Hash{}, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &statedb.SecureTrie{}, tr)
+}
+
+func TestTransitionTrie(t *testing.T) {
+	var (
+		db       = database.NewMemoryDBManager()
+		eoa      = common.HexToAddress("0x1111")
+		other    = common.HexToAddress("0x2222")
+		contract = common.HexToAddress("0x3333")
+	)
+	// The state before the transition.
+	s, err := New(common.Hash{}, NewDatabase(db), nil, nil)
+	require.NoError(t, err)
+	s.AddBalance(eoa, big.NewInt(1))
+	s.AddBalance(other, big.NewInt(1))
+	s.CreateSmartContractAccount(contract, params.CodeFormatEVM, params.Rules{IsIstanbul: true})
+	s.SetNonce(contract, 1)
+	s.SetState(contract, common.HexToHash("0x01"), common.HexToHash("0x01"))
+	root, err := s.Commit(true)
+	require.NoError(t, err)
+	require.NoError(t, s.Database().TrieDB().Commit(root, false, 0))
+
+	// The writes in the transition mode are mirrored, while the root stays the one of the current scheme.
+	sdb, err := NewDatabaseWithTrieSchemes(db, nil, "", testMirrorTrieScheme)
+	require.NoError(t, err)
+	expected, err := New(root, NewDatabase(db), nil, nil)
+	require.NoError(t, err)
+	s, err = New(root, sdb, nil, nil)
+	require.NoError(t, err)
+	for _, s := range []*StateDB{expected, s} {
+		s.AddBalance(eoa, big.NewInt(1))
+		s.SetState(contract, common.HexToHash("0x02"), common.HexToHash("0x02"))
+	}
+	expectedRoot, err := expected.Commit(true)
+	require.NoError(t, err)
+	root, err = s.Commit(true)
+	require.NoError(t, err)
+	assert.Equal(t, expectedRoot, root)
+
+	mirrorRoot, ok := db.ReadTrieMirrorRoot(root.ExtendZero())
+	require.True(t, ok)
+	mirror, err := statedb.NewSecureTrie(mirrorRoot.Unextend(), sdb.TrieDB(), nil)
+	require.NoError(t, err)
+	blob, err := mirror.TryGet(eoa.Bytes())
+	assert.NoError(t, err)
+	assert.NotEmpty(t, blob)
+	blob, err = mirror.TryGet(other.Bytes())
+	assert.NoError(t, err)
+	assert.Empty(t, blob) // not written since the transition
+
+	storageRoot, err := s.GetContractStorageRoot(contract)
+	require.NoError(t, err)
+	mirrorStorageRoot, ok := db.ReadTrieMirrorRoot(storageRoot)
+	require.True(t, ok)
+	mirrorStorage, err := statedb.NewSecureStorageTrie(mirrorStorageRoot, sdb.TrieDB(), nil)
+	require.NoError(t, err)
+	blob, err = mirrorStorage.TryGet(common.HexToHash("0x02").Bytes())
+	assert.NoError(t, err)
+	assert.NotEmpty(t, blob)
+	blob, err = mirrorStorage.TryGet(common.HexToHash("0x01").Bytes())
+	assert.NoError(t, err)
+	assert.Empty(t, blob)
+
+	// The mirror is flushed and opened again along the trie.
+	require.NoError(t, sdb.TrieDB().Commit(root, false, 0))
+	s, err = New(root, sdb, nil, nil)
+	require.NoError(t, err)
+	s.AddBalance(other, big.NewInt(1))
+	root, err = s.Commit(true)
+	require.NoError(t, err)
+	mirrorRoot, ok = db.ReadTrieMirrorRoot(root.ExtendZero())
+	require.True(t, ok)
+	mirror, err = statedb.NewSecureTrie(mirrorRoot.Unextend(), sdb.TrieDB(), nil)
+	require.NoError(t, err)
+	for _, addr := range []common.Address{eoa, other} {
+		blob, err = mirror.TryGet(addr.Bytes())
+		assert.NoError(t, err)
+		assert.NotEmpty(t, blob)
+	}
+
+	// A copy keeps mirroring the writes.
+	cpy := s.Copy()
+	assert.IsType(t, &transitionTrie{}, cpy.trie)
+}
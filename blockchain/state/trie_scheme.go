@@ -0,0 +1,95 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/storage/statedb"
+)
+
+// MPTTrieScheme is the name of the Kaia Merkle Patricia trie scheme, the default commitment scheme of the state.
+const MPTTrieScheme = "mpt"
+
+// TrieScheme is a commitment scheme of the state. It opens the tries committing to the accounts
+// and to the storage of the accounts, which keep their nodes in the trie database.
+type TrieScheme interface {
+	// OpenTrie opens the account trie of the root.
+	OpenTrie(db *statedb.Database, root common.Hash, opts *statedb.TrieOpts) (Trie, error)
+
+	// OpenStorageTrie opens the storage trie of the root.
+	OpenStorageTrie(db *statedb.Database, root common.ExtHash, opts *statedb.TrieOpts) (Trie, error)
+
+	// CopyTrie returns an independent copy of a trie opened by the scheme.
+	CopyTrie(t Trie) Trie
+}
+
+var (
+	trieSchemesMu sync.RWMutex
+	trieSchemes   = map[string]TrieScheme{MPTTrieScheme: mptTrieScheme{}}
+)
+
+// RegisterTrieScheme makes a commitment scheme available by the name. It panics if the name is
+// already taken, so it is meant to be called from the init function of the package of the scheme.
+func RegisterTrieScheme(name string, scheme TrieScheme) {
+	trieSchemesMu.Lock()
+	defer trieSchemesMu.Unlock()
+
+	if _, ok := trieSchemes[name]; ok {
+		panic(fmt.Sprintf("trie scheme %q is already registered", name))
+	}
+	trieSchemes[name] = scheme
+}
+
+// GetTrieScheme returns the commitment scheme registered by the name.
+func GetTrieScheme(name string) (TrieScheme, error) {
+	trieSchemesMu.RLock()
+	defer trieSchemesMu.RUnlock()
+
+	if scheme, ok := trieSchemes[name]; ok {
+		return scheme, nil
+	}
+	names := make([]string, 0, len(trieSchemes))
+	for name := range trieSchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("unknown trie scheme %q (available: %v)", name, names)
+}
+
+// mptTrieScheme is the Kaia Merkle Patricia trie scheme, opening secure tries.
+type mptTrieScheme struct{}
+
+func (mptTrieScheme) OpenTrie(db *statedb.Database, root common.Hash, opts *statedb.TrieOpts) (Trie, error) {
+	return statedb.NewSecureTrie(root, db, opts)
+}
+
+func (mptTrieScheme) OpenStorageTrie(db *statedb.Database, root common.ExtHash, opts *statedb.TrieOpts) (Trie, error) {
+	return statedb.NewSecureStorageTrie(root, db, opts)
+}
+
+func (mptTrieScheme) CopyTrie(t Trie) Trie {
+	switch t := t.(type) {
+	case *statedb.SecureTrie:
+		return t.Copy()
+	default:
+		panic(fmt.Errorf("unknown trie type %T", t))
+	}
+}
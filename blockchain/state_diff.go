@@ -0,0 +1,73 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"github.com/kaiachain/kaia/blockchain/state"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/rlp"
+)
+
+// stateDiffCacheLimit is the number of the recent blocks whose state diffs are kept in memory.
+const stateDiffCacheLimit = 128
+
+// IsStateDiffEnabled returns true if the state diffs of the imported blocks are recorded.
+func (bc *BlockChain) IsStateDiffEnabled() bool {
+	return bc.cacheConfig.StateDiff || bc.cacheConfig.StateDiffPersist
+}
+
+// writeStateDiff keeps the state diff of the committed block in memory,
+// and writes it to the database if persisting is enabled.
+func (bc *BlockChain) writeStateDiff(block *types.Block, stateDB *state.StateDB) {
+	diff := stateDB.StateDiff()
+	if diff == nil {
+		return
+	}
+	bc.stateDiffCache.Add(block.Hash(), diff)
+
+	if bc.cacheConfig.StateDiffPersist {
+		data, err := rlp.EncodeToBytes(diff)
+		if err != nil {
+			logger.Error("Failed to encode state diff", "blockNumber", block.NumberU64(), "err", err)
+			return
+		}
+		bc.db.WriteStateDiff(block.Hash(), block.NumberU64(), data)
+	}
+}
+
+// GetStateDiff returns the accounts and storage slots changed by the given block.
+// The state diffs are available only for the blocks imported while the recording is enabled,
+// either from the recent ones kept in memory or from the database if persisting is enabled.
+func (bc *BlockChain) GetStateDiff(blockHash common.Hash) (*state.StateDiff, error) {
+	if diff, ok := bc.stateDiffCache.Get(blockHash); ok {
+		return diff.(*state.StateDiff), nil
+	}
+	number := bc.GetBlockNumber(blockHash)
+	if number == nil {
+		return nil, ErrStateDiffNotFound
+	}
+	data := bc.db.ReadStateDiff(blockHash, *number)
+	if len(data) == 0 {
+		return nil, ErrStateDiffNotFound
+	}
+	diff := new(state.StateDiff)
+	if err := rlp.DecodeBytes(data, diff); err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kaiachain/kaia/blockchain/state"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/blockchain/vm"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/consensus/gxhash"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findAccountDiff(diff *state.StateDiff, addr common.Address) *state.AccountDiff {
+	for _, d := range diff.Accounts {
+		if d.Address == addr {
+			return d
+		}
+	}
+	return nil
+}
+
+func TestBlockChain_StateDiff(t *testing.T) {
+	var (
+		db      = database.NewMemoryDBManager()
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+		addr2   = common.HexToAddress("0xaaaa")
+
+		gspec = &Genesis{
+			Config: params.TestChainConfig.Copy(),
+			Alloc:  GenesisAlloc{addr1: {Balance: big.NewInt(10000000000000)}},
+		}
+		genesis = gspec.MustCommit(db)
+		signer  = types.LatestSignerForChainID(gspec.Config.ChainID)
+		engine  = gxhash.NewFaker()
+	)
+	gspec.Config.Istanbul = params.GetDefaultIstanbulConfig() // required by SetHead
+	cacheConfig := &CacheConfig{
+		CacheSize:        512,
+		BlockInterval:    DefaultBlockInterval,
+		TriesInMemory:    DefaultTriesInMemory,
+		StateDiffPersist: true,
+	}
+	chain, err := NewBlockChain(db, cacheConfig, gspec.Config, engine, vm.Config{})
+	require.NoError(t, err)
+
+	blocks, _ := GenerateChain(gspec.Config, genesis, engine, db, 2, func(i int, gen *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(
+			gen.TxNonce(addr1), addr2, big.NewInt(int64(i+1)), 21000, common.Big1, nil), signer, key1)
+		gen.AddTx(tx)
+	})
+	_, err = chain.InsertChain(blocks)
+	require.NoError(t, err)
+
+	check := func(chain *BlockChain) {
+		for i, block := range blocks {
+			diff, err := chain.GetStateDiff(block.Hash())
+			require.NoError(t, err)
+
+			d1 := findAccountDiff(diff, addr1)
+			require.NotNil(t, d1)
+			assert.Equal(t, uint64(i+1), d1.Nonce)
+
+			d2 := findAccountDiff(diff, addr2)
+			require.NotNil(t, d2)
+			assert.Equal(t, i == 0, d2.Created)
+			assert.Equal(t, big.NewInt(int64((i+1)*(i+2)/2)), d2.Balance)
+		}
+		_, err := chain.GetStateDiff(genesis.Hash())
+		assert.ErrorIs(t, err, ErrStateDiffNotFound)
+	}
+	// From the memory
+	check(chain)
+	chain.Stop()
+
+	// From the database
+	chain, err = NewBlockChain(db, cacheConfig, gspec.Config, engine, vm.Config{})
+	require.NoError(t, err)
+	defer chain.Stop()
+	check(chain)
+
+	// The state diffs of the rewound blocks are deleted
+	require.NoError(t, chain.SetHead(1))
+	_, err = chain.GetStateDiff(blocks[1].Hash())
+	assert.ErrorIs(t, err, ErrStateDiffNotFound)
+	assert.Nil(t, db.ReadStateDiff(blocks[1].Hash(), blocks[1].NumberU64()))
+	_, err = chain.GetStateDiff(blocks[0].Hash())
+	assert.NoError(t, err)
+}
@@ -321,6 +321,10 @@ func (bc *BlockChain) PrepareStateMigration() error {
 		return errors.New("migration already started")
 	}
 
+	if bc.statePruningRunning.Load() {
+		return errors.New("state migration not supported during state pruning")
+	}
+
 	bc.prepareStateMigration = true
 	logger.Info("State migration is prepared", "expectedMigrationStartingBlockNumber", bc.CurrentBlock().NumberU64()+1)
 
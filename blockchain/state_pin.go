@@ -0,0 +1,109 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kaiachain/kaia/common"
+)
+
+// MaxStatePinDuration is the longest duration a state root can be pinned for at once.
+const MaxStatePinDuration = 24 * time.Hour
+
+var errStatePinDuration = fmt.Errorf("pin duration must be positive and at most %v", MaxStatePinDuration)
+
+// ErrStateNotAvailable is returned when pinning a state root whose trie is not available.
+var ErrStateNotAvailable = errors.New("state of the block is not available")
+
+// statePin is a reference held on a state root until it expires.
+type statePin struct {
+	expiry time.Time
+	timer  *time.Timer
+}
+
+// PinStateRoot keeps the trie nodes of the state root from being garbage collected from the
+// memory database or deleted by the online state pruning for the given duration, and returns
+// the expiry. Pinning an already pinned root extends the pin if the new expiry is later.
+func (bc *BlockChain) PinStateRoot(root common.Hash, duration time.Duration) (time.Time, error) {
+	if duration <= 0 || duration > MaxStatePinDuration {
+		return time.Time{}, errStatePinDuration
+	}
+	bc.statePinsMu.Lock()
+	defer bc.statePinsMu.Unlock()
+
+	expiry := time.Now().Add(duration)
+	if pin, ok := bc.statePins[root]; ok {
+		if expiry.After(pin.expiry) {
+			pin.expiry = expiry
+			pin.timer.Reset(duration)
+		}
+		return pin.expiry, nil
+	}
+	// Reference first so that the trie cannot be dereferenced after the availability check.
+	trieDB := bc.stateCache.TrieDB()
+	trieDB.ReferenceRoot(root)
+	if !bc.HasState(root) {
+		trieDB.Dereference(root)
+		return time.Time{}, ErrStateNotAvailable
+	}
+	pin := &statePin{expiry: expiry}
+	pin.timer = time.AfterFunc(duration, func() { bc.expireStatePin(root, pin) })
+	bc.statePins[root] = pin
+
+	logger.Info("Pinned state root", "root", root, "expiry", expiry)
+	return expiry, nil
+}
+
+// expireStatePin releases the pin if it has not been extended meanwhile.
+func (bc *BlockChain) expireStatePin(root common.Hash, pin *statePin) {
+	bc.statePinsMu.Lock()
+	defer bc.statePinsMu.Unlock()
+
+	if bc.statePins[root] != pin || time.Now().Before(pin.expiry) {
+		return
+	}
+	delete(bc.statePins, root)
+	bc.stateCache.TrieDB().Dereference(root)
+	logger.Info("Unpinned state root", "root", root)
+}
+
+// pinnedStateRoots returns the state roots currently pinned.
+func (bc *BlockChain) pinnedStateRoots() []common.Hash {
+	bc.statePinsMu.Lock()
+	defer bc.statePinsMu.Unlock()
+
+	roots := make([]common.Hash, 0, len(bc.statePins))
+	for root := range bc.statePins {
+		roots = append(roots, root)
+	}
+	return roots
+}
+
+// releaseStatePins releases all the pins. It is called when the blockchain stops.
+func (bc *BlockChain) releaseStatePins() {
+	bc.statePinsMu.Lock()
+	defer bc.statePinsMu.Unlock()
+
+	for root, pin := range bc.statePins {
+		pin.timer.Stop()
+		bc.stateCache.TrieDB().Dereference(root)
+	}
+	bc.statePins = make(map[common.Hash]*statePin)
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/blockchain/vm"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/consensus/gxhash"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockChain_PinStateRoot(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr    = crypto.PubkeyToAddress(key.PublicKey)
+		db      = database.NewMemoryDBManager()
+		gspec   = &Genesis{Config: params.TestChainConfig, Alloc: GenesisAlloc{addr: {Balance: big.NewInt(10000000000000)}}}
+		genesis = gspec.MustCommit(db)
+		signer  = types.LatestSignerForChainID(gspec.Config.ChainID)
+	)
+	cacheConfig := &CacheConfig{
+		CacheSize:     512,
+		BlockInterval: DefaultBlockInterval,
+		TriesInMemory: DefaultTriesInMemory,
+	}
+	chain, err := NewBlockChain(db, cacheConfig, gspec.Config, gxhash.NewFaker(), vm.Config{})
+	require.NoError(t, err)
+	defer chain.Stop()
+
+	blocks, _ := GenerateChain(gspec.Config, genesis, gxhash.NewFaker(), db, 3, func(i int, gen *BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(gen.TxNonce(addr), common.HexToAddress("0x1111"), big.NewInt(1), params.TxGas, nil, nil), signer, key)
+		require.NoError(t, err)
+		gen.AddTx(tx)
+	})
+	_, err = chain.InsertChain(blocks)
+	require.NoError(t, err)
+
+	_, err = chain.PinStateRoot(blocks[0].Root(), 0)
+	assert.Error(t, err)
+	_, err = chain.PinStateRoot(blocks[0].Root(), MaxStatePinDuration+time.Second)
+	assert.Error(t, err)
+	_, err = chain.PinStateRoot(common.HexToHash("0x1234"), time.Minute)
+	assert.ErrorIs(t, err, ErrStateNotAvailable)
+
+	// A shorter pin does not shorten the existing one.
+	root := blocks[0].Root()
+	expiry, err := chain.PinStateRoot(root, 200*time.Millisecond)
+	require.NoError(t, err)
+	again, err := chain.PinStateRoot(root, time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, expiry, again)
+	assert.Contains(t, chain.statePruningRoots(0), root)
+
+	// The pinned trie survives the garbage collection of the in-memory tries.
+	trieDB := chain.stateCache.TrieDB()
+	_, size, _ := trieDB.Size()
+	trieDB.Dereference(root)
+	_, pinnedSize, _ := trieDB.Size()
+	assert.Equal(t, size, pinnedSize)
+
+	// The trie is released when the pin expires.
+	require.Eventually(t, func() bool {
+		return len(chain.pinnedStateRoots()) == 0
+	}, 10*time.Second, 10*time.Millisecond)
+	_, releasedSize, _ := trieDB.Size()
+	assert.Less(t, releasedSize, size)
+}
@@ -0,0 +1,523 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kaiachain/kaia/blockchain/types/account"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/rlp"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/kaiachain/kaia/storage/statedb"
+	"github.com/steakknife/bloomfilter"
+)
+
+const (
+	DefaultOnlinePruningRetention = DefaultTriesInMemory
+	DefaultOnlinePruningInterval  = 24 * time.Hour
+	DefaultOnlinePruningRate      = 20000
+	DefaultOnlinePruningBloomSize = 2048
+
+	// statePruningBatchSize is the number of trie nodes deleted in a batch.
+	statePruningBatchSize = 1000
+)
+
+const (
+	StatePruningMark  = "mark"
+	StatePruningSweep = "sweep"
+)
+
+var (
+	errStatePruningRunning = errors.New("state pruning is already running")
+	errStatePruningStopped = errors.New("state pruning is stopped by quit signal")
+)
+
+// StatePruningStatus is the progress of the latest online state pruning session.
+type StatePruningStatus struct {
+	Running  bool      `json:"running"`
+	Phase    string    `json:"phase,omitempty"`   // StatePruningMark or StatePruningSweep
+	Number   uint64    `json:"number"`            // the head block when the session started
+	Roots    int       `json:"roots"`             // number of the state roots kept
+	Marked   uint64    `json:"marked"`            // number of the trie nodes marked reachable
+	Scanned  uint64    `json:"scanned"`           // number of the database entries swept
+	Deleted  uint64    `json:"deleted"`           // number of the trie nodes deleted
+	Resumed  bool      `json:"resumed,omitempty"` // true if the session continues an interrupted one
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished,omitempty"`
+	Err      string    `json:"err,omitempty"`
+}
+
+// statePrunerHasher adapts a trie node hash to the hash.Hash64 required by the bloom filter.
+type statePrunerHasher []byte
+
+func (f statePrunerHasher) Write(p []byte) (n int, err error) { panic("not implemented") }
+func (f statePrunerHasher) Sum(b []byte) []byte               { panic("not implemented") }
+func (f statePrunerHasher) Reset()                            { panic("not implemented") }
+func (f statePrunerHasher) BlockSize() int                    { panic("not implemented") }
+func (f statePrunerHasher) Size() int                         { return 8 }
+func (f statePrunerHasher) Sum64() uint64                     { return binary.BigEndian.Uint64(f) }
+
+// statePruner deletes the trie nodes unreachable from the recent state roots while the node runs.
+// It is for the databases without live pruning, where a trie node may be shared by many state
+// tries and only a mark and sweep can tell that it is obsolete.
+//
+// A session marks the trie nodes and the contract codes reachable from the given roots in a bloom
+// filter, then sweeps the state trie database deleting the other entries keyed by their hash.
+// The trie nodes written during the session are added to the bloom filter, so the tries of the
+// blocks inserted meanwhile are kept intact. A false positive of the bloom filter only keeps an
+// obsolete node until the next session.
+type statePruner struct {
+	db     database.DBManager
+	trieDB *statedb.Database
+	rate   int // maximum number of the trie nodes read or deleted per second; zero means unlimited
+	quit   <-chan struct{}
+
+	mu    sync.Mutex // Serializes a deletion against the trie node writes
+	bloom *bloomfilter.Filter
+
+	started time.Time
+	ops     uint64
+	phase   atomic.Value // string
+	marked  atomic.Uint64
+	scanned atomic.Uint64
+	deleted atomic.Uint64
+
+	// The progress persisted by the checkpoints
+	checkpointFile string        // file keeping the bloom filter; if empty, no checkpoint is written
+	checkpointed   time.Time     // when the last checkpoint was written
+	bloomSaved     bool          // whether the bloom filter of the finished marking is written
+	sessionStarted time.Time     // when the session started, before any interruption
+	markedRoots    []common.Hash // the state roots whose tries are marked, from the oldest
+	pendingRoots   []common.Hash // the state roots whose tries are not marked yet
+	next           []byte        // the key the sweep continues from
+}
+
+func newStatePruner(db database.DBManager, trieDB *statedb.Database, rate int, bloomSize uint64, quit <-chan struct{}) (*statePruner, error) {
+	bloom, err := bloomfilter.New(bloomSize*1024*1024*8, 4)
+	if err != nil {
+		return nil, err
+	}
+	return newStatePrunerWithBloom(db, trieDB, rate, bloom, quit), nil
+}
+
+func newStatePrunerWithBloom(db database.DBManager, trieDB *statedb.Database, rate int, bloom *bloomfilter.Filter, quit <-chan struct{}) *statePruner {
+	now := time.Now()
+	p := &statePruner{db: db, trieDB: trieDB, rate: rate, quit: quit, bloom: bloom, started: now, checkpointed: now, sessionStarted: now}
+	p.phase.Store(StatePruningMark)
+	return p
+}
+
+// keep marks the hash as reachable. It is the trie node write hook during a session.
+func (p *statePruner) keep(hash common.ExtHash) {
+	key := database.TrieNodeKey(hash)
+	p.mu.Lock()
+	p.bloom.Add(statePrunerHasher(key))
+	p.mu.Unlock()
+}
+
+// throttle returns an error if the pruning should stop, and sleeps to keep the rate.
+func (p *statePruner) throttle() error {
+	select {
+	case <-p.quit:
+		return errStatePruningStopped
+	default:
+	}
+	if p.rate <= 0 {
+		return nil
+	}
+	p.ops++
+	if wait := time.Duration(p.ops)*time.Second/time.Duration(p.rate) - time.Since(p.started); wait > 10*time.Millisecond {
+		select {
+		case <-p.quit:
+			return errStatePruningStopped
+		case <-time.After(wait):
+		}
+	}
+	return nil
+}
+
+// mark marks the nodes of the state tries of the roots, given from the oldest. Each root is walked
+// only where it differs from the latest available root marked before, or in full if there's none.
+func (p *statePruner) mark(roots []common.Hash) error {
+	prev := common.Hash{}
+	for i := len(p.markedRoots) - 1; i >= 0; i-- {
+		if _, err := statedb.NewTrie(p.markedRoots[i], p.trieDB, nil); err == nil {
+			prev = p.markedRoots[i]
+			break
+		}
+	}
+	p.pendingRoots = roots
+	for len(p.pendingRoots) > 0 {
+		root := p.pendingRoots[0]
+		if err := p.markTrie(prev.ExtendZero(), root.ExtendZero(), true); err != nil {
+			return fmt.Errorf("failed to mark the state root %x: %w", root, err)
+		}
+		prev = root
+		p.markedRoots, p.pendingRoots = append(p.markedRoots, root), p.pendingRoots[1:]
+		if err := p.checkpoint(false); err != nil {
+			logger.Warn("Failed to write the state pruning checkpoint", "err", err)
+		}
+	}
+	return nil
+}
+
+func (p *statePruner) markTrie(prevRoot, root common.ExtHash, accounts bool) error {
+	open := statedb.NewStorageTrie
+	if accounts {
+		open = func(root common.ExtHash, db *statedb.Database, opts *statedb.TrieOpts) (*statedb.Trie, error) {
+			return statedb.NewTrie(root.Unextend(), db, opts)
+		}
+	}
+	prevTrie, err := open(prevRoot, p.trieDB, nil)
+	if err != nil {
+		return err
+	}
+	t, err := open(root, p.trieDB, nil)
+	if err != nil {
+		return err
+	}
+	it, _ := statedb.NewDifferenceIterator(prevTrie.NodeIterator(nil), t.NodeIterator(nil))
+	for it.Next(true) {
+		if err := p.throttle(); err != nil {
+			return err
+		}
+		if hash := it.Hash(); hash != (common.Hash{}) {
+			p.bloom.Add(statePrunerHasher(hash[:]))
+			p.marked.Add(1)
+		}
+		if !accounts || !it.Leaf() {
+			continue
+		}
+		// An account is reached. Mark its code and the part of its storage trie that has changed.
+		storageRoot, codeHash, err := decodeStatePrunerAccount(it.LeafBlob())
+		if err != nil {
+			return err
+		}
+		if codeHash != nil {
+			p.bloom.Add(statePrunerHasher(codeHash))
+		}
+		if common.EmptyExtHash(storageRoot) {
+			continue
+		}
+		prevStorageRoot := common.ExtHash{}
+		if blob, err := prevTrie.TryGet(it.LeafKey()); err == nil && len(blob) > 0 {
+			prevStorageRoot, _, _ = decodeStatePrunerAccount(blob)
+		}
+		if err := p.markTrie(prevStorageRoot, storageRoot, false); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// decodeStatePrunerAccount returns the storage root and the code hash of a program account, or nothing otherwise.
+func decodeStatePrunerAccount(blob []byte) (common.ExtHash, []byte, error) {
+	serializer := account.NewAccountSerializer()
+	if err := rlp.DecodeBytes(blob, serializer); err != nil {
+		return common.ExtHash{}, nil, err
+	}
+	pa := account.GetProgramAccount(serializer.GetAccount())
+	if pa == nil {
+		return common.ExtHash{}, nil, nil
+	}
+	return pa.GetStorageRoot(), pa.GetCodeHash(), nil
+}
+
+// sweep deletes the unmarked entries of the state trie database keyed by the hash of their value,
+// which are the trie nodes and the contract codes in the legacy scheme.
+func (p *statePruner) sweep() error {
+	p.phase.Store(StatePruningSweep)
+	if err := p.checkpoint(true); err != nil {
+		logger.Warn("Failed to write the state pruning checkpoint", "err", err)
+	}
+
+	it := p.db.NewStateTrieDBIterator(nil, p.next)
+	defer it.Release()
+
+	keys := make([][]byte, 0, statePruningBatchSize)
+	for it.Next() {
+		if err := p.throttle(); err != nil {
+			return err
+		}
+		p.scanned.Add(1)
+
+		key := it.Key()
+		if len(key) != common.HashLength || p.bloom.Contains(statePrunerHasher(key)) {
+			continue
+		}
+		if !bytes.Equal(crypto.Keccak256(it.Value()), key) {
+			continue
+		}
+		keys = append(keys, common.CopyBytes(key))
+		if len(keys) == statePruningBatchSize {
+			if err := p.delete(keys); err != nil {
+				return err
+			}
+			p.next, keys = keys[len(keys)-1], keys[:0]
+			if err := p.checkpoint(false); err != nil {
+				logger.Warn("Failed to write the state pruning checkpoint", "err", err)
+			}
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return p.delete(keys)
+}
+
+// delete deletes the keys except the ones written since they were swept.
+func (p *statePruner) delete(keys [][]byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	batch := p.db.NewBatch(database.StateTrieDB)
+	defer batch.Release()
+
+	deleted := uint64(0)
+	for _, key := range keys {
+		if p.bloom.Contains(statePrunerHasher(key)) {
+			continue
+		}
+		if err := batch.Delete(key); err != nil {
+			return err
+		}
+		deleted++
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	p.deleted.Add(deleted)
+	return nil
+}
+
+// CanPruneState returns an error if the online state pruning can't run on this node.
+func (bc *BlockChain) CanPruneState() error {
+	switch {
+	case bc.isArchiveMode():
+		return errors.New("state pruning is not supported in archive mode")
+	case bc.db.ReadPruningEnabled():
+		return errors.New("state pruning is not supported with live pruning enabled")
+	case bc.db.InMigration() || bc.prepareStateMigration:
+		return errors.New("state pruning is not supported during state migration")
+	case bc.cacheConfig.OnlinePruningRetention < DefaultTriesInMemory:
+		// The snapshot and the in-memory tries may depend on the state of the last TriesInMemory blocks.
+		return fmt.Errorf("state pruning retention must be at least %d", DefaultTriesInMemory)
+	}
+	return nil
+}
+
+// StartStatePruning starts an online state pruning session in background.
+func (bc *BlockChain) StartStatePruning() error {
+	if err := bc.CanPruneState(); err != nil {
+		return err
+	}
+	if !bc.statePruningRunning.CompareAndSwap(false, true) {
+		return errStatePruningRunning
+	}
+	bc.wg.Add(1)
+	go func() {
+		defer bc.wg.Done()
+		defer bc.statePruningRunning.Store(false)
+		bc.pruneState()
+	}()
+	return nil
+}
+
+// StatePruningStatus returns the status of the running or the latest state pruning session, or nil if none has run.
+func (bc *BlockChain) StatePruningStatus() *StatePruningStatus {
+	bc.statePruningMu.Lock()
+	defer bc.statePruningMu.Unlock()
+
+	if bc.statePruningStatus == nil {
+		return nil
+	}
+	status := *bc.statePruningStatus
+	if p := bc.statePruner; p != nil {
+		status.Phase, _ = p.phase.Load().(string)
+		status.Marked, status.Scanned, status.Deleted = p.marked.Load(), p.scanned.Load(), p.deleted.Load()
+	}
+	return &status
+}
+
+// statePruningLoop runs an online state pruning session periodically.
+func (bc *BlockChain) statePruningLoop() {
+	if err := bc.CanPruneState(); err != nil {
+		logger.Warn("Online state pruning is disabled", "err", err)
+		return
+	}
+	logger.Info("Online state pruning is enabled", "retention", bc.cacheConfig.OnlinePruningRetention,
+		"interval", bc.cacheConfig.OnlinePruningInterval, "rate", bc.cacheConfig.OnlinePruningRate)
+
+	bc.wg.Add(1)
+	go func() {
+		defer bc.wg.Done()
+
+		timer := time.NewTimer(bc.cacheConfig.OnlinePruningInterval)
+		defer timer.Stop()
+		for {
+			select {
+			case <-timer.C:
+				if err := bc.CanPruneState(); err != nil {
+					logger.Warn("Skipping online state pruning", "err", err)
+				} else if bc.statePruningRunning.CompareAndSwap(false, true) {
+					bc.pruneState()
+					bc.statePruningRunning.Store(false)
+				}
+				timer.Reset(bc.cacheConfig.OnlinePruningInterval)
+			case <-bc.quit:
+				return
+			}
+		}
+	}()
+}
+
+// pruneState runs a state pruning session keeping the state of the last OnlinePruningRetention blocks.
+// If a session was interrupted, it continues that session instead of starting over.
+func (bc *BlockChain) pruneState() {
+	head := bc.CurrentBlock().NumberU64()
+	status := &StatePruningStatus{Running: true, Number: head, Started: time.Now()}
+
+	cp, bloom, err := bc.readStatePruningCheckpoint()
+	if err != nil {
+		logger.Warn("Failed to read the state pruning checkpoint, starting over", "err", err)
+		bc.deleteStatePruningCheckpoint()
+		cp, bloom = nil, nil
+	}
+	var p *statePruner
+	if cp != nil {
+		p = newStatePrunerWithBloom(bc.db, bc.stateCache.TrieDB(), bc.cacheConfig.OnlinePruningRate, bloom, bc.quit)
+	} else if p, err = newStatePruner(bc.db, bc.stateCache.TrieDB(), bc.cacheConfig.OnlinePruningRate, bc.cacheConfig.OnlinePruningBloomSize, bc.quit); err != nil {
+		bc.finishStatePruning(nil, status, err)
+		return
+	}
+	p.checkpointFile = bc.cacheConfig.OnlinePruningCheckpoint
+
+	// The hook must be set before the roots are chosen, so that no trie node written since is lost.
+	bc.db.SetTrieNodeWriteHook(p.keep)
+	defer bc.db.SetTrieNodeWriteHook(nil)
+
+	roots := bc.statePruningRoots(head)
+	if cp != nil {
+		roots = p.resume(cp, roots)
+		status.Resumed, status.Started = true, p.sessionStarted
+	}
+	status.Roots = len(p.markedRoots) + len(roots)
+
+	bc.statePruningMu.Lock()
+	bc.statePruner, bc.statePruningStatus = p, status
+	bc.statePruningMu.Unlock()
+
+	logger.Info("Online state pruning is started", "number", head, "roots", status.Roots, "resumed", status.Resumed)
+	if status.Roots == 0 {
+		err = errors.New("no state to keep")
+	} else if err = p.mark(roots); err == nil {
+		logger.Info("Online state pruning marked the reachable trie nodes", "marked", p.marked.Load(), "elapsed", time.Since(status.Started))
+		err = p.sweep()
+	}
+	if errors.Is(err, errStatePruningStopped) {
+		if cerr := p.checkpoint(true); cerr != nil {
+			logger.Warn("Failed to write the state pruning checkpoint", "err", cerr)
+		}
+	} else {
+		bc.deleteStatePruningCheckpoint()
+	}
+	bc.finishStatePruning(p, status, err)
+}
+
+// resume restores the progress of the interrupted session and returns the roots to mark, which are
+// the available ones of the roots not marked yet followed by the recent ones not in the session.
+// The trie nodes written since the interruption are marked along the new roots.
+func (p *statePruner) resume(cp *statePruningCheckpoint, recent []common.Hash) []common.Hash {
+	p.sessionStarted = time.Unix(int64(cp.Started), 0)
+	p.markedRoots, p.next = cp.Marked, cp.Next
+	p.marked.Store(cp.MarkedNodes)
+	p.scanned.Store(cp.ScannedNodes)
+	p.deleted.Store(cp.DeletedNodes)
+
+	seen := make(map[common.Hash]bool)
+	for _, root := range cp.Marked {
+		seen[root] = true
+	}
+	var roots []common.Hash
+	for _, root := range append(cp.Pending, recent...) {
+		if seen[root] {
+			continue
+		}
+		seen[root] = true
+		if _, err := statedb.NewTrie(root, p.trieDB, nil); err == nil {
+			roots = append(roots, root)
+		}
+	}
+	// The bloom filter written at the start of the sweep is still up to date if there's nothing to mark.
+	p.bloomSaved = cp.Sweep && len(roots) == 0
+	return roots
+}
+
+func (bc *BlockChain) finishStatePruning(p *statePruner, status *StatePruningStatus, err error) {
+	bc.statePruningMu.Lock()
+	defer bc.statePruningMu.Unlock()
+
+	bc.statePruner, bc.statePruningStatus = nil, status
+	status.Running, status.Finished = false, time.Now()
+	if p != nil {
+		status.Phase = ""
+		status.Marked, status.Scanned, status.Deleted = p.marked.Load(), p.scanned.Load(), p.deleted.Load()
+	}
+	if err != nil {
+		status.Err = err.Error()
+		logger.Error("Online state pruning failed", "err", err)
+		return
+	}
+	logger.Info("Online state pruning is done", "number", status.Number, "marked", status.Marked,
+		"scanned", status.Scanned, "deleted", status.Deleted, "elapsed", status.Finished.Sub(status.Started))
+}
+
+// statePruningRoots returns the distinct state roots available among the last
+// OnlinePruningRetention blocks up to the given block, from the oldest, followed by the pinned ones.
+func (bc *BlockChain) statePruningRoots(head uint64) []common.Hash {
+	from := uint64(0)
+	if retention := bc.cacheConfig.OnlinePruningRetention; head >= retention {
+		from = head - retention + 1
+	}
+	var (
+		roots []common.Hash
+		seen  = make(map[common.Hash]bool)
+	)
+	for num := from; num <= head; num++ {
+		header := bc.GetHeaderByNumber(num)
+		if header == nil || seen[header.Root] || !bc.HasState(header.Root) {
+			continue
+		}
+		seen[header.Root] = true
+		roots = append(roots, header.Root)
+	}
+	for _, root := range bc.pinnedStateRoots() {
+		if !seen[root] && bc.HasState(root) {
+			seen[root] = true
+			roots = append(roots, root)
+		}
+	}
+	return roots
+}
@@ -0,0 +1,139 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/rlp"
+	"github.com/steakknife/bloomfilter"
+)
+
+// statePruningCheckpointInterval is the minimum interval between the checkpoints of a session.
+const statePruningCheckpointInterval = 5 * time.Minute
+
+// statePruningCheckpoint is the progress of an online state pruning session, persisted so that an
+// interrupted session continues where it left off on the next run. The bloom filter of the marked
+// trie nodes is kept in the OnlinePruningCheckpoint file alongside.
+type statePruningCheckpoint struct {
+	Started uint64        // unix time when the session started
+	Marked  []common.Hash // the state roots whose tries are marked, from the oldest
+	Pending []common.Hash // the state roots whose tries are not marked yet
+	Sweep   bool          // whether the marking is done and the sweep has started
+	Next    []byte        // the key the sweep continues from
+
+	MarkedNodes  uint64
+	ScannedNodes uint64
+	DeletedNodes uint64
+}
+
+// checkpoint persists the progress of the session if the checkpoint interval has passed, or
+// always if forced. The bloom filter is written only with the progress of the marking, because
+// the nodes added to it during the sweep are marked again when the session is resumed.
+func (p *statePruner) checkpoint(force bool) error {
+	if p.checkpointFile == "" || (!force && time.Since(p.checkpointed) < statePruningCheckpointInterval) {
+		return nil
+	}
+	sweep := p.phase.Load() == StatePruningSweep
+	if !sweep || !p.bloomSaved {
+		if err := writeStatePruningBloom(p.bloom, p.checkpointFile); err != nil {
+			return err
+		}
+		p.bloomSaved = sweep
+	}
+	blob, err := rlp.EncodeToBytes(&statePruningCheckpoint{
+		Started:      uint64(p.sessionStarted.Unix()),
+		Marked:       p.markedRoots,
+		Pending:      p.pendingRoots,
+		Sweep:        sweep,
+		Next:         p.next,
+		MarkedNodes:  p.marked.Load(),
+		ScannedNodes: p.scanned.Load(),
+		DeletedNodes: p.deleted.Load(),
+	})
+	if err != nil {
+		return err
+	}
+	p.db.WriteStatePruningCheckpoint(blob)
+	p.checkpointed = time.Now()
+	return nil
+}
+
+// writeStatePruningBloom writes the bloom filter to a temporary file first, not to leave a partial one behind.
+func writeStatePruningBloom(bloom *bloomfilter.Filter, file string) error {
+	tmp := file + ".new"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	_, err = bloom.WriteTo(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+// readStatePruningCheckpoint returns the progress of the interrupted session and its bloom filter,
+// or nothing if there's no session to resume.
+func (bc *BlockChain) readStatePruningCheckpoint() (*statePruningCheckpoint, *bloomfilter.Filter, error) {
+	file := bc.cacheConfig.OnlinePruningCheckpoint
+	if file == "" {
+		return nil, nil, nil
+	}
+	blob := bc.db.ReadStatePruningCheckpoint()
+	if len(blob) == 0 {
+		return nil, nil, nil
+	}
+	cp := new(statePruningCheckpoint)
+	if err := rlp.DecodeBytes(blob, cp); err != nil {
+		return nil, nil, err
+	}
+	bloom, _, err := bloomfilter.ReadFile(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cp, bloom, nil
+}
+
+// deleteStatePruningCheckpoint discards the progress of the session.
+func (bc *BlockChain) deleteStatePruningCheckpoint() {
+	file := bc.cacheConfig.OnlinePruningCheckpoint
+	if file == "" {
+		return
+	}
+	bc.db.DeleteStatePruningCheckpoint()
+	if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		logger.Warn("Failed to remove the state pruning checkpoint", "file", file, "err", err)
+	}
+}
+
+// resumeStatePruning starts the session interrupted on the last run, if any.
+func (bc *BlockChain) resumeStatePruning() {
+	if bc.cacheConfig.OnlinePruningCheckpoint == "" || len(bc.db.ReadStatePruningCheckpoint()) == 0 {
+		return
+	}
+	if err := bc.StartStatePruning(); err != nil {
+		logger.Warn("Failed to resume the online state pruning", "err", err)
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kaiachain/kaia/blockchain/state"
+	"github.com/kaiachain/kaia/blockchain/vm"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/consensus/gxhash"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// commitTestState applies the change on the state of the root and commits the result to the disk.
+func commitTestState(t *testing.T, sdb state.Database, root common.Hash, change func(s *state.StateDB)) common.Hash {
+	s, err := state.New(root, sdb, nil, nil)
+	require.NoError(t, err)
+	change(s)
+	root, err = s.Commit(true)
+	require.NoError(t, err)
+	require.NoError(t, sdb.TrieDB().Commit(root, false, 0))
+	return root
+}
+
+func checkTestState(sdb state.Database, root common.Hash) error {
+	s, err := state.New(root, sdb, nil, nil)
+	if err != nil {
+		return err
+	}
+	it := state.NewNodeIterator(s)
+	for it.Next() {
+	}
+	return it.Error
+}
+
+func TestStatePruner(t *testing.T) {
+	var (
+		db       = database.NewMemoryDBManager()
+		sdb      = state.NewDatabase(db)
+		eoa      = common.HexToAddress("0x1111")
+		contract = common.HexToAddress("0x2222")
+		code     = []byte{0x60, 0x80, 0x60, 0x40}
+	)
+	root1 := commitTestState(t, sdb, common.Hash{}, func(s *state.StateDB) {
+		s.AddBalance(eoa, big.NewInt(1))
+		s.CreateSmartContractAccount(contract, params.CodeFormatEVM, params.Rules{IsIstanbul: true})
+		s.SetCode(contract, code)
+		for i := 0; i < 32; i++ {
+			s.SetState(contract, common.BigToHash(big.NewInt(int64(i))), common.BigToHash(big.NewInt(1)))
+		}
+	})
+	root2 := commitTestState(t, sdb, root1, func(s *state.StateDB) {
+		s.AddBalance(eoa, big.NewInt(1))
+		s.SetState(contract, common.Hash{}, common.BigToHash(big.NewInt(2)))
+	})
+	root3 := commitTestState(t, sdb, root2, func(s *state.StateDB) {
+		s.AddBalance(eoa, big.NewInt(1))
+		s.SetState(contract, common.BigToHash(big.NewInt(1)), common.BigToHash(big.NewInt(3)))
+	})
+
+	// The code in the legacy scheme is keyed by its hash like a trie node.
+	_, err := db.CopyCodesToLegacyScheme()
+	require.NoError(t, err)
+	// An orphan keyed by its hash is deleted, an entry keyed otherwise is never touched.
+	orphan := []byte("orphan")
+	db.WriteTrieNode(crypto.Keccak256Hash(orphan).ExtendZero(), orphan)
+	other := common.HexToHash("0x3333")
+	db.WriteTrieNode(other.ExtendZero(), []byte("not keyed by its hash"))
+
+	p, err := newStatePruner(db, sdb.TrieDB(), 0, 1, make(chan struct{}))
+	require.NoError(t, err)
+	db.SetTrieNodeWriteHook(p.keep)
+	defer db.SetTrieNodeWriteHook(nil)
+
+	require.NoError(t, p.mark([]common.Hash{root2, root3}))
+	// A node written during the session is kept even if it is not reachable.
+	written := []byte("written during the session")
+	db.WriteTrieNode(crypto.Keccak256Hash(written).ExtendZero(), written)
+	require.NoError(t, p.sweep())
+
+	assert.NotZero(t, p.marked.Load())
+	assert.NotZero(t, p.deleted.Load())
+	assert.NoError(t, checkTestState(sdb, root2))
+	assert.NoError(t, checkTestState(sdb, root3))
+
+	// The root node of the oldest state is unique to it.
+	_, err = db.ReadTrieNode(root1.ExtendZero())
+	assert.Error(t, err)
+	_, err = db.ReadTrieNode(crypto.Keccak256Hash(orphan).ExtendZero())
+	assert.Error(t, err)
+	_, err = db.ReadTrieNode(crypto.Keccak256Hash(written).ExtendZero())
+	assert.NoError(t, err)
+	_, err = db.ReadTrieNode(other.ExtendZero())
+	assert.NoError(t, err)
+	_, err = db.ReadTrieNode(crypto.Keccak256Hash(code).ExtendZero()) // legacy code
+	assert.NoError(t, err)
+}
+
+func TestStatePruner_Stop(t *testing.T) {
+	db := database.NewMemoryDBManager()
+	sdb := state.NewDatabase(db)
+	root := commitTestState(t, sdb, common.Hash{}, func(s *state.StateDB) {
+		s.AddBalance(common.HexToAddress("0x1111"), big.NewInt(1))
+	})
+
+	quit := make(chan struct{})
+	close(quit)
+	p, err := newStatePruner(db, sdb.TrieDB(), 0, 1, quit)
+	require.NoError(t, err)
+	assert.ErrorIs(t, p.mark([]common.Hash{root}), errStatePruningStopped)
+	assert.NoError(t, checkTestState(sdb, root))
+}
+
+func TestBlockChain_StatePruning(t *testing.T) {
+	db := database.NewMemoryDBManager()
+	gspec := &Genesis{
+		Config: params.TestChainConfig,
+		Alloc:  GenesisAlloc{common.HexToAddress("0x1111"): {Balance: big.NewInt(1)}},
+	}
+	genesis := gspec.MustCommit(db)
+
+	cacheConfig := &CacheConfig{
+		CacheSize:              512,
+		BlockInterval:          DefaultBlockInterval,
+		TriesInMemory:          DefaultTriesInMemory,
+		OnlinePruningRetention: 1,
+		OnlinePruningBloomSize: 1,
+	}
+	chain, err := NewBlockChain(db, cacheConfig, gspec.Config, gxhash.NewFaker(), vm.Config{})
+	require.NoError(t, err)
+	defer chain.Stop()
+
+	// The retention must cover the in-memory tries.
+	assert.Error(t, chain.StartStatePruning())
+	cacheConfig.OnlinePruningRetention = DefaultTriesInMemory
+
+	assert.Nil(t, chain.StatePruningStatus())
+	require.NoError(t, chain.StartStatePruning())
+	var status *StatePruningStatus
+	require.Eventually(t, func() bool {
+		status = chain.StatePruningStatus()
+		return status != nil && !status.Running
+	}, 10*time.Second, 10*time.Millisecond)
+	assert.Empty(t, status.Err)
+	assert.Equal(t, 1, status.Roots)
+	assert.NotZero(t, status.Marked)
+	assert.NoError(t, checkTestState(chain.StateCache(), genesis.Root()))
+
+	cacheConfig.ArchiveMode = true
+	assert.Error(t, chain.StartStatePruning())
+}
+
+func TestStatePruner_Checkpoint(t *testing.T) {
+	var (
+		db  = database.NewMemoryDBManager()
+		sdb = state.NewDatabase(db)
+		eoa = common.HexToAddress("0x1111")
+		bc  = &BlockChain{db: db, cacheConfig: &CacheConfig{OnlinePruningCheckpoint: filepath.Join(t.TempDir(), "statepruning.bloom")}}
+	)
+	root1 := commitTestState(t, sdb, common.Hash{}, func(s *state.StateDB) {
+		s.AddBalance(eoa, big.NewInt(1))
+	})
+	root2 := commitTestState(t, sdb, root1, func(s *state.StateDB) {
+		s.AddBalance(eoa, big.NewInt(1))
+	})
+	root3 := commitTestState(t, sdb, root2, func(s *state.StateDB) {
+		s.AddBalance(eoa, big.NewInt(1))
+	})
+
+	// The session is interrupted during the marking.
+	quit := make(chan struct{})
+	close(quit)
+	p, err := newStatePruner(db, sdb.TrieDB(), 0, 1, quit)
+	require.NoError(t, err)
+	p.checkpointFile = bc.cacheConfig.OnlinePruningCheckpoint
+	require.ErrorIs(t, p.mark([]common.Hash{root2}), errStatePruningStopped)
+	require.NoError(t, p.checkpoint(true))
+
+	// The resumed session marks the pending roots and the new ones, then is interrupted during the sweep.
+	cp, bloom, err := bc.readStatePruningCheckpoint()
+	require.NoError(t, err)
+	require.NotNil(t, cp)
+	quit = make(chan struct{})
+	p = newStatePrunerWithBloom(db, sdb.TrieDB(), 0, bloom, quit)
+	p.checkpointFile = bc.cacheConfig.OnlinePruningCheckpoint
+	roots := p.resume(cp, []common.Hash{root2, root3})
+	assert.Equal(t, []common.Hash{root2, root3}, roots)
+	require.NoError(t, p.mark(roots))
+	close(quit)
+	require.ErrorIs(t, p.sweep(), errStatePruningStopped)
+	require.NoError(t, p.checkpoint(true))
+
+	// The session resumed again only sweeps.
+	cp, bloom, err = bc.readStatePruningCheckpoint()
+	require.NoError(t, err)
+	assert.True(t, cp.Sweep)
+	assert.Equal(t, []common.Hash{root2, root3}, cp.Marked)
+	p = newStatePrunerWithBloom(db, sdb.TrieDB(), 0, bloom, make(chan struct{}))
+	assert.Empty(t, p.resume(cp, []common.Hash{root3}))
+	require.NoError(t, p.sweep())
+	bc.deleteStatePruningCheckpoint()
+
+	assert.NotZero(t, p.deleted.Load())
+	assert.NoError(t, checkTestState(sdb, root2))
+	assert.NoError(t, checkTestState(sdb, root3))
+	_, err = db.ReadTrieNode(root1.ExtendZero())
+	assert.Error(t, err)
+
+	cp, _, err = bc.readStatePruningCheckpoint()
+	assert.NoError(t, err)
+	assert.Nil(t, cp)
+	_, err = os.Stat(bc.cacheConfig.OnlinePruningCheckpoint)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -37,6 +37,7 @@ import (
 var (
 	errInsufficientBalanceForGas         = errors.New("insufficient balance of the sender to pay for gas")
 	errInsufficientBalanceForGasFeePayer = errors.New("insufficient balance of the fee payer to pay for gas")
+	errInsufficientBalanceForBlobGas     = errors.New("insufficient balance of the sender to pay for blob gas")
 )
 
 /*
@@ -65,6 +66,7 @@ type StateTransition struct {
 	gasTipCap  *big.Int
 	gasFeeCap  *big.Int
 	initialGas uint64
+	blobFee    *big.Int // the fee for the blob gas charged to the sender; nil if not a blob tx
 	value      *big.Int
 	data       []byte
 	state      vm.StateDB
@@ -102,6 +104,11 @@ type Message interface {
 	EffectiveGasTip(baseFee *big.Int) *big.Int
 	EffectiveGasPrice(header *types.Header, config *params.ChainConfig) *big.Int
 
+	// For TxTypeEthereumBlob
+	BlobHashes() []common.Hash
+	BlobGas() uint64
+	BlobGasFeeCap() *big.Int
+
 	Gas() uint64
 	Value() *big.Int
 
@@ -270,6 +277,36 @@ func (st *StateTransition) buyGas() error {
 	return nil
 }
 
+// buyBlobGas charges the blob gas of a blob tx to its sender at the blob base fee.
+// The blob fee is a part of the tx fee, so it is distributed along with the gas fee.
+func (st *StateTransition) buyBlobGas() error {
+	blobGas := st.msg.BlobGas()
+	if blobGas == 0 {
+		return nil
+	}
+
+	blobBaseFee := st.evm.Context.BlobBaseFee
+	if blobBaseFee == nil {
+		return ErrBlobTxDisabled
+	}
+	if st.msg.BlobGasFeeCap().Cmp(blobBaseFee) < 0 {
+		return ErrBlobFeeCapTooLow
+	}
+
+	blobFee := new(big.Int).Mul(new(big.Int).SetUint64(blobGas), blobBaseFee)
+	validatedSender := st.msg.ValidatedSender()
+	if st.state.GetBalance(validatedSender).Cmp(blobFee) < 0 {
+		logger.Debug(errInsufficientBalanceForBlobGas.Error(), "sender", validatedSender.String(),
+			"senderBalance", st.state.GetBalance(validatedSender).Uint64(), "blobFee", blobFee.Uint64(),
+			"txHash", st.msg.Hash().String())
+		return errInsufficientBalanceForBlobGas
+	}
+
+	st.state.SubBalance(validatedSender, blobFee)
+	st.blobFee = blobFee
+	return nil
+}
+
 func (st *StateTransition) preCheck() error {
 	// when prefetching, skip the nonce and balance check logic.
 	// however, st.gas still needs to be set whether it's prefetching or not.
@@ -291,7 +328,10 @@ func (st *StateTransition) preCheck() error {
 			return ErrNonceTooLow
 		}
 	}
-	return st.buyGas()
+	if err := st.buyGas(); err != nil {
+		return err
+	}
+	return st.buyBlobGas()
 }
 
 // TransitionDb will transition the state by applying the current message and
@@ -351,6 +391,9 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	// - prepare accessList(post-berlin)
 	// - reset transient storage(eip 1153)
 	st.state.Prepare(rules, msg.ValidatedSender(), msg.ValidatedFeePayer(), st.evm.Context.Coinbase, msg.To(), vm.ActivePrecompiles(rules), msg.AccessList())
+	if rules.IsCancun {
+		st.prefetchAccessList(msg.AccessList())
+	}
 
 	// Check whether the init code size has been exceeded.
 	if rules.IsShanghai && msg.To() == nil && len(st.data) > params.MaxInitCodeSize {
@@ -392,10 +435,14 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 
 	// Defer transferring Tx fee when DeferredTxFee is true
 	if st.evm.ChainConfig().Governance == nil || !st.evm.ChainConfig().Governance.DeferredTxFee() {
+		fee := new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), st.gasPrice)
+		if st.blobFee != nil {
+			fee.Add(fee, st.blobFee)
+		}
 		if rules.IsMagma {
-			st.state.AddBalance(st.evm.Context.Rewardbase, new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), st.gasPrice))
+			st.state.AddBalance(st.evm.Context.Rewardbase, fee)
 		} else {
-			st.state.AddBalance(st.evm.Context.Coinbase, new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), st.gasPrice))
+			st.state.AddBalance(st.evm.Context.Coinbase, fee)
 		}
 	}
 
@@ -436,6 +483,7 @@ var errTxFailed2receiptstatus = map[error]uint{
 	kerrors.ErrDeprecated:                           types.ReceiptStatusErrDeprecated,
 	kerrors.ErrNotSupported:                         types.ReceiptStatusErrNotSupported,
 	kerrors.ErrInvalidCodeFormat:                    types.ReceiptStatusErrInvalidCodeFormat,
+	vm.ErrContractPaused:                            types.ReceiptStatusErrContractPaused,
 }
 
 var receiptstatus2errTxFailed = map[uint]error{
@@ -469,6 +517,23 @@ var receiptstatus2errTxFailed = map[uint]error{
 	types.ReceiptStatusErrDeprecated:                           kerrors.ErrDeprecated,
 	types.ReceiptStatusErrNotSupported:                         kerrors.ErrNotSupported,
 	types.ReceiptStatusErrInvalidCodeFormat:                    kerrors.ErrInvalidCodeFormat,
+	types.ReceiptStatusErrContractPaused:                       vm.ErrContractPaused,
+}
+
+// prefetchAccessList reads the codes and storage slots declared by the access list
+// in grouped reads, instead of a random read per account and slot during execution.
+func (st *StateTransition) prefetchAccessList(list types.AccessList) {
+	if len(list) == 0 {
+		return
+	}
+	addrs := make([]common.Address, len(list))
+	for i, el := range list {
+		addrs[i] = el.Address
+		if len(el.StorageKeys) > 0 {
+			st.state.GetStorageBatch(el.Address, el.StorageKeys)
+		}
+	}
+	st.state.GetCodeBatch(addrs)
 }
 
 func (st *StateTransition) refundGas(refundQuotient uint64) {
@@ -21,11 +21,18 @@ package blockchain
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"testing"
 
+	"github.com/kaiachain/kaia/blockchain/state"
 	"github.com/kaiachain/kaia/blockchain/types"
 	"github.com/kaiachain/kaia/blockchain/vm"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/storage/database"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetVMerrFromReceiptStatus(t *testing.T) {
@@ -76,3 +83,72 @@ func TestPrintErrorCodeTable(t *testing.T) {
 		}
 	}
 }
+
+// TestBuyBlobGas tests that the state transition charges the blob fee of a blob tx to its sender
+// and pays it along with the gas fee.
+func TestBuyBlobGas(t *testing.T) {
+	var (
+		key, _     = crypto.GenerateKey()
+		sender     = crypto.PubkeyToAddress(key.PublicKey)
+		rewardbase = common.HexToAddress("0x0000000000000000000000000000000000005678")
+		baseFee    = big.NewInt(25)
+		blobFee    = big.NewInt(int64(10 * params.BlobTxBlobGasPerBlob))
+		gasFee     = big.NewInt(int64(params.TxGas) * 25)
+
+		config = params.TestChainConfig.Copy()
+		header = &types.Header{Number: big.NewInt(1), Time: big.NewInt(0), BlockScore: big.NewInt(0), BaseFee: baseFee, Rewardbase: rewardbase}
+	)
+	config.IstanbulCompatibleBlock = big.NewInt(0)
+	config.LondonCompatibleBlock = big.NewInt(0)
+	config.EthTxTypeCompatibleBlock = big.NewInt(0)
+	config.MagmaCompatibleBlock = big.NewInt(0)
+	config.KoreCompatibleBlock = big.NewInt(0)
+	config.ShanghaiCompatibleBlock = big.NewInt(0)
+	config.CancunCompatibleBlock = big.NewInt(0)
+	config.KaiaCompatibleBlock = big.NewInt(0)
+	config.BlobTxCompatibleBlock = big.NewInt(0)
+
+	tx, err := types.SignTx(types.NewTx(&types.TxInternalDataEthereumBlob{
+		ChainID:    config.ChainID,
+		GasFeeCap:  baseFee,
+		GasTipCap:  big.NewInt(0),
+		GasLimit:   params.TxGas,
+		Recipient:  common.HexToAddress("0x0000000000000000000000000000000000001234"),
+		Amount:     big.NewInt(0),
+		BlobFeeCap: big.NewInt(10),
+		BlobHashes: []common.Hash{{types.BlobTxHashVersion}},
+	}), types.LatestSignerForChainID(config.ChainID), key)
+	require.NoError(t, err)
+
+	testcases := []struct {
+		desc        string
+		blobBaseFee *big.Int
+		balance     *big.Int
+		expectedErr error
+	}{
+		{"blob tx disabled", nil, new(big.Int).Add(gasFee, blobFee), ErrBlobTxDisabled},
+		{"fee cap too low", big.NewInt(11), new(big.Int).Add(gasFee, blobFee), ErrBlobFeeCapTooLow},
+		{"insufficient balance", big.NewInt(10), new(big.Int).Add(gasFee, new(big.Int).Sub(blobFee, common.Big1)), errInsufficientBalanceForBlobGas},
+		{"success", big.NewInt(10), new(big.Int).Add(gasFee, blobFee), nil},
+	}
+	for _, tc := range testcases {
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()), nil, nil)
+		statedb.AddBalance(sender, tc.balance)
+
+		msg, err := tx.AsMessageWithAccountKeyPicker(types.MakeSigner(config, header.Number), statedb, 0)
+		require.NoError(t, err, tc.desc)
+
+		blockContext := NewEVMBlockContext(header, nil, &common.Address{})
+		blockContext.BlobBaseFee = tc.blobBaseFee
+		evm := vm.NewEVM(blockContext, NewEVMTxContext(msg, header, config), statedb, config, &vm.Config{})
+
+		_, err = ApplyMessage(evm, msg)
+		if tc.expectedErr != nil {
+			assert.ErrorIs(t, err, tc.expectedErr, tc.desc)
+			continue
+		}
+		require.NoError(t, err, tc.desc)
+		assert.Zero(t, statedb.GetBalance(sender).Sign(), tc.desc)
+		assert.Equal(t, new(big.Int).Add(gasFee, blobFee), statedb.GetBalance(rewardbase), tc.desc)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/rlp"
+)
+
+// trieJournalVersion is the version of the state trie journal format.
+const trieJournalVersion uint64 = 1
+
+var errTrieJournalHeadMismatch = errors.New("state trie journal doesn't match the head block")
+
+// trieJournalHeader is the header of the state trie journal, followed by the
+// dirty trie nodes written by statedb.Database.Journal.
+type trieJournalHeader struct {
+	Version uint64
+	Head    common.Hash   // Hash of the head block when the journal was written
+	Roots   []common.Hash // State roots of the recent blocks kept in memory
+	Numbers []uint64      // Block numbers of the roots
+}
+
+// writeTrieJournal writes the dirty trie nodes of the recent blocks to the journal
+// file, so that their state is restored on the next startup instead of lost.
+func (bc *BlockChain) writeTrieJournal(recent []gcBlock) error {
+	start := time.Now()
+	header := trieJournalHeader{Version: trieJournalVersion, Head: bc.CurrentBlock().Hash()}
+	for _, block := range recent {
+		header.Roots = append(header.Roots, block.root)
+		header.Numbers = append(header.Numbers, block.blockNum)
+	}
+
+	// Write to a temporary file first, not to leave a partial journal behind.
+	tmp := bc.cacheConfig.TrieJournal + ".new"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	nodes, err := 0, rlp.Encode(w, header)
+	if err == nil {
+		nodes, err = bc.stateCache.TrieDB().Journal(w)
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, bc.cacheConfig.TrieJournal); err != nil {
+		return err
+	}
+	logger.Info("Journalled state tries", "roots", len(recent), "nodes", nodes, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// loadTrieJournal restores the dirty trie nodes of the recent blocks from the
+// journal file written on the last shutdown. The journal is removed afterwards,
+// since the memory database diverges from it as soon as new blocks are imported.
+func (bc *BlockChain) loadTrieJournal() error {
+	f, err := os.Open(bc.cacheConfig.TrieJournal)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer os.Remove(bc.cacheConfig.TrieJournal)
+	defer f.Close()
+
+	start := time.Now()
+	r := bufio.NewReader(f)
+	var header trieJournalHeader
+	if err := rlp.Decode(r, &header); err != nil {
+		return err
+	}
+	if header.Version != trieJournalVersion {
+		return fmt.Errorf("unsupported state trie journal version %d", header.Version)
+	}
+	if header.Head != bc.CurrentBlock().Hash() {
+		return errTrieJournalHeadMismatch
+	}
+	if len(header.Roots) != len(header.Numbers) {
+		return fmt.Errorf("state trie journal has %d roots but %d block numbers", len(header.Roots), len(header.Numbers))
+	}
+	nodes, err := bc.stateCache.TrieDB().LoadJournal(r)
+	if err != nil {
+		return err
+	}
+	for i, root := range header.Roots {
+		bc.triegc.Push(root, -int64(header.Numbers[i]))
+	}
+	logger.Info("Loaded state tries from journal", "roots", len(header.Roots), "nodes", nodes, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
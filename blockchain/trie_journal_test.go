@@ -0,0 +1,112 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kaiachain/kaia/blockchain/state"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/blockchain/vm"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/consensus/gxhash"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockChain_TrieJournal(t *testing.T) {
+	var (
+		db      = database.NewMemoryDBManager()
+		genDB   = database.NewMemoryDBManager() // GenerateChain commits the state of every block
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+
+		gspec = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  GenesisAlloc{addr1: {Balance: big.NewInt(10000000000000)}},
+		}
+		genesis = gspec.MustCommit(db)
+		signer  = types.LatestSignerForChainID(gspec.Config.ChainID)
+		engine  = gxhash.NewFaker()
+		journal = filepath.Join(t.TempDir(), "triejournal")
+	)
+	gspec.MustCommit(genDB)
+	newChain := func(journal string) *BlockChain {
+		cacheConfig := &CacheConfig{
+			CacheSize:     512,
+			BlockInterval: DefaultBlockInterval,
+			TriesInMemory: DefaultTriesInMemory,
+			TrieJournal:   journal,
+		}
+		chain, err := NewBlockChain(db, cacheConfig, gspec.Config, engine, vm.Config{})
+		require.NoError(t, err)
+		return chain
+	}
+
+	blocks, _ := GenerateChain(gspec.Config, genesis, engine, genDB, 5, func(i int, gen *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(
+			gen.TxNonce(addr1), common.HexToAddress("0xaaaa"), big.NewInt(int64(i+1)), 21000, common.Big1, nil), signer, key1)
+		gen.AddTx(tx)
+	})
+	chain := newChain(journal)
+	_, err := chain.InsertChain(blocks)
+	require.NoError(t, err)
+	chain.Stop()
+	require.FileExists(t, journal)
+
+	// Only the head state is written to the disk on shutdown
+	chain = newChain("")
+	_, err = chain.StateAt(blocks[2].Root())
+	assert.Error(t, err)
+	_, err = chain.StateAt(blocks[4].Root())
+	assert.NoError(t, err)
+	chain.Stop()
+
+	// The state of the recent blocks is restored from the journal
+	chain = newChain(journal)
+	for _, block := range blocks {
+		stateDB, err := chain.StateAt(block.Root())
+		require.NoError(t, err, "block %d", block.NumberU64())
+		want, _ := state.New(block.Root(), state.NewDatabase(genDB), nil, nil)
+		assert.Equal(t, want.GetBalance(addr1), stateDB.GetBalance(addr1), "block %d", block.NumberU64())
+		assert.Equal(t, want.GetNonce(addr1), stateDB.GetNonce(addr1), "block %d", block.NumberU64())
+	}
+	_, err = os.Stat(journal)
+	assert.True(t, os.IsNotExist(err), "journal should be removed once loaded")
+
+	// A journal of another head block is dropped
+	chain.Stop()
+	require.FileExists(t, journal)
+	more, _ := GenerateChain(gspec.Config, blocks[4], engine, genDB, 1, nil)
+	chain = newChain("")
+	_, err = chain.InsertChain(more)
+	require.NoError(t, err)
+	chain.Stop()
+
+	chain = newChain(journal)
+	defer chain.Stop()
+	_, err = chain.StateAt(blocks[2].Root())
+	assert.Error(t, err)
+	_, err = os.Stat(journal)
+	assert.True(t, os.IsNotExist(err), "stale journal should be removed")
+}
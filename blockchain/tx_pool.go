@@ -37,6 +37,7 @@ import (
 	"github.com/kaiachain/kaia/common/prque"
 	"github.com/kaiachain/kaia/consensus/misc"
 	"github.com/kaiachain/kaia/event"
+	"github.com/kaiachain/kaia/kaiax"
 	"github.com/kaiachain/kaia/kerrors"
 	"github.com/kaiachain/kaia/params"
 	"github.com/rcrowley/go-metrics"
@@ -125,13 +126,14 @@ type TxPoolConfig struct {
 	Journal            string        // Journal of local transactions to survive node restarts
 	JournalInterval    time.Duration // Time interval to regenerate the local transaction journal
 
-	PriceLimit uint64 // Minimum gas price to enforce for acceptance into the pool
+	PriceLimit uint64 // Minimum gas price to enforce for acceptance of remote transactions into the pool
 	PriceBump  uint64 // Minimum price bump percentage to replace an already existing transaction (nonce)
 
 	ExecSlotsAccount    uint64 // Number of executable transaction slots guaranteed per account
 	ExecSlotsAll        uint64 // Maximum number of executable transaction slots for all accounts
 	NonExecSlotsAccount uint64 // Maximum number of non-executable transaction slots permitted per account
 	NonExecSlotsAll     uint64 // Maximum number of non-executable transaction slots for all accounts
+	BlobsAll            uint64 // Maximum number of blobs held by the blob transactions of all accounts
 
 	KeepLocals bool          // Disables removing timed-out local transactions
 	Lifetime   time.Duration // Maximum amount of time non-executable transaction are queued
@@ -146,13 +148,14 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	Journal:         "transactions.rlp",
 	JournalInterval: time.Hour,
 
-	PriceLimit: 1,
+	PriceLimit: 0,
 	PriceBump:  10,
 
 	ExecSlotsAccount:    16,
 	ExecSlotsAll:        4096,
 	NonExecSlotsAccount: 64,
 	NonExecSlotsAll:     1024,
+	BlobsAll:            256,
 
 	KeepLocals: false,
 	Lifetime:   5 * time.Minute,
@@ -166,10 +169,6 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 		logger.Error("Sanitizing invalid txpool journal time", "provided", conf.JournalInterval, "updated", time.Second)
 		conf.JournalInterval = time.Second
 	}
-	if conf.PriceLimit < 1 {
-		logger.Error("Sanitizing invalid txpool price limit", "provided", conf.PriceLimit, "updated", DefaultTxPoolConfig.PriceLimit)
-		conf.PriceLimit = DefaultTxPoolConfig.PriceLimit
-	}
 	if conf.PriceBump < 1 {
 		logger.Error("Sanitizing invalid txpool price bump", "provided", conf.PriceBump, "updated", DefaultTxPoolConfig.PriceBump)
 		conf.PriceBump = DefaultTxPoolConfig.PriceBump
@@ -177,6 +176,55 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 	return conf
 }
 
+// TxPoolLimits holds the txpool parameters that can be changed at runtime.
+type TxPoolLimits struct {
+	PriceLimit          uint64        `json:"priceLimit"`
+	PriceBump           uint64        `json:"priceBump"`
+	ExecSlotsAccount    uint64        `json:"execSlotsAccount"`
+	ExecSlotsAll        uint64        `json:"execSlotsAll"`
+	NonExecSlotsAccount uint64        `json:"nonExecSlotsAccount"`
+	NonExecSlotsAll     uint64        `json:"nonExecSlotsAll"`
+	Lifetime            time.Duration `json:"lifetime"`
+}
+
+// Validate rejects the limits that would make the pool unworkable.
+func (l *TxPoolLimits) Validate() error {
+	if l.PriceBump < 1 {
+		return fmt.Errorf("invalid price bump: %d", l.PriceBump)
+	}
+	if l.ExecSlotsAll == 0 || l.NonExecSlotsAll == 0 {
+		return fmt.Errorf("invalid slots for all accounts: exec=%d, nonexec=%d", l.ExecSlotsAll, l.NonExecSlotsAll)
+	}
+	if l.Lifetime < time.Second {
+		return fmt.Errorf("invalid lifetime: %v", l.Lifetime)
+	}
+	return nil
+}
+
+// Limits returns the runtime-adjustable part of the configuration.
+func (config *TxPoolConfig) Limits() TxPoolLimits {
+	return TxPoolLimits{
+		PriceLimit:          config.PriceLimit,
+		PriceBump:           config.PriceBump,
+		ExecSlotsAccount:    config.ExecSlotsAccount,
+		ExecSlotsAll:        config.ExecSlotsAll,
+		NonExecSlotsAccount: config.NonExecSlotsAccount,
+		NonExecSlotsAll:     config.NonExecSlotsAll,
+		Lifetime:            config.Lifetime,
+	}
+}
+
+// SetLimits overwrites the runtime-adjustable part of the configuration.
+func (config *TxPoolConfig) SetLimits(l TxPoolLimits) {
+	config.PriceLimit = l.PriceLimit
+	config.PriceBump = l.PriceBump
+	config.ExecSlotsAccount = l.ExecSlotsAccount
+	config.ExecSlotsAll = l.ExecSlotsAll
+	config.NonExecSlotsAccount = l.NonExecSlotsAccount
+	config.NonExecSlotsAll = l.NonExecSlotsAll
+	config.Lifetime = l.Lifetime
+}
+
 // TxPool contains all currently known transactions. Transactions
 // enter the pool when they are received from the network or submitted
 // locally. They exit the pool when they are included in the blockchain.
@@ -218,6 +266,9 @@ type TxPool struct {
 	txFeedCh chan types.Transactions // A buffer for async tx event emission via txFeed
 
 	rules params.Rules // Fork indicator
+
+	// kaiax modules
+	txPoolModules []kaiax.TxPoolModule
 }
 
 // NewTxPool creates a new transaction pool to gather, sort and filter inbound
@@ -551,6 +602,42 @@ func (pool *TxPool) SetGasPrice(price *big.Int) {
 	}
 }
 
+// SetSlotLimits changes the maximum numbers of executable and non-executable transaction
+// slots for all accounts. Lowering them evicts transactions immediately.
+func (pool *TxPool) SetSlotLimits(execSlotsAll, nonExecSlotsAll uint64) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	logger.Info("TxPool.SetSlotLimits", "execSlotsAll", execSlotsAll, "nonExecSlotsAll", nonExecSlotsAll)
+	pool.config.ExecSlotsAll = execSlotsAll
+	pool.config.NonExecSlotsAll = nonExecSlotsAll
+	pool.promoteExecutables(nil)
+}
+
+// Limits returns the runtime-adjustable parameters of the pool.
+func (pool *TxPool) Limits() TxPoolLimits {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	return pool.config.Limits()
+}
+
+// SetLimits changes the runtime-adjustable parameters of the pool. Lowering the slots
+// evicts transactions immediately, while the price limit and bump apply to new transactions.
+func (pool *TxPool) SetLimits(limits TxPoolLimits) error {
+	if err := limits.Validate(); err != nil {
+		return err
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	logger.Info("TxPool.SetLimits", "before", pool.config.Limits(), "after", limits)
+	pool.config.SetLimits(limits)
+	pool.promoteExecutables(nil)
+	return nil
+}
+
 // Stats retrieves the current pool stats, namely the number of pending and the
 // number of queued (non-executable) transactions.
 func (pool *TxPool) Stats() (int, int) {
@@ -678,6 +765,10 @@ func (pool *TxPool) validateTx(tx *types.Transaction) error {
 	if !pool.rules.IsEthTxType && tx.Type() == types.TxTypeEthereumDynamicFee {
 		return ErrTxTypeNotSupported
 	}
+	// Reject blob transactions until the blob tx hardfork activates.
+	if !pool.rules.IsBlobTx && tx.Type() == types.TxTypeEthereumBlob {
+		return ErrTxTypeNotSupported
+	}
 
 	// Check whether the init code size has been exceeded
 	if pool.rules.IsShanghai && tx.To() == nil && len(tx.Data()) > params.MaxInitCodeSize {
@@ -691,7 +782,7 @@ func (pool *TxPool) validateTx(tx *types.Transaction) error {
 
 	// NOTE-Kaia Drop transactions with unexpected gasPrice
 	// If the transaction type is DynamicFee tx, Compare transaction's GasFeeCap(MaxFeePerGas) and GasTipCap with tx pool's gasPrice to check to have same value.
-	if tx.Type() == types.TxTypeEthereumDynamicFee {
+	if tx.Type() == types.TxTypeEthereumDynamicFee || tx.Type() == types.TxTypeEthereumBlob {
 		// Sanity check for extremely large numbers
 		if tx.GasTipCap().BitLen() > 256 {
 			return ErrTipVeryHigh
@@ -741,11 +832,23 @@ func (pool *TxPool) validateTx(tx *types.Transaction) error {
 		}
 	}
 
-	// Reject transactions over MaxTxDataSize to prevent DOS attacks
-	if uint64(tx.Size()) > MaxTxDataSize {
+	// Reject transactions over MaxTxDataSize to prevent DOS attacks.
+	// The blob sidecar is not counted since it is never included in a block.
+	if uint64(tx.WithoutBlobTxSidecar().Size()) > MaxTxDataSize {
 		return ErrOversizedData
 	}
 
+	// A blob transaction must carry the blobs matching its versioned hashes.
+	if tx.Type() == types.TxTypeEthereumBlob {
+		sidecar := tx.BlobTxSidecar()
+		if sidecar == nil {
+			return types.ErrMissingBlobSidecar
+		}
+		if err := sidecar.ValidateBlobHashes(tx.BlobHashes()); err != nil {
+			return err
+		}
+	}
+
 	// Transactions can't be negative. This may never happen using RLP decoded
 	// transactions but may occur if you create a transaction using the RPC.
 	if tx.Value().Sign() < 0 {
@@ -885,6 +988,30 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 		invalidTxCounter.Inc(1)
 		return false, err
 	}
+	// Drop remote transactions under the minimum gas price of this node
+	if !local && tx.GasFeeCap().Cmp(new(big.Int).SetUint64(pool.config.PriceLimit)) < 0 {
+		logger.Trace("Discarding transaction under the price limit", "hash", hash, "price", tx.GasFeeCap(), "limit", pool.config.PriceLimit)
+		underpricedTxCounter.Inc(1)
+		return false, ErrUnderpriced
+	}
+	// If any module rejects the transaction, discard it
+	for _, module := range pool.txPoolModules {
+		preAdd := module.PreAddRemote
+		if local {
+			preAdd = module.PreAddLocal
+		}
+		if err := preAdd(tx); err != nil {
+			logger.Trace("Discarding transaction rejected by a module", "hash", hash, "err", err)
+			invalidTxCounter.Inc(1)
+			return false, err
+		}
+	}
+	// Bound the memory held by the blobs, which are much larger than the other txs
+	if blobs := len(tx.BlobHashes()); blobs > 0 && uint64(pool.all.Blobs()+blobs) > pool.config.BlobsAll {
+		logger.Trace("Rejecting a new blob Tx, because the blobs in TxPool are full", "hash", hash, "blobs", pool.all.Blobs())
+		refusedTxCounter.Inc(1)
+		return false, ErrBlobPoolFull
+	}
 
 	// If the transaction pool is full and new Tx is valid,
 	// (1) discard a new Tx if there is no room for the account of the Tx
@@ -1753,6 +1880,7 @@ func (as *accountSet) add(addr common.Address) {
 type txLookup struct {
 	all   map[common.Hash]*types.Transaction
 	slots int
+	blobs int
 	lock  sync.RWMutex
 }
 
@@ -1772,6 +1900,14 @@ func (t *txLookup) Slots() int {
 	return t.slots
 }
 
+// Blobs returns the current number of blobs held by the blob transactions in the lookup.
+func (t *txLookup) Blobs() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.blobs
+}
+
 // Range calls f on each key and value present in the map.
 func (t *txLookup) Range(f func(hash common.Hash, tx *types.Transaction) bool) {
 	t.lock.RLock()
@@ -1807,6 +1943,7 @@ func (t *txLookup) Add(tx *types.Transaction) {
 
 	t.slots += numSlots(tx)
 	slotsGauge.Update(int64(t.slots))
+	t.blobs += len(tx.BlobHashes())
 
 	t.all[tx.Hash()] = tx
 }
@@ -1818,6 +1955,7 @@ func (t *txLookup) Remove(hash common.Hash) {
 
 	t.slots -= numSlots(t.all[hash])
 	slotsGauge.Update(int64(t.slots))
+	t.blobs -= len(t.all[hash].BlobHashes())
 
 	delete(t.all, hash)
 }
@@ -1826,3 +1964,10 @@ func (t *txLookup) Remove(hash common.Hash) {
 func numSlots(tx *types.Transaction) int {
 	return int((tx.Size() + txSlotSize - 1) / txSlotSize)
 }
+
+func (pool *TxPool) RegisterTxPoolModule(modules ...kaiax.TxPoolModule) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pool.txPoolModules = append(pool.txPoolModules, modules...)
+}
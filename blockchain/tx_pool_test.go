@@ -38,6 +38,7 @@ import (
 	"github.com/kaiachain/kaia/blockchain/types"
 	"github.com/kaiachain/kaia/common"
 	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/crypto/kzg4844"
 	"github.com/kaiachain/kaia/event"
 	"github.com/kaiachain/kaia/fork"
 	"github.com/kaiachain/kaia/params"
@@ -140,6 +141,40 @@ func dynamicFeeTx(nonce uint64, gaslimit uint64, gasFee *big.Int, tip *big.Int,
 	return signedTx
 }
 
+func blobTx(nonce uint64, gaslimit uint64, gasFee *big.Int, blobFee *big.Int, sidecar *types.BlobTxSidecar, key *ecdsa.PrivateKey) *types.Transaction {
+	tx := types.NewTx(&types.TxInternalDataEthereumBlob{
+		ChainID:      params.TestChainConfig.ChainID,
+		AccountNonce: nonce,
+		GasTipCap:    gasFee,
+		GasFeeCap:    gasFee,
+		GasLimit:     gaslimit,
+		Recipient:    common.HexToAddress("0xAAAA"),
+		Amount:       big.NewInt(100),
+		BlobFeeCap:   blobFee,
+		BlobHashes:   sidecar.BlobHashes(),
+		Sidecar:      sidecar,
+	})
+
+	signedTx, _ := types.SignTx(tx, types.LatestSignerForChainID(params.TestChainConfig.ChainID), key)
+	return signedTx
+}
+
+func blobTxSidecar(t *testing.T, numBlobs int) *types.BlobTxSidecar {
+	sidecar := &types.BlobTxSidecar{}
+	for i := 0; i < numBlobs; i++ {
+		var blob kzg4844.Blob
+		blob[1] = byte(i) // keep every field element below the modulus
+		commitment, err := kzg4844.BlobToCommitment(blob)
+		assert.NoError(t, err)
+		proof, err := kzg4844.ComputeBlobProof(blob, commitment)
+		assert.NoError(t, err)
+		sidecar.Blobs = append(sidecar.Blobs, blob)
+		sidecar.Commitments = append(sidecar.Commitments, commitment)
+		sidecar.Proofs = append(sidecar.Proofs, proof)
+	}
+	return sidecar
+}
+
 func cancelTx(nonce uint64, gasLimit uint64, gasPrice *big.Int, from common.Address, key *ecdsa.PrivateKey) *types.Transaction {
 	d, err := types.NewTxInternalDataWithMap(types.TxTypeCancel, map[types.TxValueKeyType]interface{}{
 		types.TxValueKeyNonce:    nonce,
@@ -1314,6 +1349,111 @@ func TestTransactionPendingGlobalLimiting(t *testing.T) {
 	}
 }
 
+// Tests that lowering the global slot limits at runtime evicts the transactions
+// over the new limits.
+func TestTransactionSetSlotLimits(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()), nil, nil)
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.ExecSlotsAll = config.ExecSlotsAccount * 10
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	keys := make([]*ecdsa.PrivateKey, 5)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000))
+	}
+	txs := types.Transactions{}
+	for _, key := range keys {
+		for j := 0; j < int(config.ExecSlotsAll)/len(keys); j++ {
+			txs = append(txs, transaction(uint64(j), 100000, key))
+		}
+	}
+	pool.AddRemotes(txs)
+
+	pending, _ := pool.Stats()
+	if pending != int(config.ExecSlotsAll) {
+		t.Fatalf("pending transactions mismatch: have %d, want %d", pending, config.ExecSlotsAll)
+	}
+
+	limit := config.ExecSlotsAll / 2
+	pool.SetSlotLimits(limit, config.NonExecSlotsAll)
+	if pending, _ := pool.Stats(); pending > int(limit) {
+		t.Fatalf("total pending transactions overflow new allowance: %d > %d", pending, limit)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the limits changed at runtime apply to the pool right away.
+func TestTransactionSetLimits(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, account, big.NewInt(1000000))
+
+	// Invalid limits are rejected without touching the pool.
+	invalid := pool.Limits()
+	invalid.PriceBump = 0
+	if err := pool.SetLimits(invalid); err == nil {
+		t.Fatal("expected error for the zero price bump")
+	}
+	if have := pool.Limits(); have != testTxPoolConfig.Limits() {
+		t.Fatalf("limits mismatch: have %+v, want %+v", have, testTxPoolConfig.Limits())
+	}
+
+	// The price limit rejects the remote transactions under it, but not the local ones.
+	limits := pool.Limits()
+	limits.PriceLimit = 2
+	limits.NonExecSlotsAccount = 2
+	limits.Lifetime = time.Minute
+	if err := pool.SetLimits(limits); err != nil {
+		t.Fatal(err)
+	}
+	if have := pool.Limits(); have != limits {
+		t.Fatalf("limits mismatch: have %+v, want %+v", have, limits)
+	}
+	if err := pool.AddRemote(transaction(0, 100000, key)); err != ErrUnderpriced {
+		t.Fatalf("adding remote transaction under the price limit error mismatch: have %v, want %v", err, ErrUnderpriced)
+	}
+	if err := pool.AddLocal(transaction(0, 100000, key)); err != nil {
+		t.Fatalf("failed to add local transaction under the price limit: %v", err)
+	}
+
+	// Lowering the per-account slots evicts the remote queued transactions over them.
+	remote, _ := crypto.GenerateKey()
+	testAddBalance(pool, crypto.PubkeyToAddress(remote.PublicKey), big.NewInt(1000000))
+
+	limits.PriceLimit = 0
+	if err := pool.SetLimits(limits); err != nil {
+		t.Fatal(err)
+	}
+	for i := uint64(1); i < 3; i++ {
+		if err := pool.AddRemote(transaction(i, 100000, remote)); err != nil {
+			t.Fatalf("failed to add queued transaction %d: %v", i, err)
+		}
+	}
+	limits.NonExecSlotsAccount = 1
+	if err := pool.SetLimits(limits); err != nil {
+		t.Fatal(err)
+	}
+	if _, queued := pool.Stats(); queued != 1 {
+		t.Fatalf("queued transactions mismatch: have %d, want %d", queued, 1)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // Test the limit on transaction size is enforced correctly.
 // This test verifies every transaction having allowed size
 // is added to the pool, and longer transactions are rejected.
@@ -2183,6 +2323,35 @@ func TestDynamicFeeTransactionAcceptedEip1559(t *testing.T) {
 	}
 }
 
+// TestBlobTransaction tests that the pool accepts a blob tx only after the blob tx hardfork
+// with its blobs, and bounds the number of blobs it holds.
+func TestBlobTransaction(t *testing.T) {
+	t.Parallel()
+
+	blobConfig := eip1559Config.Copy()
+	blobConfig.BlobTxCompatibleBlock = common.Big0
+
+	sidecar := blobTxSidecar(t, 2)
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+	assert.Equal(t, ErrTxTypeNotSupported, pool.AddRemote(blobTx(0, 21000, big.NewInt(1), big.NewInt(1), sidecar, key)))
+
+	pool, key = setupTxPoolWithConfig(blobConfig)
+	defer pool.Stop()
+	pool.config.BlobsAll = 3
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000000))
+
+	// The blobs must match the versioned hashes.
+	assert.Equal(t, types.ErrMissingBlobSidecar, pool.AddRemote(blobTx(0, 21000, big.NewInt(1), big.NewInt(1), sidecar, key).WithoutBlobTxSidecar()))
+	mismatch := blobTx(0, 21000, big.NewInt(1), big.NewInt(1), sidecar, key).WithBlobTxSidecar(blobTxSidecar(t, 1))
+	assert.ErrorIs(t, pool.AddRemote(mismatch), types.ErrBlobSidecarMismatch)
+
+	assert.NoError(t, pool.AddRemote(blobTx(0, 21000, big.NewInt(1), big.NewInt(1), sidecar, key)))
+	assert.Equal(t, 2, pool.all.Blobs())
+	assert.Equal(t, ErrBlobPoolFull, pool.AddRemote(blobTx(1, 21000, big.NewInt(1), big.NewInt(1), sidecar, key)))
+}
+
 // TestDynamicFeeTransactionAccepted tests that pool accept the transaction which has gasFeeCap bigger than or equal to baseFee.
 func TestDynamicFeeTransactionAcceptedMagma(t *testing.T) {
 	t.Parallel()
@@ -46,15 +46,27 @@ type IstanbulExtra struct {
 	Validators    []common.Address
 	Seal          []byte
 	CommittedSeal [][]byte
+	KeyRotation   *KeyRotation // Optional. Only allowed since the KeyRotation fork
+}
+
+// KeyRotation is an announcement by the block proposer that its signing key
+// will be replaced by Successor from ActivationBlock on.
+type KeyRotation struct {
+	Successor       common.Address
+	ActivationBlock uint64
 }
 
 // EncodeRLP serializes the istanbul fields into the Kaia RLP format.
 func (ist *IstanbulExtra) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, []interface{}{
+	fields := []interface{}{
 		ist.Validators,
 		ist.Seal,
 		ist.CommittedSeal,
-	})
+	}
+	if ist.KeyRotation != nil {
+		fields = append(fields, ist.KeyRotation)
+	}
+	return rlp.Encode(w, fields)
 }
 
 // DecodeRLP implements rlp.Decoder, and load the istanbul fields from a RLP stream.
@@ -63,11 +75,13 @@ func (ist *IstanbulExtra) DecodeRLP(s *rlp.Stream) error {
 		Validators    []common.Address
 		Seal          []byte
 		CommittedSeal [][]byte
+		KeyRotation   *KeyRotation `rlp:"optional"`
 	}
 	if err := s.Decode(&istanbulExtra); err != nil {
 		return err
 	}
 	ist.Validators, ist.Seal, ist.CommittedSeal = istanbulExtra.Validators, istanbulExtra.Seal, istanbulExtra.CommittedSeal
+	ist.KeyRotation = istanbulExtra.KeyRotation
 	return nil
 }
 
@@ -285,8 +285,7 @@ func (tx *Transaction) setDecoded(inner TxInternalData, size int) {
 func (tx *Transaction) Gas() uint64        { return tx.data.GetGasLimit() }
 func (tx *Transaction) GasPrice() *big.Int { return new(big.Int).Set(tx.data.GetPrice()) }
 func (tx *Transaction) GasTipCap() *big.Int {
-	if tx.Type() == TxTypeEthereumDynamicFee {
-		te := tx.GetTxInternalData().(TxInternalDataBaseFee)
+	if te, ok := tx.GetTxInternalData().(TxInternalDataBaseFee); ok {
 		return te.GetGasTipCap()
 	}
 
@@ -294,14 +293,81 @@ func (tx *Transaction) GasTipCap() *big.Int {
 }
 
 func (tx *Transaction) GasFeeCap() *big.Int {
-	if tx.Type() == TxTypeEthereumDynamicFee {
-		te := tx.GetTxInternalData().(TxInternalDataBaseFee)
+	if te, ok := tx.GetTxInternalData().(TxInternalDataBaseFee); ok {
 		return te.GetGasFeeCap()
 	}
 
 	return tx.data.GetPrice()
 }
 
+// BlobGas returns the blob gas limit of the transaction for blob transactions, 0 otherwise.
+func (tx *Transaction) BlobGas() uint64 {
+	return uint64(len(tx.BlobHashes())) * params.BlobTxBlobGasPerBlob
+}
+
+// BlobGasFeeCap returns the blob gas fee cap per blob gas of the transaction for blob transactions, nil otherwise.
+func (tx *Transaction) BlobGasFeeCap() *big.Int {
+	if tb, ok := tx.data.(TxInternalDataBlob); ok {
+		return new(big.Int).Set(tb.GetBlobFeeCap())
+	}
+	return nil
+}
+
+// BlobHashes returns the hashes of the blob commitments for blob transactions, nil otherwise.
+func (tx *Transaction) BlobHashes() []common.Hash {
+	if tb, ok := tx.data.(TxInternalDataBlob); ok {
+		return tb.GetBlobHashes()
+	}
+	return nil
+}
+
+// BlobTxSidecar returns the sidecar of a blob transaction, nil otherwise.
+func (tx *Transaction) BlobTxSidecar() *BlobTxSidecar {
+	if tb, ok := tx.data.(TxInternalDataBlob); ok {
+		return tb.GetSidecar()
+	}
+	return nil
+}
+
+// WithoutBlobTxSidecar returns a copy of tx with the blob sidecar removed.
+// The hash of the transaction is not affected.
+func (tx *Transaction) WithoutBlobTxSidecar() *Transaction {
+	return tx.withBlobTxSidecar(nil)
+}
+
+// WithBlobTxSidecar returns a copy of tx with the blob sidecar set.
+// The hash of the transaction is not affected.
+func (tx *Transaction) WithBlobTxSidecar(sidecar *BlobTxSidecar) *Transaction {
+	return tx.withBlobTxSidecar(sidecar)
+}
+
+func (tx *Transaction) withBlobTxSidecar(sidecar *BlobTxSidecar) *Transaction {
+	tb, ok := tx.data.(*TxInternalDataEthereumBlob)
+	if !ok || tb.Sidecar == sidecar {
+		return tx
+	}
+	data := *tb
+	data.Sidecar = sidecar
+
+	tx.mu.RLock()
+	cpy := &Transaction{
+		data:                  &data,
+		time:                  tx.time,
+		validatedSender:       tx.validatedSender,
+		validatedFeePayer:     tx.validatedFeePayer,
+		validatedIntrinsicGas: tx.validatedIntrinsicGas,
+		checkNonce:            tx.checkNonce,
+	}
+	tx.mu.RUnlock()
+	if h := tx.hash.Load(); h != nil {
+		cpy.hash.Store(h)
+	}
+	if f := tx.from.Load(); f != nil {
+		cpy.from.Store(f)
+	}
+	return cpy
+}
+
 func (tx *Transaction) EffectiveGasTip(baseFee *big.Int) *big.Int {
 	// effectiveGasPrice - baseFee = min(baseFee + tipCap, feeCap) - baseFee = min(tipCap, feeCap - baseFee)
 	if baseFee != nil {
@@ -555,6 +621,12 @@ func (tx *Transaction) Time() time.Time {
 	return tx.time
 }
 
+// SetTime overrides the time that transaction was created. It restores the arrival order
+// of the persisted transactions, which breaks the ties of the price ordering.
+func (tx *Transaction) SetTime(t time.Time) {
+	tx.time = t
+}
+
 // FillContractAddress fills contract address to receipt. This only works for types deploying a smart contract.
 func (tx *Transaction) FillContractAddress(from common.Address, r *Receipt) {
 	if filler, ok := tx.data.(TxInternalDataContractAddressFiller); ok {
@@ -647,6 +719,9 @@ func (tx *Transaction) WithFeePayerSignature(signer Signer, sig []byte) (*Transa
 func (tx *Transaction) Cost() *big.Int {
 	total := tx.Fee()
 	total.Add(total, tx.data.GetAmount())
+	if blobFeeCap := tx.BlobGasFeeCap(); blobFeeCap != nil {
+		total.Add(total, new(big.Int).Mul(blobFeeCap, new(big.Int).SetUint64(tx.BlobGas())))
+	}
 	return total
 }
 
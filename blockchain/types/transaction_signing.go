@@ -253,7 +253,14 @@ type Signer interface {
 
 type londonSigner struct{ eip2930Signer }
 
+// isLondonSignedTxType returns true if the type is signed by londonSigner rather than the embedded signers.
+// A blob transaction is signed the same way as a dynamic fee transaction.
+func isLondonSignedTxType(t TxType) bool {
+	return t == TxTypeEthereumDynamicFee || t == TxTypeEthereumBlob
+}
+
 // NewLondonSigner returns a signer that accepts
+// - EIP-4844 blob transactions,
 // - EIP-1559 dynamic fee transactions,
 // - EIP-2930 access list transactions and
 // - EIP-155 replay protected transactions.
@@ -273,7 +280,7 @@ func (s londonSigner) Equal(s2 Signer) bool {
 }
 
 func (s londonSigner) Sender(tx *Transaction) (common.Address, error) {
-	if tx.Type() != TxTypeEthereumDynamicFee {
+	if !isLondonSignedTxType(tx.Type()) {
 		return s.eip2930Signer.Sender(tx)
 	}
 
@@ -291,7 +298,7 @@ func (s londonSigner) Sender(tx *Transaction) (common.Address, error) {
 
 // SenderPubkey returns the public key derived from tx signature and txhash.
 func (s londonSigner) SenderPubkey(tx *Transaction) ([]*ecdsa.PublicKey, error) {
-	if tx.Type() != TxTypeEthereumDynamicFee {
+	if !isLondonSignedTxType(tx.Type()) {
 		return s.eip2930Signer.SenderPubkey(tx)
 	}
 
@@ -316,7 +323,7 @@ func (s londonSigner) SenderFeePayer(tx *Transaction) ([]*ecdsa.PublicKey, error
 // SignatureValues returns a new transaction with the given signature. This signature
 // needs to be in the [R || S || V] format where V is 0 or 1.
 func (s londonSigner) SignatureValues(tx *Transaction, sig []byte) (R, S, V *big.Int, err error) {
-	if tx.Type() != TxTypeEthereumDynamicFee {
+	if !isLondonSignedTxType(tx.Type()) {
 		return s.eip2930Signer.SignatureValues(tx, sig)
 	}
 
@@ -340,7 +347,7 @@ func (s londonSigner) SignatureValues(tx *Transaction, sig []byte) (R, S, V *big
 // Hash returns the hash to be signed by the sender.
 // It does not uniquely identify the transaction.
 func (s londonSigner) Hash(tx *Transaction) common.Hash {
-	if tx.Type() != TxTypeEthereumDynamicFee {
+	if !isLondonSignedTxType(tx.Type()) {
 		return s.eip2930Signer.Hash(tx)
 	}
 
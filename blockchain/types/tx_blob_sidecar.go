@@ -0,0 +1,81 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto/kzg4844"
+)
+
+// BlobTxHashVersion is the version byte of a blob versioned hash, kzg_to_versioned_hash of EIP-4844.
+const BlobTxHashVersion = 0x01
+
+var (
+	ErrMissingBlobHashes      = errors.New("blob transaction has no blob hashes")
+	ErrInvalidBlobHashVersion = errors.New("blob hash has an invalid version")
+	ErrMissingBlobSidecar     = errors.New("blob transaction has no sidecar")
+	ErrBlobSidecarMismatch    = errors.New("blob sidecar does not match the blob hashes")
+)
+
+// BlobTxSidecar contains the blobs of a blob transaction. The sidecar is propagated
+// along with the transaction in the txpool, but stripped off when the transaction is
+// included in a block. It does not take part in the transaction hash.
+type BlobTxSidecar struct {
+	Blobs       []kzg4844.Blob       // Blobs needed by the blob pool
+	Commitments []kzg4844.Commitment // Commitments needed by the blob pool
+	Proofs      []kzg4844.Proof      // Proofs needed by the blob pool
+}
+
+// BlobHashes computes the blob hashes of the given blobs.
+func (sc *BlobTxSidecar) BlobHashes() []common.Hash {
+	h := make([]common.Hash, len(sc.Commitments))
+	for i := range sc.Commitments {
+		h[i] = KZGToVersionedHash(sc.Commitments[i])
+	}
+	return h
+}
+
+// ValidateBlobHashes checks that the sidecar carries exactly the blobs committed to by
+// hashes, and that every blob is consistent with its commitment and proof.
+func (sc *BlobTxSidecar) ValidateBlobHashes(hashes []common.Hash) error {
+	if len(sc.Blobs) != len(hashes) || len(sc.Commitments) != len(hashes) || len(sc.Proofs) != len(hashes) {
+		return fmt.Errorf("%w: %d hashes, %d blobs, %d commitments, %d proofs", ErrBlobSidecarMismatch,
+			len(hashes), len(sc.Blobs), len(sc.Commitments), len(sc.Proofs))
+	}
+	for i, h := range sc.BlobHashes() {
+		if h != hashes[i] {
+			return fmt.Errorf("%w: blob %d has hash %x, want %x", ErrBlobSidecarMismatch, i, h, hashes[i])
+		}
+	}
+	for i := range sc.Blobs {
+		if err := kzg4844.VerifyBlobProof(sc.Blobs[i], sc.Commitments[i], sc.Proofs[i]); err != nil {
+			return fmt.Errorf("invalid kzg proof of blob %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// KZGToVersionedHash implements kzg_to_versioned_hash of EIP-4844.
+func KZGToVersionedHash(commitment kzg4844.Commitment) common.Hash {
+	h := sha256.Sum256(commitment[:])
+	h[0] = BlobTxHashVersion
+	return h
+}
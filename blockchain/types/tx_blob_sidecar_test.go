@@ -0,0 +1,88 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto/kzg4844"
+	"github.com/kaiachain/kaia/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBlobTxSidecar(t *testing.T) *BlobTxSidecar {
+	var blob kzg4844.Blob // all-zero blob is a valid blob
+	commitment, err := kzg4844.BlobToCommitment(blob)
+	require.NoError(t, err)
+	proof, err := kzg4844.ComputeBlobProof(blob, commitment)
+	require.NoError(t, err)
+
+	return &BlobTxSidecar{
+		Blobs:       []kzg4844.Blob{blob},
+		Commitments: []kzg4844.Commitment{commitment},
+		Proofs:      []kzg4844.Proof{proof},
+	}
+}
+
+func TestBlobTxSidecarValidateBlobHashes(t *testing.T) {
+	sidecar := newTestBlobTxSidecar(t)
+	hashes := sidecar.BlobHashes()
+	assert.Equal(t, byte(BlobTxHashVersion), hashes[0][0])
+	assert.NoError(t, sidecar.ValidateBlobHashes(hashes))
+
+	assert.ErrorIs(t, sidecar.ValidateBlobHashes(nil), ErrBlobSidecarMismatch)
+	assert.ErrorIs(t, sidecar.ValidateBlobHashes([]common.Hash{{BlobTxHashVersion}}), ErrBlobSidecarMismatch)
+}
+
+func TestTransactionWithoutBlobTxSidecar(t *testing.T) {
+	sidecar := newTestBlobTxSidecar(t)
+	tx, err := NewTransactionWithMap(TxTypeEthereumBlob, map[TxValueKeyType]interface{}{
+		TxValueKeyChainID:     big.NewInt(1),
+		TxValueKeyNonce:       uint64(0),
+		TxValueKeyTo:          to,
+		TxValueKeyAmount:      amount,
+		TxValueKeyData:        []byte{},
+		TxValueKeyGasLimit:    gasLimit,
+		TxValueKeyGasFeeCap:   gasFeeCap,
+		TxValueKeyGasTipCap:   gasTipCap,
+		TxValueKeyAccessList:  AccessList{},
+		TxValueKeyBlobFeeCap:  big.NewInt(1),
+		TxValueKeyBlobHashes:  sidecar.BlobHashes(),
+		TxValueKeyBlobSidecar: sidecar,
+	})
+	require.NoError(t, err)
+	require.NoError(t, tx.Sign(NewLondonSigner(big.NewInt(1)), key))
+
+	stripped := tx.WithoutBlobTxSidecar()
+	assert.Nil(t, stripped.BlobTxSidecar())
+	assert.Equal(t, sidecar, tx.BlobTxSidecar())
+	assert.Equal(t, tx.Hash(), stripped.Hash())
+	assert.Less(t, stripped.Size(), tx.Size())
+
+	// The sidecar survives the round trip of the pool encoding but not of the block encoding.
+	for _, want := range []*Transaction{tx, stripped} {
+		enc, err := rlp.EncodeToBytes(want)
+		require.NoError(t, err)
+		got := new(Transaction)
+		require.NoError(t, rlp.DecodeBytes(enc, got))
+		assert.Equal(t, want.Hash(), got.Hash())
+		assert.Equal(t, want.BlobTxSidecar(), got.BlobTxSidecar())
+	}
+}
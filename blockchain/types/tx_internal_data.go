@@ -58,7 +58,8 @@ const (
 	TxTypeKaiaLast, _, _
 	TxTypeEthereumAccessList = TxType(0x7801)
 	TxTypeEthereumDynamicFee = TxType(0x7802)
-	TxTypeEthereumLast       = TxType(0x7803)
+	TxTypeEthereumBlob       = TxType(0x7803)
+	TxTypeEthereumLast       = TxType(0x7804)
 )
 
 type TxValueKeyType uint
@@ -83,6 +84,9 @@ const (
 	TxValueKeyChainID
 	TxValueKeyGasTipCap
 	TxValueKeyGasFeeCap
+	TxValueKeyBlobFeeCap
+	TxValueKeyBlobHashes
+	TxValueKeyBlobSidecar
 )
 
 type TxTypeMask uint8
@@ -118,6 +122,8 @@ var (
 	errValueKeyChainIDInvalid            = errors.New("ChainID must be a type of ChainID")
 	errValueKeyGasTipCapMustBigInt       = errors.New("GasTipCap must be a type of *big.Int")
 	errValueKeyGasFeeCapMustBigInt       = errors.New("GasFeeCap must be a type of *big.Int")
+	errValueKeyBlobFeeCapMustBigInt      = errors.New("BlobFeeCap must be a type of *big.Int")
+	errValueKeyBlobHashesMustHashSlice   = errors.New("BlobHashes must be a slice of common.Hash")
 
 	ErrTxTypeNotSupported         = errors.New("transaction type not supported")
 	ErrSenderPubkeyNotSupported   = errors.New("SenderPubkey is not supported for this signer")
@@ -164,6 +170,12 @@ func (t TxValueKeyType) String() string {
 		return "TxValueKeyGasTipCap"
 	case TxValueKeyGasFeeCap:
 		return "TxValueKeyGasFeeCap"
+	case TxValueKeyBlobFeeCap:
+		return "TxValueKeyBlobFeeCap"
+	case TxValueKeyBlobHashes:
+		return "TxValueKeyBlobHashes"
+	case TxValueKeyBlobSidecar:
+		return "TxValueKeyBlobSidecar"
 	}
 
 	return "UndefinedTxValueKeyType"
@@ -223,11 +235,31 @@ func (t TxType) String() string {
 		return "TxTypeEthereumAccessList"
 	case TxTypeEthereumDynamicFee:
 		return "TxTypeEthereumDynamicFee"
+	case TxTypeEthereumBlob:
+		return "TxTypeEthereumBlob"
 	}
 
 	return "UndefinedTxType"
 }
 
+// ParseTxType returns the TxType whose String() is name.
+func ParseTxType(name string) (TxType, error) {
+	if name == "UndefinedTxType" { // String() of the unused slots
+		return 0, errUndefinedTxType
+	}
+	for t := TxTypeLegacyTransaction; t < TxTypeKaiaLast; t++ {
+		if t.String() == name {
+			return t, nil
+		}
+	}
+	for t := TxTypeEthereumAccessList; t < TxTypeEthereumLast; t++ {
+		if t.String() == name {
+			return t, nil
+		}
+	}
+	return 0, errUndefinedTxType
+}
+
 func (t TxType) IsAccountCreation() bool {
 	return t == TxTypeAccountCreation
 }
@@ -406,6 +438,13 @@ type TxInternalDataBaseFee interface {
 	GetGasFeeCap() *big.Int
 }
 
+// TxInternalDataBlob has functions related to EIP-4844 blob transaction.
+type TxInternalDataBlob interface {
+	GetBlobFeeCap() *big.Int
+	GetBlobHashes() []common.Hash
+	GetSidecar() *BlobTxSidecar
+}
+
 // Since we cannot access the package `blockchain/vm` directly, an interface `VM` is introduced.
 // TODO-Kaia-Refactoring: Transaction and related data structures should be a new package.
 type VM interface {
@@ -481,6 +520,8 @@ func NewTxInternalData(t TxType) (TxInternalData, error) {
 		return newTxInternalDataEthereumAccessList(), nil
 	case TxTypeEthereumDynamicFee:
 		return newTxInternalDataEthereumDynamicFee(), nil
+	case TxTypeEthereumBlob:
+		return newTxInternalDataEthereumBlob(), nil
 	}
 
 	return nil, errUndefinedTxType
@@ -538,6 +579,8 @@ func NewTxInternalDataWithMap(t TxType, values map[TxValueKeyType]interface{}) (
 		return newTxInternalDataEthereumAccessListWithMap(values)
 	case TxTypeEthereumDynamicFee:
 		return newTxInternalDataEthereumDynamicFeeWithMap(values)
+	case TxTypeEthereumBlob:
+		return newTxInternalDataEthereumBlobWithMap(values)
 	}
 
 	return nil, errUndefinedTxType
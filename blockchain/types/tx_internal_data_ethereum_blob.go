@@ -0,0 +1,512 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/kaiachain/kaia/blockchain/types/accountkey"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/fork"
+	"github.com/kaiachain/kaia/kerrors"
+	"github.com/kaiachain/kaia/rlp"
+)
+
+// TxInternalDataEthereumBlob is the EIP-4844 blob transaction. A blob transaction
+// cannot create a contract, and it carries the blobs in a sidecar which is not a
+// part of the signed transaction.
+type TxInternalDataEthereumBlob struct {
+	ChainID      *big.Int
+	AccountNonce uint64
+	GasTipCap    *big.Int // a.k.a. maxPriorityFeePerGas
+	GasFeeCap    *big.Int // a.k.a. maxFeePerGas
+	GasLimit     uint64
+	Recipient    common.Address
+	Amount       *big.Int
+	Payload      []byte
+	AccessList   AccessList
+	BlobFeeCap   *big.Int // a.k.a. maxFeePerBlobGas
+	BlobHashes   []common.Hash
+
+	// Signature values
+	V *big.Int `json:"v" gencodec:"required"`
+	R *big.Int `json:"r" gencodec:"required"`
+	S *big.Int `json:"s" gencodec:"required"`
+
+	// This is only used when marshaling to JSON.
+	Hash *common.Hash `json:"hash" rlp:"-"`
+
+	// Sidecar is present while the tx travels through the txpools, and is stripped
+	// off when the tx is included in a block.
+	Sidecar *BlobTxSidecar `rlp:"optional"`
+}
+
+type TxInternalDataEthereumBlobJSON struct {
+	Type                 TxType           `json:"typeInt"`
+	TypeStr              string           `json:"type"`
+	ChainID              *hexutil.Big     `json:"chainId"`
+	AccountNonce         hexutil.Uint64   `json:"nonce"`
+	MaxPriorityFeePerGas *hexutil.Big     `json:"maxPriorityFeePerGas"`
+	MaxFeePerGas         *hexutil.Big     `json:"maxFeePerGas"`
+	GasLimit             hexutil.Uint64   `json:"gas"`
+	Recipient            common.Address   `json:"to"`
+	Amount               *hexutil.Big     `json:"value"`
+	Payload              hexutil.Bytes    `json:"input"`
+	AccessList           AccessList       `json:"accessList"`
+	MaxFeePerBlobGas     *hexutil.Big     `json:"maxFeePerBlobGas"`
+	BlobVersionedHashes  []common.Hash    `json:"blobVersionedHashes"`
+	TxSignatures         TxSignaturesJSON `json:"signatures"`
+	Hash                 *common.Hash     `json:"hash"`
+}
+
+func newTxInternalDataEthereumBlob() *TxInternalDataEthereumBlob {
+	return &TxInternalDataEthereumBlob{
+		ChainID:      new(big.Int),
+		AccountNonce: 0,
+		GasTipCap:    new(big.Int),
+		GasFeeCap:    new(big.Int),
+		GasLimit:     0,
+		Amount:       new(big.Int),
+		Payload:      []byte{},
+		AccessList:   AccessList{},
+		BlobFeeCap:   new(big.Int),
+		BlobHashes:   []common.Hash{},
+		V:            new(big.Int),
+		R:            new(big.Int),
+		S:            new(big.Int),
+	}
+}
+
+func newTxInternalDataEthereumBlobWithMap(values map[TxValueKeyType]interface{}) (*TxInternalDataEthereumBlob, error) {
+	d := newTxInternalDataEthereumBlob()
+
+	if v, ok := values[TxValueKeyChainID].(*big.Int); ok {
+		d.ChainID.Set(v)
+		delete(values, TxValueKeyChainID)
+	} else {
+		return nil, errValueKeyChainIDInvalid
+	}
+
+	if v, ok := values[TxValueKeyNonce].(uint64); ok {
+		d.AccountNonce = v
+		delete(values, TxValueKeyNonce)
+	} else {
+		return nil, errValueKeyNonceMustUint64
+	}
+
+	if v, ok := values[TxValueKeyTo].(common.Address); ok {
+		d.Recipient = v
+		delete(values, TxValueKeyTo)
+	} else {
+		return nil, errValueKeyToMustAddress
+	}
+
+	if v, ok := values[TxValueKeyAmount].(*big.Int); ok {
+		d.Amount.Set(v)
+		delete(values, TxValueKeyAmount)
+	} else {
+		return nil, errValueKeyAmountMustBigInt
+	}
+
+	if v, ok := values[TxValueKeyData].([]byte); ok {
+		d.Payload = common.CopyBytes(v)
+		delete(values, TxValueKeyData)
+	} else {
+		return nil, errValueKeyDataMustByteSlice
+	}
+
+	if v, ok := values[TxValueKeyGasLimit].(uint64); ok {
+		d.GasLimit = v
+		delete(values, TxValueKeyGasLimit)
+	} else {
+		return nil, errValueKeyGasLimitMustUint64
+	}
+
+	if v, ok := values[TxValueKeyGasFeeCap].(*big.Int); ok {
+		d.GasFeeCap.Set(v)
+		delete(values, TxValueKeyGasFeeCap)
+	} else {
+		return nil, errValueKeyGasFeeCapMustBigInt
+	}
+	if v, ok := values[TxValueKeyGasTipCap].(*big.Int); ok {
+		d.GasTipCap.Set(v)
+		delete(values, TxValueKeyGasTipCap)
+	} else {
+		return nil, errValueKeyGasTipCapMustBigInt
+	}
+	if v, ok := values[TxValueKeyAccessList].(AccessList); ok {
+		d.AccessList = make(AccessList, len(v))
+		copy(d.AccessList, v)
+		delete(values, TxValueKeyAccessList)
+	} else {
+		return nil, errValueKeyAccessListInvalid
+	}
+	if v, ok := values[TxValueKeyBlobFeeCap].(*big.Int); ok {
+		d.BlobFeeCap.Set(v)
+		delete(values, TxValueKeyBlobFeeCap)
+	} else {
+		return nil, errValueKeyBlobFeeCapMustBigInt
+	}
+	if v, ok := values[TxValueKeyBlobHashes].([]common.Hash); ok {
+		d.BlobHashes = make([]common.Hash, len(v))
+		copy(d.BlobHashes, v)
+		delete(values, TxValueKeyBlobHashes)
+	} else {
+		return nil, errValueKeyBlobHashesMustHashSlice
+	}
+	// The sidecar is optional.
+	if v, ok := values[TxValueKeyBlobSidecar].(*BlobTxSidecar); ok {
+		d.Sidecar = v
+		delete(values, TxValueKeyBlobSidecar)
+	}
+
+	if len(values) != 0 {
+		for k := range values {
+			logger.Warn("unnecessary key", k.String())
+		}
+		return nil, errUndefinedKeyRemains
+	}
+
+	return d, nil
+}
+
+func (t *TxInternalDataEthereumBlob) Type() TxType {
+	return TxTypeEthereumBlob
+}
+
+func (t *TxInternalDataEthereumBlob) GetRoleTypeForValidation() accountkey.RoleType {
+	return accountkey.RoleTransaction
+}
+
+func (t *TxInternalDataEthereumBlob) GetAccountNonce() uint64 {
+	return t.AccountNonce
+}
+
+func (t *TxInternalDataEthereumBlob) GetPrice() *big.Int {
+	return t.GasFeeCap
+}
+
+func (t *TxInternalDataEthereumBlob) GetGasLimit() uint64 {
+	return t.GasLimit
+}
+
+func (t *TxInternalDataEthereumBlob) GetRecipient() *common.Address {
+	return &t.Recipient
+}
+
+func (t *TxInternalDataEthereumBlob) GetAmount() *big.Int {
+	return new(big.Int).Set(t.Amount)
+}
+
+func (t *TxInternalDataEthereumBlob) GetHash() *common.Hash {
+	return t.Hash
+}
+
+func (t *TxInternalDataEthereumBlob) GetPayload() []byte {
+	return t.Payload
+}
+
+func (t *TxInternalDataEthereumBlob) GetAccessList() AccessList {
+	return t.AccessList
+}
+
+func (t *TxInternalDataEthereumBlob) GetGasTipCap() *big.Int {
+	return t.GasTipCap
+}
+
+func (t *TxInternalDataEthereumBlob) GetGasFeeCap() *big.Int {
+	return t.GasFeeCap
+}
+
+func (t *TxInternalDataEthereumBlob) GetBlobFeeCap() *big.Int {
+	return t.BlobFeeCap
+}
+
+func (t *TxInternalDataEthereumBlob) GetBlobHashes() []common.Hash {
+	return t.BlobHashes
+}
+
+func (t *TxInternalDataEthereumBlob) GetSidecar() *BlobTxSidecar {
+	return t.Sidecar
+}
+
+func (t *TxInternalDataEthereumBlob) SetHash(hash *common.Hash) {
+	t.Hash = hash
+}
+
+func (t *TxInternalDataEthereumBlob) SetSignature(signatures TxSignatures) {
+	if len(signatures) != 1 {
+		logger.Crit("TxTypeEthereumBlob can receive only single signature!")
+	}
+
+	t.V = signatures[0].V
+	t.R = signatures[0].R
+	t.S = signatures[0].S
+}
+
+func (t *TxInternalDataEthereumBlob) RawSignatureValues() TxSignatures {
+	return TxSignatures{&TxSignature{t.V, t.R, t.S}}
+}
+
+func (t *TxInternalDataEthereumBlob) ValidateSignature() bool {
+	v := byte(t.V.Uint64())
+	return crypto.ValidateSignatureValues(v, t.R, t.S, false)
+}
+
+func (t *TxInternalDataEthereumBlob) RecoverAddress(txhash common.Hash, homestead bool, vfunc func(*big.Int) *big.Int) (common.Address, error) {
+	V := vfunc(t.V)
+	return recoverPlain(txhash, t.R, t.S, V, homestead)
+}
+
+func (t *TxInternalDataEthereumBlob) RecoverPubkey(txhash common.Hash, homestead bool, vfunc func(*big.Int) *big.Int) ([]*ecdsa.PublicKey, error) {
+	V := vfunc(t.V)
+
+	pk, err := recoverPlainPubkey(txhash, t.R, t.S, V, homestead)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*ecdsa.PublicKey{pk}, nil
+}
+
+// IntrinsicGas does not include the blob gas, which is paid separately at the blob base fee.
+func (t *TxInternalDataEthereumBlob) IntrinsicGas(currentBlockNumber uint64) (uint64, error) {
+	return IntrinsicGas(t.Payload, t.AccessList, false, *fork.Rules(big.NewInt(int64(currentBlockNumber))))
+}
+
+func (t *TxInternalDataEthereumBlob) ChainId() *big.Int {
+	return t.ChainID
+}
+
+func (t *TxInternalDataEthereumBlob) Equal(a TxInternalData) bool {
+	ta, ok := a.(*TxInternalDataEthereumBlob)
+	if !ok {
+		return false
+	}
+
+	return t.ChainID.Cmp(ta.ChainID) == 0 &&
+		t.AccountNonce == ta.AccountNonce &&
+		t.GasFeeCap.Cmp(ta.GasFeeCap) == 0 &&
+		t.GasTipCap.Cmp(ta.GasTipCap) == 0 &&
+		t.GasLimit == ta.GasLimit &&
+		t.Recipient == ta.Recipient &&
+		t.Amount.Cmp(ta.Amount) == 0 &&
+		reflect.DeepEqual(t.AccessList, ta.AccessList) &&
+		t.BlobFeeCap.Cmp(ta.BlobFeeCap) == 0 &&
+		reflect.DeepEqual(t.BlobHashes, ta.BlobHashes) &&
+		t.V.Cmp(ta.V) == 0 &&
+		t.R.Cmp(ta.R) == 0 &&
+		t.S.Cmp(ta.S) == 0
+}
+
+func (t *TxInternalDataEthereumBlob) String() string {
+	var from string
+	tx := &Transaction{data: t}
+
+	v, r, s := t.V, t.R, t.S
+	if v != nil {
+		signer := LatestSignerForChainID(t.ChainId())
+		if f, err := Sender(signer, tx); err != nil { // derive but don't cache
+			from = "[invalid sender: invalid sig]"
+		} else {
+			from = fmt.Sprintf("%x", f[:])
+		}
+	} else {
+		from = "[invalid sender: nil V field]"
+	}
+
+	enc, _ := rlp.EncodeToBytes(tx)
+	return fmt.Sprintf(`
+		TX(%x)
+		Chaind:   %#x
+		From:     %s
+		To:       %x
+		Nonce:    %v
+		GasTipCap: %#x
+		GasFeeCap: %#x
+		GasLimit  %#x
+		Value:    %#x
+		Data:     0x%x
+		AccessList: %x
+		BlobFeeCap: %#x
+		BlobHashes: %x
+		V:        %#x
+		R:        %#x
+		S:        %#x
+		Hex:      %x
+	`,
+		tx.Hash(),
+		t.ChainId(),
+		from,
+		t.Recipient.Bytes(),
+		t.GetAccountNonce(),
+		t.GetGasTipCap(),
+		t.GetGasFeeCap(),
+		t.GetGasLimit(),
+		t.GetAmount(),
+		t.GetPayload(),
+		t.AccessList,
+		t.BlobFeeCap,
+		t.BlobHashes,
+		v,
+		r,
+		s,
+		enc,
+	)
+}
+
+func (t *TxInternalDataEthereumBlob) SerializeForSign() []interface{} {
+	// If the chainId has nil or empty value, It will be set signer's chainId.
+	return []interface{}{
+		t.ChainID,
+		t.AccountNonce,
+		t.GasTipCap,
+		t.GasFeeCap,
+		t.GasLimit,
+		t.Recipient,
+		t.Amount,
+		t.Payload,
+		t.AccessList,
+		t.BlobFeeCap,
+		t.BlobHashes,
+	}
+}
+
+func (t *TxInternalDataEthereumBlob) TxHash() common.Hash {
+	return prefixedRlpHash(byte(t.Type()), []interface{}{
+		t.ChainID,
+		t.AccountNonce,
+		t.GasTipCap,
+		t.GasFeeCap,
+		t.GasLimit,
+		t.Recipient,
+		t.Amount,
+		t.Payload,
+		t.AccessList,
+		t.BlobFeeCap,
+		t.BlobHashes,
+		t.V,
+		t.R,
+		t.S,
+	})
+}
+
+func (t *TxInternalDataEthereumBlob) SenderTxHash() common.Hash {
+	return t.TxHash()
+}
+
+func (t *TxInternalDataEthereumBlob) Validate(stateDB StateDB, currentBlockNumber uint64) error {
+	if common.IsPrecompiledContractAddress(t.Recipient) {
+		return kerrors.ErrPrecompiledContractAddress
+	}
+	if len(t.BlobHashes) == 0 {
+		return ErrMissingBlobHashes
+	}
+	for _, h := range t.BlobHashes {
+		if h[0] != BlobTxHashVersion {
+			return ErrInvalidBlobHashVersion
+		}
+	}
+	return t.ValidateMutableValue(stateDB, currentBlockNumber)
+}
+
+func (t *TxInternalDataEthereumBlob) ValidateMutableValue(stateDB StateDB, currentBlockNumber uint64) error {
+	return nil
+}
+
+func (t *TxInternalDataEthereumBlob) IsLegacyTransaction() bool {
+	return false
+}
+
+func (t *TxInternalDataEthereumBlob) Execute(sender ContractRef, vm VM, stateDB StateDB, currentBlockNumber uint64, gas uint64, value *big.Int) (ret []byte, usedGas uint64, err error) {
+	stateDB.IncNonce(sender.Address())
+	return vm.Call(sender, t.Recipient, t.Payload, gas, value)
+}
+
+func (t *TxInternalDataEthereumBlob) MakeRPCOutput() map[string]interface{} {
+	return map[string]interface{}{
+		"typeInt":              t.Type(),
+		"type":                 t.Type().String(),
+		"chainId":              (*hexutil.Big)(t.ChainId()),
+		"nonce":                hexutil.Uint64(t.AccountNonce),
+		"maxPriorityFeePerGas": (*hexutil.Big)(t.GasTipCap),
+		"maxFeePerGas":         (*hexutil.Big)(t.GasFeeCap),
+		"gas":                  hexutil.Uint64(t.GasLimit),
+		"to":                   t.Recipient,
+		"input":                hexutil.Bytes(t.Payload),
+		"value":                (*hexutil.Big)(t.Amount),
+		"accessList":           t.AccessList,
+		"maxFeePerBlobGas":     (*hexutil.Big)(t.BlobFeeCap),
+		"blobVersionedHashes":  t.BlobHashes,
+		"signatures":           TxSignaturesJSON{&TxSignatureJSON{(*hexutil.Big)(t.V), (*hexutil.Big)(t.R), (*hexutil.Big)(t.S)}},
+	}
+}
+
+func (t *TxInternalDataEthereumBlob) MarshalJSON() ([]byte, error) {
+	return json.Marshal(TxInternalDataEthereumBlobJSON{
+		t.Type(),
+		t.Type().String(),
+		(*hexutil.Big)(t.ChainID),
+		(hexutil.Uint64)(t.AccountNonce),
+		(*hexutil.Big)(t.GasTipCap),
+		(*hexutil.Big)(t.GasFeeCap),
+		(hexutil.Uint64)(t.GasLimit),
+		t.Recipient,
+		(*hexutil.Big)(t.Amount),
+		t.Payload,
+		t.AccessList,
+		(*hexutil.Big)(t.BlobFeeCap),
+		t.BlobHashes,
+		TxSignaturesJSON{&TxSignatureJSON{(*hexutil.Big)(t.V), (*hexutil.Big)(t.R), (*hexutil.Big)(t.S)}},
+		t.Hash,
+	})
+}
+
+func (t *TxInternalDataEthereumBlob) UnmarshalJSON(bytes []byte) error {
+	js := &TxInternalDataEthereumBlobJSON{}
+	if err := json.Unmarshal(bytes, js); err != nil {
+		return err
+	}
+
+	t.ChainID = (*big.Int)(js.ChainID)
+	t.AccountNonce = uint64(js.AccountNonce)
+	t.GasTipCap = (*big.Int)(js.MaxPriorityFeePerGas)
+	t.GasFeeCap = (*big.Int)(js.MaxFeePerGas)
+	t.GasLimit = uint64(js.GasLimit)
+	t.Recipient = js.Recipient
+	t.Amount = (*big.Int)(js.Amount)
+	t.Payload = js.Payload
+	t.AccessList = js.AccessList
+	t.BlobFeeCap = (*big.Int)(js.MaxFeePerBlobGas)
+	t.BlobHashes = js.BlobVersionedHashes
+	t.V = (*big.Int)(js.TxSignatures[0].V)
+	t.R = (*big.Int)(js.TxSignatures[0].R)
+	t.S = (*big.Int)(js.TxSignatures[0].S)
+	t.Hash = js.Hash
+
+	return nil
+}
+
+func (t *TxInternalDataEthereumBlob) setSignatureValues(chainID, v, r, s *big.Int) {
+	t.ChainID, t.V, t.R, t.S = chainID, v, r, s
+}
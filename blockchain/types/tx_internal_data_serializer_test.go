@@ -74,6 +74,7 @@ func TestTransactionSerialization(t *testing.T) {
 		{"FeeDelegatedCancelWithRatio", genFeeDelegatedCancelWithRatioTransaction()},
 		{"AccessList", genAccessListTransaction()},
 		{"DynamicFee", genDynamicFeeTransaction()},
+		{"Blob", genBlobTransaction()},
 	}
 
 	testcases := []struct {
@@ -287,6 +288,27 @@ func genDynamicFeeTransaction() TxInternalData {
 	return tx
 }
 
+func genBlobTransaction() TxInternalData {
+	tx, err := NewTxInternalDataWithMap(TxTypeEthereumBlob, map[TxValueKeyType]interface{}{
+		TxValueKeyNonce:      nonce,
+		TxValueKeyTo:         to,
+		TxValueKeyAmount:     amount,
+		TxValueKeyGasLimit:   gasLimit,
+		TxValueKeyGasFeeCap:  gasFeeCap,
+		TxValueKeyGasTipCap:  gasTipCap,
+		TxValueKeyData:       []byte("1234"),
+		TxValueKeyAccessList: accesses,
+		TxValueKeyBlobFeeCap: gasFeeCap,
+		TxValueKeyBlobHashes: []common.Hash{{BlobTxHashVersion}},
+		TxValueKeyChainID:    big.NewInt(2),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return tx
+}
+
 func genValueTransferTransaction() TxInternalData {
 	d, err := NewTxInternalDataWithMap(TxTypeValueTransfer, map[TxValueKeyType]interface{}{
 		TxValueKeyNonce:    nonce,
@@ -42,6 +42,7 @@ type CallFrame struct {
 	Error        string          `json:"error,omitempty"`
 	RevertReason string          `json:"revertReason,omitempty"` // decoded revert message in geth style.
 	Reverted     *RevertedInfo   `json:"reverted,omitempty"`     // decoded revert message and reverted contract address in klaytn style.
+	ContractName string          `json:"contractName,omitempty"` // verified name of the 'to' contract, if known.
 	Calls        []CallFrame     `json:"calls,omitempty"`        // child calls
 	Value        *big.Int        `json:"value,omitempty"`
 }
@@ -122,7 +123,7 @@ func (c *CallFrame) processOutput(output []byte, err error) {
 	c.Reverted = &RevertedInfo{Contract: c.To} // 'To' was recorded when entering this call frame
 
 	// 4: attach revert reason
-	if reason, unpackErr := abi.UnpackRevert(output); unpackErr == nil {
+	if reason, unpackErr := abi.UnpackRevertOf(c.To, output); unpackErr == nil {
 		c.RevertReason = reason
 		c.Reverted.Message = reason
 	}
@@ -131,7 +132,8 @@ func (c *CallFrame) processOutput(output []byte, err error) {
 // Implements vm.Tracer interface
 type CallTracer struct {
 	callstack       []CallFrame
-	gasLimit        uint64 // saved tx.gasLimit
+	gasLimit        uint64  // saved tx.gasLimit
+	statedb         StateDB // to resolve contract names
 	interrupt       atomic.Bool
 	interruptReason error
 }
@@ -154,6 +156,9 @@ func (t *CallTracer) CaptureTxEnd(gasLeft uint64) {
 
 // Enter top-level call frame
 func (t *CallTracer) CaptureStart(env *EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	if env != nil {
+		t.statedb = env.StateDB
+	}
 	toCopy := to
 	t.callstack[0] = CallFrame{
 		Type:  CALL,
@@ -172,6 +177,7 @@ func (t *CallTracer) CaptureStart(env *EVM, from common.Address, to common.Addre
 func (t *CallTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {
 	// gasUsed will be filled by CaptureTxEnd; just process the output
 	t.callstack[0].processOutput(output, err)
+	t.callstack[0].ContractName = resolveContractName(t.statedb, t.callstack[0].To)
 }
 
 // Enter nested call frame
@@ -203,6 +209,7 @@ func (t *CallTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
 	call := t.callstack[size-1]
 	call.GasUsed = gasUsed
 	call.processOutput(output, err)
+	call.ContractName = resolveContractName(t.statedb, call.To)
 
 	// pop current frame
 	t.callstack = t.callstack[:size-1]
@@ -0,0 +1,59 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"sync"
+
+	"github.com/kaiachain/kaia/common"
+)
+
+// ContractNameResolver resolves the verified names of contracts for the tracers.
+type ContractNameResolver interface {
+	// ContractName returns the verified name of the contract with the code hash.
+	// It must not block; unknown names may be looked up in the background.
+	ContractName(addr common.Address, codeHash common.Hash) (string, bool)
+}
+
+var (
+	contractNameResolverMu sync.RWMutex
+	contractNameResolver   ContractNameResolver
+)
+
+// SetContractNameResolver registers a node-wide ContractNameResolver. Passing nil removes it.
+func SetContractNameResolver(r ContractNameResolver) {
+	contractNameResolverMu.Lock()
+	defer contractNameResolverMu.Unlock()
+	contractNameResolver = r
+}
+
+// resolveContractName returns the verified name of the contract at addr, or "" if unknown.
+func resolveContractName(statedb StateDB, addr *common.Address) string {
+	contractNameResolverMu.RLock()
+	r := contractNameResolver
+	contractNameResolverMu.RUnlock()
+	if r == nil || statedb == nil || addr == nil {
+		return ""
+	}
+
+	codeHash := statedb.GetCodeHash(*addr)
+	if common.EmptyHash(codeHash) || codeHash == emptyCodeHash {
+		return ""
+	}
+	name, _ := r.ContractName(*addr, codeHash)
+	return name
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"testing"
+
+	"github.com/kaiachain/kaia/blockchain/state"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/stretchr/testify/assert"
+)
+
+type mapNameResolver map[common.Hash]string
+
+func (r mapNameResolver) ContractName(addr common.Address, codeHash common.Hash) (string, bool) {
+	name, ok := r[codeHash]
+	return name, ok
+}
+
+func TestResolveContractName(t *testing.T) {
+	var (
+		code     = []byte{0x60, 0x00}
+		contract = common.HexToAddress("0x1111")
+		eoa      = common.HexToAddress("0x2222")
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()), nil, nil)
+	statedb.CreateSmartContractAccount(contract, params.CodeFormatEVM, params.Rules{})
+	statedb.SetCode(contract, code)
+
+	// No resolver registered
+	assert.Equal(t, "", resolveContractName(statedb, &contract))
+
+	SetContractNameResolver(mapNameResolver{crypto.Keccak256Hash(code): "Token"})
+	defer SetContractNameResolver(nil)
+
+	assert.Equal(t, "Token", resolveContractName(statedb, &contract))
+	assert.Equal(t, "", resolveContractName(statedb, &eoa))
+	assert.Equal(t, "", resolveContractName(statedb, nil))
+	assert.Equal(t, "", resolveContractName(nil, &contract))
+}
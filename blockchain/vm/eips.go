@@ -310,20 +310,26 @@ func enable6780(jt *JumpTable) {
 }
 
 // opBlobHash implements the BLOBHASH opcode
-// Since blob data is generated in dank sharding, opBlobHash will perform only the default action of setting the top of the stack as zero
-// as long as the blob txType is not fully supported in Kaia.
+// It sets the top of the stack to the versioned hash at the given index of the blob tx,
+// or zero if the index is out of range or the tx is not a blob tx.
 func opBlobHash(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
 	index := scope.Stack.peek()
-	index.Clear()
-
+	if index.LtUint64(uint64(len(interpreter.evm.TxContext.BlobHashes))) {
+		blobHash := interpreter.evm.TxContext.BlobHashes[index.Uint64()]
+		index.SetBytes32(blobHash[:])
+	} else {
+		index.Clear()
+	}
 	return nil, nil
 }
 
 // opBlobBaseFee implements BLOBBASEFEE opcode
-// Since blob data is generated in dank sharding, opBlobBaseFee will use only the zeroBaseFee
-// as long as the blob txType is not fully supported in Kaia.
+// It uses the blob base fee set by governance, or the zeroBaseFee if the blob tx is not enabled.
 func opBlobBaseFee(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
 	blobBaseFee := uint256.NewInt(params.ZeroBaseFee)
+	if interpreter.evm.Context.BlobBaseFee != nil {
+		blobBaseFee.SetFromBig(interpreter.evm.Context.BlobBaseFee)
+	}
 	scope.Stack.push(blobBaseFee)
 	return nil, nil
 }
@@ -36,6 +36,7 @@ var (
 	ErrTotalTimeLimitReached             = errors.New("reached the total execution time limit for txs in a block")
 	ErrOpcodeComputationCostLimitReached = errors.New("reached the opcode computation cost limit")
 	ErrFailedOnSetCode                   = errors.New("failed on setting code to an account")
+	ErrContractPaused                    = errors.New("contract paused by governance")
 
 	// EVM internal errors
 	ErrWriteProtection       = errors.New("evm: write protection")
@@ -112,14 +112,19 @@ type BlockContext struct {
 	BlockScore  *big.Int       // Provides information for DIFFICULTY
 	BaseFee     *big.Int       // Provides information for BASEFEE
 	Random      common.Hash    // Provides information for RANDOM
+	BlobBaseFee *big.Int       // Provides information for BLOBBASEFEE (0 if nil)
+
+	// PausedContracts are the contracts whose code must not be run at any call depth
+	PausedContracts map[common.Address]bool
 }
 
 // TxContext provides the EVM with information about a transaction.
 // All fields can change between transactions.
 type TxContext struct {
 	// Message information
-	Origin   common.Address // Provides information for ORIGIN
-	GasPrice *big.Int       // Provides information for GASPRICE
+	Origin     common.Address // Provides information for ORIGIN
+	GasPrice   *big.Int       // Provides information for GASPRICE
+	BlobHashes []common.Hash  // Provides information for BLOBHASH
 }
 
 // EVM is the Ethereum Virtual Machine base object and provides
@@ -245,6 +250,10 @@ func (evm *EVM) Call(caller types.ContractRef, addr common.Address, input []byte
 	if evm.depth > int(params.CallCreateDepth) {
 		return nil, gas, ErrDepth // TODO-Klaytn-Issue615
 	}
+	// Fail if we're trying to run a contract paused by governance
+	if evm.Context.PausedContracts[addr] {
+		return nil, gas, ErrContractPaused
+	}
 	// Fail if we're trying to transfer more than the available balance
 	if !evm.Context.CanTransfer(evm.StateDB, caller.Address(), value) {
 		return nil, gas, ErrInsufficientBalance // TODO-Klaytn-Issue615
@@ -326,6 +335,10 @@ func (evm *EVM) CallCode(caller types.ContractRef, addr common.Address, input []
 	if evm.depth > int(params.CallCreateDepth) {
 		return nil, gas, ErrDepth // TODO-Klaytn-Issue615
 	}
+	// Fail if we're trying to run a contract paused by governance
+	if evm.Context.PausedContracts[addr] {
+		return nil, gas, ErrContractPaused
+	}
 	// Note although it's noop to transfer X ether to caller itself. But
 	// if caller doesn't have enough balance, it would be an error to allow
 	// over-charging itself. So the check here is necessary.
@@ -383,6 +396,10 @@ func (evm *EVM) DelegateCall(caller types.ContractRef, addr common.Address, inpu
 	if evm.depth > int(params.CallCreateDepth) {
 		return nil, gas, ErrDepth // TODO-Klaytn-Issue615
 	}
+	// Fail if we're trying to run a contract paused by governance
+	if evm.Context.PausedContracts[addr] {
+		return nil, gas, ErrContractPaused
+	}
 
 	if !isProgramAccount(evm, caller.Address(), addr, evm.StateDB) {
 		logger.Debug("Returning since the addr is not a program account", "addr", addr)
@@ -428,6 +445,10 @@ func (evm *EVM) StaticCall(caller types.ContractRef, addr common.Address, input
 	if evm.depth > int(params.CallCreateDepth) {
 		return nil, gas, ErrDepth // TODO-Klaytn-Issue615
 	}
+	// Fail if we're trying to run a contract paused by governance
+	if evm.Context.PausedContracts[addr] {
+		return nil, gas, ErrContractPaused
+	}
 	// Make sure the readonly is only set if we aren't in readonly yet
 	// this makes also sure that the readonly flag isn't removed for
 	// child calls.
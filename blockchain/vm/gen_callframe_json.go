@@ -25,6 +25,7 @@ func (c CallFrame) MarshalJSON() ([]byte, error) {
 		Error        string          `json:"error,omitempty"`
 		RevertReason string          `json:"revertReason,omitempty"`
 		Reverted     *RevertedInfo   `json:"reverted,omitempty"`
+		ContractName string          `json:"contractName,omitempty"`
 		Calls        []CallFrame     `json:"calls,omitempty"`
 		Value        *hexutil.Big    `json:"value,omitempty"`
 		TypeString   string          `json:"type"`
@@ -40,6 +41,7 @@ func (c CallFrame) MarshalJSON() ([]byte, error) {
 	enc.Error = c.Error
 	enc.RevertReason = c.RevertReason
 	enc.Reverted = c.Reverted
+	enc.ContractName = c.ContractName
 	enc.Calls = c.Calls
 	enc.Value = (*hexutil.Big)(c.Value)
 	enc.TypeString = c.TypeString()
@@ -59,6 +61,7 @@ func (c *CallFrame) UnmarshalJSON(input []byte) error {
 		Error        *string         `json:"error,omitempty"`
 		RevertReason *string         `json:"revertReason,omitempty"`
 		Reverted     *RevertedInfo   `json:"reverted,omitempty"`
+		ContractName *string         `json:"contractName,omitempty"`
 		Calls        []CallFrame     `json:"calls,omitempty"`
 		Value        *hexutil.Big    `json:"value,omitempty"`
 	}
@@ -96,6 +99,9 @@ func (c *CallFrame) UnmarshalJSON(input []byte) error {
 	if dec.Reverted != nil {
 		c.Reverted = dec.Reverted
 	}
+	if dec.ContractName != nil {
+		c.ContractName = *dec.ContractName
+	}
 	if dec.Calls != nil {
 		c.Calls = dec.Calls
 	}
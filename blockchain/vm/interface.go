@@ -50,6 +50,8 @@ type StateDB interface {
 	GetCode(common.Address) []byte
 	SetCode(common.Address, []byte) error
 	GetCodeSize(common.Address) int
+	// GetCodeBatch returns the codes of the given accounts, reading them at once.
+	GetCodeBatch([]common.Address) [][]byte
 	GetVmVersion(common.Address) (params.VmVersion, bool)
 
 	AddRefund(uint64)
@@ -59,6 +61,8 @@ type StateDB interface {
 	GetCommittedState(common.Address, common.Hash) common.Hash
 	GetState(common.Address, common.Hash) common.Hash
 	SetState(common.Address, common.Hash, common.Hash)
+	// GetStorageBatch returns the values of the given storage keys of an account, reading them at once.
+	GetStorageBatch(common.Address, []common.Hash) []common.Hash
 
 	GetTransientState(addr common.Address, key common.Hash) common.Hash
 	SetTransientState(addr common.Address, key, value common.Hash)
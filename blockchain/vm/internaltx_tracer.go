@@ -525,13 +525,13 @@ func (t *InternalTxTracer) result() *InternalTxTrace {
 	if err := t.ctx["error"]; err != nil && err.(error).Error() == ErrExecutionReverted.Error() {
 		outputHex := t.ctx["output"].(string) // it is already a hex string
 
-		if s, err := abi.UnpackRevert(common.FromHex(outputHex)); err == nil {
+		contract := t.revertedContract
+		if s, err := abi.UnpackRevertOf(&contract, common.FromHex(outputHex)); err == nil {
 			t.revertString = s
 		} else {
 			t.revertString = ""
 		}
 
-		contract := t.revertedContract
 		message := t.revertString
 		result.Reverted = &RevertedInfo{Contract: &contract, Message: message}
 	}
@@ -44,6 +44,8 @@ func NewEnv(cfg *Config) *vm.EVM {
 		BlockScore:  cfg.BlockScore,
 		GasLimit:    cfg.GasLimit,
 		BaseFee:     cfg.BaseFee,
+
+		PausedContracts: cfg.PausedContracts,
 	}
 	return vm.NewEVM(blockContext, txContext, cfg.State, cfg.ChainConfig, &cfg.EVMConfig)
 }
@@ -53,6 +53,8 @@ type Config struct {
 	EVMConfig   vm.Config
 	BaseFee     *big.Int
 
+	PausedContracts map[common.Address]bool
+
 	State     *state.StateDB
 	GetHashFn func(n uint64) common.Hash
 }
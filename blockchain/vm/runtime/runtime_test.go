@@ -126,6 +126,67 @@ func TestCall(t *testing.T) {
 	}
 }
 
+func TestCallPausedContract(t *testing.T) {
+	state, _ := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()), nil, nil)
+	var (
+		paused = common.HexToAddress("0x0b00")
+		caller = common.HexToAddress("0x0c00")
+		dcall  = common.HexToAddress("0x0d00")
+	)
+	// The paused contract returns 1.
+	state.SetCode(paused, []byte{
+		byte(vm.PUSH1), 1,
+		byte(vm.PUSH1), 0,
+		byte(vm.MSTORE),
+		byte(vm.PUSH1), 32,
+		byte(vm.PUSH1), 0,
+		byte(vm.RETURN),
+	})
+	// The proxies return whether calling the paused contract succeeded.
+	proxy := func(op vm.OpCode) []byte {
+		code := []byte{byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0}
+		if op == vm.CALL {
+			code = append(code, byte(vm.PUSH1), 0)
+		}
+		return append(code,
+			byte(vm.PUSH2), 0x0b, 0x00,
+			byte(vm.GAS),
+			byte(op),
+			byte(vm.PUSH1), 0,
+			byte(vm.MSTORE),
+			byte(vm.PUSH1), 32,
+			byte(vm.PUSH1), 0,
+			byte(vm.RETURN),
+		)
+	}
+	state.SetCode(caller, proxy(vm.CALL))
+	state.SetCode(dcall, proxy(vm.DELEGATECALL))
+
+	for _, addr := range []common.Address{caller, dcall} {
+		ret, _, err := Call(addr, nil, &Config{State: state})
+		if err != nil {
+			t.Fatal("didn't expect error", err)
+		}
+		if new(big.Int).SetBytes(ret).Cmp(big.NewInt(1)) != 0 {
+			t.Error("Expected the call to succeed without the pause", addr)
+		}
+	}
+
+	pausedContracts := map[common.Address]bool{paused: true}
+	if _, _, err := Call(paused, nil, &Config{State: state, PausedContracts: pausedContracts}); err != vm.ErrContractPaused {
+		t.Error("Expected", vm.ErrContractPaused, "got", err)
+	}
+	for _, addr := range []common.Address{caller, dcall} {
+		ret, _, err := Call(addr, nil, &Config{State: state, PausedContracts: pausedContracts})
+		if err != nil {
+			t.Fatal("didn't expect error", err)
+		}
+		if new(big.Int).SetBytes(ret).Sign() != 0 {
+			t.Error("Expected the call to the paused contract to fail", addr)
+		}
+	}
+}
+
 func BenchmarkCall(b *testing.B) {
 	definition := `[{"constant":true,"inputs":[],"name":"seller","outputs":[{"name":"","type":"address"}],"type":"function"},{"constant":false,"inputs":[],"name":"abort","outputs":[],"type":"function"},{"constant":true,"inputs":[],"name":"value","outputs":[{"name":"","type":"uint256"}],"type":"function"},{"constant":false,"inputs":[],"name":"refund","outputs":[],"type":"function"},{"constant":true,"inputs":[],"name":"buyer","outputs":[{"name":"","type":"address"}],"type":"function"},{"constant":false,"inputs":[],"name":"confirmReceived","outputs":[],"type":"function"},{"constant":true,"inputs":[],"name":"state","outputs":[{"name":"","type":"uint8"}],"type":"function"},{"constant":false,"inputs":[],"name":"confirmPurchase","outputs":[],"type":"function"},{"inputs":[],"type":"constructor"},{"anonymous":false,"inputs":[],"name":"Aborted","type":"event"},{"anonymous":false,"inputs":[],"name":"PurchaseConfirmed","type":"event"},{"anonymous":false,"inputs":[],"name":"ItemReceived","type":"event"},{"anonymous":false,"inputs":[],"name":"Refunded","type":"event"}]`
 
@@ -73,6 +73,22 @@ func init() {
 
 		// See utils/nodecmd/snapshot.go:
 		nodecmd.SnapshotCommand,
+
+		// See utils/nodecmd/verklecmd.go:
+		nodecmd.VerkleCommand,
+
+		// See utils/nodecmd/validatorcmd.go:
+		nodecmd.ValidatorCommand,
+
+		// See utils/nodecmd/govcmd.go:
+		nodecmd.GovCommand,
+		nodecmd.DBCommand,
+
+		// See utils/nodecmd/testnetcmd.go:
+		nodecmd.TestnetCommand,
+
+		// See utils/nodecmd/doctorcmd.go:
+		nodecmd.DoctorCommand,
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 
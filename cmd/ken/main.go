@@ -73,6 +73,9 @@ func init() {
 
 		// See utils/nodecmd/snapshot.go:
 		nodecmd.SnapshotCommand,
+
+		// See utils/nodecmd/verklecmd.go:
+		nodecmd.VerkleCommand,
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 
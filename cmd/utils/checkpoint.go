@@ -0,0 +1,306 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kaiachain/kaia/blockchain"
+	"github.com/kaiachain/kaia/blockchain/state"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/reward"
+	"github.com/kaiachain/kaia/rlp"
+	"github.com/kaiachain/kaia/storage/database"
+)
+
+// checkpointArchiveVersion is the version of the checkpoint archive format.
+const checkpointArchiveVersion = 1
+
+var (
+	errCheckpointNotEmpty  = errors.New("checkpoint archive can only be imported into a chain without blocks")
+	errCheckpointVersion   = errors.New("unsupported checkpoint archive version")
+	errCheckpointLivePrune = errors.New("checkpoint archive is not supported with live pruning")
+)
+
+// checkpointHeader is the first item of a checkpoint archive. It is followed by
+// Blocks blocks starting from the genesis, each with its receipts, and then by the
+// state entries at the checkpoint block until the end of the stream.
+type checkpointHeader struct {
+	Version     uint64
+	Blocks      uint64
+	Number      uint64
+	Hash        common.Hash
+	Root        common.Hash
+	StakingInfo []byte // JSON encoded staking info used by the block next to the checkpoint, if any
+}
+
+type checkpointBlock struct {
+	Block    *types.Block
+	Receipts []*types.ReceiptForStorage
+}
+
+type checkpointStateEntry struct {
+	Code bool
+	Hash common.Hash
+	Blob []byte
+}
+
+// ExportCheckpoint exports the whole chain into the specified file together with
+// the full state at the checkpoint block, so that a new node can be bootstrapped
+// from the archive without executing the blocks up to the checkpoint.
+func ExportCheckpoint(chain *blockchain.BlockChain, fn string, checkpoint uint64) error {
+	head := chain.CurrentBlock().NumberU64()
+	if checkpoint > head {
+		return fmt.Errorf("checkpoint %d is beyond the chain head %d", checkpoint, head)
+	}
+	block := chain.GetBlockByNumber(checkpoint)
+	if block == nil {
+		return fmt.Errorf("checkpoint block %d not found", checkpoint)
+	}
+	if chain.StateCache().TrieDB().DiskDB().ReadPruningEnabled() {
+		return errCheckpointLivePrune
+	}
+	stateDB, err := chain.StateAt(block.Root())
+	if err != nil {
+		return fmt.Errorf("state of checkpoint block %d not available: %v", checkpoint, err)
+	}
+	logger.Info("Exporting blockchain with checkpoint state", "file", fn, "checkpoint", checkpoint, "head", head)
+
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var writer io.Writer = fh
+	if strings.HasSuffix(fn, ".gz") {
+		writer = gzip.NewWriter(writer)
+		defer writer.(*gzip.Writer).Close()
+	}
+
+	header := checkpointHeader{
+		Version: checkpointArchiveVersion,
+		Blocks:  head + 1,
+		Number:  checkpoint,
+		Hash:    block.Hash(),
+		Root:    block.Root(),
+	}
+	if reward.GetStakingManager() != nil {
+		if stakingInfo := reward.GetStakingInfo(checkpoint + 1); stakingInfo != nil {
+			if header.StakingInfo, err = json.Marshal(stakingInfo); err != nil {
+				return err
+			}
+		}
+	}
+	if err := rlp.Encode(writer, &header); err != nil {
+		return err
+	}
+
+	for nr := uint64(0); nr <= head; nr++ {
+		block := chain.GetBlockByNumber(nr)
+		if block == nil {
+			return fmt.Errorf("export failed on #%d: not found", nr)
+		}
+		receipts := chain.GetReceiptsByBlockHash(block.Hash())
+		storageReceipts := make([]*types.ReceiptForStorage, len(receipts))
+		for i, receipt := range receipts {
+			storageReceipts[i] = (*types.ReceiptForStorage)(receipt)
+		}
+		if err := rlp.Encode(writer, &checkpointBlock{Block: block, Receipts: storageReceipts}); err != nil {
+			return err
+		}
+	}
+
+	var (
+		trieDB = chain.StateCache().TrieDB()
+		it     = state.NewNodeIterator(stateDB)
+		nodes  = 0
+	)
+	for it.Next() {
+		if it.Hash == (common.Hash{}) {
+			continue // embedded in its parent node
+		}
+		entry := checkpointStateEntry{Hash: it.Hash}
+		if it.Type == "code" {
+			entry.Code, entry.Blob = true, it.Code
+		} else if entry.Blob, err = trieDB.Node(it.Hash.ExtendZero()); err != nil {
+			return err
+		}
+		if err := rlp.Encode(writer, &entry); err != nil {
+			return err
+		}
+		nodes++
+	}
+	if it.Error != nil {
+		return it.Error
+	}
+	logger.Info("Exported blockchain with checkpoint state", "file", fn, "blocks", header.Blocks, "stateEntries", nodes)
+	return nil
+}
+
+// ImportCheckpoint imports a checkpoint archive made by ExportCheckpoint into a
+// chain holding only its genesis block. The blocks up to the checkpoint are
+// inserted with their receipts and the checkpoint state is written as is, the
+// same way as a fast sync pivot. The blocks after the checkpoint are executed.
+func ImportCheckpoint(chain *blockchain.BlockChain, fn string) error {
+	if chain.CurrentBlock().NumberU64() != 0 {
+		return errCheckpointNotEmpty
+	}
+	dbm := chain.StateCache().TrieDB().DiskDB()
+	if dbm.ReadPruningEnabled() {
+		return errCheckpointLivePrune
+	}
+	logger.Info("Importing blockchain with checkpoint state", "file", fn)
+
+	fh, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var reader io.Reader = fh
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return err
+		}
+	}
+	stream := rlp.NewStream(reader, 0)
+
+	var header checkpointHeader
+	if err := stream.Decode(&header); err != nil {
+		return err
+	}
+	if header.Version != checkpointArchiveVersion {
+		return fmt.Errorf("%w: %d", errCheckpointVersion, header.Version)
+	}
+
+	// Insert the blocks up to the checkpoint without executing them.
+	var (
+		blocks   = make(types.Blocks, 0, importBatchSize)
+		receipts = make([]types.Receipts, 0, importBatchSize)
+		after    = make(types.Blocks, 0, header.Blocks-header.Number-1)
+	)
+	insert := func() error {
+		if len(blocks) == 0 {
+			return nil
+		}
+		headers := make([]*types.Header, len(blocks))
+		for i, block := range blocks {
+			headers[i] = block.Header()
+		}
+		if _, err := chain.InsertHeaderChain(headers, 1); err != nil {
+			return err
+		}
+		if _, err := chain.InsertReceiptChain(blocks, receipts); err != nil {
+			return err
+		}
+		blocks, receipts = blocks[:0], receipts[:0]
+		return nil
+	}
+	for nr := uint64(0); nr < header.Blocks; nr++ {
+		var entry checkpointBlock
+		if err := stream.Decode(&entry); err != nil {
+			return fmt.Errorf("at block %d: %v", nr, err)
+		}
+		block := entry.Block
+		switch {
+		case block.NumberU64() != nr:
+			return fmt.Errorf("at block %d: unexpected block number %d", nr, block.NumberU64())
+		case nr == 0:
+			if block.Hash() != chain.Genesis().Hash() {
+				return fmt.Errorf("genesis mismatch: archive %x, chain %x", block.Hash(), chain.Genesis().Hash())
+			}
+		case nr > header.Number:
+			after = append(after, block)
+		default:
+			blockReceipts := make(types.Receipts, len(entry.Receipts))
+			for i, receipt := range entry.Receipts {
+				blockReceipts[i] = (*types.Receipt)(receipt)
+			}
+			blocks, receipts = append(blocks, block), append(receipts, blockReceipts)
+			if len(blocks) == importBatchSize {
+				if err := insert(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if err := insert(); err != nil {
+		return err
+	}
+	if chain.GetBlockByHash(header.Hash) == nil {
+		return fmt.Errorf("checkpoint block %d [%x…] missing in the archive", header.Number, header.Hash.Bytes()[:4])
+	}
+
+	// Write the checkpoint state and make the checkpoint the new head.
+	var (
+		batch = dbm.NewBatch(database.StateTrieDB)
+		nodes = 0
+	)
+	defer batch.Release()
+	for {
+		var entry checkpointStateEntry
+		if err := stream.Decode(&entry); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("at state entry %d: %v", nodes, err)
+		}
+		if entry.Code {
+			dbm.PutCodeToBatch(batch, entry.Hash, entry.Blob)
+		} else {
+			dbm.PutTrieNodeToBatch(batch, entry.Hash.ExtendZero(), entry.Blob)
+		}
+		nodes++
+		if _, err := database.WriteBatchesOverThreshold(batch); err != nil {
+			return err
+		}
+	}
+	if _, err := database.WriteBatches(batch); err != nil {
+		return err
+	}
+	if len(header.StakingInfo) > 0 {
+		stakingInfo := new(reward.StakingInfo)
+		if err := json.Unmarshal(header.StakingInfo, stakingInfo); err != nil {
+			return err
+		}
+		if err := reward.AddStakingInfoToDB(stakingInfo); err != nil {
+			logger.Warn("Failed to store the staking info of the checkpoint", "err", err)
+		}
+	}
+	if err := chain.FastSyncCommitHead(header.Hash); err != nil {
+		return err
+	}
+	dbm.WriteHeadBlockHash(header.Hash)
+	logger.Info("Imported checkpoint state", "number", header.Number, "hash", header.Hash, "stateEntries", nodes)
+
+	// Execute the remaining blocks on top of the checkpoint.
+	for len(after) > 0 {
+		n := min(len(after), importBatchSize)
+		if _, err := chain.InsertChain(after[:n]); err != nil {
+			return fmt.Errorf("invalid block %d: %v", after[0].NumberU64(), err)
+		}
+		after = after[n:]
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/kaiachain/kaia/blockchain"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/blockchain/vm"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/consensus/gxhash"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpointExportImport(t *testing.T) {
+	var (
+		key, _   = crypto.GenerateKey()
+		addr     = crypto.PubkeyToAddress(key.PublicKey)
+		to       = common.HexToAddress("0xbbbb")
+		contract = common.HexToAddress("0xcccc")
+		slot     = common.HexToHash("0x01")
+		config   = params.TestChainConfig.Copy()
+		gspec    = &blockchain.Genesis{Config: config, Alloc: blockchain.GenesisAlloc{
+			addr:     {Balance: big.NewInt(params.KAIA)},
+			contract: {Balance: common.Big0, Code: []byte{0x00}, Storage: map[common.Hash]common.Hash{slot: common.HexToHash("0x11")}},
+		}}
+		signer = types.LatestSignerForChainID(config.ChainID)
+	)
+	newChain := func() *blockchain.BlockChain {
+		db := database.NewMemoryDBManager()
+		gspec.MustCommit(db)
+		chain, err := blockchain.NewBlockChain(db, nil, config, gxhash.NewFaker(), vm.Config{})
+		require.NoError(t, err)
+		t.Cleanup(chain.Stop)
+		return chain
+	}
+
+	src := newChain()
+	genDB := database.NewMemoryDBManager()
+	gspec.MustCommit(genDB)
+	blocks, _ := blockchain.GenerateChain(config, src.Genesis(), gxhash.NewFaker(), genDB, 10, func(i int, b *blockchain.BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(b.TxNonce(addr), to, big.NewInt(100), params.TxGas, big.NewInt(0), nil), signer, key)
+		require.NoError(t, err)
+		b.AddTx(tx)
+	})
+	_, err := src.InsertChain(blocks)
+	require.NoError(t, err)
+
+	fn := filepath.Join(t.TempDir(), "checkpoint.rlp.gz")
+	assert.Error(t, ExportCheckpoint(src, fn, 11))
+	require.NoError(t, ExportCheckpoint(src, fn, 6))
+
+	dst := newChain()
+	require.NoError(t, ImportCheckpoint(dst, fn))
+	assert.ErrorIs(t, ImportCheckpoint(dst, fn), errCheckpointNotEmpty)
+
+	// The blocks up to the checkpoint are not executed, so only their receipts
+	// are available, while the state is available from the checkpoint on.
+	assert.Equal(t, src.CurrentBlock().Hash(), dst.CurrentBlock().Hash())
+	assert.Len(t, dst.GetReceiptsByBlockHash(blocks[2].Hash()), 1)
+	assert.False(t, dst.HasState(blocks[2].Root()))
+	for _, block := range blocks[5:] {
+		assert.True(t, dst.HasState(block.Root()))
+	}
+	state, err := dst.State()
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1000), state.GetBalance(to))
+	assert.Equal(t, common.HexToHash("0x11"), state.GetState(contract, slot))
+}
@@ -29,6 +29,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"net/url"
 	"os"
 	"reflect"
 	"strconv"
@@ -43,6 +44,7 @@ import (
 	"github.com/kaiachain/kaia/blockchain"
 	"github.com/kaiachain/kaia/common"
 	"github.com/kaiachain/kaia/common/fdlimit"
+	"github.com/kaiachain/kaia/consensus/istanbul"
 	"github.com/kaiachain/kaia/crypto"
 	"github.com/kaiachain/kaia/crypto/bls"
 	"github.com/kaiachain/kaia/datasync/chaindatafetcher"
@@ -210,6 +212,21 @@ func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 	cfg.NetworkID, _ = getNetworkId(ctx)
 }
 
+// setFollowerP2P disables the p2p server in follower mode, where the blocks come only from
+// the upstream nodes. The follower mode is available only for ENs.
+func setFollowerP2P(ctx *cli.Context, cfg *node.Config, cnCfg *cn.Config) {
+	if !ctx.IsSet(UpstreamEndpointsFlag.Name) && len(cnCfg.UpstreamEndpoints) == 0 {
+		return
+	}
+	if cfg.P2P.ConnectionType != common.ENDPOINTNODE {
+		log.Fatalf("Option %q is available only for ENs", UpstreamEndpointsFlag.Name)
+	}
+	cfg.P2P.NoDiscovery = true
+	cfg.P2P.NoDial = true
+	cfg.P2P.NoListen = true
+	logger.Info("Follower mode is enabled, disabling p2p server")
+}
+
 // setNodeKey parses manually provided node key from command line flags,
 // either loading it from a file or as a specified hex value. If neither flags
 // were provided, this method sets cfg.PrivateKey = nil and node.Config.NodeKey()
@@ -359,6 +376,7 @@ func (kCfg *KaiaConfig) SetNodeConfig(ctx *cli.Context) {
 	cfg.DisableUnsafeDebug = ctx.Bool(UnsafeDebugDisableFlag.Name)
 
 	SetP2PConfig(ctx, &cfg.P2P)
+	setFollowerP2P(ctx, cfg, &kCfg.CN)
 	setBlsNodeKey(ctx, cfg)
 	setIPC(ctx, cfg)
 
@@ -375,6 +393,7 @@ func (kCfg *KaiaConfig) SetNodeConfig(ctx *cli.Context) {
 
 	setHTTP(ctx, cfg)
 	setWS(ctx, cfg)
+	setRPCListeners(ctx, cfg)
 	setgRPC(ctx, cfg)
 	setAPIConfig(ctx)
 	setNodeUserIdent(ctx, cfg)
@@ -386,6 +405,7 @@ func (kCfg *KaiaConfig) SetNodeConfig(ctx *cli.Context) {
 	}
 	cfg.DataDir = ctx.String(DataDirFlag.Name)
 	cfg.ChainDataDir = ctx.String(ChainDataDirFlag.Name)
+	cfg.MemoryDB = ctx.Bool(MemoryDBFlag.Name)
 
 	if ctx.IsSet(KeyStoreDirFlag.Name) {
 		cfg.KeyStoreDir = ctx.String(KeyStoreDirFlag.Name)
@@ -440,6 +460,16 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 		rpc.UpstreamArchiveEN = ctx.String(RPCUpstreamArchiveENFlag.Name)
 		cfg.UpstreamArchiveEN = rpc.UpstreamArchiveEN
 	}
+	rpc.UpstreamCacheSize = ctx.Int(RPCUpstreamCacheSizeFlag.Name)
+	rpc.HeavyCallSlots = ctx.Int(HeavyCallSlotsFlag.Name)
+	rpc.HeavyCallExecTimeLimit = ctx.Duration(HeavyCallExecTimeLimitFlag.Name)
+	rpc.SlowQueryThreshold = ctx.Duration(RPCSlowQueryThresholdFlag.Name)
+	rpc.SlowQueryLogSize = ctx.Int(RPCSlowQueryLogSizeFlag.Name)
+	if ctx.IsSet(RPCJSONCodecFlag.Name) {
+		if err := rpc.SetJSONCodec(ctx.String(RPCJSONCodecFlag.Name)); err != nil {
+			log.Fatalf("%v", err)
+		}
+	}
 }
 
 // setWS creates the WebSocket RPC listener interface string from the set
@@ -467,6 +497,67 @@ func setWS(ctx *cli.Context, cfg *node.Config) {
 	rpc.MaxWebsocketConnections = int32(ctx.Int(WSMaxConnections.Name))
 }
 
+// setRPCListeners appends the additional RPC listeners given by the command line flag
+// to those of the config file.
+func setRPCListeners(ctx *cli.Context, cfg *node.Config) {
+	if !ctx.IsSet(RPCListenersFlag.Name) {
+		return
+	}
+	listeners, err := parseRPCListeners(ctx.String(RPCListenersFlag.Name))
+	if err != nil {
+		log.Fatalf("Option %q: %v", RPCListenersFlag.Name, err)
+	}
+	cfg.RPCListeners = append(cfg.RPCListeners, listeners...)
+}
+
+// parseRPCListeners parses the semicolon separated RPC listener URLs of the form
+// <http|ws>://<host>:<port>?api=<modules>&cors=<domains>&vhosts=<hosts>&auth=<tokens>,
+// where the query values are comma separated lists.
+func parseRPCListeners(spec string) ([]node.RPCListenerConfig, error) {
+	var listeners []node.RPCListenerConfig
+	for _, s := range strings.Split(spec, ";") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		u, err := url.Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		if u.Scheme != "http" && u.Scheme != "ws" {
+			return nil, fmt.Errorf("unsupported RPC listener protocol %q", u.Scheme)
+		}
+		port, err := strconv.Atoi(u.Port())
+		if err != nil {
+			return nil, fmt.Errorf("invalid RPC listener port %q", u.Port())
+		}
+		query, err := url.ParseQuery(u.RawQuery)
+		if err != nil {
+			return nil, err
+		}
+		listener := node.RPCListenerConfig{Protocol: u.Scheme, Host: u.Hostname(), Port: port}
+		for key, values := range query {
+			var list []string
+			for _, v := range values {
+				list = append(list, SplitAndTrim(v)...)
+			}
+			switch key {
+			case "api":
+				listener.Modules = list
+			case "cors":
+				listener.Cors = list
+			case "vhosts":
+				listener.VirtualHosts = list
+			case "auth":
+				listener.AuthTokens = list
+			default:
+				return nil, fmt.Errorf("unknown RPC listener option %q", key)
+			}
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners, nil
+}
+
 // setIPC creates an IPC path configuration from the set command line flags,
 // returning an empty string if IPC was explicitly disabled, or the set path.
 func setIPC(ctx *cli.Context, cfg *node.Config) {
@@ -554,6 +645,8 @@ func (kCfg *KaiaConfig) SetKaiaConfig(ctx *cli.Context, stack *node.Node) {
 	if !database.IsPow2(cfg.NumStateTrieShards) {
 		log.Fatalf("%v should be power of 2 but %v is not!", NumStateTrieShardsFlag.Name, cfg.NumStateTrieShards)
 	}
+	cfg.StateTrieShardDirs = ctx.StringSlice(StateTrieShardDirsFlag.Name)
+	cfg.CompressedTables = ctx.StringSlice(CompressedTablesFlag.Name)
 
 	cfg.OverwriteGenesis = ctx.Bool(OverwriteGenesisFlag.Name)
 	cfg.StartBlockNumber = ctx.Uint64(StartBlockNumberFlag.Name)
@@ -565,6 +658,11 @@ func (kCfg *KaiaConfig) SetKaiaConfig(ctx *cli.Context, stack *node.Node) {
 
 	cfg.PebbleDBCacheSize = ctx.Int(PebbleDBCacheSizeFlag.Name)
 
+	cfg.AncientThreshold = ctx.Uint64(AncientThresholdFlag.Name)
+	cfg.AncientDir = ctx.Path(AncientDirFlag.Name)
+	cfg.CompactionWindow = ctx.String(CompactionWindowFlag.Name)
+	cfg.CompactionLatencyLimit = ctx.Duration(CompactionLatencyLimitFlag.Name)
+
 	cfg.RocksDBConfig.Secondary = ctx.Bool(RocksDBSecondaryFlag.Name)
 	cfg.RocksDBConfig.MaxOpenFiles = ctx.Int(RocksDBMaxOpenFilesFlag.Name)
 	if cfg.RocksDBConfig.Secondary {
@@ -595,6 +693,11 @@ func (kCfg *KaiaConfig) SetKaiaConfig(ctx *cli.Context, stack *node.Node) {
 	cfg.NoPruning = ctx.String(GCModeFlag.Name) == "archive"
 	logger.Info("Archiving mode of this node", "isArchiveMode", cfg.NoPruning)
 
+	cfg.ReceiptRetention = ctx.Uint64(ReceiptRetentionFlag.Name)
+	if cfg.NoPruning && cfg.ReceiptRetention != 0 {
+		log.Fatalf("--%s is not supported in archive mode", ReceiptRetentionFlag.Name)
+	}
+
 	cfg.AnchoringPeriod = ctx.Uint64(AnchoringPeriodFlag.Name)
 	cfg.SentChainTxsLimit = ctx.Uint64(SentChainTxsLimit.Name)
 
@@ -604,6 +707,21 @@ func (kCfg *KaiaConfig) SetKaiaConfig(ctx *cli.Context, stack *node.Node) {
 	cfg.TriesInMemory = ctx.Uint64(TriesInMemoryFlag.Name)
 	cfg.LivePruning = ctx.Bool(LivePruningFlag.Name)
 	cfg.LivePruningRetention = ctx.Uint64(LivePruningRetentionFlag.Name)
+	cfg.OnlinePruning = ctx.Bool(OnlinePruningFlag.Name)
+	cfg.OnlinePruningRetention = ctx.Uint64(OnlinePruningRetentionFlag.Name)
+	cfg.OnlinePruningInterval = ctx.Duration(OnlinePruningIntervalFlag.Name)
+	cfg.OnlinePruningRate = ctx.Int(OnlinePruningRateFlag.Name)
+	cfg.OnlinePruningBloomSize = ctx.Uint64(OnlinePruningBloomSizeFlag.Name)
+	cfg.StateDiff = ctx.Bool(StateDiffFlag.Name)
+	cfg.StateDiffPersist = ctx.Bool(StateDiffPersistFlag.Name)
+	cfg.TrieJournal = ctx.Bool(StateTrieJournalFlag.Name)
+	cfg.InvariantCheck = ctx.Bool(InvariantCheckFlag.Name)
+	cfg.InvariantAction = ctx.String(InvariantActionFlag.Name)
+	cfg.VerkleShadow = ctx.Bool(VerkleShadowFlag.Name)
+	cfg.TrieScheme = ctx.String(TrieSchemeFlag.Name)
+	cfg.TrieMirrorScheme = ctx.String(TrieMirrorSchemeFlag.Name)
+	cfg.TrieIntegrityCheckRate = ctx.Uint64(TrieIntegrityCheckRateFlag.Name)
+	cfg.TrieIntegrityHeal = ctx.Bool(TrieIntegrityHealFlag.Name)
 
 	if ctx.IsSet(CacheScaleFlag.Name) {
 		common.CacheScale = ctx.Int(CacheScaleFlag.Name)
@@ -683,6 +801,50 @@ func (kCfg *KaiaConfig) SetKaiaConfig(ctx *cli.Context, stack *node.Node) {
 	if ctx.IsSet(BlockGenerationTimeLimitFlag.Name) {
 		params.BlockGenerationTimeLimit = ctx.Duration(BlockGenerationTimeLimitFlag.Name)
 	}
+	cfg.Istanbul.AdaptiveBlockPeriod = ctx.Bool(BlockGenerationAdaptiveFlag.Name)
+	// The istanbul gossip flags are only registered for CN and SCN, so the others keep the defaults.
+	if ctx.IsSet(IstanbulGossipPolicyFlag.Name) {
+		if policy, err := istanbul.ParseGossipPolicy(ctx.String(IstanbulGossipPolicyFlag.Name)); err != nil {
+			logger.Crit("Invalid istanbul gossip policy", "err", err)
+		} else {
+			cfg.Istanbul.GossipPolicy = policy
+		}
+	}
+	if ctx.IsSet(IstanbulGossipFanoutFlag.Name) {
+		cfg.Istanbul.GossipFanout = ctx.Uint64(IstanbulGossipFanoutFlag.Name)
+	}
+	if ctx.IsSet(IstanbulGossipFullThresholdFlag.Name) {
+		cfg.Istanbul.GossipFullThreshold = ctx.Uint64(IstanbulGossipFullThresholdFlag.Name)
+	}
+	cfg.Istanbul.SessionNonce = ctx.Bool(IstanbulSessionNonceFlag.Name)
+
+	if ctx.IsSet(UpstreamEndpointsFlag.Name) {
+		cfg.UpstreamEndpoints = SplitAndTrim(ctx.String(UpstreamEndpointsFlag.Name))
+		cfg.UpstreamPollInterval = ctx.Duration(UpstreamPollIntervalFlag.Name)
+	}
+	if len(cfg.UpstreamEndpoints) > 0 {
+		// Blocks come only from the upstream, so neither import nor relay the blocks of the peers.
+		cfg.FetcherDisable = true
+		cfg.DownloaderDisable = true
+		logger.Info("Follower mode is enabled, disabling fetcher, downloader", "endpoints", cfg.UpstreamEndpoints)
+	}
+	if ctx.IsSet(PrivateTxPartnersFlag.Name) {
+		cfg.PrivateTxPartners = SplitAndTrim(ctx.String(PrivateTxPartnersFlag.Name))
+	}
+	cfg.PrivateTxPoolSize = ctx.Int(PrivateTxPoolSizeFlag.Name)
+	cfg.AnnounceEnabled = ctx.Bool(AnnounceEnabledFlag.Name)
+	if ctx.IsSet(FleetBanMembersFlag.Name) {
+		cfg.FleetBanMembers = SplitAndTrim(ctx.String(FleetBanMembersFlag.Name))
+	}
+	cfg.FleetBanThreshold = ctx.Uint64(FleetBanThresholdFlag.Name)
+	cfg.FleetBanTTL = ctx.Duration(FleetBanTTLFlag.Name)
+	cfg.RecordProposals = ctx.Bool(ProposalRecordFlag.Name)
+	cfg.TxProvenance = ctx.Bool(TxProvenanceFlag.Name)
+	cfg.TxProvenanceSize = ctx.Int(TxProvenanceSizeFlag.Name)
+	if ctx.IsSet(TxProvenanceFleetFlag.Name) {
+		cfg.TxProvenanceFleet = SplitAndTrim(ctx.String(TxProvenanceFleetFlag.Name))
+	}
+	cfg.TxFirewallRules = ctx.String(TxFirewallRulesFlag.Name)
 	if ctx.IsSet(OpcodeComputationCostLimitFlag.Name) {
 		params.OpcodeComputationCostLimitOverride = ctx.Uint64(OpcodeComputationCostLimitFlag.Name)
 	}
@@ -702,6 +864,12 @@ func (kCfg *KaiaConfig) SetKaiaConfig(ctx *cli.Context, stack *node.Node) {
 	cfg.DisableUnsafeDebug = ctx.Bool(UnsafeDebugDisableFlag.Name)
 	cfg.StateRegenerationTimeLimit = ctx.Duration(StateRegenerationTimeLimitFlag.Name)
 	tracers.HeavyAPIRequestLimit = int32(ctx.Int(HeavyDebugRequestLimitFlag.Name))
+	cfg.LoadShedding = ctx.Bool(LoadSheddingFlag.Name)
+	cfg.LoadShedCPUThreshold = ctx.Float64(LoadShedCPUThresholdFlag.Name)
+	cfg.LoadShedMemoryThreshold = ctx.Float64(LoadShedMemoryThresholdFlag.Name)
+	cfg.LoadShedDiskLatency = ctx.Duration(LoadShedDiskLatencyFlag.Name)
+	cfg.AbiStore = ctx.Bool(AbiStoreFlag.Name)
+	cfg.AbiStoreMetadataURL = ctx.String(AbiStoreMetadataURLFlag.Name)
 
 	// Override any default configs for hard coded network.
 	// TODO-Kaia-Bootnode: Discuss and add `kairos` test network's genesis block
@@ -720,6 +888,20 @@ func (kCfg *KaiaConfig) SetKaiaConfig(ctx *cli.Context, stack *node.Node) {
 	cfg.GPO.Blocks = ctx.Int(GpoBlocksFlag.Name)
 	cfg.GPO.Percentile = ctx.Int(GpoPercentileFlag.Name)
 	cfg.GPO.MaxPrice = big.NewInt(ctx.Int64(GpoMaxGasPriceFlag.Name))
+
+	// Disable every feature writing to the databases if they are opened read-only.
+	if cfg.DBReadOnly = ctx.Bool(DBReadOnlyFlag.Name); cfg.DBReadOnly {
+		cfg.FetcherDisable = true
+		cfg.DownloaderDisable = true
+		cfg.WorkerDisable = true
+		cfg.SnapshotCacheSize = 0
+		cfg.TrieJournal = false
+		cfg.TxPool.Journal = ""
+		if cfg.DBType == database.RocksDB {
+			cfg.RocksDBConfig.Secondary = true
+		}
+		logger.Info("Opening the databases read-only")
+	}
 }
 
 // raiseFDLimit increases the file descriptor limit to process's maximum value
@@ -816,6 +998,9 @@ func setTxPool(ctx *cli.Context, cfg *blockchain.TxPoolConfig) {
 	if ctx.IsSet(TxPoolNonExecSlotsAllFlag.Name) {
 		cfg.NonExecSlotsAll = ctx.Uint64(TxPoolNonExecSlotsAllFlag.Name)
 	}
+	if ctx.IsSet(TxPoolBlobsAllFlag.Name) {
+		cfg.BlobsAll = ctx.Uint64(TxPoolBlobsAllFlag.Name)
+	}
 
 	cfg.KeepLocals = ctx.Bool(TxPoolKeepLocalsFlag.Name)
 
@@ -18,6 +18,7 @@ package utils
 import (
 	"testing"
 
+	"github.com/kaiachain/kaia/node"
 	"github.com/stretchr/testify/assert"
 	"github.com/urfave/cli/v2"
 	"github.com/urfave/cli/v2/altsrc"
@@ -291,3 +292,21 @@ func TestLoadYaml(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestParseRPCListeners(t *testing.T) {
+	listeners, err := parseRPCListeners("http://0.0.0.0:8553?api=kaia,eth&cors=*&vhosts=a.io,b.io&auth=t1; ws://127.0.0.1:8554?api=kaia;")
+	assert.NoError(t, err)
+	assert.Equal(t, []node.RPCListenerConfig{
+		{Protocol: "http", Host: "0.0.0.0", Port: 8553, Modules: []string{"kaia", "eth"}, Cors: []string{"*"}, VirtualHosts: []string{"a.io", "b.io"}, AuthTokens: []string{"t1"}},
+		{Protocol: "ws", Host: "127.0.0.1", Port: 8554, Modules: []string{"kaia"}},
+	}, listeners)
+
+	for _, spec := range []string{
+		"grpc://0.0.0.0:8553",
+		"http://0.0.0.0",
+		"http://0.0.0.0:8553?unknown=1",
+	} {
+		_, err := parseRPCListeners(spec)
+		assert.Error(t, err, spec)
+	}
+}
@@ -54,6 +54,11 @@ var FlagGroups = []FlagGroup{
 			StartBlockNumberFlag,
 			BlockGenerationIntervalFlag,
 			BlockGenerationTimeLimitFlag,
+			BlockGenerationAdaptiveFlag,
+			IstanbulGossipPolicyFlag,
+			IstanbulGossipFanoutFlag,
+			IstanbulGossipFullThresholdFlag,
+			IstanbulSessionNonceFlag,
 			OpcodeComputationCostLimitFlag,
 		},
 	},
@@ -80,11 +85,16 @@ var FlagGroups = []FlagGroup{
 			TxPoolExecSlotsAllFlag,
 			TxPoolNonExecSlotsAccountFlag,
 			TxPoolNonExecSlotsAllFlag,
+			TxPoolBlobsAllFlag,
 			TxPoolLifetimeFlag,
 			TxPoolKeepLocalsFlag,
 			TxResendIntervalFlag,
 			TxResendCountFlag,
 			TxResendUseLegacyFlag,
+			TxProvenanceFlag,
+			TxProvenanceSizeFlag,
+			TxProvenanceFleetFlag,
+			TxFirewallRulesFlag,
 		},
 	},
 	{
@@ -92,8 +102,17 @@ var FlagGroups = []FlagGroup{
 		Flags: []cli.Flag{
 			LevelDBCacheSizeFlag,
 			PebbleDBCacheSizeFlag,
+			AncientThresholdFlag,
+			AncientDirFlag,
+			CompactionWindowFlag,
+			CompactionLatencyLimitFlag,
+			MemoryDBFlag,
+			DBReadOnlyFlag,
+			ReceiptRetentionFlag,
 			SingleDBFlag,
 			NumStateTrieShardsFlag,
+			StateTrieShardDirsFlag,
+			CompressedTablesFlag,
 			LevelDBCompressionTypeFlag,
 			LevelDBNoBufferPoolFlag,
 			RocksDBSecondaryFlag,
@@ -114,6 +133,7 @@ var FlagGroups = []FlagGroup{
 			NoParallelDBWriteFlag,
 			SenderTxHashIndexingFlag,
 			DBNoPerformanceMetricsFlag,
+			DBDowngradeToFlag,
 		},
 	},
 	{
@@ -194,6 +214,42 @@ var FlagGroups = []FlagGroup{
 			DstRocksDBCacheIndexAndFilterFlag,
 		},
 	},
+	{
+		Name: "VALIDATOR SIMULATION",
+		Flags: []cli.Flag{
+			ValidatorSimulateStakeFlag,
+			ValidatorSimulateBlockFlag,
+		},
+	},
+	{
+		Name: "GOVERNANCE HISTORY",
+		Flags: []cli.Flag{
+			GovHistoryOutFlag,
+			GovHistoryGenesisFlag,
+			GovHistoryTimelineFlag,
+		},
+	},
+	{
+		Name: "TESTNET",
+		Flags: []cli.Flag{
+			TestnetDirFlag,
+			TestnetValidatorsFlag,
+			TestnetENsFlag,
+			TestnetAccountsFlag,
+			TestnetChainIDFlag,
+			TestnetPortFlag,
+			TestnetRPCPortFlag,
+			TestnetKenFlag,
+			TestnetCleanFlag,
+		},
+	},
+	{
+		Name: "DOCTOR",
+		Flags: []cli.Flag{
+			DoctorDiskSizeFlag,
+			DoctorOfflineFlag,
+		},
+	},
 	{
 		Name: "STATE",
 		Flags: []cli.Flag{
@@ -202,6 +258,21 @@ var FlagGroups = []FlagGroup{
 			TriesInMemoryFlag,
 			LivePruningFlag,
 			LivePruningRetentionFlag,
+			OnlinePruningFlag,
+			OnlinePruningRetentionFlag,
+			OnlinePruningIntervalFlag,
+			OnlinePruningRateFlag,
+			OnlinePruningBloomSizeFlag,
+			StateDiffFlag,
+			StateDiffPersistFlag,
+			StateTrieJournalFlag,
+			InvariantCheckFlag,
+			InvariantActionFlag,
+			VerkleShadowFlag,
+			TrieSchemeFlag,
+			TrieMirrorSchemeFlag,
+			TrieIntegrityCheckRateFlag,
+			TrieIntegrityHealFlag,
 		},
 	},
 	{
@@ -227,6 +298,7 @@ var FlagGroups = []FlagGroup{
 		Flags: []cli.Flag{
 			ServiceChainSignerFlag,
 			RewardbaseFlag,
+			ProposalRecordFlag,
 		},
 	},
 	{
@@ -244,6 +316,14 @@ var FlagGroups = []FlagGroup{
 			RWTimerWaitTimeFlag,
 			RWTimerIntervalFlag,
 			NetrestrictFlag,
+			UpstreamEndpointsFlag,
+			UpstreamPollIntervalFlag,
+			PrivateTxPartnersFlag,
+			PrivateTxPoolSizeFlag,
+			AnnounceEnabledFlag,
+			FleetBanMembersFlag,
+			FleetBanThresholdFlag,
+			FleetBanTTLFlag,
 			NodeKeyFileFlag,
 			NodeKeyHexFlag,
 			NetworkIdFlag,
@@ -273,12 +353,22 @@ var FlagGroups = []FlagGroup{
 		Flags: []cli.Flag{
 			RPCEnabledFlag,
 			HeavyDebugRequestLimitFlag,
+			HeavyCallSlotsFlag,
+			HeavyCallExecTimeLimitFlag,
+			RPCSlowQueryThresholdFlag,
+			RPCSlowQueryLogSizeFlag,
+			RPCJSONCodecFlag,
+			LoadSheddingFlag,
+			LoadShedCPUThresholdFlag,
+			LoadShedMemoryThresholdFlag,
+			LoadShedDiskLatencyFlag,
 			StateRegenerationTimeLimitFlag,
 			RPCListenAddrFlag,
 			RPCPortFlag,
 			RPCCORSDomainFlag,
 			RPCVirtualHostsFlag,
 			RPCApiFlag,
+			RPCListenersFlag,
 			RPCGlobalGasCap,
 			RPCGlobalEVMTimeoutFlag,
 			RPCGlobalEthTxFeeCapFlag,
@@ -289,7 +379,10 @@ var FlagGroups = []FlagGroup{
 			RPCReadTimeout,
 			RPCWriteTimeoutFlag,
 			RPCUpstreamArchiveENFlag,
+			RPCUpstreamCacheSizeFlag,
 			UnsafeDebugDisableFlag,
+			AbiStoreFlag,
+			AbiStoreMetadataURLFlag,
 			IPCDisabledFlag,
 			IPCPathFlag,
 			WSEnabledFlag,
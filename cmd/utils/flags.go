@@ -23,6 +23,7 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -30,10 +31,13 @@ import (
 	"time"
 
 	"github.com/kaiachain/kaia/blockchain"
+	"github.com/kaiachain/kaia/blockchain/state"
 	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/consensus/istanbul"
 	"github.com/kaiachain/kaia/datasync/chaindatafetcher"
 	"github.com/kaiachain/kaia/datasync/chaindatafetcher/kafka"
 	"github.com/kaiachain/kaia/datasync/dbsyncer"
+	"github.com/kaiachain/kaia/datasync/follower"
 	"github.com/kaiachain/kaia/log"
 	metricutils "github.com/kaiachain/kaia/metrics/utils"
 	"github.com/kaiachain/kaia/networks/rpc"
@@ -102,6 +106,28 @@ var (
 		EnvVars:  []string{"KLAYTN_DBTYPE", "KAIA_DBTYPE"},
 		Category: "KAIA",
 	}
+	MemoryDBFlag = &cli.BoolFlag{
+		Name:     "db.memory",
+		Usage:    "Keep all databases in memory. No chain data is written to disk and the node starts with a clean chain",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_DB_MEMORY", "KAIA_DB_MEMORY"},
+		Category: "DATABASE",
+	}
+	DBReadOnlyFlag = &cli.BoolFlag{
+		Name:     "db.readonly",
+		Usage:    "Open the databases read-only and disable block sync, block generation, state snapshot and tx pool journaling, e.g. to attach to the datadir of another node",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_DB_READONLY", "KAIA_DB_READONLY"},
+		Category: "DATABASE",
+	}
+	ReceiptRetentionFlag = &cli.Uint64Flag{
+		Name:     "db.receipt-retention",
+		Usage:    "Number of the recent blocks whose receipts and logs are kept. Older receipts are deleted while headers and bodies are kept (0 = keep all, not supported in archive mode)",
+		Value:    0,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_DB_RECEIPT_RETENTION", "KAIA_DB_RECEIPT_RETENTION"},
+		Category: "DATABASE",
+	}
 	SrvTypeFlag = &cli.StringFlag{
 		Name:     "srvtype",
 		Usage:    `json rpc server type ("http", "fasthttp")`,
@@ -236,7 +262,7 @@ var (
 	}
 	TxPoolPriceLimitFlag = &cli.Uint64Flag{
 		Name:     "txpool.pricelimit",
-		Usage:    "Minimum gas price limit to enforce for acceptance into the pool",
+		Usage:    "Minimum gas price limit to enforce for acceptance of remote transactions into the pool",
 		Value:    cn.GetDefaultConfig().TxPool.PriceLimit,
 		Aliases:  []string{"txpool.price-limit"},
 		EnvVars:  []string{"KLAYTN_TXPOOL_PRICELIMIT", "KAIA_TXPOOL_PRICELIMIT"},
@@ -282,6 +308,14 @@ var (
 		EnvVars:  []string{"KLAYTN_TXPOOL_NONEXEC_SLOTS_ALL", "KAIA_TXPOOL_NONEXEC_SLOTS_ALL"},
 		Category: "TXPOOL",
 	}
+	TxPoolBlobsAllFlag = &cli.Uint64Flag{
+		Name:     "txpool.blobs.all",
+		Usage:    "Maximum number of blobs held by the blob transactions of all accounts",
+		Value:    cn.GetDefaultConfig().TxPool.BlobsAll,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_TXPOOL_BLOBS_ALL", "KAIA_TXPOOL_BLOBS_ALL"},
+		Category: "TXPOOL",
+	}
 	TxPoolKeepLocalsFlag = &cli.BoolFlag{
 		Name:     "txpool.keeplocals",
 		Usage:    "Disables removing timed-out local transactions",
@@ -289,6 +323,35 @@ var (
 		EnvVars:  []string{"KLAYTN_TXPOOL_KEEPLOCALS", "KAIA_TXPOOL_KEEPLOCALS"},
 		Category: "TXPOOL",
 	}
+	TxProvenanceFlag = &cli.BoolFlag{
+		Name:     "txprovenance",
+		Usage:    "Record node-locally where (RPC client or peer) and when each transaction was first seen, for debug_txProvenance and debug_txPropagation",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_TXPROVENANCE", "KAIA_TXPROVENANCE"},
+		Category: "TXPOOL",
+	}
+	TxProvenanceSizeFlag = &cli.IntFlag{
+		Name:     "txprovenance.size",
+		Usage:    "Maximum number of the transactions whose provenance is kept",
+		Value:    cn.GetDefaultConfig().TxProvenanceSize,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_TXPROVENANCE_SIZE", "KAIA_TXPROVENANCE_SIZE"},
+		Category: "TXPOOL",
+	}
+	TxProvenanceFleetFlag = &cli.StringFlag{
+		Name:     "txprovenance.fleet",
+		Usage:    "Comma separated list of the RPC endpoints of the operator's other nodes queried by debug_txPropagation",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_TXPROVENANCE_FLEET", "KAIA_TXPROVENANCE_FLEET"},
+		Category: "TXPOOL",
+	}
+	TxFirewallRulesFlag = &cli.StringFlag{
+		Name:     "txfirewall.rules",
+		Usage:    "File keeping the tx firewall rules which reject or tag the incoming transactions by their calldata (default: txfirewall.json in the data directory)",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_TXFIREWALL_RULES", "KAIA_TXFIREWALL_RULES"},
+		Category: "TXPOOL",
+	}
 	TxPoolLifetimeFlag = &cli.DurationFlag{
 		Name:     "txpool.lifetime",
 		Usage:    "Maximum amount of time non-executable transaction are queued",
@@ -328,6 +391,20 @@ var (
 		EnvVars:  []string{"KLAYTN_DB_NUM_STATETRIE_SHARDS", "KAIA_DB_NUM_STATETRIE_SHARDS"},
 		Category: "DATABASE",
 	}
+	StateTrieShardDirsFlag = &cli.StringSliceFlag{
+		Name:     "db.statetrie-shard-dirs",
+		Usage:    "Comma separated directories to spread the state trie DB shards over, e.g. one per disk (default = inside chaindata)",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_DB_STATETRIE_SHARD_DIRS", "KAIA_DB_STATETRIE_SHARD_DIRS"},
+		Category: "DATABASE",
+	}
+	CompressedTablesFlag = &cli.StringSliceFlag{
+		Name:     "db.compress-tables",
+		Usage:    "Comma separated tables whose large values are compressed with zstd (body, receipts, statetrie, header, txlookup, snapshot, bridgeservice). Use 'kcn db compress' to rewrite the existing values",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_DB_COMPRESS_TABLES", "KAIA_DB_COMPRESS_TABLES"},
+		Category: "DATABASE",
+	}
 	LevelDBCacheSizeFlag = &cli.IntFlag{
 		Name:     "db.leveldb.cache-size",
 		Usage:    "Size of in-memory cache in LevelDB (MiB)",
@@ -360,6 +437,36 @@ var (
 		EnvVars:  []string{"KLAYTN_DB_PEBBLE_CACHE_SIZE", "KAIA_DB_PEBBLE_CACHE_SIZE"},
 		Category: "DATABASE",
 	}
+	AncientThresholdFlag = &cli.Uint64Flag{
+		Name:     "db.ancient.threshold",
+		Usage:    "Number of recent blocks kept in the key-value database. Older headers, bodies and receipts are moved to the ancient store (0 = disabled)",
+		Value:    0,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_DB_ANCIENT_THRESHOLD", "KAIA_DB_ANCIENT_THRESHOLD"},
+		Category: "DATABASE",
+	}
+	AncientDirFlag = &cli.PathFlag{
+		Name:     "db.ancient.dir",
+		Usage:    "Directory of the ancient store (default = inside chaindata)",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_DB_ANCIENT_DIR", "KAIA_DB_ANCIENT_DIR"},
+		Category: "DATABASE",
+	}
+	CompactionWindowFlag = &cli.StringFlag{
+		Name:     "db.compaction.window",
+		Usage:    "Daily low-traffic window \"HH:MM-HH:MM\" in UTC to compact the whole database range by range (empty = disabled)",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_DB_COMPACTION_WINDOW", "KAIA_DB_COMPACTION_WINDOW"},
+		Category: "DATABASE",
+	}
+	CompactionLatencyLimitFlag = &cli.DurationFlag{
+		Name:     "db.compaction.latency-limit",
+		Usage:    "Block import time above which the scheduled compaction pauses (0 = never pause)",
+		Value:    time.Second,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_DB_COMPACTION_LATENCY_LIMIT", "KAIA_DB_COMPACTION_LATENCY_LIMIT"},
+		Category: "DATABASE",
+	}
 	RocksDBSecondaryFlag = &cli.BoolFlag{
 		Name:     "db.rocksdb.secondary",
 		Usage:    "Enable rocksdb secondary mode (read-only and catch-up with primary node dynamically)",
@@ -550,6 +657,118 @@ var (
 		EnvVars:  []string{"KLAYTN_STATE_LIVE_PRUNING_RETENTION", "KAIA_STATE_LIVE_PRUNING_RETENTION"},
 		Category: "STATE",
 	}
+	OnlinePruningFlag = &cli.BoolFlag{
+		Name:     "state.online-pruning",
+		Usage:    "Delete the trie nodes unreachable from the state of the recent blocks periodically while the node runs (not for live pruning or archive nodes)",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_STATE_ONLINE_PRUNING", "KAIA_STATE_ONLINE_PRUNING"},
+		Category: "STATE",
+	}
+	OnlinePruningRetentionFlag = &cli.Uint64Flag{
+		Name:     "state.online-pruning-retention",
+		Usage:    "Number of the recent blocks whose state is kept by the online pruning (at least state.tries-in-memory)",
+		Value:    blockchain.DefaultOnlinePruningRetention,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_STATE_ONLINE_PRUNING_RETENTION", "KAIA_STATE_ONLINE_PRUNING_RETENTION"},
+		Category: "STATE",
+	}
+	OnlinePruningIntervalFlag = &cli.DurationFlag{
+		Name:     "state.online-pruning-interval",
+		Usage:    "Interval between the online pruning sessions",
+		Value:    blockchain.DefaultOnlinePruningInterval,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_STATE_ONLINE_PRUNING_INTERVAL", "KAIA_STATE_ONLINE_PRUNING_INTERVAL"},
+		Category: "STATE",
+	}
+	OnlinePruningRateFlag = &cli.IntFlag{
+		Name:     "state.online-pruning-rate",
+		Usage:    "Maximum number of the trie nodes read or deleted per second by the online pruning (0 = unlimited)",
+		Value:    blockchain.DefaultOnlinePruningRate,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_STATE_ONLINE_PRUNING_RATE", "KAIA_STATE_ONLINE_PRUNING_RATE"},
+		Category: "STATE",
+	}
+	OnlinePruningBloomSizeFlag = &cli.Uint64Flag{
+		Name:     "state.online-pruning-bloom",
+		Usage:    "Size (MiB) of the bloom filter marking the reachable trie nodes during the online pruning",
+		Value:    blockchain.DefaultOnlinePruningBloomSize,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_STATE_ONLINE_PRUNING_BLOOM", "KAIA_STATE_ONLINE_PRUNING_BLOOM"},
+		Category: "STATE",
+	}
+	StateDiffFlag = &cli.BoolFlag{
+		Name:     "state.diff",
+		Usage:    "Record the accounts and storage slots changed by the recent blocks, served by debug_getStateDiff",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_STATE_DIFF", "KAIA_STATE_DIFF"},
+		Category: "STATE",
+	}
+	StateDiffPersistFlag = &cli.BoolFlag{
+		Name:     "state.diff-persist",
+		Usage:    "Write the state diffs of all imported blocks to the database (implies state.diff)",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_STATE_DIFF_PERSIST", "KAIA_STATE_DIFF_PERSIST"},
+		Category: "STATE",
+	}
+	StateTrieJournalFlag = &cli.BoolFlag{
+		Name:     "state.trie-journal",
+		Usage:    "Journal the in-memory state tries of the recent blocks on shutdown and restore them on startup",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_STATE_TRIE_JOURNAL", "KAIA_STATE_TRIE_JOURNAL"},
+		Category: "STATE",
+	}
+	InvariantCheckFlag = &cli.BoolFlag{
+		Name:     "state.invariant-check",
+		Usage:    "Verify chain invariants (total supply, nonce monotonicity, account key rules) on every imported block",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_STATE_INVARIANT_CHECK", "KAIA_STATE_INVARIANT_CHECK"},
+		Category: "STATE",
+	}
+	InvariantActionFlag = &cli.StringFlag{
+		Name:     "state.invariant-action",
+		Usage:    "Action on a chain invariant violation: 'alert' logs it, 'halt' also stops importing blocks",
+		Value:    "alert",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_STATE_INVARIANT_ACTION", "KAIA_STATE_INVARIANT_ACTION"},
+		Category: "STATE",
+	}
+	VerkleShadowFlag = &cli.BoolFlag{
+		Name:     "state.verkle-shadow",
+		Usage:    "Maintain a prototype verkle commitment of the state alongside the MPT on every imported block (devnets only, keeps the whole state in memory)",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_STATE_VERKLE_SHADOW", "KAIA_STATE_VERKLE_SHADOW"},
+		Category: "STATE",
+	}
+	TrieSchemeFlag = &cli.StringFlag{
+		Name:     "state.trie-scheme",
+		Usage:    "Commitment scheme of the state tries (experimental, all the nodes of the chain must agree)",
+		Value:    state.MPTTrieScheme,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_STATE_TRIE_SCHEME", "KAIA_STATE_TRIE_SCHEME"},
+		Category: "STATE",
+	}
+	TrieMirrorSchemeFlag = &cli.StringFlag{
+		Name:     "state.trie-mirror-scheme",
+		Usage:    "Commitment scheme to mirror the writes to the state tries into, building it up for a transition (experimental)",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_STATE_TRIE_MIRROR_SCHEME", "KAIA_STATE_TRIE_MIRROR_SCHEME"},
+		Category: "STATE",
+	}
+	TrieIntegrityCheckRateFlag = &cli.Uint64Flag{
+		Name:     "state.integrity-check-rate",
+		Usage:    "Verify one out of the given number of trie nodes written to the disk against its hash in the background, quarantining the corrupted ones (0 = disabled)",
+		Value:    0,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_STATE_INTEGRITY_CHECK_RATE", "KAIA_STATE_INTEGRITY_CHECK_RATE"},
+		Category: "STATE",
+	}
+	TrieIntegrityHealFlag = &cli.BoolFlag{
+		Name:     "state.integrity-heal",
+		Usage:    "Re-fetch the trie nodes quarantined by the integrity check from the peers",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_STATE_INTEGRITY_HEAL", "KAIA_STATE_INTEGRITY_HEAL"},
+		Category: "STATE",
+	}
 	CacheTypeFlag = &cli.IntFlag{
 		Name:     "cache.type",
 		Usage:    "Cache Type: 0=LRUCache, 1=LRUShardCache, 2=FIFOCache",
@@ -838,6 +1057,14 @@ var (
 		EnvVars:  []string{"KLAYTN_RPCAPI", "KAIA_RPCAPI"},
 		Category: "API AND CONSOLE",
 	}
+	RPCListenersFlag = &cli.StringFlag{
+		Name:     "rpc.listeners",
+		Usage:    "Semicolon separated list of additional RPC listeners as URLs with their own policy, e.g. 'http://0.0.0.0:8553?api=kaia,eth&cors=*&vhosts=*&auth=<token>;ws://0.0.0.0:8554?api=kaia'",
+		Value:    "",
+		Aliases:  []string{"http-rpc.listeners"},
+		EnvVars:  []string{"KLAYTN_RPC_LISTENERS", "KAIA_RPC_LISTENERS"},
+		Category: "API AND CONSOLE",
+	}
 	RPCGlobalGasCap = &cli.Uint64Flag{
 		Name:     "rpc.gascap",
 		Usage:    "Sets a cap on gas in {eth,kaia}_{call,estimateGas,estimateComputationCost} (0 = no cap)",
@@ -908,11 +1135,19 @@ var (
 	}
 	RPCUpstreamArchiveENFlag = &cli.StringFlag{
 		Name:     "upstream-en",
-		Usage:    "upstream archive mode EN endpoint",
+		Usage:    "Comma-separated upstream archive mode EN endpoints. Queries failing on the pruned local state are forwarded to the first upstream on the same chain that answers them",
 		Aliases:  []string{"rpc.upstream-en"},
 		EnvVars:  []string{"KLAYTN_RPC_UPSTREAM_EN", "KAIA_RPC_UPSTREAM_EN"},
 		Category: "API AND CONSOLE",
 	}
+	RPCUpstreamCacheSizeFlag = &cli.IntFlag{
+		Name:     "upstream-en.cache-size",
+		Usage:    "Number of results forwarded to the upstream archive ENs kept in memory (0 = disabled)",
+		Value:    rpc.UpstreamCacheSize,
+		Aliases:  []string{"rpc.upstream-en.cache-size"},
+		EnvVars:  []string{"KLAYTN_RPC_UPSTREAM_EN_CACHE_SIZE", "KAIA_RPC_UPSTREAM_EN_CACHE_SIZE"},
+		Category: "API AND CONSOLE",
+	}
 
 	WSEnabledFlag = &cli.BoolFlag{
 		Name:     "ws",
@@ -1069,6 +1304,20 @@ var (
 		EnvVars:  []string{"KLAYTN_RPC_UNSAFE_DEBUG_DISABLE", "KAIA_RPC_UNSAFE_DEBUG_DISABLE"},
 		Category: "API AND CONSOLE",
 	}
+	AbiStoreFlag = &cli.BoolFlag{
+		Name:     "abistore",
+		Usage:    "Enables the contract ABI store used for calldata and custom revert decoding (kaia_decodeCalldata, admin_setContractABI, ...)",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_ABISTORE", "KAIA_ABISTORE"},
+		Category: "API AND CONSOLE",
+	}
+	AbiStoreMetadataURLFlag = &cli.StringFlag{
+		Name:     "abistore.metadata-url",
+		Usage:    "URL template to fetch verified contract metadata from, e.g. https://repo.sourcify.dev/contracts/full_match/{chainId}/{address}/metadata.json. Placeholders: {chainId}, {address}, {codeHash}",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_ABISTORE_METADATA_URL", "KAIA_ABISTORE_METADATA_URL"},
+		Category: "API AND CONSOLE",
+	}
 	// TODO-Kaia: Consider limiting the non-debug heavy apis.
 	HeavyDebugRequestLimitFlag = &cli.IntFlag{
 		Name:     "rpc.unsafe-debug.heavy-debug.request-limit",
@@ -1078,6 +1327,77 @@ var (
 		EnvVars:  []string{"KLAYTN_RPC_UNSAFE_DEBUG_HEAVY_DEBUG_REQUEST_LIMIT", "KAIA_RPC_UNSAFE_DEBUG_HEAVY_DEBUG_REQUEST_LIMIT"},
 		Category: "API AND CONSOLE",
 	}
+	HeavyCallSlotsFlag = &cli.IntFlag{
+		Name:     "rpc.heavy-call.slots",
+		Usage:    "Number of heavy RPC calls (debug_trace*, debug_standardTrace*) executed at the same time. Waiting calls are served to each client in turn. 0 uses half of the CPUs available to the process (cgroup aware).",
+		Value:    rpc.HeavyCallSlots,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_RPC_HEAVY_CALL_SLOTS", "KAIA_RPC_HEAVY_CALL_SLOTS"},
+		Category: "API AND CONSOLE",
+	}
+	HeavyCallExecTimeLimitFlag = &cli.DurationFlag{
+		Name:     "rpc.heavy-call.exec-time-limit",
+		Usage:    "Limit the CPU time of a heavy RPC call, excluding the time waiting for a slot (0 = no limit)",
+		Value:    rpc.HeavyCallExecTimeLimit,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_RPC_HEAVY_CALL_EXEC_TIME_LIMIT", "KAIA_RPC_HEAVY_CALL_EXEC_TIME_LIMIT"},
+		Category: "API AND CONSOLE",
+	}
+	RPCSlowQueryThresholdFlag = &cli.DurationFlag{
+		Name:     "rpc.slow-query.threshold",
+		Usage:    "Record RPC calls taking longer than this in the slow query log, retrievable by debug_slowQueries (0 = disabled)",
+		Value:    rpc.SlowQueryThreshold,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_RPC_SLOW_QUERY_THRESHOLD", "KAIA_RPC_SLOW_QUERY_THRESHOLD"},
+		Category: "API AND CONSOLE",
+	}
+	RPCSlowQueryLogSizeFlag = &cli.IntFlag{
+		Name:     "rpc.slow-query.size",
+		Usage:    "Number of the latest slow RPC calls kept in the slow query log",
+		Value:    rpc.SlowQueryLogSize,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_RPC_SLOW_QUERY_SIZE", "KAIA_RPC_SLOW_QUERY_SIZE"},
+		Category: "API AND CONSOLE",
+	}
+	RPCJSONCodecFlag = &cli.StringFlag{
+		Name:     "rpc.json-codec",
+		Usage:    "JSON codec used to serialize RPC responses (std, stream, or sonic if built with -tags sonic)",
+		Value:    rpc.JSONCodecStream,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_RPC_JSON_CODEC", "KAIA_RPC_JSON_CODEC"},
+		Category: "API AND CONSOLE",
+	}
+	LoadSheddingFlag = &cli.BoolFlag{
+		Name:     "loadshed",
+		Usage:    "Progressively disable heavy and expensive RPC methods, pause the bloom indexer and shrink the txpool while the CPU, memory or disk latency is over its threshold. The state is reported at /health of the HTTP RPC server.",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_LOADSHED", "KAIA_LOADSHED"},
+		Category: "API AND CONSOLE",
+	}
+	LoadShedCPUThresholdFlag = &cli.Float64Flag{
+		Name:     "loadshed.cpu",
+		Usage:    "CPU usage threshold of load shedding, in percent of the CPUs available to the process (0 = ignore CPU)",
+		Value:    cn.GetDefaultConfig().LoadShedCPUThreshold,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_LOADSHED_CPU", "KAIA_LOADSHED_CPU"},
+		Category: "API AND CONSOLE",
+	}
+	LoadShedMemoryThresholdFlag = &cli.Float64Flag{
+		Name:     "loadshed.memory",
+		Usage:    "Memory usage threshold of load shedding, in percent of the system memory (0 = ignore memory)",
+		Value:    cn.GetDefaultConfig().LoadShedMemoryThreshold,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_LOADSHED_MEMORY", "KAIA_LOADSHED_MEMORY"},
+		Category: "API AND CONSOLE",
+	}
+	LoadShedDiskLatencyFlag = &cli.DurationFlag{
+		Name:     "loadshed.disk-latency",
+		Usage:    "Fsync latency threshold of the data directory for load shedding (0 = ignore disk)",
+		Value:    cn.GetDefaultConfig().LoadShedDiskLatency,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_LOADSHED_DISK_LATENCY", "KAIA_LOADSHED_DISK_LATENCY"},
+		Category: "API AND CONSOLE",
+	}
 	StateRegenerationTimeLimitFlag = &cli.DurationFlag{
 		Name:     "rpc.unsafe-debug.state-regeneration.time-limit",
 		Usage:    "Limit the state regeneration time. Works with unsafe-debug only.",
@@ -1199,6 +1519,79 @@ var (
 		EnvVars:  []string{"KLAYTN_NETRESTRICT", "KAIA_NETRESTRICT"},
 		Category: "NETWORK",
 	}
+	UpstreamEndpointsFlag = &cli.StringFlag{
+		Name: "upstream.endpoints",
+		Usage: "Comma separated list of trusted upstream node RPC endpoints. If set, the EN runs in " +
+			"follower mode: blocks are pulled from the upstream nodes, and the p2p server is disabled",
+		Aliases:  []string{"p2p.upstream-endpoints"},
+		EnvVars:  []string{"KLAYTN_UPSTREAM_ENDPOINTS", "KAIA_UPSTREAM_ENDPOINTS"},
+		Category: "NETWORK",
+	}
+	UpstreamPollIntervalFlag = &cli.DurationFlag{
+		Name:     "upstream.poll-interval",
+		Usage:    "Interval between upstream head checks in follower mode (EN only)",
+		Value:    follower.DefaultPollInterval,
+		Aliases:  []string{"p2p.upstream-poll-interval"},
+		EnvVars:  []string{"KLAYTN_UPSTREAM_POLL_INTERVAL", "KAIA_UPSTREAM_POLL_INTERVAL"},
+		Category: "NETWORK",
+	}
+	PrivateTxPartnersFlag = &cli.StringFlag{
+		Name: "privtx.partners",
+		Usage: "Comma separated list of the node ids or node URLs of the trusted partners exchanging private " +
+			"transactions. The transactions handed off by the partners are not gossiped, and are only included " +
+			"in the blocks proposed by this node. This flag is only applicable to CN.",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_PRIVTX_PARTNERS", "KAIA_PRIVTX_PARTNERS"},
+		Category: "NETWORK",
+	}
+	PrivateTxPoolSizeFlag = &cli.IntFlag{
+		Name:     "privtx.poolsize",
+		Usage:    "Maximum number of the private transactions kept for the partners",
+		Value:    cn.GetDefaultConfig().PrivateTxPoolSize,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_PRIVTX_POOLSIZE", "KAIA_PRIVTX_POOLSIZE"},
+		Category: "NETWORK",
+	}
+	AnnounceEnabledFlag = &cli.BoolFlag{
+		Name: "announce.enable",
+		Usage: "Enable gossiping the operational announcements (e.g. planned maintenance, upgrade readiness) " +
+			"signed by the council members. This flag is only applicable to CN.",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_ANNOUNCE_ENABLE", "KAIA_ANNOUNCE_ENABLE"},
+		Category: "NETWORK",
+	}
+	FleetBanMembersFlag = &cli.StringFlag{
+		Name: "fleetban.members",
+		Usage: "Comma separated list of the node ids or node URLs of the operator's other nodes sharing the abusive IPs " +
+			"and nodes. The nodes detected abusive by any member are refused by all members while banned.",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_FLEETBAN_MEMBERS", "KAIA_FLEETBAN_MEMBERS"},
+		Category: "NETWORK",
+	}
+	FleetBanThresholdFlag = &cli.Uint64Flag{
+		Name:     "fleetban.threshold",
+		Usage:    "Sum of the scores (1 to 100 per member) of the fleet reports to ban an IP or a node",
+		Value:    cn.GetDefaultConfig().FleetBanThreshold,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_FLEETBAN_THRESHOLD", "KAIA_FLEETBAN_THRESHOLD"},
+		Category: "NETWORK",
+	}
+	FleetBanTTLFlag = &cli.DurationFlag{
+		Name:     "fleetban.ttl",
+		Usage:    "Duration of the fleet reports made by this node",
+		Value:    cn.GetDefaultConfig().FleetBanTTL,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_FLEETBAN_TTL", "KAIA_FLEETBAN_TTL"},
+		Category: "NETWORK",
+	}
+	ProposalRecordFlag = &cli.BoolFlag{
+		Name: "proposal.record",
+		Usage: "Persist the input of building each block proposed by this node (pending transactions, ordering policy, " +
+			"time limit) so that debug_replayProposal can verify the ordering later. This flag is only applicable to CN.",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_PROPOSAL_RECORD", "KAIA_PROPOSAL_RECORD"},
+		Category: "CONSENSUS",
+	}
 	RWTimerIntervalFlag = &cli.Uint64Flag{
 		Name:     "rwtimerinterval",
 		Usage:    "Interval of using rw timer to check if it works well",
@@ -1953,6 +2346,142 @@ var (
 		Category: "DATABASE MIGRATION",
 	}
 
+	// validator simulation
+	ValidatorSimulateStakeFlag = &cli.Uint64Flag{
+		Name:     "simulate.stake",
+		Usage:    "Staking amount (in KAIA) of the prospective validator",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_SIMULATE_STAKE", "KAIA_SIMULATE_STAKE"},
+		Category: "VALIDATOR SIMULATION",
+	}
+	ValidatorSimulateBlockFlag = &cli.Uint64Flag{
+		Name:     "simulate.block",
+		Usage:    "Block number whose staking information and governance parameters are used (0 = latest)",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_SIMULATE_BLOCK", "KAIA_SIMULATE_BLOCK"},
+		Category: "VALIDATOR SIMULATION",
+	}
+
+	// governance history
+	DBDowngradeToFlag = &cli.StringFlag{
+		Name:     "to",
+		Usage:    "Database version to downgrade to (e.g. v3)",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_DB_DOWNGRADE_TO", "KAIA_DB_DOWNGRADE_TO"},
+		Category: "DATABASE",
+	}
+	GovHistoryOutFlag = &cli.PathFlag{
+		Name:     "gov.out",
+		Usage:    "File to write the exported governance history to (default: standard output)",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_GOV_OUT", "KAIA_GOV_OUT"},
+		Category: "GOVERNANCE HISTORY",
+	}
+	GovHistoryGenesisFlag = &cli.PathFlag{
+		Name:     "gov.genesis",
+		Usage:    "Genesis file of a test network to seed with the imported governance parameters instead of importing into a node",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_GOV_GENESIS", "KAIA_GOV_GENESIS"},
+		Category: "GOVERNANCE HISTORY",
+	}
+	GovHistoryTimelineFlag = &cli.BoolFlag{
+		Name:     "gov.timeline",
+		Usage:    "Seed the genesis with the parameters at genesis and schedule the later changes at their activation blocks, instead of the parameters at the head",
+		Value:    false,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_GOV_TIMELINE", "KAIA_GOV_TIMELINE"},
+		Category: "GOVERNANCE HISTORY",
+	}
+
+	// Local test network
+	TestnetDirFlag = &cli.PathFlag{
+		Name:     "testnet.dir",
+		Usage:    "Directory of the local test network, holding the genesis, the keys and the node data",
+		Value:    "testnet",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_TESTNET_DIR", "KAIA_TESTNET_DIR"},
+		Category: "TESTNET",
+	}
+	TestnetValidatorsFlag = &cli.IntFlag{
+		Name:     "testnet.validators",
+		Usage:    "Number of the validators (CNs) of the local test network",
+		Value:    4,
+		Aliases:  []string{"validators"},
+		EnvVars:  []string{"KLAYTN_TESTNET_VALIDATORS", "KAIA_TESTNET_VALIDATORS"},
+		Category: "TESTNET",
+	}
+	TestnetENsFlag = &cli.IntFlag{
+		Name:     "testnet.ens",
+		Usage:    "Number of the endpoint nodes (ENs) of the local test network. The ken binary is required if positive",
+		Value:    0,
+		Aliases:  []string{"ens"},
+		EnvVars:  []string{"KLAYTN_TESTNET_ENS", "KAIA_TESTNET_ENS"},
+		Category: "TESTNET",
+	}
+	TestnetAccountsFlag = &cli.IntFlag{
+		Name:     "testnet.accounts",
+		Usage:    "Number of the funded test accounts of the local test network",
+		Value:    10,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_TESTNET_ACCOUNTS", "KAIA_TESTNET_ACCOUNTS"},
+		Category: "TESTNET",
+	}
+	TestnetChainIDFlag = &cli.Uint64Flag{
+		Name:     "testnet.chainid",
+		Usage:    "Chain ID and network ID of the local test network",
+		Value:    1000,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_TESTNET_CHAINID", "KAIA_TESTNET_CHAINID"},
+		Category: "TESTNET",
+	}
+	TestnetPortFlag = &cli.IntFlag{
+		Name:     "testnet.port",
+		Usage:    "P2P port of the first node of the local test network. The other nodes use the following ports",
+		Value:    32323,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_TESTNET_PORT", "KAIA_TESTNET_PORT"},
+		Category: "TESTNET",
+	}
+	TestnetRPCPortFlag = &cli.IntFlag{
+		Name:     "testnet.rpcport",
+		Usage:    "HTTP-RPC port of the first node of the local test network. The other nodes use the following ports",
+		Value:    8551,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_TESTNET_RPCPORT", "KAIA_TESTNET_RPCPORT"},
+		Category: "TESTNET",
+	}
+	TestnetKenFlag = &cli.PathFlag{
+		Name:     "testnet.ken",
+		Usage:    "Path of the ken binary to run the ENs (default: ken next to this binary, or in PATH)",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_TESTNET_KEN", "KAIA_TESTNET_KEN"},
+		Category: "TESTNET",
+	}
+	TestnetCleanFlag = &cli.BoolFlag{
+		Name:     "testnet.clean",
+		Usage:    "Remove the directory of the local test network after stopping it",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_TESTNET_CLEAN", "KAIA_TESTNET_CLEAN"},
+		Category: "TESTNET",
+	}
+
+	// Doctor
+	DoctorDiskSizeFlag = &cli.IntFlag{
+		Name:     "doctor.disk-size",
+		Usage:    "Size of the file written to measure the disk throughput (MiB)",
+		Value:    256,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_DOCTOR_DISK_SIZE", "KAIA_DOCTOR_DISK_SIZE"},
+		Category: "DOCTOR",
+	}
+	DoctorOfflineFlag = &cli.BoolFlag{
+		Name:     "doctor.offline",
+		Usage:    "Skip the checks requiring the network, i.e. the clock sync",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_DOCTOR_OFFLINE", "KAIA_DOCTOR_OFFLINE"},
+		Category: "DOCTOR",
+	}
+
 	// Config
 	ConfigFileFlag = &cli.StringFlag{
 		Name:     "config",
@@ -1980,6 +2509,47 @@ var (
 		EnvVars:  []string{"KLAYTN_BLOCK_GENERATION_TIME_LIMIT", "KAIA_BLOCK_GENERATION_TIME_LIMIT"},
 		Category: "KAIA",
 	}
+	BlockGenerationAdaptiveFlag = &cli.BoolFlag{
+		Name: "block-generation-adaptive",
+		Usage: "(experimental option) Delay block proposals by the observed commit latency " +
+			"to keep the interval between committed blocks stable. This flag is only applicable to CN.",
+		Aliases:  []string{"experimental.block-generation-adaptive"},
+		EnvVars:  []string{"KLAYTN_BLOCK_GENERATION_ADAPTIVE", "KAIA_BLOCK_GENERATION_ADAPTIVE"},
+		Category: "KAIA",
+	}
+	IstanbulGossipPolicyFlag = &cli.StringFlag{
+		Name: "istanbul.gossip-policy",
+		Usage: "Policy for selecting the committee members a consensus message is forwarded to (full, random, tree). " +
+			"random and tree reduce the message complexity of large committees. This flag is only applicable to CN.",
+		Value:    istanbul.DefaultConfig.GossipPolicy.String(),
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_ISTANBUL_GOSSIP_POLICY", "KAIA_ISTANBUL_GOSSIP_POLICY"},
+		Category: "KAIA",
+	}
+	IstanbulGossipFanoutFlag = &cli.Uint64Flag{
+		Name:     "istanbul.gossip-fanout",
+		Usage:    "Number of committee members each node forwards a consensus message to under random and tree gossip",
+		Value:    istanbul.DefaultConfig.GossipFanout,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_ISTANBUL_GOSSIP_FANOUT", "KAIA_ISTANBUL_GOSSIP_FANOUT"},
+		Category: "KAIA",
+	}
+	IstanbulGossipFullThresholdFlag = &cli.Uint64Flag{
+		Name:     "istanbul.gossip-full-threshold",
+		Usage:    "Committees up to this size are fully gossiped regardless of the gossip policy",
+		Value:    istanbul.DefaultConfig.GossipFullThreshold,
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_ISTANBUL_GOSSIP_FULL_THRESHOLD", "KAIA_ISTANBUL_GOSSIP_FULL_THRESHOLD"},
+		Category: "KAIA",
+	}
+	IstanbulSessionNonceFlag = &cli.BoolFlag{
+		Name: "istanbul.session-nonce",
+		Usage: "Sign session-scoped sequence numbers into the consensus messages so that they cannot be replayed " +
+			"by relaying peers. Enable it once every validator is upgraded. This flag is only applicable to CN.",
+		Aliases:  []string{},
+		EnvVars:  []string{"KLAYTN_ISTANBUL_SESSION_NONCE", "KAIA_ISTANBUL_SESSION_NONCE"},
+		Category: "KAIA",
+	}
 	OpcodeComputationCostLimitFlag = &cli.Uint64Flag{
 		Name: "opcode-computation-cost-limit",
 		Usage: "(experimental option) Set the computation cost limit for a tx. " +
@@ -2069,51 +2639,29 @@ func RegisterCNService(stack *node.Node, cfg *cn.Config) {
 	}
 }
 
-func RegisterService(stack *node.Node, cfg *sc.SCConfig) {
-	if cfg.EnabledMainBridge {
-		err := stack.RegisterSubService(func(ctx *node.ServiceContext) (node.Service, error) {
-			mainBridge, err := sc.NewMainBridge(ctx, cfg)
-			return mainBridge, err
-		})
-		if err != nil {
-			log.Fatalf("Failed to register the main bridge service: %v", err)
-		}
-	}
+var (
+	ErrBridgeNotBuilt  = errors.New("service chain bridges are not built in this binary (nobridge tag)")
+	ErrIndexerNotBuilt = errors.New("chain data fetcher and DB syncer are not built in this binary (noindexer tag)")
+)
 
-	if cfg.EnabledSubBridge {
-		err := stack.RegisterSubService(func(ctx *node.ServiceContext) (node.Service, error) {
-			subBridge, err := sc.NewSubBridge(ctx, cfg)
-			return subBridge, err
-		})
-		if err != nil {
-			log.Fatalf("Failed to register the sub bridge service: %v", err)
-		}
+// RegisterService adds the service chain bridges enabled in the config to the stack.
+func RegisterService(stack *node.Node, cfg *sc.SCConfig) {
+	if err := registerBridges(stack, cfg); err != nil {
+		log.Fatalf("Failed to register the bridge service: %v", err)
 	}
 }
 
 // RegisterChainDataFetcherService adds a ChainDataFetcher to the stack
 func RegisterChainDataFetcherService(stack *node.Node, cfg *chaindatafetcher.ChainDataFetcherConfig) {
-	if cfg.EnabledChainDataFetcher {
-		err := stack.RegisterSubService(func(ctx *node.ServiceContext) (node.Service, error) {
-			chainDataFetcher, err := chaindatafetcher.NewChainDataFetcher(ctx, cfg)
-			return chainDataFetcher, err
-		})
-		if err != nil {
-			log.Fatalf("Failed to register the service: %v", err)
-		}
+	if err := registerChainDataFetcher(stack, cfg); err != nil {
+		log.Fatalf("Failed to register the service: %v", err)
 	}
 }
 
 // RegisterDBSyncerService adds a DBSyncer to the stack
 func RegisterDBSyncerService(stack *node.Node, cfg *dbsyncer.DBConfig) {
-	if cfg.EnabledDBSyncer {
-		err := stack.RegisterSubService(func(ctx *node.ServiceContext) (node.Service, error) {
-			dbImporter, err := dbsyncer.NewDBSyncer(ctx, cfg)
-			return dbImporter, err
-		})
-		if err != nil {
-			log.Fatalf("Failed to register the service: %v", err)
-		}
+	if err := registerDBSyncer(stack, cfg); err != nil {
+		log.Fatalf("Failed to register the service: %v", err)
 	}
 }
 
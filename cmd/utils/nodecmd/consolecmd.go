@@ -139,20 +139,25 @@ func remoteConsole(ctx *cli.Context) error {
 func rpcEndpoint(ctx *cli.Context) string {
 	endpoint := ctx.Args().First()
 	if endpoint == "" {
-		path := node.DefaultDataDir()
-		if ctx.IsSet(utils.DataDirFlag.Name) {
-			path = ctx.String(utils.DataDirFlag.Name)
-		}
-		if path != "" {
-			if ctx.Bool(utils.KairosFlag.Name) {
-				path = filepath.Join(path, "baobab") // TODO: rename to Kairos
-			}
-		}
-		endpoint = fmt.Sprintf("%s/klay.ipc", path)
+		endpoint = ipcEndpoint(ctx)
 	}
 	return endpoint
 }
 
+// ipcEndpoint returns the IPC endpoint in the data directory.
+func ipcEndpoint(ctx *cli.Context) string {
+	path := node.DefaultDataDir()
+	if ctx.IsSet(utils.DataDirFlag.Name) {
+		path = ctx.String(utils.DataDirFlag.Name)
+	}
+	if path != "" {
+		if ctx.Bool(utils.KairosFlag.Name) {
+			path = filepath.Join(path, "baobab") // TODO: rename to Kairos
+		}
+	}
+	return fmt.Sprintf("%s/klay.ipc", path)
+}
+
 // dialRPC returns a RPC client which connects to the given endpoint.
 // The check for empty endpoint implements the defaulting logic
 // for "ken attach" and "ken monitor" with no argument.
@@ -0,0 +1,159 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package nodecmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kaiachain/kaia/blockchain"
+	"github.com/kaiachain/kaia/cmd/utils"
+	"github.com/kaiachain/kaia/node"
+	"github.com/kaiachain/kaia/node/cn"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/urfave/cli/v2"
+)
+
+var errNoDowngradeTarget = errors.New("the target database version is not given; use --to")
+
+var DBCommand = &cli.Command{
+	Name:     "db",
+	Usage:    "A set of commands for the chain database",
+	Category: "MISCELLANEOUS COMMANDS",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "downgrade",
+			Usage:  "Revert the chain database to an older version",
+			Action: utils.MigrateFlags(downgradeDB),
+			Flags:  utils.DBDowngradeFlags,
+			Description: `
+kcn db downgrade --to <version>
+reverts the chain database of a stopped node to the given database version by
+running the reverse migrations in order, so that an older Kaia supporting the
+version can open it. Nothing is changed if a reverse migration is missing.
+
+Even without a reverse migration, it clears the mark of the Kaia version which
+last opened the database, allowing an older Kaia of the same database version.`,
+		},
+		{
+			Name:   "compress",
+			Usage:  "Rewrite the database values following --db.compress-tables",
+			Action: utils.MigrateFlags(compressDB),
+			Flags:  utils.DBCompressFlags,
+			Description: `
+kcn db compress --db.compress-tables body,receipts
+rewrites every value of the tables of a stopped node given by --db.compress-tables,
+compressing the large values written before the compression was enabled. The
+tables compressed before but not given anymore are restored uncompressed, so
+that an older Kaia without the compression can open the database again.`,
+		},
+		{
+			Name:   "rebuild-bloombits",
+			Usage:  "Regenerate the log bloom index from the receipts",
+			Action: utils.MigrateFlags(rebuildBloomBits),
+			Flags:  utils.SnapshotFlags,
+			Description: `
+kcn db rebuild-bloombits
+regenerates the bloom bits index used by kaia_getLogs and eth_getLogs for every
+completed section of a stopped node from the receipts, and overwrites the sections
+whose stored index differs. The bloom of each header is verified against its
+receipts, and the blocks not matching are reported.`,
+		},
+	},
+}
+
+func compressDB(ctx *cli.Context) error {
+	nodeConfig := &node.Config{
+		DataDir:      utils.MakeDataDir(ctx),
+		ChainDataDir: ctx.String(utils.ChainDataDirFlag.Name),
+		Name:         utils.ClientIdentifier,
+	}
+	dbc := getConfig(ctx)
+	dbc.Dir = nodeConfig.ResolvePath(dbc.Dir)
+	dbc.CompressedTables = ctx.StringSlice(utils.CompressedTablesFlag.Name)
+	dbm := database.NewDBManager(dbc)
+	defer dbm.Close()
+
+	count, err := dbm.MigrateCompression()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Rewrote %d values, compressed tables: %v\n", count, dbc.CompressedTables)
+	return nil
+}
+
+func rebuildBloomBits(ctx *cli.Context) error {
+	nodeConfig := &node.Config{
+		DataDir:      utils.MakeDataDir(ctx),
+		ChainDataDir: ctx.String(utils.ChainDataDirFlag.Name),
+		Name:         utils.ClientIdentifier,
+	}
+	dbc := getConfig(ctx)
+	dbc.Dir = nodeConfig.ResolvePath(dbc.Dir)
+	dbm := database.NewDBManager(dbc)
+	defer dbm.Close()
+
+	result, err := cn.RebuildBloomBits(dbm, params.BloomBitsBlocks)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Rebuilt %d bloom bits sections, repaired %d: %v\n", result.Sections, len(result.RepairedSections), result.RepairedSections)
+	if len(result.MismatchedBlocks) > 0 {
+		fmt.Printf("Blocks whose header bloom differs from their receipts: %v\n", result.MismatchedBlocks)
+	}
+	if len(result.MissingReceiptsAt) > 0 {
+		fmt.Printf("Blocks without receipts, indexed by their header bloom: %v\n", result.MissingReceiptsAt)
+	}
+	return nil
+}
+
+func downgradeDB(ctx *cli.Context) error {
+	to, err := parseDBVersion(ctx.String(utils.DBDowngradeToFlag.Name))
+	if err != nil {
+		return err
+	}
+
+	nodeConfig := &node.Config{
+		DataDir:      utils.MakeDataDir(ctx),
+		ChainDataDir: ctx.String(utils.ChainDataDirFlag.Name),
+		Name:         utils.ClientIdentifier,
+	}
+	dbc := getConfig(ctx)
+	dbc.Dir = nodeConfig.ResolvePath(dbc.Dir)
+	dbm := database.NewDBManager(dbc)
+	defer dbm.Close()
+
+	if err := blockchain.DowngradeDatabase(dbm, to); err != nil {
+		return err
+	}
+	fmt.Printf("Downgraded the database to v%d\n", to)
+	return nil
+}
+
+// parseDBVersion parses a database version given as "v3" or "3".
+func parseDBVersion(s string) (uint64, error) {
+	if s == "" {
+		return 0, errNoDowngradeTarget
+	}
+	v, err := strconv.ParseUint(strings.TrimPrefix(s, "v"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid database version %q", s)
+	}
+	return v, nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Tests that a node can be started on databases opened with --db.readonly,
+// which have never been opened by a writable node since init.
+func TestDBReadOnly(t *testing.T) {
+	datadir := tmpdir(t)
+	defer os.RemoveAll(datadir)
+	genesis := customGenesisTests[len(customGenesisTests)-1]
+
+	json := filepath.Join(datadir, "genesis.json")
+	if err := os.WriteFile(json, []byte(genesis.genesis), 0o600); err != nil {
+		t.Fatalf("failed to write genesis file: %v", err)
+	}
+	runKaia(t, "kaia-test", "--datadir", datadir, "--verbosity", "0", "init", json).WaitExit()
+
+	// The database version and the chain config are not written on startup, so init is the last writer.
+	chaindata := filepath.Join(datadir, "klay", "chaindata")
+	before := dirModTimes(t, chaindata)
+
+	kaia := runKaia(t,
+		"kaia-test", "--datadir", datadir, "--maxconnections", "0", "--port", "0",
+		"--nodiscover", "--nat", "none", "--ipcdisable", "--ntp.disable", "--db.readonly",
+		"--exec", "klay.chainId", "--verbosity", "0", "console")
+	kaia.ExpectRegexp(genesis.result[3])
+	kaia.ExpectExit()
+
+	if after := dirModTimes(t, chaindata); len(after) != len(before) {
+		t.Errorf("files were created in the read-only databases: before %v, after %v", before, after)
+	} else {
+		for path, modTime := range before {
+			if after[path] != modTime && filepath.Ext(path) == ".ldb" {
+				t.Errorf("table %s was modified in the read-only databases", path)
+			}
+		}
+	}
+}
+
+// dirModTimes returns the modification times of the files under dir.
+func dirModTimes(t *testing.T, dir string) map[string]int64 {
+	modTimes := make(map[string]int64)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			modTimes[path] = info.ModTime().UnixNano()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return modTimes
+}
@@ -0,0 +1,384 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/bt51/ntpclient"
+	"github.com/kaiachain/kaia/cmd/utils"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/fdlimit"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/crypto/bls"
+	"github.com/kaiachain/kaia/node"
+	"github.com/urfave/cli/v2"
+)
+
+// The requirements checked by kcn doctor.
+const (
+	doctorMinDiskThroughput = 200 << 20              // bytes per second of sequential writes
+	doctorMaxSyncLatency    = 10 * time.Millisecond  // 99th percentile of fsync
+	doctorMaxClockDrift     = 100 * time.Millisecond // consensus messages carry timestamps
+	doctorMinOpenFiles      = 8192
+	doctorMinFreeSpace      = 100 << 30 // bytes
+	doctorSyncSamples       = 100
+)
+
+type doctorStatus string
+
+const (
+	doctorPass doctorStatus = "PASS"
+	doctorWarn doctorStatus = "WARN"
+	doctorFail doctorStatus = "FAIL"
+)
+
+var errDoctorFailed = errors.New("some checks failed")
+
+var DoctorCommand = &cli.Command{
+	Name:     "doctor",
+	Usage:    "Check whether the host and the configuration meet the requirements of a node",
+	Category: "MISCELLANEOUS COMMANDS",
+	Action:   utils.MigrateFlags(runDoctor),
+	Flags:    utils.DoctorFlags,
+	Description: `
+kcn doctor [--datadir <dir>] [node flags]
+checks the disk throughput and fsync latency, the clock sync, the open file
+limit, the ports the node would listen on, the data directory and the node
+keys, and prints a pass/fail report with remediation hints. Pass the same
+flags as the node to check its configuration. Run it while the node is
+stopped, since a running node holds the ports. It exits with an error if any
+check fails.`,
+}
+
+// doctorResult is the outcome of a check.
+type doctorResult struct {
+	Name   string
+	Status doctorStatus
+	Detail string
+	Hint   string // how to remedy a warning or a failure
+}
+
+func runDoctor(ctx *cli.Context) error {
+	var (
+		config = &node.Config{
+			DataDir:      utils.MakeDataDir(ctx),
+			ChainDataDir: ctx.String(utils.ChainDataDirFlag.Name),
+			Name:         utils.ClientIdentifier,
+		}
+		diskSize = int64(ctx.Int(utils.DoctorDiskSizeFlag.Name)) << 20
+		results  []doctorResult
+	)
+
+	results = append(results, checkDataDir(config.DataDir))
+	results = append(results, checkDisk(config.DataDir, diskSize)...)
+	if ctx.Bool(utils.DoctorOfflineFlag.Name) {
+		results = append(results, doctorResult{Name: "clock sync", Status: doctorWarn, Detail: "skipped (--doctor.offline)"})
+	} else {
+		results = append(results, checkClock(ctx.String(utils.NtpServerFlag.Name)))
+	}
+	results = append(results, checkOpenFiles())
+	results = append(results, checkPorts(doctorPorts(ctx))...)
+	results = append(results, checkNodeKey(config, ctx.String(utils.NodeKeyFileFlag.Name), ctx.String(utils.NodeKeyHexFlag.Name)))
+	results = append(results, checkBlsKey(config, ctx.String(utils.BlsNodeKeyFileFlag.Name), ctx.String(utils.BlsNodeKeyHexFlag.Name)))
+
+	if failed := printDoctorReport(os.Stdout, results); failed > 0 {
+		return fmt.Errorf("%w: %d of %d", errDoctorFailed, failed, len(results))
+	}
+	return nil
+}
+
+// printDoctorReport prints the results and returns the number of failures.
+func printDoctorReport(w io.Writer, results []doctorResult) int {
+	failed := 0
+	for _, r := range results {
+		fmt.Fprintf(w, "[%s] %-16s %s\n", r.Status, r.Name, r.Detail)
+		if r.Status != doctorPass && r.Hint != "" {
+			fmt.Fprintf(w, "       %-16s hint: %s\n", "", r.Hint)
+		}
+		if r.Status == doctorFail {
+			failed++
+		}
+	}
+	return failed
+}
+
+// checkDataDir checks that the data directory is writable and has enough free space.
+func checkDataDir(dir string) doctorResult {
+	r := doctorResult{Name: "datadir"}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		r.Status, r.Detail, r.Hint = doctorFail, err.Error(), "set --datadir to a directory the node user owns"
+		return r
+	}
+	f, err := os.CreateTemp(dir, "doctor-*")
+	if err != nil {
+		r.Status, r.Detail, r.Hint = doctorFail, "not writable: "+err.Error(), "set --datadir to a directory the node user owns"
+		return r
+	}
+	f.Close()
+	os.Remove(f.Name())
+
+	free, err := freeDiskSpace(dir)
+	if err != nil {
+		r.Status, r.Detail = doctorWarn, fmt.Sprintf("%s, free space unknown: %v", dir, err)
+		return r
+	}
+	r.Status, r.Detail = doctorPass, fmt.Sprintf("%s, %s free", dir, common.StorageSize(free))
+	if free < doctorMinFreeSpace {
+		r.Status = doctorWarn
+		r.Hint = fmt.Sprintf("keep at least %s free for the chain data to grow", common.StorageSize(doctorMinFreeSpace))
+	}
+	return r
+}
+
+// checkDisk measures the sequential write throughput and the fsync latency of the disk holding dir.
+func checkDisk(dir string, size int64) []doctorResult {
+	throughput := doctorResult{Name: "disk throughput"}
+	latency := doctorResult{Name: "disk latency"}
+
+	bps, syncs, err := measureDisk(dir, size, doctorSyncSamples)
+	if err != nil {
+		throughput.Status, throughput.Detail = doctorFail, err.Error()
+		latency.Status, latency.Detail = doctorFail, err.Error()
+		return []doctorResult{throughput, latency}
+	}
+
+	throughput.Status = doctorPass
+	throughput.Detail = fmt.Sprintf("%s/s sequential write", common.StorageSize(bps))
+	if bps < doctorMinDiskThroughput {
+		throughput.Status = doctorFail
+		throughput.Hint = fmt.Sprintf("needs %s/s; use a local NVMe SSD for the datadir", common.StorageSize(doctorMinDiskThroughput))
+	}
+
+	slices.Sort(syncs)
+	p99 := syncs[len(syncs)*99/100]
+	latency.Status = doctorPass
+	latency.Detail = fmt.Sprintf("fsync p50 %v, p99 %v", syncs[len(syncs)/2], p99)
+	if p99 > doctorMaxSyncLatency {
+		latency.Status = doctorFail
+		latency.Hint = fmt.Sprintf("needs fsync p99 under %v; avoid network-attached or burstable volumes", doctorMaxSyncLatency)
+	}
+	return []doctorResult{throughput, latency}
+}
+
+// measureDisk writes size bytes to a temporary file in dir and syncs it, then times the given
+// number of small synced writes. It returns the throughput in bytes per second and the fsync latencies.
+func measureDisk(dir string, size int64, samples int) (float64, []time.Duration, error) {
+	f, err := os.CreateTemp(dir, "doctor-disk-*")
+	if err != nil {
+		return 0, nil, err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	buf := make([]byte, 1<<20)
+	for i := range buf {
+		buf[i] = byte(i)
+	}
+	start := time.Now()
+	for written := int64(0); written < size; written += int64(len(buf)) {
+		if _, err := f.Write(buf[:min(int64(len(buf)), size-written)]); err != nil {
+			return 0, nil, err
+		}
+	}
+	if err := f.Sync(); err != nil {
+		return 0, nil, err
+	}
+	bps := float64(size) / time.Since(start).Seconds()
+
+	syncs := make([]time.Duration, samples)
+	for i := range syncs {
+		if _, err := f.WriteAt(buf[:4096], int64(i)*4096); err != nil {
+			return 0, nil, err
+		}
+		start := time.Now()
+		if err := f.Sync(); err != nil {
+			return 0, nil, err
+		}
+		syncs[i] = time.Since(start)
+	}
+	return bps, syncs, nil
+}
+
+// checkClock compares the local clock with the NTP server given as host:port.
+func checkClock(server string) doctorResult {
+	r := doctorResult{Name: "clock sync"}
+	host, port, err := net.SplitHostPort(server)
+	if err != nil {
+		r.Status, r.Detail = doctorFail, fmt.Sprintf("invalid NTP server %q: %v", server, err)
+		return r
+	}
+	portNum, err := strconv.Atoi(port)
+	if err != nil {
+		r.Status, r.Detail = doctorFail, fmt.Sprintf("invalid NTP server %q: %v", server, err)
+		return r
+	}
+	remote, err := ntpclient.GetNetworkTime(host, portNum)
+	if err != nil || remote == nil {
+		r.Status, r.Detail, r.Hint = doctorWarn, fmt.Sprintf("cannot reach %s: %v", server, err), "allow outbound UDP 123 or set --ntp.server"
+		return r
+	}
+	drift := clockDrift(time.Now(), *remote)
+	r.Status, r.Detail = doctorPass, fmt.Sprintf("drift %v from %s", drift, server)
+	if drift > doctorMaxClockDrift {
+		r.Status = doctorFail
+		r.Hint = "enable time synchronization, e.g. chrony or systemd-timesyncd"
+	}
+	return r
+}
+
+func clockDrift(local, remote time.Time) time.Duration {
+	drift := local.Sub(remote)
+	if drift < 0 {
+		drift = -drift
+	}
+	return drift.Round(time.Millisecond)
+}
+
+// checkOpenFiles checks the open file limit that the node can raise itself to.
+func checkOpenFiles() doctorResult {
+	r := doctorResult{Name: "open files"}
+	limit, err := fdlimit.Maximum()
+	if err != nil {
+		r.Status, r.Detail = doctorWarn, err.Error()
+		return r
+	}
+	r.Status, r.Detail = doctorPass, fmt.Sprintf("limit %d", limit)
+	if limit < doctorMinOpenFiles {
+		r.Status = doctorFail
+		r.Hint = fmt.Sprintf("needs %d; raise the hard limit with 'ulimit -n' or LimitNOFILE in the systemd unit", doctorMinOpenFiles)
+	}
+	return r
+}
+
+// doctorPort is a port the node listens on.
+type doctorPort struct {
+	name    string
+	network string
+	addr    string
+}
+
+func doctorPorts(ctx *cli.Context) []doctorPort {
+	p2p := fmt.Sprintf(":%d", ctx.Int(utils.ListenPortFlag.Name))
+	ports := []doctorPort{{"p2p", "tcp", p2p}, {"discovery", "udp", p2p}}
+	if ctx.Bool(utils.RPCEnabledFlag.Name) {
+		ports = append(ports, doctorPort{"rpc", "tcp", net.JoinHostPort(ctx.String(utils.RPCListenAddrFlag.Name), strconv.Itoa(ctx.Int(utils.RPCPortFlag.Name)))})
+	}
+	if ctx.Bool(utils.WSEnabledFlag.Name) {
+		ports = append(ports, doctorPort{"ws", "tcp", net.JoinHostPort(ctx.String(utils.WSListenAddrFlag.Name), strconv.Itoa(ctx.Int(utils.WSPortFlag.Name)))})
+	}
+	return ports
+}
+
+// checkPorts checks that the node can listen on the ports. It cannot tell whether the ports are
+// reachable from outside, which depends on the firewall and the NAT in front of the host.
+func checkPorts(ports []doctorPort) []doctorResult {
+	var results []doctorResult
+	for _, p := range ports {
+		r := doctorResult{Name: p.name + " port", Status: doctorPass, Detail: fmt.Sprintf("%s %s available", p.network, p.addr)}
+		var err error
+		if p.network == "udp" {
+			var conn net.PacketConn
+			if conn, err = net.ListenPacket(p.network, p.addr); err == nil {
+				conn.Close()
+			}
+		} else {
+			var l net.Listener
+			if l, err = net.Listen(p.network, p.addr); err == nil {
+				l.Close()
+			}
+		}
+		if err != nil {
+			r.Status, r.Detail = doctorFail, fmt.Sprintf("%s %s: %v", p.network, p.addr, err)
+			r.Hint = "stop the process holding the port or choose another port; open it in the firewall for peers"
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// checkNodeKey checks that the node key given by the flags or in the datadir is loadable.
+// A missing key is a failure, since the node would generate a new one with another address.
+func checkNodeKey(config *node.Config, file, keyHex string) doctorResult {
+	r := doctorResult{Name: "node key"}
+	var (
+		key *ecdsa.PrivateKey
+		err error
+	)
+	switch {
+	case keyHex != "":
+		key, err = crypto.HexToECDSA(keyHex)
+	case file != "":
+		key, err = crypto.LoadECDSA(file)
+	default:
+		file = config.ResolvePath("nodekey")
+		if !common.FileExist(file) {
+			r.Status, r.Detail = doctorFail, "missing "+file
+			r.Hint = "restore the node key of the validator, or set --nodekey; otherwise a new key with another address is generated"
+			return r
+		}
+		key, err = crypto.LoadECDSA(file)
+	}
+	if err != nil {
+		r.Status, r.Detail, r.Hint = doctorFail, err.Error(), "the node key must be a hex-encoded secp256k1 private key"
+		return r
+	}
+	r.Status, r.Detail = doctorPass, "address "+crypto.PubkeyToAddress(key.PublicKey).Hex()
+	return r
+}
+
+// checkBlsKey checks that the BLS key given by the flags or in the datadir is loadable.
+// A missing key is a warning, since the node derives it from the node key.
+func checkBlsKey(config *node.Config, file, keyHex string) doctorResult {
+	r := doctorResult{Name: "bls key"}
+	var (
+		key bls.SecretKey
+		err error
+	)
+	switch {
+	case keyHex != "":
+		var b []byte
+		if b, err = hex.DecodeString(keyHex); err == nil {
+			key, err = bls.SecretKeyFromBytes(b)
+		}
+	case file != "":
+		key, err = bls.LoadKey(file)
+	default:
+		file = config.ResolvePath(node.DatadirBlsSecretKey)
+		if !common.FileExist(file) {
+			r.Status, r.Detail = doctorWarn, "missing "+file
+			r.Hint = "the key will be derived from the node key; restore it if the validator registered another one"
+			return r
+		}
+		key, err = bls.LoadKey(file)
+	}
+	if err != nil {
+		r.Status, r.Detail, r.Hint = doctorFail, err.Error(), "the BLS key must be a hex-encoded BLS12-381 secret key"
+		return r
+	}
+	r.Status, r.Detail = doctorPass, "public key "+common.Bytes2Hex(key.PublicKey().Marshal())
+	return r
+}
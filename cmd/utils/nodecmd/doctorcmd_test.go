@@ -0,0 +1,100 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/node"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoctorDisk(t *testing.T) {
+	dir := t.TempDir()
+
+	bps, syncs, err := measureDisk(dir, 1<<20, 10)
+	require.NoError(t, err)
+	assert.Greater(t, bps, 0.0)
+	assert.Len(t, syncs, 10)
+
+	// The temporary file is removed.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	assert.NotEqual(t, doctorFail, checkDataDir(filepath.Join(dir, "new")).Status) // may warn about the free space
+	assert.Equal(t, doctorFail, checkDataDir(filepath.Join(dir, "missing", "\x00")).Status)
+}
+
+func TestDoctorPorts(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	results := checkPorts([]doctorPort{
+		{"rpc", "tcp", l.Addr().String()},
+		{"discovery", "udp", "127.0.0.1:0"},
+	})
+	require.Len(t, results, 2)
+	assert.Equal(t, doctorFail, results[0].Status)
+	assert.NotEmpty(t, results[0].Hint)
+	assert.Equal(t, doctorPass, results[1].Status)
+}
+
+func TestDoctorNodeKey(t *testing.T) {
+	config := &node.Config{DataDir: t.TempDir(), Name: "kcn"}
+
+	// A missing key fails, since another one would be generated.
+	r := checkNodeKey(config, "", "")
+	assert.Equal(t, doctorFail, r.Status)
+
+	key, _ := crypto.GenerateKey()
+	require.NoError(t, os.MkdirAll(filepath.Join(config.DataDir, "kcn"), 0o700))
+	require.NoError(t, crypto.SaveECDSA(config.ResolvePath("nodekey"), key))
+	r = checkNodeKey(config, "", "")
+	assert.Equal(t, doctorPass, r.Status)
+	assert.Contains(t, r.Detail, crypto.PubkeyToAddress(key.PublicKey).Hex())
+
+	r = checkNodeKey(config, "", common.Bytes2Hex(crypto.FromECDSA(key)))
+	assert.Equal(t, doctorPass, r.Status)
+	assert.Equal(t, doctorFail, checkNodeKey(config, "", "xyz").Status)
+
+	// A missing BLS key is derived from the node key.
+	assert.Equal(t, doctorWarn, checkBlsKey(config, "", "").Status)
+}
+
+func TestDoctorReport(t *testing.T) {
+	var buf bytes.Buffer
+	failed := printDoctorReport(&buf, []doctorResult{
+		{Name: "open files", Status: doctorPass, Detail: "limit 65535"},
+		{Name: "clock sync", Status: doctorFail, Detail: "drift 2s", Hint: "enable time synchronization"},
+	})
+	assert.Equal(t, 1, failed)
+	assert.Contains(t, buf.String(), "[PASS] open files")
+	assert.Contains(t, buf.String(), "[FAIL] clock sync")
+	assert.Contains(t, buf.String(), "hint: enable time synchronization")
+
+	assert.Equal(t, 2*time.Second, clockDrift(time.Unix(100, 0), time.Unix(102, 0)))
+}
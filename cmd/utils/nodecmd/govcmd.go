@@ -0,0 +1,425 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/kaiachain/kaia/blockchain"
+	"github.com/kaiachain/kaia/blockchain/state"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/cmd/utils"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/consensus"
+	"github.com/kaiachain/kaia/kaiax/gov"
+	contractgovimpl "github.com/kaiachain/kaia/kaiax/gov/contractgov/impl"
+	"github.com/kaiachain/kaia/kaiax/gov/headergov"
+	headergovimpl "github.com/kaiachain/kaia/kaiax/gov/headergov/impl"
+	govimpl "github.com/kaiachain/kaia/kaiax/gov/impl"
+	"github.com/kaiachain/kaia/node"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	errNoHistoryFile = errors.New("the exported history file is not given")
+	errGovNotIndexed = errors.New("the governance data is not indexed; run the node once with this version")
+)
+
+var GovCommand = &cli.Command{
+	Name:     "gov",
+	Usage:    "A set of commands for the governance history",
+	Category: "MISCELLANEOUS COMMANDS",
+	Subcommands: []*cli.Command{
+		{
+			Name:      "export",
+			Usage:     "Export the governance history of a running node",
+			ArgsUsage: "[endpoint]",
+			Action:    utils.MigrateFlags(exportGovHistory),
+			Flags:     utils.GovHistoryFlags,
+			Description: `
+kcn gov export [--gov.out <file>] [endpoint]
+connects to a running node and writes its governance history up to the latest
+block as canonical JSON: the header governance votes and governances with the
+raw header fields, the GovParam contract records and the effective parameters.
+The node must have finished scanning the votes of the old epochs.`,
+		},
+		{
+			Name:      "import",
+			Usage:     "Seed a node or a test network genesis with an exported governance history",
+			ArgsUsage: "<file> [endpoint]",
+			Action:    utils.MigrateFlags(importGovHistory),
+			Flags:     utils.GovHistoryFlags,
+			Description: `
+kcn gov import <file> [endpoint]
+connects to a running node of the exported chain and indexes the exported votes
+and governances after checking them against its headers, so that the node does
+not have to scan the headers of all epochs for votes.
+
+kcn gov import --gov.genesis <genesis.json> <file>
+writes the effective parameters of the history into the governance
+configuration of a test network genesis before 'kcn init'. The governing node
+and the GovParam contract of the genesis are kept.
+
+kcn gov import --gov.genesis <genesis.json> --gov.timeline <file>
+writes the parameters at the genesis of the exported chain instead, and
+schedules the later parameter changes in the genesis governance data at the
+blocks where they took effect, so that the test network follows the same
+governance timeline.`,
+		},
+		{
+			Name:      "inspect",
+			Usage:     "Print the effective governance parameters of a block from the chain database",
+			ArgsUsage: "[block]",
+			Action:    utils.MigrateFlags(inspectGov),
+			Flags:     utils.SnapshotFlags,
+			Description: `
+kcn gov inspect --datadir <dir> [block]
+opens the chain database read-only, without starting a node, and prints the
+effective governance parameters of the block (default: the head block) along
+with the source of each value: the default value, header governance, the
+GovParam contract, or a registered source such as the param registry. The node must be stopped unless the
+database supports concurrent readers, and it must have run with this version
+once so that the governance data is indexed.`,
+		},
+	},
+}
+
+func exportGovHistory(ctx *cli.Context) error {
+	client, err := dialRPC(rpcEndpoint(ctx))
+	if err != nil {
+		return fmt.Errorf("unable to attach to remote node: %v", err)
+	}
+	defer client.Close()
+
+	var history gov.HistoryExport
+	if err := client.Call(&history, "governance_exportHistory"); err != nil {
+		return err
+	}
+	enc, err := json.MarshalIndent(&history, "", "  ")
+	if err != nil {
+		return err
+	}
+	enc = append(enc, '\n')
+
+	out := ctx.String(utils.GovHistoryOutFlag.Name)
+	if out == "" {
+		_, err = os.Stdout.Write(enc)
+		return err
+	}
+	if err := os.WriteFile(out, enc, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("Exported %d votes and %d governances up to block %d to %s\n",
+		len(history.Votes), len(history.Governances), history.Head, out)
+	return nil
+}
+
+func importGovHistory(ctx *cli.Context) error {
+	path := ctx.Args().First()
+	if path == "" {
+		return errNoHistoryFile
+	}
+	history, err := readGovHistory(path)
+	if err != nil {
+		return err
+	}
+
+	if genesisPath := ctx.String(utils.GovHistoryGenesisFlag.Name); genesisPath != "" {
+		return seedGenesisFile(genesisPath, history, ctx.Bool(utils.GovHistoryTimelineFlag.Name))
+	}
+
+	endpoint := ctx.Args().Get(1)
+	if endpoint == "" {
+		endpoint = ipcEndpoint(ctx)
+	}
+	client, err := dialRPC(endpoint)
+	if err != nil {
+		return fmt.Errorf("unable to attach to remote node: %v", err)
+	}
+	defer client.Close()
+
+	var res govimpl.ImportResult
+	if err := client.Call(&res, "governance_importHistory", history); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d records, skipped %d records of blocks the node does not have yet\n", res.Imported, res.Skipped)
+	if res.GovParamVerified {
+		fmt.Println("The GovParam records match the local contract.")
+	}
+	return nil
+}
+
+func readGovHistory(path string) (*gov.HistoryExport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	history := new(gov.HistoryExport)
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, fmt.Errorf("%w: %v", gov.ErrInvalidHistory, err)
+	}
+	return history, history.Validate()
+}
+
+func seedGenesisFile(path string, history *gov.HistoryExport, timeline bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	genesis := new(blockchain.Genesis)
+	if err := json.Unmarshal(data, genesis); err != nil {
+		return fmt.Errorf("invalid genesis file: %v", err)
+	}
+	if timeline {
+		err = seedGenesisTimeline(genesis, history)
+	} else {
+		err = seedGenesis(genesis, history)
+	}
+	if err != nil {
+		return err
+	}
+	enc, err := json.MarshalIndent(genesis, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(enc, '\n'), 0o644); err != nil {
+		return err
+	}
+	if timeline {
+		fmt.Printf("Applied the governance timeline of %d changes up to block %d to %s\n", len(history.Changes)-1, history.Head, path)
+	} else {
+		fmt.Printf("Applied the governance parameters at block %d to %s\n", history.Head, path)
+	}
+	return nil
+}
+
+// seedGenesis overwrites the governance configuration of genesis with the effective parameters
+// of the history. The governing node and the GovParam contract of genesis are kept, because those
+// of the exported chain are accounts that do not exist in the test network; without a governance
+// configuration in genesis, the exported governing node is used and the GovParam contract is unset.
+func seedGenesis(genesis *blockchain.Genesis, history *gov.HistoryExport) error {
+	ps, err := history.EffectiveParamSet()
+	if err != nil {
+		return err
+	}
+	return seedGenesisParams(genesis, ps)
+}
+
+// seedGenesisTimeline seeds genesis with the parameters at the genesis of the history, and schedules
+// the later changes in the genesis governance data at their activation blocks. The governing node and
+// the GovParam contract are never scheduled, for the same reason as in seedGenesis.
+func seedGenesisTimeline(genesis *blockchain.Genesis, history *gov.HistoryExport) error {
+	ps, err := history.ParamSetAt(0)
+	if err != nil {
+		return err
+	}
+	if err := seedGenesisParams(genesis, ps); err != nil {
+		return err
+	}
+
+	scheduled := make(map[uint64]gov.PartialParamSet)
+	for _, c := range history.Changes[1:] {
+		partial, err := c.PartialParamSet()
+		if err != nil {
+			return err
+		}
+		delete(partial, gov.GovernanceGoverningNode)
+		delete(partial, gov.GovernanceGovParamContract)
+		if len(partial) > 0 {
+			scheduled[c.Block] = partial
+		}
+	}
+
+	// The header governance falls back to the defaults rather than the chain config, so the genesis
+	// governance data must hold every parameter at genesis.
+	if genesis.Config.Istanbul == nil {
+		return errors.New("genesis istanbul config is not set")
+	}
+	base, err := headergov.GovBytes(blockchain.SetGenesisGovernance(genesis)).ToGovData()
+	if err != nil {
+		return err
+	}
+	items := base.Items()
+	for name, value := range ps.ToMap() {
+		if _, ok := items[name]; !ok && name != gov.GovernanceGovParamContract {
+			items[name] = value
+		}
+	}
+	data := headergov.NewScheduledGovData(items, scheduled)
+	if data == nil {
+		return fmt.Errorf("%w: the changes cannot be scheduled in the genesis", gov.ErrInvalidHistory)
+	}
+	genesis.Governance, err = data.ToGovBytes()
+	return err
+}
+
+// seedGenesisParams overwrites the governance configuration of genesis with ps, keeping the governing
+// node and the GovParam contract of genesis.
+func seedGenesisParams(genesis *blockchain.Genesis, ps gov.ParamSet) error {
+	if genesis.Config == nil {
+		return errors.New("genesis config is not set")
+	}
+	seeded := ps.ToGovParamSet().ToChainConfig()
+
+	config := genesis.Config
+	if config.Governance != nil {
+		seeded.Governance.GoverningNode = config.Governance.GoverningNode
+		seeded.Governance.GovParamContract = config.Governance.GovParamContract
+	} else {
+		seeded.Governance.GovParamContract = common.Address{}
+	}
+	config.Governance = seeded.Governance
+	config.UnitPrice = seeded.UnitPrice
+	config.DeriveShaImpl = seeded.DeriveShaImpl
+	if config.Istanbul != nil {
+		config.Istanbul.Epoch = seeded.Istanbul.Epoch
+		config.Istanbul.ProposerPolicy = seeded.Istanbul.ProposerPolicy
+		config.Istanbul.SubGroupSize = seeded.Istanbul.SubGroupSize
+	}
+	return nil
+}
+
+func inspectGov(ctx *cli.Context) error {
+	nodeConfig := &node.Config{
+		DataDir:      utils.MakeDataDir(ctx),
+		ChainDataDir: ctx.String(utils.ChainDataDirFlag.Name),
+		Name:         utils.ClientIdentifier,
+	}
+	dbc := getConfig(ctx)
+	dbc.Dir = nodeConfig.ResolvePath(dbc.Dir)
+	dbc.ReadOnly = true
+	dbm := database.NewDBManager(dbc)
+	defer dbm.Close()
+
+	m, chain, err := newInspectGovModule(dbm)
+	if err != nil {
+		return err
+	}
+	head := chain.CurrentHeader().Number.Uint64()
+	num := head
+	if arg := ctx.Args().First(); arg != "" && arg != "latest" {
+		if num, err = strconv.ParseUint(arg, 10, 64); err != nil {
+			return fmt.Errorf("invalid block number %q", arg)
+		}
+	}
+
+	ps, sources := m.EffectiveParamSources(num)
+	values := ps.ToMap()
+	names := make([]gov.ParamName, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	fmt.Printf("Effective governance parameters at block %d (head %d)\n", num, head)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tVALUE\tSOURCE")
+	for _, name := range names {
+		fmt.Fprintf(w, "%s\t%v\t%s\n", name, values[name], sources[name])
+	}
+	return w.Flush()
+}
+
+// inspectEngine serves the block author to the EVM of the GovParam calls, which do not depend on it.
+type inspectEngine struct {
+	consensus.Engine
+}
+
+func (inspectEngine) Author(*types.Header) (common.Address, error) {
+	return common.Address{}, nil
+}
+
+// inspectChain serves the governance modules from the chain database without a blockchain.
+type inspectChain struct {
+	*blockchain.HeaderChain
+	db database.DBManager
+}
+
+func (c *inspectChain) CurrentBlock() *types.Block {
+	head := c.CurrentHeader()
+	return c.db.ReadBlock(head.Hash(), head.Number.Uint64())
+}
+
+func (c *inspectChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	return c.db.ReadBlock(hash, number)
+}
+
+func (c *inspectChain) GetReceiptsByBlockHash(hash common.Hash) types.Receipts {
+	return c.db.ReadReceiptsByBlockHash(hash)
+}
+
+func (c *inspectChain) StateAt(root common.Hash) (*state.StateDB, error) {
+	return state.New(root, state.NewDatabase(c.db), nil, nil)
+}
+
+func (c *inspectChain) State() (*state.StateDB, error) {
+	return c.StateAt(c.CurrentHeader().Root)
+}
+
+// newInspectGovModule returns a governance module reading the chain database. The module is
+// not started, so only its getters are usable and nothing is written to the database.
+func newInspectGovModule(dbm database.DBManager) (*govimpl.GovModule, *inspectChain, error) {
+	config := dbm.ReadChainConfig(dbm.ReadCanonicalHash(0))
+	if config == nil {
+		return nil, nil, errors.New("no chain config in the database")
+	}
+	hc, err := blockchain.NewHeaderChain(dbm, config, inspectEngine{}, func() bool { return false })
+	if err != nil {
+		return nil, nil, err
+	}
+	chain := &inspectChain{HeaderChain: hc, db: dbm}
+
+	// The header governance module builds the missing index on init, which cannot be written read-only.
+	misc := dbm.GetMiscDB()
+	if headergovimpl.ReadGovDataBlockNums(misc) == nil || headergovimpl.ReadLowestVoteScannedBlockNum(misc) == nil {
+		return nil, nil, errGovNotIndexed
+	}
+
+	var (
+		hgm = headergovimpl.NewHeaderGovModule()
+		cgm = contractgovimpl.NewContractGovModule()
+		m   = govimpl.NewGovModule()
+	)
+	err = errors.Join(
+		hgm.Init(&headergovimpl.InitOpts{
+			ChainKv:     misc,
+			ChainConfig: config,
+			Chain:       chain,
+		}),
+		cgm.Init(&contractgovimpl.InitOpts{
+			ChainConfig: config,
+			Chain:       chain,
+			Hgm:         hgm,
+		}),
+		m.Init(&govimpl.InitOpts{
+			Hgm:   hgm,
+			Cgm:   cgm,
+			Chain: chain,
+		}),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return m, chain, nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kaiachain/kaia/blockchain"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/gov"
+	"github.com/kaiachain/kaia/kaiax/gov/headergov"
+	headergovimpl "github.com/kaiachain/kaia/kaiax/gov/headergov/impl"
+	govimpl "github.com/kaiachain/kaia/kaiax/gov/impl"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeedGenesis(t *testing.T) {
+	exported := gov.GetDefaultGovernanceParamSet()
+	exported.UnitPrice = 25e9
+	exported.Epoch = 3600
+	exported.MintingAmount, _ = new(big.Int).SetString("9600000000000000000", 10)
+	exported.GoverningNode = common.HexToAddress("0xaaaa")
+	exported.GovParamContract = common.HexToAddress("0xbbbb")
+
+	effective := make(gov.PartialParamSet)
+	for name, value := range exported.ToMap() {
+		effective[name] = value
+	}
+	history := &gov.HistoryExport{
+		Version:   gov.HistoryVersion,
+		Head:      100,
+		Epoch:     3600,
+		Effective: gov.HistoryParams(effective),
+	}
+
+	// Round trip through the file, as the command does.
+	path := filepath.Join(t.TempDir(), "gov.json")
+	enc, err := json.Marshal(history)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, enc, 0o644))
+	history, err = readGovHistory(path)
+	require.NoError(t, err)
+
+	governingNode := common.HexToAddress("0x1111")
+	genesis := &blockchain.Genesis{Config: &params.ChainConfig{
+		ChainID:    big.NewInt(1000),
+		Istanbul:   &params.IstanbulConfig{Epoch: 30},
+		Governance: &params.GovernanceConfig{GoverningNode: governingNode},
+	}}
+	require.NoError(t, seedGenesis(genesis, history))
+
+	config := genesis.Config
+	assert.Equal(t, uint64(25e9), config.UnitPrice)
+	assert.Equal(t, uint64(3600), config.Istanbul.Epoch)
+	assert.Equal(t, exported.MintingAmount, config.Governance.Reward.MintingAmount)
+	assert.Equal(t, governingNode, config.Governance.GoverningNode)
+	assert.Equal(t, common.Address{}, config.Governance.GovParamContract)
+	assert.Equal(t, big.NewInt(1000), config.ChainID)
+}
+
+func TestInspectGovModule(t *testing.T) {
+	config := &params.ChainConfig{
+		ChainID:    big.NewInt(1000),
+		UnitPrice:  25e9,
+		Istanbul:   params.GetDefaultIstanbulConfig(),
+		Governance: params.GetDefaultGovernanceConfig(),
+	}
+	config.Governance.GovernanceMode = "single"
+	genesis := &blockchain.Genesis{Config: config, BlockScore: big.NewInt(1)}
+	genesis.Governance = blockchain.SetGenesisGovernance(genesis)
+
+	dbm := database.NewMemoryDBManager()
+	genesis.MustCommit(dbm)
+
+	// The governance data must have been indexed by a node.
+	_, _, err := newInspectGovModule(dbm)
+	assert.ErrorIs(t, err, errGovNotIndexed)
+
+	headergovimpl.WriteGovDataBlockNums(dbm.GetMiscDB(), headergovimpl.StoredUint64Array{0})
+	headergovimpl.WriteLowestVoteScannedBlockNum(dbm.GetMiscDB(), 0)
+
+	m, chain, err := newInspectGovModule(dbm)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), chain.CurrentHeader().Number.Uint64())
+
+	ps, sources := m.EffectiveParamSources(0)
+	assert.Equal(t, "single", ps.GovernanceMode)
+	assert.Equal(t, uint64(25e9), ps.UnitPrice)
+	assert.Equal(t, govimpl.ParamSourceHeader, sources[gov.GovernanceGovernanceMode])
+	assert.Equal(t, govimpl.ParamSourceHeader, sources[gov.GovernanceUnitPrice])
+	assert.Equal(t, govimpl.ParamSourceDefault, sources[gov.GovernanceMultisigSigners])
+}
+
+func TestSeedGenesisTimeline(t *testing.T) {
+	history := &gov.HistoryExport{
+		Version: gov.HistoryVersion,
+		Head:    5000,
+		Epoch:   3600,
+		Changes: []gov.ParamChange{
+			{Block: 0, Params: map[gov.ParamName]any{
+				gov.GovernanceUnitPrice:     float64(25e9),
+				gov.GovernanceGoverningNode: "0x000000000000000000000000000000000000aaaa",
+				gov.Kip71LowerBoundBaseFee:  float64(25e9),
+			}},
+			{Block: 3601, Params: map[gov.ParamName]any{gov.GovernanceUnitPrice: float64(50e9)}},
+			{Block: 4000, Params: map[gov.ParamName]any{gov.GovernanceGoverningNode: "0x000000000000000000000000000000000000bbbb"}},
+		},
+	}
+	require.NoError(t, history.Validate())
+
+	governingNode := common.HexToAddress("0x1111")
+	genesis := &blockchain.Genesis{Config: &params.ChainConfig{
+		ChainID:    big.NewInt(1000),
+		Istanbul:   &params.IstanbulConfig{Epoch: 30},
+		Governance: &params.GovernanceConfig{GoverningNode: governingNode},
+	}}
+	require.NoError(t, seedGenesisTimeline(genesis, history))
+
+	// The chain config holds the parameters at genesis.
+	assert.Equal(t, uint64(25e9), genesis.Config.UnitPrice)
+	assert.Equal(t, governingNode, genesis.Config.Governance.GoverningNode)
+
+	data, err := headergov.GovBytes(genesis.Governance).ToGovData()
+	require.NoError(t, err)
+	items := data.Items()
+	assert.Equal(t, uint64(25e9), items[gov.GovernanceUnitPrice])
+	assert.Equal(t, uint64(25e9), items[gov.Kip71LowerBoundBaseFee])
+	assert.Equal(t, governingNode, items[gov.GovernanceGoverningNode])
+
+	// The later changes are scheduled, except for the governing node.
+	assert.Equal(t, headergov.ScheduledParams{
+		3601: {gov.GovernanceUnitPrice: uint64(50e9)},
+	}, data.Scheduled())
+}
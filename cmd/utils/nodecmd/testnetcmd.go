@@ -0,0 +1,503 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kaiachain/kaia/blockchain"
+	"github.com/kaiachain/kaia/blockchain/system"
+	istcommon "github.com/kaiachain/kaia/cmd/homi/common"
+	homigenesis "github.com/kaiachain/kaia/cmd/homi/genesis"
+	"github.com/kaiachain/kaia/cmd/utils"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/networks/p2p/discover"
+	"github.com/kaiachain/kaia/params"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	testnetStateFile    = "testnet.json"
+	testnetAccountsFile = "accounts.json"
+
+	testnetStartTimeout = 30 * time.Second
+	testnetStopTimeout  = 10 * time.Second
+
+	testnetRPCAPIs = "kaia,klay,eth,net,web3,txpool,debug,admin,personal,governance,istanbul"
+)
+
+var (
+	errTestnetRunning  = errors.New("the test network is already running")
+	errTestnetNotFound = errors.New("no test network in the directory; run 'kcn testnet up' first")
+	errNoValidators    = errors.New("the test network needs at least one validator")
+	errKenNotFound     = errors.New("ken binary not found; set --testnet.ken")
+
+	// testnetBalance is the genesis balance of the validators and the test accounts.
+	testnetBalance = new(big.Int).Mul(big.NewInt(1e9), big.NewInt(params.KAIA))
+)
+
+var TestnetCommand = &cli.Command{
+	Name:     "testnet",
+	Usage:    "A set of commands to run a local test network",
+	Category: "MISCELLANEOUS COMMANDS",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "up",
+			Usage:  "Create and start a local test network",
+			Action: utils.MigrateFlags(testnetUp),
+			Flags:  utils.TestnetFlags,
+			Description: `
+kcn testnet up [--validators 4] [--ens 2] [--testnet.dir <dir>]
+creates a test network of the given number of CNs and ENs in the directory,
+with a genesis that enables all hardforks at block 0 and funds the validators
+and the generated test accounts, and starts every node as a subprocess that
+listens on 127.0.0.1. The keys of the test accounts are written to
+accounts.json in the directory. The ENs are run by the ken binary, which is
+looked up next to kcn and in PATH unless --testnet.ken is given.
+
+If the directory already holds a stopped test network, its nodes are started
+again with their existing data.`,
+		},
+		{
+			Name:   "status",
+			Usage:  "Show the nodes of the local test network",
+			Action: utils.MigrateFlags(testnetStatus),
+			Flags:  utils.TestnetFlags,
+		},
+		{
+			Name:   "down",
+			Usage:  "Stop the local test network",
+			Action: utils.MigrateFlags(testnetDown),
+			Flags:  utils.TestnetFlags,
+			Description: `
+kcn testnet down [--testnet.clean]
+stops every node of the test network. The data of the nodes is kept so that
+'kcn testnet up' resumes the network, unless --testnet.clean is given.`,
+		},
+	},
+}
+
+// testnetNode is a node of the local test network.
+type testnetNode struct {
+	Name    string         `json:"name"`
+	Type    string         `json:"type"` // "cn" or "en"
+	Address common.Address `json:"address"`
+	Enode   string         `json:"enode"`
+	DataDir string         `json:"datadir"`
+	Port    int            `json:"port"`
+	RPCPort int            `json:"rpcport"`
+	PID     int            `json:"pid"`
+	Log     string         `json:"log"`
+}
+
+// testnetState is the description of the local test network, stored in the test network directory.
+type testnetState struct {
+	ChainID uint64         `json:"chainId"`
+	Kcn     string         `json:"kcn"`
+	Ken     string         `json:"ken,omitempty"`
+	Nodes   []*testnetNode `json:"nodes"`
+}
+
+type testnetAccount struct {
+	Address    common.Address `json:"address"`
+	PrivateKey string         `json:"privateKey"`
+}
+
+func (n *testnetNode) rpcURL() string {
+	return fmt.Sprintf("http://127.0.0.1:%d", n.RPCPort)
+}
+
+// alive returns true if the process of the node exists.
+func (n *testnetNode) alive() bool {
+	if n.PID == 0 {
+		return false
+	}
+	p, err := os.FindProcess(n.PID)
+	if err != nil {
+		return false
+	}
+	return p.Signal(syscall.Signal(0)) == nil
+}
+
+func (s *testnetState) alive() bool {
+	for _, n := range s.Nodes {
+		if n.alive() {
+			return true
+		}
+	}
+	return false
+}
+
+func readTestnetState(dir string) (*testnetState, error) {
+	data, err := os.ReadFile(filepath.Join(dir, testnetStateFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errTestnetNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	state := new(testnetState)
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", testnetStateFile, err)
+	}
+	return state, nil
+}
+
+func writeJSONFile(path string, v any) error {
+	enc, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(enc, '\n'), 0o644)
+}
+
+func testnetUp(ctx *cli.Context) error {
+	dir := ctx.Path(utils.TestnetDirFlag.Name)
+	state, err := readTestnetState(dir)
+	switch {
+	case err == nil && state.alive():
+		return fmt.Errorf("%w in %s; run 'kcn testnet down' first", errTestnetRunning, dir)
+	case err == nil:
+		fmt.Printf("Resuming the test network in %s\n", dir)
+	case errors.Is(err, errTestnetNotFound):
+		if state, err = createTestnet(ctx, dir); err != nil {
+			return err
+		}
+	default:
+		return err
+	}
+
+	for _, n := range state.Nodes {
+		if err := startTestnetNode(state, n); err != nil {
+			return err
+		}
+	}
+	if err := writeJSONFile(filepath.Join(dir, testnetStateFile), state); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(testnetStartTimeout)
+	for _, n := range state.Nodes {
+		for !testnetRPCReady(n) {
+			if time.Now().After(deadline) {
+				return fmt.Errorf("%s did not open its RPC endpoint in %v; see %s", n.Name, testnetStartTimeout, n.Log)
+			}
+			time.Sleep(500 * time.Millisecond)
+		}
+	}
+	printTestnetStatus(state)
+	return nil
+}
+
+// createTestnet writes the genesis, the test accounts and the node keys of a new test network to dir
+// and initializes the data directories of the nodes.
+func createTestnet(ctx *cli.Context, dir string) (*testnetState, error) {
+	var (
+		numCNs      = ctx.Int(utils.TestnetValidatorsFlag.Name)
+		numENs      = ctx.Int(utils.TestnetENsFlag.Name)
+		numAccounts = ctx.Int(utils.TestnetAccountsFlag.Name)
+		chainID     = ctx.Uint64(utils.TestnetChainIDFlag.Name)
+		port        = ctx.Int(utils.TestnetPortFlag.Name)
+		rpcPort     = ctx.Int(utils.TestnetRPCPortFlag.Name)
+	)
+	if numCNs < 1 {
+		return nil, errNoValidators
+	}
+	kcn, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	state := &testnetState{ChainID: chainID, Kcn: kcn}
+	if numENs > 0 {
+		if state.Ken, err = findKen(ctx.Path(utils.TestnetKenFlag.Name), kcn); err != nil {
+			return nil, err
+		}
+	}
+
+	nodeKeys, _, _ := istcommon.GenerateKeys(numCNs + numENs)
+	accountKeys, _, _ := istcommon.GenerateKeys(numAccounts)
+	state.Nodes = makeTestnetNodes(dir, nodeKeys, numCNs, port, rpcPort)
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	genesisPath := filepath.Join(dir, homigenesis.FileName)
+	if err := writeJSONFile(genesisPath, testnetGenesis(nodeKeys[:numCNs], accountKeys, chainID)); err != nil {
+		return nil, err
+	}
+	accounts := make([]testnetAccount, len(accountKeys))
+	for i, key := range accountKeys {
+		accounts[i] = testnetAccount{crypto.PubkeyToAddress(key.PublicKey), hexutil.Encode(crypto.FromECDSA(key))}
+	}
+	if err := writeJSONFile(filepath.Join(dir, testnetAccountsFile), accounts); err != nil {
+		return nil, err
+	}
+
+	var staticNodes []string
+	for _, n := range state.Nodes {
+		if n.Type == "cn" {
+			staticNodes = append(staticNodes, n.Enode)
+		}
+	}
+	for i, n := range state.Nodes {
+		if err := os.MkdirAll(n.DataDir, 0o700); err != nil {
+			return nil, err
+		}
+		if err := crypto.SaveECDSA(filepath.Join(n.DataDir, "nodekey"), nodeKeys[i]); err != nil {
+			return nil, err
+		}
+		if err := writeJSONFile(filepath.Join(n.DataDir, "static-nodes.json"), staticNodes); err != nil {
+			return nil, err
+		}
+		out, err := exec.Command(state.binary(n), "--datadir", n.DataDir, "init", genesisPath).CombinedOutput()
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize %s: %v\n%s", n.Name, err, out)
+		}
+	}
+	fmt.Printf("Created a test network of %d CNs and %d ENs in %s\n", numCNs, numENs, dir)
+	return state, nil
+}
+
+// makeTestnetNodes lays out the nodes of the given keys, where the first numCNs keys are of the CNs.
+// The nodes get consecutive ports from the given base ports.
+func makeTestnetNodes(dir string, keys []*ecdsa.PrivateKey, numCNs, port, rpcPort int) []*testnetNode {
+	nodes := make([]*testnetNode, len(keys))
+	for i, key := range keys {
+		var (
+			name  = fmt.Sprintf("cn%d", i)
+			typ   = "cn"
+			nType = discover.NodeTypeCN
+		)
+		if i >= numCNs {
+			name, typ, nType = fmt.Sprintf("en%d", i-numCNs), "en", discover.NodeTypeEN
+		}
+		id := discover.PubkeyID(&key.PublicKey)
+		nodes[i] = &testnetNode{
+			Name:    name,
+			Type:    typ,
+			Address: crypto.PubkeyToAddress(key.PublicKey),
+			Enode:   discover.NewNode(id, net.IPv4(127, 0, 0, 1), 0, uint16(port+i), nil, nType).String(),
+			DataDir: filepath.Join(dir, name),
+			Port:    port + i,
+			RPCPort: rpcPort + i,
+			Log:     filepath.Join(dir, name+".log"),
+		}
+	}
+	return nodes
+}
+
+// testnetGenesis returns an Istanbul genesis of the given validators with all hardforks enabled at block 0.
+// The first validator is the governing node in the single governance mode.
+func testnetGenesis(validatorKeys, accountKeys []*ecdsa.PrivateKey, chainID uint64) *blockchain.Genesis {
+	var validators, accounts []common.Address
+	for _, key := range validatorKeys {
+		validators = append(validators, crypto.PubkeyToAddress(key.PublicKey))
+	}
+	for _, key := range accountKeys {
+		accounts = append(accounts, crypto.PubkeyToAddress(key.PublicKey))
+	}
+	owner := validators[0]
+
+	govConfig := params.GetDefaultGovernanceConfig()
+	govConfig.GovernanceMode = "single"
+	govConfig.GoverningNode = owner
+
+	kip113Init := istcommon.GenerateKip113Init(validatorKeys, owner)
+	registry := &params.RegistryConfig{
+		Records: map[string]common.Address{system.Kip113Name: system.Kip113ProxyAddrMock},
+		Owner:   owner,
+	}
+
+	g := homigenesis.New(
+		homigenesis.Validators(validators...),
+		homigenesis.Alloc(append(validators, accounts...), testnetBalance),
+		homigenesis.ChainID(new(big.Int).SetUint64(chainID)),
+		homigenesis.UnitPrice(params.DefaultUnitPrice),
+		homigenesis.Governance(govConfig),
+		homigenesis.Istanbul(params.GetDefaultIstanbulConfig()),
+		homigenesis.AllocateKip113(system.Kip113ProxyAddrMock, system.Kip113LogicAddrMock, owner,
+			system.MergeStorage(system.AllocProxy(system.Kip113LogicAddrMock), system.AllocKip113Proxy(kip113Init)),
+			system.AllocKip113Logic()),
+		homigenesis.AllocateRegistry(system.AllocRegistry(registry)),
+	)
+
+	config := g.Config
+	config.IstanbulCompatibleBlock = big.NewInt(0)
+	config.LondonCompatibleBlock = big.NewInt(0)
+	config.EthTxTypeCompatibleBlock = big.NewInt(0)
+	config.MagmaCompatibleBlock = big.NewInt(0)
+	config.KoreCompatibleBlock = big.NewInt(0)
+	config.ShanghaiCompatibleBlock = big.NewInt(0)
+	config.CancunCompatibleBlock = big.NewInt(0)
+	config.KaiaCompatibleBlock = big.NewInt(0)
+	config.RandaoCompatibleBlock = big.NewInt(0)
+	config.PragueCompatibleBlock = big.NewInt(0)
+	return g
+}
+
+// findKen returns the given path, or the ken binary next to kcn or in PATH.
+func findKen(path, kcn string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+	if p := filepath.Join(filepath.Dir(kcn), "ken"); fileExists(p) {
+		return p, nil
+	}
+	if p, err := exec.LookPath("ken"); err == nil {
+		return p, nil
+	}
+	return "", errKenNotFound
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+func (s *testnetState) binary(n *testnetNode) string {
+	if n.Type == "en" {
+		return s.Ken
+	}
+	return s.Kcn
+}
+
+func startTestnetNode(state *testnetState, n *testnetNode) error {
+	args := []string{
+		"--datadir", n.DataDir,
+		"--networkid", fmt.Sprint(state.ChainID),
+		"--port", fmt.Sprint(n.Port),
+		"--nodiscover",
+		"--rpc",
+		"--rpcaddr", "127.0.0.1",
+		"--rpcport", fmt.Sprint(n.RPCPort),
+		"--rpcapi", testnetRPCAPIs,
+	}
+	if n.Type == "cn" {
+		args = append(args, "--rewardbase", n.Address.Hex())
+	}
+
+	logFile, err := os.OpenFile(n.Log, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(state.binary(n), args...)
+	cmd.Stdout, cmd.Stderr = logFile, logFile
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %v", n.Name, err)
+	}
+	n.PID = cmd.Process.Pid
+	return cmd.Process.Release()
+}
+
+func testnetRPCReady(n *testnetNode) bool {
+	client, err := dialRPC(n.rpcURL())
+	if err != nil {
+		return false
+	}
+	defer client.Close()
+	var num hexutil.Uint64
+	return client.Call(&num, "kaia_blockNumber") == nil
+}
+
+func testnetStatus(ctx *cli.Context) error {
+	state, err := readTestnetState(ctx.Path(utils.TestnetDirFlag.Name))
+	if err != nil {
+		return err
+	}
+	printTestnetStatus(state)
+	return nil
+}
+
+func printTestnetStatus(state *testnetState) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTYPE\tPID\tSTATE\tBLOCK\tPEERS\tRPC")
+	for _, n := range state.Nodes {
+		status, block, peers := "exited", "-", "-"
+		if n.alive() {
+			status = "running"
+			if client, err := dialRPC(n.rpcURL()); err == nil {
+				var num, count hexutil.Uint64
+				if client.Call(&num, "kaia_blockNumber") == nil {
+					block = fmt.Sprint(uint64(num))
+				}
+				if client.Call(&count, "net_peerCount") == nil {
+					peers = fmt.Sprint(uint64(count))
+				}
+				client.Close()
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\t%s\n", n.Name, n.Type, n.PID, status, block, peers, n.rpcURL())
+	}
+	w.Flush()
+}
+
+func testnetDown(ctx *cli.Context) error {
+	dir := ctx.Path(utils.TestnetDirFlag.Name)
+	state, err := readTestnetState(dir)
+	if err != nil {
+		return err
+	}
+	for _, n := range state.Nodes {
+		if !n.alive() {
+			continue
+		}
+		if p, err := os.FindProcess(n.PID); err == nil {
+			if err := p.Signal(os.Interrupt); err != nil {
+				p.Kill()
+			}
+		}
+	}
+	deadline := time.Now().Add(testnetStopTimeout)
+	for _, n := range state.Nodes {
+		for n.alive() && time.Now().Before(deadline) {
+			time.Sleep(200 * time.Millisecond)
+		}
+		if n.alive() {
+			if p, err := os.FindProcess(n.PID); err == nil {
+				p.Kill()
+			}
+		}
+		n.PID = 0
+	}
+
+	if ctx.Bool(utils.TestnetCleanFlag.Name) {
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+		fmt.Printf("Stopped and removed the test network in %s\n", dir)
+		return nil
+	}
+	if err := writeJSONFile(filepath.Join(dir, testnetStateFile), state); err != nil {
+		return err
+	}
+	fmt.Printf("Stopped the test network in %s\n", dir)
+	return nil
+}
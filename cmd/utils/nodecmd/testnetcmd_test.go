@@ -0,0 +1,91 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/kaiachain/kaia/blockchain/system"
+	istcommon "github.com/kaiachain/kaia/cmd/homi/common"
+	"github.com/kaiachain/kaia/networks/p2p/discover"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestnetGenesis(t *testing.T) {
+	validatorKeys, _, validators := istcommon.GenerateKeys(4)
+	accountKeys, _, accounts := istcommon.GenerateKeys(2)
+
+	g := testnetGenesis(validatorKeys, accountKeys, 1000)
+
+	config := g.Config
+	assert.Equal(t, uint64(1000), config.ChainID.Uint64())
+	assert.Equal(t, "single", config.Governance.GovernanceMode)
+	assert.Equal(t, validators[0], config.Governance.GoverningNode)
+	assert.True(t, config.IsRandaoForkEnabled(big.NewInt(0)))
+	assert.True(t, config.IsPragueForkEnabled(big.NewInt(0)))
+
+	for _, addr := range append(validators, accounts...) {
+		require.Contains(t, g.Alloc, addr)
+		assert.Equal(t, testnetBalance, g.Alloc[addr].Balance)
+	}
+	assert.Contains(t, g.Alloc, system.Kip113ProxyAddrMock)
+	assert.Contains(t, g.Alloc, system.RegistryAddr)
+}
+
+func TestMakeTestnetNodes(t *testing.T) {
+	keys, _, addrs := istcommon.GenerateKeys(3)
+	dir := t.TempDir()
+
+	nodes := makeTestnetNodes(dir, keys, 2, 32323, 8551)
+	require.Len(t, nodes, 3)
+
+	expected := []struct {
+		name, typ string
+		nType     discover.NodeType
+	}{
+		{"cn0", "cn", discover.NodeTypeCN},
+		{"cn1", "cn", discover.NodeTypeCN},
+		{"en0", "en", discover.NodeTypeEN},
+	}
+	for i, n := range nodes {
+		assert.Equal(t, expected[i].name, n.Name)
+		assert.Equal(t, expected[i].typ, n.Type)
+		assert.Equal(t, addrs[i], n.Address)
+		assert.Equal(t, filepath.Join(dir, expected[i].name), n.DataDir)
+		assert.Equal(t, 32323+i, n.Port)
+		assert.Equal(t, 8551+i, n.RPCPort)
+		assert.False(t, n.alive())
+
+		node, err := discover.ParseNode(n.Enode)
+		require.NoError(t, err)
+		assert.Equal(t, discover.PubkeyID(&keys[i].PublicKey), node.ID)
+		assert.Equal(t, uint16(32323+i), node.TCP)
+		assert.Equal(t, expected[i].nType, node.NType)
+	}
+
+	state := &testnetState{ChainID: 1000, Nodes: nodes}
+	require.NoError(t, writeJSONFile(filepath.Join(dir, testnetStateFile), state))
+	loaded, err := readTestnetState(dir)
+	require.NoError(t, err)
+	assert.Equal(t, state, loaded)
+
+	_, err = readTestnetState(t.TempDir())
+	assert.ErrorIs(t, err, errTestnetNotFound)
+}
@@ -0,0 +1,305 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/kaiachain/kaia/cmd/utils"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/consensus/istanbul"
+	"github.com/kaiachain/kaia/kaiax/reward"
+	"github.com/kaiachain/kaia/kaiax/staking"
+	"github.com/kaiachain/kaia/params"
+	legacyreward "github.com/kaiachain/kaia/reward"
+	"github.com/urfave/cli/v2"
+)
+
+const secondsPerDay = 24 * 60 * 60
+
+var (
+	errNoSimulateStake   = errors.New("staking amount is not given. Use --simulate.stake")
+	errNotWeightedRandom = errors.New("the chain does not use the weighted random proposer policy")
+)
+
+var ValidatorCommand = &cli.Command{
+	Name:     "validator",
+	Usage:    "A set of commands for prospective validators",
+	Category: "MISCELLANEOUS COMMANDS",
+	Subcommands: []*cli.Command{
+		{
+			Name:      "simulate",
+			Usage:     "Estimate the consensus participation of a prospective validator",
+			ArgsUsage: "[endpoint]",
+			Action:    utils.MigrateFlags(simulateValidator),
+			Flags:     utils.ValidatorSimulateFlags,
+			Description: `
+kcn validator simulate --simulate.stake <amount> [endpoint]
+connects to a running node and estimates how a validator staking the given
+amount of KAIA would take part in the consensus under the current staking
+information and governance parameters: the expected proposer frequency,
+the committee inclusion rate and the projected block rewards.
+Transaction fees are not included in the reward projection.`,
+		},
+	},
+}
+
+// simulationParams holds the governance parameters needed by the simulation,
+// decoded from the result of kaia_getParams.
+type simulationParams struct {
+	Policy        uint64   `json:"istanbul.policy"`
+	CommitteeSize uint64   `json:"istanbul.committeesize"`
+	MintingAmount *big.Int `json:"reward.mintingamount"`
+	MinimumStake  *big.Int `json:"reward.minimumstake"`
+	Ratio         string   `json:"reward.ratio"`
+	Kip82Ratio    string   `json:"reward.kip82ratio"`
+	UseGiniCoeff  bool     `json:"reward.useginicoeff"`
+}
+
+// simulationInput is everything the estimation depends on.
+type simulationInput struct {
+	Stakes        []uint64 // Consolidated staking amounts of the current council, in KAIA
+	Stake         uint64   // Staking amount of the prospective validator, in KAIA
+	MinimumStake  uint64
+	CommitteeSize uint64
+	UseGini       bool
+	IsKore        bool
+	MintingAmount *big.Int
+	Ratio         *reward.RewardRatio
+	Kip82Ratio    *reward.RewardKip82Ratio
+}
+
+// simulationResult is the estimated participation of the prospective validator.
+type simulationResult struct {
+	Eligible       bool     // Whether the stake is enough to be a validator
+	NumValidators  int      // Number of validators including the prospective one
+	ProposerShare  float64  // Probability of proposing a block
+	CommitteeRate  float64  // Probability of being in the committee of a block
+	ProposerReward *big.Int // Expected proposer reward per block, in kei
+	StakingReward  *big.Int // Expected staking reward per block, in kei
+}
+
+func (r *simulationResult) rewardPerBlock() *big.Int {
+	return new(big.Int).Add(r.ProposerReward, r.StakingReward)
+}
+
+func simulateValidator(ctx *cli.Context) error {
+	stake := ctx.Uint64(utils.ValidatorSimulateStakeFlag.Name)
+	if stake == 0 {
+		return errNoSimulateStake
+	}
+
+	client, err := dialRPC(rpcEndpoint(ctx))
+	if err != nil {
+		return fmt.Errorf("unable to attach to remote node: %v", err)
+	}
+	defer client.Close()
+
+	num := ctx.Uint64(utils.ValidatorSimulateBlockFlag.Name)
+	if num == 0 {
+		var latest hexutil.Uint64
+		if err := client.Call(&latest, "kaia_blockNumber"); err != nil {
+			return err
+		}
+		num = uint64(latest)
+	}
+	block := hexutil.EncodeUint64(num)
+
+	var (
+		chainConfig params.ChainConfig
+		govParams   simulationParams
+		stakingInfo staking.StakingInfoResponse
+	)
+	if err := client.Call(&chainConfig, "kaia_getChainConfig", block); err != nil {
+		return err
+	}
+	if err := client.Call(&govParams, "kaia_getParams", block); err != nil {
+		return err
+	}
+	if err := client.Call(&stakingInfo, "kaia_getStakingInfo", block); err != nil {
+		return err
+	}
+	if govParams.Policy != uint64(istanbul.WeightedRandom) {
+		return errNotWeightedRandom
+	}
+
+	in, err := newSimulationInput(&chainConfig, &govParams, &stakingInfo.StakingInfo, num, stake)
+	if err != nil {
+		return err
+	}
+	res := simulate(in)
+
+	fmt.Printf("Block number             : %d\n", num)
+	fmt.Printf("Staking amount           : %d KAIA (minimum %d KAIA)\n", stake, in.MinimumStake)
+	if !res.Eligible {
+		fmt.Println("The staking amount is below the minimum; the node would be demoted and take no part in the consensus.")
+		return nil
+	}
+	fmt.Printf("Validators               : %d (committee size %d)\n", res.NumValidators, in.CommitteeSize)
+	fmt.Printf("Proposer frequency       : %.4f%% (1 in %.1f blocks)\n", res.ProposerShare*100, 1/res.ProposerShare)
+	fmt.Printf("Committee inclusion rate : %.4f%%\n", res.CommitteeRate*100)
+
+	perBlock := res.rewardPerBlock()
+	blocksPerDay := big.NewInt(secondsPerDay / params.DefaultBlockGenerationInterval)
+	perDay := new(big.Int).Mul(perBlock, blocksPerDay)
+	perYear := new(big.Int).Mul(perDay, big.NewInt(365))
+	fmt.Printf("Reward per block         : %s KAIA (proposer %s, staking %s)\n",
+		keiToKaia(perBlock), keiToKaia(res.ProposerReward), keiToKaia(res.StakingReward))
+	fmt.Printf("Reward per day           : %s KAIA\n", keiToKaia(perDay))
+	fmt.Printf("Reward per year          : %s KAIA\n", keiToKaia(perYear))
+	return nil
+}
+
+func newSimulationInput(config *params.ChainConfig, p *simulationParams, si *staking.StakingInfo, num, stake uint64) (*simulationInput, error) {
+	if p.MintingAmount == nil || p.MinimumStake == nil {
+		return nil, errors.New("missing reward parameters")
+	}
+	ratio, err := reward.NewRewardRatio(p.Ratio)
+	if err != nil {
+		return nil, err
+	}
+	kip82Ratio, err := reward.NewRewardKip82Ratio(p.Kip82Ratio)
+	if err != nil {
+		return nil, err
+	}
+
+	var stakes []uint64
+	for _, cn := range si.ConsolidatedNodes() {
+		stakes = append(stakes, cn.StakingAmount)
+	}
+	isKore := config.IsKoreForkEnabled(new(big.Int).SetUint64(num))
+	return &simulationInput{
+		Stakes:        stakes,
+		Stake:         stake,
+		MinimumStake:  p.MinimumStake.Uint64(),
+		CommitteeSize: p.CommitteeSize,
+		UseGini:       p.UseGiniCoeff && !isKore,
+		IsKore:        isKore,
+		MintingAmount: p.MintingAmount,
+		Ratio:         ratio,
+		Kip82Ratio:    kip82Ratio,
+	}, nil
+}
+
+// simulate estimates the participation of a validator joining the council with in.Stake.
+// It follows the weighted random proposer policy: validators below the minimum stake are
+// demoted, proposers are uniformly selected after Kore and by staking weight before it.
+// The committee inclusion rate assumes the committee members are evenly drawn from the validators.
+func simulate(in *simulationInput) *simulationResult {
+	res := &simulationResult{
+		ProposerReward: new(big.Int),
+		StakingReward:  new(big.Int),
+	}
+
+	// Demote the validators below the minimum stake as filterValidators does.
+	council := append(append([]uint64{}, in.Stakes...), in.Stake)
+	var validators []float64
+	for _, s := range council {
+		if s >= in.MinimumStake {
+			validators = append(validators, float64(s))
+		}
+	}
+	if len(validators) == 0 {
+		for _, s := range council {
+			validators = append(validators, float64(s))
+		}
+	} else if in.Stake < in.MinimumStake {
+		return res
+	}
+	res.Eligible = true
+	res.NumValidators = len(validators)
+	me := len(validators) - 1 // The prospective validator is always the last one
+
+	weight, totalWeight := proposerWeight(validators, me, in.IsKore, in.UseGini)
+	res.ProposerShare = float64(weight) / float64(totalWeight)
+	if n := uint64(len(validators)); in.CommitteeSize >= n {
+		res.CommitteeRate = 1
+	} else {
+		res.CommitteeRate = float64(in.CommitteeSize) / float64(n)
+	}
+
+	// The validators' share of the minted amount goes to the proposer before Kore,
+	// and is split between the proposer and the stakers after Kore.
+	validatorsReward, _, _ := in.Ratio.Split(in.MintingAmount)
+	proposerReward := validatorsReward
+	if in.IsKore {
+		var stakersReward *big.Int
+		proposerReward, stakersReward = in.Kip82Ratio.Split(validatorsReward)
+
+		totalExcess := uint64(0)
+		for _, s := range council {
+			if s > in.MinimumStake {
+				totalExcess += s - in.MinimumStake
+			}
+		}
+		if in.Stake > in.MinimumStake {
+			res.StakingReward.Mul(stakersReward, new(big.Int).SetUint64(in.Stake-in.MinimumStake))
+			res.StakingReward.Div(res.StakingReward, new(big.Int).SetUint64(totalExcess))
+		}
+	}
+	res.ProposerReward.Mul(proposerReward, new(big.Int).SetUint64(weight))
+	res.ProposerReward.Div(res.ProposerReward, new(big.Int).SetUint64(totalWeight))
+	return res
+}
+
+// proposerWeight returns the proposer weight of validators[idx] and the total weight,
+// so that weight/total is the probability that it proposes a block.
+// The weights are computed as calcTotalAmount and calcWeight do before Kore,
+// and all validators are equally likely to propose after Kore.
+func proposerWeight(validators []float64, idx int, isKore, useGini bool) (uint64, uint64) {
+	uniform := func() (uint64, uint64) { return 1, uint64(len(validators)) }
+	if isKore {
+		return uniform()
+	}
+
+	amounts := make([]float64, len(validators))
+	copy(amounts, validators)
+	if useGini {
+		gini := legacyreward.CalcGiniCoefficient(append([]float64{}, amounts...))
+		for i := range amounts {
+			amounts[i] = math.Round(math.Pow(amounts[i], 1.0/(1+gini)))
+		}
+	}
+	total := float64(0)
+	for _, a := range amounts {
+		total += a
+	}
+	if total == 0 {
+		return uniform()
+	}
+
+	weight, totalWeight := uint64(0), uint64(0)
+	for i, a := range amounts {
+		w := uint64(math.Round(a * 100 / total))
+		if w == 0 {
+			// A validator, who holds zero or small stake, has minimum weight, 1.
+			w = 1
+		}
+		if i == idx {
+			weight = w
+		}
+		totalWeight += w
+	}
+	return weight, totalWeight
+}
+
+func keiToKaia(kei *big.Int) string {
+	return new(big.Float).Quo(new(big.Float).SetInt(kei), big.NewFloat(params.KAIA)).Text('f', 6)
+}
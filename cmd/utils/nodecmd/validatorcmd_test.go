@@ -0,0 +1,74 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kaiachain/kaia/kaiax/reward"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateValidator(t *testing.T) {
+	ratio, err := reward.NewRewardRatio("50/20/30")
+	require.NoError(t, err)
+	kip82Ratio, err := reward.NewRewardKip82Ratio("20/80")
+	require.NoError(t, err)
+
+	newInput := func(stake uint64, isKore bool) *simulationInput {
+		return &simulationInput{
+			Stakes:        []uint64{5_000_000, 7_000_000, 9_000_000, 1_000_000},
+			Stake:         stake,
+			MinimumStake:  5_000_000,
+			CommitteeSize: 2,
+			IsKore:        isKore,
+			MintingAmount: big.NewInt(1e18),
+			Ratio:         ratio,
+			Kip82Ratio:    kip82Ratio,
+		}
+	}
+
+	// Below the minimum stake, the node is demoted.
+	res := simulate(newInput(4_000_000, true))
+	assert.False(t, res.Eligible)
+	assert.Zero(t, res.rewardPerBlock().Sign())
+
+	// After Kore, proposers are uniform and 80% of the validators' share goes to
+	// the stakers proportionally to the excess stake: 4M / (2M + 4M + 4M).
+	res = simulate(newInput(9_000_000, true))
+	assert.True(t, res.Eligible)
+	assert.Equal(t, 4, res.NumValidators)
+	assert.InDelta(t, 0.25, res.ProposerShare, 1e-9)
+	assert.InDelta(t, 0.5, res.CommitteeRate, 1e-9)
+	assert.Equal(t, big.NewInt(25e15), res.ProposerReward) // 0.5 * 0.2 * 0.25 KAIA
+	assert.Equal(t, big.NewInt(16e16), res.StakingReward)  // 0.5 * 0.8 * 0.4 KAIA
+
+	// Before Kore, the proposer is weighted by stake and takes the whole validators' share.
+	res = simulate(newInput(9_000_000, false))
+	assert.True(t, res.Eligible)
+	// weights = round(stake * 100 / 30M) = [17, 23, 30, 30]
+	assert.InDelta(t, 30.0/100, res.ProposerShare, 1e-9)
+	assert.Equal(t, big.NewInt(15e16), res.ProposerReward)
+	assert.Zero(t, res.StakingReward.Sign())
+
+	// A committee larger than the validator set always includes the node.
+	in := newInput(9_000_000, true)
+	in.CommitteeSize = 22
+	assert.Equal(t, 1.0, simulate(in).CommitteeRate)
+}
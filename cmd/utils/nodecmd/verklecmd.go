@@ -0,0 +1,162 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/cmd/utils"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/node"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/kaiachain/kaia/storage/statedb"
+	"github.com/kaiachain/kaia/storage/verkle"
+	"github.com/urfave/cli/v2"
+)
+
+var errNoWitnessAddress = errors.New("the address of the witness is not given")
+
+var VerkleCommand = &cli.Command{
+	Name:     "verkle",
+	Usage:    "A set of commands for the prototype verkle state commitment",
+	Category: "MISCELLANEOUS COMMANDS",
+	Subcommands: []*cli.Command{
+		{
+			Name:      "convert",
+			Usage:     "Convert the state of a block into a verkle tree and print its root",
+			ArgsUsage: "[block]",
+			Action:    utils.MigrateFlags(convertVerkle),
+			Flags:     utils.SnapshotFlags,
+			Description: `
+kcn verkle convert --datadir <dir> [block]
+opens the chain database read-only and converts the MPT state of the block
+(default: the head block), including the storage and the code, into a verkle
+tree. It prints the verkle root, the number of converted entries and the shape
+of the tree. The tree is built in memory, so it is meant for devnets.`,
+		},
+		{
+			Name:      "witness",
+			Usage:     "Print the verkle witness of an account and its storage slots",
+			ArgsUsage: "<block> <address> [slot...]",
+			Action:    utils.MigrateFlags(verkleWitness),
+			Flags:     utils.SnapshotFlags,
+			Description: `
+kcn verkle witness --datadir <dir> <block> <address> [slot...]
+converts the state of the block like 'kcn verkle convert' and prints the
+witness of the account header and the given storage slots as JSON, followed by
+its size. The witness does not include the opening proof.`,
+		},
+	},
+}
+
+// convertVerkleAt converts the state of the block given by the argument, or of the head block.
+func convertVerkleAt(ctx *cli.Context, arg string) (*verkle.Tree, *types.Header, error) {
+	nodeConfig := &node.Config{
+		DataDir:      utils.MakeDataDir(ctx),
+		ChainDataDir: ctx.String(utils.ChainDataDirFlag.Name),
+		Name:         utils.ClientIdentifier,
+	}
+	dbc := getConfig(ctx)
+	dbc.Dir = nodeConfig.ResolvePath(dbc.Dir)
+	dbc.ReadOnly = true
+	dbm := database.NewDBManager(dbc)
+	defer dbm.Close()
+
+	hash := dbm.ReadHeadBlockHash()
+	if arg != "" && arg != "latest" {
+		num, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid block number %q", arg)
+		}
+		hash = dbm.ReadCanonicalHash(num)
+	}
+	number := dbm.ReadHeaderNumber(hash)
+	if number == nil {
+		return nil, nil, fmt.Errorf("block %q not found", arg)
+	}
+	header := dbm.ReadHeader(hash, *number)
+	if header == nil {
+		return nil, nil, fmt.Errorf("block %q not found", arg)
+	}
+
+	start := time.Now()
+	tree, stats, err := verkle.ConvertState(statedb.NewDatabase(dbm), header.Root)
+	if err != nil {
+		return nil, nil, err
+	}
+	fmt.Fprintf(os.Stderr, "Converted the state of block %d in %v: %d accounts, %d storage slots, %d code chunks\n",
+		header.Number.Uint64(), time.Since(start), stats.Accounts, stats.StorageSlots, stats.CodeChunks)
+	return tree, header, nil
+}
+
+func convertVerkle(ctx *cli.Context) error {
+	tree, header, err := convertVerkleAt(ctx, ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	treeStats := tree.Stats()
+	fmt.Printf("Block:          %d\n", header.Number.Uint64())
+	fmt.Printf("MPT root:       %s\n", header.Root.Hex())
+	fmt.Printf("Verkle root:    %s\n", tree.Commit().Hex())
+	fmt.Printf("Internal nodes: %d\n", treeStats.InternalNodes)
+	fmt.Printf("Leaves:         %d\n", treeStats.Leaves)
+	fmt.Printf("Values:         %d\n", treeStats.Values)
+	fmt.Printf("Max depth:      %d\n", treeStats.MaxDepth)
+	return nil
+}
+
+func verkleWitness(ctx *cli.Context) error {
+	if ctx.Args().Len() < 2 {
+		return errNoWitnessAddress
+	}
+	if !common.IsHexAddress(ctx.Args().Get(1)) {
+		return fmt.Errorf("invalid address %q", ctx.Args().Get(1))
+	}
+	addrHash := crypto.Keccak256Hash(common.HexToAddress(ctx.Args().Get(1)).Bytes())
+
+	var keys [][]byte
+	for leaf := byte(verkle.VersionLeafKey); leaf <= verkle.AccountKeyHashLeafKey; leaf++ {
+		keys = append(keys, verkle.AccountHeaderKey(addrHash, leaf))
+	}
+	for _, arg := range ctx.Args().Slice()[2:] {
+		slot := common.HexToHash(arg)
+		keys = append(keys, verkle.StorageSlotKey(addrHash, crypto.Keccak256Hash(slot[:])))
+	}
+
+	tree, _, err := convertVerkleAt(ctx, ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	w, err := tree.Witness(keys)
+	if err != nil {
+		return err
+	}
+	enc, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(enc))
+	fmt.Printf("Witness size: %d bytes without the opening proof\n", w.Size())
+	return nil
+}
@@ -40,6 +40,11 @@ func AllNodeFlags() []cli.Flag {
 	nodeFlags = union(nodeFlags, SnapshotFlags)
 	nodeFlags = union(nodeFlags, DBMigrationSrcFlags)
 	nodeFlags = union(nodeFlags, DBMigrationDstFlags)
+	nodeFlags = union(nodeFlags, ValidatorSimulateFlags)
+	nodeFlags = union(nodeFlags, GovHistoryFlags)
+	nodeFlags = union(nodeFlags, DBDowngradeFlags)
+	nodeFlags = union(nodeFlags, TestnetFlags)
+	nodeFlags = union(nodeFlags, DoctorFlags)
 	nodeFlags = union(nodeFlags, BNFlags)
 	nodeFlags = union(nodeFlags, KCNFlags)
 	nodeFlags = union(nodeFlags, KPNFlags)
@@ -188,13 +193,26 @@ var CommonNodeFlags = []cli.Flag{
 	altsrc.NewUint64Flag(TxPoolExecSlotsAllFlag),
 	altsrc.NewUint64Flag(TxPoolNonExecSlotsAccountFlag),
 	altsrc.NewUint64Flag(TxPoolNonExecSlotsAllFlag),
+	altsrc.NewUint64Flag(TxPoolBlobsAllFlag),
 	altsrc.NewDurationFlag(TxPoolLifetimeFlag),
 	altsrc.NewBoolFlag(TxPoolKeepLocalsFlag),
+	altsrc.NewBoolFlag(TxProvenanceFlag),
+	altsrc.NewIntFlag(TxProvenanceSizeFlag),
+	altsrc.NewStringFlag(TxProvenanceFleetFlag),
+	altsrc.NewStringFlag(TxFirewallRulesFlag),
+	altsrc.NewStringFlag(FleetBanMembersFlag),
+	altsrc.NewUint64Flag(FleetBanThresholdFlag),
+	altsrc.NewDurationFlag(FleetBanTTLFlag),
 	NewWrappedTextMarshalerFlag(SyncModeFlag),
 	altsrc.NewStringFlag(GCModeFlag),
 	altsrc.NewBoolFlag(LightKDFFlag),
+	altsrc.NewBoolFlag(MemoryDBFlag),
+	altsrc.NewBoolFlag(DBReadOnlyFlag),
+	altsrc.NewUint64Flag(ReceiptRetentionFlag),
 	altsrc.NewBoolFlag(SingleDBFlag),
 	altsrc.NewUintFlag(NumStateTrieShardsFlag),
+	altsrc.NewStringSliceFlag(StateTrieShardDirsFlag),
+	altsrc.NewStringSliceFlag(CompressedTablesFlag),
 	altsrc.NewIntFlag(LevelDBCompressionTypeFlag),
 	altsrc.NewBoolFlag(LevelDBNoBufferPoolFlag),
 	altsrc.NewBoolFlag(DBNoPerformanceMetricsFlag),
@@ -215,6 +233,10 @@ var CommonNodeFlags = []cli.Flag{
 	altsrc.NewBoolFlag(DynamoDBReadOnlyFlag),
 	altsrc.NewIntFlag(LevelDBCacheSizeFlag),
 	altsrc.NewIntFlag(PebbleDBCacheSizeFlag),
+	altsrc.NewUint64Flag(AncientThresholdFlag),
+	altsrc.NewPathFlag(AncientDirFlag),
+	altsrc.NewStringFlag(CompactionWindowFlag),
+	altsrc.NewDurationFlag(CompactionLatencyLimitFlag),
 	altsrc.NewBoolFlag(NoParallelDBWriteFlag),
 	altsrc.NewBoolFlag(SenderTxHashIndexingFlag),
 	altsrc.NewIntFlag(TrieMemoryCacheSizeFlag),
@@ -222,6 +244,21 @@ var CommonNodeFlags = []cli.Flag{
 	altsrc.NewUint64Flag(TriesInMemoryFlag),
 	altsrc.NewBoolFlag(LivePruningFlag),
 	altsrc.NewUint64Flag(LivePruningRetentionFlag),
+	altsrc.NewBoolFlag(OnlinePruningFlag),
+	altsrc.NewUint64Flag(OnlinePruningRetentionFlag),
+	altsrc.NewDurationFlag(OnlinePruningIntervalFlag),
+	altsrc.NewIntFlag(OnlinePruningRateFlag),
+	altsrc.NewUint64Flag(OnlinePruningBloomSizeFlag),
+	altsrc.NewBoolFlag(StateDiffFlag),
+	altsrc.NewBoolFlag(StateDiffPersistFlag),
+	altsrc.NewBoolFlag(StateTrieJournalFlag),
+	altsrc.NewBoolFlag(InvariantCheckFlag),
+	altsrc.NewStringFlag(InvariantActionFlag),
+	altsrc.NewBoolFlag(VerkleShadowFlag),
+	altsrc.NewStringFlag(TrieSchemeFlag),
+	altsrc.NewStringFlag(TrieMirrorSchemeFlag),
+	altsrc.NewUint64Flag(TrieIntegrityCheckRateFlag),
+	altsrc.NewBoolFlag(TrieIntegrityHealFlag),
 	altsrc.NewIntFlag(CacheTypeFlag),
 	altsrc.NewIntFlag(CacheScaleFlag),
 	altsrc.NewStringFlag(CacheUsageLevelFlag),
@@ -282,6 +319,7 @@ var CommonRPCFlags = []cli.Flag{
 	altsrc.NewStringFlag(RPCListenAddrFlag),
 	altsrc.NewIntFlag(RPCPortFlag),
 	altsrc.NewStringFlag(RPCApiFlag),
+	altsrc.NewStringFlag(RPCListenersFlag),
 	altsrc.NewUint64Flag(RPCGlobalGasCap),
 	altsrc.NewFloat64Flag(RPCGlobalEthTxFeeCapFlag),
 	altsrc.NewStringFlag(RPCCORSDomainFlag),
@@ -308,9 +346,21 @@ var CommonRPCFlags = []cli.Flag{
 	altsrc.NewIntFlag(RPCIdleTimeoutFlag),
 	altsrc.NewIntFlag(RPCExecutionTimeoutFlag),
 	altsrc.NewBoolFlag(UnsafeDebugDisableFlag),
+	altsrc.NewBoolFlag(AbiStoreFlag),
+	altsrc.NewStringFlag(AbiStoreMetadataURLFlag),
 	altsrc.NewIntFlag(HeavyDebugRequestLimitFlag),
+	altsrc.NewIntFlag(HeavyCallSlotsFlag),
+	altsrc.NewDurationFlag(HeavyCallExecTimeLimitFlag),
+	altsrc.NewDurationFlag(RPCSlowQueryThresholdFlag),
+	altsrc.NewIntFlag(RPCSlowQueryLogSizeFlag),
+	altsrc.NewStringFlag(RPCJSONCodecFlag),
+	altsrc.NewBoolFlag(LoadSheddingFlag),
+	altsrc.NewFloat64Flag(LoadShedCPUThresholdFlag),
+	altsrc.NewFloat64Flag(LoadShedMemoryThresholdFlag),
+	altsrc.NewDurationFlag(LoadShedDiskLatencyFlag),
 	altsrc.NewDurationFlag(StateRegenerationTimeLimitFlag),
 	altsrc.NewStringFlag(RPCUpstreamArchiveENFlag),
+	altsrc.NewIntFlag(RPCUpstreamCacheSizeFlag),
 }
 
 var BNFlags = []cli.Flag{
@@ -336,6 +386,15 @@ var KCNFlags = []cli.Flag{
 	altsrc.NewBoolFlag(KairosFlag),
 	altsrc.NewInt64Flag(BlockGenerationIntervalFlag),
 	altsrc.NewDurationFlag(BlockGenerationTimeLimitFlag),
+	altsrc.NewBoolFlag(BlockGenerationAdaptiveFlag),
+	altsrc.NewStringFlag(IstanbulGossipPolicyFlag),
+	altsrc.NewUint64Flag(IstanbulGossipFanoutFlag),
+	altsrc.NewUint64Flag(IstanbulGossipFullThresholdFlag),
+	altsrc.NewBoolFlag(IstanbulSessionNonceFlag),
+	altsrc.NewStringFlag(PrivateTxPartnersFlag),
+	altsrc.NewIntFlag(PrivateTxPoolSizeFlag),
+	altsrc.NewBoolFlag(AnnounceEnabledFlag),
+	altsrc.NewBoolFlag(ProposalRecordFlag),
 }
 
 var KPNFlags = []cli.Flag{
@@ -349,6 +408,8 @@ var KPNFlags = []cli.Flag{
 
 var KENFlags = []cli.Flag{
 	altsrc.NewStringFlag(ServiceChainSignerFlag),
+	altsrc.NewStringFlag(UpstreamEndpointsFlag),
+	altsrc.NewDurationFlag(UpstreamPollIntervalFlag),
 	altsrc.NewBoolFlag(MainnetFlag),
 	altsrc.NewBoolFlag(KairosFlag),
 	altsrc.NewBoolFlag(ChildChainIndexingFlag),
@@ -382,6 +443,11 @@ var KSCNFlags = []cli.Flag{
 	altsrc.NewStringFlag(RewardbaseFlag),
 	altsrc.NewInt64Flag(BlockGenerationIntervalFlag),
 	altsrc.NewDurationFlag(BlockGenerationTimeLimitFlag),
+	altsrc.NewBoolFlag(BlockGenerationAdaptiveFlag),
+	altsrc.NewStringFlag(IstanbulGossipPolicyFlag),
+	altsrc.NewUint64Flag(IstanbulGossipFanoutFlag),
+	altsrc.NewUint64Flag(IstanbulGossipFullThresholdFlag),
+	altsrc.NewBoolFlag(IstanbulSessionNonceFlag),
 	altsrc.NewStringFlag(ServiceChainSignerFlag),
 	altsrc.NewUint64Flag(AnchoringPeriodFlag),
 	altsrc.NewUint64Flag(SentChainTxsLimit),
@@ -559,6 +625,59 @@ var DBMigrationDstFlags = []cli.Flag{
 	altsrc.NewBoolFlag(DstRocksDBCacheIndexAndFilterFlag),
 }
 
+var ValidatorSimulateFlags = []cli.Flag{
+	altsrc.NewUint64Flag(ValidatorSimulateStakeFlag),
+	altsrc.NewUint64Flag(ValidatorSimulateBlockFlag),
+	altsrc.NewPathFlag(DataDirFlag),
+}
+
+var DBDowngradeFlags = append([]cli.Flag{
+	altsrc.NewStringFlag(DBDowngradeToFlag),
+}, SnapshotFlags...)
+
+var DBCompressFlags = append([]cli.Flag{
+	altsrc.NewStringSliceFlag(CompressedTablesFlag),
+}, SnapshotFlags...)
+
+var GovHistoryFlags = []cli.Flag{
+	altsrc.NewPathFlag(GovHistoryOutFlag),
+	altsrc.NewPathFlag(GovHistoryGenesisFlag),
+	altsrc.NewBoolFlag(GovHistoryTimelineFlag),
+	altsrc.NewPathFlag(DataDirFlag),
+}
+
+var TestnetFlags = []cli.Flag{
+	altsrc.NewPathFlag(TestnetDirFlag),
+	altsrc.NewIntFlag(TestnetValidatorsFlag),
+	altsrc.NewIntFlag(TestnetENsFlag),
+	altsrc.NewIntFlag(TestnetAccountsFlag),
+	altsrc.NewUint64Flag(TestnetChainIDFlag),
+	altsrc.NewIntFlag(TestnetPortFlag),
+	altsrc.NewIntFlag(TestnetRPCPortFlag),
+	altsrc.NewPathFlag(TestnetKenFlag),
+	altsrc.NewBoolFlag(TestnetCleanFlag),
+}
+
+var DoctorFlags = []cli.Flag{
+	altsrc.NewIntFlag(DoctorDiskSizeFlag),
+	altsrc.NewBoolFlag(DoctorOfflineFlag),
+	altsrc.NewPathFlag(DataDirFlag),
+	altsrc.NewPathFlag(ChainDataDirFlag),
+	altsrc.NewBoolFlag(KairosFlag),
+	altsrc.NewIntFlag(ListenPortFlag),
+	altsrc.NewBoolFlag(RPCEnabledFlag),
+	altsrc.NewStringFlag(RPCListenAddrFlag),
+	altsrc.NewIntFlag(RPCPortFlag),
+	altsrc.NewBoolFlag(WSEnabledFlag),
+	altsrc.NewStringFlag(WSListenAddrFlag),
+	altsrc.NewIntFlag(WSPortFlag),
+	altsrc.NewStringFlag(NodeKeyFileFlag),
+	altsrc.NewStringFlag(NodeKeyHexFlag),
+	altsrc.NewStringFlag(BlsNodeKeyFileFlag),
+	altsrc.NewStringFlag(BlsNodeKeyHexFlag),
+	altsrc.NewStringFlag(NtpServerFlag),
+}
+
 var ChainDataFetcherFlags = []cli.Flag{
 	altsrc.NewBoolFlag(EnableChainDataFetcherFlag),
 	altsrc.NewStringFlag(ChainDataFetcherMode),
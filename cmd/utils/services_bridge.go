@@ -0,0 +1,50 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !nobridge
+
+package utils
+
+import (
+	"github.com/kaiachain/kaia/node"
+	"github.com/kaiachain/kaia/node/sc"
+)
+
+// BridgeBuilt is false in the binaries built with the nobridge tag, which cannot run the service chain bridges.
+const BridgeBuilt = true
+
+func registerBridges(stack *node.Node, cfg *sc.SCConfig) error {
+	if cfg.EnabledMainBridge {
+		err := stack.RegisterSubService(func(ctx *node.ServiceContext) (node.Service, error) {
+			mainBridge, err := sc.NewMainBridge(ctx, cfg)
+			return mainBridge, err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if cfg.EnabledSubBridge {
+		err := stack.RegisterSubService(func(ctx *node.ServiceContext) (node.Service, error) {
+			subBridge, err := sc.NewSubBridge(ctx, cfg)
+			return subBridge, err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
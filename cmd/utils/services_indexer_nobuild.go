@@ -0,0 +1,42 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build noindexer
+
+package utils
+
+import (
+	"github.com/kaiachain/kaia/datasync/chaindatafetcher"
+	"github.com/kaiachain/kaia/datasync/dbsyncer"
+	"github.com/kaiachain/kaia/node"
+)
+
+// IndexerBuilt is false in the binaries built with the noindexer tag, which cannot run the chain data fetcher and the DB syncer.
+const IndexerBuilt = false
+
+func registerChainDataFetcher(stack *node.Node, cfg *chaindatafetcher.ChainDataFetcherConfig) error {
+	if cfg.EnabledChainDataFetcher {
+		return ErrIndexerNotBuilt
+	}
+	return nil
+}
+
+func registerDBSyncer(stack *node.Node, cfg *dbsyncer.DBConfig) error {
+	if cfg.EnabledDBSyncer {
+		return ErrIndexerNotBuilt
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/kaiachain/kaia/datasync/chaindatafetcher"
+	"github.com/kaiachain/kaia/datasync/dbsyncer"
+	"github.com/kaiachain/kaia/node"
+	"github.com/kaiachain/kaia/node/sc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The tests run with and without the nobridge and noindexer tags, e.g.
+// go test -tags nobridge,noindexer ./cmd/utils -run Services
+func newServicesTestNode(t *testing.T) *node.Node {
+	stack, err := node.New(&node.Config{DataDir: t.TempDir(), Name: "test"})
+	require.NoError(t, err)
+	return stack
+}
+
+func TestServicesBridge(t *testing.T) {
+	// Disabled services are never an error.
+	assert.NoError(t, registerBridges(newServicesTestNode(t), &sc.SCConfig{}))
+
+	err := registerBridges(newServicesTestNode(t), &sc.SCConfig{EnabledMainBridge: true})
+	if BridgeBuilt {
+		assert.NoError(t, err)
+	} else {
+		assert.ErrorIs(t, err, ErrBridgeNotBuilt)
+	}
+}
+
+func TestServicesIndexer(t *testing.T) {
+	assert.NoError(t, registerChainDataFetcher(newServicesTestNode(t), &chaindatafetcher.ChainDataFetcherConfig{}))
+	assert.NoError(t, registerDBSyncer(newServicesTestNode(t), &dbsyncer.DBConfig{}))
+
+	err1 := registerChainDataFetcher(newServicesTestNode(t), &chaindatafetcher.ChainDataFetcherConfig{EnabledChainDataFetcher: true})
+	err2 := registerDBSyncer(newServicesTestNode(t), &dbsyncer.DBConfig{EnabledDBSyncer: true})
+	if IndexerBuilt {
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+	} else {
+		assert.ErrorIs(t, err1, ErrIndexerNotBuilt)
+		assert.ErrorIs(t, err2, ErrIndexerNotBuilt)
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+/*
+vectorgen generates the canonical encode/decode test vectors of the RLP and
+consensus objects for every hardfork. A suite is written to <fork>.json for
+each hardfork, so that the SDKs in other languages can check their encoders
+against the node.
+
+# Options
+
+All available options are as follows.
+
+	--out value   Directory to write the suites to (default: "vectors")
+	--fork value  Generate the suite of the given hardfork only
+	--help, -h    Show help
+*/
+package main
@@ -0,0 +1,90 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kaiachain/kaia/tests/vectors"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	outFlag = &cli.StringFlag{
+		Name:  "out",
+		Usage: "Directory to write the suites to",
+		Value: "vectors",
+	}
+	forkFlag = &cli.StringFlag{
+		Name:  "fork",
+		Usage: "Generate the suite of the given hardfork only",
+	}
+)
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "vectorgen"
+	app.Usage = "The command line interface to generate the RLP and consensus test vectors of Kaia"
+	app.Copyright = "Copyright 2024 The Kaia Authors"
+	app.Action = genVectors
+	app.Flags = []cli.Flag{
+		outFlag,
+		forkFlag,
+	}
+	app.HideVersion = true
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// genVectors writes the suite of each hardfork as <fork>.json under the output directory.
+func genVectors(ctx *cli.Context) error {
+	var (
+		suites []*vectors.Suite
+		err    error
+	)
+	if fork := ctx.String(forkFlag.Name); fork != "" {
+		suite, err := vectors.Generate(fork)
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, fork)
+		}
+		suites = append(suites, suite)
+	} else if suites, err = vectors.GenerateAll(); err != nil {
+		return err
+	}
+
+	dir := ctx.String(outFlag.Name)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	for _, suite := range suites {
+		b, err := json.MarshalIndent(suite, "", "  ")
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, suite.Fork+".json")
+		if err := os.WriteFile(path, append(b, '\n'), 0o644); err != nil {
+			return err
+		}
+		fmt.Println("Created : ", path)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package cpulimit
+
+import (
+	"math"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// NumCPU returns the number of CPUs usable by the current process. It is the smaller of
+// runtime.NumCPU and the cgroup CPU quota rounded up.
+func NumCPU() int {
+	n := runtime.NumCPU()
+	if quota, ok := cgroupQuota(); ok && quota < n {
+		return quota
+	}
+	return n
+}
+
+// parseCPUMax parses the content of cgroup v2 "cpu.max", formatted as "$MAX $PERIOD".
+// "max" means no limit.
+func parseCPUMax(content string) (int, bool) {
+	fields := strings.Fields(content)
+	if len(fields) != 2 || fields[0] == "max" {
+		return 0, false
+	}
+	return parseQuota(fields[0], fields[1])
+}
+
+// parseQuota converts a cgroup quota and period, both in microseconds, to a number of CPUs.
+// A non-positive quota means no limit.
+func parseQuota(quotaStr, periodStr string) (int, bool) {
+	quota, err := strconv.ParseInt(strings.TrimSpace(quotaStr), 10, 64)
+	if err != nil || quota <= 0 {
+		return 0, false
+	}
+	period, err := strconv.ParseInt(strings.TrimSpace(periodStr), 10, 64)
+	if err != nil || period <= 0 {
+		return 0, false
+	}
+	return int(math.Max(1, math.Ceil(float64(quota)/float64(period)))), true
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build linux
+// +build linux
+
+package cpulimit
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+const (
+	cgroupV2CPUMax      = "/sys/fs/cgroup/cpu.max"
+	cgroupV1CFSQuotaUs  = "/sys/fs/cgroup/cpu/cpu.cfs_quota_us"
+	cgroupV1CFSPeriodUs = "/sys/fs/cgroup/cpu/cpu.cfs_period_us"
+)
+
+// cgroupQuota returns the CPU quota of the cgroup this process belongs to.
+// Both cgroup v2 and v1 are supported.
+func cgroupQuota() (int, bool) {
+	if content, err := os.ReadFile(cgroupV2CPUMax); err == nil {
+		return parseCPUMax(string(content))
+	}
+	quota, err := os.ReadFile(cgroupV1CFSQuotaUs)
+	if err != nil {
+		return 0, false
+	}
+	period, err := os.ReadFile(cgroupV1CFSPeriodUs)
+	if err != nil {
+		return 0, false
+	}
+	return parseQuota(string(quota), string(period))
+}
+
+// ProcessCPUTime returns the user and system CPU time consumed by the process so far.
+func ProcessCPUTime() (time.Duration, bool) {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		return 0, false
+	}
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano()), true
+}
@@ -0,0 +1,32 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !linux
+// +build !linux
+
+package cpulimit
+
+import "time"
+
+// cgroupQuota always reports no limit since cgroup is only available on linux.
+func cgroupQuota() (int, bool) {
+	return 0, false
+}
+
+// ProcessCPUTime is only supported on linux.
+func ProcessCPUTime() (time.Duration, bool) {
+	return 0, false
+}
@@ -0,0 +1,61 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package cpulimit
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCPUMax(t *testing.T) {
+	testcases := []struct {
+		content string
+		cpus    int
+		ok      bool
+	}{
+		{"max 100000\n", 0, false},
+		{"200000 100000\n", 2, true},
+		{"150000 100000", 2, true},
+		{"50000 100000", 1, true},
+		{"", 0, false},
+		{"abc 100000", 0, false},
+		{"100000 0", 0, false},
+	}
+	for _, tc := range testcases {
+		cpus, ok := parseCPUMax(tc.content)
+		assert.Equal(t, tc.ok, ok, tc.content)
+		assert.Equal(t, tc.cpus, cpus, tc.content)
+	}
+}
+
+func TestParseQuota(t *testing.T) {
+	cpus, ok := parseQuota("400000\n", "100000\n")
+	assert.True(t, ok)
+	assert.Equal(t, 4, cpus)
+
+	// cgroup v1 reports -1 if there is no limit
+	_, ok = parseQuota("-1\n", "100000\n")
+	assert.False(t, ok)
+}
+
+func TestNumCPU(t *testing.T) {
+	n := NumCPU()
+	assert.True(t, n >= 1)
+	assert.True(t, n <= runtime.NumCPU())
+}
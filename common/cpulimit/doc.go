@@ -0,0 +1,25 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+/*
+Package cpulimit reports the number of CPUs available to this process.
+
+When Kaia runs in a container, the CPU time may be throttled by the cgroup quota while
+runtime.NumCPU still reports every CPU of the host. This package takes the cgroup quota
+into account so that CPU-bound workers are sized to what the process can actually use.
+ProcessCPUTime can be sampled to compute the CPU utilization against that limit.
+*/
+package cpulimit
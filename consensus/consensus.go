@@ -24,6 +24,7 @@ package consensus
 
 import (
 	"math/big"
+	"time"
 
 	"github.com/kaiachain/kaia/blockchain/state"
 	"github.com/kaiachain/kaia/blockchain/types"
@@ -161,6 +162,13 @@ type Handler interface {
 	RegisterConsensusMsgCode(Peer)
 }
 
+// BlockBudgeter is implemented by the engines with a consensus deadline for the proposal.
+type BlockBudgeter interface {
+	// BlockBudget returns how long the proposer may spend executing the transactions
+	// of the block with the given header. The result never exceeds limit.
+	BlockBudget(header *types.Header, limit time.Duration) time.Duration
+}
+
 // Istanbul is a consensus engine to avoid byzantine failure
 type Istanbul interface {
 	Engine
@@ -177,6 +185,9 @@ type Istanbul interface {
 	// UpdateParam updates the governance parameter
 	UpdateParam(num uint64) error
 
+	// Council returns the validators and the demoted validators after the given block.
+	Council(number uint64, hash common.Hash) []common.Address
+
 	kaiax.ConsensusModuleHost
 	staking.StakingModuleHost
 }
@@ -86,4 +86,9 @@ type Backend interface {
 	SetCurrentView(view *View)
 
 	NodeType() common.ConnType
+
+	// QuorumCalculator returns the quorum calculator for the given block number.
+	// It returns nil if the quorum is computed by the legacy validator count rule,
+	// and an error if the quorum cannot be determined.
+	QuorumCalculator(number *big.Int) (QuorumCalculator, error)
 }
@@ -24,9 +24,11 @@ package backend
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
+	"os"
 	"reflect"
 
 	"github.com/kaiachain/kaia/accounts/abi/bind/backends"
@@ -35,10 +37,15 @@ import (
 	"github.com/kaiachain/kaia/blockchain/system"
 	"github.com/kaiachain/kaia/blockchain/types"
 	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
 	"github.com/kaiachain/kaia/consensus"
 	"github.com/kaiachain/kaia/consensus/istanbul"
 	istanbulCore "github.com/kaiachain/kaia/consensus/istanbul/core"
+	"github.com/kaiachain/kaia/consensus/istanbul/randao"
+	"github.com/kaiachain/kaia/consensus/istanbul/validator"
+	"github.com/kaiachain/kaia/crypto"
 	"github.com/kaiachain/kaia/networks/rpc"
+	"github.com/kaiachain/kaia/params"
 )
 
 // API is a user facing RPC API to dump Istanbul state
@@ -196,6 +203,125 @@ func (api *API) Discard(address common.Address) {
 	delete(api.istanbul.candidates, address)
 }
 
+// ProposerSelection is the computation of the weighted-random proposer selection of a block.
+type ProposerSelection struct {
+	Number        uint64                       `json:"number"`
+	Randao        bool                         `json:"randao"`
+	MixHash       hexutil.Bytes                `json:"mixHash,omitempty"`
+	Seed          int64                        `json:"seed"`
+	CommitteeSize uint64                       `json:"committeeSize"`
+	Validators    []ProposerSelectionCandidate `json:"validators"`
+	Ordering      []common.Address             `json:"ordering"`
+	Round         uint64                       `json:"round"`
+	Expected      common.Address               `json:"expectedProposer"`
+	Proposer      *common.Address              `json:"proposer,omitempty"`
+}
+
+// ProposerSelectionCandidate is a validator taking part in the proposer selection.
+type ProposerSelectionCandidate struct {
+	Address       common.Address `json:"address"`
+	RewardAddress common.Address `json:"rewardAddress"`
+	StakingAmount uint64         `json:"stakingAmount"`
+	Weight        uint64         `json:"weight"`
+}
+
+// GetProposerSelection returns the whole weighted-random proposer selection of the given block,
+// so that anyone can audit that the proposer was selected as the protocol defines.
+// If mixHash is given, it replaces the parent mixHash used as the seed after the Randao fork.
+// The ordering lists the proposers by round, and the round and the actual proposer are
+// filled if the block exists.
+func (api *API) GetProposerSelection(number rpc.BlockNumber, mixHash *hexutil.Bytes) (*ProposerSelection, error) {
+	var num uint64
+	switch number {
+	case rpc.PendingBlockNumber:
+		return nil, errPendingNotAllowed
+	case rpc.LatestBlockNumber:
+		num = api.chain.CurrentHeader().Number.Uint64()
+	default:
+		num = uint64(number.Int64())
+	}
+	if num == 0 {
+		// The proposer of genesis block is not selected.
+		return nil, errUnknownBlock
+	}
+	parent := api.chain.GetHeaderByNumber(num - 1)
+	if parent == nil {
+		return nil, errUnknownBlock
+	}
+
+	snap, err := checkStatesAndGetSnapshot(api.chain, api.istanbul, parent.Number.Uint64(), parent.Hash())
+	if err != nil {
+		return nil, err
+	}
+	if snap.ValSet.Policy() != istanbul.WeightedRandom {
+		return nil, errNotWeightedRandom
+	}
+
+	var seedHash []byte
+	if mixHash != nil {
+		seedHash = *mixHash
+	}
+	ordering, err := validator.ProposerOrdering(snap.ValSet, seedHash)
+	if err != nil {
+		return nil, err
+	}
+
+	bigNum := new(big.Int).SetUint64(num)
+	config := api.chain.Config()
+	selection := &ProposerSelection{
+		Number:        num,
+		Randao:        config.IsRandaoForkEnabled(bigNum),
+		CommitteeSize: snap.ValSet.SubGroupSize(),
+	}
+	if selection.Randao {
+		if seedHash == nil {
+			_, _, _, _, _, _, _, seedHash = validator.GetWeightedCouncilData(snap.ValSet)
+		}
+		selection.MixHash = seedHash
+		selection.Seed = validator.RandaoSeed(seedHash)
+	} else if pHeader := api.chain.GetHeaderByNumber(params.CalcProposerBlockNumber(num)); pHeader != nil {
+		if selection.Seed, err = validator.ConvertHashToSeed(pHeader.Hash()); err != nil {
+			return nil, err
+		}
+	}
+
+	// Staking amounts of the node ids sharing a reward address are summed up
+	stakes := make(map[common.Address]uint64)
+	if api.istanbul.stakingModule != nil {
+		stakingBlockNum := num
+		if !config.IsKaiaForkEnabled(bigNum) {
+			stakingBlockNum = params.CalcProposerBlockNumber(num) + 1
+		}
+		if si, err := api.istanbul.stakingModule.GetStakingInfo(stakingBlockNum); err == nil {
+			for _, node := range si.ConsolidatedNodes() {
+				for _, nodeId := range node.NodeIds {
+					stakes[nodeId] = node.StakingAmount
+				}
+			}
+		}
+	}
+	for _, val := range snap.ValSet.List() {
+		selection.Validators = append(selection.Validators, ProposerSelectionCandidate{
+			Address:       val.Address(),
+			RewardAddress: val.RewardAddress(),
+			StakingAmount: stakes[val.Address()],
+			Weight:        val.Weight(),
+		})
+	}
+	for _, val := range ordering {
+		selection.Ordering = append(selection.Ordering, val.Address())
+	}
+
+	if header := api.chain.GetHeaderByNumber(num); header != nil {
+		selection.Round = uint64(header.Round())
+		if proposer, err := ecrecover(header); err == nil {
+			selection.Proposer = &proposer
+		}
+	}
+	selection.Expected = selection.Ordering[selection.Round%uint64(len(selection.Ordering))]
+	return selection, nil
+}
+
 // API extended by Kaia developers
 type APIExtension struct {
 	chain    consensus.ChainReader
@@ -213,6 +339,7 @@ var (
 	errExtractIstanbulExtra    = errors.New("extract Istanbul Extra from block header of the given block number")
 	errNoBlockExist            = errors.New("block with the given block number is not existed")
 	errNoBlockNumber           = errors.New("block number is not assigned")
+	errNotWeightedRandom       = errors.New("proposer policy is not weighted random")
 )
 
 // GetCouncil retrieves the list of authorized validators at the specified block.
@@ -303,6 +430,41 @@ func (api *APIExtension) GetCommitteeSize(number *rpc.BlockNumber) (int, error)
 	}
 }
 
+// GetRandomness returns the KIP-114 randomness of the block along with the material to verify it.
+// The proposer BLS public key is omitted if the state of the parent block is unavailable.
+func (api *APIExtension) GetRandomness(number *rpc.BlockNumber) (*randao.Proof, error) {
+	header, err := headerByRpcNumber(api.chain, number)
+	if err != nil {
+		return nil, err
+	}
+	if header.Number.Sign() == 0 || !api.chain.Config().IsRandaoForkEnabled(header.Number) {
+		return nil, randao.ErrNoRandomness
+	}
+	parent := api.chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	if parent == nil {
+		return nil, errUnknownBlock
+	}
+	proposer, err := api.istanbul.Author(header)
+	if err != nil {
+		return nil, err
+	}
+
+	proof := &randao.Proof{
+		Number:       (*hexutil.Big)(header.Number),
+		Hash:         header.Hash(),
+		Randomness:   header.MixHash,
+		RandomReveal: header.RandomReveal,
+		PrevMixHash:  headerMixHash(api.chain, parent),
+		Proposer:     proposer,
+	}
+	if pub, err := api.istanbul.blsPubkeyProvider.GetBlsPubkey(api.chain, proposer, header.Number); err == nil {
+		proof.ProposerBlsPublicKey = pub.Marshal()
+	} else {
+		logger.Debug("Failed to get the proposer BLS public key", "number", header.Number, "proposer", proposer, "err", err)
+	}
+	return proof, nil
+}
+
 func (api *APIExtension) makeRPCBlockOutput(b *types.Block,
 	cInfo consensus.ConsensusInfo, transactions types.Transactions, receipts types.Receipts,
 ) map[string]interface{} {
@@ -574,6 +736,61 @@ func (api *API) GetTimeout() uint64 {
 	return istanbul.DefaultConfig.Timeout
 }
 
+// PrivateAPI is the Istanbul RPC API that must not be exposed publicly.
+type PrivateAPI struct {
+	istanbul *backend
+}
+
+// ExportState writes the consensus state of the validator into file, so that
+// the validator can be migrated to another node by ImportState.
+func (api *PrivateAPI) ExportState(file string) (bool, error) {
+	if _, err := os.Stat(file); err == nil {
+		// File already exists. Allowing overwrite could be a DoS vector,
+		// since the 'file' may point to arbitrary paths on the drive
+		return false, errors.New("location would overwrite an existing file")
+	}
+	state, err := api.istanbul.ExportConsensusState()
+	if err != nil {
+		return false, err
+	}
+	blob, err := json.Marshal(state)
+	if err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(file, blob, 0o600); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ImportState loads the consensus state written by ExportState. It must be called
+// before the consensus engine starts, e.g. before starting the miner.
+func (api *PrivateAPI) ImportState(file string) (bool, error) {
+	blob, err := os.ReadFile(file)
+	if err != nil {
+		return false, err
+	}
+	state := new(ConsensusState)
+	if err := json.Unmarshal(blob, state); err != nil {
+		return false, err
+	}
+	if err := api.istanbul.ImportConsensusState(state); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RotateKey schedules the rotation of the node's signing key to the successor key stored in keyfile.
+// The rotation is announced in the blocks proposed by the node and takes effect at activationBlock.
+// It returns the address of the successor key.
+func (api *PrivateAPI) RotateKey(keyfile string, activationBlock hexutil.Uint64) (common.Address, error) {
+	key, err := crypto.LoadECDSA(keyfile)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return api.istanbul.ScheduleKeyRotation(key, uint64(activationBlock))
+}
+
 // Retrieve the header at requested block number
 func headerByRpcNumber(chain consensus.ChainReader, number *rpc.BlockNumber) (*types.Header, error) {
 	var header *types.Header
@@ -44,6 +44,7 @@ import (
 	"github.com/kaiachain/kaia/kaiax"
 	"github.com/kaiachain/kaia/kaiax/staking"
 	"github.com/kaiachain/kaia/log"
+	"github.com/kaiachain/kaia/params"
 	"github.com/kaiachain/kaia/storage/database"
 )
 
@@ -87,6 +88,7 @@ func New(opts *BackendOpts) consensus.Istanbul {
 		governance:        opts.Governance,
 		blsPubkeyProvider: opts.BlsPubkeyProvider,
 		nodetype:          opts.NodeType,
+		gossipStrategy:    istanbul.NewGossipStrategy(opts.IstanbulConfig),
 	}
 	if backend.blsPubkeyProvider == nil {
 		backend.blsPubkeyProvider = newChainBlsPubkeyProvider()
@@ -105,7 +107,10 @@ type backend struct {
 	privateKey       *ecdsa.PrivateKey
 	address          common.Address
 	blsSecretKey     bls.SecretKey
+	keyRotation      *scheduledKeyRotation // the successor key waiting for its activation
+	keyMu            sync.RWMutex          // protects the signing keys and address
 	core             istanbulCore.Engine
+	gossipStrategy   istanbul.GossipStrategy
 	logger           log.Logger
 	db               database.DBManager
 	chain            consensus.ChainReader
@@ -147,6 +152,9 @@ type backend struct {
 	nodetype common.ConnType
 
 	isRestoringSnapshots atomic.Bool
+
+	// Observed commit latency for the adaptive proposal timing
+	commitLatency commitLatencyTracker
 }
 
 func (sb *backend) NodeType() common.ConnType {
@@ -157,12 +165,40 @@ func (sb *backend) GetRewardBase() common.Address {
 	return sb.rewardbase
 }
 
+// QuorumCalculator implements istanbul.Backend.QuorumCalculator
+func (sb *backend) QuorumCalculator(number *big.Int) (istanbul.QuorumCalculator, error) {
+	if sb.chain == nil {
+		return nil, nil
+	}
+	return sb.quorumCalculator(sb.chain.Config(), number)
+}
+
+// quorumCalculator returns the stake-weighted quorum calculator since the StakeWeightedQuorum hardfork.
+// If no staking module is registered, the quorum is computed by the number of committee members.
+// An error is returned if the staking info is unavailable, since the quorum would differ among nodes.
+func (sb *backend) quorumCalculator(config *params.ChainConfig, number *big.Int) (istanbul.QuorumCalculator, error) {
+	if !config.IsStakeWeightedQuorumForkEnabled(number) {
+		return nil, nil
+	}
+	if sb.stakingModule == nil {
+		return istanbul.CountQuorum{}, nil
+	}
+	si, err := sb.stakingModule.GetStakingInfo(number.Uint64())
+	if err != nil {
+		logger.Warn("Failed to get staking info for quorum", "number", number, "err", err)
+		return nil, err
+	}
+	return istanbul.NewStakeQuorum(si), nil
+}
+
 func (sb *backend) SetCurrentView(view *istanbul.View) {
 	sb.currentView.Store(view)
 }
 
 // Address implements istanbul.Backend.Address
 func (sb *backend) Address() common.Address {
+	sb.keyMu.RLock()
+	defer sb.keyMu.RUnlock()
 	return sb.address
 }
 
@@ -253,6 +289,29 @@ func (sb *backend) getTargetReceivers(prevHash common.Hash, valSet istanbul.Vali
 	return targets
 }
 
+// selectGossipTargets narrows down the receivers according to the gossip strategy.
+func (sb *backend) selectGossipTargets(payload []byte, receivers map[common.Address]bool) map[common.Address]bool {
+	if len(receivers) == 0 {
+		return receivers
+	}
+
+	self := sb.Address()
+	origin, ok := istanbulCore.GetMessageSender(payload)
+	if !ok {
+		origin = self
+	}
+	committee := make([]common.Address, 0, len(receivers))
+	for addr := range receivers {
+		committee = append(committee, addr)
+	}
+
+	targets := make(map[common.Address]bool)
+	for _, addr := range sb.gossipStrategy.SelectTargets(self, origin, committee) {
+		targets[addr] = true
+	}
+	return targets
+}
+
 // GossipSubPeer implements istanbul.Backend.Gossip
 func (sb *backend) GossipSubPeer(prevHash common.Hash, valSet istanbul.ValidatorSet, payload []byte) map[common.Address]bool {
 	if !sb.checkInSubList(prevHash, valSet) {
@@ -262,7 +321,7 @@ func (sb *backend) GossipSubPeer(prevHash common.Hash, valSet istanbul.Validator
 	hash := istanbul.RLPHash(payload)
 	sb.knownMessages.Add(hash, true)
 
-	targets := sb.getTargetReceivers(prevHash, valSet)
+	targets := sb.selectGossipTargets(payload, sb.getTargetReceivers(prevHash, valSet))
 
 	if sb.broadcaster != nil && len(targets) > 0 {
 		ps := sb.broadcaster.FindCNPeers(targets)
@@ -313,6 +372,7 @@ func (sb *backend) Commit(proposal istanbul.Proposal, seals [][]byte) error {
 	block = block.WithSeal(h)
 
 	sb.logger.Info("Committed", "number", proposal.Number().Uint64(), "hash", proposal.Hash(), "address", sb.Address())
+	sb.commitLatency.observe(h, now())
 	// - if the proposed and committed blocks are the same, send the proposed hash
 	//   to commit channel, which is being watched inside the engine.Seal() function.
 	// - otherwise, we try to insert the block.
@@ -336,6 +396,12 @@ func (sb *backend) EventMux() *event.TypeMux {
 	return sb.istanbulEventMux
 }
 
+// SubscribeConsensusState notifies the changes of the sequence, the round and the state of the consensus.
+// The events are sent synchronously, so a subscriber must not block.
+func (sb *backend) SubscribeConsensusState(ch chan<- istanbul.ConsensusStateEvent) event.Subscription {
+	return sb.core.SubscribeStateEvent(ch)
+}
+
 // Verify implements istanbul.Backend.Verify
 func (sb *backend) Verify(proposal istanbul.Proposal) (time.Duration, error) {
 	// Check if the proposal is a valid block
@@ -370,6 +436,8 @@ func (sb *backend) Verify(proposal istanbul.Proposal) (time.Duration, error) {
 // Sign implements istanbul.Backend.Sign
 func (sb *backend) Sign(data []byte) ([]byte, error) {
 	hashData := crypto.Keccak256([]byte(data))
+	sb.keyMu.RLock()
+	defer sb.keyMu.RUnlock()
 	return crypto.Sign(hashData, sb.privateKey)
 }
 
@@ -401,6 +469,16 @@ func (sb *backend) GetProposer(number uint64) common.Address {
 	return common.Address{}
 }
 
+// Council implements consensus.Istanbul.Council
+func (sb *backend) Council(number uint64, hash common.Hash) []common.Address {
+	valSet := sb.getValidators(number, hash)
+	council := make([]common.Address, 0, len(valSet.List())+len(valSet.DemotedList()))
+	for _, val := range append(valSet.List(), valSet.DemotedList()...) {
+		council = append(council, val.Address())
+	}
+	return council
+}
+
 // ParentValidators implements istanbul.Backend.GetParentValidators
 func (sb *backend) ParentValidators(proposal istanbul.Proposal) istanbul.ValidatorSet {
 	if block, ok := proposal.(*types.Block); ok {
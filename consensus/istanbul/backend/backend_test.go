@@ -25,6 +25,7 @@ package backend
 import (
 	"bytes"
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
 	"sort"
@@ -1033,3 +1034,34 @@ func TestGetProposer(t *testing.T) {
 		t.Errorf("proposer mismatch: have %v, want %v", actual.Hex(), expected.Hex())
 	}
 }
+
+func TestQuorumCalculator(t *testing.T) {
+	config := &params.ChainConfig{StakeWeightedQuorumCompatibleBlock: big.NewInt(10)}
+	sb := &backend{}
+
+	// Before the fork, the legacy validator count rule applies.
+	if calc, err := sb.quorumCalculator(config, big.NewInt(9)); calc != nil || err != nil {
+		t.Errorf("calculator mismatch: have (%v, %v), want (nil, nil)", calc, err)
+	}
+
+	// Without the staking module, the quorum is computed by the number of committee members.
+	if calc, err := sb.quorumCalculator(config, big.NewInt(10)); calc != (istanbul.CountQuorum{}) || err != nil {
+		t.Errorf("calculator mismatch: have (%v, %v), want (CountQuorum, nil)", calc, err)
+	}
+
+	// The verification fails if the staking info is unavailable.
+	errStaking := errors.New("staking info unavailable")
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mStaking := mock.NewMockStakingModule(mockCtrl)
+	mStaking.EXPECT().GetStakingInfo(uint64(10)).Return(nil, errStaking)
+	mStaking.EXPECT().GetStakingInfo(uint64(11)).Return(makeTestStakingInfo(nil, 0), nil)
+	sb.RegisterStakingModule(mStaking)
+
+	if calc, err := sb.quorumCalculator(config, big.NewInt(10)); calc != nil || err != errStaking {
+		t.Errorf("calculator mismatch: have (%v, %v), want (nil, %v)", calc, err, errStaking)
+	}
+	if calc, err := sb.quorumCalculator(config, big.NewInt(11)); calc == nil || err != nil {
+		t.Errorf("calculator mismatch: have (%v, %v), want (StakeQuorum, nil)", calc, err)
+	}
+}
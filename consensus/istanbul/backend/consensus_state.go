@@ -0,0 +1,154 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/consensus/istanbul"
+	istanbulCore "github.com/kaiachain/kaia/consensus/istanbul/core"
+)
+
+var (
+	errImportWhileStarted    = errors.New("consensus state can only be imported while the engine is stopped")
+	errStateAddressMismatch  = errors.New("consensus state belongs to another validator")
+	errUnknownSnapshotHeader = errors.New("snapshot of the consensus state is not on this chain")
+	errSnapshotMismatch      = errors.New("snapshot of the consensus state differs from the local one")
+)
+
+// ConsensusState is the state of a validator moved to new hardware by
+// istanbul_exportState and istanbul_importState.
+type ConsensusState struct {
+	Core           *istanbulCore.StateDump          `json:"core"`
+	RecentMessages map[common.Address][]common.Hash `json:"recentMessages"` // messages known to each peer
+	Snapshot       *Snapshot                        `json:"snapshot"`       // snapshot of the head block, only compared on import
+}
+
+// ExportConsensusState returns the state of the running engine.
+func (sb *backend) ExportConsensusState() (*ConsensusState, error) {
+	sb.coreMu.RLock()
+	started := sb.coreStarted
+	sb.coreMu.RUnlock()
+	if !started {
+		return nil, istanbul.ErrStoppedEngine
+	}
+
+	dump, err := sb.core.ExportState()
+	if err != nil {
+		return nil, err
+	}
+
+	head := sb.chain.CurrentHeader()
+	snap, err := sb.snapshot(sb.chain, head.Number.Uint64(), head.Hash(), nil, false)
+	if err != nil {
+		return nil, err
+	}
+
+	recentMessages := make(map[common.Address][]common.Hash)
+	for _, key := range sb.recentMessages.Keys() {
+		ms, ok := sb.recentMessages.Get(key)
+		if !ok {
+			continue
+		}
+		var hashes []common.Hash
+		for _, hash := range ms.(*lru.ARCCache).Keys() {
+			hashes = append(hashes, hash.(common.Hash))
+		}
+		recentMessages[key.(common.Address)] = hashes
+	}
+
+	return &ConsensusState{
+		Core:           dump,
+		RecentMessages: recentMessages,
+		Snapshot:       snap,
+	}, nil
+}
+
+// ImportConsensusState loads the state exported by another node of the same validator.
+// The core state is applied when the engine starts, if the chain is still at the same sequence.
+func (sb *backend) ImportConsensusState(state *ConsensusState) error {
+	sb.coreMu.Lock()
+	defer sb.coreMu.Unlock()
+
+	if sb.coreStarted {
+		return errImportWhileStarted
+	}
+	if sb.chain == nil {
+		return errNoChainReader
+	}
+	if state.Core != nil && state.Core.Address != sb.Address() {
+		return errStateAddressMismatch
+	}
+
+	// The snapshot is never taken from the file. It is recomputed from the local chain and must
+	// match the exported one, so that both nodes agree on the validators of the imported state.
+	if snap := state.Snapshot; snap != nil {
+		header := sb.chain.GetHeaderByHash(snap.Hash)
+		if header == nil || header.Number.Uint64() != snap.Number {
+			return errUnknownSnapshotHeader
+		}
+		local, err := sb.snapshot(sb.chain, snap.Number, snap.Hash, nil, false)
+		if err != nil {
+			return err
+		}
+		if equal, err := equalSnapshots(local, snap); err != nil {
+			return err
+		} else if !equal {
+			return errSnapshotMismatch
+		}
+	}
+
+	// Avoid resending the messages that the peers already have.
+	for addr, hashes := range state.RecentMessages {
+		var m *lru.ARCCache
+		if ms, ok := sb.recentMessages.Get(addr); ok {
+			m, _ = ms.(*lru.ARCCache)
+		} else {
+			m, _ = lru.NewARC(inmemoryMessages)
+			sb.recentMessages.Add(addr, m)
+		}
+		for _, hash := range hashes {
+			m.Add(hash, true)
+		}
+	}
+
+	if state.Core != nil {
+		// The replayed messages must not be handled again when they arrive from the peers.
+		for _, payload := range state.Core.Messages {
+			sb.knownMessages.Add(istanbul.RLPHash([]byte(payload)), true)
+		}
+		return sb.core.ImportState(state.Core)
+	}
+	return nil
+}
+
+// equalSnapshots reports whether the snapshots have the same JSON encoding.
+func equalSnapshots(a, b *Snapshot) (bool, error) {
+	ja, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	jb, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(ja, jb), nil
+}
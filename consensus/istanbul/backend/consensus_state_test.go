@@ -0,0 +1,60 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportConsensusStateSnapshot(t *testing.T) {
+	_, engine := newBlockChain(1)
+
+	state, err := engine.ExportConsensusState()
+	require.NoError(t, err)
+	require.NotNil(t, state.Snapshot)
+	require.NoError(t, engine.Stop())
+
+	// The state goes through the file as JSON.
+	reload := func(state *ConsensusState) *ConsensusState {
+		blob, err := json.Marshal(state)
+		require.NoError(t, err)
+		loaded := new(ConsensusState)
+		require.NoError(t, json.Unmarshal(blob, loaded))
+		return loaded
+	}
+
+	// A snapshot that differs from the one recomputed from the local chain is rejected.
+	tampered := reload(state)
+	tampered.Snapshot.Epoch++
+	assert.ErrorIs(t, engine.ImportConsensusState(tampered), errSnapshotMismatch)
+
+	unknown := reload(state)
+	unknown.Snapshot.Hash = common.HexToHash("0x1")
+	assert.ErrorIs(t, engine.ImportConsensusState(unknown), errUnknownSnapshotHeader)
+
+	// The tampered snapshot is never stored.
+	snap, err := engine.snapshot(engine.chain, state.Snapshot.Number, state.Snapshot.Hash, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, state.Snapshot.Epoch, snap.Epoch)
+
+	assert.NoError(t, engine.ImportConsensusState(reload(state)))
+}
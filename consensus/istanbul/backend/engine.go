@@ -24,6 +24,7 @@ package backend
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -37,6 +38,7 @@ import (
 	"github.com/kaiachain/kaia/blockchain/types"
 	"github.com/kaiachain/kaia/blockchain/vm"
 	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/cpulimit"
 	"github.com/kaiachain/kaia/consensus"
 	"github.com/kaiachain/kaia/consensus/istanbul"
 	istanbulCore "github.com/kaiachain/kaia/consensus/istanbul/core"
@@ -48,6 +50,7 @@ import (
 	"github.com/kaiachain/kaia/networks/rpc"
 	"github.com/kaiachain/kaia/params"
 	"github.com/kaiachain/kaia/rlp"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
@@ -173,14 +176,56 @@ func (sb *backend) computeSignatureAddrs(header *types.Header) error {
 		return err
 	}
 
+	// The batch is already preprocessed in the background while the headers are verified,
+	// so the seals are recovered serially not to compete with the verification for the CPUs.
 	proposalSeal := istanbulCore.PrepareCommittedSeal(header.Hash())
-	for _, seal := range istanbulExtra.CommittedSeal {
-		_, err := cacheSignatureAddresses(proposalSeal, seal)
+	_, err = recoverCommittedSealsSerially(proposalSeal, istanbulExtra.CommittedSeal)
+	return err
+}
+
+// recoverCommittedSealsSerially recovers the signers of the committed seals one by one.
+// The signers are returned in the order of the seals, and the recovery stops at the first
+// invalid seal.
+func recoverCommittedSealsSerially(proposalSeal []byte, seals [][]byte) ([]common.Address, error) {
+	addrs := make([]common.Address, len(seals))
+	for i, seal := range seals {
+		addr, err := cacheSignatureAddresses(proposalSeal, seal)
 		if err != nil {
-			return errInvalidSignature
+			return nil, errInvalidSignature
 		}
+		addrs[i] = addr
 	}
-	return nil
+	return addrs, nil
+}
+
+// recoverCommittedSeals recovers the signers of the committed seals concurrently with a bounded
+// number of goroutines. The signers are returned in the order of the seals, and the recovery
+// stops at the first invalid seal.
+func recoverCommittedSeals(proposalSeal []byte, seals [][]byte) ([]common.Address, error) {
+	addrs := make([]common.Address, len(seals))
+	g, ctx := errgroup.WithContext(context.Background())
+	g.SetLimit(cpulimit.NumCPU())
+	for i, seal := range seals {
+		if ctx.Err() != nil {
+			break
+		}
+		i, seal := i, seal
+		g.Go(func() error {
+			if ctx.Err() != nil {
+				return nil
+			}
+			addr, err := cacheSignatureAddresses(proposalSeal, seal)
+			if err != nil {
+				return errInvalidSignature
+			}
+			addrs[i] = addr
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return addrs, nil
 }
 
 // VerifyHeader checks whether a header conforms to the consensus rules of a
@@ -229,9 +274,13 @@ func (sb *backend) verifyHeader(chain consensus.ChainReader, header *types.Heade
 	}
 
 	// Ensure that the extra data format is satisfied
-	if _, err := types.ExtractIstanbulExtra(header); err != nil {
+	istanbulExtra, err := types.ExtractIstanbulExtra(header)
+	if err != nil {
 		return errInvalidExtraDataFormat
 	}
+	if istanbulExtra.KeyRotation != nil && !chain.Config().IsKeyRotationForkEnabled(header.Number) {
+		return errUnexpectedKeyRotation
+	}
 	// Ensure that the block's blockscore is meaningful (may not be correct at this point)
 	if header.BlockScore == nil || header.BlockScore.Cmp(defaultBlockScore) != 0 {
 		return errInvalidBlockScore
@@ -357,6 +406,12 @@ func (sb *backend) verifySigner(chain consensus.ChainReader, header *types.Heade
 	if _, v := snap.ValSet.GetByAddress(signer); v == nil {
 		return errUnauthorized
 	}
+	// The key rotation announced by the signer must be applicable to the snapshot of this block.
+	if chain.Config().IsKeyRotationForkEnabled(header.Number) {
+		if _, err := snap.verifyKeyRotation(header, signer, sb.stakingModule); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -386,23 +441,44 @@ func (sb *backend) verifyCommittedSeals(chain consensus.ChainReader, header *typ
 	validators := snap.ValSet.Copy()
 	// Check whether the committed seals are generated by parent's validators
 	validSeal := 0
+	signers := make([]common.Address, 0, len(extra.CommittedSeal))
 	proposalSeal := istanbulCore.PrepareCommittedSeal(header.Hash())
-	// 1. Get committed seals from current header
-	for _, seal := range extra.CommittedSeal {
-		// 2. Get the original address by seal and parent block hash
-		addr, err := cacheSignatureAddresses(proposalSeal, seal)
-		if err != nil {
-			return errInvalidSignature
-		}
-		// Every validator can have only one seal. If more than one seals are signed by a
+	// 1. Get the original addresses by committed seals from current header
+	addrs, err := recoverCommittedSeals(proposalSeal, extra.CommittedSeal)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		// 2. Every validator can have only one seal. If more than one seals are signed by a
 		// validator, the validator cannot be found and errInvalidCommittedSeals is returned.
 		if validators.RemoveValidator(addr) {
 			validSeal += 1
+			signers = append(signers, addr)
 		} else {
 			return errInvalidCommittedSeals
 		}
 	}
 
+	calc, err := sb.quorumCalculator(chain.Config(), header.Number)
+	if err != nil {
+		return err
+	}
+	if calc != nil {
+		proposer, err := ecrecover(header)
+		if err != nil {
+			return err
+		}
+		view := &istanbul.View{
+			Sequence: new(big.Int).Set(header.Number),
+			Round:    new(big.Int).SetInt64(int64(header.Round())),
+		}
+		committee := snap.ValSet.SubListWithProposer(header.ParentHash, proposer, view)
+		if !calc.HasQuorum(committee, signers) {
+			return errInvalidCommittedSeals
+		}
+		return nil
+	}
+
 	// The length of validSeal should be larger than number of faulty node + 1
 	if validSeal <= 2*snap.ValSet.F() {
 		return errInvalidCommittedSeals
@@ -468,7 +544,7 @@ func (sb *backend) Prepare(chain consensus.ChainReader, header *types.Header) er
 	}
 
 	// if there is a vote to attach, attach it to the header
-	header.Vote = sb.governance.GetEncodedVote(sb.address, number)
+	header.Vote = sb.governance.GetEncodedVote(sb.Address(), number)
 	if len(header.Vote) > 0 {
 		logger.Info("Put voteData", "num", number, "data", hex.EncodeToString(header.Vote))
 	}
@@ -483,8 +559,9 @@ func (sb *backend) Prepare(chain consensus.ChainReader, header *types.Header) er
 		header.MixHash = mixHash
 	}
 
-	// add validators (council list) in snapshot to extraData's validators section
-	extra, err := prepareExtra(header, snap.validators())
+	// add validators (council list) in snapshot to extraData's validators section,
+	// along with the announcement of the key rotation of this node if scheduled
+	extra, err := prepareExtra(header, snap.validators(), sb.announcedKeyRotation(chain.Config(), header.Number, snap))
 	if err != nil {
 		return err
 	}
@@ -546,7 +623,7 @@ func (sb *backend) Finalize(chain consensus.ChainReader, header *types.Header, s
 			valSet := sb.getValidators(lastHeader.Number.Uint64(), lastHeader.Hash())
 
 			var logMsg string
-			_, nodeValidator := valSet.GetByAddress(sb.address)
+			_, nodeValidator := valSet.GetByAddress(sb.Address())
 			if nodeValidator == nil || (nodeValidator.RewardAddress() == common.Address{}) {
 				logMsg = "No reward address for nodeValidator. Use node's rewardbase."
 			} else {
@@ -557,7 +634,7 @@ func (sb *backend) Finalize(chain consensus.ChainReader, header *types.Header, s
 				header.Rewardbase = nodeValidator.RewardAddress()
 				logMsg = "Use reward address for nodeValidator."
 			}
-			logger.Trace(logMsg, "header.Number", header.Number.Uint64(), "node address", sb.address, "rewardbase", header.Rewardbase)
+			logger.Trace(logMsg, "header.Number", header.Number.Uint64(), "node address", sb.Address(), "rewardbase", header.Rewardbase)
 		}
 	}
 
@@ -619,7 +696,7 @@ func (sb *backend) Seal(chain consensus.ChainReader, block *types.Block, stop <-
 	if err != nil {
 		return nil, err
 	}
-	if _, v := snap.ValSet.GetByAddress(sb.address); v == nil {
+	if _, v := snap.ValSet.GetByAddress(sb.Address()); v == nil {
 		return nil, errUnauthorized
 	}
 
@@ -633,7 +710,7 @@ func (sb *backend) Seal(chain consensus.ChainReader, block *types.Block, stop <-
 	}
 
 	// wait for the timestamp of header, use this to adjust the block period
-	delay := time.Unix(block.Header().Time.Int64(), 0).Sub(now())
+	delay := sb.sealDelay(block.Header())
 	select {
 	case <-time.After(delay):
 	case <-stop:
@@ -701,6 +778,11 @@ func (sb *backend) APIs(chain consensus.ChainReader) []rpc.API {
 			Version:   "1.0",
 			Service:   &API{chain: chain, istanbul: sb},
 			Public:    true,
+		}, {
+			Namespace: "istanbul",
+			Version:   "1.0",
+			Service:   &PrivateAPI{istanbul: sb},
+			Public:    false,
 		}, {
 			Namespace: "kaia",
 			Version:   "1.0",
@@ -723,6 +805,26 @@ func (sb *backend) RegisterConsensusModule(modules ...kaiax.ConsensusModule) {
 	sb.consensusModules = append(sb.consensusModules, modules...)
 }
 
+// BlobBaseFee implements blockchain.BlobBaseFeeReader by asking the registered consensus modules.
+func (sb *backend) BlobBaseFee(num uint64) *big.Int {
+	for _, module := range sb.consensusModules {
+		if reader, ok := module.(blockchain.BlobBaseFeeReader); ok {
+			return reader.BlobBaseFee(num)
+		}
+	}
+	return nil
+}
+
+// PausedContracts implements blockchain.PausedContractsReader by asking the registered consensus modules.
+func (sb *backend) PausedContracts(num uint64) map[common.Address]bool {
+	for _, module := range sb.consensusModules {
+		if reader, ok := module.(blockchain.PausedContractsReader); ok {
+			return reader.PausedContracts(num)
+		}
+	}
+	return nil
+}
+
 // Start implements consensus.Istanbul.Start
 func (sb *backend) Start(chain consensus.ChainReader, currentBlock func() *types.Block, hasBadBlock func(hash common.Hash) bool) error {
 	sb.coreMu.Lock()
@@ -993,7 +1095,7 @@ func (sb *backend) snapshot(chain consensus.ChainReader, number uint64, hash com
 	if err != nil {
 		return nil, err
 	}
-	snap, err = snap.apply(headers, sb.governance, sb.address, pset.Policy(), chain, sb.stakingModule, writable)
+	snap, err = snap.apply(headers, sb.governance, sb.Address(), pset.Policy(), chain, sb.stakingModule, writable)
 	if err != nil {
 		return nil, err
 	}
@@ -1108,8 +1210,8 @@ func ecrecover(header *types.Header) (common.Address, error) {
 	return addr, nil
 }
 
-// prepareExtra returns a extra-data of the given header and validators
-func prepareExtra(header *types.Header, vals []common.Address) ([]byte, error) {
+// prepareExtra returns a extra-data of the given header, validators and key rotation announcement
+func prepareExtra(header *types.Header, vals []common.Address, rotation *types.KeyRotation) ([]byte, error) {
 	var buf bytes.Buffer
 
 	// compensate the lack bytes if header.Extra is not enough IstanbulExtraVanity bytes.
@@ -1122,6 +1224,7 @@ func prepareExtra(header *types.Header, vals []common.Address) ([]byte, error) {
 		Validators:    vals,
 		Seal:          []byte{},
 		CommittedSeal: [][]byte{},
+		KeyRotation:   rotation,
 	}
 
 	payload, err := rlp.EncodeToBytes(&ist)
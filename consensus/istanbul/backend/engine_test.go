@@ -575,7 +575,7 @@ func TestPrepareExtra(t *testing.T) {
 		Extra: vanity,
 	}
 
-	payload, err := prepareExtra(h, validators)
+	payload, err := prepareExtra(h, validators, nil)
 	if err != nil {
 		t.Errorf("error mismatch: have %v, want: nil", err)
 	}
@@ -586,7 +586,7 @@ func TestPrepareExtra(t *testing.T) {
 	// append useless information to extra-data
 	h.Extra = append(vanity, make([]byte, 15)...)
 
-	payload, err = prepareExtra(h, validators)
+	payload, err = prepareExtra(h, validators, nil)
 	if !reflect.DeepEqual(payload, expectedResult) {
 		t.Errorf("payload mismatch: have %v, want %v", payload, expectedResult)
 	}
@@ -2054,3 +2054,33 @@ func TestChainConfig_ReadFromDBAfterVotes(t *testing.T) {
 		engine.Stop()
 	}
 }
+
+func TestRecoverCommittedSealsOrder(t *testing.T) {
+	var (
+		hash         = common.HexToHash("0x1234")
+		proposalSeal = core.PrepareCommittedSeal(hash)
+		keys         = make([]*ecdsa.PrivateKey, 30)
+		seals        = make([][]byte, len(keys))
+		expected     = make([]common.Address, len(keys))
+	)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		seals[i], _ = crypto.Sign(crypto.Keccak256(proposalSeal), keys[i])
+		expected[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+	}
+
+	invalid := make([][]byte, len(seals))
+	copy(invalid, seals)
+	invalid[len(invalid)/2] = []byte{0x1}
+
+	for _, recover := range []func([]byte, [][]byte) ([]common.Address, error){recoverCommittedSeals, recoverCommittedSealsSerially} {
+		// Signers are returned in the order of the seals
+		addrs, err := recover(proposalSeal, seals)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, addrs)
+
+		// Any invalid seal fails the whole recovery
+		_, err = recover(proposalSeal, invalid)
+		assert.Equal(t, errInvalidSignature, err)
+	}
+}
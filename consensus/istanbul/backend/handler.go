@@ -91,6 +91,11 @@ func (sb *backend) HandleMsg(addr common.Address, msg p2p.Msg) (bool, error) {
 		}
 		sb.knownMessages.Add(hash, true)
 
+		// NOTE-Kaia: ROUND CHANGE messages are deduplicated by hash like the others. They are
+		// neither aggregated nor deduplicated by (sender, view): every payload carries its own
+		// signature and the message format has no bundle, so either needs a protocol upgrade
+		// that all validators adopt at once.
+
 		go sb.istanbulEventMux.Post(istanbul.MessageEvent{
 			Payload: data,
 			Hash:    cmsg.PrevHash,
@@ -142,6 +147,9 @@ func (sb *backend) NewChainHead() error {
 		return istanbul.ErrStoppedEngine
 	}
 
+	// Switch the signing key before starting the consensus of the next block
+	sb.activateKeyRotation(sb.chain.CurrentHeader())
+
 	go sb.istanbulEventMux.Post(istanbul.FinalCommittedEvent{})
 	return nil
 }
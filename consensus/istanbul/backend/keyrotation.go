@@ -0,0 +1,273 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/crypto/bls"
+	"github.com/kaiachain/kaia/kaiax/staking"
+	"github.com/kaiachain/kaia/params"
+)
+
+var (
+	// errUnexpectedKeyRotation is returned if a key rotation is announced before the KeyRotation fork.
+	errUnexpectedKeyRotation = errors.New("unexpected key rotation")
+	// errInvalidKeyRotation is returned if an announced key rotation is malformed.
+	errInvalidKeyRotation = errors.New("invalid key rotation")
+	// errKeyRotationNotEnabled is returned if a key rotation is requested while the KeyRotation fork is not enabled.
+	errKeyRotationNotEnabled = errors.New("key rotation is not enabled")
+	// errUnregisteredSuccessor is returned if the successor key of a key rotation is not registered for the validator.
+	errUnregisteredSuccessor = errors.New("successor key is not registered for the validator")
+)
+
+// KeyRotation is a pending rotation of a validator's signing key, announced by
+// the validator itself in one of its proposed blocks.
+type KeyRotation struct {
+	Validator       common.Address `json:"validator"`
+	Successor       common.Address `json:"successor"`
+	ActivationBlock uint64         `json:"activationBlock"`
+}
+
+// scheduledKeyRotation is the successor key of this node waiting for its activation block.
+type scheduledKeyRotation struct {
+	privateKey      *ecdsa.PrivateKey
+	blsSecretKey    bls.SecretKey
+	address         common.Address
+	activationBlock uint64
+}
+
+// verifyKeyRotation returns the key rotation announced in the header by its proposer, or nil if there is none.
+// The snapshot must be the one of the parent block. The successor key must be pre-registered for the proposer,
+// see checkSuccessorRegistered. It runs in the header verification, so that a block with a malformed
+// announcement is never committed.
+func (s *Snapshot) verifyKeyRotation(header *types.Header, proposer common.Address, stakingModule staking.StakingModule) (*types.KeyRotation, error) {
+	istanbulExtra, err := types.ExtractIstanbulExtra(header)
+	if err != nil {
+		return nil, err
+	}
+	rotation := istanbulExtra.KeyRotation
+	if rotation == nil {
+		return nil, nil
+	}
+	if rotation.Successor == (common.Address{}) || rotation.Successor == proposer ||
+		rotation.ActivationBlock <= header.Number.Uint64() || s.hasValidator(rotation.Successor) {
+		return nil, errInvalidKeyRotation
+	}
+	if err := checkSuccessorRegistered(stakingModule, header.Number.Uint64(), proposer, rotation.Successor); err != nil {
+		return nil, err
+	}
+	return rotation, nil
+}
+
+// handleKeyRotation records the key rotation announced in the header by its proposer.
+// An announcement replaces the previous pending one of the same validator.
+// The announcement has been checked by verifyKeyRotation before the block was committed,
+// so an error here only asserts the consistency of the snapshot.
+func (s *Snapshot) handleKeyRotation(header *types.Header, proposer common.Address, stakingModule staking.StakingModule) error {
+	rotation, err := s.verifyKeyRotation(header, proposer, stakingModule)
+	if err != nil || rotation == nil {
+		return err
+	}
+
+	entry := KeyRotation{Validator: proposer, Successor: rotation.Successor, ActivationBlock: rotation.ActivationBlock}
+	for i, r := range s.KeyRotations {
+		if r.Validator == proposer {
+			s.KeyRotations[i] = entry
+			return nil
+		}
+	}
+	s.KeyRotations = append(s.KeyRotations, entry)
+	return nil
+}
+
+// checkSuccessorRegistered returns an error unless the successor key is registered in the AddressBook
+// with the reward address of the validator, according to the staking info of the given block.
+// The registration is made by the staking owner of the validator, so a compromised signing key
+// alone cannot hand the validator over to a key of the attacker.
+func checkSuccessorRegistered(stakingModule staking.StakingModule, num uint64, validator, successor common.Address) error {
+	if stakingModule == nil {
+		return errUnregisteredSuccessor
+	}
+	si, err := stakingModule.GetStakingInfo(num)
+	if err != nil {
+		return err
+	}
+	if si == nil {
+		return errUnregisteredSuccessor
+	}
+
+	var rewardAddrs []common.Address
+	for i, nodeId := range si.NodeIds {
+		if nodeId == validator {
+			rewardAddrs = append(rewardAddrs, si.RewardAddrs[i])
+		}
+	}
+	for i, nodeId := range si.NodeIds {
+		if nodeId != successor {
+			continue
+		}
+		for _, rewardAddr := range rewardAddrs {
+			if si.RewardAddrs[i] == rewardAddr {
+				return nil
+			}
+		}
+	}
+	return errUnregisteredSuccessor
+}
+
+// activateKeyRotations replaces the validators whose key rotation is activated at the given block
+// with their successors. A rotation is dropped if the validator has left the council or the
+// successor has joined it in the meantime.
+func (s *Snapshot) activateKeyRotations(number uint64) {
+	pending := s.KeyRotations[:0]
+	for _, r := range s.KeyRotations {
+		if r.ActivationBlock > number {
+			pending = append(pending, r)
+			continue
+		}
+		if !s.hasValidator(r.Validator) || s.hasValidator(r.Successor) {
+			logger.Warn("Drop inapplicable key rotation", "number", number, "validator", r.Validator, "successor", r.Successor)
+			continue
+		}
+		s.ValSet.RemoveValidator(r.Validator)
+		s.ValSet.AddValidator(r.Successor)
+		logger.Info("Rotated validator key", "number", number, "validator", r.Validator, "successor", r.Successor)
+	}
+	s.KeyRotations = pending
+}
+
+// keyRotationOf returns the pending key rotation of the given validator, or nil if there is none.
+func (s *Snapshot) keyRotationOf(validator common.Address) *KeyRotation {
+	for i := range s.KeyRotations {
+		if s.KeyRotations[i].Validator == validator {
+			return &s.KeyRotations[i]
+		}
+	}
+	return nil
+}
+
+// hasValidator returns whether the address is in the council, including demoted validators.
+func (s *Snapshot) hasValidator(addr common.Address) bool {
+	if _, v := s.ValSet.GetByAddress(addr); v != nil {
+		return true
+	}
+	_, v := s.ValSet.GetDemotedByAddress(addr)
+	return v != nil
+}
+
+// ScheduleKeyRotation makes this node announce the rotation of its signing key to the given
+// successor key in its proposed blocks, and sign with the successor key from the activation block.
+// The successor must already be registered in the AddressBook with the reward address of this node,
+// and must be registered in the BLS registry before the activation block.
+func (sb *backend) ScheduleKeyRotation(key *ecdsa.PrivateKey, activationBlock uint64) (common.Address, error) {
+	if sb.chain == nil {
+		return common.Address{}, errNoChainReader
+	}
+	config := sb.chain.Config()
+	next := sb.chain.CurrentHeader().Number.Uint64() + 1
+	if !config.IsKeyRotationForkEnabled(new(big.Int).SetUint64(next)) {
+		return common.Address{}, errKeyRotationNotEnabled
+	}
+
+	successor := crypto.PubkeyToAddress(key.PublicKey)
+	if successor == sb.Address() || activationBlock <= next {
+		return common.Address{}, errInvalidKeyRotation
+	}
+	if err := checkSuccessorRegistered(sb.stakingModule, next, sb.Address(), successor); err != nil {
+		return common.Address{}, err
+	}
+
+	var blsSecretKey bls.SecretKey
+	if config.IsRandaoForkEnabled(new(big.Int).SetUint64(activationBlock)) {
+		sk, err := bls.DeriveFromECDSA(key)
+		if err != nil {
+			return common.Address{}, err
+		}
+		blsSecretKey = sk
+	}
+
+	sb.keyMu.Lock()
+	defer sb.keyMu.Unlock()
+	sb.keyRotation = &scheduledKeyRotation{
+		privateKey:      key,
+		blsSecretKey:    blsSecretKey,
+		address:         successor,
+		activationBlock: activationBlock,
+	}
+	logger.Info("Scheduled key rotation", "validator", sb.address, "successor", successor, "activationBlock", activationBlock)
+	return successor, nil
+}
+
+// announcedKeyRotation returns the key rotation to be announced in the block of the given number,
+// or nil if there is nothing to announce or the snapshot already has the announcement.
+func (sb *backend) announcedKeyRotation(config *params.ChainConfig, number *big.Int, snap *Snapshot) *types.KeyRotation {
+	if !config.IsKeyRotationForkEnabled(number) {
+		return nil
+	}
+
+	sb.keyMu.RLock()
+	defer sb.keyMu.RUnlock()
+	r := sb.keyRotation
+	if r == nil || number.Uint64() >= r.activationBlock {
+		return nil
+	}
+	if pending := snap.keyRotationOf(sb.address); pending != nil &&
+		pending.Successor == r.address && pending.ActivationBlock == r.activationBlock {
+		return nil
+	}
+	return &types.KeyRotation{Successor: r.address, ActivationBlock: r.activationBlock}
+}
+
+// activateKeyRotation switches the signing key of this node to the scheduled successor
+// if the block following the given head is at or after the activation block.
+// The scheduled rotation is discarded if the chain did not accept it.
+func (sb *backend) activateKeyRotation(head *types.Header) {
+	sb.keyMu.RLock()
+	r := sb.keyRotation
+	sb.keyMu.RUnlock()
+	if r == nil || head.Number.Uint64()+1 < r.activationBlock {
+		return
+	}
+
+	snap, err := sb.snapshot(sb.chain, head.Number.Uint64(), head.Hash(), nil, false)
+	if err != nil {
+		logger.Warn("Failed to get snapshot for key rotation", "number", head.Number, "err", err)
+		return
+	}
+
+	sb.keyMu.Lock()
+	defer sb.keyMu.Unlock()
+	if sb.keyRotation != r {
+		return
+	}
+	sb.keyRotation = nil
+	if !snap.hasValidator(r.address) {
+		logger.Error("Discard key rotation not accepted by the chain", "validator", sb.address, "successor", r.address, "activationBlock", r.activationBlock)
+		return
+	}
+	logger.Info("Switched to the successor signing key", "validator", sb.address, "successor", r.address, "activationBlock", r.activationBlock)
+	sb.privateKey, sb.address = r.privateKey, r.address
+	if r.blsSecretKey != nil {
+		sb.blsSecretKey = r.blsSecretKey
+	}
+}
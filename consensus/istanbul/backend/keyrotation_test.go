@@ -0,0 +1,124 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/consensus/istanbul"
+	"github.com/kaiachain/kaia/consensus/istanbul/validator"
+	"github.com/kaiachain/kaia/kaiax/staking"
+	"github.com/kaiachain/kaia/kaiax/staking/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotKeyRotation(t *testing.T) {
+	var (
+		v1 = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		v2 = common.HexToAddress("0x2222222222222222222222222222222222222222")
+		v3 = common.HexToAddress("0x3333333333333333333333333333333333333333")
+		s1 = common.HexToAddress("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+		s2 = common.HexToAddress("0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
+		s3 = common.HexToAddress("0xcccccccccccccccccccccccccccccccccccccccc")
+		r1 = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		r2 = common.HexToAddress("0x1000000000000000000000000000000000000002")
+		r3 = common.HexToAddress("0x1000000000000000000000000000000000000003")
+	)
+
+	// s1 is registered for v1, s2 for v2, and s3 is not registered at all
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mStaking := mock.NewMockStakingModule(mockCtrl)
+	mStaking.EXPECT().GetStakingInfo(gomock.Any()).Return(&staking.StakingInfo{
+		NodeIds:     []common.Address{v1, v2, v3, s1, s2},
+		RewardAddrs: []common.Address{r1, r2, r3, r1, r2},
+	}, nil).AnyTimes()
+
+	makeHeader := func(number uint64, rotation *types.KeyRotation) *types.Header {
+		header := &types.Header{Number: new(big.Int).SetUint64(number)}
+		extra, err := prepareExtra(header, nil, rotation)
+		require.NoError(t, err)
+		header.Extra = extra
+		return header
+	}
+
+	snap := &Snapshot{ValSet: validator.NewSubSet([]common.Address{v1, v2, v3}, istanbul.RoundRobin, 22)}
+
+	// Invalid announcements
+	assert.Equal(t, errInvalidKeyRotation, snap.handleKeyRotation(makeHeader(10, &types.KeyRotation{Successor: common.Address{}, ActivationBlock: 20}), v1, mStaking))
+	assert.Equal(t, errInvalidKeyRotation, snap.handleKeyRotation(makeHeader(10, &types.KeyRotation{Successor: v1, ActivationBlock: 20}), v1, mStaking))
+	assert.Equal(t, errInvalidKeyRotation, snap.handleKeyRotation(makeHeader(10, &types.KeyRotation{Successor: v2, ActivationBlock: 20}), v1, mStaking))
+	assert.Equal(t, errInvalidKeyRotation, snap.handleKeyRotation(makeHeader(10, &types.KeyRotation{Successor: s1, ActivationBlock: 10}), v1, mStaking))
+	assert.Empty(t, snap.KeyRotations)
+
+	// Unregistered successors
+	assert.Equal(t, errUnregisteredSuccessor, snap.handleKeyRotation(makeHeader(10, &types.KeyRotation{Successor: s2, ActivationBlock: 20}), v1, mStaking))
+	assert.Equal(t, errUnregisteredSuccessor, snap.handleKeyRotation(makeHeader(10, &types.KeyRotation{Successor: s3, ActivationBlock: 20}), v1, mStaking))
+	assert.Equal(t, errUnregisteredSuccessor, snap.handleKeyRotation(makeHeader(10, &types.KeyRotation{Successor: s1, ActivationBlock: 20}), v1, nil))
+	assert.Empty(t, snap.KeyRotations)
+
+	// The checks before commit match the snapshot assertions and leave the snapshot untouched
+	_, err := snap.verifyKeyRotation(makeHeader(10, &types.KeyRotation{Successor: v2, ActivationBlock: 20}), v1, mStaking)
+	assert.Equal(t, errInvalidKeyRotation, err)
+	_, err = snap.verifyKeyRotation(makeHeader(10, &types.KeyRotation{Successor: s3, ActivationBlock: 20}), v1, mStaking)
+	assert.Equal(t, errUnregisteredSuccessor, err)
+	rotation, err := snap.verifyKeyRotation(makeHeader(10, &types.KeyRotation{Successor: s1, ActivationBlock: 20}), v1, mStaking)
+	assert.NoError(t, err)
+	assert.Equal(t, &types.KeyRotation{Successor: s1, ActivationBlock: 20}, rotation)
+	assert.Empty(t, snap.KeyRotations)
+
+	// Headers without an announcement are no-op
+	assert.NoError(t, snap.handleKeyRotation(makeHeader(10, nil), v1, mStaking))
+	assert.Empty(t, snap.KeyRotations)
+
+	// A later announcement replaces the pending one
+	assert.NoError(t, snap.handleKeyRotation(makeHeader(10, &types.KeyRotation{Successor: s1, ActivationBlock: 30}), v1, mStaking))
+	assert.NoError(t, snap.handleKeyRotation(makeHeader(11, &types.KeyRotation{Successor: s1, ActivationBlock: 20}), v1, mStaking))
+	assert.Equal(t, []KeyRotation{{Validator: v1, Successor: s1, ActivationBlock: 20}}, snap.KeyRotations)
+	assert.NotNil(t, snap.keyRotationOf(v1))
+	assert.Nil(t, snap.keyRotationOf(v2))
+
+	// Pending rotations survive the JSON round trip
+	blob, err := json.Marshal(snap)
+	require.NoError(t, err)
+	loaded := new(Snapshot)
+	require.NoError(t, json.Unmarshal(blob, loaded))
+	assert.Equal(t, snap.KeyRotations, loaded.KeyRotations)
+
+	// Copies do not share pending rotations
+	cpy := snap.copy()
+	cpy.activateKeyRotations(20)
+	assert.Len(t, snap.KeyRotations, 1)
+
+	// Not yet activated
+	snap.activateKeyRotations(19)
+	assert.Len(t, snap.KeyRotations, 1)
+	assert.True(t, snap.hasValidator(v1))
+
+	// Activated
+	snap.activateKeyRotations(20)
+	assert.Empty(t, snap.KeyRotations)
+	assert.False(t, snap.hasValidator(v1))
+	assert.True(t, snap.hasValidator(s1))
+	assert.Equal(t, uint64(3), snap.ValSet.Size())
+}
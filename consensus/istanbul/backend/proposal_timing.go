@@ -0,0 +1,164 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package backend
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common/hexutil"
+)
+
+const (
+	// latencySmoothingFactor is the weight of the newest sample in the moving
+	// average of the commit latency.
+	latencySmoothingFactor = 0.2
+
+	// minConsensusReserve is the least time left for the consensus messages
+	// after the proposal when computing the block budget.
+	minConsensusReserve = 500 * time.Millisecond
+)
+
+// ProposalTiming is the state of the adaptive proposal timing, returned by
+// istanbul_getProposalTiming.
+type ProposalTiming struct {
+	Adaptive         bool           `json:"adaptive"`
+	AvgCommitLatency time.Duration  `json:"avgCommitLatency"`
+	LastCommitTime   time.Time      `json:"lastCommitTime"`
+	Samples          hexutil.Uint64 `json:"samples"`
+}
+
+// commitLatencyTracker keeps the moving average of the time elapsed between a
+// block's proposal timestamp and its local commit.
+type commitLatencyTracker struct {
+	mu         sync.RWMutex
+	avg        time.Duration
+	lastCommit time.Time
+	samples    uint64
+}
+
+// headerTime returns the proposal timestamp of the header with the fraction
+// of second (10ms unit) included.
+func headerTime(header *types.Header) time.Time {
+	return time.Unix(header.Time.Int64(), int64(header.TimeFoS)*int64(10*time.Millisecond))
+}
+
+// observe records the commit of the given header at commitTime.
+func (t *commitLatencyTracker) observe(header *types.Header, commitTime time.Time) {
+	latency := commitTime.Sub(headerTime(header))
+	if latency < 0 {
+		latency = 0
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.samples == 0 {
+		t.avg = latency
+	} else {
+		t.avg += time.Duration(latencySmoothingFactor * float64(latency-t.avg))
+	}
+	t.lastCommit = commitTime
+	t.samples++
+}
+
+// proposalDelay returns how long to wait before proposing a block with the
+// given header so that the block is committed blockPeriod after the parent.
+// The delay never makes the proposal earlier than the header timestamp.
+func (t *commitLatencyTracker) proposalDelay(header *types.Header, blockPeriod uint64, now time.Time) time.Duration {
+	delay := time.Unix(header.Time.Int64(), 0).Sub(now)
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.samples == 0 {
+		return delay
+	}
+	period := time.Duration(blockPeriod) * time.Second
+	adaptive := t.lastCommit.Add(period - t.avg).Sub(now)
+	if adaptive > period {
+		adaptive = period
+	}
+	if adaptive > delay {
+		return adaptive
+	}
+	return delay
+}
+
+// timing returns the current state of the tracker.
+func (t *commitLatencyTracker) timing(adaptive bool) *ProposalTiming {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return &ProposalTiming{
+		Adaptive:         adaptive,
+		AvgCommitLatency: t.avg,
+		LastCommitTime:   t.lastCommit,
+		Samples:          hexutil.Uint64(t.samples),
+	}
+}
+
+// consensusReserve returns the time the block needs from the proposal to the commit.
+func (t *commitLatencyTracker) consensusReserve() time.Duration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.avg > minConsensusReserve {
+		return t.avg
+	}
+	return minConsensusReserve
+}
+
+// BlockBudget implements consensus.BlockBudgeter. If this node proposes the block in the
+// current round, the budget ends early enough for the block to be committed before the
+// round-change timer fires. A smaller block is preferred over a round change.
+func (sb *backend) BlockBudget(header *types.Header, limit time.Duration) time.Duration {
+	sb.coreMu.RLock()
+	started := sb.coreStarted
+	sb.coreMu.RUnlock()
+	if !started {
+		return limit
+	}
+
+	deadline, ok := sb.core.ProposalDeadline(header.Number)
+	if !ok {
+		return limit
+	}
+	budget := deadline.Add(-sb.commitLatency.consensusReserve()).Sub(now())
+	if budget < 0 {
+		// The first transaction is always allowed to complete.
+		budget = 0
+	}
+	if budget < limit {
+		return budget
+	}
+	return limit
+}
+
+// sealDelay returns how long Seal must wait before proposing the block.
+func (sb *backend) sealDelay(header *types.Header) time.Duration {
+	if !sb.config.AdaptiveBlockPeriod {
+		return time.Unix(header.Time.Int64(), 0).Sub(now())
+	}
+	return sb.commitLatency.proposalDelay(header, sb.config.BlockPeriod, now())
+}
+
+// GetProposalTiming returns the observed commit latency used by the adaptive
+// proposal timing mode.
+func (api *API) GetProposalTiming() *ProposalTiming {
+	return api.istanbul.commitLatency.timing(api.istanbul.config.AdaptiveBlockPeriod)
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package backend
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	istanbulCore "github.com/kaiachain/kaia/consensus/istanbul/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitLatencyTracker(t *testing.T) {
+	var (
+		tracker = &commitLatencyTracker{}
+		base    = time.Unix(1000, 0)
+		parent  = &types.Header{Time: big.NewInt(1000), TimeFoS: 0}
+		header  = &types.Header{Time: big.NewInt(1001), TimeFoS: 0}
+	)
+
+	// Without samples, the proposal waits for the header timestamp only.
+	assert.Equal(t, 700*time.Millisecond, tracker.proposalDelay(header, 1, base.Add(300*time.Millisecond)))
+
+	// The parent was committed 400ms after its proposal.
+	tracker.observe(parent, base.Add(400*time.Millisecond))
+	assert.Equal(t, 400*time.Millisecond, tracker.timing(true).AvgCommitLatency)
+
+	// The next proposal is scheduled so that it commits 1s after the parent.
+	assert.Equal(t, 1000*time.Millisecond, tracker.proposalDelay(header, 1, base))
+	assert.Equal(t, 700*time.Millisecond, tracker.proposalDelay(header, 1, base.Add(300*time.Millisecond)))
+
+	// Never earlier than the header timestamp.
+	tracker.observe(parent, base.Add(100*time.Millisecond))
+	assert.Equal(t, 340*time.Millisecond, tracker.timing(true).AvgCommitLatency)
+	assert.Equal(t, 900*time.Millisecond, tracker.proposalDelay(header, 1, base.Add(100*time.Millisecond)))
+	assert.Equal(t, uint64(2), uint64(tracker.timing(true).Samples))
+}
+
+type deadlineCore struct {
+	istanbulCore.Engine
+	sequence uint64
+	deadline time.Time
+}
+
+func (c *deadlineCore) ProposalDeadline(sequence *big.Int) (time.Time, bool) {
+	return c.deadline, sequence.Uint64() == c.sequence
+}
+
+func TestBlockBudget(t *testing.T) {
+	base := time.Unix(1000, 0)
+	now = func() time.Time { return base }
+	defer func() { now = time.Now }()
+
+	var (
+		limit  = 250 * time.Millisecond
+		header = &types.Header{Number: big.NewInt(10)}
+		core   = &deadlineCore{sequence: 10}
+		sb     = &backend{core: core, coreStarted: true}
+	)
+
+	testcases := []struct {
+		remaining time.Duration
+		expected  time.Duration
+	}{
+		{10 * time.Second, limit},                                            // far from the deadline
+		{minConsensusReserve + limit, limit},                                 // just enough
+		{minConsensusReserve + 100*time.Millisecond, 100 * time.Millisecond}, // close to the deadline
+		{minConsensusReserve / 2, 0},                                         // too late; only the first tx
+	}
+	for _, tc := range testcases {
+		core.deadline = base.Add(tc.remaining)
+		assert.Equal(t, tc.expected, sb.BlockBudget(header, limit), tc.remaining)
+	}
+
+	// Slow consensus reserves more time.
+	sb.commitLatency.observe(&types.Header{Time: big.NewInt(999)}, base)
+	core.deadline = base.Add(1500 * time.Millisecond)
+	assert.Equal(t, 250*time.Millisecond, sb.BlockBudget(header, limit))
+	core.deadline = base.Add(1100 * time.Millisecond)
+	assert.Equal(t, 100*time.Millisecond, sb.BlockBudget(header, limit))
+
+	// Not the proposer of the sequence, or the core is stopped.
+	assert.Equal(t, limit, sb.BlockBudget(&types.Header{Number: big.NewInt(11)}, limit))
+	sb.coreStarted = false
+	core.deadline = base
+	assert.Equal(t, limit, sb.BlockBudget(header, limit))
+}
@@ -27,7 +27,7 @@ import (
 	"github.com/kaiachain/kaia/common"
 	"github.com/kaiachain/kaia/common/hexutil"
 	"github.com/kaiachain/kaia/consensus"
-	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/consensus/istanbul/randao"
 	"github.com/kaiachain/kaia/crypto/bls"
 	"github.com/kaiachain/kaia/params"
 )
@@ -127,7 +127,10 @@ func (p *ChainBlsPubkeyProvider) ResetBlsCache() {
 // Calculate KIP-114 Randao header fields
 // https://github.com/klaytn/kips/blob/kip114/KIPs/kip-114.md
 func (sb *backend) CalcRandao(number *big.Int, prevMixHash []byte) ([]byte, []byte, error) {
-	if sb.blsSecretKey == nil {
+	sb.keyMu.RLock()
+	blsSecretKey := sb.blsSecretKey
+	sb.keyMu.RUnlock()
+	if blsSecretKey == nil {
 		return nil, nil, errNoBlsKey
 	}
 	if len(prevMixHash) != 32 {
@@ -136,13 +139,13 @@ func (sb *backend) CalcRandao(number *big.Int, prevMixHash []byte) ([]byte, []by
 	}
 
 	// block_num_to_bytes() = num.to_bytes(32, byteorder="big")
-	msg := calcRandaoMsg(number)
+	msg := randao.Msg(number)
 
 	// calc_random_reveal() = sign(privateKey, headerNumber)
-	randomReveal := bls.Sign(sb.blsSecretKey, msg[:]).Marshal()
+	randomReveal := bls.Sign(blsSecretKey, msg[:]).Marshal()
 
 	// calc_mix_hash() = xor(prevMixHash, keccak256(randomReveal))
-	mixHash := calcMixHash(randomReveal, prevMixHash)
+	mixHash := randao.MixHash(randomReveal, prevMixHash)
 
 	return randomReveal, mixHash, nil
 }
@@ -166,7 +169,7 @@ func (sb *backend) VerifyRandao(chain consensus.ChainReader, header *types.Heade
 
 	// if not verify(proposerPubkey, newHeader.number, newHeader.randomReveal): return False
 	sig := header.RandomReveal
-	msg := calcRandaoMsg(header.Number)
+	msg := randao.Msg(header.Number)
 	ok, err := bls.VerifySignature(sig, msg, proposerPub)
 	if err != nil {
 		return err
@@ -175,7 +178,7 @@ func (sb *backend) VerifyRandao(chain consensus.ChainReader, header *types.Heade
 	}
 
 	// if not newHeader.mixHash == calc_mix_hash(prevMixHash, newHeader.randomReveal): return False
-	mixHash := calcMixHash(header.RandomReveal, prevMixHash)
+	mixHash := randao.MixHash(header.RandomReveal, prevMixHash)
 	if !bytes.Equal(header.MixHash, mixHash) {
 		return errInvalidRandaoFields
 	}
@@ -183,21 +186,6 @@ func (sb *backend) VerifyRandao(chain consensus.ChainReader, header *types.Heade
 	return nil
 }
 
-// block_num_to_bytes() = num.to_bytes(32, byteorder="big")
-func calcRandaoMsg(number *big.Int) common.Hash {
-	return common.BytesToHash(number.Bytes())
-}
-
-// calc_mix_hash() = xor(prevMixHash, keccak256(randomReveal))
-func calcMixHash(randomReveal, prevMixHash []byte) []byte {
-	mixHash := make([]byte, 32)
-	revealHash := crypto.Keccak256(randomReveal)
-	for i := 0; i < 32; i++ {
-		mixHash[i] = prevMixHash[i] ^ revealHash[i]
-	}
-	return mixHash
-}
-
 // At the fork block's parent, pretend that prevMixHash is ZeroMixHash.
 func headerMixHash(chain consensus.ChainReader, header *types.Header) []byte {
 	if chain.Config().IsRandaoForkBlockParent(header.Number) {
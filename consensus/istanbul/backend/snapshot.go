@@ -52,6 +52,7 @@ type Snapshot struct {
 	CommitteeSize uint64
 	Votes         []governance.GovernanceVote      // List of votes cast in chronological order
 	Tally         []governance.GovernanceTallyItem // Current vote tally to avoid recalculating
+	KeyRotations  []KeyRotation                    // Pending key rotations announced by validators
 }
 
 func effectiveParams(gov governance.Engine, number uint64) (epoch uint64, policy uint64, committeeSize uint64) {
@@ -129,6 +130,10 @@ func (s *Snapshot) copy() *Snapshot {
 
 	copy(cpy.Votes, s.Votes)
 	copy(cpy.Tally, s.Tally)
+	if len(s.KeyRotations) > 0 {
+		cpy.KeyRotations = make([]KeyRotation, len(s.KeyRotations))
+		copy(cpy.KeyRotations, s.KeyRotations)
+	}
 
 	return cpy
 }
@@ -174,6 +179,11 @@ func (s *Snapshot) apply(headers []*types.Header, gov governance.Engine, addr co
 		if _, v := snap.ValSet.GetByAddress(validator); v == nil {
 			return nil, errUnauthorized
 		}
+		if chain.Config().IsKeyRotationForkEnabled(header.Number) {
+			if err := snap.handleKeyRotation(header, validator, stakingModule); err != nil {
+				return nil, err
+			}
+		}
 
 		if number%snap.Epoch == 0 {
 			if writable {
@@ -191,6 +201,8 @@ func (s *Snapshot) apply(headers []*types.Header, gov governance.Engine, addr co
 		snap.Epoch, snap.Policy, snap.CommitteeSize = effectiveParams(gov, number+1)
 
 		snap.ValSet, snap.Votes, snap.Tally = gov.HandleGovernanceVote(snap.ValSet, snap.Votes, snap.Tally, header, validator, addr, writable)
+		// because snapshot(num)'s ValSet = validators for num+1
+		snap.activateKeyRotations(number + 1)
 		if policy == uint64(params.WeightedRandom) {
 			// Snapshot of block N (Snapshot_N) should contain proposers for N+1 and following blocks.
 			// Validators for Block N+1 can be calculated based on the staking information from the previous stakingUpdateInterval block.
@@ -324,6 +336,8 @@ type snapshotJSON struct {
 	ProposersBlockNum uint64           `json:"proposersBlockNum"`
 	DemotedValidators []common.Address `json:"demotedValidators"`
 	MixHash           []byte           `json:"mixHash,omitempty"`
+
+	KeyRotations []KeyRotation `json:"keyRotations,omitempty"`
 }
 
 func (s *Snapshot) toJSONStruct() *snapshotJSON {
@@ -358,6 +372,7 @@ func (s *Snapshot) toJSONStruct() *snapshotJSON {
 		ProposersBlockNum: proposersBlockNum,
 		DemotedValidators: demotedValidators,
 		MixHash:           mixHash,
+		KeyRotations:      s.KeyRotations,
 	}
 }
 
@@ -373,6 +388,9 @@ func (s *Snapshot) UnmarshalJSON(b []byte) error {
 	s.Hash = j.Hash
 	s.Votes = j.Votes
 	s.Tally = j.Tally
+	s.KeyRotations = j.KeyRotations
+	s.Policy = uint64(j.Policy)
+	s.CommitteeSize = j.SubGroupSize
 
 	if j.Policy == istanbul.WeightedRandom {
 		s.ValSet = validator.NewWeightedCouncil(j.Validators, j.DemotedValidators, j.RewardAddrs, j.VotingPowers, j.Weights, j.Policy, j.SubGroupSize, j.Number, j.ProposersBlockNum, nil)
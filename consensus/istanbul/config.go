@@ -36,6 +36,18 @@ type Config struct {
 	ProposerPolicy ProposerPolicy `toml:",omitempty"` // The policy for proposer selection
 	Epoch          uint64         `toml:",omitempty"` // The number of blocks after which to checkpoint and reset the pending votes
 	SubGroupSize   uint64         `toml:",omitempty"`
+
+	// AdaptiveBlockPeriod delays the proposal by the observed commit latency so
+	// that blocks are committed BlockPeriod apart instead of proposed BlockPeriod apart.
+	AdaptiveBlockPeriod bool `toml:",omitempty"`
+
+	GossipPolicy        GossipPolicy `toml:",omitempty"` // The policy for selecting the peers a consensus message is forwarded to
+	GossipFanout        uint64       `toml:",omitempty"` // The number of peers each node forwards to under random and tree gossip
+	GossipFullThreshold uint64       `toml:",omitempty"` // Committees up to this size are fully gossiped regardless of GossipPolicy
+
+	// SessionNonce stamps the consensus messages of this node with session-scoped sequence numbers under its signature.
+	// Enable it once every validator understands the stamped messages.
+	SessionNonce bool `toml:",omitempty"`
 }
 
 // TODO-Kaia-Istanbul: Do not use DefaultConfig except for assigning new config
@@ -45,4 +57,8 @@ var DefaultConfig = &Config{
 	ProposerPolicy: RoundRobin,
 	Epoch:          30000,
 	SubGroupSize:   21,
+
+	GossipPolicy:        FullGossip,
+	GossipFanout:        4,
+	GossipFullThreshold: 32,
 }
@@ -55,6 +55,7 @@ func New(backend istanbul.Backend, config *istanbul.Config) Engine {
 		pendingRequests:    prque.New(),
 		pendingRequestsMu:  new(sync.Mutex),
 		consensusTimestamp: time.Time{},
+		sessions:           newSessions(),
 
 		roundMeter:         metrics.NewRegisteredMeter("consensus/istanbul/core/round", nil),
 		currentRoundGauge:  metrics.NewRegisteredGauge("consensus/istanbul/core/currentRound", nil),
@@ -89,14 +90,18 @@ type core struct {
 	backlogs   map[common.Address]*prque.Prque
 	backlogsMu *sync.Mutex
 
-	current   *roundState
-	handlerWg *sync.WaitGroup
+	current       *roundState
+	importedState *StateDump // applied at the next Start
+	handlerWg     *sync.WaitGroup
 
 	roundChangeSet    *roundChangeSet
 	roundChangeTimer  atomic.Value //*time.Timer
+	roundDeadline     atomic.Value //*roundDeadline
 	pendingRequests   *prque.Prque
 	pendingRequestsMu *sync.Mutex
 
+	sessions *sessions // the session nonces of the sent and received messages
+
 	consensusTimestamp time.Time
 	// the meter to record the round change rate
 	roundMeter metrics.Meter
@@ -111,6 +116,9 @@ type core struct {
 
 	councilSizeGauge   metrics.Gauge
 	committeeSizeGauge metrics.Gauge
+
+	stateFeed      event.Feed
+	lastStateEvent istanbul.ConsensusStateEvent // the last event sent to stateFeed
 }
 
 func (c *core) finalizeMessage(msg *message) ([]byte, error) {
@@ -118,6 +126,11 @@ func (c *core) finalizeMessage(msg *message) ([]byte, error) {
 	// Add sender address
 	msg.Address = c.Address()
 
+	// Add session nonce, which is covered by the signature
+	if c.config.SessionNonce {
+		c.sessions.stamp(msg)
+	}
+
 	// Add proof of consensus
 	msg.CommittedSeal = []byte{}
 	// Assign the CommittedSeal if it's a COMMIT message and proposal is not nil
@@ -261,6 +274,8 @@ func (c *core) startNewRound(round *big.Int) {
 			Round:    new(big.Int),
 		}
 		c.valSet = c.backend.Validators(lastProposal)
+		// The signing address may have been switched by a key rotation
+		c.address = c.backend.Address()
 
 		councilSize := int64(c.valSet.Size())
 		committeeSize := int64(c.valSet.SubGroupSize())
@@ -340,12 +355,42 @@ func (c *core) setState(state State) {
 	if c.state != state {
 		c.state = state
 	}
+	c.sendStateEvent()
 	if state == StateAcceptRequest {
 		c.processPendingRequests()
 	}
 	c.processBacklog()
 }
 
+// sendStateEvent notifies the subscribers if the sequence, the round or the state has changed since the last event.
+func (c *core) sendStateEvent() {
+	if c.current == nil {
+		return
+	}
+	last := c.lastStateEvent
+	if last.Sequence != nil && last.Sequence.Cmp(c.current.Sequence()) == 0 &&
+		last.Round.Cmp(c.current.Round()) == 0 && last.State == c.state.String() {
+		return
+	}
+
+	ev := istanbul.ConsensusStateEvent{
+		Sequence: new(big.Int).Set(c.current.Sequence()),
+		Round:    new(big.Int).Set(c.current.Round()),
+		State:    c.state.String(),
+	}
+	if c.valSet != nil {
+		if proposer := c.valSet.GetProposer(); proposer != nil {
+			ev.Proposer = proposer.Address()
+		}
+	}
+	c.lastStateEvent = ev
+	c.stateFeed.Send(ev)
+}
+
+func (c *core) SubscribeStateEvent(ch chan<- istanbul.ConsensusStateEvent) event.Subscription {
+	return c.stateFeed.Subscribe(ch)
+}
+
 func (c *core) Address() common.Address {
 	return c.address
 }
@@ -377,6 +422,7 @@ func (c *core) newRoundChangeTimer() {
 
 	current := c.current
 	proposer := c.valSet.GetProposer()
+	c.setRoundDeadline(timeout)
 
 	c.roundChangeTimer.Store(time.AfterFunc(timeout, func() {
 		var loc, proposerStr string
@@ -437,11 +483,20 @@ func RequiredMessageCount(valSet istanbul.ValidatorSet) int {
 	} else {
 		size = valSet.Size()
 	}
-	// For less than 4 validators, quorum size equals validator count.
-	if size < 4 {
-		return int(size)
+	return istanbul.QuorumSize(size)
+}
+
+// hasQuorum reports whether the voters form a quorum in the committee built on prevHash.
+// If the backend provides no quorum calculator, the number of voters is compared to RequiredMessageCount.
+// No quorum is reached while the quorum cannot be determined.
+func (c *core) hasQuorum(prevHash common.Hash, voters []common.Address) bool {
+	calc, err := c.backend.QuorumCalculator(c.current.Sequence())
+	if err != nil {
+		c.logger.Warn("Failed to get the quorum calculator", "number", c.current.Sequence(), "err", err)
+		return false
+	}
+	if calc == nil {
+		return len(voters) >= RequiredMessageCount(c.valSet)
 	}
-	// Adopted QBFT quorum implementation
-	// https://github.com/Consensys/quorum/blob/master/consensus/istanbul/qbft/core/core.go#L312
-	return int(math.Ceil(float64(2*size) / 3))
+	return calc.HasQuorum(c.valSet.SubList(prevHash, c.currentView()), voters)
 }
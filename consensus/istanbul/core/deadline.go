@@ -0,0 +1,47 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"time"
+)
+
+// roundDeadline is the time the round-change timer of the current view fires.
+type roundDeadline struct {
+	sequence   uint64
+	canPropose bool // whether this node proposes in the view
+	deadline   time.Time
+}
+
+func (c *core) setRoundDeadline(timeout time.Duration) {
+	c.roundDeadline.Store(&roundDeadline{
+		sequence:   c.current.Sequence().Uint64(),
+		canPropose: c.isProposer() && !c.waitingForRoundChange,
+		deadline:   time.Now().Add(timeout),
+	})
+}
+
+// ProposalDeadline returns when the current round times out if this node is
+// the proposer of the round for the sequence.
+func (c *core) ProposalDeadline(sequence *big.Int) (time.Time, bool) {
+	d, _ := c.roundDeadline.Load().(*roundDeadline)
+	if d == nil || !d.canPropose || !sequence.IsUint64() || d.sequence != sequence.Uint64() {
+		return time.Time{}, false
+	}
+	return d.deadline, true
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kaiachain/kaia/consensus/istanbul"
+	"github.com/kaiachain/kaia/fork"
+	"github.com/kaiachain/kaia/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCore_ProposalDeadline(t *testing.T) {
+	fork.SetHardForkBlockNumberConfig(&params.ChainConfig{})
+	defer fork.ClearHardForkBlockNumberConfig()
+
+	validatorAddrs, _ := genValidators(10)
+	mockBackend, mockCtrl := newMockBackend(t, validatorAddrs)
+	defer mockCtrl.Finish()
+	mockBackend.EXPECT().HasBadProposal(gomock.Any()).Return(false).AnyTimes()
+
+	istConfig := istanbul.DefaultConfig
+	istConfig.ProposerPolicy = istanbul.WeightedRandom
+
+	istCore := New(mockBackend, istConfig).(*core)
+	start := time.Now()
+	require.Nil(t, istCore.Start())
+	defer istCore.Stop()
+
+	var (
+		sequence = istCore.current.Sequence()
+		timeout  = time.Duration(atomic.LoadUint64(&istanbul.DefaultConfig.Timeout)) * time.Millisecond
+	)
+
+	deadline, ok := istCore.ProposalDeadline(sequence)
+	assert.Equal(t, istCore.isProposer(), ok)
+	if ok {
+		assert.WithinDuration(t, start.Add(timeout), deadline, time.Second)
+	}
+
+	// Only the current sequence has a deadline.
+	_, ok = istCore.ProposalDeadline(new(big.Int).Add(sequence, big.NewInt(1)))
+	assert.False(t, ok)
+
+	// Waiting for a round change, the node does not propose.
+	istCore.waitingForRoundChange = true
+	istCore.setRoundDeadline(timeout)
+	_, ok = istCore.ProposalDeadline(sequence)
+	assert.False(t, ok)
+}
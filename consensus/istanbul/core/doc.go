@@ -40,6 +40,7 @@ Core package is composed of following files
   - `request.go`: Implements core methods which handle, check, store and process preprepare messages
   - `roundchange.go`: Implement core methods receiving and handling roundchange messages
   - `roundstate.go`: Defines roundState struct which has messages of each phase for a round
+  - `session.go`: Stamps and verifies the session nonces protecting the consensus messages from being replayed
   - `types.go`: Defines Engine interface and message, State type
 */
 package core
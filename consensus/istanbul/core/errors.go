@@ -41,4 +41,13 @@ var (
 	errFailedDecodeMessageSet = errors.New("failed to decode message set")
 	// errInvalidSigner is returned when the message is signed by a validator different than message sender
 	errInvalidSigner = errors.New("message not signed by the sender")
+	// errStateExportTimeout is returned when the event handler does not export the state in time.
+	errStateExportTimeout = errors.New("timed out exporting consensus state")
+	// errReplayedMsg is returned when a message was already received in the session of its
+	// author, or belongs to a session the author has already left behind.
+	errReplayedMsg = errors.New("replayed consensus message")
+	// errStaleMsg is returned when a message falls behind the replay window of its session.
+	errStaleMsg = errors.New("stale consensus message")
+	// errInvalidStateDump is returned when the imported state lacks its view.
+	errInvalidStateDump = errors.New("invalid consensus state dump")
 )
@@ -35,6 +35,7 @@ func (c *core) Start() error {
 	// Tests will handle events itself, so we have to make subscribeEvents()
 	// be able to call in test.
 	c.subscribeEvents()
+	c.restoreState()
 	c.handlerWg.Add(1)
 	go c.handleEvents()
 
@@ -61,6 +62,7 @@ func (c *core) subscribeEvents() {
 		istanbul.MessageEvent{},
 		// internal events
 		backlogEvent{},
+		stateExportEvent{},
 	)
 	c.timeoutSub = c.backend.EventMux().Subscribe(
 		timeoutEvent{},
@@ -101,7 +103,7 @@ func (c *core) handleEvents() {
 					c.storeRequestMsg(r)
 				}
 			case istanbul.MessageEvent:
-				if err := c.handleMsg(ev.Payload); err == nil {
+				if err := c.handleMsg(ev.Payload); err == nil || (err == errFutureMessage && c.relaysFutureMessage(ev.Payload)) {
 					c.backend.GossipSubPeer(ev.Hash, c.valSet, ev.Payload)
 					// c.backend.Gossip(c.valSet, ev.Payload)
 				}
@@ -121,6 +123,8 @@ func (c *core) handleEvents() {
 					c.backend.GossipSubPeer(ev.Hash, c.valSet, p)
 					// c.backend.Gossip(c.valSet, p)
 				}
+			case stateExportEvent:
+				ev.result <- c.dumpState()
 			}
 		case ev, ok := <-c.timeoutSub.Chan():
 			if !ok || ev.Data == nil {
@@ -183,9 +187,34 @@ func (c *core) handleMsg(payload []byte) error {
 		return istanbul.ErrUnauthorizedAddress
 	}
 
+	// Drop the message replayed into the session of its author
+	if err := c.sessions.verify(msg); err != nil {
+		logger.Debug("Dropped consensus message", "from", msg.Address, "session", msg.Session, "seq", msg.Seq, "err", err)
+		return err
+	}
+
 	return c.handleCheckedMsg(msg, src)
 }
 
+// relaysFutureMessage returns true if a backlogged message must be forwarded right away.
+// Under the partial gossip policies, the members that receive a message only through this
+// node would otherwise miss it until this node catches up the round. Only the future rounds
+// of the current sequence are relayed, since the receivers of other sequences are unknown.
+func (c *core) relaysFutureMessage(payload []byte) bool {
+	if c.config.GossipPolicy == istanbul.FullGossip {
+		return false
+	}
+	msg := new(message)
+	if err := msg.FromPayload(payload, nil); err != nil {
+		return false
+	}
+	view, err := msg.GetView()
+	if err != nil || view == nil || view.Sequence == nil {
+		return false
+	}
+	return view.Sequence.Cmp(c.currentView().Sequence) == 0
+}
+
 func (c *core) handleCheckedMsg(msg *message, src istanbul.Validator) error {
 	logger := c.logger.NewWith("address", c.address, "from", src)
 
@@ -80,6 +80,7 @@ func newMockBackend(t *testing.T, validatorAddrs []common.Address) (*mock_istanb
 	mockBackend.EXPECT().LastProposal().Return(initBlock, validatorAddrs[0]).AnyTimes()
 	mockBackend.EXPECT().Validators(initBlock).Return(validatorSet).AnyTimes()
 	mockBackend.EXPECT().NodeType().Return(common.CONSENSUSNODE).AnyTimes()
+	mockBackend.EXPECT().QuorumCalculator(gomock.Any()).Return(nil, nil).AnyTimes()
 
 	// Set an eventMux in which istanbul core will subscribe istanbul events
 	mockBackend.EXPECT().EventMux().Return(eventMux).AnyTimes()
@@ -836,6 +837,40 @@ func TestCore_handleTimeoutMsg_race(t *testing.T) {
 	}
 }
 
+func TestCore_relaysFutureMessage(t *testing.T) {
+	fork.SetHardForkBlockNumberConfig(&params.ChainConfig{})
+	defer fork.ClearHardForkBlockNumberConfig()
+
+	validatorAddrs, validatorKeys := genValidators(10)
+	mockBackend, mockCtrl := newMockBackend(t, validatorAddrs)
+	defer mockCtrl.Finish()
+
+	istConfig := *istanbul.DefaultConfig
+	istConfig.ProposerPolicy = istanbul.WeightedRandom
+	istConfig.GossipPolicy = istanbul.TreeGossip
+
+	istCore := New(mockBackend, &istConfig).(*core)
+	if err := istCore.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer istCore.Stop()
+
+	lastProposal, _ := mockBackend.LastProposal()
+	sequence := istCore.current.sequence.Int64()
+	payload := func(round, sequence int64) []byte {
+		return makeRCMsgPayload(t, round, sequence, lastProposal.Hash(), validatorAddrs[1], validatorKeys[validatorAddrs[1]])
+	}
+
+	// A future round of the current sequence is relayed before it is backlogged under the tree gossip.
+	assert.True(t, istCore.relaysFutureMessage(payload(5, sequence)))
+	assert.False(t, istCore.relaysFutureMessage(payload(0, sequence+1)))
+	assert.False(t, istCore.relaysFutureMessage([]byte{0x01}))
+
+	// Every member receives the message from the origin under the full gossip.
+	istConfig.GossipPolicy = istanbul.FullGossip
+	assert.False(t, istCore.relaysFutureMessage(payload(5, sequence)))
+}
+
 // makeRCMsgPayload makes a payload of round change message.
 func makeRCMsgPayload(t *testing.T, round int64, sequence int64, prevHash common.Hash, senderAddr common.Address, signerKey *ecdsa.PrivateKey) []byte {
 	subject, err := Encode(&istanbul.Subject{
@@ -97,6 +97,17 @@ func (ms *messageSet) Size() int {
 	return len(ms.messages)
 }
 
+func (ms *messageSet) Addresses() (result []common.Address) {
+	ms.messagesMu.Lock()
+	defer ms.messagesMu.Unlock()
+
+	for addr := range ms.messages {
+		result = append(result, addr)
+	}
+
+	return result
+}
+
 func (ms *messageSet) Get(addr common.Address) *message {
 	ms.messagesMu.Lock()
 	defer ms.messagesMu.Unlock()
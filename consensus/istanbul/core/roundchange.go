@@ -195,6 +195,17 @@ func (rcs *roundChangeSet) Clear(round *big.Int) {
 	}
 }
 
+// Values returns the ROUND CHANGE messages of all rounds
+func (rcs *roundChangeSet) Values() (result []*message) {
+	rcs.mu.Lock()
+	defer rcs.mu.Unlock()
+
+	for _, rms := range rcs.roundChanges {
+		result = append(result, rms.Values()...)
+	}
+	return result
+}
+
 // MaxRound returns the max round which the number of messages is equal or larger than num
 func (rcs *roundChangeSet) MaxRound(num int) *big.Int {
 	rcs.mu.Lock()
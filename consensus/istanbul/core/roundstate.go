@@ -78,6 +78,20 @@ func (s *roundState) GetPrepareOrCommitSize() int {
 	return result
 }
 
+// GetPrepareOrCommitVoters returns the distinct senders of PREPARE or COMMIT messages.
+func (s *roundState) GetPrepareOrCommitVoters() []common.Address {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := s.Commits.Addresses()
+	for _, m := range s.Prepares.Values() {
+		if s.Commits.Get(m.Address) == nil {
+			result = append(result, m.Address)
+		}
+	}
+	return result
+}
+
 func (s *roundState) Subject() *istanbul.Subject {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
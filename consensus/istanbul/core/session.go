@@ -0,0 +1,134 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"slices"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	replayWindowSize   = 64  // Number of sequence numbers below the highest one tracked per session
+	maxRetiredSessions = 16  // Number of superseded sessions remembered per author
+	inmemorySessions   = 200 // Number of authors whose sessions are tracked
+)
+
+// The session nonces are signed by the author of the message along with the rest of it, so that
+// the validators relaying the message can neither restamp nor strip them. A session lasts as long
+// as the core of the author, and the receivers track it by the author regardless of the connections
+// the message arrives over. Hence a reconnection neither resets the replay protection nor rejects
+// the messages in flight. The unstamped messages are accepted for the compatibility and carry no
+// replay protection.
+
+// sessions stamps the messages of this node and tracks the sessions of the received messages.
+type sessions struct {
+	mu       sync.Mutex
+	id       uint64        // the session of the messages of this node
+	seq      uint64        // the sequence number of the last message of this node
+	received *lru.ARCCache // the session of each author, *inSession
+}
+
+func newSessions() *sessions {
+	var b [8]byte
+	for {
+		rand.Read(b[:])
+		if id := binary.BigEndian.Uint64(b[:]); id != 0 {
+			received, _ := lru.NewARC(inmemorySessions)
+			return &sessions{id: id, received: received}
+		}
+	}
+}
+
+// stamp sets the session nonce of a message of this node. It must be called before signing the message.
+func (s *sessions) stamp(msg *message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	msg.Session, msg.Seq = s.id, s.seq
+}
+
+// verify rejects the message if it was already received in the session of its author,
+// or belongs to a session the author has left behind. The signature must have been checked.
+func (s *sessions) verify(msg *message) error {
+	if msg.Session == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var in *inSession
+	if v, ok := s.received.Get(msg.Address); ok {
+		in = v.(*inSession)
+	} else {
+		in = &inSession{}
+		s.received.Add(msg.Address, in)
+	}
+	return in.accept(msg.Session, msg.Seq)
+}
+
+// inSession tracks the session of the messages received from an author.
+type inSession struct {
+	id      uint64
+	highest uint64   // the highest sequence number received in the session
+	window  uint64   // bit i is set if highest-i has been received
+	retired []uint64 // the sessions superseded by a later one
+}
+
+// accept marks the sequence number of the session as received. Messages may arrive out of
+// order within replayWindowSize since they are relayed along different paths.
+func (s *inSession) accept(id, seq uint64) error {
+	if seq == 0 {
+		return errReplayedMsg
+	}
+	if id != s.id {
+		if slices.Contains(s.retired, id) {
+			return errReplayedMsg
+		}
+		if s.id != 0 {
+			s.retired = append(s.retired, s.id)
+			if len(s.retired) > maxRetiredSessions {
+				s.retired = s.retired[1:]
+			}
+		}
+		s.id, s.highest, s.window = id, 0, 0
+	}
+
+	switch {
+	case seq > s.highest:
+		if shift := seq - s.highest; shift < replayWindowSize {
+			s.window <<= shift
+		} else {
+			s.window = 0
+		}
+		s.window |= 1
+		s.highest = seq
+	case s.highest-seq >= replayWindowSize:
+		return errStaleMsg
+	default:
+		bit := uint64(1) << (s.highest - seq)
+		if s.window&bit != 0 {
+			return errReplayedMsg
+		}
+		s.window |= bit
+	}
+	return nil
+}
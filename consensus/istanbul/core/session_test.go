@@ -0,0 +1,138 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/consensus/istanbul"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInSessionAccept(t *testing.T) {
+	s := &inSession{}
+
+	// In order and out of order within the window.
+	assert.NoError(t, s.accept(1, 1))
+	assert.NoError(t, s.accept(1, 3))
+	assert.NoError(t, s.accept(1, 2))
+	assert.ErrorIs(t, s.accept(1, 2), errReplayedMsg)
+	assert.ErrorIs(t, s.accept(1, 3), errReplayedMsg)
+	assert.ErrorIs(t, s.accept(1, 0), errReplayedMsg)
+
+	// Behind the window.
+	assert.NoError(t, s.accept(1, 3+replayWindowSize))
+	assert.ErrorIs(t, s.accept(1, 3), errStaleMsg)
+	assert.NoError(t, s.accept(1, 4))
+
+	// A new session retires the previous one.
+	assert.NoError(t, s.accept(2, 1))
+	assert.ErrorIs(t, s.accept(1, 100), errReplayedMsg)
+	assert.ErrorIs(t, s.accept(2, 1), errReplayedMsg)
+	assert.NoError(t, s.accept(2, 2))
+}
+
+func TestSessionStampAndVerify(t *testing.T) {
+	addrs, keys := genValidators(1)
+	author := addrs[0]
+
+	signedPayload := func(s *sessions) []byte {
+		msg := &message{Code: msgCommit, Msg: []byte{1}, Address: author}
+		if s != nil {
+			s.stamp(msg)
+		}
+		data, err := msg.PayloadNoSig()
+		require.NoError(t, err)
+		msg.Signature, err = crypto.Sign(crypto.Keccak256(data), keys[author])
+		require.NoError(t, err)
+		payload, err := msg.Payload()
+		require.NoError(t, err)
+		return payload
+	}
+	decode := func(payload []byte) *message {
+		msg := new(message)
+		require.NoError(t, msg.FromPayload(payload, istanbul.GetSignatureAddress))
+		return msg
+	}
+
+	var (
+		sender   = newSessions()
+		receiver = newSessions()
+	)
+
+	// The unstamped messages are accepted as before.
+	unstamped := decode(signedPayload(nil))
+	assert.NoError(t, receiver.verify(unstamped))
+	assert.NoError(t, receiver.verify(unstamped))
+
+	// The same stamped message is accepted once, whichever peer relays it.
+	first, second := decode(signedPayload(sender)), decode(signedPayload(sender))
+	assert.Equal(t, first.Session, second.Session)
+	assert.Equal(t, first.Seq+1, second.Seq)
+	assert.NoError(t, receiver.verify(second))
+	assert.NoError(t, receiver.verify(first))
+	assert.ErrorIs(t, receiver.verify(first), errReplayedMsg)
+
+	// The unstamped messages of an author that stamps its messages are still accepted.
+	assert.NoError(t, receiver.verify(unstamped))
+
+	// A restarted author starts a new session, retiring the previous one.
+	next := decode(signedPayload(newSessions()))
+	assert.NotEqual(t, first.Session, next.Session)
+	assert.NoError(t, receiver.verify(next))
+	assert.ErrorIs(t, receiver.verify(decode(signedPayload(sender))), errReplayedMsg)
+
+	// A relay cannot restamp the message, since the nonce is signed by the author.
+	restamped := decode(signedPayload(sender))
+	restamped.Seq += 100
+	payload, err := restamped.Payload()
+	require.NoError(t, err)
+	assert.ErrorIs(t, new(message).FromPayload(payload, istanbul.GetSignatureAddress), errInvalidSigner)
+}
+
+func TestMessageSessionCompatibility(t *testing.T) {
+	type legacyMessage struct {
+		Hash          common.Hash
+		Code          uint64
+		Msg           []byte
+		Address       common.Address
+		Signature     []byte
+		CommittedSeal []byte
+	}
+	legacy := legacyMessage{Hash: common.HexToHash("0x1"), Code: msgCommit, Msg: []byte{1, 2, 3}, Address: common.HexToAddress("0x2"), Signature: []byte{4}, CommittedSeal: []byte{5}}
+	unstamped := &message{Hash: legacy.Hash, Code: legacy.Code, Msg: legacy.Msg, Address: legacy.Address, Signature: legacy.Signature, CommittedSeal: legacy.CommittedSeal}
+
+	// Unstamped messages are encoded as before.
+	enc, err := rlp.EncodeToBytes(unstamped)
+	require.NoError(t, err)
+	legacyEnc, err := rlp.EncodeToBytes(&legacy)
+	require.NoError(t, err)
+	assert.Equal(t, legacyEnc, enc)
+
+	// Stamped messages round-trip.
+	stamped := *unstamped
+	stamped.Session, stamped.Seq = 7, 9
+	enc, err = rlp.EncodeToBytes(&stamped)
+	require.NoError(t, err)
+	var dec message
+	require.NoError(t, rlp.DecodeBytes(enc, &dec))
+	assert.Equal(t, stamped, dec)
+}
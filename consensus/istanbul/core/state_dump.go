@@ -0,0 +1,152 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/consensus/istanbul"
+	"github.com/kaiachain/kaia/rlp"
+)
+
+var stateExportTimeout = 5 * time.Second
+
+// StateDump is the consensus state of a validator in the current sequence.
+// It lets the validator resume on another node without violating its lock.
+type StateDump struct {
+	Address               common.Address `json:"address"`
+	Sequence              *big.Int       `json:"sequence"`
+	Round                 *big.Int       `json:"round"`
+	WaitingForRoundChange bool           `json:"waitingForRoundChange"`
+	LockedHash            common.Hash    `json:"lockedHash"`
+	LockedPreprepare      hexutil.Bytes  `json:"lockedPreprepare,omitempty"` // RLP-encoded istanbul.Preprepare of the locked proposal
+
+	// Signed PREPARE, COMMIT and ROUND CHANGE messages received in the sequence.
+	// They are verified again when replayed after the import.
+	Messages []hexutil.Bytes `json:"messages"`
+}
+
+type stateExportEvent struct {
+	result chan *StateDump
+}
+
+// ExportState returns the consensus state. The state is taken by the event
+// handler so that it is consistent with the messages being processed.
+func (c *core) ExportState() (*StateDump, error) {
+	result := make(chan *StateDump, 1)
+	if err := c.backend.EventMux().Post(stateExportEvent{result: result}); err != nil {
+		return nil, err
+	}
+	select {
+	case dump := <-result:
+		return dump, nil
+	case <-time.After(stateExportTimeout):
+		return nil, errStateExportTimeout
+	}
+}
+
+// ImportState keeps the state until the next Start. The core must be stopped.
+func (c *core) ImportState(dump *StateDump) error {
+	if dump == nil || dump.Sequence == nil || dump.Round == nil {
+		return errInvalidStateDump
+	}
+	c.importedState = dump
+	return nil
+}
+
+func (c *core) dumpState() *StateDump {
+	dump := &StateDump{
+		Address:               c.Address(),
+		Sequence:              new(big.Int).Set(c.current.Sequence()),
+		Round:                 new(big.Int).Set(c.current.Round()),
+		WaitingForRoundChange: c.waitingForRoundChange,
+		LockedHash:            c.current.GetLockedHash(),
+	}
+
+	c.current.mu.RLock()
+	preprepare := c.current.Preprepare
+	c.current.mu.RUnlock()
+	if !common.EmptyHash(dump.LockedHash) && preprepare != nil {
+		if b, err := rlp.EncodeToBytes(preprepare); err == nil {
+			dump.LockedPreprepare = b
+		} else {
+			c.logger.Warn("Failed to encode the locked proposal", "err", err)
+		}
+	}
+
+	msgs := append(c.current.Prepares.Values(), c.current.Commits.Values()...)
+	msgs = append(msgs, c.roundChangeSet.Values()...)
+	for _, msg := range msgs {
+		if payload, err := msg.Payload(); err == nil {
+			dump.Messages = append(dump.Messages, payload)
+		}
+	}
+	return dump
+}
+
+// restoreState applies the imported state if it is still of the current sequence.
+// It must be called after the events are subscribed so that the messages can be replayed.
+func (c *core) restoreState() {
+	dump := c.importedState
+	c.importedState = nil
+	if dump == nil {
+		return
+	}
+	logger := c.logger.NewWith("seq", dump.Sequence, "round", dump.Round)
+	if dump.Sequence.Cmp(c.current.Sequence()) != 0 {
+		logger.Warn("Discard the imported consensus state of another sequence", "current_seq", c.current.Sequence())
+		return
+	}
+
+	lastProposal, lastProposer := c.backend.LastProposal()
+	view := &istanbul.View{
+		Sequence: new(big.Int).Set(dump.Sequence),
+		Round:    new(big.Int).Set(dump.Round),
+	}
+
+	// Keep the lock, so that this node never votes for another proposal in this sequence.
+	var (
+		lockedHash common.Hash
+		preprepare *istanbul.Preprepare
+	)
+	if !common.EmptyHash(dump.LockedHash) {
+		preprepare = new(istanbul.Preprepare)
+		if err := rlp.DecodeBytes(dump.LockedPreprepare, preprepare); err != nil || preprepare.Proposal.Hash() != dump.LockedHash {
+			logger.Error("Discard the imported consensus state with a broken lock", "lockedHash", dump.LockedHash, "err", err)
+			return
+		}
+		lockedHash = dump.LockedHash
+	}
+
+	c.backend.SetCurrentView(view)
+	c.current = newRoundState(view, c.valSet, lockedHash, preprepare, nil, c.backend.HasBadProposal)
+	c.valSet.CalcProposer(lastProposer, view.Round.Uint64())
+	c.waitingForRoundChange = dump.WaitingForRoundChange
+	c.currentRoundGauge.Update(view.Round.Int64())
+	c.newRoundChangeTimer()
+
+	for _, payload := range dump.Messages {
+		go c.backend.EventMux().Post(istanbul.MessageEvent{
+			Hash:    lastProposal.Hash(),
+			Payload: payload,
+		})
+	}
+	logger.Info("Restored the imported consensus state", "locked", !common.EmptyHash(lockedHash), "messages", len(dump.Messages))
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/consensus/istanbul"
+	"github.com/kaiachain/kaia/fork"
+	"github.com/kaiachain/kaia/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCore_ExportImportState(t *testing.T) {
+	fork.SetHardForkBlockNumberConfig(&params.ChainConfig{})
+	defer fork.ClearHardForkBlockNumberConfig()
+
+	validatorAddrs, validatorKeys := genValidators(10)
+	mockBackend, mockCtrl := newMockBackend(t, validatorAddrs)
+	defer mockCtrl.Finish()
+	mockBackend.EXPECT().HasBadProposal(gomock.Any()).Return(false).AnyTimes()
+
+	istConfig := istanbul.DefaultConfig
+	istConfig.ProposerPolicy = istanbul.WeightedRandom
+
+	istCore := New(mockBackend, istConfig).(*core)
+	require.Nil(t, istCore.Start())
+
+	var (
+		eventMux              = mockBackend.EventMux()
+		lastProposal, _       = mockBackend.LastProposal()
+		lastBlock             = lastProposal.(*types.Block)
+		sequence              = istCore.current.sequence.Int64()
+		round           int64 = 5
+	)
+
+	// A ROUND CHANGE message for a future round is kept in the round change set.
+	payload := makeRCMsgPayload(t, round, sequence, lastBlock.Hash(), validatorAddrs[1], validatorKeys[validatorAddrs[1]])
+	require.Nil(t, eventMux.Post(istanbul.MessageEvent{Hash: lastBlock.Hash(), Payload: payload}))
+	time.Sleep(200 * time.Millisecond)
+
+	// Lock a proposal in the current round.
+	proposal, err := genBlock(lastBlock, validatorKeys[validatorAddrs[0]])
+	require.Nil(t, err)
+	istCore.current.SetPreprepare(&istanbul.Preprepare{View: istCore.currentView(), Proposal: proposal})
+	istCore.current.LockHash()
+
+	dump, err := istCore.ExportState()
+	require.Nil(t, err)
+	assert.Equal(t, validatorAddrs[0], dump.Address)
+	assert.Equal(t, sequence, dump.Sequence.Int64())
+	assert.Equal(t, int64(0), dump.Round.Int64())
+	assert.Equal(t, proposal.Hash(), dump.LockedHash)
+	assert.Contains(t, dump.Messages, hexutil.Bytes(payload))
+	require.Nil(t, istCore.Stop())
+
+	// The state of another sequence is discarded.
+	stale := *dump
+	stale.Sequence = big.NewInt(sequence + 1)
+	require.Nil(t, istCore.ImportState(&stale))
+	require.Nil(t, istCore.Start())
+	assert.Equal(t, int64(0), istCore.current.Round().Int64())
+	assert.False(t, istCore.current.IsHashLocked())
+	require.Nil(t, istCore.Stop())
+
+	// The state of the current sequence restores the round and the lock.
+	dump.Round = big.NewInt(round)
+	require.Nil(t, istCore.ImportState(dump))
+	require.Nil(t, istCore.Start())
+	defer istCore.Stop()
+	assert.Equal(t, round, istCore.current.Round().Int64())
+	assert.Equal(t, proposal.Hash(), istCore.current.GetLockedHash())
+	assert.Equal(t, proposal.Hash(), istCore.current.Proposal().Hash())
+
+	assert.NotNil(t, istCore.ImportState(&StateDump{}))
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kaiachain/kaia/consensus/istanbul"
+	"github.com/kaiachain/kaia/fork"
+	"github.com/kaiachain/kaia/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCore_StateEvent(t *testing.T) {
+	fork.SetHardForkBlockNumberConfig(&params.ChainConfig{})
+	defer fork.ClearHardForkBlockNumberConfig()
+
+	validatorAddrs, _ := genValidators(10)
+	mockBackend, mockCtrl := newMockBackend(t, validatorAddrs)
+	defer mockCtrl.Finish()
+	mockBackend.EXPECT().HasBadProposal(gomock.Any()).Return(false).AnyTimes()
+
+	istCore := New(mockBackend, istanbul.DefaultConfig).(*core)
+	events := make(chan istanbul.ConsensusStateEvent, 10)
+	sub := istCore.SubscribeStateEvent(events)
+	defer sub.Unsubscribe()
+
+	require.Nil(t, istCore.Start())
+	defer istCore.Stop()
+
+	receive := func() istanbul.ConsensusStateEvent {
+		select {
+		case ev := <-events:
+			return ev
+		case <-time.After(time.Second):
+			t.Fatal("no consensus state event")
+			return istanbul.ConsensusStateEvent{}
+		}
+	}
+
+	// The first round of the sequence
+	ev := receive()
+	assert.Equal(t, istCore.current.Sequence(), ev.Sequence)
+	assert.Equal(t, int64(0), ev.Round.Int64())
+	assert.Equal(t, StateAcceptRequest.String(), ev.State)
+	assert.Equal(t, istCore.valSet.GetProposer().Address(), ev.Proposer)
+
+	istCore.setState(StatePrepared)
+	ev = receive()
+	assert.Equal(t, StatePrepared.String(), ev.State)
+
+	// No event without a change
+	istCore.setState(StatePrepared)
+	select {
+	case ev := <-events:
+		t.Fatalf("unexpected event: %v", ev)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -26,15 +26,31 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"math/big"
+	"time"
 
 	"github.com/kaiachain/kaia/common"
 	"github.com/kaiachain/kaia/consensus/istanbul"
+	"github.com/kaiachain/kaia/event"
 	"github.com/kaiachain/kaia/rlp"
 )
 
 type Engine interface {
 	Start() error
 	Stop() error
+
+	// ExportState returns the consensus state of the running engine.
+	ExportState() (*StateDump, error)
+	// ImportState restores the state at the next Start. The engine must be stopped.
+	ImportState(dump *StateDump) error
+
+	// ProposalDeadline returns when the current round times out if this node is
+	// the proposer of the round for the sequence.
+	ProposalDeadline(sequence *big.Int) (time.Time, bool)
+
+	// SubscribeStateEvent notifies the changes of the sequence, the round and the state.
+	// The events are sent synchronously, so a subscriber must not block.
+	SubscribeStateEvent(ch chan<- istanbul.ConsensusStateEvent) event.Subscription
 }
 
 type State uint64
@@ -90,6 +106,11 @@ type message struct {
 	Address       common.Address
 	Signature     []byte
 	CommittedSeal []byte
+
+	// Session and Seq scope the message to the session of its author so that it cannot be replayed.
+	// They are signed with the rest of the message, and omitted from the encoding if unset for the compatibility.
+	Session uint64
+	Seq     uint64
 }
 
 // ==============================================
@@ -98,7 +119,10 @@ type message struct {
 
 // EncodeRLP serializes m into the Kaia RLP format.
 func (m *message) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, []interface{}{m.Hash, m.Code, m.Msg, m.Address, m.Signature, m.CommittedSeal})
+	if m.Session == 0 {
+		return rlp.Encode(w, []interface{}{m.Hash, m.Code, m.Msg, m.Address, m.Signature, m.CommittedSeal})
+	}
+	return rlp.Encode(w, []interface{}{m.Hash, m.Code, m.Msg, m.Address, m.Signature, m.CommittedSeal, m.Session, m.Seq})
 }
 
 // DecodeRLP implements rlp.Decoder, and load the consensus fields from a RLP stream.
@@ -110,12 +134,15 @@ func (m *message) DecodeRLP(s *rlp.Stream) error {
 		Address       common.Address
 		Signature     []byte
 		CommittedSeal []byte
+		Session       uint64 `rlp:"optional"`
+		Seq           uint64 `rlp:"optional"`
 	}
 
 	if err := s.Decode(&msg); err != nil {
 		return err
 	}
 	m.Hash, m.Code, m.Msg, m.Address, m.Signature, m.CommittedSeal = msg.Hash, msg.Code, msg.Msg, msg.Address, msg.Signature, msg.CommittedSeal
+	m.Session, m.Seq = msg.Session, msg.Seq
 	return nil
 }
 
@@ -161,6 +188,8 @@ func (m *message) PayloadNoSig() ([]byte, error) {
 		Address:       m.Address,
 		Signature:     []byte{},
 		CommittedSeal: m.CommittedSeal,
+		Session:       m.Session,
+		Seq:           m.Seq,
 	})
 }
 
@@ -193,6 +222,16 @@ func (m *message) GetView() (*istanbul.View, error) {
 	return msgView, nil
 }
 
+// GetMessageSender returns the validator that created the payload. The signature is not
+// checked, so the result must only be used for routing, not for the consensus itself.
+func GetMessageSender(payload []byte) (common.Address, bool) {
+	msg := new(message)
+	if err := msg.FromPayload(payload, nil); err != nil {
+		return common.Address{}, false
+	}
+	return msg.Address, true
+}
+
 // ==============================================
 //
 // helper functions
@@ -0,0 +1,48 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/kaiachain/kaia/consensus/istanbul"
+	"github.com/kaiachain/kaia/tests/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMessageVectors checks that the consensus message vectors for the SDKs are
+// decoded and verified by the consensus core.
+func TestMessageVectors(t *testing.T) {
+	suites, err := vectors.GenerateAll()
+	assert.Nil(t, err)
+
+	for _, suite := range suites {
+		for _, v := range suite.Vectors {
+			if v.Kind != vectors.KindConsensus {
+				continue
+			}
+			msg := new(message)
+			err := msg.FromPayload(v.RLP, istanbul.GetSignatureAddress)
+			assert.Nil(t, err, v.Name)
+			assert.Equal(t, *v.Signer, msg.Address, v.Name)
+
+			view, err := msg.GetView()
+			assert.Nil(t, err, v.Name)
+			assert.Equal(t, int64(1), view.Sequence.Int64(), v.Name)
+		}
+	}
+}
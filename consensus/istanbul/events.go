@@ -22,7 +22,11 @@
 
 package istanbul
 
-import "github.com/kaiachain/kaia/common"
+import (
+	"math/big"
+
+	"github.com/kaiachain/kaia/common"
+)
 
 // RequestEvent is posted to propose a proposal
 type RequestEvent struct {
@@ -41,3 +45,11 @@ type CommitEvent struct {
 
 // FinalCommittedEvent is posted when a proposal is committed
 type FinalCommittedEvent struct{}
+
+// ConsensusStateEvent is sent when the sequence, the round or the state of the consensus core changes.
+type ConsensusStateEvent struct {
+	Sequence *big.Int
+	Round    *big.Int
+	State    string         // e.g. "Accept request", "Preprepared", "Prepared" or "Committed"
+	Proposer common.Address // the proposer of the round
+}
@@ -0,0 +1,193 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package istanbul
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/kaiachain/kaia/common"
+)
+
+type GossipPolicy uint64
+
+const (
+	FullGossip GossipPolicy = iota
+	RandomGossip
+	TreeGossip
+)
+
+func (p GossipPolicy) String() string {
+	switch p {
+	case FullGossip:
+		return "full"
+	case RandomGossip:
+		return "random"
+	case TreeGossip:
+		return "tree"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint64(p))
+	}
+}
+
+// ParseGossipPolicy converts the policy name into a GossipPolicy.
+func ParseGossipPolicy(name string) (GossipPolicy, error) {
+	for _, p := range []GossipPolicy{FullGossip, RandomGossip, TreeGossip} {
+		if p.String() == name {
+			return p, nil
+		}
+	}
+	return FullGossip, fmt.Errorf("unknown gossip policy %q (full, random, tree)", name)
+}
+
+// GossipStrategy selects the committee members a consensus message is forwarded to.
+// Every node forwards a message once when it first accepts it, so a strategy only
+// has to make sure that the forwarding of all nodes together reaches the committee.
+type GossipStrategy interface {
+	// SelectTargets returns a subset of the committee. The committee excludes self.
+	// origin is the validator that created the message.
+	SelectTargets(self, origin common.Address, committee []common.Address) []common.Address
+}
+
+// NewGossipStrategy returns the strategy of the config. Committees not larger than
+// GossipFullThreshold are always fully gossiped, since the saving is negligible there.
+func NewGossipStrategy(config *Config) GossipStrategy {
+	var strategy GossipStrategy
+	switch config.GossipPolicy {
+	case RandomGossip:
+		strategy = &randomGossip{fanout: int(config.GossipFanout)}
+	case TreeGossip:
+		strategy = &treeGossip{fanout: int(config.GossipFanout)}
+	default:
+		return fullGossip{}
+	}
+	return &thresholdGossip{threshold: int(config.GossipFullThreshold), strategy: strategy}
+}
+
+// fullGossip sends to every committee member, which costs O(N²) messages per round.
+type fullGossip struct{}
+
+func (fullGossip) SelectTargets(self, origin common.Address, committee []common.Address) []common.Address {
+	return committee
+}
+
+type thresholdGossip struct {
+	threshold int
+	strategy  GossipStrategy
+}
+
+func (g *thresholdGossip) SelectTargets(self, origin common.Address, committee []common.Address) []common.Address {
+	// The committee excludes self
+	if len(committee)+1 <= g.threshold {
+		return committee
+	}
+	return g.strategy.SelectTargets(self, origin, committee)
+}
+
+// randomGossip sends to fanout members chosen at random. A member misses the message
+// with probability about exp(-fanout), so the fanout is raised to ln(N)+3 for large
+// committees to keep the expected number of missing members well below one.
+type randomGossip struct {
+	fanout int
+}
+
+func (g *randomGossip) SelectTargets(self, origin common.Address, committee []common.Address) []common.Address {
+	fanout := g.fanout
+	if floor := int(math.Ceil(math.Log(float64(len(committee)+1)))) + 3; fanout < floor {
+		fanout = floor
+	}
+	if len(committee) <= fanout {
+		return committee
+	}
+	targets := make([]common.Address, 0, fanout)
+	for _, i := range rand.Perm(len(committee))[:fanout] {
+		targets = append(targets, committee[i])
+	}
+	return targets
+}
+
+// treeGossip arranges the committee into two fanout-ary trees rooted at the origin,
+// one over the members in ascending and one in descending address order, and sends
+// to the children of self in both. The ancestors of a member in one tree all come
+// after it in the other, so a single faulty member cannot cut off any subtree; a
+// member receives a message at most twice, i.e. about 2N messages per round.
+type treeGossip struct {
+	fanout int
+}
+
+func (g *treeGossip) SelectTargets(self, origin common.Address, committee []common.Address) []common.Address {
+	if g.fanout <= 0 {
+		return committee
+	}
+
+	// Sort the members, so that every member builds the same trees.
+	members := make([]common.Address, 0, len(committee))
+	for _, addr := range committee {
+		if addr != origin {
+			members = append(members, addr)
+		}
+	}
+	if self != origin {
+		members = append(members, self)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return bytes.Compare(members[i].Bytes(), members[j].Bytes()) < 0
+	})
+
+	targets := g.children(self, origin, members)
+	for i, j := 0, len(members)-1; i < j; i, j = i+1, j-1 {
+		members[i], members[j] = members[j], members[i]
+	}
+	for _, addr := range g.children(self, origin, members) {
+		if !containsAddress(targets, addr) {
+			targets = append(targets, addr)
+		}
+	}
+	return targets
+}
+
+// children returns the children of self in the tree with the origin at the root
+// and the members in the given order below it.
+func (g *treeGossip) children(self, origin common.Address, members []common.Address) []common.Address {
+	idx := 0
+	if self != origin {
+		for i, addr := range members {
+			if addr == self {
+				idx = i + 1
+				break
+			}
+		}
+	}
+
+	var targets []common.Address
+	for c := idx*g.fanout + 1; c <= idx*g.fanout+g.fanout && c <= len(members); c++ {
+		targets = append(targets, members[c-1])
+	}
+	return targets
+}
+
+func containsAddress(addrs []common.Address, target common.Address) bool {
+	for _, addr := range addrs {
+		if addr == target {
+			return true
+		}
+	}
+	return false
+}
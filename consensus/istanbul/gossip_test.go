@@ -0,0 +1,143 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package istanbul_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/consensus/istanbul"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gossipCommittee(n int) []common.Address {
+	members := make([]common.Address, n)
+	for i := range members {
+		members[i] = common.BigToAddress(big.NewInt(int64(i + 1)))
+	}
+	return members
+}
+
+func without(members []common.Address, self common.Address) []common.Address {
+	var others []common.Address
+	for _, addr := range members {
+		if addr != self {
+			others = append(others, addr)
+		}
+	}
+	return others
+}
+
+// propagate simulates a message from origin, forwarded once by every member that receives it.
+// The faulty members receive but never forward. It returns how many times each member received the message.
+func propagate(strategy istanbul.GossipStrategy, members []common.Address, origin common.Address, faulty ...common.Address) map[common.Address]int {
+	received := map[common.Address]int{origin: 1}
+	queue := []common.Address{origin}
+	for len(queue) > 0 {
+		self := queue[0]
+		queue = queue[1:]
+		for _, target := range strategy.SelectTargets(self, origin, without(members, self)) {
+			received[target]++
+			if received[target] == 1 && !containsAddr(faulty, target) {
+				queue = append(queue, target)
+			}
+		}
+	}
+	return received
+}
+
+func containsAddr(addrs []common.Address, target common.Address) bool {
+	for _, addr := range addrs {
+		if addr == target {
+			return true
+		}
+	}
+	return false
+}
+
+func TestParseGossipPolicy(t *testing.T) {
+	for _, p := range []istanbul.GossipPolicy{istanbul.FullGossip, istanbul.RandomGossip, istanbul.TreeGossip} {
+		parsed, err := istanbul.ParseGossipPolicy(p.String())
+		require.Nil(t, err)
+		assert.Equal(t, p, parsed)
+	}
+	_, err := istanbul.ParseGossipPolicy("mesh")
+	assert.NotNil(t, err)
+}
+
+func TestGossipStrategy_Full(t *testing.T) {
+	members := gossipCommittee(100)
+	strategy := istanbul.NewGossipStrategy(&istanbul.Config{GossipPolicy: istanbul.FullGossip})
+
+	assert.Equal(t, members[1:], strategy.SelectTargets(members[0], members[0], members[1:]))
+}
+
+func TestGossipStrategy_Threshold(t *testing.T) {
+	members := gossipCommittee(10)
+	for _, policy := range []istanbul.GossipPolicy{istanbul.RandomGossip, istanbul.TreeGossip} {
+		strategy := istanbul.NewGossipStrategy(&istanbul.Config{GossipPolicy: policy, GossipFanout: 2, GossipFullThreshold: 10})
+		assert.Equal(t, members[1:], strategy.SelectTargets(members[0], members[0], members[1:]), policy.String())
+	}
+}
+
+func TestGossipStrategy_Random(t *testing.T) {
+	var (
+		members  = gossipCommittee(200)
+		self     = members[0]
+		strategy = istanbul.NewGossipStrategy(&istanbul.Config{GossipPolicy: istanbul.RandomGossip, GossipFanout: 12})
+	)
+	targets := strategy.SelectTargets(self, self, members[1:])
+	assert.Len(t, targets, 12)
+
+	seen := make(map[common.Address]bool)
+	for _, addr := range targets {
+		assert.NotEqual(t, self, addr)
+		assert.False(t, seen[addr], "duplicate target")
+		seen[addr] = true
+	}
+
+	// The fanout is raised for large committees: ceil(ln(200)) + 3 = 9
+	strategy = istanbul.NewGossipStrategy(&istanbul.Config{GossipPolicy: istanbul.RandomGossip, GossipFanout: 2})
+	assert.Len(t, strategy.SelectTargets(self, self, members[1:]), 9)
+}
+
+func TestGossipStrategy_Tree(t *testing.T) {
+	members := gossipCommittee(200)
+	strategy := istanbul.NewGossipStrategy(&istanbul.Config{GossipPolicy: istanbul.TreeGossip, GossipFanout: 4})
+
+	for _, origin := range []common.Address{members[0], members[77], members[199]} {
+		received := propagate(strategy, members, origin)
+
+		// Every member receives the message at most twice, once per tree.
+		assert.Len(t, received, len(members))
+		for addr, count := range received {
+			assert.LessOrEqual(t, count, 2, addr.Hex())
+		}
+
+		// A single faulty member does not cut off anyone.
+		for _, faulty := range []common.Address{members[1], members[100], members[198]} {
+			if faulty != origin {
+				assert.Len(t, propagate(strategy, members, origin, faulty), len(members), faulty.Hex())
+			}
+		}
+	}
+
+	// The origin sends to its children in both trees.
+	assert.Len(t, strategy.SelectTargets(members[5], members[5], without(members, members[5])), 8)
+}
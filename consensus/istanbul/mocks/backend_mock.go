@@ -249,6 +249,21 @@ func (mr *MockBackendMockRecorder) ParentValidators(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParentValidators", reflect.TypeOf((*MockBackend)(nil).ParentValidators), arg0)
 }
 
+// QuorumCalculator mocks base method
+func (m *MockBackend) QuorumCalculator(arg0 *big.Int) (istanbul.QuorumCalculator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuorumCalculator", arg0)
+	ret0, _ := ret[0].(istanbul.QuorumCalculator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QuorumCalculator indicates an expected call of QuorumCalculator
+func (mr *MockBackendMockRecorder) QuorumCalculator(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuorumCalculator", reflect.TypeOf((*MockBackend)(nil).QuorumCalculator), arg0)
+}
+
 // SetCurrentView mocks base method
 func (m *MockBackend) SetCurrentView(arg0 *istanbul.View) {
 	m.ctrl.T.Helper()
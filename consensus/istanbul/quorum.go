@@ -0,0 +1,101 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package istanbul
+
+import (
+	"math"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/staking"
+)
+
+// QuorumCalculator decides whether a set of voters is large enough to proceed the consensus.
+type QuorumCalculator interface {
+	// HasQuorum reports whether the voters form a quorum of the committee.
+	// Voters that are not in the committee are ignored.
+	HasQuorum(committee []Validator, voters []common.Address) bool
+}
+
+// QuorumSize returns the minimum number of votes among n validators to proceed the consensus.
+func QuorumSize(n uint64) int {
+	// For less than 4 validators, quorum size equals validator count.
+	if n < 4 {
+		return int(n)
+	}
+	// Adopted QBFT quorum implementation
+	// https://github.com/Consensys/quorum/blob/master/consensus/istanbul/qbft/core/core.go#L312
+	return int(math.Ceil(float64(2*n) / 3))
+}
+
+// CountQuorum computes the quorum by the number of validators.
+type CountQuorum struct{}
+
+func (CountQuorum) HasQuorum(committee []Validator, voters []common.Address) bool {
+	return len(committeeVoters(committee, voters)) >= QuorumSize(uint64(len(committee)))
+}
+
+// StakeQuorum computes the quorum by the staking amounts of validators. The voters
+// must hold more than 2/3 of the total staking amount of the committee.
+type StakeQuorum struct {
+	stakes map[common.Address]uint64
+}
+
+// NewStakeQuorum returns a StakeQuorum whose staking amounts are read from the staking info.
+// Staking amounts of the node ids sharing a reward address are summed up, so that each of
+// those node ids votes with the total amount.
+func NewStakeQuorum(si *staking.StakingInfo) *StakeQuorum {
+	stakes := make(map[common.Address]uint64)
+	for _, node := range si.ConsolidatedNodes() {
+		for _, nodeId := range node.NodeIds {
+			stakes[nodeId] = node.StakingAmount
+		}
+	}
+	return &StakeQuorum{stakes: stakes}
+}
+
+// HasQuorum falls back to CountQuorum if the committee has no stake at all.
+func (q *StakeQuorum) HasQuorum(committee []Validator, voters []common.Address) bool {
+	total := uint64(0)
+	for _, val := range committee {
+		total += q.stakes[val.Address()]
+	}
+	if total == 0 {
+		return CountQuorum{}.HasQuorum(committee, voters)
+	}
+
+	voted := uint64(0)
+	for _, addr := range committeeVoters(committee, voters) {
+		voted += q.stakes[addr]
+	}
+	return 3*voted > 2*total
+}
+
+// committeeVoters returns the distinct voters who are in the committee.
+func committeeVoters(committee []Validator, voters []common.Address) []common.Address {
+	members := make(map[common.Address]bool, len(committee))
+	for _, val := range committee {
+		members[val.Address()] = true
+	}
+	result := make([]common.Address, 0, len(voters))
+	for _, addr := range voters {
+		if members[addr] {
+			result = append(result, addr)
+			delete(members, addr)
+		}
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package istanbul_test
+
+import (
+	"testing"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/consensus/istanbul"
+	"github.com/kaiachain/kaia/consensus/istanbul/validator"
+	"github.com/kaiachain/kaia/kaiax/staking"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuorumSize(t *testing.T) {
+	for n, expected := range []int{0, 1, 2, 3, 3, 4, 4, 5, 6, 6, 7} {
+		assert.Equal(t, expected, istanbul.QuorumSize(uint64(n)), "n=%d", n)
+	}
+}
+
+func TestQuorumCalculator(t *testing.T) {
+	var (
+		n1 = common.HexToAddress("0x0000000000000000000000000000000000000001")
+		n2 = common.HexToAddress("0x0000000000000000000000000000000000000002")
+		n3 = common.HexToAddress("0x0000000000000000000000000000000000000003")
+		n4 = common.HexToAddress("0x0000000000000000000000000000000000000004")
+		nx = common.HexToAddress("0x00000000000000000000000000000000000000ff")
+
+		committee = []istanbul.Validator{validator.New(n1), validator.New(n2), validator.New(n3), validator.New(n4)}
+
+		// n1 and n2 share a reward address, so each of them votes with 5M+1M.
+		si = &staking.StakingInfo{
+			NodeIds:          []common.Address{n1, n2, n3, n4},
+			StakingContracts: []common.Address{{0x11}, {0x12}, {0x13}, {0x14}},
+			RewardAddrs:      []common.Address{{0x21}, {0x21}, {0x23}, {0x24}},
+			StakingAmounts:   []uint64{5_000_000, 1_000_000, 2_000_000, 2_000_000},
+		}
+		noStake = &staking.StakingInfo{
+			NodeIds:          []common.Address{n1, n2, n3, n4},
+			StakingContracts: []common.Address{{0x11}, {0x12}, {0x13}, {0x14}},
+			RewardAddrs:      []common.Address{{0x21}, {0x22}, {0x23}, {0x24}},
+			StakingAmounts:   []uint64{0, 0, 0, 0},
+		}
+	)
+
+	testcases := []struct {
+		name     string
+		calc     istanbul.QuorumCalculator
+		voters   []common.Address
+		expected bool
+	}{
+		{"count 3 of 4", istanbul.CountQuorum{}, []common.Address{n1, n3, n4}, true},
+		{"count 2 of 4", istanbul.CountQuorum{}, []common.Address{n3, n4}, false},
+		{"count duplicated", istanbul.CountQuorum{}, []common.Address{n3, n4, n4}, false},
+		{"count non-committee", istanbul.CountQuorum{}, []common.Address{n3, n4, nx}, false},
+		// total = 6M+6M+2M+2M = 16M, quorum requires more than 32M/3
+		{"stake 12M", istanbul.NewStakeQuorum(si), []common.Address{n1, n2}, true},
+		{"stake 10M", istanbul.NewStakeQuorum(si), []common.Address{n1, n3, n4}, false},
+		{"stake 8M", istanbul.NewStakeQuorum(si), []common.Address{n2, n3}, false},
+		{"stake duplicated", istanbul.NewStakeQuorum(si), []common.Address{n3, n3, n4, n4, nx}, false},
+		{"no stake 3 of 4", istanbul.NewStakeQuorum(noStake), []common.Address{n1, n2, n3}, true},
+		{"no stake 2 of 4", istanbul.NewStakeQuorum(noStake), []common.Address{n1, n2}, false},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.calc.HasQuorum(committee, tc.voters))
+		})
+	}
+}
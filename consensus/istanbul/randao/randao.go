@@ -0,0 +1,95 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package randao verifies the KIP-114 randomness of the blocks.
+// https://github.com/klaytn/kips/blob/kip114/KIPs/kip-114.md
+package randao
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/crypto/bls"
+)
+
+var (
+	ErrNoRandomness   = errors.New("block has no randomness")
+	ErrNoPublicKey    = errors.New("proposer BLS public key is not given")
+	ErrInvalidFields  = errors.New("invalid randao fields")
+	ErrInvalidReveal  = errors.New("random reveal is not signed by the proposer")
+	ErrInvalidMixHash = errors.New("mix hash does not match the random reveal")
+)
+
+// Msg returns the message signed by the proposer of the block.
+// block_num_to_bytes() = num.to_bytes(32, byteorder="big")
+func Msg(number *big.Int) common.Hash {
+	return common.BytesToHash(number.Bytes())
+}
+
+// MixHash returns the mix hash of the block.
+// calc_mix_hash() = xor(prevMixHash, keccak256(randomReveal))
+func MixHash(randomReveal, prevMixHash []byte) []byte {
+	mixHash := make([]byte, 32)
+	revealHash := crypto.Keccak256(randomReveal)
+	for i := 0; i < 32; i++ {
+		mixHash[i] = prevMixHash[i] ^ revealHash[i]
+	}
+	return mixHash
+}
+
+// Proof is the randomness of a block along with the material to verify it.
+// The randomness of consecutive blocks is chained: PrevMixHash equals the Randomness of the parent,
+// except at the Randao fork block where it is params.ZeroMixHash.
+type Proof struct {
+	Number               *hexutil.Big   `json:"number"`
+	Hash                 common.Hash    `json:"hash"`
+	Randomness           hexutil.Bytes  `json:"randomness"` // header.MixHash
+	RandomReveal         hexutil.Bytes  `json:"randomReveal"`
+	PrevMixHash          hexutil.Bytes  `json:"prevMixHash"`
+	Proposer             common.Address `json:"proposer"`
+	ProposerBlsPublicKey hexutil.Bytes  `json:"proposerBlsPublicKey,omitempty"` // empty if the registry state is unavailable
+}
+
+// Verify checks that the random reveal is the signature of the proposer on the block number,
+// and that the randomness is derived from the random reveal and the previous mix hash.
+// The proposer BLS public key must be the one registered in the KIP-113 contract at the parent block.
+func (p *Proof) Verify() error {
+	if p.Number == nil || len(p.PrevMixHash) != 32 || len(p.Randomness) != 32 {
+		return ErrInvalidFields
+	}
+	if !bytes.Equal(p.Randomness, MixHash(p.RandomReveal, p.PrevMixHash)) {
+		return ErrInvalidMixHash
+	}
+	if len(p.ProposerBlsPublicKey) == 0 {
+		return ErrNoPublicKey
+	}
+
+	pub, err := bls.PublicKeyFromBytes(p.ProposerBlsPublicKey)
+	if err != nil {
+		return err
+	}
+	ok, err := bls.VerifySignature(p.RandomReveal, Msg(p.Number.ToInt()), pub)
+	if err != nil {
+		return err
+	} else if !ok {
+		return ErrInvalidReveal
+	}
+	return nil
+}
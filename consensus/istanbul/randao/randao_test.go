@@ -0,0 +1,77 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package randao
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/crypto/bls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testSk = hexutil.MustDecode("0x6c605527c8e4f31c959478801d51384d690a22dfc6438604646f7709032c893a")
+
+	testNum    = big.NewInt(31337)
+	testMsg    = common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000007a69")
+	testReveal = hexutil.MustDecode("0xadfe25ced45819332cbf088f01cdd2807686dd6309b11d7440237dd623624f401d4753747f5fb92374235e997edcd18318bae2806a1675b1e685e792abd1fbdf5c50ec1e148cc7fe861984d8bc3204c1b2136725b176902bc52eeb595919df3b")
+	testMix1   = hexutil.MustDecode("0x8019df1a2a9f833dc7f400a15b33e54a5c80295165c5953dc23891aab9203810")
+	testMix2   = hexutil.MustDecode("0x8772d58248bdf34e81ecbf36f28299cfa758b61ccf3f64e1dc0646687a55892f")
+)
+
+func TestMixHash(t *testing.T) {
+	assert.Equal(t, testMsg, Msg(testNum))
+	assert.Equal(t, testMix2, MixHash(testReveal, testMix1))
+}
+
+func TestVerifyMixHash(t *testing.T) {
+	proof := &Proof{
+		Number:       (*hexutil.Big)(testNum),
+		Randomness:   testMix2,
+		RandomReveal: testReveal,
+		PrevMixHash:  testMix1,
+	}
+	assert.ErrorIs(t, proof.Verify(), ErrNoPublicKey)
+
+	proof.PrevMixHash = testMix2
+	assert.ErrorIs(t, proof.Verify(), ErrInvalidMixHash)
+
+	proof.PrevMixHash = nil
+	assert.ErrorIs(t, proof.Verify(), ErrInvalidFields)
+}
+
+func TestVerify(t *testing.T) {
+	sk, err := bls.SecretKeyFromBytes(testSk)
+	require.NoError(t, err)
+
+	proof := &Proof{
+		Number:               (*hexutil.Big)(testNum),
+		Randomness:           testMix2,
+		RandomReveal:         testReveal,
+		PrevMixHash:          testMix1,
+		ProposerBlsPublicKey: sk.PublicKey().Marshal(),
+	}
+	assert.NoError(t, proof.Verify())
+
+	// The reveal of another block
+	proof.Number = (*hexutil.Big)(big.NewInt(31338))
+	assert.ErrorIs(t, proof.Verify(), ErrInvalidReveal)
+}
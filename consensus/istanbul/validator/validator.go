@@ -155,7 +155,7 @@ func SelectRandaoCommittee(validators []istanbul.Validator, committeeSize uint64
 		return validators
 	}
 
-	seed := int64(binary.BigEndian.Uint64(mixHash[:8]))
+	seed := RandaoSeed(mixHash)
 	size := committeeSize
 	if committeeSize > uint64(len(validators)) {
 		size = uint64(len(validators))
@@ -163,6 +163,11 @@ func SelectRandaoCommittee(validators []istanbul.Validator, committeeSize uint64
 	return shuffleValidators(validators, seed)[:size]
 }
 
+// RandaoSeed returns the PRNG seed derived from the mixHash, which shuffles the validators after the Randao fork.
+func RandaoSeed(mixHash []byte) int64 {
+	return int64(binary.BigEndian.Uint64(mixHash[:8]))
+}
+
 func shuffleValidators(validators istanbul.Validators, seed int64) []istanbul.Validator {
 	ret := make([]istanbul.Validator, len(validators))
 	copy(ret, validators)
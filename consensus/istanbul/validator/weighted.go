@@ -310,6 +310,45 @@ func weightedRandomProposer(valSet istanbul.ValidatorSet, lastProposer common.Ad
 	return proposer
 }
 
+// ProposerOrdering returns the validators in the order they take the proposer role by round,
+// i.e. the proposer of round r is ordering[r % len(ordering)]. After the Randao fork, the ordering is
+// the committee shuffled by the given mixHash, or by the council's mixHash if nil. Before the Randao fork,
+// it is the weighted proposers list starting from the proposer of round 0 and the mixHash is ignored.
+func ProposerOrdering(valSet istanbul.ValidatorSet, mixHash []byte) ([]istanbul.Validator, error) {
+	weightedCouncil, ok := valSet.(*weightedCouncil)
+	if !ok {
+		return nil, errors.New("not weightedCouncil type")
+	}
+
+	rules := fork.Rules(new(big.Int).SetUint64(weightedCouncil.blockNum + 1))
+	if rules.IsRandao {
+		if mixHash == nil {
+			mixHash = weightedCouncil.mixHash
+		}
+		if len(mixHash) < 8 {
+			return nil, errors.New("invalid mixHash")
+		}
+		committee := SelectRandaoCommittee(weightedCouncil.List(), weightedCouncil.subSize, mixHash)
+		if len(committee) == 0 {
+			return nil, errors.New("no available proposers")
+		}
+		return committee, nil
+	}
+
+	numProposers := uint64(len(weightedCouncil.proposers))
+	if numProposers == 0 {
+		return nil, errors.New("no available proposers")
+	}
+	// Same as the picker of weightedRandomProposer for round 0
+	blockNum := weightedCouncil.blockNum
+	offset := (blockNum - params.CalcProposerBlockNumber(blockNum+1)) % numProposers
+	ordering := make([]istanbul.Validator, 0, numProposers)
+	for i := uint64(0); i < numProposers; i++ {
+		ordering = append(ordering, weightedCouncil.proposers[(offset+i)%numProposers])
+	}
+	return ordering, nil
+}
+
 func (valSet *weightedCouncil) Size() uint64 {
 	valSet.validatorMu.RLock()
 	defer valSet.validatorMu.RUnlock()
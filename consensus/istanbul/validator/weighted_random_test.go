@@ -660,3 +660,37 @@ func TestWeightedCouncil_Copy(t *testing.T) {
 
 	assert.Equal(t, a.GetProposer(), b.GetProposer())
 }
+
+func TestProposerOrdering(t *testing.T) {
+	forkNum := uint64(5)
+	fork.SetHardForkBlockNumberConfig(&params.ChainConfig{
+		RandaoCompatibleBlock: big.NewInt(int64(forkNum)),
+	})
+	defer fork.ClearHardForkBlockNumberConfig()
+
+	valSet := makeTestWeightedCouncil(testNonZeroWeights)
+	runRefreshForTest(valSet)
+
+	for _, blockNum := range []uint64{forkNum - 3, forkNum - 2, forkNum, forkNum + 1} {
+		valSet.SetBlockNum(blockNum)
+		valSet.SetSubGroupSize(uint64(len(testAddrs)) - 2)
+		valSet.SetMixHash(testMixHash)
+
+		ordering, err := ProposerOrdering(valSet, nil)
+		assert.NoError(t, err)
+		for round := uint64(0); round < uint64(2*len(ordering)); round++ {
+			valSet.CalcProposer(common.Address{}, round)
+			assert.Equal(t, valSet.GetProposer(), ordering[round%uint64(len(ordering))], "blockNum %d round %d", blockNum, round)
+		}
+	}
+
+	// The given mixHash overrides the council's one after the fork
+	valSet.SetBlockNum(forkNum)
+	otherMixHash := crypto.Keccak256(testMixHash)
+	ordering, err := ProposerOrdering(valSet, otherMixHash)
+	assert.NoError(t, err)
+	assert.Equal(t, SelectRandaoCommittee(valSet.List(), valSet.SubGroupSize(), otherMixHash), ordering)
+
+	_, err = ProposerOrdering(valSet, []byte{0x1})
+	assert.Error(t, err)
+}
@@ -31,6 +31,9 @@ var Modules = map[string]string{
 	"personal":         Personal_JS,
 	"rpc":              RPC_JS,
 	"txpool":           TxPool_JS,
+	"privtx":           PrivTx_JS,
+	"announce":         Announce_JS,
+	"fleetban":         FleetBan_JS,
 	"istanbul":         Istanbul_JS,
 	"mainbridge":       MainBridge_JS,
 	"subbridge":        SubBridge_JS,
@@ -342,12 +345,65 @@ web3._extend({
 			call: 'governance_vote',
 			params: 2
 		}),
+		new web3._extend.Method({
+			name: 'scheduleVote',
+			call: 'governance_vote',
+			params: 3
+		}),
+		new web3._extend.Method({
+			name: 'emergencyVote',
+			call: 'governance_emergencyVote',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'validateVote',
+			call: 'governance_validateVote',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'validateScheduledVote',
+			call: 'governance_validateVote',
+			params: 3
+		}),
 		new web3._extend.Method({
 			name: 'getParams',
 			call: 'governance_getParams',
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'simulateParamSet',
+			call: 'governance_simulateParamSet',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'paramDiff',
+			call: 'governance_paramDiff',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getRecentChanges',
+			call: 'governance_getRecentChanges',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getAuditLog',
+			call: 'governance_getAuditLog',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'exportHistory',
+			call: 'governance_exportHistory',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'importHistory',
+			call: 'governance_importHistory',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'getContractParams',
 			call: 'governance_getContractParams',
@@ -392,6 +448,14 @@ web3._extend({
 			name: 'pendingChanges',
 			getter: 'governance_pendingChanges',
 		}),
+		new web3._extend.Property({
+			name: 'scheduledChanges',
+			getter: 'governance_scheduledChanges',
+		}),
+		new web3._extend.Property({
+			name: 'proposalStatus',
+			getter: 'governance_getProposalStatus',
+		}),
 		new web3._extend.Property({
 			name: 'votes',
 			getter: 'governance_votes',
@@ -438,6 +502,39 @@ web3._extend({
 			call: 'admin_importChainFromString',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'setContractABI',
+			call: 'admin_setContractABI',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'removeContractABI',
+			call: 'admin_removeContractABI',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'fetchContractABI',
+			call: 'admin_fetchContractABI',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'setMethodSignatures',
+			call: 'admin_setMethodSignatures',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'exportContractABIs',
+			call: 'admin_exportContractABIs',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'importContractABIs',
+			call: 'admin_importContractABIs',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'sleepBlocks',
 			call: 'admin_sleepBlocks',
@@ -483,6 +580,10 @@ web3._extend({
 			name: 'stopStateMigration',
 			call: 'admin_stopStateMigration',
 		}),
+		new web3._extend.Method({
+			name: 'startStatePruning',
+			call: 'admin_startStatePruning',
+		}),
 		new web3._extend.Method({
 			name: 'saveTrieNodeCacheToDisk',
 			call: 'admin_saveTrieNodeCacheToDisk',
@@ -519,6 +620,21 @@ web3._extend({
 			name: 'getSpamThrottlerCandidateList',
 			call: 'admin_getSpamThrottlerCandidateList',
 		}),
+		new web3._extend.Method({
+			name: 'setTxPoolLimits',
+			call: 'admin_setTxPoolLimits',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'addTxFirewallRule',
+			call: 'admin_addTxFirewallRule',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'removeTxFirewallRule',
+			call: 'admin_removeTxFirewallRule',
+			params: 1,
+		}),
 		new web3._extend.Method({
 			name: 'syncStakingInfo',
 			call: 'admin_syncStakingInfo',
@@ -528,6 +644,33 @@ web3._extend({
 			name: 'syncStakingInfoStatus',
 			call: 'admin_syncStakingInfoStatus',
 		}),
+		new web3._extend.Method({
+			name: 'healState',
+			call: 'admin_healState',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'healStateStatus',
+			call: 'admin_healStateStatus',
+		}),
+		new web3._extend.Method({
+			name: 'drain',
+			call: 'admin_drain',
+			params: 1,
+			inputFormatter: [null],
+		}),
+		new web3._extend.Method({
+			name: 'drainStatus',
+			call: 'admin_drainStatus',
+		}),
+		new web3._extend.Method({
+			name: 'cancelDrain',
+			call: 'admin_cancelDrain',
+		}),
+		new web3._extend.Method({
+			name: 'loadStatus',
+			call: 'admin_loadStatus',
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -542,14 +685,34 @@ web3._extend({
 			name: 'datadir',
 			getter: 'admin_datadir'
 		}),
+		new web3._extend.Property({
+			name: 'capabilities',
+			getter: 'admin_capabilities'
+		}),
 		new web3._extend.Property({
 			name: 'stateMigrationStatus',
 			getter: 'admin_stateMigrationStatus'
 		}),
+		new web3._extend.Property({
+			name: 'statePruningStatus',
+			getter: 'admin_statePruningStatus'
+		}),
+		new web3._extend.Property({
+			name: 'dbStats',
+			getter: 'admin_dbStats'
+		}),
 		new web3._extend.Property({
 			name: 'spamThrottlerConfig',
 			getter: 'admin_spamThrottlerConfig'
 		}),
+		new web3._extend.Property({
+			name: 'txPoolLimits',
+			getter: 'admin_txPoolLimits'
+		}),
+		new web3._extend.Property({
+			name: 'txFirewallRules',
+			getter: 'admin_txFirewallRules'
+		}),
 		new web3._extend.Property({
 			name: 'nodeConfig',
 			getter: 'admin_nodeConfig',
@@ -567,6 +730,81 @@ web3._extend({
 			call: 'debug_dumpBlock',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'simulationStart',
+			call: 'debug_simulationStart',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'simulationStop',
+			call: 'debug_simulationStop',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'simulationSetBalance',
+			call: 'debug_simulationSetBalance',
+			params: 3
+		}),
+		new web3._extend.Method({
+			name: 'simulationSetNonce',
+			call: 'debug_simulationSetNonce',
+			params: 3
+		}),
+		new web3._extend.Method({
+			name: 'simulationSetCode',
+			call: 'debug_simulationSetCode',
+			params: 3
+		}),
+		new web3._extend.Method({
+			name: 'simulationSetStorageAt',
+			call: 'debug_simulationSetStorageAt',
+			params: 4
+		}),
+		new web3._extend.Method({
+			name: 'simulationGetBalance',
+			call: 'debug_simulationGetBalance',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'simulationGetTransactionCount',
+			call: 'debug_simulationGetTransactionCount',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'simulationGetCode',
+			call: 'debug_simulationGetCode',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'simulationGetStorageAt',
+			call: 'debug_simulationGetStorageAt',
+			params: 3
+		}),
+		new web3._extend.Method({
+			name: 'simulationCall',
+			call: 'debug_simulationCall',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'simulationSendTransaction',
+			call: 'debug_simulationSendTransaction',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'simulationSnapshot',
+			call: 'debug_simulationSnapshot',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'simulationRevert',
+			call: 'debug_simulationRevert',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'accountRange',
+			call: 'debug_accountRange',
+			params: 6
+		}),
 		new web3._extend.Method({
 			name: 'dumpStateTrie',
 			call: 'debug_dumpStateTrie',
@@ -600,6 +838,12 @@ web3._extend({
 			call: 'debug_getBadBlocks',
 			params: 0,
 		}),
+		new web3._extend.Method({
+			name: 'getContractMetadata',
+			call: 'debug_getContractMetadata',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'printBlock',
 			call: 'debug_printBlock',
@@ -627,11 +871,30 @@ web3._extend({
 			name: 'stopWarmUp',
 			call: 'debug_stopWarmUp',
 		}),
+		new web3._extend.Method({
+			name: 'slowQueries',
+			call: 'debug_slowQueries',
+		}),
 		new web3._extend.Method({
 			name: 'startCollectingTrieStats',
 			call: 'debug_startCollectingTrieStats',
 			params: 1,
 		}),
+		new web3._extend.Method({
+			name: 'getStateDiff',
+			call: 'debug_getStateDiff',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'setTrieCacheSize',
+			call: 'debug_setTrieCacheSize',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'pinStateRoot',
+			call: 'debug_pinStateRoot',
+			params: 2,
+		}),
 		new web3._extend.Method({
 			name: 'chaindbProperty',
 			call: 'debug_chaindbProperty',
@@ -850,11 +1113,59 @@ web3._extend({
 			params: 1,
 			inputFormatter: [null]
 		}),
+		new web3._extend.Method({
+			name: 'replayProposal',
+			call: 'debug_replayProposal',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'txProvenance',
+			call: 'debug_txProvenance',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'txPropagation',
+			call: 'debug_txPropagation',
+			params: 1,
+		}),
 		new web3._extend.Method({
 			name: 'storageRangeAt',
 			call: 'debug_storageRangeAt',
 			params: 5,
 		}),
+		new web3._extend.Method({
+			name: 'dumpContractState',
+			call: 'debug_dumpContractState',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'invariantStatus',
+			call: 'debug_invariantStatus',
+			params: 0,
+		}),
+		new web3._extend.Method({
+			name: 'verkleStatus',
+			call: 'debug_verkleStatus',
+			params: 0,
+		}),
+		new web3._extend.Method({
+			name: 'verkleUpdate',
+			call: 'debug_verkleUpdate',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'verkleWitness',
+			call: 'debug_verkleWitness',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'getLogsPlan',
+			call: 'debug_getLogsPlan',
+			params: 1,
+		}),
 		new web3._extend.Method({
 			name: 'setVMLogTarget',
 			call: 'debug_setVMLogTarget',
@@ -925,6 +1236,12 @@ var klayMethods = [
 		params: 1,
 		inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
 	}),
+	new web3._extend.Method({
+		name: 'getRandomness',
+		call: 'klay_getRandomness',
+		params: 1,
+		inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+	}),
 	new web3._extend.Method({
 		name: 'getCommittee',
 		call: 'klay_getCommittee',
@@ -1158,12 +1475,47 @@ var klayMethods = [
 		params: 2,
 		inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, function (val) { return !!val; }]
 	}),
+	new web3._extend.Method({
+		name: 'getContractABI',
+		call: 'klay_getContractABI',
+		params: 1,
+		inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+	}),
+	new web3._extend.Method({
+		name: 'decodeCalldata',
+		call: 'klay_decodeCalldata',
+		params: 2,
+		inputFormatter: [web3._extend.formatters.inputAddressFormatter, null]
+	}),
+	new web3._extend.Method({
+		name: 'decodeTransaction',
+		call: 'klay_decodeTransaction',
+		params: 1
+	}),
+	new web3._extend.Method({
+		name: 'decodeRevert',
+		call: 'klay_decodeRevert',
+		params: 2,
+		inputFormatter: [web3._extend.formatters.inputAddressFormatter, null]
+	}),
 	new web3._extend.Method({
 		name: 'getProof',
 		call: 'klay_getProof',
 		params: 3,
 		inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, web3._extend.formatters.inputBlockNumberFormatter]
 	}),
+	new web3._extend.Method({
+		name: 'getStateExpiry',
+		call: 'klay_getStateExpiry',
+		params: 2,
+		inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+	}),
+	new web3._extend.Method({
+		name: 'getResurrectionData',
+		call: 'klay_getResurrectionData',
+		params: 2,
+		inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+	}),
 ];
 `
 
@@ -1354,6 +1706,77 @@ web3._extend({
 });
 `
 
+const PrivTx_JS = `
+web3._extend({
+	property: 'privtx',
+	methods: [
+		new web3._extend.Method({
+			name: 'sendRawTransactions',
+			call: 'privtx_sendRawTransactions',
+			params: 2
+		}),
+	],
+	properties:
+	[
+		new web3._extend.Property({
+			name: 'partners',
+			getter: 'privtx_partners'
+		}),
+		new web3._extend.Property({
+			name: 'pending',
+			getter: 'privtx_pending'
+		}),
+	]
+});
+`
+
+const Announce_JS = `
+web3._extend({
+	property: 'announce',
+	methods: [
+		new web3._extend.Method({
+			name: 'announce',
+			call: 'announce_announce',
+			params: 4
+		}),
+		new web3._extend.Method({
+			name: 'getAnnouncements',
+			call: 'announce_getAnnouncements',
+			params: 1,
+			inputFormatter: [null]
+		}),
+	]
+});
+`
+
+const FleetBan_JS = `
+web3._extend({
+	property: 'fleetban',
+	methods: [
+		new web3._extend.Method({
+			name: 'report',
+			call: 'fleetban_report',
+			params: 4
+		}),
+		new web3._extend.Method({
+			name: 'banned',
+			call: 'fleetban_banned',
+			params: 2
+		}),
+	],
+	properties: [
+		new web3._extend.Property({
+			name: 'entries',
+			getter: 'fleetban_entries'
+		}),
+		new web3._extend.Property({
+			name: 'members',
+			getter: 'fleetban_members'
+		}),
+	]
+});
+`
+
 const Istanbul_JS = `
 web3._extend({
 	property: 'istanbul',
@@ -1396,6 +1819,28 @@ web3._extend({
 			name: 'discard',
 			call: 'istanbul_discard',
 			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getProposerSelection',
+			call: 'istanbul_getProposerSelection',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'rotateKey',
+			call: 'istanbul_rotateKey',
+			params: 2,
+			inputFormatter: [null, web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'exportState',
+			call: 'istanbul_exportState',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'importState',
+			call: 'istanbul_importState',
+			params: 1
 		})
 	],
 	properties:
@@ -1407,6 +1852,10 @@ web3._extend({
 		new web3._extend.Property({
 			name: 'timeout',
 			getter: 'istanbul_getTimeout'
+		}),
+		new web3._extend.Property({
+			name: 'proposalTiming',
+			getter: 'istanbul_getProposalTiming'
 		})
 	]
 });
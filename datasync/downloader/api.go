@@ -27,6 +27,7 @@ import (
 	"sync"
 
 	"github.com/kaiachain/kaia"
+	"github.com/kaiachain/kaia/common"
 	"github.com/kaiachain/kaia/event"
 	"github.com/kaiachain/kaia/networks/rpc"
 )
@@ -44,6 +45,8 @@ type downloader interface {
 	Progress() kaia.SyncProgress
 	SyncStakingInfo(id string, from, to uint64) error
 	SyncStakingInfoStatus() *SyncingStatus
+	HealState(root common.Hash) error
+	HealStateStatus() *HealingStatus
 }
 
 // NewPublicDownloaderAPI creates a new PublicDownloaderAPI. The API has an internal event loop that
@@ -196,3 +199,13 @@ func (api *PrivateDownloaderAPI) SyncStakingInfo(id string, from, to uint64) err
 func (api *PrivateDownloaderAPI) SyncStakingInfoStatus() *SyncingStatus {
 	return api.d.SyncStakingInfoStatus()
 }
+
+// HealState fetches the state missing under the given state root from the peers in the background.
+func (api *PrivateDownloaderAPI) HealState(root common.Hash) error {
+	return api.d.HealState(root)
+}
+
+// HealStateStatus returns the progress of the running or the last state healing.
+func (api *PrivateDownloaderAPI) HealStateStatus() *HealingStatus {
+	return api.d.HealStateStatus()
+}
@@ -96,6 +96,7 @@ var (
 	errCanceled                = errors.New("syncing canceled (requested)")
 	errNoSyncActive            = errors.New("no sync active")
 	errTooOld                  = errors.New("peer doesn't speak recent enough protocol version (need version >= 62)")
+	errAlreadyHealing          = errors.New("state healing already running")
 )
 
 type Downloader struct {
@@ -107,6 +108,11 @@ type Downloader struct {
 	stakingInfoRecoveryCh     chan []*reward.StakingInfo
 	stakingInfoRecoveryBlocks []uint64
 
+	healing     int32          // Whether the state healing is running, accessed atomically
+	healStatus  *HealingStatus // Progress of the running or the last state healing
+	healStarted uint64         // Number of processed state entries when the state healing started
+	healLock    sync.RWMutex   // Lock protecting healStatus and healStarted
+
 	queue *queue   // Scheduler for selecting the hashes to download
 	peers *peerSet // Set of active peers from which download can proceed
 
@@ -1721,7 +1727,7 @@ func (d *Downloader) importBlockResults(results []*fetchResult) error {
 		default:
 			if _, err := d.blockchain.InsertChain(types.Blocks{block}); err != nil {
 				logger.Debug("Downloaded item processing failed", "number", block.Number(), "hash", block.Hash(), "err", err)
-				return fmt.Errorf("%w: %v", errInvalidChain, err)
+				return fmt.Errorf("%w: %w", errInvalidChain, err)
 			}
 		}
 	}
@@ -1957,6 +1963,9 @@ func (d *Downloader) DeliverStakingInfos(id string, stakingInfos []*reward.Staki
 
 // DeliverNodeData injects a new batch of node state data received from a remote node.
 func (d *Downloader) DeliverNodeData(id string, data [][]byte) (err error) {
+	if atomic.LoadInt32(&d.healing) == 1 && atomic.LoadInt32(&d.synchronising) == 0 {
+		return d.deliverHealData(&statePack{id, data})
+	}
 	return d.deliver(id, d.stateCh, &statePack{id, data}, stateInMeter, stateDropMeter)
 }
 
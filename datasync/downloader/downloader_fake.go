@@ -64,5 +64,8 @@ func (*FakeDownloader) Cancel()                     {}
 func (*FakeDownloader) GetSnapSyncer() *snap.Syncer                      { return nil }
 func (*FakeDownloader) SyncStakingInfo(id string, from, to uint64) error { return nil }
 func (*FakeDownloader) SyncStakingInfoStatus() *SyncingStatus            { return nil }
+func (*FakeDownloader) HealState(root common.Hash) error                 { return nil }
+func (*FakeDownloader) HealTrieNode(hash common.Hash) error              { return nil }
+func (*FakeDownloader) HealStateStatus() *HealingStatus                  { return nil }
 
 func (*FakeDownloader) Config() *params.ChainConfig { return params.TestChainConfig }
@@ -35,6 +35,7 @@ import (
 	"time"
 
 	"github.com/kaiachain/kaia/blockchain"
+	"github.com/kaiachain/kaia/blockchain/state"
 	"github.com/kaiachain/kaia/blockchain/types"
 	"github.com/kaiachain/kaia/common"
 	"github.com/kaiachain/kaia/consensus/gxhash"
@@ -1955,3 +1956,50 @@ func testStakingInfoSync(t *testing.T, protocol int) {
 		}
 	}
 }
+
+// Tests that the state healing fetches the state missing under a root from the
+// peers out of a sync cycle.
+func TestHealState(t *testing.T) {
+	t.Parallel()
+
+	tester := newTester()
+	defer tester.terminate()
+
+	hashes, headers, blocks, receipts, stakingInfos := tester.makeChain(10, 0, tester.genesis, nil, false)
+	tester.newPeer("peer", 65, hashes, headers, blocks, receipts, stakingInfos)
+
+	root := blocks[hashes[0]].Root()
+	if _, err := state.New(root, state.NewDatabase(tester.stateDb), nil, nil); err == nil {
+		t.Fatal("state is available before healing")
+	}
+	if status := tester.downloader.HealStateStatus(); status != nil {
+		t.Fatalf("healing status before healing: %v", status)
+	}
+	if err := tester.downloader.HealState(root); err != nil {
+		t.Fatalf("failed to start healing: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	status := tester.downloader.HealStateStatus()
+	for ; status.Healing; status = tester.downloader.HealStateStatus() {
+		if time.Now().After(deadline) {
+			t.Fatalf("healing timed out: %v", status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if status.Error != "" || status.Root != root || status.Processed == 0 {
+		t.Fatalf("unexpected healing status: %+v", status)
+	}
+
+	// Make sure the whole state is available locally
+	stateDB, err := state.New(root, state.NewDatabase(tester.stateDb), nil, nil)
+	if err != nil {
+		t.Fatalf("state is not available after healing: %v", err)
+	}
+	it := state.NewNodeIterator(stateDB)
+	for it.Next() {
+	}
+	if it.Error != nil {
+		t.Fatalf("state is incomplete after healing: %v", it.Error)
+	}
+}
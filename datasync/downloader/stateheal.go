@@ -0,0 +1,139 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/storage/statedb"
+)
+
+// HealingStatus is the progress of the running or the last state healing.
+type HealingStatus struct {
+	Healing   bool        `json:"healing"`
+	Root      common.Hash `json:"root"`
+	Started   time.Time   `json:"started"`
+	Processed uint64      `json:"processed"` // Number of trie nodes and contract codes fetched so far
+	Pending   uint64      `json:"pending"`   // Number of trie nodes and contract codes known to be missing
+	Error     string      `json:"error,omitempty"`
+}
+
+// HealState fetches the trie nodes and contract codes missing under the given
+// state root from the peers in the background. The state is walked from the root
+// and the subtries already in the database are skipped, so only the gaps left by
+// an interrupted sync are downloaded while the available state keeps being served.
+// A state sync of a new sync cycle takes over and aborts the healing.
+func (d *Downloader) HealState(root common.Hash) error {
+	return d.heal(root, nil)
+}
+
+// HealTrieNode re-fetches a single trie node, e.g. quarantined as corrupted, from
+// the peers in the background. The subtries under the node are expected to be
+// in the database, so they are only fetched where missing. It shares the state
+// healing, hence fails if one is already running.
+func (d *Downloader) HealTrieNode(hash common.Hash) error {
+	return d.heal(hash, statedb.NewTrieSync(hash, d.stateDB, nil, d.stateBloom, nil))
+}
+
+// heal runs the state healing of the given root in the background. The state
+// sync scheduler is replaced with sched if it's given.
+func (d *Downloader) heal(root common.Hash, sched *statedb.TrieSync) error {
+	if atomic.LoadInt32(&d.synchronising) == 1 {
+		return errBusy
+	}
+	if !atomic.CompareAndSwapInt32(&d.healing, 0, 1) {
+		return errAlreadyHealing
+	}
+	d.syncStatsLock.RLock()
+	processed := d.syncStatsState.processed
+	d.syncStatsLock.RUnlock()
+
+	d.healLock.Lock()
+	d.healStatus = &HealingStatus{Healing: true, Root: root, Started: time.Now()}
+	d.healStarted = processed
+	d.healLock.Unlock()
+
+	s := newStateSync(d, root)
+	s.heal = true
+	if sched != nil {
+		s.sched = sched
+	}
+	select {
+	case d.stateSyncStart <- s:
+		<-s.started
+	case <-d.quitCh:
+		atomic.StoreInt32(&d.healing, 0)
+		return errCancelStateFetch
+	}
+	logger.Info("State healing started", "root", root)
+
+	go func() {
+		defer atomic.StoreInt32(&d.healing, 0)
+
+		err := s.Wait()
+		status := d.HealStateStatus()
+
+		d.healLock.Lock()
+		d.healStatus.Healing = false
+		d.healStatus.Processed, d.healStatus.Pending = status.Processed, status.Pending
+		if err != nil {
+			d.healStatus.Error = err.Error()
+		}
+		d.healLock.Unlock()
+
+		if err != nil {
+			logger.Warn("State healing aborted", "root", root, "processed", status.Processed, "pending", status.Pending, "err", err)
+			return
+		}
+		logger.Info("State healing completed", "root", root, "processed", status.Processed, "elapsed", common.PrettyDuration(time.Since(status.Started)))
+	}()
+	return nil
+}
+
+// HealStateStatus returns the progress of the running or the last state healing,
+// or nil if no state healing has been started.
+func (d *Downloader) HealStateStatus() *HealingStatus {
+	d.healLock.RLock()
+	defer d.healLock.RUnlock()
+
+	if d.healStatus == nil {
+		return nil
+	}
+	status := *d.healStatus
+	if status.Healing {
+		d.syncStatsLock.RLock()
+		status.Processed = d.syncStatsState.processed - d.healStarted
+		status.Pending = d.syncStatsState.pending
+		d.syncStatsLock.RUnlock()
+	}
+	return &status
+}
+
+// deliverHealData injects a batch of node state data received while the state
+// healing runs out of a sync cycle, which has no cancel channel to abort on.
+func (d *Downloader) deliverHealData(packet dataPack) error {
+	stateInMeter.Mark(int64(packet.Items()))
+	select {
+	case d.stateCh <- packet:
+		return nil
+	case <-d.quitCh:
+		stateDropMeter.Mark(int64(packet.Items()))
+		return errCancelStateFetch
+	}
+}
@@ -277,6 +277,8 @@ type stateSync struct {
 	numUncommitted   int
 	bytesUncommitted int
 
+	heal bool // Whether the sync heals the state out of a sync cycle (see HealState)
+
 	started    chan struct{}  // Started is signalled once the sync loop starts
 	deliver    chan *stateReq // Delivery channel multiplexing peer responses
 	cancel     chan struct{}  // Channel to signal a termination request
@@ -320,7 +322,7 @@ func newStateSync(d *Downloader, root common.Hash) *stateSync {
 // finish.
 func (s *stateSync) run() {
 	close(s.started)
-	if s.d.snapSync {
+	if s.d.snapSync && !s.heal {
 		s.err = s.d.SnapSyncer.Sync(s.root, s.cancel)
 	} else {
 		s.err = s.loop()
@@ -372,7 +374,7 @@ func (s *stateSync) loop() (err error) {
 		case <-s.cancel:
 			return errCancelStateFetch
 
-		case <-s.d.cancelCh:
+		case <-s.abortCh():
 			return errCanceled
 
 		case req := <-s.deliver:
@@ -389,7 +391,7 @@ func (s *stateSync) loop() (err error) {
 
 					// If this peer was the master peer, abort sync immediately
 					s.d.cancelLock.RLock()
-					master := req.peer.id == s.d.cancelPeer
+					master := !s.heal && req.peer.id == s.d.cancelPeer
 					s.d.cancelLock.RUnlock()
 
 					if master {
@@ -410,6 +412,15 @@ func (s *stateSync) loop() (err error) {
 	return nil
 }
 
+// abortCh returns the channel aborting the sync on the cancellation of the sync
+// cycle. Healing runs out of a sync cycle, so it's only aborted by s.cancel.
+func (s *stateSync) abortCh() <-chan struct{} {
+	if s.heal {
+		return nil
+	}
+	return s.d.cancelCh
+}
+
 func (s *stateSync) commit(force bool) error {
 	if !force && s.bytesUncommitted < database.IdealBatchSize {
 		return nil
@@ -0,0 +1,34 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+/*
+Package follower implements the follower mode of ENs, which keeps the local chain
+in sync by pulling blocks from trusted upstream nodes' JSON-RPC APIs instead of
+the p2p block synchronisation. It is meant for environments where p2p traffic
+is prohibited. The follower mode is enabled by --upstream.endpoints of ken, and
+is rejected for the other node types.
+
+Blocks are fetched with debug_getBlockRlp and imported with InsertChain, which
+verifies them including the committed seals. When an upstream endpoint fails or
+serves an invalid block, the next endpoint is used. The p2p server neither dials
+nor listens in the follower mode, and the fetcher and the downloader are disabled.
+
+# Source Files
+
+  - follower.go      : The Follower which polls the upstream nodes and imports blocks.
+  - follower_test.go : Functions for testing the follower.
+*/
+package follower
@@ -0,0 +1,246 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package follower
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/log"
+	"github.com/kaiachain/kaia/networks/rpc"
+	"github.com/kaiachain/kaia/rlp"
+)
+
+const (
+	// DefaultPollInterval is the default interval between upstream head checks.
+	DefaultPollInterval = time.Second
+
+	// maxBlocksPerRound is the maximum number of blocks imported in one round.
+	maxBlocksPerRound = 128
+)
+
+var (
+	logger = log.NewModuleLogger(log.DatasyncFollower)
+
+	// requestTimeout is the timeout of each request to the upstream.
+	requestTimeout = 10 * time.Second
+
+	errNoEndpoint       = errors.New("no upstream endpoint is given")
+	errAllEndpointsDown = errors.New("all upstream endpoints failed")
+)
+
+// BlockChain is the subset of the blockchain used by the follower.
+type BlockChain interface {
+	CurrentBlock() *types.Block
+	InsertChain(chain types.Blocks) (int, error)
+}
+
+// Follower imports blocks from trusted upstream nodes through their JSON-RPC
+// APIs instead of the p2p block synchronisation. Every block is verified by
+// InsertChain, including the committed seals, before it is imported.
+// The endpoints are used in the given order; the next one is tried on failure.
+type Follower struct {
+	endpoints []string
+	interval  time.Duration
+
+	chain BlockChain
+
+	dial    func(endpoint string) (*rpc.Client, error)
+	clients map[string]*rpc.Client
+	current int // index of the endpoint in use
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a follower which polls the given upstream endpoints.
+func New(endpoints []string, interval time.Duration, chain BlockChain) (*Follower, error) {
+	if len(endpoints) == 0 {
+		return nil, errNoEndpoint
+	}
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	return &Follower{
+		endpoints: endpoints,
+		interval:  interval,
+		chain:     chain,
+		dial:      rpc.Dial,
+		clients:   make(map[string]*rpc.Client),
+		quit:      make(chan struct{}),
+	}, nil
+}
+
+// Start starts following the upstream nodes in the background.
+func (f *Follower) Start() {
+	f.wg.Add(1)
+	go f.loop()
+	logger.Info("Started following upstream nodes", "endpoints", f.endpoints, "interval", f.interval)
+}
+
+// Stop stops the follower and closes the upstream connections.
+func (f *Follower) Stop() {
+	close(f.quit)
+	f.wg.Wait()
+	for _, client := range f.clients {
+		client.Close()
+	}
+	logger.Info("Stopped following upstream nodes")
+}
+
+func (f *Follower) loop() {
+	defer f.wg.Done()
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := f.sync(); err != nil {
+				logger.Warn("Failed to follow upstream", "err", err)
+			}
+		case <-f.quit:
+			return
+		}
+	}
+}
+
+// sync imports the blocks the local chain is missing, failing over to the next
+// endpoint when the current one is unreachable or serves an invalid block.
+func (f *Follower) sync() error {
+	for i := 0; i < len(f.endpoints); i++ {
+		endpoint := f.endpoints[f.current]
+		err := f.syncFrom(endpoint)
+		if err == nil {
+			return nil
+		}
+		logger.Warn("Upstream endpoint failed", "endpoint", endpoint, "err", err)
+		f.dropClient(endpoint)
+		f.current = (f.current + 1) % len(f.endpoints)
+	}
+	return errAllEndpointsDown
+}
+
+func (f *Follower) syncFrom(endpoint string) error {
+	client, err := f.client(endpoint)
+	if err != nil {
+		return err
+	}
+
+	var head hexutil.Uint64
+	if err := call(client, &head, "kaia_blockNumber"); err != nil {
+		return err
+	}
+
+	for {
+		local := f.chain.CurrentBlock().NumberU64()
+		if local >= uint64(head) {
+			return nil
+		}
+		last := local + maxBlocksPerRound
+		if last > uint64(head) {
+			last = uint64(head)
+		}
+
+		blocks := make(types.Blocks, 0, last-local)
+		for num := local + 1; num <= last; num++ {
+			block, err := fetchBlock(client, num)
+			if err != nil {
+				return err
+			}
+			blocks = append(blocks, block)
+		}
+		if err := f.checkLinked(blocks); err != nil {
+			return err
+		}
+		if n, err := f.chain.InsertChain(blocks); err != nil {
+			return fmt.Errorf("failed to insert block %d: %w", blocks[n].NumberU64(), err)
+		}
+		logger.Debug("Imported blocks from upstream", "endpoint", endpoint, "from", local+1, "to", last)
+
+		select {
+		case <-f.quit:
+			return nil
+		default:
+		}
+	}
+}
+
+// checkLinked checks that the blocks are linked to the local chain. The blocks
+// themselves, including the committed seals, are verified by InsertChain.
+func (f *Follower) checkLinked(blocks types.Blocks) error {
+	parent := f.chain.CurrentBlock().Hash()
+	for _, block := range blocks {
+		if block.ParentHash() != parent {
+			return fmt.Errorf("upstream block %d is not linked to the local chain", block.NumberU64())
+		}
+		parent = block.Hash()
+	}
+	return nil
+}
+
+// call invokes the method of the upstream, giving up after requestTimeout.
+func call(client *rpc.Client, result interface{}, method string, args ...interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	return client.CallContext(ctx, result, method, args...)
+}
+
+func fetchBlock(client *rpc.Client, num uint64) (*types.Block, error) {
+	var encoded string
+	if err := call(client, &encoded, "debug_getBlockRlp", hexutil.Uint64(num)); err != nil {
+		return nil, err
+	}
+	raw, err := hex.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	block := new(types.Block)
+	if err := rlp.DecodeBytes(raw, block); err != nil {
+		return nil, err
+	}
+	if block.NumberU64() != num {
+		return nil, fmt.Errorf("upstream returned block %d for %d", block.NumberU64(), num)
+	}
+	return block, nil
+}
+
+func (f *Follower) client(endpoint string) (*rpc.Client, error) {
+	if client, ok := f.clients[endpoint]; ok {
+		return client, nil
+	}
+	client, err := f.dial(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	f.clients[endpoint] = client
+	return client, nil
+}
+
+func (f *Follower) dropClient(endpoint string) {
+	if client, ok := f.clients[endpoint]; ok {
+		client.Close()
+		delete(f.clients, endpoint)
+	}
+}
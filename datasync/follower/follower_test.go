@@ -0,0 +1,133 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package follower
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/kaiachain/kaia/blockchain"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/blockchain/vm"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/consensus/gxhash"
+	"github.com/kaiachain/kaia/networks/rpc"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/rlp"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// upstreamKaiaAPI and upstreamDebugAPI serve the subset of the upstream APIs
+// used by the follower.
+type upstreamKaiaAPI struct{ blocks []*types.Block }
+
+func (api *upstreamKaiaAPI) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(len(api.blocks))
+}
+
+type upstreamDebugAPI struct {
+	blocks []*types.Block
+	delay  time.Duration // the latency of each request
+}
+
+func (api *upstreamDebugAPI) GetBlockRlp(number rpc.BlockNumberOrHash) (string, error) {
+	time.Sleep(api.delay)
+	num, _ := number.Number()
+	if num < 1 || int(num) > len(api.blocks) {
+		return "", errors.New("block not found")
+	}
+	encoded, err := rlp.EncodeToBytes(api.blocks[num-1])
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", encoded), nil
+}
+
+func newTestChain(t *testing.T, gspec *blockchain.Genesis) (*blockchain.BlockChain, *types.Block, database.DBManager) {
+	db := database.NewMemoryDBManager()
+	genesis := gspec.MustCommit(db)
+	chain, err := blockchain.NewBlockChain(db, nil, gspec.Config, gxhash.NewFaker(), vm.Config{})
+	require.NoError(t, err)
+	return chain, genesis, db
+}
+
+func TestFollowerSync(t *testing.T) {
+	gspec := &blockchain.Genesis{Config: params.TestChainConfig.Copy(), BlockScore: big.NewInt(1)}
+	upstream, genesis, db := newTestChain(t, gspec)
+	defer upstream.Stop()
+	blocks, _ := blockchain.GenerateChain(gspec.Config, genesis, gxhash.NewFaker(), db, 300, nil)
+
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("kaia", &upstreamKaiaAPI{blocks}))
+	require.NoError(t, server.RegisterName("debug", &upstreamDebugAPI{blocks: blocks}))
+	defer server.Stop()
+
+	local, _, _ := newTestChain(t, gspec)
+	defer local.Stop()
+
+	f, err := New([]string{"down", "up"}, 0, local)
+	require.NoError(t, err)
+	f.dial = func(endpoint string) (*rpc.Client, error) {
+		if endpoint == "down" {
+			return nil, errors.New("connection refused")
+		}
+		return rpc.DialInProc(server), nil
+	}
+
+	// The first endpoint fails, so the follower fails over to the second one.
+	require.NoError(t, f.sync())
+	assert.Equal(t, 1, f.current)
+	assert.Equal(t, blocks[len(blocks)-1].Hash(), local.CurrentBlock().Hash())
+}
+
+func TestFollowerSyncSlowUpstream(t *testing.T) {
+	defer func(timeout time.Duration) { requestTimeout = timeout }(requestTimeout)
+	requestTimeout = 200 * time.Millisecond
+
+	gspec := &blockchain.Genesis{Config: params.TestChainConfig.Copy(), BlockScore: big.NewInt(1)}
+	upstream, genesis, db := newTestChain(t, gspec)
+	defer upstream.Stop()
+	blocks, _ := blockchain.GenerateChain(gspec.Config, genesis, gxhash.NewFaker(), db, 100, nil)
+
+	// Each request is well within the timeout, but the whole round is not.
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("kaia", &upstreamKaiaAPI{blocks}))
+	require.NoError(t, server.RegisterName("debug", &upstreamDebugAPI{blocks: blocks, delay: 5 * time.Millisecond}))
+	defer server.Stop()
+
+	local, _, _ := newTestChain(t, gspec)
+	defer local.Stop()
+
+	f, err := New([]string{"up"}, 0, local)
+	require.NoError(t, err)
+	f.dial = func(endpoint string) (*rpc.Client, error) {
+		return rpc.DialInProc(server), nil
+	}
+
+	require.NoError(t, f.sync())
+	assert.Equal(t, blocks[len(blocks)-1].Hash(), local.CurrentBlock().Hash())
+}
+
+func TestFollowerNoEndpoint(t *testing.T) {
+	_, err := New(nil, 0, nil)
+	assert.Equal(t, errNoEndpoint, err)
+}
@@ -86,7 +86,7 @@ func (e *ContractEngine) contractGetAllParamsAt(num uint64) (*params.GovParamSet
 	}
 
 	config := chain.Config()
-	if !config.IsKoreForkEnabled(new(big.Int).SetUint64(num)) {
+	if !config.IsContractGovEnabled(new(big.Int).SetUint64(num)) {
 		logger.Trace("ContractEngine disabled: hardfork block not passed")
 		return params.NewGovParamSet(), nil
 	}
@@ -29,6 +29,7 @@ import (
 	"sync/atomic"
 
 	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/gov/headergov"
 	"github.com/kaiachain/kaia/log"
 	"github.com/kaiachain/kaia/params"
 	"github.com/kaiachain/kaia/rlp"
@@ -124,9 +125,11 @@ var (
 	}
 
 	GovernanceModeMap = map[string]int{
-		"none":   params.GovernanceMode_None,
-		"single": params.GovernanceMode_Single,
-		"ballot": params.GovernanceMode_Ballot,
+		"none":     params.GovernanceMode_None,
+		"single":   params.GovernanceMode_Single,
+		"ballot":   params.GovernanceMode_Ballot,
+		"stake":    params.GovernanceMode_Stake,
+		"multisig": params.GovernanceMode_Multisig,
 	}
 )
 
@@ -929,6 +932,10 @@ func (gov *Governance) triggerChange(src map[string]interface{}) {
 }
 
 func adjustDecodedSet(src map[string]interface{}) map[string]interface{} {
+	// The changes with explicit activation blocks and the emergency changes are handled by kaiax/gov/headergov only.
+	delete(src, headergov.ScheduledKey)
+	delete(src, headergov.EmergencyKey)
+
 	for k, v := range src {
 		x := reflect.ValueOf(v)
 		if x.Kind() == reflect.Float64 {
@@ -50,7 +50,7 @@ type MixedEngine struct {
 
 	// Subordinate engines
 	// contractGov is enabled when all the following conditions are met:
-	//   - Kore hardfork block has passed
+	//   - Kore hardfork block has passed, or ContractGovFromGenesis is set
 	//   - GovParamContract has been set
 	// contractGov can be ignored even if it is enabled for various reasons. To name a few:
 	//   - GovParam returns invalid parameters
@@ -129,7 +129,7 @@ func (e *MixedEngine) EffectiveParams(num uint64) (*params.GovParamSet, error) {
 	var contractParams *params.GovParamSet
 	var err error
 
-	if e.config.IsKoreForkEnabled(new(big.Int).SetUint64(num)) {
+	if e.config.IsContractGovEnabled(new(big.Int).SetUint64(num)) {
 		contractParams, err = e.contractGov.EffectiveParams(num)
 		if err != nil {
 			logger.Error("contractGov.EffectiveParams() failed", "err", err)
@@ -152,7 +152,7 @@ func (e *MixedEngine) UpdateParams(num uint64) error {
 	var contractParams *params.GovParamSet
 	numBigInt := big.NewInt(int64(num))
 
-	if e.config.IsKoreForkEnabled(numBigInt) {
+	if e.config.IsContractGovEnabled(numBigInt) {
 		if err := e.contractGov.UpdateParams(num); err != nil {
 			logger.Error("contractGov.UpdateParams(num) failed", "num", num, "err", err)
 			return err
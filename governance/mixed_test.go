@@ -278,3 +278,43 @@ func TestMixedEngine_ParamsAt(t *testing.T) {
 			i, headerBlock, contractBlock)
 	}
 }
+
+// TestMixedEngine_ContractGovFromGenesis tests if contractGov is effective before Kore if ContractGovFromGenesis is set.
+func TestMixedEngine_ContractGovFromGenesis(t *testing.T) {
+	var (
+		name        = "kip71.gastarget"
+		valueA      = uint64(0xa)
+		valueC      = uint64(0xcccccc)
+		valueCBytes = []byte{0xcc, 0xcc, 0xcc}
+	)
+
+	for _, fromGenesis := range []bool{false, true} {
+		config := getTestConfig()
+		config.Governance.KIP71.GasTarget = valueA
+
+		e, owner, sim, contract := newTestMixedEngine(t, config)
+		for _, c := range []*params.ChainConfig{config, sim.BlockChain().Config()} {
+			c.KoreCompatibleBlock = new(big.Int).SetUint64(0xffffffff)
+			c.ContractGovFromGenesis = fromGenesis
+		}
+		e.headerGov.db.WriteGovernance(map[string]interface{}{
+			"governance.govparamcontract": config.Governance.GovParamContract,
+		}, 0)
+
+		_, err := contract.SetParamIn(owner, name, true, valueCBytes, big.NewInt(1))
+		require.Nil(t, err)
+		sim.Commit()
+
+		num := sim.BlockChain().CurrentBlock().NumberU64()
+		pset, err := e.EffectiveParams(num + 1)
+		require.Nil(t, err)
+		require.Nil(t, e.UpdateParams(num))
+
+		expected := valueA
+		if fromGenesis {
+			expected = valueC
+		}
+		assert.Equal(t, expected, pset.GasTarget(), "fromGenesis=%v", fromGenesis)
+		assert.Equal(t, expected, e.CurrentParams().GasTarget(), "fromGenesis=%v", fromGenesis)
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package abistore
+
+import (
+	"errors"
+)
+
+var (
+	ErrInitUnexpectedNil = errors.New("unexpected nil during module init")
+	ErrNoABI             = errors.New("contract ABI not found")
+	ErrShortCalldata     = errors.New("calldata shorter than a method selector")
+	ErrUnknownRevert     = errors.New("revert data matches no error in contract ABI")
+	ErrNoABIInMetadata   = errors.New("no ABI found in contract metadata")
+	ErrUnsupportedURL    = errors.New("metadata URL must be http or https")
+	ErrMetadataTooLarge  = errors.New("contract metadata too large")
+	ErrFileExists        = errors.New("location would overwrite an existing file")
+	ErrNoMetadataURL     = errors.New("contract metadata URL not configured")
+	ErrNoContract        = errors.New("no contract code at address")
+	ErrInvalidSignature  = errors.New("invalid method signature")
+	ErrEmptyTx           = errors.New("empty transaction")
+)
@@ -0,0 +1,257 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package abistore
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/kaiax/abistore"
+	"github.com/kaiachain/kaia/networks/rpc"
+)
+
+func (s *AbiStoreModule) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "kaia",
+			Version:   "1.0",
+			Service:   NewAbiStoreAPI(s),
+			Public:    true,
+		},
+		{
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   NewPrivateAbiStoreAPI(s),
+			Public:    false,
+		},
+		{
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   NewDebugAbiStoreAPI(s),
+			Public:    false,
+		},
+	}
+}
+
+type AbiStoreAPI struct {
+	s *AbiStoreModule
+}
+
+func NewAbiStoreAPI(s *AbiStoreModule) *AbiStoreAPI {
+	return &AbiStoreAPI{s: s}
+}
+
+func (api *AbiStoreAPI) GetContractABI(addr common.Address) (json.RawMessage, error) {
+	abiJson := api.s.GetABI(addr)
+	if abiJson == nil {
+		return nil, abistore.ErrNoABI
+	}
+	return abiJson, nil
+}
+
+func (api *AbiStoreAPI) DecodeCalldata(addr common.Address, data hexutil.Bytes) (*abistore.DecodedCall, error) {
+	return api.s.DecodeCalldata(addr, data)
+}
+
+func (api *AbiStoreAPI) DecodeRevert(addr common.Address, data hexutil.Bytes) (string, error) {
+	return api.s.DecodeRevert(addr, data)
+}
+
+// TxDecoder serves kaia_decodeTransaction. Decoding a transaction needs no stored ABI,
+// so it is registered even if the ABI store is disabled. Then the calldata is not decoded.
+type TxDecoder struct {
+	s *AbiStoreModule // nil if the ABI store is disabled
+}
+
+func NewTxDecoder(s *AbiStoreModule) *TxDecoder {
+	return &TxDecoder{s: s}
+}
+
+func (d *TxDecoder) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "kaia",
+			Version:   "1.0",
+			Service:   NewTxDecoderAPI(d.s),
+			Public:    true,
+		},
+	}
+}
+
+type TxDecoderAPI struct {
+	s *AbiStoreModule
+}
+
+func NewTxDecoderAPI(s *AbiStoreModule) *TxDecoderAPI {
+	return &TxDecoderAPI{s: s}
+}
+
+func (api *TxDecoderAPI) DecodeTransaction(rawTx hexutil.Bytes) (map[string]interface{}, error) {
+	if api.s == nil {
+		return decodeTransaction(rawTx, nil)
+	}
+	return api.s.DecodeTransaction(rawTx)
+}
+
+type PrivateAbiStoreAPI struct {
+	s *AbiStoreModule
+}
+
+func NewPrivateAbiStoreAPI(s *AbiStoreModule) *PrivateAbiStoreAPI {
+	return &PrivateAbiStoreAPI{s: s}
+}
+
+// SetContractABI stores the ABI given either as a JSON array or as a JSON-encoded string.
+func (api *PrivateAbiStoreAPI) SetContractABI(addr common.Address, abiJson json.RawMessage) (bool, error) {
+	var str string
+	if err := json.Unmarshal(abiJson, &str); err == nil {
+		abiJson = json.RawMessage(str)
+	}
+	if err := api.s.HandleSetABI(addr, abiJson); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (api *PrivateAbiStoreAPI) RemoveContractABI(addr common.Address) bool {
+	api.s.HandleRemoveABI(addr)
+	return true
+}
+
+// SetMethodSignatures stores the method signatures used to decode calldata without an ABI.
+func (api *PrivateAbiStoreAPI) SetMethodSignatures(sigs []string) (int, error) {
+	for i, sig := range sigs {
+		if err := api.s.HandleSetSignature(sig); err != nil {
+			return i, err
+		}
+	}
+	return len(sigs), nil
+}
+
+// FetchContractABI downloads the verified-contract metadata at the URL and stores its ABI.
+func (api *PrivateAbiStoreAPI) FetchContractABI(addr common.Address, url string) (bool, error) {
+	abiJson, err := api.s.fetchABI(url)
+	if err != nil {
+		return false, err
+	}
+	if err := api.s.HandleSetABI(addr, abiJson); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ExportContractABIs writes every stored ABI into the file, one JSON object per line.
+func (api *PrivateAbiStoreAPI) ExportContractABIs(file string) (int, error) {
+	if _, err := os.Stat(file); err == nil {
+		// File already exists. Allowing overwrite could be a DoS vector,
+		// since the 'file' may point to arbitrary paths on the drive
+		return 0, abistore.ErrFileExists
+	}
+	out, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+
+	var writer io.Writer = out
+	if strings.HasSuffix(file, ".gz") {
+		writer = gzip.NewWriter(writer)
+		defer writer.(*gzip.Writer).Close()
+	}
+
+	var (
+		enc      = json.NewEncoder(writer)
+		count    = 0
+		writeErr error
+	)
+	err = IterateABIs(api.s.ChainKv, func(addr common.Address, abiJson []byte) bool {
+		if writeErr = enc.Encode(&abistore.ContractABI{Address: addr, ABI: abiJson}); writeErr != nil {
+			return false
+		}
+		count++
+		return true
+	})
+	if writeErr != nil {
+		return count, writeErr
+	}
+	return count, err
+}
+
+// ImportContractABIs stores the ABIs from a file written by ExportContractABIs.
+func (api *PrivateAbiStoreAPI) ImportContractABIs(file string) (int, error) {
+	in, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+
+	var reader io.Reader = in
+	if strings.HasSuffix(file, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return 0, err
+		}
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, int(metadataMaxBytes))
+	count := 0
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var entry abistore.ContractABI
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return count, fmt.Errorf("line %d: %v", line, err)
+		}
+		if err := api.s.HandleSetABI(entry.Address, entry.ABI); err != nil {
+			return count, fmt.Errorf("line %d: %v", line, err)
+		}
+		count++
+	}
+	return count, scanner.Err()
+}
+
+type DebugAbiStoreAPI struct {
+	s *AbiStoreModule
+}
+
+func NewDebugAbiStoreAPI(s *AbiStoreModule) *DebugAbiStoreAPI {
+	return &DebugAbiStoreAPI{s: s}
+}
+
+// GetContractMetadata returns the verification metadata of the contract currently deployed at the address.
+func (api *DebugAbiStoreAPI) GetContractMetadata(addr common.Address) (*abistore.ContractMetadata, error) {
+	if api.s.Chain == nil {
+		return nil, abistore.ErrNoMetadataURL
+	}
+	statedb, err := api.s.Chain.StateAt(api.s.Chain.CurrentBlock().Root())
+	if err != nil {
+		return nil, err
+	}
+	codeHash := statedb.GetCodeHash(addr)
+	if common.EmptyHash(codeHash) || codeHash == emptyCodeHash {
+		return nil, abistore.ErrNoContract
+	}
+	return api.s.GetContractMetadata(addr, codeHash)
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package abistore
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/abistore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImportContractABIs(t *testing.T) {
+	addrs := []common.Address{
+		common.HexToAddress("0x1111"),
+		common.HexToAddress("0x2222"),
+	}
+
+	for _, name := range []string{"abis.jsonl", "abis.jsonl.gz"} {
+		var (
+			file = filepath.Join(t.TempDir(), name)
+			src  = newTestModule(t)
+			dst  = newTestModule(t)
+		)
+		for _, addr := range addrs {
+			ok, err := NewPrivateAbiStoreAPI(src).SetContractABI(addr, []byte(testABI))
+			require.Nil(t, err)
+			require.True(t, ok)
+		}
+
+		count, err := NewPrivateAbiStoreAPI(src).ExportContractABIs(file)
+		require.Nil(t, err)
+		assert.Equal(t, len(addrs), count)
+
+		// Refuse to overwrite
+		_, err = NewPrivateAbiStoreAPI(src).ExportContractABIs(file)
+		assert.ErrorIs(t, err, abistore.ErrFileExists)
+
+		count, err = NewPrivateAbiStoreAPI(dst).ImportContractABIs(file)
+		require.Nil(t, err)
+		assert.Equal(t, len(addrs), count)
+		for _, addr := range addrs {
+			assert.Equal(t, src.GetABI(addr), dst.GetABI(addr))
+		}
+	}
+}
+
+func TestSetContractABI_String(t *testing.T) {
+	var (
+		s    = newTestModule(t)
+		addr = common.HexToAddress("0x1111")
+	)
+	ok, err := NewPrivateAbiStoreAPI(s).SetContractABI(addr, []byte(`"[{\"type\":\"fallback\"}]"`))
+	require.Nil(t, err)
+	assert.True(t, ok)
+
+	abiJson, err := NewAbiStoreAPI(s).GetContractABI(addr)
+	require.Nil(t, err)
+	assert.Equal(t, `[{"type":"fallback"}]`, string(abiJson))
+}
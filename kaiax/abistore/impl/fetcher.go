@@ -0,0 +1,105 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package abistore
+
+import (
+	"time"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/abistore"
+)
+
+type fetchRequest struct {
+	addr     common.Address
+	codeHash common.Hash
+}
+
+// GetContractMetadata returns the metadata of the contract code, fetching it synchronously on a miss.
+func (s *AbiStoreModule) GetContractMetadata(addr common.Address, codeHash common.Hash) (*abistore.ContractMetadata, error) {
+	if metadata := s.getCachedMetadata(codeHash); metadata != nil {
+		return metadata, nil
+	}
+	if s.MetadataURL == "" {
+		return nil, abistore.ErrNoMetadataURL
+	}
+	metadata, err := s.fetchMetadata(addr, codeHash)
+	if err != nil {
+		s.missCache.Add(codeHash, time.Now())
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// ContractName returns the cached name of the contract code. On a miss, the metadata is
+// queued for a background fetch so that later traces are labelled; the caller is never blocked.
+func (s *AbiStoreModule) ContractName(addr common.Address, codeHash common.Hash) (string, bool) {
+	if metadata := s.getCachedMetadata(codeHash); metadata != nil {
+		return metadata.Name, metadata.Name != ""
+	}
+	s.scheduleFetch(addr, codeHash)
+	return "", false
+}
+
+func (s *AbiStoreModule) getCachedMetadata(codeHash common.Hash) *abistore.ContractMetadata {
+	if cached, ok := s.metadataCache.Get(codeHash); ok {
+		return cached.(*abistore.ContractMetadata)
+	}
+	if metadata := ReadMetadata(s.ChainKv, codeHash); metadata != nil {
+		s.metadataCache.Add(codeHash, metadata)
+		return metadata
+	}
+	return nil
+}
+
+func (s *AbiStoreModule) scheduleFetch(addr common.Address, codeHash common.Hash) {
+	if s.fetchCh == nil {
+		return
+	}
+	if failedAt, ok := s.missCache.Get(codeHash); ok && time.Since(failedAt.(time.Time)) < metadataMissExpiry {
+		return
+	}
+
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+	if _, ok := s.pending[codeHash]; ok {
+		return
+	}
+	select {
+	case s.fetchCh <- fetchRequest{addr, codeHash}:
+		s.pending[codeHash] = struct{}{}
+	default:
+		// Queue full; the code will be requested again by a later trace.
+	}
+}
+
+func (s *AbiStoreModule) fetchLoop() {
+	defer s.wg.Done()
+	for {
+		select {
+		case req := <-s.fetchCh:
+			if _, err := s.fetchMetadata(req.addr, req.codeHash); err != nil {
+				logger.Trace("Failed to fetch contract metadata", "addr", req.addr, "codeHash", req.codeHash, "err", err)
+				s.missCache.Add(req.codeHash, time.Now())
+			}
+			s.pendingMu.Lock()
+			delete(s.pending, req.codeHash)
+			s.pendingMu.Unlock()
+		case <-s.quitCh:
+			return
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package abistore
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/kaiachain/kaia/accounts/abi"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/abistore"
+	"github.com/kaiachain/kaia/rlp"
+)
+
+func (s *AbiStoreModule) GetABI(addr common.Address) []byte {
+	return ReadABI(s.ChainKv, addr)
+}
+
+// getParsedABI returns the parsed ABI of the contract, using the cache if possible.
+func (s *AbiStoreModule) getParsedABI(addr common.Address) (*abi.ABI, error) {
+	if cached, ok := s.abiCache.Get(addr); ok {
+		return cached.(*abi.ABI), nil
+	}
+
+	abiJson := ReadABI(s.ChainKv, addr)
+	if abiJson == nil {
+		return nil, abistore.ErrNoABI
+	}
+	parsed, err := abi.JSON(bytes.NewReader(abiJson))
+	if err != nil {
+		return nil, err
+	}
+	s.abiCache.Add(addr, &parsed)
+	return &parsed, nil
+}
+
+func (s *AbiStoreModule) DecodeCalldata(addr common.Address, data []byte) (*abistore.DecodedCall, error) {
+	if len(data) < 4 {
+		return nil, abistore.ErrShortCalldata
+	}
+	var method *abi.Method
+	parsed, err := s.getParsedABI(addr)
+	if err == nil {
+		method, err = parsed.MethodById(data[:4])
+	}
+	if err != nil {
+		// Without a matching ABI entry, fall back to the registered signatures.
+		if method = s.getSignatureMethod(data[:4]); method == nil {
+			return nil, err
+		}
+	}
+	args := make(map[string]interface{})
+	if err := method.Inputs.UnpackIntoMap(args, data[4:]); err != nil {
+		return nil, err
+	}
+	return &abistore.DecodedCall{
+		Method:    method.Name,
+		Signature: method.Sig,
+		Args:      args,
+	}, nil
+}
+
+func (s *AbiStoreModule) DecodeTransaction(rawTx []byte) (map[string]interface{}, error) {
+	return decodeTransaction(rawTx, s.DecodeCalldata)
+}
+
+// decodeTransaction decodes the raw transaction, and its calldata with decodeCalldata if given.
+func decodeTransaction(rawTx []byte, decodeCalldata func(common.Address, []byte) (*abistore.DecodedCall, error)) (map[string]interface{}, error) {
+	if len(rawTx) == 0 {
+		return nil, abistore.ErrEmptyTx
+	}
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(rawTx, tx); err != nil {
+		// Ethereum typed transactions may come without the Kaia envelope prefix.
+		if rawTx[0] == 0 || rawTx[0] >= 0x7f {
+			return nil, err
+		}
+		tx = new(types.Transaction)
+		if rlp.DecodeBytes(append([]byte{byte(types.EthereumTxTypeEnvelope)}, rawTx...), tx) != nil {
+			return nil, err
+		}
+	}
+
+	fields := tx.MakeRPCOutput()
+	fields["hash"] = tx.Hash()
+	fields["senderTxHash"] = tx.SenderTxHashAll()
+	var from common.Address
+	if tx.IsEthereumTransaction() {
+		from, _ = types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	} else {
+		from, _ = tx.From()
+	}
+	fields["from"] = from
+	if to := tx.To(); to != nil && decodeCalldata != nil {
+		if call, err := decodeCalldata(*to, tx.Data()); err == nil {
+			fields["decodedInput"] = call
+		}
+	}
+	return fields, nil
+}
+
+func (s *AbiStoreModule) DecodeRevert(addr common.Address, data []byte) (string, error) {
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return reason, nil
+	}
+	if len(data) < 4 {
+		return "", abistore.ErrUnknownRevert
+	}
+	parsed, err := s.getParsedABI(addr)
+	if err != nil {
+		return "", err
+	}
+	for _, e := range parsed.Errors {
+		if !bytes.Equal(data[:4], e.ID[:4]) {
+			continue
+		}
+		values, err := e.Inputs.Unpack(data[4:])
+		if err != nil {
+			return "", err
+		}
+		args := make([]string, len(values))
+		for i, v := range values {
+			args[i] = fmt.Sprintf("%s=%v", e.Inputs[i].Name, v)
+		}
+		return fmt.Sprintf("%s(%s)", e.Name, strings.Join(args, ", ")), nil
+	}
+	return "", abistore.ErrUnknownRevert
+}
@@ -0,0 +1,204 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package abistore
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/kaiachain/kaia/accounts/abi"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/kaiax/abistore"
+	"github.com/kaiachain/kaia/rlp"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testABI = `[
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"error","name":"InsufficientBalance","inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]}
+]`
+
+func newTestModule(t *testing.T) *AbiStoreModule {
+	s := NewAbiStoreModule()
+	require.Nil(t, s.Init(&InitOpts{ChainKv: database.NewMemDB()}))
+	return s
+}
+
+func TestDecodeCalldata(t *testing.T) {
+	var (
+		s        = newTestModule(t)
+		contract = common.HexToAddress("0x1111")
+		to       = common.HexToAddress("0x2222")
+	)
+	parsed, err := abi.JSON(strings.NewReader(testABI))
+	require.Nil(t, err)
+	data, err := parsed.Pack("transfer", to, big.NewInt(5))
+	require.Nil(t, err)
+
+	// No ABI yet
+	_, err = s.DecodeCalldata(contract, data)
+	assert.ErrorIs(t, err, abistore.ErrNoABI)
+
+	require.Nil(t, s.HandleSetABI(contract, []byte(testABI)))
+	call, err := s.DecodeCalldata(contract, data)
+	require.Nil(t, err)
+	assert.Equal(t, "transfer", call.Method)
+	assert.Equal(t, "transfer(address,uint256)", call.Signature)
+	assert.Equal(t, to, call.Args["to"])
+	assert.Equal(t, big.NewInt(5), call.Args["amount"])
+
+	_, err = s.DecodeCalldata(contract, data[:3])
+	assert.ErrorIs(t, err, abistore.ErrShortCalldata)
+
+	// Removal drops the cached ABI as well
+	s.HandleRemoveABI(contract)
+	_, err = s.DecodeCalldata(contract, data)
+	assert.ErrorIs(t, err, abistore.ErrNoABI)
+}
+
+func TestDecodeRevert(t *testing.T) {
+	var (
+		s        = newTestModule(t)
+		contract = common.HexToAddress("0x1111")
+	)
+	require.Nil(t, s.HandleSetABI(contract, []byte(testABI)))
+	require.Nil(t, s.Start())
+	defer s.Stop()
+
+	parsed, err := abi.JSON(strings.NewReader(testABI))
+	require.Nil(t, err)
+	customErr := parsed.Errors["InsufficientBalance"]
+	args, err := customErr.Inputs.Pack(big.NewInt(1), big.NewInt(2))
+	require.Nil(t, err)
+	data := append(common.CopyBytes(customErr.ID[:4]), args...)
+
+	reason, err := s.DecodeRevert(contract, data)
+	require.Nil(t, err)
+	assert.Equal(t, "InsufficientBalance(available=1, required=2)", reason)
+
+	// Tracers reach the module through the registered revert decoder.
+	reason, err = abi.UnpackRevertOf(&contract, data)
+	require.Nil(t, err)
+	assert.Equal(t, "InsufficientBalance(available=1, required=2)", reason)
+
+	// Error(string) does not need an ABI.
+	other := common.HexToAddress("0x3333")
+	errorString := common.FromHex("0x08c379a0000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000047465737400000000000000000000000000000000000000000000000000000000")
+	reason, err = s.DecodeRevert(other, errorString)
+	require.Nil(t, err)
+	assert.Equal(t, "test", reason)
+
+	_, err = s.DecodeRevert(contract, []byte{0xde, 0xad, 0xbe, 0xef})
+	assert.ErrorIs(t, err, abistore.ErrUnknownRevert)
+}
+
+func TestHandleSetABI_Invalid(t *testing.T) {
+	s := newTestModule(t)
+	addr := common.HexToAddress("0x1111")
+
+	assert.NotNil(t, s.HandleSetABI(addr, []byte(`{"not":"an abi"}`)))
+	assert.Nil(t, s.GetABI(addr))
+}
+
+func TestDecodeCalldata_Signature(t *testing.T) {
+	var (
+		s        = newTestModule(t)
+		contract = common.HexToAddress("0x1111")
+		to       = common.HexToAddress("0x2222")
+	)
+	parsed, err := abi.JSON(strings.NewReader(testABI))
+	require.Nil(t, err)
+	data, err := parsed.Pack("transfer", to, big.NewInt(5))
+	require.Nil(t, err)
+
+	require.Nil(t, s.HandleSetSignature("transfer(address, uint256)"))
+	call, err := s.DecodeCalldata(contract, data)
+	require.Nil(t, err)
+	assert.Equal(t, "transfer", call.Method)
+	assert.Equal(t, "transfer(address,uint256)", call.Signature)
+	assert.Equal(t, to, call.Args["arg0"])
+	assert.Equal(t, big.NewInt(5), call.Args["arg1"])
+
+	// A registered ABI takes precedence over the signature.
+	require.Nil(t, s.HandleSetABI(contract, []byte(testABI)))
+	call, err = s.DecodeCalldata(contract, data)
+	require.Nil(t, err)
+	assert.Equal(t, to, call.Args["to"])
+
+	for _, sig := range []string{"transfer", "(address)", "transfer(addr)", "transfer((address,uint256))"} {
+		assert.ErrorIs(t, s.HandleSetSignature(sig), abistore.ErrInvalidSignature, sig)
+	}
+}
+
+func TestDecodeTransaction(t *testing.T) {
+	var (
+		s        = newTestModule(t)
+		contract = common.HexToAddress("0x1111")
+		to       = common.HexToAddress("0x2222")
+		chainId  = big.NewInt(1001)
+	)
+	key, err := crypto.GenerateKey()
+	require.Nil(t, err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	parsed, err := abi.JSON(strings.NewReader(testABI))
+	require.Nil(t, err)
+	data, err := parsed.Pack("transfer", to, big.NewInt(5))
+	require.Nil(t, err)
+	require.Nil(t, s.HandleSetABI(contract, []byte(testABI)))
+
+	tx := types.NewTx(&types.TxInternalDataEthereumDynamicFee{
+		ChainID:   chainId,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(1),
+		GasLimit:  100000,
+		Recipient: &contract,
+		Amount:    big.NewInt(0),
+		Payload:   data,
+	})
+	require.Nil(t, tx.Sign(types.NewLondonSigner(chainId), key))
+	rawTx, err := rlp.EncodeToBytes(tx)
+	require.Nil(t, err)
+
+	// Both the Kaia envelope and the bare Ethereum typed encoding are accepted.
+	for _, raw := range [][]byte{rawTx, rawTx[1:]} {
+		fields, err := s.DecodeTransaction(raw)
+		require.Nil(t, err)
+		assert.Equal(t, tx.Hash(), fields["hash"])
+		assert.Equal(t, from, fields["from"])
+		call, ok := fields["decodedInput"].(*abistore.DecodedCall)
+		require.True(t, ok)
+		assert.Equal(t, "transfer", call.Method)
+		assert.Equal(t, to, call.Args["to"])
+	}
+
+	// Without the ABI store, the transaction is decoded without its calldata.
+	fields, err := NewTxDecoderAPI(nil).DecodeTransaction(rawTx)
+	require.Nil(t, err)
+	assert.Equal(t, tx.Hash(), fields["hash"])
+	assert.NotContains(t, fields, "decodedInput")
+
+	_, err = s.DecodeTransaction(nil)
+	assert.ErrorIs(t, err, abistore.ErrEmptyTx)
+	_, err = s.DecodeTransaction([]byte{0x02, 0xde, 0xad})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,46 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package abistore
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/kaiachain/kaia/accounts/abi"
+	"github.com/kaiachain/kaia/common"
+)
+
+func (s *AbiStoreModule) HandleSetABI(addr common.Address, abiJson []byte) error {
+	parsed, err := abi.JSON(bytes.NewReader(abiJson))
+	if err != nil {
+		return err
+	}
+	// Store in a compact form so that exports are one line per contract.
+	compact := new(bytes.Buffer)
+	if err := json.Compact(compact, abiJson); err != nil {
+		return err
+	}
+	WriteABI(s.ChainKv, addr, compact.Bytes())
+	s.abiCache.Add(addr, &parsed)
+	logger.Debug("Stored contract ABI", "addr", addr, "methods", len(parsed.Methods), "errors", len(parsed.Errors))
+	return nil
+}
+
+func (s *AbiStoreModule) HandleRemoveABI(addr common.Address) {
+	DeleteABI(s.ChainKv, addr)
+	s.abiCache.Remove(addr)
+}
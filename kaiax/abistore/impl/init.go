@@ -0,0 +1,135 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package abistore
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/kaiachain/kaia/accounts/abi"
+	"github.com/kaiachain/kaia/blockchain/state"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/blockchain/vm"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/kaiax/abistore"
+	"github.com/kaiachain/kaia/log"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/storage/database"
+)
+
+var (
+	_ abistore.AbiStoreModule = &AbiStoreModule{}
+	_ abi.RevertDecoder       = &AbiStoreModule{}
+	_ vm.ContractNameResolver = &AbiStoreModule{}
+
+	logger = log.NewModuleLogger(log.KaiaxAbiStore)
+
+	abiCacheSize     = 1024
+	metadataTimeout  = 10 * time.Second
+	metadataMaxBytes = int64(4 * 1024 * 1024)
+
+	metadataCacheSize  = 4096
+	metadataQueueSize  = 256
+	metadataMissExpiry = 10 * time.Minute // retry interval of codes with no metadata
+
+	emptyCodeHash = crypto.Keccak256Hash(nil)
+)
+
+type blockChain interface {
+	CurrentBlock() *types.Block
+	StateAt(root common.Hash) (*state.StateDB, error)
+}
+
+type InitOpts struct {
+	ChainKv database.Database
+
+	// Optional. If MetadataURL is set, contract metadata is fetched from it by code hash.
+	// The URL may contain the placeholders {chainId}, {address} and {codeHash}.
+	MetadataURL string
+	ChainConfig *params.ChainConfig
+	Chain       blockChain
+}
+
+type AbiStoreModule struct {
+	InitOpts
+
+	abiCache   *lru.ARCCache // (addr common.Address) -> (parsed *abi.ABI)
+	httpClient *http.Client  // fetches verified-contract metadata
+
+	metadataCache *lru.ARCCache // (codeHash common.Hash) -> (*abistore.ContractMetadata)
+	missCache     *lru.Cache    // (codeHash common.Hash) -> (time.Time of the failed fetch)
+	fetchCh       chan fetchRequest
+	pendingMu     sync.Mutex
+	pending       map[common.Hash]struct{}
+	quitCh        chan struct{}
+	wg            sync.WaitGroup
+}
+
+func NewAbiStoreModule() *AbiStoreModule {
+	abiCache, _ := lru.NewARC(abiCacheSize)
+	metadataCache, _ := lru.NewARC(metadataCacheSize)
+	missCache, _ := lru.New(metadataCacheSize)
+	return &AbiStoreModule{
+		abiCache:      abiCache,
+		httpClient:    &http.Client{Timeout: metadataTimeout},
+		metadataCache: metadataCache,
+		missCache:     missCache,
+		pending:       make(map[common.Hash]struct{}),
+	}
+}
+
+func (s *AbiStoreModule) Init(opts *InitOpts) error {
+	if opts == nil || opts.ChainKv == nil {
+		return abistore.ErrInitUnexpectedNil
+	}
+	if opts.MetadataURL != "" && (opts.ChainConfig == nil || opts.Chain == nil) {
+		return abistore.ErrInitUnexpectedNil
+	}
+	s.InitOpts = *opts
+	return nil
+}
+
+func (s *AbiStoreModule) Start() error {
+	s.abiCache.Purge()
+	// Let the tracers decode custom errors of the registered contracts.
+	abi.SetRevertDecoder(s)
+
+	if s.MetadataURL != "" {
+		s.metadataCache.Purge()
+		s.missCache.Purge()
+		s.fetchCh = make(chan fetchRequest, metadataQueueSize)
+		s.quitCh = make(chan struct{})
+		s.wg.Add(1)
+		go s.fetchLoop()
+		// Let the tracers label the calls with verified contract names.
+		vm.SetContractNameResolver(s)
+	}
+	return nil
+}
+
+func (s *AbiStoreModule) Stop() {
+	abi.SetRevertDecoder(nil)
+	if s.quitCh != nil {
+		vm.SetContractNameResolver(nil)
+		close(s.quitCh)
+		s.wg.Wait()
+		s.quitCh = nil
+	}
+}
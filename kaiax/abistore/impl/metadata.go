@@ -0,0 +1,163 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package abistore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/abistore"
+)
+
+// fetchABI downloads the verified-contract metadata from the URL and extracts the ABI.
+func (s *AbiStoreModule) fetchABI(rawurl string) ([]byte, error) {
+	body, err := s.httpGet(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return extractABI(body)
+}
+
+// fetchMetadata downloads the metadata of the contract from the configured endpoint
+// and stores it under the code hash.
+func (s *AbiStoreModule) fetchMetadata(addr common.Address, codeHash common.Hash) (*abistore.ContractMetadata, error) {
+	rawurl := s.metadataURL(addr, codeHash)
+	body, err := s.httpGet(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := parseMetadata(body)
+	if err != nil {
+		return nil, err
+	}
+	metadata.Source = rawurl
+
+	WriteMetadata(s.ChainKv, codeHash, metadata)
+	s.metadataCache.Add(codeHash, metadata)
+	logger.Debug("Stored contract metadata", "addr", addr, "codeHash", codeHash, "name", metadata.Name)
+	return metadata, nil
+}
+
+// metadataURL fills the placeholders {chainId}, {address} and {codeHash} of the configured URL.
+func (s *AbiStoreModule) metadataURL(addr common.Address, codeHash common.Hash) string {
+	return strings.NewReplacer(
+		"{chainId}", s.ChainConfig.ChainID.String(),
+		"{address}", addr.Hex(),
+		"{codeHash}", codeHash.Hex(),
+	).Replace(s.MetadataURL)
+}
+
+func (s *AbiStoreModule) httpGet(rawurl string) ([]byte, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, abistore.ErrUnsupportedURL
+	}
+
+	resp, err := s.httpClient.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("metadata fetch failed: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, metadataMaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > metadataMaxBytes {
+		return nil, abistore.ErrMetadataTooLarge
+	}
+	return body, nil
+}
+
+// extractABI accepts the common metadata formats:
+// a bare ABI array, Solidity compiler metadata ({"output":{"abi":[...]}}),
+// and build artifacts ({"abi":[...]}).
+func extractABI(body []byte) ([]byte, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		return body, nil
+	}
+
+	var metadata struct {
+		ABI    json.RawMessage `json:"abi"`
+		Output struct {
+			ABI json.RawMessage `json:"abi"`
+		} `json:"output"`
+	}
+	if err := json.Unmarshal(body, &metadata); err != nil {
+		return nil, err
+	}
+	if len(metadata.Output.ABI) > 0 {
+		return metadata.Output.ABI, nil
+	}
+	if len(metadata.ABI) > 0 {
+		return metadata.ABI, nil
+	}
+	return nil, abistore.ErrNoABIInMetadata
+}
+
+// parseMetadata accepts Solidity compiler metadata as served by Sourcify, where the
+// name is the compilation target, and build artifacts with a "contractName".
+func parseMetadata(body []byte) (*abistore.ContractMetadata, error) {
+	var raw struct {
+		ContractName string `json:"contractName"`
+		Compiler     struct {
+			Version string `json:"version"`
+		} `json:"compiler"`
+		Settings struct {
+			CompilationTarget map[string]string `json:"compilationTarget"`
+		} `json:"settings"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	metadata := &abistore.ContractMetadata{
+		Name:     raw.ContractName,
+		Compiler: raw.Compiler.Version,
+	}
+	if metadata.Name == "" {
+		// There is exactly one compilation target in practice; sort for determinism anyway.
+		var targets []string
+		for _, name := range raw.Settings.CompilationTarget {
+			targets = append(targets, name)
+		}
+		sort.Strings(targets)
+		if len(targets) > 0 {
+			metadata.Name = targets[0]
+		}
+	}
+	if abiJson, err := extractABI(body); err == nil {
+		metadata.ABI = abiJson
+	}
+	if metadata.Name == "" && metadata.ABI == nil {
+		return nil, abistore.ErrNoABIInMetadata
+	}
+	return metadata, nil
+}
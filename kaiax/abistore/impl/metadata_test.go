@@ -0,0 +1,166 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package abistore
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaiachain/kaia/blockchain/state"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/abistore"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractABI(t *testing.T) {
+	testcases := []struct {
+		body     string
+		expected string
+		err      error
+	}{
+		{`[{"type":"fallback"}]`, `[{"type":"fallback"}]`, nil},
+		{`{"compiler":{},"output":{"abi":[{"type":"fallback"}]}}`, `[{"type":"fallback"}]`, nil},
+		{`{"contractName":"A","abi":[{"type":"fallback"}]}`, `[{"type":"fallback"}]`, nil},
+		{`{"compiler":{}}`, "", abistore.ErrNoABIInMetadata},
+	}
+	for _, tc := range testcases {
+		abiJson, err := extractABI([]byte(tc.body))
+		if tc.err != nil {
+			assert.ErrorIs(t, err, tc.err, tc.body)
+			continue
+		}
+		require.Nil(t, err, tc.body)
+		assert.Equal(t, tc.expected, string(abiJson), tc.body)
+	}
+}
+
+func TestFetchContractABI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metadata.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"output":{"abi":` + testABI + `}}`))
+	}))
+	defer server.Close()
+
+	var (
+		s    = newTestModule(t)
+		api  = NewPrivateAbiStoreAPI(s)
+		addr = common.HexToAddress("0x1111")
+	)
+
+	ok, err := api.FetchContractABI(addr, server.URL+"/metadata.json")
+	require.Nil(t, err)
+	assert.True(t, ok)
+	assert.NotNil(t, s.GetABI(addr))
+
+	_, err = api.FetchContractABI(addr, server.URL+"/missing.json")
+	assert.NotNil(t, err)
+
+	_, err = api.FetchContractABI(addr, "file:///etc/passwd")
+	assert.ErrorIs(t, err, abistore.ErrUnsupportedURL)
+}
+
+func TestParseMetadata(t *testing.T) {
+	testcases := []struct {
+		body     string
+		name     string
+		compiler string
+		err      error
+	}{
+		{
+			`{"compiler":{"version":"0.8.24+commit.e11b9ed9"},"settings":{"compilationTarget":{"contracts/Token.sol":"Token"}},"output":{"abi":[]}}`,
+			"Token", "0.8.24+commit.e11b9ed9", nil,
+		},
+		{`{"contractName":"Vault","abi":[{"type":"fallback"}]}`, "Vault", "", nil},
+		{`{"compiler":{"version":"0.8.24"}}`, "", "", abistore.ErrNoABIInMetadata},
+	}
+	for _, tc := range testcases {
+		metadata, err := parseMetadata([]byte(tc.body))
+		if tc.err != nil {
+			assert.ErrorIs(t, err, tc.err, tc.body)
+			continue
+		}
+		require.Nil(t, err, tc.body)
+		assert.Equal(t, tc.name, metadata.Name, tc.body)
+		assert.Equal(t, tc.compiler, metadata.Compiler, tc.body)
+	}
+}
+
+type nullChain struct{}
+
+func (nullChain) CurrentBlock() *types.Block { return nil }
+func (nullChain) StateAt(common.Hash) (*state.StateDB, error) {
+	return nil, errors.New("no state")
+}
+
+func TestContractName(t *testing.T) {
+	var (
+		addr     = common.HexToAddress("0x1111")
+		codeHash = common.HexToHash("0xc0de")
+		other    = common.HexToHash("0xbeef")
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/1001/"+codeHash.Hex() {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"contractName":"Token","abi":` + testABI + `}`))
+	}))
+	defer server.Close()
+
+	db := database.NewMemDB()
+	s := NewAbiStoreModule()
+	require.Nil(t, s.Init(&InitOpts{
+		ChainKv:     db,
+		MetadataURL: server.URL + "/{chainId}/{codeHash}",
+		ChainConfig: &params.ChainConfig{ChainID: params.KairosChainConfig.ChainID},
+		Chain:       nullChain{},
+	}))
+	require.Nil(t, s.Start())
+	defer s.Stop()
+
+	// The first lookup misses and schedules a background fetch.
+	_, ok := s.ContractName(addr, codeHash)
+	assert.False(t, ok)
+	assert.Eventually(t, func() bool {
+		name, ok := s.ContractName(addr, codeHash)
+		return ok && name == "Token"
+	}, time.Second, 10*time.Millisecond)
+
+	// The metadata is persisted by code hash.
+	metadata := ReadMetadata(db, codeHash)
+	require.NotNil(t, metadata)
+	assert.True(t, strings.HasSuffix(metadata.Source, codeHash.Hex()))
+
+	// Failed fetches are not retried until the miss expires.
+	_, err := s.GetContractMetadata(addr, other)
+	assert.NotNil(t, err)
+	s.scheduleFetch(addr, other)
+	s.pendingMu.Lock()
+	assert.NotContains(t, s.pending, other)
+	s.pendingMu.Unlock()
+}
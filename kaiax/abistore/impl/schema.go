@@ -0,0 +1,120 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package abistore
+
+import (
+	"encoding/json"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/kaiax/abistore"
+	"github.com/kaiachain/kaia/storage/database"
+)
+
+var (
+	abiPrefix       = []byte("abiStore")
+	metadataPrefix  = []byte("abiMetadata")
+	signaturePrefix = []byte("abiSignature")
+)
+
+func abiKey(addr common.Address) []byte {
+	return append(abiPrefix, addr.Bytes()...)
+}
+
+func ReadABI(db database.Database, addr common.Address) []byte {
+	b, err := db.Get(abiKey(addr))
+	if err != nil || len(b) == 0 {
+		return nil
+	}
+	return b
+}
+
+func WriteABI(db database.Database, addr common.Address, abiJson []byte) {
+	if err := db.Put(abiKey(addr), abiJson); err != nil {
+		logger.Crit("Failed to write contract ABI", "addr", addr, "err", err)
+	}
+}
+
+func DeleteABI(db database.Database, addr common.Address) {
+	if err := db.Delete(abiKey(addr)); err != nil {
+		logger.Crit("Failed to delete contract ABI", "addr", addr, "err", err)
+	}
+}
+
+// IterateABIs calls fn for every stored ABI in address order until fn returns false.
+func IterateABIs(db database.Database, fn func(addr common.Address, abiJson []byte) bool) error {
+	it := db.NewIterator(abiPrefix, nil)
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(abiPrefix)+common.AddressLength {
+			continue
+		}
+		addr := common.BytesToAddress(key[len(abiPrefix):])
+		if !fn(addr, common.CopyBytes(it.Value())) {
+			break
+		}
+	}
+	return it.Error()
+}
+
+func signatureKey(selector []byte) []byte {
+	return append(signaturePrefix, selector...)
+}
+
+func ReadSignature(db database.Database, selector []byte) string {
+	b, err := db.Get(signatureKey(selector))
+	if err != nil || len(b) == 0 {
+		return ""
+	}
+	return string(b)
+}
+
+func WriteSignature(db database.Database, selector []byte, sig string) {
+	if err := db.Put(signatureKey(selector), []byte(sig)); err != nil {
+		logger.Crit("Failed to write method signature", "selector", hexutil.Encode(selector), "err", err)
+	}
+}
+
+func metadataKey(codeHash common.Hash) []byte {
+	return append(metadataPrefix, codeHash.Bytes()...)
+}
+
+func ReadMetadata(db database.Database, codeHash common.Hash) *abistore.ContractMetadata {
+	b, err := db.Get(metadataKey(codeHash))
+	if err != nil || len(b) == 0 {
+		return nil
+	}
+	metadata := new(abistore.ContractMetadata)
+	if err := json.Unmarshal(b, metadata); err != nil {
+		logger.Error("Malformed contract metadata", "codeHash", codeHash, "err", err)
+		return nil
+	}
+	return metadata
+}
+
+func WriteMetadata(db database.Database, codeHash common.Hash, metadata *abistore.ContractMetadata) {
+	b, err := json.Marshal(metadata)
+	if err != nil {
+		logger.Error("Failed to marshal contract metadata", "codeHash", codeHash, "err", err)
+		return
+	}
+	if err := db.Put(metadataKey(codeHash), b); err != nil {
+		logger.Crit("Failed to write contract metadata", "codeHash", codeHash, "err", err)
+	}
+}
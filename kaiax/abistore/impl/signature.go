@@ -0,0 +1,75 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package abistore
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kaiachain/kaia/accounts/abi"
+	"github.com/kaiachain/kaia/kaiax/abistore"
+)
+
+// parseSignature builds a method from a text signature like "transfer(address,uint256)".
+// The arguments are named arg0, arg1, ... since a signature carries no names.
+// Tuple arguments are not supported.
+func parseSignature(sig string) (*abi.Method, error) {
+	sig = strings.ReplaceAll(sig, " ", "")
+	open := strings.IndexByte(sig, '(')
+	if open <= 0 || !strings.HasSuffix(sig, ")") {
+		return nil, fmt.Errorf("%w: %q", abistore.ErrInvalidSignature, sig)
+	}
+	name, params := sig[:open], sig[open+1:len(sig)-1]
+	if strings.ContainsAny(params, "()") {
+		return nil, fmt.Errorf("%w: tuple arguments not supported: %q", abistore.ErrInvalidSignature, sig)
+	}
+
+	var inputs abi.Arguments
+	if len(params) > 0 {
+		for i, param := range strings.Split(params, ",") {
+			typ, err := abi.NewType(param, "", nil)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q: %v", abistore.ErrInvalidSignature, sig, err)
+			}
+			inputs = append(inputs, abi.Argument{Name: fmt.Sprintf("arg%d", i), Type: typ})
+		}
+	}
+	method := abi.NewMethod(name, name, abi.Function, "", false, false, inputs, nil)
+	return &method, nil
+}
+
+func (s *AbiStoreModule) HandleSetSignature(sig string) error {
+	method, err := parseSignature(sig)
+	if err != nil {
+		return err
+	}
+	WriteSignature(s.ChainKv, method.ID, method.Sig)
+	return nil
+}
+
+// getSignatureMethod returns the method registered for the selector, or nil if none.
+func (s *AbiStoreModule) getSignatureMethod(selector []byte) *abi.Method {
+	sig := ReadSignature(s.ChainKv, selector)
+	if sig == "" {
+		return nil
+	}
+	method, err := parseSignature(sig)
+	if err != nil {
+		return nil
+	}
+	return method
+}
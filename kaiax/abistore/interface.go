@@ -0,0 +1,60 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package abistore
+
+import (
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax"
+)
+
+type AbiStoreModule interface {
+	kaiax.BaseModule
+	kaiax.JsonRpcModule
+
+	// GetABI returns the JSON ABI registered for the contract, or nil if none.
+	GetABI(addr common.Address) []byte
+
+	// DecodeCalldata decodes the transaction input against the contract ABI,
+	// falling back to the registered method signatures.
+	DecodeCalldata(addr common.Address, data []byte) (*DecodedCall, error)
+
+	// DecodeTransaction decodes a raw transaction of any Kaia or Ethereum type into its
+	// RPC representation, with the sender and the decoded input if available.
+	DecodeTransaction(rawTx []byte) (map[string]interface{}, error)
+
+	// DecodeRevert decodes the revert data, either `Error(string)` or a custom error
+	// declared in the contract ABI, into a human-readable string.
+	DecodeRevert(addr common.Address, data []byte) (string, error)
+
+	// HandleSetABI validates and stores the JSON ABI for the contract.
+	HandleSetABI(addr common.Address, abiJson []byte) error
+
+	// HandleRemoveABI deletes the ABI of the contract, if any.
+	HandleRemoveABI(addr common.Address)
+
+	// HandleSetSignature validates and stores a method signature such as
+	// "transfer(address,uint256)" under its selector.
+	HandleSetSignature(sig string) error
+
+	// GetContractMetadata returns the verification metadata of the contract code,
+	// fetching it from the configured endpoint if not cached.
+	GetContractMetadata(addr common.Address, codeHash common.Hash) (*ContractMetadata, error)
+
+	// ContractName returns the verified name of the contract code without blocking.
+	// A miss schedules a background fetch.
+	ContractName(addr common.Address, codeHash common.Hash) (string, bool)
+}
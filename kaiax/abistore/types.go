@@ -0,0 +1,46 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package abistore
+
+import (
+	"encoding/json"
+
+	"github.com/kaiachain/kaia/common"
+)
+
+// DecodedCall is a transaction input decoded against a contract ABI.
+type DecodedCall struct {
+	Method    string                 `json:"method"`
+	Signature string                 `json:"signature"`
+	Args      map[string]interface{} `json:"args"`
+}
+
+// ContractABI is the export/import unit of the store. Exported files contain
+// one JSON-encoded ContractABI per line.
+type ContractABI struct {
+	Address common.Address  `json:"address"`
+	ABI     json.RawMessage `json:"abi"`
+}
+
+// ContractMetadata is the verification metadata of a contract code, as fetched
+// from the configured explorer or Sourcify-style endpoint.
+type ContractMetadata struct {
+	Name     string          `json:"name,omitempty"`
+	Compiler string          `json:"compiler,omitempty"`
+	ABI      json.RawMessage `json:"abi,omitempty"`
+	Source   string          `json:"source"` // URL the metadata was fetched from
+}
@@ -0,0 +1,39 @@
+package gov
+
+import (
+	"math/big"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+)
+
+// BlobFee returns the fee for the blob gas of tx at BlobBaseFee. It is zero for a non-blob tx.
+func (p *ParamSet) BlobFee(tx *types.Transaction) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(tx.BlobGas()), new(big.Int).SetUint64(p.BlobBaseFee))
+}
+
+// CheckBlobTx returns an error if the blob gas of tx cannot fit in a block or its blob fee cap
+// is below BlobBaseFee. Whether the blob tx hardfork is enabled is NOT checked.
+func (p *ParamSet) CheckBlobTx(tx *types.Transaction) error {
+	if tx.Type() != types.TxTypeEthereumBlob {
+		return nil
+	}
+	if tx.BlobGas() > p.MaxBlobGasPerBlock {
+		return ErrBlobGasLimitExceeded
+	}
+	if tx.BlobGasFeeCap().Cmp(new(big.Int).SetUint64(p.BlobBaseFee)) < 0 {
+		return ErrBlobFeeCapTooLow
+	}
+	return nil
+}
+
+// CheckBlockBlobGas returns an error if the total blob gas of txs exceeds MaxBlobGasPerBlock.
+func (p *ParamSet) CheckBlockBlobGas(txs []*types.Transaction) error {
+	var used uint64
+	for _, tx := range txs {
+		used += tx.BlobGas()
+		if used > p.MaxBlobGasPerBlock {
+			return ErrBlobGasLimitExceeded
+		}
+	}
+	return nil
+}
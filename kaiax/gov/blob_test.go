@@ -0,0 +1,60 @@
+package gov
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBlobTx(t *testing.T, numBlobs int, blobFeeCap int64) *types.Transaction {
+	hashes := make([]common.Hash, numBlobs)
+	for i := range hashes {
+		hashes[i][0] = types.BlobTxHashVersion
+	}
+	tx, err := types.NewTransactionWithMap(types.TxTypeEthereumBlob, map[types.TxValueKeyType]interface{}{
+		types.TxValueKeyChainID:    big.NewInt(1),
+		types.TxValueKeyNonce:      uint64(0),
+		types.TxValueKeyTo:         common.HexToAddress("0x0000000000000000000000000000000000001234"),
+		types.TxValueKeyAmount:     big.NewInt(0),
+		types.TxValueKeyData:       []byte{},
+		types.TxValueKeyGasLimit:   uint64(21000),
+		types.TxValueKeyGasFeeCap:  big.NewInt(1),
+		types.TxValueKeyGasTipCap:  big.NewInt(1),
+		types.TxValueKeyAccessList: types.AccessList{},
+		types.TxValueKeyBlobFeeCap: big.NewInt(blobFeeCap),
+		types.TxValueKeyBlobHashes: hashes,
+	})
+	require.NoError(t, err)
+	return tx
+}
+
+func TestCheckBlobTx(t *testing.T) {
+	ps := &ParamSet{
+		BlobBaseFee:        10,
+		MaxBlobGasPerBlock: 2 * params.BlobTxBlobGasPerBlob,
+	}
+
+	legacy := types.NewTransaction(0, common.Address{}, big.NewInt(0), 21000, big.NewInt(1), nil)
+	assert.NoError(t, ps.CheckBlobTx(legacy))
+	assert.Equal(t, big.NewInt(0), ps.BlobFee(legacy))
+
+	tx := newTestBlobTx(t, 2, 10)
+	assert.NoError(t, ps.CheckBlobTx(tx))
+	assert.Equal(t, big.NewInt(int64(20*params.BlobTxBlobGasPerBlob)), ps.BlobFee(tx))
+
+	assert.ErrorIs(t, ps.CheckBlobTx(newTestBlobTx(t, 3, 10)), ErrBlobGasLimitExceeded)
+	assert.ErrorIs(t, ps.CheckBlobTx(newTestBlobTx(t, 1, 9)), ErrBlobFeeCapTooLow)
+}
+
+func TestCheckBlockBlobGas(t *testing.T) {
+	ps := &ParamSet{MaxBlobGasPerBlock: 2 * params.BlobTxBlobGasPerBlob}
+
+	assert.NoError(t, ps.CheckBlockBlobGas(nil))
+	assert.NoError(t, ps.CheckBlockBlobGas([]*types.Transaction{newTestBlobTx(t, 1, 0), newTestBlobTx(t, 1, 0)}))
+	assert.ErrorIs(t, ps.CheckBlockBlobGas([]*types.Transaction{newTestBlobTx(t, 1, 0), newTestBlobTx(t, 2, 0)}), ErrBlobGasLimitExceeded)
+}
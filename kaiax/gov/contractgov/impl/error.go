@@ -7,4 +7,6 @@ var (
 
 	ErrNotReady      = errors.New("ContractEngine is not ready")
 	ErrHeaderGovFail = errors.New("headerGov EffectiveParams() failed")
+
+	ErrInvalidCheckpoints = errors.New("getAllCheckpoints result invalid")
 )
@@ -1,8 +1,12 @@
 package impl
 
 import (
+	"cmp"
 	"math/big"
+	"slices"
+	"strings"
 
+	"github.com/kaiachain/kaia/accounts/abi/bind"
 	"github.com/kaiachain/kaia/accounts/abi/bind/backends"
 	"github.com/kaiachain/kaia/common"
 	govcontract "github.com/kaiachain/kaia/contracts/contracts/system_contracts/gov"
@@ -10,7 +14,7 @@ import (
 )
 
 // EffectiveParamSet returns default parameter set in case of the following errors:
-// (1) contractgov is disabled (i.e., pre-Kore or GovParam address is zero)
+// (1) contractgov is disabled (i.e., pre-Kore unless ContractGovFromGenesis, or GovParam address is zero)
 // (2) GovParam address is not set
 // (3) Contract call to GovParam failed
 // Invalid parameters in the contract (i.e., invalid parameter name or non-canonical value) are ignored.
@@ -39,6 +43,113 @@ func (c *contractGovModule) EffectiveParamsPartial(blockNum uint64) gov.PartialP
 	return m
 }
 
+// ParamChangeBlocks returns the activation blocks in (from, to] of the parameters in the GovParam
+// contracts effective at from and to, in ascending order.
+func (c *contractGovModule) ParamChangeBlocks(from, to uint64) []uint64 {
+	blocks := make(map[uint64]bool)
+	for _, num := range []uint64{from, to} {
+		addr, err := c.contractAddrAt(num)
+		if err != nil || common.EmptyAddress(addr) {
+			continue
+		}
+		checkpoints, err := c.contractGetAllCheckpoints(num, addr)
+		if err != nil {
+			readFailureCounter.Inc(1)
+			logger.Warn("Failed to get the checkpoints of GovParam", "addr", addr, "err", err)
+			continue
+		}
+		for _, activation := range checkpoints {
+			if from < activation && activation <= to {
+				blocks[activation] = true
+			}
+		}
+	}
+
+	ret := make([]uint64, 0, len(blocks))
+	for num := range blocks {
+		ret = append(ret, num)
+	}
+	slices.Sort(ret)
+	return ret
+}
+
+// contractGetAllCheckpoints returns the activation blocks of all parameters in the contract.
+func (c *contractGovModule) contractGetAllCheckpoints(blockNum uint64, addr common.Address) ([]uint64, error) {
+	chain := c.Chain
+	if chain == nil {
+		return nil, ErrNotReady
+	}
+	if !c.ChainConfig.IsContractGovEnabled(new(big.Int).SetUint64(blockNum)) {
+		return nil, nil
+	}
+
+	caller := backends.NewBlockchainContractBackend(chain, nil, nil)
+	contract, err := govcontract.NewGovParamCaller(addr, caller)
+	if err != nil {
+		return nil, err
+	}
+	_, checkpoints, err := contract.GetAllCheckpoints(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []uint64
+	for _, params := range checkpoints {
+		for _, param := range params {
+			if param.Activation != nil && param.Activation.IsUint64() {
+				ret = append(ret, param.Activation.Uint64())
+			}
+		}
+	}
+	return ret, nil
+}
+
+// ExportRecords returns all checkpoints of the GovParam contract effective at blockNum,
+// sorted by name and activation. It returns nil if there is no such contract.
+func (c *contractGovModule) ExportRecords(blockNum uint64) (*gov.GovParamRecords, error) {
+	if c.Chain == nil {
+		return nil, ErrNotReady
+	}
+	addr, err := c.contractAddrAt(blockNum)
+	if err != nil || common.EmptyAddress(addr) || !c.ChainConfig.IsContractGovEnabled(new(big.Int).SetUint64(blockNum)) {
+		return nil, err
+	}
+
+	caller := backends.NewBlockchainContractBackend(c.Chain, nil, nil)
+	contract, err := govcontract.NewGovParamCaller(addr, caller)
+	if err != nil {
+		return nil, err
+	}
+	names, checkpoints, err := contract.GetAllCheckpoints(&bind.CallOpts{BlockNumber: new(big.Int).SetUint64(blockNum)})
+	if err != nil {
+		return nil, err
+	}
+	if len(names) != len(checkpoints) {
+		return nil, ErrInvalidCheckpoints
+	}
+
+	records := make([]gov.ContractRecord, 0)
+	for i, name := range names {
+		for _, param := range checkpoints[i] {
+			if param.Activation == nil || !param.Activation.IsUint64() {
+				continue
+			}
+			record := gov.ContractRecord{Name: name, Activation: param.Activation.Uint64()}
+			if param.Exists {
+				record.Value = param.Val
+			}
+			records = append(records, record)
+		}
+	}
+	slices.SortStableFunc(records, func(a, b gov.ContractRecord) int {
+		if a.Name != b.Name {
+			return strings.Compare(a.Name, b.Name)
+		}
+		return cmp.Compare(a.Activation, b.Activation)
+	})
+	return &gov.GovParamRecords{Address: addr, Records: records}, nil
+}
+
 func (c *contractGovModule) contractGetAllParamsAt(blockNum uint64) (gov.PartialParamSet, error) {
 	addr, err := c.contractAddrAt(blockNum)
 	if err != nil {
@@ -59,23 +170,26 @@ func (c *contractGovModule) contractGetAllParamsAtFromAddr(blockNum uint64, addr
 	}
 
 	config := c.ChainConfig
-	if !config.IsKoreForkEnabled(new(big.Int).SetUint64(blockNum)) {
+	if !config.IsContractGovEnabled(new(big.Int).SetUint64(blockNum)) {
 		return nil, ErrNotReady
 	}
 
 	caller := backends.NewBlockchainContractBackend(chain, nil, nil)
 	contract, err := govcontract.NewGovParamCaller(addr, caller)
 	if err != nil {
+		readFailureCounter.Inc(1)
 		return nil, err
 	}
 
 	names, values, err := contract.GetAllParamsAt(nil, new(big.Int).SetUint64(blockNum))
 	if err != nil {
+		readFailureCounter.Inc(1)
 		logger.Warn("ContractEngine disabled: getAllParams call failed", "err", err)
 		return nil, nil
 	}
 
 	if len(names) != len(values) {
+		readFailureCounter.Inc(1)
 		logger.Warn("ContractEngine disabled: getAllParams result invalid", "len(names)", len(names), "len(values)", len(values))
 		return nil, nil
 	}
@@ -89,10 +203,13 @@ func (c *contractGovModule) contractAddrAt(blockNum uint64) (common.Address, err
 	return headerParams.GovParamContract, nil
 }
 
+// ParseContractCall returns the valid parameters of a GetAllParamsAt result. Invalid ones are skipped.
 func ParseContractCall(names []string, values [][]byte) gov.PartialParamSet {
 	ret := make(gov.PartialParamSet)
 	for i := 0; i < len(names); i++ {
-		ret.Add(names[i], values[i])
+		if err := ret.Add(names[i], values[i]); err != nil {
+			logger.Debug("Skipping invalid parameter in GovParam", "name", names[i], "err", err)
+		}
 	}
 
 	return ret
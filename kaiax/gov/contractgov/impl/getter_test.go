@@ -107,3 +107,38 @@ func TestEffectiveParamSet(t *testing.T) {
 		assert.Equal(t, uint64(125), ps.UnitPrice)
 	}
 }
+
+func TestEffectiveParamSetContractGovFromGenesis(t *testing.T) {
+	log.EnableLogForTest(log.LvlCrit, log.LvlError)
+	paramName := string(gov.GovernanceUnitPrice)
+	accounts, sim, addr, gp := createSimulateBackend(t)
+
+	activation := big.NewInt(10)
+	tx, err := gp.SetParam(accounts[0], paramName, true, []byte{0, 0, 0, 0, 0, 0, 0, 25}, activation)
+	require.Nil(t, err)
+	sim.Commit()
+	receipt, _ := sim.TransactionReceipt(nil, tx.Hash())
+	require.NotNil(t, receipt)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+
+	testcases := []struct {
+		fromGenesis bool
+		expected    uint64
+	}{
+		{false, gov.GetDefaultGovernanceParamSet().UnitPrice}, // pre-Kore
+		{true, 25},
+	}
+	for _, tc := range testcases {
+		mockHGM := headergov_mock.NewMockHeaderGovModule(gomock.NewController(t))
+		mockHGM.EXPECT().EffectiveParamSet(gomock.Any()).Return(gov.ParamSet{GovParamContract: addr}).AnyTimes()
+		cgm := NewContractGovModule()
+		require.Nil(t, cgm.Init(&InitOpts{
+			Chain:       sim.BlockChain(),
+			ChainConfig: &params.ChainConfig{KoreCompatibleBlock: big.NewInt(100), ContractGovFromGenesis: tc.fromGenesis},
+			Hgm:         mockHGM,
+		}))
+
+		ps := cgm.EffectiveParamSet(activation.Uint64())
+		assert.Equal(t, tc.expected, ps.UnitPrice, "fromGenesis=%v", tc.fromGenesis)
+	}
+}
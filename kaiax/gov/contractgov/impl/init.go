@@ -9,12 +9,15 @@ import (
 	"github.com/kaiachain/kaia/kaiax/gov/headergov"
 	"github.com/kaiachain/kaia/log"
 	"github.com/kaiachain/kaia/params"
+	"github.com/rcrowley/go-metrics"
 )
 
 var (
 	_ contractgov.ContractGovModule = (*contractGovModule)(nil)
 
 	logger = log.NewModuleLogger(log.KaiaxGov)
+
+	readFailureCounter = metrics.NewRegisteredCounter("kaiax/gov/contract/failures", nil)
 )
 
 type chain interface {
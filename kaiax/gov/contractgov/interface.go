@@ -9,7 +9,8 @@ import (
 type ContractGovModule interface {
 	kaiax.BaseModule
 	kaiax.JsonRpcModule
+	gov.ParamSource
 
 	EffectiveParamSet(blockNum uint64) gov.ParamSet
-	EffectiveParamsPartial(blockNum uint64) gov.PartialParamSet
+	ExportRecords(blockNum uint64) (*gov.GovParamRecords, error)
 }
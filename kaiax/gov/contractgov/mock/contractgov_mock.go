@@ -77,6 +77,35 @@ func (mr *MockContractGovModuleMockRecorder) EffectiveParamsPartial(arg0 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EffectiveParamsPartial", reflect.TypeOf((*MockContractGovModule)(nil).EffectiveParamsPartial), arg0)
 }
 
+// ExportRecords mocks base method.
+func (m *MockContractGovModule) ExportRecords(arg0 uint64) (*gov.GovParamRecords, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportRecords", arg0)
+	ret0, _ := ret[0].(*gov.GovParamRecords)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportRecords indicates an expected call of ExportRecords.
+func (mr *MockContractGovModuleMockRecorder) ExportRecords(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportRecords", reflect.TypeOf((*MockContractGovModule)(nil).ExportRecords), arg0)
+}
+
+// ParamChangeBlocks mocks base method.
+func (m *MockContractGovModule) ParamChangeBlocks(arg0, arg1 uint64) []uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParamChangeBlocks", arg0, arg1)
+	ret0, _ := ret[0].([]uint64)
+	return ret0
+}
+
+// ParamChangeBlocks indicates an expected call of ParamChangeBlocks.
+func (mr *MockContractGovModuleMockRecorder) ParamChangeBlocks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParamChangeBlocks", reflect.TypeOf((*MockContractGovModule)(nil).ParamChangeBlocks), arg0, arg1)
+}
+
 // Start mocks base method.
 func (m *MockContractGovModule) Start() error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,44 @@
+package gov
+
+import (
+	"math/big"
+
+	"github.com/kaiachain/kaia/params"
+)
+
+// Deprecation retires a parameter at a hardfork. Once the hardfork is enabled, a new value for the
+// parameter has no effect and the RPC APIs show the parameter as Value if given. The effective
+// parameter set is not affected, so a deprecation never changes the consensus.
+type Deprecation struct {
+	Hardfork func(config *params.ChainConfig) *big.Int // returns the activation block of the hardfork
+	Value    any                                       // canonical value
+}
+
+var (
+	kore   = func(config *params.ChainConfig) *big.Int { return config.KoreCompatibleBlock }
+	randao = func(config *params.ChainConfig) *big.Int { return config.RandaoCompatibleBlock }
+	kaia   = func(config *params.ChainConfig) *big.Int { return config.KaiaCompatibleBlock }
+)
+
+// IsActive returns true if the parameter is deprecated at the given block.
+func (d *Deprecation) IsActive(config *params.ChainConfig, num uint64) bool {
+	activation := d.Hardfork(config)
+	return activation != nil && activation.Cmp(new(big.Int).SetUint64(num)) <= 0
+}
+
+// IsDeprecated returns true if the parameter is deprecated at the given block,
+// i.e. a new value for it has no effect.
+func IsDeprecated(config *params.ChainConfig, name ParamName, num uint64) bool {
+	param, ok := Params[name]
+	return ok && param.Deprecation != nil && param.Deprecation.IsActive(config, num)
+}
+
+// ApplyDeprecations fixes the parameters deprecated at the given block to their deprecated values.
+// It is meant for displaying the parameters, e.g. by the RPC APIs, not for the consensus.
+func (p *ParamSet) ApplyDeprecations(config *params.ChainConfig, num uint64) {
+	for name, param := range Params {
+		if d := param.Deprecation; d != nil && d.Value != nil && d.IsActive(config, num) {
+			p.Set(name, d.Value)
+		}
+	}
+}
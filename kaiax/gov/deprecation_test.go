@@ -0,0 +1,48 @@
+package gov
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kaiachain/kaia/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeprecationDeclaration(t *testing.T) {
+	for name, param := range Params {
+		d := param.Deprecation
+		if d == nil {
+			continue
+		}
+		require.NotNil(t, d.Hardfork, name)
+		if d.Value != nil {
+			cv, err := param.Canonicalize(name, d.Value)
+			require.NoError(t, err, name)
+			assert.Equal(t, d.Value, cv, name)
+		}
+	}
+}
+
+func TestIsDeprecated(t *testing.T) {
+	config := &params.ChainConfig{KoreCompatibleBlock: big.NewInt(100)}
+
+	assert.False(t, IsDeprecated(config, GovernanceUnitPrice, 100))
+	assert.True(t, IsDeprecated(config, RewardUseGiniCoeff, 100))
+	assert.False(t, IsDeprecated(config, RewardUseGiniCoeff, 99))
+	assert.False(t, IsDeprecated(&params.ChainConfig{}, RewardUseGiniCoeff, 100))
+}
+
+func TestApplyDeprecations(t *testing.T) {
+	config := &params.ChainConfig{KoreCompatibleBlock: big.NewInt(100), KaiaCompatibleBlock: big.NewInt(300)}
+
+	ps := GetDefaultGovernanceParamSet()
+	ps.UseGiniCoeff = true
+	ps.ApplyDeprecations(config, 99)
+	assert.True(t, ps.UseGiniCoeff)
+	ps.ApplyDeprecations(config, 100)
+	assert.False(t, ps.UseGiniCoeff)
+	assert.Equal(t, uint64(86400), ps.StakingUpdateInterval)
+	ps.ApplyDeprecations(config, 300)
+	assert.Equal(t, uint64(1), ps.StakingUpdateInterval)
+}
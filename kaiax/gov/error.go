@@ -9,6 +9,17 @@ var (
 	ErrInvalidParamValue = errors.New("invalid param value")
 	ErrCannotSet         = errors.New("invalid field or cannot set the value")
 	ErrUnknownBlock      = errors.New("unknown block")
+	ErrInvalidBlockRange = errors.New("invalid block range")
+	ErrTxPaused          = errors.New("tx is paused by governance")
+	ErrParamDeprecated   = errors.New("param is deprecated")
+	ErrAuditLogDisabled  = errors.New("governance audit log is disabled")
+	ErrInvalidCount      = errors.New("invalid count")
+
+	ErrInvalidActivationDelay = errors.New("activation delay must be a param name and a number of blocks separated by ':'")
+
+	ErrBlobTxDisabled       = errors.New("blob tx is not enabled")
+	ErrBlobFeeCapTooLow     = errors.New("max fee per blob gas is below the blob base fee")
+	ErrBlobGasLimitExceeded = errors.New("blob gas exceeds the max blob gas per block")
 
 	ErrCanonicalizeUint64        = errors.New("could not canonicalize value to uint64")
 	ErrCanonicalizeString        = errors.New("could not canonicalize value to string")
@@ -7,5 +7,7 @@ var (
 	ErrInvalidJson     = errors.New("invalid json")
 	ErrInvalidGovData  = errors.New("invalid gov data")
 	ErrInvalidVoteData = errors.New("invalid vote data")
+	ErrVoteForbidden   = errors.New("the parameter cannot be changed by vote")
+	ErrNotEmergency    = errors.New("the parameter cannot be changed by emergency vote")
 	ErrNoHistory       = errors.New("history search failed")
 )
@@ -3,19 +3,30 @@ package headergov
 import (
 	"encoding/json"
 	"math/big"
+	"strconv"
 
 	"github.com/kaiachain/kaia/common/hexutil"
 	"github.com/kaiachain/kaia/kaiax/gov"
 	"github.com/kaiachain/kaia/rlp"
 )
 
+// ScheduledKey is the key of header.Governance which holds the ratified changes with explicit activation blocks.
+const ScheduledKey = "scheduled"
+
+// EmergencyKey is the key of header.Governance which holds the changes ratified by the emergency votes of the council.
+// They take effect from the block after the header.
+const EmergencyKey = "emergency"
+
 type (
-	GovBytes   []byte
-	GovDataMap map[uint64]GovData
+	GovBytes        []byte
+	GovDataMap      map[uint64]GovData
+	ScheduledParams map[uint64]gov.PartialParamSet // activation block -> params
 )
 
 type govData struct {
-	items gov.PartialParamSet
+	items     gov.PartialParamSet
+	scheduled ScheduledParams     // nil if empty
+	emergency gov.PartialParamSet // nil if empty
 }
 
 // NewGovData returns a canonical & formatted gov data. It returns nil if any entry from `m` is invalid.
@@ -34,23 +45,96 @@ func NewGovData(m gov.PartialParamSet) GovData {
 	}
 }
 
+// NewScheduledGovData is NewGovData with the changes scheduled at explicit activation blocks.
+// It returns nil if any entry from `m` or `scheduled` is invalid.
+func NewScheduledGovData(m gov.PartialParamSet, scheduled map[uint64]gov.PartialParamSet) GovData {
+	g := NewGovData(m)
+	if g == nil {
+		return nil
+	}
+
+	for activation, params := range scheduled {
+		if activation == 0 {
+			return nil
+		}
+		if len(params) == 0 {
+			continue
+		}
+		if g.(*govData).scheduled == nil {
+			g.(*govData).scheduled = make(ScheduledParams)
+		}
+		items := NewGovData(params)
+		if items == nil {
+			return nil
+		}
+		g.(*govData).scheduled[activation] = items.Items()
+	}
+	return g
+}
+
+// NewEmergencyGovData is NewScheduledGovData with the emergency changes which take effect from the next block.
+// It returns nil if any entry is invalid.
+func NewEmergencyGovData(m gov.PartialParamSet, scheduled map[uint64]gov.PartialParamSet, emergency gov.PartialParamSet) GovData {
+	g := NewScheduledGovData(m, scheduled)
+	if g == nil {
+		return nil
+	}
+
+	if len(emergency) > 0 {
+		items := NewGovData(emergency)
+		if items == nil {
+			return nil
+		}
+		g.(*govData).emergency = items.Items()
+	}
+	return g
+}
+
 func (g *govData) MarshalJSON() ([]byte, error) {
-	tmp := make(gov.PartialParamSet)
-	for name, value := range g.items {
-		if bigInt, ok := value.(*big.Int); ok {
-			tmp[name] = bigInt.String()
-		} else {
-			tmp[name] = value
+	tmp := make(map[string]any)
+	for name, value := range stringifyBigInts(g.items) {
+		tmp[string(name)] = value
+	}
+
+	if len(g.scheduled) > 0 {
+		scheduled := make(map[uint64]gov.PartialParamSet)
+		for activation, params := range g.scheduled {
+			scheduled[activation] = stringifyBigInts(params)
 		}
+		tmp[ScheduledKey] = scheduled
+	}
+
+	if len(g.emergency) > 0 {
+		tmp[EmergencyKey] = stringifyBigInts(g.emergency)
 	}
 
 	return json.Marshal(tmp)
 }
 
+func stringifyBigInts(m gov.PartialParamSet) gov.PartialParamSet {
+	ret := make(gov.PartialParamSet)
+	for name, value := range m {
+		if bigInt, ok := value.(*big.Int); ok {
+			ret[name] = bigInt.String()
+		} else {
+			ret[name] = value
+		}
+	}
+	return ret
+}
+
 func (g *govData) Items() gov.PartialParamSet {
 	return g.items
 }
 
+func (g *govData) Scheduled() ScheduledParams {
+	return g.scheduled
+}
+
+func (g *govData) Emergency() gov.PartialParamSet {
+	return g.emergency
+}
+
 func (g *govData) ToGovBytes() (GovBytes, error) {
 	j, err := g.MarshalJSON()
 	if err != nil {
@@ -72,11 +156,29 @@ func (gb GovBytes) ToGovData() (GovData, error) {
 		return nil, ErrInvalidJson
 	}
 
+	var scheduled map[uint64]gov.PartialParamSet
+	if raw, ok := m[ScheduledKey]; ok {
+		delete(m, ScheduledKey)
+		if scheduled, err = parseScheduled(raw); err != nil {
+			return nil, err
+		}
+	}
+
+	var emergency gov.PartialParamSet
+	if raw, ok := m[EmergencyKey]; ok {
+		delete(m, EmergencyKey)
+		if emergency, err = parseParams(raw); err != nil {
+			return nil, err
+		}
+	}
+
 	for name, value := range m {
-		m.Add(string(name), value)
+		if err := m.Add(string(name), value); err != nil {
+			return nil, err
+		}
 	}
 
-	gov := NewGovData(m)
+	gov := NewEmergencyGovData(m, scheduled, emergency)
 	if gov == nil {
 		return nil, ErrInvalidGovData
 	}
@@ -84,6 +186,42 @@ func (gb GovBytes) ToGovData() (GovData, error) {
 	return gov, nil
 }
 
+// parseScheduled parses the JSON-decoded value of ScheduledKey.
+func parseScheduled(raw any) (map[uint64]gov.PartialParamSet, error) {
+	m, ok := raw.(map[string]any)
+	if !ok {
+		return nil, ErrInvalidGovData
+	}
+
+	ret := make(map[uint64]gov.PartialParamSet)
+	for key, value := range m {
+		activation, err := strconv.ParseUint(key, 10, 64)
+		if err != nil {
+			return nil, ErrInvalidGovData
+		}
+		params, err := parseParams(value)
+		if err != nil {
+			return nil, err
+		}
+		ret[activation] = params
+	}
+	return ret, nil
+}
+
+// parseParams parses a JSON-decoded object of params.
+func parseParams(raw any) (gov.PartialParamSet, error) {
+	m, ok := raw.(map[string]any)
+	if !ok {
+		return nil, ErrInvalidGovData
+	}
+
+	ret := make(gov.PartialParamSet)
+	for name, v := range m {
+		ret[gov.ParamName(name)] = v
+	}
+	return ret, nil
+}
+
 func (gb GovBytes) String() string {
 	return hexutil.Encode(gb)
 }
@@ -151,3 +151,56 @@ func TestGovSerialization(t *testing.T) {
 		})
 	}
 }
+
+func TestScheduledGovSerialization(t *testing.T) {
+	data := NewScheduledGovData(gov.PartialParamSet{
+		gov.GovernanceUnitPrice: uint64(25e9),
+	}, map[uint64]gov.PartialParamSet{
+		3500: {gov.Kip71LowerBoundBaseFee: uint64(50e9), gov.RewardMintingAmount: big.NewInt(6.4e18)},
+		4000: {gov.Kip71UpperBoundBaseFee: uint64(500e9)},
+	})
+	assert.NotNil(t, data)
+	assert.Len(t, data.Scheduled(), 2)
+
+	serialized, err := data.ToGovBytes()
+	assert.NoError(t, err)
+	actual, err := serialized.ToGovData()
+	assert.NoError(t, err)
+	assert.Equal(t, data, actual)
+
+	// Empty schedules are dropped so that unscheduled governance data encodes as before.
+	assert.Equal(t,
+		NewGovData(gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(25e9)}),
+		NewScheduledGovData(gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(25e9)}, map[uint64]gov.PartialParamSet{3500: {}}))
+
+	// Invalid schedules are rejected.
+	assert.Nil(t, NewScheduledGovData(nil, map[uint64]gov.PartialParamSet{0: {gov.GovernanceUnitPrice: uint64(1)}}))
+	assert.Nil(t, NewScheduledGovData(nil, map[uint64]gov.PartialParamSet{100: {"nonexistent.param": uint64(1)}}))
+}
+
+func TestEmergencyGovSerialization(t *testing.T) {
+	data := NewEmergencyGovData(nil, nil, gov.PartialParamSet{gov.Kip71LowerBoundBaseFee: uint64(50e9)})
+	assert.NotNil(t, data)
+	assert.Empty(t, data.Items())
+	assert.Equal(t, gov.PartialParamSet{gov.Kip71LowerBoundBaseFee: uint64(50e9)}, data.Emergency())
+
+	serialized, err := data.ToGovBytes()
+	assert.NoError(t, err)
+	actual, err := serialized.ToGovData()
+	assert.NoError(t, err)
+	assert.Equal(t, data, actual)
+
+	// Emergency changes can be ratified along with the regular ones at an epoch block.
+	data = NewEmergencyGovData(gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(25e9)}, nil, gov.PartialParamSet{gov.IstanbulCommitteeSize: uint64(7)})
+	serialized, err = data.ToGovBytes()
+	assert.NoError(t, err)
+	actual, err = serialized.ToGovData()
+	assert.NoError(t, err)
+	assert.Equal(t, data, actual)
+
+	// Without emergency changes, the governance data encodes as before.
+	assert.Equal(t,
+		NewGovData(gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(25e9)}),
+		NewEmergencyGovData(gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(25e9)}, nil, nil))
+	assert.Nil(t, NewEmergencyGovData(nil, nil, gov.PartialParamSet{"nonexistent.param": uint64(1)}))
+}
@@ -1,7 +1,11 @@
 package impl
 
 import (
+	"reflect"
+	"slices"
+
 	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/gov"
 	"github.com/kaiachain/kaia/kaiax/gov/headergov"
 	"github.com/kaiachain/kaia/networks/rpc"
 )
@@ -28,10 +32,12 @@ type VotesResponse struct {
 }
 
 type MyVotesResponse struct {
-	BlockNum uint64
-	Key      string
-	Value    any
-	Casted   bool
+	BlockNum        uint64
+	Key             string
+	Value           any
+	ActivationBlock uint64 `json:",omitempty"`
+	Emergency       bool   `json:",omitempty"`
+	Casted          bool
 }
 
 type StatusResponse struct {
@@ -42,36 +48,133 @@ type StatusResponse struct {
 	MyVotes      []headergov.VoteData              `json:"myVotes"`
 }
 
+type ProposalStatusResponse struct {
+	Epoch      uint64           `json:"epoch"`      // the current epoch whose votes are tallied
+	TallyBlock uint64           `json:"tallyBlock"` // the epoch block where the votes are ratified
+	TallyMode  string           `json:"tallyMode"`
+	Proposals  []ProposalStatus `json:"proposals"`
+}
+
+type ValidateVoteResponse struct {
+	Valid     bool              `json:"valid"`
+	Reason    string            `json:"reason,omitempty"`    // why the vote would be rejected
+	Conflicts []MyVotesResponse `json:"conflicts,omitempty"` // votes of this node for the same parameter with other values
+}
+
 func NewHeaderGovAPI(s *headerGovModule) *headerGovAPI {
 	return &headerGovAPI{s}
 }
 
-func (api *headerGovAPI) Vote(name string, value any) (string, error) {
+// Vote casts a vote for a parameter. If activation is given, the change takes effect at the
+// activation block instead of the start of the epoch after the next.
+func (api *headerGovAPI) Vote(name string, value any, activation *uint64) (string, error) {
+	vote, err := api.validateVote(name, value, activation)
+	if err != nil {
+		return "", err
+	}
+
+	// TODO-kaiax: add removevalidator vote check
+
+	api.h.PushMyVotes(vote)
+	return "(kaiax) Your vote is prepared. It will be put into the block header or applied when your node generates a block as a proposer. Note that your vote may be duplicate.", nil
+}
+
+// EmergencyVote casts an emergency vote for a parameter that can be changed in an emergency. Once more than 2/3
+// of the council vote for the same value in an epoch, the change takes effect from the next block.
+func (api *headerGovAPI) EmergencyVote(name string, value any) (string, error) {
 	var (
 		voter       = api.h.nodeAddress
 		blockNumber = api.h.Chain.CurrentBlock().NumberU64()
-		gp          = api.h.EffectiveParamSet(blockNumber + 1)
-		gMode       = gp.GovernanceMode
 	)
 
-	if gMode == "single" && voter != gp.GoverningNode {
-		return "", ErrVotePermissionDenied
+	if !api.h.isEmergencyEnabled(blockNumber + 1) {
+		return "", ErrEmergencyVoteDisabled
+	}
+	if err := headergov.CheckEmergencyVote(name, value); err != nil {
+		return "", err
+	}
+	if gov.IsDeprecated(api.h.ChainConfig, gov.ParamName(name), blockNumber+1) {
+		return "", gov.ErrParamDeprecated
 	}
 
-	vote := headergov.NewVoteData(voter, name, value)
+	vote := headergov.NewEmergencyVoteData(voter, name, value)
 	if vote == nil {
 		return "", ErrInvalidKeyValue
 	}
+	if err := api.h.checkConsistency(blockNumber+1, vote); err != nil {
+		return "", err
+	}
+	if err := api.h.checkEmergency(blockNumber+1, vote); err != nil {
+		return "", err
+	}
+	if err := api.h.checkActivation(blockNumber+1, vote); err != nil {
+		return "", err
+	}
+
+	api.h.PushMyVotes(vote)
+	return "(kaiax) Your emergency vote is prepared. It will be put into the block header when your node generates a block as a proposer.", nil
+}
 
-	err := api.h.checkConsistency(blockNumber+1, vote)
+// ValidateVote runs the checks of Vote without queuing the vote, and reports why it would be rejected.
+// It also reports the votes of this node for the same parameter with other values, which the vote would override.
+func (api *headerGovAPI) ValidateVote(name string, value any, activation *uint64) *ValidateVoteResponse {
+	vote, err := api.validateVote(name, value, activation)
 	if err != nil {
-		return "", err
+		return &ValidateVoteResponse{Valid: false, Reason: err.Error()}
 	}
 
-	// TODO-kaiax: add removevalidator vote check
+	ret := &ValidateVoteResponse{Valid: true}
+	for _, v := range api.MyVotes() {
+		if v.Key == string(vote.Name()) && v.ActivationBlock == vote.ActivationBlock() && !v.Emergency && !reflect.DeepEqual(v.Value, vote.Value()) {
+			ret.Conflicts = append(ret.Conflicts, v)
+		}
+	}
+	return ret
+}
 
-	api.h.PushMyVotes(vote)
-	return "(kaiax) Your vote is prepared. It will be put into the block header or applied when your node generates a block as a proposer. Note that your vote may be duplicate.", nil
+// validateVote returns the vote of this node if it passes the checks on the voter, the value and the activation.
+func (api *headerGovAPI) validateVote(name string, value any, activation *uint64) (headergov.VoteData, error) {
+	var (
+		voter       = api.h.nodeAddress
+		blockNumber = api.h.Chain.CurrentBlock().NumberU64()
+		gp          = api.h.EffectiveParamSet(blockNumber + 1)
+		gMode       = gp.GovernanceMode
+	)
+
+	if gMode == "single" && voter != gp.GoverningNode {
+		return nil, ErrVotePermissionDenied
+	}
+	if api.h.isMultisig(blockNumber + 1) {
+		signers, _ := gov.ParseAddressList(gp.MultisigSigners)
+		if !slices.Contains(signers, voter) {
+			return nil, ErrVotePermissionDenied
+		}
+	}
+
+	if err := headergov.CheckVoteValue(name, value); err != nil {
+		return nil, err
+	}
+	if gov.IsDeprecated(api.h.ChainConfig, gov.ParamName(name), blockNumber+1) {
+		return nil, gov.ErrParamDeprecated
+	}
+
+	var vote headergov.VoteData
+	if activation != nil {
+		vote = headergov.NewScheduledVoteData(voter, name, value, *activation)
+	} else {
+		vote = headergov.NewVoteData(voter, name, value)
+	}
+	if vote == nil {
+		return nil, ErrInvalidKeyValue
+	}
+
+	if err := api.h.checkConsistency(blockNumber+1, vote); err != nil {
+		return nil, err
+	}
+	if err := api.h.checkActivation(blockNumber+1, vote); err != nil {
+		return nil, err
+	}
+	return vote, nil
 }
 
 func (api *headerGovAPI) IdxCache() []uint64 {
@@ -108,26 +211,56 @@ func (api *headerGovAPI) MyVotes() []MyVotesResponse {
 	for blockNum, vote := range votesInEpoch {
 		if vote.Voter() == api.h.nodeAddress {
 			ret = append(ret, MyVotesResponse{
-				BlockNum: blockNum,
-				Casted:   true,
-				Key:      string(vote.Name()),
-				Value:    vote.Value(),
+				BlockNum:        blockNum,
+				Casted:          true,
+				Key:             string(vote.Name()),
+				Value:           vote.Value(),
+				ActivationBlock: vote.ActivationBlock(),
+				Emergency:       vote.Emergency(),
 			})
 		}
 	}
 
 	for _, vote := range api.h.myVotes {
 		ret = append(ret, MyVotesResponse{
-			BlockNum: 0,
-			Casted:   false,
-			Key:      string(vote.Name()),
-			Value:    vote.Value(),
+			BlockNum:        0,
+			Casted:          false,
+			Key:             string(vote.Name()),
+			Value:           vote.Value(),
+			ActivationBlock: vote.ActivationBlock(),
+			Emergency:       vote.Emergency(),
 		})
 	}
 
 	return ret
 }
 
+// ScheduledChanges returns the ratified changes that are scheduled to take effect after the current block.
+func (api *headerGovAPI) ScheduledChanges() headergov.ScheduledParams {
+	return api.h.PendingScheduled(api.h.Chain.CurrentBlock().NumberU64())
+}
+
+// GetProposalStatus returns the tally of the votes cast in the current epoch: the voters of each
+// proposed value, the votes still needed for the ratification at the next epoch block, and the
+// block where the value would take effect.
+func (api *headerGovAPI) GetProposalStatus() (*ProposalStatusResponse, error) {
+	epochIdx := calcEpochIdx(api.h.Chain.CurrentBlock().NumberU64(), api.h.epoch)
+	tallyBlock := calcEpochStartBlock(epochIdx+1, api.h.epoch)
+	mode, proposals, err := api.h.proposalStatus(tallyBlock)
+	if err != nil {
+		return nil, err
+	}
+	if proposals == nil {
+		proposals = make([]ProposalStatus, 0)
+	}
+	return &ProposalStatusResponse{
+		Epoch:      epochIdx,
+		TallyBlock: tallyBlock,
+		TallyMode:  mode,
+		Proposals:  proposals,
+	}, nil
+}
+
 func (api *headerGovAPI) Status() StatusResponse {
 	api.h.mu.RLock()
 	defer api.h.mu.RUnlock()
@@ -2,8 +2,11 @@ package impl
 
 import (
 	"fmt"
+	"math/big"
 	"testing"
 
+	"github.com/kaiachain/kaia/kaiax/gov"
+	"github.com/kaiachain/kaia/kaiax/gov/headergov"
 	"github.com/kaiachain/kaia/params"
 	"github.com/stretchr/testify/assert"
 )
@@ -13,21 +16,23 @@ func newHeaderGovAPI(t *testing.T) *headerGovAPI {
 		Istanbul: &params.IstanbulConfig{
 			Epoch: 1000,
 		},
+		ScheduledVoteCompatibleBlock: big.NewInt(0),
+		MultisigGovCompatibleBlock:   big.NewInt(0),
 	})
 	return NewHeaderGovAPI(h)
 }
 
 func TestUpperBoundBaseFeeSet(t *testing.T) {
 	api := newHeaderGovAPI(t)
-	s, err := api.Vote("kip71.upperboundbasefee", uint64(1))
-	assert.Equal(t, ErrUpperBoundBaseFee, err)
+	s, err := api.Vote("kip71.upperboundbasefee", uint64(1), nil)
+	assert.Equal(t, &gov.ParamError{Name: gov.Kip71UpperBoundBaseFee, Value: uint64(1), Rule: gov.RuleDependency, Reason: "kip71.lowerboundbasefee <= kip71.upperboundbasefee"}, err)
 	assert.Equal(t, "", s)
 }
 
 func TestLowerBoundBaseFeeSet(t *testing.T) {
 	api := newHeaderGovAPI(t)
-	s, err := api.Vote("kip71.lowerboundbasefee", uint64(1e18))
-	assert.Equal(t, ErrLowerBoundBaseFee, err)
+	s, err := api.Vote("kip71.lowerboundbasefee", uint64(1e18), nil)
+	assert.Equal(t, &gov.ParamError{Name: gov.Kip71LowerBoundBaseFee, Value: uint64(1e18), Rule: gov.RuleDependency, Reason: "kip71.lowerboundbasefee <= kip71.upperboundbasefee"}, err)
 	assert.Equal(t, "", s)
 }
 
@@ -39,18 +44,82 @@ func TestValidatorVotes(t *testing.T) {
 	}{
 		{key: "governance.addvalidator", value: "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"},
 		{key: "governance.addvalidator", value: "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266,0x70997970C51812dc3A010C7d01b50e0d17dc79C8"},
-		{key: "governance.addvalidator", value: ",0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", expectedErr: ErrInvalidKeyValue},
+		{key: "governance.addvalidator", value: ",0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", expectedErr: gov.ErrCanonicalizeStringToAddress},
 		{key: "governance.removevalidator", value: "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"},
 		{key: "governance.removevalidator", value: "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266,0x70997970C51812dc3A010C7d01b50e0d17dc79C8"},
-		{key: "governance.removevalidator", value: ",0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", expectedErr: ErrInvalidKeyValue},
+		{key: "governance.removevalidator", value: ",0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", expectedErr: gov.ErrCanonicalizeStringToAddress},
 	}
 
 	api := newHeaderGovAPI(t)
 
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("case %d", i), func(t *testing.T) {
-			_, err := api.Vote(tc.key, tc.value)
-			assert.Equal(t, tc.expectedErr, err)
+			_, err := api.Vote(tc.key, tc.value, nil)
+			if tc.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, gov.ErrInvalidParamValue)
+				assert.ErrorIs(t, err, tc.expectedErr)
+			}
 		})
 	}
 }
+
+func TestMultisigVotePermission(t *testing.T) {
+	api := newHeaderGovAPI(t)
+	other := "0x0000000000000000000000000000000000000001"
+	setSigners := func(signers string) {
+		api.h.AddGov(0, headergov.NewGovData(gov.PartialParamSet{
+			gov.GovernanceGovernanceMode:    "multisig",
+			gov.GovernanceMultisigSigners:   signers,
+			gov.GovernanceMultisigThreshold: uint64(1),
+		}))
+	}
+
+	setSigners(other)
+	_, err := api.Vote("governance.unitprice", uint64(100), nil)
+	assert.ErrorIs(t, err, ErrVotePermissionDenied)
+
+	setSigners(other + "," + api.h.nodeAddress.Hex())
+	_, err = api.Vote("governance.unitprice", uint64(100), nil)
+	assert.NoError(t, err)
+}
+
+func TestValidateVote(t *testing.T) {
+	api := newHeaderGovAPI(t)
+
+	// Rejected votes are reported with the reason and never queued.
+	res := api.ValidateVote("governance.unknown", uint64(1), nil)
+	assert.Equal(t, &ValidateVoteResponse{Valid: false, Reason: gov.ErrInvalidParamName.Error()}, res)
+
+	res = api.ValidateVote("kip71.upperboundbasefee", uint64(1), nil)
+	assert.False(t, res.Valid)
+	assert.Contains(t, res.Reason, "kip71.lowerboundbasefee <= kip71.upperboundbasefee")
+
+	activation := uint64(1500)
+	res = api.ValidateVote("governance.unitprice", uint64(100), &activation)
+	assert.Equal(t, &ValidateVoteResponse{Valid: false, Reason: ErrActivationTooEarly.Error()}, res)
+
+	res = api.ValidateVote("governance.pauseexpiry", uint64(100), nil)
+	assert.Equal(t, &ValidateVoteResponse{Valid: false, Reason: ErrEmergencyPauseDisabled.Error()}, res)
+	assert.Empty(t, api.h.myVotes)
+
+	// A valid vote is reported along with the pending votes for other values of the same parameter.
+	res = api.ValidateVote("governance.unitprice", uint64(100), nil)
+	assert.Equal(t, &ValidateVoteResponse{Valid: true}, res)
+	assert.Empty(t, api.h.myVotes)
+
+	_, err := api.Vote("governance.unitprice", uint64(100), nil)
+	assert.NoError(t, err)
+	_, err = api.Vote("governance.deriveshaimpl", uint64(2), nil)
+	assert.NoError(t, err)
+
+	res = api.ValidateVote("governance.unitprice", uint64(100), nil)
+	assert.Equal(t, &ValidateVoteResponse{Valid: true}, res)
+
+	res = api.ValidateVote("governance.unitprice", uint64(200), nil)
+	assert.Equal(t, &ValidateVoteResponse{Valid: true, Conflicts: []MyVotesResponse{
+		{Key: "governance.unitprice", Value: uint64(100)},
+	}}, res)
+	assert.Len(t, api.h.myVotes, 2)
+}
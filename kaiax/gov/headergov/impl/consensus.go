@@ -1,7 +1,9 @@
 package impl
 
 import (
+	"math/big"
 	"reflect"
+	"slices"
 
 	"github.com/kaiachain/kaia/blockchain/state"
 	"github.com/kaiachain/kaia/blockchain/types"
@@ -41,12 +43,13 @@ func (h *headerGovModule) PrepareHeader(header *types.Header) error {
 		header.Vote, _ = h.myVotes[0].ToVoteBytes()
 	}
 
-	// if epoch block & vote exists in the last epoch, put Governance field.
-	if header.Number.Uint64()%h.epoch == 0 {
-		gov := h.getExpectedGovernance(header.Number.Uint64())
-		if len(gov.Items()) > 0 {
-			header.Governance, _ = gov.ToGovBytes()
-		}
+	// if epoch block & vote exists in the last epoch, or the vote ratifies an emergency change, put Governance field.
+	gov, err := h.expectedGovernance(header)
+	if err != nil {
+		return err
+	}
+	if !isEmptyGov(gov) {
+		header.Governance, _ = gov.ToGovBytes()
 	}
 
 	return nil
@@ -59,7 +62,10 @@ func (h *headerGovModule) FinalizeHeader(header *types.Header, state *state.Stat
 // VerifyVote checks the followings:
 // (1) voter must be in valset,
 // (2) integrity of the voter (the voter must be the block proposer),
-// (3) the vote value must be consistent compared to the latest ParamSet.
+// (3) the vote value must be consistent compared to the latest ParamSet,
+// (4) the activation block, if given, must be enabled by the ScheduledVote fork and must not precede the next-epoch activation,
+// nor the activation delay of the parameter,
+// (5) the emergency vote must be enabled and cast by a council member.
 func (h *headerGovModule) VerifyVote(blockNum uint64, vote headergov.VoteData) error {
 	if vote == nil {
 		return ErrNilVote
@@ -69,18 +75,33 @@ func (h *headerGovModule) VerifyVote(blockNum uint64, vote headergov.VoteData) e
 	// TODO: check if Voter is the block proposer.
 
 	// (3)
-	return h.checkConsistency(blockNum, vote)
+	if err := h.checkConsistency(blockNum, vote); err != nil {
+		return err
+	}
+
+	// (4)
+	if err := h.checkActivation(blockNum, vote); err != nil {
+		return err
+	}
+
+	// (5)
+	return h.checkEmergency(blockNum, vote)
 }
 
 // VerifyGov checks the followings:
-// (1) governance must be empty in non-epoch block,
+// (1) governance must be empty in non-epoch block unless the vote of the block ratifies an emergency change,
 // (2) if there are no votes in the previous epoch, governance must be empty,
 // (3) if any vote exists in the previous epoch, governance must not be empty,
-// (4) the json must not contain unknown fields,
-// (5) the parsed json must exactly match the map derived locally from the previous epoch's votes.
+// (4) the json must not contain unknown fields, nor scheduled changes before the ScheduledVote fork,
+// (5) the parsed json must exactly match the map derived locally from the previous epoch's votes and the emergency votes.
 func (h *headerGovModule) VerifyGov(header *types.Header) error {
+	expected, err := h.expectedGovernance(header)
+	if err != nil {
+		return err
+	}
+
 	// (1)
-	if header.Number.Uint64()%h.epoch != 0 {
+	if header.Number.Uint64()%h.epoch != 0 && isEmptyGov(expected) {
 		if len(header.Governance) > 0 {
 			logger.Error("governance is not allowed in non-epoch block", "num", header.Number.Uint64())
 			return ErrGovInNonEpochBlock
@@ -90,9 +111,8 @@ func (h *headerGovModule) VerifyGov(header *types.Header) error {
 	}
 
 	// (2), (3)
-	expected := h.getExpectedGovernance(header.Number.Uint64())
 	if len(header.Governance) == 0 {
-		if len(expected.Items()) != 0 {
+		if !isEmptyGov(expected) {
 			return ErrGovVerification
 		}
 
@@ -106,6 +126,10 @@ func (h *headerGovModule) VerifyGov(header *types.Header) error {
 		logger.Error("DeserializeHeaderGov error", "num", header.Number.Uint64(), "governance", gb, "err", err)
 		return err
 	}
+	if len(actual.Scheduled()) > 0 && !h.isScheduledVoteEnabled(header.Number.Uint64()) {
+		logger.Error("scheduled governance is not enabled", "num", header.Number.Uint64())
+		return ErrScheduledVoteDisabled
+	}
 
 	// (5)
 	if !reflect.DeepEqual(expected, actual) {
@@ -137,33 +161,145 @@ func (h *headerGovModule) checkConsistency(blockNum uint64, vote headergov.VoteD
 		if pa == nil || pa.Empty() {
 			return ErrGovParamNotContract
 		}
-	case gov.Kip71LowerBoundBaseFee:
-		params := h.EffectiveParamSet(blockNum)
-		if vote.Value().(uint64) > params.UpperBoundBaseFee {
-			return ErrLowerBoundBaseFee
+	case gov.GovernancePausedContracts, gov.GovernancePausedTxTypes, gov.GovernancePauseExpiry:
+		if !h.ChainConfig.IsEmergencyPauseForkEnabled(new(big.Int).SetUint64(blockNum)) {
+			return ErrEmergencyPauseDisabled
 		}
-	case gov.Kip71UpperBoundBaseFee:
-		params := h.EffectiveParamSet(blockNum)
-		if vote.Value().(uint64) < params.LowerBoundBaseFee {
-			return ErrUpperBoundBaseFee
+	case gov.GovernanceActivationDelays:
+		if !h.isScheduledVoteEnabled(blockNum) {
+			return ErrScheduledVoteDisabled
 		}
+	case gov.GovernanceMultisigSigners, gov.GovernanceMultisigThreshold:
+		if !h.ChainConfig.IsMultisigGovForkEnabled(new(big.Int).SetUint64(blockNum)) {
+			return ErrMultisigGovDisabled
+		}
+	case gov.BlobTxBaseFee, gov.BlobTxMaxGasPerBlock:
+		if !h.ChainConfig.IsBlobTxForkEnabled(new(big.Int).SetUint64(blockNum)) {
+			return ErrBlobTxDisabled
+		}
+	case gov.ParamName(gov.AddValidator), gov.ParamName(gov.RemoveValidator):
+		return nil
+	}
+
+	return gov.CheckDependencies(h.EffectiveParamSet(blockNum), gov.PartialParamSet{vote.Name(): vote.Value()})
+}
+
+// checkEmergency checks that an emergency vote is cast by a council member after the emergency votes are enabled.
+func (h *headerGovModule) checkEmergency(blockNum uint64, vote headergov.VoteData) error {
+	if !vote.Emergency() {
+		return nil
+	}
+	if !h.isEmergencyEnabled(blockNum) {
+		return ErrEmergencyVoteDisabled
+	}
+
+	council, _, err := h.emergencyCouncil(blockNum)
+	if err != nil {
+		return err
+	}
+	if _, ok := council[vote.Voter()]; !ok {
+		return ErrVotePermissionDenied
+	}
+	return nil
+}
+
+// checkActivation checks that a scheduled vote is cast after the ScheduledVote fork and that it activates no earlier than
+// the block where an unscheduled vote would take effect, i.e. the start of the epoch after the next.
+// After the fork, it also checks that the change takes effect no earlier than the activation delay of the parameter
+// after the epoch block ratifying it. An emergency vote cannot change a parameter with a delay.
+func (h *headerGovModule) checkActivation(blockNum uint64, vote headergov.VoteData) error {
+	activation := vote.ActivationBlock()
+	if activation > 0 && !h.isScheduledVoteEnabled(blockNum) {
+		return ErrScheduledVoteDisabled
+	}
+	if activation > 0 && activation < calcEpochStartBlock(calcEpochIdx(blockNum, h.epoch)+2, h.epoch) {
+		return ErrActivationTooEarly
+	}
+	if !h.isScheduledVoteEnabled(blockNum) {
+		return nil
 	}
 
+	delays, err := gov.ParseActivationDelays(h.EffectiveParamSet(blockNum).ActivationDelays)
+	if err != nil {
+		return err
+	}
+	delay := delays[vote.Name()]
+	if delay == 0 {
+		return nil
+	}
+	if vote.Emergency() {
+		return ErrActivationDelayed
+	}
+
+	ratified := calcEpochStartBlock(calcEpochIdx(blockNum, h.epoch)+1, h.epoch)
+	if activation == 0 {
+		activation = h.firstEffectiveBlock(ratified)
+	}
+	if activation-ratified < delay {
+		return ErrActivationDelayed
+	}
 	return nil
 }
 
+// expectedGovernance returns the governance which the header must have: the votes of the previous epoch
+// ratified at an epoch block, and the emergency change ratified by the vote of the header.
+func (h *headerGovModule) expectedGovernance(header *types.Header) (headergov.GovData, error) {
+	num := header.Number.Uint64()
+
+	var vote headergov.VoteData
+	if len(header.Vote) > 0 {
+		vote, _ = headergov.VoteBytes(header.Vote).ToVoteData()
+	}
+	emergency, err := h.emergencyChanges(num, vote)
+	if err != nil {
+		return nil, err
+	}
+
+	if num%h.epoch != 0 {
+		return headergov.NewEmergencyGovData(nil, nil, emergency), nil
+	}
+	g, err := h.getExpectedGovernance(num)
+	if err != nil {
+		return nil, err
+	}
+	return headergov.NewEmergencyGovData(g.Items(), g.Scheduled(), emergency), nil
+}
+
 // The blockNum's epoch index must be greater than 0. That is, it must be blockNum >= epoch.
-func (h *headerGovModule) getExpectedGovernance(blockNum uint64) headergov.GovData {
+func (h *headerGovModule) getExpectedGovernance(blockNum uint64) (headergov.GovData, error) {
 	prevEpochIdx := calcEpochIdx(blockNum, h.epoch) - 1
-	prevEpochVotes := h.getVotesInEpoch(prevEpochIdx)
+	prevEpochVotes, err := h.ratifiedVotes(blockNum, h.getVotesInEpoch(prevEpochIdx))
+	if err != nil {
+		return nil, err
+	}
 	govs := make(gov.PartialParamSet)
+	scheduled := make(map[uint64]gov.PartialParamSet)
+
+	// Votes are ratified in the block order so that the last vote of a param wins.
+	voteBlockNums := make([]uint64, 0, len(prevEpochVotes))
+	for num := range prevEpochVotes {
+		voteBlockNums = append(voteBlockNums, num)
+	}
+	slices.Sort(voteBlockNums)
 
-	for _, vote := range prevEpochVotes {
-		govs.Add(string(vote.Name()), vote.Value())
+	for _, num := range voteBlockNums {
+		vote := prevEpochVotes[num]
+		if activation := vote.ActivationBlock(); activation > 0 {
+			if _, ok := scheduled[activation]; !ok {
+				scheduled[activation] = make(gov.PartialParamSet)
+			}
+			scheduled[activation].Add(string(vote.Name()), vote.Value())
+		} else {
+			govs.Add(string(vote.Name()), vote.Value())
+		}
 	}
 
 	// assert(len(headergov.NewGovData(govs).Items()) == len(govs))
-	return headergov.NewGovData(govs)
+	return headergov.NewScheduledGovData(govs, scheduled), nil
+}
+
+func isEmptyGov(g headergov.GovData) bool {
+	return len(g.Items()) == 0 && len(g.Scheduled()) == 0 && len(g.Emergency()) == 0
 }
 
 func (h *headerGovModule) getVotesInEpoch(epochIdx uint64) map[uint64]headergov.VoteData {
@@ -77,8 +77,8 @@ func TestVerifyVote(t *testing.T) {
 		{desc: "valid upper", vote: headergov.NewVoteData(common.Address{}, string(gov.Kip71UpperBoundBaseFee), uint64(1e18)), expectedError: nil},
 		{desc: "invalid govparam", vote: headergov.NewVoteData(common.Address{}, string(gov.GovernanceGovParamContract), common.Address{}), expectedError: ErrGovParamNotAccount},
 		{desc: "invalid govparam", vote: headergov.NewVoteData(common.Address{}, string(gov.GovernanceGovParamContract), eoa), expectedError: ErrGovParamNotContract},
-		{desc: "invalid lower", vote: headergov.NewVoteData(common.Address{}, string(gov.Kip71LowerBoundBaseFee), uint64(1e18)), expectedError: ErrLowerBoundBaseFee},
-		{desc: "invalid upper", vote: headergov.NewVoteData(common.Address{}, string(gov.Kip71UpperBoundBaseFee), uint64(1)), expectedError: ErrUpperBoundBaseFee},
+		{desc: "invalid lower", vote: headergov.NewVoteData(common.Address{}, string(gov.Kip71LowerBoundBaseFee), uint64(1e18)), expectedError: &gov.ParamError{Name: gov.Kip71LowerBoundBaseFee, Value: uint64(1e18), Rule: gov.RuleDependency, Reason: "kip71.lowerboundbasefee <= kip71.upperboundbasefee"}},
+		{desc: "invalid upper", vote: headergov.NewVoteData(common.Address{}, string(gov.Kip71UpperBoundBaseFee), uint64(1)), expectedError: &gov.ParamError{Name: gov.Kip71UpperBoundBaseFee, Value: uint64(1), Rule: gov.RuleDependency, Reason: "kip71.lowerboundbasefee <= kip71.upperboundbasefee"}},
 	}
 
 	for _, tc := range tcs {
@@ -131,8 +131,8 @@ func TestGetExpectedGovernance(t *testing.T) {
 	h.HandleGov(1000, g1)
 	h.HandleGov(2000, g2)
 
-	assert.Equal(t, g1, h.getExpectedGovernance(1000))
-	assert.Equal(t, g2, h.getExpectedGovernance(2000))
+	assert.Equal(t, g1, expectedGovAt(t, h, 1000))
+	assert.Equal(t, g2, expectedGovAt(t, h, 2000))
 }
 
 func TestPrepareHeader(t *testing.T) {
@@ -162,3 +162,85 @@ func TestPrepareHeader(t *testing.T) {
 	ps := h.EffectiveParamSet(2001)
 	assert.Equal(t, ps.UnitPrice, uint64(100))
 }
+
+func TestVerifyVoteActivationDelay(t *testing.T) {
+	var (
+		paramName = string(gov.GovernanceUnitPrice)
+		config    = &params.ChainConfig{
+			Istanbul: &params.IstanbulConfig{
+				Epoch: 1000,
+			},
+			ScheduledVoteCompatibleBlock: big.NewInt(0),
+		}
+		h = newHeaderGovModule(t, config)
+	)
+	h.AddGov(0, headergov.NewGovData(gov.PartialParamSet{
+		gov.GovernanceActivationDelays: "governance.unitprice:3000,kip71.lowerboundbasefee:1",
+	}))
+
+	// The vote cast at 500 is ratified at 1000, so the change must not take effect before 4000.
+	assert.ErrorIs(t, h.VerifyVote(500, headergov.NewVoteData(common.Address{1}, paramName, uint64(100))), ErrActivationDelayed)
+	assert.ErrorIs(t, h.VerifyVote(500, headergov.NewScheduledVoteData(common.Address{1}, paramName, uint64(100), 3999)), ErrActivationDelayed)
+	assert.NoError(t, h.VerifyVote(500, headergov.NewScheduledVoteData(common.Address{1}, paramName, uint64(100), 4000)))
+
+	// The params without a delay are not affected.
+	assert.NoError(t, h.VerifyVote(500, headergov.NewVoteData(common.Address{1}, string(gov.Kip71GasTarget), uint64(100))))
+
+	// An emergency vote cannot bypass the delay.
+	vote := headergov.NewEmergencyVoteData(common.Address{1}, string(gov.Kip71LowerBoundBaseFee), uint64(100))
+	assert.ErrorIs(t, h.VerifyVote(500, vote), ErrActivationDelayed)
+}
+
+func TestGetExpectedGovernanceScheduled(t *testing.T) {
+	var (
+		paramName = string(gov.GovernanceUnitPrice)
+		config    = &params.ChainConfig{
+			Istanbul: &params.IstanbulConfig{
+				Epoch: 1000,
+			},
+			ScheduledVoteCompatibleBlock: big.NewInt(0),
+		}
+		h = newHeaderGovModule(t, config)
+	)
+
+	// A scheduled vote must not activate before the next-epoch activation.
+	assert.ErrorIs(t, h.VerifyVote(500, headergov.NewScheduledVoteData(common.Address{1}, paramName, uint64(100), 1999)), ErrActivationTooEarly)
+	assert.NoError(t, h.VerifyVote(500, headergov.NewScheduledVoteData(common.Address{1}, paramName, uint64(100), 2000)))
+
+	h.HandleVote(500, headergov.NewScheduledVoteData(common.Address{1}, paramName, uint64(100), 2500))
+	h.HandleVote(600, headergov.NewVoteData(common.Address{1}, paramName, uint64(200)))
+
+	expected := headergov.NewScheduledGovData(
+		gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(200)},
+		map[uint64]gov.PartialParamSet{2500: {gov.GovernanceUnitPrice: uint64(100)}},
+	)
+	assert.Equal(t, expected, expectedGovAt(t, h, 1000))
+}
+
+func TestScheduledVoteFork(t *testing.T) {
+	var (
+		paramName = string(gov.GovernanceUnitPrice)
+		config    = &params.ChainConfig{
+			Istanbul: &params.IstanbulConfig{
+				Epoch: 1000,
+			},
+			ScheduledVoteCompatibleBlock: big.NewInt(2000),
+		}
+		h    = newHeaderGovModule(t, config)
+		vote = headergov.NewScheduledVoteData(common.Address{1}, paramName, uint64(100), 5000)
+	)
+
+	// A vote with an activation block is rejected before the fork.
+	assert.ErrorIs(t, h.VerifyVote(1500, vote), ErrScheduledVoteDisabled)
+	assert.NoError(t, h.VerifyVote(2500, vote))
+
+	// So is a vote for the activation delays.
+	delays := headergov.NewVoteData(common.Address{1}, string(gov.GovernanceActivationDelays), "governance.unitprice:3000")
+	assert.ErrorIs(t, h.VerifyVote(1500, delays), ErrScheduledVoteDisabled)
+	assert.NoError(t, h.VerifyVote(2500, delays))
+
+	// So is a scheduled change in header.Governance.
+	gb, err := headergov.NewScheduledGovData(nil, map[uint64]gov.PartialParamSet{5000: {gov.GovernanceUnitPrice: uint64(100)}}).ToGovBytes()
+	assert.NoError(t, err)
+	assert.ErrorIs(t, h.VerifyGov(&types.Header{Number: big.NewInt(1000), Governance: gb}), ErrScheduledVoteDisabled)
+}
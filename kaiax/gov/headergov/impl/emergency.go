@@ -0,0 +1,92 @@
+package impl
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/gov"
+	"github.com/kaiachain/kaia/kaiax/gov/headergov"
+)
+
+// isEmergencyEnabled returns true if the emergency votes are accepted at the given block.
+// The council is taken from the staking info, so the StakingModule is required.
+func (h *headerGovModule) isEmergencyEnabled(blockNum uint64) bool {
+	return h.StakingModule != nil && h.ChainConfig.IsEmergencyPauseForkEnabled(new(big.Int).SetUint64(blockNum))
+}
+
+// emergencyCouncil returns the voter index of each council member and the size of the council at the given block.
+// The council consists of the consensus nodes in the staking info. A node that registered multiple node IDs is counted once.
+func (h *headerGovModule) emergencyCouncil(blockNum uint64) (map[common.Address]int, int, error) {
+	nodeIdx, stakes, _, err := h.stakeWeights(blockNum)
+	if err != nil {
+		return nil, 0, err
+	}
+	return nodeIdx, len(stakes), nil
+}
+
+// emergencyChanges returns the params ratified by the emergency vote cast at the given block, which take effect
+// from the next block. A change is ratified at the block whose vote makes more than 2/3 of the council agree on it.
+// Only the latest emergency vote of each member in the current epoch counts.
+func (h *headerGovModule) emergencyChanges(blockNum uint64, vote headergov.VoteData) (gov.PartialParamSet, error) {
+	if vote == nil || !vote.Emergency() || !h.isEmergencyEnabled(blockNum) {
+		return nil, nil
+	}
+
+	council, size, err := h.emergencyCouncil(blockNum)
+	if err != nil {
+		logger.Error("Failed to get the council for the emergency vote", "num", blockNum, "err", err)
+		return nil, err
+	}
+
+	votes := emergencyVotes(h.getVotesInEpoch(calcEpochIdx(blockNum, h.epoch)))
+	delete(votes, blockNum) // the vote may have been handled already if the header is verified again
+	before := emergencySupport(votes, council, vote)
+	votes[blockNum] = vote
+	after := emergencySupport(votes, council, vote)
+
+	if isSupermajority(before, size) || !isSupermajority(after, size) {
+		return nil, nil
+	}
+	return gov.PartialParamSet{vote.Name(): vote.Value()}, nil
+}
+
+// emergencySupport returns the number of the council members whose latest emergency vote agrees with the given vote.
+func emergencySupport(votes map[uint64]headergov.VoteData, council map[common.Address]int, vote headergov.VoteData) int {
+	target := choice{string(vote.Name()), 0, fmt.Sprint(vote.Value())}
+
+	support := 0
+	for _, b := range latestBallots(votes, council) {
+		if b.choice == target {
+			support++
+		}
+	}
+	return support
+}
+
+// isSupermajority returns true if the support is more than 2/3 of the council.
+func isSupermajority(support, size int) bool {
+	return size > 0 && support*3 > size*2
+}
+
+// emergencyVotes returns the emergency votes among the given votes.
+func emergencyVotes(votes map[uint64]headergov.VoteData) map[uint64]headergov.VoteData {
+	ret := make(map[uint64]headergov.VoteData)
+	for num, vote := range votes {
+		if vote.Emergency() {
+			ret[num] = vote
+		}
+	}
+	return ret
+}
+
+// regularVotes returns the votes to be tallied at the epoch block, i.e. those other than the emergency votes.
+func regularVotes(votes map[uint64]headergov.VoteData) map[uint64]headergov.VoteData {
+	ret := make(map[uint64]headergov.VoteData)
+	for num, vote := range votes {
+		if !vote.Emergency() {
+			ret[num] = vote
+		}
+	}
+	return ret
+}
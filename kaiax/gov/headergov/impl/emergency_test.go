@@ -0,0 +1,115 @@
+package impl
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/gov"
+	"github.com/kaiachain/kaia/kaiax/gov/headergov"
+	"github.com/kaiachain/kaia/kaiax/staking"
+	staking_mock "github.com/kaiachain/kaia/kaiax/staking/mock"
+	"github.com/kaiachain/kaia/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmergencyVote(t *testing.T) {
+	var (
+		paramName = string(gov.Kip71LowerBoundBaseFee)
+		n1        = common.Address{1}
+		n2        = common.Address{2}
+		n3        = common.Address{3}
+		n4        = common.Address{4}
+		outsider  = common.Address{5}
+	)
+
+	newModule := func(t *testing.T, enabled bool) *headerGovModule {
+		config := &params.ChainConfig{Istanbul: &params.IstanbulConfig{Epoch: 1000}, KoreCompatibleBlock: big.NewInt(0)}
+		if enabled {
+			config.EmergencyPauseCompatibleBlock = big.NewInt(0)
+		}
+		h := newHeaderGovModule(t, config)
+		mStaking := staking_mock.NewMockStakingModule(gomock.NewController(t))
+		mStaking.EXPECT().GetStakingInfo(gomock.Any()).Return(&staking.StakingInfo{
+			NodeIds:          []common.Address{n1, n2, n3, n4},
+			StakingContracts: []common.Address{{0x11}, {0x12}, {0x13}, {0x14}},
+			RewardAddrs:      []common.Address{{0x21}, {0x22}, {0x23}, {0x24}},
+			StakingAmounts:   []uint64{5_000_000, 5_000_000, 5_000_000, 5_000_000},
+		}, nil).AnyTimes()
+		h.StakingModule = mStaking
+		return h
+	}
+	newHeader := func(num uint64, vote headergov.VoteData) *types.Header {
+		vb, err := vote.ToVoteBytes()
+		require.NoError(t, err)
+		return &types.Header{Number: new(big.Int).SetUint64(num), Vote: vb}
+	}
+	vote := func(voter common.Address) headergov.VoteData {
+		return headergov.NewEmergencyVoteData(voter, paramName, uint64(50e9))
+	}
+
+	t.Run("ratified", func(t *testing.T) {
+		h := newModule(t, true)
+		assert.NoError(t, h.VerifyVote(100, vote(n1)))
+		assert.ErrorIs(t, h.VerifyVote(100, vote(outsider)), ErrVotePermissionDenied)
+
+		h.HandleVote(100, vote(n1))
+		h.HandleVote(150, vote(outsider)) // not a council member
+		h.HandleVote(200, vote(n2))
+
+		// The third vote out of four council members ratifies the change.
+		header := newHeader(200, vote(n2))
+		assert.True(t, isEmptyGov(expectedGovOf(t, h, header)))
+		header = newHeader(300, vote(n3))
+		expected := expectedGovOf(t, h, header)
+		assert.Equal(t, gov.PartialParamSet{gov.Kip71LowerBoundBaseFee: uint64(50e9)}, expected.Emergency())
+
+		// The header must carry the emergency change.
+		assert.ErrorIs(t, h.VerifyGov(header), ErrGovVerification)
+		header.Governance, _ = expected.ToGovBytes()
+		assert.NoError(t, h.VerifyGov(header))
+		require.NoError(t, h.HandleVote(300, vote(n3)))
+		require.NoError(t, h.HandleGov(300, expected))
+
+		// The change takes effect from the next block.
+		assert.Equal(t, uint64(25e9), h.EffectiveParamSet(300).LowerBoundBaseFee)
+		assert.Equal(t, uint64(50e9), h.EffectiveParamSet(301).LowerBoundBaseFee)
+		assert.Contains(t, h.ParamChangeBlocks(0, 1000), uint64(301))
+
+		// Further votes for the same value do not ratify it again.
+		header = newHeader(400, vote(n4))
+		assert.True(t, isEmptyGov(expectedGovOf(t, h, header)))
+		header.Governance, _ = expected.ToGovBytes()
+		assert.ErrorIs(t, h.VerifyGov(header), ErrGovInNonEpochBlock)
+
+		// The emergency votes are not tallied at the epoch block.
+		assert.True(t, isEmptyGov(expectedGovAt(t, h, 1000)))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		h := newModule(t, false)
+		assert.ErrorIs(t, h.VerifyVote(100, vote(n1)), ErrEmergencyVoteDisabled)
+
+		h.HandleVote(100, vote(n1))
+		h.HandleVote(200, vote(n2))
+		assert.True(t, isEmptyGov(expectedGovOf(t, h, newHeader(300, vote(n3)))))
+	})
+
+	t.Run("api", func(t *testing.T) {
+		api := NewHeaderGovAPI(newModule(t, true))
+		api.h.nodeAddress = n1
+
+		_, err := api.EmergencyVote(string(gov.GovernanceUnitPrice), uint64(50e9))
+		assert.ErrorIs(t, err, headergov.ErrNotEmergency)
+		_, err = api.EmergencyVote(paramName, uint64(50e9))
+		assert.NoError(t, err)
+		assert.Equal(t, []headergov.VoteData{vote(n1)}, api.h.myVotes)
+
+		api.h.nodeAddress = outsider
+		_, err = api.EmergencyVote(paramName, uint64(50e9))
+		assert.ErrorIs(t, err, ErrVotePermissionDenied)
+	})
+}
@@ -6,6 +6,7 @@ var (
 	ErrZeroEpoch                      = errors.New("epoch cannot be zero")
 	ErrInitNil                        = errors.New("cannot init headergov module because of nil")
 	ErrLowestVoteScannedBlockNotFound = errors.New("lowest vote scanned block not found")
+	ErrVoteScanInProgress             = errors.New("votes of old epochs are still being scanned")
 
 	ErrVotePermissionDenied = errors.New("you don't have the right to vote")
 	ErrInvalidKeyValue      = errors.New("your vote couldn't be placed. Please check your vote's key and value")
@@ -16,6 +17,12 @@ var (
 
 	ErrGovParamNotAccount  = errors.New("govparamcontract is not an account")
 	ErrGovParamNotContract = errors.New("govparamcontract is not an contract account")
-	ErrLowerBoundBaseFee   = errors.New("lowerboundbasefee is greater than upperboundbasefee")
-	ErrUpperBoundBaseFee   = errors.New("upperboundbasefee is less than lowerboundbasefee")
+	ErrActivationTooEarly  = errors.New("activation block must not precede the next-epoch activation")
+	ErrActivationDelayed   = errors.New("change must not take effect before the activation delay of the param")
+
+	ErrEmergencyPauseDisabled = errors.New("emergency pause is not enabled in the chain config")
+	ErrBlobTxDisabled         = errors.New("blob tx is not enabled in the chain config")
+	ErrMultisigGovDisabled    = errors.New("multisig governance is not enabled in the chain config")
+	ErrEmergencyVoteDisabled  = errors.New("emergency vote is not enabled")
+	ErrScheduledVoteDisabled  = errors.New("scheduled vote is not enabled in the chain config")
 )
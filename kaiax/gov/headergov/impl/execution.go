@@ -38,6 +38,10 @@ func (h *headerGovModule) PostInsertBlock(b *types.Block) error {
 		}
 	}
 
+	// The votes in the epoch of the block are tallied at the next epoch block.
+	h.mu.RLock()
+	pendingVotesGauge.Update(int64(len(h.groupedVotes[calcEpochIdx(b.NumberU64(), h.epoch)])))
+	h.mu.RUnlock()
 	return nil
 }
 
@@ -52,7 +56,9 @@ func (h *headerGovModule) HandleVote(blockNum uint64, vote headergov.VoteData) e
 	for i, myvote := range h.myVotes {
 		if bytes.Equal(myvote.Voter().Bytes(), vote.Voter().Bytes()) &&
 			myvote.Name() == vote.Name() &&
-			reflect.DeepEqual(myvote.Value(), vote.Value()) {
+			reflect.DeepEqual(myvote.Value(), vote.Value()) &&
+			myvote.ActivationBlock() == vote.ActivationBlock() &&
+			myvote.Emergency() == vote.Emergency() {
 			h.PopMyVotes(i)
 			break
 		}
@@ -62,6 +68,9 @@ func (h *headerGovModule) HandleVote(blockNum uint64, vote headergov.VoteData) e
 }
 
 func (h *headerGovModule) HandleGov(blockNum uint64, gov headergov.GovData) error {
+	if len(gov.Emergency()) > 0 {
+		logger.Warn("Emergency change ratified by the council", "num", blockNum, "params", gov.Emergency(), "effective", blockNum+1)
+	}
 	h.AddGov(blockNum, gov)
 
 	data := h.GovBlockNums()
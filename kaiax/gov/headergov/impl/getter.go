@@ -2,9 +2,11 @@ package impl
 
 import (
 	"slices"
+	"sort"
 
 	"github.com/kaiachain/kaia/common"
 	"github.com/kaiachain/kaia/kaiax/gov"
+	"github.com/kaiachain/kaia/kaiax/gov/headergov"
 )
 
 func (h *headerGovModule) EffectiveParamSet(blockNum uint64) gov.ParamSet {
@@ -12,27 +14,161 @@ func (h *headerGovModule) EffectiveParamSet(blockNum uint64) gov.ParamSet {
 	defer h.mu.RUnlock()
 
 	prevEpochStart := PrevEpochStart(blockNum, h.epoch, h.isKoreHF(blockNum))
-	gh := h.history
-	gp, err := gh.Search(prevEpochStart)
-	if err != nil {
-		return *gov.GetDefaultGovernanceParamSet()
+	if !h.hasScheduled() {
+		gh := h.history
+		gp, err := gh.Search(prevEpochStart)
+		if err != nil {
+			return *gov.GetDefaultGovernanceParamSet()
+		}
+		return gp
+	}
+
+	// The history does not know the scheduled changes, so assemble the param set from the changes.
+	gp := *gov.GetDefaultGovernanceParamSet()
+	for _, change := range h.effectiveChanges(blockNum) {
+		if err := gp.SetFromMap(change.items); err != nil {
+			continue
+		}
 	}
 	return gp
 }
 
 func (h *headerGovModule) EffectiveParamsPartial(blockNum uint64) gov.PartialParamSet {
-	prevEpochStart := PrevEpochStart(blockNum, h.epoch, h.isKoreHF(blockNum))
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
 	ret := make(gov.PartialParamSet)
 
-	// merge all governance sets before num's prevEpochStart.
-	for _, num := range h.GovBlockNums() {
-		if num <= prevEpochStart {
-			for name, value := range h.governances[num].Items() {
-				ret[name] = value
+	// merge all governance sets effective at num in the order of activation.
+	for _, change := range h.effectiveChanges(blockNum) {
+		for name, value := range change.items {
+			ret[name] = value
+		}
+	}
+
+	return ret
+}
+
+// govChange is a set of ratified params and the block where they take effect.
+type govChange struct {
+	activation uint64
+	ratified   uint64
+	items      gov.PartialParamSet
+}
+
+// effectiveChanges returns the changes effective at blockNum in the order they take effect.
+// A regular change ratified at an epoch block g takes effect at g+epoch, while a scheduled change takes
+// effect at its activation block and an emergency change ratified at a block n takes effect at n+1.
+// If two changes take effect at the same block, the later ratification wins.
+// It should be called only when the caller holds the lock.
+func (h *headerGovModule) effectiveChanges(blockNum uint64) []govChange {
+	prevEpochStart := PrevEpochStart(blockNum, h.epoch, h.isKoreHF(blockNum))
+
+	changes := make([]govChange, 0, len(h.governances))
+	for num, g := range h.governances {
+		if num > prevEpochStart {
+			continue
+		}
+		activation := num + h.epoch
+		if num == 0 {
+			activation = 0 // the genesis governance precedes any emergency change
+		}
+		changes = append(changes, govChange{activation: activation, ratified: num, items: g.Items()})
+		for activation, items := range g.Scheduled() {
+			if activation <= blockNum {
+				changes = append(changes, govChange{activation: activation, ratified: num, items: items})
 			}
 		}
 	}
+	// The emergency changes take effect from the block after the ratification regardless of the epoch.
+	for num, g := range h.governances {
+		if len(g.Emergency()) > 0 && num < blockNum {
+			changes = append(changes, govChange{activation: num + 1, ratified: num, items: g.Emergency()})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].activation != changes[j].activation {
+			return changes[i].activation < changes[j].activation
+		}
+		return changes[i].ratified < changes[j].ratified
+	})
+	return changes
+}
+
+// hasScheduled returns true if any scheduled or emergency change has been ratified.
+// It should be called only when the caller holds the lock.
+func (h *headerGovModule) hasScheduled() bool {
+	for _, g := range h.governances {
+		if len(g.Scheduled()) > 0 || len(g.Emergency()) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ParamChangeBlocks returns the blocks in (from, to] at which a ratified change takes effect, in ascending order.
+func (h *headerGovModule) ParamChangeBlocks(from, to uint64) []uint64 {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	blocks := make(map[uint64]bool)
+	add := func(num uint64) {
+		if from < num && num <= to {
+			blocks[num] = true
+		}
+	}
+	for num, g := range h.governances {
+		if num%h.epoch == 0 {
+			add(h.firstEffectiveBlock(num))
+		}
+		for activation := range g.Scheduled() {
+			add(activation)
+		}
+		if len(g.Emergency()) > 0 {
+			add(num + 1)
+		}
+	}
 
+	ret := make([]uint64, 0, len(blocks))
+	for num := range blocks {
+		ret = append(ret, num)
+	}
+	slices.Sort(ret)
+	return ret
+}
+
+// firstEffectiveBlock returns the first block where the governance ratified at num is effective.
+func (h *headerGovModule) firstEffectiveBlock(num uint64) uint64 {
+	if num == 0 {
+		return 0
+	}
+	b := num + h.epoch
+	if PrevEpochStart(b, h.epoch, h.isKoreHF(b)) < num {
+		b++ // before Kore, a change takes effect one block after the epoch start
+	}
+	return b
+}
+
+// PendingScheduled returns the ratified scheduled changes which have not taken effect at blockNum yet.
+func (h *headerGovModule) PendingScheduled(blockNum uint64) headergov.ScheduledParams {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	ret := make(headergov.ScheduledParams)
+	for _, num := range sortedKeys(h.governances) {
+		for activation, items := range h.governances[num].Scheduled() {
+			if activation <= blockNum {
+				continue
+			}
+			if _, ok := ret[activation]; !ok {
+				ret[activation] = make(gov.PartialParamSet)
+			}
+			for name, value := range items {
+				ret[activation][name] = value
+			}
+		}
+	}
 	return ret
 }
 
@@ -60,9 +196,13 @@ func (h *headerGovModule) GovBlockNums() []uint64 {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
+	return sortedKeys(h.governances)
+}
+
+func sortedKeys(govs headergov.GovDataMap) []uint64 {
 	// TODO: replace with maps.Keys(h.Govs())
 	blockNums := make([]uint64, 0)
-	for num := range h.governances {
+	for num := range govs {
 		blockNums = append(blockNums, num)
 	}
 
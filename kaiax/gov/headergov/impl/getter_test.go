@@ -138,3 +138,45 @@ func TestPrevEpochStart(t *testing.T) {
 		})
 	}
 }
+
+func TestEffectiveParamsScheduled(t *testing.T) {
+	var (
+		epoch = uint64(1000)
+		h     = newHeaderGovModule(t, &params.ChainConfig{
+			KoreCompatibleBlock: big.NewInt(0),
+			Istanbul:            &params.IstanbulConfig{Epoch: epoch},
+		})
+	)
+
+	// Ratified at 1000: unitprice=1 from 2000, unitprice=5 from 3500.
+	// Ratified at 3000: unitprice=2 from 4000, which supersedes the scheduled change.
+	h.AddGov(1000, headergov.NewScheduledGovData(
+		gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(1)},
+		map[uint64]gov.PartialParamSet{3500: {gov.GovernanceUnitPrice: uint64(5)}},
+	))
+	h.AddGov(3000, headergov.NewGovData(gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(2)}))
+
+	testCases := []struct {
+		blockNum      uint64
+		expectedPrice uint64
+	}{
+		{1999, 250e9},
+		{2000, 1},
+		{3499, 1},
+		{3500, 5},
+		{3999, 5},
+		{4000, 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Block %d", tc.blockNum), func(t *testing.T) {
+			assert.Equal(t, tc.expectedPrice, h.EffectiveParamSet(tc.blockNum).UnitPrice)
+			if tc.expectedPrice != 250e9 {
+				assert.Equal(t, tc.expectedPrice, h.EffectiveParamsPartial(tc.blockNum)[gov.GovernanceUnitPrice])
+			}
+		})
+	}
+
+	assert.Len(t, h.PendingScheduled(3000), 1)
+	assert.Len(t, h.PendingScheduled(3500), 0)
+}
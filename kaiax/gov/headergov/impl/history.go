@@ -0,0 +1,126 @@
+package impl
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/kaiachain/kaia/kaiax/gov"
+	"github.com/kaiachain/kaia/kaiax/gov/headergov"
+)
+
+// ExportHistory returns the votes and governances in the blocks up to head along with
+// the raw header fields. It fails until the votes of all epochs have been scanned.
+func (h *headerGovModule) ExportHistory(head uint64) ([]gov.VoteRecord, []gov.GovRecord, error) {
+	if lowest := ReadLowestVoteScannedBlockNum(h.ChainKv); lowest == nil || *lowest != 0 {
+		return nil, nil, ErrVoteScanInProgress
+	}
+
+	votes := make([]gov.VoteRecord, 0)
+	for _, num := range h.VoteBlockNums() {
+		if num > head {
+			break
+		}
+		header := h.Chain.GetHeaderByNumber(num)
+		if header == nil {
+			return nil, nil, fmt.Errorf("%w: header %d", gov.ErrHistoryMismatch, num)
+		}
+		vote, err := headergov.VoteBytes(header.Vote).ToVoteData()
+		if err != nil {
+			return nil, nil, err
+		}
+		votes = append(votes, gov.VoteRecord{
+			Block: num,
+			Voter: vote.Voter(),
+			Name:  vote.Name(),
+			Value: gov.HistoryValue(vote.Value()),
+			Raw:   header.Vote,
+		})
+	}
+
+	govs := make([]gov.GovRecord, 0)
+	for _, num := range h.GovBlockNums() {
+		if num > head {
+			break
+		}
+		header := h.Chain.GetHeaderByNumber(num)
+		if header == nil {
+			return nil, nil, fmt.Errorf("%w: header %d", gov.ErrHistoryMismatch, num)
+		}
+		if len(header.Governance) == 0 {
+			continue // genesis without governance
+		}
+		data, err := headergov.GovBytes(header.Governance).ToGovData()
+		if err != nil {
+			return nil, nil, err
+		}
+		govs = append(govs, gov.GovRecord{
+			Block:  num,
+			Params: gov.HistoryParams(data.Items()),
+			Raw:    header.Governance,
+		})
+	}
+	return votes, govs, nil
+}
+
+// ImportHistory indexes the given votes and governances as if they had been scanned from
+// the headers. Every record of a local block must match the header; nothing is written
+// otherwise. Records beyond the local head are skipped and indexed when the blocks arrive.
+// If the records cover every epoch that has not been scanned yet, the vote scan is completed.
+func (h *headerGovModule) ImportHistory(head uint64, votes []gov.VoteRecord, govs []gov.GovRecord) (imported, skipped int, err error) {
+	localHead := h.Chain.CurrentBlock().NumberU64()
+
+	parsedVotes := make(map[uint64]headergov.VoteData)
+	for _, record := range votes {
+		if record.Block > localHead {
+			skipped++
+			continue
+		}
+		header := h.Chain.GetHeaderByNumber(record.Block)
+		if header == nil || !bytes.Equal(header.Vote, record.Raw) {
+			return 0, 0, fmt.Errorf("%w: vote at block %d", gov.ErrHistoryMismatch, record.Block)
+		}
+		vote, err := headergov.VoteBytes(record.Raw).ToVoteData()
+		if err != nil {
+			return 0, 0, err
+		}
+		if _, ok := gov.Params[vote.Name()]; ok {
+			parsedVotes[record.Block] = vote
+		}
+	}
+
+	parsedGovs := make(map[uint64]headergov.GovData)
+	for _, record := range govs {
+		if record.Block > localHead {
+			skipped++
+			continue
+		}
+		header := h.Chain.GetHeaderByNumber(record.Block)
+		if header == nil || !bytes.Equal(header.Governance, record.Raw) {
+			return 0, 0, fmt.Errorf("%w: governance at block %d", gov.ErrHistoryMismatch, record.Block)
+		}
+		data, err := headergov.GovBytes(record.Raw).ToGovData()
+		if err != nil {
+			return 0, 0, err
+		}
+		parsedGovs[record.Block] = data
+	}
+
+	for num, vote := range parsedVotes {
+		h.AddVote(calcEpochIdx(num, h.epoch), num, vote)
+		InsertVoteDataBlockNum(h.ChainKv, num)
+	}
+	for num, data := range parsedGovs {
+		h.AddGov(num, data)
+	}
+	WriteGovDataBlockNums(h.ChainKv, h.GovBlockNums())
+
+	// The records cover the epochs up to that of min(head, localHead). Together with the
+	// epochs scanned locally, this is the whole history if no epoch is left in between.
+	covered := calcEpochIdx(min(head, localHead), h.epoch)
+	if lowest := ReadLowestVoteScannedBlockNum(h.ChainKv); lowest != nil && *lowest <= covered+1 {
+		WriteLowestVoteScannedBlockNum(h.ChainKv, 0)
+	}
+
+	logger.Info("Imported governance history", "votes", len(parsedVotes), "governances", len(parsedGovs), "skipped", skipped)
+	return len(parsedVotes) + len(parsedGovs), skipped, nil
+}
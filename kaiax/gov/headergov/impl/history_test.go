@@ -0,0 +1,100 @@
+package impl
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/gov"
+	"github.com/kaiachain/kaia/kaiax/gov/headergov"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/kaiachain/kaia/work/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newHistoryTestChain returns 26 headers with a vote at block 3 and its governance at block 10.
+func newHistoryTestChain(t *testing.T) map[uint64]*types.Header {
+	genesisGov, _ := headergov.NewGovData(gov.GetDefaultGovernanceParamSet().ToMap()).ToGovBytes()
+	vote, _ := headergov.NewVoteData(common.HexToAddress("0x1"), string(gov.GovernanceUnitPrice), uint64(123)).ToVoteBytes()
+	govBytes, _ := headergov.NewGovData(gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(123)}).ToGovBytes()
+
+	headers := make(map[uint64]*types.Header)
+	for i := uint64(0); i <= 25; i++ {
+		headers[i] = &types.Header{Number: new(big.Int).SetUint64(i)}
+	}
+	headers[0].Governance = genesisGov
+	headers[3].Vote = vote
+	headers[10].Governance = govBytes
+	return headers
+}
+
+func newHistoryTestModule(t *testing.T, headers map[uint64]*types.Header) (*headerGovModule, database.Database) {
+	chain := mocks.NewMockBlockChain(gomock.NewController(t))
+	chain.EXPECT().GetHeaderByNumber(gomock.Any()).DoAndReturn(func(num uint64) *types.Header {
+		return headers[num]
+	}).AnyTimes()
+	chain.EXPECT().CurrentBlock().Return(types.NewBlockWithHeader(headers[25])).AnyTimes()
+
+	db := database.NewMemoryDBManager().GetMemDB()
+	WriteGovDataBlockNums(db, StoredUint64Array{0})
+
+	h := NewHeaderGovModule()
+	require.NoError(t, h.Init(&InitOpts{
+		Chain:   chain,
+		ChainKv: db,
+		ChainConfig: &params.ChainConfig{
+			KoreCompatibleBlock: big.NewInt(0),
+			Istanbul:            &params.IstanbulConfig{Epoch: 10},
+		},
+	}))
+	return h, db
+}
+
+func TestHistoryExportImport(t *testing.T) {
+	headers := newHistoryTestChain(t)
+
+	src, srcDb := newHistoryTestModule(t, headers)
+	govData, _ := headergov.GovBytes(headers[10].Governance).ToGovData()
+	require.NoError(t, src.HandleGov(10, govData)) // indexed on insertion, not by the vote scan
+	_, _, err := src.ExportHistory(25)
+	assert.ErrorIs(t, err, ErrVoteScanInProgress)
+
+	require.NoError(t, src.Start())
+	require.Eventually(t, func() bool {
+		lowest := ReadLowestVoteScannedBlockNum(srcDb)
+		return lowest != nil && *lowest == 0
+	}, time.Second, 10*time.Millisecond)
+
+	votes, govs, err := src.ExportHistory(25)
+	require.NoError(t, err)
+	require.Len(t, votes, 1)
+	assert.Equal(t, uint64(3), votes[0].Block)
+	assert.Equal(t, gov.GovernanceUnitPrice, votes[0].Name)
+	require.Len(t, govs, 2)
+	assert.Equal(t, uint64(10), govs[1].Block)
+
+	// A node that has only scanned the latest epoch completes the scan with the imported records.
+	dst, dstDb := newHistoryTestModule(t, headers)
+	assert.Equal(t, uint64(2), *ReadLowestVoteScannedBlockNum(dstDb))
+	assert.Equal(t, uint64(250e9), dst.EffectiveParamSet(25).UnitPrice)
+
+	tampered := append([]gov.VoteRecord{}, votes...)
+	tampered[0].Raw = []byte{0x01}
+	_, _, err = dst.ImportHistory(25, tampered, govs)
+	assert.ErrorIs(t, err, gov.ErrHistoryMismatch)
+	assert.Empty(t, dst.VoteBlockNums())
+
+	imported, skipped, err := dst.ImportHistory(25, votes, govs)
+	require.NoError(t, err)
+	assert.Equal(t, 3, imported)
+	assert.Equal(t, 0, skipped)
+	assert.Equal(t, uint64(0), *ReadLowestVoteScannedBlockNum(dstDb))
+	assert.Equal(t, []uint64{3}, dst.VoteBlockNums())
+	assert.Equal(t, []uint64{0, 10}, dst.GovBlockNums())
+	assert.Equal(t, uint64(123), dst.EffectiveParamSet(25).UnitPrice)
+}
@@ -9,15 +9,19 @@ import (
 	"github.com/kaiachain/kaia/common"
 	"github.com/kaiachain/kaia/kaiax/gov"
 	"github.com/kaiachain/kaia/kaiax/gov/headergov"
+	"github.com/kaiachain/kaia/kaiax/staking"
 	"github.com/kaiachain/kaia/log"
 	"github.com/kaiachain/kaia/params"
 	"github.com/kaiachain/kaia/storage/database"
+	"github.com/rcrowley/go-metrics"
 )
 
 var (
 	_ headergov.HeaderGovModule = (*headerGovModule)(nil)
 
 	logger = log.NewModuleLogger(log.KaiaxGov)
+
+	pendingVotesGauge = metrics.NewRegisteredGauge("kaiax/gov/votes/pending", nil)
 )
 
 type chain interface {
@@ -27,17 +31,19 @@ type chain interface {
 }
 
 type InitOpts struct {
-	ChainKv     database.Database
-	ChainConfig *params.ChainConfig
-	Chain       chain
-	NodeAddress common.Address
+	ChainKv       database.Database
+	ChainConfig   *params.ChainConfig
+	Chain         chain
+	NodeAddress   common.Address
+	StakingModule staking.StakingModule // optional; required for the stake-weighted tally
 }
 
 //go:generate mockgen -destination=kaiax/headergov/mocks/headergov_mock.go github.com/kaiachain/kaia/kaiax/headergov HeaderGovModule
 type headerGovModule struct {
-	ChainKv     database.Database
-	ChainConfig *params.ChainConfig
-	Chain       chain
+	ChainKv       database.Database
+	ChainConfig   *params.ChainConfig
+	Chain         chain
+	StakingModule staking.StakingModule
 
 	groupedVotes headergov.GroupedVotesMap
 	governances  headergov.GovDataMap
@@ -63,6 +69,7 @@ func (h *headerGovModule) Init(opts *InitOpts) error {
 	h.ChainKv = opts.ChainKv
 	h.ChainConfig = opts.ChainConfig
 	h.Chain = opts.Chain
+	h.StakingModule = opts.StakingModule
 	h.nodeAddress = opts.NodeAddress
 	h.myVotes = make([]headergov.VoteData, 0)
 	h.mu = &sync.RWMutex{}
@@ -81,7 +88,8 @@ func (h *headerGovModule) Init(opts *InitOpts) error {
 	h.groupedVotes = make(map[uint64]headergov.VotesInEpoch)
 	h.governances = make(map[uint64]headergov.GovData)
 	govs := readGovDataFromDB(h.Chain, h.ChainKv)
-	h.history = make(headergov.History)
+	// The history always has the default params at block 0, even if the DB holds no governance.
+	h.history = headergov.GovsToHistory(h.governances)
 	for blockNum, gov := range govs {
 		h.AddGov(blockNum, gov)
 	}
@@ -111,7 +119,7 @@ func (h *headerGovModule) Start() error {
 	}
 
 	// Scan all epochs in the background including 0th epoch
-	epochIdxIter := calcEpochIdx(*lowestVoteScannedBlockNumPtr, h.epoch)
+	epochIdxIter := *lowestVoteScannedBlockNumPtr // stored as an epoch index
 	go func() {
 		for int64(epochIdxIter) > 0 {
 			epochIdxIter -= 1
@@ -130,6 +138,11 @@ func (h *headerGovModule) isKoreHF(num uint64) bool {
 	return h.ChainConfig.IsKoreForkEnabled(new(big.Int).SetUint64(num))
 }
 
+// isScheduledVoteEnabled returns true if a vote can carry an activation block at num.
+func (h *headerGovModule) isScheduledVoteEnabled(num uint64) bool {
+	return h.ChainConfig.IsScheduledVoteForkEnabled(new(big.Int).SetUint64(num))
+}
+
 func (h *headerGovModule) PushMyVotes(vote headergov.VoteData) {
 	h.myVotes = append(h.myVotes, vote)
 }
@@ -174,6 +187,11 @@ func (h *headerGovModule) scanAllVotesInEpoch(epochIdx uint64) map[uint64]header
 func (h *headerGovModule) accumulateVotesInEpoch(epochIdx uint64) {
 	lowestVoteScannedBlockNumPtr := ReadLowestVoteScannedBlockNum(h.ChainKv)
 
+	// The epoch has already been covered, e.g. by an imported history.
+	if lowestVoteScannedBlockNumPtr != nil && *lowestVoteScannedBlockNumPtr <= epochIdx {
+		return
+	}
+
 	// assert epochIdx == lowestVoteScannedBlockNum - 1
 	if lowestVoteScannedBlockNumPtr != nil && *lowestVoteScannedBlockNumPtr != epochIdx+1 {
 		logger.Error("Invalid epochIdx", "epochIdx", epochIdx, "lowestScanned", *lowestVoteScannedBlockNumPtr)
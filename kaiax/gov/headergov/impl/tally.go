@@ -0,0 +1,265 @@
+package impl
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/gov"
+	"github.com/kaiachain/kaia/kaiax/gov/headergov"
+)
+
+// isStakeWeighted returns true if the votes ratified at the given epoch block are weighted by stake.
+// It requires the `stake` governance mode and the StakeWeightedGov hardfork.
+func (h *headerGovModule) isStakeWeighted(blockNum uint64) bool {
+	if h.StakingModule == nil || !h.ChainConfig.IsStakeWeightedGovForkEnabled(new(big.Int).SetUint64(blockNum)) {
+		return false
+	}
+	return h.EffectiveParamSet(blockNum).GovernanceMode == "stake"
+}
+
+// isMultisig returns true if the votes ratified at the given epoch block must be co-signed by the multisig signers.
+// It requires the `multisig` governance mode and the MultisigGov hardfork.
+func (h *headerGovModule) isMultisig(blockNum uint64) bool {
+	if !h.ChainConfig.IsMultisigGovForkEnabled(new(big.Int).SetUint64(blockNum)) {
+		return false
+	}
+	return h.EffectiveParamSet(blockNum).GovernanceMode == "multisig"
+}
+
+// ratifiedVotes returns the votes that are ratified at the given epoch block.
+// Under the stake-weighted tally, a voter's latest vote for a parameter counts with the staked KAIA
+// of the voter's node, and a value is ratified if its votes hold more than half of the total stake.
+// Under the multisig tally, a value is ratified if at least the threshold number of signers co-sign it.
+// Otherwise, every vote is ratified. The emergency votes are never ratified at the epoch block.
+// An error is returned if the voters cannot be determined, since the tally would differ among nodes.
+func (h *headerGovModule) ratifiedVotes(blockNum uint64, votes map[uint64]headergov.VoteData) (map[uint64]headergov.VoteData, error) {
+	votes = regularVotes(votes)
+	if h.isStakeWeighted(blockNum) {
+		return h.stakeRatifiedVotes(blockNum, votes)
+	}
+	if h.isMultisig(blockNum) {
+		return multisigRatifiedVotes(h.EffectiveParamSet(blockNum), votes)
+	}
+	return votes, nil
+}
+
+func (h *headerGovModule) stakeRatifiedVotes(blockNum uint64, votes map[uint64]headergov.VoteData) (map[uint64]headergov.VoteData, error) {
+	nodeIdx, stakes, total, err := h.stakeWeights(blockNum)
+	if err != nil {
+		logger.Error("Failed to get staking info for the vote tally", "num", blockNum, "err", err)
+		return nil, err
+	}
+
+	ballots := latestBallots(votes, nodeIdx)
+	weights := make(map[choice]*big.Int)
+	for _, b := range ballots {
+		if _, ok := weights[b.choice]; !ok {
+			weights[b.choice] = new(big.Int)
+		}
+		weights[b.choice].Add(weights[b.choice], new(big.Int).SetUint64(stakes[b.voter]))
+	}
+
+	ret := make(map[uint64]headergov.VoteData)
+	for num, b := range ballots {
+		// weight > total / 2
+		if new(big.Int).Lsh(weights[b.choice], 1).Cmp(total) > 0 {
+			ret[num] = votes[num]
+		}
+	}
+	return ret, nil
+}
+
+// stakeWeights returns the voter index of each node ID, the stake of each voter and the total stake.
+// A node that registered multiple node IDs is counted once.
+func (h *headerGovModule) stakeWeights(blockNum uint64) (map[common.Address]int, []uint64, *big.Int, error) {
+	si, err := h.StakingModule.GetStakingInfo(blockNum)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var (
+		nodeIdx = make(map[common.Address]int)
+		stakes  []uint64
+		total   = new(big.Int)
+	)
+	for i, node := range si.ConsolidatedNodes() {
+		for _, id := range node.NodeIds {
+			nodeIdx[id] = i
+		}
+		stakes = append(stakes, node.StakingAmount)
+		total.Add(total, new(big.Int).SetUint64(node.StakingAmount))
+	}
+	return nodeIdx, stakes, total, nil
+}
+
+// multisigRatifiedVotes ratifies a value once MultisigThreshold distinct MultisigSigners have voted for it.
+// A zero threshold ratifies nothing.
+func multisigRatifiedVotes(ps gov.ParamSet, votes map[uint64]headergov.VoteData) (map[uint64]headergov.VoteData, error) {
+	signers, err := gov.ParseAddressList(ps.MultisigSigners)
+	if err != nil {
+		logger.Error("Invalid multisig signers for the vote tally", "signers", ps.MultisigSigners, "err", err)
+		return nil, err
+	}
+	signerIdx := make(map[common.Address]int)
+	for i, signer := range signers {
+		signerIdx[signer] = i
+	}
+
+	ballots := latestBallots(votes, signerIdx)
+	cosigners := make(map[choice]uint64)
+	for _, b := range ballots {
+		cosigners[b.choice]++
+	}
+
+	ret := make(map[uint64]headergov.VoteData)
+	for num, b := range ballots {
+		if ps.MultisigThreshold > 0 && cosigners[b.choice] >= ps.MultisigThreshold {
+			ret[num] = votes[num]
+		}
+	}
+	return ret, nil
+}
+
+type choice struct {
+	name       string
+	activation uint64
+	value      string
+}
+
+type ballot struct {
+	voter  int
+	choice choice
+}
+
+// latestBallots returns the latest vote of each voter for each parameter and activation, keyed by
+// the block number of the vote. Votes from the addresses missing in voterIdx do not count.
+// Addresses mapped to the same index are counted as one voter.
+func latestBallots(votes map[uint64]headergov.VoteData, voterIdx map[common.Address]int) map[uint64]ballot {
+	type ballotKey struct {
+		voter      int
+		name       string
+		activation uint64
+	}
+
+	voteBlockNums := make([]uint64, 0, len(votes))
+	for num := range votes {
+		voteBlockNums = append(voteBlockNums, num)
+	}
+	slices.Sort(voteBlockNums)
+
+	latest := make(map[ballotKey]uint64)
+	for _, num := range voteBlockNums {
+		vote := votes[num]
+		idx, ok := voterIdx[vote.Voter()]
+		if !ok {
+			continue
+		}
+		latest[ballotKey{idx, string(vote.Name()), vote.ActivationBlock()}] = num
+	}
+
+	ret := make(map[uint64]ballot)
+	for key, num := range latest {
+		ret[num] = ballot{key.voter, choice{key.name, key.activation, fmt.Sprint(votes[num].Value())}}
+	}
+	return ret
+}
+
+const (
+	TallyModeStake     = "stake"     // a value needs the votes of more than half of the total stake
+	TallyModeMultisig  = "multisig"  // a value needs the votes of MultisigThreshold signers
+	TallyModeUnanimous = "unanimous" // every vote is ratified
+)
+
+// ProposalStatus is the tally of the votes for a parameter value and activation.
+type ProposalStatus struct {
+	Key             string           `json:"key"`
+	Value           any              `json:"value"`
+	ActivationBlock uint64           `json:"activationBlock"` // the block where the value takes effect once ratified
+	Voters          []common.Address `json:"voters"`
+	Tally           uint64           `json:"tally"`  // staked KAIA of the voters in the stake mode, the number of voters otherwise
+	Quorum          uint64           `json:"quorum"` // the tally required for the ratification
+	Needed          uint64           `json:"needed"` // the tally still needed for the ratification
+}
+
+// proposalStatus tallies the votes to be ratified at the given epoch block, i.e. those cast in the previous epoch.
+// Only the latest vote of each voter for a parameter counts, and the votes from ineligible voters are left out.
+func (h *headerGovModule) proposalStatus(blockNum uint64) (string, []ProposalStatus, error) {
+	votes := regularVotes(h.getVotesInEpoch(calcEpochIdx(blockNum, h.epoch) - 1))
+
+	var (
+		mode     string
+		voterIdx map[common.Address]int
+		weight   func(voter int) uint64
+		quorum   uint64
+	)
+	ps := h.EffectiveParamSet(blockNum)
+	switch {
+	case h.isStakeWeighted(blockNum):
+		nodeIdx, stakes, total, err := h.stakeWeights(blockNum)
+		if err != nil {
+			return "", nil, err
+		}
+		mode, voterIdx = TallyModeStake, nodeIdx
+		weight = func(voter int) uint64 { return stakes[voter] }
+		quorum = new(big.Int).Rsh(total, 1).Uint64() + 1
+	case h.isMultisig(blockNum):
+		signers, err := gov.ParseAddressList(ps.MultisigSigners)
+		if err != nil {
+			return "", nil, err
+		}
+		mode, voterIdx = TallyModeMultisig, make(map[common.Address]int)
+		for i, signer := range signers {
+			voterIdx[signer] = i
+		}
+		weight = func(int) uint64 { return 1 }
+		quorum = ps.MultisigThreshold
+	default:
+		mode, voterIdx = TallyModeUnanimous, make(map[common.Address]int)
+		for _, vote := range votes {
+			if _, ok := voterIdx[vote.Voter()]; !ok {
+				voterIdx[vote.Voter()] = len(voterIdx)
+			}
+		}
+		weight = func(int) uint64 { return 1 }
+		quorum = 1
+	}
+
+	ballots := latestBallots(votes, voterIdx)
+	voteBlockNums := make([]uint64, 0, len(ballots))
+	for num := range ballots {
+		voteBlockNums = append(voteBlockNums, num)
+	}
+	slices.Sort(voteBlockNums)
+
+	var (
+		ret   []ProposalStatus
+		index = make(map[choice]int)
+	)
+	for _, num := range voteBlockNums {
+		b, vote := ballots[num], votes[num]
+		i, ok := index[b.choice]
+		if !ok {
+			activation := b.choice.activation
+			if activation == 0 {
+				activation = h.firstEffectiveBlock(blockNum)
+			}
+			i = len(ret)
+			index[b.choice] = i
+			ret = append(ret, ProposalStatus{Key: b.choice.name, Value: vote.Value(), ActivationBlock: activation, Quorum: quorum})
+		}
+		ret[i].Voters = append(ret[i].Voters, vote.Voter())
+		ret[i].Tally += weight(b.voter)
+	}
+	for i := range ret {
+		if ret[i].Tally < ret[i].Quorum {
+			ret[i].Needed = ret[i].Quorum - ret[i].Tally
+		}
+	}
+	slices.SortStableFunc(ret, func(a, b ProposalStatus) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return mode, ret, nil
+}
@@ -0,0 +1,281 @@
+package impl
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/gov"
+	"github.com/kaiachain/kaia/kaiax/gov/headergov"
+	"github.com/kaiachain/kaia/kaiax/staking"
+	staking_mock "github.com/kaiachain/kaia/kaiax/staking/mock"
+	"github.com/kaiachain/kaia/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStakeWeightedTally(t *testing.T) {
+	var (
+		paramName = string(gov.GovernanceUnitPrice)
+		n1        = common.Address{1}
+		n2        = common.Address{2}
+		n2b       = common.Address{0x2b} // n2's second node id
+		n3        = common.Address{3}
+		si        = &staking.StakingInfo{
+			NodeIds:          []common.Address{n1, n2, n2b, n3},
+			StakingContracts: []common.Address{{0x11}, {0x12}, {0x13}, {0x14}},
+			RewardAddrs:      []common.Address{{0x21}, {0x22}, {0x22}, {0x23}},
+			StakingAmounts:   []uint64{3_000_000, 2_000_000, 1_000_000, 5_000_000},
+		}
+	)
+
+	newModule := func(t *testing.T, forkBlock *big.Int, mode string) *headerGovModule {
+		h := newHeaderGovModule(t, &params.ChainConfig{
+			Istanbul:                        &params.IstanbulConfig{Epoch: 1000},
+			StakeWeightedGovCompatibleBlock: forkBlock,
+		})
+		mStaking := staking_mock.NewMockStakingModule(gomock.NewController(t))
+		mStaking.EXPECT().GetStakingInfo(gomock.Any()).Return(si, nil).AnyTimes()
+		h.StakingModule = mStaking
+		h.AddGov(0, headergov.NewGovData(gov.PartialParamSet{gov.GovernanceGovernanceMode: mode}))
+		return h
+	}
+
+	t.Run("majority of stake", func(t *testing.T) {
+		h := newModule(t, big.NewInt(0), "stake")
+		// n1 and n2 (3M + 3M of 11M) vote for 100, n3 (5M) votes for 200.
+		h.HandleVote(100, headergov.NewVoteData(n1, paramName, uint64(100)))
+		h.HandleVote(200, headergov.NewVoteData(n2b, paramName, uint64(100)))
+		h.HandleVote(300, headergov.NewVoteData(n3, paramName, uint64(200)))
+
+		expected := headergov.NewGovData(gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(100)})
+		assert.Equal(t, expected, expectedGovAt(t, h, 1000))
+	})
+
+	t.Run("no majority", func(t *testing.T) {
+		h := newModule(t, big.NewInt(0), "stake")
+		// Both node ids of n2 count once, with 3M of 11M in total.
+		h.HandleVote(100, headergov.NewVoteData(n2, paramName, uint64(100)))
+		h.HandleVote(200, headergov.NewVoteData(n2b, paramName, uint64(100)))
+		h.HandleVote(300, headergov.NewVoteData(common.Address{4}, paramName, uint64(100)))
+		assert.True(t, isEmptyGov(expectedGovAt(t, h, 1000)))
+	})
+
+	t.Run("latest vote of a voter", func(t *testing.T) {
+		h := newModule(t, big.NewInt(0), "stake")
+		h.HandleVote(100, headergov.NewVoteData(n3, paramName, uint64(100)))
+		h.HandleVote(200, headergov.NewVoteData(n1, paramName, uint64(200)))
+		h.HandleVote(300, headergov.NewVoteData(n3, paramName, uint64(200)))
+
+		expected := headergov.NewGovData(gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(200)})
+		assert.Equal(t, expected, expectedGovAt(t, h, 1000))
+	})
+
+	t.Run("before hardfork", func(t *testing.T) {
+		h := newModule(t, big.NewInt(2000), "stake")
+		h.HandleVote(100, headergov.NewVoteData(common.Address{4}, paramName, uint64(100)))
+
+		expected := headergov.NewGovData(gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(100)})
+		assert.Equal(t, expected, expectedGovAt(t, h, 1000))
+	})
+
+	t.Run("other mode", func(t *testing.T) {
+		h := newModule(t, big.NewInt(0), "none")
+		h.HandleVote(100, headergov.NewVoteData(common.Address{4}, paramName, uint64(100)))
+
+		expected := headergov.NewGovData(gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(100)})
+		assert.Equal(t, expected, expectedGovAt(t, h, 1000))
+	})
+}
+
+func TestMultisigTally(t *testing.T) {
+	var (
+		paramName = string(gov.GovernanceUnitPrice)
+		s1        = common.Address{1}
+		s2        = common.Address{2}
+		s3        = common.Address{3}
+		outsider  = common.Address{4}
+	)
+
+	newModule := func(t *testing.T, threshold uint64) *headerGovModule {
+		h := newHeaderGovModule(t, &params.ChainConfig{Istanbul: &params.IstanbulConfig{Epoch: 1000}, MultisigGovCompatibleBlock: big.NewInt(0)})
+		h.AddGov(0, headergov.NewGovData(gov.PartialParamSet{
+			gov.GovernanceGovernanceMode:    "multisig",
+			gov.GovernanceMultisigSigners:   s1.Hex() + "," + s2.Hex() + "," + s3.Hex(),
+			gov.GovernanceMultisigThreshold: threshold,
+		}))
+		return h
+	}
+
+	t.Run("co-signed", func(t *testing.T) {
+		h := newModule(t, 2)
+		h.HandleVote(100, headergov.NewVoteData(s1, paramName, uint64(100)))
+		h.HandleVote(200, headergov.NewVoteData(s3, paramName, uint64(100)))
+
+		expected := headergov.NewGovData(gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(100)})
+		assert.Equal(t, expected, expectedGovAt(t, h, 1000))
+	})
+
+	t.Run("single signer", func(t *testing.T) {
+		h := newModule(t, 2)
+		h.HandleVote(100, headergov.NewVoteData(s1, paramName, uint64(100)))
+		h.HandleVote(200, headergov.NewVoteData(s1, paramName, uint64(100)))
+		h.HandleVote(300, headergov.NewVoteData(outsider, paramName, uint64(100)))
+		assert.True(t, isEmptyGov(expectedGovAt(t, h, 1000)))
+	})
+
+	t.Run("different values", func(t *testing.T) {
+		h := newModule(t, 2)
+		h.HandleVote(100, headergov.NewVoteData(s1, paramName, uint64(100)))
+		h.HandleVote(200, headergov.NewVoteData(s2, paramName, uint64(200)))
+		assert.True(t, isEmptyGov(expectedGovAt(t, h, 1000)))
+	})
+
+	t.Run("latest vote of a signer", func(t *testing.T) {
+		h := newModule(t, 2)
+		h.HandleVote(100, headergov.NewVoteData(s1, paramName, uint64(100)))
+		h.HandleVote(200, headergov.NewVoteData(s2, paramName, uint64(200)))
+		h.HandleVote(300, headergov.NewVoteData(s1, paramName, uint64(200)))
+
+		expected := headergov.NewGovData(gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(200)})
+		assert.Equal(t, expected, expectedGovAt(t, h, 1000))
+	})
+
+	t.Run("before the fork", func(t *testing.T) {
+		h := newModule(t, 2)
+		h.ChainConfig.MultisigGovCompatibleBlock = big.NewInt(2000)
+
+		// The multisig params cannot be voted and the votes are ratified as in the `none` mode.
+		assert.ErrorIs(t, h.VerifyVote(100, headergov.NewVoteData(s1, string(gov.GovernanceMultisigThreshold), uint64(1))), ErrMultisigGovDisabled)
+		h.HandleVote(100, headergov.NewVoteData(outsider, paramName, uint64(100)))
+
+		expected := headergov.NewGovData(gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(100)})
+		assert.Equal(t, expected, expectedGovAt(t, h, 1000))
+	})
+
+	t.Run("zero threshold", func(t *testing.T) {
+		h := newModule(t, 0)
+		h.HandleVote(100, headergov.NewVoteData(s1, paramName, uint64(100)))
+		assert.True(t, isEmptyGov(expectedGovAt(t, h, 1000)))
+	})
+}
+
+func TestProposalStatus(t *testing.T) {
+	var (
+		paramName = string(gov.GovernanceUnitPrice)
+		n1        = common.Address{1}
+		n2        = common.Address{2}
+		n3        = common.Address{3}
+		outsider  = common.Address{4}
+	)
+
+	t.Run("stake", func(t *testing.T) {
+		h := newHeaderGovModule(t, &params.ChainConfig{
+			Istanbul:                        &params.IstanbulConfig{Epoch: 1000},
+			KoreCompatibleBlock:             big.NewInt(0),
+			StakeWeightedGovCompatibleBlock: big.NewInt(0),
+		})
+		mStaking := staking_mock.NewMockStakingModule(gomock.NewController(t))
+		mStaking.EXPECT().GetStakingInfo(gomock.Any()).Return(&staking.StakingInfo{
+			NodeIds:          []common.Address{n1, n2, n3},
+			StakingContracts: []common.Address{{0x11}, {0x12}, {0x13}},
+			RewardAddrs:      []common.Address{{0x21}, {0x22}, {0x23}},
+			StakingAmounts:   []uint64{3_000_000, 2_000_000, 5_000_000},
+		}, nil).AnyTimes()
+		h.StakingModule = mStaking
+		h.AddGov(0, headergov.NewGovData(gov.PartialParamSet{gov.GovernanceGovernanceMode: "stake"}))
+
+		h.HandleVote(100, headergov.NewVoteData(n1, paramName, uint64(100)))
+		h.HandleVote(200, headergov.NewVoteData(n2, paramName, uint64(100)))
+		h.HandleVote(300, headergov.NewVoteData(outsider, paramName, uint64(100)))
+		h.HandleVote(400, headergov.NewScheduledVoteData(n3, paramName, uint64(200), 5000))
+
+		mode, status, err := h.proposalStatus(1000)
+		assert.NoError(t, err)
+		assert.Equal(t, TallyModeStake, mode)
+		assert.Equal(t, []ProposalStatus{
+			{Key: paramName, Value: uint64(100), ActivationBlock: 2000, Voters: []common.Address{n1, n2}, Tally: 5_000_000, Quorum: 5_000_001, Needed: 1},
+			{Key: paramName, Value: uint64(200), ActivationBlock: 5000, Voters: []common.Address{n3}, Tally: 5_000_000, Quorum: 5_000_001, Needed: 1},
+		}, status)
+	})
+
+	t.Run("multisig", func(t *testing.T) {
+		h := newHeaderGovModule(t, &params.ChainConfig{Istanbul: &params.IstanbulConfig{Epoch: 1000}, MultisigGovCompatibleBlock: big.NewInt(0)})
+		h.AddGov(0, headergov.NewGovData(gov.PartialParamSet{
+			gov.GovernanceGovernanceMode:    "multisig",
+			gov.GovernanceMultisigSigners:   n1.Hex() + "," + n2.Hex() + "," + n3.Hex(),
+			gov.GovernanceMultisigThreshold: uint64(2),
+		}))
+
+		// n1 changes its vote to 200, co-signed by n3.
+		h.HandleVote(100, headergov.NewVoteData(n1, paramName, uint64(100)))
+		h.HandleVote(200, headergov.NewVoteData(n1, paramName, uint64(200)))
+		h.HandleVote(300, headergov.NewVoteData(n3, paramName, uint64(200)))
+		h.HandleVote(400, headergov.NewVoteData(outsider, paramName, uint64(100)))
+
+		mode, status, err := h.proposalStatus(1000)
+		assert.NoError(t, err)
+		assert.Equal(t, TallyModeMultisig, mode)
+		// Before Kore, the change takes effect one block after the epoch block.
+		assert.Equal(t, []ProposalStatus{
+			{Key: paramName, Value: uint64(200), ActivationBlock: 2001, Voters: []common.Address{n1, n3}, Tally: 2, Quorum: 2, Needed: 0},
+		}, status)
+	})
+
+	t.Run("unanimous", func(t *testing.T) {
+		h := newHeaderGovModule(t, &params.ChainConfig{Istanbul: &params.IstanbulConfig{Epoch: 1000}, KoreCompatibleBlock: big.NewInt(0)})
+		h.AddGov(0, headergov.NewGovData(gov.PartialParamSet{gov.GovernanceGovernanceMode: "none"}))
+		h.HandleVote(1100, headergov.NewVoteData(outsider, paramName, uint64(100)))
+
+		mode, status, err := h.proposalStatus(2000)
+		assert.NoError(t, err)
+		assert.Equal(t, TallyModeUnanimous, mode)
+		assert.Equal(t, []ProposalStatus{
+			{Key: paramName, Value: uint64(100), ActivationBlock: 3000, Voters: []common.Address{outsider}, Tally: 1, Quorum: 1, Needed: 0},
+		}, status)
+
+		_, status, err = h.proposalStatus(3000)
+		assert.NoError(t, err)
+		assert.Empty(t, status)
+	})
+}
+
+func TestTallyStakingInfoError(t *testing.T) {
+	errStaking := errors.New("staking info unavailable")
+	h := newHeaderGovModule(t, &params.ChainConfig{
+		Istanbul:                        &params.IstanbulConfig{Epoch: 1000},
+		StakeWeightedGovCompatibleBlock: big.NewInt(0),
+		EmergencyPauseCompatibleBlock:   big.NewInt(0),
+	})
+	mStaking := staking_mock.NewMockStakingModule(gomock.NewController(t))
+	mStaking.EXPECT().GetStakingInfo(gomock.Any()).Return(nil, errStaking).AnyTimes()
+	h.StakingModule = mStaking
+	h.AddGov(0, headergov.NewGovData(gov.PartialParamSet{gov.GovernanceGovernanceMode: "stake"}))
+	h.HandleVote(100, headergov.NewVoteData(common.Address{1}, string(gov.GovernanceUnitPrice), uint64(100)))
+
+	// The tally must not silently ratify nothing when the voters are unknown.
+	_, err := h.getExpectedGovernance(1000)
+	assert.ErrorIs(t, err, errStaking)
+	assert.ErrorIs(t, h.PrepareHeader(&types.Header{Number: big.NewInt(1000)}), errStaking)
+	assert.ErrorIs(t, h.VerifyGov(&types.Header{Number: big.NewInt(1000)}), errStaking)
+
+	// Neither does the emergency tally.
+	vb, err := headergov.NewEmergencyVoteData(common.Address{1}, string(gov.Kip71LowerBoundBaseFee), uint64(50e9)).ToVoteBytes()
+	require.NoError(t, err)
+	assert.ErrorIs(t, h.VerifyGov(&types.Header{Number: big.NewInt(300), Vote: vb}), errStaking)
+}
+
+func expectedGovAt(t *testing.T, h *headerGovModule, blockNum uint64) headergov.GovData {
+	g, err := h.getExpectedGovernance(blockNum)
+	require.NoError(t, err)
+	return g
+}
+
+func expectedGovOf(t *testing.T, h *headerGovModule, header *types.Header) headergov.GovData {
+	g, err := h.expectedGovernance(header)
+	require.NoError(t, err)
+	return g
+}
@@ -13,14 +13,19 @@ type HeaderGovModule interface {
 	kaiax.ConsensusModule
 	kaiax.ExecutionModule
 	kaiax.RewindableModule
+	gov.ParamSource
 
 	EffectiveParamSet(blockNum uint64) gov.ParamSet
-	EffectiveParamsPartial(blockNum uint64) gov.PartialParamSet
 	NodeAddress() common.Address
+
+	ExportHistory(head uint64) ([]gov.VoteRecord, []gov.GovRecord, error)
+	ImportHistory(head uint64, votes []gov.VoteRecord, govs []gov.GovRecord) (imported, skipped int, err error)
 }
 
 type GovData interface {
 	Items() gov.PartialParamSet
+	Scheduled() ScheduledParams
+	Emergency() gov.PartialParamSet
 	ToGovBytes() (GovBytes, error)
 }
 
@@ -28,6 +33,8 @@ type VoteData interface {
 	Voter() common.Address
 	Name() gov.ParamName
 	Value() any
+	ActivationBlock() uint64
+	Emergency() bool
 
 	ToVoteBytes() (VoteBytes, error)
 }
@@ -80,6 +80,22 @@ func (mr *MockHeaderGovModuleMockRecorder) EffectiveParamsPartial(arg0 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EffectiveParamsPartial", reflect.TypeOf((*MockHeaderGovModule)(nil).EffectiveParamsPartial), arg0)
 }
 
+// ExportHistory mocks base method.
+func (m *MockHeaderGovModule) ExportHistory(arg0 uint64) ([]gov.VoteRecord, []gov.GovRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportHistory", arg0)
+	ret0, _ := ret[0].([]gov.VoteRecord)
+	ret1, _ := ret[1].([]gov.GovRecord)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ExportHistory indicates an expected call of ExportHistory.
+func (mr *MockHeaderGovModuleMockRecorder) ExportHistory(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportHistory", reflect.TypeOf((*MockHeaderGovModule)(nil).ExportHistory), arg0)
+}
+
 // FinalizeHeader mocks base method.
 func (m *MockHeaderGovModule) FinalizeHeader(arg0 *types.Header, arg1 *state.StateDB, arg2 []*types.Transaction, arg3 []*types.Receipt) error {
 	m.ctrl.T.Helper()
@@ -94,6 +110,22 @@ func (mr *MockHeaderGovModuleMockRecorder) FinalizeHeader(arg0, arg1, arg2, arg3
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinalizeHeader", reflect.TypeOf((*MockHeaderGovModule)(nil).FinalizeHeader), arg0, arg1, arg2, arg3)
 }
 
+// ImportHistory mocks base method.
+func (m *MockHeaderGovModule) ImportHistory(arg0 uint64, arg1 []gov.VoteRecord, arg2 []gov.GovRecord) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ImportHistory indicates an expected call of ImportHistory.
+func (mr *MockHeaderGovModuleMockRecorder) ImportHistory(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportHistory", reflect.TypeOf((*MockHeaderGovModule)(nil).ImportHistory), arg0, arg1, arg2)
+}
+
 // NodeAddress mocks base method.
 func (m *MockHeaderGovModule) NodeAddress() common.Address {
 	m.ctrl.T.Helper()
@@ -108,6 +140,20 @@ func (mr *MockHeaderGovModuleMockRecorder) NodeAddress() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeAddress", reflect.TypeOf((*MockHeaderGovModule)(nil).NodeAddress))
 }
 
+// ParamChangeBlocks mocks base method.
+func (m *MockHeaderGovModule) ParamChangeBlocks(arg0, arg1 uint64) []uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParamChangeBlocks", arg0, arg1)
+	ret0, _ := ret[0].([]uint64)
+	return ret0
+}
+
+// ParamChangeBlocks indicates an expected call of ParamChangeBlocks.
+func (mr *MockHeaderGovModuleMockRecorder) ParamChangeBlocks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParamChangeBlocks", reflect.TypeOf((*MockHeaderGovModule)(nil).ParamChangeBlocks), arg0, arg1)
+}
+
 // PostInsertBlock mocks base method.
 func (m *MockHeaderGovModule) PostInsertBlock(arg0 *types.Block) error {
 	m.ctrl.T.Helper()
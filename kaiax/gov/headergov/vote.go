@@ -17,41 +17,90 @@ type (
 )
 
 type voteData struct {
-	voter common.Address
-	name  gov.ParamName
-	value any // canonicalized value
+	voter      common.Address
+	name       gov.ParamName
+	value      any    // canonicalized value
+	activation uint64 // explicit activation block. Zero if the vote takes effect in the next epoch.
+	emergency  bool   // true if the vote is for the emergency change of the council
 }
 
 // NewVoteData returns a valid, canonical vote data.
 // If return is not nil, the name and the value is valid.
 // The format of the value is checked, but consistency is NOT checked.
 func NewVoteData(voter common.Address, name string, value any) VoteData {
+	cv, err := canonicalizeVote(name, value)
+	if err != nil {
+		return nil
+	}
+
+	return &voteData{
+		voter: voter,
+		name:  gov.ParamName(name),
+		value: cv,
+	}
+}
+
+// CheckVoteValue returns why NewVoteData would reject the name and value, or nil if it accepts them.
+// An invalid value is reported as a *gov.ParamError.
+func CheckVoteValue(name string, value any) error {
+	_, err := canonicalizeVote(name, value)
+	return err
+}
+
+func canonicalizeVote(name string, value any) (any, error) {
 	param, ok := gov.Params[gov.ParamName(name)]
 	if !ok {
 		param, ok = gov.ValidatorParams[gov.ValidatorParamName(name)]
 		if !ok {
-			return nil
+			return nil, gov.ErrInvalidParamName
 		}
 	}
 
 	if param.VoteForbidden {
+		return nil, ErrVoteForbidden
+	}
+
+	return param.Canonicalize(gov.ParamName(name), value)
+}
+
+// NewScheduledVoteData returns a valid, canonical vote data which takes effect at the given activation block.
+// Only governance params can be scheduled. Whether the activation block is reachable is NOT checked.
+func NewScheduledVoteData(voter common.Address, name string, value any, activation uint64) VoteData {
+	if _, ok := gov.Params[gov.ParamName(name)]; !ok {
 		return nil
 	}
 
-	cv, err := param.Canonicalizer(value)
-	if err != nil {
+	vote := NewVoteData(voter, name, value)
+	if vote == nil {
 		return nil
 	}
 
-	if !param.FormatChecker(cv) {
+	vote.(*voteData).activation = activation
+	return vote
+}
+
+// NewEmergencyVoteData returns a valid, canonical vote data for an emergency change, which takes effect
+// right after the council reaches the supermajority. Only the parameters marked Emergency can be voted.
+func NewEmergencyVoteData(voter common.Address, name string, value any) VoteData {
+	if param, ok := gov.Params[gov.ParamName(name)]; !ok || !param.Emergency {
 		return nil
 	}
 
-	return &voteData{
-		voter: voter,
-		name:  gov.ParamName(name),
-		value: cv,
+	vote := NewVoteData(voter, name, value)
+	if vote == nil {
+		return nil
+	}
+
+	vote.(*voteData).emergency = true
+	return vote
+}
+
+// CheckEmergencyVote returns why NewEmergencyVoteData would reject the name and value, or nil if it accepts them.
+func CheckEmergencyVote(name string, value any) error {
+	if param, ok := gov.Params[gov.ParamName(name)]; ok && !param.Emergency {
+		return ErrNotEmergency
 	}
+	return CheckVoteValue(name, value)
 }
 
 func (vote *voteData) Voter() common.Address {
@@ -66,15 +115,28 @@ func (vote *voteData) Value() any {
 	return vote.value
 }
 
+func (vote *voteData) ActivationBlock() uint64 {
+	return vote.activation
+}
+
+func (vote *voteData) Emergency() bool {
+	return vote.emergency
+}
+
 func (vote *voteData) ToVoteBytes() (VoteBytes, error) {
+	// Activation and Emergency are omitted if zero, so that a regular vote is encoded as before.
 	v := &struct {
-		Validator common.Address
-		Key       string
-		Value     any
+		Validator  common.Address
+		Key        string
+		Value      any
+		Activation uint64 `rlp:"optional"`
+		Emergency  bool   `rlp:"optional"`
 	}{
-		Validator: vote.voter,
-		Key:       string(vote.name),
-		Value:     vote.value,
+		Validator:  vote.voter,
+		Key:        string(vote.name),
+		Value:      vote.value,
+		Activation: vote.activation,
+		Emergency:  vote.emergency,
 	}
 
 	if cv, ok := vote.value.(*big.Int); ok {
@@ -93,13 +155,17 @@ func (vote *voteData) ToVoteBytes() (VoteBytes, error) {
 
 func (vote *voteData) MarshalJSON() ([]byte, error) {
 	v := &struct {
-		Voter common.Address
-		Name  string
-		Value any
+		Voter           common.Address
+		Name            string
+		Value           any
+		ActivationBlock uint64 `json:",omitempty"`
+		Emergency       bool   `json:",omitempty"`
 	}{
-		Voter: vote.voter,
-		Name:  string(vote.name),
-		Value: vote.value,
+		Voter:           vote.voter,
+		Name:            string(vote.name),
+		Value:           vote.value,
+		ActivationBlock: vote.activation,
+		Emergency:       vote.emergency,
 	}
 
 	return json.Marshal(v)
@@ -107,9 +173,11 @@ func (vote *voteData) MarshalJSON() ([]byte, error) {
 
 func (vb VoteBytes) ToVoteData() (VoteData, error) {
 	var v struct {
-		Validator common.Address
-		Key       string
-		Value     []byte
+		Validator  common.Address
+		Key        string
+		Value      []byte
+		Activation uint64 `rlp:"optional"`
+		Emergency  bool   `rlp:"optional"`
 	}
 
 	err := rlp.DecodeBytes(vb, &v)
@@ -117,7 +185,17 @@ func (vb VoteBytes) ToVoteData() (VoteData, error) {
 		return nil, ErrInvalidRlp
 	}
 
-	vote := NewVoteData(v.Validator, v.Key, v.Value)
+	var vote VoteData
+	if v.Emergency {
+		if v.Activation > 0 {
+			return nil, ErrInvalidVoteData
+		}
+		vote = NewEmergencyVoteData(v.Validator, v.Key, v.Value)
+	} else if v.Activation > 0 {
+		vote = NewScheduledVoteData(v.Validator, v.Key, v.Value, v.Activation)
+	} else {
+		vote = NewVoteData(v.Validator, v.Key, v.Value)
+	}
 	if vote == nil {
 		return nil, ErrInvalidVoteData
 	}
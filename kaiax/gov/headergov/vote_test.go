@@ -259,3 +259,58 @@ func TestVoteSerialization(t *testing.T) {
 		})
 	}
 }
+
+func TestScheduledVoteSerialization(t *testing.T) {
+	v1 := common.HexToAddress("0x52d41ca72af615a1ac3301b0a93efa222ecc7541")
+
+	// Validator votes cannot be scheduled.
+	assert.Nil(t, NewScheduledVoteData(v1, "governance.removevalidator", "0xa2ba8f7798649a778a1fd66d3035904949fec555", 100))
+
+	vote := NewScheduledVoteData(v1, "governance.unitprice", uint64(50e9), 3000)
+	assert.NotNil(t, vote)
+	assert.Equal(t, uint64(3000), vote.ActivationBlock())
+
+	// The activation block is appended to the unscheduled encoding.
+	unscheduled, err := NewVoteData(v1, "governance.unitprice", uint64(50e9)).ToVoteBytes()
+	assert.NoError(t, err)
+	scheduled, err := vote.ToVoteBytes()
+	assert.NoError(t, err)
+	assert.NotEqual(t, unscheduled, scheduled)
+
+	actual, err := scheduled.ToVoteData()
+	assert.NoError(t, err)
+	assert.Equal(t, vote, actual)
+
+	actual, err = unscheduled.ToVoteData()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), actual.ActivationBlock())
+}
+
+func TestEmergencyVoteSerialization(t *testing.T) {
+	v1 := common.HexToAddress("0x52d41ca72af615a1ac3301b0a93efa222ecc7541")
+
+	// Only the parameters marked Emergency can be voted in an emergency.
+	assert.Nil(t, NewEmergencyVoteData(v1, "governance.unitprice", uint64(50e9)))
+	assert.Nil(t, NewEmergencyVoteData(v1, "governance.removevalidator", "0xa2ba8f7798649a778a1fd66d3035904949fec555"))
+	assert.ErrorIs(t, CheckEmergencyVote("governance.unitprice", uint64(50e9)), ErrNotEmergency)
+	assert.NoError(t, CheckEmergencyVote("kip71.lowerboundbasefee", uint64(50e9)))
+
+	vote := NewEmergencyVoteData(v1, "kip71.lowerboundbasefee", uint64(50e9))
+	assert.NotNil(t, vote)
+	assert.True(t, vote.Emergency())
+	assert.Equal(t, uint64(0), vote.ActivationBlock())
+
+	regular, err := NewVoteData(v1, "kip71.lowerboundbasefee", uint64(50e9)).ToVoteBytes()
+	assert.NoError(t, err)
+	emergency, err := vote.ToVoteBytes()
+	assert.NoError(t, err)
+	assert.NotEqual(t, regular, emergency)
+
+	actual, err := emergency.ToVoteData()
+	assert.NoError(t, err)
+	assert.Equal(t, vote, actual)
+
+	actual, err = regular.ToVoteData()
+	assert.NoError(t, err)
+	assert.False(t, actual.Emergency())
+}
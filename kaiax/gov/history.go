@@ -0,0 +1,163 @@
+package gov
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+)
+
+// HistoryVersion is the version of the HistoryExport format.
+const HistoryVersion = 1
+
+var (
+	ErrInvalidHistory  = errors.New("invalid governance history")
+	ErrHistoryMismatch = errors.New("governance history does not match the local chain")
+)
+
+// HistoryExport is the governance history of a chain up to Head. Records are sorted by
+// block number, so that the same chain always exports to the same JSON. Parameter values
+// are in their JSON form (see HistoryValue) and canonicalized again when read.
+type HistoryExport struct {
+	Version     int               `json:"version"`
+	GenesisHash common.Hash       `json:"genesisHash"`
+	Head        uint64            `json:"head"`
+	Epoch       uint64            `json:"epoch"`
+	Votes       []VoteRecord      `json:"votes"`
+	Governances []GovRecord       `json:"governances"`
+	GovParam    *GovParamRecords  `json:"govParam,omitempty"`
+	Effective   map[ParamName]any `json:"effective"` // the effective parameters at Head
+	Changes     []ParamChange     `json:"changes,omitempty"`
+}
+
+// ParamChange is a change of the effective parameters taking effect at Block. The change
+// at block 0 holds every parameter effective at genesis.
+type ParamChange struct {
+	Block  uint64            `json:"block"`
+	Params map[ParamName]any `json:"params"`
+}
+
+// VoteRecord is a header governance vote. Raw is the Vote field of the header.
+type VoteRecord struct {
+	Block uint64         `json:"block"`
+	Voter common.Address `json:"voter"`
+	Name  ParamName      `json:"name"`
+	Value any            `json:"value"`
+	Raw   hexutil.Bytes  `json:"raw"`
+}
+
+// GovRecord is a ratified header governance. Raw is the Governance field of the header.
+type GovRecord struct {
+	Block  uint64            `json:"block"`
+	Params map[ParamName]any `json:"params"`
+	Raw    hexutil.Bytes     `json:"raw"`
+}
+
+// GovParamRecords are the checkpoints stored in the GovParam contract at Head.
+type GovParamRecords struct {
+	Address common.Address   `json:"address"`
+	Records []ContractRecord `json:"records"`
+}
+
+// ContractRecord is a GovParam checkpoint. Value is empty if the parameter is deleted.
+type ContractRecord struct {
+	Name       string        `json:"name"`
+	Activation uint64        `json:"activation"`
+	Value      hexutil.Bytes `json:"value"`
+}
+
+// Validate checks the version and that the records are sorted and within Head.
+func (h *HistoryExport) Validate() error {
+	if h.Version != HistoryVersion {
+		return fmt.Errorf("%w: version %d, want %d", ErrInvalidHistory, h.Version, HistoryVersion)
+	}
+	if h.Epoch == 0 {
+		return fmt.Errorf("%w: zero epoch", ErrInvalidHistory)
+	}
+	for i, v := range h.Votes {
+		if v.Block > h.Head || (i > 0 && v.Block <= h.Votes[i-1].Block) {
+			return fmt.Errorf("%w: vote at block %d is unsorted or beyond the head", ErrInvalidHistory, v.Block)
+		}
+	}
+	for i, g := range h.Governances {
+		if g.Block > h.Head || (i > 0 && g.Block <= h.Governances[i-1].Block) {
+			return fmt.Errorf("%w: governance at block %d is unsorted or beyond the head", ErrInvalidHistory, g.Block)
+		}
+		if g.Block%h.Epoch != 0 {
+			return fmt.Errorf("%w: governance at non-epoch block %d", ErrInvalidHistory, g.Block)
+		}
+	}
+	for i, c := range h.Changes {
+		if c.Block > h.Head || (i > 0 && c.Block <= h.Changes[i-1].Block) {
+			return fmt.Errorf("%w: change at block %d is unsorted or beyond the head", ErrInvalidHistory, c.Block)
+		}
+	}
+	return nil
+}
+
+// ParamSetAt returns the parameter set effective at num according to Changes.
+func (h *HistoryExport) ParamSetAt(num uint64) (ParamSet, error) {
+	if len(h.Changes) == 0 || h.Changes[0].Block != 0 {
+		return ParamSet{}, fmt.Errorf("%w: no parameter changes from genesis", ErrInvalidHistory)
+	}
+	ps := GetDefaultGovernanceParamSet()
+	for _, c := range h.Changes {
+		if c.Block > num {
+			break
+		}
+		partial, err := c.PartialParamSet()
+		if err != nil {
+			return ParamSet{}, err
+		}
+		if err := ps.SetFromMap(partial); err != nil {
+			return ParamSet{}, err
+		}
+	}
+	return *ps, nil
+}
+
+// PartialParamSet returns the canonical values of the change.
+func (c *ParamChange) PartialParamSet() (PartialParamSet, error) {
+	partial := make(PartialParamSet)
+	for name, value := range c.Params {
+		if err := partial.Add(string(name), value); err != nil {
+			return nil, fmt.Errorf("%w: %s at block %d: %v", ErrInvalidHistory, name, c.Block, err)
+		}
+	}
+	return partial, nil
+}
+
+// EffectiveParamSet returns the parameter set effective at Head.
+func (h *HistoryExport) EffectiveParamSet() (ParamSet, error) {
+	partial := make(PartialParamSet)
+	for name, value := range h.Effective {
+		if err := partial.Add(string(name), value); err != nil {
+			return ParamSet{}, fmt.Errorf("%w: %s: %v", ErrInvalidHistory, name, err)
+		}
+	}
+	ps := GetDefaultGovernanceParamSet()
+	if err := ps.SetFromMap(partial); err != nil {
+		return ParamSet{}, err
+	}
+	return *ps, nil
+}
+
+// HistoryValue returns the JSON form of a canonical value. Big integers become decimal
+// strings, because JSON numbers would lose their precision.
+func HistoryValue(cv any) any {
+	if b, ok := cv.(*big.Int); ok && b != nil {
+		return b.String()
+	}
+	return cv
+}
+
+// HistoryParams applies HistoryValue to every value of p.
+func HistoryParams(p PartialParamSet) map[ParamName]any {
+	ret := make(map[ParamName]any, len(p))
+	for name, value := range p {
+		ret[name] = HistoryValue(value)
+	}
+	return ret
+}
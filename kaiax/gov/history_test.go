@@ -0,0 +1,44 @@
+package gov
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryParamSetAt(t *testing.T) {
+	h := &HistoryExport{
+		Version: HistoryVersion,
+		Head:    2000,
+		Epoch:   100,
+		Changes: []ParamChange{
+			{Block: 0, Params: map[ParamName]any{GovernanceUnitPrice: float64(25e9), RewardMintingAmount: "9600000000000000000"}},
+			{Block: 201, Params: map[ParamName]any{GovernanceUnitPrice: float64(50e9)}},
+			{Block: 1500, Params: map[ParamName]any{RewardMintingAmount: "6400000000000000000"}},
+		},
+	}
+	require.NoError(t, h.Validate())
+
+	for _, tc := range []struct {
+		num       uint64
+		unitPrice uint64
+		minting   string
+	}{
+		{0, 25e9, "9600000000000000000"},
+		{200, 25e9, "9600000000000000000"},
+		{201, 50e9, "9600000000000000000"},
+		{2000, 50e9, "6400000000000000000"},
+	} {
+		ps, err := h.ParamSetAt(tc.num)
+		require.NoError(t, err)
+		assert.Equal(t, tc.unitPrice, ps.UnitPrice, tc.num)
+		assert.Equal(t, tc.minting, ps.MintingAmount.String(), tc.num)
+	}
+
+	// The changes must start at genesis, be sorted and be within the head.
+	h.Changes[1].Block = 3000
+	assert.ErrorIs(t, h.Validate(), ErrInvalidHistory)
+	_, err := (&HistoryExport{Changes: h.Changes[1:]}).ParamSetAt(2000)
+	assert.ErrorIs(t, err, ErrInvalidHistory)
+}
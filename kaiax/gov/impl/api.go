@@ -1,6 +1,7 @@
 package impl
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/kaiachain/kaia/common"
@@ -43,6 +44,87 @@ func (api *GovAPI) GetParams(num *rpc.BlockNumber) (gov.PartialParamSet, error)
 	return getParams(api.g, num)
 }
 
+// SimulateParamSet returns the parameter set at the given block as if the overrides
+// had been voted through header governance, so that a vote can be previewed before casting it.
+func (api *GovAPI) SimulateParamSet(overrides map[string]any, num *rpc.BlockNumber) (gov.ParamSet, error) {
+	partial := make(gov.PartialParamSet)
+	for name, value := range overrides {
+		if err := partial.Add(name, value); err != nil {
+			return gov.ParamSet{}, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	blockNumber := uint64(0)
+	if num == nil || *num == rpc.LatestBlockNumber || *num == rpc.PendingBlockNumber {
+		blockNumber = api.g.Chain.CurrentBlock().NumberU64()
+	} else {
+		blockNumber = uint64(num.Int64())
+	}
+	return api.g.simulateParamSet(blockNumber, partial), nil
+}
+
+// ParamDiff returns the parameters whose effective values changed between the two blocks,
+// with the block at which each change took effect.
+func (api *GovAPI) ParamDiff(from, to rpc.BlockNumber) (map[gov.ParamName][]ParamChange, error) {
+	current := api.g.Chain.CurrentBlock().NumberU64()
+	resolve := func(num rpc.BlockNumber) uint64 {
+		if num == rpc.LatestBlockNumber || num == rpc.PendingBlockNumber {
+			return current
+		}
+		return uint64(num.Int64())
+	}
+
+	fromNum, toNum := resolve(from), resolve(to)
+	if toNum > current {
+		return nil, gov.ErrUnknownBlock
+	}
+	if fromNum > toNum {
+		return nil, gov.ErrInvalidBlockRange
+	}
+	return api.g.ParamDiff(fromNum, toNum), nil
+}
+
+// maxRecentChanges is the maximum count of governance_getRecentChanges.
+const maxRecentChanges = 100
+
+// GetRecentChanges returns the latest count changes of the effective parameters up to the latest block, the latest first.
+func (api *GovAPI) GetRecentChanges(count uint64) ([]RecentParamChange, error) {
+	if count == 0 || count > maxRecentChanges {
+		return nil, fmt.Errorf("%w: must be between 1 and %d", gov.ErrInvalidCount, maxRecentChanges)
+	}
+	return api.g.RecentParamChanges(api.g.Chain.CurrentBlock().NumberU64(), int(count)), nil
+}
+
+// GetAuditLog returns the governance audit records of the blocks in [from, to].
+func (api *GovAPI) GetAuditLog(from, to rpc.BlockNumber) ([]AuditRecord, error) {
+	if api.g.ChainKv == nil {
+		return nil, gov.ErrAuditLogDisabled
+	}
+	current := api.g.Chain.CurrentBlock().NumberU64()
+	resolve := func(num rpc.BlockNumber) uint64 {
+		if num == rpc.LatestBlockNumber || num == rpc.PendingBlockNumber {
+			return current
+		}
+		return uint64(num.Int64())
+	}
+
+	fromNum, toNum := resolve(from), resolve(to)
+	if fromNum > toNum {
+		return nil, gov.ErrInvalidBlockRange
+	}
+	return ReadAuditRecords(api.g.ChainKv, fromNum, toNum)
+}
+
+// ExportHistory returns the governance history up to the latest block.
+func (api *GovAPI) ExportHistory() (*gov.HistoryExport, error) {
+	return api.g.ExportHistory()
+}
+
+// ImportHistory indexes the header governance records of an exported history of this chain.
+func (api *GovAPI) ImportHistory(h gov.HistoryExport) (*ImportResult, error) {
+	return api.g.ImportHistory(&h)
+}
+
 func (api *GovAPI) NodeAddress() (common.Address, error) {
 	return api.g.Hgm.NodeAddress(), nil
 }
@@ -76,6 +158,7 @@ func getChainConfig(g *GovModule, num *rpc.BlockNumber) *params.ChainConfig {
 	config.EthTxTypeCompatibleBlock = latestConfig.EthTxTypeCompatibleBlock
 	config.MagmaCompatibleBlock = latestConfig.MagmaCompatibleBlock
 	config.KoreCompatibleBlock = latestConfig.KoreCompatibleBlock
+	config.ContractGovFromGenesis = latestConfig.ContractGovFromGenesis
 	config.ShanghaiCompatibleBlock = latestConfig.ShanghaiCompatibleBlock
 	config.CancunCompatibleBlock = latestConfig.CancunCompatibleBlock
 	config.KaiaCompatibleBlock = latestConfig.KaiaCompatibleBlock
@@ -116,32 +199,10 @@ func getParams(g *GovModule, num *rpc.BlockNumber) (gov.PartialParamSet, error)
 	}
 
 	gp := g.EffectiveParamSet(blockNumber)
-	ret := gp.ToMap()
-	// To avoid confusion, override some parameters that are deprecated after hardforks.
+	// To avoid confusion, override the parameters that are deprecated after hardforks.
 	// e.g., stakingupdateinterval is shown as 86400 but actually irrelevant (i.e. updated every block)
-	rule := g.Chain.Config().Rules(new(big.Int).SetUint64(blockNumber))
-	if rule.IsKore {
-		// Gini option deprecated since Kore, as All committee members have an equal chance
-		// of being elected block proposers.
-		if _, ok := ret[gov.RewardUseGiniCoeff]; ok {
-			ret[gov.RewardUseGiniCoeff] = false
-		}
-	}
-	if rule.IsRandao {
-		// Block proposer is randomly elected at every block with Randao,
-		// no more precalculated proposer list.
-		if _, ok := ret[gov.RewardProposerUpdateInterval]; ok {
-			ret[gov.RewardProposerUpdateInterval] = 1
-		}
-	}
-	if rule.IsKaia {
-		// Staking information updated every block since Kaia.
-		if _, ok := ret[gov.RewardStakingUpdateInterval]; ok {
-			ret[gov.RewardStakingUpdateInterval] = 1
-		}
-	}
-
-	return ret, nil
+	gp.ApplyDeprecations(g.Chain.Config(), blockNumber)
+	return gp.ToMap(), nil
 }
 
 func (api *KaiaAPI) NodeAddress() common.Address {
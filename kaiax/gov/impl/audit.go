@@ -0,0 +1,133 @@
+package impl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"sync"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/kaiax/gov"
+	"github.com/kaiachain/kaia/storage/database"
+)
+
+const (
+	AuditVoteReceived    = "voteReceived"    // a vote is included in a block
+	AuditVoteApplied     = "voteApplied"     // header governance is ratified at an epoch block
+	AuditParamsFinalized = "paramsFinalized" // the effective parameters change at the block
+	AuditContractRecord  = "contractRecord"  // a parameter record of the GovParam contract is read from the block
+)
+
+var (
+	auditLogPrefix  = []byte("governanceAuditLog")
+	auditLogSeqKey  = []byte("governanceAuditSeq")
+	auditLogWriteMu sync.Mutex
+)
+
+// AuditRecord is an entry of the governance audit log. Seq is unique and increases in the order
+// the records are written, so a block inserted twice (e.g. after a reorg) keeps both sets of records.
+type AuditRecord struct {
+	Seq uint64 `json:"seq"`
+	GovEvent
+}
+
+// auditLogKey = prefix || blockNum (8 bytes) || seq (8 bytes)
+func auditLogKey(blockNum, seq uint64) []byte {
+	key := make([]byte, len(auditLogPrefix)+16)
+	copy(key, auditLogPrefix)
+	binary.BigEndian.PutUint64(key[len(auditLogPrefix):], blockNum)
+	binary.BigEndian.PutUint64(key[len(auditLogPrefix)+8:], seq)
+	return key
+}
+
+func readAuditLogSeq(db database.Database) uint64 {
+	b, err := db.Get(auditLogSeqKey)
+	if err != nil || len(b) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+// WriteAuditRecords appends the records to the audit log, assigning their Seq.
+func WriteAuditRecords(db database.Database, records []AuditRecord) {
+	if len(records) == 0 {
+		return
+	}
+	auditLogWriteMu.Lock()
+	defer auditLogWriteMu.Unlock()
+
+	seq := readAuditLogSeq(db)
+	batch := db.NewBatch()
+	defer batch.Release()
+	for i := range records {
+		records[i].Seq = seq
+		b, err := json.Marshal(records[i])
+		if err != nil {
+			logger.Error("Failed to marshal governance audit record", "num", records[i].BlockNumber, "err", err)
+			continue
+		}
+		if err := batch.Put(auditLogKey(records[i].BlockNumber, seq), b); err != nil {
+			logger.Crit("Failed to write governance audit record", "err", err)
+		}
+		seq++
+	}
+	if err := batch.Put(auditLogSeqKey, binary.BigEndian.AppendUint64(nil, seq)); err != nil {
+		logger.Crit("Failed to write governance audit log seq", "err", err)
+	}
+	if err := batch.Write(); err != nil {
+		logger.Crit("Failed to write governance audit records", "err", err)
+	}
+}
+
+// ReadAuditRecords returns the audit records of the blocks in [from, to], ordered by block number and then by Seq.
+func ReadAuditRecords(db database.Database, from, to uint64) ([]AuditRecord, error) {
+	it := db.NewIterator(auditLogPrefix, binary.BigEndian.AppendUint64(nil, from))
+	defer it.Release()
+
+	ret := []AuditRecord{}
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(auditLogPrefix)+16 || !bytes.HasPrefix(key, auditLogPrefix) {
+			continue
+		}
+		if binary.BigEndian.Uint64(key[len(auditLogPrefix):]) > to {
+			break
+		}
+		// Decode numbers as json.Number so that big integers keep their precision.
+		var record AuditRecord
+		dec := json.NewDecoder(bytes.NewReader(it.Value()))
+		dec.UseNumber()
+		if err := dec.Decode(&record); err != nil {
+			logger.Error("Malformed governance audit record", "key", key, "err", err)
+			continue
+		}
+		ret = append(ret, record)
+	}
+	return ret, it.Error()
+}
+
+// auditRecords converts the governance events of the block into audit records, adding a
+// paramsFinalized record if the effective parameters of the block differ from those of its parent.
+func (m *GovModule) auditRecords(b *types.Block, events []GovEvent, changed gov.PartialParamSet) []AuditRecord {
+	var records []AuditRecord
+	for _, ev := range events {
+		switch ev.Type {
+		case GovEventVote:
+			ev.Type = AuditVoteReceived
+		case GovEventParamSet:
+			ev.Type = AuditVoteApplied
+		case GovEventContractParam:
+			ev.Type = AuditContractRecord
+		}
+		records = append(records, AuditRecord{GovEvent: ev})
+	}
+
+	if len(changed) > 0 {
+		num := b.NumberU64()
+		records = append(records, AuditRecord{GovEvent: GovEvent{
+			Type: AuditParamsFinalized, BlockNumber: num, BlockHash: b.Hash(),
+			Params: changed, EffectiveBlock: num,
+		}})
+	}
+	return records
+}
@@ -0,0 +1,111 @@
+package impl
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/gov"
+	"github.com/kaiachain/kaia/kaiax/gov/headergov"
+	blockchain_mock "github.com/kaiachain/kaia/kaiax/gov/impl/mock"
+	"github.com/kaiachain/kaia/networks/rpc"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog(t *testing.T) {
+	var (
+		config = &params.ChainConfig{
+			KoreCompatibleBlock: big.NewInt(0),
+			Istanbul:            &params.IstanbulConfig{Epoch: 1000},
+		}
+		hgm   = newHeaderGovModuleMock(t)
+		cgm   = newContractGovModuleMock(t)
+		chain = blockchain_mock.NewMockBlockChain(gomock.NewController(t))
+		db    = database.NewMemDB()
+		m     = NewGovModule()
+		voter = common.HexToAddress("0x1")
+	)
+	chain.EXPECT().Config().Return(config).AnyTimes()
+	chain.EXPECT().GetHeaderByNumber(gomock.Any()).Return(nil).AnyTimes()
+	chain.EXPECT().GetReceiptsByBlockHash(gomock.Any()).Return(nil).AnyTimes()
+	require.NoError(t, m.Init(&InitOpts{Hgm: hgm, Cgm: cgm, Chain: chain, ChainKv: db}))
+
+	// The unit price changes from 25 to 100 at block 2000.
+	hgm.EXPECT().PostInsertBlock(gomock.Any()).Return(nil).AnyTimes()
+	hgm.EXPECT().EffectiveParamsPartial(gomock.Any()).DoAndReturn(func(num uint64) gov.PartialParamSet {
+		if num < 2000 {
+			return gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(25)}
+		}
+		return gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(100)}
+	}).AnyTimes()
+	cgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(nil).AnyTimes()
+
+	voteBytes, _ := headergov.NewVoteData(voter, string(gov.GovernanceUnitPrice), uint64(100)).ToVoteBytes()
+	govBytes, _ := headergov.NewGovData(gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(100)}).ToGovBytes()
+	blocks := []*types.Block{
+		types.NewBlockWithHeader(&types.Header{Number: big.NewInt(999), Vote: voteBytes}),
+		types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1000), Governance: govBytes}),
+		types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1001)}),
+		types.NewBlockWithHeader(&types.Header{Number: big.NewInt(2000)}),
+	}
+	for _, b := range blocks {
+		require.NoError(t, m.PostInsertBlock(b))
+	}
+
+	records, err := ReadAuditRecords(db, 0, 3000)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	assert.Equal(t, uint64(0), records[0].Seq)
+	assert.Equal(t, AuditVoteReceived, records[0].Type)
+	assert.Equal(t, uint64(999), records[0].BlockNumber)
+	assert.Equal(t, blocks[0].Hash(), records[0].BlockHash)
+	assert.Equal(t, &voter, records[0].Voter)
+	assert.Equal(t, json.Number("100"), records[0].Value)
+
+	assert.Equal(t, uint64(1), records[1].Seq)
+	assert.Equal(t, AuditVoteApplied, records[1].Type)
+	assert.Equal(t, uint64(2000), records[1].EffectiveBlock)
+
+	assert.Equal(t, uint64(2), records[2].Seq)
+	assert.Equal(t, AuditParamsFinalized, records[2].Type)
+	assert.Equal(t, uint64(2000), records[2].BlockNumber)
+	assert.Equal(t, gov.PartialParamSet{gov.GovernanceUnitPrice: json.Number("100")}, records[2].Params)
+
+	// Range queries are inclusive.
+	records, err = ReadAuditRecords(db, 1000, 1999)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, AuditVoteApplied, records[0].Type)
+
+	records, err = ReadAuditRecords(db, 1001, 1999)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	// A re-inserted block appends new records instead of overwriting.
+	require.NoError(t, m.PostInsertBlock(blocks[0]))
+	records, err = ReadAuditRecords(db, 999, 999)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, []uint64{0, 3}, []uint64{records[0].Seq, records[1].Seq})
+
+	// API
+	chain.EXPECT().CurrentBlock().Return(blocks[3]).AnyTimes()
+	api := NewGovAPI(m)
+	records, err = api.GetAuditLog(0, rpc.LatestBlockNumber)
+	require.NoError(t, err)
+	assert.Len(t, records, 4)
+
+	_, err = api.GetAuditLog(10, 5)
+	assert.ErrorIs(t, err, gov.ErrInvalidBlockRange)
+
+	m.ChainKv = nil
+	_, err = api.GetAuditLog(0, rpc.LatestBlockNumber)
+	assert.ErrorIs(t, err, gov.ErrAuditLogDisabled)
+}
@@ -0,0 +1,38 @@
+package impl
+
+import (
+	"math/big"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/kaiax/gov"
+)
+
+// BlobBaseFee returns the blob base fee at the given block, or nil before the blob tx hardfork.
+// The state transition charges the blob gas at this price, and the BLOBBASEFEE opcode returns it.
+func (m *GovModule) BlobBaseFee(num uint64) *big.Int {
+	if !m.Chain.Config().IsBlobTxForkEnabled(new(big.Int).SetUint64(num)) {
+		return nil
+	}
+	ps := m.EffectiveParamSet(num)
+	return new(big.Int).SetUint64(ps.BlobBaseFee)
+}
+
+// MaxBlobGas implements work.BlobGasLimiter.
+func (m *GovModule) MaxBlobGas(num uint64) uint64 {
+	if !m.Chain.Config().IsBlobTxForkEnabled(new(big.Int).SetUint64(num)) {
+		return 0
+	}
+	ps := m.EffectiveParamSet(num)
+	return ps.MaxBlobGasPerBlock
+}
+
+func (m *GovModule) checkBlobTx(num uint64, tx *types.Transaction) error {
+	if tx.Type() != types.TxTypeEthereumBlob {
+		return nil
+	}
+	if !m.Chain.Config().IsBlobTxForkEnabled(new(big.Int).SetUint64(num)) {
+		return gov.ErrBlobTxDisabled
+	}
+	ps := m.EffectiveParamSet(num)
+	return ps.CheckBlobTx(tx)
+}
@@ -0,0 +1,70 @@
+package impl
+
+import (
+	"math/big"
+	"testing"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/blockchain/vm"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/gov"
+	"github.com/kaiachain/kaia/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreRunTxBlob(t *testing.T) {
+	var (
+		blobParams = gov.PartialParamSet{
+			gov.BlobTxBaseFee:        uint64(10),
+			gov.BlobTxMaxGasPerBlock: uint64(2 * params.BlobTxBlobGasPerBlob),
+		}
+	)
+	tx, err := types.NewTransactionWithMap(types.TxTypeEthereumBlob, map[types.TxValueKeyType]interface{}{
+		types.TxValueKeyChainID:    big.NewInt(1),
+		types.TxValueKeyNonce:      uint64(0),
+		types.TxValueKeyTo:         common.HexToAddress("0x0000000000000000000000000000000000001234"),
+		types.TxValueKeyAmount:     big.NewInt(0),
+		types.TxValueKeyData:       []byte{},
+		types.TxValueKeyGasLimit:   uint64(21000),
+		types.TxValueKeyGasFeeCap:  big.NewInt(1),
+		types.TxValueKeyGasTipCap:  big.NewInt(1),
+		types.TxValueKeyAccessList: types.AccessList{},
+		types.TxValueKeyBlobFeeCap: big.NewInt(10),
+		types.TxValueKeyBlobHashes: []common.Hash{{types.BlobTxHashVersion}},
+	})
+	require.NoError(t, err)
+	evm := &vm.EVM{Context: vm.BlockContext{BlockNumber: big.NewInt(1)}}
+
+	t.Run("fork disabled", func(t *testing.T) {
+		_, _, m := newGovModuleMock(t, &params.ChainConfig{})
+		_, err := m.PreRunTx(evm, tx)
+		assert.ErrorIs(t, err, gov.ErrBlobTxDisabled)
+		assert.Nil(t, m.BlobBaseFee(1))
+		assert.Zero(t, m.MaxBlobGas(1))
+	})
+
+	t.Run("fork enabled", func(t *testing.T) {
+		hgm, cgm, m := newGovModuleMock(t, &params.ChainConfig{BlobTxCompatibleBlock: big.NewInt(0)})
+		hgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(blobParams).AnyTimes()
+		cgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(nil).AnyTimes()
+
+		_, err := m.PreRunTx(evm, tx)
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(10), m.BlobBaseFee(1))
+		assert.Equal(t, uint64(2*params.BlobTxBlobGasPerBlob), m.MaxBlobGas(1))
+	})
+
+	t.Run("fee cap too low", func(t *testing.T) {
+		hgm, cgm, m := newGovModuleMock(t, &params.ChainConfig{BlobTxCompatibleBlock: big.NewInt(0)})
+		hgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(gov.PartialParamSet{
+			gov.BlobTxBaseFee:        uint64(11),
+			gov.BlobTxMaxGasPerBlock: uint64(2 * params.BlobTxBlobGasPerBlob),
+		}).AnyTimes()
+		cgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(nil).AnyTimes()
+
+		_, err := m.PreRunTx(evm, tx)
+		assert.ErrorIs(t, err, gov.ErrBlobFeeCapTooLow)
+	})
+}
@@ -14,5 +14,11 @@ func (g *GovModule) PrepareHeader(header *types.Header) error {
 }
 
 func (g *GovModule) FinalizeHeader(header *types.Header, state *state.StateDB, txs []*types.Transaction, receipts []*types.Receipt) error {
+	if g.Chain.Config().IsBlobTxForkEnabled(header.Number) {
+		ps := g.EffectiveParamSet(header.Number.Uint64())
+		if err := ps.CheckBlockBlobGas(txs); err != nil {
+			return err
+		}
+	}
 	return g.Hgm.FinalizeHeader(header, state, txs, receipts)
 }
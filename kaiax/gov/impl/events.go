@@ -0,0 +1,191 @@
+package impl
+
+import (
+	"context"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+	govcontract "github.com/kaiachain/kaia/contracts/contracts/system_contracts/gov"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/event"
+	"github.com/kaiachain/kaia/kaiax/gov"
+	"github.com/kaiachain/kaia/kaiax/gov/headergov"
+	"github.com/kaiachain/kaia/networks/rpc"
+)
+
+const (
+	GovEventVote          = "vote"          // a governance vote is included in a block
+	GovEventParamSet      = "paramSet"      // header governance ratified at an epoch block
+	GovEventContractParam = "contractParam" // a parameter is written to the GovParam contract
+
+	govEventChanSize = 64
+)
+
+// setParamEventID is the topic of the SetParam event of the GovParam contract.
+var setParamEventID = crypto.Keccak256Hash([]byte("SetParam(string,bool,bytes,uint256)"))
+
+// GovEvent is a governance event pushed to the subscribers of the governance events.
+// The fields are set depending on Type.
+type GovEvent struct {
+	Type        string      `json:"type"`
+	BlockNumber uint64      `json:"blockNumber"`
+	BlockHash   common.Hash `json:"blockHash"`
+
+	// vote and contractParam
+	Name       string `json:"name,omitempty"`
+	Value      any    `json:"value,omitempty"`
+	Activation uint64 `json:"activation,omitempty"`
+
+	// vote
+	Voter *common.Address `json:"voter,omitempty"`
+
+	// vote and paramSet
+	Emergency bool `json:"emergency,omitempty"`
+
+	// paramSet
+	Params         gov.PartialParamSet            `json:"params,omitempty"`
+	Scheduled      map[uint64]gov.PartialParamSet `json:"scheduled,omitempty"`
+	EffectiveBlock uint64                         `json:"effectiveBlock,omitempty"`
+
+	// contractParam
+	Exists *bool        `json:"exists,omitempty"`
+	TxHash *common.Hash `json:"txHash,omitempty"`
+}
+
+// SubscribeGovEvents registers a subscription of the governance events.
+func (m *GovModule) SubscribeGovEvents(ch chan<- GovEvent) event.Subscription {
+	return m.govEventScope.Track(m.govEventFeed.Subscribe(ch))
+}
+
+// sendGovEvents sends the governance events of the inserted block if anyone is subscribing.
+func (m *GovModule) sendGovEvents(events []GovEvent) {
+	if m.govEventScope.Count() == 0 {
+		return
+	}
+	for _, ev := range events {
+		m.govEventFeed.Send(ev)
+	}
+}
+
+func (m *GovModule) govEvents(b *types.Block) []GovEvent {
+	var (
+		header = b.Header()
+		num    = b.NumberU64()
+		hash   = b.Hash()
+		events []GovEvent
+	)
+
+	if len(header.Vote) > 0 {
+		if vote, err := headergov.VoteBytes(header.Vote).ToVoteData(); err == nil {
+			if _, ok := gov.Params[vote.Name()]; ok {
+				voter := vote.Voter()
+				events = append(events, GovEvent{
+					Type: GovEventVote, BlockNumber: num, BlockHash: hash,
+					Name: string(vote.Name()), Value: vote.Value(), Activation: vote.ActivationBlock(), Voter: &voter,
+					Emergency: vote.Emergency(),
+				})
+			}
+		}
+	}
+
+	if len(header.Governance) > 0 {
+		if g, err := headergov.GovBytes(header.Governance).ToGovData(); err == nil {
+			if len(g.Items()) > 0 || len(g.Scheduled()) > 0 {
+				effective := num + m.Chain.Config().Istanbul.Epoch
+				if !m.isKoreHF(effective) {
+					effective++ // before Kore, a change takes effect one block after the epoch start
+				}
+				events = append(events, GovEvent{
+					Type: GovEventParamSet, BlockNumber: num, BlockHash: hash,
+					Params: g.Items(), Scheduled: g.Scheduled(), EffectiveBlock: effective,
+				})
+			}
+			if len(g.Emergency()) > 0 {
+				events = append(events, GovEvent{
+					Type: GovEventParamSet, BlockNumber: num, BlockHash: hash,
+					Params: g.Emergency(), EffectiveBlock: num + 1, Emergency: true,
+				})
+			}
+		}
+	}
+
+	if m.isContractGovEnabled(num) {
+		events = append(events, m.contractParamEvents(b)...)
+	}
+	return events
+}
+
+// contractParamEvents returns the SetParam events emitted by the GovParam contract in the block.
+func (m *GovModule) contractParamEvents(b *types.Block) []GovEvent {
+	receipts := m.Chain.GetReceiptsByBlockHash(b.Hash())
+	if len(receipts) == 0 {
+		return nil
+	}
+	addr := m.EffectiveParamSet(b.NumberU64()).GovParamContract
+	if common.EmptyAddress(addr) {
+		return nil
+	}
+	filterer, err := govcontract.NewGovParamFilterer(addr, nil)
+	if err != nil {
+		return nil
+	}
+
+	var events []GovEvent
+	for _, receipt := range receipts {
+		for _, log := range receipt.Logs {
+			if log.Address != addr || len(log.Topics) == 0 || log.Topics[0] != setParamEventID {
+				continue
+			}
+			ev, err := filterer.ParseSetParam(*log)
+			if err != nil {
+				logger.Warn("Failed to parse SetParam event", "num", b.NumberU64(), "err", err)
+				continue
+			}
+
+			// Decode the value if it is a valid parameter, otherwise leave it as raw bytes.
+			var value any = hexutil.Bytes(ev.Value)
+			if ps := make(gov.PartialParamSet); ps.Add(ev.Name, ev.Value) == nil {
+				value = ps[gov.ParamName(ev.Name)]
+			}
+			exists, txHash := ev.Exists, log.TxHash
+			events = append(events, GovEvent{
+				Type: GovEventContractParam, BlockNumber: b.NumberU64(), BlockHash: b.Hash(),
+				Name: ev.Name, Value: value, Activation: ev.Activation.Uint64(), Exists: &exists, TxHash: &txHash,
+			})
+		}
+	}
+	return events
+}
+
+// Governance creates a subscription that is notified of the votes, the header governance ratified
+// at epoch blocks and the parameters written to the GovParam contract.
+func (api *KaiaAPI) Governance(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		events := make(chan GovEvent, govEventChanSize)
+		sub := api.g.SubscribeGovEvents(events)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-events:
+				notifier.Notify(rpcSub.ID, ev)
+			case <-sub.Err():
+				return
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
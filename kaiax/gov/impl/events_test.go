@@ -0,0 +1,85 @@
+package impl
+
+import (
+	"math/big"
+	"testing"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	govcontract "github.com/kaiachain/kaia/contracts/contracts/system_contracts/gov"
+	"github.com/kaiachain/kaia/kaiax/gov"
+	"github.com/kaiachain/kaia/kaiax/gov/headergov"
+	blockchain_mock "github.com/kaiachain/kaia/kaiax/gov/impl/mock"
+	"github.com/kaiachain/kaia/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGovEvents(t *testing.T) {
+	var (
+		config = &params.ChainConfig{
+			KoreCompatibleBlock: big.NewInt(0),
+			Istanbul:            &params.IstanbulConfig{Epoch: 1000},
+		}
+		hgm      = newHeaderGovModuleMock(t)
+		cgm      = newContractGovModuleMock(t)
+		chain    = blockchain_mock.NewMockBlockChain(gomock.NewController(t))
+		m        = NewGovModule()
+		voter    = common.HexToAddress("0x1")
+		govParam = common.HexToAddress("0x400")
+	)
+	chain.EXPECT().Config().Return(config).AnyTimes()
+	chain.EXPECT().GetHeaderByNumber(gomock.Any()).Return(nil).AnyTimes()
+	require.NoError(t, m.Init(&InitOpts{Hgm: hgm, Cgm: cgm, Chain: chain}))
+
+	parsed, err := govcontract.GovParamMetaData.GetAbi()
+	require.NoError(t, err)
+	assert.Equal(t, parsed.Events["SetParam"].ID, setParamEventID)
+	data, err := parsed.Events["SetParam"].Inputs.Pack("governance.unitprice", true, common.BigToHash(big.NewInt(50)).Bytes()[24:], big.NewInt(2000))
+	require.NoError(t, err)
+
+	voteBytes, _ := headergov.NewVoteData(voter, string(gov.GovernanceUnitPrice), uint64(100)).ToVoteBytes()
+	govBytes, _ := headergov.NewGovData(gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(100)}).ToGovBytes()
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1000), Vote: voteBytes, Governance: govBytes})
+	txHash := common.HexToHash("0xabcd")
+	receipts := types.Receipts{{Logs: []*types.Log{
+		{Address: govParam, Topics: []common.Hash{setParamEventID}, Data: data, TxHash: txHash},
+		{Address: common.HexToAddress("0x500"), Topics: []common.Hash{setParamEventID}, Data: data, TxHash: txHash},
+	}}}
+
+	hgm.EXPECT().PostInsertBlock(block).Return(nil)
+	hgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(gov.PartialParamSet{gov.GovernanceGovParamContract: govParam})
+	cgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(nil)
+	chain.EXPECT().GetReceiptsByBlockHash(block.Hash()).Return(receipts)
+
+	events := make(chan GovEvent, govEventChanSize)
+	sub := m.SubscribeGovEvents(events)
+	defer sub.Unsubscribe()
+	require.NoError(t, m.PostInsertBlock(block))
+
+	exists := true
+	expected := []GovEvent{
+		{
+			Type: GovEventVote, BlockNumber: 1000, BlockHash: block.Hash(),
+			Name: string(gov.GovernanceUnitPrice), Value: uint64(100), Voter: &voter,
+		},
+		{
+			Type: GovEventParamSet, BlockNumber: 1000, BlockHash: block.Hash(),
+			Params: gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(100)}, EffectiveBlock: 2000,
+		},
+		{
+			Type: GovEventContractParam, BlockNumber: 1000, BlockHash: block.Hash(),
+			Name: string(gov.GovernanceUnitPrice), Value: uint64(50), Activation: 2000, Exists: &exists, TxHash: &txHash,
+		},
+	}
+	for _, want := range expected {
+		assert.Equal(t, want, <-events)
+	}
+	assert.Empty(t, events)
+
+	// Nothing is computed without subscribers.
+	sub.Unsubscribe()
+	hgm.EXPECT().PostInsertBlock(block).Return(nil)
+	require.NoError(t, m.PostInsertBlock(block))
+}
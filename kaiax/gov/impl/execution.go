@@ -2,8 +2,33 @@ package impl
 
 import (
 	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/kaiax/gov"
+	metricutils "github.com/kaiachain/kaia/metrics/utils"
 )
 
 func (g *GovModule) PostInsertBlock(b *types.Block) error {
-	return g.Hgm.PostInsertBlock(b)
+	if err := g.Hgm.PostInsertBlock(b); err != nil {
+		return err
+	}
+	// The param set of the next block may have been cached before Hgm handled this block.
+	g.paramSetGen.Add(1)
+	g.paramSetCache.Remove(b.NumberU64() + 1)
+
+	var changed gov.PartialParamSet
+	if g.ChainKv != nil || metricutils.Enabled {
+		changed = g.paramsChangedAt(b.NumberU64())
+	}
+	if metricutils.Enabled {
+		g.updateParamChangeMetrics(b.NumberU64(), changed)
+	}
+
+	var events []GovEvent
+	if g.ChainKv != nil || g.govEventScope.Count() > 0 {
+		events = g.govEvents(b)
+	}
+	if g.ChainKv != nil {
+		WriteAuditRecords(g.ChainKv, g.auditRecords(b, events, changed))
+	}
+	g.sendGovEvents(events)
+	return nil
 }
@@ -1,21 +1,317 @@
 package impl
 
-import "github.com/kaiachain/kaia/kaiax/gov"
+import (
+	"errors"
+	"maps"
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/gov"
+)
+
+// ParamChange is a change of an effective parameter value at Block.
+type ParamChange struct {
+	Block uint64 `json:"block"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+type cachedParamSet struct {
+	parentHash common.Hash
+	ps         gov.ParamSet
+}
+
+// EffectiveParamSet caches the param sets of the blocks whose parent is in the chain, because the
+// params of such blocks are final. An entry is valid only while the parent is still canonical,
+// so that the cache survives a reorg.
 func (m *GovModule) EffectiveParamSet(blockNum uint64) gov.ParamSet {
-	ret := gov.GetDefaultGovernanceParamSet()
+	defer paramSetTimer.UpdateSince(time.Now())
 
-	p1 := m.Hgm.EffectiveParamsPartial(blockNum)
-	for k, v := range p1 {
-		ret.Set(k, v)
+	var parentHash common.Hash
+	if blockNum > 0 {
+		if parent := m.Chain.GetHeaderByNumber(blockNum - 1); parent != nil {
+			parentHash = parent.Hash()
+		}
 	}
+	if common.EmptyHash(parentHash) {
+		paramSetCacheMissCounter.Inc(1)
+		return m.simulateParamSet(blockNum, nil)
+	}
+
+	if cached, ok := m.paramSetCache.Get(blockNum); ok && cached.(*cachedParamSet).parentHash == parentHash {
+		paramSetCacheHitCounter.Inc(1)
+		return copyParamSet(cached.(*cachedParamSet).ps)
+	}
+	paramSetCacheMissCounter.Inc(1)
 
-	if m.isKoreHF(blockNum) {
-		p2 := m.Cgm.EffectiveParamsPartial(blockNum)
-		for k, v := range p2 {
+	gen := m.paramSetGen.Load()
+	ps := m.simulateParamSet(blockNum, nil)
+	if m.paramSetGen.Load() == gen {
+		m.paramSetCache.Add(blockNum, &cachedParamSet{parentHash: parentHash, ps: copyParamSet(ps)})
+	}
+	return ps
+}
+
+func (m *GovModule) purgeParamSetCache() {
+	m.paramSetGen.Add(1)
+	m.paramSetCache.Purge()
+}
+
+// copyParamSet deep-copies the big.Int fields so that callers cannot modify the cached entry.
+func copyParamSet(ps gov.ParamSet) gov.ParamSet {
+	if ps.MintingAmount != nil {
+		ps.MintingAmount = new(big.Int).Set(ps.MintingAmount)
+	}
+	if ps.MinimumStake != nil {
+		ps.MinimumStake = new(big.Int).Set(ps.MinimumStake)
+	}
+	return ps
+}
+
+// ParamSource tells which source supplied the effective value of a parameter.
+type ParamSource string
+
+const (
+	ParamSourceDefault  ParamSource = "default"  // the default value of the parameter
+	ParamSourceHeader   ParamSource = "header"   // header governance, including the genesis config
+	ParamSourceContract ParamSource = "contract" // the GovParam contract
+	ParamSourceRegistry ParamSource = "registry" // the param registry contract in the chain config
+)
+
+type paramSource struct {
+	name    ParamSource
+	source  gov.ParamSource
+	enabled func(blockNum uint64) bool // nil if always enabled
+}
+
+// RegisterParamSource adds a source of the parameters taking precedence over the sources registered
+// before it, including header and contract governance. A parameter of the source is ignored if it
+// violates a parameter dependency. The source is started and stopped by its owner, not by GovModule.
+// It must be called after Init and before Start.
+func (m *GovModule) RegisterParamSource(name ParamSource, source gov.ParamSource) {
+	m.paramSources = append(m.paramSources, paramSource{name: name, source: source})
+	m.purgeParamSetCache()
+}
+
+// EffectiveParamSources returns the effective parameter set at blockNum along with the source of each value.
+func (m *GovModule) EffectiveParamSources(blockNum uint64) (gov.ParamSet, map[gov.ParamName]ParamSource) {
+	return m.simulateParamSetWithSources(blockNum, nil)
+}
+
+// simulateParamSet returns the effective parameter set as if the overrides had been
+// applied by header governance. Hence contract governance still takes precedence once enabled.
+func (m *GovModule) simulateParamSet(blockNum uint64, overrides gov.PartialParamSet) gov.ParamSet {
+	ps, _ := m.simulateParamSetWithSources(blockNum, overrides)
+	return ps
+}
+
+func (m *GovModule) simulateParamSetWithSources(blockNum uint64, overrides gov.PartialParamSet) (gov.ParamSet, map[gov.ParamName]ParamSource) {
+	var (
+		ret     = gov.GetDefaultGovernanceParamSet()
+		sources = make(map[gov.ParamName]ParamSource)
+	)
+	for name := range gov.Params {
+		sources[name] = ParamSourceDefault
+	}
+
+	// The first source, header governance, is the base of the others and is applied without the dependency check.
+	for i, s := range m.paramSources {
+		if s.enabled != nil && !s.enabled(blockNum) {
+			continue
+		}
+		var p gov.PartialParamSet
+		if i == 0 {
+			p = s.source.EffectiveParamsPartial(blockNum)
+		} else {
+			p = m.paramsConsistentWith(blockNum, *ret, s.source.EffectiveParamsPartial(blockNum), s.name)
+		}
+		for k, v := range p {
 			ret.Set(k, v)
+			sources[k] = s.name
+		}
+		if i == 0 {
+			for k, v := range overrides {
+				ret.Set(k, v)
+				sources[k] = s.name
+			}
+		}
+	}
+	return *ret, sources
+}
+
+// paramsConsistentWith returns the parameters p of the source at blockNum,
+// excluding those violating a parameter dependency when applied on top of base.
+func (m *GovModule) paramsConsistentWith(blockNum uint64, base gov.ParamSet, p gov.PartialParamSet, source ParamSource) gov.PartialParamSet {
+	cloned := false
+	for {
+		var perr *gov.ParamError
+		if err := gov.CheckDependencies(base, p); !errors.As(err, &perr) {
+			return p
+		}
+		logger.Warn("Ignoring governance parameter", "source", source, "num", blockNum, "err", perr)
+		if !cloned {
+			p, cloned = maps.Clone(p), true
 		}
+		delete(p, perr.Name)
 	}
+}
 
-	return *ret
+// EffectiveParamSetRange returns the effective parameter sets of [from, to] in ascending order,
+// one per run of blocks sharing the same set. Only the blocks at which a change may take effect
+// are evaluated, so the cost does not depend on the length of the range.
+func (m *GovModule) EffectiveParamSetRange(from, to uint64) []gov.ParamSetRange {
+	if from > to {
+		return nil
+	}
+	ret := []gov.ParamSetRange{{From: from, To: to, ParamSet: m.EffectiveParamSet(from)}}
+	for _, num := range m.paramChangeCandidates(from, to) {
+		ps := m.EffectiveParamSet(num)
+		last := &ret[len(ret)-1]
+		if paramSetEqual(last.ParamSet, ps) {
+			continue
+		}
+		last.To = num - 1
+		ret = append(ret, gov.ParamSetRange{From: num, To: to, ParamSet: ps})
+	}
+	return ret
+}
+
+// ParamDiff returns the parameters whose effective values changed in (from, to],
+// along with every change in ascending block order.
+func (m *GovModule) ParamDiff(from, to uint64) map[gov.ParamName][]ParamChange {
+	ret := make(map[gov.ParamName][]ParamChange)
+	prevSet := m.EffectiveParamSet(from)
+	prev := prevSet.ToMap()
+	for _, num := range m.paramChangeCandidates(from, to) {
+		curSet := m.EffectiveParamSet(num)
+		cur := curSet.ToMap()
+		for name, value := range cur {
+			if !paramValueEqual(prev[name], value) {
+				ret[name] = append(ret[name], ParamChange{Block: num, From: prev[name], To: value})
+			}
+		}
+		prev = cur
+	}
+	return ret
+}
+
+// RecentParamChange is a change of an effective parameter value along with the source of the new value.
+type RecentParamChange struct {
+	Key             gov.ParamName `json:"key"`
+	OldValue        any           `json:"oldValue"`
+	NewValue        any           `json:"newValue"`
+	ActivationBlock uint64        `json:"activationBlock"`
+	Source          ParamSource   `json:"source"`
+}
+
+// RecentParamChanges returns the latest count changes of the effective parameters up to num, the latest first.
+// The changes at the same block are sorted by key. The changes of the sources that do not report
+// their change blocks, such as the param registry, are not found.
+func (m *GovModule) RecentParamChanges(num uint64, count int) []RecentParamChange {
+	ret := []RecentParamChange{}
+	candidates := m.paramChangeCandidates(0, num)
+	for i := len(candidates) - 1; i >= 0 && len(ret) < count; i-- {
+		at := candidates[i]
+		prevSet := m.EffectiveParamSet(at - 1)
+		curSet, sources := m.EffectiveParamSources(at)
+		prev := prevSet.ToMap()
+
+		var changes []RecentParamChange
+		for name, value := range curSet.ToMap() {
+			if !paramValueEqual(prev[name], value) {
+				changes = append(changes, RecentParamChange{
+					Key: name, OldValue: prev[name], NewValue: value, ActivationBlock: at, Source: sources[name],
+				})
+			}
+		}
+		slices.SortFunc(changes, func(a, b RecentParamChange) int {
+			return strings.Compare(string(a.Key), string(b.Key))
+		})
+		ret = append(ret, changes...)
+	}
+	if len(ret) > count {
+		ret = ret[:count]
+	}
+	return ret
+}
+
+// paramsChangedAt returns the parameters whose effective values at num differ from those at num-1.
+func (m *GovModule) paramsChangedAt(num uint64) gov.PartialParamSet {
+	changed := make(gov.PartialParamSet)
+	if num == 0 {
+		return changed
+	}
+	prevSet, curSet := m.EffectiveParamSet(num-1), m.EffectiveParamSet(num)
+	prev := prevSet.ToMap()
+	for name, value := range curSet.ToMap() {
+		if !paramValueEqual(prev[name], value) {
+			changed[name] = value
+		}
+	}
+	return changed
+}
+
+// latestParamChange returns the latest block up to num where the effective parameters changed, or 0 if none.
+func (m *GovModule) latestParamChange(num uint64) uint64 {
+	candidates := m.paramChangeCandidates(0, num)
+	for i := len(candidates) - 1; i >= 0; i-- {
+		if len(m.paramsChangedAt(candidates[i])) > 0 {
+			return candidates[i]
+		}
+	}
+	return 0
+}
+
+// updateParamChangeMetrics records the change of the effective parameters at num, if any,
+// and updates the number of epochs since the latest change.
+func (m *GovModule) updateParamChangeMetrics(num uint64, changed gov.PartialParamSet) {
+	if len(changed) > 0 {
+		m.lastParamChange.Store(num)
+	}
+	istanbul := m.Chain.Config().Istanbul
+	if istanbul == nil || istanbul.Epoch == 0 {
+		return
+	}
+	if last := m.lastParamChange.Load(); num >= last {
+		epochsSinceParamChangeGauge.Update(int64((num - last) / istanbul.Epoch))
+	}
+}
+
+// paramChangeCandidates returns the blocks in (from, to] at which the effective parameters
+// may change, in ascending order.
+func (m *GovModule) paramChangeCandidates(from, to uint64) []uint64 {
+	var candidates []uint64
+	for _, s := range m.paramSources {
+		candidates = append(candidates, s.source.ParamChangeBlocks(from, to)...)
+	}
+	if kore := m.Chain.Config().KoreCompatibleBlock; kore != nil && kore.IsUint64() {
+		if num := kore.Uint64(); from < num && num <= to {
+			candidates = append(candidates, num)
+		}
+	}
+	slices.Sort(candidates)
+	return slices.Compact(candidates)
+}
+
+func paramSetEqual(a, b gov.ParamSet) bool {
+	am, bm := a.ToMap(), b.ToMap()
+	for name, value := range am {
+		if !paramValueEqual(value, bm[name]) {
+			return false
+		}
+	}
+	return true
+}
+
+func paramValueEqual(a, b any) bool {
+	if x, ok := a.(*big.Int); ok {
+		if y, ok := b.(*big.Int); ok && x != nil && y != nil {
+			return x.Cmp(y) == 0
+		}
+	}
+	return reflect.DeepEqual(a, b)
 }
@@ -5,12 +5,17 @@ import (
 	"testing"
 
 	gomock "github.com/golang/mock/gomock"
+	"github.com/kaiachain/kaia/blockchain/types"
 	"github.com/kaiachain/kaia/kaiax/gov"
 	contractgov_mock "github.com/kaiachain/kaia/kaiax/gov/contractgov/mock"
 	headergov_mock "github.com/kaiachain/kaia/kaiax/gov/headergov/mock"
 	blockchain_mock "github.com/kaiachain/kaia/kaiax/gov/impl/mock"
+	registrygov_mock "github.com/kaiachain/kaia/kaiax/gov/registrygov/mock"
+	metricutils "github.com/kaiachain/kaia/metrics/utils"
+	"github.com/kaiachain/kaia/networks/rpc"
 	"github.com/kaiachain/kaia/params"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func newHeaderGovModuleMock(t *testing.T) *headergov_mock.MockHeaderGovModule {
@@ -28,6 +33,7 @@ func newGovModuleMock(t *testing.T, config *params.ChainConfig) (*headergov_mock
 	cgm := newContractGovModuleMock(t)
 	chain := blockchain_mock.NewMockBlockChain(gomock.NewController(t))
 	chain.EXPECT().Config().Return(config).AnyTimes()
+	chain.EXPECT().GetHeaderByNumber(gomock.Any()).Return(nil).AnyTimes() // disables the param set cache
 	m := NewGovModule()
 	m.Init(&InitOpts{
 		Hgm:   hgm,
@@ -93,3 +99,414 @@ func TestEffectiveParamSet(t *testing.T) {
 		})
 	})
 }
+
+func TestEffectiveParamSetDependency(t *testing.T) {
+	hgm, cgm, m := newGovModuleMock(t, &params.ChainConfig{KoreCompatibleBlock: big.NewInt(0)})
+
+	hgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(gov.PartialParamSet{
+		gov.Kip71LowerBoundBaseFee: uint64(1),
+		gov.Kip71UpperBoundBaseFee: uint64(100),
+	})
+	// The contract's lowerboundbasefee exceeds the upperboundbasefee, so it is ignored.
+	cgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(gov.PartialParamSet{
+		gov.Kip71LowerBoundBaseFee: uint64(200),
+		gov.GovernanceUnitPrice:    uint64(5),
+	})
+	ps := m.EffectiveParamSet(1)
+	assert.Equal(t, uint64(1), ps.LowerBoundBaseFee)
+	assert.Equal(t, uint64(100), ps.UpperBoundBaseFee)
+	assert.Equal(t, uint64(5), ps.UnitPrice)
+}
+
+func TestEffectiveParamSetCache(t *testing.T) {
+	var (
+		hgm     = newHeaderGovModuleMock(t)
+		cgm     = newContractGovModuleMock(t)
+		chain   = blockchain_mock.NewMockBlockChain(gomock.NewController(t))
+		m       = NewGovModule()
+		parentA = &types.Header{Number: big.NewInt(9), Extra: []byte{0xa}}
+		parentB = &types.Header{Number: big.NewInt(9), Extra: []byte{0xb}}
+	)
+	chain.EXPECT().Config().Return(&params.ChainConfig{}).AnyTimes()
+	require.NoError(t, m.Init(&InitOpts{Hgm: hgm, Cgm: cgm, Chain: chain}))
+
+	// Computed once, then served from the cache.
+	chain.EXPECT().GetHeaderByNumber(uint64(9)).Return(parentA).Times(2)
+	hgm.EXPECT().EffectiveParamsPartial(uint64(10)).Return(gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(1)}).Times(1)
+	assert.Equal(t, uint64(1), m.EffectiveParamSet(10).UnitPrice)
+	assert.Equal(t, uint64(1), m.EffectiveParamSet(10).UnitPrice)
+
+	// Returned values do not alias the cached entry.
+	chain.EXPECT().GetHeaderByNumber(uint64(9)).Return(parentA).Times(1)
+	m.EffectiveParamSet(10).MintingAmount.SetUint64(123)
+
+	// A reorg replaces the parent, so the entry is recomputed.
+	chain.EXPECT().GetHeaderByNumber(uint64(9)).Return(parentB).Times(1)
+	hgm.EXPECT().EffectiveParamsPartial(uint64(10)).Return(gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(2)}).Times(1)
+	ps := m.EffectiveParamSet(10)
+	assert.Equal(t, uint64(2), ps.UnitPrice)
+	assert.Equal(t, uint64(0), ps.MintingAmount.Uint64())
+
+	// Rewinding purges the cache.
+	hgm.EXPECT().RewindTo(gomock.Any())
+	m.RewindTo(types.NewBlockWithHeader(parentB))
+	chain.EXPECT().GetHeaderByNumber(uint64(9)).Return(parentB).Times(1)
+	hgm.EXPECT().EffectiveParamsPartial(uint64(10)).Return(gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(3)}).Times(1)
+	assert.Equal(t, uint64(3), m.EffectiveParamSet(10).UnitPrice)
+
+	// Inserting the parent invalidates the entry.
+	hgm.EXPECT().PostInsertBlock(gomock.Any()).Return(nil)
+	require.NoError(t, m.PostInsertBlock(types.NewBlockWithHeader(parentB)))
+	chain.EXPECT().GetHeaderByNumber(uint64(9)).Return(parentB).Times(1)
+	hgm.EXPECT().EffectiveParamsPartial(uint64(10)).Return(gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(4)}).Times(1)
+	assert.Equal(t, uint64(4), m.EffectiveParamSet(10).UnitPrice)
+
+	// Without the parent in the chain, nothing is cached.
+	chain.EXPECT().GetHeaderByNumber(uint64(10)).Return(nil).Times(2)
+	hgm.EXPECT().EffectiveParamsPartial(uint64(11)).Return(nil).Times(2)
+	m.EffectiveParamSet(11)
+	m.EffectiveParamSet(11)
+}
+
+func TestSimulateParamSet(t *testing.T) {
+	var (
+		headerGovVal   = uint64(123)
+		contractGovVal = uint64(456)
+		overrideVal    = uint64(789)
+		num            = rpc.BlockNumber(1)
+		overrides      = map[string]any{string(gov.GovernanceUnitPrice): float64(overrideVal)}
+	)
+
+	t.Run("pre-kore", func(t *testing.T) {
+		hgm, cgm, m := newGovModuleMock(t, &params.ChainConfig{KoreCompatibleBlock: nil})
+		hgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(gov.PartialParamSet{gov.GovernanceUnitPrice: headerGovVal})
+		cgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(gov.PartialParamSet{gov.GovernanceUnitPrice: contractGovVal}).AnyTimes()
+
+		ps, err := NewGovAPI(m).SimulateParamSet(overrides, &num)
+		assert.NoError(t, err)
+		assert.Equal(t, overrideVal, ps.UnitPrice)
+	})
+
+	t.Run("post-kore", func(t *testing.T) {
+		hgm, cgm, m := newGovModuleMock(t, &params.ChainConfig{KoreCompatibleBlock: big.NewInt(0)})
+		api := NewGovAPI(m)
+
+		hgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(gov.PartialParamSet{gov.GovernanceUnitPrice: headerGovVal})
+		cgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(gov.PartialParamSet{})
+		ps, err := api.SimulateParamSet(overrides, &num)
+		assert.NoError(t, err)
+		assert.Equal(t, overrideVal, ps.UnitPrice)
+
+		// contract governance takes precedence over the header governance vote.
+		hgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(gov.PartialParamSet{gov.GovernanceUnitPrice: headerGovVal})
+		cgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(gov.PartialParamSet{gov.GovernanceUnitPrice: contractGovVal})
+		ps, err = api.SimulateParamSet(overrides, &num)
+		assert.NoError(t, err)
+		assert.Equal(t, contractGovVal, ps.UnitPrice)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, _, m := newGovModuleMock(t, &params.ChainConfig{})
+		api := NewGovAPI(m)
+
+		_, err := api.SimulateParamSet(map[string]any{"governance.unknown": float64(1)}, &num)
+		assert.ErrorIs(t, err, gov.ErrInvalidParamName)
+		_, err = api.SimulateParamSet(map[string]any{string(gov.GovernanceUnitPrice): "abc"}, &num)
+		assert.Error(t, err)
+	})
+}
+
+func TestEffectiveParamSetDeprecation(t *testing.T) {
+	hgm, cgm, m := newGovModuleMock(t, &params.ChainConfig{KoreCompatibleBlock: big.NewInt(100), RandaoCompatibleBlock: big.NewInt(200)})
+
+	hgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(gov.PartialParamSet{
+		gov.RewardUseGiniCoeff:           true,
+		gov.RewardProposerUpdateInterval: uint64(3600),
+	}).AnyTimes()
+	cgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(nil).AnyTimes()
+
+	// The deprecations do not affect the effective parameter set.
+	ps := m.EffectiveParamSet(200)
+	assert.True(t, ps.UseGiniCoeff)
+	assert.Equal(t, uint64(3600), ps.ProposerUpdateInterval)
+
+	// The RPC APIs show the deprecated values.
+	for _, tc := range []struct {
+		num          rpc.BlockNumber
+		useGiniCoeff bool
+		interval     uint64
+	}{
+		{99, true, 3600},
+		{100, false, 3600},
+		{200, false, 1},
+	} {
+		num := tc.num
+		p, err := getParams(m, &num)
+		require.NoError(t, err)
+		assert.Equal(t, tc.useGiniCoeff, p[gov.RewardUseGiniCoeff], num)
+		assert.Equal(t, tc.interval, p[gov.RewardProposerUpdateInterval], num)
+	}
+}
+
+func TestEffectiveParamSources(t *testing.T) {
+	hgm, cgm, m := newGovModuleMock(t, &params.ChainConfig{KoreCompatibleBlock: big.NewInt(100)})
+
+	hgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(gov.PartialParamSet{
+		gov.GovernanceUnitPrice: uint64(123),
+		gov.IstanbulEpoch:       uint64(1000),
+		gov.RewardUseGiniCoeff:  true,
+	}).AnyTimes()
+	cgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(gov.PartialParamSet{
+		gov.GovernanceUnitPrice: uint64(456),
+	}).AnyTimes()
+
+	ps, sources := m.EffectiveParamSources(99)
+	assert.Equal(t, uint64(123), ps.UnitPrice)
+	assert.Equal(t, ParamSourceHeader, sources[gov.GovernanceUnitPrice])
+	assert.Equal(t, ParamSourceHeader, sources[gov.IstanbulEpoch])
+	assert.Equal(t, ParamSourceHeader, sources[gov.RewardUseGiniCoeff])
+	assert.Equal(t, ParamSourceDefault, sources[gov.RewardMintingAmount])
+	assert.Len(t, sources, len(gov.Params))
+
+	ps, sources = m.EffectiveParamSources(100)
+	assert.Equal(t, uint64(456), ps.UnitPrice)
+	assert.Equal(t, ParamSourceContract, sources[gov.GovernanceUnitPrice])
+	assert.Equal(t, ParamSourceHeader, sources[gov.IstanbulEpoch])
+	assert.Equal(t, ParamSourceHeader, sources[gov.RewardUseGiniCoeff])
+	assert.Equal(t, m.EffectiveParamSet(100), ps)
+}
+
+func TestEffectiveParamSourcesRegistry(t *testing.T) {
+	hgm, cgm, m := newGovModuleMock(t, &params.ChainConfig{KoreCompatibleBlock: big.NewInt(100)})
+	rgm := registrygov_mock.NewMockRegistryGovModule(gomock.NewController(t))
+	m.RegisterParamSource(ParamSourceRegistry, rgm)
+
+	hgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(gov.PartialParamSet{
+		gov.GovernanceUnitPrice: uint64(123),
+	}).AnyTimes()
+	cgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(gov.PartialParamSet{
+		gov.GovernanceUnitPrice: uint64(456),
+	}).AnyTimes()
+
+	// The registry takes precedence over header and contract governance.
+	rgm.EXPECT().EffectiveParamsPartial(uint64(100)).Return(gov.PartialParamSet{
+		gov.GovernanceUnitPrice: uint64(789),
+		gov.IstanbulEpoch:       uint64(1000),
+	})
+	ps, sources := m.EffectiveParamSources(100)
+	assert.Equal(t, uint64(789), ps.UnitPrice)
+	assert.Equal(t, uint64(1000), ps.Epoch)
+	assert.Equal(t, ParamSourceRegistry, sources[gov.GovernanceUnitPrice])
+	assert.Equal(t, ParamSourceRegistry, sources[gov.IstanbulEpoch])
+
+	// A registry value violating a dependency is ignored.
+	rgm.EXPECT().EffectiveParamsPartial(uint64(101)).Return(gov.PartialParamSet{
+		gov.Kip71LowerBoundBaseFee: uint64(1e18),
+	})
+	ps, sources = m.EffectiveParamSources(101)
+	assert.Equal(t, gov.GetDefaultGovernanceParamSet().LowerBoundBaseFee, ps.LowerBoundBaseFee)
+	assert.Equal(t, ParamSourceDefault, sources[gov.Kip71LowerBoundBaseFee])
+
+	// Falls back to the other sources if the registry is not readable.
+	rgm.EXPECT().EffectiveParamsPartial(uint64(102)).Return(nil)
+	ps, sources = m.EffectiveParamSources(102)
+	assert.Equal(t, uint64(456), ps.UnitPrice)
+	assert.Equal(t, ParamSourceContract, sources[gov.GovernanceUnitPrice])
+}
+
+func TestRegisterParamSource(t *testing.T) {
+	hgm, cgm, m := newGovModuleMock(t, &params.ChainConfig{})
+	ctrl := gomock.NewController(t)
+	first, second := registrygov_mock.NewMockRegistryGovModule(ctrl), registrygov_mock.NewMockRegistryGovModule(ctrl)
+	m.RegisterParamSource("first", first)
+	m.RegisterParamSource("second", second)
+
+	// Contract governance is disabled, so it is not consulted.
+	hgm.EXPECT().EffectiveParamsPartial(uint64(10)).Return(gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(1)})
+	first.EXPECT().EffectiveParamsPartial(uint64(10)).Return(gov.PartialParamSet{
+		gov.GovernanceUnitPrice: uint64(2),
+		gov.IstanbulEpoch:       uint64(2),
+	})
+	second.EXPECT().EffectiveParamsPartial(uint64(10)).Return(gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(3)})
+
+	// A later registered source takes precedence.
+	ps, sources := m.EffectiveParamSources(10)
+	assert.Equal(t, uint64(3), ps.UnitPrice)
+	assert.Equal(t, uint64(2), ps.Epoch)
+	assert.Equal(t, ParamSource("second"), sources[gov.GovernanceUnitPrice])
+	assert.Equal(t, ParamSource("first"), sources[gov.IstanbulEpoch])
+
+	// The change blocks of every source are candidates.
+	hgm.EXPECT().ParamChangeBlocks(uint64(0), uint64(100)).Return([]uint64{50})
+	cgm.EXPECT().ParamChangeBlocks(uint64(0), uint64(100)).Return(nil)
+	first.EXPECT().ParamChangeBlocks(uint64(0), uint64(100)).Return([]uint64{30, 50})
+	second.EXPECT().ParamChangeBlocks(uint64(0), uint64(100)).Return([]uint64{70})
+	assert.Equal(t, []uint64{30, 50, 70}, m.paramChangeCandidates(0, 100))
+}
+
+func TestParamDiff(t *testing.T) {
+	hgm, cgm, m := newGovModuleMock(t, &params.ChainConfig{KoreCompatibleBlock: big.NewInt(300)})
+
+	// header gov changes the unit price at 105 and the epoch at 205;
+	// contract gov changes the unit price at 305.
+	hgm.EXPECT().ParamChangeBlocks(uint64(100), uint64(400)).Return([]uint64{105, 205})
+	cgm.EXPECT().ParamChangeBlocks(uint64(100), uint64(400)).Return([]uint64{305})
+	hgm.EXPECT().EffectiveParamsPartial(gomock.Any()).DoAndReturn(func(num uint64) gov.PartialParamSet {
+		ret := gov.PartialParamSet{}
+		if num >= 105 {
+			ret[gov.GovernanceUnitPrice] = uint64(123)
+		}
+		if num >= 205 {
+			ret[gov.IstanbulEpoch] = uint64(1000)
+		}
+		return ret
+	}).AnyTimes()
+	cgm.EXPECT().EffectiveParamsPartial(gomock.Any()).DoAndReturn(func(num uint64) gov.PartialParamSet {
+		if num >= 305 {
+			return gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(456)}
+		}
+		return nil
+	}).AnyTimes()
+
+	diff := m.ParamDiff(100, 400)
+	assert.Len(t, diff, 2)
+	assert.Equal(t, []ParamChange{
+		{Block: 105, From: uint64(250e9), To: uint64(123)},
+		{Block: 305, From: uint64(123), To: uint64(456)},
+	}, diff[gov.GovernanceUnitPrice])
+	assert.Equal(t, []ParamChange{
+		{Block: 205, From: uint64(604800), To: uint64(1000)},
+	}, diff[gov.IstanbulEpoch])
+}
+
+func TestRecentParamChanges(t *testing.T) {
+	hgm, cgm, m := newGovModuleMock(t, &params.ChainConfig{KoreCompatibleBlock: big.NewInt(300)})
+
+	// header gov changes the unit price and the epoch at 105 and the epoch at 205;
+	// contract gov changes the unit price at 305.
+	hgm.EXPECT().ParamChangeBlocks(uint64(0), uint64(400)).Return([]uint64{105, 205}).AnyTimes()
+	cgm.EXPECT().ParamChangeBlocks(uint64(0), uint64(400)).Return([]uint64{305}).AnyTimes()
+	hgm.EXPECT().EffectiveParamsPartial(gomock.Any()).DoAndReturn(func(num uint64) gov.PartialParamSet {
+		ret := gov.PartialParamSet{}
+		if num >= 105 {
+			ret[gov.GovernanceUnitPrice] = uint64(123)
+			ret[gov.IstanbulEpoch] = uint64(1000)
+		}
+		if num >= 205 {
+			ret[gov.IstanbulEpoch] = uint64(2000)
+		}
+		return ret
+	}).AnyTimes()
+	cgm.EXPECT().EffectiveParamsPartial(gomock.Any()).DoAndReturn(func(num uint64) gov.PartialParamSet {
+		if num >= 305 {
+			return gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(456)}
+		}
+		return nil
+	}).AnyTimes()
+
+	all := []RecentParamChange{
+		{Key: gov.GovernanceUnitPrice, OldValue: uint64(123), NewValue: uint64(456), ActivationBlock: 305, Source: ParamSourceContract},
+		{Key: gov.IstanbulEpoch, OldValue: uint64(1000), NewValue: uint64(2000), ActivationBlock: 205, Source: ParamSourceHeader},
+		{Key: gov.GovernanceUnitPrice, OldValue: uint64(250e9), NewValue: uint64(123), ActivationBlock: 105, Source: ParamSourceHeader},
+		{Key: gov.IstanbulEpoch, OldValue: uint64(604800), NewValue: uint64(1000), ActivationBlock: 105, Source: ParamSourceHeader},
+	}
+	assert.Equal(t, all, m.RecentParamChanges(400, 10))
+	assert.Equal(t, all[:3], m.RecentParamChanges(400, 3))
+
+	// API
+	chain := m.Chain.(*blockchain_mock.MockBlockChain)
+	chain.EXPECT().CurrentBlock().Return(types.NewBlockWithHeader(&types.Header{Number: big.NewInt(400)})).AnyTimes()
+	api := NewGovAPI(m)
+	changes, err := api.GetRecentChanges(1)
+	require.NoError(t, err)
+	assert.Equal(t, all[:1], changes)
+
+	_, err = api.GetRecentChanges(0)
+	assert.ErrorIs(t, err, gov.ErrInvalidCount)
+	_, err = api.GetRecentChanges(maxRecentChanges + 1)
+	assert.ErrorIs(t, err, gov.ErrInvalidCount)
+}
+
+func TestEffectiveParamSetRange(t *testing.T) {
+	hgm, cgm, m := newGovModuleMock(t, &params.ChainConfig{KoreCompatibleBlock: big.NewInt(300)})
+
+	// header gov changes the unit price at 105 and sets it to the same value again at 205;
+	// the kore hardfork at 300 does not change anything.
+	hgm.EXPECT().ParamChangeBlocks(uint64(100), uint64(400)).Return([]uint64{105, 205})
+	cgm.EXPECT().ParamChangeBlocks(uint64(100), uint64(400)).Return(nil)
+	hgm.EXPECT().EffectiveParamsPartial(gomock.Any()).DoAndReturn(func(num uint64) gov.PartialParamSet {
+		if num >= 105 {
+			return gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(123)}
+		}
+		return nil
+	}).AnyTimes()
+	cgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(nil).AnyTimes()
+
+	ranges := m.EffectiveParamSetRange(100, 400)
+	assert.Len(t, ranges, 2)
+	assert.Equal(t, uint64(100), ranges[0].From)
+	assert.Equal(t, uint64(104), ranges[0].To)
+	assert.Equal(t, uint64(250e9), ranges[0].ParamSet.UnitPrice)
+	assert.Equal(t, uint64(105), ranges[1].From)
+	assert.Equal(t, uint64(400), ranges[1].To)
+	assert.Equal(t, uint64(123), ranges[1].ParamSet.UnitPrice)
+
+	assert.Nil(t, m.EffectiveParamSetRange(400, 100))
+}
+
+func TestGovMetrics(t *testing.T) {
+	defer func(enabled bool) { metricutils.Enabled = enabled }(metricutils.Enabled)
+	metricutils.Enabled = true
+
+	var (
+		config = &params.ChainConfig{
+			KoreCompatibleBlock: big.NewInt(0),
+			Istanbul:            &params.IstanbulConfig{Epoch: 1000},
+		}
+		hgm   = newHeaderGovModuleMock(t)
+		cgm   = newContractGovModuleMock(t)
+		chain = blockchain_mock.NewMockBlockChain(gomock.NewController(t))
+		m     = NewGovModule()
+		head  = &types.Header{Number: big.NewInt(9)}
+	)
+	chain.EXPECT().Config().Return(config).AnyTimes()
+	chain.EXPECT().GetHeaderByNumber(uint64(9)).Return(head).AnyTimes()
+	chain.EXPECT().GetHeaderByNumber(gomock.Any()).Return(nil).AnyTimes()
+	require.NoError(t, m.Init(&InitOpts{Hgm: hgm, Cgm: cgm, Chain: chain}))
+
+	// The unit price changes from 25 to 100 at block 2000.
+	hgm.EXPECT().PostInsertBlock(gomock.Any()).Return(nil).AnyTimes()
+	hgm.EXPECT().EffectiveParamsPartial(gomock.Any()).DoAndReturn(func(num uint64) gov.PartialParamSet {
+		if num < 2000 {
+			return gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(25)}
+		}
+		return gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(100)}
+	}).AnyTimes()
+	cgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(nil).AnyTimes()
+
+	// The cache hits only when the parent is known.
+	hits, misses := paramSetCacheHitCounter.Count(), paramSetCacheMissCounter.Count()
+	m.EffectiveParamSet(10)
+	m.EffectiveParamSet(10)
+	m.EffectiveParamSet(20)
+	assert.Equal(t, int64(1), paramSetCacheHitCounter.Count()-hits)
+	assert.Equal(t, int64(2), paramSetCacheMissCounter.Count()-misses)
+
+	for _, num := range []int64{2000, 4999, 5000} {
+		require.NoError(t, m.PostInsertBlock(types.NewBlockWithHeader(&types.Header{Number: big.NewInt(num)})))
+	}
+	assert.Equal(t, uint64(2000), m.lastParamChange.Load())
+	assert.Equal(t, int64(3), epochsSinceParamChangeGauge.Value())
+
+	// Start recovers the latest change from the chain.
+	m = NewGovModule()
+	require.NoError(t, m.Init(&InitOpts{Hgm: hgm, Cgm: cgm, Chain: chain}))
+	hgm.EXPECT().Start().Return(nil)
+	cgm.EXPECT().Start().Return(nil)
+	hgm.EXPECT().ParamChangeBlocks(gomock.Any(), gomock.Any()).Return([]uint64{2000}).AnyTimes()
+	cgm.EXPECT().ParamChangeBlocks(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	chain.EXPECT().CurrentBlock().Return(types.NewBlockWithHeader(&types.Header{Number: big.NewInt(6500)}))
+	require.NoError(t, m.Start())
+	assert.Equal(t, uint64(2000), m.lastParamChange.Load())
+	assert.Equal(t, int64(4), epochsSinceParamChangeGauge.Value())
+}
@@ -0,0 +1,110 @@
+package impl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kaiachain/kaia/kaiax/gov"
+)
+
+// ImportResult summarizes an ImportHistory call.
+type ImportResult struct {
+	Imported         int  `json:"imported"`         // header governance records indexed
+	Skipped          int  `json:"skipped"`          // records of blocks the node does not have yet
+	GovParamVerified bool `json:"govParamVerified"` // whether the GovParam records were compared with the local contract
+}
+
+// ExportHistory returns the whole governance history up to the current block.
+func (m *GovModule) ExportHistory() (*gov.HistoryExport, error) {
+	head := m.Chain.CurrentBlock().NumberU64()
+	genesis := m.Chain.GetHeaderByNumber(0)
+	if genesis == nil {
+		return nil, gov.ErrUnknownBlock
+	}
+
+	votes, govs, err := m.Hgm.ExportHistory(head)
+	if err != nil {
+		return nil, err
+	}
+	records, err := m.Cgm.ExportRecords(head)
+	if err != nil {
+		return nil, err
+	}
+
+	ps := m.EffectiveParamSet(head)
+	effective := make(gov.PartialParamSet)
+	for name, value := range ps.ToMap() {
+		effective[name] = value
+	}
+
+	return &gov.HistoryExport{
+		Version:     gov.HistoryVersion,
+		GenesisHash: genesis.Hash(),
+		Head:        head,
+		Epoch:       m.Chain.Config().Istanbul.Epoch,
+		Votes:       votes,
+		Governances: govs,
+		GovParam:    records,
+		Effective:   gov.HistoryParams(effective),
+		Changes:     m.paramChanges(head),
+	}, nil
+}
+
+// paramChanges returns the changes of the effective parameters up to head with their activation
+// blocks, starting with the whole parameter set at genesis.
+func (m *GovModule) paramChanges(head uint64) []gov.ParamChange {
+	genesis := make(gov.PartialParamSet)
+	ps := m.EffectiveParamSet(0)
+	for name, value := range ps.ToMap() {
+		genesis[name] = value
+	}
+
+	changes := []gov.ParamChange{{Block: 0, Params: gov.HistoryParams(genesis)}}
+	for _, num := range m.paramChangeCandidates(0, head) {
+		if changed := m.paramsChangedAt(num); len(changed) > 0 {
+			changes = append(changes, gov.ParamChange{Block: num, Params: gov.HistoryParams(changed)})
+		}
+	}
+	return changes
+}
+
+// ImportHistory indexes the header governance records of a history exported from the same
+// chain, after checking them against the local headers. The GovParam records are part of the
+// contract state and cannot be imported; they are only compared with the local contract.
+func (m *GovModule) ImportHistory(h *gov.HistoryExport) (*ImportResult, error) {
+	if err := h.Validate(); err != nil {
+		return nil, err
+	}
+	genesis := m.Chain.GetHeaderByNumber(0)
+	if genesis == nil || genesis.Hash() != h.GenesisHash {
+		return nil, fmt.Errorf("%w: genesis %s", gov.ErrHistoryMismatch, h.GenesisHash.Hex())
+	}
+	if epoch := m.Chain.Config().Istanbul.Epoch; epoch != h.Epoch {
+		return nil, fmt.Errorf("%w: epoch %d, want %d", gov.ErrHistoryMismatch, h.Epoch, epoch)
+	}
+
+	ret := &ImportResult{}
+	if h.GovParam != nil && h.Head <= m.Chain.CurrentBlock().NumberU64() {
+		local, err := m.Cgm.ExportRecords(h.Head)
+		if err != nil {
+			return nil, err
+		}
+		// Compare the JSON forms, which do not distinguish nil and empty slices.
+		want, _ := json.Marshal(h.GovParam)
+		got, _ := json.Marshal(local)
+		if !bytes.Equal(want, got) {
+			return nil, fmt.Errorf("%w: GovParam records at block %d", gov.ErrHistoryMismatch, h.Head)
+		}
+		ret.GovParamVerified = true
+	}
+
+	imported, skipped, err := m.Hgm.ImportHistory(h.Head, h.Votes, h.Governances)
+	if err != nil {
+		return nil, err
+	}
+	m.purgeParamSetCache()
+
+	ret.Imported, ret.Skipped = imported, skipped
+	return ret, nil
+}
@@ -0,0 +1,48 @@
+package impl
+
+import (
+	"math/big"
+	"testing"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/kaiachain/kaia/kaiax/gov"
+	"github.com/kaiachain/kaia/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParamChanges(t *testing.T) {
+	hgm, cgm, m := newGovModuleMock(t, &params.ChainConfig{KoreCompatibleBlock: big.NewInt(300)})
+
+	// header gov changes the unit price at 105; contract gov changes it back at 305.
+	hgm.EXPECT().ParamChangeBlocks(uint64(0), uint64(400)).Return([]uint64{105}).AnyTimes()
+	cgm.EXPECT().ParamChangeBlocks(uint64(0), uint64(400)).Return([]uint64{305}).AnyTimes()
+	hgm.EXPECT().EffectiveParamsPartial(gomock.Any()).DoAndReturn(func(num uint64) gov.PartialParamSet {
+		if num >= 105 {
+			return gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(123)}
+		}
+		return nil
+	}).AnyTimes()
+	cgm.EXPECT().EffectiveParamsPartial(gomock.Any()).DoAndReturn(func(num uint64) gov.PartialParamSet {
+		if num >= 305 {
+			return gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(250e9)}
+		}
+		return nil
+	}).AnyTimes()
+
+	changes := m.paramChanges(400)
+	require.Len(t, changes, 3)
+	assert.Equal(t, uint64(0), changes[0].Block)
+	assert.Equal(t, uint64(250e9), changes[0].Params[gov.GovernanceUnitPrice])
+	assert.Equal(t, gov.ParamChange{Block: 105, Params: map[gov.ParamName]any{gov.GovernanceUnitPrice: uint64(123)}}, changes[1])
+	assert.Equal(t, gov.ParamChange{Block: 305, Params: map[gov.ParamName]any{gov.GovernanceUnitPrice: uint64(250e9)}}, changes[2])
+
+	// The timeline reproduces the effective parameters.
+	h := &gov.HistoryExport{Head: 400, Changes: changes}
+	for _, num := range []uint64{0, 104, 105, 304, 305, 400} {
+		ps, err := h.ParamSetAt(num)
+		require.NoError(t, err)
+		expected := m.EffectiveParamSet(num)
+		assert.Equal(t, expected.UnitPrice, ps.UnitPrice, num)
+	}
+}
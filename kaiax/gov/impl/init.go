@@ -3,21 +3,34 @@ package impl
 import (
 	"errors"
 	"math/big"
+	"sync/atomic"
 
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/kaiachain/kaia/blockchain/state"
 	"github.com/kaiachain/kaia/blockchain/types"
 	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/event"
 	"github.com/kaiachain/kaia/kaiax/gov"
 	"github.com/kaiachain/kaia/kaiax/gov/contractgov"
 	"github.com/kaiachain/kaia/kaiax/gov/headergov"
 	"github.com/kaiachain/kaia/log"
+	metricutils "github.com/kaiachain/kaia/metrics/utils"
 	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/rcrowley/go-metrics"
 )
 
 var (
 	_ gov.GovModule = (*GovModule)(nil)
 
 	logger = log.NewModuleLogger(log.KaiaxGov)
+
+	paramSetCacheSize = 128
+
+	paramSetCacheHitCounter     = metrics.NewRegisteredCounter("kaiax/gov/paramset/cache/hits", nil)
+	paramSetCacheMissCounter    = metrics.NewRegisteredCounter("kaiax/gov/paramset/cache/misses", nil)
+	paramSetTimer               = metrics.NewRegisteredTimer("kaiax/gov/paramset/time", nil)
+	epochsSinceParamChangeGauge = metrics.NewRegisteredGauge("kaiax/gov/params/epochssincechange", nil)
 )
 
 //go:generate mockgen -destination=kaiax/gov/impl/mock/blockchain_mock.go github.com/kaiachain/kaia/kaiax/gov/impl BlockChain
@@ -32,16 +45,32 @@ type BlockChain interface {
 
 type GovModule struct {
 	InitOpts
+
+	govEventFeed  event.Feed
+	govEventScope event.SubscriptionScope
+
+	paramSetCache *lru.Cache    // (blockNum uint64) -> (*cachedParamSet)
+	paramSetGen   atomic.Uint64 // bumped whenever the cached param sets may become stale
+
+	lastParamChange atomic.Uint64 // the latest block where the effective parameters changed; tracked only with metrics enabled
+
+	paramSources []paramSource // in ascending precedence; header and contract governance come first
 }
 
 type InitOpts struct {
 	Hgm   headergov.HeaderGovModule
 	Cgm   contractgov.ContractGovModule
 	Chain BlockChain
+
+	// ChainKv stores the governance audit log. The audit log is disabled if nil.
+	ChainKv database.Database
 }
 
 func NewGovModule() *GovModule {
-	return &GovModule{}
+	paramSetCache, _ := lru.New(paramSetCacheSize)
+	return &GovModule{
+		paramSetCache: paramSetCache,
+	}
 }
 
 func (m *GovModule) Init(opts *InitOpts) error {
@@ -50,19 +79,31 @@ func (m *GovModule) Init(opts *InitOpts) error {
 	}
 
 	m.InitOpts = *opts
+	m.paramSources = []paramSource{
+		{name: ParamSourceHeader, source: m.Hgm},
+		{name: ParamSourceContract, source: m.Cgm, enabled: m.isContractGovEnabled},
+	}
 	return nil
 }
 
 func (m *GovModule) Start() error {
 	logger.Info("GovModule started")
-	return errors.Join(
-		m.Hgm.Start(),
-		m.Cgm.Start(),
-	)
+	m.purgeParamSetCache()
+	if err := errors.Join(m.Hgm.Start(), m.Cgm.Start()); err != nil {
+		return err
+	}
+	if metricutils.Enabled {
+		if head := m.Chain.CurrentBlock(); head != nil {
+			m.lastParamChange.Store(m.latestParamChange(head.NumberU64()))
+			m.updateParamChangeMetrics(head.NumberU64(), nil)
+		}
+	}
+	return nil
 }
 
 func (m *GovModule) Stop() {
 	logger.Info("GovModule stopped")
+	m.govEventScope.Close()
 	m.Hgm.Stop()
 	m.Cgm.Stop()
 }
@@ -70,3 +111,7 @@ func (m *GovModule) Stop() {
 func (m *GovModule) isKoreHF(num uint64) bool {
 	return m.Chain.Config().IsKoreForkEnabled(new(big.Int).SetUint64(num))
 }
+
+func (m *GovModule) isContractGovEnabled(num uint64) bool {
+	return m.Chain.Config().IsContractGovEnabled(new(big.Int).SetUint64(num))
+}
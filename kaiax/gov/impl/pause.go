@@ -0,0 +1,72 @@
+package impl
+
+import (
+	"math/big"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/blockchain/vm"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/gov"
+)
+
+// PreRunTx rejects a tx paused by governance or an invalid blob tx, which invalidates a block containing it.
+// The blob fee of a blob tx is charged by the state transition.
+func (m *GovModule) PreRunTx(evm *vm.EVM, tx *types.Transaction) (*types.Transaction, error) {
+	if err := m.checkTxAddable(evm.Context.BlockNumber.Uint64(), tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (m *GovModule) PostRunTx(evm *vm.EVM, tx *types.Transaction) error {
+	return nil
+}
+
+func (m *GovModule) PreAddLocal(tx *types.Transaction) error {
+	return m.checkTxAddable(m.Chain.CurrentBlock().NumberU64()+1, tx)
+}
+
+func (m *GovModule) PreAddRemote(tx *types.Transaction) error {
+	return m.checkTxAddable(m.Chain.CurrentBlock().NumberU64()+1, tx)
+}
+
+func (m *GovModule) checkTxAddable(num uint64, tx *types.Transaction) error {
+	if err := m.checkTxPaused(num, tx); err != nil {
+		return err
+	}
+	return m.checkBlobTx(num, tx)
+}
+
+// PausedContracts returns the contracts paused by governance at the given block, or nil if none.
+// The EVM refuses to run their code at any call depth, so that they cannot be reached through other contracts.
+func (m *GovModule) PausedContracts(num uint64) map[common.Address]bool {
+	if !m.Chain.Config().IsEmergencyPauseForkEnabled(new(big.Int).SetUint64(num)) {
+		return nil
+	}
+
+	ps := m.EffectiveParamSet(num)
+	if num >= ps.PauseExpiry {
+		return nil
+	}
+	contracts, _ := gov.ParseAddressList(ps.PausedContracts)
+	if len(contracts) == 0 {
+		return nil
+	}
+	ret := make(map[common.Address]bool, len(contracts))
+	for _, contract := range contracts {
+		ret[contract] = true
+	}
+	return ret
+}
+
+func (m *GovModule) checkTxPaused(num uint64, tx *types.Transaction) error {
+	if !m.Chain.Config().IsEmergencyPauseForkEnabled(new(big.Int).SetUint64(num)) {
+		return nil
+	}
+
+	ps := m.EffectiveParamSet(num)
+	if ps.IsTxPaused(num, tx) {
+		return gov.ErrTxPaused
+	}
+	return nil
+}
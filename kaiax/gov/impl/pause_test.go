@@ -0,0 +1,51 @@
+package impl
+
+import (
+	"math/big"
+	"testing"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/blockchain/vm"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/gov"
+	"github.com/kaiachain/kaia/params"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreRunTxPaused(t *testing.T) {
+	var (
+		contract = common.HexToAddress("0x0000000000000000000000000000000000001234")
+		tx       = types.NewTransaction(0, contract, big.NewInt(0), 21000, big.NewInt(1), nil)
+		pause    = gov.PartialParamSet{
+			gov.GovernancePausedContracts: contract.Hex(),
+			gov.GovernancePauseExpiry:     uint64(100),
+		}
+		evmAt = func(num int64) *vm.EVM {
+			return &vm.EVM{Context: vm.BlockContext{BlockNumber: big.NewInt(num)}}
+		}
+	)
+
+	t.Run("fork disabled", func(t *testing.T) {
+		_, _, m := newGovModuleMock(t, &params.ChainConfig{})
+		_, err := m.PreRunTx(evmAt(50), tx)
+		assert.NoError(t, err)
+		assert.Nil(t, m.PausedContracts(50))
+	})
+
+	t.Run("fork enabled", func(t *testing.T) {
+		hgm, cgm, m := newGovModuleMock(t, &params.ChainConfig{EmergencyPauseCompatibleBlock: big.NewInt(0)})
+		hgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(pause).AnyTimes()
+		cgm.EXPECT().EffectiveParamsPartial(gomock.Any()).Return(nil).AnyTimes()
+
+		_, err := m.PreRunTx(evmAt(50), tx)
+		assert.ErrorIs(t, err, gov.ErrTxPaused)
+
+		ret, err := m.PreRunTx(evmAt(100), tx)
+		assert.NoError(t, err)
+		assert.Equal(t, tx, ret)
+
+		assert.Equal(t, map[common.Address]bool{contract: true}, m.PausedContracts(50))
+		assert.Nil(t, m.PausedContracts(100))
+	})
+}
@@ -7,8 +7,10 @@ import (
 
 func (m *GovModule) RewindTo(newBlock *types.Block) {
 	m.Hgm.RewindTo(newBlock)
+	m.purgeParamSetCache()
 }
 
 func (m *GovModule) RewindDelete(hash common.Hash, num uint64) {
 	m.Hgm.RewindDelete(hash, num)
+	m.purgeParamSetCache()
 }
@@ -11,6 +11,25 @@ type GovModule interface {
 	kaiax.ConsensusModule
 	kaiax.ExecutionModule
 	kaiax.RewindableModule
+	kaiax.TxProcessModule
+	kaiax.TxPoolModule
 
 	EffectiveParamSet(blockNum uint64) ParamSet
+	EffectiveParamSetRange(from, to uint64) []ParamSetRange
+}
+
+// ParamSource supplies a part of the governance parameters. GovModule merges the parameters of its
+// sources in the order of their registration, so a later source takes precedence.
+type ParamSource interface {
+	// EffectiveParamsPartial returns the parameters supplied at blockNum, or nil if none.
+	EffectiveParamsPartial(blockNum uint64) PartialParamSet
+	// ParamChangeBlocks returns the blocks in (from, to] at which the supplied parameters may change, in ascending order.
+	ParamChangeBlocks(from, to uint64) []uint64
+}
+
+// ParamSetRange is the effective parameter set of the blocks from From to To, inclusive.
+type ParamSetRange struct {
+	From     uint64
+	To       uint64
+	ParamSet ParamSet
 }
@@ -10,6 +10,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	state "github.com/kaiachain/kaia/blockchain/state"
 	types "github.com/kaiachain/kaia/blockchain/types"
+	vm "github.com/kaiachain/kaia/blockchain/vm"
 	common "github.com/kaiachain/kaia/common"
 	gov "github.com/kaiachain/kaia/kaiax/gov"
 	rpc "github.com/kaiachain/kaia/networks/rpc"
@@ -66,6 +67,20 @@ func (mr *MockGovModuleMockRecorder) EffectiveParamSet(arg0 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EffectiveParamSet", reflect.TypeOf((*MockGovModule)(nil).EffectiveParamSet), arg0)
 }
 
+// EffectiveParamSetRange mocks base method.
+func (m *MockGovModule) EffectiveParamSetRange(arg0, arg1 uint64) []gov.ParamSetRange {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EffectiveParamSetRange", arg0, arg1)
+	ret0, _ := ret[0].([]gov.ParamSetRange)
+	return ret0
+}
+
+// EffectiveParamSetRange indicates an expected call of EffectiveParamSetRange.
+func (mr *MockGovModuleMockRecorder) EffectiveParamSetRange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EffectiveParamSetRange", reflect.TypeOf((*MockGovModule)(nil).EffectiveParamSetRange), arg0, arg1)
+}
+
 // FinalizeHeader mocks base method.
 func (m *MockGovModule) FinalizeHeader(arg0 *types.Header, arg1 *state.StateDB, arg2 []*types.Transaction, arg3 []*types.Receipt) error {
 	m.ctrl.T.Helper()
@@ -94,6 +109,63 @@ func (mr *MockGovModuleMockRecorder) PostInsertBlock(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostInsertBlock", reflect.TypeOf((*MockGovModule)(nil).PostInsertBlock), arg0)
 }
 
+// PostRunTx mocks base method.
+func (m *MockGovModule) PostRunTx(arg0 *vm.EVM, arg1 *types.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostRunTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostRunTx indicates an expected call of PostRunTx.
+func (mr *MockGovModuleMockRecorder) PostRunTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostRunTx", reflect.TypeOf((*MockGovModule)(nil).PostRunTx), arg0, arg1)
+}
+
+// PreAddLocal mocks base method.
+func (m *MockGovModule) PreAddLocal(arg0 *types.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreAddLocal", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PreAddLocal indicates an expected call of PreAddLocal.
+func (mr *MockGovModuleMockRecorder) PreAddLocal(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreAddLocal", reflect.TypeOf((*MockGovModule)(nil).PreAddLocal), arg0)
+}
+
+// PreAddRemote mocks base method.
+func (m *MockGovModule) PreAddRemote(arg0 *types.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreAddRemote", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PreAddRemote indicates an expected call of PreAddRemote.
+func (mr *MockGovModuleMockRecorder) PreAddRemote(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreAddRemote", reflect.TypeOf((*MockGovModule)(nil).PreAddRemote), arg0)
+}
+
+// PreRunTx mocks base method.
+func (m *MockGovModule) PreRunTx(arg0 *vm.EVM, arg1 *types.Transaction) (*types.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreRunTx", arg0, arg1)
+	ret0, _ := ret[0].(*types.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreRunTx indicates an expected call of PreRunTx.
+func (mr *MockGovModuleMockRecorder) PreRunTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreRunTx", reflect.TypeOf((*MockGovModule)(nil).PreRunTx), arg0, arg1)
+}
+
 // PrepareHeader mocks base method.
 func (m *MockGovModule) PrepareHeader(arg0 *types.Header) error {
 	m.ctrl.T.Helper()
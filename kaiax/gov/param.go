@@ -3,22 +3,25 @@ package gov
 import (
 	"bytes"
 	"errors"
+	"math"
 	"math/big"
-	"strconv"
 	"strings"
 
 	"github.com/kaiachain/kaia/common"
 	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/params"
 )
 
 type canonicalizerT func(v any) (any, error)
 
 type Param struct {
 	Canonicalizer canonicalizerT
-	FormatChecker func(cv any) bool // validation on canonical value.
+	Validators    []ValueValidator // validation on canonical value, applied in order.
 
 	DefaultValue  any
 	VoteForbidden bool
+	Emergency     bool         // true if the parameter can be changed by the emergency votes of the council
+	Deprecation   *Deprecation // nil if the parameter is never deprecated
 }
 
 var (
@@ -161,10 +164,6 @@ var (
 	}
 )
 
-func noopFormatChecker(cv any) bool {
-	return true
-}
-
 type (
 	ParamName          string
 	ValidatorParamName string
@@ -172,10 +171,18 @@ type (
 
 // alphabetically sorted. These are only used in-memory, so the order does not matter.
 const (
+	BlobTxBaseFee                  ParamName = "blobtx.basefee"
+	BlobTxMaxGasPerBlock           ParamName = "blobtx.maxgasperblock"
+	GovernanceActivationDelays     ParamName = "governance.activationdelays"
 	GovernanceDeriveShaImpl        ParamName = "governance.deriveshaimpl"
 	GovernanceGovernanceMode       ParamName = "governance.governancemode"
 	GovernanceGoverningNode        ParamName = "governance.governingnode"
 	GovernanceGovParamContract     ParamName = "governance.govparamcontract"
+	GovernanceMultisigSigners      ParamName = "governance.multisigsigners"
+	GovernanceMultisigThreshold    ParamName = "governance.multisigthreshold"
+	GovernancePausedContracts      ParamName = "governance.pausedcontracts"
+	GovernancePausedTxTypes        ParamName = "governance.pausedtxtypes"
+	GovernancePauseExpiry          ParamName = "governance.pauseexpiry"
 	GovernanceUnitPrice            ParamName = "governance.unitprice"
 	IstanbulCommitteeSize          ParamName = "istanbul.committeesize"
 	IstanbulEpoch                  ParamName = "istanbul.epoch"
@@ -201,230 +208,207 @@ const (
 )
 
 var Params = map[ParamName]*Param{
+	BlobTxBaseFee: {
+		Canonicalizer: uint64Canonicalizer,
+		Validators:    []ValueValidator{isType[uint64]()},
+		DefaultValue:  uint64(25000000000),
+		VoteForbidden: false,
+	},
+	BlobTxMaxGasPerBlock: {
+		Canonicalizer: uint64Canonicalizer,
+		Validators:    []ValueValidator{isType[uint64]()},
+		DefaultValue:  uint64(6 * params.BlobTxBlobGasPerBlob),
+		VoteForbidden: false,
+	},
+	GovernanceActivationDelays: {
+		Canonicalizer: stringCanonicalizer,
+		Validators:    []ValueValidator{isType[string]()}, // activationDelays() is appended in init.
+		DefaultValue:  "",
+		VoteForbidden: false,
+	},
 	GovernanceDeriveShaImpl: {
 		Canonicalizer: uint64Canonicalizer,
-		FormatChecker: func(cv any) bool {
-			v, ok := cv.(uint64)
-			if !ok {
-				return false
-			}
-			return v <= 2 // deriveShaImpl has only three options.
-		},
+		Validators:    []ValueValidator{isType[uint64](), uint64Range(0, 2)}, // deriveShaImpl has only three options.
 		DefaultValue:  uint64(0),
 		VoteForbidden: false,
 	},
 	GovernanceGovernanceMode: {
 		Canonicalizer: stringCanonicalizer,
-		FormatChecker: func(cv any) bool {
-			v, ok := cv.(string)
-			if !ok {
-				return false
-			}
-			if v == "none" || v == "single" {
-				return true
-			}
-			return false
-		},
+		Validators:    []ValueValidator{isType[string](), stringOneOf("none", "single", "stake", "multisig")},
 		DefaultValue:  "none",
 		VoteForbidden: true,
 	},
 	GovernanceGoverningNode: {
 		Canonicalizer: addressCanonicalizer,
-		FormatChecker: func(cv any) bool {
-			_, ok := cv.(common.Address)
-			return ok
-		},
+		Validators:    []ValueValidator{isType[common.Address]()},
 		DefaultValue:  common.HexToAddress("0x0000000000000000000000000000000000000000"),
 		VoteForbidden: false,
 	},
 	GovernanceGovParamContract: {
 		Canonicalizer: addressCanonicalizer,
-		FormatChecker: func(cv any) bool {
-			_, ok := cv.(common.Address)
-			return ok
-		},
+		Validators:    []ValueValidator{isType[common.Address]()},
 		DefaultValue:  common.HexToAddress("0x0000000000000000000000000000000000000000"),
 		VoteForbidden: false,
 	},
+	GovernanceMultisigSigners: {
+		Canonicalizer: stringCanonicalizer,
+		Validators:    []ValueValidator{isType[string](), addressList()},
+		DefaultValue:  "",
+		VoteForbidden: false,
+	},
+	GovernanceMultisigThreshold: {
+		Canonicalizer: uint64Canonicalizer,
+		Validators:    []ValueValidator{isType[uint64]()},
+		DefaultValue:  uint64(0),
+		VoteForbidden: false,
+	},
+	GovernancePausedContracts: {
+		Canonicalizer: stringCanonicalizer,
+		Validators:    []ValueValidator{isType[string](), addressList()},
+		DefaultValue:  "",
+		VoteForbidden: false,
+	},
+	GovernancePausedTxTypes: {
+		Canonicalizer: stringCanonicalizer,
+		Validators:    []ValueValidator{isType[string](), txTypeList()},
+		DefaultValue:  "",
+		VoteForbidden: false,
+	},
+	GovernancePauseExpiry: {
+		Canonicalizer: uint64Canonicalizer,
+		Validators:    []ValueValidator{isType[uint64]()},
+		DefaultValue:  uint64(0),
+		VoteForbidden: false,
+	},
 	GovernanceUnitPrice: {
 		Canonicalizer: uint64Canonicalizer,
-		FormatChecker: noopFormatChecker,
+		Validators:    []ValueValidator{isType[uint64]()},
 		DefaultValue:  uint64(250e9),
 		VoteForbidden: false,
 	},
 	IstanbulCommitteeSize: {
 		Canonicalizer: uint64Canonicalizer,
-		FormatChecker: func(cv any) bool {
-			v, ok := cv.(uint64)
-			if !ok {
-				return false
-			}
-			return v > 0
-		},
+		Validators:    []ValueValidator{isType[uint64](), uint64Range(1, math.MaxUint64)},
 		DefaultValue:  uint64(21),
 		VoteForbidden: false,
+		Emergency:     true,
 	},
 	IstanbulEpoch: {
 		Canonicalizer: uint64Canonicalizer,
-		FormatChecker: noopFormatChecker,
+		Validators:    []ValueValidator{isType[uint64]()},
 		DefaultValue:  uint64(604800),
 		VoteForbidden: true,
 	},
 	IstanbulPolicy: {
 		Canonicalizer: uint64Canonicalizer,
-		FormatChecker: func(cv any) bool {
-			v, ok := cv.(uint64)
-			if !ok {
-				return false
-			}
-			return v <= 2 // policy has only three options.
-		},
+		Validators:    []ValueValidator{isType[uint64](), uint64Range(0, 2)}, // policy has only three options.
 		DefaultValue:  uint64(RoundRobin),
 		VoteForbidden: true,
 	},
 	Kip71BaseFeeDenominator: {
 		Canonicalizer: uint64Canonicalizer,
-		FormatChecker: func(cv any) bool {
-			v, ok := cv.(uint64)
-			return ok && v != 0
-		},
+		Validators:    []ValueValidator{isType[uint64](), uint64Range(1, math.MaxUint64)},
 		DefaultValue:  uint64(20),
 		VoteForbidden: false,
 	},
 	Kip71GasTarget: {
 		Canonicalizer: uint64Canonicalizer,
-		FormatChecker: noopFormatChecker,
+		Validators:    []ValueValidator{isType[uint64]()},
 		DefaultValue:  uint64(30000000),
 		VoteForbidden: false,
 	},
 	Kip71LowerBoundBaseFee: {
 		Canonicalizer: uint64Canonicalizer,
-		FormatChecker: noopFormatChecker,
+		Validators:    []ValueValidator{isType[uint64]()},
 		DefaultValue:  uint64(25000000000),
 		VoteForbidden: false,
+		Emergency:     true,
 	},
 	Kip71MaxBlockGasUsedForBaseFee: {
 		Canonicalizer: uint64Canonicalizer,
-		FormatChecker: noopFormatChecker,
+		Validators:    []ValueValidator{isType[uint64]()},
 		DefaultValue:  uint64(60000000),
 		VoteForbidden: false,
 	},
 	Kip71UpperBoundBaseFee: {
 		Canonicalizer: uint64Canonicalizer,
-		FormatChecker: noopFormatChecker,
+		Validators:    []ValueValidator{isType[uint64]()},
 		DefaultValue:  uint64(750000000000),
 		VoteForbidden: false,
+		Emergency:     true,
 	},
 	RewardDeferredTxFee: {
 		Canonicalizer: boolCanonicalizer,
-		FormatChecker: noopFormatChecker,
+		Validators:    []ValueValidator{isType[bool]()},
 		DefaultValue:  false,
 		VoteForbidden: true,
 	},
 	RewardKip82Ratio: {
 		Canonicalizer: stringCanonicalizer,
-		FormatChecker: func(cv any) bool {
-			v, ok := cv.(string)
-			if !ok {
-				return false
-			}
-			parts := strings.Split(v, "/")
-			if len(parts) != 2 {
-				return false
-			}
-			sum := 0
-			for _, part := range parts {
-				num, err := strconv.Atoi(part)
-				if err != nil {
-					return false
-				}
-				if num < 0 {
-					return false
-				}
-				sum += num
-			}
-
-			return sum == 100
-		},
+		Validators:    []ValueValidator{isType[string](), ratio(2)},
 		DefaultValue:  "20/80",
 		VoteForbidden: false,
 	},
 	RewardMintingAmount: {
 		Canonicalizer: bigIntCanonicalizer,
-		FormatChecker: noopFormatChecker,
+		Validators:    []ValueValidator{isType[*big.Int]()},
 		DefaultValue:  big.NewInt(0),
 		VoteForbidden: false,
 	},
 	RewardMinimumStake: {
 		Canonicalizer: bigIntCanonicalizer,
-		FormatChecker: func(cv any) bool {
-			v, ok := cv.(*big.Int)
-			if !ok {
-				return false
-			}
-			return v.Sign() >= 0
-		},
+		Validators:    []ValueValidator{isType[*big.Int](), bigIntNonNegative()},
 		DefaultValue:  big.NewInt(2000000),
 		VoteForbidden: true,
 	},
 	RewardProposerUpdateInterval: {
 		Canonicalizer: uint64Canonicalizer,
-		FormatChecker: noopFormatChecker,
+		Validators:    []ValueValidator{isType[uint64]()},
 		DefaultValue:  uint64(3600),
 		VoteForbidden: true,
+		// The proposer is elected at every block since Randao, with no precalculated proposer list.
+		Deprecation: &Deprecation{Hardfork: randao, Value: uint64(1)},
 	},
 	RewardRatio: {
 		Canonicalizer: stringCanonicalizer,
-		FormatChecker: func(cv any) bool {
-			v, ok := cv.(string)
-			if !ok {
-				return false
-			}
-			parts := strings.Split(v, "/")
-			if len(parts) != 3 {
-				return false
-			}
-			sum := 0
-			for _, part := range parts {
-				num, err := strconv.Atoi(part)
-				if err != nil {
-					return false
-				}
-				if num < 0 {
-					return false
-				}
-				sum += num
-			}
-
-			return sum == 100
-		},
+		Validators:    []ValueValidator{isType[string](), ratio(3)},
 		DefaultValue:  "100/0/0",
 		VoteForbidden: false,
 	},
 	RewardStakingUpdateInterval: {
 		Canonicalizer: uint64Canonicalizer,
-		FormatChecker: noopFormatChecker,
+		Validators:    []ValueValidator{isType[uint64]()},
 		DefaultValue:  uint64(86400),
 		VoteForbidden: true,
+		// Staking information is updated at every block since Kaia.
+		Deprecation: &Deprecation{Hardfork: kaia, Value: uint64(1)},
 	},
 	RewardUseGiniCoeff: {
 		Canonicalizer: boolCanonicalizer,
-		FormatChecker: noopFormatChecker,
+		Validators:    []ValueValidator{isType[bool]()},
 		DefaultValue:  false,
 		VoteForbidden: true,
+		// All committee members have an equal chance of being the proposer since Kore.
+		Deprecation: &Deprecation{Hardfork: kore, Value: false},
 	},
 }
 
+func init() {
+	// The validator looks up the param names in Params, so it cannot be set in the literal.
+	p := Params[GovernanceActivationDelays]
+	p.Validators = append(p.Validators, activationDelays())
+}
+
 var ValidatorParams = map[ValidatorParamName]*Param{
 	AddValidator: {
 		Canonicalizer: validatorAddressListCanonicalizer,
-		FormatChecker: noopFormatChecker,
+		Validators:    []ValueValidator{isType[[]common.Address]()},
 		DefaultValue:  []common.Address{},
 		VoteForbidden: false,
 	},
 	RemoveValidator: {
 		Canonicalizer: validatorAddressListCanonicalizer,
-		FormatChecker: noopFormatChecker,
+		Validators:    []ValueValidator{isType[[]common.Address]()},
 		DefaultValue:  []common.Address{},
 		VoteForbidden: false,
 	},
@@ -12,7 +12,7 @@ import (
 func TestParam(t *testing.T) {
 	for name, param := range Params {
 		assert.NotEmpty(t, param.Canonicalizer, name)
-		assert.NotEmpty(t, param.FormatChecker, name)
+		assert.NotEmpty(t, param.Validators, name)
 	}
 }
 
@@ -14,6 +14,9 @@ type ParamSet struct {
 	// governance
 	GovernanceMode                  string
 	GoverningNode, GovParamContract common.Address
+	MultisigSigners                 string
+	MultisigThreshold               uint64
+	ActivationDelays                string
 
 	// istanbul
 	CommitteeSize, ProposerPolicy, Epoch uint64
@@ -27,6 +30,13 @@ type ParamSet struct {
 	// KIP-71
 	LowerBoundBaseFee, UpperBoundBaseFee, GasTarget, MaxBlockGasUsedForBaseFee, BaseFeeDenominator uint64
 
+	// emergency pause
+	PausedContracts, PausedTxTypes string
+	PauseExpiry                    uint64
+
+	// blob tx
+	BlobBaseFee, MaxBlobGasPerBlock uint64
+
 	// etc.
 	DeriveShaImpl uint64
 	UnitPrice     uint64
@@ -49,12 +59,28 @@ func GetDefaultGovernanceParamSet() *ParamSet {
 func (p *ParamSet) Set(name ParamName, cv any) error {
 	var ok bool
 	switch name {
+	case BlobTxBaseFee:
+		p.BlobBaseFee, ok = cv.(uint64)
+	case BlobTxMaxGasPerBlock:
+		p.MaxBlobGasPerBlock, ok = cv.(uint64)
+	case GovernanceActivationDelays:
+		p.ActivationDelays, ok = cv.(string)
 	case GovernanceGovernanceMode:
 		p.GovernanceMode, ok = cv.(string)
 	case GovernanceGoverningNode:
 		p.GoverningNode, ok = cv.(common.Address)
 	case GovernanceGovParamContract:
 		p.GovParamContract, ok = cv.(common.Address)
+	case GovernanceMultisigSigners:
+		p.MultisigSigners, ok = cv.(string)
+	case GovernanceMultisigThreshold:
+		p.MultisigThreshold, ok = cv.(uint64)
+	case GovernancePausedContracts:
+		p.PausedContracts, ok = cv.(string)
+	case GovernancePausedTxTypes:
+		p.PausedTxTypes, ok = cv.(string)
+	case GovernancePauseExpiry:
+		p.PauseExpiry, ok = cv.(uint64)
 	case GovernanceUnitPrice:
 		p.UnitPrice, ok = cv.(uint64)
 	case IstanbulCommitteeSize:
@@ -119,58 +145,78 @@ func (p *ParamSet) ToJSON() (string, error) {
 	return string(j), nil
 }
 
+// get returns the value of the parameter in the ParamSet, or nil if the name is unknown.
+func (p *ParamSet) get(name ParamName) any {
+	switch name {
+	case BlobTxBaseFee:
+		return p.BlobBaseFee
+	case BlobTxMaxGasPerBlock:
+		return p.MaxBlobGasPerBlock
+	case GovernanceActivationDelays:
+		return p.ActivationDelays
+	case GovernanceGovernanceMode:
+		return p.GovernanceMode
+	case GovernanceGoverningNode:
+		return p.GoverningNode
+	case GovernanceGovParamContract:
+		return p.GovParamContract
+	case GovernanceMultisigSigners:
+		return p.MultisigSigners
+	case GovernanceMultisigThreshold:
+		return p.MultisigThreshold
+	case GovernancePausedContracts:
+		return p.PausedContracts
+	case GovernancePausedTxTypes:
+		return p.PausedTxTypes
+	case GovernancePauseExpiry:
+		return p.PauseExpiry
+	case GovernanceUnitPrice:
+		return p.UnitPrice
+	case IstanbulCommitteeSize:
+		return p.CommitteeSize
+	case IstanbulEpoch:
+		return p.Epoch
+	case IstanbulPolicy:
+		return p.ProposerPolicy
+	case Kip71BaseFeeDenominator:
+		return p.BaseFeeDenominator
+	case Kip71GasTarget:
+		return p.GasTarget
+	case Kip71LowerBoundBaseFee:
+		return p.LowerBoundBaseFee
+	case Kip71MaxBlockGasUsedForBaseFee:
+		return p.MaxBlockGasUsedForBaseFee
+	case Kip71UpperBoundBaseFee:
+		return p.UpperBoundBaseFee
+	case RewardDeferredTxFee:
+		return p.DeferredTxFee
+	case RewardKip82Ratio:
+		return p.Kip82Ratio
+	case RewardMintingAmount:
+		return p.MintingAmount
+	case RewardMinimumStake:
+		return p.MinimumStake
+	case RewardProposerUpdateInterval:
+		return p.ProposerUpdateInterval
+	case RewardRatio:
+		return p.Ratio
+	case RewardStakingUpdateInterval:
+		return p.StakingUpdateInterval
+	case RewardUseGiniCoeff:
+		return p.UseGiniCoeff
+	case GovernanceDeriveShaImpl:
+		return p.DeriveShaImpl
+	default:
+		return nil
+	}
+}
+
 // TODO: remove this. Currently it's used for kaia_getParams API.
 func (p *ParamSet) ToMap() map[ParamName]any {
 	ret := make(map[ParamName]any)
-
-	// Iterate through all params in Params and ensure they're in the result
 	for name := range Params {
-		switch name {
-		case GovernanceGovernanceMode:
-			ret[name] = p.GovernanceMode
-		case GovernanceGoverningNode:
-			ret[name] = p.GoverningNode
-		case GovernanceGovParamContract:
-			ret[name] = p.GovParamContract
-		case GovernanceUnitPrice:
-			ret[name] = p.UnitPrice
-		case IstanbulCommitteeSize:
-			ret[name] = p.CommitteeSize
-		case IstanbulEpoch:
-			ret[name] = p.Epoch
-		case IstanbulPolicy:
-			ret[name] = p.ProposerPolicy
-		case Kip71BaseFeeDenominator:
-			ret[name] = p.BaseFeeDenominator
-		case Kip71GasTarget:
-			ret[name] = p.GasTarget
-		case Kip71LowerBoundBaseFee:
-			ret[name] = p.LowerBoundBaseFee
-		case Kip71MaxBlockGasUsedForBaseFee:
-			ret[name] = p.MaxBlockGasUsedForBaseFee
-		case Kip71UpperBoundBaseFee:
-			ret[name] = p.UpperBoundBaseFee
-		case RewardDeferredTxFee:
-			ret[name] = p.DeferredTxFee
-		case RewardKip82Ratio:
-			ret[name] = p.Kip82Ratio
-		case RewardMintingAmount:
-			ret[name] = p.MintingAmount
-		case RewardMinimumStake:
-			ret[name] = p.MinimumStake
-		case RewardProposerUpdateInterval:
-			ret[name] = p.ProposerUpdateInterval
-		case RewardRatio:
-			ret[name] = p.Ratio
-		case RewardStakingUpdateInterval:
-			ret[name] = p.StakingUpdateInterval
-		case RewardUseGiniCoeff:
-			ret[name] = p.UseGiniCoeff
-		case GovernanceDeriveShaImpl:
-			ret[name] = p.DeriveShaImpl
-		}
+		ret[name] = p.get(name)
 	}
-
 	return ret
 }
 
@@ -178,6 +224,12 @@ func (p *ParamSet) ToMap() map[ParamName]any {
 func (p *ParamSet) ToGovParamSet() *params.GovParamSet {
 	m := make(map[string]any)
 	for name, val := range p.ToMap() {
+		switch name {
+		case GovernanceMultisigSigners, GovernanceMultisigThreshold, GovernanceActivationDelays,
+			GovernancePausedContracts, GovernancePausedTxTypes, GovernancePauseExpiry,
+			BlobTxBaseFee, BlobTxMaxGasPerBlock:
+			continue // unknown to the legacy GovParamSet
+		}
 		m[string(name)] = val
 	}
 
@@ -191,15 +243,11 @@ func (p PartialParamSet) Add(name string, value any) error {
 		return ErrInvalidParamName
 	}
 
-	cv, err := param.Canonicalizer(value)
+	cv, err := param.Canonicalize(ParamName(name), value)
 	if err != nil {
 		return err
 	}
 
-	if !param.FormatChecker(cv) {
-		return ErrInvalidParamValue
-	}
-
 	p[ParamName(name)] = cv
 	return nil
 }
@@ -13,6 +13,8 @@ func TestParametersSetAndAdd(t *testing.T) {
 		name  ParamName
 		value any
 	}{
+		{name: BlobTxBaseFee, value: uint64(1)},
+		{name: BlobTxMaxGasPerBlock, value: uint64(131072)},
 		{name: GovernanceDeriveShaImpl, value: uint64(2)},
 		{name: GovernanceGovernanceMode, value: "none"},
 		{name: GovernanceGoverningNode, value: common.HexToAddress("0x000000000000000000000000000abcd000000000")},
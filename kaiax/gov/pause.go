@@ -0,0 +1,48 @@
+package gov
+
+import (
+	"strings"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+)
+
+// IsTxPaused returns true if tx must not be processed in block num. Until PauseExpiry, exclusive,
+// a tx is paused if its type is in PausedTxTypes or its recipient is in PausedContracts.
+// Whether the emergency pause hardfork is enabled is NOT checked.
+func (p *ParamSet) IsTxPaused(num uint64, tx *types.Transaction) bool {
+	if num >= p.PauseExpiry {
+		return false
+	}
+
+	txTypes, _ := parseTxTypeList(p.PausedTxTypes)
+	for _, txType := range txTypes {
+		if tx.Type() == txType {
+			return true
+		}
+	}
+
+	if to := tx.To(); to != nil {
+		contracts, _ := ParseAddressList(p.PausedContracts)
+		for _, contract := range contracts {
+			if *to == contract {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func parseTxTypeList(v string) ([]types.TxType, error) {
+	if v == "" {
+		return nil, nil
+	}
+	ret := []types.TxType{}
+	for _, name := range strings.Split(v, ",") {
+		txType, err := types.ParseTxType(name)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, txType)
+	}
+	return ret, nil
+}
@@ -0,0 +1,84 @@
+package gov
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTxPaused(t *testing.T) {
+	var (
+		paused   = common.HexToAddress("0x0000000000000000000000000000000000001234")
+		unpaused = common.HexToAddress("0x0000000000000000000000000000000000005678")
+
+		legacyToPaused   = types.NewTransaction(0, paused, big.NewInt(0), 21000, big.NewInt(1), nil)
+		legacyToUnpaused = types.NewTransaction(0, unpaused, big.NewInt(0), 21000, big.NewInt(1), nil)
+		deploy           = types.NewContractCreation(0, big.NewInt(0), 21000, big.NewInt(1), nil)
+	)
+	valueTransfer, err := types.NewTransactionWithMap(types.TxTypeValueTransfer, map[types.TxValueKeyType]interface{}{
+		types.TxValueKeyNonce:    uint64(0),
+		types.TxValueKeyFrom:     unpaused,
+		types.TxValueKeyTo:       unpaused,
+		types.TxValueKeyAmount:   big.NewInt(0),
+		types.TxValueKeyGasLimit: uint64(21000),
+		types.TxValueKeyGasPrice: big.NewInt(1),
+	})
+	require.NoError(t, err)
+
+	ps := &ParamSet{
+		PausedContracts: paused.Hex(),
+		PausedTxTypes:   "TxTypeValueTransfer",
+		PauseExpiry:     100,
+	}
+
+	tcs := []struct {
+		desc     string
+		num      uint64
+		tx       *types.Transaction
+		expected bool
+	}{
+		{desc: "paused contract", num: 99, tx: legacyToPaused, expected: true},
+		{desc: "paused tx type", num: 99, tx: valueTransfer, expected: true},
+		{desc: "unpaused", num: 99, tx: legacyToUnpaused, expected: false},
+		{desc: "contract creation", num: 99, tx: deploy, expected: false},
+		{desc: "expired contract", num: 100, tx: legacyToPaused, expected: false},
+		{desc: "expired tx type", num: 100, tx: valueTransfer, expected: false},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ps.IsTxPaused(tc.num, tc.tx))
+		})
+	}
+}
+
+func TestPauseParamValidation(t *testing.T) {
+	tcs := []struct {
+		name  ParamName
+		value any
+		ok    bool
+	}{
+		{name: GovernancePausedContracts, value: "", ok: true},
+		{name: GovernancePausedContracts, value: "0x0000000000000000000000000000000000001234,0x0000000000000000000000000000000000005678", ok: true},
+		{name: GovernancePausedContracts, value: "0x1234", ok: false},
+		{name: GovernancePausedTxTypes, value: "", ok: true},
+		{name: GovernancePausedTxTypes, value: "TxTypeSmartContractDeploy,TxTypeEthereumDynamicFee", ok: true},
+		{name: GovernancePausedTxTypes, value: "TxTypeUnknown", ok: false},
+		{name: GovernancePausedTxTypes, value: "UndefinedTxType", ok: false},
+	}
+
+	for _, tc := range tcs {
+		_, err := Params[tc.name].Canonicalize(tc.name, tc.value)
+		if tc.ok {
+			assert.NoError(t, err, tc.value)
+		} else {
+			var perr *ParamError
+			require.ErrorAs(t, err, &perr, tc.value)
+			assert.Equal(t, RuleFormat, perr.Rule)
+		}
+	}
+}
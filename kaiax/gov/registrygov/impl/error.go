@@ -0,0 +1,9 @@
+package impl
+
+import "errors"
+
+var (
+	ErrInitNil = errors.New("cannot init registrygov module because of nil")
+
+	ErrInvalidRegistryParam = errors.New("invalid parameter in the param registry config")
+)
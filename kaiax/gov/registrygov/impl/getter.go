@@ -0,0 +1,77 @@
+package impl
+
+import (
+	"maps"
+
+	"github.com/kaiachain/kaia/accounts/abi/bind"
+	"github.com/kaiachain/kaia/accounts/abi/bind/backends"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	govcontract "github.com/kaiachain/kaia/contracts/contracts/system_contracts/gov"
+	"github.com/kaiachain/kaia/kaiax/gov"
+)
+
+type cachedParams struct {
+	parentHash common.Hash
+	params     gov.PartialParamSet
+}
+
+// EffectiveParamsPartial returns the registry parameters effective at blockNum, read from the
+// state of its parent. The result is cached per block while the parent is still canonical.
+// It returns nil if the registry is not configured or cannot be read, so that the parameters
+// fall back to the other sources. Parameters missing in the registry or having invalid values are ignored.
+func (r *registryGovModule) EffectiveParamsPartial(blockNum uint64) gov.PartialParamSet {
+	registry := r.ChainConfig.ParamRegistry
+	if registry == nil || common.EmptyAddress(registry.Address) || len(registry.Params) == 0 || blockNum == 0 {
+		return nil
+	}
+
+	parent := r.Chain.GetHeaderByNumber(blockNum - 1)
+	if parent == nil {
+		logger.Debug("Param registry not readable: parent unknown", "num", blockNum)
+		return nil
+	}
+	if cached, ok := r.paramsCache.Get(blockNum); ok && cached.(*cachedParams).parentHash == parent.Hash() {
+		return maps.Clone(cached.(*cachedParams).params)
+	}
+
+	ret, err := r.readRegistry(parent)
+	if err != nil {
+		readFailureCounter.Inc(1)
+		logger.Warn("Failed to read the param registry", "num", blockNum, "addr", registry.Address, "err", err)
+		return nil
+	}
+	r.paramsCache.Add(blockNum, &cachedParams{parentHash: parent.Hash(), params: maps.Clone(ret)})
+	return ret
+}
+
+func (r *registryGovModule) readRegistry(parent *types.Header) (gov.PartialParamSet, error) {
+	registry := r.ChainConfig.ParamRegistry
+	caller := backends.NewBlockchainContractBackend(r.Chain, nil, nil)
+	contract, err := govcontract.NewGovParamCaller(registry.Address, caller)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make(gov.PartialParamSet)
+	opts := &bind.CallOpts{BlockNumber: parent.Number}
+	for _, name := range registry.Params {
+		exists, value, err := contract.GetParam(opts, name)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+		if err := ret.Add(name, value); err != nil {
+			logger.Debug("Skipping invalid parameter in the param registry", "name", name, "err", err)
+		}
+	}
+	return ret, nil
+}
+
+// ParamChangeBlocks returns nil because the registry values may change at any block.
+// The changes are therefore not found by the range queries of GovModule.
+func (r *registryGovModule) ParamChangeBlocks(from, to uint64) []uint64 {
+	return nil
+}
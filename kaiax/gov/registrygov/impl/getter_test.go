@@ -0,0 +1,130 @@
+package impl
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kaiachain/kaia/accounts/abi/bind"
+	"github.com/kaiachain/kaia/accounts/abi/bind/backends"
+	"github.com/kaiachain/kaia/blockchain"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	govcontract "github.com/kaiachain/kaia/contracts/contracts/system_contracts/gov"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/kaiax/gov"
+	"github.com/kaiachain/kaia/log"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createSimulateBackend(t *testing.T) (*bind.TransactOpts, *backends.SimulatedBackend, common.Address, *govcontract.GovParam) {
+	key, _ := crypto.GenerateKey()
+	owner := bind.NewKeyedTransactor(key)
+	owner.GasLimit = 10000000
+	alloc := blockchain.GenesisAlloc{owner.From: {Balance: big.NewInt(params.KAIA)}}
+
+	config := &params.ChainConfig{}
+	config.SetDefaults()
+	config.UnitPrice = 25e9
+	config.IstanbulCompatibleBlock = common.Big0
+	config.LondonCompatibleBlock = common.Big0
+	config.EthTxTypeCompatibleBlock = common.Big0
+	config.MagmaCompatibleBlock = common.Big0
+	config.KoreCompatibleBlock = common.Big0
+
+	sim := backends.NewSimulatedBackendWithDatabase(database.NewMemoryDBManager(), alloc, config)
+	address, _, contract, err := govcontract.DeployGovParam(owner, sim)
+	require.NoError(t, err)
+	sim.Commit()
+	return owner, sim, address, contract
+}
+
+func setParam(t *testing.T, sim *backends.SimulatedBackend, owner *bind.TransactOpts, gp *govcontract.GovParam, name string, val []byte) {
+	tx, err := gp.SetParamIn(owner, name, true, val, big.NewInt(1))
+	require.NoError(t, err)
+	sim.Commit()
+	receipt, _ := sim.TransactionReceipt(nil, tx.Hash())
+	require.NotNil(t, receipt)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+	sim.Commit() // activated
+}
+
+func TestEffectiveParamsPartial(t *testing.T) {
+	log.EnableLogForTest(log.LvlCrit, log.LvlError)
+	owner, sim, addr, gp := createSimulateBackend(t)
+	bc := sim.BlockChain()
+
+	setParam(t, sim, owner, gp, string(gov.GovernanceUnitPrice), []byte{0, 0, 0, 0, 0, 0, 0, 25})
+	setParam(t, sim, owner, gp, string(gov.Kip71GasTarget), []byte{0, 0, 0, 0, 0, 0, 0, 100})
+
+	r := NewRegistryGovModule()
+	require.NoError(t, r.Init(&InitOpts{
+		Chain: bc,
+		ChainConfig: &params.ChainConfig{ParamRegistry: &params.ParamRegistryConfig{
+			Address: addr,
+			Params:  []string{string(gov.GovernanceUnitPrice), string(gov.Kip71LowerBoundBaseFee)},
+		}},
+	}))
+
+	// Only the listed parameters are read, and those missing in the registry are ignored.
+	next := bc.CurrentBlock().NumberU64() + 1
+	assert.Equal(t, gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(25)}, r.EffectiveParamsPartial(next))
+	_, ok := r.paramsCache.Get(next)
+	assert.True(t, ok)
+
+	// A change in the registry takes effect from the next block.
+	setParam(t, sim, owner, gp, string(gov.GovernanceUnitPrice), []byte{0, 0, 0, 0, 0, 0, 0, 125})
+	assert.Equal(t, gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(25)}, r.EffectiveParamsPartial(next))
+	assert.Equal(t, gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(125)}, r.EffectiveParamsPartial(bc.CurrentBlock().NumberU64()+1))
+
+	// The returned set does not alias the cached entry.
+	r.EffectiveParamsPartial(next)[gov.GovernanceUnitPrice] = uint64(1)
+	assert.Equal(t, gov.PartialParamSet{gov.GovernanceUnitPrice: uint64(25)}, r.EffectiveParamsPartial(next))
+
+	// Nothing is read without the parent.
+	assert.Nil(t, r.EffectiveParamsPartial(bc.CurrentBlock().NumberU64()+2))
+	assert.Nil(t, r.EffectiveParamsPartial(0))
+}
+
+func TestEffectiveParamsPartialFailure(t *testing.T) {
+	log.EnableLogForTest(log.LvlCrit, log.LvlError)
+	_, sim, _, _ := createSimulateBackend(t)
+	bc := sim.BlockChain()
+	next := bc.CurrentBlock().NumberU64() + 1
+
+	// No code at the address.
+	r := NewRegistryGovModule()
+	require.NoError(t, r.Init(&InitOpts{
+		Chain: bc,
+		ChainConfig: &params.ChainConfig{ParamRegistry: &params.ParamRegistryConfig{
+			Address: common.HexToAddress("0x1234"),
+			Params:  []string{string(gov.GovernanceUnitPrice)},
+		}},
+	}))
+	failures := readFailureCounter.Count()
+	assert.Nil(t, r.EffectiveParamsPartial(next))
+	assert.Equal(t, int64(1), readFailureCounter.Count()-failures)
+	_, ok := r.paramsCache.Get(next)
+	assert.False(t, ok)
+
+	// Not configured.
+	r = NewRegistryGovModule()
+	require.NoError(t, r.Init(&InitOpts{Chain: bc, ChainConfig: &params.ChainConfig{}}))
+	assert.Nil(t, r.EffectiveParamsPartial(next))
+}
+
+func TestInit(t *testing.T) {
+	_, sim, addr, _ := createSimulateBackend(t)
+
+	r := NewRegistryGovModule()
+	assert.ErrorIs(t, r.Init(nil), ErrInitNil)
+	assert.ErrorIs(t, r.Init(&InitOpts{
+		Chain: sim.BlockChain(),
+		ChainConfig: &params.ChainConfig{ParamRegistry: &params.ParamRegistryConfig{
+			Address: addr,
+			Params:  []string{"governance.unknown"},
+		}},
+	}), ErrInvalidRegistryParam)
+}
@@ -0,0 +1,82 @@
+package impl
+
+import (
+	"fmt"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/kaiachain/kaia/blockchain"
+	"github.com/kaiachain/kaia/blockchain/state"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/gov"
+	"github.com/kaiachain/kaia/kaiax/gov/registrygov"
+	"github.com/kaiachain/kaia/log"
+	"github.com/kaiachain/kaia/params"
+	"github.com/rcrowley/go-metrics"
+)
+
+var (
+	_ registrygov.RegistryGovModule = (*registryGovModule)(nil)
+
+	logger = log.NewModuleLogger(log.KaiaxGov)
+
+	paramsCacheSize = 128
+
+	readFailureCounter = metrics.NewRegisteredCounter("kaiax/gov/registry/failures", nil)
+)
+
+type chain interface {
+	blockchain.ChainContext
+
+	GetHeaderByNumber(number uint64) *types.Header
+	CurrentBlock() *types.Block
+	State() (*state.StateDB, error)
+	StateAt(root common.Hash) (*state.StateDB, error)
+	Config() *params.ChainConfig
+	GetBlock(hash common.Hash, number uint64) *types.Block
+}
+
+type InitOpts struct {
+	ChainConfig *params.ChainConfig
+	Chain       chain
+}
+
+type registryGovModule struct {
+	InitOpts
+
+	paramsCache *lru.Cache // (blockNum uint64) -> (*cachedParams)
+}
+
+func NewRegistryGovModule() *registryGovModule {
+	paramsCache, _ := lru.New(paramsCacheSize)
+	return &registryGovModule{
+		paramsCache: paramsCache,
+	}
+}
+
+func (r *registryGovModule) Init(opts *InitOpts) error {
+	if opts == nil || opts.ChainConfig == nil || opts.Chain == nil {
+		return ErrInitNil
+	}
+
+	if registry := opts.ChainConfig.ParamRegistry; registry != nil {
+		for _, name := range registry.Params {
+			if _, ok := gov.Params[gov.ParamName(name)]; !ok {
+				return fmt.Errorf("%w: %s", ErrInvalidRegistryParam, name)
+			}
+		}
+	}
+
+	r.InitOpts = *opts
+	return nil
+}
+
+func (r *registryGovModule) Start() error {
+	logger.Info("RegistryGovModule started")
+	r.paramsCache.Purge()
+	return nil
+}
+
+func (r *registryGovModule) Stop() {
+	logger.Info("RegistryGovModule stopped")
+}
@@ -0,0 +1,12 @@
+package registrygov
+
+import (
+	"github.com/kaiachain/kaia/kaiax"
+	"github.com/kaiachain/kaia/kaiax/gov"
+)
+
+//go:generate mockgen -destination=kaiax/gov/registrygov/mock/registrygov_mock.go github.com/kaiachain/kaia/kaiax/gov/registrygov RegistryGovModule
+type RegistryGovModule interface {
+	kaiax.BaseModule
+	gov.ParamSource
+}
@@ -0,0 +1,89 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kaiachain/kaia/kaiax/gov/registrygov (interfaces: RegistryGovModule)
+
+// Package mock_registrygov is a generated GoMock package.
+package mock_registrygov
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	gov "github.com/kaiachain/kaia/kaiax/gov"
+)
+
+// MockRegistryGovModule is a mock of RegistryGovModule interface.
+type MockRegistryGovModule struct {
+	ctrl     *gomock.Controller
+	recorder *MockRegistryGovModuleMockRecorder
+}
+
+// MockRegistryGovModuleMockRecorder is the mock recorder for MockRegistryGovModule.
+type MockRegistryGovModuleMockRecorder struct {
+	mock *MockRegistryGovModule
+}
+
+// NewMockRegistryGovModule creates a new mock instance.
+func NewMockRegistryGovModule(ctrl *gomock.Controller) *MockRegistryGovModule {
+	mock := &MockRegistryGovModule{ctrl: ctrl}
+	mock.recorder = &MockRegistryGovModuleMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRegistryGovModule) EXPECT() *MockRegistryGovModuleMockRecorder {
+	return m.recorder
+}
+
+// EffectiveParamsPartial mocks base method.
+func (m *MockRegistryGovModule) EffectiveParamsPartial(arg0 uint64) gov.PartialParamSet {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EffectiveParamsPartial", arg0)
+	ret0, _ := ret[0].(gov.PartialParamSet)
+	return ret0
+}
+
+// EffectiveParamsPartial indicates an expected call of EffectiveParamsPartial.
+func (mr *MockRegistryGovModuleMockRecorder) EffectiveParamsPartial(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EffectiveParamsPartial", reflect.TypeOf((*MockRegistryGovModule)(nil).EffectiveParamsPartial), arg0)
+}
+
+// ParamChangeBlocks mocks base method.
+func (m *MockRegistryGovModule) ParamChangeBlocks(arg0, arg1 uint64) []uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParamChangeBlocks", arg0, arg1)
+	ret0, _ := ret[0].([]uint64)
+	return ret0
+}
+
+// ParamChangeBlocks indicates an expected call of ParamChangeBlocks.
+func (mr *MockRegistryGovModuleMockRecorder) ParamChangeBlocks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParamChangeBlocks", reflect.TypeOf((*MockRegistryGovModule)(nil).ParamChangeBlocks), arg0, arg1)
+}
+
+// Start mocks base method.
+func (m *MockRegistryGovModule) Start() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockRegistryGovModuleMockRecorder) Start() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockRegistryGovModule)(nil).Start))
+}
+
+// Stop mocks base method.
+func (m *MockRegistryGovModule) Stop() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Stop")
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockRegistryGovModuleMockRecorder) Stop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockRegistryGovModule)(nil).Stop))
+}
@@ -0,0 +1,71 @@
+package gov
+
+import (
+	"math/big"
+
+	"github.com/kaiachain/kaia/common"
+)
+
+// Key binds a parameter name to the Go type of its canonical value, so that reading a
+// parameter through Get or Lookup is checked at compile time rather than by a type assertion
+// at the call site. For example, gov.Get(ps, gov.CommitteeSize) returns a uint64 and
+// gov.Get[string](ps, gov.CommitteeSize) does not compile.
+type Key[T any] struct {
+	Name ParamName
+}
+
+func (k Key[T]) String() string {
+	return string(k.Name)
+}
+
+// Typed keys. Each key's type must match the canonical type of the parameter in Params, which is unit-tested.
+var (
+	BlobBaseFee               = Key[uint64]{BlobTxBaseFee}
+	MaxBlobGasPerBlock        = Key[uint64]{BlobTxMaxGasPerBlock}
+	ActivationDelays          = Key[string]{GovernanceActivationDelays}
+	DeriveShaImpl             = Key[uint64]{GovernanceDeriveShaImpl}
+	GovernanceMode            = Key[string]{GovernanceGovernanceMode}
+	GoverningNode             = Key[common.Address]{GovernanceGoverningNode}
+	GovParamContract          = Key[common.Address]{GovernanceGovParamContract}
+	MultisigSigners           = Key[string]{GovernanceMultisigSigners}
+	MultisigThreshold         = Key[uint64]{GovernanceMultisigThreshold}
+	PausedContracts           = Key[string]{GovernancePausedContracts}
+	PausedTxTypes             = Key[string]{GovernancePausedTxTypes}
+	PauseExpiry               = Key[uint64]{GovernancePauseExpiry}
+	UnitPrice                 = Key[uint64]{GovernanceUnitPrice}
+	CommitteeSize             = Key[uint64]{IstanbulCommitteeSize}
+	Epoch                     = Key[uint64]{IstanbulEpoch}
+	ProposerPolicy            = Key[uint64]{IstanbulPolicy}
+	BaseFeeDenominator        = Key[uint64]{Kip71BaseFeeDenominator}
+	GasTarget                 = Key[uint64]{Kip71GasTarget}
+	LowerBoundBaseFee         = Key[uint64]{Kip71LowerBoundBaseFee}
+	MaxBlockGasUsedForBaseFee = Key[uint64]{Kip71MaxBlockGasUsedForBaseFee}
+	UpperBoundBaseFee         = Key[uint64]{Kip71UpperBoundBaseFee}
+	DeferredTxFee             = Key[bool]{RewardDeferredTxFee}
+	Kip82Ratio                = Key[string]{RewardKip82Ratio}
+	MintingAmount             = Key[*big.Int]{RewardMintingAmount}
+	MinimumStake              = Key[*big.Int]{RewardMinimumStake}
+	ProposerUpdateInterval    = Key[uint64]{RewardProposerUpdateInterval}
+	Ratio                     = Key[string]{RewardRatio}
+	StakingUpdateInterval     = Key[uint64]{RewardStakingUpdateInterval}
+	UseGiniCoeff              = Key[bool]{RewardUseGiniCoeff}
+)
+
+// Get returns the value of the parameter in the ParamSet.
+func Get[T any](p *ParamSet, key Key[T]) T {
+	v, _ := p.get(key.Name).(T)
+	return v
+}
+
+// Set sets the value of the parameter in the ParamSet.
+func Set[T any](p *ParamSet, key Key[T], v T) {
+	// Set only fails for an unknown name or a mismatching type, both of which the key rules out.
+	_ = p.Set(key.Name, v)
+}
+
+// Lookup returns the value of the parameter in the PartialParamSet and whether it is present.
+// A value of a different type is reported as absent.
+func Lookup[T any](p PartialParamSet, key Key[T]) (T, bool) {
+	v, ok := p[key.Name].(T)
+	return v, ok
+}
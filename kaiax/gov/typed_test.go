@@ -0,0 +1,69 @@
+package gov
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// Every parameter must have a typed key whose type matches the canonical type of its default value.
+func TestKeysMatchParams(t *testing.T) {
+	keys := map[ParamName]reflect.Type{}
+	add := func(name ParamName, v any) { keys[name] = reflect.TypeOf(v) }
+	for _, k := range []Key[uint64]{
+		BlobBaseFee, MaxBlobGasPerBlock, DeriveShaImpl, MultisigThreshold, PauseExpiry, UnitPrice, CommitteeSize, Epoch, ProposerPolicy,
+		BaseFeeDenominator, GasTarget, LowerBoundBaseFee, MaxBlockGasUsedForBaseFee, UpperBoundBaseFee,
+		ProposerUpdateInterval, StakingUpdateInterval,
+	} {
+		add(k.Name, uint64(0))
+	}
+	for _, k := range []Key[string]{ActivationDelays, GovernanceMode, MultisigSigners, PausedContracts, PausedTxTypes, Kip82Ratio, Ratio} {
+		add(k.Name, "")
+	}
+	for _, k := range []Key[common.Address]{GoverningNode, GovParamContract} {
+		add(k.Name, common.Address{})
+	}
+	for _, k := range []Key[*big.Int]{MintingAmount, MinimumStake} {
+		add(k.Name, new(big.Int))
+	}
+	for _, k := range []Key[bool]{DeferredTxFee, UseGiniCoeff} {
+		add(k.Name, false)
+	}
+
+	assert.Equal(t, len(Params), len(keys))
+	for name, param := range Params {
+		assert.Equal(t, reflect.TypeOf(param.DefaultValue), keys[name], name)
+	}
+}
+
+func TestGetSetLookup(t *testing.T) {
+	ps := GetDefaultGovernanceParamSet()
+	assert.Equal(t, uint64(21), Get(ps, CommitteeSize))
+	assert.Equal(t, "none", Get(ps, GovernanceMode))
+	assert.Equal(t, ps.MintingAmount, Get(ps, MintingAmount))
+
+	Set(ps, CommitteeSize, 7)
+	Set(ps, DeferredTxFee, true)
+	Set(ps, GoverningNode, common.HexToAddress("0xabcd"))
+	assert.Equal(t, uint64(7), ps.CommitteeSize)
+	assert.Equal(t, uint64(7), Get[uint64](ps, CommitteeSize))
+	assert.True(t, Get(ps, DeferredTxFee))
+	assert.Equal(t, common.HexToAddress("0xabcd"), Get(ps, GoverningNode))
+
+	// the typed getter and the map view agree
+	m := ps.ToMap()
+	assert.Equal(t, m[IstanbulCommitteeSize], Get(ps, CommitteeSize))
+	assert.Equal(t, m[RewardRatio], Get(ps, Ratio))
+
+	pps := PartialParamSet{IstanbulEpoch: uint64(100), RewardRatio: uint64(1)}
+	epoch, ok := Lookup(pps, Epoch)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(100), epoch)
+	_, ok = Lookup(pps, CommitteeSize)
+	assert.False(t, ok)
+	_, ok = Lookup(pps, Ratio) // wrong type
+	assert.False(t, ok)
+}
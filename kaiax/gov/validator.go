@@ -0,0 +1,253 @@
+package gov
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/kaiachain/kaia/common"
+)
+
+// Rules reported in ParamError.
+const (
+	RuleType       = "type"       // the value cannot be canonicalized to the parameter type
+	RuleRange      = "range"      // the value is out of the allowed range or options
+	RuleFormat     = "format"     // the value is not in the required format
+	RuleDependency = "dependency" // the value conflicts with other parameters
+)
+
+// ParamError is a structured parameter validation failure.
+// It satisfies errors.Is(err, ErrInvalidParamValue).
+type ParamError struct {
+	Name   ParamName
+	Value  any
+	Rule   string
+	Reason string
+	Err    error // the underlying error, if any
+}
+
+func (e *ParamError) Error() string {
+	return fmt.Sprintf("%v: %s=%v violates the %s rule: %s", ErrInvalidParamValue, e.Name, e.Value, e.Rule, e.Reason)
+}
+
+func (e *ParamError) Unwrap() []error {
+	if e.Err != nil {
+		return []error{ErrInvalidParamValue, e.Err}
+	}
+	return []error{ErrInvalidParamValue}
+}
+
+// ValueValidator checks a canonical value. Name and Value of the returned error are filled by the caller.
+type ValueValidator func(cv any) *ParamError
+
+func isType[T any]() ValueValidator {
+	return func(cv any) *ParamError {
+		if _, ok := cv.(T); !ok {
+			var zero T
+			return &ParamError{Rule: RuleType, Reason: fmt.Sprintf("must be %T", zero)}
+		}
+		return nil
+	}
+}
+
+func uint64Range(min, max uint64) ValueValidator {
+	return func(cv any) *ParamError {
+		v, _ := cv.(uint64)
+		if v < min || v > max {
+			reason := fmt.Sprintf("must be between %d and %d", min, max)
+			if max == math.MaxUint64 {
+				reason = fmt.Sprintf("must be at least %d", min)
+			}
+			return &ParamError{Rule: RuleRange, Reason: reason}
+		}
+		return nil
+	}
+}
+
+func stringOneOf(options ...string) ValueValidator {
+	return func(cv any) *ParamError {
+		v, _ := cv.(string)
+		for _, option := range options {
+			if v == option {
+				return nil
+			}
+		}
+		return &ParamError{Rule: RuleRange, Reason: "must be one of " + strings.Join(options, ", ")}
+	}
+}
+
+func bigIntNonNegative() ValueValidator {
+	return func(cv any) *ParamError {
+		if v, _ := cv.(*big.Int); v == nil || v.Sign() < 0 {
+			return &ParamError{Rule: RuleRange, Reason: "must not be negative"}
+		}
+		return nil
+	}
+}
+
+// ratio requires n non-negative integers separated by '/' whose sum is 100.
+func ratio(n int) ValueValidator {
+	return func(cv any) *ParamError {
+		v, _ := cv.(string)
+		parts := strings.Split(v, "/")
+		if len(parts) != n {
+			return &ParamError{Rule: RuleFormat, Reason: fmt.Sprintf("must have %d parts separated by '/'", n)}
+		}
+		sum := 0
+		for _, part := range parts {
+			num, err := strconv.Atoi(part)
+			if err != nil || num < 0 {
+				return &ParamError{Rule: RuleFormat, Reason: "each part must be a non-negative integer", Err: err}
+			}
+			sum += num
+		}
+		if sum != 100 {
+			return &ParamError{Rule: RuleRange, Reason: fmt.Sprintf("parts must sum to 100, not %d", sum)}
+		}
+		return nil
+	}
+}
+
+// addressList requires comma-separated hex addresses, or an empty string.
+func addressList() ValueValidator {
+	return func(cv any) *ParamError {
+		v, _ := cv.(string)
+		if _, err := ParseAddressList(v); err != nil {
+			return &ParamError{Rule: RuleFormat, Reason: "must be comma-separated addresses", Err: err}
+		}
+		return nil
+	}
+}
+
+// ParseAddressList parses comma-separated hex addresses. An empty string is an empty list.
+func ParseAddressList(v string) ([]common.Address, error) {
+	if v == "" {
+		return nil, nil
+	}
+	ret := []common.Address{}
+	for _, address := range strings.Split(v, ",") {
+		if !common.IsHexAddress(address) {
+			return nil, ErrCanonicalizeStringToAddress
+		}
+		ret = append(ret, common.HexToAddress(address))
+	}
+	return ret, nil
+}
+
+// activationDelays requires comma-separated name:blocks pairs of known params, or an empty string.
+func activationDelays() ValueValidator {
+	return func(cv any) *ParamError {
+		v, _ := cv.(string)
+		if _, err := ParseActivationDelays(v); err != nil {
+			return &ParamError{Rule: RuleFormat, Reason: "must be comma-separated name:blocks pairs", Err: err}
+		}
+		return nil
+	}
+}
+
+// ParseActivationDelays parses comma-separated name:blocks pairs such as "governance.unitprice:604800"
+// into the minimum number of blocks between the ratification and the activation of each param.
+// An empty string has no delay.
+func ParseActivationDelays(v string) (map[ParamName]uint64, error) {
+	ret := make(map[ParamName]uint64)
+	if v == "" {
+		return ret, nil
+	}
+	for _, pair := range strings.Split(v, ",") {
+		name, blocks, found := strings.Cut(pair, ":")
+		if !found {
+			return nil, ErrInvalidActivationDelay
+		}
+		if _, ok := Params[ParamName(name)]; !ok {
+			return nil, ErrInvalidParamName
+		}
+		if _, ok := ret[ParamName(name)]; ok {
+			return nil, ErrInvalidActivationDelay
+		}
+		delay, err := strconv.ParseUint(blocks, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidActivationDelay, err)
+		}
+		ret[ParamName(name)] = delay
+	}
+	return ret, nil
+}
+
+// txTypeList requires comma-separated tx type names such as TxTypeValueTransfer, or an empty string.
+func txTypeList() ValueValidator {
+	return func(cv any) *ParamError {
+		v, _ := cv.(string)
+		if _, err := parseTxTypeList(v); err != nil {
+			return &ParamError{Rule: RuleFormat, Reason: "must be comma-separated tx type names", Err: err}
+		}
+		return nil
+	}
+}
+
+// Canonicalize returns the canonical value, or a *ParamError if the value
+// cannot be canonicalized or fails any of the validators.
+func (p *Param) Canonicalize(name ParamName, value any) (any, error) {
+	cv, err := p.Canonicalizer(value)
+	if err != nil {
+		return nil, &ParamError{Name: name, Value: value, Rule: RuleType, Reason: err.Error(), Err: err}
+	}
+	for _, validate := range p.Validators {
+		if perr := validate(cv); perr != nil {
+			perr.Name, perr.Value = name, cv
+			return nil, perr
+		}
+	}
+	return cv, nil
+}
+
+// Dependency is a constraint among the values of several parameters.
+type Dependency struct {
+	Params []ParamName
+	Desc   string
+	Check  func(ps *ParamSet) bool
+}
+
+var Dependencies = []*Dependency{
+	{
+		Params: []ParamName{Kip71LowerBoundBaseFee, Kip71UpperBoundBaseFee},
+		Desc:   "kip71.lowerboundbasefee <= kip71.upperboundbasefee",
+		Check: func(ps *ParamSet) bool {
+			return ps.LowerBoundBaseFee <= ps.UpperBoundBaseFee
+		},
+	},
+	{
+		Params: []ParamName{GovernanceMultisigSigners, GovernanceMultisigThreshold},
+		Desc:   "governance.multisigthreshold <= number of governance.multisigsigners, and not zero in multisig mode",
+		Check: func(ps *ParamSet) bool {
+			signers, _ := ParseAddressList(ps.MultisigSigners)
+			if ps.MultisigThreshold > uint64(len(signers)) {
+				return false
+			}
+			return ps.GovernanceMode != "multisig" || ps.MultisigThreshold > 0
+		},
+	},
+}
+
+// CheckDependencies applies the changes to a copy of ps and checks the dependencies involving
+// any of the changed parameters. It returns a *ParamError naming the first changed parameter
+// of the first violated dependency.
+func CheckDependencies(ps ParamSet, changes PartialParamSet) error {
+	if err := ps.SetFromMap(changes); err != nil {
+		return err
+	}
+	for _, dep := range Dependencies {
+		for _, name := range dep.Params {
+			value, ok := changes[name]
+			if !ok {
+				continue
+			}
+			if !dep.Check(&ps) {
+				return &ParamError{Name: name, Value: value, Rule: RuleDependency, Reason: dep.Desc}
+			}
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package gov
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParamCanonicalize(t *testing.T) {
+	tcs := []struct {
+		name     ParamName
+		value    any
+		expected any
+		rule     string // empty if valid
+	}{
+		{name: GovernanceUnitPrice, value: uint64(25), expected: uint64(25)},
+		{name: GovernanceUnitPrice, value: "25", rule: RuleType},
+		{name: GovernanceDeriveShaImpl, value: uint64(2), expected: uint64(2)},
+		{name: GovernanceDeriveShaImpl, value: uint64(3), rule: RuleRange},
+		{name: GovernanceGovernanceMode, value: "stake", expected: "stake"},
+		{name: GovernanceGovernanceMode, value: "ballot", rule: RuleRange},
+		{name: GovernanceGoverningNode, value: "0x01", rule: RuleType},
+		{name: GovernanceGoverningNode, value: common.Address{1}.Hex(), expected: common.Address{1}},
+		{name: IstanbulCommitteeSize, value: uint64(0), rule: RuleRange},
+		{name: Kip71BaseFeeDenominator, value: uint64(0), rule: RuleRange},
+		{name: RewardMinimumStake, value: "-1", rule: RuleRange},
+		{name: RewardMinimumStake, value: "5000000", expected: big.NewInt(5000000)},
+		{name: RewardRatio, value: "50/25/25", expected: "50/25/25"},
+		{name: RewardRatio, value: "50/50", rule: RuleFormat},
+		{name: RewardRatio, value: "50/x/25", rule: RuleFormat},
+		{name: RewardRatio, value: "50/25/24", rule: RuleRange},
+		{name: RewardKip82Ratio, value: "20/80", expected: "20/80"},
+		{name: RewardKip82Ratio, value: "-20/120", rule: RuleFormat},
+		{name: GovernanceActivationDelays, value: "governance.unitprice:604800,reward.ratio:86400", expected: "governance.unitprice:604800,reward.ratio:86400"},
+		{name: GovernanceActivationDelays, value: "governance.unknown:604800", rule: RuleFormat},
+		{name: GovernanceActivationDelays, value: "governance.unitprice", rule: RuleFormat},
+		{name: GovernanceActivationDelays, value: "governance.unitprice:-1", rule: RuleFormat},
+		{name: GovernanceActivationDelays, value: "governance.unitprice:1,governance.unitprice:2", rule: RuleFormat},
+	}
+
+	for _, tc := range tcs {
+		t.Run(string(tc.name), func(t *testing.T) {
+			cv, err := Params[tc.name].Canonicalize(tc.name, tc.value)
+			if tc.rule == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, cv)
+				return
+			}
+			var perr *ParamError
+			assert.True(t, errors.As(err, &perr), err)
+			assert.True(t, errors.Is(err, ErrInvalidParamValue))
+			assert.Equal(t, tc.name, perr.Name)
+			assert.Equal(t, tc.rule, perr.Rule)
+		})
+	}
+}
+
+func TestCheckDependencies(t *testing.T) {
+	ps := *GetDefaultGovernanceParamSet()
+	ps.LowerBoundBaseFee, ps.UpperBoundBaseFee = 25, 750
+
+	tcs := []struct {
+		desc     string
+		changes  PartialParamSet
+		expected error
+	}{
+		{desc: "no changes", changes: nil},
+		{desc: "unrelated", changes: PartialParamSet{GovernanceUnitPrice: uint64(1000)}},
+		{desc: "valid lower", changes: PartialParamSet{Kip71LowerBoundBaseFee: uint64(750)}},
+		{desc: "valid both", changes: PartialParamSet{Kip71LowerBoundBaseFee: uint64(1000), Kip71UpperBoundBaseFee: uint64(2000)}},
+		{
+			desc:     "invalid lower",
+			changes:  PartialParamSet{Kip71LowerBoundBaseFee: uint64(751)},
+			expected: &ParamError{Name: Kip71LowerBoundBaseFee, Value: uint64(751), Rule: RuleDependency, Reason: "kip71.lowerboundbasefee <= kip71.upperboundbasefee"},
+		},
+		{
+			desc:     "invalid upper",
+			changes:  PartialParamSet{Kip71UpperBoundBaseFee: uint64(24)},
+			expected: &ParamError{Name: Kip71UpperBoundBaseFee, Value: uint64(24), Rule: RuleDependency, Reason: "kip71.lowerboundbasefee <= kip71.upperboundbasefee"},
+		},
+		{desc: "invalid type", changes: PartialParamSet{Kip71UpperBoundBaseFee: "24"}, expected: ErrInvalidParamValue},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.expected, CheckDependencies(ps, tc.changes))
+		})
+	}
+	// The given set is not modified.
+	assert.Equal(t, uint64(25), ps.LowerBoundBaseFee)
+}
+
+func TestCheckMultisigDependency(t *testing.T) {
+	var (
+		twoSigners = "0x0000000000000000000000000000000000000001,0x0000000000000000000000000000000000000002"
+		ps         = *GetDefaultGovernanceParamSet()
+	)
+	ps.GovernanceMode, ps.MultisigSigners, ps.MultisigThreshold = "multisig", twoSigners, 2
+
+	assert.NoError(t, CheckDependencies(ps, PartialParamSet{GovernanceMultisigThreshold: uint64(1)}))
+	assert.NoError(t, CheckDependencies(ps, PartialParamSet{GovernanceMultisigSigners: twoSigners + ",0x0000000000000000000000000000000000000003"}))
+
+	for _, changes := range []PartialParamSet{
+		{GovernanceMultisigThreshold: uint64(3)},
+		{GovernanceMultisigThreshold: uint64(0)},
+		{GovernanceMultisigSigners: "0x0000000000000000000000000000000000000001"},
+	} {
+		var perr *ParamError
+		if assert.ErrorAs(t, CheckDependencies(ps, changes), &perr) {
+			assert.Equal(t, RuleDependency, perr.Rule)
+		}
+	}
+}
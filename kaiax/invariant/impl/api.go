@@ -0,0 +1,55 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package invariant
+
+import (
+	"github.com/kaiachain/kaia/kaiax/invariant"
+	"github.com/kaiachain/kaia/networks/rpc"
+)
+
+func (s *InvariantModule) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   NewDebugInvariantAPI(s),
+			Public:    false,
+		},
+	}
+}
+
+type DebugInvariantAPI struct {
+	s *InvariantModule
+}
+
+func NewDebugInvariantAPI(s *InvariantModule) *DebugInvariantAPI {
+	return &DebugInvariantAPI{s: s}
+}
+
+// InvariantStatus returns the action, the halt state and the recent violations of the invariant checker.
+func (api *DebugInvariantAPI) InvariantStatus() *invariant.Status {
+	s := api.s
+	s.mu.RLock()
+	checked := s.checked
+	s.mu.RUnlock()
+	return &invariant.Status{
+		Action:     s.Action,
+		Halted:     s.Halted(),
+		Checked:    checked,
+		Violations: s.Violations(),
+	}
+}
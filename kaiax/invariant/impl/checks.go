@@ -0,0 +1,235 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package invariant
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/blockchain/types/account"
+	"github.com/kaiachain/kaia/blockchain/types/accountkey"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/kaiax/invariant"
+	"github.com/kaiachain/kaia/rlp"
+	"github.com/kaiachain/kaia/storage/statedb"
+)
+
+var (
+	// The canonical burn addresses are excluded from the supply sum because
+	// the supply module accounts their balances as burnt.
+	zeroBurnKey = common.BytesToHash(crypto.Keccak256(common.HexToAddress("0x0").Bytes()))
+	deadBurnKey = common.BytesToHash(crypto.Keccak256(common.HexToAddress("0xdead").Bytes()))
+)
+
+// accountDiff is an account changed by a block. old is nil if created, new is nil if deleted.
+type accountDiff struct {
+	old account.Account
+	new account.Account
+}
+
+func (s *InvariantModule) checkBlock(block *types.Block) ([]*invariant.Violation, error) {
+	num := block.NumberU64()
+	if num == 0 {
+		return nil, nil
+	}
+	parent := s.Chain.GetHeaderByHash(block.ParentHash())
+	if parent == nil {
+		return nil, fmt.Errorf("parent header not found: %x", block.ParentHash())
+	}
+	diffs, err := s.diffAccounts(parent.Root, block.Root())
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []*invariant.Violation
+	report := func(name, format string, args ...interface{}) {
+		violations = append(violations, &invariant.Violation{
+			Number:    num,
+			Hash:      block.Hash(),
+			Invariant: name,
+			Detail:    fmt.Sprintf(format, args...),
+		})
+	}
+
+	s.checkAccountKeys(num, diffs, report)
+	s.checkNonceRegression(num, diffs, report)
+	if err := s.checkSenderNonces(block, report); err != nil {
+		return violations, err
+	}
+	if err := s.checkSupply(num, diffs, report); err != nil {
+		return violations, err
+	}
+	return violations, nil
+}
+
+// diffAccounts collects the accounts that differ between the two state roots, keyed by the hashed address.
+func (s *InvariantModule) diffAccounts(oldRoot, newRoot common.Hash) (map[common.Hash]*accountDiff, error) {
+	trieDB := s.Chain.StateCache().TrieDB()
+	oldTrie, err := statedb.NewSecureTrie(oldRoot, trieDB, nil)
+	if err != nil {
+		return nil, err
+	}
+	newTrie, err := statedb.NewSecureTrie(newRoot, trieDB, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := make(map[common.Hash]*accountDiff)
+	get := func(key common.Hash) *accountDiff {
+		if d, ok := diffs[key]; ok {
+			return d
+		}
+		d := &accountDiff{}
+		diffs[key] = d
+		return d
+	}
+	// Leaves only in the new trie are created or modified accounts,
+	// and leaves only in the old trie are modified or deleted accounts.
+	if err := iterateDiff(oldTrie, newTrie, func(key common.Hash, acc account.Account) { get(key).new = acc }); err != nil {
+		return nil, err
+	}
+	if err := iterateDiff(newTrie, oldTrie, func(key common.Hash, acc account.Account) { get(key).old = acc }); err != nil {
+		return nil, err
+	}
+	return diffs, nil
+}
+
+// iterateDiff calls fn for every account leaf in b that is not in a.
+func iterateDiff(a, b *statedb.SecureTrie, fn func(common.Hash, account.Account)) error {
+	diff, _ := statedb.NewDifferenceIterator(a.NodeIterator(nil), b.NodeIterator(nil))
+	it := statedb.NewIterator(diff)
+	for it.Next() {
+		serializer := account.NewAccountSerializer()
+		if err := rlp.DecodeBytes(it.Value, serializer); err != nil {
+			return fmt.Errorf("malformed account %x: %w", it.Key, err)
+		}
+		fn(common.BytesToHash(it.Key), serializer.GetAccount())
+	}
+	return it.Err
+}
+
+// checkAccountKeys reports changed account keys that could not have been installed,
+// and changes of AccountKeyFail, which is never updatable.
+func (s *InvariantModule) checkAccountKeys(num uint64, diffs map[common.Hash]*accountDiff, report func(string, string, ...interface{})) {
+	for key, d := range diffs {
+		if d.new == nil {
+			continue
+		}
+		newKey := accountKeyOf(d.new)
+		oldKey := accountKeyOf(d.old)
+		if newKey == nil || (oldKey != nil && oldKey.Equal(newKey)) {
+			continue
+		}
+		if oldKey != nil && oldKey.Type() == accountkey.AccountKeyTypeFail {
+			report(invariant.InvariantAccountKey, "account %x replaced AccountKeyFail with key type %d", key, newKey.Type())
+			continue
+		}
+		if err := newKey.CheckInstallable(num); err != nil {
+			report(invariant.InvariantAccountKey, "account %x has an uninstallable key of type %d: %v", key, newKey.Type(), err)
+		}
+	}
+}
+
+func accountKeyOf(acc account.Account) accountkey.AccountKey {
+	if acc == nil {
+		return nil
+	}
+	if ak := account.GetAccountWithKey(acc); ak != nil {
+		return ak.GetKey()
+	}
+	return nil
+}
+
+// checkNonceRegression reports accounts whose nonce decreased.
+// Before Cancun, a contract can be self-destructed and re-created in one block, resetting its nonce.
+func (s *InvariantModule) checkNonceRegression(num uint64, diffs map[common.Hash]*accountDiff, report func(string, string, ...interface{})) {
+	recreatable := !s.ChainConfig.IsCancunForkEnabled(new(big.Int).SetUint64(num))
+	for key, d := range diffs {
+		if d.old == nil || d.new == nil || d.new.GetNonce() >= d.old.GetNonce() {
+			continue
+		}
+		if recreatable && d.new.Type() == account.SmartContractAccountType {
+			continue
+		}
+		report(invariant.InvariantNonce, "account %x nonce decreased from %d to %d", key, d.old.GetNonce(), d.new.GetNonce())
+	}
+}
+
+// checkSenderNonces reports transaction senders whose nonce did not advance past their transactions.
+func (s *InvariantModule) checkSenderNonces(block *types.Block, report func(string, string, ...interface{})) error {
+	txs := block.Transactions()
+	if len(txs) == 0 {
+		return nil
+	}
+	signer := types.MakeSigner(s.ChainConfig, block.Number())
+	want := make(map[common.Address]uint64)
+	for _, tx := range txs {
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			return err
+		}
+		if n := tx.Nonce() + 1; n > want[from] {
+			want[from] = n
+		}
+	}
+	statedb, err := s.Chain.StateAt(block.Root())
+	if err != nil {
+		return err
+	}
+	for from, n := range want {
+		if got := statedb.GetNonce(from); got < n {
+			report(invariant.InvariantNonce, "sender %s has nonce %d, want at least %d", from.Hex(), got, n)
+		}
+	}
+	return nil
+}
+
+// checkSupply reports a block whose sum of balance changes differs from the change of the total supply.
+// Both sides exclude the canonical burn addresses.
+func (s *InvariantModule) checkSupply(num uint64, diffs map[common.Hash]*accountDiff, report func(string, string, ...interface{})) error {
+	if s.SupplyModule == nil {
+		return nil
+	}
+	prev, err := s.SupplyModule.GetTotalSupply(num - 1)
+	if prev == nil || prev.TotalSupply == nil {
+		return fmt.Errorf("total supply at %d unavailable: %w", num-1, err)
+	}
+	curr, err := s.SupplyModule.GetTotalSupply(num)
+	if curr == nil || curr.TotalSupply == nil {
+		return fmt.Errorf("total supply at %d unavailable: %w", num, err)
+	}
+	expected := new(big.Int).Sub(curr.TotalSupply, prev.TotalSupply)
+
+	actual := new(big.Int)
+	for key, d := range diffs {
+		if key == zeroBurnKey || key == deadBurnKey {
+			continue
+		}
+		if d.new != nil {
+			actual.Add(actual, d.new.GetBalance())
+		}
+		if d.old != nil {
+			actual.Sub(actual, d.old.GetBalance())
+		}
+	}
+	if actual.Cmp(expected) != 0 {
+		report(invariant.InvariantSupply, "balances changed by %v, but total supply changed by %v", actual, expected)
+	}
+	return nil
+}
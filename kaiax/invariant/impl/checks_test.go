@@ -0,0 +1,237 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package invariant
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kaiachain/kaia/blockchain/state"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/blockchain/types/account"
+	"github.com/kaiachain/kaia/blockchain/types/accountkey"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/kaiax/invariant"
+	"github.com/kaiachain/kaia/kaiax/supply"
+	supply_mock "github.com/kaiachain/kaia/kaiax/supply/mock"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/rlp"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/kaiachain/kaia/storage/statedb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testChain struct {
+	db      state.Database
+	headers map[common.Hash]*types.Header
+}
+
+func (c *testChain) GetHeaderByHash(hash common.Hash) *types.Header { return c.headers[hash] }
+func (c *testChain) StateAt(root common.Hash) (*state.StateDB, error) {
+	return state.New(root, c.db, nil, nil)
+}
+func (c *testChain) StateCache() state.Database { return c.db }
+
+type testAccount struct {
+	nonce   uint64
+	balance int64
+	key     accountkey.AccountKey // AccountKeyLegacy if nil
+}
+
+// writeState commits the accounts to a new state trie and returns its root.
+func (c *testChain) writeState(t *testing.T, accounts map[common.Address]testAccount) common.Hash {
+	trie, err := statedb.NewSecureTrie(common.Hash{}, c.db.TrieDB(), nil)
+	require.NoError(t, err)
+	for addr, a := range accounts {
+		key := a.key
+		if key == nil {
+			key = accountkey.NewAccountKeyLegacy()
+		}
+		acc, err := account.NewAccountWithMap(account.ExternallyOwnedAccountType, map[account.AccountValueKeyType]interface{}{
+			account.AccountValueKeyNonce:      a.nonce,
+			account.AccountValueKeyBalance:    big.NewInt(a.balance),
+			account.AccountValueKeyAccountKey: key,
+		})
+		require.NoError(t, err)
+		enc, err := rlp.EncodeToBytes(account.NewAccountSerializerWithAccount(acc))
+		require.NoError(t, err)
+		require.NoError(t, trie.TryUpdate(addr.Bytes(), enc))
+	}
+	root, err := trie.Commit(nil)
+	require.NoError(t, err)
+	return root
+}
+
+// makeBlocks returns the block 1 on top of the block 0 with the given states.
+func (c *testChain) makeBlocks(t *testing.T, before, after map[common.Address]testAccount, txs []*types.Transaction) *types.Block {
+	parent := &types.Header{Number: big.NewInt(0), Root: c.writeState(t, before)}
+	c.headers[parent.Hash()] = parent
+	header := &types.Header{Number: big.NewInt(1), ParentHash: parent.Hash(), Root: c.writeState(t, after)}
+	return types.NewBlockWithHeader(header).WithBody(txs)
+}
+
+func newTestModule(t *testing.T, ctrl *gomock.Controller, action string, supplyDelta int64) (*InvariantModule, *testChain) {
+	chain := &testChain{
+		db:      state.NewDatabase(database.NewMemoryDBManager()),
+		headers: make(map[common.Hash]*types.Header),
+	}
+	mSupply := supply_mock.NewMockSupplyModule(ctrl)
+	mSupply.EXPECT().GetTotalSupply(uint64(0)).Return(&supply.TotalSupply{TotalSupply: big.NewInt(1000)}, nil).AnyTimes()
+	mSupply.EXPECT().GetTotalSupply(uint64(1)).Return(&supply.TotalSupply{TotalSupply: big.NewInt(1000 + supplyDelta)}, nil).AnyTimes()
+
+	config := params.TestChainConfig.Copy()
+	config.CancunCompatibleBlock = big.NewInt(0)
+
+	s := NewInvariantModule()
+	require.NoError(t, s.Init(&InitOpts{
+		ChainConfig:  config,
+		Chain:        chain,
+		SupplyModule: mSupply,
+		Action:       action,
+	}))
+	return s, chain
+}
+
+func signTx(t *testing.T, prv *ecdsa.PrivateKey, nonce uint64) *types.Transaction {
+	signer := types.LatestSignerForChainID(params.TestChainConfig.ChainID)
+	tx, err := types.SignTx(types.NewTransaction(nonce, common.Address{1}, big.NewInt(10), 21000, big.NewInt(0), nil), signer, prv)
+	require.NoError(t, err)
+	return tx
+}
+
+func TestCheckBlock(t *testing.T) {
+	prv, _ := crypto.GenerateKey()
+	var (
+		a    = crypto.PubkeyToAddress(prv.PublicKey)
+		b    = common.Address{1}
+		dead = common.HexToAddress("0xdead")
+		fail = accountkey.NewAccountKeyFail()
+	)
+
+	testcases := []struct {
+		desc          string
+		before, after map[common.Address]testAccount
+		txNonces      []uint64
+		supplyDelta   int64
+		expected      []string // violated invariants
+	}{
+		{
+			desc:     "transfer",
+			before:   map[common.Address]testAccount{a: {0, 100, nil}},
+			after:    map[common.Address]testAccount{a: {1, 90, nil}, b: {0, 10, nil}},
+			txNonces: []uint64{0},
+		},
+		{
+			desc:        "canonical burn",
+			before:      map[common.Address]testAccount{a: {0, 100, nil}},
+			after:       map[common.Address]testAccount{a: {1, 90, nil}, dead: {0, 10, nil}},
+			txNonces:    []uint64{0},
+			supplyDelta: -10,
+		},
+		{
+			desc:     "unaccounted mint",
+			before:   map[common.Address]testAccount{a: {0, 100, nil}},
+			after:    map[common.Address]testAccount{a: {1, 95, nil}, b: {0, 10, nil}},
+			txNonces: []uint64{0},
+			expected: []string{invariant.InvariantSupply},
+		},
+		{
+			desc:     "deleted account",
+			before:   map[common.Address]testAccount{a: {0, 100, nil}, b: {0, 10, nil}},
+			after:    map[common.Address]testAccount{a: {0, 100, nil}},
+			expected: []string{invariant.InvariantSupply},
+		},
+		{
+			desc:     "nonce regression",
+			before:   map[common.Address]testAccount{a: {5, 100, nil}},
+			after:    map[common.Address]testAccount{a: {3, 100, nil}},
+			expected: []string{invariant.InvariantNonce},
+		},
+		{
+			desc:     "sender nonce not advanced",
+			before:   map[common.Address]testAccount{a: {3, 100, nil}},
+			after:    map[common.Address]testAccount{a: {3, 100, nil}},
+			txNonces: []uint64{3},
+			expected: []string{invariant.InvariantNonce},
+		},
+		{
+			desc:     "AccountKeyFail replaced",
+			before:   map[common.Address]testAccount{a: {0, 100, fail}},
+			after:    map[common.Address]testAccount{a: {0, 100, nil}},
+			expected: []string{invariant.InvariantAccountKey},
+		},
+		{
+			desc:     "uninstallable key",
+			before:   map[common.Address]testAccount{a: {0, 100, nil}},
+			after:    map[common.Address]testAccount{a: {0, 100, accountkey.NewAccountKeyWeightedMultiSig()}},
+			expected: []string{invariant.InvariantAccountKey},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s, chain := newTestModule(t, ctrl, invariant.ActionAlert, tc.supplyDelta)
+
+			var txs []*types.Transaction
+			for _, nonce := range tc.txNonces {
+				txs = append(txs, signTx(t, prv, nonce))
+			}
+			block := chain.makeBlocks(t, tc.before, tc.after, txs)
+
+			require.NoError(t, s.PostInsertBlock(block))
+			var violated []string
+			for _, v := range s.Violations() {
+				assert.Equal(t, block.Hash(), v.Hash)
+				violated = append(violated, v.Invariant)
+			}
+			assert.Equal(t, tc.expected, violated)
+		})
+	}
+}
+
+func TestHalt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s, chain := newTestModule(t, ctrl, invariant.ActionHalt, 0)
+
+	a := common.Address{2}
+	good := chain.makeBlocks(t, map[common.Address]testAccount{a: {0, 100, nil}}, map[common.Address]testAccount{a: {0, 100, nil}}, nil)
+	bad := chain.makeBlocks(t, map[common.Address]testAccount{a: {0, 100, nil}}, map[common.Address]testAccount{a: {0, 200, nil}}, nil)
+
+	assert.NoError(t, s.PostInsertBlock(good))
+	assert.False(t, s.Halted())
+
+	assert.ErrorIs(t, s.PostInsertBlock(bad), invariant.ErrInvariantViolation)
+	assert.True(t, s.Halted())
+
+	// Every block after the violation is rejected.
+	assert.ErrorIs(t, s.PostInsertBlock(good), invariant.ErrInvariantViolation)
+	assert.Len(t, s.Violations(), 1)
+}
+
+func TestInitAction(t *testing.T) {
+	chain := &testChain{}
+	s := NewInvariantModule()
+	assert.NoError(t, s.Init(&InitOpts{ChainConfig: params.TestChainConfig, Chain: chain}))
+	assert.Equal(t, invariant.ActionAlert, s.Action)
+	assert.ErrorIs(t, s.Init(&InitOpts{ChainConfig: params.TestChainConfig, Chain: chain, Action: "panic"}), invariant.ErrUnknownAction)
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package invariant
+
+import (
+	"fmt"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/kaiax/invariant"
+)
+
+// PostInsertBlock checks the invariants between the block and its parent.
+// Under invariant.ActionHalt, the first violation makes this and every later call fail,
+// which stops the block import until the node is restarted.
+func (s *InvariantModule) PostInsertBlock(block *types.Block) error {
+	if v := s.haltedBy(); v != nil {
+		return haltErr(v)
+	}
+
+	violations, err := s.checkBlock(block)
+	if err != nil {
+		// An incomplete check is not a violation; e.g. the parent state may have been pruned.
+		checkErrCounter.Inc(1)
+		logger.Warn("Failed to check chain invariants", "number", block.NumberU64(), "hash", block.Hash(), "err", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checked++
+	for _, v := range violations {
+		violationCounter.Inc(1)
+		logger.Error("Chain invariant violated", "invariant", v.Invariant, "number", v.Number, "hash", v.Hash, "detail", v.Detail)
+		if len(s.violations) >= maxViolations {
+			s.violations = s.violations[1:]
+		}
+		s.violations = append(s.violations, v)
+	}
+	if len(violations) > 0 && s.Action == invariant.ActionHalt {
+		s.halted = violations[0]
+		logger.Error("Block import halted by chain invariant violation; restart the node to resume", "number", block.NumberU64())
+		return haltErr(s.halted)
+	}
+	return nil
+}
+
+func (s *InvariantModule) haltedBy() *invariant.Violation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.halted
+}
+
+func haltErr(v *invariant.Violation) error {
+	return fmt.Errorf("%w: %s at block %d: %s", invariant.ErrInvariantViolation, v.Invariant, v.Number, v.Detail)
+}
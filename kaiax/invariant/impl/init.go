@@ -0,0 +1,93 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package invariant
+
+import (
+	"sync"
+
+	"github.com/kaiachain/kaia/blockchain/state"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/invariant"
+	"github.com/kaiachain/kaia/kaiax/supply"
+	"github.com/kaiachain/kaia/log"
+	"github.com/kaiachain/kaia/params"
+	"github.com/rcrowley/go-metrics"
+)
+
+var (
+	_ invariant.InvariantModule = &InvariantModule{}
+
+	logger = log.NewModuleLogger(log.KaiaxInvariant)
+
+	violationCounter = metrics.NewRegisteredCounter("kaiax/invariant/violations", nil)
+	checkErrCounter  = metrics.NewRegisteredCounter("kaiax/invariant/errors", nil)
+
+	maxViolations = 1024 // number of recent violations kept in memory
+)
+
+type blockChain interface {
+	GetHeaderByHash(hash common.Hash) *types.Header
+	StateAt(root common.Hash) (*state.StateDB, error)
+	StateCache() state.Database
+}
+
+type InitOpts struct {
+	ChainConfig *params.ChainConfig
+	Chain       blockChain
+
+	// Optional. If nil, the supply invariant is not checked.
+	SupplyModule supply.SupplyModule
+
+	// Either invariant.ActionAlert or invariant.ActionHalt. Defaults to invariant.ActionAlert.
+	Action string
+}
+
+type InvariantModule struct {
+	InitOpts
+
+	mu         sync.RWMutex
+	violations []*invariant.Violation
+	halted     *invariant.Violation // the violation that halted the block import, if any
+	checked    uint64
+}
+
+func NewInvariantModule() *InvariantModule {
+	return &InvariantModule{}
+}
+
+func (s *InvariantModule) Init(opts *InitOpts) error {
+	if opts == nil || opts.ChainConfig == nil || opts.Chain == nil {
+		return invariant.ErrInitUnexpectedNil
+	}
+	switch opts.Action {
+	case "":
+		opts.Action = invariant.ActionAlert
+	case invariant.ActionAlert, invariant.ActionHalt:
+	default:
+		return invariant.ErrUnknownAction
+	}
+	s.InitOpts = *opts
+	return nil
+}
+
+func (s *InvariantModule) Start() error {
+	logger.Info("Chain invariant checker enabled", "action", s.Action, "supply", s.SupplyModule != nil)
+	return nil
+}
+
+func (s *InvariantModule) Stop() {}
@@ -0,0 +1,48 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package invariant
+
+import (
+	"github.com/kaiachain/kaia/common"
+)
+
+// Invariants checked on every imported block.
+const (
+	InvariantSupply     = "supply"     // balance changes match the minted and burnt amounts
+	InvariantNonce      = "nonce"      // account nonces never decrease, senders' nonces advance
+	InvariantAccountKey = "accountKey" // stored keys are installable, AccountKeyFail is permanent
+)
+
+// Actions taken on a violation.
+const (
+	ActionAlert = "alert" // log and record the violation
+	ActionHalt  = "halt"  // additionally reject the block and every block after it
+)
+
+type Violation struct {
+	Number    uint64      `json:"number"`
+	Hash      common.Hash `json:"hash"`
+	Invariant string      `json:"invariant"`
+	Detail    string      `json:"detail"`
+}
+
+type Status struct {
+	Action     string       `json:"action"`
+	Halted     bool         `json:"halted"`
+	Checked    uint64       `json:"checked"` // number of blocks checked since the start
+	Violations []*Violation `json:"violations"`
+}
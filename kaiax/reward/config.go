@@ -41,6 +41,7 @@ type RewardConfig struct {
 	DeferredTxFee bool              // reward.deferredtxfee
 	RewardRatio   *RewardRatio      // reward.ratio
 	Kip82Ratio    *RewardKip82Ratio // reward.kip82ratio
+	BlobBaseFee   *big.Int          // blobtx.basefee; nil before the blob tx hardfork
 }
 
 // TODO-kaiax: Restore to gov.GovModule after introducing kaiax/gov
@@ -60,6 +61,9 @@ func NewRewardConfig(chainConfig *params.ChainConfig, govModule GovModule, heade
 	rc.MintingAmount = new(big.Int).Set(paramset.MintingAmount)
 	rc.MinimumStake = new(big.Int).Set(paramset.MinimumStake)
 	rc.DeferredTxFee = paramset.DeferredTxFee
+	if rc.Rules.IsBlobTx {
+		rc.BlobBaseFee = new(big.Int).SetUint64(paramset.BlobBaseFee)
+	}
 
 	if ratio, err := NewRewardRatio(paramset.Ratio); err != nil {
 		return nil, err
@@ -177,8 +177,25 @@ func (r *RewardModule) getDeferredReward(config *reward.RewardConfig, header *ty
 	}
 }
 
-// getTotalFee calculates the total transaction fees in the block.
+// getTotalFee calculates the total transaction fees in the block, including the blob fees.
 func getTotalFee(config *reward.RewardConfig, header *types.Header, txs []*types.Transaction, receipts []*types.Receipt) (*big.Int, error) {
+	totalFee, err := getTotalGasFee(config, header, txs, receipts)
+	if err != nil {
+		return nil, err
+	}
+	if config.BlobBaseFee != nil {
+		// sum { tx[i].blobGas * blobBaseFee }
+		blobGas := new(big.Int)
+		for _, tx := range txs {
+			blobGas.Add(blobGas, new(big.Int).SetUint64(tx.BlobGas()))
+		}
+		totalFee.Add(totalFee, blobGas.Mul(blobGas, config.BlobBaseFee))
+	}
+	return totalFee, nil
+}
+
+// getTotalGasFee calculates the total fees for the gas used in the block.
+func getTotalGasFee(config *reward.RewardConfig, header *types.Header, txs []*types.Transaction, receipts []*types.Receipt) (*big.Int, error) {
 	if config.Rules.IsKaia {
 		// sum { tx[i].gasUsed * tx[i].effectiveGasPrice }
 		// = block.gasUsed * block.baseFeePerGas + sum { tx[i].gasUsed * tx[i].effectiveGasTip }
@@ -412,6 +412,20 @@ func TestGetTotalFee(t *testing.T) {
 			},
 			big.NewInt(0.0376e18), // sum{ (effectiveGasTip[i] + baseFee) * gasUsed[i] }
 		},
+		{
+			"blob",
+			&reward.RewardConfig{Rules: params.Rules{IsMagma: true, IsKaia: true, IsBlobTx: true}, UnitPrice: big.NewInt(25e9), BlobBaseFee: big.NewInt(1e9)},
+			&types.Header{BaseFee: big.NewInt(27e9), GasUsed: 300_000},
+			[]*types.Transaction{
+				makeTestTx_type2(50e9, 1e9, 200_000),   // effectivePrice = 28e9, effectiveTip = 1e9
+				makeTestTx_blob(50e9, 1e9, 400_000, 2), // effectivePrice = 28e9, effectiveTip = 1e9, blobGas = 2 * 131072
+			},
+			[]*types.Receipt{
+				{GasUsed: 100_000},
+				{GasUsed: 200_000},
+			},
+			big.NewInt(0.0084e18 + 2*131072*1e9), // sum{ (effectiveGasTip[i] + baseFee) * gasUsed[i] } + sum{ blobGas[i] } * blobBaseFee
+		},
 	}
 	for _, tc := range testcases {
 		totalFee, err := getTotalFee(tc.config, tc.header, tc.txs, tc.receipts)
@@ -200,3 +200,19 @@ func makeTestTx_type2(feeCap, tipCap, gasLimit int64) *types.Transaction {
 	}), types.NewLondonSigner(big.NewInt(31337)), key)
 	return tx
 }
+
+func makeTestTx_blob(feeCap, tipCap, gasLimit int64, numBlobs int) *types.Transaction {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	tx, _ := types.SignTx(types.NewTx(&types.TxInternalDataEthereumBlob{
+		ChainID:    big.NewInt(31337),
+		GasFeeCap:  big.NewInt(feeCap),
+		GasTipCap:  big.NewInt(tipCap),
+		GasLimit:   uint64(gasLimit),
+		Recipient:  addr,
+		Amount:     big.NewInt(0),
+		BlobFeeCap: big.NewInt(feeCap),
+		BlobHashes: make([]common.Hash, numBlobs),
+	}), types.LatestSignerForChainID(big.NewInt(31337)), key)
+	return tx
+}
@@ -0,0 +1,31 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package stateexpiry
+
+import (
+	"errors"
+)
+
+var (
+	ErrInitUnexpectedNil   = errors.New("unexpected nil during module init")
+	ErrZeroPeriod          = errors.New("state expiry period must be positive")
+	ErrUnknownBlock        = errors.New("unknown block")
+	ErrNotEnabled          = errors.New("state expiry is not enabled at the block")
+	ErrStateExpired        = errors.New("account state is expired")
+	ErrNotExpired          = errors.New("account state is not expired")
+	ErrInvalidResurrection = errors.New("invalid resurrection")
+	ErrIncompatibleState   = errors.New("state expiry requires the state snapshot and the live pruning to be disabled")
+)
@@ -0,0 +1,60 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package impl
+
+import (
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/kaiax/stateexpiry"
+	"github.com/kaiachain/kaia/networks/rpc"
+)
+
+func (s *StateExpiryModule) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "kaia",
+			Version:   "1.0",
+			Service:   newStateExpiryAPI(s),
+			Public:    true,
+		},
+	}
+}
+
+type stateExpiryAPI struct {
+	s *StateExpiryModule
+}
+
+func newStateExpiryAPI(s *StateExpiryModule) *stateExpiryAPI {
+	return &stateExpiryAPI{s}
+}
+
+// GetStateExpiry returns the state expiry status of the account at the given block.
+func (api *stateExpiryAPI) GetStateExpiry(addr common.Address, num rpc.BlockNumber) (*stateexpiry.Status, error) {
+	return api.s.GetStatus(addr, api.blockNumber(num))
+}
+
+// GetResurrectionData returns the data of a resurrection tx for the account, proven at the given block.
+// The tx must be sent to stateexpiry.RegistryAddr.
+func (api *stateExpiryAPI) GetResurrectionData(addr common.Address, num rpc.BlockNumber) (hexutil.Bytes, error) {
+	return api.s.resurrection(addr, api.blockNumber(num))
+}
+
+func (api *stateExpiryAPI) blockNumber(num rpc.BlockNumber) uint64 {
+	if num == rpc.LatestBlockNumber || num == rpc.PendingBlockNumber {
+		return api.s.Chain.CurrentBlock().NumberU64()
+	}
+	return num.Uint64()
+}
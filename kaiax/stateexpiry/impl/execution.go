@@ -0,0 +1,123 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package impl
+
+import (
+	"fmt"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/blockchain/types/account"
+	"github.com/kaiachain/kaia/blockchain/vm"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/kaiax/stateexpiry"
+)
+
+// accountRestorer is the state that can restore a swept account.
+type accountRestorer interface {
+	RestoreAccount(addr common.Address, acc account.Account)
+}
+
+// PreRunTx rejects a tx whose sender or recipient is expired, which invalidates a block containing it.
+// A resurrection tx restores the resurrected account if it was swept, and touches it before it runs.
+func (s *StateExpiryModule) PreRunTx(evm *vm.EVM, tx *types.Transaction) (*types.Transaction, error) {
+	num := evm.Context.BlockNumber.Uint64()
+	if !s.isEnabled(num) {
+		return tx, nil
+	}
+
+	r, err := s.checkTx(evm.StateDB, num, evm.Origin, tx)
+	if err != nil {
+		rejectedTxCounter.Inc(1)
+		return nil, err
+	}
+	if r != nil {
+		if r.data != nil {
+			restorer, ok := evm.StateDB.(accountRestorer)
+			if !ok {
+				return nil, fmt.Errorf("%w: the state cannot restore accounts", stateexpiry.ErrInvalidResurrection)
+			}
+			restorer.RestoreAccount(r.addr, r.data)
+			setUint64(evm.StateDB, sweptKey(r.addr), 0)
+		}
+		touch(evm.StateDB, r.addr, s.epochOf(num))
+		resurrectedCounter.Inc(1)
+		logger.Debug("Resurrected an expired account", "account", r.addr.Hex(), "num", num, "restored", r.data != nil)
+	}
+	return tx, nil
+}
+
+// PostRunTx touches the sender and the recipient of the tx, or the deployed contract.
+func (s *StateExpiryModule) PostRunTx(evm *vm.EVM, tx *types.Transaction) error {
+	num := evm.Context.BlockNumber.Uint64()
+	if !s.isEnabled(num) {
+		return nil
+	}
+
+	epoch := s.epochOf(num)
+	touch(evm.StateDB, evm.Origin, epoch)
+	if to := tx.To(); to == nil {
+		touch(evm.StateDB, crypto.CreateAddress(evm.Origin, tx.Nonce()), epoch)
+	} else if *to != stateexpiry.RegistryAddr {
+		touch(evm.StateDB, *to, epoch)
+	}
+	epochGauge.Update(int64(epoch))
+	return nil
+}
+
+func (s *StateExpiryModule) PreAddLocal(tx *types.Transaction) error {
+	return s.checkPoolTx(tx)
+}
+
+func (s *StateExpiryModule) PreAddRemote(tx *types.Transaction) error {
+	return s.checkPoolTx(tx)
+}
+
+func (s *StateExpiryModule) checkPoolTx(tx *types.Transaction) error {
+	num := s.Chain.CurrentBlock().NumberU64() + 1
+	if !s.isEnabled(num) {
+		return nil
+	}
+
+	st, err := s.Chain.State()
+	if err != nil {
+		return err
+	}
+	_, err = s.checkTx(st, num, tx.ValidatedSender(), tx)
+	return err
+}
+
+// checkTx checks that neither the sender nor the recipient of the tx is expired at the block.
+// The sender of a resurrection tx may be expired if it resurrects itself.
+// It returns the verified resurrection if the tx is a valid resurrection tx.
+func (s *StateExpiryModule) checkTx(st vm.StateDB, num uint64, sender common.Address, tx *types.Transaction) (*resurrection, error) {
+	var resurrected *resurrection
+	if to := tx.To(); to != nil && *to == stateexpiry.RegistryAddr {
+		r, err := s.verifyResurrection(st, num, tx.Data())
+		if err != nil {
+			return nil, err
+		}
+		resurrected = r
+	}
+
+	if (resurrected == nil || resurrected.addr != sender) && s.isExpired(st, sender, num) {
+		return nil, fmt.Errorf("%w: sender %s", stateexpiry.ErrStateExpired, sender.Hex())
+	}
+	if to := tx.To(); to != nil && s.isExpired(st, *to, num) {
+		return nil, fmt.Errorf("%w: recipient %s", stateexpiry.ErrStateExpired, to.Hex())
+	}
+	return resurrected, nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package impl
+
+import (
+	"math/big"
+
+	"github.com/kaiachain/kaia/blockchain/vm"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/stateexpiry"
+)
+
+func (s *StateExpiryModule) isEnabled(num uint64) bool {
+	return s.ChainConfig.IsStateExpiryForkEnabled(new(big.Int).SetUint64(num))
+}
+
+// epochOf returns the state expiry epoch of the block. The fork block starts the epoch 0.
+func (s *StateExpiryModule) epochOf(num uint64) uint64 {
+	return (num - s.ChainConfig.StateExpiryCompatibleBlock.Uint64()) / s.ChainConfig.StateExpiryPeriod
+}
+
+// epochStart returns the first block of the epoch.
+func (s *StateExpiryModule) epochStart(epoch uint64) uint64 {
+	return s.ChainConfig.StateExpiryCompatibleBlock.Uint64() + epoch*s.ChainConfig.StateExpiryPeriod
+}
+
+// expiryEpochs returns the number of whole epochs an account may stay untouched.
+func (s *StateExpiryModule) expiryEpochs() uint64 {
+	if s.ChainConfig.StateExpiryEpochs == 0 {
+		return 1
+	}
+	return s.ChainConfig.StateExpiryEpochs
+}
+
+// isExpired returns true if the account exists and was touched in neither the epoch of the block
+// nor the expiryEpochs epochs before it, or if the account was swept.
+func (s *StateExpiryModule) isExpired(st vm.StateDB, addr common.Address, num uint64) bool {
+	if addr == stateexpiry.RegistryAddr {
+		return false
+	}
+	if !st.Exist(addr) {
+		return sweptBlock(st, addr) != 0
+	}
+	return s.epochOf(num) > lastEpoch(st, addr)+s.expiryEpochs()
+}
+
+// lastEpoch returns the last epoch when the account was touched.
+// An account untouched since the fork is regarded as touched in the epoch 0.
+func lastEpoch(st vm.StateDB, addr common.Address) uint64 {
+	v := st.GetState(stateexpiry.RegistryAddr, registryKey(addr)).Big()
+	if v.Sign() == 0 {
+		return 0
+	}
+	return v.Uint64() - 1
+}
+
+// touch records that the account is touched in the epoch. The registry stores epoch+1 so that zero means untouched.
+// The account is listed in the epoch on its first touch in the epoch, to be swept once the epoch is expired.
+func touch(st vm.StateDB, addr common.Address, epoch uint64) {
+	key, value := registryKey(addr), common.BigToHash(new(big.Int).SetUint64(epoch+1))
+	if st.GetState(stateexpiry.RegistryAddr, key) != value {
+		st.SetState(stateexpiry.RegistryAddr, key, value)
+		appendList(st, epoch, addr)
+		touchedCounter.Inc(1)
+	}
+	// The nonce keeps the registry from being deleted as an empty account.
+	if st.GetNonce(stateexpiry.RegistryAddr) == 0 {
+		st.SetNonce(stateexpiry.RegistryAddr, 1)
+	}
+}
+
+func registryKey(addr common.Address) common.Hash {
+	return common.BytesToHash(addr.Bytes())
+}
+
+func (s *StateExpiryModule) GetStatus(addr common.Address, num uint64) (*stateexpiry.Status, error) {
+	if !s.isEnabled(num) {
+		return nil, stateexpiry.ErrNotEnabled
+	}
+	header := s.Chain.GetHeaderByNumber(num)
+	if header == nil {
+		return nil, stateexpiry.ErrUnknownBlock
+	}
+	st, err := s.Chain.StateAt(header.Root)
+	if err != nil {
+		return nil, err
+	}
+	return &stateexpiry.Status{
+		Account:      addr,
+		Exists:       st.Exist(addr),
+		LastEpoch:    lastEpoch(st, addr),
+		CurrentEpoch: s.epochOf(num),
+		Expired:      s.isExpired(st, addr, num),
+		SweptBlock:   sweptBlock(st, addr),
+	}, nil
+}
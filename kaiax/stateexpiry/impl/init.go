@@ -0,0 +1,85 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package impl
+
+import (
+	"github.com/kaiachain/kaia/blockchain/state"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/stateexpiry"
+	"github.com/kaiachain/kaia/log"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/snapshot"
+	"github.com/rcrowley/go-metrics"
+)
+
+var (
+	_ stateexpiry.StateExpiryModule = &StateExpiryModule{}
+
+	logger = log.NewModuleLogger(log.KaiaxStateExpiry)
+
+	rejectedTxCounter  = metrics.NewRegisteredCounter("kaiax/stateexpiry/rejected", nil)
+	resurrectedCounter = metrics.NewRegisteredCounter("kaiax/stateexpiry/resurrected", nil)
+	touchedCounter     = metrics.NewRegisteredCounter("kaiax/stateexpiry/touched", nil)
+	sweptCounter       = metrics.NewRegisteredCounter("kaiax/stateexpiry/swept", nil)
+	epochGauge         = metrics.NewRegisteredGauge("kaiax/stateexpiry/epoch", nil)
+)
+
+type blockChain interface {
+	CurrentBlock() *types.Block
+	GetHeaderByNumber(number uint64) *types.Header
+	State() (*state.StateDB, error)
+	StateAt(root common.Hash) (*state.StateDB, error)
+	StateCache() state.Database
+	Snapshots() *snapshot.Tree
+	IsLivePruningRequired() bool
+}
+
+type InitOpts struct {
+	ChainConfig *params.ChainConfig
+	Chain       blockChain
+}
+
+type StateExpiryModule struct {
+	InitOpts
+}
+
+func NewStateExpiryModule() *StateExpiryModule {
+	return &StateExpiryModule{}
+}
+
+func (s *StateExpiryModule) Init(opts *InitOpts) error {
+	if opts == nil || opts.ChainConfig == nil || opts.Chain == nil {
+		return stateexpiry.ErrInitUnexpectedNil
+	}
+	if opts.ChainConfig.StateExpiryCompatibleBlock != nil && opts.ChainConfig.StateExpiryPeriod == 0 {
+		return stateexpiry.ErrZeroPeriod
+	}
+	// The swept accounts are restored from the trie nodes left in the database,
+	// which the snapshot does not see and the live pruning deletes.
+	if opts.Chain.Snapshots() != nil || opts.Chain.IsLivePruningRequired() {
+		return stateexpiry.ErrIncompatibleState
+	}
+	s.InitOpts = *opts
+	return nil
+}
+
+func (s *StateExpiryModule) Start() error {
+	logger.Warn("Experimental state expiry enabled", "block", s.ChainConfig.StateExpiryCompatibleBlock, "period", s.ChainConfig.StateExpiryPeriod, "epochs", s.expiryEpochs())
+	return nil
+}
+
+func (s *StateExpiryModule) Stop() {}
@@ -0,0 +1,104 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package impl
+
+import (
+	"fmt"
+
+	"github.com/kaiachain/kaia/blockchain/types/account"
+	"github.com/kaiachain/kaia/blockchain/vm"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/kaiax/stateexpiry"
+	"github.com/kaiachain/kaia/rlp"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/kaiachain/kaia/storage/statedb"
+)
+
+// resurrection is a verified resurrection tx.
+type resurrection struct {
+	addr common.Address
+	data account.Account // the account data to restore if the account was swept, nil otherwise
+}
+
+// verifyResurrection decodes the data of a resurrection tx and verifies it at the block. The account must be expired.
+// If the account is still in the state, the proof must show that the account exists at a block after the start of
+// the epoch it was last touched. If the account was swept, the proof must be made at the block before the sweep,
+// which holds the latest data of the account, and the proven data is returned to be restored.
+func (s *StateExpiryModule) verifyResurrection(st vm.StateDB, num uint64, data []byte) (*resurrection, error) {
+	r := new(stateexpiry.Resurrection)
+	if err := rlp.DecodeBytes(data, r); err != nil {
+		return nil, fmt.Errorf("%w: %v", stateexpiry.ErrInvalidResurrection, err)
+	}
+	if !s.isExpired(st, r.Account, num) {
+		return nil, stateexpiry.ErrNotExpired
+	}
+	if swept := sweptBlock(st, r.Account); swept != 0 {
+		if r.BlockNumber != swept-1 {
+			return nil, fmt.Errorf("%w: proof block %d is not the block before the sweep at %d", stateexpiry.ErrInvalidResurrection, r.BlockNumber, swept)
+		}
+	} else if r.BlockNumber >= num || r.BlockNumber < s.epochStart(lastEpoch(st, r.Account)) {
+		return nil, fmt.Errorf("%w: proof block %d is out of the range", stateexpiry.ErrInvalidResurrection, r.BlockNumber)
+	}
+	header := s.Chain.GetHeaderByNumber(r.BlockNumber)
+	if header == nil {
+		return nil, fmt.Errorf("%w: %v", stateexpiry.ErrInvalidResurrection, stateexpiry.ErrUnknownBlock)
+	}
+
+	proofDB := database.NewMemoryDBManager()
+	for _, node := range r.Proof {
+		proofDB.WriteMerkleProof(database.TrieNodeKey(common.BytesToExtHash(crypto.Keccak256(node))), node)
+	}
+	value, err, _ := statedb.VerifyProof(header.Root, crypto.Keccak256(r.Account.Bytes()), proofDB)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", stateexpiry.ErrInvalidResurrection, err)
+	}
+	if value == nil {
+		return nil, fmt.Errorf("%w: account is absent at block %d", stateexpiry.ErrInvalidResurrection, r.BlockNumber)
+	}
+	if sweptBlock(st, r.Account) == 0 {
+		return &resurrection{addr: r.Account}, nil
+	}
+
+	serializer := account.NewAccountSerializer()
+	if err := rlp.DecodeBytes(value, serializer); err != nil {
+		return nil, fmt.Errorf("%w: %v", stateexpiry.ErrInvalidResurrection, err)
+	}
+	return &resurrection{addr: r.Account, data: serializer.GetAccount()}, nil
+}
+
+// resurrection builds the data of a resurrection tx for the account, proven at the block.
+func (s *StateExpiryModule) resurrection(addr common.Address, num uint64) ([]byte, error) {
+	header := s.Chain.GetHeaderByNumber(num)
+	if header == nil {
+		return nil, stateexpiry.ErrUnknownBlock
+	}
+	trie, err := statedb.NewTrie(header.Root, s.Chain.StateCache().TrieDB(), nil)
+	if err != nil {
+		return nil, err
+	}
+	var proof proofList
+	if err := trie.Prove(crypto.Keccak256(addr.Bytes()), 0, &proof); err != nil {
+		return nil, err
+	}
+	return rlp.EncodeToBytes(&stateexpiry.Resurrection{Account: addr, BlockNumber: num, Proof: proof})
+}
+
+type proofList [][]byte
+
+func (n *proofList) WriteMerkleProof(key, value []byte) {
+	*n = append(*n, value)
+}
@@ -0,0 +1,277 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package impl
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kaiachain/kaia/blockchain/state"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/blockchain/vm"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/stateexpiry"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/rlp"
+	"github.com/kaiachain/kaia/snapshot"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testChain struct {
+	db      state.Database
+	headers map[uint64]*types.Header
+	current uint64
+}
+
+func (c *testChain) CurrentBlock() *types.Block {
+	return types.NewBlockWithHeader(c.headers[c.current])
+}
+func (c *testChain) GetHeaderByNumber(number uint64) *types.Header { return c.headers[number] }
+func (c *testChain) State() (*state.StateDB, error)                { return c.StateAt(c.headers[c.current].Root) }
+func (c *testChain) StateAt(root common.Hash) (*state.StateDB, error) {
+	return state.New(root, c.db, nil, nil)
+}
+func (c *testChain) StateCache() state.Database  { return c.db }
+func (c *testChain) Snapshots() *snapshot.Tree   { return nil }
+func (c *testChain) IsLivePruningRequired() bool { return false }
+
+// commit writes the state as the state of the block.
+func (c *testChain) commit(t *testing.T, st *state.StateDB, num uint64) {
+	root, err := st.Commit(true)
+	require.NoError(t, err)
+	c.headers[num] = &types.Header{Number: new(big.Int).SetUint64(num), Root: root}
+	c.current = num
+}
+
+// newTestModule returns a module whose epochs are 10 blocks long from the block 100.
+func newTestModule(t *testing.T) (*StateExpiryModule, *testChain) {
+	chain := &testChain{
+		db:      state.NewDatabase(database.NewMemoryDBManager()),
+		headers: make(map[uint64]*types.Header),
+	}
+	config := params.TestChainConfig.Copy()
+	config.StateExpiryCompatibleBlock = big.NewInt(100)
+	config.StateExpiryPeriod = 10
+
+	s := NewStateExpiryModule()
+	require.NoError(t, s.Init(&InitOpts{ChainConfig: config, Chain: chain}))
+	return s, chain
+}
+
+func newTestEVM(s *StateExpiryModule, st vm.StateDB, num uint64, origin common.Address) *vm.EVM {
+	return vm.NewEVM(vm.BlockContext{BlockNumber: new(big.Int).SetUint64(num)}, vm.TxContext{Origin: origin}, st, s.ChainConfig, &vm.Config{})
+}
+
+func TestInit(t *testing.T) {
+	config := params.TestChainConfig.Copy()
+	config.StateExpiryCompatibleBlock = big.NewInt(100)
+	assert.ErrorIs(t, NewStateExpiryModule().Init(&InitOpts{ChainConfig: config, Chain: &testChain{}}), stateexpiry.ErrZeroPeriod)
+	assert.ErrorIs(t, NewStateExpiryModule().Init(&InitOpts{ChainConfig: config}), stateexpiry.ErrInitUnexpectedNil)
+}
+
+func TestStateExpiry(t *testing.T) {
+	var (
+		s, chain = newTestModule(t)
+		alice    = common.HexToAddress("0xa")
+		bob      = common.HexToAddress("0xb")
+	)
+	st, err := state.New(common.Hash{}, chain.db, nil, nil)
+	require.NoError(t, err)
+	st.AddBalance(alice, big.NewInt(1))
+	st.AddBalance(bob, big.NewInt(1))
+	chain.commit(t, st, 105) // epoch 0
+
+	st, err = chain.State()
+	require.NoError(t, err)
+	transfer := types.NewTransaction(0, alice, big.NewInt(1), 21000, big.NewInt(0), nil)
+
+	// The accounts untouched since the epoch 0 expire in the epoch 2.
+	_, err = s.checkTx(st, 119, bob, transfer)
+	assert.NoError(t, err)
+	_, err = s.checkTx(st, 120, bob, transfer)
+	assert.ErrorIs(t, err, stateexpiry.ErrStateExpired)
+	_, err = s.PreRunTx(newTestEVM(s, st, 120, bob), transfer)
+	assert.ErrorIs(t, err, stateexpiry.ErrStateExpired)
+
+	// Nothing expires before the fork.
+	config := s.ChainConfig
+	config.StateExpiryCompatibleBlock = big.NewInt(1000)
+	_, err = s.PreRunTx(newTestEVM(s, st, 120, bob), transfer)
+	assert.NoError(t, err)
+	config.StateExpiryCompatibleBlock = big.NewInt(100)
+
+	// A touched account stays alive for the next epoch.
+	require.NoError(t, s.PostRunTx(newTestEVM(s, st, 119, bob), transfer))
+	assert.Equal(t, uint64(1), lastEpoch(st, alice))
+	assert.Equal(t, uint64(1), lastEpoch(st, bob))
+	_, err = s.checkTx(st, 129, bob, transfer)
+	assert.NoError(t, err)
+	_, err = s.checkTx(st, 130, bob, transfer)
+	assert.ErrorIs(t, err, stateexpiry.ErrStateExpired)
+}
+
+func TestStateExpiry_Epochs(t *testing.T) {
+	var (
+		s, chain = newTestModule(t)
+		bob      = common.HexToAddress("0xb")
+	)
+	s.ChainConfig.StateExpiryEpochs = 3
+
+	st, err := state.New(common.Hash{}, chain.db, nil, nil)
+	require.NoError(t, err)
+	st.AddBalance(bob, big.NewInt(1))
+	touch(st, bob, 1)
+	chain.commit(t, st, 115)
+
+	st, err = chain.State()
+	require.NoError(t, err)
+
+	// An account touched in the epoch 1 stays alive until the end of the epoch 4.
+	assert.False(t, s.isExpired(st, bob, 149))
+	assert.True(t, s.isExpired(st, bob, 150))
+}
+
+func TestResurrection(t *testing.T) {
+	var (
+		s, chain = newTestModule(t)
+		alice    = common.HexToAddress("0xa")
+		bob      = common.HexToAddress("0xb")
+		carol    = common.HexToAddress("0xc")
+	)
+	st, err := state.New(common.Hash{}, chain.db, nil, nil)
+	require.NoError(t, err)
+	st.AddBalance(alice, big.NewInt(1))
+	chain.commit(t, st, 99) // before the fork
+	st.AddBalance(bob, big.NewInt(1))
+	chain.commit(t, st, 105)
+	st.AddBalance(carol, big.NewInt(1))
+	touch(st, carol, 2)
+	chain.commit(t, st, 125)
+
+	resurrect := func(account common.Address, num uint64) *types.Transaction {
+		data, err := s.resurrection(account, num)
+		require.NoError(t, err)
+		return types.NewTransaction(0, stateexpiry.RegistryAddr, big.NewInt(0), 100000, big.NewInt(0), data)
+	}
+
+	st, err = chain.State()
+	require.NoError(t, err)
+	assert.True(t, s.isExpired(st, alice, 130))
+
+	// The proof must be taken after the account was last touched.
+	_, err = s.checkTx(st, 130, carol, resurrect(alice, 99))
+	assert.ErrorIs(t, err, stateexpiry.ErrInvalidResurrection)
+
+	// The proof must show that the account exists.
+	r := new(stateexpiry.Resurrection)
+	require.NoError(t, rlp.DecodeBytes(resurrect(alice, 105).Data(), r))
+	r.Proof = r.Proof[:len(r.Proof)-1]
+	data, err := rlp.EncodeToBytes(r)
+	require.NoError(t, err)
+	forged := types.NewTransaction(0, stateexpiry.RegistryAddr, big.NewInt(0), 100000, big.NewInt(0), data)
+	_, err = s.checkTx(st, 130, carol, forged)
+	assert.ErrorIs(t, err, stateexpiry.ErrInvalidResurrection)
+
+	// An alive account cannot be resurrected.
+	_, err = s.checkTx(st, 130, carol, resurrect(carol, 125))
+	assert.ErrorIs(t, err, stateexpiry.ErrNotExpired)
+
+	// An expired sender can resurrect itself, which makes it alive.
+	_, err = s.checkTx(st, 130, bob, resurrect(alice, 105))
+	assert.ErrorIs(t, err, stateexpiry.ErrStateExpired)
+	_, err = s.PreRunTx(newTestEVM(s, st, 130, alice), resurrect(alice, 105))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), lastEpoch(st, alice))
+	assert.False(t, s.isExpired(st, alice, 130))
+
+	status, err := s.GetStatus(alice, 125)
+	require.NoError(t, err)
+	assert.Equal(t, &stateexpiry.Status{Account: alice, Exists: true, LastEpoch: 0, CurrentEpoch: 2, Expired: true}, status)
+}
+
+func TestSweep(t *testing.T) {
+	var (
+		s, chain = newTestModule(t)
+		alice    = common.HexToAddress("0xa")
+		bob      = common.HexToAddress("0xb")
+		carol    = common.HexToAddress("0xc")
+		dave     = common.HexToAddress("0xd")
+		slot     = common.HexToHash("0x1")
+	)
+	st, err := state.New(common.Hash{}, chain.db, nil, nil)
+	require.NoError(t, err)
+	for _, addr := range []common.Address{alice, bob, dave} {
+		st.AddBalance(addr, big.NewInt(1))
+	}
+	st.CreateSmartContractAccount(carol, params.CodeFormatEVM, params.Rules{IsIstanbul: true})
+	require.NoError(t, st.SetCode(carol, []byte{0x60, 0x80}))
+	st.SetState(carol, slot, common.HexToHash("0x11"))
+	st.AddBalance(carol, big.NewInt(2))
+	for _, addr := range []common.Address{alice, bob, carol, dave} {
+		touch(st, addr, 0)
+	}
+	chain.commit(t, st, 105)
+
+	st, err = chain.State()
+	require.NoError(t, err)
+	touch(st, bob, 1)
+	chain.commit(t, st, 119)
+
+	// The accounts touched only in the epoch 0 are swept in the epoch 2, except for the modified one.
+	st, err = chain.State()
+	require.NoError(t, err)
+	st.AddBalance(dave, big.NewInt(1))
+	require.NoError(t, s.FinalizeHeader(&types.Header{Number: big.NewInt(120)}, st, nil, nil))
+	chain.commit(t, st, 120)
+
+	assert.False(t, st.Exist(alice))
+	assert.False(t, st.Exist(carol))
+	assert.True(t, st.Exist(bob))
+	assert.True(t, st.Exist(dave))
+	assert.Equal(t, uint64(120), sweptBlock(st, alice))
+	assert.Zero(t, sweptBlock(st, dave))
+	assert.Zero(t, listLen(st, 0))
+	assert.Equal(t, uint64(1), listLen(st, 2))
+	assert.Equal(t, uint64(1), getUint64(st, sweepEpochKey))
+	assert.True(t, s.isExpired(st, carol, 121))
+
+	status, err := s.GetStatus(alice, 120)
+	require.NoError(t, err)
+	assert.Equal(t, &stateexpiry.Status{Account: alice, Exists: false, LastEpoch: 0, CurrentEpoch: 2, Expired: true, SweptBlock: 120}, status)
+
+	resurrect := func(account common.Address, num uint64) *types.Transaction {
+		data, err := s.resurrection(account, num)
+		require.NoError(t, err)
+		return types.NewTransaction(0, stateexpiry.RegistryAddr, big.NewInt(0), 100000, big.NewInt(0), data)
+	}
+
+	// A swept account must be proven at the block before the sweep.
+	_, err = s.checkTx(st, 121, bob, resurrect(carol, 105))
+	assert.ErrorIs(t, err, stateexpiry.ErrInvalidResurrection)
+
+	// The resurrection restores the swept account with its storage.
+	_, err = s.PreRunTx(newTestEVM(s, st, 121, bob), resurrect(carol, 119))
+	require.NoError(t, err)
+	assert.True(t, st.Exist(carol))
+	assert.Equal(t, big.NewInt(2), st.GetBalance(carol))
+	assert.Equal(t, common.HexToHash("0x11"), st.GetState(carol, slot))
+	assert.Equal(t, []byte{0x60, 0x80}, st.GetCode(carol))
+	assert.Zero(t, sweptBlock(st, carol))
+	assert.Equal(t, uint64(2), lastEpoch(st, carol))
+	assert.False(t, s.isExpired(st, carol, 121))
+}
@@ -0,0 +1,132 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package impl
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/kaiachain/kaia/blockchain/state"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/blockchain/vm"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/kaiax/stateexpiry"
+)
+
+// sweepBatchSize is the number of the touched list entries the sweep visits in a block.
+const sweepBatchSize = 100
+
+var (
+	sweepEpochKey = crypto.Keccak256Hash([]byte("stateexpiry.sweep.epoch")) // the epoch of the list being swept
+	sweepIndexKey = crypto.Keccak256Hash([]byte("stateexpiry.sweep.index")) // the next entry of the list to visit
+)
+
+func (s *StateExpiryModule) VerifyHeader(header *types.Header) error {
+	return nil
+}
+
+func (s *StateExpiryModule) PrepareHeader(header *types.Header) error {
+	return nil
+}
+
+// FinalizeHeader sweeps the expired accounts out of the state after the txs of the block ran.
+func (s *StateExpiryModule) FinalizeHeader(header *types.Header, st *state.StateDB, txs []*types.Transaction, receipts []*types.Receipt) error {
+	num := header.Number.Uint64()
+	if !s.isEnabled(num) {
+		return nil
+	}
+	s.sweep(st, num)
+	return nil
+}
+
+// sweep removes the expired accounts from the state, together with their storage. The accounts touched
+// in an epoch are listed in the registry, and the list is swept once the epoch is expired, at most
+// sweepBatchSize entries per block. An entry is skipped if the account was touched again later.
+//
+// The account of an entry modified in the block is not swept because the resurrection could not prove
+// its latest data. The entry is moved to the list of the current epoch instead, to be swept later.
+func (s *StateExpiryModule) sweep(st *state.StateDB, num uint64) {
+	epoch, expiry := s.epochOf(num), s.expiryEpochs()
+	if epoch <= expiry || !st.Exist(stateexpiry.RegistryAddr) {
+		return
+	}
+	due := epoch - expiry - 1 // the last expired epoch
+
+	listEpoch, index := getUint64(st, sweepEpochKey), getUint64(st, sweepIndexKey)
+	for visited := 0; visited < sweepBatchSize && listEpoch <= due; {
+		if index >= listLen(st, listEpoch) {
+			setUint64(st, listKey(listEpoch), 0)
+			listEpoch, index = listEpoch+1, 0
+			continue
+		}
+		addr := common.BytesToAddress(st.GetState(stateexpiry.RegistryAddr, listItemKey(listEpoch, index)).Bytes())
+		st.SetState(stateexpiry.RegistryAddr, listItemKey(listEpoch, index), common.Hash{})
+		index, visited = index+1, visited+1
+
+		if lastEpoch(st, addr) > listEpoch || !st.Exist(addr) {
+			continue
+		}
+		if st.IsDirty(addr) {
+			appendList(st, epoch, addr)
+			continue
+		}
+		st.SelfDestruct(addr)
+		setUint64(st, sweptKey(addr), num)
+		sweptCounter.Inc(1)
+		logger.Debug("Swept an expired account", "account", addr.Hex(), "num", num)
+	}
+	setUint64(st, sweepEpochKey, listEpoch)
+	setUint64(st, sweepIndexKey, index)
+}
+
+// sweptBlock returns the block where the account was swept, or zero if it was not swept.
+func sweptBlock(st vm.StateDB, addr common.Address) uint64 {
+	return getUint64(st, sweptKey(addr))
+}
+
+// appendList adds the account to the list of the accounts touched in the epoch.
+func appendList(st vm.StateDB, epoch uint64, addr common.Address) {
+	n := listLen(st, epoch)
+	st.SetState(stateexpiry.RegistryAddr, listItemKey(epoch, n), common.BytesToHash(addr.Bytes()))
+	setUint64(st, listKey(epoch), n+1)
+}
+
+func listLen(st vm.StateDB, epoch uint64) uint64 {
+	return getUint64(st, listKey(epoch))
+}
+
+// listKey is the slot of the length of the list of the epoch, followed by the slots of its entries.
+func listKey(epoch uint64) common.Hash {
+	return crypto.Keccak256Hash([]byte("stateexpiry.list"), binary.BigEndian.AppendUint64(nil, epoch))
+}
+
+func listItemKey(epoch, index uint64) common.Hash {
+	key := listKey(epoch).Big()
+	return common.BigToHash(key.Add(key, new(big.Int).SetUint64(index+1)))
+}
+
+func sweptKey(addr common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("stateexpiry.swept"), addr.Bytes())
+}
+
+func getUint64(st vm.StateDB, key common.Hash) uint64 {
+	return st.GetState(stateexpiry.RegistryAddr, key).Big().Uint64()
+}
+
+func setUint64(st vm.StateDB, key common.Hash, v uint64) {
+	st.SetState(stateexpiry.RegistryAddr, key, common.BigToHash(new(big.Int).SetUint64(v)))
+}
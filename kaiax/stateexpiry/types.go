@@ -0,0 +1,40 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package stateexpiry
+
+import (
+	"github.com/kaiachain/kaia/common"
+)
+
+// RegistryAddr keeps the last epoch when each account was touched in its storage.
+// A tx to RegistryAddr is a resurrection tx whose data is an RLP-encoded Resurrection.
+var RegistryAddr = common.HexToAddress("0x0000000000000000000000000000000000000410")
+
+// Resurrection is the data of a resurrection tx.
+type Resurrection struct {
+	Account     common.Address
+	BlockNumber uint64   // the block whose state root the proof is verified against
+	Proof       [][]byte // the trie nodes from the state root to the account, e.g. accountProof of eth_getProof
+}
+
+type Status struct {
+	Account      common.Address `json:"account"`
+	Exists       bool           `json:"exists"`
+	LastEpoch    uint64         `json:"lastEpoch"` // the last epoch when the account was touched
+	CurrentEpoch uint64         `json:"currentEpoch"`
+	Expired      bool           `json:"expired"`
+	SweptBlock   uint64         `json:"sweptBlock,omitempty"` // the block where the account was swept, if it was
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package verkle
+
+import (
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/kaiax/verkle"
+	"github.com/kaiachain/kaia/networks/rpc"
+	verkletree "github.com/kaiachain/kaia/storage/verkle"
+)
+
+func (s *VerkleModule) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   NewDebugVerkleAPI(s),
+			Public:    false,
+		},
+	}
+}
+
+type DebugVerkleAPI struct {
+	s *VerkleModule
+}
+
+func NewDebugVerkleAPI(s *VerkleModule) *DebugVerkleAPI {
+	return &DebugVerkleAPI{s: s}
+}
+
+// VerkleStatus returns the block that the verkle tree reflects, the shape of the tree and the recent updates.
+func (api *DebugVerkleAPI) VerkleStatus() (*verkle.Status, error) {
+	status := api.s.Status()
+	if status == nil {
+		return nil, verkle.ErrNotReady
+	}
+	return status, nil
+}
+
+// VerkleUpdate returns the recent update of the verkle tree for the block.
+func (api *DebugVerkleAPI) VerkleUpdate(number rpc.BlockNumber) (*verkle.Update, error) {
+	s := api.s
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i := len(s.updates) - 1; i >= 0; i-- {
+		u := s.updates[i]
+		if number == rpc.LatestBlockNumber || number == rpc.PendingBlockNumber || u.Number == uint64(number.Int64()) {
+			return u, nil
+		}
+	}
+	return nil, verkle.ErrBlockNotFound
+}
+
+// VerkleWitness returns the witness of the account header and the storage slots of the address
+// against the state that the verkle tree reflects.
+func (api *DebugVerkleAPI) VerkleWitness(addr common.Address, slots []common.Hash) (*verkletree.Witness, error) {
+	s := api.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tree == nil {
+		return nil, verkle.ErrNotReady
+	}
+	addrHash := crypto.Keccak256Hash(addr[:])
+	var keys [][]byte
+	for leaf := byte(verkletree.VersionLeafKey); leaf <= verkletree.AccountKeyHashLeafKey; leaf++ {
+		keys = append(keys, verkletree.AccountHeaderKey(addrHash, leaf))
+	}
+	for _, slot := range slots {
+		keys = append(keys, verkletree.StorageSlotKey(addrHash, crypto.Keccak256Hash(slot[:])))
+	}
+	return s.tree.Witness(keys)
+}
@@ -0,0 +1,156 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package verkle
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+
+	"github.com/kaiachain/kaia/blockchain/types/account"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/rlp"
+	"github.com/kaiachain/kaia/storage/statedb"
+)
+
+// accountChange is an account changed between two states, keyed by the hashed address.
+type accountChange struct {
+	addrHash common.Hash
+	old, new account.Account        // nil if absent
+	slots    map[common.Hash][]byte // the changed slots by the hashed slot, nil if deleted
+}
+
+// diffState collects the accounts and the storage slots that differ between the two state roots,
+// sorted by the hashed address.
+func (s *VerkleModule) diffState(oldRoot, newRoot common.Hash) ([]*accountChange, error) {
+	trieDB := s.Chain.StateCache().TrieDB()
+	oldTrie, err := statedb.NewSecureTrie(oldRoot, trieDB, nil)
+	if err != nil {
+		return nil, err
+	}
+	newTrie, err := statedb.NewSecureTrie(newRoot, trieDB, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[common.Hash]*accountChange)
+	get := func(key []byte) *accountChange {
+		addrHash := common.BytesToHash(key)
+		if c, ok := changes[addrHash]; ok {
+			return c
+		}
+		c := &accountChange{addrHash: addrHash, slots: make(map[common.Hash][]byte)}
+		changes[addrHash] = c
+		return c
+	}
+	// Leaves only in the new trie are created or modified accounts,
+	// and leaves only in the old trie are modified or deleted accounts.
+	err = iterateDiff(oldTrie.NodeIterator(nil), newTrie.NodeIterator(nil), func(key, value []byte) error {
+		acc, err := decodeAccount(value)
+		get(key).new = acc
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = iterateDiff(newTrie.NodeIterator(nil), oldTrie.NodeIterator(nil), func(key, value []byte) error {
+		acc, err := decodeAccount(value)
+		get(key).old = acc
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*accountChange, 0, len(changes))
+	for _, c := range changes {
+		if oldStorage, newStorage := storageRoot(c.old), storageRoot(c.new); oldStorage != newStorage {
+			if err := diffStorage(trieDB, oldStorage, newStorage, c.slots); err != nil {
+				return nil, fmt.Errorf("storage of %x: %w", c.addrHash, err)
+			}
+		}
+		ret = append(ret, c)
+	}
+	slices.SortFunc(ret, func(a, b *accountChange) int {
+		return bytes.Compare(a.addrHash[:], b.addrHash[:])
+	})
+	return ret, nil
+}
+
+// diffStorage writes the slots that differ between the two storage roots into slots.
+func diffStorage(trieDB *statedb.Database, oldRoot, newRoot common.ExtHash, slots map[common.Hash][]byte) error {
+	oldTrie, err := statedb.NewSecureStorageTrie(oldRoot, trieDB, nil)
+	if err != nil {
+		return err
+	}
+	newTrie, err := statedb.NewSecureStorageTrie(newRoot, trieDB, nil)
+	if err != nil {
+		return err
+	}
+	// Deleted first, so that a modified slot ends up with its new value.
+	err = iterateDiff(newTrie.NodeIterator(nil), oldTrie.NodeIterator(nil), func(key, _ []byte) error {
+		slots[common.BytesToHash(key)] = nil
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return iterateDiff(oldTrie.NodeIterator(nil), newTrie.NodeIterator(nil), func(key, value []byte) error {
+		slots[common.BytesToHash(key)] = common.CopyBytes(value)
+		return nil
+	})
+}
+
+// iterateDiff calls fn for every leaf in b that is not in a.
+func iterateDiff(a, b statedb.NodeIterator, fn func(key, value []byte) error) error {
+	diff, _ := statedb.NewDifferenceIterator(a, b)
+	it := statedb.NewIterator(diff)
+	for it.Next() {
+		if err := fn(it.Key, it.Value); err != nil {
+			return err
+		}
+	}
+	return it.Err
+}
+
+func decodeAccount(enc []byte) (account.Account, error) {
+	serializer := account.NewAccountSerializer()
+	if err := rlp.DecodeBytes(enc, serializer); err != nil {
+		return nil, fmt.Errorf("malformed account: %w", err)
+	}
+	return serializer.GetAccount(), nil
+}
+
+func storageRoot(acc account.Account) common.ExtHash {
+	if pa := account.GetProgramAccount(acc); pa != nil {
+		return pa.GetStorageRoot()
+	}
+	return common.ExtHash{}
+}
+
+func codeHash(acc account.Account) []byte {
+	if pa := account.GetProgramAccount(acc); pa != nil {
+		return pa.GetCodeHash()
+	}
+	return nil
+}
+
+func (s *VerkleModule) code(acc account.Account) []byte {
+	if hash := codeHash(acc); hash != nil {
+		return s.Chain.StateCache().TrieDB().DiskDB().ReadCode(common.BytesToHash(hash))
+	}
+	return nil
+}
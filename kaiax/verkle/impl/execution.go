@@ -0,0 +1,129 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package verkle
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/verkle"
+	verkletree "github.com/kaiachain/kaia/storage/verkle"
+)
+
+// PostInsertBlock applies the state changes of the block to the verkle tree. If the tree does not
+// reflect the parent, e.g. after a reorg or a failed update, the state of the block is converted instead.
+// A failure never stops the block import.
+func (s *VerkleModule) PostInsertBlock(block *types.Block) error {
+	start := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := &verkle.Update{Number: block.NumberU64(), Hash: block.Hash(), StateRoot: block.Root()}
+	applied := false
+	if s.tree != nil && s.head.Hash() == block.ParentHash() {
+		if err := s.apply(s.head.Root, block.Root(), u); err != nil {
+			errCounter.Inc(1)
+			logger.Warn("Failed to update the verkle tree, rebuilding", "number", u.Number, "err", err)
+		} else {
+			applied = true
+		}
+	}
+	if !applied {
+		if err := s.rebuild(block.Header()); err != nil {
+			errCounter.Inc(1)
+			logger.Error("Failed to build the verkle tree", "number", u.Number, "err", err)
+			s.tree, s.head = nil, nil
+			return nil
+		}
+		u.Rebuilt = true
+	}
+	s.head = block.Header()
+	u.VerkleRoot = s.root
+	u.ElapsedMs = time.Since(start).Milliseconds()
+	updateTimer.UpdateSince(start)
+
+	if len(s.updates) >= maxUpdates {
+		s.updates = s.updates[1:]
+	}
+	s.updates = append(s.updates, u)
+	return nil
+}
+
+// rebuild converts the state of the header into a new tree. It must be called with the lock held.
+func (s *VerkleModule) rebuild(header *types.Header) error {
+	start := time.Now()
+	tree, stats, err := verkletree.ConvertState(s.Chain.StateCache().TrieDB(), header.Root)
+	if err != nil {
+		return err
+	}
+	s.tree, s.head, s.root = tree, header, tree.Commit()
+	rebuildCounter.Inc(1)
+	logger.Info("Converted the state into a verkle tree", "number", header.Number, "root", s.root,
+		"accounts", stats.Accounts, "slots", stats.StorageSlots, "codeChunks", stats.CodeChunks, "elapsed", time.Since(start))
+	return nil
+}
+
+// apply writes the state diff between the roots to the tree, after taking the witness of the written
+// keys against the parent state. It must be called with the lock held. The tree is left partially
+// updated on an error.
+func (s *VerkleModule) apply(oldRoot, newRoot common.Hash, u *verkle.Update) error {
+	changes, err := s.diffState(oldRoot, newRoot)
+	if err != nil {
+		return err
+	}
+
+	var keys [][]byte
+	for _, c := range changes {
+		for leaf := byte(verkletree.VersionLeafKey); leaf <= verkletree.AccountKeyHashLeafKey; leaf++ {
+			keys = append(keys, verkletree.AccountHeaderKey(c.addrHash, leaf))
+		}
+		for slot := range c.slots {
+			keys = append(keys, verkletree.StorageSlotKey(c.addrHash, slot))
+		}
+		u.Slots += len(c.slots)
+	}
+	w, err := s.tree.Witness(keys)
+	if err != nil {
+		return err
+	}
+	u.Accounts, u.WitnessKeys, u.WitnessSize = len(changes), len(keys), w.Size()
+	witnessSizeGauge.Update(int64(u.WitnessSize))
+
+	for _, c := range changes {
+		// The code chunks are rewritten only if the code has changed.
+		codeLen := 0
+		if c.new == nil || !bytes.Equal(codeHash(c.old), codeHash(c.new)) {
+			codeLen = len(s.code(c.old))
+		}
+		if err := verkletree.DeleteAccount(s.tree, c.addrHash, codeLen); err != nil {
+			return err
+		}
+		if c.new != nil {
+			if err := verkletree.InsertAccount(s.tree, c.addrHash, c.new, s.code(c.new)); err != nil {
+				return err
+			}
+		}
+		for slot, enc := range c.slots {
+			if err := verkletree.InsertStorage(s.tree, c.addrHash, slot, enc); err != nil {
+				return err
+			}
+		}
+	}
+	s.root = s.tree.Commit()
+	return nil
+}
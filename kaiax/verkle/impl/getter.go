@@ -0,0 +1,37 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package verkle
+
+import (
+	"github.com/kaiachain/kaia/kaiax/verkle"
+)
+
+// Status returns nil if the tree is not built.
+func (s *VerkleModule) Status() *verkle.Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.tree == nil {
+		return nil
+	}
+	return &verkle.Status{
+		Number:     s.head.Number.Uint64(),
+		Hash:       s.head.Hash(),
+		VerkleRoot: s.root,
+		Tree:       s.tree.Stats(),
+		Updates:    append([]*verkle.Update{}, s.updates...),
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package verkle
+
+import (
+	"sync"
+
+	"github.com/kaiachain/kaia/blockchain/state"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/verkle"
+	"github.com/kaiachain/kaia/log"
+	"github.com/kaiachain/kaia/params"
+	verkletree "github.com/kaiachain/kaia/storage/verkle"
+	"github.com/rcrowley/go-metrics"
+)
+
+var (
+	_ verkle.VerkleModule = &VerkleModule{}
+
+	logger = log.NewModuleLogger(log.KaiaxVerkle)
+
+	updateTimer      = metrics.NewRegisteredTimer("kaiax/verkle/update", nil)
+	rebuildCounter   = metrics.NewRegisteredCounter("kaiax/verkle/rebuilds", nil)
+	errCounter       = metrics.NewRegisteredCounter("kaiax/verkle/errors", nil)
+	witnessSizeGauge = metrics.NewRegisteredGauge("kaiax/verkle/witness/size", nil)
+
+	maxUpdates = 128 // number of recent updates kept in memory
+)
+
+type blockChain interface {
+	CurrentBlock() *types.Block
+	StateCache() state.Database
+}
+
+type InitOpts struct {
+	ChainConfig *params.ChainConfig
+	Chain       blockChain
+}
+
+type VerkleModule struct {
+	InitOpts
+
+	mu      sync.RWMutex
+	tree    *verkletree.Tree
+	head    *types.Header // the block that the tree reflects
+	root    common.Hash
+	updates []*verkle.Update
+}
+
+func NewVerkleModule() *VerkleModule {
+	return &VerkleModule{}
+}
+
+func (s *VerkleModule) Init(opts *InitOpts) error {
+	if opts == nil || opts.ChainConfig == nil || opts.ChainConfig.ChainID == nil || opts.Chain == nil {
+		return verkle.ErrInitUnexpectedNil
+	}
+	if chainID := opts.ChainConfig.ChainID; chainID.IsUint64() {
+		if id := chainID.Uint64(); id == params.MainnetNetworkId || id == params.KairosNetworkId {
+			return verkle.ErrNotDevnet
+		}
+	}
+	s.InitOpts = *opts
+	return nil
+}
+
+// Start converts the state of the current block. The tree lives in memory only, so it is rebuilt
+// at every start.
+func (s *VerkleModule) Start() error {
+	logger.Warn("Verkle shadow commitment enabled. This is a prototype for devnets and keeps the whole state in memory")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.rebuild(s.Chain.CurrentBlock().Header()); err != nil {
+		// Retried at the next block.
+		errCounter.Inc(1)
+		logger.Error("Failed to build the verkle tree", "err", err)
+	}
+	return nil
+}
+
+func (s *VerkleModule) Stop() {}
@@ -0,0 +1,146 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package verkle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kaiachain/kaia/blockchain/state"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/kaiax/verkle"
+	"github.com/kaiachain/kaia/networks/rpc"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/storage/database"
+	verkletree "github.com/kaiachain/kaia/storage/verkle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testChain struct {
+	db      state.Database
+	current *types.Block
+}
+
+func (c *testChain) CurrentBlock() *types.Block { return c.current }
+func (c *testChain) StateCache() state.Database { return c.db }
+
+// commitBlock applies fn to the state of the parent and returns the child block.
+func (c *testChain) commitBlock(t *testing.T, parent *types.Header, fn func(*state.StateDB)) *types.Block {
+	var root common.Hash
+	if parent != nil {
+		root = parent.Root
+	}
+	statedb, err := state.New(root, c.db, nil, nil)
+	require.NoError(t, err)
+	fn(statedb)
+	root, err = statedb.Commit(true)
+	require.NoError(t, err)
+	require.NoError(t, c.db.TrieDB().Commit(root, false, 0))
+
+	header := &types.Header{Number: big.NewInt(0), Root: root}
+	if parent != nil {
+		header.Number = new(big.Int).Add(parent.Number, common.Big1)
+		header.ParentHash = parent.Hash()
+	}
+	return types.NewBlockWithHeader(header)
+}
+
+func TestVerkleModule(t *testing.T) {
+	var (
+		chain    = &testChain{db: state.NewDatabase(database.NewMemoryDBManager())}
+		eoa1     = common.HexToAddress("0x1")
+		eoa2     = common.HexToAddress("0x2")
+		contract = common.HexToAddress("0x3")
+	)
+	genesis := chain.commitBlock(t, nil, func(s *state.StateDB) {
+		s.SetBalance(eoa1, big.NewInt(100))
+		s.CreateSmartContractAccount(contract, params.CodeFormatEVM, params.Rules{})
+		s.SetCode(contract, []byte{0x60, 0x01, 0x60, 0x00, 0x55})
+		s.SetState(contract, common.HexToHash("0x0"), common.HexToHash("0x1"))
+		s.SetState(contract, common.HexToHash("0x1"), common.HexToHash("0x2"))
+	})
+	chain.current = genesis
+
+	config := params.TestChainConfig.Copy()
+	s := NewVerkleModule()
+	require.NoError(t, s.Init(&InitOpts{ChainConfig: config, Chain: chain}))
+	require.NoError(t, s.Start())
+
+	// The incrementally updated tree must match the tree converted from the same state.
+	requireConverted := func(root common.Hash) {
+		tree, _, err := verkletree.ConvertState(chain.db.TrieDB(), root)
+		require.NoError(t, err)
+		require.Equal(t, tree.Commit(), s.Status().VerkleRoot)
+	}
+	requireConverted(genesis.Root())
+
+	block1 := chain.commitBlock(t, genesis.Header(), func(s *state.StateDB) {
+		s.SetBalance(eoa1, big.NewInt(50))
+		s.SetBalance(eoa2, big.NewInt(50))
+		s.SetState(contract, common.HexToHash("0x0"), common.HexToHash("0x5"))
+		s.SetState(contract, common.HexToHash("0x1"), common.Hash{})
+		s.SetState(contract, common.HexToHash("0x2"), common.HexToHash("0x3"))
+	})
+	require.NoError(t, s.PostInsertBlock(block1))
+	requireConverted(block1.Root())
+
+	u := s.Status().Updates[0]
+	assert.False(t, u.Rebuilt)
+	assert.Equal(t, 3, u.Accounts)
+	assert.Equal(t, 3, u.Slots)
+	assert.Equal(t, 3*6+3, u.WitnessKeys)
+	assert.Greater(t, u.WitnessSize, 0)
+
+	block2 := chain.commitBlock(t, block1.Header(), func(s *state.StateDB) {
+		s.SelfDestruct(contract)
+	})
+	require.NoError(t, s.PostInsertBlock(block2))
+	requireConverted(block2.Root())
+
+	// A block on another branch is converted.
+	fork := chain.commitBlock(t, block1.Header(), func(s *state.StateDB) {
+		s.SetNonce(eoa1, 1)
+	})
+	require.NoError(t, s.PostInsertBlock(fork))
+	requireConverted(fork.Root())
+
+	api := NewDebugVerkleAPI(s)
+	u, err := api.VerkleUpdate(rpc.LatestBlockNumber)
+	require.NoError(t, err)
+	assert.True(t, u.Rebuilt)
+	assert.Equal(t, fork.Hash(), u.Hash)
+	u, err = api.VerkleUpdate(rpc.BlockNumber(1))
+	require.NoError(t, err)
+	assert.Equal(t, block1.Hash(), u.Hash)
+	_, err = api.VerkleUpdate(rpc.BlockNumber(10))
+	assert.ErrorIs(t, err, verkle.ErrBlockNotFound)
+
+	w, err := api.VerkleWitness(eoa1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, s.Status().VerkleRoot, w.Root)
+	require.Len(t, w.Stems, 1)
+	assert.Equal(t, uint8(verkletree.StemPresent), w.Stems[0].Status)
+}
+
+func TestVerkleModuleNotDevnet(t *testing.T) {
+	s := NewVerkleModule()
+	chain := &testChain{db: state.NewDatabase(database.NewMemoryDBManager())}
+	assert.ErrorIs(t, s.Init(&InitOpts{ChainConfig: params.MainnetChainConfig, Chain: chain}), verkle.ErrNotDevnet)
+	assert.ErrorIs(t, s.Init(&InitOpts{ChainConfig: params.KairosChainConfig, Chain: chain}), verkle.ErrNotDevnet)
+	assert.ErrorIs(t, s.Init(&InitOpts{ChainConfig: params.TestChainConfig}), verkle.ErrInitUnexpectedNil)
+}
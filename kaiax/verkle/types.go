@@ -0,0 +1,47 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package verkle
+
+import (
+	"github.com/kaiachain/kaia/common"
+	verkletree "github.com/kaiachain/kaia/storage/verkle"
+)
+
+// Update describes how the verkle tree followed an imported block.
+type Update struct {
+	Number     uint64      `json:"number"`
+	Hash       common.Hash `json:"hash"`
+	StateRoot  common.Hash `json:"stateRoot"`  // the MPT root of the block
+	VerkleRoot common.Hash `json:"verkleRoot"` // the verkle root of the same state
+	Rebuilt    bool        `json:"rebuilt"`    // the tree was converted from the MPT instead of updated
+
+	// The accounts and slots written by the block, and the witness of their keys against the parent state.
+	// Zero if Rebuilt.
+	Accounts    int `json:"accounts"`
+	Slots       int `json:"slots"`
+	WitnessKeys int `json:"witnessKeys"`
+	WitnessSize int `json:"witnessSize"` // bytes, without the opening proof
+
+	ElapsedMs int64 `json:"elapsedMs"`
+}
+
+type Status struct {
+	Number     uint64               `json:"number"`
+	Hash       common.Hash          `json:"hash"`
+	VerkleRoot common.Hash          `json:"verkleRoot"`
+	Tree       verkletree.TreeStats `json:"tree"`
+	Updates    []*Update            `json:"updates"` // oldest first
+}
@@ -134,6 +134,11 @@ const (
 
 	// 61~70
 	KaiaxGov
+	DatasyncFollower
+	KaiaxAbiStore
+	KaiaxInvariant
+	KaiaxStateExpiry
+	KaiaxVerkle
 
 	// ModuleNameLen should be placed at the end of the list.
 	ModuleNameLen
@@ -217,4 +222,9 @@ var moduleNames = [ModuleNameLen]string{
 
 	// 61~70
 	"kaiax/gov",
+	"datasync/follower",
+	"kaiax/abistore",
+	"kaiax/invariant",
+	"kaiax/stateexpiry",
+	"kaiax/verkle",
 }
@@ -0,0 +1,58 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// authHandler is a handler which admits only the requests carrying one of the allowed
+// bearer tokens in the Authorization header.
+type authHandler struct {
+	tokens [][]byte
+	next   http.Handler
+}
+
+// ServeHTTP serves JSON-RPC requests over HTTP, implements http.Handler
+func (h *authHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	const prefix = "Bearer "
+	auth := r.Header.Get("Authorization")
+	if strings.HasPrefix(auth, prefix) {
+		given := []byte(strings.TrimPrefix(auth, prefix))
+		for _, token := range h.tokens {
+			if subtle.ConstantTimeCompare(given, token) == 1 {
+				h.next.ServeHTTP(w, r)
+				return
+			}
+		}
+	}
+	http.Error(w, "unauthorized", http.StatusUnauthorized)
+}
+
+// newAuthHandler returns next as it is if no token is given.
+func newAuthHandler(tokens []string, next http.Handler) http.Handler {
+	if len(tokens) == 0 {
+		return next
+	}
+	h := &authHandler{next: next}
+	for _, token := range tokens {
+		h.tokens = append(h.tokens, []byte(token))
+	}
+	return h
+}
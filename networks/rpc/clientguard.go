@@ -0,0 +1,68 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"errors"
+	"sync"
+)
+
+// ClientGuard refuses the RPC clients banned by the operator and learns of their misbehavior.
+// The clients are identified by host, as in the heavy call queue.
+type ClientGuard interface {
+	// Blocked returns true if the requests from the host must be refused.
+	Blocked(host string) bool
+	// Misbehaved reports a request from the host that is rejected as abusive.
+	Misbehaved(host, reason string)
+}
+
+var (
+	errClientBlocked = errors.New("client is blocked")
+
+	guardMu     sync.RWMutex
+	clientGuard ClientGuard
+)
+
+// SetClientGuard registers the guard of all RPC servers. Passing nil removes it.
+func SetClientGuard(g ClientGuard) {
+	guardMu.Lock()
+	defer guardMu.Unlock()
+	clientGuard = g
+}
+
+func getClientGuard() ClientGuard {
+	guardMu.RLock()
+	defer guardMu.RUnlock()
+	return clientGuard
+}
+
+// blockedClient returns true if the client at the remote address is refused by the guard.
+func blockedClient(remoteAddr string) bool {
+	g := getClientGuard()
+	if g == nil || !g.Blocked(clientKey(remoteAddr)) {
+		return false
+	}
+	blockedRequestsCounter.Inc(1)
+	return true
+}
+
+// reportMisbehavior tells the guard about an abusive request from the remote address.
+func reportMisbehavior(remoteAddr, reason string) {
+	if g := getClientGuard(); g != nil {
+		g.Misbehaved(clientKey(remoteAddr), reason)
+	}
+}
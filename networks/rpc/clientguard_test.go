@@ -0,0 +1,63 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type testGuard struct {
+	blocked    map[string]bool
+	misbehaved []string
+}
+
+func (g *testGuard) Blocked(host string) bool { return g.blocked[host] }
+
+func (g *testGuard) Misbehaved(host, reason string) { g.misbehaved = append(g.misbehaved, host) }
+
+func TestClientGuard(t *testing.T) {
+	server := newTestServer("service", new(Service))
+	defer server.Stop()
+
+	guard := &testGuard{blocked: map[string]bool{"10.0.0.1": true}}
+	SetClientGuard(guard)
+	defer SetClientGuard(nil)
+
+	post := func(remoteAddr, body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("content-type", contentType)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	request := `{"jsonrpc":"2.0","id":1,"method":"service_echo","params":["hello",10,{"S":"world"}]}`
+
+	assert.Equal(t, http.StatusForbidden, post("10.0.0.1:5000", request))
+	assert.Equal(t, http.StatusOK, post("10.0.0.2:5000", request))
+
+	// An oversized request is reported.
+	large := strings.Repeat(" ", common.MaxRequestContentLength+1)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, post("10.0.0.2:5000", large))
+	assert.Equal(t, []string{"10.0.0.2"}, guard.misbehaved)
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import "sync/atomic"
+
+var (
+	// draining is set when the node is preparing to terminate. While it is set,
+	// only the namespaces in drainExemptNamespaces accept new method calls.
+	draining int32 = 0
+
+	// inflightCallCount is the number of method calls currently being executed,
+	// excluding the calls to drainExemptNamespaces.
+	inflightCallCount int64 = 0
+
+	// drainExemptNamespaces are the namespaces which keep serving during the drain
+	// so that the operator can still observe and control the node.
+	drainExemptNamespaces = map[string]bool{
+		"admin":     true,
+		MetadataApi: true,
+	}
+)
+
+// StartDraining makes all RPC servers reject new method calls except for the
+// administrative namespaces. In-flight calls are not interrupted.
+func StartDraining() {
+	atomic.StoreInt32(&draining, 1)
+}
+
+// StopDraining makes all RPC servers accept new method calls again.
+func StopDraining() {
+	atomic.StoreInt32(&draining, 0)
+}
+
+// IsDraining returns true if the RPC servers are rejecting new method calls.
+func IsDraining() bool {
+	return atomic.LoadInt32(&draining) == 1
+}
+
+// InflightCallCount returns the number of method calls being executed,
+// excluding the administrative ones which are served during the drain.
+func InflightCallCount() int64 {
+	return atomic.LoadInt64(&inflightCallCount)
+}
+
+// rejectedByDrain returns true if a call to the given namespace must be refused.
+func rejectedByDrain(namespace string) bool {
+	return IsDraining() && !drainExemptNamespaces[namespace]
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"testing"
+)
+
+func TestServerDraining(t *testing.T) {
+	server := newTestServer("service", new(Service))
+	defer server.Stop()
+	if err := server.RegisterName("admin", new(Service)); err != nil {
+		t.Fatal(err)
+	}
+	client := DialInProc(server)
+	defer client.Close()
+
+	StartDraining()
+	defer StopDraining()
+
+	var resp Result
+	err := client.Call(&resp, "service_echo", "hello", 10, &Args{"world"})
+	if err == nil || err.Error() != (&drainingError{}).Error() {
+		t.Fatalf("expected draining error, got %v", err)
+	}
+	if err := client.Call(&resp, "admin_echo", "hello", 10, &Args{"world"}); err != nil {
+		t.Fatalf("admin namespace must be served while draining: %v", err)
+	}
+	if n := InflightCallCount(); n != 0 {
+		t.Errorf("expected no in-flight calls, got %d", n)
+	}
+
+	StopDraining()
+	if err := client.Call(&resp, "service_echo", "hello", 10, &Args{"world"}); err != nil {
+		t.Fatalf("expected call to succeed after drain stop: %v", err)
+	}
+}
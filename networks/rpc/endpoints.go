@@ -24,10 +24,17 @@ package rpc
 
 import (
 	"net"
+	"net/http"
 )
 
 // StartHTTPEndpoint starts the HTTP RPC endpoint, configured with cors/vhosts/modules
 func StartHTTPEndpoint(endpoint string, apis []API, modules []string, cors []string, vhosts []string, timeouts HTTPTimeouts) (net.Listener, *Server, error) {
+	return StartAuthHTTPEndpoint(endpoint, apis, modules, cors, vhosts, timeouts, nil)
+}
+
+// StartAuthHTTPEndpoint starts the HTTP RPC endpoint, configured with cors/vhosts/modules.
+// If authTokens is not empty, requests must carry one of them as a bearer token.
+func StartAuthHTTPEndpoint(endpoint string, apis []API, modules []string, cors []string, vhosts []string, timeouts HTTPTimeouts, authTokens []string) (net.Listener, *Server, error) {
 	// Generate the whitelist based on the allowed modules
 	whitelist := make(map[string]bool)
 	for _, module := range modules {
@@ -59,12 +66,18 @@ func StartHTTPEndpoint(endpoint string, apis []API, modules []string, cors []str
 	if listener, err = net.Listen("tcp", endpoint); err != nil {
 		return nil, nil, err
 	}
-	go NewHTTPServer(cors, vhosts, timeouts, handler).Serve(listener)
+	go NewHTTPServer(cors, vhosts, timeouts, newAuthHandler(authTokens, handler)).Serve(listener)
 	return listener, handler, err
 }
 
 // StartWSEndpoint starts a websocket endpoint
 func StartWSEndpoint(endpoint string, apis []API, modules []string, wsOrigins []string, exposeAll bool) (net.Listener, *Server, error) {
+	return StartAuthWSEndpoint(endpoint, apis, modules, wsOrigins, exposeAll, nil)
+}
+
+// StartAuthWSEndpoint starts a websocket endpoint. If authTokens is not empty, the
+// handshake requests must carry one of them as a bearer token.
+func StartAuthWSEndpoint(endpoint string, apis []API, modules []string, wsOrigins []string, exposeAll bool, authTokens []string) (net.Listener, *Server, error) {
 	// Generate the whitelist based on the allowed modules
 	whitelist := make(map[string]bool)
 	for _, module := range modules {
@@ -96,7 +109,7 @@ func StartWSEndpoint(endpoint string, apis []API, modules []string, wsOrigins []
 	if listener, err = net.Listen("tcp", endpoint); err != nil {
 		return nil, nil, err
 	}
-	go NewWSServer(wsOrigins, handler).Serve(listener)
+	go (&http.Server{Handler: newAuthHandler(authTokens, handler.WebsocketHandler(wsOrigins))}).Serve(listener)
 	return listener, handler, err
 }
 
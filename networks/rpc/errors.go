@@ -16,7 +16,10 @@
 
 package rpc
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 const defaultErrorCode = -32000
 
@@ -77,3 +80,28 @@ type shutdownError struct{}
 func (e *shutdownError) ErrorCode() int { return defaultErrorCode }
 
 func (e *shutdownError) Error() string { return "server is shutting down" }
+
+// issued when a request is received while the server is draining.
+type drainingError struct{}
+
+func (e *drainingError) ErrorCode() int { return defaultErrorCode }
+
+func (e *drainingError) Error() string { return "server is draining" }
+
+// issued when a method is disabled to shed load.
+type overloadedError struct{ level LoadLevel }
+
+func (e *overloadedError) ErrorCode() int { return -32005 }
+
+func (e *overloadedError) Error() string {
+	return fmt.Sprintf("method disabled while the node is under %s load", e.level)
+}
+
+// issued when a heavy call used up its execution time.
+type execTimeLimitError struct{ limit time.Duration }
+
+func (e *execTimeLimitError) ErrorCode() int { return defaultErrorCode }
+
+func (e *execTimeLimitError) Error() string {
+	return fmt.Sprintf("execution time limit exceeded: %v of CPU time", e.limit)
+}
@@ -335,8 +335,10 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMess
 		return nil
 	case msg.isCall():
 		resp := h.handleCall(ctx, msg)
+		duration := time.Since(start)
+		h.observeCall(msg, resp, duration)
 		var ctx []interface{}
-		ctx = append(ctx, "reqid", idForLog{msg.ID}, "duration", time.Since(start))
+		ctx = append(ctx, "reqid", idForLog{msg.ID}, "duration", duration)
 		if resp.Error != nil {
 			ctx = append(ctx, "err", resp.Error.Message)
 			if resp.Error.Data != nil {
@@ -377,6 +379,21 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 		rpcErrorResponsesCounter.Inc(1)
 		return msg.errorResponse(&invalidParamsError{err.Error()})
 	}
+	if rejectedByDrain(msg.namespace()) {
+		rpcErrorResponsesCounter.Inc(1)
+		return msg.errorResponse(&drainingError{})
+	}
+	if !drainExemptNamespaces[msg.namespace()] {
+		atomic.AddInt64(&inflightCallCount, 1)
+		defer atomic.AddInt64(&inflightCallCount, -1)
+	}
+	if rejectedByLoad(msg.Method) {
+		rpcErrorResponsesCounter.Inc(1)
+		return msg.errorResponse(&overloadedError{GetLoadLevel()})
+	}
+	if isHeavyMethod(msg.Method) {
+		return h.runHeavyMethod(cp.ctx, msg, callb, args)
+	}
 	return h.runMethod(cp.ctx, msg, callb, args)
 }
 
@@ -395,6 +412,11 @@ func (h *handler) handleSubscribe(cp *callProc, msg *jsonrpcMessage) *jsonrpcMes
 		})
 	}
 
+	if rejectedByDrain(msg.namespace()) {
+		rpcErrorResponsesCounter.Inc(1)
+		return msg.errorResponse(&drainingError{})
+	}
+
 	// Subscription method name is first argument.
 	name, err := parseSubscriptionName(msg.Params)
 	if err != nil {
@@ -431,13 +453,14 @@ func (h *handler) handleSubscribe(cp *callProc, msg *jsonrpcMessage) *jsonrpcMes
 func (h *handler) runMethod(ctx context.Context, msg *jsonrpcMessage, callb *callback, args []reflect.Value) *jsonrpcMessage {
 	result, err := callb.call(ctx, msg.Method, args)
 	if err != nil {
-		// TODO-Kaia:
-		// 1. The single URL may be extended to the list of URL.
-		// 2. The URL (list or single) seems not modifiable at runtime.
-		// 3. Any request can be relayed as well as the state lack.
-		// 4. Make a new rpcErrorResponse struct.
-		if UpstreamArchiveEN != "" && shouldRequestUpstream(err) {
-			return requestUpstream(ctx, msg, args)
+		if proxy := getArchiveProxy(); proxy != nil && shouldRequestUpstream(err) {
+			resp := proxy.forward(ctx, h.reg, msg)
+			if resp.Error != nil {
+				rpcErrorResponsesCounter.Inc(1)
+			} else {
+				rpcSuccessResponsesCounter.Inc(1)
+			}
+			return resp
 		}
 		rpcErrorResponsesCounter.Inc(1)
 		return msg.errorResponse(err)
@@ -455,30 +478,6 @@ func shouldRequestUpstream(err error) bool {
 	return errors.As(err, &missingNodeError)
 }
 
-// requestUpstream is the function to request upstream archive en
-func requestUpstream(ctx context.Context, msg *jsonrpcMessage, args []reflect.Value) *jsonrpcMessage {
-	ctx, cancel := context.WithTimeout(ctx, DefaultHTTPTimeouts.ExecutionTimeout)
-	defer cancel()
-
-	var result interface{}
-	c, err := DialContext(ctx, UpstreamArchiveEN)
-	if err == nil {
-		defer c.Close()
-
-		var params []interface{}
-		for _, a := range args {
-			params = append(params, a.Interface())
-		}
-		if err = c.CallContext(ctx, &result, msg.Method, params...); err == nil {
-			rpcSuccessResponsesCounter.Inc(1)
-			return msg.response(result)
-		}
-	}
-
-	rpcErrorResponsesCounter.Inc(1)
-	return msg.errorResponse(err)
-}
-
 // unsubscribe is the callback function for all *_unsubscribe calls.
 func (h *handler) unsubscribe(ctx context.Context, id ID) (bool, error) {
 	h.subLock.Lock()
@@ -0,0 +1,274 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kaiachain/kaia/common/cpulimit"
+)
+
+var (
+	// HeavyCallSlots is the number of heavy calls executed at the same time.
+	// If it is 0, half of the CPUs available to the process are used so that
+	// the other half is left for block processing.
+	// It can be overwritten by rpc.heavy-call.slots flag
+	HeavyCallSlots = 0
+
+	// HeavyCallExecTimeLimit is the maximum CPU time a heavy call can consume after it is
+	// dequeued. The waiting time in the queue is not counted. 0 means no limit.
+	// It can be overwritten by rpc.heavy-call.exec-time-limit flag
+	HeavyCallExecTimeLimit time.Duration = 0
+
+	// heavyCallMeterInterval is the interval of charging the CPU time to the running heavy calls.
+	heavyCallMeterInterval = 20 * time.Millisecond
+
+	// heavyMethodPrefixes are the prefixes of the CPU-bound methods which re-execute transactions.
+	heavyMethodPrefixes = []string{"debug_trace", "debug_standardTrace"}
+
+	heavyQueueOnce sync.Once
+	heavyQueue     *fairQueue
+)
+
+// isHeavyMethod returns true if the method must be executed through the heavy call queue.
+func isHeavyMethod(method string) bool {
+	for _, prefix := range heavyMethodPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// heavyCallQueue returns the queue shared by all RPC servers.
+func heavyCallQueue() *fairQueue {
+	heavyQueueOnce.Do(func() {
+		slots := HeavyCallSlots
+		if slots <= 0 {
+			slots = cpulimit.NumCPU() / 2
+		}
+		if slots < 1 {
+			slots = 1
+		}
+		logger.Info("Initialized heavy RPC call queue", "slots", slots, "execTimeLimit", HeavyCallExecTimeLimit)
+		heavyQueue = newFairQueue(slots)
+	})
+	return heavyQueue
+}
+
+// clientKey identifies the client of a connection for fair queuing.
+// Connections from the same host share the same key.
+func clientKey(remoteAddr string) string {
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		return host
+	}
+	return remoteAddr
+}
+
+// runHeavyMethod executes a heavy method once the client gets a slot of the heavy call queue.
+// The context of the method is cancelled when it consumes HeavyCallExecTimeLimit of CPU time,
+// so the method must honour the cancellation to be aborted, e.g. by cancelling its EVM.
+func (h *handler) runHeavyMethod(ctx context.Context, msg *jsonrpcMessage, callb *callback, args []reflect.Value) *jsonrpcMessage {
+	client := clientKey(h.conn.remoteAddr())
+
+	queue := heavyCallQueue()
+	queuedAt := time.Now()
+	if err := queue.acquire(ctx, client); err != nil {
+		rpcErrorResponsesCounter.Inc(1)
+		return msg.errorResponse(err)
+	}
+	defer queue.release()
+
+	startedAt := time.Now()
+	heavyCallWaitTimer.Update(startedAt.Sub(queuedAt))
+
+	limit := HeavyCallExecTimeLimit
+	if limit > 0 {
+		var cancel context.CancelCauseFunc
+		ctx, cancel = context.WithCancelCause(ctx)
+		defer cancel(nil)
+		go queue.meter(ctx, cancel, limit)
+	}
+	resp := h.runMethod(ctx, msg, callb, args)
+
+	elapsed := time.Since(startedAt)
+	heavyCallExecTimer.Update(elapsed)
+	logger.Debug("Executed heavy RPC call", "method", msg.Method, "client", client,
+		"wait", startedAt.Sub(queuedAt), "exec", elapsed)
+
+	var limitErr *execTimeLimitError
+	if errors.As(context.Cause(ctx), &limitErr) {
+		heavyCallLimitCounter.Inc(1)
+		return msg.errorResponse(limitErr)
+	}
+	return resp
+}
+
+// meter charges a running call with its share of the CPU time of the process until ctx is done,
+// and cancels the call with execTimeLimitError once the charged time reaches the limit.
+// The CPU time consumed while the calls run is split evenly among them, so the block processing
+// in the meantime is charged to them too. Where the CPU time is not available, the wall-clock
+// time is charged instead.
+func (q *fairQueue) meter(ctx context.Context, cancel context.CancelCauseFunc, limit time.Duration) {
+	ticker := time.NewTicker(heavyCallMeterInterval)
+	defer ticker.Stop()
+
+	var (
+		used       time.Duration
+		lastCPU, _ = cpulimit.ProcessCPUTime()
+		lastAt     = time.Now()
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if cpu, ok := cpulimit.ProcessCPUTime(); ok {
+				used += (cpu - lastCPU) / time.Duration(q.runningCount())
+				lastCPU = cpu
+			} else {
+				used += now.Sub(lastAt)
+			}
+			lastAt = now
+			if used >= limit {
+				cancel(&execTimeLimitError{limit})
+				return
+			}
+		}
+	}
+}
+
+// fairQueue limits the number of concurrent executions. When every slot is taken, the
+// waiting requests are queued per client and dispatched to the clients in turn, so that
+// a client flooding requests cannot delay the requests of the others.
+type fairQueue struct {
+	mu      sync.Mutex
+	slots   int
+	running int
+	waiters map[string][]chan struct{} // waiting requests per client, in arrival order
+	turns   []string                   // clients having waiting requests, in dispatch order
+}
+
+func newFairQueue(slots int) *fairQueue {
+	return &fairQueue{
+		slots:   slots,
+		waiters: make(map[string][]chan struct{}),
+	}
+}
+
+// acquire blocks until a slot is assigned to the request or ctx is done.
+func (q *fairQueue) acquire(ctx context.Context, client string) error {
+	q.mu.Lock()
+	if q.running < q.slots && len(q.turns) == 0 {
+		q.running++
+		q.mu.Unlock()
+		return nil
+	}
+	ready := make(chan struct{})
+	if len(q.waiters[client]) == 0 {
+		q.turns = append(q.turns, client)
+	}
+	q.waiters[client] = append(q.waiters[client], ready)
+	heavyCallQueuedCounter.Inc(1)
+	q.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		select {
+		case <-ready:
+			// The slot has been assigned right before the cancellation.
+			q.mu.Unlock()
+			q.release()
+		default:
+			q.remove(client, ready)
+			q.mu.Unlock()
+		}
+		return ctx.Err()
+	}
+}
+
+// release returns the slot to the next client in turn.
+func (q *fairQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.turns) == 0 {
+		q.running--
+		return
+	}
+	client := q.turns[0]
+	q.turns = q.turns[1:]
+
+	ready := q.waiters[client][0]
+	if rest := q.waiters[client][1:]; len(rest) > 0 {
+		q.waiters[client] = rest
+		q.turns = append(q.turns, client)
+	} else {
+		delete(q.waiters, client)
+	}
+	heavyCallQueuedCounter.Dec(1)
+	close(ready)
+}
+
+// runningCount returns the number of executing requests, which is at least one for a caller holding a slot.
+func (q *fairQueue) runningCount() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.running < 1 {
+		return 1
+	}
+	return q.running
+}
+
+// waitersOf returns the number of waiting requests of the client.
+func (q *fairQueue) waitersOf(client string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.waiters[client])
+}
+
+// remove drops a cancelled request from the queue. The caller must hold the lock.
+func (q *fairQueue) remove(client string, ready chan struct{}) {
+	waiters := q.waiters[client]
+	for i, w := range waiters {
+		if w == ready {
+			waiters = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	heavyCallQueuedCounter.Dec(1)
+	if len(waiters) > 0 {
+		q.waiters[client] = waiters
+		return
+	}
+	delete(q.waiters, client)
+	for i, c := range q.turns {
+		if c == client {
+			q.turns = append(q.turns[:i], q.turns[i+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kaiachain/kaia/common/cpulimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFairQueue(t *testing.T) {
+	q := newFairQueue(1)
+	assert.Nil(t, q.acquire(context.Background(), "a"))
+
+	// While the only slot is taken, client a floods the queue before client b.
+	order := make(chan string, 4)
+	enqueue := func(client string) {
+		queued := q.waitersOf(client)
+		go func() {
+			assert.Nil(t, q.acquire(context.Background(), client))
+			order <- client
+		}()
+		for q.waitersOf(client) == queued {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	enqueue("a")
+	enqueue("a")
+	enqueue("a")
+	enqueue("b")
+
+	// Client b is served right after the first request of client a.
+	var served []string
+	for i := 0; i < 4; i++ {
+		q.release()
+		served = append(served, <-order)
+	}
+	assert.Equal(t, []string{"a", "b", "a", "a"}, served)
+
+	q.release()
+	assert.Equal(t, 0, q.running)
+}
+
+func TestFairQueueCancel(t *testing.T) {
+	q := newFairQueue(1)
+	assert.Nil(t, q.acquire(context.Background(), "a"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, q.acquire(ctx, "b"))
+	assert.Equal(t, 0, q.waitersOf("b"))
+	assert.Empty(t, q.turns)
+
+	q.release()
+	assert.Nil(t, q.acquire(context.Background(), "b"))
+}
+
+type heavyService struct{}
+
+// Spin keeps a CPU busy for the duration or until ctx is done.
+func (s *heavyService) Spin(ctx context.Context, duration time.Duration) {
+	for deadline := time.Now().Add(duration); time.Now().Before(deadline) && ctx.Err() == nil; {
+	}
+}
+
+func TestHeavyCallExecTimeLimit(t *testing.T) {
+	server := newTestServer("service", new(Service))
+	defer server.Stop()
+	assert.Nil(t, server.RegisterName("heavy", new(heavyService)))
+	client := DialInProc(server)
+	defer client.Close()
+
+	defer func(prefixes []string, limit time.Duration) {
+		heavyMethodPrefixes, HeavyCallExecTimeLimit = prefixes, limit
+	}(heavyMethodPrefixes, HeavyCallExecTimeLimit)
+	heavyMethodPrefixes = []string{"service_sleep", "heavy_spin"}
+	HeavyCallExecTimeLimit = 50 * time.Millisecond
+
+	// A busy call is cancelled once it consumes the limit of CPU time.
+	start := time.Now()
+	err := client.Call(nil, "heavy_spin", 10*time.Second)
+	assert.EqualError(t, err, (&execTimeLimitError{HeavyCallExecTimeLimit}).Error())
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	assert.Nil(t, client.Call(nil, "heavy_spin", time.Millisecond))
+
+	// Waiting consumes no CPU time, so an idle call outlives the limit if the CPU time is metered.
+	if _, ok := cpulimit.ProcessCPUTime(); ok {
+		assert.Nil(t, client.Call(nil, "service_sleep", 200*time.Millisecond))
+	}
+}
@@ -347,11 +347,26 @@ func NewFastHTTPServer(cors []string, vhosts []string, timeouts HTTPTimeouts, sr
 
 // ServeHTTP serves JSON-RPC requests over HTTP.
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if blockedClient(r.RemoteAddr) {
+		http.Error(w, errClientBlocked.Error(), http.StatusForbidden)
+		return
+	}
+	if r.Method == http.MethodGet && r.URL.Path == healthPath {
+		if code, body, ok := healthResponse(); ok {
+			w.Header().Set("content-type", contentType)
+			w.WriteHeader(code)
+			w.Write(body)
+			return
+		}
+	}
 	// Permit dumb empty requests for remote health-checks (AWS)
 	if r.Method == http.MethodGet && r.ContentLength == 0 && r.URL.RawQuery == "" {
 		return
 	}
 	if code, err := validateRequest(r); err != nil {
+		if code == http.StatusRequestEntityTooLarge {
+			reportMisbehavior(r.RemoteAddr, err.Error())
+		}
 		http.Error(w, err.Error(), code)
 		return
 	}
@@ -379,11 +394,26 @@ func (srv *Server) HandleFastHTTP(requestCtx *fasthttp.RequestCtx) {
 	r := &requestCtx.Request
 	w := &requestCtx.Response
 
+	if blockedClient(requestCtx.RemoteAddr().String()) {
+		requestCtx.Error(errClientBlocked.Error(), http.StatusForbidden)
+		return
+	}
+	if requestCtx.IsGet() && string(requestCtx.Path()) == healthPath {
+		if code, body, ok := healthResponse(); ok {
+			w.Header.SetContentType(contentType)
+			w.SetStatusCode(code)
+			w.SetBody(body)
+			return
+		}
+	}
 	// Permit dumb empty requests for remote health-checks (AWS)
 	if requestCtx.IsGet() && requestCtx.Request.Header.ContentLength() == 0 && string(requestCtx.URI().QueryString()) == "" {
 		return
 	}
 	if code, err := validateFastRequest(requestCtx); err != nil {
+		if code == http.StatusRequestEntityTooLarge {
+			reportMisbehavior(requestCtx.RemoteAddr().String(), err.Error())
+		}
 		w.Header.Set("Content-Type", "text/plain; charset=utf-8")
 		w.Header.Set("X-Content-Type-Options", "nosniff")
 		w.Header.SetStatusCode(code)
@@ -124,7 +124,7 @@ func (msg *jsonrpcMessage) errorResponse(err error) *jsonrpcMessage {
 }
 
 func (msg *jsonrpcMessage) response(result interface{}) *jsonrpcMessage {
-	enc, err := json.Marshal(result)
+	enc, err := jsonMarshal(result)
 	if err != nil {
 		// TODO: wrap with 'internal server error'
 		return msg.errorResponse(err)
@@ -225,11 +225,12 @@ func NewFuncCodec(conn deadlineCloser, encode, decode func(v interface{}) error)
 
 // NewCodec creates a codec on the given connection. If conn implements ConnRemoteAddr, log
 // messages will use it to include the remote address of the connection.
+// Messages are written with the active JSONCodec.
 func NewCodec(conn Conn) ServerCodec {
-	enc := json.NewEncoder(conn)
+	encode := func(v interface{}) error { return jsonEncode(conn, v) }
 	dec := json.NewDecoder(conn)
 	dec.UseNumber()
-	return NewFuncCodec(conn, enc.Encode, dec.Decode)
+	return NewFuncCodec(conn, encode, dec.Decode)
 }
 
 func (c *jsonCodec) remoteAddr() string {
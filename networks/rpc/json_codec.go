@@ -0,0 +1,250 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	JSONCodecStd    = "std"
+	JSONCodecStream = "stream"
+)
+
+// JSONCodec serializes RPC results and messages. Implementations must produce
+// output that encoding/json can decode into the same value.
+type JSONCodec interface {
+	// Marshal returns the JSON encoding of v. The returned slice is owned by the caller.
+	Marshal(v interface{}) ([]byte, error)
+	// Encode writes the JSON encoding of v followed by a newline to w.
+	Encode(w io.Writer, v interface{}) error
+}
+
+var (
+	jsonCodecsMu sync.RWMutex
+	jsonCodecs   = map[string]JSONCodec{
+		JSONCodecStd:    stdJSONCodec{},
+		JSONCodecStream: streamJSONCodec{},
+	}
+	activeCodec atomic.Value // holds namedJSONCodec
+)
+
+type namedJSONCodec struct {
+	name  string
+	codec JSONCodec
+}
+
+func init() {
+	activeCodec.Store(namedJSONCodec{JSONCodecStream, streamJSONCodec{}})
+}
+
+// RegisterJSONCodec makes a codec available to SetJSONCodec under the given name.
+func RegisterJSONCodec(name string, codec JSONCodec) {
+	jsonCodecsMu.Lock()
+	defer jsonCodecsMu.Unlock()
+	jsonCodecs[name] = codec
+}
+
+// JSONCodecs returns the names of the registered codecs.
+func JSONCodecs() []string {
+	jsonCodecsMu.RLock()
+	defer jsonCodecsMu.RUnlock()
+	names := make([]string, 0, len(jsonCodecs))
+	for name := range jsonCodecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetJSONCodec switches the codec used by all RPC transports. It can be called at
+// any time; messages being written keep the codec they started with.
+func SetJSONCodec(name string) error {
+	jsonCodecsMu.RLock()
+	codec, ok := jsonCodecs[name]
+	jsonCodecsMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown JSON codec %q (available: %v)", name, JSONCodecs())
+	}
+	activeCodec.Store(namedJSONCodec{name, codec})
+	logger.Info("Set the JSON codec of RPC", "codec", name)
+	return nil
+}
+
+// ActiveJSONCodec returns the name of the codec in use.
+func ActiveJSONCodec() string {
+	return activeCodec.Load().(namedJSONCodec).name
+}
+
+func jsonMarshal(v interface{}) ([]byte, error) {
+	start := time.Now()
+	b, err := activeCodec.Load().(namedJSONCodec).codec.Marshal(v)
+	rpcSerializeMarshalTimer.UpdateSince(start)
+	return b, err
+}
+
+func jsonEncode(w io.Writer, v interface{}) error {
+	start := time.Now()
+	err := activeCodec.Load().(namedJSONCodec).codec.Encode(w, v)
+	rpcSerializeEncodeTimer.UpdateSince(start)
+	return err
+}
+
+// stdJSONCodec is plain encoding/json.
+type stdJSONCodec struct{}
+
+func (stdJSONCodec) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+func (stdJSONCodec) Encode(w io.Writer, v interface{}) error { return json.NewEncoder(w).Encode(v) }
+
+// streamJSONCodec writes JSON-RPC envelopes by hand around the already encoded
+// id, params and result, so large results are not re-validated and compacted
+// a second time. Encoding goes through pooled buffers and reaches w in a single write.
+type streamJSONCodec struct{}
+
+// maxPooledBufferSize keeps buffers grown by huge responses (e.g. traces) out of the pool.
+const maxPooledBufferSize = 4 * 1024 * 1024
+
+var jsonBufferPool = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+
+func getJSONBuffer() *bytes.Buffer {
+	buf := jsonBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func putJSONBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledBufferSize {
+		jsonBufferPool.Put(buf)
+	}
+}
+
+func (streamJSONCodec) Marshal(v interface{}) ([]byte, error) {
+	buf := getJSONBuffer()
+	defer putJSONBuffer(buf)
+	if err := streamEncode(buf, v); err != nil {
+		return nil, err
+	}
+	// Drop the trailing newline written by the encoder.
+	out := make([]byte, buf.Len()-1)
+	copy(out, buf.Bytes())
+	return out, nil
+}
+
+func (streamJSONCodec) Encode(w io.Writer, v interface{}) error {
+	buf := getJSONBuffer()
+	defer putJSONBuffer(buf)
+	if err := streamEncode(buf, v); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// streamEncode writes v and a trailing newline to buf.
+func streamEncode(buf *bytes.Buffer, v interface{}) error {
+	switch msg := v.(type) {
+	case *jsonrpcMessage:
+		if err := writeMessage(buf, msg); err != nil {
+			return err
+		}
+	case []*jsonrpcMessage:
+		buf.WriteByte('[')
+		for i, m := range msg {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeMessage(buf, m); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		return json.NewEncoder(buf).Encode(v)
+	}
+	buf.WriteByte('\n')
+	return nil
+}
+
+// writeMessage follows the field order and omitempty rules of jsonrpcMessage.
+func writeMessage(buf *bytes.Buffer, msg *jsonrpcMessage) error {
+	if msg == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	sep := byte('{')
+	field := func(name string) {
+		buf.WriteByte(sep)
+		sep = ','
+		buf.WriteByte('"')
+		buf.WriteString(name)
+		buf.WriteString(`":`)
+	}
+	if msg.Version != "" {
+		field("jsonrpc")
+		if err := writeString(buf, msg.Version); err != nil {
+			return err
+		}
+	}
+	if len(msg.ID) > 0 {
+		field("id")
+		buf.Write(msg.ID)
+	}
+	if msg.Method != "" {
+		field("method")
+		if err := writeString(buf, msg.Method); err != nil {
+			return err
+		}
+	}
+	if len(msg.Params) > 0 {
+		field("params")
+		buf.Write(msg.Params)
+	}
+	if msg.Error != nil {
+		field("error")
+		enc, err := json.Marshal(msg.Error)
+		if err != nil {
+			return err
+		}
+		buf.Write(enc)
+	}
+	if len(msg.Result) > 0 {
+		field("result")
+		buf.Write(msg.Result)
+	}
+	if sep == '{' {
+		buf.WriteByte('{')
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func writeString(buf *bytes.Buffer, s string) error {
+	enc, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	buf.Write(enc)
+	return nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build sonic
+
+// The sonic codec is only compiled with `-tags sonic` and requires
+// `go get github.com/bytedance/sonic` first.
+
+package rpc
+
+import (
+	"io"
+
+	"github.com/bytedance/sonic"
+)
+
+const JSONCodecSonic = "sonic"
+
+// sonicJSONCodec uses the encoding/json compatible configuration of sonic.
+type sonicJSONCodec struct{}
+
+func (sonicJSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return sonic.ConfigStd.Marshal(v)
+}
+
+func (sonicJSONCodec) Encode(w io.Writer, v interface{}) error {
+	return sonic.ConfigStd.NewEncoder(w).Encode(v)
+}
+
+func init() {
+	RegisterJSONCodec(JSONCodecSonic, sonicJSONCodec{})
+}
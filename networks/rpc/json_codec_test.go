@@ -0,0 +1,68 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamJSONCodecMatchesStd(t *testing.T) {
+	result, _ := json.Marshal(map[string]interface{}{"hash": "0x01", "html": "<&>", "txs": []int{1, 2, 3}})
+	call := &jsonrpcMessage{Version: vsn, ID: json.RawMessage("7"), Method: "kaia_blockNumber", Params: json.RawMessage(`[1,"a"]`)}
+	values := []interface{}{
+		&jsonrpcMessage{},
+		&jsonrpcMessage{Version: vsn, ID: json.RawMessage(`"abc"`), Result: result},
+		call,
+		call.errorResponse(&invalidParamsError{"missing value"}),
+		errorMessage(errors.New("<boom>")),
+		[]*jsonrpcMessage{call, nil, call.response(uint64(10))},
+		[]*jsonrpcMessage{},
+		map[string]string{"not": "a message"},
+	}
+	for _, v := range values {
+		want, err := json.Marshal(v)
+		assert.NoError(t, err)
+
+		got, err := streamJSONCodec{}.Marshal(v)
+		assert.NoError(t, err)
+		assert.Equal(t, string(want), string(got))
+
+		var wantBuf, gotBuf bytes.Buffer
+		assert.NoError(t, stdJSONCodec{}.Encode(&wantBuf, v))
+		assert.NoError(t, streamJSONCodec{}.Encode(&gotBuf, v))
+		assert.Equal(t, wantBuf.String(), gotBuf.String())
+	}
+}
+
+func TestSetJSONCodec(t *testing.T) {
+	defer SetJSONCodec(JSONCodecStream)
+
+	assert.Equal(t, JSONCodecStream, ActiveJSONCodec())
+	assert.Error(t, SetJSONCodec("unknown"))
+	assert.Equal(t, JSONCodecStream, ActiveJSONCodec())
+
+	assert.NoError(t, SetJSONCodec(JSONCodecStd))
+	assert.Equal(t, JSONCodecStd, ActiveJSONCodec())
+	b, err := jsonMarshal([]int{1})
+	assert.NoError(t, err)
+	assert.Equal(t, "[1]", string(b))
+}
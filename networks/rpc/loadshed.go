@@ -0,0 +1,135 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// LoadLevel is a step of the degradation ladder of a node under resource pressure.
+// Each level sheds more work than the previous one.
+type LoadLevel int32
+
+const (
+	LoadNormal   LoadLevel = iota
+	LoadElevated           // heavy methods are rejected
+	LoadHigh               // expensive methods are rejected as well
+	LoadCritical           // same RPC policy as LoadHigh; the node sheds non-RPC work too
+)
+
+func (l LoadLevel) String() string {
+	switch l {
+	case LoadNormal:
+		return "normal"
+	case LoadElevated:
+		return "elevated"
+	case LoadHigh:
+		return "high"
+	case LoadCritical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+func (l LoadLevel) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// healthPath is the path of the health endpoint on the HTTP servers.
+const healthPath = "/health"
+
+var (
+	loadLevel int32 = int32(LoadNormal)
+
+	// expensiveMethodSuffixes are the methods, regardless of the namespace, which
+	// execute the EVM or scan a range of blocks on behalf of the caller.
+	expensiveMethodSuffixes = []string{
+		"_call", "_estimateGas", "_estimateComputationCost", "_createAccessList",
+		"_getLogs", "_getFilterLogs", "_newFilter",
+	}
+
+	healthMu       sync.RWMutex
+	healthReporter HealthReporter
+)
+
+// HealthReporter returns the health report served at /health by the HTTP servers.
+// The report is encoded in JSON. If healthy is false, the status code is 503.
+type HealthReporter func() (report interface{}, healthy bool)
+
+// SetLoadLevel changes the methods rejected by all RPC servers.
+func SetLoadLevel(level LoadLevel) {
+	atomic.StoreInt32(&loadLevel, int32(level))
+}
+
+// GetLoadLevel returns the current load level of the RPC servers.
+func GetLoadLevel() LoadLevel {
+	return LoadLevel(atomic.LoadInt32(&loadLevel))
+}
+
+// SetHealthReporter registers the reporter of the /health endpoint. Passing nil removes it.
+func SetHealthReporter(r HealthReporter) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	healthReporter = r
+}
+
+func isExpensiveMethod(method string) bool {
+	for _, suffix := range expensiveMethodSuffixes {
+		if strings.HasSuffix(method, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// rejectedByLoad returns true if a call to the method must be refused at the current load level.
+func rejectedByLoad(method string) bool {
+	level := GetLoadLevel()
+	if level == LoadNormal || drainExemptNamespaces[strings.SplitN(method, serviceMethodSeparator, 2)[0]] {
+		return false
+	}
+	if isHeavyMethod(method) {
+		return true
+	}
+	return level >= LoadHigh && isExpensiveMethod(method)
+}
+
+// healthResponse returns the status code and the body of the /health endpoint.
+// ok is false if no reporter is registered.
+func healthResponse() (code int, body []byte, ok bool) {
+	healthMu.RLock()
+	r := healthReporter
+	healthMu.RUnlock()
+	if r == nil {
+		return 0, nil, false
+	}
+
+	report, healthy := r()
+	body, err := json.Marshal(report)
+	if err != nil {
+		return http.StatusInternalServerError, []byte(err.Error()), true
+	}
+	if !healthy {
+		return http.StatusServiceUnavailable, body, true
+	}
+	return http.StatusOK, body, true
+}
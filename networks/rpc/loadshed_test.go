@@ -0,0 +1,108 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRejectedByLoad(t *testing.T) {
+	defer SetLoadLevel(LoadNormal)
+
+	testcases := []struct {
+		method   string
+		rejected [4]bool // at LoadNormal, LoadElevated, LoadHigh and LoadCritical
+	}{
+		{"kaia_blockNumber", [4]bool{false, false, false, false}},
+		{"debug_traceTransaction", [4]bool{false, true, true, true}},
+		{"eth_getLogs", [4]bool{false, false, true, true}},
+		{"kaia_call", [4]bool{false, false, true, true}},
+		{"admin_drainStatus", [4]bool{false, false, false, false}},
+	}
+	for _, tc := range testcases {
+		for level := LoadNormal; level <= LoadCritical; level++ {
+			SetLoadLevel(level)
+			assert.Equal(t, tc.rejected[level], rejectedByLoad(tc.method), "%s at %s", tc.method, level)
+		}
+	}
+}
+
+func TestServerLoadShedding(t *testing.T) {
+	server := newTestServer("service", new(Service))
+	defer server.Stop()
+	if err := server.RegisterName("debug", new(Service)); err != nil {
+		t.Fatal(err)
+	}
+	client := DialInProc(server)
+	defer client.Close()
+
+	// Treat debug_echo as a heavy method.
+	heavyMethodPrefixes = append(heavyMethodPrefixes, "debug_echo")
+	defer func() { heavyMethodPrefixes = heavyMethodPrefixes[:len(heavyMethodPrefixes)-1] }()
+
+	SetLoadLevel(LoadElevated)
+	defer SetLoadLevel(LoadNormal)
+
+	var resp Result
+	err := client.Call(&resp, "debug_echo", "hello", 10, &Args{"world"})
+	assert.EqualError(t, err, (&overloadedError{LoadElevated}).Error())
+	assert.Nil(t, client.Call(&resp, "service_echo", "hello", 10, &Args{"world"}))
+
+	SetLoadLevel(LoadNormal)
+	assert.Nil(t, client.Call(&resp, "debug_echo", "hello", 10, &Args{"world"}))
+}
+
+func TestHealthEndpoint(t *testing.T) {
+	server := newTestServer("service", new(Service))
+	defer server.Stop()
+
+	get := func(path string) (int, string) {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		body, _ := io.ReadAll(rec.Body)
+		return rec.Code, string(body)
+	}
+
+	// Without a reporter, /health is the plain health check.
+	code, body := get("/health")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "", body)
+
+	healthy := true
+	SetHealthReporter(func() (interface{}, bool) {
+		return map[string]LoadLevel{"level": GetLoadLevel()}, healthy
+	})
+	defer SetHealthReporter(nil)
+
+	code, body = get("/health")
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"level":"normal"}`, body)
+
+	healthy = false
+	code, _ = get("/health")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	// Other paths keep the plain health check.
+	code, body = get("/")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "", body)
+}
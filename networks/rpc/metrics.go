@@ -26,4 +26,20 @@ var (
 	wsSubscriptionReqCounter   = metrics.NewRegisteredCounter("ws/counts/subscription/request", nil)
 	wsUnsubscriptionReqCounter = metrics.NewRegisteredCounter("ws/counts/unsubscription/request", nil)
 	wsConnCounter              = metrics.NewRegisteredCounter("ws/counts/connections/total", nil)
+
+	heavyCallWaitTimer     = metrics.NewRegisteredTimer("rpc/heavy/wait", nil)
+	heavyCallExecTimer     = metrics.NewRegisteredTimer("rpc/heavy/exec", nil)
+	heavyCallQueuedCounter = metrics.NewRegisteredCounter("rpc/heavy/queued", nil)
+	heavyCallLimitCounter  = metrics.NewRegisteredCounter("rpc/heavy/limited", nil)
+
+	upstreamRequestCounter  = metrics.NewRegisteredCounter("rpc/upstream/requests", nil)
+	upstreamCacheHitCounter = metrics.NewRegisteredCounter("rpc/upstream/cache/hits", nil)
+	upstreamFailureCounter  = metrics.NewRegisteredCounter("rpc/upstream/failures", nil)
+
+	rpcSerializeMarshalTimer = metrics.NewRegisteredTimer("rpc/serialize/marshal", nil)
+	rpcSerializeEncodeTimer  = metrics.NewRegisteredTimer("rpc/serialize/encode", nil)
+
+	slowQueryCounter = metrics.NewRegisteredCounter("rpc/slow", nil)
+
+	blockedRequestsCounter = metrics.NewRegisteredCounter("rpc/blocked", nil)
 )
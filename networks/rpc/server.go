@@ -25,6 +25,7 @@ package rpc
 import (
 	"context"
 	"io"
+	"sort"
 	"sync/atomic"
 
 	mapset "github.com/deckarep/golang-set"
@@ -73,7 +74,7 @@ var (
 	// It can be overwritten by rpc.eth.noncompatible flag
 	NonEthCompatible = false
 
-	// UpstreamArchiveEN is the upstream archive mode EN endpoint
+	// UpstreamArchiveEN is a comma-separated list of upstream archive mode EN endpoints
 	UpstreamArchiveEN string
 )
 
@@ -114,6 +115,27 @@ func (s *RPCService) Modules() map[string]string {
 	return modules
 }
 
+// Methods returns the sorted list of RPC methods and subscriptions registered
+// under each namespace. Subscriptions are reported with a "subscribe:" prefix.
+func (s *Server) Methods() map[string][]string {
+	s.services.mu.Lock()
+	defer s.services.mu.Unlock()
+
+	methods := make(map[string][]string)
+	for name, svc := range s.services.services {
+		list := make([]string, 0, len(svc.callbacks)+len(svc.subscriptions))
+		for method := range svc.callbacks {
+			list = append(list, method)
+		}
+		for sub := range svc.subscriptions {
+			list = append(list, "subscribe:"+sub)
+		}
+		sort.Strings(list)
+		methods[name] = list
+	}
+	return methods
+}
+
 func (s *Server) GetServices() map[string]service {
 	return s.services.services
 }
@@ -21,6 +21,7 @@ import (
 	"context"
 	"encoding/json"
 	"net"
+	"sort"
 	"testing"
 	"time"
 )
@@ -101,6 +102,34 @@ func TestServerRegisterName(t *testing.T) {
 	}
 }
 
+func TestServerMethods(t *testing.T) {
+	server := NewServer()
+	service := new(Service)
+
+	if err := server.RegisterName("calc", service); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	methods := server.Methods()
+	if _, ok := methods[MetadataApi]; !ok {
+		t.Fatalf("Expected metadata service %q to be listed", MetadataApi)
+	}
+
+	calc, ok := methods["calc"]
+	if !ok {
+		t.Fatalf("Expected service calc to be listed")
+	}
+	if len(calc) != 6 {
+		t.Fatalf("Expected 6 methods for service 'calc', got %d: %v", len(calc), calc)
+	}
+	if !sort.StringsAreSorted(calc) {
+		t.Errorf("Expected sorted methods, got %v", calc)
+	}
+	if calc[len(calc)-1] != "subscribe:subscription" {
+		t.Errorf("Expected subscription to be listed last, got %v", calc)
+	}
+}
+
 func testServerMethodExecution(t *testing.T, method string) {
 	server := NewServer()
 	service := new(Service)
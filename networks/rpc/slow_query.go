@@ -0,0 +1,144 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package rpc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+var (
+	// SlowQueryThreshold is the minimum duration of a call recorded in the slow query log.
+	// 0 disables the slow query log.
+	// It can be overwritten by rpc.slow-query.threshold flag
+	SlowQueryThreshold time.Duration = 0
+
+	// SlowQueryLogSize is the number of the latest slow queries kept in memory.
+	// It can be overwritten by rpc.slow-query.size flag
+	SlowQueryLogSize = 1000
+
+	slowQueryOnce sync.Once
+	slowQueryRing *slowQueryLog
+)
+
+// SlowQuery is an entry of the slow query log.
+type SlowQuery struct {
+	Time         time.Time     `json:"time"`
+	Method       string        `json:"method"`
+	ParamsDigest string        `json:"paramsDigest"` // hex of the first 8 bytes of sha256(params)
+	Caller       string        `json:"caller"`
+	Duration     time.Duration `json:"duration"`
+	Error        string        `json:"error,omitempty"`
+}
+
+// slowQueryLog is a fixed-size ring buffer of slow queries.
+type slowQueryLog struct {
+	mu      sync.Mutex
+	entries []SlowQuery
+	next    int
+	full    bool
+}
+
+func newSlowQueryLog(size int) *slowQueryLog {
+	if size < 1 {
+		size = 1
+	}
+	return &slowQueryLog{entries: make([]SlowQuery, size)}
+}
+
+func (l *slowQueryLog) add(q SlowQuery) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries[l.next] = q
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// list returns the entries from the oldest to the newest.
+func (l *slowQueryLog) list() []SlowQuery {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.full {
+		return append([]SlowQuery{}, l.entries[:l.next]...)
+	}
+	ret := make([]SlowQuery, 0, len(l.entries))
+	ret = append(ret, l.entries[l.next:]...)
+	return append(ret, l.entries[:l.next]...)
+}
+
+// slowQueries returns the log shared by all RPC servers.
+func slowQueries() *slowQueryLog {
+	slowQueryOnce.Do(func() {
+		slowQueryRing = newSlowQueryLog(SlowQueryLogSize)
+	})
+	return slowQueryRing
+}
+
+// SlowQueries returns the slow queries recorded so far, from the oldest to the newest.
+func SlowQueries() []SlowQuery {
+	return slowQueries().list()
+}
+
+// paramsDigest shortens the parameters of a call so that it can be logged without exposing them.
+func paramsDigest(params []byte) string {
+	sum := sha256.Sum256(params)
+	return hex.EncodeToString(sum[:8])
+}
+
+// methodTimer returns the latency timer of the method.
+func methodTimer(method string) metrics.Timer {
+	return metrics.GetOrRegisterTimer("rpc/methods/"+method+"/latency", nil)
+}
+
+// methodSizeHistogram returns the response size histogram of the method.
+func methodSizeHistogram(method string) metrics.Histogram {
+	return metrics.GetOrRegisterHistogram("rpc/methods/"+method+"/size", nil, metrics.NewExpDecaySample(1028, 0.015))
+}
+
+// observeCall records the latency and the response size of a served call, and logs it if it is slow.
+func (h *handler) observeCall(msg *jsonrpcMessage, resp *jsonrpcMessage, duration time.Duration) {
+	// Unknown methods are not recorded to keep the number of metrics bounded.
+	if !msg.isSubscribe() && !msg.isUnsubscribe() && h.reg.callback(msg.Method) == nil {
+		return
+	}
+	methodTimer(msg.Method).Update(duration)
+	methodSizeHistogram(msg.Method).Update(int64(len(resp.Result)))
+
+	if SlowQueryThreshold <= 0 || duration < SlowQueryThreshold {
+		return
+	}
+	q := SlowQuery{
+		Time:         time.Now(),
+		Method:       msg.Method,
+		ParamsDigest: paramsDigest(msg.Params),
+		Caller:       h.conn.remoteAddr(),
+		Duration:     duration,
+	}
+	if resp.Error != nil {
+		q.Error = resp.Error.Message
+	}
+	slowQueryCounter.Inc(1)
+	slowQueries().add(q)
+	logger.Warn("Slow RPC call", "method", q.Method, "params", q.ParamsDigest, "caller", q.Caller, "duration", q.Duration, "err", q.Error)
+}
@@ -0,0 +1,66 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package rpc
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlowQueryLog(t *testing.T) {
+	l := newSlowQueryLog(3)
+	assert.Empty(t, l.list())
+
+	for i := 0; i < 5; i++ {
+		l.add(SlowQuery{Method: fmt.Sprint(i)})
+	}
+	var methods []string
+	for _, q := range l.list() {
+		methods = append(methods, q.Method)
+	}
+	assert.Equal(t, []string{"2", "3", "4"}, methods)
+}
+
+func TestSlowQuery(t *testing.T) {
+	server := newTestServer("service", new(Service))
+	defer server.Stop()
+	client := DialInProc(server)
+	defer client.Close()
+
+	defer func(threshold time.Duration, ring *slowQueryLog) {
+		SlowQueryThreshold, slowQueryRing = threshold, ring
+	}(SlowQueryThreshold, slowQueries())
+	SlowQueryThreshold = 50 * time.Millisecond
+	slowQueryRing = newSlowQueryLog(10)
+
+	timer := methodTimer("service_sleep")
+	count := timer.Count()
+
+	assert.Nil(t, client.Call(nil, "service_sleep", time.Millisecond))
+	assert.Nil(t, client.Call(nil, "service_sleep", 100*time.Millisecond))
+	assert.NotNil(t, client.Call(nil, "service_unknown"))
+
+	assert.Equal(t, count+2, timer.Count())
+	queries := SlowQueries()
+	if assert.Len(t, queries, 1) {
+		assert.Equal(t, "service_sleep", queries[0].Method)
+		assert.Equal(t, paramsDigest([]byte(`[100000000]`)), queries[0].ParamsDigest)
+		assert.GreaterOrEqual(t, queries[0].Duration, 100*time.Millisecond)
+	}
+}
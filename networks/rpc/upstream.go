@@ -0,0 +1,285 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// UpstreamCacheSize is the number of forwarded results kept in memory. 0 disables the cache.
+var UpstreamCacheSize = 1024
+
+// genesisProbeMethod is answered identically by Kaia and Klaytn nodes and identifies the chain.
+const genesisProbeMethod = "klay_getHeaderByNumber"
+
+var errUpstreamMismatch = errors.New("upstream serves a different chain")
+
+// archiveUpstream is one archive EN. Its client is dialed on demand and kept
+// until a transport error occurs.
+type archiveUpstream struct {
+	url string
+
+	mu       sync.Mutex
+	client   *Client
+	verified bool
+}
+
+// archiveProxy forwards queries that failed on the pruned local state to the
+// upstream archive ENs listed in UpstreamArchiveEN.
+type archiveProxy struct {
+	upstreams []*archiveUpstream
+	next      uint32 // round-robin start index
+	cache     *lru.Cache
+
+	genesisMu sync.Mutex
+	genesis   string // local genesis hash, resolved on first use
+}
+
+var (
+	archiveProxyMu       sync.Mutex
+	archiveProxyInstance *archiveProxy
+)
+
+// getArchiveProxy returns the proxy for the current UpstreamArchiveEN, or nil if none is configured.
+func getArchiveProxy() *archiveProxy {
+	archiveProxyMu.Lock()
+	defer archiveProxyMu.Unlock()
+	if archiveProxyInstance == nil && UpstreamArchiveEN != "" {
+		archiveProxyInstance = newArchiveProxy(strings.Split(UpstreamArchiveEN, ","), UpstreamCacheSize)
+	}
+	return archiveProxyInstance
+}
+
+func newArchiveProxy(urls []string, cacheSize int) *archiveProxy {
+	p := &archiveProxy{}
+	for _, url := range urls {
+		if url = strings.TrimSpace(url); url != "" {
+			p.upstreams = append(p.upstreams, &archiveUpstream{url: url})
+		}
+	}
+	if cacheSize > 0 {
+		p.cache, _ = lru.New(cacheSize)
+	}
+	return p
+}
+
+// forward sends msg to the upstreams in turn until one of them answers it.
+// An upstream that fails to connect, serves another chain, is pruned as well
+// or returns an implausible result is skipped.
+func (p *archiveProxy) forward(ctx context.Context, reg *serviceRegistry, msg *jsonrpcMessage) *jsonrpcMessage {
+	upstreamRequestCounter.Inc(1)
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultHTTPTimeouts.ExecutionTimeout)
+	defer cancel()
+
+	key := msg.Method + string(msg.Params)
+	cacheable := p.cache != nil && !hasRelativeBlockTag(msg.Params)
+	if cacheable {
+		if result, ok := p.cache.Get(key); ok {
+			upstreamCacheHitCounter.Inc(1)
+			return &jsonrpcMessage{Version: vsn, ID: msg.ID, Result: result.(json.RawMessage)}
+		}
+	}
+
+	var rawParams []json.RawMessage
+	if len(msg.Params) > 0 {
+		if err := json.Unmarshal(msg.Params, &rawParams); err != nil {
+			return msg.errorResponse(&invalidParamsError{err.Error()})
+		}
+	}
+	params := make([]interface{}, len(rawParams))
+	for i, param := range rawParams {
+		params[i] = param
+	}
+
+	err := errors.New("no upstream archive EN")
+	start := atomic.AddUint32(&p.next, 1)
+	for i := range p.upstreams {
+		u := p.upstreams[(int(start)+i)%len(p.upstreams)]
+
+		var result json.RawMessage
+		err = p.call(ctx, reg, u, &result, msg.Method, params...)
+		if err == nil {
+			err = checkUpstreamResult(rawParams, result)
+		}
+		if err == nil {
+			if cacheable && string(result) != "null" {
+				p.cache.Add(key, result)
+			}
+			return &jsonrpcMessage{Version: vsn, ID: msg.ID, Result: result}
+		}
+		var rpcErr Error
+		if errors.As(err, &rpcErr) && !strings.Contains(rpcErr.Error(), "missing trie node") {
+			// A regular error answer of an archive node is final.
+			return msg.errorResponse(err)
+		}
+		upstreamFailureCounter.Inc(1)
+		logger.Debug("Upstream archive EN failed", "url", u.url, "method", msg.Method, "err", err)
+	}
+	return msg.errorResponse(err)
+}
+
+// call runs method on u, making sure it has been checked against the local genesis first.
+func (p *archiveProxy) call(ctx context.Context, reg *serviceRegistry, u *archiveUpstream, result interface{}, method string, args ...interface{}) error {
+	u.mu.Lock()
+	if u.client == nil {
+		c, err := DialContext(ctx, u.url)
+		if err != nil {
+			u.mu.Unlock()
+			return err
+		}
+		u.client = c
+	}
+	client, verified := u.client, u.verified
+	u.mu.Unlock()
+
+	if !verified {
+		if err := p.verify(ctx, reg, client); err != nil {
+			if errors.Is(err, errUpstreamMismatch) {
+				logger.Error("Upstream archive EN is not on the local chain", "url", u.url, "err", err)
+			}
+			u.drop(client)
+			return err
+		}
+		u.mu.Lock()
+		u.verified = u.client == client
+		u.mu.Unlock()
+	}
+
+	err := client.CallContext(ctx, result, method, args...)
+	var rpcErr Error
+	if err != nil && !errors.As(err, &rpcErr) {
+		u.drop(client)
+	}
+	return err
+}
+
+// drop closes client and forces the next call to dial and verify again.
+func (u *archiveUpstream) drop(client *Client) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.client == client {
+		u.client, u.verified = nil, false
+	}
+	client.Close()
+}
+
+// verify compares the genesis hash of the upstream with the local one.
+func (p *archiveProxy) verify(ctx context.Context, reg *serviceRegistry, client *Client) error {
+	local, err := p.localGenesis(ctx, reg)
+	if err != nil {
+		return err
+	}
+	if local == "" {
+		return nil // the local node does not serve headers; nothing to compare against
+	}
+	var header struct {
+		Hash string `json:"hash"`
+	}
+	if err := client.CallContext(ctx, &header, genesisProbeMethod, "0x0"); err != nil {
+		return err
+	}
+	if !strings.EqualFold(header.Hash, local) {
+		return fmt.Errorf("%w: genesis %s, want %s", errUpstreamMismatch, header.Hash, local)
+	}
+	return nil
+}
+
+func (p *archiveProxy) localGenesis(ctx context.Context, reg *serviceRegistry) (string, error) {
+	p.genesisMu.Lock()
+	defer p.genesisMu.Unlock()
+	if p.genesis != "" {
+		return p.genesis, nil
+	}
+	cb := reg.callback(genesisProbeMethod)
+	if cb == nil {
+		return "", nil
+	}
+	args, err := parsePositionalArguments(json.RawMessage(`["0x0"]`), cb.argTypes)
+	if err != nil {
+		return "", err
+	}
+	res, err := cb.call(ctx, genesisProbeMethod, args)
+	if err != nil {
+		return "", err
+	}
+	enc, err := json.Marshal(res)
+	if err != nil {
+		return "", err
+	}
+	var header struct {
+		Hash string `json:"hash"`
+	}
+	if err := json.Unmarshal(enc, &header); err != nil {
+		return "", err
+	}
+	p.genesis = header.Hash
+	return p.genesis, nil
+}
+
+// checkUpstreamResult rejects results that do not belong to the request: an
+// object carrying a hash or number must echo the block or transaction hash,
+// or the block number, given as the first parameter.
+func checkUpstreamResult(params []json.RawMessage, result json.RawMessage) error {
+	if len(params) == 0 || len(result) == 0 || result[0] != '{' {
+		return nil
+	}
+	var first string
+	if json.Unmarshal(params[0], &first) != nil || !strings.HasPrefix(first, "0x") {
+		return nil
+	}
+	var obj struct {
+		Hash   *string `json:"hash"`
+		Number *string `json:"number"`
+	}
+	if err := json.Unmarshal(result, &obj); err != nil {
+		return fmt.Errorf("invalid upstream result: %w", err)
+	}
+	switch {
+	case len(first) == 66 && obj.Hash != nil && !strings.EqualFold(*obj.Hash, first):
+		return fmt.Errorf("upstream result hash %s does not match the requested %s", *obj.Hash, first)
+	case len(first) <= 18 && obj.Number != nil && obj.Hash != nil && !sameQuantity(*obj.Number, first):
+		return fmt.Errorf("upstream result number %s does not match the requested %s", *obj.Number, first)
+	}
+	return nil
+}
+
+func sameQuantity(a, b string) bool {
+	trim := func(s string) string {
+		s = strings.TrimLeft(strings.ToLower(strings.TrimPrefix(s, "0x")), "0")
+		return s
+	}
+	return trim(a) == trim(b)
+}
+
+// hasRelativeBlockTag reports whether the params refer to a block that moves with the chain head.
+func hasRelativeBlockTag(params json.RawMessage) bool {
+	for _, tag := range []string{`"latest"`, `"pending"`, `"safe"`, `"finalized"`} {
+		if strings.Contains(string(params), tag) {
+			return true
+		}
+	}
+	return false
+}
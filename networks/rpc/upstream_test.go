@@ -0,0 +1,126 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/kaiachain/kaia/storage/statedb"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testGenesis = "0x1111111111111111111111111111111111111111111111111111111111111111"
+	otherChain  = "0x2222222222222222222222222222222222222222222222222222222222222222"
+)
+
+type archiveTestService struct {
+	genesis string
+	pruned  bool
+	calls   int32
+}
+
+func (s *archiveTestService) GetHeaderByNumber(n BlockNumber) map[string]interface{} {
+	return map[string]interface{}{"hash": s.genesis, "number": fmt.Sprintf("0x%x", n.Int64())}
+}
+
+func (s *archiveTestService) GetBalance(addr string, n BlockNumber) (string, error) {
+	atomic.AddInt32(&s.calls, 1)
+	if s.pruned {
+		return "", fmt.Errorf("state unavailable: %w", &statedb.MissingNodeError{})
+	}
+	return fmt.Sprintf("%s@%d", s.genesis[:4], n.Int64()), nil
+}
+
+func newArchiveTestServer(t *testing.T, service *archiveTestService) string {
+	srv := NewServer()
+	assert.NoError(t, srv.RegisterName("kaia", service))
+	httpsrv := httptest.NewServer(srv)
+	t.Cleanup(func() {
+		httpsrv.Close()
+		srv.Stop()
+	})
+	return httpsrv.URL
+}
+
+func TestArchiveProxy(t *testing.T) {
+	other := &archiveTestService{genesis: otherChain}
+	archive := &archiveTestService{genesis: testGenesis}
+	urls := []string{newArchiveTestServer(t, other), newArchiveTestServer(t, archive)}
+
+	defer func(orig string) {
+		UpstreamArchiveEN = orig
+		archiveProxyInstance = nil
+	}(UpstreamArchiveEN)
+	UpstreamArchiveEN = urls[0] + "," + urls[1]
+	archiveProxyInstance = nil
+
+	local := NewServer()
+	defer local.Stop()
+	assert.NoError(t, local.RegisterName("kaia", &archiveTestService{genesis: testGenesis, pruned: true}))
+	client := DialInProc(local)
+	defer client.Close()
+
+	// The upstream of another chain is skipped and the result comes from the archive EN.
+	for i := 0; i < 3; i++ {
+		var balance string
+		assert.NoError(t, client.Call(&balance, "klay_getBalance", "0xabc", "0x10"))
+		assert.Equal(t, "0x11@16", balance)
+	}
+	assert.Equal(t, int32(0), atomic.LoadInt32(&other.calls))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&archive.calls), "pinned queries are cached")
+
+	// Queries relative to the head are not cached.
+	for i := 0; i < 2; i++ {
+		var balance string
+		assert.NoError(t, client.Call(&balance, "klay_getBalance", "0xabc", "latest"))
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&archive.calls))
+}
+
+func TestCheckUpstreamResult(t *testing.T) {
+	hash := `"` + testGenesis + `"`
+	params := func(ps ...string) []json.RawMessage {
+		var raw []json.RawMessage
+		for _, p := range ps {
+			raw = append(raw, json.RawMessage(p))
+		}
+		return raw
+	}
+	testcases := []struct {
+		params []json.RawMessage
+		result string
+		ok     bool
+	}{
+		{params(hash), `{"hash":` + hash + `}`, true},
+		{params(hash), `{"hash":"` + otherChain + `"}`, false},
+		{params(`"0x10"`, "true"), `{"hash":` + hash + `,"number":"0x10"}`, true},
+		{params(`"0x10"`, "true"), `{"hash":` + hash + `,"number":"0x11"}`, false},
+		{params(`"0x10"`), `{"number":"0x11"}`, true},
+		{params(`"0xabc"`, `"0x10"`), `"0x5"`, true},
+		{params(`"0x10"`), `null`, true},
+		{nil, `{"hash":"0x"}`, true},
+	}
+	for i, tc := range testcases {
+		err := checkUpstreamResult(tc.params, json.RawMessage(tc.result))
+		assert.Equal(t, tc.ok, err == nil, "testcase %d: %v", i, err)
+	}
+}
@@ -59,7 +59,19 @@ func newWebsocketCodec(conn *websocket.Conn) ServerCodec {
 	if WebsocketWriteDeadline != 0 {
 		conn.SetWriteDeadline(time.Now().Add(time.Duration(WebsocketWriteDeadline) * time.Second))
 	}
-	return NewFuncCodec(conn, conn.WriteJSON, conn.ReadJSON)
+	encode := func(v interface{}) error {
+		w, err := conn.NextWriter(websocket.TextMessage)
+		if err != nil {
+			return err
+		}
+		err1 := jsonEncode(w, v)
+		err2 := w.Close()
+		if err1 != nil {
+			return err1
+		}
+		return err2
+	}
+	return NewFuncCodec(conn, encode, conn.ReadJSON)
 }
 
 // WebsocketHandler returns a handler that serves JSON-RPC to WebSocket connections.
@@ -74,6 +86,10 @@ func (srv *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 		CheckOrigin:     wsHandshakeValidator(allowedOrigins),
 	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if blockedClient(r.RemoteAddr) {
+			http.Error(w, errClientBlocked.Error(), http.StatusForbidden)
+			return
+		}
 		if atomic.LoadInt32(&srv.wsConnCount) >= MaxWebsocketConnections {
 			return
 		}
@@ -98,6 +114,10 @@ var upgrader = fastws.Upgrader{
 }
 
 func (srv *Server) FastWebsocketHandler(ctx *fasthttp.RequestCtx) {
+	if blockedClient(ctx.RemoteAddr().String()) {
+		ctx.Error(errClientBlocked.Error(), http.StatusForbidden)
+		return
+	}
 	// TODO-Kaia handle websocket protocol
 	protocol := ctx.Request.Header.Peek("Sec-WebSocket-Protocol")
 	if protocol != nil {
@@ -122,7 +142,7 @@ func (srv *Server) FastWebsocketHandler(ctx *fasthttp.RequestCtx) {
 		}
 		// Create a custom encode/decode pair to enforce payload size and number encoding
 		encoder := func(v interface{}) error {
-			msg, err := json.Marshal(v)
+			msg, err := jsonMarshal(v)
 			if err != nil {
 				return err
 			}
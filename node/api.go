@@ -345,6 +345,76 @@ func (api *PublicAdminAPI) Datadir() string {
 	return api.node.DataDir()
 }
 
+// ProtocolCapability describes a p2p protocol enabled on the node.
+type ProtocolCapability struct {
+	Name    string `json:"name"`
+	Version uint   `json:"version"`
+}
+
+// CapabilitiesOutput is a machine-readable summary of what the node supports,
+// so that clients can feature-detect instead of parsing the client version.
+type CapabilitiesOutput struct {
+	ClientVersion string               `json:"clientVersion"`
+	Protocols     []ProtocolCapability `json:"protocols"`
+	Endpoints     map[string][]string  `json:"endpoints"` // transport or listener URL => exposed namespaces
+	Methods       map[string][]string  `json:"methods"`   // namespace => methods
+	Features      map[string]bool      `json:"features"`
+}
+
+// Capabilities reports the enabled p2p protocols, the RPC namespaces exposed on
+// each transport and additional RPC listener, every registered RPC method, and
+// the node's feature flags.
+func (api *PublicAdminAPI) Capabilities() (*CapabilitiesOutput, error) {
+	server := api.node.Server()
+	if server == nil {
+		return nil, ErrNodeStopped
+	}
+	handler, err := api.node.RPCHandler()
+	if err != nil {
+		return nil, err
+	}
+
+	protocols := []ProtocolCapability{}
+	for _, p := range server.GetProtocols() {
+		protocols = append(protocols, ProtocolCapability{Name: p.Name, Version: p.Version})
+	}
+
+	config := api.node.config
+	endpoints := make(map[string][]string)
+	if api.node.IPCEndpoint() != "" {
+		endpoints["ipc"] = []string{"*"}
+	}
+	if api.node.HTTPEndpoint() != "" {
+		endpoints["http"] = config.HTTPModules
+	}
+	if api.node.WSEndpoint() != "" {
+		if config.WSExposeAll {
+			endpoints["ws"] = []string{"*"}
+		} else {
+			endpoints["ws"] = config.WSModules
+		}
+	}
+	if config.GRPCEndpoint() != "" {
+		endpoints["grpc"] = []string{"*"}
+	}
+	// The additional RPC listeners are keyed by their URLs, since several may share a transport.
+	for _, l := range api.node.rpcListeners {
+		endpoints[l.url] = l.modules
+	}
+
+	return &CapabilitiesOutput{
+		ClientVersion: server.Name(),
+		Protocols:     protocols,
+		Endpoints:     endpoints,
+		Methods:       handler.Methods(),
+		Features: map[string]bool{
+			"ethCompatible":     !rpc.NonEthCompatible,
+			"upstreamArchiveEN": rpc.UpstreamArchiveEN != "",
+			"unsafeDebug":       !config.DisableUnsafeDebug,
+		},
+	}, nil
+}
+
 // PublicDebugAPI is the collection of debugging related API methods exposed over
 // both secure and unsecure RPC channels.
 type PublicDebugAPI struct {
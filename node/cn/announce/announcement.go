@@ -0,0 +1,104 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package announce
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"time"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/crypto/sha3"
+	"github.com/kaiachain/kaia/rlp"
+)
+
+const (
+	maxKindLength    = 32
+	maxMessageLength = 1024
+
+	// maxLifetime is how far in the future an announcement may expire.
+	maxLifetime = 30 * 24 * time.Hour
+	// maxClockDrift is how far in the future an announcement may be made, tolerating unsynced clocks.
+	maxClockDrift = time.Minute
+)
+
+// Announcement is an operational notice of a council member, such as a planned maintenance
+// or an upgrade readiness. It is signed with the node key of the council member.
+type Announcement struct {
+	Kind      string        `json:"kind"` // e.g. "maintenance", "upgrade"
+	Message   string        `json:"message"`
+	Start     uint64        `json:"start"` // Unix time when the announced event begins
+	End       uint64        `json:"end"`   // Unix time when the announced event ends, and the announcement expires
+	Time      uint64        `json:"time"`  // Unix time when the announcement was made
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// Hash returns the hash identifying the announcement.
+func (a *Announcement) Hash() common.Hash {
+	return a.sigHash()
+}
+
+func (a *Announcement) sigHash() (h common.Hash) {
+	hw := sha3.NewKeccak256()
+	rlp.Encode(hw, []interface{}{a.Kind, a.Message, a.Start, a.End, a.Time})
+	hw.Sum(h[:0])
+	return h
+}
+
+func (a *Announcement) sign(key *ecdsa.PrivateKey) error {
+	sig, err := crypto.Sign(a.sigHash().Bytes(), key)
+	if err != nil {
+		return err
+	}
+	a.Signature = sig
+	return nil
+}
+
+// Signer returns the address of the node which signed the announcement.
+func (a *Announcement) Signer() (common.Address, error) {
+	pub, err := crypto.SigToPub(a.sigHash().Bytes(), a.Signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// expired returns true if the announced event has ended at the given time.
+func (a *Announcement) expired(now time.Time) bool {
+	return a.End <= uint64(now.Unix())
+}
+
+// validate checks the fields of the announcement regardless of the signer.
+func (a *Announcement) validate(now time.Time) error {
+	switch {
+	case a.Kind == "" || len(a.Kind) > maxKindLength:
+		return fmt.Errorf("%w: kind must be 1 to %d bytes", ErrInvalidAnnouncement, maxKindLength)
+	case len(a.Message) > maxMessageLength:
+		return fmt.Errorf("%w: message must be at most %d bytes", ErrInvalidAnnouncement, maxMessageLength)
+	case a.Start > a.End:
+		return fmt.Errorf("%w: start %d is after end %d", ErrInvalidAnnouncement, a.Start, a.End)
+	case a.Time > uint64(now.Add(maxClockDrift).Unix()):
+		return fmt.Errorf("%w: time %d is in the future", ErrInvalidAnnouncement, a.Time)
+	case a.End > uint64(now.Add(maxLifetime).Unix()):
+		return fmt.Errorf("%w: end %d is too far in the future", ErrInvalidAnnouncement, a.End)
+	case a.expired(now):
+		return ErrExpired
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package announce
+
+import (
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/networks/rpc"
+)
+
+// APIs returns the `announce` APIs. Making an announcement is not public since it is
+// signed with the node key.
+func (r *Relay) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "announce",
+			Version:   "1.0",
+			Service:   NewPublicAnnounceAPI(r),
+			Public:    true,
+		},
+		{
+			Namespace: "announce",
+			Version:   "1.0",
+			Service:   NewPrivateAnnounceAPI(r),
+			Public:    false,
+		},
+	}
+}
+
+// RPCAnnouncement is an announcement with its hash and signer.
+type RPCAnnouncement struct {
+	Hash   common.Hash    `json:"hash"`
+	Signer common.Address `json:"signer"`
+	*Announcement
+}
+
+// PublicAnnounceAPI provides the APIs to query the announcements of the council members.
+type PublicAnnounceAPI struct {
+	r *Relay
+}
+
+func NewPublicAnnounceAPI(r *Relay) *PublicAnnounceAPI {
+	return &PublicAnnounceAPI{r}
+}
+
+// GetAnnouncements returns the unexpired announcements, optionally filtered by the signer.
+func (api *PublicAnnounceAPI) GetAnnouncements(signer *common.Address) []*RPCAnnouncement {
+	announcements, signers := api.r.Announcements()
+	ret := make([]*RPCAnnouncement, 0, len(announcements))
+	for i, a := range announcements {
+		if signer != nil && signers[i] != *signer {
+			continue
+		}
+		ret = append(ret, &RPCAnnouncement{a.Hash(), signers[i], a})
+	}
+	return ret
+}
+
+// PrivateAnnounceAPI provides the APIs to make announcements signed with the node key.
+type PrivateAnnounceAPI struct {
+	r *Relay
+}
+
+func NewPrivateAnnounceAPI(r *Relay) *PrivateAnnounceAPI {
+	return &PrivateAnnounceAPI{r}
+}
+
+// Announce gossips an announcement of the given kind (e.g. "maintenance", "upgrade") for
+// the event between start and end, in Unix time. The announcement expires at end.
+func (api *PrivateAnnounceAPI) Announce(kind, message string, start, end uint64) (*RPCAnnouncement, error) {
+	a, err := api.r.Announce(kind, message, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return &RPCAnnouncement{a.Hash(), crypto.PubkeyToAddress(api.r.key.PublicKey), a}, nil
+}
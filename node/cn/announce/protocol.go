@@ -0,0 +1,57 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package announce
+
+import (
+	"errors"
+)
+
+// Constants to match up protocol versions and messages
+const (
+	ANNOUNCE1 = 1
+)
+
+// ProtocolName is the official short name of the `announce` protocol used during
+// p2p capability negotiation.
+const ProtocolName = "announce"
+
+// ProtocolVersions are the supported versions of the `announce` protocol (first
+// is primary).
+var ProtocolVersions = []uint{ANNOUNCE1}
+
+// ProtocolLengths are the number of implemented message corresponding to
+// different protocol versions.
+var ProtocolLengths = map[uint]uint64{ANNOUNCE1: 1}
+
+// maxMessageSize is the maximum cap on the size of a protocol message.
+const maxMessageSize = 1024 * 1024
+
+const (
+	AnnouncementsMsg = 0x00
+)
+
+var (
+	errMsgTooLarge    = errors.New("message too long")
+	errDecode         = errors.New("invalid message")
+	errInvalidMsgCode = errors.New("invalid message code")
+
+	ErrNotCouncil           = errors.New("not signed by a council member")
+	ErrAlreadyKnown         = errors.New("already known")
+	ErrExpired              = errors.New("announcement expired")
+	ErrInvalidAnnouncement  = errors.New("invalid announcement")
+	ErrTooManyAnnouncements = errors.New("too many announcements from the signer")
+)
@@ -0,0 +1,301 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package announce
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/log"
+	"github.com/kaiachain/kaia/networks/p2p"
+	"github.com/kaiachain/kaia/networks/p2p/discover"
+	"github.com/rcrowley/go-metrics"
+)
+
+var (
+	logger = log.NewModuleLogger(log.NodeCN)
+
+	receivedCounter = metrics.NewRegisteredCounter("announce/received", nil)
+	acceptedCounter = metrics.NewRegisteredCounter("announce/accepted", nil)
+)
+
+const (
+	// maxPerSigner is the maximum number of the unexpired announcements kept for each council member.
+	maxPerSigner = 16
+	// pruneInterval is the interval of dropping the expired announcements.
+	pruneInterval = time.Minute
+)
+
+type entry struct {
+	*Announcement
+	signer common.Address
+}
+
+// Relay gossips the announcements signed by the council members over the `announce` protocol.
+// An announcement is kept until it expires, and is forwarded to the other peers only when it
+// is seen for the first time. The announcements not signed by a council member are dropped.
+type Relay struct {
+	key       *ecdsa.PrivateKey
+	isCouncil func(common.Address) bool
+
+	mu            sync.Mutex
+	peers         map[discover.NodeID]p2p.MsgReadWriter
+	announcements map[common.Hash]*entry
+
+	quitCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func NewRelay(key *ecdsa.PrivateKey, isCouncil func(common.Address) bool) *Relay {
+	return &Relay{
+		key:           key,
+		isCouncil:     isCouncil,
+		peers:         make(map[discover.NodeID]p2p.MsgReadWriter),
+		announcements: make(map[common.Hash]*entry),
+		quitCh:        make(chan struct{}),
+	}
+}
+
+func (r *Relay) Start() {
+	r.wg.Add(1)
+	go r.loop()
+}
+
+func (r *Relay) Stop() {
+	close(r.quitCh)
+	r.wg.Wait()
+}
+
+// loop drops the expired announcements periodically.
+func (r *Relay) loop() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.prune(time.Now())
+		case <-r.quitCh:
+			return
+		}
+	}
+}
+
+func (r *Relay) prune(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for hash, e := range r.announcements {
+		if e.expired(now) {
+			delete(r.announcements, hash)
+		}
+	}
+}
+
+// Protocols returns the `announce` protocols to be run with the peers.
+func (r *Relay) Protocols() []p2p.Protocol {
+	protocols := make([]p2p.Protocol, 0, len(ProtocolVersions))
+	for _, version := range ProtocolVersions {
+		protocols = append(protocols, p2p.Protocol{
+			Name:    ProtocolName,
+			Version: version,
+			Length:  ProtocolLengths[version],
+			Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+				return r.runPeer(p.ID(), rw)
+			},
+			RunWithRWs: func(p *p2p.Peer, rws []p2p.MsgReadWriter) error {
+				return r.runPeer(p.ID(), rws[p2p.ConnDefault])
+			},
+		})
+	}
+	return protocols
+}
+
+// Announce signs the announcement with the node key and gossips it to the peers.
+func (r *Relay) Announce(kind, message string, start, end uint64) (*Announcement, error) {
+	a := &Announcement{
+		Kind:    kind,
+		Message: message,
+		Start:   start,
+		End:     end,
+		Time:    uint64(time.Now().Unix()),
+	}
+	if err := a.sign(r.key); err != nil {
+		return nil, err
+	}
+	if err := r.add(a, time.Now()); err != nil {
+		return nil, err
+	}
+	r.broadcast([]*Announcement{a}, nil)
+	return a, nil
+}
+
+// Announcements returns the unexpired announcements with their signers, ordered by the time
+// they were made.
+func (r *Relay) Announcements() ([]*Announcement, []common.Address) {
+	r.mu.Lock()
+	entries := make([]*entry, 0, len(r.announcements))
+	for _, e := range r.announcements {
+		if !e.expired(time.Now()) {
+			entries = append(entries, e)
+		}
+	}
+	r.mu.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Time != entries[j].Time {
+			return entries[i].Time < entries[j].Time
+		}
+		return entries[i].signer.Hex() < entries[j].signer.Hex()
+	})
+	announcements := make([]*Announcement, len(entries))
+	signers := make([]common.Address, len(entries))
+	for i, e := range entries {
+		announcements[i], signers[i] = e.Announcement, e.signer
+	}
+	return announcements, signers
+}
+
+// add verifies the announcement and keeps it until it expires.
+func (r *Relay) add(a *Announcement, now time.Time) error {
+	if err := a.validate(now); err != nil {
+		return err
+	}
+	signer, err := a.Signer()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAnnouncement, err)
+	}
+	if !r.isCouncil(signer) {
+		return ErrNotCouncil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hash := a.Hash()
+	if _, ok := r.announcements[hash]; ok {
+		return ErrAlreadyKnown
+	}
+	count := 0
+	for _, e := range r.announcements {
+		if e.signer == signer && !e.expired(now) {
+			count++
+		}
+	}
+	if count >= maxPerSigner {
+		return ErrTooManyAnnouncements
+	}
+	r.announcements[hash] = &entry{a, signer}
+	return nil
+}
+
+// broadcast sends the announcements to every peer except the one they came from.
+func (r *Relay) broadcast(announcements []*Announcement, from *discover.NodeID) {
+	r.mu.Lock()
+	peers := make(map[discover.NodeID]p2p.MsgReadWriter, len(r.peers))
+	for id, rw := range r.peers {
+		if from == nil || id != *from {
+			peers[id] = rw
+		}
+	}
+	r.mu.Unlock()
+
+	for id, rw := range peers {
+		if err := p2p.Send(rw, AnnouncementsMsg, announcements); err != nil {
+			logger.Debug("Failed to send announcements", "id", id, "err", err)
+		}
+	}
+}
+
+func (r *Relay) runPeer(id discover.NodeID, rw p2p.MsgReadWriter) error {
+	r.mu.Lock()
+	r.peers[id] = rw
+	r.mu.Unlock()
+	logger.Debug("Announcement peer connected", "id", id)
+
+	defer func() {
+		r.mu.Lock()
+		delete(r.peers, id)
+		r.mu.Unlock()
+		logger.Debug("Announcement peer disconnected", "id", id)
+	}()
+
+	// Catch up the new peer with the known announcements. It is sent asynchronously
+	// since the peer does the same at the same time.
+	if announcements, _ := r.Announcements(); len(announcements) > 0 {
+		go func() {
+			if err := p2p.Send(rw, AnnouncementsMsg, announcements); err != nil {
+				logger.Debug("Failed to send announcements", "id", id, "err", err)
+			}
+		}()
+	}
+
+	for {
+		if err := r.handleMsg(id, rw); err != nil {
+			logger.Debug("Announcement message handling failed", "id", id, "err", err)
+			return err
+		}
+	}
+}
+
+func (r *Relay) handleMsg(id discover.NodeID, rw p2p.MsgReadWriter) error {
+	msg, err := rw.ReadMsg()
+	if err != nil {
+		return err
+	}
+	if msg.Size > maxMessageSize {
+		return fmt.Errorf("%w: %v > %v", errMsgTooLarge, msg.Size, maxMessageSize)
+	}
+	defer msg.Discard()
+
+	switch msg.Code {
+	case AnnouncementsMsg:
+		var announcements []*Announcement
+		if err := msg.Decode(&announcements); err != nil {
+			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+		}
+		receivedCounter.Inc(int64(len(announcements)))
+
+		// Council membership may differ among the nodes during a council change, so
+		// the rejected announcements are dropped without disconnecting the peer.
+		var fresh []*Announcement
+		for _, a := range announcements {
+			if err := r.add(a, time.Now()); err != nil {
+				if !errors.Is(err, ErrAlreadyKnown) {
+					logger.Debug("Dropped an announcement", "id", id, "hash", a.Hash(), "err", err)
+				}
+				continue
+			}
+			fresh = append(fresh, a)
+		}
+		if len(fresh) > 0 {
+			acceptedCounter.Inc(int64(len(fresh)))
+			r.broadcast(fresh, &id)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("%w: %v", errInvalidMsgCode, msg.Code)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package announce
+
+import (
+	"crypto/ecdsa"
+	"testing"
+	"time"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/networks/p2p"
+	"github.com/kaiachain/kaia/networks/p2p/discover"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func councilOf(keys ...*ecdsa.PrivateKey) func(common.Address) bool {
+	council := make(map[common.Address]bool)
+	for _, key := range keys {
+		council[crypto.PubkeyToAddress(key.PublicKey)] = true
+	}
+	return func(addr common.Address) bool { return council[addr] }
+}
+
+func TestAnnouncementSignature(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	a := &Announcement{Kind: "maintenance", Message: "disk replacement", Start: 100, End: 200, Time: 50}
+	require.NoError(t, a.sign(key))
+
+	signer, err := a.Signer()
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), signer)
+
+	a.End = 300
+	signer, err = a.Signer()
+	require.NoError(t, err)
+	assert.NotEqual(t, crypto.PubkeyToAddress(key.PublicKey), signer)
+}
+
+func TestAdd(t *testing.T) {
+	var (
+		key, _      = crypto.GenerateKey()
+		outsider, _ = crypto.GenerateKey()
+		r           = NewRelay(key, councilOf(key))
+		now         = time.Unix(1000, 0)
+		signed      = func(key *ecdsa.PrivateKey, a *Announcement) *Announcement {
+			require.NoError(t, a.sign(key))
+			return a
+		}
+	)
+
+	testcases := []struct {
+		a   *Announcement
+		err error
+	}{
+		{signed(key, &Announcement{Kind: "upgrade", Start: 1000, End: 2000, Time: 1000}), nil},
+		{signed(key, &Announcement{Kind: "upgrade", Start: 1000, End: 2000, Time: 1000}), ErrAlreadyKnown},
+		{signed(outsider, &Announcement{Kind: "upgrade", Start: 1000, End: 2000, Time: 1000}), ErrNotCouncil},
+		{signed(key, &Announcement{Kind: "", Start: 1000, End: 2000, Time: 1000}), ErrInvalidAnnouncement},
+		{signed(key, &Announcement{Kind: "upgrade", Start: 2000, End: 1500, Time: 1000}), ErrInvalidAnnouncement},
+		{signed(key, &Announcement{Kind: "upgrade", Start: 1000, End: 2000, Time: 2000}), ErrInvalidAnnouncement},
+		{signed(key, &Announcement{Kind: "upgrade", Start: 0, End: 1000, Time: 500}), ErrExpired},
+		{&Announcement{Kind: "upgrade", Start: 1000, End: 2000, Time: 1000, Signature: make([]byte, 65)}, ErrInvalidAnnouncement},
+	}
+	for i, tc := range testcases {
+		assert.ErrorIs(t, r.add(tc.a, now), tc.err, i)
+	}
+
+	for i := 1; i < maxPerSigner; i++ {
+		require.NoError(t, r.add(signed(key, &Announcement{Kind: "upgrade", Start: 1000, End: 2000, Time: uint64(1000 - i)}), now))
+	}
+	assert.ErrorIs(t, r.add(signed(key, &Announcement{Kind: "upgrade", Start: 1000, End: 3000, Time: 1000}), now), ErrTooManyAnnouncements)
+
+	r.prune(time.Unix(2000, 0))
+	assert.Empty(t, r.announcements)
+}
+
+// connect runs the `announce` protocol between the two relays over a pipe.
+func connect(t *testing.T, a, b *Relay) {
+	rwA, rwB := p2p.MsgPipe()
+	t.Cleanup(func() {
+		rwA.Close()
+		rwB.Close()
+	})
+	go a.runPeer(discover.PubkeyID(&b.key.PublicKey), rwA)
+	go b.runPeer(discover.PubkeyID(&a.key.PublicKey), rwB)
+}
+
+func waitAnnouncements(t *testing.T, r *Relay, n int) []*Announcement {
+	for i := 0; i < 100; i++ {
+		if announcements, _ := r.Announcements(); len(announcements) == n {
+			return announcements
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("expected %d announcements", n)
+	return nil
+}
+
+func TestGossip(t *testing.T) {
+	var (
+		keyA, _ = crypto.GenerateKey()
+		keyB, _ = crypto.GenerateKey()
+		keyC, _ = crypto.GenerateKey()
+		council = councilOf(keyA, keyB, keyC)
+		a       = NewRelay(keyA, council)
+		b       = NewRelay(keyB, council)
+		c       = NewRelay(keyC, council)
+		end     = uint64(time.Now().Add(time.Hour).Unix())
+	)
+
+	// An announcement made before connecting is sent on the connection.
+	_, err := a.Announce("maintenance", "restarting", 0, end)
+	require.NoError(t, err)
+
+	// a - b - c
+	connect(t, a, b)
+	connect(t, b, c)
+	waitAnnouncements(t, c, 1)
+
+	// A new announcement is forwarded through b.
+	announced, err := c.Announce("upgrade", "ready for v2.0.0", 0, end)
+	require.NoError(t, err)
+	announcements := waitAnnouncements(t, a, 2)
+	assert.Contains(t, announcements, announced)
+
+	_, signers := b.Announcements()
+	assert.ElementsMatch(t, []common.Address{crypto.PubkeyToAddress(keyA.PublicKey), crypto.PubkeyToAddress(keyC.PublicKey)}, signers)
+
+	api := NewPublicAnnounceAPI(a)
+	signer := crypto.PubkeyToAddress(keyC.PublicKey)
+	filtered := api.GetAnnouncements(&signer)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, announced.Hash(), filtered[0].Hash)
+	assert.Equal(t, "upgrade", filtered[0].Kind)
+}
+
+func TestAnnounceNotCouncil(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	r := NewRelay(key, councilOf())
+
+	_, err := r.Announce("maintenance", "", 0, uint64(time.Now().Add(time.Hour).Unix()))
+	assert.ErrorIs(t, err, ErrNotCouncil)
+}
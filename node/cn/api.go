@@ -41,6 +41,7 @@ import (
 	"github.com/kaiachain/kaia/networks/rpc"
 	"github.com/kaiachain/kaia/params"
 	"github.com/kaiachain/kaia/rlp"
+	"github.com/kaiachain/kaia/storage/database"
 	"github.com/kaiachain/kaia/storage/statedb"
 	"github.com/kaiachain/kaia/work"
 )
@@ -73,6 +74,39 @@ func NewPrivateAdminAPI(cn *CN) *PrivateAdminAPI {
 	return &PrivateAdminAPI{cn: cn}
 }
 
+// Drain prepares the node for termination, e.g. from a Kubernetes preStop hook.
+// It rejects new RPC calls except the admin namespace, lets in-flight calls
+// complete, and stops block proposal after the node has proposed a block within
+// the next handoverBlocks blocks. Poll admin_drainStatus until safeToTerminate.
+func (api *PrivateAdminAPI) Drain(handoverBlocks *uint64) *DrainStatus {
+	blocks := uint64(defaultHandoverBlocks)
+	if handoverBlocks != nil {
+		blocks = *handoverBlocks
+	}
+	api.cn.drainer.start(api.cn, blocks)
+	return api.cn.drainer.status(api.cn)
+}
+
+// CancelDrain cancels the drain started by admin_drain, so that the node serves
+// all RPC calls again. If the block proposal was already stopped, restart it by miner_start.
+func (api *PrivateAdminAPI) CancelDrain() *DrainStatus {
+	api.cn.drainer.stop()
+	return api.cn.drainer.status(api.cn)
+}
+
+// DrainStatus returns the progress of the drain started by admin_drain.
+func (api *PrivateAdminAPI) DrainStatus() *DrainStatus {
+	return api.cn.drainer.status(api.cn)
+}
+
+// LoadStatus returns the state of the load shedding enabled by --loadshed.
+func (api *PrivateAdminAPI) LoadStatus() (*LoadStatus, error) {
+	if api.cn.loadShedder == nil {
+		return nil, errors.New("load shedding is not enabled")
+	}
+	return api.cn.loadShedder.status(), nil
+}
+
 // ExportChain exports the current blockchain into a local file,
 // or a range of blocks if first and last are non-nil.
 func (api *PrivateAdminAPI) ExportChain(file string, first, last *rpc.BlockNumber) (bool, error) {
@@ -211,10 +245,90 @@ func (api *PrivateAdminAPI) StateMigrationStatus() map[string]interface{} {
 	}
 }
 
+// StartStatePruning starts an online state pruning session, which deletes the trie nodes
+// unreachable from the state of the recent blocks.
+func (api *PrivateAdminAPI) StartStatePruning() error {
+	return api.cn.blockchain.StartStatePruning()
+}
+
+// StatePruningStatus returns the progress of the running or the latest online state pruning session.
+func (api *PrivateAdminAPI) StatePruningStatus() *blockchain.StatePruningStatus {
+	return api.cn.blockchain.StatePruningStatus()
+}
+
+// DBStats returns the size, disk throughput, compaction backlog and open files of each logical database.
+func (api *PrivateAdminAPI) DBStats() []*database.DBStats {
+	return api.cn.ChainDB().DBStats()
+}
+
 func (api *PrivateAdminAPI) SaveTrieNodeCacheToDisk() error {
 	return api.cn.BlockChain().SaveTrieNodeCacheToDisk()
 }
 
+// TxPoolLimits returns the txpool parameters that can be changed at runtime.
+func (api *PrivateAdminAPI) TxPoolLimits() (*blockchain.TxPoolLimits, error) {
+	pool, ok := api.cn.txPool.(txPoolLimiter)
+	if !ok {
+		return nil, errors.New("txpool limits are not adjustable")
+	}
+	limits := pool.Limits()
+	return &limits, nil
+}
+
+// SetTxPoolLimits changes the given txpool parameters without a restart and persists them in
+// the data directory, where they override the txpool flags from then on.
+func (api *PrivateAdminAPI) SetTxPoolLimits(args TxPoolLimitsArgs) (*blockchain.TxPoolLimits, error) {
+	pool, ok := api.cn.txPool.(txPoolLimiter)
+	if !ok {
+		return nil, errors.New("txpool limits are not adjustable")
+	}
+	limits, err := args.apply(pool.Limits())
+	if err != nil {
+		return nil, err
+	}
+	if err := pool.SetLimits(limits); err != nil {
+		return nil, err
+	}
+	if api.cn.loadShedder != nil {
+		api.cn.loadShedder.setSlotLimits(limits.ExecSlotsAll, limits.NonExecSlotsAll)
+	}
+	if err := writeTxPoolLimits(api.cn.txPoolLimits, limits); err != nil {
+		return nil, fmt.Errorf("txpool limits are changed but not persisted: %w", err)
+	}
+	return &limits, nil
+}
+
+// TxFirewallRules returns the tx firewall rules with their hits since the start.
+func (api *PrivateAdminAPI) TxFirewallRules() []TxFirewallRule {
+	return api.cn.txFirewall.list()
+}
+
+// AddTxFirewallRule adds the tx firewall rule, or replaces the rule of the same name, and
+// persists the rules. It takes effect on the next incoming transaction.
+func (api *PrivateAdminAPI) AddTxFirewallRule(rule TxFirewallRule) error {
+	if err := api.cn.txFirewall.add(rule); err != nil {
+		return err
+	}
+	logger.Warn("Tx firewall rule is added", "name", rule.Name, "action", rule.Action,
+		"selector", rule.Selector, "pattern", rule.Pattern, "to", rule.To)
+	if err := api.cn.txFirewall.save(); err != nil {
+		return fmt.Errorf("tx firewall rule is added but not persisted: %w", err)
+	}
+	return nil
+}
+
+// RemoveTxFirewallRule removes the tx firewall rule of the name and persists the rules.
+func (api *PrivateAdminAPI) RemoveTxFirewallRule(name string) error {
+	if !api.cn.txFirewall.remove(name) {
+		return fmt.Errorf("unknown tx firewall rule: %s", name)
+	}
+	logger.Warn("Tx firewall rule is removed", "name", name)
+	if err := api.cn.txFirewall.save(); err != nil {
+		return fmt.Errorf("tx firewall rule is removed but not persisted: %w", err)
+	}
+	return nil
+}
+
 func (api *PrivateAdminAPI) SpamThrottlerConfig(ctx context.Context) (*blockchain.ThrottlerConfig, error) {
 	throttler := blockchain.GetSpamThrottler()
 	if throttler == nil {
@@ -316,6 +430,92 @@ func (api *PublicDebugAPI) DumpBlock(ctx context.Context, blockNrOrHash rpc.Bloc
 	return stateDb.RawDump(), nil
 }
 
+// AccountRangeMaxResults is the maximum number of accounts returned by a debug_accountRange call.
+const AccountRangeMaxResults = 256
+
+// AccountRangeResult is the result of a debug_accountRange API call.
+type AccountRangeResult struct {
+	state.IteratorDump
+	Proof []string `json:"proof"` // Merkle proof of the start key and the last returned account, see statedb.VerifyRangeProof.
+}
+
+// AccountRange enumerates the accounts of the given block, ordered by the hashed address,
+// starting from the given hashed address. The result can be paged by passing its 'next'
+// as the start of the following call.
+func (api *PublicDebugAPI) AccountRange(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, start hexutil.Bytes, maxResults int, nocode, nostorage, incompletes bool) (AccountRangeResult, error) {
+	var block *types.Block
+	var err error
+	if number, ok := blockNrOrHash.Number(); ok && number == rpc.PendingBlockNumber {
+		return AccountRangeResult{}, errors.New("pending state is not supported")
+	} else if ok && number == rpc.LatestBlockNumber {
+		block = api.cn.APIBackend.CurrentBlock()
+	} else {
+		block, err = api.cn.APIBackend.BlockByNumberOrHash(ctx, blockNrOrHash)
+		if err != nil {
+			blockNrOrHashString, _ := blockNrOrHash.NumberOrHashString()
+			return AccountRangeResult{}, fmt.Errorf("block %v not found", blockNrOrHashString)
+		}
+	}
+	stateDb, err := api.cn.BlockChain().StateAtWithPersistent(block.Root())
+	if err != nil {
+		return AccountRangeResult{}, err
+	}
+
+	if maxResults > AccountRangeMaxResults || maxResults <= 0 {
+		maxResults = AccountRangeMaxResults
+	}
+	dump, err := stateDb.IteratorDump(&state.DumpConfig{
+		SkipCode:          nocode,
+		SkipStorage:       nostorage,
+		OnlyWithAddresses: !incompletes,
+		Start:             start,
+		Max:               uint64(maxResults),
+	})
+	if err != nil {
+		return AccountRangeResult{}, err
+	}
+
+	var last []byte
+	for _, acc := range dump.Accounts {
+		if bytes.Compare(acc.SecureKey, last) > 0 {
+			last = acc.SecureKey
+		}
+	}
+	tr, err := stateDb.Database().OpenTrie(block.Root(), nil)
+	if err != nil {
+		return AccountRangeResult{}, err
+	}
+	proof, err := rangeProof(tr, start, last)
+	if err != nil {
+		return AccountRangeResult{}, err
+	}
+	return AccountRangeResult{IteratorDump: dump, Proof: proof}, nil
+}
+
+// rangeProof returns the Merkle proof nodes of the first and the last keys of a range
+// iterated from the given trie. The first key is right-padded to the hashed key length,
+// as the trie iterators do. The last key is omitted if the range is empty.
+func rangeProof(tr state.Trie, first, last []byte) ([]string, error) {
+	proofDB := database.NewMemoryDBManager()
+	defer proofDB.Close()
+
+	if err := tr.Prove(common.RightPadBytes(first, common.HashLength), 0, proofDB); err != nil {
+		return nil, err
+	}
+	if last != nil {
+		if err := tr.Prove(last, 0, proofDB); err != nil {
+			return nil, err
+		}
+	}
+	var proof []string
+	it := proofDB.GetMiscDB().NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		proof = append(proof, hexutil.Encode(it.Value()))
+	}
+	return proof, it.Error()
+}
+
 type Trie struct {
 	Type   string `json:"type"`
 	Hash   string `json:"hash"`
@@ -360,6 +560,12 @@ func (api *PublicDebugAPI) DumpStateTrie(ctx context.Context, blockNrOrHash rpc.
 	return result, nil
 }
 
+// SlowQueries returns the RPC calls recorded in the slow query log, from the oldest to the newest.
+// The calls are recorded only if rpc.slow-query.threshold is set.
+func (api *PrivateDebugAPI) SlowQueries() []rpc.SlowQuery {
+	return rpc.SlowQueries()
+}
+
 // TODO-Kaia: Rearrange PublicDebugAPI and PrivateDebugAPI receivers
 // StartWarmUp retrieves all state/storage tries of the latest committed state root and caches the tries.
 func (api *PrivateDebugAPI) StartWarmUp(minLoad uint) error {
@@ -385,6 +591,31 @@ func (api *PrivateDebugAPI) StartCollectingTrieStats(contractAddr common.Address
 	return api.cn.blockchain.StartCollectingTrieStats(contractAddr)
 }
 
+// GetStateDiff returns the accounts and storage slots created, modified or deleted by the given block.
+// The state diff is available only if the block is imported with state.diff or state.diff-persist enabled.
+func (api *PrivateDebugAPI) GetStateDiff(blockHash common.Hash) (*state.StateDiff, error) {
+	return api.cn.blockchain.GetStateDiff(blockHash)
+}
+
+// SetTrieCacheSize resizes the local trie node cache to the given size in MiB.
+// The cached trie nodes are dropped, so the cache warms up again from empty.
+func (api *PrivateDebugAPI) SetTrieCacheSize(sizeMiB int) error {
+	return api.cn.blockchain.StateCache().TrieDB().ResizeTrieNodeCache(sizeMiB)
+}
+
+// PinStateRoot keeps the state of the given block from being garbage collected or pruned for
+// the given duration in seconds, and returns the expiry. Pinning the same block again extends the pin.
+func (api *PrivateDebugAPI) PinStateRoot(blockHash common.Hash, duration uint64) (time.Time, error) {
+	header := api.cn.blockchain.GetHeaderByHash(blockHash)
+	if header == nil {
+		return time.Time{}, fmt.Errorf("block %v not found", blockHash.Hex())
+	}
+	if duration > uint64(blockchain.MaxStatePinDuration/time.Second) {
+		duration = uint64(blockchain.MaxStatePinDuration / time.Second)
+	}
+	return api.cn.blockchain.PinStateRoot(header.Root, time.Duration(duration)*time.Second)
+}
+
 // PrivateDebugAPI is the collection of CN full node APIs exposed over
 // the private debugging endpoint.
 type PrivateDebugAPI struct {
@@ -406,6 +637,30 @@ func (api *PrivateDebugAPI) Preimage(ctx context.Context, hash common.Hash) (hex
 	return nil, errors.New("unknown preimage")
 }
 
+// ReplayProposal re-derives the transactions of a block proposed by this node from the input
+// recorded with --proposal.record, and verifies that the block followed the ordering policy.
+func (api *PrivateDebugAPI) ReplayProposal(hash common.Hash) (*work.ProposalReplay, error) {
+	return work.ReplayProposal(api.cn.blockchain, api.cn.engine, api.cn.chainDB.GetMiscDB(), hash)
+}
+
+// TxProvenance returns where and when this node has first seen the transaction, or null if it has not.
+// It requires --txprovenance.
+func (api *PrivateDebugAPI) TxProvenance(hash common.Hash) (*TxProvenance, error) {
+	if api.cn.txProvenance == nil {
+		return nil, errors.New("tx provenance is not enabled")
+	}
+	return api.cn.txProvenance.get(hash), nil
+}
+
+// TxPropagation returns the propagation path of the transaction across this node and the
+// endpoints given by --txprovenance.fleet.
+func (api *PrivateDebugAPI) TxPropagation(ctx context.Context, hash common.Hash) (*TxPropagation, error) {
+	if api.cn.txProvenance == nil {
+		return nil, errors.New("tx provenance is not enabled")
+	}
+	return api.cn.txProvenance.propagation(ctx, hash, api.cn.config.TxProvenanceFleet), nil
+}
+
 // TODO-Kaia: Rearrange PublicDebugAPI and PrivateDebugAPI receivers
 // GetBadBLocks returns a list of the last 'bad blocks' that the client has seen on the network
 // and returns them as a JSON list of block-hashes
@@ -416,7 +671,8 @@ func (api *PublicDebugAPI) GetBadBlocks(ctx context.Context) ([]blockchain.BadBl
 // StorageRangeResult is the result of a debug_storageRangeAt API call.
 type StorageRangeResult struct {
 	Storage storageMap   `json:"storage"`
-	NextKey *common.Hash `json:"nextKey"` // nil if Storage includes the last key in the statedb.
+	NextKey *common.Hash `json:"nextKey"`         // nil if Storage includes the last key in the statedb.
+	Proof   []string     `json:"proof,omitempty"` // Merkle proof of the start key and the last returned key, see statedb.VerifyRangeProof.
 }
 
 type storageMap map[common.Hash]storageEntry
@@ -443,7 +699,21 @@ func (api *PrivateDebugAPI) StorageRangeAt(ctx context.Context, blockHash common
 	if st == nil {
 		return StorageRangeResult{}, fmt.Errorf("account %x doesn't exist", contractAddress)
 	}
-	return storageRangeAt(st, keyStart, maxResult)
+	result, err := storageRangeAt(st, keyStart, maxResult)
+	if err != nil {
+		return StorageRangeResult{}, err
+	}
+
+	var last []byte
+	for key := range result.Storage {
+		if bytes.Compare(key[:], last) > 0 {
+			last = common.CopyBytes(key[:])
+		}
+	}
+	if result.Proof, err = rangeProof(st, keyStart, last); err != nil {
+		return StorageRangeResult{}, err
+	}
+	return result, nil
 }
 
 func storageRangeAt(st state.Trie, start []byte, maxResult int) (StorageRangeResult, error) {
@@ -469,6 +739,66 @@ func storageRangeAt(st state.Trie, start []byte, maxResult int) (StorageRangeRes
 	return result, nil
 }
 
+const (
+	defaultDumpContractStateLimit = 1024
+	maxDumpContractStateLimit     = 65536
+)
+
+// DumpContractStateOptions are the pagination options of debug_dumpContractState.
+type DumpContractStateOptions struct {
+	Start hexutil.Bytes `json:"start"` // the hashed storage key to start from, i.e. the nextKey of the previous page
+	Limit int           `json:"limit"` // the number of storage entries in a page
+}
+
+// ContractStateDump is the result of a debug_dumpContractState API call.
+type ContractStateDump struct {
+	Root    common.Hash    `json:"root"`
+	Storage storageMap     `json:"storage"`
+	NextKey *common.Hash   `json:"nextKey"` // nil if Storage includes the last key in the statedb.
+	Decoded map[string]any `json:"decoded,omitempty"`
+}
+
+// DumpContractState returns the storage of a contract at the given block, paginated by the hashed
+// storage key. If the storage layout emitted by solc is given, the first page also contains the
+// state variables decoded by the layout.
+func (api *PrivateDebugAPI) DumpContractState(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash, layout *StorageLayout, opts *DumpContractStateOptions) (*ContractStateDump, error) {
+	var (
+		start []byte
+		limit = defaultDumpContractStateLimit
+	)
+	if opts != nil {
+		start = opts.Start
+		if opts.Limit > 0 {
+			limit = min(opts.Limit, maxDumpContractStateLimit)
+		}
+	}
+
+	statedb, _, err := api.cn.APIBackend.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if !statedb.IsProgramAccount(address) {
+		return nil, fmt.Errorf("account %x is not a contract", address)
+	}
+	st := statedb.StorageTrie(address)
+	if st == nil {
+		return nil, fmt.Errorf("account %x doesn't exist", address)
+	}
+
+	result, err := storageRangeAt(st, start, limit)
+	if err != nil {
+		return nil, err
+	}
+	dump := &ContractStateDump{Root: st.Hash(), Storage: result.Storage, NextKey: result.NextKey}
+	if layout != nil && len(start) == 0 {
+		read := func(slot common.Hash) common.Hash { return statedb.GetState(address, slot) }
+		if dump.Decoded, err = layout.Decode(read, uint64(limit)); err != nil {
+			return nil, err
+		}
+	}
+	return dump, nil
+}
+
 // TODO-Kaia: Rearrange PublicDebugAPI and PrivateDebugAPI receivers
 // GetModifiedAccountsByNumber returns all accounts that have changed between the
 // two blocks specified. A change is defined as a difference in nonce, balance,
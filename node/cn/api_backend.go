@@ -231,7 +231,13 @@ func (b *CNAPIBackend) GetBlockReceipts(ctx context.Context, hash common.Hash) t
 }
 
 func (b *CNAPIBackend) GetLogs(ctx context.Context, hash common.Hash) ([][]*types.Log, error) {
-	return b.cn.blockchain.GetLogsByHash(hash), nil
+	logs := b.cn.blockchain.GetLogsByHash(hash)
+	if logs == nil {
+		if number := b.cn.ChainDB().ReadHeaderNumber(hash); number != nil {
+			return nil, blockchain.CheckReceiptsPruned(b.cn.ChainDB(), *number)
+		}
+	}
+	return logs, nil
 }
 
 func (b *CNAPIBackend) GetTd(blockHash common.Hash) *big.Int {
@@ -268,6 +274,14 @@ func (b *CNAPIBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscrip
 }
 
 func (b *CNAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
+	if b.cn.txProvenance != nil {
+		b.cn.txProvenance.recordRPC(ctx, signedTx.Hash())
+	}
+	if b.cn.txFirewall != nil {
+		if err := b.cn.txFirewall.checkRPC(ctx, signedTx); err != nil {
+			return err
+		}
+	}
 	return b.cn.txPool.AddLocal(signedTx)
 }
 
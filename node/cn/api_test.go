@@ -22,13 +22,19 @@
 package cn
 
 import (
+	"bytes"
 	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/kaiachain/kaia/blockchain/state"
 	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/rlp"
 	"github.com/kaiachain/kaia/storage/database"
+	"github.com/kaiachain/kaia/storage/statedb"
 )
 
 var dumper = spew.ConfigState{Indent: "    "}
@@ -63,23 +69,23 @@ func TestStorageRangeAt(t *testing.T) {
 	}{
 		{
 			start: []byte{}, limit: 0,
-			want: StorageRangeResult{storageMap{}, &keys[0]},
+			want: StorageRangeResult{storageMap{}, &keys[0], nil},
 		},
 		{
 			start: []byte{}, limit: 100,
-			want: StorageRangeResult{storage, nil},
+			want: StorageRangeResult{storage, nil, nil},
 		},
 		{
 			start: []byte{}, limit: 2,
-			want: StorageRangeResult{storageMap{keys[0]: storage[keys[0]], keys[1]: storage[keys[1]]}, &keys[2]},
+			want: StorageRangeResult{storageMap{keys[0]: storage[keys[0]], keys[1]: storage[keys[1]]}, &keys[2], nil},
 		},
 		{
 			start: []byte{0x00}, limit: 4,
-			want: StorageRangeResult{storage, nil},
+			want: StorageRangeResult{storage, nil, nil},
 		},
 		{
 			start: []byte{0x40}, limit: 2,
-			want: StorageRangeResult{storageMap{keys[1]: storage[keys[1]], keys[2]: storage[keys[2]]}, &keys[3]},
+			want: StorageRangeResult{storageMap{keys[1]: storage[keys[1]], keys[2]: storage[keys[2]]}, &keys[3], nil},
 		},
 	}
 	for _, test := range tests {
@@ -93,3 +99,61 @@ func TestStorageRangeAt(t *testing.T) {
 		}
 	}
 }
+
+func TestStorageRangeProof(t *testing.T) {
+	state, _ := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()), nil, nil)
+	addr := common.Address{0x01}
+	for i := byte(1); i <= 16; i++ {
+		state.SetState(addr, common.Hash{i}, common.Hash{i})
+	}
+	st := state.StorageTrie(addr)
+
+	for _, start := range [][]byte{nil, {0x40}, {0xff}} {
+		result, err := storageRangeAt(st, start, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var keys, values [][]byte
+		for key, entry := range result.Storage {
+			value, _ := rlp.EncodeToBytes(common.TrimLeftZeroes(entry.Value[:]))
+			keys, values = append(keys, common.CopyBytes(key[:])), append(values, value)
+		}
+		sort.Sort(&kvSorter{keys, values})
+
+		var last []byte
+		if len(keys) > 0 {
+			last = keys[len(keys)-1]
+		}
+		proof, err := rangeProof(st, start, last)
+		if err != nil {
+			t.Fatal(err)
+		}
+		proofDB := database.NewMemoryDBManager()
+		for _, node := range proof {
+			enc := hexutil.MustDecode(node)
+			proofDB.WriteMerkleProof(database.TrieNodeKey(common.BytesToExtHash(crypto.Keccak256(enc))), enc)
+		}
+		first := common.RightPadBytes(start, common.HashLength)
+		if last == nil {
+			last = first
+		}
+		more, err := statedb.VerifyRangeProof(st.Hash(), first, last, keys, values, proofDB)
+		if err != nil {
+			t.Fatalf("invalid proof for range 0x%x..: %v", start, err)
+		}
+		if more != (result.NextKey != nil) {
+			t.Fatalf("wrong continuation flag for range 0x%x..: have %v, want %v", start, more, result.NextKey != nil)
+		}
+	}
+}
+
+type kvSorter struct {
+	keys, values [][]byte
+}
+
+func (s *kvSorter) Len() int           { return len(s.keys) }
+func (s *kvSorter) Less(i, j int) bool { return bytes.Compare(s.keys[i], s.keys[j]) < 0 }
+func (s *kvSorter) Swap(i, j int) {
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+	s.values[i], s.values[j] = s.values[j], s.values[i]
+}
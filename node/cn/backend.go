@@ -28,6 +28,7 @@ import (
 	"math/big"
 	"os/exec"
 	"runtime"
+	"slices"
 	"sync"
 	"time"
 
@@ -45,21 +46,31 @@ import (
 	istanbulBackend "github.com/kaiachain/kaia/consensus/istanbul/backend"
 	"github.com/kaiachain/kaia/crypto"
 	"github.com/kaiachain/kaia/datasync/downloader"
+	"github.com/kaiachain/kaia/datasync/follower"
 	"github.com/kaiachain/kaia/event"
 	"github.com/kaiachain/kaia/governance"
 	"github.com/kaiachain/kaia/kaiax"
+	abistore_impl "github.com/kaiachain/kaia/kaiax/abistore/impl"
 	contractgov_impl "github.com/kaiachain/kaia/kaiax/gov/contractgov/impl"
 	headergov_impl "github.com/kaiachain/kaia/kaiax/gov/headergov/impl"
 	gov_impl "github.com/kaiachain/kaia/kaiax/gov/impl"
+	registrygov_impl "github.com/kaiachain/kaia/kaiax/gov/registrygov/impl"
+	invariant_impl "github.com/kaiachain/kaia/kaiax/invariant/impl"
 	reward_impl "github.com/kaiachain/kaia/kaiax/reward/impl"
 	"github.com/kaiachain/kaia/kaiax/staking"
 	staking_impl "github.com/kaiachain/kaia/kaiax/staking/impl"
+	stateexpiry_impl "github.com/kaiachain/kaia/kaiax/stateexpiry/impl"
 	supply_impl "github.com/kaiachain/kaia/kaiax/supply/impl"
+	verkle_impl "github.com/kaiachain/kaia/kaiax/verkle/impl"
 	"github.com/kaiachain/kaia/networks/p2p"
+	"github.com/kaiachain/kaia/networks/p2p/discover"
 	"github.com/kaiachain/kaia/networks/rpc"
 	"github.com/kaiachain/kaia/node"
+	"github.com/kaiachain/kaia/node/cn/announce"
 	"github.com/kaiachain/kaia/node/cn/filters"
+	"github.com/kaiachain/kaia/node/cn/fleetban"
 	"github.com/kaiachain/kaia/node/cn/gasprice"
+	"github.com/kaiachain/kaia/node/cn/privtx"
 	"github.com/kaiachain/kaia/node/cn/tracers"
 	"github.com/kaiachain/kaia/params"
 	"github.com/kaiachain/kaia/reward"
@@ -68,7 +79,18 @@ import (
 	"github.com/kaiachain/kaia/work"
 )
 
-var errCNLightSync = errors.New("can't run cn.CN in light sync mode")
+var (
+	errCNLightSync      = errors.New("can't run cn.CN in light sync mode")
+	errProposerNotBuilt = errors.New("can't run a consensus node in a binary built with the noproposer tag")
+)
+
+// trieJournalFile is the file in the data directory which keeps the in-memory state tries
+// of the recent blocks across the restarts if the state trie journal is enabled.
+const trieJournalFile = "triejournal"
+
+// statePruningCheckpointFile is the file in the data directory which keeps the bloom filter
+// of an interrupted online state pruning session.
+const statePruningCheckpointFile = "statepruning.bloom"
 
 //go:generate mockgen -destination=mocks/lesserver_mock.go -package=mocks github.com/kaiachain/kaia/node/cn LesServer
 type LesServer interface {
@@ -90,6 +112,9 @@ type Miner interface {
 	SetExtra(extra []byte) error
 	Pending() (*types.Block, *state.StateDB)
 	PendingBlock() *types.Block
+	SetPrivateTxSource(source work.PrivateTxSource)
+	SetBlobGasLimiter(limiter work.BlobGasLimiter)
+	SetRecordProposals(enabled bool)
 	kaiax.ExecutionModuleHost // Because miner executes blocks, inject ExecutionModule.
 }
 
@@ -145,6 +170,18 @@ type CN struct {
 
 	lock sync.RWMutex // Protects the variadic fields (e.g. gas price)
 
+	drainer      drainer       // Hands off the proposer duty on admin_drain
+	loadShedder  *loadShedder  // Degrades the service under resource pressure; nil if disabled
+	txPoolLimits string        // File persisting the txpool limits changed at runtime
+	txProvenance *txProvenance // Records where and when the transactions arrived; nil if disabled
+	txFirewall   *txFirewall   // Filters the incoming transactions by their calldata
+
+	follower *follower.Follower // Pulls blocks from upstream nodes in follower mode
+
+	privTxRelay   *privtx.Relay   // Exchanges private transactions with the partners; nil if disabled
+	announceRelay *announce.Relay // Gossips the announcements of the council members; nil if disabled
+	fleetBan      *fleetban.Relay // Shares the abusive IPs and nodes with the operator's fleet; nil if disabled
+
 	components []interface{}
 
 	governance governance.Engine
@@ -161,6 +198,30 @@ func (s *CN) AddLesServer(ls LesServer) {
 }
 
 // senderTxHashIndexer subscribes chainEvent and stores senderTxHash to txHash mapping information.
+// trieNodeHealer re-fetches the trie nodes quarantined by the integrity check from the peers.
+func trieNodeHealer(d ProtocolManagerDownloader, corruption <-chan blockchain.TrieCorruptionEvent, subscription event.Subscription) {
+	defer subscription.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-corruption:
+			// The peers serve the trie nodes by the merkle hash only
+			if !ev.Hash.IsZeroExtended() {
+				logger.Warn("Cannot re-fetch the quarantined trie node", "hash", ev.Hash)
+				continue
+			}
+			if err := d.HealTrieNode(ev.Hash.Unextend()); err != nil {
+				logger.Warn("Failed to re-fetch the quarantined trie node", "hash", ev.Hash, "err", err)
+				continue
+			}
+			logger.Info("Re-fetching the quarantined trie node", "hash", ev.Hash)
+
+		case <-subscription.Err():
+			return
+		}
+	}
+}
+
 func senderTxHashIndexer(db database.DBManager, chainEvent <-chan blockchain.ChainEvent, subscription event.Subscription) {
 	defer subscription.Unsubscribe()
 
@@ -207,6 +268,14 @@ func checkSyncMode(config *Config) error {
 	return nil
 }
 
+// checkProposerBuilt rejects a consensus node if the block production is excluded from the binary.
+func checkProposerBuilt(nodeType common.ConnType) error {
+	if nodeType == common.CONSENSUSNODE && !work.ProposerBuilt {
+		return errProposerNotBuilt
+	}
+	return nil
+}
+
 func setEngineType(chainConfig *params.ChainConfig) {
 	if chainConfig.Clique != nil {
 		types.EngineType = types.Engine_Clique
@@ -222,6 +291,9 @@ func New(ctx *node.ServiceContext, config *Config) (*CN, error) {
 	if err := checkSyncMode(config); err != nil {
 		return nil, err
 	}
+	if err := checkProposerBuilt(ctx.NodeType()); err != nil {
+		return nil, err
+	}
 
 	chainDB := CreateDB(ctx, config, "chaindata")
 
@@ -281,8 +353,30 @@ func New(ctx *node.ServiceContext, config *Config) (*CN, error) {
 			SenderTxHashIndexing: config.SenderTxHashIndexing,
 			SnapshotCacheSize:    config.SnapshotCacheSize,
 			SnapshotAsyncGen:     config.SnapshotAsyncGen,
+
+			OnlinePruning:          config.OnlinePruning,
+			OnlinePruningRetention: config.OnlinePruningRetention,
+			OnlinePruningInterval:  config.OnlinePruningInterval,
+			OnlinePruningRate:      config.OnlinePruningRate,
+			OnlinePruningBloomSize: config.OnlinePruningBloomSize,
+
+			ReceiptRetention: config.ReceiptRetention,
+
+			StateDiff:        config.StateDiff,
+			StateDiffPersist: config.StateDiffPersist,
+
+			TrieScheme:       config.TrieScheme,
+			TrieMirrorScheme: config.TrieMirrorScheme,
+
+			TrieIntegrityCheckRate: config.TrieIntegrityCheckRate,
 		}
 	)
+	if config.TrieJournal && !ctx.IsMemoryDB() {
+		cacheConfig.TrieJournal = ctx.ResolvePath(trieJournalFile)
+	}
+	if !ctx.IsMemoryDB() {
+		cacheConfig.OnlinePruningCheckpoint = ctx.ResolvePath(statePruningCheckpointFile)
+	}
 
 	bc, err := blockchain.NewBlockChain(chainDB, cacheConfig, cn.chainConfig, cn.engine, vmConfig)
 	if err != nil {
@@ -291,7 +385,7 @@ func New(ctx *node.ServiceContext, config *Config) (*CN, error) {
 	bc.SetCanonicalBlock(config.StartBlockNumber)
 
 	// Write the live pruning flag to database if the node is started for the first time
-	if config.LivePruning && !chainDB.ReadPruningEnabled() {
+	if config.LivePruning && !chainDB.ReadPruningEnabled() && !chainDB.IsReadOnly() {
 		if bc.CurrentBlock().NumberU64() > 0 {
 			return nil, errors.New("cannot enable live pruning after chain has advanced")
 		}
@@ -336,7 +430,10 @@ func New(ctx *node.ServiceContext, config *Config) (*CN, error) {
 	}
 
 	// Rewind the chain in case of an incompatible config upgrade.
-	if compat, ok := genesisErr.(*params.ConfigCompatError); ok {
+	// A read-only node cannot rewind, so it leaves the upgrade to the node owning the databases.
+	if compat, ok := genesisErr.(*params.ConfigCompatError); ok && chainDB.IsReadOnly() {
+		logger.Warn("Skipping the chain rewind on the read-only databases", "err", compat)
+	} else if ok {
 		logger.Error("Rewinding chain to upgrade configuration", "err", compat)
 		cn.blockchain.SetHead(compat.RewindTo)
 		chainDB.WriteChainConfig(genesisHash, cn.chainConfig)
@@ -346,16 +443,67 @@ func New(ctx *node.ServiceContext, config *Config) (*CN, error) {
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = ctx.ResolvePath(config.TxPool.Journal)
 	}
+	cn.txPoolLimits = ctx.ResolvePath(txPoolLimitsFile)
+	if limits, err := readTxPoolLimits(cn.txPoolLimits); err != nil {
+		return nil, err
+	} else if limits != nil {
+		logger.Info("Applying the txpool limits changed at runtime", "path", cn.txPoolLimits, "limits", *limits)
+		config.TxPool.SetLimits(*limits)
+	}
 	// TODO-Kaia-ServiceChain: add account creation prevention in the txPool if TxTypeAccountCreation is supported.
 	config.TxPool.NoAccountCreation = config.NoAccountCreation
 	cn.txPool = blockchain.NewTxPool(config.TxPool, cn.chainConfig, bc)
 	governance.SetTxPool(cn.txPool)
 
+	if config.LoadShedding {
+		txPool, _ := cn.txPool.(slotLimiter)
+		cn.loadShedder = newLoadShedder(config, ctx.ResolvePath("loadshed.probe"), cn.bloomIndexer, txPool)
+	}
+
+	if config.TxProvenance {
+		cn.txProvenance = newTxProvenance(discover.PubkeyID(&ctx.NodeKey().PublicKey).String(), config.TxProvenanceSize)
+		logger.Info("Tx provenance is enabled", "size", config.TxProvenanceSize, "fleet", len(config.TxProvenanceFleet))
+	}
+
+	if len(config.FleetBanMembers) > 0 {
+		members, err := fleetban.ParseMembers(config.FleetBanMembers)
+		if err != nil {
+			return nil, err
+		}
+		cn.fleetBan = fleetban.NewRelay(ctx.NodeKey(), members, config.FleetBanThreshold, config.FleetBanTTL)
+		logger.Info("Fleet ban sharing is enabled", "members", len(members), "threshold", config.FleetBanThreshold, "ttl", config.FleetBanTTL)
+	}
+
+	rulesPath := config.TxFirewallRules
+	if rulesPath == "" {
+		rulesPath = txFirewallFile
+	}
+	if cn.txFirewall, err = newTxFirewall(ctx.ResolvePath(rulesPath)); err != nil {
+		return nil, err
+	}
+	if rules := cn.txFirewall.list(); len(rules) > 0 {
+		logger.Warn("Tx firewall rules are loaded", "path", cn.txFirewall.path, "rules", len(rules))
+	}
+
 	// Permit the downloader to use the trie cache allowance during fast sync
 	cacheLimit := cacheConfig.TrieNodeCacheConfig.LocalCacheSizeMiB
-	if cn.protocolManager, err = NewProtocolManager(cn.chainConfig, config.SyncMode, config.NetworkId, cn.eventMux, cn.txPool, cn.engine, cn.blockchain, chainDB, cacheLimit, ctx.NodeType(), config); err != nil {
+	pm, err := NewProtocolManager(cn.chainConfig, config.SyncMode, config.NetworkId, cn.eventMux, cn.txPool, cn.engine, cn.blockchain, chainDB, cacheLimit, ctx.NodeType(), config)
+	if err != nil {
 		return nil, err
 	}
+	pm.txProvenance = cn.txProvenance
+	pm.txFirewall = cn.txFirewall
+	if cn.fleetBan != nil {
+		pm.fleetBan = cn.fleetBan
+		cn.fleetBan.SetBanHandler(func(string, string) { pm.dropBannedPeers() })
+	}
+	cn.protocolManager = pm
+
+	if config.TrieIntegrityCheckRate != 0 && config.TrieIntegrityHeal {
+		ch := make(chan blockchain.TrieCorruptionEvent, 16)
+		trieCorruptionSubscription := bc.SubscribeTrieCorruptionEvent(ch)
+		go trieNodeHealer(pm.Downloader(), ch, trieCorruptionSubscription)
+	}
 
 	if err := cn.setAcceptTxs(); err != nil {
 		logger.Error("Failed to decode IstanbulExtra", "err", err)
@@ -433,6 +581,43 @@ func New(ctx *node.ServiceContext, config *Config) (*CN, error) {
 
 	// istanbul BFT
 	cn.miner.SetExtra(makeExtraData(config.ExtraData))
+	if config.RecordProposals {
+		if ctx.NodeType() != common.CONSENSUSNODE {
+			return nil, errors.New("recording proposals is only available on CN")
+		}
+		cn.miner.SetRecordProposals(true)
+		logger.Info("Recording proposals is enabled")
+	}
+
+	if len(config.PrivateTxPartners) > 0 {
+		if ctx.NodeType() != common.CONSENSUSNODE {
+			return nil, errors.New("private tx relay is only available on CN")
+		}
+		partners, err := privtx.ParsePartners(config.PrivateTxPartners)
+		if err != nil {
+			return nil, err
+		}
+		pool := privtx.NewPool(types.LatestSignerForChainID(cn.chainConfig.ChainID), config.PrivateTxPoolSize)
+		cn.privTxRelay = privtx.NewRelay(ctx.NodeKey(), partners, pool, cn.blockchain)
+		cn.miner.SetPrivateTxSource(pool)
+		logger.Info("Private tx relay is enabled", "partners", len(partners))
+	}
+
+	if config.AnnounceEnabled {
+		if ctx.NodeType() != common.CONSENSUSNODE {
+			return nil, errors.New("council announcements are only available on CN")
+		}
+		istBackend, ok := cn.engine.(consensus.Istanbul)
+		if !ok {
+			return nil, errors.New("council announcements require the istanbul engine")
+		}
+		isCouncil := func(addr common.Address) bool {
+			head := cn.blockchain.CurrentHeader()
+			return slices.Contains(istBackend.Council(head.Number.Uint64(), head.Hash()), addr)
+		}
+		cn.announceRelay = announce.NewRelay(ctx.NodeKey(), isCouncil)
+		logger.Info("Council announcements are enabled")
+	}
 
 	cn.APIBackend = &CNAPIBackend{cn, nil}
 
@@ -533,6 +718,7 @@ func (s *CN) SetupKaiaxModules() error {
 		mSupply      = supply_impl.NewSupplyModule()
 		mHeaderGov   = headergov_impl.NewHeaderGovModule()
 		mContractGov = contractgov_impl.NewContractGovModule()
+		mRegistryGov = registrygov_impl.NewRegistryGovModule()
 		mGov         = gov_impl.NewGovModule()
 	)
 
@@ -556,39 +742,116 @@ func (s *CN) SetupKaiaxModules() error {
 			RewardModule: mReward,
 		}),
 		mHeaderGov.Init(&headergov_impl.InitOpts{
-			ChainKv:     s.chainDB.GetMiscDB(),
-			ChainConfig: s.chainConfig,
-			Chain:       s.blockchain,
-			NodeAddress: s.nodeAddress,
+			ChainKv:       s.chainDB.GetMiscDB(),
+			ChainConfig:   s.chainConfig,
+			Chain:         s.blockchain,
+			NodeAddress:   s.nodeAddress,
+			StakingModule: mStaking,
 		}),
 		mContractGov.Init(&contractgov_impl.InitOpts{
 			ChainConfig: s.chainConfig,
 			Chain:       s.blockchain,
 			Hgm:         mHeaderGov,
 		}),
+		mRegistryGov.Init(&registrygov_impl.InitOpts{
+			ChainConfig: s.chainConfig,
+			Chain:       s.blockchain,
+		}),
 		mGov.Init(&gov_impl.InitOpts{
-			Hgm:   mHeaderGov,
-			Cgm:   mContractGov,
-			Chain: s.blockchain,
+			Hgm:     mHeaderGov,
+			Cgm:     mContractGov,
+			Chain:   s.blockchain,
+			ChainKv: s.chainDB.GetMiscDB(),
 		}),
 	)
 	if err != nil {
 		return err
 	}
+	mGov.RegisterParamSource(gov_impl.ParamSourceRegistry, mRegistryGov)
 
 	// Register modules to respective components
 	// TODO-kaiax: Organize below lines.
-	s.RegisterBaseModules(mStaking, mReward, mSupply, mGov)
+	s.RegisterBaseModules(mStaking, mReward, mSupply, mRegistryGov, mGov)
 	s.RegisterJsonRpcModules(mStaking, mReward, mSupply, mGov)
 	s.miner.RegisterExecutionModule(mSupply, mGov)
 	s.blockchain.RegisterExecutionModule(mSupply, mGov)
 	s.blockchain.RegisterRewindableModule(mStaking, mSupply, mGov)
+	s.blockchain.RegisterTxProcessModule(mGov)
+	s.miner.SetBlobGasLimiter(mGov)
+	if txPool, ok := s.txPool.(kaiax.TxPoolModuleHost); ok {
+		txPool.RegisterTxPoolModule(mGov)
+	}
 	if engine, ok := s.engine.(consensus.Istanbul); ok {
 		engine.RegisterStakingModule(mStaking)
 		engine.RegisterConsensusModule(mReward, mGov)
 	}
 	s.protocolManager.RegisterStakingModule(mStaking)
 
+	var mAbiStore *abistore_impl.AbiStoreModule
+	if s.config.AbiStore {
+		mAbiStore = abistore_impl.NewAbiStoreModule()
+		if err := mAbiStore.Init(&abistore_impl.InitOpts{
+			ChainKv:     s.chainDB.GetMiscDB(),
+			MetadataURL: s.config.AbiStoreMetadataURL,
+			ChainConfig: s.chainConfig,
+			Chain:       s.blockchain,
+		}); err != nil {
+			return err
+		}
+		s.RegisterBaseModules(mAbiStore)
+		s.RegisterJsonRpcModules(mAbiStore)
+	}
+	s.RegisterJsonRpcModules(abistore_impl.NewTxDecoder(mAbiStore))
+
+	if s.config.InvariantCheck {
+		mInvariant := invariant_impl.NewInvariantModule()
+		if err := mInvariant.Init(&invariant_impl.InitOpts{
+			ChainConfig:  s.chainConfig,
+			Chain:        s.blockchain,
+			SupplyModule: mSupply,
+			Action:       s.config.InvariantAction,
+		}); err != nil {
+			return err
+		}
+		s.RegisterBaseModules(mInvariant)
+		s.RegisterJsonRpcModules(mInvariant)
+		// Registered after mSupply, which must have accumulated the block first.
+		s.miner.RegisterExecutionModule(mInvariant)
+		s.blockchain.RegisterExecutionModule(mInvariant)
+	}
+
+	if s.chainConfig.StateExpiryCompatibleBlock != nil {
+		mStateExpiry := stateexpiry_impl.NewStateExpiryModule()
+		if err := mStateExpiry.Init(&stateexpiry_impl.InitOpts{
+			ChainConfig: s.chainConfig,
+			Chain:       s.blockchain,
+		}); err != nil {
+			return err
+		}
+		s.RegisterBaseModules(mStateExpiry)
+		s.RegisterJsonRpcModules(mStateExpiry)
+		s.blockchain.RegisterTxProcessModule(mStateExpiry)
+		if txPool, ok := s.txPool.(kaiax.TxPoolModuleHost); ok {
+			txPool.RegisterTxPoolModule(mStateExpiry)
+		}
+		if engine, ok := s.engine.(consensus.Istanbul); ok {
+			engine.RegisterConsensusModule(mStateExpiry)
+		}
+	}
+
+	if s.config.VerkleShadow {
+		mVerkle := verkle_impl.NewVerkleModule()
+		if err := mVerkle.Init(&verkle_impl.InitOpts{
+			ChainConfig: s.chainConfig,
+			Chain:       s.blockchain,
+		}); err != nil {
+			return err
+		}
+		s.RegisterBaseModules(mVerkle)
+		s.RegisterJsonRpcModules(mVerkle)
+		s.blockchain.RegisterExecutionModule(mVerkle)
+	}
+
 	s.stakingModule = mStaking
 	return nil
 }
@@ -625,9 +888,12 @@ func makeExtraData(extra []byte) []byte {
 func CreateDB(ctx *node.ServiceContext, config *Config, name string) database.DBManager {
 	dbc := &database.DBConfig{
 		Dir: name, DBType: config.DBType, ParallelDBWrite: config.ParallelDBWrite, SingleDB: config.SingleDB, NumStateTrieShards: config.NumStateTrieShards,
-		LevelDBCacheSize: config.LevelDBCacheSize, LevelDBCompression: config.LevelDBCompression,
+		StateTrieShardDirs: config.StateTrieShardDirs, ReadOnly: config.DBReadOnly, LevelDBCacheSize: config.LevelDBCacheSize, LevelDBCompression: config.LevelDBCompression,
 		PebbleDBCacheSize: config.PebbleDBCacheSize, OpenFilesLimit: database.GetOpenFilesLimit(),
 		LevelDBBufferPool: config.LevelDBBufferPool, EnableDBPerfMetrics: config.EnableDBPerfMetrics, RocksDBConfig: &config.RocksDBConfig, DynamoDBConfig: &config.DynamoDBConfig,
+		AncientThreshold: config.AncientThreshold, AncientDir: config.AncientDir,
+		CompactionWindow: config.CompactionWindow, CompactionLatencyLimit: config.CompactionLatencyLimit,
+		CompressedTables: config.CompressedTables,
 	}
 	return ctx.OpenDatabase(dbc)
 }
@@ -702,6 +968,12 @@ func (s *CN) APIs() []rpc.API {
 			Service:   api.NewPrivateDebugAPI(s.APIBackend),
 			Public:    false,
 			IPCOnly:   s.config.DisableUnsafeDebug,
+		}, {
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   api.NewPrivateSimulationAPI(s.APIBackend),
+			Public:    false,
+			IPCOnly:   s.config.DisableUnsafeDebug,
 		},
 	}
 
@@ -756,6 +1028,11 @@ func (s *CN) APIs() []rpc.API {
 			Version:   "1.0",
 			Service:   NewPublicDebugAPI(s),
 			Public:    false,
+		}, {
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   filters.NewPublicFilterDebugAPI(s.APIBackend),
+			Public:    false,
 		}, {
 			Namespace: "debug",
 			Version:   "1.0",
@@ -786,6 +1063,16 @@ func (s *CN) APIs() []rpc.API {
 		},
 	}...)
 
+	if s.privTxRelay != nil {
+		apis = append(apis, s.privTxRelay.APIs()...)
+	}
+	if s.announceRelay != nil {
+		apis = append(apis, s.announceRelay.APIs()...)
+	}
+	if s.fleetBan != nil {
+		apis = append(apis, s.fleetBan.APIs()...)
+	}
+
 	// Append APIs exposed by JsonRpcModules
 	for _, module := range s.jsonRpcModules {
 		apis = append(apis, module.APIs()...)
@@ -857,10 +1144,20 @@ func (s *CN) ReBroadcastTxs(transactions types.Transactions) {
 // Protocols implements node.Service, returning all the currently configured
 // network protocols to start.
 func (s *CN) Protocols() []p2p.Protocol {
-	if s.lesServer == nil {
-		return s.protocolManager.GetSubProtocols()
+	protocols := s.protocolManager.GetSubProtocols()
+	if s.lesServer != nil {
+		protocols = append(protocols, s.lesServer.Protocols()...)
+	}
+	if s.privTxRelay != nil {
+		protocols = append(protocols, s.privTxRelay.Protocols()...)
+	}
+	if s.announceRelay != nil {
+		protocols = append(protocols, s.announceRelay.Protocols()...)
 	}
-	return append(s.protocolManager.GetSubProtocols(), s.lesServer.Protocols()...)
+	if s.fleetBan != nil {
+		protocols = append(protocols, s.fleetBan.Protocols()...)
+	}
+	return protocols
 }
 
 // Start implements node.Service, starting all internal goroutines needed by the
@@ -876,6 +1173,20 @@ func (s *CN) Start(srvr p2p.Server) error {
 	// Start the bloom bits servicing goroutines
 	s.startBloomHandlers()
 
+	if s.loadShedder != nil {
+		s.loadShedder.start()
+	}
+	if s.privTxRelay != nil {
+		s.privTxRelay.Start()
+	}
+	if s.announceRelay != nil {
+		s.announceRelay.Start()
+	}
+	if s.fleetBan != nil {
+		s.fleetBan.Start()
+		rpc.SetClientGuard(s.fleetBan)
+	}
+
 	// Start the RPC service
 	s.netRPCService = api.NewPublicNetAPI(srvr, s.NetVersion())
 
@@ -887,6 +1198,17 @@ func (s *CN) Start(srvr p2p.Server) error {
 		s.lesServer.Start(srvr)
 	}
 
+	// In follower mode, blocks come from the upstream nodes instead of peers
+	if len(s.config.UpstreamEndpoints) > 0 {
+		f, err := follower.New(s.config.UpstreamEndpoints, s.config.UpstreamPollInterval, s.blockchain)
+		if err != nil {
+			return err
+		}
+		s.protocolManager.SetSyncStop(true)
+		s.follower = f
+		s.follower.Start()
+	}
+
 	if !s.chainConfig.IsKaiaForkEnabled(s.blockchain.CurrentBlock().Number()) {
 		reward.StakingManagerSubscribe()
 	}
@@ -898,15 +1220,31 @@ func (s *CN) Start(srvr p2p.Server) error {
 // Kaia protocol.
 func (s *CN) Stop() error {
 	// Stop all the peer-related stuff first.
+	if s.follower != nil {
+		s.follower.Stop()
+	}
 	s.protocolManager.Stop()
 	if s.lesServer != nil {
 		s.lesServer.Stop()
 	}
+	if s.privTxRelay != nil {
+		s.privTxRelay.Stop()
+	}
+	if s.announceRelay != nil {
+		s.announceRelay.Stop()
+	}
+	if s.fleetBan != nil {
+		rpc.SetClientGuard(nil)
+		s.fleetBan.Stop()
+	}
 
 	// Then stop everything else.
 	for _, module := range s.baseModules {
 		module.Stop()
 	}
+	if s.loadShedder != nil {
+		s.loadShedder.stop()
+	}
 	s.bloomIndexer.Close()
 	close(s.closeBloomHandler)
 	s.txPool.Stop()
@@ -24,9 +24,11 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
 	"github.com/kaiachain/kaia/datasync/downloader"
 	"github.com/kaiachain/kaia/node/cn/mocks"
 	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/work"
 	mocks2 "github.com/kaiachain/kaia/work/mocks"
 	"github.com/stretchr/testify/assert"
 )
@@ -59,6 +61,16 @@ func TestCN_CheckSyncMode(t *testing.T) {
 	assert.Equal(t, errCNLightSync, checkSyncMode(c))
 }
 
+func TestCN_CheckProposerBuilt(t *testing.T) {
+	assert.NoError(t, checkProposerBuilt(common.ENDPOINTNODE))
+	assert.NoError(t, checkProposerBuilt(common.PROXYNODE))
+	if work.ProposerBuilt {
+		assert.NoError(t, checkProposerBuilt(common.CONSENSUSNODE))
+	} else {
+		assert.Equal(t, errProposerNotBuilt, checkProposerBuilt(common.CONSENSUSNODE))
+	}
+}
+
 func TestCN_SetEngineType(t *testing.T) {
 	cc := &params.ChainConfig{}
 	originalEngineType := types.EngineType
@@ -23,6 +23,9 @@
 package cn
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/kaiachain/kaia/blockchain"
@@ -150,3 +153,80 @@ func (b *BloomIndexer) Commit() error {
 	}
 	return batch.Write()
 }
+
+// BloomBitsRebuildResult summarizes a bloom bits index rebuild.
+type BloomBitsRebuildResult struct {
+	Sections          uint64   // number of sections regenerated
+	RepairedSections  []uint64 // sections whose stored index differed from the regenerated one
+	MismatchedBlocks  []uint64 // blocks whose header bloom differs from the bloom of their receipts
+	MissingReceiptsAt []uint64 // blocks whose receipts are missing, indexed by their header bloom
+}
+
+// RebuildBloomBits regenerates the bloom bits index of every completed section
+// from the receipts, verifying the bloom of each header against its receipts.
+// Sections whose stored index differs from the regenerated one are overwritten.
+func RebuildBloomBits(db database.DBManager, size uint64) (*BloomBitsRebuildResult, error) {
+	headNumber := db.ReadHeaderNumber(db.ReadHeadBlockHash())
+	if headNumber == nil {
+		return nil, errors.New("head block not found")
+	}
+	result := &BloomBitsRebuildResult{Sections: (*headNumber + 1) / size}
+
+	for section := uint64(0); section < result.Sections; section++ {
+		gen, err := bloombits.NewGenerator(uint(size))
+		if err != nil {
+			return nil, err
+		}
+		var head common.Hash
+		for number := section * size; number < (section+1)*size; number++ {
+			head = db.ReadCanonicalHash(number)
+			header := db.ReadHeader(head, number)
+			if header == nil {
+				return nil, fmt.Errorf("header #%d not found", number)
+			}
+			bloom := header.Bloom
+			if receipts := db.ReadReceipts(head, number); receipts != nil || header.Bloom == (types.Bloom{}) {
+				if bloom = types.CreateBloom(receipts); bloom != header.Bloom {
+					result.MismatchedBlocks = append(result.MismatchedBlocks, number)
+				}
+			} else {
+				result.MissingReceiptsAt = append(result.MissingReceiptsAt, number)
+			}
+			if err := gen.AddBloom(uint(number-section*size), bloom); err != nil {
+				return nil, err
+			}
+		}
+
+		repaired := false
+		batch := db.NewBatch(database.MiscDB)
+		for i := 0; i < types.BloomBitLength; i++ {
+			bits, err := gen.Bitset(uint(i))
+			if err != nil {
+				batch.Release()
+				return nil, err
+			}
+			key := database.BloomBitsKey(uint(i), section, head)
+			stored, err := db.ReadBloomBits(key)
+			if err == nil {
+				if blob, err := bitutil.DecompressBytes(stored, int(size)/8); err == nil && bytes.Equal(blob, bits) {
+					continue
+				}
+			}
+			repaired = true
+			if err := batch.Put(key, bitutil.CompressBytes(bits)); err != nil {
+				batch.Release()
+				return nil, err
+			}
+		}
+		err = batch.Write()
+		batch.Release()
+		if err != nil {
+			return nil, err
+		}
+		if repaired {
+			result.RepairedSections = append(result.RepairedSections, section)
+		}
+		logger.Info("Rebuilt bloom bits section", "section", section, "head", head, "repaired", repaired)
+	}
+	return result, nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebuildBloomBits(t *testing.T) {
+	const size = 8
+	db := database.NewMemoryDBManager()
+
+	// Write 2.5 sections of blocks, each with a log of a distinct address.
+	var head common.Hash
+	for number := uint64(0); number < 2*size+size/2; number++ {
+		receipts := types.Receipts{{
+			Status: types.ReceiptStatusSuccessful,
+			Logs:   []*types.Log{{Address: common.BigToAddress(new(big.Int).SetUint64(number + 1))}},
+		}}
+		header := &types.Header{Number: new(big.Int).SetUint64(number), Bloom: types.CreateBloom(receipts)}
+		if number == 3 {
+			header.Bloom = types.Bloom{} // header not matching its receipts
+		}
+		head = header.Hash()
+		db.WriteHeader(header)
+		db.WriteCanonicalHash(head, number)
+		db.WriteReceipts(head, number, receipts)
+	}
+	db.WriteHeadBlockHash(head)
+
+	result, err := RebuildBloomBits(db, size)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), result.Sections)
+	assert.Equal(t, []uint64{0, 1}, result.RepairedSections)
+	assert.Equal(t, []uint64{3}, result.MismatchedBlocks)
+	assert.Empty(t, result.MissingReceiptsAt)
+
+	// Corrupt a bit vector of the second section, then only that section is repaired.
+	key := database.BloomBitsKey(0, 1, db.ReadCanonicalHash(2*size-1))
+	db.WriteBloomBits(key, []byte{0xde, 0xad})
+
+	result, err = RebuildBloomBits(db, size)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{1}, result.RepairedSections)
+	_, err = db.ReadBloomBits(key)
+	assert.NoError(t, err)
+
+	result, err = RebuildBloomBits(db, size)
+	require.NoError(t, err)
+	assert.Empty(t, result.RepairedSections)
+}
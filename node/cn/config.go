@@ -46,17 +46,21 @@ var logger = log.NewModuleLogger(log.NodeCN)
 // GetDefaultConfig returns default settings for use on the Kaia main net.
 func GetDefaultConfig() *Config {
 	return &Config{
-		SyncMode:             downloader.FullSync,
-		NetworkId:            params.MainnetNetworkId,
-		LevelDBCacheSize:     768,
-		PebbleDBCacheSize:    768,
-		TrieCacheSize:        512,
-		TrieTimeout:          5 * time.Minute,
-		TrieBlockInterval:    blockchain.DefaultBlockInterval,
-		TrieNodeCacheConfig:  *statedb.GetEmptyTrieNodeCacheConfig(),
-		TriesInMemory:        blockchain.DefaultTriesInMemory,
-		LivePruningRetention: blockchain.DefaultLivePruningRetention,
-		GasPrice:             big.NewInt(18 * params.Gkei),
+		SyncMode:               downloader.FullSync,
+		NetworkId:              params.MainnetNetworkId,
+		LevelDBCacheSize:       768,
+		PebbleDBCacheSize:      768,
+		TrieCacheSize:          512,
+		TrieTimeout:            5 * time.Minute,
+		TrieBlockInterval:      blockchain.DefaultBlockInterval,
+		TrieNodeCacheConfig:    *statedb.GetEmptyTrieNodeCacheConfig(),
+		TriesInMemory:          blockchain.DefaultTriesInMemory,
+		LivePruningRetention:   blockchain.DefaultLivePruningRetention,
+		OnlinePruningRetention: blockchain.DefaultOnlinePruningRetention,
+		OnlinePruningInterval:  blockchain.DefaultOnlinePruningInterval,
+		OnlinePruningRate:      blockchain.DefaultOnlinePruningRate,
+		OnlinePruningBloomSize: blockchain.DefaultOnlinePruningBloomSize,
+		GasPrice:               big.NewInt(18 * params.Gkei),
 
 		TxPool: blockchain.DefaultTxPoolConfig,
 		GPO: gasprice.Config{
@@ -70,6 +74,17 @@ func GetDefaultConfig() *Config {
 
 		Istanbul:      *istanbul.DefaultConfig,
 		RPCEVMTimeout: 5 * time.Second,
+
+		LoadShedCPUThreshold:    90,
+		LoadShedMemoryThreshold: 90,
+		LoadShedDiskLatency:     500 * time.Millisecond,
+
+		PrivateTxPoolSize: 4096,
+
+		TxProvenanceSize: 100000,
+
+		FleetBanThreshold: 100,
+		FleetBanTTL:       time.Hour,
 	}
 }
 
@@ -115,12 +130,17 @@ type Config struct {
 	DBType               database.DBType
 	SkipBcVersionCheck   bool `toml:"-"`
 	SingleDB             bool
+	DBReadOnly           bool // Opens the databases read-only, e.g. to attach to the datadir of another node
 	NumStateTrieShards   uint
+	StateTrieShardDirs   []string // Directories to spread the state trie shards over, defaults to inside chaindata
+	CompressedTables     []string // Tables whose large values are compressed with zstd
 	EnableDBPerfMetrics  bool
 	LevelDBCompression   database.LevelDBCompressionType
 	LevelDBBufferPool    bool
 	LevelDBCacheSize     int
 	PebbleDBCacheSize    int
+	AncientThreshold     uint64 // Number of recent blocks kept in the key-value database, 0 disables the ancient store
+	AncientDir           string // Directory of the ancient store, defaults to "ancient" under chaindata
 	DynamoDBConfig       database.DynamoDBConfig
 	RocksDBConfig        database.RocksDBConfig
 	TrieCacheSize        int
@@ -130,10 +150,37 @@ type Config struct {
 	LivePruning          bool
 	LivePruningRetention uint64
 	SenderTxHashIndexing bool
-	ParallelDBWrite      bool
-	TrieNodeCacheConfig  statedb.TrieNodeCacheConfig
-	SnapshotCacheSize    int
-	SnapshotAsyncGen     bool
+
+	// Online state pruning. If enabled, the trie nodes unreachable from the state of the
+	// recent blocks are deleted periodically while the node runs.
+	OnlinePruning          bool
+	OnlinePruningRetention uint64
+	OnlinePruningInterval  time.Duration
+	OnlinePruningRate      int
+	OnlinePruningBloomSize uint64
+
+	// Receipt pruning. If non-zero, the receipts of the blocks older than the given number of
+	// recent blocks are deleted while the node runs.
+	ReceiptRetention uint64
+
+	// State diff. If enabled, the accounts and storage slots changed by each imported block
+	// are recorded and optionally written to the database.
+	StateDiff        bool
+	StateDiffPersist bool
+
+	// State trie journal. If enabled, the in-memory state tries of the recent blocks are
+	// journalled on shutdown and restored on startup, instead of being dropped.
+	TrieJournal bool
+
+	// Scheduled compaction. If CompactionWindow is set, the databases are compacted range by range
+	// during the daily window and the compaction pauses while block import is slower than the limit.
+	CompactionWindow       string
+	CompactionLatencyLimit time.Duration
+
+	ParallelDBWrite     bool
+	TrieNodeCacheConfig statedb.TrieNodeCacheConfig
+	SnapshotCacheSize   int
+	SnapshotAsyncGen    bool
 
 	// Mining-related options
 	ServiceChainSigner common.Address `toml:",omitempty"`
@@ -194,6 +241,65 @@ type Config struct {
 	// Disable option for unsafe debug APIs
 	DisableUnsafeDebug         bool          `toml:",omitempty"`
 	StateRegenerationTimeLimit time.Duration `toml:",omitempty"`
+
+	// Enables the contract ABI store (kaiax/abistore)
+	AbiStore            bool   `toml:",omitempty"`
+	AbiStoreMetadataURL string `toml:",omitempty"` // URL template of verified contract metadata
+
+	// Enables the chain invariant checker (kaiax/invariant)
+	InvariantCheck  bool   `toml:",omitempty"`
+	InvariantAction string `toml:",omitempty"` // "alert" or "halt"
+
+	// Maintains a prototype verkle commitment of the state (kaiax/verkle). Devnets only.
+	VerkleShadow bool `toml:",omitempty"`
+
+	// Commitment scheme of the state tries, and the one the writes are mirrored to in the transition mode.
+	TrieScheme       string `toml:",omitempty"`
+	TrieMirrorScheme string `toml:",omitempty"`
+
+	// Background integrity check of the trie nodes written to the disk, and the re-fetch of the corrupted ones.
+	TrieIntegrityCheckRate uint64 `toml:",omitempty"`
+	TrieIntegrityHeal      bool   `toml:",omitempty"`
+
+	// Load shedding under resource pressure. A threshold of 0 disables the resource.
+	LoadShedding            bool          `toml:",omitempty"`
+	LoadShedCPUThreshold    float64       `toml:",omitempty"` // percent of the CPUs available to the process
+	LoadShedMemoryThreshold float64       `toml:",omitempty"` // percent of the system memory
+	LoadShedDiskLatency     time.Duration `toml:",omitempty"` // fsync latency of the data directory
+
+	// Follower mode. If UpstreamEndpoints is set, blocks are pulled from the
+	// trusted upstream nodes' APIs instead of the p2p block synchronisation.
+	UpstreamEndpoints    []string      `toml:",omitempty"`
+	UpstreamPollInterval time.Duration `toml:",omitempty"`
+
+	// Private transaction relay. The transactions handed off by the partners
+	// are only included in the blocks proposed by this node.
+	PrivateTxPartners []string `toml:",omitempty"` // node ids or node URLs
+	PrivateTxPoolSize int      `toml:",omitempty"`
+
+	// Council announcements. If enabled, the operational announcements signed by
+	// the council members are gossiped among the CNs.
+	AnnounceEnabled bool `toml:",omitempty"`
+
+	// Proposal audit. If enabled, the input of building each block proposed by this node is
+	// persisted so that debug_replayProposal can verify the ordering later.
+	RecordProposals bool `toml:",omitempty"`
+
+	// Transaction provenance. If enabled, where and when each transaction was first
+	// seen is kept node-locally for debugging the transaction propagation.
+	TxProvenance      bool     `toml:",omitempty"`
+	TxProvenanceSize  int      `toml:",omitempty"`
+	TxProvenanceFleet []string `toml:",omitempty"` // RPC endpoints of the operator's other nodes
+
+	// Transaction firewall. The rules rejecting or tagging the incoming transactions by
+	// their calldata are kept in this file, txfirewall.json in the data directory by default.
+	TxFirewallRules string `toml:",omitempty"`
+
+	// Fleet ban sharing. If members are set, the abusive IPs and nodes detected by this node
+	// are shared with the operator's other nodes, and those banned by the fleet are refused.
+	FleetBanMembers   []string      `toml:",omitempty"` // node ids or node URLs
+	FleetBanThreshold uint64        `toml:",omitempty"` // the sum of the report scores to ban a target
+	FleetBanTTL       time.Duration `toml:",omitempty"` // how long the reports of this node last
 }
 
 type configMarshaling struct {
@@ -0,0 +1,134 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"sync"
+
+	"github.com/kaiachain/kaia/blockchain"
+	"github.com/kaiachain/kaia/networks/rpc"
+)
+
+// defaultHandoverBlocks is the number of blocks a draining proposer waits for
+// its own turn before it stops producing blocks anyway.
+const defaultHandoverBlocks = 10
+
+// DrainStatus reports the progress of a drain started by admin_drain.
+// SafeToTerminate becomes true when no RPC call is in-flight and the node no
+// longer participates in block proposal.
+type DrainStatus struct {
+	Draining         bool  `json:"draining"`
+	InflightRequests int64 `json:"inflightRequests"`
+	Mining           bool  `json:"mining"`
+	HandoverDone     bool  `json:"handoverDone"`
+	SafeToTerminate  bool  `json:"safeToTerminate"`
+}
+
+// drainer hands off the proposer duty of a draining node. The miner keeps
+// running until the node has proposed a block in the handover window, so that
+// the drain does not force the other validators into a round change.
+type drainer struct {
+	mu           sync.Mutex
+	started      bool // true while the handover is in progress
+	handoverDone bool
+	quit         chan struct{}
+}
+
+// start begins the drain. It is a no-op if a handover is already in progress.
+func (d *drainer) start(cn *CN, handoverBlocks uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.started {
+		return
+	}
+	rpc.StartDraining()
+
+	if !cn.IsMining() {
+		d.handoverDone = true
+		return
+	}
+	d.started, d.handoverDone = true, false
+	d.quit = make(chan struct{})
+	go d.handover(cn, handoverBlocks, d.quit)
+}
+
+// stop cancels the drain, so that the RPC servers accept new method calls again.
+// A pending handover is abandoned, while a miner already stopped stays stopped.
+func (d *drainer) stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.started {
+		close(d.quit)
+		d.started = false
+	}
+	d.handoverDone = false
+	rpc.StopDraining()
+}
+
+// handover stops the miner right after this node proposes a block, or once
+// handoverBlocks blocks have passed without this node's turn coming up.
+func (d *drainer) handover(cn *CN, handoverBlocks uint64, quit chan struct{}) {
+	headCh := make(chan blockchain.ChainHeadEvent, 10)
+	sub := cn.blockchain.SubscribeChainHeadEvent(headCh)
+	defer sub.Unsubscribe()
+
+	for seen := uint64(0); seen < handoverBlocks; {
+		select {
+		case ev := <-headCh:
+			seen++
+			if author, err := cn.engine.Author(ev.Block.Header()); err == nil && author == cn.nodeAddress {
+				logger.Info("Proposed the last block before drain", "number", ev.Block.NumberU64())
+				seen = handoverBlocks
+			}
+		case <-sub.Err():
+			seen = handoverBlocks
+		case <-quit:
+			logger.Info("Cancelled the handover for drain")
+			return
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// The drain may have been cancelled while waiting for the lock.
+	select {
+	case <-quit:
+		return
+	default:
+	}
+	cn.StopMining()
+	logger.Info("Stopped block proposal for drain")
+	d.started, d.handoverDone = false, true
+}
+
+// status returns the current drain progress.
+func (d *drainer) status(cn *CN) *DrainStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	status := &DrainStatus{
+		Draining:         rpc.IsDraining(),
+		InflightRequests: rpc.InflightCallCount(),
+		Mining:           cn.IsMining(),
+		HandoverDone:     d.handoverDone,
+	}
+	status.SafeToTerminate = status.Draining && status.HandoverDone && !status.Mining && status.InflightRequests == 0
+	return status
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kaiachain/kaia/event"
+	"github.com/kaiachain/kaia/networks/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrainerCancel(t *testing.T) {
+	mockCtrl, mockBlockChain, mockMiner, api := newCNAPIBackend(t)
+	defer mockCtrl.Finish()
+	defer rpc.StopDraining()
+
+	var (
+		cn = api.cn
+		d  drainer
+	)
+	mockMiner.EXPECT().Mining().Return(true).AnyTimes()
+	mockBlockChain.EXPECT().SubscribeChainHeadEvent(gomock.Any()).Return(event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	})).MaxTimes(2)
+
+	// A cancelled drain serves the calls again without stopping the miner.
+	d.start(cn, 10)
+	assert.True(t, d.status(cn).Draining)
+	d.stop()
+	status := d.status(cn)
+	assert.False(t, status.Draining)
+	assert.False(t, status.HandoverDone)
+
+	// The drain can start again after the cancel.
+	d.start(cn, 10)
+	assert.True(t, d.status(cn).Draining)
+	d.stop()
+}
+
+func TestDrainerRestart(t *testing.T) {
+	mockCtrl, _, mockMiner, api := newCNAPIBackend(t)
+	defer mockCtrl.Finish()
+	defer rpc.StopDraining()
+
+	var (
+		cn = api.cn
+		d  drainer
+	)
+	mockMiner.EXPECT().Mining().Return(false).AnyTimes()
+
+	// Without the block proposal, the handover completes at once.
+	d.start(cn, 10)
+	assert.True(t, d.status(cn).SafeToTerminate)
+	d.stop()
+	assert.False(t, d.status(cn).Draining)
+
+	d.start(cn, 10)
+	assert.True(t, d.status(cn).SafeToTerminate)
+}
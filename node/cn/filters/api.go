@@ -381,32 +381,33 @@ func (api *PublicFilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([
 	ctx, cancelFnc := context.WithTimeout(ctx, GetLogsDeadline)
 	defer cancelFnc()
 
-	var filter *Filter
-	if crit.BlockHash != nil {
-		// Block filter requested, construct a single-shot filter
-		filter = NewBlockFilter(api.backend, *crit.BlockHash, crit.Addresses, crit.Topics)
-	} else {
-		// Convert the RPC block numbers into internal representations
-		begin := rpc.LatestBlockNumber.Int64()
-		if crit.FromBlock != nil {
-			begin = crit.FromBlock.Int64()
-		}
-		end := rpc.LatestBlockNumber.Int64()
-		if crit.ToBlock != nil {
-			end = crit.ToBlock.Int64()
-		}
-		// Construct the range filter
-		filter = NewRangeFilter(api.backend, begin, end, crit.Addresses, crit.Topics)
-	}
-
 	// Run the filter and return all the logs
-	logs, err := filter.Logs(ctx)
+	logs, err := newCriteriaFilter(api.backend, crit).Logs(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return returnLogs(logs), err
 }
 
+// newCriteriaFilter returns a block filter if crit has a block hash, and a range filter otherwise.
+func newCriteriaFilter(backend Backend, crit FilterCriteria) *Filter {
+	if crit.BlockHash != nil {
+		// Block filter requested, construct a single-shot filter
+		return NewBlockFilter(backend, *crit.BlockHash, crit.Addresses, crit.Topics)
+	}
+	// Convert the RPC block numbers into internal representations
+	begin := rpc.LatestBlockNumber.Int64()
+	if crit.FromBlock != nil {
+		begin = crit.FromBlock.Int64()
+	}
+	end := rpc.LatestBlockNumber.Int64()
+	if crit.ToBlock != nil {
+		end = crit.ToBlock.Int64()
+	}
+	// Construct the range filter
+	return NewRangeFilter(backend, begin, end, crit.Addresses, crit.Topics)
+}
+
 // UninstallFilter removes the filter with the given filter id.
 func (api *PublicFilterAPI) UninstallFilter(id rpc.ID) bool {
 	api.filtersMu.Lock()
@@ -627,3 +628,17 @@ func decodeTopic(s string) (common.Hash, error) {
 	}
 	return common.BytesToHash(b), err
 }
+
+// PublicFilterDebugAPI offers the plans of log queries for diagnosing their latencies.
+type PublicFilterDebugAPI struct {
+	backend Backend
+}
+
+func NewPublicFilterDebugAPI(backend Backend) *PublicFilterDebugAPI {
+	return &PublicFilterDebugAPI{backend: backend}
+}
+
+// GetLogsPlan returns the access paths that getLogs takes for the criteria, without running the query.
+func (api *PublicFilterDebugAPI) GetLogsPlan(ctx context.Context, crit FilterCriteria) (*QueryPlan, error) {
+	return newCriteriaFilter(api.backend, crit).Plan(ctx)
+}
@@ -39,6 +39,8 @@ import (
 	"github.com/kaiachain/kaia/storage/database"
 )
 
+var errUnknownBlock = errors.New("unknown block")
+
 //go:generate mockgen -destination=mock/backend_mock.go -package=cn github.com/kaiachain/kaia/node/cn/filters Backend
 type Backend interface {
 	ChainDB() database.DBManager
@@ -134,7 +136,7 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 			return nil, err
 		}
 		if header == nil {
-			return nil, errors.New("unknown block")
+			return nil, errUnknownBlock
 		}
 		return f.blockLogs(ctx, header)
 	}
@@ -144,34 +146,31 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 	if header == nil {
 		return nil, nil
 	}
-	head := header.Number.Uint64()
+	begin, end := f.resolveRange(header.Number.Uint64())
+	f.begin = int64(begin)
 
-	if f.begin == -1 {
-		f.begin = int64(head)
-	}
-	end := uint64(f.end)
-	if f.end == -1 {
-		end = head
-	}
-	// Gather all indexed logs, and finish with non indexed ones
-	var (
-		logs []*types.Log
-		err  error
-	)
-	size, sections := f.backend.BloomStatus()
-	if indexed := sections * size; indexed > uint64(f.begin) {
-		if indexed > end {
-			logs, err = f.indexedLogs(ctx, end)
-		} else {
-			logs, err = f.indexedLogs(ctx, indexed-1)
+	// Run the steps of the cheapest plan in order
+	plan := f.plan(begin, end)
+	logger.Trace("Planned log query", "from", begin, "to", end, "steps", plan.Steps)
+
+	var logs []*types.Log
+	for _, step := range plan.Steps {
+		var (
+			found []*types.Log
+			err   error
+		)
+		switch step.Path {
+		case PathBloomBits:
+			found, err = f.indexedLogs(ctx, step.To)
+		default:
+			found, err = f.unindexedLogs(ctx, step.To)
 		}
+		logs = append(logs, found...)
 		if err != nil {
 			return logs, err
 		}
 	}
-	rest, err := f.unindexedLogs(ctx, end)
-	logs = append(logs, rest...)
-	return logs, err
+	return logs, nil
 }
 
 // blockLogs returns the logs matching the filter criteria within a single block.
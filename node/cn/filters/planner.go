@@ -0,0 +1,122 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"context"
+	"math"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/networks/rpc"
+)
+
+// Access paths of a log query.
+const (
+	PathBlock      = "block"      // the logs of a single block
+	PathBloomBits  = "bloombits"  // the bloom bits index, then the blocks it matches
+	PathHeaderScan = "headerScan" // the bloom of every header in the range
+)
+
+// bloomBitsPerItem is the number of bloom bits an address or a topic sets.
+const bloomBitsPerItem = 3
+
+// PlanStep is the access path for a part of the queried range.
+type PlanStep struct {
+	Path string `json:"path"`
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+	Cost uint64 `json:"cost"` // the estimated number of database reads, excluding the matched blocks
+}
+
+// QueryPlan lists the steps that a log query takes in order.
+type QueryPlan struct {
+	Steps []PlanStep `json:"steps"`
+}
+
+// Plan returns the plan of the filter without running it. It returns nil if the chain is empty.
+func (f *Filter) Plan(ctx context.Context) (*QueryPlan, error) {
+	if f.block != (common.Hash{}) {
+		header, err := f.backend.HeaderByHash(ctx, f.block)
+		if err != nil {
+			return nil, err
+		}
+		if header == nil {
+			return nil, errUnknownBlock
+		}
+		num := header.Number.Uint64()
+		return &QueryPlan{Steps: []PlanStep{{Path: PathBlock, From: num, To: num, Cost: 1}}}, nil
+	}
+
+	header, _ := f.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if header == nil {
+		return nil, nil
+	}
+	begin, end := f.resolveRange(header.Number.Uint64())
+	return f.plan(begin, end), nil
+}
+
+// resolveRange returns the range of the filter, taking -1 as the head.
+func (f *Filter) resolveRange(head uint64) (uint64, uint64) {
+	begin, end := uint64(f.begin), uint64(f.end)
+	if f.begin == -1 {
+		begin = head
+	}
+	if f.end == -1 {
+		end = head
+	}
+	return begin, end
+}
+
+// plan picks the cheaper access path for the indexed part of [begin, end], and scans the headers
+// of the rest. The bloom bits index costs a read per bloom bit of the criteria and per section,
+// while scanning costs a read per block. Hence a short range or a filter with many addresses and
+// topics is scanned, and a filter without any criteria is never served by the index.
+func (f *Filter) plan(begin, end uint64) *QueryPlan {
+	plan := &QueryPlan{Steps: []PlanStep{}}
+	if begin > end {
+		return plan
+	}
+
+	size, sections := f.backend.BloomStatus()
+	if indexed := sections * size; indexed > begin {
+		to := min(end, indexed-1)
+		scanCost := to - begin + 1
+		if bitsCost := f.bloomBitsCost(begin, to, size); bitsCost < scanCost {
+			plan.Steps = append(plan.Steps, PlanStep{Path: PathBloomBits, From: begin, To: to, Cost: bitsCost})
+		} else {
+			plan.Steps = append(plan.Steps, PlanStep{Path: PathHeaderScan, From: begin, To: to, Cost: scanCost})
+		}
+		begin = to + 1
+	}
+	if begin <= end {
+		plan.Steps = append(plan.Steps, PlanStep{Path: PathHeaderScan, From: begin, To: end, Cost: end - begin + 1})
+	}
+	return plan
+}
+
+// bloomBitsCost returns the number of bloom bit vectors the matcher retrieves for [begin, end],
+// or MaxUint64 if the criteria do not narrow down the blocks.
+func (f *Filter) bloomBitsCost(begin, end, size uint64) uint64 {
+	items := len(f.addresses)
+	for _, topicList := range f.topics {
+		items += len(topicList)
+	}
+	if items == 0 || size == 0 {
+		return math.MaxUint64
+	}
+	return (end/size - begin/size + 1) * uint64(items*bloomBitsPerItem)
+}
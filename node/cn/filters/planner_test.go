@@ -0,0 +1,95 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/networks/rpc"
+	cn "github.com/kaiachain/kaia/node/cn/filters/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_Plan(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockBackend := cn.NewMockBackend(mockCtrl)
+
+	// Two sections of 4096 blocks are indexed.
+	mockBackend.EXPECT().BloomStatus().Return(uint64(4096), uint64(2)).AnyTimes()
+	mockBackend.EXPECT().HeaderByNumber(ctx, rpc.LatestBlockNumber).Return(&types.Header{Number: big.NewInt(10000)}, nil).AnyTimes()
+
+	testcases := []struct {
+		name       string
+		begin, end int64
+		addresses  []common.Address
+		topics     [][]common.Hash
+		expected   []PlanStep
+	}{
+		{
+			"long range uses the index",
+			0, -1, addrs, nil,
+			[]PlanStep{
+				{Path: PathBloomBits, From: 0, To: 8191, Cost: 12},
+				{Path: PathHeaderScan, From: 8192, To: 10000, Cost: 1809},
+			},
+		},
+		{
+			"short range is scanned",
+			100, 102, addrs, topics,
+			[]PlanStep{{Path: PathHeaderScan, From: 100, To: 102, Cost: 3}},
+		},
+		{
+			"no criteria is scanned",
+			0, 8191, nil, [][]common.Hash{{}},
+			[]PlanStep{{Path: PathHeaderScan, From: 0, To: 8191, Cost: 8192}},
+		},
+		{
+			"unindexed range",
+			9000, -1, addrs, nil,
+			[]PlanStep{{Path: PathHeaderScan, From: 9000, To: 10000, Cost: 1001}},
+		},
+		{
+			"empty range",
+			10, 5, addrs, nil,
+			[]PlanStep{},
+		},
+	}
+	for _, tc := range testcases {
+		plan, err := NewRangeFilter(mockBackend, tc.begin, tc.end, tc.addresses, tc.topics).Plan(ctx)
+		require.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expected, plan.Steps, tc.name)
+	}
+
+	// Block filter
+	hash := common.HexToHash("0x1")
+	mockBackend.EXPECT().HeaderByHash(ctx, hash).Return(&types.Header{Number: big.NewInt(123)}, nil)
+	plan, err := NewBlockFilter(mockBackend, hash, addrs, nil).Plan(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []PlanStep{{Path: PathBlock, From: 123, To: 123, Cost: 1}}, plan.Steps)
+
+	mockBackend.EXPECT().HeaderByHash(ctx, hash).Return(nil, nil)
+	_, err = NewBlockFilter(mockBackend, hash, addrs, nil).Plan(ctx)
+	assert.ErrorIs(t, err, errUnknownBlock)
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package fleetban
+
+import (
+	"github.com/kaiachain/kaia/networks/rpc"
+)
+
+// APIs returns the `fleetban` APIs. They are not public since they reveal and affect
+// whom the fleet serves.
+func (r *Relay) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "fleetban",
+			Version:   "1.0",
+			Service:   NewPrivateFleetBanAPI(r),
+			Public:    false,
+		},
+	}
+}
+
+// PrivateFleetBanAPI provides the APIs to inspect and report the abuses shared in the fleet.
+type PrivateFleetBanAPI struct {
+	r *Relay
+}
+
+func NewPrivateFleetBanAPI(r *Relay) *PrivateFleetBanAPI {
+	return &PrivateFleetBanAPI{r}
+}
+
+// Report reports an abusive target of the kind "ip" or "node" to the fleet. The score is
+// added to the unexpired report of this node on the target, up to 100.
+func (api *PrivateFleetBanAPI) Report(kind, target string, score uint64, reason string) (*Report, error) {
+	return api.r.Report(kind, target, score, reason)
+}
+
+// Entries returns the targets with unexpired reports, the highest score first.
+func (api *PrivateFleetBanAPI) Entries() []*Entry {
+	return api.r.Entries()
+}
+
+// Banned returns whether the target of the kind "ip" or "node" is banned by the fleet.
+func (api *PrivateFleetBanAPI) Banned(kind, target string) (bool, error) {
+	if _, err := NormalizeTarget(kind, target); err != nil {
+		return false, err
+	}
+	return api.r.Banned(kind, target), nil
+}
+
+// Members returns whether each fleet member is connected.
+func (api *PrivateFleetBanAPI) Members() map[string]bool {
+	ret := make(map[string]bool)
+	for id, connected := range api.r.Members() {
+		ret[id.String()] = connected
+	}
+	return ret
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package fleetban
+
+import (
+	"errors"
+)
+
+// Constants to match up protocol versions and messages
+const (
+	FLEETBAN1 = 1
+)
+
+// ProtocolName is the official short name of the `fleetban` protocol used during
+// p2p capability negotiation.
+const ProtocolName = "fleetban"
+
+// ProtocolVersions are the supported versions of the `fleetban` protocol (first
+// is primary).
+var ProtocolVersions = []uint{FLEETBAN1}
+
+// ProtocolLengths are the number of implemented message corresponding to
+// different protocol versions.
+var ProtocolLengths = map[uint]uint64{FLEETBAN1: 1}
+
+// maxMessageSize is the maximum cap on the size of a protocol message.
+const maxMessageSize = 4 * 1024 * 1024
+
+const (
+	ReportsMsg = 0x00
+)
+
+var (
+	errMsgTooLarge    = errors.New("message too long")
+	errDecode         = errors.New("invalid message")
+	errInvalidMsgCode = errors.New("invalid message code")
+
+	ErrNotMember      = errors.New("not signed by a fleet member")
+	ErrMemberTarget   = errors.New("fleet members cannot be reported")
+	ErrAlreadyKnown   = errors.New("already known")
+	ErrExpired        = errors.New("report expired")
+	ErrInvalidReport  = errors.New("invalid report")
+	ErrTooManyReports = errors.New("too many reports from the member")
+)
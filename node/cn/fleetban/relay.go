@@ -0,0 +1,553 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package fleetban
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kaiachain/kaia/log"
+	"github.com/kaiachain/kaia/networks/p2p"
+	"github.com/kaiachain/kaia/networks/p2p/discover"
+	"github.com/rcrowley/go-metrics"
+)
+
+var (
+	logger = log.NewModuleLogger(log.NodeCN)
+
+	receivedCounter = metrics.NewRegisteredCounter("fleetban/received", nil)
+	acceptedCounter = metrics.NewRegisteredCounter("fleetban/accepted", nil)
+	bannedGauge     = metrics.NewRegisteredGauge("fleetban/banned", nil)
+)
+
+const (
+	// maxPerMember is the maximum number of the unexpired reports kept for each member.
+	maxPerMember = 4096
+	// maxReportsPerMsg is the maximum number of the reports sent in a message.
+	maxReportsPerMsg = 1024
+	// pruneInterval is the interval of dropping the expired reports.
+	pruneInterval = time.Minute
+
+	// The scores of the abuses detected locally.
+	rpcMisbehaviorScore  = 10 // e.g. an oversized request
+	peerMisbehaviorScore = 50 // a breach of the p2p protocol
+)
+
+type target struct {
+	kind, target string
+}
+
+// ParseMembers parses the node IDs of the fleet members, given either as hex node IDs or node URLs.
+func ParseMembers(members []string) ([]discover.NodeID, error) {
+	ids := make([]discover.NodeID, 0, len(members))
+	for _, member := range members {
+		if strings.Contains(member, "://") {
+			node, err := discover.ParseNode(member)
+			if err != nil {
+				return nil, fmt.Errorf("invalid fleet member %q: %v", member, err)
+			}
+			ids = append(ids, node.ID)
+			continue
+		}
+		id, err := discover.HexID(member)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fleet member %q: %v", member, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// Relay shares the abusive IP addresses and nodes among the nodes of an operator's fleet over
+// the `fleetban` protocol. Each member reports the abuses it detects with a score and a TTL, and
+// a target is banned while the scores of the unexpired reports add up to the threshold.
+// The protocol is only run with the members, and the reports not signed by a member are dropped.
+type Relay struct {
+	key       *ecdsa.PrivateKey
+	self      discover.NodeID
+	members   map[discover.NodeID]bool
+	threshold uint64
+	ttl       time.Duration
+	onBan     func(kind, target string)
+
+	mu      sync.Mutex
+	peers   map[discover.NodeID]p2p.MsgReadWriter
+	reports map[target]map[discover.NodeID]*Report
+	counts  map[discover.NodeID]int // the number of the targets reported by each member
+	banned  map[target]bool         // the targets banned as of the last change
+
+	quitCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewRelay creates a relay which bans a target once the scores of its reports reach the
+// threshold. The reports of this node last for ttl.
+func NewRelay(key *ecdsa.PrivateKey, members []discover.NodeID, threshold uint64, ttl time.Duration) *Relay {
+	r := &Relay{
+		key:       key,
+		self:      discover.PubkeyID(&key.PublicKey),
+		members:   make(map[discover.NodeID]bool),
+		threshold: threshold,
+		ttl:       ttl,
+		peers:     make(map[discover.NodeID]p2p.MsgReadWriter),
+		reports:   make(map[target]map[discover.NodeID]*Report),
+		counts:    make(map[discover.NodeID]int),
+		banned:    make(map[target]bool),
+		quitCh:    make(chan struct{}),
+	}
+	for _, id := range members {
+		if id != r.self {
+			r.members[id] = true
+		}
+	}
+	return r
+}
+
+// SetBanHandler sets the function called when a target gets banned, e.g. to drop the
+// connected peers. It must be set before the relay starts.
+func (r *Relay) SetBanHandler(fn func(kind, target string)) {
+	r.onBan = fn
+}
+
+func (r *Relay) Start() {
+	r.wg.Add(1)
+	go r.loop()
+}
+
+func (r *Relay) Stop() {
+	close(r.quitCh)
+	r.wg.Wait()
+}
+
+// loop drops the expired reports periodically.
+func (r *Relay) loop() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.prune(time.Now())
+		case <-r.quitCh:
+			return
+		}
+	}
+}
+
+func (r *Relay) prune(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for t, byMember := range r.reports {
+		for id, rep := range byMember {
+			if rep.expired(now) {
+				delete(byMember, id)
+				r.counts[id]--
+			}
+		}
+		if len(byMember) == 0 {
+			delete(r.reports, t)
+		}
+		if r.banned[t] && r.scoreLocked(t, now) < r.threshold {
+			delete(r.banned, t)
+			logger.Info("Fleet ban lifted", "kind", t.kind, "target", t.target)
+		}
+	}
+	bannedGauge.Update(int64(len(r.banned)))
+}
+
+// Protocols returns the `fleetban` protocols to be run with the peers.
+func (r *Relay) Protocols() []p2p.Protocol {
+	protocols := make([]p2p.Protocol, 0, len(ProtocolVersions))
+	for _, version := range ProtocolVersions {
+		protocols = append(protocols, p2p.Protocol{
+			Name:    ProtocolName,
+			Version: version,
+			Length:  ProtocolLengths[version],
+			Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+				return r.runPeer(p.ID(), rw)
+			},
+			RunWithRWs: func(p *p2p.Peer, rws []p2p.MsgReadWriter) error {
+				return r.runPeer(p.ID(), rws[p2p.ConnDefault])
+			},
+		})
+	}
+	return protocols
+}
+
+// Members returns the connection status of each member.
+func (r *Relay) Members() map[discover.NodeID]bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ret := make(map[discover.NodeID]bool)
+	for id := range r.members {
+		_, ret[id] = r.peers[id]
+	}
+	return ret
+}
+
+// Report signs a report of this node on the target and shares it with the members.
+// The score is added to the unexpired report of this node on the target, up to MaxScore,
+// and the TTL is renewed.
+func (r *Relay) Report(kind, targetStr string, score uint64, reason string) (*Report, error) {
+	targetStr, err := NormalizeTarget(kind, targetStr)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+
+	r.mu.Lock()
+	if prev, ok := r.reports[target{kind, targetStr}][r.self]; ok && !prev.expired(now) {
+		score += prev.Score
+	}
+	r.mu.Unlock()
+	if score > MaxScore {
+		score = MaxScore
+	}
+
+	rep := &Report{
+		Kind:    kind,
+		Target:  targetStr,
+		Score:   score,
+		Reason:  reason,
+		Time:    uint64(now.Unix()),
+		Expires: uint64(now.Add(r.ttl).Unix()),
+	}
+	if err := rep.sign(r.key); err != nil {
+		return nil, err
+	}
+	if err := r.add(rep, now); err != nil {
+		return nil, err
+	}
+	r.broadcast([]*Report{rep}, nil)
+	return rep, nil
+}
+
+// report makes a report on an abuse detected locally. Nothing is reported on a target already banned.
+func (r *Relay) report(kind, targetStr string, score uint64, reason string) {
+	if r.Banned(kind, targetStr) {
+		return
+	}
+	if _, err := r.Report(kind, targetStr, score, reason); err != nil && !errors.Is(err, ErrAlreadyKnown) {
+		logger.Debug("Failed to report an abuse to the fleet", "kind", kind, "target", targetStr, "err", err)
+	}
+}
+
+// Banned returns true if the target is banned by the fleet.
+func (r *Relay) Banned(kind, targetStr string) bool {
+	targetStr, err := NormalizeTarget(kind, targetStr)
+	if err != nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.scoreLocked(target{kind, targetStr}, time.Now()) >= r.threshold
+}
+
+// Blocked implements rpc.ClientGuard. The host of an RPC client is an IP address.
+func (r *Relay) Blocked(host string) bool {
+	return r.Banned(KindIP, host)
+}
+
+// Misbehaved implements rpc.ClientGuard. The loopback clients are never reported.
+func (r *Relay) Misbehaved(host, reason string) {
+	if ip := net.ParseIP(host); ip != nil && !ip.IsLoopback() {
+		r.report(KindIP, host, rpcMisbehaviorScore, reason)
+	}
+}
+
+// BannedPeer returns true if either the node or the IP address of the peer is banned by the fleet.
+func (r *Relay) BannedPeer(id discover.NodeID, addr net.Addr) bool {
+	if r.Banned(KindNode, id.String()) {
+		return true
+	}
+	if tcp, ok := addr.(*net.TCPAddr); ok {
+		return r.Banned(KindIP, tcp.IP.String())
+	}
+	return false
+}
+
+// PeerMisbehaved reports a peer which breached the p2p protocol.
+func (r *Relay) PeerMisbehaved(id discover.NodeID, reason string) {
+	if len(reason) > maxReasonLength {
+		reason = reason[:maxReasonLength]
+	}
+	r.report(KindNode, id.String(), peerMisbehaviorScore, reason)
+}
+
+// scoreLocked returns the sum of the scores of the unexpired reports on the target.
+func (r *Relay) scoreLocked(t target, now time.Time) uint64 {
+	score := uint64(0)
+	for _, rep := range r.reports[t] {
+		if !rep.expired(now) {
+			score += rep.Score
+		}
+	}
+	return score
+}
+
+// add verifies the report and keeps it until it expires or a later report of the member
+// on the same target replaces it.
+func (r *Relay) add(rep *Report, now time.Time) error {
+	if err := rep.validate(now); err != nil {
+		return err
+	}
+	signer, err := rep.Signer()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidReport, err)
+	}
+	if signer != r.self && !r.members[signer] {
+		return ErrNotMember
+	}
+	if rep.Kind == KindNode {
+		if id, _ := discover.HexID(rep.Target); id == r.self || r.members[id] {
+			return ErrMemberTarget
+		}
+	}
+
+	r.mu.Lock()
+	t := target{rep.Kind, rep.Target}
+	byMember, ok := r.reports[t]
+	if !ok {
+		byMember = make(map[discover.NodeID]*Report)
+		r.reports[t] = byMember
+	}
+	if prev, ok := byMember[signer]; ok {
+		// A report is later if it is made later, or at the same second with a higher score.
+		if rep.Time < prev.Time || (rep.Time == prev.Time && rep.Score <= prev.Score) {
+			r.mu.Unlock()
+			return ErrAlreadyKnown
+		}
+	} else {
+		if r.counts[signer] >= maxPerMember {
+			if len(byMember) == 0 {
+				delete(r.reports, t)
+			}
+			r.mu.Unlock()
+			return ErrTooManyReports
+		}
+		r.counts[signer]++
+	}
+	byMember[signer] = rep
+
+	newlyBanned := !r.banned[t] && r.scoreLocked(t, now) >= r.threshold
+	if newlyBanned {
+		r.banned[t] = true
+		bannedGauge.Update(int64(len(r.banned)))
+	}
+	r.mu.Unlock()
+
+	if newlyBanned {
+		logger.Info("Banned by the fleet", "kind", t.kind, "target", t.target, "reason", rep.Reason)
+		if r.onBan != nil {
+			r.onBan(t.kind, t.target)
+		}
+	}
+	return nil
+}
+
+// Entry is the standing of a target in the fleet.
+type Entry struct {
+	Kind    string          `json:"kind"`
+	Target  string          `json:"target"`
+	Score   uint64          `json:"score"`
+	Banned  bool            `json:"banned"`
+	Reports []*MemberReport `json:"reports"`
+}
+
+// MemberReport is a report with the member who made it.
+type MemberReport struct {
+	Member discover.NodeID `json:"member"`
+	*Report
+}
+
+// Entries returns the targets with unexpired reports, the highest score first.
+func (r *Relay) Entries() []*Entry {
+	now := time.Now()
+	r.mu.Lock()
+	entries := make([]*Entry, 0, len(r.reports))
+	for t, byMember := range r.reports {
+		e := &Entry{Kind: t.kind, Target: t.target}
+		for id, rep := range byMember {
+			if !rep.expired(now) {
+				e.Score += rep.Score
+				e.Reports = append(e.Reports, &MemberReport{id, rep})
+			}
+		}
+		if len(e.Reports) == 0 {
+			continue
+		}
+		e.Banned = e.Score >= r.threshold
+		sort.Slice(e.Reports, func(i, j int) bool { return e.Reports[i].Time < e.Reports[j].Time })
+		entries = append(entries, e)
+	}
+	r.mu.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Score != entries[j].Score {
+			return entries[i].Score > entries[j].Score
+		}
+		if entries[i].Kind != entries[j].Kind {
+			return entries[i].Kind < entries[j].Kind
+		}
+		return entries[i].Target < entries[j].Target
+	})
+	return entries
+}
+
+// unexpiredReports returns the unexpired reports to catch up a member with.
+func (r *Relay) unexpiredReports() []*Report {
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var reports []*Report
+	for _, byMember := range r.reports {
+		for _, rep := range byMember {
+			if !rep.expired(now) {
+				reports = append(reports, rep)
+			}
+		}
+	}
+	return reports
+}
+
+// broadcast sends the reports to every member except the one they came from.
+func (r *Relay) broadcast(reports []*Report, from *discover.NodeID) {
+	r.mu.Lock()
+	peers := make(map[discover.NodeID]p2p.MsgReadWriter, len(r.peers))
+	for id, rw := range r.peers {
+		if from == nil || id != *from {
+			peers[id] = rw
+		}
+	}
+	r.mu.Unlock()
+
+	for id, rw := range peers {
+		if err := sendReports(rw, reports); err != nil {
+			logger.Debug("Failed to send fleet reports", "id", id, "err", err)
+		}
+	}
+}
+
+// sendReports sends the reports in messages of at most maxReportsPerMsg reports.
+func sendReports(rw p2p.MsgWriter, reports []*Report) error {
+	for len(reports) > 0 {
+		n := len(reports)
+		if n > maxReportsPerMsg {
+			n = maxReportsPerMsg
+		}
+		if err := p2p.Send(rw, ReportsMsg, reports[:n]); err != nil {
+			return err
+		}
+		reports = reports[n:]
+	}
+	return nil
+}
+
+func (r *Relay) runPeer(id discover.NodeID, rw p2p.MsgReadWriter) error {
+	if !r.members[id] {
+		// Not a member. Ignore everything from the peer, but leave the connection
+		// to the other protocols.
+		for {
+			msg, err := rw.ReadMsg()
+			if err != nil {
+				return err
+			}
+			msg.Discard()
+		}
+	}
+
+	r.mu.Lock()
+	r.peers[id] = rw
+	r.mu.Unlock()
+	logger.Info("Fleet member connected", "id", id)
+
+	defer func() {
+		r.mu.Lock()
+		delete(r.peers, id)
+		r.mu.Unlock()
+		logger.Info("Fleet member disconnected", "id", id)
+	}()
+
+	// Catch up the new member with the known reports. It is sent asynchronously
+	// since the member does the same at the same time.
+	if reports := r.unexpiredReports(); len(reports) > 0 {
+		go func() {
+			if err := sendReports(rw, reports); err != nil {
+				logger.Debug("Failed to send fleet reports", "id", id, "err", err)
+			}
+		}()
+	}
+
+	for {
+		if err := r.handleMsg(id, rw); err != nil {
+			logger.Debug("Fleet ban message handling failed", "id", id, "err", err)
+			return err
+		}
+	}
+}
+
+func (r *Relay) handleMsg(id discover.NodeID, rw p2p.MsgReadWriter) error {
+	msg, err := rw.ReadMsg()
+	if err != nil {
+		return err
+	}
+	if msg.Size > maxMessageSize {
+		return fmt.Errorf("%w: %v > %v", errMsgTooLarge, msg.Size, maxMessageSize)
+	}
+	defer msg.Discard()
+
+	switch msg.Code {
+	case ReportsMsg:
+		var reports []*Report
+		if err := msg.Decode(&reports); err != nil {
+			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+		}
+		receivedCounter.Inc(int64(len(reports)))
+
+		// The member lists may differ among the nodes while the fleet changes, so
+		// the rejected reports are dropped without disconnecting the member.
+		var fresh []*Report
+		for _, rep := range reports {
+			if err := r.add(rep, time.Now()); err != nil {
+				if !errors.Is(err, ErrAlreadyKnown) {
+					logger.Debug("Dropped a fleet report", "id", id, "kind", rep.Kind, "target", rep.Target, "err", err)
+				}
+				continue
+			}
+			fresh = append(fresh, rep)
+		}
+		if len(fresh) > 0 {
+			acceptedCounter.Inc(int64(len(fresh)))
+			r.broadcast(fresh, &id)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("%w: %v", errInvalidMsgCode, msg.Code)
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package fleetban
+
+import (
+	"crypto/ecdsa"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/networks/p2p"
+	"github.com/kaiachain/kaia/networks/p2p/discover"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func idOf(key *ecdsa.PrivateKey) discover.NodeID {
+	return discover.PubkeyID(&key.PublicKey)
+}
+
+func TestNormalizeTarget(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	id := idOf(key)
+
+	target, err := NormalizeTarget(KindIP, "::ffff:10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", target)
+
+	target, err = NormalizeTarget(KindNode, "0x"+id.String())
+	require.NoError(t, err)
+	assert.Equal(t, id.String(), target)
+
+	_, err = NormalizeTarget(KindIP, "10.0.0")
+	assert.ErrorIs(t, err, ErrInvalidReport)
+	_, err = NormalizeTarget("host", "10.0.0.1")
+	assert.ErrorIs(t, err, ErrInvalidReport)
+}
+
+func TestAdd(t *testing.T) {
+	var (
+		key, _      = crypto.GenerateKey()
+		member, _   = crypto.GenerateKey()
+		outsider, _ = crypto.GenerateKey()
+		r           = NewRelay(key, []discover.NodeID{idOf(member)}, 100, time.Hour)
+		now         = time.Unix(1000, 0)
+		signed      = func(key *ecdsa.PrivateKey, rep *Report) *Report {
+			require.NoError(t, rep.sign(key))
+			return rep
+		}
+	)
+
+	testcases := []struct {
+		rep *Report
+		err error
+	}{
+		{signed(member, &Report{Kind: KindIP, Target: "10.0.0.1", Score: 50, Time: 1000, Expires: 2000}), nil},
+		{signed(member, &Report{Kind: KindIP, Target: "10.0.0.1", Score: 50, Time: 1000, Expires: 2000}), ErrAlreadyKnown},
+		{signed(member, &Report{Kind: KindIP, Target: "10.0.0.1", Score: 60, Time: 900, Expires: 2000}), ErrAlreadyKnown},
+		{signed(outsider, &Report{Kind: KindIP, Target: "10.0.0.1", Score: 50, Time: 1000, Expires: 2000}), ErrNotMember},
+		{signed(member, &Report{Kind: KindNode, Target: idOf(key).String(), Score: 50, Time: 1000, Expires: 2000}), ErrMemberTarget},
+		{signed(member, &Report{Kind: KindIP, Target: "::ffff:10.0.0.1", Score: 50, Time: 1000, Expires: 2000}), ErrInvalidReport},
+		{signed(member, &Report{Kind: KindIP, Target: "10.0.0.2", Score: 0, Time: 1000, Expires: 2000}), ErrInvalidReport},
+		{signed(member, &Report{Kind: KindIP, Target: "10.0.0.2", Score: 101, Time: 1000, Expires: 2000}), ErrInvalidReport},
+		{signed(member, &Report{Kind: KindIP, Target: "10.0.0.2", Score: 1, Time: 2000, Expires: 3000}), ErrInvalidReport},
+		{signed(member, &Report{Kind: KindIP, Target: "10.0.0.2", Score: 1, Time: 1000, Expires: 1000 + 8*24*3600}), ErrInvalidReport},
+		{signed(member, &Report{Kind: KindIP, Target: "10.0.0.2", Score: 1, Time: 500, Expires: 1000}), ErrExpired},
+		{&Report{Kind: KindIP, Target: "10.0.0.2", Score: 1, Time: 1000, Expires: 2000, Signature: make([]byte, 65)}, ErrInvalidReport},
+	}
+	for i, tc := range testcases {
+		assert.ErrorIs(t, r.add(tc.rep, now), tc.err, i)
+	}
+
+	// The scores of the members add up to the threshold.
+	banned := make(chan string, 1)
+	r.SetBanHandler(func(kind, target string) { banned <- target })
+	r.mu.Lock()
+	assert.Equal(t, uint64(50), r.scoreLocked(target{KindIP, "10.0.0.1"}, now))
+	r.mu.Unlock()
+	require.NoError(t, r.add(signed(key, &Report{Kind: KindIP, Target: "10.0.0.1", Score: 50, Time: 1000, Expires: 1500}), now))
+	assert.Equal(t, "10.0.0.1", <-banned)
+
+	// A later report of the member replaces the earlier one.
+	require.NoError(t, r.add(signed(member, &Report{Kind: KindIP, Target: "10.0.0.1", Score: 10, Time: 1050, Expires: 3000}), now))
+	r.mu.Lock()
+	assert.Equal(t, uint64(60), r.scoreLocked(target{KindIP, "10.0.0.1"}, now))
+	r.mu.Unlock()
+
+	r.prune(time.Unix(1500, 0))
+	assert.Len(t, r.reports, 1)
+	assert.Empty(t, r.banned)
+	r.prune(time.Unix(3000, 0))
+	assert.Empty(t, r.reports)
+	assert.Empty(t, r.counts[idOf(member)])
+}
+
+func TestReport(t *testing.T) {
+	var (
+		key, _    = crypto.GenerateKey()
+		member, _ = crypto.GenerateKey()
+		r         = NewRelay(key, []discover.NodeID{idOf(member)}, 100, time.Hour)
+		addr      = &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 32323}
+	)
+
+	// The local abuses add up to the ban.
+	for i := 0; i < 10; i++ {
+		assert.False(t, r.Blocked("10.0.0.1"))
+		r.Misbehaved("10.0.0.1", "content length too large")
+	}
+	assert.True(t, r.Blocked("10.0.0.1"))
+	assert.True(t, r.BannedPeer(idOf(member), addr))
+	assert.False(t, r.Banned(KindNode, idOf(member).String()))
+
+	// The loopback clients and the members are never banned.
+	for i := 0; i < 10; i++ {
+		r.Misbehaved("127.0.0.1", "content length too large")
+		r.PeerMisbehaved(idOf(member), "invalid message")
+	}
+	assert.False(t, r.Blocked("127.0.0.1"))
+	assert.False(t, r.Banned(KindNode, idOf(member).String()))
+
+	_, err := r.Report(KindNode, idOf(key).String(), 10, "")
+	assert.ErrorIs(t, err, ErrMemberTarget)
+	_, err = r.Report(KindNode, "0x1234", 10, "")
+	assert.ErrorIs(t, err, ErrInvalidReport)
+
+	entries := NewPrivateFleetBanAPI(r).Entries()
+	require.Len(t, entries, 1)
+	assert.Equal(t, uint64(100), entries[0].Score)
+	assert.True(t, entries[0].Banned)
+	assert.Equal(t, idOf(key), entries[0].Reports[0].Member)
+}
+
+// connect runs the `fleetban` protocol between the two relays over a pipe.
+func connect(t *testing.T, a, b *Relay) {
+	rwA, rwB := p2p.MsgPipe()
+	t.Cleanup(func() {
+		rwA.Close()
+		rwB.Close()
+	})
+	go a.runPeer(b.self, rwA)
+	go b.runPeer(a.self, rwB)
+}
+
+func waitEntries(t *testing.T, r *Relay, n int) []*Entry {
+	for i := 0; i < 100; i++ {
+		if entries := r.Entries(); len(entries) == n {
+			return entries
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("expected %d entries", n)
+	return nil
+}
+
+func TestShare(t *testing.T) {
+	var (
+		keyA, _     = crypto.GenerateKey()
+		keyB, _     = crypto.GenerateKey()
+		keyC, _     = crypto.GenerateKey()
+		outsider, _ = crypto.GenerateKey()
+		fleet       = []discover.NodeID{idOf(keyA), idOf(keyB), idOf(keyC)}
+		a           = NewRelay(keyA, fleet, 100, time.Hour)
+		b           = NewRelay(keyB, fleet, 100, time.Hour)
+		c           = NewRelay(keyC, fleet, 100, time.Hour)
+		o           = NewRelay(outsider, fleet, 100, time.Hour)
+		abuser      = idOf(outsider).String()
+	)
+
+	// A report made before connecting is sent on the connection.
+	_, err := a.Report(KindNode, abuser, 60, "invalid message")
+	require.NoError(t, err)
+
+	// a - b - c
+	connect(t, a, b)
+	connect(t, b, c)
+	entries := waitEntries(t, c, 1)
+	assert.False(t, entries[0].Banned)
+
+	// A new report is forwarded through b, and the target is banned by every member.
+	_, err = c.Report(KindNode, abuser, 40, "invalid message")
+	require.NoError(t, err)
+	for _, r := range []*Relay{a, b, c} {
+		for i := 0; i < 100 && !r.Banned(KindNode, abuser); i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		assert.True(t, r.Banned(KindNode, abuser))
+	}
+	assert.Equal(t, map[discover.NodeID]bool{idOf(keyA): true, idOf(keyC): true}, b.Members())
+
+	// The reports of a non-member are ignored.
+	connect(t, o, a)
+	_, err = o.Report(KindIP, "10.0.0.1", 100, "")
+	require.NoError(t, err)
+	time.Sleep(50 * time.Millisecond)
+	assert.False(t, a.Banned(KindIP, "10.0.0.1"))
+}
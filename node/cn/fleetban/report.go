@@ -0,0 +1,132 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package fleetban
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/crypto/sha3"
+	"github.com/kaiachain/kaia/networks/p2p/discover"
+	"github.com/kaiachain/kaia/rlp"
+)
+
+const (
+	KindIP   = "ip"   // the target is an IP address of RPC clients or peers
+	KindNode = "node" // the target is the node ID of a peer
+
+	// MaxScore is the maximum score of a report. The scores of the members add up.
+	MaxScore = 100
+
+	maxReasonLength = 128
+
+	// maxTTL is how long a report may last.
+	maxTTL = 7 * 24 * time.Hour
+	// maxClockDrift is how far in the future a report may be made, tolerating unsynced clocks.
+	maxClockDrift = time.Minute
+)
+
+// Report is a fleet member's verdict on an abusive IP address or node. It is signed with the
+// node key of the reporting member, so that it stays authenticated when relayed by the others.
+// A later report of a member on the same target replaces the earlier one.
+type Report struct {
+	Kind      string        `json:"kind"`   // KindIP or KindNode
+	Target    string        `json:"target"` // the IP address or the hex node ID, in the canonical form
+	Score     uint64        `json:"score"`  // 1 to MaxScore
+	Reason    string        `json:"reason"`
+	Time      uint64        `json:"time"`    // Unix time when the report was made
+	Expires   uint64        `json:"expires"` // Unix time when the report expires
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// NormalizeTarget returns the canonical form of the target, so that every member keys it the same.
+func NormalizeTarget(kind, target string) (string, error) {
+	switch kind {
+	case KindIP:
+		ip := net.ParseIP(target)
+		if ip == nil {
+			return "", fmt.Errorf("%w: invalid IP address %q", ErrInvalidReport, target)
+		}
+		return ip.String(), nil
+	case KindNode:
+		id, err := discover.HexID(target)
+		if err != nil {
+			return "", fmt.Errorf("%w: invalid node ID %q", ErrInvalidReport, target)
+		}
+		return id.String(), nil
+	default:
+		return "", fmt.Errorf("%w: kind must be %q or %q", ErrInvalidReport, KindIP, KindNode)
+	}
+}
+
+func (r *Report) sigHash() (h common.Hash) {
+	hw := sha3.NewKeccak256()
+	rlp.Encode(hw, []interface{}{r.Kind, r.Target, r.Score, r.Reason, r.Time, r.Expires})
+	hw.Sum(h[:0])
+	return h
+}
+
+func (r *Report) sign(key *ecdsa.PrivateKey) error {
+	sig, err := crypto.Sign(r.sigHash().Bytes(), key)
+	if err != nil {
+		return err
+	}
+	r.Signature = sig
+	return nil
+}
+
+// Signer returns the node ID of the member which signed the report.
+func (r *Report) Signer() (discover.NodeID, error) {
+	pub, err := crypto.SigToPub(r.sigHash().Bytes(), r.Signature)
+	if err != nil {
+		return discover.NodeID{}, err
+	}
+	return discover.PubkeyID(pub), nil
+}
+
+// expired returns true if the report has expired at the given time.
+func (r *Report) expired(now time.Time) bool {
+	return r.Expires <= uint64(now.Unix())
+}
+
+// validate checks the fields of the report regardless of the signer.
+func (r *Report) validate(now time.Time) error {
+	target, err := NormalizeTarget(r.Kind, r.Target)
+	if err != nil {
+		return err
+	}
+	switch {
+	case target != r.Target:
+		return fmt.Errorf("%w: target %q is not canonical", ErrInvalidReport, r.Target)
+	case r.Score == 0 || r.Score > MaxScore:
+		return fmt.Errorf("%w: score must be 1 to %d", ErrInvalidReport, MaxScore)
+	case len(r.Reason) > maxReasonLength:
+		return fmt.Errorf("%w: reason must be at most %d bytes", ErrInvalidReport, maxReasonLength)
+	case r.Time > uint64(now.Add(maxClockDrift).Unix()):
+		return fmt.Errorf("%w: time %d is in the future", ErrInvalidReport, r.Time)
+	case r.Expires > r.Time+uint64(maxTTL/time.Second):
+		return fmt.Errorf("%w: expires %d is too far from time %d", ErrInvalidReport, r.Expires, r.Time)
+	case r.expired(now):
+		return ErrExpired
+	}
+	return nil
+}
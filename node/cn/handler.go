@@ -46,6 +46,7 @@ import (
 	"github.com/kaiachain/kaia/kaiax/staking"
 	"github.com/kaiachain/kaia/networks/p2p"
 	"github.com/kaiachain/kaia/networks/p2p/discover"
+	"github.com/kaiachain/kaia/node/cn/fleetban"
 	"github.com/kaiachain/kaia/node/cn/snap"
 	"github.com/kaiachain/kaia/params"
 	"github.com/kaiachain/kaia/reward"
@@ -138,6 +139,10 @@ type ProtocolManager struct {
 	syncStop int32
 
 	stakingModule staking.StakingModule
+
+	txProvenance *txProvenance   // nil if disabled
+	txFirewall   *txFirewall     // nil if not set
+	fleetBan     *fleetban.Relay // nil if disabled
 }
 
 // NewProtocolManager returns a new Kaia sub protocol manager. The Kaia sub protocol manages peers capable
@@ -379,6 +384,23 @@ func (pm *ProtocolManager) removePeer(id string) {
 	}
 }
 
+// reportMisbehavior shares the breach of the protocol by the peer with the fleet.
+func (pm *ProtocolManager) reportMisbehavior(p Peer, err error) {
+	if pm.fleetBan != nil {
+		pm.fleetBan.PeerMisbehaved(p.GetP2PPeerID(), err.Error())
+	}
+}
+
+// dropBannedPeers disconnects the peers banned by the fleet.
+func (pm *ProtocolManager) dropBannedPeers() {
+	for id, p := range pm.peers.Peers() {
+		if pm.fleetBan.BannedPeer(p.GetP2PPeerID(), p.GetP2PPeer().RemoteAddr()) {
+			logger.Info("Dropping a peer banned by the fleet", "peer", id)
+			pm.removePeer(id)
+		}
+	}
+}
+
 // getChainID returns the current chain id.
 func (pm *ProtocolManager) getChainID() *big.Int {
 	return pm.blockchain.Config().ChainID
@@ -487,6 +509,10 @@ func (pm *ProtocolManager) handle(p Peer) error {
 	if pm.peers.Len() >= pm.maxPeers && !p.GetP2PPeer().Info().Networks[p2p.ConnDefault].Trusted {
 		return p2p.DiscTooManyPeers
 	}
+	if pm.fleetBan != nil && pm.fleetBan.BannedPeer(p.GetP2PPeerID(), p.GetP2PPeer().RemoteAddr()) {
+		p.GetP2PPeer().Log().Debug("Kaia peer is banned by the fleet")
+		return p2p.DiscUselessPeer
+	}
 	p.GetP2PPeer().Log().Debug("Kaia peer connected", "name", p.GetP2PPeer().Name())
 
 	pm.peerWg.Add(1)
@@ -576,6 +602,7 @@ func (pm *ProtocolManager) handle(p Peer) error {
 		if msg.Size > ProtocolMaxMsgSize {
 			err := errResp(ErrMsgTooLarge, "%v > %v", msg.Size, ProtocolMaxMsgSize)
 			p.GetP2PPeer().Log().Warn("ProtocolManager over max msg size", "err", err)
+			pm.reportMisbehavior(p, err)
 			return err
 		}
 
@@ -613,6 +640,7 @@ func (pm *ProtocolManager) processMsg(msgCh <-chan p2p.Msg, p Peer, addr common.
 		for msg := range msgCh {
 			if err := pm.handleMsg(p, addr, msg); err != nil {
 				p.GetP2PPeer().Log().Error("ProtocolManager failed to handle message", "msg", msg, "err", err)
+				pm.reportMisbehavior(p, err)
 				errCh <- err
 				return
 			}
@@ -1251,6 +1279,12 @@ func handleTxMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
 			continue
 		}
 		p.AddToKnownTxs(tx.Hash())
+		if pm.txProvenance != nil {
+			pm.txProvenance.recordPeer(p.GetID(), tx.Hash())
+		}
+		if pm.txFirewall != nil && pm.txFirewall.checkPeer(p.GetID(), tx) != nil {
+			continue
+		}
 		validTxs = append(validTxs, tx)
 		txReceiveCounter.Inc(1)
 	}
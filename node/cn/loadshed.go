@@ -0,0 +1,312 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/kaiachain/kaia/common/cpulimit"
+	"github.com/kaiachain/kaia/networks/rpc"
+	"github.com/pbnjay/memory"
+)
+
+const (
+	loadSampleInterval = 5 * time.Second
+
+	// A level is raised after escalateAfter samples in a row over a threshold, and
+	// lowered after recoverAfter samples in a row under recoverRatio of every threshold.
+	escalateAfter = 3
+	recoverAfter  = 6
+	recoverRatio  = 0.8
+
+	// txPoolShedDivisor divides the txpool slots at LoadCritical.
+	txPoolShedDivisor = 4
+)
+
+// LoadSample is a measurement of the resources watched by the load shedder.
+type LoadSample struct {
+	CPU         float64       `json:"cpu"`         // percent of the CPUs available to the process
+	Memory      float64       `json:"memory"`      // percent of the system memory used by the process
+	DiskLatency time.Duration `json:"diskLatency"` // fsync latency of the data directory
+}
+
+// LoadStatus is reported by admin_loadStatus and the /health endpoint of the HTTP RPC servers.
+type LoadStatus struct {
+	Level    rpc.LoadLevel `json:"level"`
+	Since    time.Time     `json:"since"`
+	Sample   LoadSample    `json:"sample"`
+	Pressure []string      `json:"pressure"` // resources over their thresholds
+	Shedding []string      `json:"shedding"` // degradations in effect
+}
+
+type pausableIndexer interface {
+	Pause()
+	Resume()
+}
+
+type slotLimiter interface {
+	SetSlotLimits(execSlotsAll, nonExecSlotsAll uint64)
+}
+
+// loadShedder walks the degradation ladder of rpc.LoadLevel as the resource pressure
+// changes, so that an overloaded node sheds optional work instead of falling over.
+//
+//   - LoadElevated: heavy RPC methods (tracing) are rejected.
+//   - LoadHigh: expensive RPC methods (call, estimateGas, getLogs, ...) are rejected
+//     and the bloom bits indexer is paused.
+//   - LoadCritical: the txpool slots are reduced and /health reports unhealthy.
+type loadShedder struct {
+	cpuThreshold         float64
+	memoryThreshold      float64
+	diskLatencyThreshold time.Duration
+	probePath            string // file in the data directory to measure the fsync latency
+
+	indexer         pausableIndexer
+	txPool          slotLimiter
+	execSlotsAll    uint64
+	nonExecSlotsAll uint64
+
+	mu         sync.Mutex
+	level      rpc.LoadLevel
+	since      time.Time
+	sample     LoadSample
+	pressure   []string
+	overCount  int
+	underCount int
+
+	lastCPUTime  time.Duration
+	lastSampleAt time.Time
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newLoadShedder(config *Config, probePath string, indexer pausableIndexer, txPool slotLimiter) *loadShedder {
+	return &loadShedder{
+		cpuThreshold:         config.LoadShedCPUThreshold,
+		memoryThreshold:      config.LoadShedMemoryThreshold,
+		diskLatencyThreshold: config.LoadShedDiskLatency,
+		probePath:            probePath,
+		indexer:              indexer,
+		txPool:               txPool,
+		execSlotsAll:         config.TxPool.ExecSlotsAll,
+		nonExecSlotsAll:      config.TxPool.NonExecSlotsAll,
+		level:                rpc.LoadNormal,
+		since:                time.Now(),
+	}
+}
+
+func (s *loadShedder) start() {
+	s.quit = make(chan struct{})
+	rpc.SetHealthReporter(func() (interface{}, bool) {
+		status := s.status()
+		return status, status.Level < rpc.LoadCritical
+	})
+
+	s.wg.Add(1)
+	go s.loop()
+}
+
+func (s *loadShedder) stop() {
+	close(s.quit)
+	s.wg.Wait()
+
+	rpc.SetHealthReporter(nil)
+	s.mu.Lock()
+	s.setLevel(rpc.LoadNormal)
+	s.mu.Unlock()
+}
+
+func (s *loadShedder) loop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(loadSampleInterval)
+	defer ticker.Stop()
+
+	s.lastCPUTime, _ = cpulimit.ProcessCPUTime()
+	s.lastSampleAt = time.Now()
+	for {
+		select {
+		case <-ticker.C:
+			s.observe(s.measure())
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// measure takes a sample of the resource usage since the previous one.
+func (s *loadShedder) measure() LoadSample {
+	var sample LoadSample
+
+	now := time.Now()
+	if cpuTime, ok := cpulimit.ProcessCPUTime(); ok {
+		if elapsed := now.Sub(s.lastSampleAt); elapsed > 0 {
+			sample.CPU = 100 * float64(cpuTime-s.lastCPUTime) / float64(elapsed) / float64(cpulimit.NumCPU())
+		}
+		s.lastCPUTime = cpuTime
+	}
+	s.lastSampleAt = now
+
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	if total := memory.TotalMemory(); total > 0 {
+		sample.Memory = 100 * float64(memStats.Sys-memStats.HeapReleased) / float64(total)
+	}
+
+	if s.probePath != "" {
+		latency, err := fsyncLatency(s.probePath)
+		if err != nil {
+			logger.Debug("Failed to measure the disk latency", "err", err)
+		}
+		sample.DiskLatency = latency
+	}
+	return sample
+}
+
+// fsyncLatency returns how long it takes to write and sync a page to the file.
+func fsyncLatency(path string) (time.Duration, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	start := time.Now()
+	if _, err := f.WriteAt(make([]byte, 4096), 0); err != nil {
+		return 0, err
+	}
+	if err := f.Sync(); err != nil {
+		return 0, err
+	}
+	return time.Since(start), nil
+}
+
+// observe records the sample and moves one step along the ladder if the pressure persists.
+func (s *loadShedder) observe(sample LoadSample) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sample = sample
+	s.pressure = s.pressure[:0]
+	calm := true
+	check := func(name string, value, threshold float64) {
+		if threshold <= 0 {
+			return
+		}
+		if value >= threshold {
+			s.pressure = append(s.pressure, name)
+		}
+		if value >= threshold*recoverRatio {
+			calm = false
+		}
+	}
+	check("cpu", sample.CPU, s.cpuThreshold)
+	check("memory", sample.Memory, s.memoryThreshold)
+	check("disk", float64(sample.DiskLatency), float64(s.diskLatencyThreshold))
+
+	switch {
+	case len(s.pressure) > 0:
+		s.underCount = 0
+		s.overCount++
+		if s.overCount >= escalateAfter && s.level < rpc.LoadCritical {
+			s.overCount = 0
+			s.setLevel(s.level + 1)
+		}
+	case calm:
+		s.overCount = 0
+		s.underCount++
+		if s.underCount >= recoverAfter && s.level > rpc.LoadNormal {
+			s.underCount = 0
+			s.setLevel(s.level - 1)
+		}
+	default:
+		// Between the recovery and the pressure thresholds; hold the level
+		s.overCount, s.underCount = 0, 0
+	}
+}
+
+// setLevel applies the degradations of the level. The caller must hold s.mu.
+func (s *loadShedder) setLevel(level rpc.LoadLevel) {
+	old := s.level
+	if old == level {
+		return
+	}
+	s.level = level
+	s.since = time.Now()
+	rpc.SetLoadLevel(level)
+
+	if s.indexer != nil && (old >= rpc.LoadHigh) != (level >= rpc.LoadHigh) {
+		if level >= rpc.LoadHigh {
+			s.indexer.Pause()
+		} else {
+			s.indexer.Resume()
+		}
+	}
+	if s.txPool != nil && (old >= rpc.LoadCritical) != (level >= rpc.LoadCritical) {
+		if level >= rpc.LoadCritical {
+			s.txPool.SetSlotLimits(s.execSlotsAll/txPoolShedDivisor, s.nonExecSlotsAll/txPoolShedDivisor)
+		} else {
+			s.txPool.SetSlotLimits(s.execSlotsAll, s.nonExecSlotsAll)
+		}
+	}
+
+	if level > old {
+		logger.Warn("Shedding load under resource pressure", "level", level, "pressure", s.pressure, "sample", s.sample)
+	} else {
+		logger.Info("Restoring service as the load decreased", "level", level, "sample", s.sample)
+	}
+}
+
+// setSlotLimits changes the txpool slots restored once the load decreases.
+// If the txpool is being shed, the reduced slots take effect right away.
+func (s *loadShedder) setSlotLimits(execSlotsAll, nonExecSlotsAll uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.execSlotsAll, s.nonExecSlotsAll = execSlotsAll, nonExecSlotsAll
+	if s.txPool != nil && s.level >= rpc.LoadCritical {
+		s.txPool.SetSlotLimits(s.execSlotsAll/txPoolShedDivisor, s.nonExecSlotsAll/txPoolShedDivisor)
+	}
+}
+
+// status returns the current state of the ladder.
+func (s *loadShedder) status() *LoadStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := &LoadStatus{
+		Level:    s.level,
+		Since:    s.since,
+		Sample:   s.sample,
+		Pressure: append([]string{}, s.pressure...),
+		Shedding: []string{},
+	}
+	if s.level >= rpc.LoadElevated {
+		status.Shedding = append(status.Shedding, "heavyRPC")
+	}
+	if s.level >= rpc.LoadHigh {
+		status.Shedding = append(status.Shedding, "expensiveRPC", "bloomIndexer")
+	}
+	if s.level >= rpc.LoadCritical {
+		status.Shedding = append(status.Shedding, "txPoolSize")
+	}
+	return status
+}
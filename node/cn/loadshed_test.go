@@ -0,0 +1,115 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kaiachain/kaia/networks/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testIndexer struct{ paused bool }
+
+func (i *testIndexer) Pause()  { i.paused = true }
+func (i *testIndexer) Resume() { i.paused = false }
+
+type testSlotLimiter struct{ exec, nonExec uint64 }
+
+func (p *testSlotLimiter) SetSlotLimits(exec, nonExec uint64) { p.exec, p.nonExec = exec, nonExec }
+
+func TestLoadShedderLadder(t *testing.T) {
+	config := GetDefaultConfig()
+	var (
+		indexer = &testIndexer{}
+		txPool  = &testSlotLimiter{config.TxPool.ExecSlotsAll, config.TxPool.NonExecSlotsAll}
+		s       = newLoadShedder(config, "", indexer, txPool)
+
+		overloaded = LoadSample{CPU: 95, Memory: 10}
+		busy       = LoadSample{CPU: 80, Memory: 10} // under the threshold but not calm
+		calm       = LoadSample{CPU: 10, Memory: 10}
+	)
+	defer rpc.SetLoadLevel(rpc.LoadNormal)
+
+	observe := func(sample LoadSample, n int) {
+		for i := 0; i < n; i++ {
+			s.observe(sample)
+		}
+	}
+
+	// Escalate one level per escalateAfter samples under pressure.
+	observe(overloaded, escalateAfter-1)
+	assert.Equal(t, rpc.LoadNormal, s.status().Level)
+	observe(overloaded, 1)
+	assert.Equal(t, rpc.LoadElevated, rpc.GetLoadLevel())
+	assert.Equal(t, []string{"cpu"}, s.status().Pressure)
+	assert.False(t, indexer.paused)
+
+	observe(overloaded, escalateAfter)
+	assert.Equal(t, rpc.LoadHigh, rpc.GetLoadLevel())
+	assert.True(t, indexer.paused)
+	assert.Equal(t, config.TxPool.ExecSlotsAll, txPool.exec)
+
+	observe(overloaded, escalateAfter*2)
+	status := s.status()
+	assert.Equal(t, rpc.LoadCritical, status.Level)
+	assert.Equal(t, []string{"heavyRPC", "expensiveRPC", "bloomIndexer", "txPoolSize"}, status.Shedding)
+	assert.Equal(t, config.TxPool.ExecSlotsAll/txPoolShedDivisor, txPool.exec)
+	assert.Equal(t, config.TxPool.NonExecSlotsAll/txPoolShedDivisor, txPool.nonExec)
+
+	// Busy samples hold the level.
+	observe(busy, recoverAfter*2)
+	assert.Equal(t, rpc.LoadCritical, s.status().Level)
+
+	// Recover one level per recoverAfter calm samples.
+	observe(calm, recoverAfter)
+	assert.Equal(t, rpc.LoadHigh, rpc.GetLoadLevel())
+	assert.Equal(t, config.TxPool.ExecSlotsAll, txPool.exec)
+	assert.True(t, indexer.paused)
+
+	observe(calm, recoverAfter*2)
+	assert.Equal(t, rpc.LoadNormal, rpc.GetLoadLevel())
+	assert.False(t, indexer.paused)
+	assert.Empty(t, s.status().Shedding)
+}
+
+func TestLoadShedderDiskLatency(t *testing.T) {
+	config := GetDefaultConfig()
+	config.LoadShedCPUThreshold = 0
+	config.LoadShedMemoryThreshold = 0
+	s := newLoadShedder(config, filepath.Join(t.TempDir(), "loadshed.probe"), nil, nil)
+	defer rpc.SetLoadLevel(rpc.LoadNormal)
+
+	sample := s.measure()
+	assert.Greater(t, sample.DiskLatency, time.Duration(0))
+
+	// Thresholds of 0 are ignored.
+	for i := 0; i < escalateAfter; i++ {
+		s.observe(LoadSample{CPU: 100, Memory: 100})
+	}
+	assert.Equal(t, rpc.LoadNormal, s.status().Level)
+
+	for i := 0; i < escalateAfter; i++ {
+		s.observe(LoadSample{DiskLatency: time.Second})
+	}
+	status := s.status()
+	require.Equal(t, rpc.LoadElevated, status.Level)
+	assert.Equal(t, []string{"disk"}, status.Pressure)
+}
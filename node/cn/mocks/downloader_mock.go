@@ -150,6 +150,48 @@ func (mr *MockProtocolManagerDownloaderMockRecorder) GetSnapSyncer() *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSnapSyncer", reflect.TypeOf((*MockProtocolManagerDownloader)(nil).GetSnapSyncer))
 }
 
+// HealState mocks base method.
+func (m *MockProtocolManagerDownloader) HealState(arg0 common.Hash) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HealState", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HealState indicates an expected call of HealState.
+func (mr *MockProtocolManagerDownloaderMockRecorder) HealState(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealState", reflect.TypeOf((*MockProtocolManagerDownloader)(nil).HealState), arg0)
+}
+
+// HealStateStatus mocks base method.
+func (m *MockProtocolManagerDownloader) HealStateStatus() *downloader.HealingStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HealStateStatus")
+	ret0, _ := ret[0].(*downloader.HealingStatus)
+	return ret0
+}
+
+// HealStateStatus indicates an expected call of HealStateStatus.
+func (mr *MockProtocolManagerDownloaderMockRecorder) HealStateStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealStateStatus", reflect.TypeOf((*MockProtocolManagerDownloader)(nil).HealStateStatus))
+}
+
+// HealTrieNode mocks base method.
+func (m *MockProtocolManagerDownloader) HealTrieNode(arg0 common.Hash) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HealTrieNode", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HealTrieNode indicates an expected call of HealTrieNode.
+func (mr *MockProtocolManagerDownloaderMockRecorder) HealTrieNode(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealTrieNode", reflect.TypeOf((*MockProtocolManagerDownloader)(nil).HealTrieNode), arg0)
+}
+
 // Progress mocks base method.
 func (m *MockProtocolManagerDownloader) Progress() kaia.SyncProgress {
 	m.ctrl.T.Helper()
@@ -122,6 +122,18 @@ func (mr *MockMinerMockRecorder) RegisterExecutionModule(arg0 ...interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterExecutionModule", reflect.TypeOf((*MockMiner)(nil).RegisterExecutionModule), arg0...)
 }
 
+// SetBlobGasLimiter mocks base method.
+func (m *MockMiner) SetBlobGasLimiter(arg0 work.BlobGasLimiter) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetBlobGasLimiter", arg0)
+}
+
+// SetBlobGasLimiter indicates an expected call of SetBlobGasLimiter.
+func (mr *MockMinerMockRecorder) SetBlobGasLimiter(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBlobGasLimiter", reflect.TypeOf((*MockMiner)(nil).SetBlobGasLimiter), arg0)
+}
+
 // SetExtra mocks base method.
 func (m *MockMiner) SetExtra(arg0 []byte) error {
 	m.ctrl.T.Helper()
@@ -136,6 +148,30 @@ func (mr *MockMinerMockRecorder) SetExtra(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetExtra", reflect.TypeOf((*MockMiner)(nil).SetExtra), arg0)
 }
 
+// SetPrivateTxSource mocks base method.
+func (m *MockMiner) SetPrivateTxSource(arg0 work.PrivateTxSource) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetPrivateTxSource", arg0)
+}
+
+// SetPrivateTxSource indicates an expected call of SetPrivateTxSource.
+func (mr *MockMinerMockRecorder) SetPrivateTxSource(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPrivateTxSource", reflect.TypeOf((*MockMiner)(nil).SetPrivateTxSource), arg0)
+}
+
+// SetRecordProposals mocks base method.
+func (m *MockMiner) SetRecordProposals(arg0 bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetRecordProposals", arg0)
+}
+
+// SetRecordProposals indicates an expected call of SetRecordProposals.
+func (mr *MockMinerMockRecorder) SetRecordProposals(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRecordProposals", reflect.TypeOf((*MockMiner)(nil).SetRecordProposals), arg0)
+}
+
 // Start mocks base method.
 func (m *MockMiner) Start() {
 	m.ctrl.T.Helper()
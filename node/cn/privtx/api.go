@@ -0,0 +1,90 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package privtx
+
+import (
+	"time"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/networks/rpc"
+	"github.com/kaiachain/kaia/rlp"
+)
+
+// handoffTimeout is how long the API waits for the receipt of the partner.
+var handoffTimeout = 5 * time.Second
+
+// APIs returns the `privtx` APIs. They are not public since they hand off transactions
+// bypassing the tx pool.
+func (r *Relay) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "privtx",
+			Version:   "1.0",
+			Service:   NewPrivateTxAPI(r),
+			Public:    false,
+		},
+	}
+}
+
+// PrivateTxAPI provides the APIs to exchange private transactions with the partners.
+type PrivateTxAPI struct {
+	r *Relay
+}
+
+func NewPrivateTxAPI(r *Relay) *PrivateTxAPI {
+	return &PrivateTxAPI{r}
+}
+
+// SendRawTransactions hands off the signed transactions to the partner, given as a node id
+// or a node URL, and returns the receipt signed by the partner.
+func (api *PrivateTxAPI) SendRawTransactions(partner string, encodedTxs []hexutil.Bytes) (*HandoffReceipt, error) {
+	ids, err := ParsePartners([]string{partner})
+	if err != nil {
+		return nil, err
+	}
+	txs := make(types.Transactions, 0, len(encodedTxs))
+	for _, encodedTx := range encodedTxs {
+		tx := new(types.Transaction)
+		if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	return api.r.Handoff(ids[0], txs, handoffTimeout)
+}
+
+// Partners returns whether each partner is connected.
+func (api *PrivateTxAPI) Partners() map[string]bool {
+	ret := make(map[string]bool)
+	for id, connected := range api.r.Partners() {
+		ret[id.String()] = connected
+	}
+	return ret
+}
+
+// Pending returns the hashes of the transactions handed off by the partners, grouped by sender.
+func (api *PrivateTxAPI) Pending() map[common.Address][]common.Hash {
+	ret := make(map[common.Address][]common.Hash)
+	for addr, txs := range api.r.Pool().Pending() {
+		for _, tx := range txs {
+			ret[addr] = append(ret[addr], tx.Hash())
+		}
+	}
+	return ret
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package privtx
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+)
+
+// txLifetime is how long a private transaction is kept if it is not included in a block.
+var txLifetime = 30 * time.Minute
+
+type poolTx struct {
+	tx      *types.Transaction
+	from    common.Address
+	arrival time.Time
+}
+
+// Pool holds the transactions handed off by the partners. They are never gossiped,
+// and are only included in the blocks proposed by this node.
+type Pool struct {
+	signer types.Signer
+	limit  int
+
+	mu  sync.RWMutex
+	txs map[common.Hash]*poolTx
+}
+
+func NewPool(signer types.Signer, limit int) *Pool {
+	return &Pool{
+		signer: signer,
+		limit:  limit,
+		txs:    make(map[common.Hash]*poolTx),
+	}
+}
+
+// add adds a transaction whose nonce is not lower than the given state nonce.
+func (p *Pool) add(tx *types.Transaction, nonceAt func(common.Address) uint64) error {
+	from, err := types.Sender(p.signer, tx)
+	if err != nil {
+		return err
+	}
+	if tx.Nonce() < nonceAt(from) {
+		return ErrNonceTooLow
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.txs[tx.Hash()]; ok {
+		return ErrAlreadyKnown
+	}
+	if len(p.txs) >= p.limit {
+		return ErrPoolFull
+	}
+	p.txs[tx.Hash()] = &poolTx{tx: tx, from: from, arrival: time.Now()}
+	return nil
+}
+
+// Pending returns the transactions grouped by sender and sorted by nonce.
+func (p *Pool) Pending() map[common.Address]types.Transactions {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	pending := make(map[common.Address]types.Transactions)
+	for _, ptx := range p.txs {
+		pending[ptx.from] = append(pending[ptx.from], ptx.tx)
+	}
+	for _, txs := range pending {
+		sort.Sort(types.TxByNonce(txs))
+	}
+	return pending
+}
+
+// Len returns the number of the transactions in the pool.
+func (p *Pool) Len() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return len(p.txs)
+}
+
+// prune drops the transactions which are included or replaced on chain, and the expired ones.
+func (p *Pool) prune(nonceAt func(common.Address) uint64, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for hash, ptx := range p.txs {
+		if ptx.tx.Nonce() < nonceAt(ptx.from) || now.Sub(ptx.arrival) > txLifetime {
+			delete(p.txs, hash)
+		}
+	}
+}
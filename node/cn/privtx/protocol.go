@@ -0,0 +1,69 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package privtx
+
+import (
+	"errors"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+)
+
+// Constants to match up protocol versions and messages
+const (
+	PRIVTX1 = 1
+)
+
+// ProtocolName is the official short name of the `privtx` protocol used during
+// p2p capability negotiation.
+const ProtocolName = "privtx"
+
+// ProtocolVersions are the supported versions of the `privtx` protocol (first
+// is primary).
+var ProtocolVersions = []uint{PRIVTX1}
+
+// ProtocolLengths are the number of implemented message corresponding to
+// different protocol versions.
+var ProtocolLengths = map[uint]uint64{PRIVTX1: 2}
+
+// maxMessageSize is the maximum cap on the size of a protocol message.
+const maxMessageSize = 4 * 1024 * 1024
+
+const (
+	TransactionsMsg   = 0x00
+	HandoffReceiptMsg = 0x01
+)
+
+var (
+	errMsgTooLarge    = errors.New("message too long")
+	errDecode         = errors.New("invalid message")
+	errInvalidMsgCode = errors.New("invalid message code")
+
+	ErrUnknownPartner      = errors.New("not a private tx partner")
+	ErrPartnerNotConnected = errors.New("private tx partner is not connected")
+	ErrHandoffTimeout      = errors.New("timed out waiting for the handoff receipt")
+	ErrInvalidReceipt      = errors.New("handoff receipt is not signed by the partner")
+	ErrNoTransactions      = errors.New("no transactions to hand off")
+	ErrAlreadyKnown        = errors.New("already known")
+	ErrPoolFull            = errors.New("private tx pool is full")
+	ErrNonceTooLow         = errors.New("nonce too low")
+)
+
+// TransactionsPacket hands off transactions to a partner.
+type TransactionsPacket struct {
+	ID  uint64 // Request ID to match up the receipt with
+	Txs []*types.Transaction
+}
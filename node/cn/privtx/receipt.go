@@ -0,0 +1,74 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package privtx
+
+import (
+	"crypto/ecdsa"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/crypto/sha3"
+	"github.com/kaiachain/kaia/networks/p2p/discover"
+	"github.com/kaiachain/kaia/rlp"
+)
+
+// HandoffReceipt is the acknowledgement of a partner for the transactions handed off to it.
+// It is signed with the node key of the partner so that the sender can prove the handoff.
+type HandoffReceipt struct {
+	ID        uint64        `json:"id"`
+	Time      uint64        `json:"time"`   // Unix time when the partner received the transactions
+	Hashes    []common.Hash `json:"hashes"` // Hashes of the transactions in the order they were handed off
+	Errors    []string      `json:"errors"` // Rejection reasons, empty if the transaction is accepted
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// Accepted returns the number of the transactions accepted by the partner.
+func (r *HandoffReceipt) Accepted() int {
+	n := 0
+	for _, e := range r.Errors {
+		if e == "" {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *HandoffReceipt) sigHash() (h common.Hash) {
+	hw := sha3.NewKeccak256()
+	rlp.Encode(hw, []interface{}{r.ID, r.Time, r.Hashes, r.Errors})
+	hw.Sum(h[:0])
+	return h
+}
+
+func (r *HandoffReceipt) sign(key *ecdsa.PrivateKey) error {
+	sig, err := crypto.Sign(r.sigHash().Bytes(), key)
+	if err != nil {
+		return err
+	}
+	r.Signature = sig
+	return nil
+}
+
+// Signer returns the node id which signed the receipt.
+func (r *HandoffReceipt) Signer() (discover.NodeID, error) {
+	pub, err := crypto.SigToPub(r.sigHash().Bytes(), r.Signature)
+	if err != nil {
+		return discover.NodeID{}, err
+	}
+	return discover.PubkeyID(pub), nil
+}
@@ -0,0 +1,339 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package privtx
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kaiachain/kaia/blockchain"
+	"github.com/kaiachain/kaia/blockchain/state"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/event"
+	"github.com/kaiachain/kaia/log"
+	"github.com/kaiachain/kaia/networks/p2p"
+	"github.com/kaiachain/kaia/networks/p2p/discover"
+	"github.com/rcrowley/go-metrics"
+)
+
+var (
+	logger = log.NewModuleLogger(log.NodeCN)
+
+	errRelayStopped = errors.New("private tx relay stopped")
+
+	receivedTxsCounter = metrics.NewRegisteredCounter("privtx/received", nil)
+	acceptedTxsCounter = metrics.NewRegisteredCounter("privtx/accepted", nil)
+	handedOffCounter   = metrics.NewRegisteredCounter("privtx/handedoff", nil)
+)
+
+type blockChain interface {
+	State() (*state.StateDB, error)
+	SubscribeChainHeadEvent(ch chan<- blockchain.ChainHeadEvent) event.Subscription
+}
+
+// ParsePartners parses the node ids of the partners, given either as hex node ids or node URLs.
+func ParsePartners(partners []string) ([]discover.NodeID, error) {
+	ids := make([]discover.NodeID, 0, len(partners))
+	for _, partner := range partners {
+		if strings.Contains(partner, "://") {
+			node, err := discover.ParseNode(partner)
+			if err != nil {
+				return nil, fmt.Errorf("invalid private tx partner %q: %v", partner, err)
+			}
+			ids = append(ids, node.ID)
+			continue
+		}
+		id, err := discover.HexID(partner)
+		if err != nil {
+			return nil, fmt.Errorf("invalid private tx partner %q: %v", partner, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// Relay exchanges transactions with the trusted partners over the `privtx` protocol.
+// The transactions handed off by the partners are kept in the Pool instead of the tx pool,
+// so they are never gossiped. Every handoff is acknowledged with a HandoffReceipt signed
+// by the receiving node.
+type Relay struct {
+	key      *ecdsa.PrivateKey
+	partners map[discover.NodeID]bool
+	pool     *Pool
+	chain    blockChain
+
+	mu       sync.Mutex
+	peers    map[discover.NodeID]p2p.MsgReadWriter
+	requests map[uint64]chan *HandoffReceipt
+	nextID   uint64
+
+	quitCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func NewRelay(key *ecdsa.PrivateKey, partners []discover.NodeID, pool *Pool, chain blockChain) *Relay {
+	r := &Relay{
+		key:      key,
+		partners: make(map[discover.NodeID]bool),
+		pool:     pool,
+		chain:    chain,
+		peers:    make(map[discover.NodeID]p2p.MsgReadWriter),
+		requests: make(map[uint64]chan *HandoffReceipt),
+		quitCh:   make(chan struct{}),
+	}
+	for _, id := range partners {
+		r.partners[id] = true
+	}
+	return r
+}
+
+// Pool returns the pool of the transactions handed off by the partners.
+func (r *Relay) Pool() *Pool {
+	return r.pool
+}
+
+func (r *Relay) Start() {
+	r.wg.Add(1)
+	go r.loop()
+}
+
+func (r *Relay) Stop() {
+	close(r.quitCh)
+	r.wg.Wait()
+}
+
+// loop drops the stale transactions from the pool on every new block.
+func (r *Relay) loop() {
+	defer r.wg.Done()
+
+	headCh := make(chan blockchain.ChainHeadEvent, 10)
+	sub := r.chain.SubscribeChainHeadEvent(headCh)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-headCh:
+			r.pool.prune(r.nonceAt(), time.Now())
+		case <-sub.Err():
+			return
+		case <-r.quitCh:
+			return
+		}
+	}
+}
+
+// nonceAt returns the nonce getter of the current state.
+func (r *Relay) nonceAt() func(common.Address) uint64 {
+	statedb, err := r.chain.State()
+	if err != nil {
+		logger.Warn("Failed to get the state for private txs", "err", err)
+		return func(common.Address) uint64 { return 0 }
+	}
+	return statedb.GetNonce
+}
+
+// Protocols returns the `privtx` protocols to be run with the peers.
+func (r *Relay) Protocols() []p2p.Protocol {
+	protocols := make([]p2p.Protocol, 0, len(ProtocolVersions))
+	for _, version := range ProtocolVersions {
+		protocols = append(protocols, p2p.Protocol{
+			Name:    ProtocolName,
+			Version: version,
+			Length:  ProtocolLengths[version],
+			Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+				return r.runPeer(p.ID(), rw)
+			},
+			RunWithRWs: func(p *p2p.Peer, rws []p2p.MsgReadWriter) error {
+				return r.runPeer(p.ID(), rws[p2p.ConnDefault])
+			},
+		})
+	}
+	return protocols
+}
+
+// Partners returns the connection status of each partner.
+func (r *Relay) Partners() map[discover.NodeID]bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ret := make(map[discover.NodeID]bool)
+	for id := range r.partners {
+		_, ret[id] = r.peers[id]
+	}
+	return ret
+}
+
+// Handoff sends the transactions to the partner and waits for its receipt.
+func (r *Relay) Handoff(partner discover.NodeID, txs types.Transactions, timeout time.Duration) (*HandoffReceipt, error) {
+	if !r.partners[partner] {
+		return nil, ErrUnknownPartner
+	}
+	if len(txs) == 0 {
+		return nil, ErrNoTransactions
+	}
+
+	r.mu.Lock()
+	rw, ok := r.peers[partner]
+	if !ok {
+		r.mu.Unlock()
+		return nil, ErrPartnerNotConnected
+	}
+	r.nextID++
+	id := r.nextID
+	ch := make(chan *HandoffReceipt, 1)
+	r.requests[id] = ch
+	r.mu.Unlock()
+
+	defer func() {
+		r.mu.Lock()
+		delete(r.requests, id)
+		r.mu.Unlock()
+	}()
+
+	if err := p2p.Send(rw, TransactionsMsg, &TransactionsPacket{ID: id, Txs: txs}); err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case receipt := <-ch:
+		if signer, err := receipt.Signer(); err != nil || signer != partner {
+			return nil, ErrInvalidReceipt
+		}
+		if len(receipt.Hashes) != len(txs) || len(receipt.Errors) != len(txs) {
+			return nil, ErrInvalidReceipt
+		}
+		for i, tx := range txs {
+			if receipt.Hashes[i] != tx.Hash() {
+				return nil, ErrInvalidReceipt
+			}
+		}
+		handedOffCounter.Inc(int64(receipt.Accepted()))
+		return receipt, nil
+	case <-timer.C:
+		return nil, ErrHandoffTimeout
+	case <-r.quitCh:
+		return nil, errRelayStopped
+	}
+}
+
+func (r *Relay) runPeer(id discover.NodeID, rw p2p.MsgReadWriter) error {
+	if !r.partners[id] {
+		// Not a partner. Ignore everything from the peer, but leave the connection
+		// to the other protocols.
+		for {
+			msg, err := rw.ReadMsg()
+			if err != nil {
+				return err
+			}
+			msg.Discard()
+		}
+	}
+
+	r.mu.Lock()
+	r.peers[id] = rw
+	r.mu.Unlock()
+	logger.Info("Private tx partner connected", "id", id)
+
+	defer func() {
+		r.mu.Lock()
+		delete(r.peers, id)
+		r.mu.Unlock()
+		logger.Info("Private tx partner disconnected", "id", id)
+	}()
+
+	for {
+		if err := r.handleMsg(rw); err != nil {
+			logger.Debug("Private tx message handling failed", "id", id, "err", err)
+			return err
+		}
+	}
+}
+
+func (r *Relay) handleMsg(rw p2p.MsgReadWriter) error {
+	msg, err := rw.ReadMsg()
+	if err != nil {
+		return err
+	}
+	if msg.Size > maxMessageSize {
+		return fmt.Errorf("%w: %v > %v", errMsgTooLarge, msg.Size, maxMessageSize)
+	}
+	defer msg.Discard()
+
+	switch msg.Code {
+	case TransactionsMsg:
+		var packet TransactionsPacket
+		if err := msg.Decode(&packet); err != nil {
+			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+		}
+		return p2p.Send(rw, HandoffReceiptMsg, r.accept(&packet))
+
+	case HandoffReceiptMsg:
+		var receipt HandoffReceipt
+		if err := msg.Decode(&receipt); err != nil {
+			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+		}
+		r.mu.Lock()
+		ch, ok := r.requests[receipt.ID]
+		r.mu.Unlock()
+		if ok {
+			select {
+			case ch <- &receipt:
+			default:
+			}
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("%w: %v", errInvalidMsgCode, msg.Code)
+	}
+}
+
+// accept adds the handed off transactions to the pool and returns the signed receipt.
+func (r *Relay) accept(packet *TransactionsPacket) *HandoffReceipt {
+	var (
+		nonceAt = r.nonceAt()
+		receipt = &HandoffReceipt{
+			ID:     packet.ID,
+			Time:   uint64(time.Now().Unix()),
+			Hashes: make([]common.Hash, 0, len(packet.Txs)),
+			Errors: make([]string, 0, len(packet.Txs)),
+		}
+	)
+	for _, tx := range packet.Txs {
+		receipt.Hashes = append(receipt.Hashes, tx.Hash())
+		if err := r.pool.add(tx, nonceAt); err != nil {
+			receipt.Errors = append(receipt.Errors, err.Error())
+		} else {
+			receipt.Errors = append(receipt.Errors, "")
+		}
+	}
+
+	receivedTxsCounter.Inc(int64(len(packet.Txs)))
+	acceptedTxsCounter.Inc(int64(receipt.Accepted()))
+	if err := receipt.sign(r.key); err != nil {
+		logger.Error("Failed to sign the handoff receipt", "err", err)
+	}
+	return receipt
+}
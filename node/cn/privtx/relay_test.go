@@ -0,0 +1,175 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package privtx
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/kaiachain/kaia/blockchain"
+	"github.com/kaiachain/kaia/blockchain/state"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/event"
+	"github.com/kaiachain/kaia/networks/p2p"
+	"github.com/kaiachain/kaia/networks/p2p/discover"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSigner = types.LatestSignerForChainID(big.NewInt(1))
+
+type testChain struct {
+	feed event.Feed
+}
+
+func (c *testChain) State() (*state.StateDB, error) {
+	return state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()), nil, nil)
+}
+
+func (c *testChain) SubscribeChainHeadEvent(ch chan<- blockchain.ChainHeadEvent) event.Subscription {
+	return c.feed.Subscribe(ch)
+}
+
+func signedTx(t *testing.T, key *ecdsa.PrivateKey, nonce uint64) *types.Transaction {
+	tx := types.NewTransaction(nonce, common.Address{1}, big.NewInt(1), 21000, big.NewInt(25e9), nil)
+	signed, err := types.SignTx(tx, testSigner, key)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestPool(t *testing.T) {
+	var (
+		key, _  = crypto.GenerateKey()
+		from    = crypto.PubkeyToAddress(key.PublicKey)
+		pool    = NewPool(testSigner, 3)
+		nonceAt = func(common.Address) uint64 { return 1 }
+	)
+
+	assert.ErrorIs(t, pool.add(signedTx(t, key, 0), nonceAt), ErrNonceTooLow)
+	assert.NoError(t, pool.add(signedTx(t, key, 3), nonceAt))
+	assert.NoError(t, pool.add(signedTx(t, key, 1), nonceAt))
+	assert.ErrorIs(t, pool.add(signedTx(t, key, 1), nonceAt), ErrAlreadyKnown)
+	assert.NoError(t, pool.add(signedTx(t, key, 2), nonceAt))
+	assert.ErrorIs(t, pool.add(signedTx(t, key, 4), nonceAt), ErrPoolFull)
+
+	pending := pool.Pending()
+	require.Len(t, pending[from], 3)
+	for i, tx := range pending[from] {
+		assert.Equal(t, uint64(i+1), tx.Nonce())
+	}
+
+	// Included up to nonce 1
+	pool.prune(func(common.Address) uint64 { return 2 }, time.Now())
+	assert.Equal(t, 2, pool.Len())
+
+	// Expired
+	pool.prune(nonceAt, time.Now().Add(txLifetime+time.Second))
+	assert.Equal(t, 0, pool.Len())
+}
+
+func TestReceiptSignature(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	receipt := &HandoffReceipt{ID: 1, Time: 100, Hashes: []common.Hash{{1}}, Errors: []string{""}}
+	require.NoError(t, receipt.sign(key))
+
+	signer, err := receipt.Signer()
+	require.NoError(t, err)
+	assert.Equal(t, discover.PubkeyID(&key.PublicKey), signer)
+	assert.Equal(t, 1, receipt.Accepted())
+
+	receipt.Errors[0] = ErrAlreadyKnown.Error()
+	signer, err = receipt.Signer()
+	require.NoError(t, err)
+	assert.NotEqual(t, discover.PubkeyID(&key.PublicKey), signer)
+}
+
+// connect runs the `privtx` protocol between the two relays over a pipe.
+func connect(t *testing.T, a, b *Relay) {
+	rwA, rwB := p2p.MsgPipe()
+	t.Cleanup(func() {
+		rwA.Close()
+		rwB.Close()
+	})
+	go a.runPeer(discover.PubkeyID(&b.key.PublicKey), rwA)
+	go b.runPeer(discover.PubkeyID(&a.key.PublicKey), rwB)
+}
+
+func waitConnected(t *testing.T, r *Relay, id discover.NodeID) {
+	for i := 0; i < 100; i++ {
+		if r.Partners()[id] {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("partner is not connected")
+}
+
+func TestHandoff(t *testing.T) {
+	var (
+		keyA, _      = crypto.GenerateKey()
+		keyB, _      = crypto.GenerateKey()
+		senderKey, _ = crypto.GenerateKey()
+		idA          = discover.PubkeyID(&keyA.PublicKey)
+		idB          = discover.PubkeyID(&keyB.PublicKey)
+		a            = NewRelay(keyA, []discover.NodeID{idB}, NewPool(testSigner, 10), &testChain{})
+		b            = NewRelay(keyB, []discover.NodeID{idA}, NewPool(testSigner, 10), &testChain{})
+		txs          = types.Transactions{signedTx(t, senderKey, 0), signedTx(t, senderKey, 1)}
+	)
+
+	_, err := a.Handoff(idB, txs, time.Second)
+	assert.ErrorIs(t, err, ErrPartnerNotConnected)
+	_, err = a.Handoff(idA, txs, time.Second)
+	assert.ErrorIs(t, err, ErrUnknownPartner)
+
+	connect(t, a, b)
+	waitConnected(t, a, idB)
+
+	receipt, err := a.Handoff(idB, txs, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, 2, receipt.Accepted())
+	assert.Equal(t, 2, b.Pool().Len())
+	assert.Equal(t, 0, a.Pool().Len())
+
+	// Handing off the same transactions again is acknowledged with the errors.
+	receipt, err = a.Handoff(idB, txs, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, 0, receipt.Accepted())
+	assert.Equal(t, []string{ErrAlreadyKnown.Error(), ErrAlreadyKnown.Error()}, receipt.Errors)
+}
+
+func TestHandoffToNonPartner(t *testing.T) {
+	var (
+		keyA, _ = crypto.GenerateKey()
+		keyB, _ = crypto.GenerateKey()
+		idB     = discover.PubkeyID(&keyB.PublicKey)
+		a       = NewRelay(keyA, []discover.NodeID{idB}, NewPool(testSigner, 10), &testChain{})
+		b       = NewRelay(keyB, nil, NewPool(testSigner, 10), &testChain{})
+	)
+
+	connect(t, a, b)
+	waitConnected(t, a, idB)
+
+	// b does not trust a, so the transactions are dropped without a receipt.
+	_, err := a.Handoff(idB, types.Transactions{signedTx(t, keyA, 0)}, 100*time.Millisecond)
+	assert.ErrorIs(t, err, ErrHandoffTimeout)
+	assert.Equal(t, 0, b.Pool().Len())
+}
@@ -142,6 +142,9 @@ type ProtocolManagerDownloader interface {
 	GetSnapSyncer() *snap.Syncer
 	SyncStakingInfo(id string, from, to uint64) error
 	SyncStakingInfoStatus() *downloader.SyncingStatus
+	HealState(root common.Hash) error
+	HealTrieNode(hash common.Hash) error
+	HealStateStatus() *downloader.HealingStatus
 }
 
 // ProtocolManagerFetcher is an interface of fetcher.Fetcher used by ProtocolManager.
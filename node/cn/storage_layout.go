@@ -0,0 +1,255 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/crypto"
+)
+
+const (
+	maxLayoutDepth    = 32      // Maximum nesting of the types decoded by a storage layout
+	maxLayoutBytesLen = 1 << 20 // Maximum length of a bytes or string value decoded by a storage layout
+)
+
+var (
+	errLayoutTooDeep      = errors.New("storage layout nested too deep")
+	errLayoutBytesTooLong = errors.New("bytes value too long to decode")
+)
+
+// StorageLayout is the storage layout of a contract as emitted by `solc --storage-layout`.
+type StorageLayout struct {
+	Storage []StorageLayoutEntry         `json:"storage"`
+	Types   map[string]StorageLayoutType `json:"types"`
+}
+
+// StorageLayoutEntry is a state variable or a struct member in a storage layout.
+type StorageLayoutEntry struct {
+	Label  string `json:"label"`
+	Offset uint64 `json:"offset"`
+	Slot   string `json:"slot"`
+	Type   string `json:"type"`
+}
+
+// StorageLayoutType describes how a type is encoded in the storage.
+type StorageLayoutType struct {
+	Encoding      string               `json:"encoding"` // inplace, mapping, dynamic_array or bytes
+	Label         string               `json:"label"`
+	NumberOfBytes string               `json:"numberOfBytes"`
+	Base          string               `json:"base,omitempty"`  // element type of arrays
+	Key           string               `json:"key,omitempty"`   // key type of mappings
+	Value         string               `json:"value,omitempty"` // value type of mappings
+	Members       []StorageLayoutEntry `json:"members,omitempty"`
+}
+
+// Decode returns the state variables decoded from the storage read by the given function.
+// Mappings cannot be enumerated and are decoded as nil, and at most maxElems elements of
+// each dynamic array are decoded.
+func (l *StorageLayout) Decode(read func(slot common.Hash) common.Hash, maxElems uint64) (map[string]any, error) {
+	d := &layoutDecoder{types: l.Types, read: read, maxElems: maxElems}
+	return d.decodeEntries(common.Big0, l.Storage, 0)
+}
+
+type layoutDecoder struct {
+	types    map[string]StorageLayoutType
+	read     func(slot common.Hash) common.Hash
+	maxElems uint64
+}
+
+func (d *layoutDecoder) decodeEntries(base *big.Int, entries []StorageLayoutEntry, depth int) (map[string]any, error) {
+	ret := make(map[string]any, len(entries))
+	for _, e := range entries {
+		slot, ok := new(big.Int).SetString(e.Slot, 10)
+		if !ok {
+			return nil, fmt.Errorf("%s: invalid slot %q", e.Label, e.Slot)
+		}
+		v, err := d.decode(slot.Add(slot, base), e.Offset, e.Type, depth)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Label, err)
+		}
+		ret[e.Label] = v
+	}
+	return ret, nil
+}
+
+func (d *layoutDecoder) decode(slot *big.Int, offset uint64, typ string, depth int) (any, error) {
+	if depth > maxLayoutDepth {
+		return nil, errLayoutTooDeep
+	}
+	t, ok := d.types[typ]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %q", typ)
+	}
+
+	switch t.Encoding {
+	case "mapping":
+		return nil, nil
+	case "bytes":
+		return d.decodeBytes(slot, t.Label)
+	case "dynamic_array":
+		length := d.read(common.BigToHash(slot)).Big()
+		count := length.Uint64()
+		if !length.IsUint64() || count > d.maxElems {
+			count = d.maxElems
+		}
+		elems, err := d.decodeElems(d.dataSlot(slot), t.Base, count, depth)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"length": length.String(), "elements": elems}, nil
+	case "inplace":
+		switch {
+		case len(t.Members) > 0:
+			return d.decodeEntries(slot, t.Members, depth+1)
+		case t.Base != "":
+			count, err := staticArrayLen(typ)
+			if err != nil {
+				return nil, err
+			}
+			return d.decodeElems(slot, t.Base, count, depth)
+		default:
+			size, err := t.size()
+			if err != nil {
+				return nil, err
+			}
+			if size == 0 || offset+size > 32 {
+				return nil, fmt.Errorf("invalid offset %d of type %q", offset, typ)
+			}
+			word := d.read(common.BigToHash(slot))
+			return decodeValue(word[32-offset-size:32-offset], t.Label), nil
+		}
+	default:
+		return nil, fmt.Errorf("unknown encoding %q", t.Encoding)
+	}
+}
+
+// decodeElems decodes the array elements starting at slot. Elements of up to 16 bytes
+// are packed in a slot, and the others start at a new slot.
+func (d *layoutDecoder) decodeElems(slot *big.Int, elemType string, count uint64, depth int) ([]any, error) {
+	t, ok := d.types[elemType]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %q", elemType)
+	}
+	size, err := t.size()
+	if err != nil {
+		return nil, err
+	}
+	if size == 0 {
+		return nil, fmt.Errorf("invalid size of type %q", elemType)
+	}
+
+	ret := make([]any, 0, count)
+	for i := uint64(0); i < count; i++ {
+		var elemSlot *big.Int
+		var offset uint64
+		if size <= 16 {
+			perSlot := 32 / size
+			elemSlot = new(big.Int).Add(slot, new(big.Int).SetUint64(i/perSlot))
+			offset = (i % perSlot) * size
+		} else {
+			slots := new(big.Int).SetUint64((size + 31) / 32)
+			elemSlot = new(big.Int).Add(slot, slots.Mul(slots, new(big.Int).SetUint64(i)))
+		}
+		v, err := d.decode(elemSlot, offset, elemType, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, v)
+	}
+	return ret, nil
+}
+
+// decodeBytes decodes a bytes or string value. A value shorter than 32 bytes is stored in the
+// slot with twice its length in the lowest byte. Otherwise, the slot holds twice its length
+// plus one, and the value is stored from keccak256(slot).
+func (d *layoutDecoder) decodeBytes(slot *big.Int, label string) (any, error) {
+	word := d.read(common.BigToHash(slot))
+
+	var data []byte
+	if word[31]&1 == 0 {
+		data = common.CopyBytes(word[:word[31]/2])
+	} else {
+		length := new(big.Int).Rsh(word.Big(), 1)
+		if !length.IsUint64() || length.Uint64() > maxLayoutBytesLen {
+			return nil, errLayoutBytesTooLong
+		}
+		n := length.Uint64()
+		data = make([]byte, 0, n+31)
+		start := d.dataSlot(slot)
+		for i := uint64(0); uint64(len(data)) < n; i++ {
+			chunk := d.read(common.BigToHash(new(big.Int).Add(start, new(big.Int).SetUint64(i))))
+			data = append(data, chunk[:]...)
+		}
+		data = data[:n]
+	}
+
+	if label == "string" {
+		return string(data), nil
+	}
+	return hexutil.Bytes(data), nil
+}
+
+// dataSlot returns the slot where the data of a dynamic array or a long bytes value starts.
+func (d *layoutDecoder) dataSlot(slot *big.Int) *big.Int {
+	return crypto.Keccak256Hash(common.BigToHash(slot).Bytes()).Big()
+}
+
+func (t StorageLayoutType) size() (uint64, error) {
+	size, err := strconv.ParseUint(t.NumberOfBytes, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid numberOfBytes %q of type %q", t.NumberOfBytes, t.Label)
+	}
+	return size, nil
+}
+
+// staticArrayLen parses the length of a static array type such as t_array(t_uint256)3_storage.
+func staticArrayLen(typ string) (uint64, error) {
+	s := typ[strings.LastIndex(typ, ")")+1:]
+	s = strings.TrimSuffix(s, "_storage")
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid static array type %q", typ)
+	}
+	return n, nil
+}
+
+// decodeValue decodes a value type by its label. Integers are decoded as decimal strings.
+func decodeValue(b []byte, label string) any {
+	switch {
+	case label == "bool":
+		return b[len(b)-1] != 0
+	case label == "address" || label == "address payable" || strings.HasPrefix(label, "contract "):
+		return common.BytesToAddress(b)
+	case strings.HasPrefix(label, "uint") || strings.HasPrefix(label, "enum "):
+		return new(big.Int).SetBytes(b).String()
+	case strings.HasPrefix(label, "int"):
+		v := new(big.Int).SetBytes(b)
+		if len(b) > 0 && b[0]&0x80 != 0 {
+			v.Sub(v, new(big.Int).Lsh(common.Big1, uint(len(b)*8)))
+		}
+		return v.String()
+	default:
+		return hexutil.Bytes(common.CopyBytes(b))
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testLayout is the storage layout of the following contract.
+//
+//	contract Test {
+//	    uint256 total;                        // slot 0
+//	    uint8 small; address owner; bool on;  // slot 1
+//	    string name;                          // slot 2
+//	    string note;                          // slot 3
+//	    struct Pair { int64 a; uint256 b; }
+//	    Pair pair;                            // slot 4-5
+//	    uint32[3] fixedArr;                   // slot 6
+//	    uint256[] dynArr;                     // slot 7
+//	    mapping(address => uint256) balances; // slot 8
+//	}
+const testLayout = `{
+  "storage": [
+    {"label": "total", "offset": 0, "slot": "0", "type": "t_uint256"},
+    {"label": "small", "offset": 0, "slot": "1", "type": "t_uint8"},
+    {"label": "owner", "offset": 1, "slot": "1", "type": "t_address"},
+    {"label": "on", "offset": 21, "slot": "1", "type": "t_bool"},
+    {"label": "name", "offset": 0, "slot": "2", "type": "t_string_storage"},
+    {"label": "note", "offset": 0, "slot": "3", "type": "t_string_storage"},
+    {"label": "pair", "offset": 0, "slot": "4", "type": "t_struct(Pair)_storage"},
+    {"label": "fixedArr", "offset": 0, "slot": "6", "type": "t_array(t_uint32)3_storage"},
+    {"label": "dynArr", "offset": 0, "slot": "7", "type": "t_array(t_uint256)dyn_storage"},
+    {"label": "balances", "offset": 0, "slot": "8", "type": "t_mapping(t_address,t_uint256)"}
+  ],
+  "types": {
+    "t_address": {"encoding": "inplace", "label": "address", "numberOfBytes": "20"},
+    "t_bool": {"encoding": "inplace", "label": "bool", "numberOfBytes": "1"},
+    "t_int64": {"encoding": "inplace", "label": "int64", "numberOfBytes": "8"},
+    "t_uint8": {"encoding": "inplace", "label": "uint8", "numberOfBytes": "1"},
+    "t_uint32": {"encoding": "inplace", "label": "uint32", "numberOfBytes": "4"},
+    "t_uint256": {"encoding": "inplace", "label": "uint256", "numberOfBytes": "32"},
+    "t_string_storage": {"encoding": "bytes", "label": "string", "numberOfBytes": "32"},
+    "t_struct(Pair)_storage": {"encoding": "inplace", "label": "struct Test.Pair", "numberOfBytes": "64", "members": [
+      {"label": "a", "offset": 0, "slot": "0", "type": "t_int64"},
+      {"label": "b", "offset": 0, "slot": "1", "type": "t_uint256"}
+    ]},
+    "t_array(t_uint32)3_storage": {"encoding": "inplace", "label": "uint32[3]", "numberOfBytes": "32", "base": "t_uint32"},
+    "t_array(t_uint256)dyn_storage": {"encoding": "dynamic_array", "label": "uint256[]", "numberOfBytes": "32", "base": "t_uint256"},
+    "t_mapping(t_address,t_uint256)": {"encoding": "mapping", "label": "mapping(address => uint256)", "numberOfBytes": "32", "key": "t_address", "value": "t_uint256"}
+  }
+}`
+
+func TestStorageLayoutDecode(t *testing.T) {
+	var (
+		storage = make(map[common.Hash]common.Hash)
+		owner   = common.HexToAddress("0x000000000000000000000000000000000000abcd")
+		note    = strings.Repeat("long note ", 5)
+		slot    = func(n int64) common.Hash { return common.BigToHash(big.NewInt(n)) }
+		data    = func(n int64) *big.Int { return crypto.Keccak256Hash(slot(n).Bytes()).Big() }
+	)
+
+	storage[slot(0)] = slot(1000)
+
+	var packed common.Hash
+	packed[31] = 7
+	copy(packed[11:31], owner.Bytes())
+	packed[10] = 1
+	storage[slot(1)] = packed
+
+	var name common.Hash
+	copy(name[:], "kaia")
+	name[31] = 4 * 2
+	storage[slot(2)] = name
+
+	storage[slot(3)] = common.BigToHash(big.NewInt(int64(len(note)*2 + 1)))
+	storage[common.BigToHash(data(3))] = common.BytesToHash([]byte(note[:32]))
+	var tail common.Hash
+	copy(tail[:], note[32:])
+	storage[common.BigToHash(new(big.Int).Add(data(3), common.Big1))] = tail
+
+	storage[slot(4)] = common.BytesToHash(common.FromHex("0xfffffffffffffffe")) // -2
+	storage[slot(5)] = slot(42)
+
+	storage[slot(6)] = common.BytesToHash(common.FromHex("0x000000030000000200000001"))
+
+	storage[slot(7)] = slot(3)
+	for i := int64(0); i < 3; i++ {
+		storage[common.BigToHash(new(big.Int).Add(data(7), big.NewInt(i)))] = slot(10 + i)
+	}
+
+	var layout StorageLayout
+	require.NoError(t, json.Unmarshal([]byte(testLayout), &layout))
+	read := func(slot common.Hash) common.Hash { return storage[slot] }
+
+	decoded, err := layout.Decode(read, 2)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"total":    "1000",
+		"small":    "7",
+		"owner":    owner,
+		"on":       true,
+		"name":     "kaia",
+		"note":     note,
+		"pair":     map[string]any{"a": "-2", "b": "42"},
+		"fixedArr": []any{"1", "2", "3"},
+		"dynArr":   map[string]any{"length": "3", "elements": []any{"10", "11"}},
+		"balances": nil,
+	}, decoded)
+
+	// Unknown types are reported with the variable.
+	layout.Storage = append(layout.Storage, StorageLayoutEntry{Label: "bad", Slot: "9", Type: "t_unknown"})
+	_, err = layout.Decode(read, 2)
+	assert.ErrorContains(t, err, "bad: unknown type")
+}
+
+func TestDecodeValue(t *testing.T) {
+	assert.Equal(t, "-1", decodeValue([]byte{0xff}, "int8"))
+	assert.Equal(t, "255", decodeValue([]byte{0xff}, "uint8"))
+	assert.Equal(t, "2", decodeValue([]byte{0x02}, "enum Test.State"))
+	assert.Equal(t, hexutil.Bytes{0x12, 0x34}, decodeValue([]byte{0x12, 0x34}, "bytes2"))
+}
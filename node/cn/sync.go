@@ -23,6 +23,7 @@
 package cn
 
 import (
+	"errors"
 	"math/big"
 	"math/rand"
 	"sync/atomic"
@@ -33,6 +34,7 @@ import (
 	"github.com/kaiachain/kaia/datasync/downloader"
 	"github.com/kaiachain/kaia/networks/p2p/discover"
 	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/storage/statedb"
 )
 
 const (
@@ -195,6 +197,21 @@ func (pm *ProtocolManager) getSyncMode(currentBlock *types.Block) downloader.Syn
 	return downloader.FullSync
 }
 
+// healMissingState starts healing the state of the head block in the background
+// if the sync failed on a missing trie node, e.g. left by an interrupted fast sync.
+func (pm *ProtocolManager) healMissingState(err error) {
+	var missing *statedb.MissingNodeError
+	if !errors.As(err, &missing) {
+		return
+	}
+	root := pm.blockchain.CurrentBlock().Root()
+	if err := pm.downloader.HealState(root); err != nil {
+		logger.Debug("Failed to start state healing", "root", root, "missing", missing.NodeHash, "err", err)
+		return
+	}
+	logger.Warn("Missing trie node found, healing the state", "root", root, "missing", missing.NodeHash)
+}
+
 // synchronise tries to sync up our local block chain with a remote peer.
 func (pm *ProtocolManager) synchronise(peer Peer) {
 	// Short circuit if no peers are available or syncStop flag is set to true
@@ -220,6 +237,7 @@ func (pm *ProtocolManager) synchronise(peer Peer) {
 
 	// Run the sync cycle, and disable fast sync if we've went past the pivot block
 	if err := pm.downloader.Synchronise(peer.GetID(), pHead, pTd, mode); err != nil {
+		pm.healMissingState(err)
 		return
 	}
 	if atomic.LoadUint32(&pm.fastSync) == 1 {
@@ -29,7 +29,6 @@ import (
 	"math/big"
 	"os"
 	"reflect"
-	"runtime"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -40,6 +39,7 @@ import (
 	"github.com/kaiachain/kaia/blockchain/types"
 	"github.com/kaiachain/kaia/blockchain/vm"
 	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/cpulimit"
 	"github.com/kaiachain/kaia/common/hexutil"
 	"github.com/kaiachain/kaia/consensus"
 	"github.com/kaiachain/kaia/log"
@@ -320,7 +320,7 @@ func (api *CommonAPI) traceChain(start, end *types.Block, config *TraceConfig, n
 	}
 	// Execute all the transaction contained within the chain concurrently for each block
 	blocks := int(end.NumberU64() - start.NumberU64())
-	threads := runtime.NumCPU()
+	threads := cpulimit.NumCPU()
 	if threads > blocks {
 		threads = blocks
 	}
@@ -643,7 +643,7 @@ func (api *CommonAPI) traceBlock(ctx context.Context, block *types.Block, config
 		blockCtx = blockchain.NewEVMBlockContext(header, newChainContext(ctx, api.backend), nil)
 	)
 
-	threads := runtime.NumCPU()
+	threads := cpulimit.NumCPU()
 	if threads > len(txs) {
 		threads = len(txs)
 	}
@@ -674,6 +674,11 @@ func (api *CommonAPI) traceBlock(ctx context.Context, block *types.Block, config
 	// Feed the transactions into the tracers and return
 	var failed error
 	for i, tx := range txs {
+		// Stop feeding if the call is cancelled, e.g. by the execution limit of heavy calls
+		if err := context.Cause(ctx); err != nil {
+			failed = err
+			break
+		}
 		// Send the trace task over for execution
 		jobs <- &txTraceTask{statedb: statedb.Copy(), index: i}
 
@@ -754,6 +759,9 @@ func (api *CommonAPI) standardTraceBlockToFile(ctx context.Context, block *types
 		dumps  []string
 	)
 	for i, tx := range block.Transactions() {
+		if err := context.Cause(ctx); err != nil {
+			return dumps, err
+		}
 		// Prepare the transaction for un-traced execution
 		msg, err := tx.AsMessageWithAccountKeyPicker(signer, statedb, block.NumberU64())
 		if err != nil {
@@ -927,8 +935,9 @@ func (api *CommonAPI) TraceCall(ctx context.Context, args kaiaapi.CallArgs, bloc
 func (api *CommonAPI) traceTx(ctx context.Context, message blockchain.Message, blockCtx vm.BlockContext, txCtx vm.TxContext, statedb *state.StateDB, config *TraceConfig) (interface{}, error) {
 	// Assemble the structured logger or the JavaScript tracer
 	var (
-		tracer vm.Tracer
-		err    error
+		tracer      vm.Tracer
+		err         error
+		deadlineCtx = ctx
 	)
 	switch {
 	case config != nil && config.Tracer != nil:
@@ -948,23 +957,8 @@ func (api *CommonAPI) traceTx(ctx context.Context, message blockchain.Message, b
 				return nil, err
 			}
 		}
-		// Handle timeouts and RPC cancellations
-		deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
-		go func() {
-			<-deadlineCtx.Done()
-			if errors.Is(deadlineCtx.Err(), context.DeadlineExceeded) {
-				switch t := tracer.(type) {
-				case *Tracer:
-					t.Stop(errors.New("execution timeout"))
-				case *vm.InternalTxTracer:
-					t.Stop(errors.New("execution timeout"))
-				case *vm.CallTracer:
-					t.Stop(errors.New("execution timeout"))
-				default:
-					logger.Warn("unknown tracer type", "type", reflect.TypeOf(t).String())
-				}
-			}
-		}()
+		var cancel context.CancelFunc
+		deadlineCtx, cancel = context.WithTimeout(ctx, timeout)
 		defer cancel()
 
 	case config == nil:
@@ -976,10 +970,41 @@ func (api *CommonAPI) traceTx(ctx context.Context, message blockchain.Message, b
 	// Run the transaction with tracing enabled.
 	vmenv := vm.NewEVM(blockCtx, txCtx, statedb, api.backend.ChainConfig(), &vm.Config{Debug: true, Tracer: tracer})
 
+	// Handle timeouts and RPC cancellations, e.g. by the execution limit of heavy calls
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-deadlineCtx.Done():
+		case <-done:
+			return
+		}
+		vmenv.Cancel(vm.CancelByCtxDone)
+		reason := errors.New("execution timeout")
+		if cause := context.Cause(ctx); cause != nil {
+			reason = cause
+		}
+		switch t := tracer.(type) {
+		case *Tracer:
+			t.Stop(reason)
+		case *vm.InternalTxTracer:
+			t.Stop(reason)
+		case *vm.CallTracer:
+			t.Stop(reason)
+		case *vm.StructLogger:
+			// Nothing to stop, the cancelled EVM ends the trace.
+		default:
+			logger.Warn("unknown tracer type", "type", reflect.TypeOf(t).String())
+		}
+	}()
+
 	ret, err := blockchain.ApplyMessage(vmenv, message)
 	if err != nil {
 		return nil, fmt.Errorf("tracing failed: %v", err)
 	}
+	if err := context.Cause(ctx); err != nil {
+		return nil, fmt.Errorf("tracing aborted: %w", err)
+	}
 	// Depending on the tracer type, format and return the output
 	switch tracer := tracer.(type) {
 	case *vm.StructLogger:
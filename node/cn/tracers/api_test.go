@@ -395,6 +395,15 @@ func TestTraceTransaction(t *testing.T) {
 	}) {
 		t.Error("Transaction tracing result is different")
 	}
+
+	// A cancelled trace reports the cause, e.g. the execution limit of heavy calls.
+	ctx, cancel := context.WithCancelCause(context.Background())
+	limitErr := errors.New("execution time limit exceeded")
+	cancel(limitErr)
+	_, err = api.TraceTransaction(ctx, target, nil)
+	assert.ErrorIs(t, err, limitErr)
+	_, err = api.TraceBlockByNumber(ctx, rpc.LatestBlockNumber, nil)
+	assert.ErrorIs(t, err, limitErr)
 }
 
 func TestTraceBlock(t *testing.T) {
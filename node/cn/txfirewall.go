@@ -0,0 +1,235 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/rcrowley/go-metrics"
+)
+
+// txFirewallFile is the default file in the data directory which keeps the tx firewall rules.
+const txFirewallFile = "txfirewall.json"
+
+const (
+	TxFirewallReject = "reject" // the transaction is dropped at the ingress
+	TxFirewallTag    = "tag"    // the transaction is logged and counted but accepted
+)
+
+var (
+	ErrTxFirewallRejected = errors.New("transaction rejected by the tx firewall")
+
+	txFirewallRejectedCounter = metrics.NewRegisteredCounter("klay/txfirewall/rejected", nil)
+	txFirewallTaggedCounter   = metrics.NewRegisteredCounter("klay/txfirewall/tagged", nil)
+)
+
+// TxFirewallRule matches the transactions by their calldata. A transaction matches the rule if
+// every given condition holds. At least one of Selector and Pattern must be given.
+type TxFirewallRule struct {
+	Name     string          `json:"name"`
+	Action   string          `json:"action"`             // TxFirewallReject or TxFirewallTag
+	Selector hexutil.Bytes   `json:"selector,omitempty"` // the first 4 bytes of the calldata
+	Pattern  hexutil.Bytes   `json:"pattern,omitempty"`  // a byte sequence anywhere in the calldata
+	To       *common.Address `json:"to,omitempty"`       // the recipient
+	Hits     uint64          `json:"hits"`               // number of matched transactions since the start
+}
+
+func (r *TxFirewallRule) validate() error {
+	switch {
+	case r.Name == "":
+		return errors.New("rule name is empty")
+	case r.Action != TxFirewallReject && r.Action != TxFirewallTag:
+		return fmt.Errorf("rule %s: action must be %q or %q", r.Name, TxFirewallReject, TxFirewallTag)
+	case len(r.Selector) == 0 && len(r.Pattern) == 0:
+		return fmt.Errorf("rule %s: either selector or pattern must be given", r.Name)
+	case len(r.Selector) != 0 && len(r.Selector) != 4:
+		return fmt.Errorf("rule %s: selector must be 4 bytes", r.Name)
+	}
+	return nil
+}
+
+type txFirewallEntry struct {
+	rule TxFirewallRule
+	hits uint64 // accessed atomically
+}
+
+func (r *TxFirewallRule) match(tx *types.Transaction) bool {
+	if r.To != nil && (tx.To() == nil || *tx.To() != *r.To) {
+		return false
+	}
+	data := tx.Data()
+	if len(r.Selector) != 0 && !bytes.HasPrefix(data, r.Selector) {
+		return false
+	}
+	if len(r.Pattern) != 0 && !bytes.Contains(data, r.Pattern) {
+		return false
+	}
+	return true
+}
+
+// txFirewall filters the transactions arriving from the RPC clients and the peers by the
+// operator-configured rules, for the emergency response to known exploit payloads.
+// The rules are kept in a file and can be changed at runtime by the admin APIs.
+type txFirewall struct {
+	path    string
+	rules   []*txFirewallEntry
+	metrics map[string]metrics.Counter // rule name -> hit counter
+
+	mu sync.RWMutex
+}
+
+// newTxFirewall loads the rules from the file, if any.
+func newTxFirewall(path string) (*txFirewall, error) {
+	f := &txFirewall{path: path, metrics: make(map[string]metrics.Counter)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var rules []*TxFirewallRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("invalid tx firewall file %s: %w", path, err)
+	}
+	for _, rule := range rules {
+		if err := f.add(*rule); err != nil {
+			return nil, fmt.Errorf("invalid tx firewall file %s: %w", path, err)
+		}
+	}
+	return f, nil
+}
+
+// add adds the rule or replaces the rule of the same name.
+func (f *txFirewall) add(rule TxFirewallRule) error {
+	if err := rule.validate(); err != nil {
+		return err
+	}
+	rule.Hits = 0
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	rules := make([]*txFirewallEntry, 0, len(f.rules)+1)
+	for _, e := range f.rules {
+		if e.rule.Name != rule.Name {
+			rules = append(rules, e)
+		}
+	}
+	f.rules = append(rules, &txFirewallEntry{rule: rule})
+	if _, ok := f.metrics[rule.Name]; !ok {
+		f.metrics[rule.Name] = metrics.NewRegisteredCounter("klay/txfirewall/rule/"+rule.Name, nil)
+	}
+	return nil
+}
+
+// remove removes the rule of the name. It returns false if there is no such rule.
+func (f *txFirewall) remove(name string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, e := range f.rules {
+		if e.rule.Name == name {
+			f.rules = append(f.rules[:i:i], f.rules[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// list returns a copy of the rules.
+func (f *txFirewall) list() []TxFirewallRule {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	ret := make([]TxFirewallRule, 0, len(f.rules))
+	for _, e := range f.rules {
+		rule := e.rule
+		rule.Hits = atomic.LoadUint64(&e.hits)
+		ret = append(ret, rule)
+	}
+	return ret
+}
+
+// save persists the rules. The file is replaced atomically.
+func (f *txFirewall) save() error {
+	data, err := json.MarshalIndent(f.list(), "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
+
+// check returns ErrTxFirewallRejected if the transaction matches a reject rule. Every matching
+// rule is counted and logged for the audit, so a tag rule never hides a later reject rule.
+func (f *txFirewall) check(tx *types.Transaction, source ...interface{}) error {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var rejectedBy string
+	matched := false
+	for _, e := range f.rules {
+		r := &e.rule
+		if !r.match(tx) {
+			continue
+		}
+		matched = true
+		atomic.AddUint64(&e.hits, 1)
+		f.metrics[r.Name].Inc(1)
+		ctx := append([]interface{}{"rule", r.Name, "action", r.Action, "tx", tx.Hash(), "to", tx.To()}, source...)
+		if r.Action == TxFirewallReject {
+			logger.Warn("Tx firewall rejected a transaction", ctx...)
+			if rejectedBy == "" {
+				rejectedBy = r.Name
+			}
+		} else {
+			logger.Info("Tx firewall tagged a transaction", ctx...)
+		}
+	}
+	switch {
+	case rejectedBy != "":
+		txFirewallRejectedCounter.Inc(1)
+		return fmt.Errorf("%w (rule %s)", ErrTxFirewallRejected, rejectedBy)
+	case matched:
+		txFirewallTaggedCounter.Inc(1)
+	}
+	return nil
+}
+
+// checkRPC checks the transaction submitted by the RPC client of the given request context.
+func (f *txFirewall) checkRPC(ctx context.Context, tx *types.Transaction) error {
+	remote, _ := ctx.Value("remote").(string)
+	return f.check(tx, "source", TxSourceRPC, "remote", remote)
+}
+
+// checkPeer checks the transaction sent by the given peer.
+func (f *txFirewall) checkPeer(peer string, tx *types.Transaction) error {
+	return f.check(tx, "source", TxSourcePeer, "peer", peer)
+}
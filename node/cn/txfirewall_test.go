@@ -0,0 +1,110 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFirewallTestTx(to common.Address, data []byte) *types.Transaction {
+	return types.NewTransaction(0, to, big.NewInt(0), 100000, big.NewInt(1), data)
+}
+
+func TestTxFirewallRuleValidate(t *testing.T) {
+	selector := hexutil.Bytes{0xde, 0xad, 0xbe, 0xef}
+	for _, rule := range []TxFirewallRule{
+		{Action: TxFirewallReject, Selector: selector},                          // no name
+		{Name: "r", Action: "drop", Selector: selector},                         // unknown action
+		{Name: "r", Action: TxFirewallTag},                                      // no condition
+		{Name: "r", Action: TxFirewallTag, Selector: hexutil.Bytes{0xde, 0xad}}, // short selector
+		{Name: "r", Action: TxFirewallTag, To: &common.Address{}},               // no calldata condition
+		{Name: "r", Action: TxFirewallTag, Selector: append(selector, 0x00)},    // long selector
+	} {
+		assert.Error(t, rule.validate(), rule)
+	}
+	assert.NoError(t, (&TxFirewallRule{Name: "r", Action: TxFirewallReject, Selector: selector}).validate())
+	assert.NoError(t, (&TxFirewallRule{Name: "r", Action: TxFirewallTag, Pattern: hexutil.Bytes{0x01}}).validate())
+}
+
+func TestTxFirewallCheck(t *testing.T) {
+	var (
+		victim = common.HexToAddress("0x1111")
+		other  = common.HexToAddress("0x2222")
+		f, err = newTxFirewall(filepath.Join(t.TempDir(), txFirewallFile))
+	)
+	require.NoError(t, err)
+
+	exploit := newFirewallTestTx(victim, common.FromHex("0xdeadbeef00000000cafebabe"))
+	benign := newFirewallTestTx(victim, common.FromHex("0xa9059cbb0000000000000000"))
+
+	// No rules, nothing is filtered.
+	assert.NoError(t, f.checkPeer("peer", exploit))
+
+	require.NoError(t, f.add(TxFirewallRule{Name: "tag-cafebabe", Action: TxFirewallTag, Pattern: common.FromHex("0xcafebabe")}))
+	require.NoError(t, f.add(TxFirewallRule{Name: "exploit", Action: TxFirewallReject, Selector: common.FromHex("0xdeadbeef"), To: &victim}))
+
+	err = f.checkRPC(context.Background(), exploit)
+	assert.True(t, errors.Is(err, ErrTxFirewallRejected))
+	assert.Contains(t, err.Error(), "exploit")
+	assert.NoError(t, f.checkPeer("peer", benign))
+	assert.NoError(t, f.checkPeer("peer", newFirewallTestTx(other, exploit.Data()))) // only tagged
+
+	rules := f.list()
+	require.Len(t, rules, 2)
+	assert.Equal(t, uint64(2), rules[0].Hits) // tagged twice
+	assert.Equal(t, uint64(1), rules[1].Hits)
+
+	// Replacing a rule moves it to the end and resets its hits.
+	require.NoError(t, f.add(TxFirewallRule{Name: "exploit", Action: TxFirewallTag, Selector: common.FromHex("0xdeadbeef")}))
+	assert.NoError(t, f.checkPeer("peer", exploit))
+	rules = f.list()
+	require.Len(t, rules, 2)
+	assert.Equal(t, TxFirewallTag, rules[1].Action)
+	assert.Equal(t, uint64(1), rules[1].Hits)
+
+	assert.True(t, f.remove("exploit"))
+	assert.False(t, f.remove("exploit"))
+	assert.Len(t, f.list(), 1)
+}
+
+func TestTxFirewallPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), txFirewallFile)
+	f, err := newTxFirewall(path)
+	require.NoError(t, err)
+	to := common.HexToAddress("0x1111")
+	require.NoError(t, f.add(TxFirewallRule{Name: "exploit", Action: TxFirewallReject, Selector: common.FromHex("0xdeadbeef"), To: &to}))
+	require.NoError(t, f.save())
+
+	loaded, err := newTxFirewall(path)
+	require.NoError(t, err)
+	rules := loaded.list()
+	require.Len(t, rules, 1)
+	assert.Equal(t, "exploit", rules[0].Name)
+	assert.Equal(t, hexutil.Bytes(common.FromHex("0xdeadbeef")), rules[0].Selector)
+	assert.Equal(t, &to, rules[0].To)
+	assert.Error(t, loaded.checkPeer("peer", newFirewallTestTx(to, common.FromHex("0xdeadbeef"))))
+}
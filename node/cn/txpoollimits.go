@@ -0,0 +1,101 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package cn
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kaiachain/kaia/blockchain"
+)
+
+// txPoolLimitsFile is the file in the data directory which keeps the txpool limits changed at
+// runtime, so that they survive the node restarts. It overrides the txpool flags once written.
+const txPoolLimitsFile = "txpool.limits.json"
+
+type txPoolLimiter interface {
+	Limits() blockchain.TxPoolLimits
+	SetLimits(limits blockchain.TxPoolLimits) error
+}
+
+// TxPoolLimitsArgs changes the given txpool limits and keeps the omitted ones.
+type TxPoolLimitsArgs struct {
+	PriceLimit          *uint64 `json:"priceLimit"`
+	PriceBump           *uint64 `json:"priceBump"`
+	ExecSlotsAccount    *uint64 `json:"execSlotsAccount"`
+	ExecSlotsAll        *uint64 `json:"execSlotsAll"`
+	NonExecSlotsAccount *uint64 `json:"nonExecSlotsAccount"`
+	NonExecSlotsAll     *uint64 `json:"nonExecSlotsAll"`
+	Lifetime            *string `json:"lifetime"` // duration string such as "5m"
+}
+
+// apply returns the limits with the given fields changed.
+func (args *TxPoolLimitsArgs) apply(limits blockchain.TxPoolLimits) (blockchain.TxPoolLimits, error) {
+	set := func(dst *uint64, src *uint64) {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	set(&limits.PriceLimit, args.PriceLimit)
+	set(&limits.PriceBump, args.PriceBump)
+	set(&limits.ExecSlotsAccount, args.ExecSlotsAccount)
+	set(&limits.ExecSlotsAll, args.ExecSlotsAll)
+	set(&limits.NonExecSlotsAccount, args.NonExecSlotsAccount)
+	set(&limits.NonExecSlotsAll, args.NonExecSlotsAll)
+	if args.Lifetime != nil {
+		lifetime, err := time.ParseDuration(*args.Lifetime)
+		if err != nil {
+			return limits, fmt.Errorf("invalid lifetime: %w", err)
+		}
+		limits.Lifetime = lifetime
+	}
+	return limits, limits.Validate()
+}
+
+// readTxPoolLimits returns the persisted txpool limits, or nil if nothing has been persisted.
+func readTxPoolLimits(path string) (*blockchain.TxPoolLimits, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	limits := new(blockchain.TxPoolLimits)
+	if err := json.Unmarshal(data, limits); err != nil {
+		return nil, fmt.Errorf("invalid txpool limits file %s: %w", path, err)
+	}
+	if err := limits.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid txpool limits file %s: %w", path, err)
+	}
+	return limits, nil
+}
+
+// writeTxPoolLimits persists the txpool limits. The file is replaced atomically so that
+// a crash in the middle does not leave a broken file behind.
+func writeTxPoolLimits(path string, limits blockchain.TxPoolLimits) error {
+	data, err := json.MarshalIndent(limits, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
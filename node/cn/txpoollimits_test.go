@@ -0,0 +1,72 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package cn
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kaiachain/kaia/blockchain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxPoolLimitsArgs(t *testing.T) {
+	var (
+		base     = blockchain.DefaultTxPoolConfig.Limits()
+		zero     = uint64(0)
+		slots    = uint64(8)
+		lifetime = "90s"
+		bad      = "1 hour"
+	)
+
+	limits, err := (&TxPoolLimitsArgs{ExecSlotsAccount: &slots, Lifetime: &lifetime}).apply(base)
+	require.NoError(t, err)
+	want := base
+	want.ExecSlotsAccount, want.Lifetime = slots, 90*time.Second
+	assert.Equal(t, want, limits)
+
+	_, err = (&TxPoolLimitsArgs{Lifetime: &bad}).apply(base)
+	assert.ErrorContains(t, err, "invalid lifetime")
+
+	_, err = (&TxPoolLimitsArgs{ExecSlotsAll: &zero}).apply(base)
+	assert.ErrorContains(t, err, "invalid slots")
+}
+
+func TestTxPoolLimitsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), txPoolLimitsFile)
+
+	// Nothing is persisted yet.
+	limits, err := readTxPoolLimits(path)
+	require.NoError(t, err)
+	assert.Nil(t, limits)
+
+	want := blockchain.DefaultTxPoolConfig.Limits()
+	want.PriceLimit = 50000000000
+	want.NonExecSlotsAll = 256
+	require.NoError(t, writeTxPoolLimits(path, want))
+
+	limits, err = readTxPoolLimits(path)
+	require.NoError(t, err)
+	assert.Equal(t, &want, limits)
+
+	// A broken file fails the startup rather than being ignored.
+	require.NoError(t, os.WriteFile(path, []byte(`{"priceBump": 0}`), 0o644))
+	_, err = readTxPoolLimits(path)
+	assert.Error(t, err)
+}
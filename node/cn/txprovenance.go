@@ -0,0 +1,166 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package cn
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/networks/rpc"
+)
+
+const (
+	TxSourceRPC  = "rpc"
+	TxSourcePeer = "peer"
+
+	// txPropagationTimeout bounds each query of debug_txPropagation to a fleet node.
+	txPropagationTimeout = 5 * time.Second
+)
+
+// TxProvenance tells where and when a node has first seen a transaction.
+// It is kept node-locally and never propagated.
+type TxProvenance struct {
+	Hash      common.Hash `json:"hash"`
+	Node      string      `json:"node"`                // node ID of the recording node
+	FirstSeen time.Time   `json:"firstSeen"`           // the first arrival
+	Source    string      `json:"source"`              // TxSourceRPC or TxSourcePeer
+	Peer      string      `json:"peer,omitempty"`      // the sending peer if the source is a peer
+	Remote    string      `json:"remote,omitempty"`    // the remote address if the source is an RPC client
+	UserAgent string      `json:"userAgent,omitempty"` // the user agent if the source is an RPC client
+	Seen      uint64      `json:"seen"`                // number of arrivals including the first one
+}
+
+// TxPropagation is the propagation path of a transaction across the fleet, ordered by the first-seen time.
+type TxPropagation struct {
+	Hash    common.Hash       `json:"hash"`
+	Path    []*TxProvenance   `json:"path"`
+	Missing []string          `json:"missing,omitempty"` // endpoints that have not seen the transaction
+	Errors  map[string]string `json:"errors,omitempty"`  // endpoints that could not be queried
+}
+
+// txProvenance records the provenance of the recent transactions.
+type txProvenance struct {
+	node  string
+	cache *lru.Cache // common.Hash -> *TxProvenance
+
+	mu sync.Mutex // Serializes the read-modify-write of the records
+}
+
+func newTxProvenance(node string, size int) *txProvenance {
+	cache, _ := lru.New(size)
+	return &txProvenance{node: node, cache: cache}
+}
+
+// recordRPC records the transaction submitted by the RPC client of the given request context.
+func (t *txProvenance) recordRPC(ctx context.Context, hash common.Hash) {
+	remote, _ := ctx.Value("remote").(string)
+	ua, _ := ctx.Value("User-Agent").(string)
+	t.record(&TxProvenance{Hash: hash, Source: TxSourceRPC, Remote: remote, UserAgent: ua})
+}
+
+// recordPeer records the transaction sent by the given peer.
+func (t *txProvenance) recordPeer(peer string, hash common.Hash) {
+	t.record(&TxProvenance{Hash: hash, Source: TxSourcePeer, Peer: peer})
+}
+
+// record keeps the source of the first arrival and counts the later ones.
+func (t *txProvenance) record(p *TxProvenance) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if v, ok := t.cache.Get(p.Hash); ok {
+		v.(*TxProvenance).Seen++
+		return
+	}
+	p.Node = t.node
+	p.FirstSeen = time.Now()
+	p.Seen = 1
+	t.cache.Add(p.Hash, p)
+}
+
+// get returns a copy of the provenance of the transaction, or nil if it is unknown.
+func (t *txProvenance) get(hash common.Hash) *TxProvenance {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	v, ok := t.cache.Peek(hash)
+	if !ok {
+		return nil
+	}
+	p := *v.(*TxProvenance)
+	return &p
+}
+
+// propagation collects the provenance of the transaction from this node and the fleet endpoints.
+func (t *txProvenance) propagation(ctx context.Context, hash common.Hash, fleet []string) *TxPropagation {
+	var (
+		ret = &TxPropagation{Hash: hash, Errors: make(map[string]string)}
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+	)
+	if p := t.get(hash); p != nil {
+		ret.Path = append(ret.Path, p)
+	}
+	for _, endpoint := range fleet {
+		wg.Add(1)
+		go func(endpoint string) {
+			defer wg.Done()
+			p, err := queryTxProvenance(ctx, endpoint, hash)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil:
+				ret.Errors[endpoint] = err.Error()
+			case p == nil:
+				ret.Missing = append(ret.Missing, endpoint)
+			default:
+				ret.Path = append(ret.Path, p)
+			}
+		}(endpoint)
+	}
+	wg.Wait()
+
+	sort.Slice(ret.Path, func(i, j int) bool { return ret.Path[i].FirstSeen.Before(ret.Path[j].FirstSeen) })
+	sort.Strings(ret.Missing)
+	if len(ret.Errors) == 0 {
+		ret.Errors = nil
+	}
+	return ret
+}
+
+// queryTxProvenance calls debug_txProvenance of the endpoint. It returns nil if the endpoint has not seen the transaction.
+func queryTxProvenance(ctx context.Context, endpoint string, hash common.Hash) (*TxProvenance, error) {
+	ctx, cancel := context.WithTimeout(ctx, txPropagationTimeout)
+	defer cancel()
+
+	client, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	var p *TxProvenance
+	if err := client.CallContext(ctx, &p, "debug_txProvenance", hash); err != nil {
+		return nil, fmt.Errorf("debug_txProvenance: %w", err)
+	}
+	return p, nil
+}
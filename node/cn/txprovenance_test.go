@@ -0,0 +1,97 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package cn
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/networks/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testTxProvenanceAPI struct{ t *txProvenance }
+
+func (api *testTxProvenanceAPI) TxProvenance(hash common.Hash) *TxProvenance { return api.t.get(hash) }
+
+func TestTxProvenanceRecord(t *testing.T) {
+	var (
+		tracker = newTxProvenance("node0", 2)
+		hash1   = common.HexToHash("0x01")
+		hash2   = common.HexToHash("0x02")
+		hash3   = common.HexToHash("0x03")
+	)
+
+	ctx := context.WithValue(context.Background(), "remote", "10.0.0.1:1234")
+	ctx = context.WithValue(ctx, "User-Agent", "wallet/1.0")
+	tracker.recordRPC(ctx, hash1)
+	tracker.recordPeer("peer1", hash1)
+
+	// The first arrival is kept and the later ones are counted.
+	p := tracker.get(hash1)
+	require.NotNil(t, p)
+	assert.Equal(t, "node0", p.Node)
+	assert.Equal(t, TxSourceRPC, p.Source)
+	assert.Equal(t, "10.0.0.1:1234", p.Remote)
+	assert.Equal(t, "wallet/1.0", p.UserAgent)
+	assert.Empty(t, p.Peer)
+	assert.Equal(t, uint64(2), p.Seen)
+	assert.False(t, p.FirstSeen.IsZero())
+
+	tracker.recordPeer("peer2", hash2)
+	p = tracker.get(hash2)
+	require.NotNil(t, p)
+	assert.Equal(t, TxSourcePeer, p.Source)
+	assert.Equal(t, "peer2", p.Peer)
+
+	// The oldest record is evicted.
+	tracker.recordPeer("peer3", hash3)
+	assert.Nil(t, tracker.get(hash1))
+	assert.NotNil(t, tracker.get(hash3))
+}
+
+func TestTxProvenancePropagation(t *testing.T) {
+	var (
+		hash    = common.HexToHash("0x01")
+		local   = newTxProvenance("node0", 16)
+		seen    = newTxProvenance("node1", 16)
+		notSeen = newTxProvenance("node2", 16)
+	)
+	seen.recordRPC(context.Background(), hash)
+	local.recordPeer("node1", hash)
+
+	newEndpoint := func(tracker *txProvenance) string {
+		server := rpc.NewServer()
+		require.NoError(t, server.RegisterName("debug", &testTxProvenanceAPI{tracker}))
+		ts := httptest.NewServer(server)
+		t.Cleanup(ts.Close)
+		return ts.URL
+	}
+	seenURL, notSeenURL := newEndpoint(seen), newEndpoint(notSeen)
+	badURL := "http://127.0.0.1:1"
+
+	ret := local.propagation(context.Background(), hash, []string{seenURL, notSeenURL, badURL})
+	require.Len(t, ret.Path, 2)
+	assert.Equal(t, "node1", ret.Path[0].Node)
+	assert.Equal(t, TxSourceRPC, ret.Path[0].Source)
+	assert.Equal(t, "node0", ret.Path[1].Node)
+	assert.Equal(t, "node1", ret.Path[1].Peer)
+	assert.Equal(t, []string{notSeenURL}, ret.Missing)
+	assert.Contains(t, ret.Errors, badURL)
+}
@@ -70,6 +70,10 @@ type Config struct {
 	// key-value database type [LevelDB, RocksDB, BadgerDB, MemoryDB, DynamoDB, PebbleDB]
 	DBType database.DBType
 
+	// MemoryDB, if set, keeps all the databases in memory even if DataDir is given,
+	// so that the node always starts with a clean chain and writes no chain data to disk.
+	MemoryDB bool `toml:",omitempty"`
+
 	// DataDir is the file system folder the node should use for any data storage
 	// requirements. The configured data directory will not be directly shared with
 	// registered services, instead those can use utility methods to create/access
@@ -171,6 +175,10 @@ type Config struct {
 	// private APIs to untrusted users is a major security risk.
 	WSExposeAll bool `toml:",omitempty"`
 
+	// RPCListeners is a list of additional HTTP and websocket RPC listeners. Each of them
+	// exposes its own API modules with its own CORS, virtual hosts and authentication policy.
+	RPCListeners []RPCListenerConfig `toml:",omitempty"`
+
 	// GRPCHost is the host interface on which to start the gRPC server. If
 	// this field is empty, no gRPC API endpoint will be started.
 	GRPCHost string `toml:",omitempty"`
@@ -180,7 +188,7 @@ type Config struct {
 	// ephemeral nodes).
 	GRPCPort int `toml:",omitempty"`
 
-	// UpstreamArchiveEN is an archive mode EN endpoint
+	// UpstreamArchiveEN is a comma-separated list of archive mode EN endpoints
 	UpstreamArchiveEN string
 
 	// Ntp server:port to check the synchronization when booting the node
@@ -193,6 +201,36 @@ type Config struct {
 	Logger log.Logger `toml:",omitempty"`
 }
 
+// RPCListenerConfig is the configuration of an additional HTTP or websocket RPC listener.
+type RPCListenerConfig struct {
+	// Protocol is either "http" or "ws".
+	Protocol string
+
+	// Host and Port are the interface and TCP port number to listen at.
+	Host string
+	Port int
+
+	// Modules is a list of API modules to expose. If it is empty, all RPC API
+	// endpoints designated public will be exposed.
+	Modules []string `toml:",omitempty"`
+
+	// Cors is the list of allowed Cross-Origin Resource Sharing domains for HTTP,
+	// or the list of allowed origins for websocket.
+	Cors []string `toml:",omitempty"`
+
+	// VirtualHosts is the list of allowed virtual hostnames for HTTP.
+	VirtualHosts []string `toml:",omitempty"`
+
+	// AuthTokens is the list of bearer tokens accepted in the Authorization header.
+	// If it is empty, no authentication is required.
+	AuthTokens []string `toml:",omitempty"`
+}
+
+// Endpoint returns the interface and port to listen at.
+func (c *RPCListenerConfig) Endpoint() string {
+	return fmt.Sprintf("%s:%d", c.Host, c.Port)
+}
+
 // IPCEndpoint resolves an IPC endpoint based on a configured value, taking into
 // account the set data folders as well as the designated platform we're currently
 // running on.
@@ -362,62 +400,84 @@ func (c *Config) HttpServerType() string {
 // first any manually set key, falling back to the one found in the configured
 // data folder. If no key can be found, a new one is generated.
 func (c *Config) NodeKey() *ecdsa.PrivateKey {
+	key, err := c.LoadNodeKey()
+	if err != nil {
+		logger.Crit("Failed to load node key", "err", err)
+	}
+	return key
+}
+
+// LoadNodeKey is NodeKey returning an error instead of exiting the process.
+func (c *Config) LoadNodeKey() (*ecdsa.PrivateKey, error) {
 	// Use any specifically configured key.
 	if c.P2P.PrivateKey != nil {
-		return c.P2P.PrivateKey
+		return c.P2P.PrivateKey, nil
 	}
 
 	keyfile := c.ResolvePath(datadirPrivateKey)
 	if key, err := crypto.LoadECDSA(keyfile); err == nil {
-		return key
+		return key, nil
 	}
 	// No persistent key found, generate and store a new one.
 	key, err := crypto.GenerateKey()
 	if err != nil {
-		logger.Crit("Failed to generate node key", "err", err)
+		return nil, fmt.Errorf("failed to generate node key: %w", err)
 	}
 	instanceDir := filepath.Join(c.DataDir, c.name())
 	if err := os.MkdirAll(instanceDir, 0o700); err != nil {
-		logger.Crit("Failed to make dir to persist node key", "err", err)
+		return nil, fmt.Errorf("failed to make dir to persist node key: %w", err)
 	}
 	keyfile = filepath.Join(instanceDir, datadirPrivateKey)
 	if err := crypto.SaveECDSA(keyfile, key); err != nil {
-		logger.Crit("Failed to persist node key", "err", err)
+		return nil, fmt.Errorf("failed to persist node key: %w", err)
 	}
 	logger.Warn("Generated nodekey")
-	return key
+	return key, nil
 }
 
 // BlsNodeKey retrieves the currently configured BLS secret key key of the node,
 // check first any manually set key, falling back to the one found in the configured
 // data folder. If no key can be found, derive from the NodeKey.
 func (c *Config) BlsNodeKey() bls.SecretKey {
+	key, err := c.LoadBlsNodeKey()
+	if err != nil {
+		logger.Crit("Failed to load bls-nodekey", "err", err)
+	}
+	return key
+}
+
+// LoadBlsNodeKey is BlsNodeKey returning an error instead of exiting the process.
+func (c *Config) LoadBlsNodeKey() (bls.SecretKey, error) {
 	// Manually set via flags --bls-nodekey or --bls-nodekeyhex
 	if c.BlsKey != nil {
-		return c.BlsKey
+		return c.BlsKey, nil
 	}
 
 	// Load from default location under datadir
 	path := c.ResolvePath(DatadirBlsSecretKey)
 	if key, err := bls.LoadKey(path); err == nil {
-		return key
+		return key, nil
 	}
 
 	// No persistent key found, derive from NodeKey and store it
-	key, err := bls.GenerateKey(crypto.FromECDSA(c.NodeKey()))
+	nodeKey, err := c.LoadNodeKey()
 	if err != nil {
-		logger.Crit("Failed to derive bls-nodekey from nodekey", "err", err)
+		return nil, err
+	}
+	key, err := bls.GenerateKey(crypto.FromECDSA(nodeKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive bls-nodekey from nodekey: %w", err)
 	}
 	instanceDir := filepath.Join(c.DataDir, c.name())
 	if err := os.MkdirAll(instanceDir, 0o700); err != nil {
-		logger.Crit("Failed to make dir to persist bls node key", "err", err)
+		return nil, fmt.Errorf("failed to make dir to persist bls node key: %w", err)
 	}
 	keyfile := c.ResolvePath(DatadirBlsSecretKey)
 	if err := bls.SaveKey(keyfile, key); err != nil {
-		logger.Crit("Failed to persist bls node key", "err", err)
+		return nil, fmt.Errorf("failed to persist bls node key: %w", err)
 	}
 	logger.Warn("Derived bls-nodekey from nodekey")
-	return key
+	return key, nil
 }
 
 // StaticNodes returns a list of node enode URLs configured as static nodes.
@@ -156,3 +156,19 @@ func TestNodeKeyPersistency(t *testing.T) {
 		}
 	*/
 }
+
+// Tests that a node key failing to persist is reported instead of exiting the process.
+func TestLoadNodeKeyError(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+
+	config := &Config{Name: "unit-test", DataDir: file}
+	if _, err := config.LoadNodeKey(); err == nil {
+		t.Fatalf("node key persisted under a file")
+	}
+	if _, err := config.LoadBlsNodeKey(); err == nil {
+		t.Fatalf("bls node key persisted under a file")
+	}
+}
@@ -0,0 +1,320 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package embedded runs a Kaia consensus node inside another Go program.
+//
+// Unlike the kcn command, it does not read flags, install signal handlers or exit the
+// process: the node is configured with options, the errors are returned to the caller,
+// and the caller controls when the node starts and stops.
+//
+//	n, err := embedded.New(
+//		embedded.WithDataDir(dir),
+//		embedded.WithGenesis(genesis),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	if err := n.Start(); err != nil {
+//		return err
+//	}
+//	defer n.Stop()
+//
+//	heads := make(chan blockchain.ChainHeadEvent, 16)
+//	sub, err := n.SubscribeChainHead(heads)
+//
+// The events are sent synchronously, as to the other subscribers of the node, so a subscriber
+// must keep receiving from its channel until it unsubscribes. A slow consensus state
+// subscriber delays the consensus of the node.
+//
+// The chain configuration is registered process-wide while a node runs, so a process runs at
+// most one embedded node at a time.
+package embedded
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/kaiachain/kaia/blockchain"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/consensus/istanbul"
+	"github.com/kaiachain/kaia/event"
+	"github.com/kaiachain/kaia/log"
+	"github.com/kaiachain/kaia/networks/rpc"
+	"github.com/kaiachain/kaia/node"
+	"github.com/kaiachain/kaia/node/cn"
+	"github.com/kaiachain/kaia/params"
+)
+
+var (
+	ErrNotStarted          = errors.New("embedded node is not started")
+	ErrAlreadyStarted      = errors.New("embedded node is already started")
+	ErrAnotherNodeRunning  = errors.New("another embedded node is running in this process")
+	ErrNoConsensusState    = errors.New("consensus engine does not report its state")
+	ErrMissingGenesisChain = errors.New("genesis has no chain config")
+
+	logger = log.NewModuleLogger(log.Node)
+
+	running atomic.Bool // true while a node of this process is started
+)
+
+// Config is the configuration of an embedded node.
+type Config struct {
+	Node node.Config
+	CN   cn.Config
+
+	// Mining starts the block production once the node starts, as kcn does.
+	Mining bool
+}
+
+// DefaultConfig returns the default configuration of the protocol stack and the Kaia service
+// for the Mainnet. The RPC endpoints are disabled.
+func DefaultConfig() Config {
+	nodeConfig := node.DefaultConfig
+	nodeConfig.Name = "klay" // the same instance directory as kcn
+	nodeConfig.Version = params.Version
+	return Config{
+		Node:   nodeConfig,
+		CN:     *cn.GetDefaultConfig(),
+		Mining: true,
+	}
+}
+
+// Option modifies the configuration of an embedded node.
+type Option func(*Config)
+
+func WithDataDir(dir string) Option {
+	return func(c *Config) { c.Node.DataDir = dir }
+}
+
+// WithGenesis sets the genesis block and takes the network ID from its chain ID.
+func WithGenesis(genesis *blockchain.Genesis) Option {
+	return func(c *Config) {
+		c.CN.Genesis = genesis
+		if genesis != nil && genesis.Config != nil && genesis.Config.ChainID != nil {
+			c.CN.NetworkId = genesis.Config.ChainID.Uint64()
+		}
+	}
+}
+
+func WithNetworkId(id uint64) Option {
+	return func(c *Config) { c.CN.NetworkId = id }
+}
+
+func WithNodeKey(key *ecdsa.PrivateKey) Option {
+	return func(c *Config) { c.Node.P2P.PrivateKey = key }
+}
+
+func WithMining(enabled bool) Option {
+	return func(c *Config) { c.Mining = enabled }
+}
+
+// WithNodeConfig modifies the configuration of the protocol stack, e.g. the P2P and RPC endpoints.
+func WithNodeConfig(fn func(*node.Config)) Option {
+	return func(c *Config) { fn(&c.Node) }
+}
+
+// WithCNConfig modifies the configuration of the Kaia service.
+func WithCNConfig(fn func(*cn.Config)) Option {
+	return func(c *Config) { fn(&c.CN) }
+}
+
+// Node is a Kaia consensus node embedded in the program. It can be started again after it stops.
+type Node struct {
+	config Config
+	stack  *node.Node
+
+	mu sync.Mutex
+	cn *cn.CN // nil unless started
+}
+
+// New creates a node with the options applied to DefaultConfig. The node keys are loaded,
+// or generated under the data directory, here so that a failure is returned instead of
+// exiting the process.
+func New(opts ...Option) (*Node, error) {
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.CN.Genesis != nil && config.CN.Genesis.Config == nil {
+		return nil, ErrMissingGenesisChain
+	}
+
+	nodeKey, err := config.Node.LoadNodeKey()
+	if err != nil {
+		return nil, err
+	}
+	blsKey, err := config.Node.LoadBlsNodeKey()
+	if err != nil {
+		return nil, err
+	}
+	config.Node.P2P.PrivateKey, config.Node.BlsKey = nodeKey, blsKey
+
+	stack, err := node.New(&config.Node)
+	if err != nil {
+		return nil, err
+	}
+	cnConfig := config.CN
+	err = stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
+		cnConfig.WsEndpoint = stack.WSEndpoint()
+		return cn.New(ctx, &cnConfig)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Node{config: config, stack: stack}, nil
+}
+
+// Start starts the protocol stack and the Kaia service, and the block production if enabled.
+func (n *Node) Start() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.cn != nil {
+		return ErrAlreadyStarted
+	}
+	if !running.CompareAndSwap(false, true) {
+		return ErrAnotherNodeRunning
+	}
+
+	service, err := n.start()
+	if err != nil {
+		running.Store(false)
+		return err
+	}
+	n.cn = service
+	return nil
+}
+
+func (n *Node) start() (*cn.CN, error) {
+	if err := n.stack.Start(); err != nil {
+		return nil, err
+	}
+	var service *cn.CN
+	err := n.stack.Service(&service)
+	if err == nil && n.config.Mining {
+		err = service.StartMining(false)
+	}
+	if err != nil {
+		if stopErr := n.stack.Stop(); stopErr != nil {
+			logger.Error("Failed to stop the embedded node", "err", stopErr)
+		}
+		return nil, err
+	}
+	return service, nil
+}
+
+// Stop stops the node. The subscriptions end with the node.
+func (n *Node) Stop() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.cn == nil {
+		return ErrNotStarted
+	}
+	n.cn = nil
+	defer running.Store(false)
+	return n.stack.Stop()
+}
+
+// Wait blocks until the node stops.
+func (n *Node) Wait() {
+	n.stack.Wait()
+}
+
+// Config returns the configuration the node is created with.
+func (n *Node) Config() Config {
+	return n.config
+}
+
+// Stack returns the underlying protocol stack.
+func (n *Node) Stack() *node.Node {
+	return n.stack
+}
+
+// CN returns the Kaia service, or nil if the node is not started.
+func (n *Node) CN() *cn.CN {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.cn
+}
+
+// Attach creates an in-process RPC client of the node.
+func (n *Node) Attach() (*rpc.Client, error) {
+	return n.stack.Attach()
+}
+
+func (n *Node) service() (*cn.CN, error) {
+	if service := n.CN(); service != nil {
+		return service, nil
+	}
+	return nil, ErrNotStarted
+}
+
+// SubscribeChainHead notifies the new head blocks of the canonical chain.
+func (n *Node) SubscribeChainHead(ch chan<- blockchain.ChainHeadEvent) (event.Subscription, error) {
+	service, err := n.service()
+	if err != nil {
+		return nil, err
+	}
+	return service.BlockChain().SubscribeChainHeadEvent(ch), nil
+}
+
+// SubscribeChain notifies the blocks inserted into the canonical chain along with their logs.
+func (n *Node) SubscribeChain(ch chan<- blockchain.ChainEvent) (event.Subscription, error) {
+	service, err := n.service()
+	if err != nil {
+		return nil, err
+	}
+	return service.BlockChain().SubscribeChainEvent(ch), nil
+}
+
+// SubscribeLogs notifies the logs of the blocks inserted into the canonical chain.
+func (n *Node) SubscribeLogs(ch chan<- []*types.Log) (event.Subscription, error) {
+	service, err := n.service()
+	if err != nil {
+		return nil, err
+	}
+	return service.BlockChain().SubscribeLogsEvent(ch), nil
+}
+
+// SubscribeRemovedLogs notifies the logs removed from the canonical chain by a reorg.
+func (n *Node) SubscribeRemovedLogs(ch chan<- blockchain.RemovedLogsEvent) (event.Subscription, error) {
+	service, err := n.service()
+	if err != nil {
+		return nil, err
+	}
+	return service.BlockChain().SubscribeRemovedLogsEvent(ch), nil
+}
+
+type consensusStateSubscriber interface {
+	SubscribeConsensusState(ch chan<- istanbul.ConsensusStateEvent) event.Subscription
+}
+
+// SubscribeConsensusState notifies the changes of the sequence, the round and the state
+// of the Istanbul consensus of this node.
+func (n *Node) SubscribeConsensusState(ch chan<- istanbul.ConsensusStateEvent) (event.Subscription, error) {
+	service, err := n.service()
+	if err != nil {
+		return nil, err
+	}
+	engine, ok := service.Engine().(consensusStateSubscriber)
+	if !ok {
+		return nil, ErrNoConsensusState
+	}
+	return engine.SubscribeConsensusState(ch), nil
+}
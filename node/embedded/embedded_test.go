@@ -0,0 +1,83 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package embedded
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kaiachain/kaia/blockchain"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/consensus/istanbul"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/node"
+	"github.com/kaiachain/kaia/node/cn"
+	"github.com/kaiachain/kaia/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	genesis := &blockchain.Genesis{Config: &params.ChainConfig{ChainID: big.NewInt(2019)}}
+
+	config := DefaultConfig()
+	for _, opt := range []Option{
+		WithDataDir("/data"),
+		WithGenesis(genesis),
+		WithNodeKey(key),
+		WithMining(false),
+		WithNodeConfig(func(c *node.Config) { c.HTTPHost = "127.0.0.1" }),
+		WithCNConfig(func(c *cn.Config) { c.NoPruning = true }),
+	} {
+		opt(&config)
+	}
+	assert.Equal(t, "/data", config.Node.DataDir)
+	assert.Equal(t, genesis, config.CN.Genesis)
+	assert.Equal(t, uint64(2019), config.CN.NetworkId)
+	assert.Equal(t, key, config.Node.P2P.PrivateKey)
+	assert.False(t, config.Mining)
+	assert.Equal(t, "127.0.0.1", config.Node.HTTPHost)
+	assert.True(t, config.CN.NoPruning)
+
+	// A later option overrides an earlier one.
+	WithNetworkId(1001)(&config)
+	assert.Equal(t, uint64(1001), config.CN.NetworkId)
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	_, err := New(WithDataDir(t.TempDir()), WithGenesis(&blockchain.Genesis{}))
+	assert.ErrorIs(t, err, ErrMissingGenesisChain)
+}
+
+func TestNotStarted(t *testing.T) {
+	n := &Node{}
+	assert.Nil(t, n.CN())
+	assert.ErrorIs(t, n.Stop(), ErrNotStarted)
+
+	_, err := n.SubscribeChainHead(make(chan blockchain.ChainHeadEvent))
+	assert.ErrorIs(t, err, ErrNotStarted)
+	_, err = n.SubscribeChain(make(chan blockchain.ChainEvent))
+	assert.ErrorIs(t, err, ErrNotStarted)
+	_, err = n.SubscribeLogs(make(chan []*types.Log))
+	assert.ErrorIs(t, err, ErrNotStarted)
+	_, err = n.SubscribeRemovedLogs(make(chan blockchain.RemovedLogsEvent))
+	assert.ErrorIs(t, err, ErrNotStarted)
+	_, err = n.SubscribeConsensusState(make(chan istanbul.ConsensusStateEvent))
+	assert.ErrorIs(t, err, ErrNotStarted)
+}
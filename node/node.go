@@ -85,6 +85,8 @@ type Node struct {
 	wsListener net.Listener // Websocket RPC listener socket to server API requests
 	wsHandler  *rpc.Server  // Websocket RPC request handler to process the API requests
 
+	rpcListeners []*rpcListener // Additional HTTP and websocket RPC listeners
+
 	grpcEndpoint string         // gRPC endpoint (interface + port) to listen at (empty = gRPC disabled)
 	grpcListener *grpc.Listener // gRPC listener socket to server API requests
 	grpcHandler  *rpc.Server    // gRPC request handler to process the API requests
@@ -359,9 +361,18 @@ func (n *Node) startRPC(services map[reflect.Type]Service) error {
 		n.stopInProc()
 		return err
 	}
+	if err := n.startRPCListeners(apis, n.config.RPCListeners); err != nil {
+		n.stopWS()
+		n.stopHTTP()
+		n.stopIPC()
+		n.stopInProc()
+		return err
+	}
 
 	// start gRPC server
 	if err := n.startgRPC(apis); err != nil {
+		n.stopRPCListeners()
+		n.stopWS()
 		n.stopHTTP()
 		n.stopIPC()
 		n.stopInProc()
@@ -516,6 +527,53 @@ func (n *Node) stopWS() {
 	}
 }
 
+// rpcListener is an additional HTTP or websocket RPC listener.
+type rpcListener struct {
+	url      string
+	modules  []string
+	listener net.Listener
+	handler  *rpc.Server
+}
+
+// startRPCListeners initializes and starts the additional RPC listeners.
+func (n *Node) startRPCListeners(apis []rpc.API, configs []RPCListenerConfig) error {
+	for _, cfg := range configs {
+		var (
+			listener net.Listener
+			handler  *rpc.Server
+			err      error
+		)
+		endpoint := cfg.Endpoint()
+		switch cfg.Protocol {
+		case "http":
+			listener, handler, err = rpc.StartAuthHTTPEndpoint(endpoint, apis, cfg.Modules, cfg.Cors, cfg.VirtualHosts, n.config.HTTPTimeouts, cfg.AuthTokens)
+		case "ws":
+			listener, handler, err = rpc.StartAuthWSEndpoint(endpoint, apis, cfg.Modules, cfg.Cors, false, cfg.AuthTokens)
+		default:
+			err = fmt.Errorf("unsupported RPC listener protocol %q", cfg.Protocol)
+		}
+		if err != nil {
+			n.stopRPCListeners()
+			return err
+		}
+		url := fmt.Sprintf("%s://%s", cfg.Protocol, listener.Addr())
+		n.logger.Info("RPC listener opened", "url", url, "modules", strings.Join(cfg.Modules, ","),
+			"cors", strings.Join(cfg.Cors, ","), "vhosts", strings.Join(cfg.VirtualHosts, ","), "auth", len(cfg.AuthTokens) > 0)
+		n.rpcListeners = append(n.rpcListeners, &rpcListener{url: url, modules: cfg.Modules, listener: listener, handler: handler})
+	}
+	return nil
+}
+
+// stopRPCListeners terminates the additional RPC listeners.
+func (n *Node) stopRPCListeners() {
+	for _, l := range n.rpcListeners {
+		l.listener.Close()
+		l.handler.Stop()
+		n.logger.Info("RPC listener closed", "url", l.url)
+	}
+	n.rpcListeners = nil
+}
+
 func (n *Node) stopgRPC() {
 	if n.grpcListener != nil {
 		n.grpcListener.Stop()
@@ -542,6 +600,7 @@ func (n *Node) Stop() error {
 	}
 
 	// Terminate the API, services and the p2p server.
+	n.stopRPCListeners()
 	n.stopWS()
 	n.stopHTTP()
 	n.stopIPC()
@@ -708,9 +767,9 @@ func (n *Node) EventMux() *event.TypeMux {
 
 // OpenDatabase opens an existing database with the given name (or creates one if no
 // previous can be found) from within the node's instance directory. If the node is
-// ephemeral, a memory database is returned.
+// ephemeral or uses the memory database mode, a memory database is returned.
 func (n *Node) OpenDatabase(dbc *database.DBConfig) database.DBManager {
-	if n.config.DataDir == "" {
+	if n.config.DataDir == "" || n.config.MemoryDB {
 		return database.NewMemoryDBManager()
 	}
 	dbc.Dir = n.config.ResolvePath(dbc.Dir)
@@ -24,8 +24,11 @@ package node
 
 import (
 	"errors"
+	"io"
+	"net/http"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -579,3 +582,79 @@ func TestAPIGather(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the additional RPC listeners apply their own exposure policies.
+func TestRPCListeners(t *testing.T) {
+	config := testNodeConfig()
+	config.RPCListeners = []RPCListenerConfig{
+		{Protocol: "http", Host: "127.0.0.1", Port: 0, Modules: []string{"debug"}, AuthTokens: []string{"secret"}},
+		{Protocol: "http", Host: "127.0.0.1", Port: 0},
+	}
+	stack, err := New(config)
+	if err != nil {
+		t.Fatalf("failed to create protocol stack: %v", err)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatalf("failed to start node: %v", err)
+	}
+	defer stack.Stop()
+
+	if len(stack.rpcListeners) != 2 {
+		t.Fatalf("listener count mismatch: have %d, want 2", len(stack.rpcListeners))
+	}
+	call := func(l *rpcListener, token string, method string) (int, string) {
+		body := `{"jsonrpc":"2.0","id":1,"method":"` + method + `","params":[]}`
+		req, _ := http.NewRequest(http.MethodPost, "http://"+l.listener.Addr().String(), strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("failed to call %s: %v", method, err)
+		}
+		defer resp.Body.Close()
+		out, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(out)
+	}
+	private, public := stack.rpcListeners[0], stack.rpcListeners[1]
+
+	// The listeners are reported by admin_capabilities
+	caps, err := NewPublicAdminAPI(stack).Capabilities()
+	if err != nil {
+		t.Fatalf("failed to get capabilities: %v", err)
+	}
+	if modules, ok := caps.Endpoints[private.url]; !ok || !reflect.DeepEqual(modules, []string{"debug"}) {
+		t.Errorf("private listener modules mismatch: have %v, want [debug]", modules)
+	}
+	if _, ok := caps.Endpoints[public.url]; !ok {
+		t.Errorf("public listener %s is not reported", public.url)
+	}
+
+	// The private listener requires the token and exposes the debug module
+	if code, _ := call(private, "", "debug_gcStats"); code != http.StatusUnauthorized {
+		t.Errorf("status mismatch without token: have %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code, _ := call(private, "wrong", "debug_gcStats"); code != http.StatusUnauthorized {
+		t.Errorf("status mismatch with wrong token: have %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code, out := call(private, "secret", "debug_gcStats"); code != http.StatusOK || strings.Contains(out, "does not exist") {
+		t.Errorf("debug module must be exposed: %d %s", code, out)
+	}
+	// The public listener does not require the token but hides the non-public debug API
+	if code, out := call(public, "", "debug_gcStats"); code != http.StatusOK || !strings.Contains(out, "does not exist") {
+		t.Errorf("debug module must not be exposed: %d %s", code, out)
+	}
+
+	// Unknown protocols are rejected
+	config = testNodeConfig()
+	config.RPCListeners = []RPCListenerConfig{{Protocol: "grpc", Host: "127.0.0.1"}}
+	stack2, err := New(config)
+	if err != nil {
+		t.Fatalf("failed to create protocol stack: %v", err)
+	}
+	if err := stack2.Start(); err == nil {
+		stack2.Stop()
+		t.Fatal("expected failure for unsupported protocol")
+	}
+}
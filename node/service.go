@@ -48,15 +48,23 @@ func NewServiceContext(conf *Config, srv map[reflect.Type]Service, mux *event.Ty
 
 // OpenDatabase opens an existing database with the given name (or creates one
 // if no previous can be found) from within the node's data directory. If the
-// node is an ephemeral one, a memory database is returned.
+// node is an ephemeral one or uses the memory database mode, a memory database is returned.
 func (ctx *ServiceContext) OpenDatabase(dbc *database.DBConfig) database.DBManager {
-	if ctx.config.DataDir == "" {
+	if ctx.IsMemoryDB() {
 		return database.NewMemoryDBManager()
 	}
 	dbc.Dir = ctx.config.ResolvePath(dbc.Dir)
+	if dbc.AncientDir != "" {
+		dbc.AncientDir = ctx.config.ResolvePath(dbc.AncientDir)
+	}
 	return database.NewDBManager(dbc)
 }
 
+// IsMemoryDB returns whether the databases opened by OpenDatabase are kept in memory only.
+func (ctx *ServiceContext) IsMemoryDB() bool {
+	return ctx.config.DataDir == "" || ctx.config.MemoryDB
+}
+
 // ResolvePath resolves a user path into the data directory if that was relative
 // and if the user actually uses persistent storage. It will return an empty string
 // for emphemeral storage and the user's own input for absolute paths.
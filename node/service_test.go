@@ -74,6 +74,20 @@ func TestContextDatabases(t *testing.T) {
 	if _, err := os.Stat(filepath.Join(dir, "ephemeral")); err == nil {
 		t.Fatalf("ephemeral database exists")
 	}
+	// Request the opening/creation of a database in the memory database mode and ensure it's not persisted
+	ctx = NewServiceContext(&Config{Name: "unit-test", DataDir: dir, MemoryDB: true}, map[reflect.Type]Service{}, &event.TypeMux{}, &accounts.Manager{})
+	dbc = &database.DBConfig{
+		Dir: "memory", DBType: database.LevelDB,
+		LevelDBCacheSize: 0, OpenFilesLimit: 0,
+		PebbleDBCacheSize: 0,
+	}
+
+	db = ctx.OpenDatabase(dbc)
+	db.Close()
+
+	if _, err := os.Stat(filepath.Join(dir, "unit-test", "memory")); err == nil {
+		t.Fatalf("memory database exists")
+	}
 }
 
 // Tests that already constructed services can be retrieves by later ones.
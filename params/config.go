@@ -26,6 +26,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"reflect"
 
 	"github.com/kaiachain/kaia/common"
 	"github.com/kaiachain/kaia/log"
@@ -233,6 +234,48 @@ type ChainConfig struct {
 	RandaoCompatibleBlock *big.Int        `json:"randaoCompatibleBlock,omitempty"` // RandaoCompatible activate block (nil = no fork)
 	RandaoRegistry        *RegistryConfig `json:"randaoRegistry,omitempty"`        // Registry initial states
 
+	// StakeWeightedQuorum is an optional hardfork
+	// Once enabled, the consensus quorum is computed by the staking amounts of the committee instead of its headcount
+	StakeWeightedQuorumCompatibleBlock *big.Int `json:"stakeWeightedQuorumCompatibleBlock,omitempty"` // StakeWeightedQuorumCompatible activate block (nil = no fork)
+
+	// KeyRotation is an optional hardfork
+	// Once enabled, a validator can announce the rotation of its signing key to a successor key in its proposed block
+	KeyRotationCompatibleBlock *big.Int `json:"keyRotationCompatibleBlock,omitempty"` // KeyRotationCompatible activate block (nil = no fork)
+
+	// EmergencyPause is an optional hardfork intended for private and consortium chains
+	// Once enabled, governance can pause the processing of specific tx types or calls to specific contracts until an expiry block
+	EmergencyPauseCompatibleBlock *big.Int `json:"emergencyPauseCompatibleBlock,omitempty"` // EmergencyPauseCompatible activate block (nil = no fork)
+
+	// BlobTx is an optional hardfork intended for service chains hosting rollups
+	// Once enabled, EIP-4844 style blob txs are accepted and their blob gas is charged by the governance blob base fee
+	BlobTxCompatibleBlock *big.Int `json:"blobTxCompatibleBlock,omitempty"` // BlobTxCompatible activate block (nil = no fork)
+
+	// StateExpiry is an experimental hardfork intended for devnets
+	// Once enabled, an account untouched for StateExpiryEpochs whole epochs becomes inaccessible until it is resurrected with a merkle proof
+	StateExpiryCompatibleBlock *big.Int `json:"stateExpiryCompatibleBlock,omitempty"` // StateExpiryCompatible activate block (nil = no fork)
+	StateExpiryPeriod          uint64   `json:"stateExpiryPeriod,omitempty"`          // Number of blocks in a state expiry epoch
+	StateExpiryEpochs          uint64   `json:"stateExpiryEpochs,omitempty"`          // Number of whole epochs an account may stay untouched (0 = 1)
+
+	// ScheduledVote is an optional hardfork
+	// Once enabled, a governance vote can carry an explicit activation block, which changes the encoding of header.Vote and header.Governance
+	ScheduledVoteCompatibleBlock *big.Int `json:"scheduledVoteCompatibleBlock,omitempty"` // ScheduledVoteCompatible activate block (nil = no fork)
+
+	// StakeWeightedGov is an optional hardfork
+	// Once enabled, the header governance votes in the `stake` governance mode are tallied by the staked KAIA of the voters
+	StakeWeightedGovCompatibleBlock *big.Int `json:"stakeWeightedGovCompatibleBlock,omitempty"` // StakeWeightedGovCompatible activate block (nil = no fork)
+
+	// MultisigGov is an optional hardfork
+	// Once enabled, the `multisig` governance mode ratifies the header governance votes co-signed by the multisig signers
+	MultisigGovCompatibleBlock *big.Int `json:"multisigGovCompatibleBlock,omitempty"` // MultisigGovCompatible activate block (nil = no fork)
+
+	// ContractGovFromGenesis is intended for private networks
+	// Once set, the GovParam contract governance is effective from the genesis block instead of the Kore hardfork
+	ContractGovFromGenesis bool `json:"contractGovFromGenesis,omitempty"`
+
+	// ParamRegistry is intended for app-chains
+	// Once set, the listed governance parameters are read from the registry contract, taking precedence over header and contract governance
+	ParamRegistry *ParamRegistryConfig `json:"paramRegistry,omitempty"`
+
 	// Various consensus engines
 	Gxhash   *GxhashConfig   `json:"gxhash,omitempty"` // (deprecated) not supported engine
 	Clique   *CliqueConfig   `json:"clique,omitempty"`
@@ -245,11 +288,12 @@ type ChainConfig struct {
 
 // GovernanceConfig stores governance information for a network
 type GovernanceConfig struct {
-	GoverningNode    common.Address `json:"governingNode"`
-	GovernanceMode   string         `json:"governanceMode"`
-	GovParamContract common.Address `json:"govParamContract"`
-	Reward           *RewardConfig  `json:"reward,omitempty"`
-	KIP71            *KIP71Config   `json:"kip71,omitempty"`
+	GoverningNode    common.Address  `json:"governingNode"`
+	GovernanceMode   string          `json:"governanceMode"`
+	GovParamContract common.Address  `json:"govParamContract"`
+	Reward           *RewardConfig   `json:"reward,omitempty"`
+	KIP71            *KIP71Config    `json:"kip71,omitempty"`
+	Multisig         *MultisigConfig `json:"multisig,omitempty"`
 }
 
 func (g *GovernanceConfig) DeferredTxFee() bool {
@@ -277,6 +321,12 @@ type KIP71Config struct {
 	BaseFeeDenominator        uint64 `json:"basefeedenominator"`        // For normalizing effect of the rapid change like impulse gas used
 }
 
+// MultisigConfig stores the governing keys of the multisig governance mode
+type MultisigConfig struct {
+	Signers   []common.Address `json:"signers"`   // Governing keys that co-sign a header governance vote
+	Threshold uint64           `json:"threshold"` // Number of signers required to ratify a vote
+}
+
 // IstanbulConfig is the consensus engine configs for Istanbul based sealing.
 type IstanbulConfig struct {
 	Epoch          uint64 `json:"epoch"`  // Epoch length to reset votes and checkpoint
@@ -297,6 +347,13 @@ type RegistryConfig struct {
 	Owner   common.Address            `json:"owner"`
 }
 
+// ParamRegistryConfig is the registry contract supplying app-chain-specific governance parameters.
+// The contract must implement `getParam(string name) returns (bool exists, bytes value)` of GovParam.
+type ParamRegistryConfig struct {
+	Address common.Address `json:"address"`
+	Params  []string       `json:"params"` // the names of the parameters read from the registry
+}
+
 // GxhashConfig is the consensus engine configs for proof-of-work based sealing.
 // Deprecated: Use IstanbulConfig or CliqueConfig.
 type GxhashConfig struct{}
@@ -340,7 +397,7 @@ func (c *ChainConfig) String() string {
 	kip160 := fmt.Sprintf("KIP160CompatibleBlock: %v KIP160ContractAddress %s", c.Kip160CompatibleBlock, c.Kip160ContractAddress.String())
 
 	if c.Istanbul != nil {
-		return fmt.Sprintf("{ChainID: %v IstanbulCompatibleBlock: %v LondonCompatibleBlock: %v EthTxTypeCompatibleBlock: %v MagmaCompatibleBlock: %v KoreCompatibleBlock: %v ShanghaiCompatibleBlock: %v CancunCompatibleBlock: %v KaiaCompatibleBlock: %v RandaoCompatibleBlock: %v PragueCompatibleBlock: %v %s %s SubGroupSize: %d UnitPrice: %d DeriveShaImpl: %d Engine: %v}",
+		return fmt.Sprintf("{ChainID: %v IstanbulCompatibleBlock: %v LondonCompatibleBlock: %v EthTxTypeCompatibleBlock: %v MagmaCompatibleBlock: %v KoreCompatibleBlock: %v ShanghaiCompatibleBlock: %v CancunCompatibleBlock: %v KaiaCompatibleBlock: %v RandaoCompatibleBlock: %v PragueCompatibleBlock: %v StakeWeightedQuorumCompatibleBlock: %v KeyRotationCompatibleBlock: %v EmergencyPauseCompatibleBlock: %v BlobTxCompatibleBlock: %v StateExpiryCompatibleBlock: %v ScheduledVoteCompatibleBlock: %v StakeWeightedGovCompatibleBlock: %v MultisigGovCompatibleBlock: %v ContractGovFromGenesis: %v %s %s SubGroupSize: %d UnitPrice: %d DeriveShaImpl: %d Engine: %v}",
 			c.ChainID,
 			c.IstanbulCompatibleBlock,
 			c.LondonCompatibleBlock,
@@ -352,6 +409,15 @@ func (c *ChainConfig) String() string {
 			c.KaiaCompatibleBlock,
 			c.RandaoCompatibleBlock,
 			c.PragueCompatibleBlock,
+			c.StakeWeightedQuorumCompatibleBlock,
+			c.KeyRotationCompatibleBlock,
+			c.EmergencyPauseCompatibleBlock,
+			c.BlobTxCompatibleBlock,
+			c.StateExpiryCompatibleBlock,
+			c.ScheduledVoteCompatibleBlock,
+			c.StakeWeightedGovCompatibleBlock,
+			c.MultisigGovCompatibleBlock,
+			c.ContractGovFromGenesis,
 			kip103,
 			kip160,
 			c.Istanbul.SubGroupSize,
@@ -360,7 +426,7 @@ func (c *ChainConfig) String() string {
 			engine,
 		)
 	} else {
-		return fmt.Sprintf("{ChainID: %v IstanbulCompatibleBlock: %v LondonCompatibleBlock: %v EthTxTypeCompatibleBlock: %v MagmaCompatibleBlock: %v KoreCompatibleBlock: %v ShanghaiCompatibleBlock: %v CancunCompatibleBlock: %v KaiaCompatibleBlock: %v RandaoCompatibleBlock: %v PragueCompatibleBlock: %v %s %s UnitPrice: %d DeriveShaImpl: %d Engine: %v }",
+		return fmt.Sprintf("{ChainID: %v IstanbulCompatibleBlock: %v LondonCompatibleBlock: %v EthTxTypeCompatibleBlock: %v MagmaCompatibleBlock: %v KoreCompatibleBlock: %v ShanghaiCompatibleBlock: %v CancunCompatibleBlock: %v KaiaCompatibleBlock: %v RandaoCompatibleBlock: %v PragueCompatibleBlock: %v StakeWeightedQuorumCompatibleBlock: %v KeyRotationCompatibleBlock: %v EmergencyPauseCompatibleBlock: %v BlobTxCompatibleBlock: %v StateExpiryCompatibleBlock: %v ScheduledVoteCompatibleBlock: %v StakeWeightedGovCompatibleBlock: %v MultisigGovCompatibleBlock: %v ContractGovFromGenesis: %v %s %s UnitPrice: %d DeriveShaImpl: %d Engine: %v }",
 			c.ChainID,
 			c.IstanbulCompatibleBlock,
 			c.LondonCompatibleBlock,
@@ -372,6 +438,15 @@ func (c *ChainConfig) String() string {
 			c.KaiaCompatibleBlock,
 			c.RandaoCompatibleBlock,
 			c.PragueCompatibleBlock,
+			c.StakeWeightedQuorumCompatibleBlock,
+			c.KeyRotationCompatibleBlock,
+			c.EmergencyPauseCompatibleBlock,
+			c.BlobTxCompatibleBlock,
+			c.StateExpiryCompatibleBlock,
+			c.ScheduledVoteCompatibleBlock,
+			c.StakeWeightedGovCompatibleBlock,
+			c.MultisigGovCompatibleBlock,
+			c.ContractGovFromGenesis,
 			kip103,
 			kip160,
 			c.UnitPrice,
@@ -438,6 +513,52 @@ func (c *ChainConfig) IsPragueForkEnabled(num *big.Int) bool {
 	return isForked(c.PragueCompatibleBlock, num)
 }
 
+// IsStakeWeightedQuorumForkEnabled returns whether num is either equal to the stake-weighted quorum block or greater.
+func (c *ChainConfig) IsStakeWeightedQuorumForkEnabled(num *big.Int) bool {
+	return isForked(c.StakeWeightedQuorumCompatibleBlock, num)
+}
+
+// IsKeyRotationForkEnabled returns whether num is either equal to the key rotation block or greater.
+func (c *ChainConfig) IsKeyRotationForkEnabled(num *big.Int) bool {
+	return isForked(c.KeyRotationCompatibleBlock, num)
+}
+
+// IsEmergencyPauseForkEnabled returns whether num is either equal to the emergency pause block or greater.
+func (c *ChainConfig) IsEmergencyPauseForkEnabled(num *big.Int) bool {
+	return isForked(c.EmergencyPauseCompatibleBlock, num)
+}
+
+// IsBlobTxForkEnabled returns whether num is either equal to the blob tx block or greater.
+func (c *ChainConfig) IsBlobTxForkEnabled(num *big.Int) bool {
+	return isForked(c.BlobTxCompatibleBlock, num)
+}
+
+// IsStateExpiryForkEnabled returns whether num is either equal to the state expiry block or greater.
+func (c *ChainConfig) IsStateExpiryForkEnabled(num *big.Int) bool {
+	return isForked(c.StateExpiryCompatibleBlock, num)
+}
+
+// IsScheduledVoteForkEnabled returns whether num is either equal to the scheduled vote block or greater.
+func (c *ChainConfig) IsScheduledVoteForkEnabled(num *big.Int) bool {
+	return isForked(c.ScheduledVoteCompatibleBlock, num)
+}
+
+// IsStakeWeightedGovForkEnabled returns whether num is either equal to the stake-weighted governance block or greater.
+func (c *ChainConfig) IsStakeWeightedGovForkEnabled(num *big.Int) bool {
+	return isForked(c.StakeWeightedGovCompatibleBlock, num)
+}
+
+// IsMultisigGovForkEnabled returns whether num is either equal to the multisig governance block or greater.
+func (c *ChainConfig) IsMultisigGovForkEnabled(num *big.Int) bool {
+	return isForked(c.MultisigGovCompatibleBlock, num)
+}
+
+// IsContractGovEnabled returns whether the GovParam contract governance is effective at num,
+// i.e., from the genesis if ContractGovFromGenesis is set and from the kore block otherwise.
+func (c *ChainConfig) IsContractGovEnabled(num *big.Int) bool {
+	return c.ContractGovFromGenesis || c.IsKoreForkEnabled(num)
+}
+
 // IsKIP103ForkBlock returns whether num is equal to the kip103 block.
 func (c *ChainConfig) IsKIP103ForkBlock(num *big.Int) bool {
 	return isForkBlock(c.Kip103CompatibleBlock, num)
@@ -556,6 +677,49 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *Confi
 	if isForkIncompatible(c.PragueCompatibleBlock, newcfg.PragueCompatibleBlock, head) {
 		return newCompatError("Prague Block", c.PragueCompatibleBlock, newcfg.PragueCompatibleBlock)
 	}
+	if isForkIncompatible(c.StakeWeightedQuorumCompatibleBlock, newcfg.StakeWeightedQuorumCompatibleBlock, head) {
+		return newCompatError("StakeWeightedQuorum Block", c.StakeWeightedQuorumCompatibleBlock, newcfg.StakeWeightedQuorumCompatibleBlock)
+	}
+	if isForkIncompatible(c.KeyRotationCompatibleBlock, newcfg.KeyRotationCompatibleBlock, head) {
+		return newCompatError("KeyRotation Block", c.KeyRotationCompatibleBlock, newcfg.KeyRotationCompatibleBlock)
+	}
+	if isForkIncompatible(c.EmergencyPauseCompatibleBlock, newcfg.EmergencyPauseCompatibleBlock, head) {
+		return newCompatError("EmergencyPause Block", c.EmergencyPauseCompatibleBlock, newcfg.EmergencyPauseCompatibleBlock)
+	}
+	if isForkIncompatible(c.BlobTxCompatibleBlock, newcfg.BlobTxCompatibleBlock, head) {
+		return newCompatError("BlobTx Block", c.BlobTxCompatibleBlock, newcfg.BlobTxCompatibleBlock)
+	}
+	if isForkIncompatible(c.StateExpiryCompatibleBlock, newcfg.StateExpiryCompatibleBlock, head) {
+		return newCompatError("StateExpiry Block", c.StateExpiryCompatibleBlock, newcfg.StateExpiryCompatibleBlock)
+	}
+	if isForkIncompatible(c.ScheduledVoteCompatibleBlock, newcfg.ScheduledVoteCompatibleBlock, head) {
+		return newCompatError("ScheduledVote Block", c.ScheduledVoteCompatibleBlock, newcfg.ScheduledVoteCompatibleBlock)
+	}
+	if isForkIncompatible(c.StakeWeightedGovCompatibleBlock, newcfg.StakeWeightedGovCompatibleBlock, head) {
+		return newCompatError("StakeWeightedGov Block", c.StakeWeightedGovCompatibleBlock, newcfg.StakeWeightedGovCompatibleBlock)
+	}
+	if isForkIncompatible(c.MultisigGovCompatibleBlock, newcfg.MultisigGovCompatibleBlock, head) {
+		return newCompatError("MultisigGov Block", c.MultisigGovCompatibleBlock, newcfg.MultisigGovCompatibleBlock)
+	}
+	// The epochs of the state expiry cannot be changed once the fork is activated.
+	if (c.StateExpiryPeriod != newcfg.StateExpiryPeriod || c.StateExpiryEpochs != newcfg.StateExpiryEpochs) && isForked(c.StateExpiryCompatibleBlock, head) {
+		return newCompatError("StateExpiry Period", c.StateExpiryCompatibleBlock, newcfg.StateExpiryCompatibleBlock)
+	}
+	// ContractGovFromGenesis is regarded as a fork at the genesis block.
+	if c.ContractGovFromGenesis != newcfg.ContractGovFromGenesis {
+		return newCompatError("ContractGovFromGenesis", genesisForkBlock(c.ContractGovFromGenesis), genesisForkBlock(newcfg.ContractGovFromGenesis))
+	}
+	// ParamRegistry is regarded as a fork at the genesis block.
+	if !reflect.DeepEqual(c.ParamRegistry, newcfg.ParamRegistry) {
+		return newCompatError("ParamRegistry", genesisForkBlock(c.ParamRegistry != nil), genesisForkBlock(newcfg.ParamRegistry != nil))
+	}
+	return nil
+}
+
+func genesisForkBlock(enabled bool) *big.Int {
+	if enabled {
+		return big.NewInt(0)
+	}
 	return nil
 }
 
@@ -695,6 +859,11 @@ type Rules struct {
 	IsKaia      bool
 	IsRandao    bool
 	IsPrague    bool
+
+	IsStakeWeightedQuorum bool
+	IsKeyRotation         bool
+	IsEmergencyPause      bool
+	IsBlobTx              bool
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -715,6 +884,11 @@ func (c *ChainConfig) Rules(num *big.Int) Rules {
 		IsKaia:      c.IsKaiaForkEnabled(num),
 		IsRandao:    c.IsRandaoForkEnabled(num),
 		IsPrague:    c.IsPragueForkEnabled(num),
+
+		IsStakeWeightedQuorum: c.IsStakeWeightedQuorumForkEnabled(num),
+		IsKeyRotation:         c.IsKeyRotationForkEnabled(num),
+		IsEmergencyPause:      c.IsEmergencyPauseForkEnabled(num),
+		IsBlobTx:              c.IsBlobTxForkEnabled(num),
 	}
 }
 
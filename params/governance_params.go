@@ -76,6 +76,8 @@ const (
 	GovernanceMode_None = iota
 	GovernanceMode_Single
 	GovernanceMode_Ballot
+	GovernanceMode_Stake
+	GovernanceMode_Multisig
 )
 
 const (
@@ -82,9 +82,11 @@ func (ty *govParamType) Validate(v interface{}) bool {
 
 var (
 	govModeNames = map[string]int{
-		"none":   GovernanceMode_None,
-		"single": GovernanceMode_Single,
-		"ballot": GovernanceMode_Ballot,
+		"none":     GovernanceMode_None,
+		"single":   GovernanceMode_Single,
+		"ballot":   GovernanceMode_Ballot,
+		"stake":    GovernanceMode_Stake,
+		"multisig": GovernanceMode_Multisig,
 	}
 
 	parseValueString = func(v interface{}) (interface{}, bool) {
@@ -374,6 +374,57 @@ func (dl *diffLayer) Storage(accountHash, storageHash common.Hash) ([]byte, erro
 	return dl.storage(accountHash, storageHash, 0)
 }
 
+// StorageBatch directly retrieves the storage data associated with the given hashes,
+// within a particular account. The slots missed by the bloom filter are read from
+// the bottom persistent disk layer at once.
+func (dl *diffLayer) StorageBatch(accountHash common.Hash, storageHashes []common.Hash) ([][]byte, error) {
+	dl.lock.RLock()
+	// Check staleness before reaching further.
+	if dl.Stale() {
+		dl.lock.RUnlock()
+		return nil, ErrSnapshotStale
+	}
+	var (
+		origin   = dl.origin // extract origin while holding the lock
+		hits     []int
+		misses   []int
+		destruct = dl.diffed.Contains(destructBloomHasher(accountHash))
+	)
+	for i, storageHash := range storageHashes {
+		if destruct || dl.diffed.Contains(storageBloomHasher{accountHash, storageHash}) {
+			hits = append(hits, i)
+		} else {
+			misses = append(misses, i)
+		}
+	}
+	dl.lock.RUnlock()
+
+	blobs := make([][]byte, len(storageHashes))
+	if len(misses) > 0 {
+		missHashes := make([]common.Hash, len(misses))
+		for j, i := range misses {
+			missHashes[j] = storageHashes[i]
+		}
+		snapshotBloomStorageMissMeter.Mark(int64(len(misses)))
+		missBlobs, err := origin.StorageBatch(accountHash, missHashes)
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range misses {
+			blobs[i] = missBlobs[j]
+		}
+	}
+	// The bloom filter hit, poke in the internal maps one by one
+	for _, i := range hits {
+		blob, err := dl.storage(accountHash, storageHashes[i], 0)
+		if err != nil {
+			return nil, err
+		}
+		blobs[i] = blob
+	}
+	return blobs, nil
+}
+
 // storage is an internal version of Storage that skips the bloom filter checks
 // and uses the internal maps to try and retrieve the data. It's meant  to be
 // used if a higher layer's bloom filter hit already.
@@ -242,3 +242,62 @@ func emptyLayer() *diskLayer {
 		cache:  fastcache.New(500 * 1024),
 	}
 }
+
+// TestStorageBatch tests that the batched storage retrieval returns the same data
+// as the individual one through the diff and disk layers.
+func TestStorageBatch(t *testing.T) {
+	db := database.NewMemoryDBManager()
+	var (
+		acc      = common.Hash{0x1}
+		nuked    = common.Hash{0x2}
+		slots    = []common.Hash{{0x10}, {0x11}, {0x12}, {0x13}, {0x14}}
+		baseRoot = randomHash()
+		diffRoot = randomHash()
+	)
+	db.WriteAccountSnapshot(acc, acc[:])
+	db.WriteAccountSnapshot(nuked, nuked[:])
+	for _, slot := range slots[:4] {
+		db.WriteStorageSnapshot(acc, slot, slot[:])
+		db.WriteStorageSnapshot(nuked, slot, slot[:])
+	}
+	db.WriteSnapshotRoot(baseRoot)
+
+	snaps := &Tree{
+		layers: map[common.Hash]snapshot{
+			baseRoot: &diskLayer{
+				diskdb: db,
+				cache:  fastcache.New(500 * 1024),
+				root:   baseRoot,
+			},
+		},
+	}
+	base := snaps.Snapshot(baseRoot)
+	base.Storage(acc, slots[0]) // cached in the disk layer
+
+	if err := snaps.Update(diffRoot, baseRoot, map[common.Hash]struct{}{nuked: {}}, map[common.Hash][]byte{acc: acc[:]},
+		map[common.Hash]map[common.Hash][]byte{acc: {slots[1]: []byte{0xff}, slots[2]: nil}}); err != nil {
+		t.Fatalf("failed to update snapshot tree: %v", err)
+	}
+	diff := snaps.Snapshot(diffRoot)
+
+	for _, snap := range []Snapshot{base, diff} {
+		for _, account := range []common.Hash{acc, nuked} {
+			blobs, err := snap.StorageBatch(account, slots)
+			if err != nil {
+				t.Fatalf("failed to retrieve storage batch: %v", err)
+			}
+			for i, slot := range slots {
+				blob, err := snap.Storage(account, slot)
+				if err != nil {
+					t.Fatalf("failed to retrieve storage: %v", err)
+				}
+				if !bytes.Equal(blob, blobs[i]) {
+					t.Errorf("root %x account %x slot %x: batch %x, want %x", snap.Root(), account, slot, blobs[i], blob)
+				}
+			}
+		}
+	}
+	if blobs, _ := diff.StorageBatch(acc, slots[1:3]); !bytes.Equal(blobs[0], []byte{0xff}) || blobs[1] != nil {
+		t.Errorf("modified slots: have %x", blobs)
+	}
+}
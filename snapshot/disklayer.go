@@ -47,6 +47,8 @@ type diskLayer struct {
 	genPending chan struct{}             // Notification channel when generation is done (test synchronicity)
 	genAbort   chan chan *generatorStats // Notification channel to abort generating the snapshot in this layer
 
+	wiper *storageWiper // Background wiper deleting the storage of the destructed accounts
+
 	lock sync.RWMutex
 }
 
@@ -54,6 +56,9 @@ type diskLayer struct {
 // Reset() in order to not leak memory.
 // OBS: It does not invoke Close on the diskdb
 func (dl *diskLayer) Release() error {
+	if dl.wiper != nil {
+		dl.wiper.stop()
+	}
 	if dl.cache != nil {
 		dl.cache.Reset()
 	}
@@ -152,6 +157,10 @@ func (dl *diskLayer) Storage(accountHash, storageHash common.Hash) ([]byte, erro
 	if dl.genMarker != nil && bytes.Compare(key, dl.genMarker) > 0 {
 		return nil, ErrNotCoveredYet
 	}
+	// If the storage of the account is being wiped, the account was destructed
+	if dl.wiper != nil && dl.wiper.isPending(accountHash) {
+		return nil, nil
+	}
 	// If we're in the disk layer, all diff layers missed
 	snapshotDirtyStorageMissMeter.Mark(1)
 
@@ -174,6 +183,64 @@ func (dl *diskLayer) Storage(accountHash, storageHash common.Hash) ([]byte, erro
 	return blob, nil
 }
 
+// StorageBatch directly retrieves the storage data associated with the given hashes,
+// within a particular account. The slots missing in the memory cache are read from
+// the disk at once.
+func (dl *diskLayer) StorageBatch(accountHash common.Hash, storageHashes []common.Hash) ([][]byte, error) {
+	dl.lock.RLock()
+	defer dl.lock.RUnlock()
+
+	// If the layer was flattened into, consider it invalid (any live reference to
+	// the original should be marked as unusable).
+	if dl.stale {
+		return nil, ErrSnapshotStale
+	}
+	var (
+		blobs       = make([][]byte, len(storageHashes))
+		missing     []int
+		missingKeys []common.Hash
+		wiping      = dl.wiper != nil && dl.wiper.isPending(accountHash)
+	)
+	for i, storageHash := range storageHashes {
+		key := append(accountHash[:], storageHash[:]...)
+
+		// If the layer is being generated, ensure the requested hash has already been
+		// covered by the generator.
+		if dl.genMarker != nil && bytes.Compare(key, dl.genMarker) > 0 {
+			return nil, ErrNotCoveredYet
+		}
+		if wiping {
+			continue
+		}
+		snapshotDirtyStorageMissMeter.Mark(1)
+
+		if blob, found := dl.cache.HasGet(nil, key); found {
+			snapshotCleanStorageHitMeter.Mark(1)
+			snapshotCleanStorageReadMeter.Mark(int64(len(blob)))
+			blobs[i] = blob
+			continue
+		}
+		missing = append(missing, i)
+		missingKeys = append(missingKeys, storageHash)
+	}
+	if len(missing) == 0 {
+		return blobs, nil
+	}
+	// Pull the slots missing in the cache from disk at once, and cache them for later
+	for j, blob := range dl.diskdb.ReadStorageSnapshots(accountHash, missingKeys) {
+		dl.cache.Set(append(accountHash[:], missingKeys[j][:]...), blob)
+		blobs[missing[j]] = blob
+
+		snapshotCleanStorageMissMeter.Mark(1)
+		if n := len(blob); n > 0 {
+			snapshotCleanStorageWriteMeter.Mark(int64(n))
+		} else {
+			snapshotCleanStorageInexMeter.Mark(1)
+		}
+	}
+	return blobs, nil
+}
+
 // Update creates a new layer on top of the existing snapshot diff tree with
 // the specified data items. Note, the maps are retained by the method to avoid
 // copying everything.
@@ -148,6 +148,10 @@ func generateSnapshot(db database.DBManager, triedb *statedb.Database, cache int
 	)
 	defer batch.Release()
 
+	// The generator deletes the storage of the destructed accounts by itself
+	for _, hash := range db.ReadSnapshotStorageWipes() {
+		batch.DeleteSnapshotStorageWipe(hash)
+	}
 	batch.WriteSnapshotRoot(root)
 	journalProgress(batch, genMarker, stats)
 	if err := batch.Write(); err != nil {
@@ -162,6 +166,7 @@ func generateSnapshot(db database.DBManager, triedb *statedb.Database, cache int
 		genPending: make(chan struct{}),
 		genAbort:   make(chan chan *generatorStats),
 	}
+	base.wiper = newStorageWiper(db, base.cache)
 	go base.generate(stats)
 	logger.Debug("Start snapshot generation", "root", root)
 	return base
@@ -342,9 +342,12 @@ type diskStorageIterator struct {
 
 // StorageIterator creates a storage iterator over a disk layer.
 // If the whole storage is destructed, then all entries in the disk
-// layer are deleted already. So the "destructed" flag returned here
-// is always false.
+// layer are deleted already or hidden until the wiper deletes them.
+// So the "destructed" flag returned here is always false.
 func (dl *diskLayer) StorageIterator(account common.Hash, seek common.Hash) (StorageIterator, bool) {
+	if dl.wiper != nil && dl.wiper.isPending(account) {
+		return &diskStorageIterator{layer: dl, account: account}, false
+	}
 	pos := common.TrimRightZeroes(seek[:])
 	return &diskStorageIterator{
 		layer:   dl,
@@ -175,6 +175,7 @@ func loadSnapshot(diskdb database.DBManager, triedb *statedb.Database, cache int
 		logger.Warn("Snapshot is not continuous with chain", "snaproot", head, "chainroot", root)
 	}
 	// Everything loaded correctly, resume any suspended operations
+	base.wiper = newStorageWiper(diskdb, base.cache)
 	if !generator.Done {
 		// Whether or not wiping was in progress, load any generator progress too
 		base.genMarker = generator.Marker
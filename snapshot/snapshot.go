@@ -82,6 +82,9 @@ var (
 	snapshotFlushStorageItemMeter = metrics.NewRegisteredMeter("state/snapshot/flush/storage/item", nil)
 	snapshotFlushStorageSizeMeter = metrics.NewRegisteredMeter("state/snapshot/flush/storage/size", nil)
 
+	snapshotStorageWipePendingGauge = metrics.NewRegisteredGauge("state/snapshot/wipe/storage/pending", nil)
+	snapshotStorageWipeItemMeter    = metrics.NewRegisteredMeter("state/snapshot/wipe/storage/item", nil)
+
 	// TODO-Kaia-Snapshot update snapshotBloomIndexTimer
 	// snapshotBloomIndexTimer = metrics.NewRegisteredResettingTimer("state/snapshot/bloom/index", nil)
 	snapshotBloomErrorGauge = metrics.NewRegisteredGaugeFloat64("state/snapshot/bloom/error", nil)
@@ -129,6 +132,10 @@ type Snapshot interface {
 	// Storage directly retrieves the storage data associated with a particular hash,
 	// within a particular account.
 	Storage(accountHash, storageHash common.Hash) ([]byte, error)
+
+	// StorageBatch retrieves the storage data associated with the given hashes
+	// within a particular account, reading the ones missing in memory at once.
+	StorageBatch(accountHash common.Hash, storageHashes []common.Hash) ([][]byte, error)
 }
 
 // snapshot is the internal version of the snapshot data layer that supports some
@@ -271,6 +278,10 @@ func (t *Tree) Disable() {
 				layer.genAbort <- abort
 				<-abort
 			}
+			// Stop wiping the storage, it's continued by the next disk layer
+			if layer.wiper != nil {
+				layer.wiper.stop()
+			}
 			// Layer should be inactive now, mark it as stale
 			layer.lock.Lock()
 			layer.stale = true
@@ -553,6 +564,21 @@ func diffToDisk(bottom *diffLayer) *diskLayer {
 	base.stale = true
 	base.lock.Unlock()
 
+	// Finish wiping the storage of the accounts written again before pushing
+	// them, otherwise the background wiper would delete the new slots.
+	if base.wiper != nil {
+		for hash := range bottom.accountData {
+			base.wiper.finish(hash)
+		}
+		for hash := range bottom.storageData {
+			base.wiper.finish(hash)
+		}
+	}
+	// Keep the wiper from running until the wipe marks are persisted
+	if base.wiper != nil {
+		base.wiper.wipeLock.Lock()
+		defer base.wiper.wipeLock.Unlock()
+	}
 	// Destroy all the destructed accounts from the database
 	for hash := range bottom.destructSet {
 		// Skip any account not covered yet by the snapshot
@@ -563,6 +589,14 @@ func diffToDisk(bottom *diffLayer) *diskLayer {
 		batch.DeleteAccountSnapshot(hash)
 		base.cache.Set(hash[:], nil)
 
+		// Leave the storage of the account to the background wiper, unless the
+		// account is resurrected in the same layer.
+		if base.wiper != nil && !rewritten(bottom, hash) {
+			if base.wiper.isPending(hash) || hasStorageSnapshot(base.diskdb, hash) {
+				base.wiper.schedule(batch, hash)
+			}
+			continue
+		}
 		it := base.diskdb.NewSnapshotDBIterator(database.StorageSnapshotsKey(hash), nil)
 		for it.Next() {
 			if key := it.Key(); len(key) == 65 { // TODO(karalabe): Yuck, we should move this into the iterator
@@ -652,6 +686,10 @@ func diffToDisk(bottom *diffLayer) *diskLayer {
 		triedb:     base.triedb,
 		genMarker:  base.genMarker,
 		genPending: base.genPending,
+		wiper:      base.wiper,
+	}
+	if res.wiper != nil {
+		res.wiper.notify()
 	}
 	// If snapshot generation hasn't finished yet, port over all the starts and
 	// continue where the previous round left off.
@@ -735,6 +773,10 @@ func (t *Tree) Rebuild(root common.Hash) {
 				layer.genAbort <- abort
 				<-abort
 			}
+			// Stop wiping the storage, it's continued by the next disk layer
+			if layer.wiper != nil {
+				layer.wiper.stop()
+			}
 			// Layer should be inactive now, mark it as stale
 			layer.lock.Lock()
 			layer.stale = true
@@ -0,0 +1,221 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"sync"
+
+	"github.com/VictoriaMetrics/fastcache"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/storage/database"
+)
+
+// storageWipeChunk is the maximum number of storage slots deleted by the storage
+// wiper at once, bounding the time the disk layer transition may wait for it.
+const storageWipeChunk = 10000
+
+// storageWiper physically deletes the storage snapshot of the destructed accounts
+// in the background. Deleting the storage of a huge contract synchronously stalls
+// the block import, so the disk layer only marks the account to be wiped and
+// hides its storage until the wiper has finished.
+//
+// The pending accounts are persisted in the database, so that an interrupted
+// wipe is continued after a restart.
+type storageWiper struct {
+	db    database.DBManager
+	cache *fastcache.Cache
+
+	wipeLock sync.Mutex // Lock serializing the deletions of the wiper
+	lock     sync.RWMutex
+	pending  map[common.Hash]struct{} // Accounts whose storage is being wiped
+
+	wake     chan struct{}
+	quit     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// newStorageWiper creates a storage wiper and continues wiping the accounts left
+// pending in the database.
+func newStorageWiper(db database.DBManager, cache *fastcache.Cache) *storageWiper {
+	w := &storageWiper{
+		db:      db,
+		cache:   cache,
+		pending: make(map[common.Hash]struct{}),
+		wake:    make(chan struct{}, 1),
+		quit:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	for _, hash := range db.ReadSnapshotStorageWipes() {
+		w.pending[hash] = struct{}{}
+	}
+	if len(w.pending) == 0 {
+		// Nothing is scheduled yet, so the marker only keeps the next start from the lookup
+		db.DeleteSnapshotWipeMarker()
+	}
+	snapshotStorageWipePendingGauge.Update(int64(len(w.pending)))
+	if len(w.pending) > 0 {
+		logger.Info("Resuming snapshot storage wipes", "accounts", len(w.pending))
+		w.notify()
+	}
+	go w.loop()
+	return w
+}
+
+// isPending returns whether the storage of the account is waiting to be wiped.
+func (w *storageWiper) isPending(hash common.Hash) bool {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	_, ok := w.pending[hash]
+	return ok
+}
+
+// schedule marks the storage of the account to be wiped. The mark is persisted
+// with the given batch, and the wipe starts after notify is called.
+func (w *storageWiper) schedule(batch database.SnapshotDBBatch, hash common.Hash) {
+	batch.WriteSnapshotStorageWipe(hash)
+
+	w.lock.Lock()
+	w.pending[hash] = struct{}{}
+	snapshotStorageWipePendingGauge.Update(int64(len(w.pending)))
+	w.lock.Unlock()
+}
+
+// notify wakes up the wiper if it's idle.
+func (w *storageWiper) notify() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// finish synchronously wipes the rest of the storage of the account, if it's
+// pending. It's used before the account is written again.
+func (w *storageWiper) finish(hash common.Hash) {
+	if !w.isPending(hash) {
+		return
+	}
+	w.wipeLock.Lock()
+	defer w.wipeLock.Unlock()
+
+	for w.isPending(hash) {
+		w.wipeChunk(hash)
+	}
+}
+
+// wipeChunk deletes a chunk of the storage of the account. If no slots are left,
+// the account is unmarked.
+func (w *storageWiper) wipeChunk(hash common.Hash) {
+	batch := w.db.NewSnapshotDBBatch()
+	defer batch.Release()
+
+	var (
+		it    = w.db.NewSnapshotDBIterator(database.StorageSnapshotsKey(hash), nil)
+		count = 0
+	)
+	for count < storageWipeChunk && it.Next() {
+		if key := it.Key(); len(key) == len(database.SnapshotStoragePrefix)+2*common.HashLength {
+			batch.Delete(key)
+			w.cache.Del(key[len(database.SnapshotStoragePrefix):])
+			count++
+		}
+	}
+	it.Release()
+
+	completed := count < storageWipeChunk
+	if completed {
+		batch.DeleteSnapshotStorageWipe(hash)
+	}
+	if err := batch.Write(); err != nil {
+		logger.Crit("Failed to wipe storage snapshot", "err", err)
+	}
+	snapshotStorageWipeItemMeter.Mark(int64(count))
+
+	if completed {
+		w.lock.Lock()
+		delete(w.pending, hash)
+		snapshotStorageWipePendingGauge.Update(int64(len(w.pending)))
+		w.lock.Unlock()
+		logger.Debug("Wiped storage snapshot", "account", hash)
+	}
+}
+
+// loop wipes the pending accounts chunk by chunk until stopped.
+func (w *storageWiper) loop() {
+	defer close(w.done)
+
+	for {
+		select {
+		case <-w.wake:
+		case <-w.quit:
+			return
+		}
+		w.lock.RLock()
+		hashes := make([]common.Hash, 0, len(w.pending))
+		for hash := range w.pending {
+			hashes = append(hashes, hash)
+		}
+		w.lock.RUnlock()
+
+		for _, hash := range hashes {
+			for w.isPending(hash) {
+				select {
+				case <-w.quit:
+					return
+				default:
+				}
+				w.wipeLock.Lock()
+				if w.isPending(hash) {
+					w.wipeChunk(hash)
+				}
+				w.wipeLock.Unlock()
+			}
+		}
+	}
+}
+
+// stop terminates the wiper. The accounts left pending are wiped after restart.
+func (w *storageWiper) stop() {
+	w.stopOnce.Do(func() {
+		close(w.quit)
+		<-w.done
+	})
+}
+
+// rewritten returns whether the account is written again in the diff layer.
+func rewritten(dl *diffLayer, hash common.Hash) bool {
+	if _, ok := dl.accountData[hash]; ok {
+		return true
+	}
+	_, ok := dl.storageData[hash]
+	return ok
+}
+
+// hasStorageSnapshot returns whether any storage slot of the account is present
+// in the snapshot database.
+func hasStorageSnapshot(db database.DBManager, hash common.Hash) bool {
+	it := db.NewSnapshotDBIterator(database.StorageSnapshotsKey(hash), nil)
+	defer it.Release()
+
+	for it.Next() {
+		if len(it.Key()) == len(database.SnapshotStoragePrefix)+2*common.HashLength {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,216 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/VictoriaMetrics/fastcache"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/storage/database"
+)
+
+// newTestStorageWiper creates a storage wiper whose background loop isn't running,
+// so the tests can step through the wipe.
+func newTestStorageWiper(db database.DBManager, cache *fastcache.Cache) *storageWiper {
+	return &storageWiper{
+		db:      db,
+		cache:   cache,
+		pending: make(map[common.Hash]struct{}),
+		wake:    make(chan struct{}, 1),
+		quit:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// newWiperTestTree creates a snapshot tree with a contract holding more storage
+// slots than the wiper deletes at once.
+func newWiperTestTree() (database.DBManager, *Tree, *storageWiper, common.Hash, common.Hash) {
+	var (
+		db       = database.NewMemoryDBManager()
+		contract = common.Hash{0x1}
+		baseRoot = randomHash()
+	)
+	db.WriteAccountSnapshot(contract, contract[:])
+	for i := 0; i < storageWipeChunk+10; i++ {
+		slot := common.BigToHash(big.NewInt(int64(i + 1)))
+		db.WriteStorageSnapshot(contract, slot, slot[:])
+	}
+	db.WriteSnapshotRoot(baseRoot)
+
+	cache := fastcache.New(500 * 1024)
+	wiper := newTestStorageWiper(db, cache)
+	snaps := &Tree{
+		layers: map[common.Hash]snapshot{
+			baseRoot: &diskLayer{
+				diskdb: db,
+				cache:  cache,
+				root:   baseRoot,
+				wiper:  wiper,
+			},
+		},
+	}
+	return db, snaps, wiper, contract, baseRoot
+}
+
+// countStorageSnapshot returns the number of the storage slots of the account
+// left in the database.
+func countStorageSnapshot(db database.DBManager, account common.Hash) int {
+	it := db.NewSnapshotDBIterator(database.StorageSnapshotsKey(account), nil)
+	defer it.Release()
+
+	count := 0
+	for it.Next() {
+		count++
+	}
+	return count
+}
+
+// Tests that destructing a contract only marks its storage to be wiped, hiding
+// it from the disk layer until the wiper deletes it chunk by chunk.
+func TestStorageWiperDeferred(t *testing.T) {
+	db, snaps, wiper, contract, baseRoot := newWiperTestTree()
+	slot := common.BigToHash(big.NewInt(1))
+
+	// Warm up the cache, it must not serve the destructed slot either
+	if blob, _ := snaps.Snapshot(baseRoot).Storage(contract, slot); !bytes.Equal(blob, slot[:]) {
+		t.Fatalf("storage mismatch: have %x, want %x", blob, slot[:])
+	}
+	diffRoot := randomHash()
+	if err := snaps.Update(diffRoot, baseRoot, map[common.Hash]struct{}{contract: {}}, nil, nil); err != nil {
+		t.Fatalf("failed to update snapshot tree: %v", err)
+	}
+	if err := snaps.Cap(diffRoot, 0); err != nil {
+		t.Fatalf("failed to flatten snapshot tree: %v", err)
+	}
+	// The storage must be left on disk, but invisible through the disk layer
+	if n := countStorageSnapshot(db, contract); n != storageWipeChunk+10 {
+		t.Fatalf("storage deleted synchronously: have %d slots, want %d", n, storageWipeChunk+10)
+	}
+	if wipes := db.ReadSnapshotStorageWipes(); len(wipes) != 1 || wipes[0] != contract {
+		t.Fatalf("wipe mark mismatch: have %v, want [%x]", wipes, contract)
+	}
+	base := snaps.Snapshot(diffRoot)
+	if blob, err := base.Storage(contract, slot); err != nil || blob != nil {
+		t.Fatalf("destructed storage visible: %x, %v", blob, err)
+	}
+	if blobs, err := base.StorageBatch(contract, []common.Hash{slot}); err != nil || blobs[0] != nil {
+		t.Fatalf("destructed storage visible in batch: %x, %v", blobs, err)
+	}
+	it, err := snaps.StorageIterator(diffRoot, contract, common.Hash{})
+	if err != nil {
+		t.Fatalf("failed to create storage iterator: %v", err)
+	}
+	if it.Next() {
+		t.Fatalf("destructed storage iterated: %x", it.Hash())
+	}
+	it.Release()
+
+	// Wipe the storage chunk by chunk
+	wiper.wipeChunk(contract)
+	if !wiper.isPending(contract) {
+		t.Fatal("wipe completed before deleting all slots")
+	}
+	if n := countStorageSnapshot(db, contract); n != 10 {
+		t.Fatalf("chunk size mismatch: have %d slots left, want %d", n, 10)
+	}
+	wiper.wipeChunk(contract)
+	if wiper.isPending(contract) {
+		t.Fatal("wipe not completed")
+	}
+	if n := countStorageSnapshot(db, contract); n != 0 {
+		t.Fatalf("storage not wiped: have %d slots left", n)
+	}
+	if wipes := db.ReadSnapshotStorageWipes(); len(wipes) != 0 {
+		t.Fatalf("wipe mark not deleted: %v", wipes)
+	}
+	if blob, err := base.Storage(contract, slot); err != nil || blob != nil {
+		t.Fatalf("wiped storage visible: %x, %v", blob, err)
+	}
+}
+
+// Tests that an account written again while its storage is being wiped gets
+// wiped synchronously, so the new slots survive.
+func TestStorageWiperResurrect(t *testing.T) {
+	db, snaps, wiper, contract, baseRoot := newWiperTestTree()
+
+	diffRoot := randomHash()
+	if err := snaps.Update(diffRoot, baseRoot, map[common.Hash]struct{}{contract: {}}, nil, nil); err != nil {
+		t.Fatalf("failed to update snapshot tree: %v", err)
+	}
+	if err := snaps.Cap(diffRoot, 0); err != nil {
+		t.Fatalf("failed to flatten snapshot tree: %v", err)
+	}
+	slot := common.BigToHash(big.NewInt(1))
+	value := []byte{0xff}
+
+	resurrectRoot := randomHash()
+	if err := snaps.Update(resurrectRoot, diffRoot, nil, map[common.Hash][]byte{
+		contract: reverse(contract[:]),
+	}, map[common.Hash]map[common.Hash][]byte{
+		contract: {slot: value},
+	}); err != nil {
+		t.Fatalf("failed to update snapshot tree: %v", err)
+	}
+	if err := snaps.Cap(resurrectRoot, 0); err != nil {
+		t.Fatalf("failed to flatten snapshot tree: %v", err)
+	}
+	if wiper.isPending(contract) {
+		t.Fatal("wipe not completed before resurrection")
+	}
+	if wipes := db.ReadSnapshotStorageWipes(); len(wipes) != 0 {
+		t.Fatalf("wipe mark not deleted: %v", wipes)
+	}
+	if n := countStorageSnapshot(db, contract); n != 1 {
+		t.Fatalf("storage mismatch: have %d slots, want 1", n)
+	}
+	if blob, err := snaps.Snapshot(resurrectRoot).Storage(contract, slot); err != nil || !bytes.Equal(blob, value) {
+		t.Fatalf("resurrected storage mismatch: have %x, want %x (err: %v)", blob, value, err)
+	}
+}
+
+// Tests that the wiper continues the wipes left pending in the database.
+func TestStorageWiperResume(t *testing.T) {
+	db, snaps, _, contract, baseRoot := newWiperTestTree()
+
+	diffRoot := randomHash()
+	if err := snaps.Update(diffRoot, baseRoot, map[common.Hash]struct{}{contract: {}}, nil, nil); err != nil {
+		t.Fatalf("failed to update snapshot tree: %v", err)
+	}
+	if err := snaps.Cap(diffRoot, 0); err != nil {
+		t.Fatalf("failed to flatten snapshot tree: %v", err)
+	}
+	// Restart the wiper, it must pick up the pending wipe by itself
+	wiper := newStorageWiper(db, fastcache.New(500*1024))
+	defer wiper.stop()
+
+	for deadline := time.Now().Add(5 * time.Second); wiper.isPending(contract); {
+		if time.Now().After(deadline) {
+			t.Fatal("pending wipe not resumed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := countStorageSnapshot(db, contract); n != 0 {
+		t.Fatalf("storage not wiped: have %d slots left", n)
+	}
+	if wipes := db.ReadSnapshotStorageWipes(); len(wipes) != 0 {
+		t.Fatalf("wipe mark not deleted: %v", wipes)
+	}
+}
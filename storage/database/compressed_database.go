@@ -0,0 +1,289 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/klauspost/compress/zstd"
+)
+
+// compressionThreshold is the minimum size of a value to be compressed. Smaller
+// values, like most trie nodes, hardly shrink and are stored as they are.
+const compressionThreshold = 256
+
+var errCompressionSingleDB = errors.New("value compression is not supported with a single database")
+
+// zstdMagic is the magic number starting every zstd frame.
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault), zstd.WithEncoderConcurrency(1))
+	zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+)
+
+// compressValue returns the value to store for the given value. A value at least
+// compressionThreshold long is stored as a zstd frame if it gets smaller. A value
+// looking like a zstd frame is always stored compressed to keep it unambiguous.
+func compressValue(value []byte) []byte {
+	framed := bytes.HasPrefix(value, zstdMagic)
+	if len(value) < compressionThreshold && !framed {
+		return value
+	}
+	compressed := zstdEncoder.EncodeAll(value, make([]byte, 0, len(value)))
+	if len(compressed) >= len(value) && !framed {
+		return value
+	}
+	return compressed
+}
+
+// decompressValue returns the original value of the given stored value. A value
+// which is not a zstd frame is returned as it is, e.g. one written before the
+// compression is enabled.
+func decompressValue(value []byte) []byte {
+	if !bytes.HasPrefix(value, zstdMagic) {
+		return value
+	}
+	decompressed, err := zstdDecoder.DecodeAll(value, nil)
+	if err != nil {
+		return value
+	}
+	return decompressed
+}
+
+// compressedDB is a Database storing large values compressed with zstd. The values
+// are decompressed on read whether or not the compression is enabled for writes,
+// so that a table written compressed stays readable after it is disabled.
+type compressedDB struct {
+	Database
+	compress bool // If false, values are written uncompressed
+}
+
+func newCompressedDB(db Database, compress bool) *compressedDB {
+	return &compressedDB{Database: db, compress: compress}
+}
+
+func (db *compressedDB) encode(value []byte) []byte {
+	if !db.compress {
+		return value
+	}
+	return compressValue(value)
+}
+
+func (db *compressedDB) Get(key []byte) ([]byte, error) {
+	value, err := db.Database.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return decompressValue(value), nil
+}
+
+func (db *compressedDB) MultiGet(keys [][]byte) ([][]byte, error) {
+	values, err := MultiGet(db.Database, keys)
+	if err != nil {
+		return nil, err
+	}
+	for i, value := range values {
+		values[i] = decompressValue(value)
+	}
+	return values, nil
+}
+
+func (db *compressedDB) Put(key []byte, value []byte) error {
+	return db.Database.Put(key, db.encode(value))
+}
+
+func (db *compressedDB) NewBatch() Batch {
+	return &compressedBatch{Batch: db.Database.NewBatch(), db: db}
+}
+
+func (db *compressedDB) NewIterator(prefix []byte, start []byte) Iterator {
+	return &compressedIterator{Iterator: db.Database.NewIterator(prefix, start)}
+}
+
+// compressedBatch is a Batch of a compressedDB.
+type compressedBatch struct {
+	Batch
+	db *compressedDB
+}
+
+func (b *compressedBatch) Put(key []byte, value []byte) error {
+	return b.Batch.Put(key, b.db.encode(value))
+}
+
+// Replay replays the batch contents with the original values.
+func (b *compressedBatch) Replay(w KeyValueWriter) error {
+	return b.Batch.Replay(&decompressingWriter{w})
+}
+
+// decompressingWriter writes the original values of the stored values to a KeyValueWriter.
+type decompressingWriter struct {
+	KeyValueWriter
+}
+
+func (w *decompressingWriter) Put(key []byte, value []byte) error {
+	return w.KeyValueWriter.Put(key, decompressValue(value))
+}
+
+// compressedIterator is an Iterator of a compressedDB returning the original values.
+type compressedIterator struct {
+	Iterator
+}
+
+func (it *compressedIterator) Value() []byte {
+	return decompressValue(it.Iterator.Value())
+}
+
+// parseCompressedTables returns the database entry types of the given table names.
+// StateTrieMigrationDB follows StateTrieDB, and MiscDB can't be compressed.
+func parseCompressedTables(names []string) (map[DBEntryType]bool, error) {
+	tables := make(map[DBEntryType]bool)
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		found := false
+		for et := MiscDB + 1; et < databaseEntryTypeSize; et++ {
+			if et != StateTrieMigrationDB && dbBaseDirs[et] == name {
+				tables[et] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown table %q to compress", name)
+		}
+	}
+	if tables[StateTrieDB] {
+		tables[StateTrieMigrationDB] = true
+	}
+	return tables, nil
+}
+
+// compressedTableNames returns the names of the given tables in the order of DBEntryType.
+func compressedTableNames(tables map[DBEntryType]bool) []string {
+	var names []string
+	for et := MiscDB + 1; et < databaseEntryTypeSize; et++ {
+		if tables[et] && et != StateTrieMigrationDB {
+			names = append(names, dbBaseDirs[et])
+		}
+	}
+	return names
+}
+
+// readCompressedTables returns the tables which have ever been written compressed.
+func readCompressedTables(miscDB Database) map[DBEntryType]bool {
+	data, _ := miscDB.Get(compressedTablesKey)
+	if len(data) == 0 {
+		return map[DBEntryType]bool{}
+	}
+	tables, err := parseCompressedTables(strings.Split(string(data), ","))
+	if err != nil {
+		logger.Crit("Invalid compressed tables", "tables", string(data), "err", err)
+	}
+	return tables
+}
+
+func writeCompressedTables(miscDB Database, tables map[DBEntryType]bool) {
+	if err := miscDB.Put(compressedTablesKey, []byte(strings.Join(compressedTableNames(tables), ","))); err != nil {
+		logger.Crit("Failed to store the compressed tables", "err", err)
+	}
+}
+
+// openCompressedTables returns the tables to be wrapped by compressedDB, which are the
+// tables enabled by the configuration and the ones written compressed before, and
+// whether each of them is compressed on write.
+func openCompressedTables(dbc *DBConfig, miscDB Database) (map[DBEntryType]bool, error) {
+	enabled, err := parseCompressedTables(dbc.CompressedTables)
+	if err != nil {
+		return nil, err
+	}
+	tables := readCompressedTables(miscDB)
+	for et := range tables {
+		tables[et] = false
+	}
+	for et := range enabled {
+		tables[et] = true
+	}
+	if len(enabled) > 0 && !dbc.ReadOnly {
+		writeCompressedTables(miscDB, tables)
+	}
+	return tables, nil
+}
+
+// MigrateCompression rewrites every value of the tables written compressed before
+// or enabled by the configuration, so that the values follow the configuration:
+// the existing values of an enabled table are compressed, and the values of a
+// disabled one are restored. Afterwards, only the enabled tables are decompressed
+// on read. It returns the number of the rewritten values.
+func (dbm *databaseManager) MigrateCompression() (int, error) {
+	if dbm.config.SingleDB {
+		return 0, errCompressionSingleDB
+	}
+	var (
+		enabled = make(map[DBEntryType]bool)
+		total   = 0
+	)
+	for et := MiscDB + 1; et < databaseEntryTypeSize; et++ {
+		cdb, ok := dbm.dbs[et].(*compressedDB)
+		if !ok {
+			continue
+		}
+		if cdb.compress {
+			enabled[et] = true
+		}
+		count, err := rewriteValues(cdb)
+		if err != nil {
+			return total, fmt.Errorf("failed to rewrite %s: %w", dbBaseDirs[et], err)
+		}
+		logger.Info("Rewrote database values", "table", dbBaseDirs[et], "compressed", cdb.compress, "count", count)
+		total += count
+	}
+	writeCompressedTables(dbm.getDatabase(MiscDB), enabled)
+	return total, nil
+}
+
+// rewriteValues writes every value of the database again through it.
+func rewriteValues(db *compressedDB) (int, error) {
+	it := db.NewIterator(nil, nil)
+	defer it.Release()
+
+	batch := db.NewBatch()
+	defer batch.Release()
+
+	count := 0
+	for it.Next() {
+		if err := batch.Put(common.CopyBytes(it.Key()), common.CopyBytes(it.Value())); err != nil {
+			return count, err
+		}
+		if _, err := WriteBatchesOverThreshold(batch); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := it.Error(); err != nil {
+		return count, err
+	}
+	if err := batch.Write(); err != nil {
+		return count, err
+	}
+	return count, nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/kaiachain/kaia/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressValue(t *testing.T) {
+	small := []byte("small value")
+	assert.Equal(t, small, compressValue(small))
+
+	large := bytes.Repeat([]byte("compressible"), 100)
+	compressed := compressValue(large)
+	assert.Less(t, len(compressed), len(large))
+	assert.Equal(t, large, decompressValue(compressed))
+
+	// A value not shrinking is stored as it is.
+	random := make([]byte, 2*compressionThreshold)
+	rand.New(rand.NewSource(1)).Read(random)
+	assert.Equal(t, random, compressValue(random))
+
+	// A raw value looking like a zstd frame is always compressed to stay unambiguous.
+	framed := append(append([]byte{}, zstdMagic...), 0x01)
+	assert.NotEqual(t, framed, compressValue(framed))
+	assert.Equal(t, framed, decompressValue(compressValue(framed)))
+	assert.Equal(t, framed, decompressValue(framed))
+}
+
+func TestDBManager_CompressedTables(t *testing.T) {
+	log.EnableLogForTest(log.LvlCrit, log.LvlTrace)
+	var (
+		dbc   = &DBConfig{Dir: t.TempDir(), DBType: LevelDB, NumStateTrieShards: 2, LevelDBCacheSize: 16, OpenFilesLimit: 32}
+		key   = []byte("key")
+		value = bytes.Repeat([]byte("compressible"), 100)
+	)
+	rawGet := func(dbm DBManager) []byte {
+		db := dbm.getDatabase(BodyDB)
+		if cdb, ok := db.(*compressedDB); ok {
+			db = cdb.Database
+		}
+		data, err := db.Get(key)
+		require.NoError(t, err)
+		return data
+	}
+
+	// An uncompressed value written before the compression is enabled.
+	dbm := NewDBManager(dbc)
+	require.NoError(t, dbm.getDatabase(BodyDB).Put(key, value))
+	dbm.Close()
+
+	compressedDBC := *dbc
+	compressedDBC.CompressedTables = []string{"body", "receipts"}
+	dbm = NewDBManager(&compressedDBC)
+	assert.Equal(t, value, rawGet(dbm))
+	count, err := dbm.MigrateCompression()
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Less(t, len(rawGet(dbm)), len(value))
+	data, err := dbm.getDatabase(BodyDB).Get(key)
+	require.NoError(t, err)
+	assert.Equal(t, value, data)
+	dbm.Close()
+
+	// The compressed values are still readable after the compression is disabled.
+	dbm = NewDBManager(dbc)
+	data, err = dbm.getDatabase(BodyDB).Get(key)
+	require.NoError(t, err)
+	assert.Equal(t, value, data)
+	_, err = dbm.MigrateCompression()
+	require.NoError(t, err)
+	assert.Equal(t, value, rawGet(dbm))
+	dbm.Close()
+
+	// Once restored, the tables are not wrapped anymore.
+	dbm = NewDBManager(dbc)
+	_, ok := dbm.getDatabase(BodyDB).(*compressedDB)
+	assert.False(t, ok)
+	dbm.Close()
+
+	unknownDBC := *dbc
+	unknownDBC.CompressedTables = []string{"misc"}
+	_, err = databaseDBManager(&unknownDBC)
+	assert.Error(t, err)
+}
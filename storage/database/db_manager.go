@@ -30,6 +30,8 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/dgraph-io/badger"
 	"github.com/kaiachain/kaia/blockchain/types"
@@ -53,6 +55,7 @@ var (
 type DBManager interface {
 	IsParallelDBWrite() bool
 	IsSingle() bool
+	IsReadOnly() bool
 	InMigration() bool
 	MigrationBlockNumber() uint64
 	getStateTrieMigrationInfo() uint64
@@ -141,11 +144,13 @@ type DBManager interface {
 
 	// Bytecodes related operations
 	ReadCode(hash common.Hash) []byte
+	ReadCodes(hashes []common.Hash) [][]byte
 	ReadCodeWithPrefix(hash common.Hash) []byte
 	WriteCode(hash common.Hash, code []byte)
 	PutCodeToBatch(batch Batch, hash common.Hash, code []byte)
 	DeleteCode(hash common.Hash)
 	HasCode(hash common.Hash) bool
+	CopyCodesToLegacyScheme() (int, error)
 
 	// State Trie Database related operations
 	ReadTrieNode(hash common.ExtHash) ([]byte, error)
@@ -169,6 +174,8 @@ type DBManager interface {
 	WriteTrieNode(hash common.ExtHash, node []byte)
 	PutTrieNodeToBatch(batch Batch, hash common.ExtHash, node []byte)
 	DeleteTrieNode(hash common.ExtHash)
+	SetTrieNodeWriteHook(hook func(hash common.ExtHash))
+	NewStateTrieDBIterator(prefix []byte, start []byte) Iterator
 	WritePreimages(number uint64, preimages map[common.Hash][]byte)
 
 	// Trie pruning
@@ -183,6 +190,26 @@ type DBManager interface {
 	WriteLastPrunedBlockNumber(blockNumber uint64)
 	ReadLastPrunedBlockNumber() (uint64, error)
 
+	// Value compression
+	MigrateCompression() (int, error)
+
+	// Receipt pruning
+	PruneReceipts(from, to uint64) int
+	WriteLastPrunedReceiptBlockNumber(blockNumber uint64)
+	ReadLastPrunedReceiptBlockNumber() (uint64, error)
+
+	// Online state pruning
+	ReadStatePruningCheckpoint() []byte
+	WriteStatePruningCheckpoint(checkpoint []byte)
+	DeleteStatePruningCheckpoint()
+
+	// State trie transition
+	ReadTrieMirrorRoot(root common.ExtHash) (common.ExtHash, bool)
+	WriteTrieMirrorRoot(root, mirrorRoot common.ExtHash)
+
+	QuarantineTrieNode(hash common.ExtHash, node []byte)
+	ReadQuarantinedTrieNodes() []common.ExtHash
+
 	// from accessors_indexes.go
 	ReadTxLookupEntry(hash common.Hash) (common.Hash, uint64, uint64)
 	WriteTxLookupEntries(block *types.Block)
@@ -209,6 +236,9 @@ type DBManager interface {
 	// from accessors_metadata.go
 	ReadDatabaseVersion() *uint64
 	WriteDatabaseVersion(version uint64)
+	ReadDatabaseAppVersion() string
+	WriteDatabaseAppVersion(version string)
+	DeleteDatabaseAppVersion()
 
 	ReadChainConfig(hash common.Hash) *params.ChainConfig
 	WriteChainConfig(hash common.Hash, cfg *params.ChainConfig)
@@ -230,6 +260,9 @@ type DBManager interface {
 	WriteSnapshotRecoveryNumber(number uint64)
 	DeleteSnapshotRecoveryNumber()
 
+	ReadSnapshotStorageWipes() []common.Hash
+	DeleteSnapshotWipeMarker()
+
 	ReadSnapshotSyncStatus() []byte
 	WriteSnapshotSyncStatus(status []byte)
 	DeleteSnapshotSyncStatus()
@@ -243,6 +276,7 @@ type DBManager interface {
 	DeleteAccountSnapshot(hash common.Hash)
 
 	ReadStorageSnapshot(accountHash, storageHash common.Hash) []byte
+	ReadStorageSnapshots(accountHash common.Hash, storageHashes []common.Hash) [][]byte
 	WriteStorageSnapshot(accountHash, storageHash common.Hash, entry []byte)
 	DeleteStorageSnapshot(accountHash, storageHash common.Hash)
 
@@ -300,6 +334,11 @@ type DBManager interface {
 	HasStakingInfo(blockNum uint64) (bool, error)
 	DeleteStakingInfo(blockNum uint64)
 
+	// StateDiff related functions
+	ReadStateDiff(hash common.Hash, number uint64) []byte
+	WriteStateDiff(hash common.Hash, number uint64, stateDiff []byte)
+	DeleteStateDiff(hash common.Hash, number uint64)
+
 	// TotalSupply checkpoint functions
 	ReadSupplyCheckpoint(blockNum uint64) *SupplyCheckpoint
 	WriteSupplyCheckpoint(blockNum uint64, checkpoint *SupplyCheckpoint)
@@ -317,7 +356,9 @@ type DBManager interface {
 	TryCatchUpWithPrimary() error
 
 	Stat(string) (string, error)
+	DBStats() []*DBStats
 	Compact([]byte, []byte) error
+	ReportBlockImportTime(elapsed time.Duration)
 }
 
 type DBEntryType uint8
@@ -441,6 +482,14 @@ type databaseManager struct {
 	lockInMigration      sync.RWMutex
 	inMigration          bool
 	migrationBlockNumber uint64
+
+	trieNodeWriteHook atomic.Pointer[func(hash common.ExtHash)] // called before a trie node is written
+
+	freezer     *freezer // Ancient store of old canonical blocks, nil if disabled
+	freezerQuit chan struct{}
+	freezerWg   sync.WaitGroup
+
+	compaction *compactionScheduler // Compaction during the configured window, nil if disabled
 }
 
 func NewMemoryDBManager() DBManager {
@@ -461,11 +510,14 @@ type DBConfig struct {
 	// General configurations for all types of DB.
 	Dir                 string
 	DBType              DBType
-	SingleDB            bool // whether dbs (such as MiscDB, headerDB and etc) share one physical DB
-	NumStateTrieShards  uint // the number of shards of state trie db
+	SingleDB            bool     // whether dbs (such as MiscDB, headerDB and etc) share one physical DB
+	NumStateTrieShards  uint     // the number of shards of state trie db
+	StateTrieShardDirs  []string // directories to spread the state trie db shards over, round-robin (empty = inside Dir)
 	ParallelDBWrite     bool
 	OpenFilesLimit      int
-	EnableDBPerfMetrics bool // If true, read and write performance will be logged
+	EnableDBPerfMetrics bool     // If true, read and write performance will be logged
+	ReadOnly            bool     // If true, LevelDB and PebbleDB are opened read-only
+	CompressedTables    []string // names of the tables whose large values are compressed with zstd, e.g. "body", "receipts"
 
 	// LevelDB related configurations.
 	LevelDBCacheSize   int // LevelDBCacheSize = BlockCacheCapacity + WriteBuffer
@@ -480,13 +532,24 @@ type DBConfig struct {
 
 	// DynamoDB related configurations
 	DynamoDBConfig *DynamoDBConfig
+
+	// Ancient store related configurations
+	AncientThreshold uint64 // Number of recent blocks kept in the key-value databases; 0 disables the ancient store
+	AncientDir       string // Directory of the ancient store; defaults to "ancient" under Dir
+
+	// Scheduled compaction related configurations
+	CompactionWindow       string        // Daily window "HH:MM-HH:MM" in UTC to compact the databases; empty disables it
+	CompactionLatencyLimit time.Duration // Block import time above which the scheduled compaction pauses; 0 disables throttling
 }
 
 const dbMetricPrefix = "klay/db/chaindata/"
 
 // singleDatabaseDBManager returns DBManager which handles one single Database.
 // Each Database will share one common Database.
-func singleDatabaseDBManager(dbc *DBConfig) (DBManager, error) {
+func singleDatabaseDBManager(dbc *DBConfig) (*databaseManager, error) {
+	if len(dbc.CompressedTables) > 0 {
+		return nil, errCompressionSingleDB
+	}
 	dbm := newDatabaseManager(dbc)
 	db, err := newDatabase(dbc, 0)
 	if err != nil {
@@ -509,6 +572,9 @@ func newMiscDB(dbc *DBConfig) Database {
 	}
 
 	db.Meter(dbMetricPrefix + dbBaseDirs[MiscDB] + "/")
+	if dbc.ReadOnly {
+		return newReadOnlyDB(db)
+	}
 	return db
 }
 
@@ -523,6 +589,12 @@ func databaseDBManager(dbc *DBConfig) (*databaseManager, error) {
 	miscDB := newMiscDB(dbc)
 	dbm.dbs[MiscDB] = miscDB
 
+	compressedTables, err := openCompressedTables(dbc, miscDB)
+	if err != nil {
+		miscDB.Close()
+		return nil, err
+	}
+
 	// Create other DBs
 	for et := int(MiscDB) + 1; et < int(databaseEntryTypeSize); et++ {
 		entryType := DBEntryType(et)
@@ -550,6 +622,9 @@ func databaseDBManager(dbc *DBConfig) (*databaseManager, error) {
 		if err != nil {
 			logger.Crit("Failed while generating databases", "DBType", dbBaseDirs[et], "err", err)
 		}
+		if compress, ok := compressedTables[entryType]; ok {
+			db = newCompressedDB(db, compress)
+		}
 
 		dbm.dbs[et] = db
 		db.Meter(dbMetricPrefix + dbBaseDirs[et] + "/") // Each database collects metrics independently.
@@ -596,6 +671,16 @@ func NewDBManager(dbc *DBConfig) DBManager {
 		if dbm, err := singleDatabaseDBManager(dbc); err != nil {
 			logger.Crit("Failed to create a single database", "DBType", dbc.DBType, "err", err)
 		} else {
+			if dbc.AncientThreshold > 0 {
+				if err := dbm.openFreezer(); err != nil {
+					logger.Crit("Failed to open ancient store", "err", err)
+				}
+			}
+			if dbc.CompactionWindow != "" && !dbc.ReadOnly {
+				if err := dbm.openCompactionScheduler(); err != nil {
+					logger.Crit("Failed to schedule database compaction", "err", err)
+				}
+			}
 			return dbm
 		}
 	} else {
@@ -614,6 +699,16 @@ func NewDBManager(dbc *DBConfig) DBManager {
 				dbm.migrationBlockNumber = migrationBlockNum
 			}
 		}
+		if dbc.AncientThreshold > 0 {
+			if err := dbm.openFreezer(); err != nil {
+				logger.Crit("Failed to open ancient store", "err", err)
+			}
+		}
+		if dbc.CompactionWindow != "" && !dbc.ReadOnly {
+			if err := dbm.openCompactionScheduler(); err != nil {
+				logger.Crit("Failed to schedule database compaction", "err", err)
+			}
+		}
 		return dbm
 	}
 	logger.Crit("Must not reach here!")
@@ -628,6 +723,11 @@ func (dbm *databaseManager) IsSingle() bool {
 	return dbm.config.SingleDB
 }
 
+// IsReadOnly returns true if the databases are opened without write access.
+func (dbm *databaseManager) IsReadOnly() bool {
+	return dbm.config.ReadOnly
+}
+
 func (dbm *databaseManager) InMigration() bool {
 	dbm.lockInMigration.RLock()
 	defer dbm.lockInMigration.RUnlock()
@@ -866,6 +966,13 @@ func (dbm *databaseManager) removeOldDB(dbPath string, endCheck chan struct{}) {
 			close(endCheck)
 		}
 	}()
+	// Shards placed on other disks are linked from dbPath, so remove their targets first.
+	for _, target := range shardLinkTargets(dbPath) {
+		if err := os.RemoveAll(target); err != nil {
+			logger.Error("Failed to remove the database shard due to an error", "err", err, "dir", target)
+			return
+		}
+	}
 	if err := os.RemoveAll(dbPath); err != nil {
 		logger.Error("Failed to remove the database due to an error", "err", err, "dir", dbPath)
 		return
@@ -965,6 +1072,9 @@ func (dbm *databaseManager) getDatabase(dbEntryType DBEntryType) Database {
 }
 
 func (dbm *databaseManager) Close() {
+	dbm.closeCompactionScheduler()
+	dbm.closeFreezer()
+
 	// If single DB, only close the first database.
 	if dbm.config.SingleDB {
 		dbm.dbs[0].Close()
@@ -1143,7 +1253,7 @@ func (dbm *databaseManager) HasHeader(hash common.Hash, number uint64) bool {
 
 	db := dbm.getDatabase(headerDB)
 	if has, err := db.Has(headerKey(number, hash)); !has || err != nil {
-		return false
+		return len(dbm.readAncient(freezerHeaderTable, hash, number)) > 0
 	}
 	return true
 }
@@ -1173,6 +1283,9 @@ func (dbm *databaseManager) ReadHeader(hash common.Hash, number uint64) *types.H
 func (dbm *databaseManager) ReadHeaderRLP(hash common.Hash, number uint64) rlp.RawValue {
 	db := dbm.getDatabase(headerDB)
 	data, _ := db.Get(headerKey(number, hash))
+	if len(data) == 0 {
+		data = dbm.readAncient(freezerHeaderTable, hash, number)
+	}
 	return data
 }
 
@@ -1211,6 +1324,7 @@ func (dbm *databaseManager) DeleteHeader(hash common.Hash, number uint64) {
 	if err := db.Delete(headerKey(number, hash)); err != nil {
 		logger.Crit("Failed to delete header", "err", err)
 	}
+	dbm.truncateAncients(hash, number)
 	if err := db.Delete(headerNumberKey(hash)); err != nil {
 		logger.Crit("Failed to delete hash to number mapping", "err", err)
 	}
@@ -1244,7 +1358,7 @@ func (dbm *databaseManager) ReadHeaderNumber(hash common.Hash) *uint64 {
 func (dbm *databaseManager) HasBody(hash common.Hash, number uint64) bool {
 	db := dbm.getDatabase(BodyDB)
 	if has, err := db.Has(blockBodyKey(number, hash)); !has || err != nil {
-		return false
+		return len(dbm.readAncient(freezerBodiesTable, hash, number)) > 0
 	}
 	return true
 }
@@ -1294,6 +1408,9 @@ func (dbm *databaseManager) ReadBodyRLP(hash common.Hash, number uint64) rlp.Raw
 	// not found in cache, find body in database
 	db := dbm.getDatabase(BodyDB)
 	data, _ := db.Get(blockBodyKey(number, hash))
+	if len(data) == 0 {
+		data = dbm.readAncient(freezerBodiesTable, hash, number)
+	}
 
 	// Write to cache at the end of successful read.
 	dbm.cm.writeBodyRLPCache(hash, data)
@@ -1323,6 +1440,9 @@ func (dbm *databaseManager) ReadBodyRLPByHash(hash common.Hash) rlp.RawValue {
 
 	db := dbm.getDatabase(BodyDB)
 	data, _ := db.Get(blockBodyKey(*number, hash))
+	if len(data) == 0 {
+		data = dbm.readAncient(freezerBodiesTable, hash, *number)
+	}
 
 	// Write to cache at the end of successful read.
 	dbm.cm.writeBodyRLPCache(hash, data)
@@ -1371,6 +1491,7 @@ func (dbm *databaseManager) DeleteBody(hash common.Hash, number uint64) {
 	if err := db.Delete(blockBodyKey(number, hash)); err != nil {
 		logger.Crit("Failed to delete block body", "err", err)
 	}
+	dbm.truncateAncients(hash, number)
 	dbm.cm.deleteBodyCache(hash)
 }
 
@@ -1442,6 +1563,9 @@ func (dbm *databaseManager) ReadReceipts(blockHash common.Hash, number uint64) t
 	db := dbm.getDatabase(ReceiptsDB)
 	// Retrieve the flattened receipt slice
 	data, _ := db.Get(blockReceiptsKey(number, blockHash))
+	if len(data) == 0 {
+		data = dbm.readAncient(freezerReceiptTable, blockHash, number)
+	}
 	if len(data) == 0 {
 		return nil
 	}
@@ -1512,6 +1636,7 @@ func (dbm *databaseManager) DeleteReceipts(hash common.Hash, number uint64) {
 	if err := db.Delete(blockReceiptsKey(number, hash)); err != nil {
 		logger.Crit("Failed to delete block receipts", "err", err)
 	}
+	dbm.truncateAncients(hash, number)
 
 	// Delete blockReceiptsCache and txReceiptCache.
 	dbm.cm.deleteBlockReceiptsCache(hash)
@@ -1783,6 +1908,45 @@ func (dbm *databaseManager) ReadCode(hash common.Hash) []byte {
 	return dbm.ReadCodeWithPrefix(hash)
 }
 
+// ReadCodes retrieves the contract codes of the provided code hashes in grouped
+// reads. The code of a missing hash is nil.
+func (dbm *databaseManager) ReadCodes(hashes []common.Hash) [][]byte {
+	db := dbm.getDatabase(StateTrieDB)
+
+	keys := make([][]byte, len(hashes))
+	for i, hash := range hashes {
+		keys[i] = common.CopyBytes(hash[:])
+	}
+	codes, err := MultiGet(db, keys)
+	if err != nil {
+		logger.Error("Failed to read codes", "err", err)
+		codes = make([][]byte, len(hashes))
+	}
+	// Retry the codes missing in the legacy scheme with the current scheme.
+	var (
+		missing     []int
+		missingKeys [][]byte
+	)
+	for i, code := range codes {
+		if len(code) == 0 {
+			missing = append(missing, i)
+			missingKeys = append(missingKeys, CodeKey(hashes[i]))
+		}
+	}
+	if len(missing) == 0 {
+		return codes
+	}
+	prefixed, err := MultiGet(db, missingKeys)
+	if err != nil {
+		logger.Error("Failed to read codes", "err", err)
+		return codes
+	}
+	for j, i := range missing {
+		codes[i] = prefixed[j]
+	}
+	return codes
+}
+
 // ReadCodeWithPrefix retrieves the contract code of the provided code hash.
 // The main difference between this function and ReadCode is this function
 // will only check the existence with latest scheme(with prefix).
@@ -1815,6 +1979,39 @@ func (dbm *databaseManager) HasCodeWithPrefix(hash common.Hash) bool {
 	return ok
 }
 
+// CopyCodesToLegacyScheme copies the contract codes stored with the prefix to the legacy
+// scheme, which is keyed by the code hash alone, and returns the number of copied codes.
+func (dbm *databaseManager) CopyCodesToLegacyScheme() (int, error) {
+	db := dbm.getDatabase(StateTrieDB)
+	it := db.NewIterator(codePrefix, nil)
+	defer it.Release()
+
+	batch := db.NewBatch()
+	defer batch.Release()
+
+	copied := 0
+	for it.Next() {
+		ok, hash := IsCodeKey(it.Key())
+		if !ok {
+			continue
+		}
+		if err := batch.Put(common.CopyBytes(hash), common.CopyBytes(it.Value())); err != nil {
+			return copied, err
+		}
+		copied++
+		if batch.ValueSize() > IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return copied, err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return copied, err
+	}
+	return copied, batch.Write()
+}
+
 // WriteCode writes the provided contract code database.
 func (dbm *databaseManager) WriteCode(hash common.Hash, code []byte) {
 	dbm.lockInMigration.RLock()
@@ -1941,6 +2138,8 @@ func (dbm *databaseManager) ReadPreimageFromOld(hash common.Hash) []byte {
 }
 
 func (dbm *databaseManager) WriteTrieNode(hash common.ExtHash, node []byte) {
+	dbm.callTrieNodeWriteHook(hash)
+
 	dbm.lockInMigration.RLock()
 	defer dbm.lockInMigration.RUnlock()
 
@@ -1955,6 +2154,7 @@ func (dbm *databaseManager) WriteTrieNode(hash common.ExtHash, node []byte) {
 }
 
 func (dbm *databaseManager) PutTrieNodeToBatch(batch Batch, hash common.ExtHash, node []byte) {
+	dbm.callTrieNodeWriteHook(hash)
 	if err := batch.Put(TrieNodeKey(hash), node); err != nil {
 		logger.Crit("Failed to store trie node", "err", err)
 	}
@@ -1967,6 +2167,28 @@ func (dbm *databaseManager) DeleteTrieNode(hash common.ExtHash) {
 	}
 }
 
+// SetTrieNodeWriteHook sets the function called with the hash of every trie node before it is
+// written to the state trie database, or removes it if nil. It lets the online state pruner keep
+// the nodes written while it runs.
+func (dbm *databaseManager) SetTrieNodeWriteHook(hook func(hash common.ExtHash)) {
+	if hook == nil {
+		dbm.trieNodeWriteHook.Store(nil)
+	} else {
+		dbm.trieNodeWriteHook.Store(&hook)
+	}
+}
+
+func (dbm *databaseManager) callTrieNodeWriteHook(hash common.ExtHash) {
+	if hook := dbm.trieNodeWriteHook.Load(); hook != nil {
+		(*hook)(hash)
+	}
+}
+
+// NewStateTrieDBIterator returns an iterator over the state trie database.
+func (dbm *databaseManager) NewStateTrieDBIterator(prefix []byte, start []byte) Iterator {
+	return dbm.getDatabase(StateTrieDB).NewIterator(prefix, start)
+}
+
 // WritePreimages writes the provided set of preimages to the database. `number` is the
 // current block number, and is used for debug messages only.
 func (dbm *databaseManager) WritePreimages(number uint64, preimages map[common.Hash][]byte) {
@@ -2096,6 +2318,134 @@ func (dbm *databaseManager) ReadLastPrunedBlockNumber() (uint64, error) {
 	return binary.LittleEndian.Uint64(lastPruned), nil
 }
 
+// ReadStatePruningCheckpoint retrieves the serialized progress of an interrupted online state pruning session.
+func (dbm *databaseManager) ReadStatePruningCheckpoint() []byte {
+	db := dbm.getDatabase(MiscDB)
+	data, _ := db.Get(statePruningCheckpointKey)
+	return data
+}
+
+// WriteStatePruningCheckpoint stores the serialized progress of the running online state pruning session.
+func (dbm *databaseManager) WriteStatePruningCheckpoint(checkpoint []byte) {
+	db := dbm.getDatabase(MiscDB)
+	if err := db.Put(statePruningCheckpointKey, checkpoint); err != nil {
+		logger.Crit("Failed to store the state pruning checkpoint", "err", err)
+	}
+}
+
+// DeleteStatePruningCheckpoint deletes the progress of the online state pruning session.
+func (dbm *databaseManager) DeleteStatePruningCheckpoint() {
+	db := dbm.getDatabase(MiscDB)
+	if err := db.Delete(statePruningCheckpointKey); err != nil {
+		logger.Crit("Failed to remove the state pruning checkpoint", "err", err)
+	}
+}
+
+// ReadTrieMirrorRoot retrieves the root of the mirror trie committed along the trie of the given root.
+func (dbm *databaseManager) ReadTrieMirrorRoot(root common.ExtHash) (common.ExtHash, bool) {
+	db := dbm.getDatabase(MiscDB)
+	data, _ := db.Get(trieMirrorRootKey(root))
+	if len(data) != common.ExtHashLength {
+		return common.ExtHash{}, false
+	}
+	return common.BytesToExtHash(data), true
+}
+
+// WriteTrieMirrorRoot stores the root of the mirror trie committed along the trie of the given root.
+func (dbm *databaseManager) WriteTrieMirrorRoot(root, mirrorRoot common.ExtHash) {
+	db := dbm.getDatabase(MiscDB)
+	if err := db.Put(trieMirrorRootKey(root), mirrorRoot.Bytes()); err != nil {
+		logger.Crit("Failed to store the trie mirror root", "err", err)
+	}
+}
+
+// QuarantineTrieNode moves the corrupted value of a trie node out of the state trie database, so
+// that the node is treated as missing instead of being decoded. The value is kept for inspection.
+func (dbm *databaseManager) QuarantineTrieNode(hash common.ExtHash, node []byte) {
+	if err := dbm.getDatabase(MiscDB).Put(quarantinedTrieNodeKey(hash), node); err != nil {
+		logger.Crit("Failed to store the quarantined trie node", "err", err)
+	}
+
+	dbm.lockInMigration.RLock()
+	defer dbm.lockInMigration.RUnlock()
+
+	if dbm.inMigration {
+		if err := dbm.getDatabase(StateTrieMigrationDB).Delete(TrieNodeKey(hash)); err != nil {
+			logger.Crit("Failed to delete the quarantined trie node", "err", err)
+		}
+	}
+	if err := dbm.getDatabase(StateTrieDB).Delete(TrieNodeKey(hash)); err != nil {
+		logger.Crit("Failed to delete the quarantined trie node", "err", err)
+	}
+}
+
+// ReadQuarantinedTrieNodes returns the hashes of the trie nodes quarantined as corrupted.
+func (dbm *databaseManager) ReadQuarantinedTrieNodes() []common.ExtHash {
+	db := dbm.getDatabase(MiscDB)
+	it := db.NewIterator(quarantinedTrieNodePrefix, nil)
+	defer it.Release()
+
+	var hashes []common.ExtHash
+	for it.Next() {
+		if key := it.Key(); len(key) == len(quarantinedTrieNodePrefix)+common.ExtHashLength {
+			hashes = append(hashes, common.BytesToExtHash(key[len(quarantinedTrieNodePrefix):]))
+		}
+	}
+	return hashes
+}
+
+// PruneReceipts deletes the receipts of the canonical blocks in [from, to] from
+// the receipts database, leaving their headers and bodies untouched, and returns
+// the number of blocks whose receipts were deleted. Receipts already moved into
+// the ancient store are kept.
+func (dbm *databaseManager) PruneReceipts(from, to uint64) int {
+	batch := dbm.NewBatch(ReceiptsDB)
+	defer batch.Release()
+
+	pruned := 0
+	for number := from; number <= to; number++ {
+		hash := dbm.ReadCanonicalHash(number)
+		if common.EmptyHash(hash) {
+			continue
+		}
+		if err := batch.Delete(blockReceiptsKey(number, hash)); err != nil {
+			logger.Crit("Failed to prune block receipts", "err", err)
+		}
+		if _, err := WriteBatchesOverThreshold(batch); err != nil {
+			logger.Crit("Failed to prune block receipts", "err", err)
+		}
+		if receipts := dbm.ReadBlockReceiptsInCache(hash); receipts != nil {
+			for _, receipt := range receipts {
+				dbm.cm.deleteTxReceiptCache(receipt.TxHash)
+			}
+		}
+		dbm.cm.deleteBlockReceiptsCache(hash)
+		pruned++
+	}
+	if err := batch.Write(); err != nil {
+		logger.Crit("Failed to batch prune block receipts", "err", err)
+	}
+	return pruned
+}
+
+// WriteLastPrunedReceiptBlockNumber records the number of the most recent block whose receipts are pruned.
+func (dbm *databaseManager) WriteLastPrunedReceiptBlockNumber(blockNumber uint64) {
+	db := dbm.getDatabase(MiscDB)
+	if err := db.Put(lastPrunedReceiptBlockNumberKey, common.Int64ToByteLittleEndian(blockNumber)); err != nil {
+		logger.Crit("Failed to store the last pruned receipt block number", "err", err)
+	}
+}
+
+// ReadLastPrunedReceiptBlockNumber reads the number of the most recent block whose receipts are pruned.
+func (dbm *databaseManager) ReadLastPrunedReceiptBlockNumber() (uint64, error) {
+	db := dbm.getDatabase(MiscDB)
+	lastPruned, err := db.Get(lastPrunedReceiptBlockNumberKey)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(lastPruned), nil
+}
+
 // ReadTxLookupEntry retrieves the positional metadata associated with a transaction
 // hash to allow retrieving the transaction or receipt by hash.
 func (dbm *databaseManager) ReadTxLookupEntry(hash common.Hash) (common.Hash, uint64, uint64) {
@@ -2308,6 +2658,30 @@ func (dbm *databaseManager) WriteDatabaseVersion(version uint64) {
 	}
 }
 
+// ReadDatabaseAppVersion retrieves the version of Kaia which last opened the database.
+func (dbm *databaseManager) ReadDatabaseAppVersion() string {
+	db := dbm.getDatabase(MiscDB)
+	enc, _ := db.Get(databaseAppVersionKey)
+	return string(enc)
+}
+
+// WriteDatabaseAppVersion stores the version of Kaia which opened the database.
+func (dbm *databaseManager) WriteDatabaseAppVersion(version string) {
+	db := dbm.getDatabase(MiscDB)
+	if err := db.Put(databaseAppVersionKey, []byte(version)); err != nil {
+		logger.Crit("Failed to store the database app version", "err", err)
+	}
+}
+
+// DeleteDatabaseAppVersion removes the version of Kaia which last opened the database,
+// so that any version supporting the database version can open it.
+func (dbm *databaseManager) DeleteDatabaseAppVersion() {
+	db := dbm.getDatabase(MiscDB)
+	if err := db.Delete(databaseAppVersionKey); err != nil {
+		logger.Crit("Failed to delete the database app version", "err", err)
+	}
+}
+
 // ReadChainConfig retrieves the consensus settings based on the given genesis hash.
 func (dbm *databaseManager) ReadChainConfig(hash common.Hash) *params.ChainConfig {
 	db := dbm.getDatabase(MiscDB)
@@ -2412,6 +2786,34 @@ func (dbm *databaseManager) DeleteSnapshotDisabled() {
 	}
 }
 
+// ReadSnapshotStorageWipes retrieves the hashes of the destructed accounts whose
+// storage snapshot is pending for the deletion.
+func (dbm *databaseManager) ReadSnapshotStorageWipes() []common.Hash {
+	db := dbm.getDatabase(SnapshotDB)
+	if ok, _ := db.Has(snapshotWipeMarkerKey); !ok {
+		return nil
+	}
+	it := db.NewIterator(snapshotStorageWipePrefix, nil)
+	defer it.Release()
+
+	var hashes []common.Hash
+	for it.Next() {
+		if key := it.Key(); len(key) == len(snapshotStorageWipePrefix)+common.HashLength {
+			hashes = append(hashes, common.BytesToHash(key[len(snapshotStorageWipePrefix):]))
+		}
+	}
+	return hashes
+}
+
+// DeleteSnapshotWipeMarker deletes the marker of the pending storage snapshot
+// deletions. It must be called only if no deletion is pending.
+func (dbm *databaseManager) DeleteSnapshotWipeMarker() {
+	db := dbm.getDatabase(SnapshotDB)
+	if err := db.Delete(snapshotWipeMarkerKey); err != nil {
+		logger.Crit("Failed to remove snapshot wipe marker", "err", err)
+	}
+}
+
 // ReadSnapshotRecoveryNumber retrieves the block number of the last persisted
 // snapshot layer.
 func (dbm *databaseManager) ReadSnapshotRecoveryNumber() *uint64 {
@@ -2528,6 +2930,22 @@ func (dbm *databaseManager) ReadStorageSnapshot(accountHash, storageHash common.
 	return data
 }
 
+// ReadStorageSnapshots retrieves the snapshot entries of the storage trie leaves of
+// an account in a grouped read. The entry of a missing leaf is nil.
+func (dbm *databaseManager) ReadStorageSnapshots(accountHash common.Hash, storageHashes []common.Hash) [][]byte {
+	db := dbm.getDatabase(SnapshotDB)
+	keys := make([][]byte, len(storageHashes))
+	for i, storageHash := range storageHashes {
+		keys[i] = StorageSnapshotKey(accountHash, storageHash)
+	}
+	data, err := MultiGet(db, keys)
+	if err != nil {
+		logger.Error("Failed to read storage snapshots", "err", err)
+		return make([][]byte, len(storageHashes))
+	}
+	return data
+}
+
 // WriteStorageSnapshot stores the snapshot entry of an storage trie leaf.
 func (dbm *databaseManager) WriteStorageSnapshot(accountHash, storageHash common.Hash, entry []byte) {
 	db := dbm.getDatabase(SnapshotDB)
@@ -3000,6 +3418,9 @@ type SnapshotDBBatch interface {
 
 	WriteSnapshotRecoveryNumber(number uint64)
 	DeleteSnapshotRecoveryNumber()
+
+	WriteSnapshotStorageWipe(accountHash common.Hash)
+	DeleteSnapshotStorageWipe(accountHash common.Hash)
 }
 
 type snapshotDBBatch struct {
@@ -3062,6 +3483,14 @@ func (batch *snapshotDBBatch) DeleteSnapshotRecoveryNumber() {
 	deleteSnapshotRecoveryNumber(batch)
 }
 
+func (batch *snapshotDBBatch) WriteSnapshotStorageWipe(accountHash common.Hash) {
+	writeSnapshotStorageWipe(batch, accountHash)
+}
+
+func (batch *snapshotDBBatch) DeleteSnapshotStorageWipe(accountHash common.Hash) {
+	deleteSnapshotStorageWipe(batch, accountHash)
+}
+
 func writeSnapshotRoot(db KeyValueWriter, root common.Hash) {
 	if err := db.Put(snapshotRootKey, root[:]); err != nil {
 		logger.Crit("Failed to store snapshot root", "err", err)
@@ -3147,3 +3576,18 @@ func deleteSnapshotRecoveryNumber(db KeyValueWriter) {
 		logger.Crit("Failed to remove snapshot recovery number", "err", err)
 	}
 }
+
+func writeSnapshotStorageWipe(db KeyValueWriter, accountHash common.Hash) {
+	if err := db.Put(snapshotStorageWipeKey(accountHash), []byte{0x01}); err != nil {
+		logger.Crit("Failed to store snapshot storage wipe", "err", err)
+	}
+	if err := db.Put(snapshotWipeMarkerKey, []byte{0x01}); err != nil {
+		logger.Crit("Failed to store snapshot wipe marker", "err", err)
+	}
+}
+
+func deleteSnapshotStorageWipe(db KeyValueWriter, accountHash common.Hash) {
+	if err := db.Delete(snapshotStorageWipeKey(accountHash)); err != nil {
+		logger.Crit("Failed to remove snapshot storage wipe", "err", err)
+	}
+}
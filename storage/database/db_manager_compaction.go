@@ -0,0 +1,235 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+const (
+	// compactionRecheckInterval is the interval to check whether the scheduled compaction can run.
+	compactionRecheckInterval = 30 * time.Second
+
+	// compactionRanges is the number of key ranges compacted one by one, split by the first byte of the keys.
+	compactionRanges = 256
+
+	// compactionLatencyExpiry is how long a reported block import time is used for throttling.
+	// A stale report is ignored so that the compaction is not blocked after block import stops.
+	compactionLatencyExpiry = time.Minute
+)
+
+var (
+	compactionRangesCounter    = metrics.NewRegisteredCounter("klay/db/compaction/scheduled/ranges", nil)
+	compactionThrottledMeter   = metrics.NewRegisteredMeter("klay/db/compaction/scheduled/throttled", nil)
+	compactionRangeTimeGauge   = metrics.NewRegisteredGauge("klay/db/compaction/scheduled/rangetime", nil)
+	compactionCompletedMeter   = metrics.NewRegisteredMeter("klay/db/compaction/scheduled/completed", nil)
+	errInvalidCompactionWindow = errors.New("invalid compaction window, expected HH:MM-HH:MM")
+)
+
+// compactionWindow is a daily time window in UTC, given in minutes of the day.
+// If start is greater than end, the window spans midnight.
+type compactionWindow struct {
+	start, end int
+}
+
+// parseCompactionWindow parses the window in the form of "HH:MM-HH:MM" in UTC.
+func parseCompactionWindow(s string) (*compactionWindow, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("%w: %q", errInvalidCompactionWindow, s)
+	}
+	var minutes [2]int
+	for i, part := range parts {
+		t, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", errInvalidCompactionWindow, s)
+		}
+		minutes[i] = t.Hour()*60 + t.Minute()
+	}
+	if minutes[0] == minutes[1] {
+		return nil, fmt.Errorf("%w: %q is empty", errInvalidCompactionWindow, s)
+	}
+	return &compactionWindow{start: minutes[0], end: minutes[1]}, nil
+}
+
+// occurrence returns the start time of the window containing t, or false if t is out of the window.
+func (w *compactionWindow) occurrence(t time.Time) (time.Time, bool) {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	minute := t.Hour()*60 + t.Minute()
+
+	switch {
+	case w.start < w.end && w.start <= minute && minute < w.end:
+		return day.Add(time.Duration(w.start) * time.Minute), true
+	case w.start > w.end && minute >= w.start:
+		return day.Add(time.Duration(w.start) * time.Minute), true
+	case w.start > w.end && minute < w.end:
+		return day.AddDate(0, 0, -1).Add(time.Duration(w.start) * time.Minute), true
+	}
+	return time.Time{}, false
+}
+
+// compactionScheduler compacts the whole key space range by range during the daily
+// low-traffic window, instead of leaving the timing entirely to the database.
+// The compaction pauses while block import is slower than the latency limit,
+// and resumes from the next range in the following round or window.
+type compactionScheduler struct {
+	window       *compactionWindow
+	latencyLimit time.Duration // 0 disables throttling
+	compact      func(start, limit []byte) error
+	now          func() time.Time
+
+	importTime atomic.Int64 // time taken to import the latest block
+	importedAt atomic.Int64 // unix nano time when importTime is reported
+
+	next int       // index of the next range to compact
+	done time.Time // start of the window in which the whole key space is compacted
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newCompactionScheduler(window *compactionWindow, latencyLimit time.Duration, compact func(start, limit []byte) error) *compactionScheduler {
+	return &compactionScheduler{
+		window:       window,
+		latencyLimit: latencyLimit,
+		compact:      compact,
+		now:          time.Now,
+		quit:         make(chan struct{}),
+	}
+}
+
+// compactionRange returns the i-th key range to compact.
+func compactionRange(i int) (start, limit []byte) {
+	if i > 0 {
+		start = []byte{byte(i)}
+	}
+	if i < compactionRanges-1 {
+		limit = []byte{byte(i + 1)}
+	}
+	return start, limit
+}
+
+// reportImportTime records the time taken to import the latest block.
+func (s *compactionScheduler) reportImportTime(elapsed time.Duration) {
+	s.importTime.Store(int64(elapsed))
+	s.importedAt.Store(s.now().UnixNano())
+}
+
+// throttled returns true if block import is recently slower than the latency limit.
+func (s *compactionScheduler) throttled(now time.Time) bool {
+	if s.latencyLimit == 0 {
+		return false
+	}
+	if now.Sub(time.Unix(0, s.importedAt.Load())) > compactionLatencyExpiry {
+		return false
+	}
+	return time.Duration(s.importTime.Load()) > s.latencyLimit
+}
+
+func (s *compactionScheduler) loop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(compactionRecheckInterval)
+	defer ticker.Stop()
+
+	for {
+		s.run()
+		select {
+		case <-s.quit:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run compacts the ranges one by one while it is in the window and not throttled.
+func (s *compactionScheduler) run() {
+	for {
+		now := s.now()
+		windowStart, ok := s.window.occurrence(now)
+		if !ok || windowStart.Equal(s.done) {
+			return
+		}
+		if s.throttled(now) {
+			compactionThrottledMeter.Mark(1)
+			return
+		}
+		select {
+		case <-s.quit:
+			return
+		default:
+		}
+
+		if s.next == 0 {
+			logger.Info("Starting scheduled database compaction", "window", windowStart)
+		}
+		start, limit := compactionRange(s.next)
+		begin := time.Now()
+		if err := s.compact(start, limit); err != nil {
+			logger.Error("Failed to compact database", "start", start, "limit", limit, "err", err)
+			return
+		}
+		compactionRangeTimeGauge.Update(int64(time.Since(begin)))
+		compactionRangesCounter.Inc(1)
+
+		if s.next++; s.next == compactionRanges {
+			s.next = 0
+			s.done = windowStart
+			compactionCompletedMeter.Mark(1)
+			logger.Info("Finished scheduled database compaction", "window", windowStart)
+			return
+		}
+	}
+}
+
+// openCompactionScheduler starts compacting the databases during CompactionWindow.
+func (dbm *databaseManager) openCompactionScheduler() error {
+	window, err := parseCompactionWindow(dbm.config.CompactionWindow)
+	if err != nil {
+		return err
+	}
+	dbm.compaction = newCompactionScheduler(window, dbm.config.CompactionLatencyLimit, dbm.Compact)
+	dbm.compaction.wg.Add(1)
+	go dbm.compaction.loop()
+
+	logger.Info("Scheduled database compaction", "window", dbm.config.CompactionWindow, "latencyLimit", dbm.config.CompactionLatencyLimit)
+	return nil
+}
+
+// closeCompactionScheduler stops the scheduled compaction, waiting for the running range compaction.
+func (dbm *databaseManager) closeCompactionScheduler() {
+	if dbm.compaction == nil {
+		return
+	}
+	close(dbm.compaction.quit)
+	dbm.compaction.wg.Wait()
+}
+
+// ReportBlockImportTime lets the scheduled compaction pause while block import is slow.
+func (dbm *databaseManager) ReportBlockImportTime(elapsed time.Duration) {
+	if dbm.compaction != nil {
+		dbm.compaction.reportImportTime(elapsed)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompactionWindow(t *testing.T) {
+	for _, s := range []string{"", "02:00", "02:00-", "25:00-03:00", "02:00-02:00", "2-3"} {
+		_, err := parseCompactionWindow(s)
+		assert.ErrorIs(t, err, errInvalidCompactionWindow, s)
+	}
+
+	at := func(day, hour, min int) time.Time { return time.Date(2024, 1, day, hour, min, 0, 0, time.UTC) }
+	testCases := []struct {
+		window string
+		t      time.Time
+		start  time.Time
+		ok     bool
+	}{
+		{"02:00-05:00", at(10, 1, 59), time.Time{}, false},
+		{"02:00-05:00", at(10, 2, 0), at(10, 2, 0), true},
+		{"02:00-05:00", at(10, 4, 59), at(10, 2, 0), true},
+		{"02:00-05:00", at(10, 5, 0), time.Time{}, false},
+		{"23:00-01:30", at(10, 23, 10), at(10, 23, 0), true},
+		{"23:00-01:30", at(11, 1, 0), at(10, 23, 0), true},
+		{"23:00-01:30", at(11, 1, 30), time.Time{}, false},
+		{"23:00-01:30", at(11, 12, 0), time.Time{}, false},
+	}
+	for _, tc := range testCases {
+		w, err := parseCompactionWindow(tc.window)
+		require.NoError(t, err)
+		start, ok := w.occurrence(tc.t)
+		assert.Equal(t, tc.ok, ok, "%s %v", tc.window, tc.t)
+		assert.Equal(t, tc.start, start, "%s %v", tc.window, tc.t)
+	}
+}
+
+func TestCompactionScheduler(t *testing.T) {
+	window, err := parseCompactionWindow("02:00-05:00")
+	require.NoError(t, err)
+
+	var (
+		now       = time.Date(2024, 1, 10, 1, 0, 0, 0, time.UTC)
+		compacted int
+		slowAt    = -1 // the range at which block import gets slow
+	)
+	s := newCompactionScheduler(window, time.Second, nil)
+	s.now = func() time.Time { return now }
+	s.compact = func(start, limit []byte) error {
+		expectedStart, expectedLimit := compactionRange(compacted)
+		assert.Equal(t, expectedStart, start)
+		assert.Equal(t, expectedLimit, limit)
+		if compacted++; compacted == slowAt {
+			s.reportImportTime(2 * time.Second)
+		}
+		return nil
+	}
+
+	// Out of the window
+	s.run()
+	assert.Equal(t, 0, compacted)
+
+	// Slow block import pauses the compaction
+	now = now.Add(time.Hour)
+	slowAt = 100
+	s.run()
+	assert.Equal(t, 100, compacted)
+	s.run()
+	assert.Equal(t, 100, compacted)
+
+	// Resumes when block import gets fast or the report is stale
+	now = now.Add(compactionLatencyExpiry + time.Second)
+	s.run()
+	assert.Equal(t, compactionRanges, compacted)
+	assert.Equal(t, 0, s.next)
+
+	// Compacts only once in a window
+	now = now.Add(time.Hour)
+	s.run()
+	assert.Equal(t, compactionRanges, compacted)
+
+	// Compacts again in the next window
+	compacted, slowAt = 0, -1
+	now = now.Add(24 * time.Hour)
+	s.reportImportTime(100 * time.Millisecond)
+	s.run()
+	assert.Equal(t, compactionRanges, compacted)
+}
+
+func TestDBManager_CompactionScheduler(t *testing.T) {
+	dbm := NewDBManager(&DBConfig{Dir: t.TempDir(), DBType: LevelDB, SingleDB: true, LevelDBCacheSize: 16, OpenFilesLimit: 32, CompactionWindow: "00:00-23:59"})
+	dm := dbm.(*databaseManager)
+	require.NotNil(t, dm.compaction)
+	dbm.ReportBlockImportTime(time.Millisecond)
+	dbm.Close()
+}
@@ -0,0 +1,194 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/kaiachain/kaia/common"
+)
+
+const (
+	// freezerRecheckInterval is the interval to check the chain head for
+	// blocks which became old enough to be moved into the freezer.
+	freezerRecheckInterval = time.Minute
+
+	// freezerBatchLimit is the maximum number of blocks frozen in one round.
+	freezerBatchLimit = 30000
+
+	// freezerDirName is the default directory of the freezer under the chain data.
+	freezerDirName = "ancient"
+)
+
+// openFreezer opens the freezer configured by AncientDir and starts moving
+// canonical blocks older than AncientThreshold into it.
+func (dbm *databaseManager) openFreezer() error {
+	dir := dbm.config.AncientDir
+	if dir == "" {
+		dir = filepath.Join(dbm.config.Dir, freezerDirName)
+	}
+	f, err := newFreezer(dir, dbm.config.ReadOnly)
+	if err != nil {
+		return err
+	}
+	dbm.freezer = f
+	logger.Info("Opened ancient store", "dir", dir, "frozen", f.ancients(), "threshold", dbm.config.AncientThreshold)
+
+	if !dbm.config.ReadOnly {
+		dbm.freezerQuit = make(chan struct{})
+		dbm.freezerWg.Add(1)
+		go dbm.freezeLoop()
+	}
+	return nil
+}
+
+// closeFreezer stops the freeze loop and closes the freezer.
+func (dbm *databaseManager) closeFreezer() {
+	if dbm.freezer == nil {
+		return
+	}
+	if dbm.freezerQuit != nil {
+		close(dbm.freezerQuit)
+		dbm.freezerWg.Wait()
+	}
+	if err := dbm.freezer.close(); err != nil {
+		logger.Error("Failed to close ancient store", "err", err)
+	}
+}
+
+// freezeLoop periodically moves the blocks older than AncientThreshold from
+// the key-value databases into the freezer.
+func (dbm *databaseManager) freezeLoop() {
+	defer dbm.freezerWg.Done()
+
+	ticker := time.NewTicker(freezerRecheckInterval)
+	defer ticker.Stop()
+
+	for {
+		number := dbm.ReadHeaderNumber(dbm.ReadHeadBlockHash())
+		if number != nil && *number > dbm.config.AncientThreshold {
+			if _, err := dbm.freezeRange(*number - dbm.config.AncientThreshold); err != nil {
+				logger.Error("Failed to freeze blocks", "err", err)
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-dbm.freezerQuit:
+			return
+		}
+	}
+}
+
+// freezeRange moves the canonical blocks below limit into the freezer, at
+// most freezerBatchLimit blocks at a time, and returns the number of frozen
+// blocks. The blocks are deleted from the key-value databases only after the
+// freezer has been flushed to disk.
+func (dbm *databaseManager) freezeRange(limit uint64) (uint64, error) {
+	var (
+		f      = dbm.freezer
+		first  = f.ancients()
+		hashes []common.Hash
+		start  = time.Now()
+	)
+	if limit > first+freezerBatchLimit {
+		limit = first + freezerBatchLimit
+	}
+	for number := first; number < limit; number++ {
+		hash := dbm.ReadCanonicalHash(number)
+		if common.EmptyHash(hash) {
+			logger.Error("Canonical hash missing, can't freeze", "number", number)
+			break
+		}
+		header, _ := dbm.getDatabase(headerDB).Get(headerKey(number, hash))
+		if len(header) == 0 {
+			// Blocks not yet downloaded, e.g. during fast sync.
+			break
+		}
+		body, _ := dbm.getDatabase(BodyDB).Get(blockBodyKey(number, hash))
+		receipts, _ := dbm.getDatabase(ReceiptsDB).Get(blockReceiptsKey(number, hash))
+		if err := f.append(number, hash, header, body, receipts); err != nil {
+			return 0, err
+		}
+		hashes = append(hashes, hash)
+	}
+	if len(hashes) == 0 {
+		return 0, nil
+	}
+	if err := f.sync(); err != nil {
+		return 0, err
+	}
+
+	headerBatch := dbm.NewBatch(headerDB)
+	bodyBatch := dbm.NewBatch(BodyDB)
+	receiptsBatch := dbm.NewBatch(ReceiptsDB)
+	defer headerBatch.Release()
+	defer bodyBatch.Release()
+	defer receiptsBatch.Release()
+
+	for i, hash := range hashes {
+		number := first + uint64(i)
+		if err := headerBatch.Delete(headerKey(number, hash)); err != nil {
+			return 0, err
+		}
+		if err := bodyBatch.Delete(blockBodyKey(number, hash)); err != nil {
+			return 0, err
+		}
+		if err := receiptsBatch.Delete(blockReceiptsKey(number, hash)); err != nil {
+			return 0, err
+		}
+		if _, err := WriteBatchesOverThreshold(headerBatch, bodyBatch, receiptsBatch); err != nil {
+			return 0, err
+		}
+	}
+	if _, err := WriteBatches(headerBatch, bodyBatch, receiptsBatch); err != nil {
+		return 0, err
+	}
+
+	logger.Info("Moved blocks into ancient store", "from", first, "to", first+uint64(len(hashes))-1,
+		"elapsed", common.PrettyDuration(time.Since(start)))
+	return uint64(len(hashes)), nil
+}
+
+// readAncient retrieves the item of the given kind from the freezer if the
+// block of the given hash and number has been frozen.
+func (dbm *databaseManager) readAncient(kind string, hash common.Hash, number uint64) []byte {
+	if dbm.freezer == nil || number >= dbm.freezer.ancients() {
+		return nil
+	}
+	if dbm.freezer.ancientHash(number) != hash {
+		return nil
+	}
+	data, _ := dbm.freezer.ancient(kind, number)
+	return data
+}
+
+// truncateAncients discards the frozen block of the given hash and number
+// and all blocks after it, which happens when the chain is rewound below the
+// frozen blocks.
+func (dbm *databaseManager) truncateAncients(hash common.Hash, number uint64) {
+	if dbm.freezer == nil || number >= dbm.freezer.ancients() {
+		return
+	}
+	if dbm.freezer.ancientHash(number) != hash {
+		return
+	}
+	if err := dbm.freezer.truncate(number); err != nil {
+		logger.Crit("Failed to truncate ancient store", "number", number, "err", err)
+	}
+}
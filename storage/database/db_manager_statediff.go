@@ -0,0 +1,48 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import "github.com/kaiachain/kaia/common"
+
+// ReadStateDiff retrieves the encoded state diff of the given block.
+// It returns nil if the state diff of the block is not stored.
+// StateDiff is stored in MiscDB.
+func (dbm *databaseManager) ReadStateDiff(hash common.Hash, number uint64) []byte {
+	db := dbm.getDatabase(MiscDB)
+
+	data, _ := db.Get(stateDiffKey(number, hash))
+	return data
+}
+
+// WriteStateDiff stores the encoded state diff of the given block.
+// stateDiff should be the RLP encoding of type StateDiff defined in blockchain/state/state_diff.go
+func (dbm *databaseManager) WriteStateDiff(hash common.Hash, number uint64, stateDiff []byte) {
+	db := dbm.getDatabase(MiscDB)
+
+	if err := db.Put(stateDiffKey(number, hash), stateDiff); err != nil {
+		logger.Crit("Failed to store state diff", "err", err)
+	}
+}
+
+// DeleteStateDiff removes the state diff of the given block.
+func (dbm *databaseManager) DeleteStateDiff(hash common.Hash, number uint64) {
+	db := dbm.getDatabase(MiscDB)
+
+	if err := db.Delete(stateDiffKey(number, hash)); err != nil {
+		logger.Crit("Failed to delete state diff", "err", err)
+	}
+}
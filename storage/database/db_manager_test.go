@@ -1317,3 +1317,52 @@ func genRandomData() (common.Hash, []byte) {
 	data := common.MakeRandomBytes(100)
 	return hash, data
 }
+
+func TestDBManager_ReadOnly(t *testing.T) {
+	log.EnableLogForTest(log.LvlCrit, log.LvlTrace)
+	for _, dbType := range []DBType{LevelDB, PebbleDB} {
+		dbc := &DBConfig{Dir: t.TempDir(), DBType: dbType, NumStateTrieShards: 2, LevelDBCacheSize: 16, PebbleDBCacheSize: 16, OpenFilesLimit: 32}
+		dbm := NewDBManager(dbc)
+		dbm.WriteCanonicalHash(hash1, num1)
+		dbm.Close()
+
+		readOnlyDBC := *dbc
+		readOnlyDBC.ReadOnly = true
+		dbm = NewDBManager(&readOnlyDBC)
+		assert.Equal(t, hash1, dbm.ReadCanonicalHash(num1), dbType)
+		assert.Error(t, dbm.getDatabase(headerDB).Put([]byte("key"), []byte("value")), dbType)
+
+		// The writes to the misc DB are kept in memory only.
+		miscDB := dbm.GetMiscDB()
+		assert.NoError(t, miscDB.Put([]byte("key"), []byte("value")), dbType)
+		value, err := miscDB.Get([]byte("key"))
+		assert.NoError(t, err, dbType)
+		assert.Equal(t, []byte("value"), value, dbType)
+		it := miscDB.NewIterator([]byte("ke"), nil)
+		assert.True(t, it.Next(), dbType)
+		assert.Equal(t, []byte("key"), it.Key(), dbType)
+		it.Release()
+		dbm.Close()
+
+		dbm = NewDBManager(&readOnlyDBC)
+		has, _ := dbm.GetMiscDB().Has([]byte("key"))
+		assert.False(t, has, dbType)
+		dbm.Close()
+	}
+}
+
+func TestDBManager_ReadCodes(t *testing.T) {
+	dbm := NewMemoryDBManager()
+	var (
+		legacy   = []byte{0x60, 0x01}
+		prefixed = []byte{0x60, 0x02}
+		hashes   = []common.Hash{crypto.Keccak256Hash(legacy), crypto.Keccak256Hash(prefixed), {0x01}}
+	)
+	assert.NoError(t, dbm.getDatabase(StateTrieDB).Put(hashes[0][:], legacy))
+	dbm.WriteCode(hashes[1], prefixed)
+
+	assert.Equal(t, [][]byte{legacy, prefixed, nil}, dbm.ReadCodes(hashes))
+	for i, hash := range hashes[:2] {
+		assert.Equal(t, dbm.ReadCode(hash), dbm.ReadCodes(hashes)[i])
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"io/fs"
+	"path/filepath"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// DBStats is the statistics of a logical database, used to attribute disk pressure to a database.
+type DBStats struct {
+	Name string `json:"name"`
+	Type DBType `json:"type"`
+	Dir  string `json:"dir,omitempty"`
+
+	Size      int64   `json:"size"`      // Bytes on disk
+	ReadRate  float64 `json:"readRate"`  // Bytes per second read from disk, averaged over a minute
+	WriteRate float64 `json:"writeRate"` // Bytes per second written to disk, averaged over a minute
+
+	CompactionBacklog int64 `json:"compactionBacklog"` // Estimated bytes waiting for compaction
+	OpenFiles         int   `json:"openFiles"`         // Number of the open table files
+}
+
+// statsProvider is implemented by the databases reporting engine statistics for DBStats.
+type statsProvider interface {
+	fillStats(stats *DBStats)
+}
+
+func (db *levelDB) fillStats(stats *DBStats) {
+	if db.diskReadMeter != nil {
+		stats.ReadRate += db.diskReadMeter.Rate1()
+		stats.WriteRate += db.diskWriteMeter.Rate1()
+	}
+	var s leveldb.DBStats
+	if err := db.db.Stats(&s); err != nil {
+		return
+	}
+	// LevelDB has no compaction debt estimate, so the level 0 tables waiting to be merged are used.
+	if len(s.LevelSizes) > 0 {
+		stats.CompactionBacklog += s.LevelSizes[0]
+	}
+	stats.OpenFiles += int(s.OpenedTablesCount)
+}
+
+func (d *pebbleDB) fillStats(stats *DBStats) {
+	// Pebble doesn't track non-compaction reads, so ReadRate is always zero.
+	if d.diskReadMeter != nil {
+		stats.ReadRate += d.diskReadMeter.Rate1()
+		stats.WriteRate += d.diskWriteMeter.Rate1()
+	}
+	m := d.db.Metrics()
+	stats.CompactionBacklog += int64(m.Compact.EstimatedDebt)
+	stats.OpenFiles += int(m.TableCache.Count)
+}
+
+func (db *shardedDB) fillStats(stats *DBStats) {
+	for _, shard := range db.shards {
+		if p, ok := shard.(statsProvider); ok {
+			p.fillStats(stats)
+		}
+	}
+}
+
+// dirSize returns the total size of the files under the directory.
+func dirSize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && !d.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// DBStats returns the statistics of each logical database and the ancient store.
+// If a single database is used, the statistics of the whole database are returned.
+func (dbm *databaseManager) DBStats() []*DBStats {
+	newStats := func(name string, db Database, dir string) *DBStats {
+		stats := &DBStats{Name: name, Type: db.Type()}
+		if dbm.config.DBType != MemoryDB && dbm.config.DBType != DynamoDB {
+			stats.Dir = dir
+			stats.Size = dirSize(dir)
+		}
+		if p, ok := db.(statsProvider); ok {
+			p.fillStats(stats)
+		}
+		return stats
+	}
+
+	var result []*DBStats
+	if dbm.config.SingleDB || dbm.config.DBType == MemoryDB {
+		result = append(result, newStats("chaindata", dbm.dbs[0], dbm.config.Dir))
+	} else {
+		for idx, db := range dbm.dbs {
+			if db == nil {
+				continue
+			}
+			entryType := DBEntryType(idx)
+			result = append(result, newStats(entryType.String(), db, filepath.Join(dbm.config.Dir, dbm.getDBDir(entryType))))
+		}
+	}
+	if dbm.freezer != nil {
+		result = append(result, &DBStats{Name: freezerDirName, Type: "freezer", Dir: dbm.freezer.dir, Size: dirSize(dbm.freezer.dir)})
+	}
+	return result
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDBManager_DBStats(t *testing.T) {
+	for _, dbType := range []DBType{LevelDB, PebbleDB} {
+		dir := t.TempDir()
+		dbm := NewDBManager(&DBConfig{Dir: dir, DBType: dbType, LevelDBCacheSize: 16, PebbleDBCacheSize: 16, OpenFilesLimit: 32})
+		header := &types.Header{Number: big.NewInt(1), Extra: make([]byte, 1024)}
+		dbm.WriteHeader(header)
+
+		stats := dbm.DBStats()
+		require.Len(t, stats, int(databaseEntryTypeSize)-1, dbType) // StateTrieMigrationDB is not opened
+		for _, s := range stats {
+			assert.Equal(t, dbType, s.Type)
+			assert.NotEmpty(t, s.Dir)
+			assert.NotZero(t, s.Size, s.Name)
+			assert.GreaterOrEqual(t, s.CompactionBacklog, int64(0))
+		}
+		assert.Equal(t, "misc", stats[MiscDB].Name)
+		assert.Equal(t, "header", stats[headerDB].Name)
+		dbm.Close()
+	}
+
+	// A single database is reported as a whole
+	dbm := NewDBManager(&DBConfig{Dir: t.TempDir(), DBType: LevelDB, SingleDB: true, LevelDBCacheSize: 16, OpenFilesLimit: 32})
+	defer dbm.Close()
+	stats := dbm.DBStats()
+	require.Len(t, stats, 1)
+	assert.Equal(t, "chaindata", stats[0].Name)
+
+	stats = NewMemoryDBManager().DBStats()
+	require.Len(t, stats, 1)
+	assert.Zero(t, stats[0].Size)
+}
@@ -0,0 +1,373 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/kaiachain/kaia/common"
+)
+
+// The freezer (ancient store) keeps canonical chain segments older than a
+// configurable threshold in append-only flat files instead of the key-value
+// databases. Each kind of data is stored in its own table, which consists of
+// a data file holding the raw RLP blobs back to back and an index file holding
+// the 8-byte end offset of every item. Item n of every table belongs to block n.
+const (
+	freezerHashTable     = "hashes"
+	freezerHeaderTable   = "headers"
+	freezerBodiesTable   = "bodies"
+	freezerReceiptTable  = "receipts"
+	freezerIndexItemSize = 8
+)
+
+var freezerTables = []string{freezerHashTable, freezerHeaderTable, freezerBodiesTable, freezerReceiptTable}
+
+var (
+	errOutOfBounds     = errors.New("out of bounds")
+	errOutOrderInsert  = errors.New("the append operation is out-order")
+	errUnknownTable    = errors.New("unknown table")
+	errFreezerReadOnly = errors.New("freezer is read-only")
+)
+
+// freezerTable is a single append-only table of the freezer.
+type freezerTable struct {
+	name  string
+	data  *os.File
+	index *os.File
+	items uint64 // Number of items stored in the table
+	size  uint64 // Number of bytes of the data file referenced by the index
+}
+
+// newFreezerTable opens the table of the given name in dir and drops any
+// trailing data which is not completely referenced by the index, which can be
+// left behind by an unclean shutdown.
+func newFreezerTable(dir, name string, readonly bool) (*freezerTable, error) {
+	flag := os.O_RDWR | os.O_CREATE
+	if readonly {
+		flag = os.O_RDONLY
+	}
+	data, err := os.OpenFile(filepath.Join(dir, name+".dat"), flag, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	index, err := os.OpenFile(filepath.Join(dir, name+".idx"), flag, 0o644)
+	if err != nil {
+		data.Close()
+		return nil, err
+	}
+	t := &freezerTable{name: name, data: data, index: index}
+	if err := t.repair(readonly); err != nil {
+		t.close()
+		return nil, err
+	}
+	return t, nil
+}
+
+// repair truncates a partially written index entry and drops index entries
+// pointing beyond the end of the data file.
+func (t *freezerTable) repair(readonly bool) error {
+	indexStat, err := t.index.Stat()
+	if err != nil {
+		return err
+	}
+	dataStat, err := t.data.Stat()
+	if err != nil {
+		return err
+	}
+	items := uint64(indexStat.Size()) / freezerIndexItemSize
+	dataSize := uint64(dataStat.Size())
+
+	var size uint64
+	for ; items > 0; items-- {
+		if size, err = t.offset(items - 1); err != nil {
+			return err
+		}
+		if size <= dataSize {
+			break
+		}
+	}
+	if items == 0 {
+		size = 0
+	}
+	t.items, t.size = items, size
+
+	if readonly {
+		return nil
+	}
+	if err := t.index.Truncate(int64(items * freezerIndexItemSize)); err != nil {
+		return err
+	}
+	return t.data.Truncate(int64(size))
+}
+
+// offset returns the end offset of the item stored at the given position.
+func (t *freezerTable) offset(item uint64) (uint64, error) {
+	var buf [freezerIndexItemSize]byte
+	if _, err := t.index.ReadAt(buf[:], int64(item*freezerIndexItemSize)); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(buf[:]), nil
+}
+
+// retrieve returns the item stored at the given position.
+func (t *freezerTable) retrieve(item uint64) ([]byte, error) {
+	if item >= t.items {
+		return nil, errOutOfBounds
+	}
+	var start uint64
+	if item > 0 {
+		var err error
+		if start, err = t.offset(item - 1); err != nil {
+			return nil, err
+		}
+	}
+	end, err := t.offset(item)
+	if err != nil {
+		return nil, err
+	}
+	if end < start {
+		return nil, fmt.Errorf("corrupted index of %s table at item %d", t.name, item)
+	}
+	blob := make([]byte, end-start)
+	if _, err := t.data.ReadAt(blob, int64(start)); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return blob, nil
+}
+
+// append stores the blob as the next item of the table. The data is written
+// before the index so that a crash in between leaves only unreferenced data.
+func (t *freezerTable) append(item uint64, blob []byte) error {
+	if item != t.items {
+		return fmt.Errorf("%w: %s table has %d items, got %d", errOutOrderInsert, t.name, t.items, item)
+	}
+	if _, err := t.data.WriteAt(blob, int64(t.size)); err != nil {
+		return err
+	}
+	var buf [freezerIndexItemSize]byte
+	binary.BigEndian.PutUint64(buf[:], t.size+uint64(len(blob)))
+	if _, err := t.index.WriteAt(buf[:], int64(item*freezerIndexItemSize)); err != nil {
+		return err
+	}
+	t.items++
+	t.size += uint64(len(blob))
+	return nil
+}
+
+// truncate discards all items at and after the given position.
+func (t *freezerTable) truncate(items uint64) error {
+	if items >= t.items {
+		return nil
+	}
+	var size uint64
+	if items > 0 {
+		var err error
+		if size, err = t.offset(items - 1); err != nil {
+			return err
+		}
+	}
+	if err := t.index.Truncate(int64(items * freezerIndexItemSize)); err != nil {
+		return err
+	}
+	if err := t.data.Truncate(int64(size)); err != nil {
+		return err
+	}
+	t.items, t.size = items, size
+	return nil
+}
+
+func (t *freezerTable) sync() error {
+	if err := t.data.Sync(); err != nil {
+		return err
+	}
+	return t.index.Sync()
+}
+
+func (t *freezerTable) close() error {
+	var errs []error
+	if err := t.data.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := t.index.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// freezer is the collection of freezer tables sharing the same item numbering.
+type freezer struct {
+	lock     sync.RWMutex
+	dir      string
+	readonly bool
+	tables   map[string]*freezerTable
+	frozen   atomic.Uint64 // Number of blocks already frozen
+}
+
+// newFreezer opens the freezer in dir, creating it if needed. Tables are
+// truncated to the shortest one so that every block is either stored in all
+// tables or in none of them.
+func newFreezer(dir string, readonly bool) (*freezer, error) {
+	if !readonly {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	f := &freezer{dir: dir, readonly: readonly, tables: make(map[string]*freezerTable)}
+	for _, name := range freezerTables {
+		table, err := newFreezerTable(dir, name, readonly)
+		if err != nil {
+			f.close()
+			return nil, err
+		}
+		f.tables[name] = table
+	}
+
+	frozen := f.tables[freezerHashTable].items
+	for _, table := range f.tables {
+		if table.items < frozen {
+			frozen = table.items
+		}
+	}
+	if !readonly {
+		for _, table := range f.tables {
+			if err := table.truncate(frozen); err != nil {
+				f.close()
+				return nil, err
+			}
+		}
+	}
+	f.frozen.Store(frozen)
+	return f, nil
+}
+
+// ancients returns the number of blocks stored in the freezer.
+func (f *freezer) ancients() uint64 {
+	return f.frozen.Load()
+}
+
+// ancient retrieves the item of the given kind belonging to the given block.
+func (f *freezer) ancient(kind string, number uint64) ([]byte, error) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	table := f.tables[kind]
+	if table == nil {
+		return nil, errUnknownTable
+	}
+	if number >= f.frozen.Load() {
+		return nil, errOutOfBounds
+	}
+	return table.retrieve(number)
+}
+
+// ancientHash returns the hash of the frozen block of the given number, or an
+// empty hash if the block is not frozen.
+func (f *freezer) ancientHash(number uint64) common.Hash {
+	data, err := f.ancient(freezerHashTable, number)
+	if err != nil || len(data) != common.HashLength {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
+// append stores a block as the next item of every table.
+func (f *freezer) append(number uint64, hash common.Hash, header, body, receipts []byte) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.readonly {
+		return errFreezerReadOnly
+	}
+	if frozen := f.frozen.Load(); number != frozen {
+		return fmt.Errorf("%w: frozen %d, got %d", errOutOrderInsert, frozen, number)
+	}
+	blobs := map[string][]byte{
+		freezerHashTable:    hash.Bytes(),
+		freezerHeaderTable:  header,
+		freezerBodiesTable:  body,
+		freezerReceiptTable: receipts,
+	}
+	for _, name := range freezerTables {
+		if err := f.tables[name].append(number, blobs[name]); err != nil {
+			// Roll back the tables already written to keep them aligned.
+			for _, table := range f.tables {
+				table.truncate(number)
+			}
+			return err
+		}
+	}
+	f.frozen.Store(number + 1)
+	return nil
+}
+
+// truncate discards all blocks at and after the given number.
+func (f *freezer) truncate(items uint64) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.readonly {
+		return errFreezerReadOnly
+	}
+	if items >= f.frozen.Load() {
+		return nil
+	}
+	for _, table := range f.tables {
+		if err := table.truncate(items); err != nil {
+			return err
+		}
+	}
+	f.frozen.Store(items)
+	return nil
+}
+
+// sync flushes all tables to disk.
+func (f *freezer) sync() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.readonly {
+		return nil
+	}
+	for _, table := range f.tables {
+		if err := table.sync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *freezer) close() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	var errs []error
+	for _, table := range f.tables {
+		if err := table.close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	f.tables = nil
+	return errors.Join(errs...)
+}
@@ -0,0 +1,183 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func appendTestAncients(t *testing.T, f *freezer, from, to uint64) {
+	for i := from; i < to; i++ {
+		b := []byte{byte(i)}
+		require.NoError(t, f.append(i, common.BytesToHash(b), b, append(b, b...), nil))
+	}
+}
+
+func TestFreezer_AppendAndReopen(t *testing.T) {
+	dir := t.TempDir()
+
+	f, err := newFreezer(dir, false)
+	require.NoError(t, err)
+	appendTestAncients(t, f, 0, 10)
+
+	// Appending out of order must fail.
+	assert.ErrorIs(t, f.append(11, common.Hash{}, nil, nil, nil), errOutOrderInsert)
+	require.NoError(t, f.close())
+
+	f, err = newFreezer(dir, false)
+	require.NoError(t, err)
+	defer f.close()
+
+	assert.Equal(t, uint64(10), f.ancients())
+	for i := uint64(0); i < 10; i++ {
+		b := []byte{byte(i)}
+		assert.Equal(t, common.BytesToHash(b), f.ancientHash(i))
+
+		data, err := f.ancient(freezerBodiesTable, i)
+		require.NoError(t, err)
+		assert.Equal(t, append(b, b...), data)
+
+		data, err = f.ancient(freezerReceiptTable, i)
+		require.NoError(t, err)
+		assert.Empty(t, data)
+	}
+	_, err = f.ancient(freezerHeaderTable, 10)
+	assert.ErrorIs(t, err, errOutOfBounds)
+	_, err = f.ancient("unknown", 0)
+	assert.ErrorIs(t, err, errUnknownTable)
+}
+
+func TestFreezer_Repair(t *testing.T) {
+	dir := t.TempDir()
+
+	f, err := newFreezer(dir, false)
+	require.NoError(t, err)
+	appendTestAncients(t, f, 0, 5)
+	require.NoError(t, f.close())
+
+	// Simulate an unclean shutdown: unreferenced data in one table,
+	// a partially written index entry in another and an extra item in a third.
+	data, err := os.OpenFile(filepath.Join(dir, freezerHeaderTable+".dat"), os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = data.Write([]byte{0xde, 0xad})
+	require.NoError(t, err)
+	require.NoError(t, data.Close())
+
+	index, err := os.OpenFile(filepath.Join(dir, freezerBodiesTable+".idx"), os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = index.Write([]byte{0, 0, 0})
+	require.NoError(t, err)
+	require.NoError(t, index.Close())
+
+	table, err := newFreezerTable(dir, freezerHashTable, false)
+	require.NoError(t, err)
+	require.NoError(t, table.append(5, common.Hash{}.Bytes()))
+	require.NoError(t, table.close())
+
+	f, err = newFreezer(dir, false)
+	require.NoError(t, err)
+	defer f.close()
+
+	assert.Equal(t, uint64(5), f.ancients())
+	for _, table := range f.tables {
+		assert.Equal(t, uint64(5), table.items, table.name)
+	}
+	appendTestAncients(t, f, 5, 6)
+	header, err := f.ancient(freezerHeaderTable, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{5}, header)
+}
+
+func TestFreezer_Truncate(t *testing.T) {
+	f, err := newFreezer(t.TempDir(), false)
+	require.NoError(t, err)
+	defer f.close()
+
+	appendTestAncients(t, f, 0, 10)
+	require.NoError(t, f.truncate(4))
+	assert.Equal(t, uint64(4), f.ancients())
+	assert.Equal(t, common.Hash{}, f.ancientHash(4))
+
+	appendTestAncients(t, f, 4, 6)
+	data, err := f.ancient(freezerHeaderTable, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{5}, data)
+}
+
+func TestDBManager_Ancient(t *testing.T) {
+	dir := t.TempDir()
+	dbc := &DBConfig{Dir: dir, DBType: LevelDB, LevelDBCacheSize: 32, OpenFilesLimit: 32, AncientThreshold: 1000}
+
+	var (
+		dbm      = NewDBManager(dbc).(*databaseManager)
+		headers  []*types.Header
+		receipts = types.Receipts{{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{}}}
+	)
+	for i := 0; i < 10; i++ {
+		header := &types.Header{Number: big.NewInt(int64(i)), BlockScore: big.NewInt(1)}
+		if i > 0 {
+			header.ParentHash = headers[i-1].Hash()
+		}
+		headers = append(headers, header)
+
+		dbm.WriteHeader(header)
+		dbm.WriteCanonicalHash(header.Hash(), uint64(i))
+		dbm.WriteBody(header.Hash(), uint64(i), &types.Body{})
+		dbm.WriteReceipts(header.Hash(), uint64(i), receipts)
+	}
+
+	frozen, err := dbm.freezeRange(8)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(8), frozen)
+
+	// Frozen blocks are removed from the key-value databases.
+	has, _ := dbm.getDatabase(headerDB).Has(headerKey(0, headers[0].Hash()))
+	assert.False(t, has)
+	has, _ = dbm.getDatabase(headerDB).Has(headerKey(8, headers[8].Hash()))
+	assert.True(t, has)
+	dbm.Close()
+
+	// Reopen to read the frozen blocks without the caches.
+	dbm = NewDBManager(dbc).(*databaseManager)
+	defer dbm.Close()
+
+	assert.Equal(t, uint64(8), dbm.freezer.ancients())
+	for i, header := range headers {
+		hash, number := header.Hash(), uint64(i)
+		assert.True(t, dbm.HasHeader(hash, number))
+		assert.True(t, dbm.HasBody(hash, number))
+		assert.Equal(t, hash, dbm.ReadHeader(hash, number).Hash())
+		assert.NotNil(t, dbm.ReadBody(hash, number))
+		assert.Len(t, dbm.ReadReceipts(hash, number), 1)
+	}
+	assert.Nil(t, dbm.ReadHeader(common.Hash{1}, 3))
+
+	// Rewinding below the frozen blocks truncates the ancient store.
+	dbm.DeleteHeader(common.Hash{1}, 5)
+	assert.Equal(t, uint64(8), dbm.freezer.ancients())
+	dbm.DeleteHeader(headers[5].Hash(), 5)
+	assert.Equal(t, uint64(5), dbm.freezer.ancients())
+	assert.False(t, dbm.HasBody(headers[6].Hash(), 6))
+}
@@ -89,6 +89,27 @@ type Compacter interface {
 	Compact(start []byte, limit []byte) error
 }
 
+// MultiGetter wraps the MultiGet method of a backing data store.
+type MultiGetter interface {
+	// MultiGet retrieves the values of the given keys in a single grouped read.
+	// The value of a missing key is nil.
+	MultiGet(keys [][]byte) ([][]byte, error)
+}
+
+// MultiGet retrieves the values of the given keys from the database, in a single
+// grouped read if the database supports it or one by one otherwise. The value of
+// a missing key is nil.
+func MultiGet(db Database, keys [][]byte) ([][]byte, error) {
+	if mg, ok := db.(MultiGetter); ok {
+		return mg.MultiGet(keys)
+	}
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		values[i], _ = db.Get(key)
+	}
+	return values, nil
+}
+
 // Database wraps all database operations. All methods are safe for concurrent use.
 type Database interface {
 	KeyValueWriter
@@ -162,6 +162,7 @@ func NewLevelDB(dbc *DBConfig, entryType DBEntryType) (*levelDB, error) {
 
 	ldbOpts := getLevelDBOptions(dbc)
 	ldbOpts.Compression = getCompressionType(dbc.LevelDBCompression, entryType)
+	ldbOpts.ReadOnly = dbc.ReadOnly
 
 	localLogger.Info("LevelDB configurations",
 		"levelDBCacheSize", (ldbOpts.WriteBuffer+ldbOpts.BlockCacheCapacity)/opt.MiB, "openFilesLimit", ldbOpts.OpenFilesCacheCapacity,
@@ -170,7 +171,7 @@ func NewLevelDB(dbc *DBConfig, entryType DBEntryType) (*levelDB, error) {
 
 	// Open the db and recover any potential corruptions
 	db, err := leveldb.OpenFile(dbc.Dir, ldbOpts)
-	if _, corrupted := err.(*errors.ErrCorrupted); corrupted {
+	if _, corrupted := err.(*errors.ErrCorrupted); corrupted && !dbc.ReadOnly {
 		db, err = leveldb.RecoverFile(dbc.Dir, nil)
 	}
 	// (Re)check for errors and abort if opening of the db failed
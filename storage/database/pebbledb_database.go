@@ -154,7 +154,7 @@ func (l panicLogger) Fatalf(format string, args ...interface{}) {
 func NewPebbleDB(dbc *DBConfig, file string) (*pebbleDB, error) {
 	// Ensure we have some minimal caching and file guarantees
 	ephemeral := false
-	readonly := false
+	readonly := dbc.ReadOnly
 	if dbc.PebbleDBCacheSize < minCache {
 		dbc.PebbleDBCacheSize = minCache
 	}
@@ -191,7 +191,6 @@ func NewPebbleDB(dbc *DBConfig, file string) (*pebbleDB, error) {
 	db := &pebbleDB{
 		fn:           file,
 		log:          logger,
-		quitChan:     make(chan chan error),
 		writeOptions: &pebble.WriteOptions{Sync: ephemeral},
 		perfCheck:    dbc.EnableDBPerfMetrics,
 	}
@@ -275,7 +274,11 @@ func (d *pebbleDB) Meter(prefix string) {
 	d.putTimer = kaiametrics.NewRegisteredHybridTimer(prefix+"put/time", nil)
 	d.batchWriteTimer = kaiametrics.NewRegisteredHybridTimer(prefix+"batchwrite/time", nil)
 
-	// Start up the metrics gathering and return
+	// Create a quit channel for the periodic collector and run it
+	d.quitLock.Lock()
+	d.quitChan = make(chan chan error)
+	d.quitLock.Unlock()
+
 	go d.meter(metricsGatheringInterval, prefix)
 }
 
@@ -674,6 +677,9 @@ func (iter *pebbleIterator) Error() error {
 // should not modify the contents of the returned slice, and its contents may
 // change on the next call to Next.
 func (iter *pebbleIterator) Key() []byte {
+	if iter.moved {
+		return nil
+	}
 	return iter.iter.Key()
 }
 
@@ -681,6 +687,9 @@ func (iter *pebbleIterator) Key() []byte {
 // caller should not modify the contents of the returned slice, and its contents
 // may change on the next call to Next.
 func (iter *pebbleIterator) Value() []byte {
+	if iter.moved {
+		return nil
+	}
 	return iter.iter.Value()
 }
 
@@ -0,0 +1,134 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kaiachain/kaia/common"
+)
+
+// readOnlyDB wraps a database opened read-only. Its writes are kept in memory on top of
+// the database instead of failing, so the indices and caches derived from the chain
+// (e.g. the kaiax module data in the misc DB) work as usual until the node stops.
+// Nothing is written to the wrapped database.
+type readOnlyDB struct {
+	Database
+
+	writes map[string]keyvalue
+	lock   sync.RWMutex
+}
+
+func newReadOnlyDB(db Database) *readOnlyDB {
+	return &readOnlyDB{
+		Database: db,
+		writes:   make(map[string]keyvalue),
+	}
+}
+
+func (db *readOnlyDB) Has(key []byte) (bool, error) {
+	db.lock.RLock()
+	kv, ok := db.writes[string(key)]
+	db.lock.RUnlock()
+
+	if ok {
+		return !kv.delete, nil
+	}
+	return db.Database.Has(key)
+}
+
+func (db *readOnlyDB) Get(key []byte) ([]byte, error) {
+	db.lock.RLock()
+	kv, ok := db.writes[string(key)]
+	db.lock.RUnlock()
+
+	if !ok {
+		return db.Database.Get(key)
+	}
+	if kv.delete {
+		return nil, dataNotFoundErr
+	}
+	return common.CopyBytes(kv.value), nil
+}
+
+func (db *readOnlyDB) Put(key []byte, value []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.writes[string(key)] = keyvalue{common.CopyBytes(key), common.CopyBytes(value), false}
+	return nil
+}
+
+func (db *readOnlyDB) Delete(key []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.writes[string(key)] = keyvalue{common.CopyBytes(key), nil, true}
+	return nil
+}
+
+func (db *readOnlyDB) NewBatch() Batch {
+	return &readOnlyBatch{db: db}
+}
+
+// NewIterator merges the writes in memory into the iterator of the wrapped database.
+// The whole range is loaded into memory, so it is meant for the small ranges of the misc DB.
+func (db *readOnlyDB) NewIterator(prefix []byte, start []byte) Iterator {
+	entries := make(map[string][]byte)
+	it := db.Database.NewIterator(prefix, start)
+	for it.Next() {
+		entries[string(it.Key())] = common.CopyBytes(it.Value())
+	}
+	it.Release()
+
+	db.lock.RLock()
+	st := string(append(common.CopyBytes(prefix), start...))
+	for key, kv := range db.writes {
+		if !strings.HasPrefix(key, string(prefix)) || key < st {
+			continue
+		}
+		if kv.delete {
+			delete(entries, key)
+		} else {
+			entries[key] = kv.value
+		}
+	}
+	db.lock.RUnlock()
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	values := make([][]byte, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, entries[key])
+	}
+	return &iterator{keys: keys, values: values}
+}
+
+// readOnlyBatch commits its changes to the memory of readOnlyDB.
+type readOnlyBatch struct {
+	memBatch
+	db *readOnlyDB
+}
+
+func (b *readOnlyBatch) Write() error {
+	return b.Replay(b.db)
+}
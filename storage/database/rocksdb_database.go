@@ -27,6 +27,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/kaiachain/kaia/common"
 	"github.com/kaiachain/kaia/log"
 	kaiametrics "github.com/kaiachain/kaia/metrics"
 	metricutils "github.com/kaiachain/kaia/metrics/utils"
@@ -211,6 +212,27 @@ func (db *rocksDB) get(key []byte) ([]byte, error) {
 	return dat, nil
 }
 
+// MultiGet retrieves the values of the given keys with a single RocksDB MultiGet.
+func (db *rocksDB) MultiGet(keys [][]byte) ([][]byte, error) {
+	if !db.config.DisableMetrics {
+		start := time.Now()
+		defer db.getTimer.Update(time.Since(start))
+	}
+	slices, err := db.db.MultiGet(db.ro, keys...)
+	if err != nil {
+		return nil, err
+	}
+	defer slices.Destroy()
+
+	values := make([][]byte, len(keys))
+	for i, slice := range slices {
+		if slice.Exists() {
+			values[i] = common.CopyBytes(slice.Data())
+		}
+	}
+	return values, nil
+}
+
 func (db *rocksDB) Delete(key []byte) error {
 	if db.config.Secondary {
 		return nil
@@ -37,6 +37,9 @@ var (
 	// databaseVerisionKey tracks the current database version.
 	databaseVerisionKey = []byte("DatabaseVersion")
 
+	// databaseAppVersionKey tracks the version of Kaia which last opened the database.
+	databaseAppVersionKey = []byte("DatabaseAppVersion")
+
 	// headHeaderKey tracks the latest know header's hash.
 	headHeaderKey = []byte("LastHeader")
 
@@ -76,6 +79,13 @@ var (
 	// snapshotRootKey tracks the hash of the last snapshot.
 	snapshotRootKey = []byte("SnapshotRoot")
 
+	// snapshotStorageWipePrefix + account hash tracks the destructed accounts whose storage snapshot is pending for the deletion.
+	snapshotStorageWipePrefix = []byte("SnapshotStorageWipe")
+
+	// snapshotWipeMarkerKey tracks if any storage snapshot may be pending for the deletion, so that
+	// the pending deletions are looked up only if needed.
+	snapshotWipeMarkerKey = []byte("SnapshotWipeMarker")
+
 	// badBlockKey tracks the list of bad blocks seen by local
 	badBlockKey = []byte("InvalidBlock")
 
@@ -96,11 +106,15 @@ var (
 	preimagePrefix = []byte("secure-key-")  // preimagePrefix + hash -> preimage
 	configPrefix   = []byte("klay-config-") // config prefix for the db
 
-	pruningEnabledKey        = []byte("PruningEnabled")
-	pruningMarkPrefix        = []byte("Pruning-")                                // KIP-111 pruning markings
-	pruningMarkValue         = []byte{0x01}                                      // A nonempty value to store a pruning mark
-	pruningMarkKeyLen        = len(pruningMarkPrefix) + 8 + common.ExtHashLength // prefix + num (uint64) + node hash
-	lastPrunedBlockNumberKey = []byte("lastPrunedBlockNumber")
+	pruningEnabledKey               = []byte("PruningEnabled")
+	pruningMarkPrefix               = []byte("Pruning-")                                // KIP-111 pruning markings
+	pruningMarkValue                = []byte{0x01}                                      // A nonempty value to store a pruning mark
+	pruningMarkKeyLen               = len(pruningMarkPrefix) + 8 + common.ExtHashLength // prefix + num (uint64) + node hash
+	lastPrunedBlockNumberKey        = []byte("lastPrunedBlockNumber")
+	lastPrunedReceiptBlockNumberKey = []byte("lastPrunedReceiptBlockNumber")
+	statePruningCheckpointKey       = []byte("StatePruningCheckpoint")
+
+	compressedTablesKey = []byte("CompressedTables")
 
 	// Chain index prefixes (use `i` + single byte to avoid mixing data types).
 	BloomBitsIndexPrefix = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
@@ -132,6 +146,12 @@ var (
 
 	stakingInfoPrefix = []byte("stakingInfo")
 
+	stateDiffPrefix = []byte("stateDiff") // stateDiffPrefix + num (uint64 big endian) + hash -> state diff of the block
+
+	trieMirrorRootPrefix = []byte("trieMirrorRoot") // trieMirrorRootPrefix + trie root (ExtHash) -> mirror trie root (ExtHash)
+
+	quarantinedTrieNodePrefix = []byte("quarantinedTrieNode") // quarantinedTrieNodePrefix + node hash (ExtHash) -> corrupted node value
+
 	supplyCheckpointPrefix        = []byte("supplyCheckpoint")
 	lastSupplyCheckpointNumberKey = []byte("lastSupplyCheckpointNumber")
 
@@ -191,6 +211,11 @@ func StorageSnapshotKey(accountHash, storageHash common.Hash) []byte {
 	return append(append(SnapshotStoragePrefix, accountHash.Bytes()...), storageHash.Bytes()...)
 }
 
+// snapshotStorageWipeKey = snapshotStorageWipePrefix + account hash
+func snapshotStorageWipeKey(accountHash common.Hash) []byte {
+	return append(snapshotStorageWipePrefix, accountHash.Bytes()...)
+}
+
 // StorageSnapshotsKey = SnapshotStoragePrefix + account hash + storage hash
 func StorageSnapshotsKey(accountHash common.Hash) []byte {
 	return append(SnapshotStoragePrefix, accountHash.Bytes()...)
@@ -315,3 +340,18 @@ func (c *SupplyCheckpoint) Copy() *SupplyCheckpoint {
 func supplyCheckpointKey(blockNumber uint64) []byte {
 	return append(supplyCheckpointPrefix, common.Int64ToByteBigEndian(blockNumber)...)
 }
+
+// stateDiffKey = stateDiffPrefix + num (uint64 big endian) + hash
+func stateDiffKey(number uint64, hash common.Hash) []byte {
+	return append(append(stateDiffPrefix, common.Int64ToByteBigEndian(number)...), hash.Bytes()...)
+}
+
+// trieMirrorRootKey = trieMirrorRootPrefix + root (ExtHash)
+func trieMirrorRootKey(root common.ExtHash) []byte {
+	return append(trieMirrorRootPrefix, root.Bytes()...)
+}
+
+// quarantinedTrieNodeKey = quarantinedTrieNodePrefix + node hash (ExtHash)
+func quarantinedTrieNodeKey(hash common.ExtHash) []byte {
+	return append(quarantinedTrieNodePrefix, hash.Bytes()...)
+}
@@ -23,7 +23,9 @@ import (
 	"container/heap"
 	"context"
 	"fmt"
+	"os"
 	"path"
+	"path/filepath"
 	"strconv"
 	"sync"
 
@@ -54,6 +56,58 @@ type sdbBatchResult struct {
 	err   error // Error from the batch write operation.
 }
 
+// prepareShardDir returns the directory of the i-th shard of a sharded database.
+// A shard always lives at <dbc.Dir>/<i>. If StateTrieShardDirs is given and the
+// shard does not exist yet, the shard is created at <StateTrieShardDirs[i%n]>/<base of dbc.Dir>/<i>
+// and linked from <dbc.Dir>/<i>, so that the shards are spread over several disks.
+// An existing shard is opened where it is, so the layout can't change after creation.
+func prepareShardDir(dbc *DBConfig, i int) (string, error) {
+	shardDir := path.Join(dbc.Dir, strconv.Itoa(i))
+	if len(dbc.StateTrieShardDirs) == 0 || dbc.DBType == MemoryDB || dbc.DBType.selfShardable() {
+		return shardDir, nil
+	}
+	if _, err := os.Lstat(shardDir); err == nil {
+		return shardDir, nil
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	target := filepath.Join(dbc.StateTrieShardDirs[i%len(dbc.StateTrieShardDirs)], filepath.Base(dbc.Dir), strconv.Itoa(i))
+	if err := os.MkdirAll(target, 0o755); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dbc.Dir, 0o755); err != nil {
+		return "", err
+	}
+	if err := os.Symlink(target, shardDir); err != nil {
+		return "", err
+	}
+	logger.Info("Placed a database shard on a separate directory", "shard", shardDir, "target", target)
+	return shardDir, nil
+}
+
+// shardLinkTargets returns the directories that the shards of the sharded database
+// in dir are linked to. Shards stored directly inside dir are not returned.
+func shardLinkTargets(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var targets []string
+	for _, entry := range entries {
+		if entry.Type()&os.ModeSymlink == 0 {
+			continue
+		}
+		if _, err := strconv.Atoi(entry.Name()); err != nil {
+			continue
+		}
+		if target, err := filepath.EvalSymlinks(filepath.Join(dir, entry.Name())); err == nil {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
 // newShardedDB creates database with numShards shards, or partitions.
 // The type of database is specified DBConfig.DBType.
 func newShardedDB(dbc *DBConfig, et DBEntryType, numShards uint) (*shardedDB, error) {
@@ -82,7 +136,11 @@ func newShardedDB(dbc *DBConfig, et DBEntryType, numShards uint) (*shardedDB, er
 	}
 	for i := 0; i < int(numShards); i++ {
 		copiedDBC := *dbc
-		copiedDBC.Dir = path.Join(copiedDBC.Dir, strconv.Itoa(i))
+		shardDir, err := prepareShardDir(dbc, i)
+		if err != nil {
+			return nil, err
+		}
+		copiedDBC.Dir = shardDir
 		copiedDBC.LevelDBCacheSize = sdbLevelDBCacheSize
 		copiedDBC.PebbleDBCacheSize = sdbPebbleDBCacheSize
 		copiedDBC.OpenFilesLimit = sdbOpenFilesLimit
@@ -99,7 +157,7 @@ func newShardedDB(dbc *DBConfig, et DBEntryType, numShards uint) (*shardedDB, er
 		go batchWriteWorker(sdbBatchTaskCh)
 	}
 
-	logger.Info("Created a sharded database", "dbType", et, "numShards", numShards)
+	logger.Info("Created a sharded database", "dbType", et, "numShards", numShards, "shardDirs", len(dbc.StateTrieShardDirs))
 	return &shardedDB{
 		fn: dbc.Dir, shards: shards,
 		numShards: numShards, sdbBatchTaskCh: sdbBatchTaskCh,
@@ -153,6 +211,36 @@ func (db *shardedDB) Get(key []byte) ([]byte, error) {
 	}
 }
 
+// MultiGet groups the given keys by shard and reads each group at once.
+func (db *shardedDB) MultiGet(keys [][]byte) ([][]byte, error) {
+	var (
+		shardKeys    = make([][][]byte, db.numShards)
+		shardIndices = make([][]int, db.numShards)
+	)
+	for i, key := range keys {
+		shardIndex, err := shardIndexByKey(key, db.numShards)
+		if err != nil {
+			return nil, err
+		}
+		shardKeys[shardIndex] = append(shardKeys[shardIndex], key)
+		shardIndices[shardIndex] = append(shardIndices[shardIndex], i)
+	}
+	values := make([][]byte, len(keys))
+	for shardIndex, group := range shardKeys {
+		if len(group) == 0 {
+			continue
+		}
+		shardValues, err := MultiGet(db.shards[shardIndex], group)
+		if err != nil {
+			return nil, err
+		}
+		for j, value := range shardValues {
+			values[shardIndices[shardIndex][j]] = value
+		}
+	}
+	return values, nil
+}
+
 func (db *shardedDB) Has(key []byte) (bool, error) {
 	if shard, err := db.getShardByKey(key); err != nil {
 		return false, err
@@ -21,8 +21,10 @@ import (
 	"bytes"
 	"context"
 	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
+	"strconv"
 	"sync"
 	"testing"
 
@@ -300,3 +302,62 @@ func TestShardedDBParallelIterator_Release(t *testing.T) {
 			}
 		})
 }
+
+func TestShardedDB_ShardDirs(t *testing.T) {
+	baseDir := t.TempDir()
+	diskDirs := []string{t.TempDir(), t.TempDir()}
+	dbc := &DBConfig{Dir: filepath.Join(baseDir, "statetrie"), DBType: LevelDB, NumStateTrieShards: 4, StateTrieShardDirs: diskDirs}
+
+	db, err := newShardedDB(dbc, StateTrieDB, dbc.NumStateTrieShards)
+	assert.NoError(t, err)
+	entries := common.CreateEntries(100)
+	for _, entry := range entries {
+		assert.NoError(t, db.Put(entry.Key, entry.Val))
+	}
+	db.Close()
+
+	// Each shard is linked from the database dir to one of the disk dirs in turn.
+	for i := 0; i < int(dbc.NumStateTrieShards); i++ {
+		target, err := os.Readlink(filepath.Join(dbc.Dir, strconv.Itoa(i)))
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join(diskDirs[i%len(diskDirs)], "statetrie", strconv.Itoa(i)), target)
+	}
+	assert.Len(t, shardLinkTargets(dbc.Dir), int(dbc.NumStateTrieShards))
+
+	// Reopening without the disk dirs follows the existing links.
+	dbc.StateTrieShardDirs = nil
+	db, err = newShardedDB(dbc, StateTrieDB, dbc.NumStateTrieShards)
+	assert.NoError(t, err)
+	for _, entry := range entries {
+		val, err := db.Get(entry.Key)
+		assert.NoError(t, err)
+		assert.Equal(t, entry.Val, val)
+	}
+	db.Close()
+}
+
+func TestShardedDB_MultiGet(t *testing.T) {
+	dbc := &DBConfig{Dir: t.TempDir(), DBType: MemoryDB, NumStateTrieShards: 4}
+	db, err := newShardedDB(dbc, StateTrieDB, dbc.NumStateTrieShards)
+	assert.NoError(t, err)
+	defer db.Close()
+
+	entries := common.CreateEntries(100)
+	keys := make([][]byte, 0, len(entries)+1)
+	for _, entry := range entries {
+		assert.NoError(t, db.Put(entry.Key, entry.Val))
+		keys = append(keys, entry.Key)
+	}
+	keys = append(keys, []byte("missing"))
+
+	values, err := MultiGet(db, keys)
+	assert.NoError(t, err)
+	assert.Len(t, values, len(keys))
+	for i, entry := range entries {
+		assert.Equal(t, entry.Val, values[i])
+	}
+	assert.Nil(t, values[len(entries)])
+
+	_, err = db.MultiGet([][]byte{{}})
+	assert.ErrorIs(t, err, errKeyLengthZero)
+}
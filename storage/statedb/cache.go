@@ -61,6 +61,12 @@ type TrieNodeCache interface {
 	Close() error
 }
 
+// resizableTrieNodeCache is a TrieNodeCache whose memory allowance can be changed at runtime.
+type resizableTrieNodeCache interface {
+	Resize(sizeMiB int) error
+	MaxBytes() uint64
+}
+
 type BlockPubSub interface {
 	PublishBlock(msg string) error
 	SubscribeBlockCh() <-chan *redis.Message
@@ -77,6 +83,9 @@ const (
 var (
 	errNotSupportedCacheType  = errors.New("not supported stateDB TrieNodeCache type")
 	errNilTrieNodeCacheConfig = errors.New("TrieNodeCacheConfig is nil")
+
+	errInvalidTrieNodeCacheSize  = errors.New("invalid trie node cache size")
+	errTrieNodeCacheNotResizable = errors.New("trie node cache is disabled or not resizable")
 )
 
 func (cacheType TrieNodeCacheType) ToValid() TrieNodeCacheType {
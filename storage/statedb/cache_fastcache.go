@@ -19,6 +19,8 @@
 package statedb
 
 import (
+	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/VictoriaMetrics/fastcache"
@@ -39,10 +41,16 @@ var (
 	memcacheFastInvalidMetavalueErrors = metrics.NewRegisteredGauge("trie/memcache/fast/error/invalid/matal", nil)
 	memcacheFastInvalidValueLenErrors  = metrics.NewRegisteredGauge("trie/memcache/fast/error/invalid/valuelen", nil)
 	memcacheFastInvalidValueHashErrors = metrics.NewRegisteredGauge("trie/memcache/fast/error/invalid/hash", nil)
+	memcacheFastHits                   = metrics.NewRegisteredGauge("trie/memcache/fast/hits", nil)
+	memcacheFastEvictions              = metrics.NewRegisteredGauge("trie/memcache/fast/evictions", nil)
+	memcacheFastCapacity               = metrics.NewRegisteredGauge("trie/memcache/fast/capacity", nil)
 )
 
 type FastCache struct {
-	fast *fastcache.Cache
+	fast atomic.Pointer[fastcache.Cache]
+
+	// Number of sets into the caches replaced by Resize, which are all evicted.
+	replacedSets atomic.Uint64
 }
 
 // newFastCache creates a FastCache with given cache size.
@@ -61,7 +69,8 @@ func newFastCache(config *TrieNodeCacheConfig) TrieNodeCache {
 		"MaxMiB", config.LocalCacheSizeMiB, "FilePath", config.FastCacheFileDir)
 
 	start := time.Now()
-	fc := &FastCache{fast: fastcache.LoadFromFileOrNew(config.FastCacheFileDir, config.LocalCacheSizeMiB*int(units.MiB))}
+	fc := &FastCache{}
+	fc.fast.Store(fastcache.LoadFromFileOrNew(config.FastCacheFileDir, config.LocalCacheSizeMiB*int(units.MiB)))
 	stats := fc.UpdateStats().(fastcache.Stats)
 
 	logger.Info("Initialized local trie node cache (fastCache)",
@@ -71,20 +80,44 @@ func newFastCache(config *TrieNodeCacheConfig) TrieNodeCache {
 }
 
 func (cache *FastCache) Get(k []byte) []byte {
-	return cache.fast.Get(nil, k)
+	return cache.fast.Load().Get(nil, k)
 }
 
 func (cache *FastCache) Set(k, v []byte) {
-	cache.fast.Set(k, v)
+	cache.fast.Load().Set(k, v)
 }
 
 func (cache *FastCache) Has(k []byte) ([]byte, bool) {
-	return cache.fast.HasGet(nil, k)
+	return cache.fast.Load().HasGet(nil, k)
+}
+
+// Resize replaces the cache with an empty one of the given size.
+// The cached entries are dropped since fastcache cannot be resized in place.
+func (cache *FastCache) Resize(sizeMiB int) error {
+	if sizeMiB <= 0 {
+		return fmt.Errorf("%w: %d MiB", errInvalidTrieNodeCacheSize, sizeMiB)
+	}
+	old := cache.fast.Swap(fastcache.New(sizeMiB * int(units.MiB)))
+
+	var stats fastcache.Stats
+	old.UpdateStats(&stats)
+	cache.replacedSets.Add(stats.SetCalls)
+	old.Reset()
+
+	logger.Info("Resized local trie node cache (fastCache)", "MaxMiB", sizeMiB, "droppedEntries", stats.EntriesCount)
+	return nil
+}
+
+// MaxBytes returns the memory allowance of the cache.
+func (cache *FastCache) MaxBytes() uint64 {
+	var stats fastcache.Stats
+	cache.fast.Load().UpdateStats(&stats)
+	return stats.MaxBytesSize
 }
 
 func (cache *FastCache) UpdateStats() interface{} {
 	var stats fastcache.Stats
-	cache.fast.UpdateStats(&stats)
+	cache.fast.Load().UpdateStats(&stats)
 
 	memcacheFastMisses.Update(int64(stats.Misses))
 	memcacheFastCollisions.Update(int64(stats.Collisions))
@@ -97,12 +130,20 @@ func (cache *FastCache) UpdateStats() interface{} {
 	memcacheFastInvalidMetavalueErrors.Update(int64(stats.InvalidMetavalueErrors))
 	memcacheFastInvalidValueLenErrors.Update(int64(stats.InvalidValueLenErrors))
 	memcacheFastInvalidValueHashErrors.Update(int64(stats.InvalidValueHashErrors))
+	memcacheFastHits.Update(int64(stats.GetCalls - stats.Misses))
+	memcacheFastCapacity.Update(int64(stats.MaxBytesSize))
+
+	// Trie nodes are content-addressed and set only when they are not cached,
+	// so the sets not matched by a cached entry approximate the evictions.
+	if sets := cache.replacedSets.Load() + stats.SetCalls; sets > stats.EntriesCount {
+		memcacheFastEvictions.Update(int64(sets - stats.EntriesCount))
+	}
 
 	return stats
 }
 
 func (cache *FastCache) SaveToFile(filePath string, concurrency int) error {
-	return cache.fast.SaveToFileConcurrent(filePath, concurrency)
+	return cache.fast.Load().SaveToFileConcurrent(filePath, concurrency)
 }
 
 func (cache *FastCache) Close() error {
@@ -82,6 +82,23 @@ func (cache *HybridCache) UpdateStats() interface{} {
 	return stats{cache.local.UpdateStats(), cache.remote.UpdateStats()}
 }
 
+// Resize changes the memory allowance of the local cache.
+func (cache *HybridCache) Resize(sizeMiB int) error {
+	local, ok := cache.local.(resizableTrieNodeCache)
+	if !ok {
+		return errTrieNodeCacheNotResizable
+	}
+	return local.Resize(sizeMiB)
+}
+
+// MaxBytes returns the memory allowance of the local cache.
+func (cache *HybridCache) MaxBytes() uint64 {
+	if local, ok := cache.local.(resizableTrieNodeCache); ok {
+		return local.MaxBytes()
+	}
+	return 0
+}
+
 func (cache *HybridCache) SaveToFile(filePath string, concurrency int) error {
 	if err := cache.local.SaveToFile(filePath, concurrency); err != nil {
 		logger.Error("failed to save local cache to file",
@@ -24,7 +24,9 @@ import (
 	"runtime"
 	"testing"
 
+	"github.com/alecthomas/units"
 	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/storage/database"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -83,3 +85,39 @@ func TestFastCache_SaveAndLoad(t *testing.T) {
 		assert.Equal(t, fastCacheFromFile.Get(key), vals[idx])
 	}
 }
+
+// TestFastCache_Resize tests resizing the fastcache and the trie node cache of a Database at runtime.
+func TestFastCache_Resize(t *testing.T) {
+	dirName, err := os.MkdirTemp(os.TempDir(), "fastcache_resize")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dirName)
+
+	config := getTestFastCacheConfig()
+	config.LocalCacheSizeMiB = 32
+	config.FastCacheFileDir = dirName
+
+	cache, err := NewTrieNodeCache(config)
+	assert.NoError(t, err)
+	fastCache := cache.(*FastCache)
+	assert.Equal(t, uint64(32*units.MiB), fastCache.MaxBytes())
+
+	key, val := common.MakeRandomBytes(32), common.MakeRandomBytes(128)
+	fastCache.Set(key, val)
+	assert.Equal(t, val, fastCache.Get(key))
+
+	// Resizing drops the cached entries and changes the allowance
+	assert.NoError(t, fastCache.Resize(64))
+	assert.Equal(t, uint64(64*units.MiB), fastCache.MaxBytes())
+	assert.Equal(t, []byte(nil), fastCache.Get(key))
+	assert.ErrorIs(t, fastCache.Resize(0), errInvalidTrieNodeCacheSize)
+
+	// Database exposes the live size of the cache
+	db := NewDatabaseWithExistingCache(database.NewMemoryDBManager(), fastCache)
+	assert.NoError(t, db.ResizeTrieNodeCache(96))
+	assert.Equal(t, uint64(96*units.MiB), db.GetTrieNodeLocalCacheByteLimit())
+
+	// Without a trie node cache, resizing fails
+	assert.ErrorIs(t, NewDatabase(database.NewMemoryDBManager()).ResizeTrieNodeCache(48), errTrieNodeCacheNotResizable)
+}
@@ -113,6 +113,8 @@ type Database struct {
 	trieNodeCache                TrieNodeCache        // GC friendly memory cache of trie node RLPs
 	trieNodeCacheConfig          *TrieNodeCacheConfig // Configuration of trieNodeCache
 	savingTrieNodeCacheTriggered bool                 // Whether saving trie node cache has been triggered or not
+
+	integrity *integrityChecker // Background verifier of the trie nodes written to the disk, nil if disabled
 }
 
 // rawNode is a simple binary blob used to differentiate between collapsed trie
@@ -362,9 +364,22 @@ func (db *Database) GetTrieNodeCacheConfig() *TrieNodeCacheConfig {
 
 // GetTrieNodeLocalCacheByteLimit returns the byte size of trie node cache.
 func (db *Database) GetTrieNodeLocalCacheByteLimit() uint64 {
+	if cache, ok := db.trieNodeCache.(resizableTrieNodeCache); ok {
+		return cache.MaxBytes()
+	}
 	return uint64(db.trieNodeCacheConfig.LocalCacheSizeMiB) * 1024 * 1024
 }
 
+// ResizeTrieNodeCache changes the memory allowance of the local trie node cache.
+// The cached trie nodes are dropped because the cache is recreated with the new size.
+func (db *Database) ResizeTrieNodeCache(sizeMiB int) error {
+	cache, ok := db.trieNodeCache.(resizableTrieNodeCache)
+	if !ok {
+		return errTrieNodeCacheNotResizable
+	}
+	return cache.Resize(sizeMiB)
+}
+
 // RLockGCCachedNode locks the GC lock of CachedNode.
 func (db *Database) RLockGCCachedNode() {
 	db.gcLock.RLock()
@@ -667,14 +682,19 @@ func (db *Database) reference(child common.ExtHash, parent common.ExtHash) {
 	if !ok {
 		return
 	}
+	// If the parent does not exist, it's already flushed to disk, skip
+	parentNode, ok := db.nodes[parent]
+	if !ok {
+		return
+	}
 	// If the reference already exists, only duplicate for roots
-	if db.nodes[parent].children == nil {
-		db.nodes[parent].children = make(map[common.ExtHash]uint64)
-	} else if _, ok = db.nodes[parent].children[child]; ok && !common.EmptyExtHash(parent) {
+	if parentNode.children == nil {
+		parentNode.children = make(map[common.ExtHash]uint64)
+	} else if _, ok = parentNode.children[child]; ok && !common.EmptyExtHash(parent) {
 		return
 	}
 	node.parents++
-	db.nodes[parent].children[child]++
+	parentNode.children[child]++
 }
 
 // Dereference removes an existing reference from a state root node.
@@ -779,6 +799,7 @@ func (db *Database) Cap(limit common.StorageSize) error {
 		node := db.nodes[oldest]
 		enc := node.rlp()
 		db.diskDB.PutTrieNodeToBatch(batch, oldest, enc)
+		db.integrity.sample(oldest)
 		if _, err := database.WriteBatchesOverThreshold(batch); err != nil {
 			db.lock.RUnlock()
 			return err
@@ -874,6 +895,7 @@ func (db *Database) writeBatchNodes(node common.ExtHash) error {
 		}
 
 		db.diskDB.PutTrieNodeToBatch(batch, result.hash, result.val)
+		db.integrity.sample(result.hash)
 		if _, err := database.WriteBatchesOverThreshold(batch); err != nil {
 			return err
 		}
@@ -881,6 +903,7 @@ func (db *Database) writeBatchNodes(node common.ExtHash) error {
 
 	enc := rootNode.rlp()
 	db.diskDB.PutTrieNodeToBatch(batch, node, enc)
+	db.integrity.sample(node)
 	if err := batch.Write(); err != nil {
 		logger.Error("Failed to write trie to disk", "err", err)
 		return err
@@ -0,0 +1,149 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package statedb
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/rlp"
+)
+
+// errUnexpectedJournalEntry is returned if a journal entry follows the meta-root entry.
+var errUnexpectedJournalEntry = errors.New("unexpected entry after the meta-root in the trie journal")
+
+// journalNode is a dirty trie node in the journal of the memory database.
+// The entry with the empty hash carries the references of the meta-root.
+type journalNode struct {
+	Hash     common.ExtHash
+	Size     uint16
+	Blob     []byte
+	Children []journalRef // External references of the node, e.g. to the storage trie roots
+}
+
+// journalRef is an external reference from a dirty trie node.
+type journalRef struct {
+	Hash  common.ExtHash
+	Count uint64
+}
+
+// Journal writes the dirty trie nodes and their external references into w.
+// The nodes are written in the order of the flush-list, so that LoadJournal
+// restores the same reference counts and flush order.
+func (db *Database) Journal(w io.Writer) (int, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	nodes := 0
+	for hash := db.oldest; !common.EmptyExtHash(hash); {
+		node, ok := db.nodes[hash]
+		if !ok {
+			return nodes, fmt.Errorf("trie node %x in the flush-list is missing", hash)
+		}
+		entry := journalNode{Hash: hash, Size: node.size, Blob: node.rlp(), Children: journalRefs(node.children)}
+		if err := rlp.Encode(w, entry); err != nil {
+			return nodes, err
+		}
+		nodes++
+		hash = node.flushNext
+	}
+	var metaRefs []journalRef
+	if metaRoot := db.nodes[common.ExtHash{}]; metaRoot != nil {
+		metaRefs = journalRefs(metaRoot.children)
+	}
+	return nodes, rlp.Encode(w, journalNode{Children: metaRefs})
+}
+
+func journalRefs(children map[common.ExtHash]uint64) []journalRef {
+	refs := make([]journalRef, 0, len(children))
+	for hash, count := range children {
+		refs = append(refs, journalRef{hash, count})
+	}
+	return refs
+}
+
+// LoadJournal inserts the dirty trie nodes written by Journal into the memory
+// database and restores their references. The references to the nodes already
+// flushed to the disk are skipped as they are no longer tracked.
+func (db *Database) LoadJournal(r io.Reader) (int, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	var (
+		stream = rlp.NewStream(r, 0)
+		refs   = make(map[common.ExtHash][]journalRef)
+		nodes  = 0
+	)
+	for {
+		var entry journalNode
+		if err := stream.Decode(&entry); err != nil {
+			return nodes, err
+		}
+		if common.EmptyExtHash(entry.Hash) {
+			refs[entry.Hash] = entry.Children
+			break
+		}
+		n, err := decodeNode(entry.Hash[:], entry.Blob)
+		if err != nil {
+			return nodes, fmt.Errorf("trie node %x: %w", entry.Hash, err)
+		}
+		db.insert(entry.Hash, entry.Size, collapseDecoded(n))
+		if len(entry.Children) > 0 {
+			refs[entry.Hash] = entry.Children
+		}
+		nodes++
+	}
+	if _, err := stream.Raw(); err != io.EOF {
+		return nodes, errUnexpectedJournalEntry
+	}
+	// The references are restored after all the nodes are inserted, since a
+	// storage trie root may follow the account trie node referencing it.
+	for parent, children := range refs {
+		for _, child := range children {
+			for i := uint64(0); i < child.Count; i++ {
+				db.reference(child.Hash, parent)
+			}
+		}
+	}
+	return nodes, nil
+}
+
+// collapseDecoded converts the keys of the decoded short nodes back into the compact
+// encoding, since the memory database caches the nodes collapsed by the hasher.
+func collapseDecoded(n node) node {
+	switch n := n.(type) {
+	case *shortNode:
+		collapsed := n.copy()
+		collapsed.Key = hexToCompact(n.Key)
+		collapsed.Val = collapseDecoded(n.Val)
+		return collapsed
+
+	case *fullNode:
+		collapsed := n.copy()
+		for i, child := range n.Children {
+			if child != nil {
+				collapsed.Children[i] = collapseDecoded(child)
+			}
+		}
+		return collapsed
+
+	default:
+		return n
+	}
+}
@@ -19,11 +19,15 @@
 package statedb
 
 import (
+	"bytes"
+	"fmt"
+	"sort"
 	"testing"
 
 	"github.com/kaiachain/kaia/common"
 	"github.com/kaiachain/kaia/storage/database"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -116,6 +120,61 @@ func TestDatabase_Size(t *testing.T) {
 	assert.Equal(t, common.StorageSize(100), preimagesSize)
 }
 
+func TestDatabase_Journal(t *testing.T) {
+	memDB := database.NewMemoryDBManager()
+	db := NewDatabase(memDB)
+
+	// Two roots sharing most of their nodes, kept only in memory
+	trie, _ := NewTrie(common.Hash{}, db, nil)
+	for i := 0; i < 100; i++ {
+		trie.Update([]byte(fmt.Sprintf("key-%d", i)), []byte(fmt.Sprintf("value-%d", i)))
+	}
+	root1, err := trie.Commit(nil)
+	require.NoError(t, err)
+	db.ReferenceRoot(root1)
+	trie.Update([]byte("key-0"), []byte("changed"))
+	root2, err := trie.Commit(nil)
+	require.NoError(t, err)
+	db.ReferenceRoot(root2)
+
+	var journal bytes.Buffer
+	nodes, err := db.Journal(&journal)
+	require.NoError(t, err)
+	assert.Equal(t, len(db.Nodes()), nodes)
+
+	restored := NewDatabase(memDB)
+	loaded, err := restored.LoadJournal(&journal)
+	require.NoError(t, err)
+	assert.Equal(t, nodes, loaded)
+
+	sortedNodes := func(db *Database) []common.ExtHash {
+		hashes := db.Nodes()
+		sort.Slice(hashes, func(i, j int) bool { return bytes.Compare(hashes[i][:], hashes[j][:]) < 0 })
+		return hashes
+	}
+	assert.Equal(t, sortedNodes(db), sortedNodes(restored))
+	size, _, _ := db.Size()
+	restoredSize, _, _ := restored.Size()
+	assert.Equal(t, size, restoredSize)
+
+	// The reference counts are restored, so the roots are garbage collected the same
+	restoredTrie, err := NewTrie(root1, restored, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value-0"), restoredTrie.Get([]byte("key-0")))
+
+	db.Dereference(root1)
+	restored.Dereference(root1)
+	assert.Equal(t, sortedNodes(db), sortedNodes(restored))
+
+	restoredTrie, err = NewTrie(root2, restored, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("changed"), restoredTrie.Get([]byte("key-0")))
+
+	db.Dereference(root2)
+	restored.Dereference(root2)
+	assert.Empty(t, restored.Nodes())
+}
+
 func TestCache(t *testing.T) {
 	memDB := database.NewMemoryDBManager()
 	db := NewDatabaseWithNewCache(memDB, &TrieNodeCacheConfig{CacheType: CacheTypeLocal, LocalCacheSizeMiB: 10})
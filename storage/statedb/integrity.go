@@ -0,0 +1,202 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package statedb
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/rcrowley/go-metrics"
+)
+
+// integrityQueueSize is the maximum number of the sampled trie nodes waiting for
+// the verification. The samples are dropped while the queue is full.
+const integrityQueueSize = 4096
+
+// integrityCheckDelay is the time a sampled trie node waits before it's verified,
+// so that it's read back from the disk rather than from the write buffers.
+var integrityCheckDelay = time.Minute
+
+var (
+	integrityCheckedMeter   = metrics.NewRegisteredMeter("trie/integrity/checked", nil)
+	integrityDroppedMeter   = metrics.NewRegisteredMeter("trie/integrity/dropped", nil)
+	integrityCorruptCounter = metrics.NewRegisteredCounter("trie/integrity/corrupt", nil)
+)
+
+// IntegrityCheckConfig contains the settings of the background trie integrity check.
+type IntegrityCheckConfig struct {
+	SampleRate uint64                    // One out of SampleRate trie nodes written to the disk is verified
+	OnCorrupt  func(hash common.ExtHash) // Called with the hash of a quarantined trie node, e.g. to re-fetch it
+}
+
+// integritySample is a trie node written to the disk, waiting for the verification.
+type integritySample struct {
+	hash    common.ExtHash
+	written time.Time
+}
+
+// integrityChecker samples the trie nodes written to the disk and verifies them
+// against their hashes in the background. A corrupted node is quarantined, so
+// that the block import fails on a missing node instead of crashing on decoding
+// the corrupted one.
+type integrityChecker struct {
+	diskDB database.DBManager
+	config IntegrityCheckConfig
+
+	written uint64 // Number of the trie nodes written, accessed atomically
+	queue   chan integritySample
+	quit    chan struct{}
+	done    chan struct{}
+}
+
+// StartIntegrityCheck starts verifying the trie nodes written to the disk in the
+// background. It does nothing if the check is already running or the sample rate
+// is zero.
+func (db *Database) StartIntegrityCheck(config IntegrityCheckConfig) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.integrity != nil || config.SampleRate == 0 {
+		return
+	}
+	db.integrity = &integrityChecker{
+		diskDB: db.diskDB,
+		config: config,
+		queue:  make(chan integritySample, integrityQueueSize),
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go db.integrity.loop()
+	logger.Info("Started trie integrity check", "sampleRate", config.SampleRate)
+}
+
+// StopIntegrityCheck stops the background trie integrity check, dropping the
+// samples not verified yet.
+func (db *Database) StopIntegrityCheck() {
+	db.lock.Lock()
+	c := db.integrity
+	db.integrity = nil
+	db.lock.Unlock()
+
+	if c != nil {
+		close(c.quit)
+		<-c.done
+	}
+}
+
+// sample picks the trie node written to the disk for the verification at the
+// configured sample rate. It's safe to be called on a nil checker.
+func (c *integrityChecker) sample(hash common.ExtHash) {
+	if c == nil || atomic.AddUint64(&c.written, 1)%c.config.SampleRate != 0 {
+		return
+	}
+	select {
+	case c.queue <- integritySample{hash: hash, written: time.Now()}:
+	default:
+		integrityDroppedMeter.Mark(1)
+	}
+}
+
+// loop verifies the sampled trie nodes once they are old enough.
+func (c *integrityChecker) loop() {
+	defer close(c.done)
+
+	timer := time.NewTimer(0)
+	<-timer.C
+	for {
+		var s integritySample
+		select {
+		case s = <-c.queue:
+		case <-c.quit:
+			return
+		}
+		if wait := time.Until(s.written.Add(integrityCheckDelay)); wait > 0 {
+			timer.Reset(wait)
+			select {
+			case <-timer.C:
+			case <-c.quit:
+				timer.Stop()
+				return
+			}
+		}
+		c.verify(s.hash)
+	}
+}
+
+// verify reads the trie node back from the disk and quarantines it if the value
+// doesn't match the hash.
+func (c *integrityChecker) verify(hash common.ExtHash) {
+	enc, err := c.diskDB.ReadTrieNode(hash)
+	if err != nil || len(enc) == 0 {
+		return // The node may have been pruned in the meantime
+	}
+	integrityCheckedMeter.Mark(1)
+	if verifyNodeHash(hash, enc) {
+		return
+	}
+	integrityCorruptCounter.Inc(1)
+	logger.Error("Corrupted trie node found, quarantining it", "hash", hash, "size", len(enc))
+
+	c.diskDB.QuarantineTrieNode(hash, enc)
+	if c.config.OnCorrupt != nil {
+		c.config.OnCorrupt(hash)
+	}
+}
+
+// verifyNodeHash returns whether the stored encoding of a trie node matches its
+// hash. The extended hashes of the children are stripped before hashing, like
+// the hasher does for the merkle proof.
+func verifyNodeHash(hash common.ExtHash, enc []byte) bool {
+	want := hash.Unextend()
+	if crypto.Keccak256Hash(enc) == want {
+		return true
+	}
+	n, err := decodeNode(want[:], enc)
+	if err != nil {
+		return false
+	}
+	h := newHasher(nil)
+	defer returnHasherToPool(h)
+
+	h.nodeForHashing(collapseNode(n)).encode(h.encbuf)
+	return crypto.Keccak256Hash(h.encodedBytes()) == want
+}
+
+// collapseNode converts the keys of the short nodes in a decoded node back to the
+// compact encoding they are hashed in.
+func collapseNode(n node) node {
+	switch n := n.(type) {
+	case *shortNode:
+		collapsed := n.copy()
+		collapsed.Key = hexToCompact(n.Key)
+		collapsed.Val = collapseNode(n.Val)
+		return collapsed
+	case *fullNode:
+		collapsed := n.copy()
+		for i := 0; i < 16; i++ {
+			if n.Children[i] != nil {
+				collapsed.Children[i] = collapseNode(n.Children[i])
+			}
+		}
+		return collapsed
+	default:
+		return n
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package statedb
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newIntegrityTestTrie commits a trie with some entries to the disk and returns
+// the hashes of the committed nodes.
+func newIntegrityTestTrie(t *testing.T, db *Database) []common.ExtHash {
+	trie, err := NewTrie(common.Hash{}, db, nil)
+	require.NoError(t, err)
+	for i := 0; i < 100; i++ {
+		trie.Update([]byte(fmt.Sprintf("key-%d", i)), []byte(fmt.Sprintf("value-%d", i)))
+	}
+	root, err := trie.Commit(nil)
+	require.NoError(t, err)
+
+	nodes := db.Nodes()
+	require.NoError(t, db.Commit(root, false, 0))
+	return nodes
+}
+
+func TestVerifyNodeHash(t *testing.T) {
+	for _, pruning := range []bool{false, true} {
+		memDB := database.NewMemoryDBManager()
+		if pruning {
+			memDB.WritePruningEnabled()
+		}
+		db := NewDatabase(memDB)
+
+		nodes := newIntegrityTestTrie(t, db)
+		require.NotEmpty(t, nodes)
+		for _, hash := range nodes {
+			if common.EmptyExtHash(hash) {
+				continue // The meta root
+			}
+			enc, err := memDB.ReadTrieNode(hash)
+			require.NoError(t, err)
+			assert.True(t, verifyNodeHash(hash, enc), "pruning: %v, node: %x", pruning, hash)
+
+			// The extensions of the child hashes aren't covered by the hash, so corrupt
+			// the head of the node rather than its tail.
+			corrupted := common.CopyBytes(enc)
+			corrupted[1] ^= 0xff
+			assert.False(t, verifyNodeHash(hash, corrupted), "pruning: %v, node: %x", pruning, hash)
+		}
+	}
+}
+
+func TestIntegrityCheck_Quarantine(t *testing.T) {
+	defer func(delay time.Duration) { integrityCheckDelay = delay }(integrityCheckDelay)
+	integrityCheckDelay = 100 * time.Millisecond
+
+	memDB := database.NewMemoryDBManager()
+	db := NewDatabase(memDB)
+
+	corrupted := make(chan common.ExtHash, 1)
+	db.StartIntegrityCheck(IntegrityCheckConfig{
+		SampleRate: 1,
+		OnCorrupt:  func(hash common.ExtHash) { corrupted <- hash },
+	})
+	defer db.StopIntegrityCheck()
+
+	// Corrupt a node on the disk before the check reads it back
+	var target common.ExtHash
+	for _, hash := range newIntegrityTestTrie(t, db) {
+		if !common.EmptyExtHash(hash) {
+			target = hash
+			break
+		}
+	}
+	memDB.WriteTrieNode(target, []byte{0xc2, 0x80, 0x80})
+
+	select {
+	case hash := <-corrupted:
+		assert.Equal(t, target, hash)
+	case <-time.After(5 * time.Second):
+		t.Fatal("corrupted trie node not found")
+	}
+	// The corrupted node must be moved out of the state trie database
+	ok, _ := memDB.HasTrieNode(target)
+	assert.False(t, ok)
+	assert.Equal(t, []common.ExtHash{target}, memDB.ReadQuarantinedTrieNodes())
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package verkle
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/kaiachain/kaia/common"
+)
+
+// generatorSeed is the domain separator of the generator derivation.
+const generatorSeed = "kaia_verkle_prototype_generator"
+
+var (
+	generatorsOnce sync.Once
+	generators     [NodeWidth]bandersnatch.PointAffine
+	groupOrder     *big.Int // the order of the prime order subgroup, i.e. the modulus of the committed scalars
+)
+
+// getGenerators returns the NodeWidth generators of the Pedersen vector commitment.
+// The generators are derived by hashing to the curve, so that no one knows the discrete log
+// between any two of them.
+func getGenerators() *[NodeWidth]bandersnatch.PointAffine {
+	generatorsOnce.Do(func() {
+		curve := bandersnatch.GetEdwardsCurve()
+		groupOrder = new(big.Int).Set(&curve.Order)
+		for i := range generators {
+			generators[i] = hashToPoint(&curve, uint64(i))
+		}
+	})
+	return &generators
+}
+
+// hashToPoint maps the index to a point in the prime order subgroup by try-and-increment:
+// y is hashed from (seed, index, counter) until ax^2 + y^2 = 1 + dx^2y^2 has a solution for x,
+// and the point is then multiplied by the cofactor.
+func hashToPoint(curve *bandersnatch.CurveParams, index uint64) bandersnatch.PointAffine {
+	var buf [16]byte
+	binary.BigEndian.PutUint64(buf[:8], index)
+	for ctr := uint64(0); ; ctr++ {
+		binary.BigEndian.PutUint64(buf[8:], ctr)
+		h := sha256.Sum256(append([]byte(generatorSeed), buf[:]...))
+
+		var one, y, y2, num, den, x fr.Element
+		one.SetOne()
+		y.SetBytes(h[:])
+		y2.Square(&y)
+		num.Sub(&one, &y2)
+		den.Mul(&y2, &curve.D)
+		den.Sub(&curve.A, &den)
+		if den.IsZero() {
+			continue
+		}
+		x.Div(&num, &den)
+		if x.Sqrt(&x) == nil {
+			continue
+		}
+
+		p := bandersnatch.NewPointAffine(x, y)
+		p.Double(&p)
+		p.Double(&p)
+		if p.IsZero() {
+			continue
+		}
+		return p
+	}
+}
+
+// commitment is a Pedersen vector commitment, sum(v_i * G_i).
+type commitment struct {
+	point bandersnatch.PointAffine
+}
+
+func newCommitment() commitment {
+	var c commitment
+	c.point.Y.SetOne() // the identity
+	return c
+}
+
+// update adds (to - from) * G_i to the commitment. Because the commitment is linear, changing the
+// i-th value from a to b is the same as adding (b - a) * G_i.
+func (c *commitment) update(i int, from, to *big.Int) {
+	gens := getGenerators()
+	delta := new(big.Int).Sub(to, from)
+	if delta.Mod(delta, groupOrder).Sign() == 0 {
+		return
+	}
+	var p bandersnatch.PointAffine
+	p.ScalarMultiplication(&gens[i], delta)
+	c.point.Add(&c.point, &p)
+}
+
+// scalar maps the commitment to a scalar to be committed by the parent node: X/Y reduced modulo
+// the group order. The identity maps to zero.
+func (c *commitment) scalar() *big.Int {
+	if c.point.Y.IsZero() {
+		return new(big.Int)
+	}
+	var s fr.Element
+	s.Div(&c.point.X, &c.point.Y)
+	ret := s.BigInt(new(big.Int))
+	return ret.Mod(ret, getGroupOrder())
+}
+
+func getGroupOrder() *big.Int {
+	getGenerators()
+	return groupOrder
+}
+
+// Hash returns the compressed encoding of the commitment.
+func (c *commitment) Hash() common.Hash {
+	return common.Hash(c.point.Bytes())
+}
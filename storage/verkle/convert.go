@@ -0,0 +1,178 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package verkle
+
+import (
+	"fmt"
+
+	"github.com/holiman/uint256"
+	"github.com/kaiachain/kaia/blockchain/types/account"
+	"github.com/kaiachain/kaia/blockchain/types/accountkey"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/rlp"
+	"github.com/kaiachain/kaia/storage/statedb"
+)
+
+var emptyCodeHash = crypto.Keccak256(nil)
+
+// ConvertStats counts the entries converted from the MPT.
+type ConvertStats struct {
+	Accounts     uint64 `json:"accounts"`
+	StorageSlots uint64 `json:"storageSlots"`
+	CodeChunks   uint64 `json:"codeChunks"`
+}
+
+// InsertAccount writes the header and the code chunks of an account. The code is ignored unless
+// the account is a program account.
+func InsertAccount(t *Tree, addrHash common.Hash, acc account.Account, code []byte) error {
+	codeHash := emptyCodeHash
+	if pa := account.GetProgramAccount(acc); pa != nil {
+		codeHash = pa.GetCodeHash()
+	} else {
+		code = nil
+	}
+
+	version := make([]byte, ValueSize)
+	version[0] = byte(acc.Type())
+	balance, overflow := uint256.FromBig(acc.GetBalance())
+	if overflow {
+		return fmt.Errorf("balance of %x overflows", addrHash)
+	}
+
+	header := map[byte][]byte{
+		VersionLeafKey:  version,
+		BalanceLeafKey:  uint256Value(balance),
+		NonceLeafKey:    uint256Value(uint256.NewInt(acc.GetNonce())),
+		CodeHashLeafKey: common.BytesToHash(codeHash).Bytes(),
+		CodeSizeLeafKey: uint256Value(uint256.NewInt(uint64(len(code)))),
+	}
+	if ak := account.GetAccountWithKey(acc); ak != nil && ak.GetKey() != nil {
+		enc, err := rlp.EncodeToBytes(accountkey.NewAccountKeySerializerWithAccountKey(ak.GetKey()))
+		if err != nil {
+			return err
+		}
+		header[AccountKeyHashLeafKey] = crypto.Keccak256(enc)
+	}
+	for leaf, value := range header {
+		if err := t.Insert(AccountHeaderKey(addrHash, leaf), value); err != nil {
+			return err
+		}
+	}
+	for i, chunk := range ChunkifyCode(code) {
+		if err := t.Insert(CodeChunkKey(addrHash, uint64(i)), chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteAccount removes the header and the code chunks of an account with the code of the given length.
+// The storage slots must be deleted separately.
+func DeleteAccount(t *Tree, addrHash common.Hash, codeLen int) error {
+	for leaf := byte(VersionLeafKey); leaf <= AccountKeyHashLeafKey; leaf++ {
+		if err := t.Delete(AccountHeaderKey(addrHash, leaf)); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < (codeLen+StemSize-1)/StemSize; i++ {
+		if err := t.Delete(CodeChunkKey(addrHash, uint64(i))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InsertStorage writes a storage slot given its value as stored in the MPT, i.e. RLP-encoded
+// with the leading zeros trimmed. An empty value deletes the slot.
+func InsertStorage(t *Tree, addrHash, slotHash common.Hash, enc []byte) error {
+	key := StorageSlotKey(addrHash, slotHash)
+	if len(enc) == 0 {
+		return t.Delete(key)
+	}
+	_, content, _, err := rlp.Split(enc)
+	if err != nil {
+		return err
+	}
+	if len(content) == 0 {
+		return t.Delete(key)
+	}
+	return t.Insert(key, common.BytesToHash(content).Bytes())
+}
+
+// uint256Value encodes an integer in little-endian as in EIP-6800.
+func uint256Value(v *uint256.Int) []byte {
+	b := v.Bytes32()
+	return reverse(b[:])
+}
+
+// ConvertState builds a verkle tree from the MPT state at the root, including the storage and the code.
+func ConvertState(db *statedb.Database, root common.Hash) (*Tree, *ConvertStats, error) {
+	accTrie, err := statedb.NewSecureTrie(root, db, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		t     = NewTree()
+		stats = &ConvertStats{}
+		it    = statedb.NewIterator(accTrie.NodeIterator(nil))
+	)
+	for it.Next() {
+		addrHash := common.BytesToHash(it.Key)
+		serializer := account.NewAccountSerializer()
+		if err := rlp.DecodeBytes(it.Value, serializer); err != nil {
+			return nil, nil, fmt.Errorf("malformed account %x: %w", it.Key, err)
+		}
+		acc := serializer.GetAccount()
+
+		var code []byte
+		if pa := account.GetProgramAccount(acc); pa != nil {
+			code = db.DiskDB().ReadCode(common.BytesToHash(pa.GetCodeHash()))
+			slots, err := convertStorage(t, db, addrHash, pa.GetStorageRoot())
+			if err != nil {
+				return nil, nil, err
+			}
+			stats.StorageSlots += slots
+		}
+		if err := InsertAccount(t, addrHash, acc, code); err != nil {
+			return nil, nil, err
+		}
+		stats.Accounts++
+		stats.CodeChunks += uint64(len(ChunkifyCode(code)))
+	}
+	if it.Err != nil {
+		return nil, nil, it.Err
+	}
+	t.Commit()
+	return t, stats, nil
+}
+
+func convertStorage(t *Tree, db *statedb.Database, addrHash common.Hash, root common.ExtHash) (uint64, error) {
+	storageTrie, err := statedb.NewSecureStorageTrie(root, db, nil)
+	if err != nil {
+		return 0, err
+	}
+	count := uint64(0)
+	it := statedb.NewIterator(storageTrie.NodeIterator(nil))
+	for it.Next() {
+		if err := InsertStorage(t, addrHash, common.BytesToHash(it.Key), it.Value); err != nil {
+			return 0, fmt.Errorf("malformed storage %x of %x: %w", it.Key, addrHash, err)
+		}
+		count++
+	}
+	return count, it.Err
+}
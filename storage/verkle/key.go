@@ -0,0 +1,103 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package verkle
+
+import (
+	"github.com/holiman/uint256"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto"
+)
+
+// The leaves of the account header, at the tree index 0 of an account.
+// AccountKeyHashLeafKey is a Kaia extension committing to the account key.
+const (
+	VersionLeafKey        = 0 // the account type in the first byte
+	BalanceLeafKey        = 1
+	NonceLeafKey          = 2
+	CodeHashLeafKey       = 3
+	CodeSizeLeafKey       = 4
+	AccountKeyHashLeafKey = 5
+
+	CodeOffset = 128 // the position of the first code chunk
+)
+
+// mainStorageIndexOffset is the tree index of the first storage slot, MAIN_STORAGE_OFFSET / NodeWidth
+// of EIP-6800.
+var mainStorageIndexOffset = new(uint256.Int).Lsh(uint256.NewInt(1), 240)
+
+// treeKey returns the key of the subIndex-th value at the treeIndex of an account.
+// Unlike EIP-6800, the stem is derived from the hashed address with keccak256 instead of the
+// Pedersen hash of the address, so that a tree can be built from the MPT without the preimages.
+func treeKey(addrHash common.Hash, treeIndex *uint256.Int, subIndex byte) []byte {
+	index := treeIndex.Bytes32()
+	stem := crypto.Keccak256(addrHash[:], reverse(index[:]))[:StemSize]
+	return append(stem, subIndex)
+}
+
+// AccountHeaderKey returns the key of a leaf of the account header, e.g. BalanceLeafKey.
+func AccountHeaderKey(addrHash common.Hash, leaf byte) []byte {
+	return treeKey(addrHash, new(uint256.Int), leaf)
+}
+
+// StorageSlotKey returns the key of a storage slot. The slot is hashed as in the MPT, so
+// every slot is in the main storage and the header storage of EIP-6800 is not used.
+func StorageSlotKey(addrHash, slotHash common.Hash) []byte {
+	index := new(uint256.Int).SetBytes(slotHash[:])
+	index.Rsh(index, 8)
+	index.Add(index, mainStorageIndexOffset)
+	return treeKey(addrHash, index, slotHash[common.HashLength-1])
+}
+
+// CodeChunkKey returns the key of the i-th code chunk.
+func CodeChunkKey(addrHash common.Hash, chunk uint64) []byte {
+	pos := uint256.NewInt(CodeOffset + chunk)
+	subIndex := byte(pos.Uint64() % NodeWidth)
+	return treeKey(addrHash, pos.Rsh(pos, 8), subIndex)
+}
+
+const (
+	push1  = 0x60
+	push32 = 0x7f
+)
+
+// ChunkifyCode splits the code into 32-byte chunks of 31 code bytes, each prefixed by the number
+// of its leading bytes that are push data, as in EIP-6800.
+func ChunkifyCode(code []byte) [][]byte {
+	if len(code)%StemSize != 0 {
+		code = append(common.CopyBytes(code), make([]byte, StemSize-len(code)%StemSize)...)
+	}
+	pushData := make([]int, len(code)+32)
+	for pos := 0; pos < len(code); {
+		n := 0
+		if op := code[pos]; push1 <= op && op <= push32 {
+			n = int(op-push1) + 1
+		}
+		pos++
+		for i := 0; i < n; i++ {
+			pushData[pos+i] = n - i
+		}
+		pos += n
+	}
+
+	chunks := make([][]byte, 0, len(code)/StemSize)
+	for pos := 0; pos < len(code); pos += StemSize {
+		chunk := make([]byte, ValueSize)
+		chunk[0] = byte(min(pushData[pos], StemSize))
+		copy(chunk[1:], code[pos:pos+StemSize])
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
@@ -0,0 +1,342 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package verkle implements a prototype verkle tree, a 256-ary trie committed by Pedersen vector
+// commitments over the Bandersnatch curve, to evaluate stateless-friendly state commitments.
+//
+// The tree follows the layout of EIP-6800: a 32-byte key is a 31-byte stem and a 1-byte suffix,
+// the 256 values of a stem are kept in one leaf, and the internal nodes branch on the stem bytes.
+// It is not used for consensus.
+package verkle
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/kaiachain/kaia/common"
+)
+
+const (
+	NodeWidth = 256
+	StemSize  = 31
+	KeySize   = StemSize + 1
+	ValueSize = 32
+)
+
+var (
+	ErrInvalidKeySize   = errors.New("verkle key must be 32 bytes")
+	ErrInvalidValueSize = errors.New("verkle value must be 32 bytes")
+
+	// leafMarker is added to the lower half of every present value, so that a zero value
+	// is distinguished from an absent one.
+	leafMarker = new(big.Int).Lsh(big.NewInt(1), 128)
+)
+
+type node interface {
+	commit() *commitment
+}
+
+// internalNode branches on the stem byte at its depth. A child is nil, an *internalNode or a *leafNode.
+type internalNode struct {
+	depth    int
+	children [NodeWidth]node
+
+	commitment commitment
+	scalars    [NodeWidth]*big.Int // the children's commitments as last committed, nil for zero
+	changed    [NodeWidth]bool     // the children that may differ from scalars
+	dirty      bool
+}
+
+// leafNode holds the values of a stem. Its commitment is
+// 1*G_0 + stem*G_1 + s(C1)*G_2 + s(C2)*G_3, where C1 and C2 commit to the lower and the upper
+// 128 values, each value taking two slots for its lower and upper 16 bytes.
+type leafNode struct {
+	stem   []byte
+	values [NodeWidth][]byte
+
+	commitment commitment
+	c1, c2     commitment
+	changed    map[byte][]byte // the previous values of the suffixes changed since the last commit
+}
+
+func newInternalNode(depth int) *internalNode {
+	return &internalNode{depth: depth, commitment: newCommitment(), dirty: true}
+}
+
+func newLeafNode(stem []byte) *leafNode {
+	return &leafNode{
+		stem:       common.CopyBytes(stem),
+		commitment: newCommitment(),
+		c1:         newCommitment(),
+		c2:         newCommitment(),
+		changed:    make(map[byte][]byte),
+	}
+}
+
+func (n *internalNode) commit() *commitment {
+	if !n.dirty {
+		return &n.commitment
+	}
+	for i, child := range n.children {
+		if !n.changed[i] {
+			continue
+		}
+		s := new(big.Int)
+		if child != nil {
+			s = child.commit().scalar()
+		}
+		prev := n.scalars[i]
+		if prev == nil {
+			prev = new(big.Int)
+		}
+		n.commitment.update(i, prev, s)
+		n.scalars[i] = s
+		if s.Sign() == 0 {
+			n.scalars[i] = nil
+		}
+		n.changed[i] = false
+	}
+	n.dirty = false
+	return &n.commitment
+}
+
+func (n *internalNode) setChanged(idx byte) {
+	n.changed[idx] = true
+	n.dirty = true
+}
+
+func (n *internalNode) numChildren() int {
+	count := 0
+	for _, child := range n.children {
+		if child != nil {
+			count++
+		}
+	}
+	return count
+}
+
+func (n *leafNode) commit() *commitment {
+	if len(n.changed) == 0 {
+		return &n.commitment
+	}
+	for suffix, prev := range n.changed {
+		c := &n.c1
+		if suffix >= NodeWidth/2 {
+			c = &n.c2
+		}
+		oldLo, oldHi := valueScalars(prev)
+		newLo, newHi := valueScalars(n.values[suffix])
+		idx := 2 * (int(suffix) % (NodeWidth / 2))
+		c.update(idx, oldLo, newLo)
+		c.update(idx+1, oldHi, newHi)
+	}
+	n.changed = make(map[byte][]byte)
+
+	zero := new(big.Int)
+	n.commitment = newCommitment()
+	n.commitment.update(0, zero, big.NewInt(1))
+	n.commitment.update(1, zero, new(big.Int).SetBytes(reverse(n.stem)))
+	n.commitment.update(2, zero, n.c1.scalar())
+	n.commitment.update(3, zero, n.c2.scalar())
+	return &n.commitment
+}
+
+func (n *leafNode) set(suffix byte, value []byte) {
+	if _, ok := n.changed[suffix]; !ok {
+		n.changed[suffix] = n.values[suffix]
+	}
+	n.values[suffix] = value
+}
+
+func (n *leafNode) empty() bool {
+	for _, v := range n.values {
+		if v != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// valueScalars splits a value into its lower and upper 16 bytes, read as little-endian.
+// The lower half of a present value is offset by leafMarker.
+func valueScalars(value []byte) (lo, hi *big.Int) {
+	if value == nil {
+		return new(big.Int), new(big.Int)
+	}
+	lo = new(big.Int).SetBytes(reverse(value[:16]))
+	return lo.Add(lo, leafMarker), new(big.Int).SetBytes(reverse(value[16:]))
+}
+
+func reverse(b []byte) []byte {
+	ret := make([]byte, len(b))
+	for i := range b {
+		ret[len(b)-1-i] = b[i]
+	}
+	return ret
+}
+
+// Tree is an in-memory verkle tree. It is not safe for concurrent use.
+type Tree struct {
+	root *internalNode
+}
+
+func NewTree() *Tree {
+	return &Tree{root: newInternalNode(0)}
+}
+
+// Get returns the value at the key, or nil if absent.
+func (t *Tree) Get(key []byte) ([]byte, error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKeySize
+	}
+	n := t.root
+	for {
+		switch child := n.children[key[n.depth]].(type) {
+		case nil:
+			return nil, nil
+		case *leafNode:
+			if !bytes.Equal(child.stem, key[:StemSize]) {
+				return nil, nil
+			}
+			return child.values[key[StemSize]], nil
+		case *internalNode:
+			n = child
+		}
+	}
+}
+
+// Insert sets the value at the key. A nil value deletes the key.
+func (t *Tree) Insert(key, value []byte) error {
+	if len(key) != KeySize {
+		return ErrInvalidKeySize
+	}
+	if value == nil {
+		return t.Delete(key)
+	}
+	if len(value) != ValueSize {
+		return ErrInvalidValueSize
+	}
+	value = common.CopyBytes(value)
+
+	n := t.root
+	for {
+		idx := key[n.depth]
+		n.setChanged(idx)
+		switch child := n.children[idx].(type) {
+		case nil:
+			leaf := newLeafNode(key[:StemSize])
+			leaf.set(key[StemSize], value)
+			n.children[idx] = leaf
+			return nil
+		case *leafNode:
+			if bytes.Equal(child.stem, key[:StemSize]) {
+				child.set(key[StemSize], value)
+				return nil
+			}
+			// Split: push the existing leaf one level down and retry from the new node.
+			next := newInternalNode(n.depth + 1)
+			next.children[child.stem[next.depth]] = child
+			next.setChanged(child.stem[next.depth])
+			n.children[idx] = next
+			n = next
+		case *internalNode:
+			n = child
+		}
+	}
+}
+
+// Delete removes the value at the key. A leaf without values is removed, and an internal node
+// left with a single leaf is replaced by the leaf.
+func (t *Tree) Delete(key []byte) error {
+	if len(key) != KeySize {
+		return ErrInvalidKeySize
+	}
+	t.delete(t.root, key)
+	return nil
+}
+
+// delete returns true if the subtree of n has changed.
+func (t *Tree) delete(n *internalNode, key []byte) bool {
+	idx := key[n.depth]
+	switch child := n.children[idx].(type) {
+	case nil:
+		return false
+	case *leafNode:
+		if !bytes.Equal(child.stem, key[:StemSize]) || child.values[key[StemSize]] == nil {
+			return false
+		}
+		child.set(key[StemSize], nil)
+		if child.empty() {
+			n.children[idx] = nil
+		}
+	case *internalNode:
+		if !t.delete(child, key) {
+			return false
+		}
+		switch child.numChildren() {
+		case 0:
+			n.children[idx] = nil
+		case 1:
+			for _, grandchild := range child.children {
+				if leaf, ok := grandchild.(*leafNode); ok {
+					n.children[idx] = leaf
+				}
+			}
+		}
+	}
+	n.setChanged(idx)
+	return true
+}
+
+// Commit updates the commitments of the changed nodes and returns the root commitment.
+func (t *Tree) Commit() common.Hash {
+	return t.root.commit().Hash()
+}
+
+// TreeStats describes the shape of a tree.
+type TreeStats struct {
+	InternalNodes uint64 `json:"internalNodes"`
+	Leaves        uint64 `json:"leaves"`
+	Values        uint64 `json:"values"`
+	MaxDepth      int    `json:"maxDepth"` // the depth of the deepest leaf
+}
+
+func (t *Tree) Stats() TreeStats {
+	var stats TreeStats
+	var walk func(n *internalNode)
+	walk = func(n *internalNode) {
+		stats.InternalNodes++
+		for _, child := range n.children {
+			switch child := child.(type) {
+			case *internalNode:
+				walk(child)
+			case *leafNode:
+				stats.Leaves++
+				if n.depth+1 > stats.MaxDepth {
+					stats.MaxDepth = n.depth + 1
+				}
+				for _, v := range child.values {
+					if v != nil {
+						stats.Values++
+					}
+				}
+			}
+		}
+	}
+	walk(t.root)
+	return stats
+}
@@ -0,0 +1,209 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package verkle
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/kaiachain/kaia/blockchain/state"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func randKeyValues(r *rand.Rand, n int) ([][]byte, [][]byte) {
+	keys, values := make([][]byte, n), make([][]byte, n)
+	for i := range keys {
+		keys[i], values[i] = make([]byte, KeySize), make([]byte, ValueSize)
+		r.Read(keys[i])
+		r.Read(values[i])
+		if i%4 == 1 {
+			// share the stem with the previous key
+			copy(keys[i], keys[i-1][:StemSize])
+		}
+	}
+	return keys, values
+}
+
+func TestTree(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	keys, values := randKeyValues(r, 64)
+
+	tree := NewTree()
+	empty := tree.Commit()
+	for i := range keys {
+		require.NoError(t, tree.Insert(keys[i], values[i]))
+	}
+	for i := range keys {
+		v, err := tree.Get(keys[i])
+		require.NoError(t, err)
+		assert.Equal(t, values[i], v)
+	}
+	root := tree.Commit()
+	assert.NotEqual(t, empty, root)
+
+	// The root does not depend on the insertion order nor on the intermediate commits.
+	other := NewTree()
+	for _, i := range r.Perm(len(keys)) {
+		require.NoError(t, other.Insert(keys[i], values[i]))
+		if i%8 == 0 {
+			other.Commit()
+		}
+	}
+	assert.Equal(t, root, other.Commit())
+
+	// Inserting and deleting another key restores the root.
+	extra := common.CopyBytes(keys[0])
+	extra[StemSize-1] ^= 0xff
+	require.NoError(t, tree.Insert(extra, values[0]))
+	assert.NotEqual(t, root, tree.Commit())
+	require.NoError(t, tree.Delete(extra))
+	assert.Equal(t, root, tree.Commit())
+
+	// A zero value differs from an absent one.
+	require.NoError(t, tree.Insert(extra, make([]byte, ValueSize)))
+	assert.NotEqual(t, root, tree.Commit())
+
+	// Deleting every key empties the tree.
+	require.NoError(t, tree.Delete(extra))
+	for i := range keys {
+		require.NoError(t, tree.Delete(keys[i]))
+	}
+	assert.Equal(t, empty, tree.Commit())
+	assert.Equal(t, TreeStats{InternalNodes: 1}, tree.Stats())
+
+	assert.ErrorIs(t, tree.Insert(keys[0][:10], values[0]), ErrInvalidKeySize)
+	assert.ErrorIs(t, tree.Insert(keys[0], values[0][:10]), ErrInvalidValueSize)
+}
+
+func TestChunkifyCode(t *testing.T) {
+	// PUSH4 at the end of the first chunk spills three bytes of push data into the second.
+	code := make([]byte, 33)
+	code[29] = push1 + 3
+	code[30], code[31], code[32] = 1, 2, 3
+
+	chunks := ChunkifyCode(code)
+	require.Len(t, chunks, 2)
+	assert.Equal(t, byte(0), chunks[0][0])
+	assert.Equal(t, code[:StemSize], chunks[0][1:])
+	assert.Equal(t, byte(3), chunks[1][0])
+	assert.Equal(t, code[StemSize:], chunks[1][1:3])
+	assert.Empty(t, ChunkifyCode(nil))
+}
+
+func TestWitness(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	keys, values := randKeyValues(r, 32)
+
+	tree := NewTree()
+	for i := range keys {
+		require.NoError(t, tree.Insert(keys[i], values[i]))
+	}
+
+	absent := common.CopyBytes(keys[0])
+	absent[KeySize-1] ^= 0xff // same stem
+	otherStem := common.CopyBytes(keys[0])
+	otherStem[StemSize-1] ^= 0xff // ends at the leaf of keys[0]
+
+	w, err := tree.Witness([][]byte{keys[0], keys[1], absent, otherStem})
+	require.NoError(t, err)
+	assert.Equal(t, tree.Commit(), w.Root)
+	require.Len(t, w.Stems, 2)
+	for _, sw := range w.Stems {
+		switch string(sw.Stem) {
+		case string(keys[0][:StemSize]):
+			assert.Equal(t, uint8(StemPresent), sw.Status)
+			require.Len(t, sw.Values, 3)
+			for i, suffix := range sw.Suffixes {
+				switch suffix {
+				case keys[0][StemSize]:
+					assert.Equal(t, values[0], []byte(sw.Values[i]))
+				case keys[1][StemSize]:
+					assert.Equal(t, values[1], []byte(sw.Values[i]))
+				default:
+					assert.Nil(t, sw.Values[i])
+				}
+			}
+		case string(otherStem[:StemSize]):
+			assert.Equal(t, uint8(StemAbsentOther), sw.Status)
+			assert.Equal(t, keys[0][:StemSize], []byte(sw.OtherStem))
+		default:
+			t.Fatalf("unexpected stem %x", sw.Stem)
+		}
+	}
+	assert.NotEmpty(t, w.Commitments)
+	assert.Greater(t, w.Size(), 0)
+
+	empty, err := NewTree().Witness([][]byte{keys[0]})
+	require.NoError(t, err)
+	assert.Equal(t, uint8(StemAbsentEmpty), empty.Stems[0].Status)
+	assert.Empty(t, empty.Commitments)
+}
+
+func TestConvertState(t *testing.T) {
+	var (
+		db       = state.NewDatabase(database.NewMemoryDBManager())
+		eoa      = common.HexToAddress("0x1")
+		contract = common.HexToAddress("0x2")
+		code     = []byte{push1, 0x01, push1, 0x00, 0x55} // PUSH1 1 PUSH1 0 SSTORE
+		slot     = common.HexToHash("0x0")
+	)
+	statedb, err := state.New(common.Hash{}, db, nil, nil)
+	require.NoError(t, err)
+	statedb.SetBalance(eoa, big.NewInt(100))
+	statedb.SetNonce(eoa, 3)
+	statedb.CreateSmartContractAccount(contract, params.CodeFormatEVM, params.Rules{})
+	statedb.SetCode(contract, code)
+	statedb.SetState(contract, slot, common.HexToHash("0x1"))
+	root, err := statedb.Commit(false)
+	require.NoError(t, err)
+	require.NoError(t, db.TrieDB().Commit(root, false, 0))
+
+	tree, stats, err := ConvertState(db.TrieDB(), root)
+	require.NoError(t, err)
+	assert.Equal(t, &ConvertStats{Accounts: 2, StorageSlots: 1, CodeChunks: 1}, stats)
+
+	eoaHash, contractHash := crypto.Keccak256Hash(eoa[:]), crypto.Keccak256Hash(contract[:])
+	get := func(key []byte) []byte {
+		v, err := tree.Get(key)
+		require.NoError(t, err)
+		return v
+	}
+	assert.Equal(t, uint256Bytes(100), get(AccountHeaderKey(eoaHash, BalanceLeafKey)))
+	assert.Equal(t, uint256Bytes(3), get(AccountHeaderKey(eoaHash, NonceLeafKey)))
+	assert.Equal(t, emptyCodeHash, get(AccountHeaderKey(eoaHash, CodeHashLeafKey)))
+	assert.Equal(t, crypto.Keccak256(code), get(AccountHeaderKey(contractHash, CodeHashLeafKey)))
+	assert.Equal(t, uint256Bytes(uint64(len(code))), get(AccountHeaderKey(contractHash, CodeSizeLeafKey)))
+	assert.Equal(t, ChunkifyCode(code)[0], get(CodeChunkKey(contractHash, 0)))
+	assert.Equal(t, common.HexToHash("0x1").Bytes(), get(StorageSlotKey(contractHash, crypto.Keccak256Hash(slot[:]))))
+
+	// Deleting the accounts and the slot empties the tree.
+	require.NoError(t, DeleteAccount(tree, eoaHash, 0))
+	require.NoError(t, DeleteAccount(tree, contractHash, len(code)))
+	require.NoError(t, InsertStorage(tree, contractHash, crypto.Keccak256Hash(slot[:]), nil))
+	assert.Equal(t, NewTree().Commit(), tree.Commit())
+}
+
+func uint256Bytes(v uint64) []byte {
+	b := make([]byte, ValueSize)
+	new(big.Int).SetUint64(v).FillBytes(b)
+	return reverse(b)
+}
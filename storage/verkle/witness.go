@@ -0,0 +1,135 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package verkle
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/rlp"
+)
+
+// The status of a stem in a witness, telling how its path ends.
+const (
+	StemPresent     = 0 // at the leaf of the stem
+	StemAbsentEmpty = 1 // at an empty child
+	StemAbsentOther = 2 // at the leaf of another stem
+)
+
+// StemWitness carries the values of the requested suffixes of a stem.
+type StemWitness struct {
+	Stem      hexutil.Bytes   `json:"stem"`
+	Depth     uint8           `json:"depth"` // the depth of the node where the path ends
+	Status    uint8           `json:"status"`
+	OtherStem hexutil.Bytes   `json:"otherStem,omitempty"` // for StemAbsentOther
+	Suffixes  hexutil.Bytes   `json:"suffixes"`
+	Values    []hexutil.Bytes `json:"values"` // empty if absent
+}
+
+// Witness is the part of a tree that a stateless client needs to read the values of a set of keys:
+// the commitments of the nodes on their paths and the values.
+// The opening proof of the commitments, the IPA multiproof of EIP-6800, is not generated by the
+// prototype, and the witness is meant to evaluate the size of the rest.
+type Witness struct {
+	Root        common.Hash   `json:"root"`
+	Stems       []StemWitness `json:"stems"`
+	Commitments []common.Hash `json:"commitments"` // below the root, deduplicated, in the order of the first visit
+}
+
+// Witness commits the tree and returns the witness of the keys.
+func (t *Tree) Witness(keys [][]byte) (*Witness, error) {
+	stems := make(map[string][]byte)
+	for _, key := range keys {
+		if len(key) != KeySize {
+			return nil, ErrInvalidKeySize
+		}
+		stem := string(key[:StemSize])
+		if !bytes.Contains(stems[stem], key[StemSize:]) {
+			stems[stem] = append(stems[stem], key[StemSize])
+		}
+	}
+	sortedStems := make([]string, 0, len(stems))
+	for stem := range stems {
+		sortedStems = append(sortedStems, stem)
+	}
+	slices.Sort(sortedStems)
+
+	var (
+		w    = &Witness{Root: t.Commit()}
+		seen = make(map[common.Hash]bool)
+		add  = func(c *commitment) {
+			if h := c.Hash(); !seen[h] {
+				seen[h] = true
+				w.Commitments = append(w.Commitments, h)
+			}
+		}
+	)
+	for _, stem := range sortedStems {
+		suffixes := stems[stem]
+		slices.Sort(suffixes)
+		sw := StemWitness{Stem: []byte(stem), Suffixes: suffixes}
+
+		n := t.root
+	walk:
+		for {
+			sw.Depth = uint8(n.depth + 1)
+			switch child := n.children[stem[n.depth]].(type) {
+			case nil:
+				sw.Status = StemAbsentEmpty
+				break walk
+			case *leafNode:
+				add(&child.commitment)
+				if string(child.stem) != stem {
+					sw.Status, sw.OtherStem = StemAbsentOther, common.CopyBytes(child.stem)
+					break walk
+				}
+				sw.Status = StemPresent
+				for _, suffix := range suffixes {
+					if suffix < NodeWidth/2 {
+						add(&child.c1)
+					} else {
+						add(&child.c2)
+					}
+				}
+				break walk
+			case *internalNode:
+				add(&child.commitment)
+				n = child
+			}
+		}
+
+		sw.Values = make([]hexutil.Bytes, len(suffixes))
+		if sw.Status == StemPresent {
+			leaf := n.children[stem[n.depth]].(*leafNode)
+			for i, suffix := range suffixes {
+				sw.Values[i] = common.CopyBytes(leaf.values[suffix])
+			}
+		}
+		w.Stems = append(w.Stems, sw)
+	}
+	return w, nil
+}
+
+// Size returns the RLP-encoded size of the witness in bytes.
+func (w *Witness) Size() int {
+	enc, err := rlp.EncodeToBytes(w)
+	if err != nil {
+		return 0
+	}
+	return len(enc)
+}
@@ -108,6 +108,9 @@ func TestValidationPoolInsert(t *testing.T) {
 		if i == types.TxTypeKaiaLast {
 			i = types.TxTypeEthereumAccessList
 		}
+		if i == types.TxTypeEthereumBlob {
+			continue // needs the blob tx hardfork and a sidecar
+		}
 
 		_, err := types.NewTxInternalData(i)
 		if err == nil {
@@ -237,6 +240,9 @@ func TestValidationPoolInsertMagma(t *testing.T) {
 		if i == types.TxTypeKaiaLast {
 			i = types.TxTypeEthereumAccessList
 		}
+		if i == types.TxTypeEthereumBlob {
+			continue // needs the blob tx hardfork and a sidecar
+		}
 
 		_, err := types.NewTxInternalData(i)
 		if err == nil {
@@ -366,6 +372,9 @@ func TestValidationBlockTx(t *testing.T) {
 		if i == types.TxTypeKaiaLast {
 			i = types.TxTypeEthereumAccessList
 		}
+		if i == types.TxTypeEthereumBlob {
+			continue // needs the blob tx hardfork and a sidecar
+		}
 
 		_, err := types.NewTxInternalData(i)
 		if err == nil {
@@ -572,6 +581,9 @@ func TestValidationInvalidSig(t *testing.T) {
 		if i == types.TxTypeKaiaLast {
 			i = types.TxTypeEthereumAccessList
 		}
+		if i == types.TxTypeEthereumBlob {
+			continue // needs the blob tx hardfork and a sidecar
+		}
 
 		_, err := types.NewTxInternalData(i)
 		if err == nil {
@@ -799,6 +811,9 @@ func TestInvalidBalance(t *testing.T) {
 		if i == types.TxTypeKaiaLast {
 			i = types.TxTypeEthereumAccessList
 		}
+		if i == types.TxTypeEthereumBlob {
+			continue // needs the blob tx hardfork and a sidecar
+		}
 
 		_, err := types.NewTxInternalData(i)
 		if err == nil {
@@ -1192,6 +1207,9 @@ func TestInvalidBalanceBlockTx(t *testing.T) {
 		if i == types.TxTypeKaiaLast {
 			i = types.TxTypeEthereumAccessList
 		}
+		if i == types.TxTypeEthereumBlob {
+			continue // needs the blob tx hardfork and a sidecar
+		}
 
 		_, err := types.NewTxInternalData(i)
 		if err == nil {
@@ -1606,6 +1624,9 @@ func TestValidationTxSizeAfterRLP(t *testing.T) {
 		if i == types.TxTypeKaiaLast {
 			i = types.TxTypeEthereumAccessList
 		}
+		if i == types.TxTypeEthereumBlob {
+			continue // needs the blob tx hardfork and a sidecar
+		}
 
 		tx, err := types.NewTxInternalData(i)
 		if err == nil {
@@ -1772,6 +1793,9 @@ func TestValidationPoolResetAfterSenderKeyChange(t *testing.T) {
 		if i == types.TxTypeKaiaLast {
 			i = types.TxTypeEthereumAccessList
 		}
+		if i == types.TxTypeEthereumBlob {
+			continue // needs the blob tx hardfork and a sidecar
+		}
 
 		_, err := types.NewTxInternalData(i)
 		if err == nil {
@@ -1917,6 +1941,9 @@ func TestValidationPoolResetAfterFeePayerKeyChange(t *testing.T) {
 		if i == types.TxTypeKaiaLast {
 			i = types.TxTypeEthereumAccessList
 		}
+		if i == types.TxTypeEthereumBlob {
+			continue // needs the blob tx hardfork and a sidecar
+		}
 
 		_, err := types.NewTxInternalData(i)
 		if err == nil {
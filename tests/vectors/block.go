@@ -0,0 +1,174 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package vectors
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/blockchain/types/derivesha"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/rlp"
+)
+
+// istanbulExtraJSON is the JSON form of types.IstanbulExtra.
+type istanbulExtraJSON struct {
+	Validators    []common.Address   `json:"validators"`
+	Seal          hexutil.Bytes      `json:"seal"`
+	CommittedSeal []hexutil.Bytes    `json:"committedSeal"`
+	KeyRotation   *types.KeyRotation `json:"keyRotation,omitempty"`
+}
+
+// blockJSON is the JSON form of types.Block.
+type blockJSON struct {
+	Header       *types.Header `json:"header"`
+	Transactions []common.Hash `json:"transactions"`
+}
+
+// genBlock returns the block of the given transactions sealed by the validators, and its vectors.
+func genBlock(config *params.ChainConfig, txs types.Transactions) (*types.Block, []*Vector, error) {
+	var (
+		number     = common.Big1
+		proposer   = crypto.PubkeyToAddress(validatorKeys[0].PublicKey)
+		validators = make([]common.Address, len(validatorKeys))
+	)
+	for i, key := range validatorKeys {
+		validators[i] = crypto.PubkeyToAddress(key.PublicKey)
+	}
+
+	header := &types.Header{
+		ParentHash:  crypto.Keccak256Hash([]byte("parent")),
+		Rewardbase:  proposer,
+		Root:        crypto.Keccak256Hash([]byte("state")),
+		TxHash:      derivesha.DeriveShaConcat{}.DeriveSha(txs),
+		ReceiptHash: crypto.Keccak256Hash([]byte("receipts")),
+		BlockScore:  common.Big1,
+		Number:      number,
+		GasUsed:     params.TxGas * uint64(len(txs)),
+		Time:        big.NewInt(1700000000),
+		TimeFoS:     50,
+		Governance:  []byte{},
+		Vote:        []byte{},
+	}
+	if config.IsMagmaForkEnabled(number) {
+		header.BaseFee = big.NewInt(25 * params.Gkei)
+	}
+	if config.IsRandaoForkEnabled(number) {
+		header.RandomReveal = append(crypto.Keccak256([]byte("reveal0")), append(crypto.Keccak256([]byte("reveal1")), crypto.Keccak256([]byte("reveal2"))...)...)
+		header.MixHash = crypto.Keccak256([]byte("mix"))
+	}
+
+	extra := &types.IstanbulExtra{
+		Validators:    validators,
+		Seal:          []byte{},
+		CommittedSeal: [][]byte{},
+	}
+	if config.IsKeyRotationForkEnabled(number) {
+		extra.KeyRotation = &types.KeyRotation{
+			Successor:       crypto.PubkeyToAddress(testKey("successor").PublicKey),
+			ActivationBlock: 100,
+		}
+	}
+	if err := writeExtra(header, extra); err != nil {
+		return nil, nil, err
+	}
+
+	// The proposer seals the header without the committed seals.
+	enc, err := rlp.EncodeToBytes(types.IstanbulFilteredHeader(header, false))
+	if err != nil {
+		return nil, nil, err
+	}
+	if extra.Seal, err = sign(crypto.Keccak256(enc), validatorKeys[0]); err != nil {
+		return nil, nil, err
+	}
+	if err := writeExtra(header, extra); err != nil {
+		return nil, nil, err
+	}
+
+	// The validators commit the block hash, which excludes the committed seals.
+	hash := header.Hash()
+	for _, key := range validatorKeys {
+		seal, err := sign(committedSeal(hash), key)
+		if err != nil {
+			return nil, nil, err
+		}
+		extra.CommittedSeal = append(extra.CommittedSeal, seal)
+	}
+	if err := writeExtra(header, extra); err != nil {
+		return nil, nil, err
+	}
+
+	block := types.NewBlockWithHeader(header).WithBody(txs)
+
+	enc, err = rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, nil, err
+	}
+	headerVector, err := newVector("header", KindHeader, enc, header)
+	if err != nil {
+		return nil, nil, err
+	}
+	headerVector.Hash, headerVector.Signer = hash, &proposer
+
+	extraJSON := &istanbulExtraJSON{
+		Validators:    extra.Validators,
+		Seal:          extra.Seal,
+		CommittedSeal: make([]hexutil.Bytes, len(extra.CommittedSeal)),
+		KeyRotation:   extra.KeyRotation,
+	}
+	for i, seal := range extra.CommittedSeal {
+		extraJSON.CommittedSeal[i] = seal
+	}
+	extraVector, err := newVector("istanbulExtra", KindIstanbulExtra, header.Extra[types.IstanbulExtraVanity:], extraJSON)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	enc, err = rlp.EncodeToBytes(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	txHashes := make([]common.Hash, len(txs))
+	for i, tx := range txs {
+		txHashes[i] = tx.Hash()
+	}
+	blockVector, err := newVector("block", KindBlock, enc, &blockJSON{Header: header, Transactions: txHashes})
+	if err != nil {
+		return nil, nil, err
+	}
+	blockVector.Hash, blockVector.Signer = hash, &proposer
+
+	return block, []*Vector{headerVector, extraVector, blockVector}, nil
+}
+
+func writeExtra(header *types.Header, extra *types.IstanbulExtra) error {
+	payload, err := rlp.EncodeToBytes(extra)
+	if err != nil {
+		return err
+	}
+	header.Extra = append(make([]byte, types.IstanbulExtraVanity), payload...)
+	return nil
+}
+
+// sign signs the keccak256 of data as the istanbul backend does.
+func sign(data []byte, key *ecdsa.PrivateKey) ([]byte, error) {
+	return crypto.Sign(crypto.Keccak256(data), key)
+}
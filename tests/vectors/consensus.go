@@ -0,0 +1,153 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package vectors
+
+import (
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/consensus/istanbul"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/rlp"
+)
+
+// The consensus message codes of consensus/istanbul/core.
+const (
+	msgPreprepare uint64 = iota
+	msgPrepare
+	msgCommit
+	msgRoundChange
+)
+
+// message is the wire format of the consensus messages of consensus/istanbul/core.
+type message struct {
+	Hash          common.Hash
+	Code          uint64
+	Msg           []byte
+	Address       common.Address
+	Signature     []byte
+	CommittedSeal []byte
+}
+
+type viewJSON struct {
+	Sequence *hexutil.Big `json:"sequence"`
+	Round    *hexutil.Big `json:"round"`
+}
+
+// messageJSON is the JSON form of the consensus message along with its decoded Msg.
+type messageJSON struct {
+	Code          uint64         `json:"code"`
+	Hash          common.Hash    `json:"hash"`
+	Msg           hexutil.Bytes  `json:"msg"`
+	Address       common.Address `json:"address"`
+	Signature     hexutil.Bytes  `json:"signature"`
+	CommittedSeal hexutil.Bytes  `json:"committedSeal"`
+
+	View     *viewJSON    `json:"view"`
+	Proposal *common.Hash `json:"proposal,omitempty"` // preprepare only
+	Digest   *common.Hash `json:"digest,omitempty"`   // other than preprepare
+	PrevHash *common.Hash `json:"prevHash,omitempty"` // other than preprepare
+}
+
+// committedSeal returns the data the validators sign to commit the block.
+func committedSeal(hash common.Hash) []byte {
+	return append(hash.Bytes(), byte(msgCommit))
+}
+
+// genConsensusMessages returns the vectors of the consensus messages agreeing on the block.
+func genConsensusMessages(block *types.Block) ([]*Vector, error) {
+	var (
+		view = &istanbul.View{Sequence: block.Number(), Round: common.Big0}
+		next = &istanbul.View{Sequence: block.Number(), Round: common.Big1}
+		sub  = &istanbul.Subject{View: view, Digest: block.Hash(), PrevHash: block.ParentHash()}
+		rc   = &istanbul.Subject{View: next, Digest: common.Hash{}, PrevHash: block.ParentHash()}
+	)
+
+	specs := []struct {
+		name string
+		code uint64
+		msg  interface{}
+		view *istanbul.View
+	}{
+		{"consensus/preprepare", msgPreprepare, &istanbul.Preprepare{View: view, Proposal: block}, view},
+		{"consensus/prepare", msgPrepare, sub, view},
+		{"consensus/commit", msgCommit, sub, view},
+		{"consensus/roundChange", msgRoundChange, rc, next},
+	}
+
+	var vectors []*Vector
+	for _, spec := range specs {
+		// The proposer sends the preprepare, and another validator sends the others.
+		key := validatorKeys[0]
+		if spec.code != msgPreprepare {
+			key = validatorKeys[1]
+		}
+
+		payload, err := rlp.EncodeToBytes(spec.msg)
+		if err != nil {
+			return nil, err
+		}
+		msg := &message{
+			Hash:          block.ParentHash(),
+			Code:          spec.code,
+			Msg:           payload,
+			Address:       crypto.PubkeyToAddress(key.PublicKey),
+			Signature:     []byte{},
+			CommittedSeal: []byte{},
+		}
+		if spec.code == msgCommit {
+			if msg.CommittedSeal, err = sign(committedSeal(block.Hash()), key); err != nil {
+				return nil, err
+			}
+		}
+		noSig, err := rlp.EncodeToBytes(msg)
+		if err != nil {
+			return nil, err
+		}
+		if msg.Signature, err = sign(noSig, key); err != nil {
+			return nil, err
+		}
+		enc, err := rlp.EncodeToBytes(msg)
+		if err != nil {
+			return nil, err
+		}
+
+		value := &messageJSON{
+			Code:          msg.Code,
+			Hash:          msg.Hash,
+			Msg:           msg.Msg,
+			Address:       msg.Address,
+			Signature:     msg.Signature,
+			CommittedSeal: msg.CommittedSeal,
+			View:          &viewJSON{Sequence: (*hexutil.Big)(spec.view.Sequence), Round: (*hexutil.Big)(spec.view.Round)},
+		}
+		if s, ok := spec.msg.(*istanbul.Subject); ok {
+			value.Digest, value.PrevHash = &s.Digest, &s.PrevHash
+		} else {
+			hash := block.Hash()
+			value.Proposal = &hash
+		}
+
+		v, err := newVector(spec.name, KindConsensus, enc, value)
+		if err != nil {
+			return nil, err
+		}
+		v.Signer = &msg.Address
+		vectors = append(vectors, v)
+	}
+	return vectors, nil
+}
@@ -0,0 +1,208 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package vectors
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/blockchain/types/accountkey"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/rlp"
+)
+
+var (
+	to         = common.HexToAddress("0x7b65B75d204aBed71587c9E519a89277766EE1d0")
+	amount     = big.NewInt(1e18)
+	gasLimit   = uint64(1000000)
+	gasPrice   = big.NewInt(25 * params.Gkei)
+	accessList = types.AccessList{{Address: common.HexToAddress("0x0000000000000000000000000000000000000001"), StorageKeys: []common.Hash{{1}}}}
+	code       = common.FromHex("0x6080604052348015600f57600080fd5b50603f80601d6000396000f3fe")
+	callData   = common.FromHex("0xa9059cbb0000000000000000000000007b65b75d204abed71587c9e519a89277766ee1d0")
+)
+
+// kaiaTxTypes are the basic Kaia tx types. Each of them is followed by its
+// fee delegated and partially fee delegated variants.
+var kaiaTxTypes = []types.TxType{
+	types.TxTypeValueTransfer,
+	types.TxTypeValueTransferMemo,
+	types.TxTypeAccountUpdate,
+	types.TxTypeSmartContractDeploy,
+	types.TxTypeSmartContractExecution,
+	types.TxTypeCancel,
+	types.TxTypeChainDataAnchoring,
+}
+
+// txValues returns the fields of the given tx type.
+func txValues(txType types.TxType, nonce uint64) (map[types.TxValueKeyType]interface{}, error) {
+	from := crypto.PubkeyToAddress(senderKey.PublicKey)
+	values := map[types.TxValueKeyType]interface{}{
+		types.TxValueKeyNonce:    nonce,
+		types.TxValueKeyGasLimit: gasLimit,
+	}
+
+	switch txType {
+	case types.TxTypeLegacyTransaction:
+		values[types.TxValueKeyTo] = to
+		values[types.TxValueKeyAmount] = amount
+		values[types.TxValueKeyGasPrice] = gasPrice
+		values[types.TxValueKeyData] = callData
+		return values, nil
+	case types.TxTypeEthereumAccessList:
+		values[types.TxValueKeyTo] = &to
+		values[types.TxValueKeyAmount] = amount
+		values[types.TxValueKeyGasPrice] = gasPrice
+		values[types.TxValueKeyData] = callData
+		values[types.TxValueKeyAccessList] = accessList
+		values[types.TxValueKeyChainID] = chainID
+		return values, nil
+	case types.TxTypeEthereumDynamicFee:
+		values[types.TxValueKeyTo] = &to
+		values[types.TxValueKeyAmount] = amount
+		values[types.TxValueKeyGasFeeCap] = gasPrice
+		values[types.TxValueKeyGasTipCap] = gasPrice
+		values[types.TxValueKeyData] = callData
+		values[types.TxValueKeyAccessList] = accessList
+		values[types.TxValueKeyChainID] = chainID
+		return values, nil
+	}
+
+	values[types.TxValueKeyFrom] = from
+	values[types.TxValueKeyGasPrice] = gasPrice
+	if txType.IsFeeDelegatedTransaction() {
+		values[types.TxValueKeyFeePayer] = crypto.PubkeyToAddress(feePayerKey.PublicKey)
+	}
+	if txType.IsFeeDelegatedWithRatioTransaction() {
+		values[types.TxValueKeyFeeRatioOfFeePayer] = types.FeeRatio(30)
+	}
+
+	switch txType &^ (1<<types.SubTxTypeBits - 1) {
+	case types.TxTypeValueTransfer:
+		values[types.TxValueKeyTo] = to
+		values[types.TxValueKeyAmount] = amount
+	case types.TxTypeValueTransferMemo:
+		values[types.TxValueKeyTo] = to
+		values[types.TxValueKeyAmount] = amount
+		values[types.TxValueKeyData] = []byte("hello")
+	case types.TxTypeAccountUpdate:
+		values[types.TxValueKeyAccountKey] = accountkey.NewAccountKeyPublicWithValue(&senderKey.PublicKey)
+	case types.TxTypeSmartContractDeploy:
+		values[types.TxValueKeyTo] = (*common.Address)(nil)
+		values[types.TxValueKeyAmount] = common.Big0
+		values[types.TxValueKeyData] = code
+		values[types.TxValueKeyHumanReadable] = false
+		values[types.TxValueKeyCodeFormat] = params.CodeFormatEVM
+	case types.TxTypeSmartContractExecution:
+		values[types.TxValueKeyTo] = to
+		values[types.TxValueKeyAmount] = common.Big0
+		values[types.TxValueKeyData] = callData
+	case types.TxTypeCancel:
+	case types.TxTypeChainDataAnchoring:
+		anchoredData, err := anchoringData()
+		if err != nil {
+			return nil, err
+		}
+		values[types.TxValueKeyAnchoredData] = anchoredData
+	}
+	return values, nil
+}
+
+func anchoringData() ([]byte, error) {
+	data := &types.AnchoringDataInternalType0{
+		BlockHash:     common.HexToHash("0x01"),
+		TxHash:        common.HexToHash("0x02"),
+		ParentHash:    common.HexToHash("0x03"),
+		ReceiptHash:   common.HexToHash("0x04"),
+		StateRootHash: common.HexToHash("0x05"),
+		BlockNumber:   big.NewInt(6),
+		BlockCount:    big.NewInt(7),
+		TxCount:       big.NewInt(8),
+	}
+	enc, err := rlp.EncodeToBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	return rlp.EncodeToBytes(&types.AnchoringData{Type: types.AnchoringDataType0, Data: enc})
+}
+
+// txTypes returns the tx types available under the given config.
+func txTypes(config *params.ChainConfig) []types.TxType {
+	ret := []types.TxType{types.TxTypeLegacyTransaction}
+	for _, txType := range kaiaTxTypes {
+		ret = append(ret, txType, txType+1, txType+2)
+	}
+	if config.IsEthTxTypeForkEnabled(common.Big0) {
+		ret = append(ret, types.TxTypeEthereumAccessList, types.TxTypeEthereumDynamicFee)
+	}
+	return ret
+}
+
+// genTransactions returns the signed transactions of all available tx types and their vectors.
+func genTransactions(config *params.ChainConfig) (types.Transactions, []*Vector, error) {
+	var (
+		signer  = types.MakeSigner(config, common.Big1)
+		txs     types.Transactions
+		vectors []*Vector
+	)
+	for i, txType := range txTypes(config) {
+		values, err := txValues(txType, uint64(i))
+		if err != nil {
+			return nil, nil, err
+		}
+		tx, err := types.NewTransactionWithMap(txType, values)
+		if err != nil {
+			return nil, nil, err
+		}
+		if tx, err = types.SignTx(tx, signer, senderKey); err != nil {
+			return nil, nil, err
+		}
+		if txType.IsFeeDelegatedTransaction() {
+			if tx, err = types.SignTxAsFeePayer(tx, signer, feePayerKey); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		enc, err := rlp.EncodeToBytes(tx)
+		if err != nil {
+			return nil, nil, err
+		}
+		v, err := newVector("tx/"+strings.TrimPrefix(txType.String(), "TxType"), KindTransaction, enc, tx)
+		if err != nil {
+			return nil, nil, err
+		}
+		v.Hash = tx.Hash()
+		sender, err := types.Sender(signer, tx)
+		if err != nil {
+			return nil, nil, err
+		}
+		v.Signer = &sender
+		if txType.IsFeeDelegatedTransaction() {
+			feePayer, err := types.SenderFeePayer(signer, tx)
+			if err != nil {
+				return nil, nil, err
+			}
+			v.FeePayer = &feePayer
+		}
+
+		txs = append(txs, tx)
+		vectors = append(vectors, v)
+	}
+	return txs, vectors, nil
+}
@@ -0,0 +1,187 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package vectors generates the canonical encode/decode test vectors of the
+// RLP and consensus objects for every hardfork. SDKs in other languages
+// consume the suites to keep their encoders in lockstep with the node.
+package vectors
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/common/hexutil"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/params"
+)
+
+// Kind is the type of the encoded object.
+type Kind string
+
+const (
+	KindTransaction   Kind = "transaction"
+	KindHeader        Kind = "header"
+	KindIstanbulExtra Kind = "istanbulExtra"
+	KindBlock         Kind = "block"
+	KindConsensus     Kind = "consensus"
+)
+
+// Vector is a single encode/decode test vector. Decoding RLP must produce
+// Value, and encoding Value must produce RLP.
+//
+// Hash is the transaction hash for transactions, the block hash for headers
+// and blocks, and the keccak256 of RLP otherwise.
+type Vector struct {
+	Name     string          `json:"name"`
+	Kind     Kind            `json:"kind"`
+	RLP      hexutil.Bytes   `json:"rlp"`
+	Hash     common.Hash     `json:"hash"`
+	Signer   *common.Address `json:"signer,omitempty"` // tx sender, block proposer or message author
+	FeePayer *common.Address `json:"feePayer,omitempty"`
+	Value    json.RawMessage `json:"value"`
+}
+
+// Suite is the set of the vectors valid since a hardfork.
+type Suite struct {
+	Fork    string              `json:"fork"`
+	Config  *params.ChainConfig `json:"config"`
+	Vectors []*Vector           `json:"vectors"`
+}
+
+// Fork is a hardfork in the order of activation.
+type Fork struct {
+	Name   string
+	enable func(c *params.ChainConfig)
+}
+
+// Forks lists the protocol hardforks in the order of activation. The one-time
+// system contract upgrades (KIP-103, KIP-160) are not listed since they do not
+// change any encoding.
+var Forks = []Fork{
+	{"genesis", func(c *params.ChainConfig) {}},
+	{"istanbul", func(c *params.ChainConfig) { c.IstanbulCompatibleBlock = common.Big0 }},
+	{"london", func(c *params.ChainConfig) { c.LondonCompatibleBlock = common.Big0 }},
+	{"ethTxType", func(c *params.ChainConfig) { c.EthTxTypeCompatibleBlock = common.Big0 }},
+	{"magma", func(c *params.ChainConfig) { c.MagmaCompatibleBlock = common.Big0 }},
+	{"kore", func(c *params.ChainConfig) { c.KoreCompatibleBlock = common.Big0 }},
+	{"shanghai", func(c *params.ChainConfig) { c.ShanghaiCompatibleBlock = common.Big0 }},
+	{"cancun", func(c *params.ChainConfig) { c.CancunCompatibleBlock = common.Big0 }},
+	{"randao", func(c *params.ChainConfig) { c.RandaoCompatibleBlock = common.Big0 }},
+	{"kaia", func(c *params.ChainConfig) { c.KaiaCompatibleBlock = common.Big0 }},
+	{"prague", func(c *params.ChainConfig) { c.PragueCompatibleBlock = common.Big0 }},
+	{"stakeWeightedQuorum", func(c *params.ChainConfig) { c.StakeWeightedQuorumCompatibleBlock = common.Big0 }},
+	{"keyRotation", func(c *params.ChainConfig) { c.KeyRotationCompatibleBlock = common.Big0 }},
+	{"emergencyPause", func(c *params.ChainConfig) { c.EmergencyPauseCompatibleBlock = common.Big0 }},
+	{"blobTx", func(c *params.ChainConfig) { c.BlobTxCompatibleBlock = common.Big0 }},
+	{"scheduledVote", func(c *params.ChainConfig) { c.ScheduledVoteCompatibleBlock = common.Big0 }},
+	{"stakeWeightedGov", func(c *params.ChainConfig) { c.StakeWeightedGovCompatibleBlock = common.Big0 }},
+	{"multisigGov", func(c *params.ChainConfig) { c.MultisigGovCompatibleBlock = common.Big0 }},
+}
+
+var (
+	ErrUnknownFork = errors.New("unknown hardfork")
+
+	chainID = big.NewInt(1001)
+
+	// The keys are derived from fixed seeds so that the vectors are reproducible.
+	senderKey     = testKey("sender")
+	feePayerKey   = testKey("feePayer")
+	validatorKeys = []*ecdsa.PrivateKey{testKey("validator0"), testKey("validator1"), testKey("validator2"), testKey("validator3")}
+)
+
+// Config returns the chain config with the hardforks up to the given one enabled from genesis.
+func Config(fork string) (*params.ChainConfig, error) {
+	config := &params.ChainConfig{
+		ChainID:       chainID,
+		UnitPrice:     25 * params.Gkei,
+		DeriveShaImpl: types.ImplDeriveShaConcat,
+		Istanbul:      &params.IstanbulConfig{Epoch: 604800, ProposerPolicy: uint64(params.WeightedRandom), SubGroupSize: 22},
+	}
+	for _, f := range Forks {
+		f.enable(config)
+		if f.Name == fork {
+			return config, nil
+		}
+	}
+	return nil, ErrUnknownFork
+}
+
+// Generate returns the suite of the given hardfork.
+func Generate(fork string) (*Suite, error) {
+	config, err := Config(fork)
+	if err != nil {
+		return nil, err
+	}
+	suite := &Suite{Fork: fork, Config: config}
+
+	txs, txVectors, err := genTransactions(config)
+	if err != nil {
+		return nil, err
+	}
+	suite.Vectors = append(suite.Vectors, txVectors...)
+
+	block, blockVectors, err := genBlock(config, txs)
+	if err != nil {
+		return nil, err
+	}
+	suite.Vectors = append(suite.Vectors, blockVectors...)
+
+	msgVectors, err := genConsensusMessages(block)
+	if err != nil {
+		return nil, err
+	}
+	suite.Vectors = append(suite.Vectors, msgVectors...)
+	return suite, nil
+}
+
+// GenerateAll returns the suites of all hardforks.
+func GenerateAll() ([]*Suite, error) {
+	suites := make([]*Suite, 0, len(Forks))
+	for _, f := range Forks {
+		suite, err := Generate(f.Name)
+		if err != nil {
+			return nil, err
+		}
+		suites = append(suites, suite)
+	}
+	return suites, nil
+}
+
+func newVector(name string, kind Kind, enc []byte, value interface{}) (*Vector, error) {
+	j, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return &Vector{
+		Name:  name,
+		Kind:  kind,
+		RLP:   enc,
+		Hash:  crypto.Keccak256Hash(enc),
+		Value: j,
+	}, nil
+}
+
+func testKey(seed string) *ecdsa.PrivateKey {
+	key, err := crypto.ToECDSA(crypto.Keccak256([]byte("kaia/tests/vectors/" + seed)))
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
@@ -0,0 +1,155 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+
+package vectors
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateDeterministic(t *testing.T) {
+	for _, f := range Forks {
+		s1, err := Generate(f.Name)
+		require.NoError(t, err)
+		s2, err := Generate(f.Name)
+		require.NoError(t, err)
+
+		j1, err := json.Marshal(s1)
+		require.NoError(t, err)
+		j2, err := json.Marshal(s2)
+		require.NoError(t, err)
+		assert.Equal(t, j1, j2, f.Name)
+	}
+
+	_, err := Generate("unknown")
+	assert.ErrorIs(t, err, ErrUnknownFork)
+}
+
+func TestVectorsRoundTrip(t *testing.T) {
+	suites, err := GenerateAll()
+	require.NoError(t, err)
+
+	for _, suite := range suites {
+		signer := types.MakeSigner(suite.Config, common.Big1)
+		for _, v := range suite.Vectors {
+			name := suite.Fork + "/" + v.Name
+			switch v.Kind {
+			case KindTransaction:
+				tx := new(types.Transaction)
+				require.NoError(t, rlp.DecodeBytes(v.RLP, tx), name)
+				assert.Equal(t, v.Hash, tx.Hash(), name)
+				sender, err := types.Sender(signer, tx)
+				require.NoError(t, err, name)
+				assert.Equal(t, *v.Signer, sender, name)
+				checkEncoding(t, name, v, tx)
+
+			case KindHeader:
+				header := new(types.Header)
+				require.NoError(t, rlp.DecodeBytes(v.RLP, header), name)
+				assert.Equal(t, v.Hash, header.Hash(), name)
+				checkEncoding(t, name, v, header)
+
+			case KindIstanbulExtra:
+				extra := new(types.IstanbulExtra)
+				require.NoError(t, rlp.DecodeBytes(v.RLP, extra), name)
+				assert.Len(t, extra.CommittedSeal, len(validatorKeys), name)
+				checkEncoding(t, name, v, extra)
+
+			case KindBlock:
+				block := new(types.Block)
+				require.NoError(t, rlp.DecodeBytes(v.RLP, block), name)
+				assert.Equal(t, v.Hash, block.Hash(), name)
+				checkEncoding(t, name, v, block)
+
+			case KindConsensus:
+				msg := new(message)
+				require.NoError(t, rlp.DecodeBytes(v.RLP, msg), name)
+				assert.Equal(t, *v.Signer, msg.Address, name)
+				checkEncoding(t, name, v, msg)
+
+			default:
+				t.Errorf("%s: unknown kind %s", name, v.Kind)
+			}
+		}
+	}
+}
+
+func checkEncoding(t *testing.T, name string, v *Vector, decoded interface{}) {
+	enc, err := rlp.EncodeToBytes(decoded)
+	require.NoError(t, err, name)
+	assert.True(t, bytes.Equal(v.RLP, enc), name)
+}
+
+func TestVectorsByFork(t *testing.T) {
+	find := func(suite *Suite, name string) *Vector {
+		for _, v := range suite.Vectors {
+			if v.Name == name {
+				return v
+			}
+		}
+		return nil
+	}
+	header := func(suite *Suite) *types.Header {
+		header := new(types.Header)
+		require.NoError(t, rlp.DecodeBytes(find(suite, "header").RLP, header))
+		return header
+	}
+
+	testcases := []struct {
+		fork         string
+		ethTxTypes   bool
+		baseFee      bool
+		randao       bool
+		keyRotation  bool
+		numTxVectors int
+	}{
+		{"genesis", false, false, false, false, 22},
+		{"london", false, false, false, false, 22},
+		{"ethTxType", true, false, false, false, 24},
+		{"magma", true, true, false, false, 24},
+		{"randao", true, true, true, false, 24},
+		{"keyRotation", true, true, true, true, 24},
+	}
+	for _, tc := range testcases {
+		suite, err := Generate(tc.fork)
+		require.NoError(t, err)
+
+		numTxVectors := 0
+		for _, v := range suite.Vectors {
+			if v.Kind == KindTransaction {
+				numTxVectors++
+			}
+		}
+		assert.Equal(t, tc.numTxVectors, numTxVectors, tc.fork)
+		assert.Equal(t, tc.ethTxTypes, find(suite, "tx/EthereumDynamicFee") != nil, tc.fork)
+
+		h := header(suite)
+		assert.Equal(t, tc.baseFee, h.BaseFee != nil, tc.fork)
+		assert.Equal(t, tc.randao, h.MixHash != nil, tc.fork)
+
+		extra, err := types.ExtractIstanbulExtra(h)
+		require.NoError(t, err)
+		assert.Equal(t, tc.keyRotation, extra.KeyRotation != nil, tc.fork)
+	}
+}
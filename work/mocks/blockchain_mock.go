@@ -8,6 +8,7 @@ import (
 	io "io"
 	big "math/big"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	blockchain "github.com/kaiachain/kaia/blockchain"
@@ -438,6 +439,21 @@ func (mr *MockBlockChainMockRecorder) GetReceiptsByBlockHash(arg0 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReceiptsByBlockHash", reflect.TypeOf((*MockBlockChain)(nil).GetReceiptsByBlockHash), arg0)
 }
 
+// GetStateDiff mocks base method.
+func (m *MockBlockChain) GetStateDiff(arg0 common.Hash) (*state.StateDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStateDiff", arg0)
+	ret0, _ := ret[0].(*state.StateDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStateDiff indicates an expected call of GetStateDiff.
+func (mr *MockBlockChainMockRecorder) GetStateDiff(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStateDiff", reflect.TypeOf((*MockBlockChain)(nil).GetStateDiff), arg0)
+}
+
 // GetTd mocks base method.
 func (m *MockBlockChain) GetTd(arg0 common.Hash, arg1 uint64) *big.Int {
 	m.ctrl.T.Helper()
@@ -623,6 +639,20 @@ func (mr *MockBlockChainMockRecorder) InsertReceiptChain(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertReceiptChain", reflect.TypeOf((*MockBlockChain)(nil).InsertReceiptChain), arg0, arg1)
 }
 
+// IsLivePruningRequired mocks base method.
+func (m *MockBlockChain) IsLivePruningRequired() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsLivePruningRequired")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsLivePruningRequired indicates an expected call of IsLivePruningRequired.
+func (mr *MockBlockChainMockRecorder) IsLivePruningRequired() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsLivePruningRequired", reflect.TypeOf((*MockBlockChain)(nil).IsLivePruningRequired))
+}
+
 // IsParallelDBWrite mocks base method.
 func (m *MockBlockChain) IsParallelDBWrite() bool {
 	m.ctrl.T.Helper()
@@ -651,6 +681,21 @@ func (mr *MockBlockChainMockRecorder) IsSenderTxHashIndexingEnabled() *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSenderTxHashIndexingEnabled", reflect.TypeOf((*MockBlockChain)(nil).IsSenderTxHashIndexingEnabled))
 }
 
+// PinStateRoot mocks base method.
+func (m *MockBlockChain) PinStateRoot(arg0 common.Hash, arg1 time.Duration) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinStateRoot", arg0, arg1)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PinStateRoot indicates an expected call of PinStateRoot.
+func (mr *MockBlockChainMockRecorder) PinStateRoot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinStateRoot", reflect.TypeOf((*MockBlockChain)(nil).PinStateRoot), arg0, arg1)
+}
+
 // PostChainEvents mocks base method.
 func (m *MockBlockChain) PostChainEvents(arg0 []interface{}, arg1 []*types.Log) {
 	m.ctrl.T.Helper()
@@ -738,6 +783,22 @@ func (mr *MockBlockChainMockRecorder) RegisterRewindableModule(arg0 ...interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterRewindableModule", reflect.TypeOf((*MockBlockChain)(nil).RegisterRewindableModule), arg0...)
 }
 
+// RegisterTxProcessModule mocks base method.
+func (m *MockBlockChain) RegisterTxProcessModule(arg0 ...kaiax.TxProcessModule) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range arg0 {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "RegisterTxProcessModule", varargs...)
+}
+
+// RegisterTxProcessModule indicates an expected call of RegisterTxProcessModule.
+func (mr *MockBlockChainMockRecorder) RegisterTxProcessModule(arg0 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterTxProcessModule", reflect.TypeOf((*MockBlockChain)(nil).RegisterTxProcessModule), arg0...)
+}
+
 // ResetWithGenesisBlock mocks base method.
 func (m *MockBlockChain) ResetWithGenesisBlock(arg0 *types.Block) error {
 	m.ctrl.T.Helper()
@@ -848,6 +909,20 @@ func (mr *MockBlockChainMockRecorder) StartStateMigration(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartStateMigration", reflect.TypeOf((*MockBlockChain)(nil).StartStateMigration), arg0, arg1)
 }
 
+// StartStatePruning mocks base method.
+func (m *MockBlockChain) StartStatePruning() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartStatePruning")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartStatePruning indicates an expected call of StartStatePruning.
+func (mr *MockBlockChainMockRecorder) StartStatePruning() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartStatePruning", reflect.TypeOf((*MockBlockChain)(nil).StartStatePruning))
+}
+
 // StartWarmUp mocks base method.
 func (m *MockBlockChain) StartWarmUp(arg0 uint) error {
 	m.ctrl.T.Helper()
@@ -956,6 +1031,20 @@ func (mr *MockBlockChainMockRecorder) StateMigrationStatus() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateMigrationStatus", reflect.TypeOf((*MockBlockChain)(nil).StateMigrationStatus))
 }
 
+// StatePruningStatus mocks base method.
+func (m *MockBlockChain) StatePruningStatus() *blockchain.StatePruningStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StatePruningStatus")
+	ret0, _ := ret[0].(*blockchain.StatePruningStatus)
+	return ret0
+}
+
+// StatePruningStatus indicates an expected call of StatePruningStatus.
+func (mr *MockBlockChainMockRecorder) StatePruningStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatePruningStatus", reflect.TypeOf((*MockBlockChain)(nil).StatePruningStatus))
+}
+
 // Stop mocks base method.
 func (m *MockBlockChain) Stop() {
 	m.ctrl.T.Helper()
@@ -1010,6 +1099,20 @@ func (mr *MockBlockChainMockRecorder) SubscribeChainEvent(arg0 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeChainEvent", reflect.TypeOf((*MockBlockChain)(nil).SubscribeChainEvent), arg0)
 }
 
+// SubscribeChainEventFrom mocks base method.
+func (m *MockBlockChain) SubscribeChainEventFrom(arg0 uint64, arg1 chan<- blockchain.ChainEvent) event.Subscription {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeChainEventFrom", arg0, arg1)
+	ret0, _ := ret[0].(event.Subscription)
+	return ret0
+}
+
+// SubscribeChainEventFrom indicates an expected call of SubscribeChainEventFrom.
+func (mr *MockBlockChainMockRecorder) SubscribeChainEventFrom(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeChainEventFrom", reflect.TypeOf((*MockBlockChain)(nil).SubscribeChainEventFrom), arg0, arg1)
+}
+
 // SubscribeChainHeadEvent mocks base method.
 func (m *MockBlockChain) SubscribeChainHeadEvent(arg0 chan<- blockchain.ChainHeadEvent) event.Subscription {
 	m.ctrl.T.Helper()
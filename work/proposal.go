@@ -0,0 +1,212 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package work
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/consensus"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/rlp"
+	"github.com/kaiachain/kaia/storage/database"
+)
+
+// ProposalPolicyPriceAndNonce orders the transactions by the effective gas price across
+// the senders and by the nonce within a sender. It is the ordering policy of commitNewWork.
+const ProposalPolicyPriceAndNonce = "price-and-nonce"
+
+// replayTimeLimit replaces the block generation time limit when a proposal is replayed,
+// so that the replay is not cut short by the speed of the auditing node.
+const replayTimeLimit = time.Hour
+
+var (
+	proposalRecordPrefix = []byte("proposalRecord")
+
+	errUnknownBlock     = errors.New("unknown block")
+	errNoProposalRecord = errors.New("no proposal record for the block; it was not proposed by this node with --proposal.record")
+)
+
+// ProposalRecord is the input of building a block proposed by this node. Along with the
+// parent state and the header, it suffices to re-derive the transactions of the block.
+type ProposalRecord struct {
+	Policy     string
+	TimeLimit  uint64 // nanoseconds
+	Rewardbase common.Address
+	PoolHash   common.Hash        // hash of the candidates
+	Candidates types.Transactions // pending transactions ordered by sender, then by nonce
+	Times      []uint64           // arrival time of each candidate in unix nanoseconds, which breaks the ties of the price
+}
+
+// ProposalReplay is the result of re-deriving a proposed block from its ProposalRecord.
+type ProposalReplay struct {
+	Number     uint64        `json:"number"`
+	Hash       common.Hash   `json:"hash"`
+	Policy     string        `json:"policy"`
+	PoolHash   common.Hash   `json:"poolHash"`
+	TimeLimit  time.Duration `json:"timeLimit"`
+	Candidates int           `json:"candidates"`
+	Included   []common.Hash `json:"included"` // transactions of the block
+	Expected   []common.Hash `json:"expected"` // transactions of the replay without the time limit
+	Compliant  bool          `json:"compliant"`
+	Truncated  bool          `json:"truncated"` // the block includes a strict prefix of the replay, e.g. due to the time limit
+	Reason     string        `json:"reason,omitempty"`
+}
+
+// newProposalRecord flattens the pending transactions in a deterministic order.
+func newProposalRecord(pending map[common.Address]types.Transactions, timeLimit time.Duration, rewardbase common.Address) *ProposalRecord {
+	senders := make([]common.Address, 0, len(pending))
+	for addr := range pending {
+		senders = append(senders, addr)
+	}
+	sort.Slice(senders, func(i, j int) bool { return bytes.Compare(senders[i][:], senders[j][:]) < 0 })
+
+	var (
+		candidates types.Transactions
+		times      []uint64
+	)
+	for _, addr := range senders {
+		for _, tx := range pending[addr] {
+			candidates = append(candidates, tx)
+			times = append(times, uint64(tx.Time().UnixNano()))
+		}
+	}
+	return &ProposalRecord{
+		Policy:     ProposalPolicyPriceAndNonce,
+		TimeLimit:  uint64(timeLimit),
+		Rewardbase: rewardbase,
+		PoolHash:   proposalPoolHash(candidates),
+		Candidates: candidates,
+		Times:      times,
+	}
+}
+
+func proposalPoolHash(candidates types.Transactions) common.Hash {
+	b, _ := rlp.EncodeToBytes(candidates)
+	return crypto.Keccak256Hash(b)
+}
+
+func proposalRecordKey(hash common.Hash) []byte {
+	return append(common.CopyBytes(proposalRecordPrefix), hash[:]...)
+}
+
+// WriteProposalRecord stores the proposal record of the block.
+func WriteProposalRecord(db database.Database, hash common.Hash, rec *ProposalRecord) {
+	b, err := rlp.EncodeToBytes(rec)
+	if err != nil {
+		logger.Error("Failed to encode proposal record", "hash", hash, "err", err)
+		return
+	}
+	if err := db.Put(proposalRecordKey(hash), b); err != nil {
+		logger.Error("Failed to write proposal record", "hash", hash, "err", err)
+	}
+}
+
+// ReadProposalRecord returns the proposal record of the block, or nil if there is none.
+func ReadProposalRecord(db database.Database, hash common.Hash) *ProposalRecord {
+	b, err := db.Get(proposalRecordKey(hash))
+	if err != nil || len(b) == 0 {
+		return nil
+	}
+	rec := new(ProposalRecord)
+	if err := rlp.DecodeBytes(b, rec); err != nil {
+		logger.Error("Invalid proposal record", "hash", hash, "err", err)
+		return nil
+	}
+	return rec
+}
+
+// ReplayProposal re-derives the transactions of a block proposed by this node from its proposal
+// record and the parent state, and verifies that the block follows the ordering policy.
+// A block is compliant if its transactions are a prefix of the replay, since the building
+// may have stopped early at the block generation time limit.
+func ReplayProposal(chain BlockChain, engine consensus.Engine, db database.Database, hash common.Hash) (*ProposalReplay, error) {
+	block := chain.GetBlockByHash(hash)
+	if block == nil {
+		return nil, errUnknownBlock
+	}
+	rec := ReadProposalRecord(db, hash)
+	if rec == nil {
+		return nil, errNoProposalRecord
+	}
+	ret := &ProposalReplay{
+		Number:     block.NumberU64(),
+		Hash:       hash,
+		Policy:     rec.Policy,
+		PoolHash:   rec.PoolHash,
+		TimeLimit:  time.Duration(rec.TimeLimit),
+		Candidates: len(rec.Candidates),
+	}
+	for _, tx := range block.Transactions() {
+		ret.Included = append(ret.Included, tx.Hash())
+	}
+	if rec.Policy != ProposalPolicyPriceAndNonce {
+		return nil, fmt.Errorf("unsupported ordering policy %q", rec.Policy)
+	}
+	if proposalPoolHash(rec.Candidates) != rec.PoolHash || len(rec.Times) != len(rec.Candidates) {
+		ret.Reason = "candidates do not match the pool hash"
+		return ret, nil
+	}
+
+	parent := chain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, errUnknownBlock
+	}
+	statedb, err := chain.StateAt(parent.Root())
+	if err != nil {
+		return nil, err
+	}
+	header := types.CopyHeader(block.Header())
+	header.GasUsed = 0
+	engine.Initialize(chain, header, statedb)
+
+	config := chain.Config()
+	task := NewTask(config, types.MakeSigner(config, header.Number), statedb, header)
+	task.timeLimit = replayTimeLimit
+
+	pending := make(map[common.Address]types.Transactions)
+	for i, tx := range rec.Candidates {
+		tx.SetTime(time.Unix(0, int64(rec.Times[i])))
+		from, err := types.Sender(task.signer, tx)
+		if err != nil {
+			return nil, fmt.Errorf("invalid candidate %s: %w", tx.Hash().String(), err)
+		}
+		pending[from] = append(pending[from], tx)
+	}
+	task.ApplyTransactions(types.NewTransactionsByPriceAndNonce(task.signer, pending, header.BaseFee), chain, rec.Rewardbase)
+	for _, tx := range task.txs {
+		ret.Expected = append(ret.Expected, tx.Hash())
+	}
+
+	if len(ret.Included) > len(ret.Expected) {
+		ret.Reason = fmt.Sprintf("block includes %d transactions but the replay derives %d", len(ret.Included), len(ret.Expected))
+		return ret, nil
+	}
+	for i, h := range ret.Included {
+		if ret.Expected[i] != h {
+			ret.Reason = fmt.Sprintf("transaction %d is %s but the replay derives %s", i, h.String(), ret.Expected[i].String())
+			return ret, nil
+		}
+	}
+	ret.Compliant = true
+	ret.Truncated = len(ret.Included) < len(ret.Expected)
+	return ret, nil
+}
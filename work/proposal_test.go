@@ -0,0 +1,106 @@
+// Copyright 2024 The Kaia Authors
+// This file is part of the Kaia library.
+//
+// The Kaia library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Kaia library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Kaia library. If not, see <http://www.gnu.org/licenses/>.
+package work
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/kaiachain/kaia/blockchain"
+	"github.com/kaiachain/kaia/blockchain/types"
+	"github.com/kaiachain/kaia/blockchain/vm"
+	"github.com/kaiachain/kaia/common"
+	"github.com/kaiachain/kaia/consensus/gxhash"
+	"github.com/kaiachain/kaia/crypto"
+	"github.com/kaiachain/kaia/params"
+	"github.com/kaiachain/kaia/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayProposal(t *testing.T) {
+	var (
+		config  = &params.ChainConfig{ChainID: big.NewInt(1)}
+		signer  = types.LatestSignerForChainID(config.ChainID)
+		keyA, _ = crypto.GenerateKey()
+		keyB, _ = crypto.GenerateKey()
+		addrA   = crypto.PubkeyToAddress(keyA.PublicKey)
+		addrB   = crypto.PubkeyToAddress(keyB.PublicKey)
+		to      = common.HexToAddress("0x1234")
+		balance = new(big.Int).Mul(big.NewInt(params.KAIA), big.NewInt(1000))
+	)
+	newTx := func(key *ecdsa.PrivateKey, nonce uint64, price int64) *types.Transaction {
+		tx, err := types.SignTx(types.NewTransaction(nonce, to, big.NewInt(1), params.TxGas, big.NewInt(price), nil), signer, key)
+		require.NoError(t, err)
+		return tx
+	}
+	var (
+		txA0 = newTx(keyA, 0, 50)
+		txA1 = newTx(keyA, 1, 50)
+		txB0 = newTx(keyB, 0, 30)
+	)
+	pending := map[common.Address]types.Transactions{
+		addrA: {txA0, txA1},
+		addrB: {txB0},
+	}
+
+	testcases := []struct {
+		name      string
+		included  types.Transactions
+		compliant bool
+		truncated bool
+	}{
+		{"complete", types.Transactions{txA0, txA1, txB0}, true, false},
+		{"truncated", types.Transactions{txA0, txA1}, true, true},
+		{"reordered", types.Transactions{txB0, txA0, txA1}, false, false},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := database.NewMemoryDBManager()
+			gspec := &blockchain.Genesis{
+				Config: config,
+				Alloc:  blockchain.GenesisAlloc{addrA: {Balance: balance}, addrB: {Balance: balance}},
+			}
+			genesis := gspec.MustCommit(db)
+			blocks, _ := blockchain.GenerateChain(config, genesis, gxhash.NewFaker(), db, 1, func(i int, gen *blockchain.BlockGen) {
+				for _, tx := range tc.included {
+					gen.AddTx(tx)
+				}
+			})
+			chain, err := blockchain.NewBlockChain(db, nil, config, gxhash.NewFaker(), vm.Config{})
+			require.NoError(t, err)
+			defer chain.Stop()
+			_, err = chain.InsertChain(blocks)
+			require.NoError(t, err)
+
+			block := blocks[0]
+			_, err = ReplayProposal(chain, gxhash.NewFaker(), db.GetMiscDB(), block.Hash())
+			assert.ErrorIs(t, err, errNoProposalRecord)
+
+			rec := newProposalRecord(pending, time.Second, block.Rewardbase())
+			WriteProposalRecord(db.GetMiscDB(), block.Hash(), rec)
+
+			ret, err := ReplayProposal(chain, gxhash.NewFaker(), db.GetMiscDB(), block.Hash())
+			require.NoError(t, err)
+			assert.Equal(t, []common.Hash{txA0.Hash(), txA1.Hash(), txB0.Hash()}, ret.Expected)
+			assert.Equal(t, 3, ret.Candidates)
+			assert.Equal(t, tc.compliant, ret.Compliant, ret.Reason)
+			assert.Equal(t, tc.truncated, ret.Truncated)
+		})
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"io"
 	"math/big"
 	"sync/atomic"
+	"time"
 
 	"github.com/kaiachain/kaia/accounts"
 	"github.com/kaiachain/kaia/blockchain"
@@ -228,6 +229,33 @@ func (self *Miner) RegisterExecutionModule(modules ...kaiax.ExecutionModule) {
 	self.worker.RegisterExecutionModule(modules...)
 }
 
+// SetPrivateTxSource sets the source of the private transactions which are
+// included in the blocks proposed by this node along with the tx pool transactions.
+func (self *Miner) SetPrivateTxSource(source PrivateTxSource) {
+	self.worker.setPrivateTxSource(source)
+}
+
+// SetBlobGasLimiter sets the source of the blob gas limit of the blocks proposed by this node.
+func (self *Miner) SetBlobGasLimiter(limiter BlobGasLimiter) {
+	self.worker.setBlobGasLimiter(limiter)
+}
+
+// SetRecordProposals enables recording the input of building the blocks proposed by this node,
+// so that the proposals can be verified later by ReplayProposal.
+func (self *Miner) SetRecordProposals(enabled bool) {
+	self.worker.setRecordProposals(enabled)
+}
+
+// PrivateTxSource provides the transactions which are not in the tx pool.
+type PrivateTxSource interface {
+	Pending() map[common.Address]types.Transactions
+}
+
+// BlobGasLimiter provides the maximum total blob gas of a block.
+type BlobGasLimiter interface {
+	MaxBlobGas(num uint64) uint64
+}
+
 // BlockChain is an interface of blockchain.BlockChain used by ProtocolManager.
 //
 //go:generate mockgen -destination=mocks/blockchain_mock.go -package=mocks github.com/kaiachain/kaia/work BlockChain
@@ -268,6 +296,7 @@ type BlockChain interface {
 	StateCache() state.Database
 
 	SubscribeChainEvent(ch chan<- blockchain.ChainEvent) event.Subscription
+	SubscribeChainEventFrom(from uint64, ch chan<- blockchain.ChainEvent) event.Subscription
 	SetHead(head uint64) error
 	Stop()
 
@@ -277,6 +306,7 @@ type BlockChain interface {
 	SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription
 	IsParallelDBWrite() bool
 	IsSenderTxHashIndexingEnabled() bool
+	IsLivePruningRequired() bool
 
 	Processor() blockchain.Processor
 	BadBlocks() ([]blockchain.BadBlockArgs, error)
@@ -306,11 +336,19 @@ type BlockChain interface {
 	StopStateMigration() error
 	StateMigrationStatus() (bool, uint64, int, int, int, float64, error)
 
+	// State Pruning
+	StartStatePruning() error
+	StatePruningStatus() *blockchain.StatePruningStatus
+	PinStateRoot(root common.Hash, duration time.Duration) (time.Time, error)
+
 	// Warm up
 	StartWarmUp(minLoad uint) error
 	StartContractWarmUp(contractAddr common.Address, minLoad uint) error
 	StopWarmUp() error
 
+	// State diff
+	GetStateDiff(blockHash common.Hash) (*state.StateDiff, error)
+
 	// Collect state/storage trie statistics
 	StartCollectingTrieStats(contractAddr common.Address) error
 	GetContractStorageRoot(block *types.Block, db state.Database, contractAddr common.Address) (common.ExtHash, error)
@@ -331,4 +369,5 @@ type BlockChain interface {
 	// kaiax module host
 	kaiax.ExecutionModuleHost
 	kaiax.RewindableModuleHost
+	kaiax.TxProcessModuleHost
 }
@@ -23,7 +23,9 @@
 package work
 
 import (
+	"math"
 	"math/big"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -65,6 +67,7 @@ var (
 	ResultChGauge           = metrics.NewRegisteredGauge("miner/resultch", nil)
 	resentTxGauge           = metrics.NewRegisteredGauge("miner/tx/resend/gauge", nil)
 	usedAllTxsCounter       = metrics.NewRegisteredCounter("miner/usedalltxs", nil)
+	budgetReducedCounter    = metrics.NewRegisteredCounter("miner/budgetreduced", nil)
 	checkedTxsGauge         = metrics.NewRegisteredGauge("miner/checkedtxs", nil)
 	tCountGauge             = metrics.NewRegisteredGauge("miner/tcount", nil)
 	nonceTooLowTxsGauge     = metrics.NewRegisteredGauge("miner/nonce/low/txs", nil)
@@ -118,7 +121,13 @@ type Task struct {
 	txs      []*types.Transaction
 	receipts []*types.Receipt
 
+	timeLimit time.Duration // execution time limit for all txs in the block
 	createdAt time.Time
+
+	blobGasUsed uint64 // total blob gas of the blob txs in the block
+	maxBlobGas  uint64 // blob gas limit of the block
+
+	proposal *ProposalRecord // the input of building the block; nil unless recording proposals
 }
 
 type Result struct {
@@ -151,6 +160,9 @@ type worker struct {
 	proc             blockchain.Validator
 	chainDB          database.DBManager
 	executionModules []kaiax.ExecutionModule
+	privateTxs       PrivateTxSource
+	blobGasLimiter   BlobGasLimiter
+	recordProposals  bool
 
 	extra []byte
 
@@ -417,6 +429,10 @@ func (self *worker) wait(TxResendUseLegacy bool) {
 			}
 			blockWriteTime := time.Since(start)
 
+			if work.proposal != nil {
+				WriteProposalRecord(self.chainDB.GetMiscDB(), block.Hash(), work.proposal)
+			}
+
 			// TODO-Klaytn-Issue264 If we are using istanbul BFT, then we always have a canonical chain.
 			//         Later we may be able to refine below code.
 
@@ -516,7 +532,7 @@ func (self *worker) commitNewWork() {
 	// TODO-Kaia drop or missing tx
 	tstart := time.Now()
 	tstamp := tstart.Unix()
-	if self.nodetype == common.CONSENSUSNODE {
+	if ProposerBuilt && self.nodetype == common.CONSENSUSNODE {
 		parentTimestamp := parent.Time().Int64()
 		ideal := time.Unix(parentTimestamp+params.BlockGenerationInterval, 0)
 		// If a timestamp of this block is faster than the ideal timestamp,
@@ -540,13 +556,16 @@ func (self *worker) commitNewWork() {
 	var pending map[common.Address]types.Transactions
 	var err error
 	var nextBaseFee *big.Int
-	if self.nodetype == common.CONSENSUSNODE {
+	if ProposerBuilt && self.nodetype == common.CONSENSUSNODE {
 		// Check any fork transitions needed
 		pending, err = self.backend.TxPool().Pending()
 		if err != nil {
 			logger.Error("Failed to fetch pending transactions", "err", err)
 			return
 		}
+		if self.privateTxs != nil {
+			pending = mergePrivateTxs(pending, self.privateTxs.Pending())
+		}
 
 		if self.config.IsMagmaForkEnabled(nextBlockNum) {
 			// NOTE-Kaia NextBlockBaseFee needs the header of parent, self.chain.CurrentBlock
@@ -584,7 +603,21 @@ func (self *worker) commitNewWork() {
 
 	// Create the current work task
 	work := self.current
-	if self.nodetype == common.CONSENSUSNODE {
+	if ProposerBuilt && self.nodetype == common.CONSENSUSNODE {
+		// Shrink the block rather than miss the consensus deadline of the round.
+		if budgeter, ok := self.engine.(consensus.BlockBudgeter); ok {
+			if budget := budgeter.BlockBudget(header, work.timeLimit); budget < work.timeLimit {
+				logger.Debug("Reduced block generation time limit", "number", header.Number, "budget", budget)
+				budgetReducedCounter.Inc(1)
+				work.timeLimit = budget
+			}
+		}
+		if self.blobGasLimiter != nil {
+			work.maxBlobGas = self.blobGasLimiter.MaxBlobGas(header.Number.Uint64())
+		}
+		if self.recordProposals {
+			work.proposal = newProposalRecord(pending, work.timeLimit, self.rewardbase)
+		}
 		txs := types.NewTransactionsByPriceAndNonce(self.current.signer, pending, work.header.BaseFee)
 		work.commitTransactions(self.mux, txs, self.chain, self.rewardbase)
 		finishedCommitTx := time.Now()
@@ -655,6 +688,52 @@ func (self *worker) RegisterExecutionModule(modules ...kaiax.ExecutionModule) {
 	self.executionModules = append(self.executionModules, modules...)
 }
 
+func (self *worker) setPrivateTxSource(source PrivateTxSource) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.privateTxs = source
+}
+
+func (self *worker) setBlobGasLimiter(limiter BlobGasLimiter) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.blobGasLimiter = limiter
+}
+
+func (self *worker) setRecordProposals(enabled bool) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.recordProposals = enabled
+}
+
+// mergePrivateTxs adds the private transactions to the pending transactions of the tx pool.
+// A private transaction replaces the pool transaction of the same sender and nonce.
+func mergePrivateTxs(pending, private map[common.Address]types.Transactions) map[common.Address]types.Transactions {
+	if len(private) == 0 {
+		return pending
+	}
+	merged := make(map[common.Address]types.Transactions, len(pending)+len(private))
+	for addr, txs := range pending {
+		merged[addr] = txs
+	}
+	for addr, privTxs := range private {
+		nonces := make(map[uint64]bool, len(privTxs))
+		for _, tx := range privTxs {
+			nonces[tx.Nonce()] = true
+		}
+		txs := make(types.Transactions, 0, len(merged[addr])+len(privTxs))
+		for _, tx := range merged[addr] {
+			if !nonces[tx.Nonce()] {
+				txs = append(txs, tx)
+			}
+		}
+		txs = append(txs, privTxs...)
+		sort.Sort(types.TxByNonce(txs))
+		merged[addr] = txs
+	}
+	return merged
+}
+
 func (env *Task) commitTransactions(mux *event.TypeMux, txs *types.TransactionsByPriceAndNonce, bc BlockChain, rewardbase common.Address) {
 	coalescedLogs := env.ApplyTransactions(txs, bc, rewardbase)
 
@@ -691,7 +770,7 @@ func (env *Task) ApplyTransactions(txs *types.TransactionsByPriceAndNonce, bc Bl
 	chEVM := make(chan *vm.EVM, 1)
 
 	go func() {
-		blockTimer := time.NewTimer(params.BlockGenerationTimeLimit)
+		blockTimer := time.NewTimer(env.timeLimit)
 		timeout := false
 		var evm *vm.EVM
 
@@ -732,7 +811,7 @@ CommitTransactionLoop:
 		// Retrieve the next transaction and abort if all done
 		tx := txs.Peek()
 		if tx == nil {
-			// To indicate that it does not have enough transactions for params.BlockGenerationTimeLimit.
+			// To indicate that it does not have enough transactions for the time limit.
 			if numTxsChecked > 0 {
 				usedAllTxsCounter.Inc(1)
 			}
@@ -745,6 +824,13 @@ CommitTransactionLoop:
 		// We use the eip155 signer regardless of the current hf.
 		from, _ := types.Sender(env.signer, tx)
 
+		// Leave the blob tx and the rest of the account out if its blobs don't fit in the block.
+		if blobGas := tx.BlobGas(); blobGas > 0 && env.blobGasUsed+blobGas > env.maxBlobGas {
+			logger.Trace("Blob gas limit exceeded for current block", "sender", from, "blobGas", blobGas)
+			txs.Pop()
+			continue
+		}
+
 		// NOTE-Kaia Since Kaia is always in EIP155, the below replay protection code is not needed.
 		// TODO-Kaia-RemoveLater Remove the code commented below.
 		// Check whether the tx is replay protected. If we're not in the EIP155 hf
@@ -831,7 +917,9 @@ func (env *Task) commitTransaction(tx *types.Transaction, bc BlockChain, rewardb
 		env.state.RevertToSnapshot(snap)
 		return err, nil
 	}
-	env.txs = append(env.txs, tx)
+	// The blobs are kept only in the tx pool and not included in the block.
+	env.blobGasUsed += tx.BlobGas()
+	env.txs = append(env.txs, tx.WithoutBlobTxSidecar())
 	env.receipts = append(env.receipts, receipt)
 
 	return nil, receipt.Logs
@@ -839,11 +927,13 @@ func (env *Task) commitTransaction(tx *types.Transaction, bc BlockChain, rewardb
 
 func NewTask(config *params.ChainConfig, signer types.Signer, statedb *state.StateDB, header *types.Header) *Task {
 	return &Task{
-		config:    config,
-		signer:    signer,
-		state:     statedb,
-		header:    header,
-		createdAt: time.Now(),
+		config:     config,
+		signer:     signer,
+		state:      statedb,
+		header:     header,
+		timeLimit:  params.BlockGenerationTimeLimit,
+		createdAt:  time.Now(),
+		maxBlobGas: math.MaxUint64,
 	}
 }
 
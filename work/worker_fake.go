@@ -42,3 +42,6 @@ func (*FakeWorker) SetExtra([]byte) error                                    { r
 func (*FakeWorker) Pending() (*types.Block, *state.StateDB)                  { return nil, nil }
 func (*FakeWorker) PendingBlock() *types.Block                               { return nil }
 func (*FakeWorker) RegisterExecutionModule(modules ...kaiax.ExecutionModule) {}
+func (*FakeWorker) SetPrivateTxSource(PrivateTxSource)                       {}
+func (*FakeWorker) SetBlobGasLimiter(BlobGasLimiter)                         {}
+func (*FakeWorker) SetRecordProposals(bool)                                  {}